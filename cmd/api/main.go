@@ -2,23 +2,28 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"log"
 	"os"
 	"os/signal"
 	"sync"
 	"syscall"
-	"time"
 
 	"github.com/nurlyy/task_manager/internal/api"
 	"github.com/nurlyy/task_manager/internal/app"
 	"github.com/nurlyy/task_manager/internal/service"
 	"github.com/nurlyy/task_manager/pkg/auth"
+	"github.com/nurlyy/task_manager/pkg/circuit"
 	"github.com/nurlyy/task_manager/pkg/config"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
 	applogger "github.com/nurlyy/task_manager/pkg/logger"
 )
 
 func main() {
+	validateConfig := flag.Bool("validate-config", false, "print the resolved configuration with secrets masked and exit")
+	flag.Parse()
+
 	// Инициализируем контекст приложения
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -29,6 +34,17 @@ func main() {
 		log.Fatalf("Failed to load config: %v", err)
 	}
 
+	// Проверяем конфигурацию: по флагу только выводим результат, иначе не запускаемся с ошибками
+	if *validateConfig {
+		if err := cfg.Report(os.Stdout); err != nil {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("Invalid config: %v", err)
+	}
+
 	// Обновляем контекст приложения в конфигурации
 	cfg.App.Context = ctx
 
@@ -38,6 +54,11 @@ func main() {
 		log.Fatalf("Failed to initialize logger: %v", err)
 	}
 
+	// Ссылки на описание кодов ошибок в ответах API ведут на каталог этого экземпляра
+	if cfg.App.BaseURL != "" {
+		apperrors.SetDocsBaseURL(cfg.App.BaseURL + "/api/v1/errors")
+	}
+
 	// Инициализируем менеджер JWT
 	jwtManager := auth.NewJWTManager(&cfg.JWT)
 
@@ -48,6 +69,9 @@ func main() {
 	}
 	defer application.Close()
 
+	// Запускаем сервер метрик запросов к базе данных и внешних сервисов
+	application.StartMetricsServer()
+
 	// Инициализируем сервисы
 	services, err := initServices(application, jwtManager)
 	if err != nil {
@@ -56,6 +80,7 @@ func main() {
 
 	repositories := &api.Repositories{
 		TelegramRepository: application.Repositories.TelegramRepository,
+		CacheRepository:    application.Repositories.CacheRepository,
 	}
 
 	// Инициализируем API сервер
@@ -80,7 +105,7 @@ func main() {
 	logger.Info("Shutting down...")
 
 	// Создаем контекст с таймаутом для остановки
-	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), cfg.HTTP.ShutdownTimeout)
 	defer shutdownCancel()
 
 	// Останавливаем сервер
@@ -96,10 +121,9 @@ func main() {
 // initServices инициализирует все сервисы для API
 func initServices(application *app.Application, jwtManager *auth.JWTManager) (*api.Services, error) {
 	// Инициализация сервисов
-	application.Logger.Info("THERE SHOULD BE TOKEN: ")
-	application.Logger.Info(application.Config.Telegram.Token)
 	telegramSender := service.NewTelegramSender(
-		application.Config.Telegram.Token,
+		&application.Config.Telegram,
+		application.Config.App.BaseURL,
 		application.Repositories.TelegramRepository,
 		application.Logger,
 	)
@@ -124,19 +148,37 @@ func initServices(application *app.Application, jwtManager *auth.JWTManager) (*a
 		application.Repositories.ProjectRepository,
 		application.Repositories.UserRepository,
 		application.Repositories.TaskRepository,
+		application.Repositories.ProjectMetricsRepository,
+		application.Repositories.NotificationRepository,
+		application.Repositories.TxManager,
 		application.Repositories.CacheRepository,
 		application.Messaging.Producer,
 		application.Logger,
 	)
 
+	// Права для политик доступа маршрутов
+	accessService := service.NewAccessService(
+		application.Repositories.UserRepository,
+		projectService,
+	)
+
+	taskViewService := service.NewTaskViewService(
+		application.Repositories.TaskViewRepository,
+		application.Repositories.CacheRepository,
+		application.Logger,
+	)
+
 	taskService := service.NewTaskService(
 		application.Repositories.TaskRepository,
 		application.Repositories.ProjectRepository,
 		application.Repositories.UserRepository,
 		application.Repositories.CommentRepository,
+		application.Repositories.AttachmentRepository,
+		application.Repositories.TxManager,
 		application.Repositories.CacheRepository,
 		application.Messaging.Producer,
 		projectService,
+		taskViewService,
 		application.Logger,
 	)
 
@@ -145,6 +187,7 @@ func initServices(application *app.Application, jwtManager *auth.JWTManager) (*a
 		application.Repositories.TaskRepository,
 		application.Repositories.UserRepository,
 		taskService,
+		application.Repositories.CacheRepository,
 		application.Messaging.Producer,
 		application.Logger,
 	)
@@ -152,16 +195,369 @@ func initServices(application *app.Application, jwtManager *auth.JWTManager) (*a
 	notificationService := service.NewNotificationService(
 		application.Repositories.NotificationRepository,
 		application.Repositories.UserRepository,
+		application.Logger,
+	)
+
+	unsubscribeService := service.NewUnsubscribeService(
+		application.Repositories.NotificationRepository,
+		auth.NewUnsubscribeManager(
+			application.Config.Notifier.Unsubscribe.Secret,
+			application.Config.Notifier.Unsubscribe.ExpiresIn,
+		),
+		application.Config.App.BaseURL,
+		application.Logger,
+	)
+
+	featureFlagService := service.NewFeatureFlagService(
+		&application.Config.Features,
+		application.Repositories.CacheRepository,
+		application.Repositories.UserRepository,
+		application.Logger,
+	)
+
+	searchService := service.NewSearchService(
+		application.Repositories.SearchRepository,
+		application.Repositories.UserRepository,
+		projectService,
+		featureFlagService,
+		application.Logger,
+	)
+
+	taskFormService := service.NewTaskFormService(
+		application.Repositories.TaskFormRepository,
+		application.Repositories.TaskRepository,
+		application.Repositories.ProjectRepository,
+		projectService,
+		taskService,
+		application.Logger,
+	)
+
+	taskTemplateService := service.NewTaskTemplateService(
+		application.Repositories.TaskTemplateRepository,
+		projectService,
+		taskService,
+		application.Logger,
+	)
+
+	scheduledTaskService := service.NewScheduledTaskService(
+		application.Repositories.ScheduledTaskRepository,
+		projectService,
+		taskService,
+		application.Logger,
+	)
+
+	inboundEmailService := service.NewInboundEmailService(
+		application.Repositories.InboundEmailRepository,
+		application.Repositories.UserRepository,
+		taskService,
+		&application.Config.Inbound,
+		application.Logger,
+	)
+
+	projectWebhookService := service.NewProjectWebhookService(
+		application.Repositories.ProjectWebhookRepository,
+		taskService,
+		application.Config.App.BaseURL,
+		application.Logger,
+	)
+
+	incidentService := service.NewIncidentService(
+		application.Repositories.IncidentRepository,
+		taskService,
+		&application.Config.Incidents,
+		application.Config.App.BaseURL,
+		application.Logger,
+	)
+
+	teamsService := service.NewTeamsService(
+		application.Repositories.TeamsRepository,
+		service.NewTeamsSender(&application.Config.Notifier.Teams, application.Config.App.BaseURL, application.Logger),
+		application.Logger,
+	)
+
+	discordService := service.NewDiscordService(
+		application.Repositories.DiscordRepository,
+		service.NewDiscordSender(&application.Config.Notifier.Discord, application.Config.App.BaseURL, application.Logger),
+		application.Logger,
+	)
+
+	matrixService := service.NewMatrixService(
+		application.Repositories.MatrixRepository,
+		service.NewMatrixSender(&application.Config.Notifier.Matrix, application.Config.App.BaseURL, application.Logger),
+		application.Logger,
+	)
+
+	smsService := service.NewSMSService(
+		application.Repositories.SMSRepository,
+		service.NewSMSSender(&application.Config.Notifier.SMS, application.Repositories.SMSRepository, application.Logger),
+		application.Config.Notifier.SMS.CodeTTL,
+		application.Logger,
+	)
+
+	emailSender := service.NewEmailSender(&application.Config.Notifier.SMTP, application.Logger)
+
+	feedbackService := service.NewFeedbackService(
+		application.Repositories.FeedbackRepository,
+		application.Repositories.ProjectRepository,
+		taskService,
+		application.Repositories.TxManager,
+		emailSender,
+		application.Config.App.BaseURL,
+		application.Logger,
+	)
+
+	roadmapService := service.NewRoadmapService(
+		application.Repositories.RoadmapRepository,
+		application.Repositories.ProjectRepository,
+		application.Repositories.CacheRepository,
+		application.Config.App.BaseURL,
+		application.Logger,
+	)
+
+	boardService := service.NewBoardService(
+		application.Repositories.BoardRepository,
+		application.Repositories.TaskRepository,
+		projectService,
+		taskService,
+		application.Logger,
+	)
+
+	epicService := service.NewEpicService(
+		application.Repositories.EpicRepository,
+		application.Repositories.TaskRepository,
+		application.Repositories.ProjectRepository,
+		application.Repositories.UserRepository,
+		projectService,
+		taskService,
+		boardService,
+		application.Logger,
+	)
+
+	sprintService := service.NewSprintService(
+		application.Repositories.SprintRepository,
+		application.Repositories.TaskRepository,
+		projectService,
+		taskService,
+		application.Logger,
+	)
+
+	okrService := service.NewOKRService(
+		application.Repositories.OKRRepository,
+		application.Repositories.EpicRepository,
+		application.Repositories.UserRepository,
+		projectService,
+		application.Logger,
+	)
+
+	decisionService := service.NewDecisionService(
+		application.Repositories.DecisionRepository,
+		application.Repositories.TaskRepository,
+		application.Repositories.ProjectRepository,
+		projectService,
+		taskService,
+		application.Logger,
+	)
+
+	wikiService := service.NewWikiService(
+		application.Repositories.WikiRepository,
+		application.Repositories.TaskRepository,
+		projectService,
+		taskService,
+		application.Logger,
+	)
+
+	meetingNoteService := service.NewMeetingNoteService(
+		application.Repositories.MeetingNoteRepository,
+		application.Repositories.TaskRepository,
+		application.Repositories.ProjectRepository,
+		application.Repositories.UserRepository,
+		projectService,
+		taskService,
+		application.Logger,
+	)
+
+	projectSplitService := service.NewProjectSplitService(
+		application.Repositories.ProjectRepository,
+		application.Repositories.TaskRepository,
+		application.Repositories.EpicRepository,
+		application.Repositories.CacheRepository,
+		application.Messaging.Producer,
+		projectService,
+		application.Logger,
+	)
+
+	projectRosterService := service.NewProjectRosterService(
+		application.Repositories.InvitationRepository,
+		application.Repositories.ProjectRepository,
+		application.Repositories.UserRepository,
+		projectService,
+		emailSender,
+		application.Config.App.BaseURL,
+		application.Logger,
+	)
+
+	inboxService := service.NewInboxService(
+		application.Repositories.InboxRepository,
+		application.Repositories.NotificationRepository,
+		application.Repositories.TxManager,
+		taskService,
+		application.Messaging.Producer,
+		application.Logger,
+	)
+
+	maintenanceService := service.NewMaintenanceService(
+		application.Repositories.CacheRepository,
+		application.Logger,
+	)
+	// При несовместимой схеме или по флагу конфигурации (миграции, разбор инцидентов)
+	// экземпляр допускается только к чтению данных
+	switch {
+	case !application.Schema.Compatible():
+		maintenanceService.ForceReadOnly(application.Schema.Problem())
+	case application.Config.HTTP.ReadOnly:
+		maintenanceService.ForceReadOnly(application.Config.HTTP.ReadOnlyMessage)
+	}
+
+	consistencyService := service.NewConsistencyService(
+		application.Repositories.ConsistencyRepository,
+		application.Repositories.NotificationRepository,
+		application.Repositories.CacheRepository,
+		application.Logger,
+	)
+
+	jobRunService := service.NewJobRunService(
+		application.Repositories.JobRunRepository,
+		application.Logger,
+	)
+
+	auditService := service.NewAuditService(
+		application.Repositories.AuditRepository,
+		application.Logger,
+	)
+
+	projectViewService := service.NewProjectViewService(
+		application.Repositories.ProjectViewRepository,
+		application.Repositories.ProjectRepository,
+		application.Logger,
+	)
+
+	taskRecurrenceService := service.NewTaskRecurrenceService(
+		application.Repositories.TaskRecurrenceRepository,
+		application.Repositories.TaskRepository,
+		projectService,
+		taskService,
+		application.Repositories.TxManager,
+		application.Logger,
+	)
+
+	attachmentService := service.NewAttachmentService(
+		application.Repositories.AttachmentRepository,
+		application.Repositories.TaskRepository,
+		application.Repositories.CommentRepository,
+		application.Storage,
+		projectService,
+		taskService,
+		application.Config.Storage.MaxFileSize,
+		application.Logger,
+	)
+
+	// Страница статуса проверяет компоненты сервиса в фоне; без PostgreSQL и Redis API не работает,
+	// а недоступность Kafka и Telegram означает работу с перебоями
+	statusService := service.NewStatusService(
+		[]service.StatusCheck{
+			{Name: "database", Critical: true, Check: application.DB.PingContext},
+			{Name: "redis", Critical: true, Check: application.Repositories.CacheRepository.Ping},
+			{Name: "kafka", Check: application.Messaging.Producer.Ping},
+			{Name: "telegram", Check: service.BreakerCheck(circuit.Default.Get("telegram"))},
+		},
+		application.Repositories.StatusNoteRepository,
+		application.Repositories.CacheRepository,
+		application.Logger,
+	)
+	statusService.StartChecks(application.Config.App.Context)
+
+	// События об изменении задач, проектов и участников сбрасывают их кэш и зависящие от них
+	// ответы HTTP-кэша: сразу при публикации и по событиям из Kafka, в том числе других процессов
+	application.Messaging.Producer.AddListener(application.Repositories.CacheRepository.InvalidateEvent)
+
+	// Изменения, о которых сервисы сообщают событиями, записываются в журнал аудита с подробностями
+	application.Messaging.Producer.AddListener(auditService.RecordEvent)
+
+	cacheInvalidationService := service.NewCacheInvalidationService(
+		application.Repositories.CacheRepository,
+		application.Logger,
+	)
+	cacheInvalidationTopics := service.CacheInvalidationTopics{
+		Task: []string{
+			application.Config.Kafka.Topics.TaskCreated,
+			application.Config.Kafka.Topics.TaskUpdated,
+			application.Config.Kafka.Topics.TaskAssigned,
+		},
+		Comment: []string{
+			application.Config.Kafka.Topics.TaskCommented,
+		},
+		Project: []string{
+			application.Config.Kafka.Topics.ProjectCreated,
+			application.Config.Kafka.Topics.ProjectUpdated,
+		},
+		Member: []string{
+			application.Config.Kafka.Topics.ProjectMemberAdded,
+			application.Config.Kafka.Topics.ProjectMemberRemoved,
+		},
+	}
+	if err := cacheInvalidationService.Start(application.Config.App.Context, &application.Config.Kafka, cacheInvalidationTopics); err != nil {
+		return nil, err
+	}
+
+	// Изменения счетчиков непрочитанных уведомлений из всех процессов доставляются
+	// потокам клиентов, подключенным к этому экземпляру
+	unreadCountHub := service.NewUnreadCountHub(
 		application.Repositories.CacheRepository,
 		application.Logger,
 	)
+	unreadCountHub.Start(application.Config.App.Context)
 
 	return &api.Services{
-		UserService:         userService,
-		ProjectService:      projectService,
-		TaskService:         taskService,
-		CommentService:      commentService,
-		NotificationService: notificationService,
-		TelegramService:     telegramSender,
+		UserService:           userService,
+		ProjectService:        projectService,
+		TaskService:           taskService,
+		CommentService:        commentService,
+		NotificationService:   notificationService,
+		TelegramService:       telegramSender,
+		UnsubscribeService:    unsubscribeService,
+		SearchService:         searchService,
+		TaskFormService:       taskFormService,
+		TaskTemplateService:   taskTemplateService,
+		ScheduledTaskService:  scheduledTaskService,
+		InboundEmailService:   inboundEmailService,
+		ProjectWebhookService: projectWebhookService,
+		IncidentService:       incidentService,
+		TeamsService:          teamsService,
+		DiscordService:        discordService,
+		MatrixService:         matrixService,
+		SMSService:            smsService,
+		FeedbackService:       feedbackService,
+		RoadmapService:        roadmapService,
+		EpicService:           epicService,
+		BoardService:          boardService,
+		SprintService:         sprintService,
+		OKRService:            okrService,
+		DecisionService:       decisionService,
+		WikiService:           wikiService,
+		MeetingNoteService:    meetingNoteService,
+		ProjectSplitService:   projectSplitService,
+		MaintenanceService:    maintenanceService,
+		FeatureFlagService:    featureFlagService,
+		ProjectRosterService:  projectRosterService,
+		InboxService:          inboxService,
+		StatusService:         statusService,
+		ConsistencyService:    consistencyService,
+		JobRunService:         jobRunService,
+		AuditService:          auditService,
+		ProjectViewService:    projectViewService,
+		TaskRecurrenceService: taskRecurrenceService,
+		AttachmentService:     attachmentService,
+		AccessService:         accessService,
+		UnreadCountHub:        unreadCountHub,
 	}, nil
 }
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/nurlyy/task_manager/internal/backup"
+	"github.com/nurlyy/task_manager/pkg/cache"
+	"github.com/nurlyy/task_manager/pkg/config"
+	"github.com/nurlyy/task_manager/pkg/database"
+	applogger "github.com/nurlyy/task_manager/pkg/logger"
+	"github.com/nurlyy/task_manager/pkg/storage"
+)
+
+const usage = `Usage:
+  backup create -dir <path>
+  backup restore -dir <path> -confirm [-replay-due] [-verify-attachments]`
+
+// Команда создает резервную копию данных приложения или восстанавливает ее.
+// Подключения к базе, Redis и хранилищу вложений берутся из конфигурации
+func main() {
+	if len(os.Args) < 2 {
+		log.Fatal(usage)
+	}
+	command := os.Args[1]
+
+	flags := flag.NewFlagSet(command, flag.ExitOnError)
+	dir := flags.String("dir", "", "backup directory")
+	confirm := flags.Bool("confirm", false, "confirm that the configured database and Redis are overwritten by the backup")
+	replayDue := flags.Bool("replay-due", false, "send delayed notifications that became due before the restore instead of dropping them")
+	verifyAttachments := flags.Bool("verify-attachments", false, "check that every attachment in the backup exists in storage")
+	flags.Parse(os.Args[2:])
+
+	if *dir == "" {
+		log.Fatal(usage)
+	}
+	if command != "create" && command != "restore" {
+		log.Fatalf("Unknown command %q\n%s", command, usage)
+	}
+	if command == "restore" && !*confirm {
+		log.Fatal("Restore replaces the configured database and Redis state: pass -confirm to proceed")
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	// Загружаем конфигурацию
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("Invalid config: %v", err)
+	}
+
+	// Инициализируем логгер
+	logger, err := applogger.NewLogger(cfg.App.LogLevel, false)
+	if err != nil {
+		log.Fatalf("Failed to initialize logger: %v", err)
+	}
+
+	postgres, err := database.NewPostgres(ctx, &cfg.Database, logger)
+	if err != nil {
+		logger.Fatal("Failed to connect to PostgreSQL", err)
+	}
+	defer postgres.Close()
+
+	redis, err := cache.NewRedis(ctx, &cfg.Redis, logger)
+	if err != nil {
+		logger.Fatal("Failed to connect to Redis", err)
+	}
+	defer redis.Close()
+
+	fileStorage, err := storage.New(&cfg.Storage)
+	if err != nil {
+		logger.Fatal("Failed to initialize storage", err)
+	}
+
+	service := backup.NewService(postgres.DB, &cfg.Database, redis.Client, cfg.Kafka.Delays.Key, fileStorage, logger)
+
+	switch command {
+	case "create":
+		metadata, err := service.Create(ctx, *dir)
+		if err != nil {
+			logger.Fatal("Failed to create backup", err)
+		}
+		logger.Info("Backup created", map[string]interface{}{
+			"dir":         *dir,
+			"created_at":  metadata.CreatedAt,
+			"wal_lsn":     metadata.WALLSN,
+			"redis_keys":  metadata.RedisKeys,
+			"attachments": metadata.Attachments,
+		})
+
+	case "restore":
+		report, err := service.Restore(ctx, *dir, backup.RestoreOptions{
+			ReplayDue:         *replayDue,
+			VerifyAttachments: *verifyAttachments,
+		})
+		if err != nil {
+			logger.Fatal("Failed to restore backup", err)
+		}
+		logger.Info("Backup restored", map[string]interface{}{
+			"created_at":          report.Metadata.CreatedAt,
+			"redis_keys":          report.RedisKeys,
+			"cache_keys_deleted":  report.CacheKeysDeleted,
+			"delayed_dropped":     report.DelayedDropped,
+			"missing_attachments": len(report.MissingAttachments),
+		})
+		for _, key := range report.MissingAttachments {
+			fmt.Println(key)
+		}
+	}
+}
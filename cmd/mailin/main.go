@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/nurlyy/task_manager/internal/app"
+	"github.com/nurlyy/task_manager/internal/service"
+	"github.com/nurlyy/task_manager/pkg/config"
+	applogger "github.com/nurlyy/task_manager/pkg/logger"
+)
+
+func main() {
+	validateConfig := flag.Bool("validate-config", false, "print the resolved configuration with secrets masked and exit")
+	flag.Parse()
+
+	// Инициализируем контекст приложения
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Загружаем конфигурацию
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	// Проверяем конфигурацию: по флагу только выводим результат, иначе не запускаемся с ошибками
+	if *validateConfig {
+		if err := cfg.Report(os.Stdout); err != nil {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("Invalid config: %v", err)
+	}
+
+	// Обновляем контекст приложения в конфигурации
+	cfg.App.Context = ctx
+
+	// Инициализируем логгер
+	logger, err := applogger.NewLogger(cfg.App.LogLevel, false)
+	if err != nil {
+		log.Fatalf("Failed to initialize logger: %v", err)
+	}
+
+	logger.Info("Starting inbound email service")
+
+	// Инициализируем основное приложение
+	application, err := app.NewApplication(ctx, cfg, logger)
+	if err != nil {
+		logger.Fatal("Failed to initialize application", err)
+	}
+	defer application.Close()
+
+	// Фоновые задачи изменяют данные, поэтому при несовместимой схеме не запускаются
+	if err := application.RequireCompatibleSchema(); err != nil {
+		logger.Fatal("Refusing to start worker", err)
+	}
+
+	// Задачи из писем создаются через сервис задач со всеми проверками и уведомлениями
+	projectService := service.NewProjectService(
+		application.Repositories.ProjectRepository,
+		application.Repositories.UserRepository,
+		application.Repositories.TaskRepository,
+		application.Repositories.ProjectMetricsRepository,
+		application.Repositories.NotificationRepository,
+		application.Repositories.TxManager,
+		application.Repositories.CacheRepository,
+		application.Messaging.Producer,
+		logger,
+	)
+
+	taskViewService := service.NewTaskViewService(
+		application.Repositories.TaskViewRepository,
+		application.Repositories.CacheRepository,
+		logger,
+	)
+
+	taskService := service.NewTaskService(
+		application.Repositories.TaskRepository,
+		application.Repositories.ProjectRepository,
+		application.Repositories.UserRepository,
+		application.Repositories.CommentRepository,
+		application.Repositories.AttachmentRepository,
+		application.Repositories.TxManager,
+		application.Repositories.CacheRepository,
+		application.Messaging.Producer,
+		projectService,
+		taskViewService,
+		logger,
+	)
+
+	inboundEmailService := service.NewInboundEmailService(
+		application.Repositories.InboundEmailRepository,
+		application.Repositories.UserRepository,
+		taskService,
+		&cfg.Inbound,
+		logger,
+	)
+
+	// Запускаем воркер приема почты
+	if err := inboundEmailService.Start(ctx); err != nil {
+		logger.Fatal("Failed to start inbound email service", err)
+	}
+
+	// Создаем канал для перехвата сигналов остановки
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+
+	// Блокируем основную горутину до получения сигнала остановки
+	<-stop
+	logger.Info("Shutting down inbound email service")
+	cancel()
+
+	// Создаем контекст с таймаутом для остановки
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer shutdownCancel()
+
+	// Ожидаем завершения всех задач
+	<-shutdownCtx.Done()
+	logger.Info("Inbound email service stopped")
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"flag"
 	"log"
 	"os"
 	"os/signal"
@@ -15,6 +16,9 @@ import (
 )
 
 func main() {
+	validateConfig := flag.Bool("validate-config", false, "print the resolved configuration with secrets masked and exit")
+	flag.Parse()
+
 	// Инициализируем контекст приложения
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -25,6 +29,17 @@ func main() {
 		log.Fatalf("Failed to load config: %v", err)
 	}
 
+	// Проверяем конфигурацию: по флагу только выводим результат, иначе не запускаемся с ошибками
+	if *validateConfig {
+		if err := cfg.Report(os.Stdout); err != nil {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("Invalid config: %v", err)
+	}
+
 	// Обновляем контекст приложения в конфигурации
 	cfg.App.Context = ctx
 
@@ -43,6 +58,11 @@ func main() {
 	}
 	defer application.Close()
 
+	// Фоновые задачи изменяют данные, поэтому при несовместимой схеме не запускаются
+	if err := application.RequireCompatibleSchema(); err != nil {
+		logger.Fatal("Refusing to start worker", err)
+	}
+
 	// Инициализируем сервис уведомлений
 	notifierService := service.NewNotifierService(
 		application.Repositories.NotificationRepository,
@@ -50,16 +70,88 @@ func main() {
 		application.Repositories.TaskRepository,
 		application.Repositories.ProjectRepository,
 		application.Repositories.TelegramRepository,
-		cfg.Kafka.Brokers,
+		application.Repositories.TeamsRepository,
+		application.Repositories.DiscordRepository,
+		application.Repositories.MatrixRepository,
+		application.Repositories.SMSRepository,
+		&cfg.Kafka,
+		cfg.App.BaseURL,
 		&cfg.Notifier,
 		logger,
 	)
 
+	// Запускаем сервер метрик, в том числе состояния выключателей внешних сервисов
+	// и чтения уведомлений из Kafka
+	for _, metrics := range notifierService.Metrics() {
+		application.AddMetrics(metrics)
+	}
+	application.StartMetricsServer()
+
 	// Запускаем сервис уведомлений
 	if err := notifierService.Start(ctx); err != nil {
 		logger.Fatal("Failed to start notifier service", err)
 	}
 
+	// Переходы задач, связанных с инцидентами, подтверждают и закрывают инциденты PagerDuty и Opsgenie
+	projectService := service.NewProjectService(
+		application.Repositories.ProjectRepository,
+		application.Repositories.UserRepository,
+		application.Repositories.TaskRepository,
+		application.Repositories.ProjectMetricsRepository,
+		application.Repositories.NotificationRepository,
+		application.Repositories.TxManager,
+		application.Repositories.CacheRepository,
+		application.Messaging.Producer,
+		logger,
+	)
+
+	taskViewService := service.NewTaskViewService(
+		application.Repositories.TaskViewRepository,
+		application.Repositories.CacheRepository,
+		logger,
+	)
+
+	taskService := service.NewTaskService(
+		application.Repositories.TaskRepository,
+		application.Repositories.ProjectRepository,
+		application.Repositories.UserRepository,
+		application.Repositories.CommentRepository,
+		application.Repositories.AttachmentRepository,
+		application.Repositories.TxManager,
+		application.Repositories.CacheRepository,
+		application.Messaging.Producer,
+		projectService,
+		taskViewService,
+		logger,
+	)
+
+	incidentService := service.NewIncidentService(
+		application.Repositories.IncidentRepository,
+		taskService,
+		&cfg.Incidents,
+		cfg.App.BaseURL,
+		logger,
+	)
+
+	if err := incidentService.StartSync(ctx, &cfg.Kafka, cfg.Kafka.Topics.TaskUpdated); err != nil {
+		logger.Fatal("Failed to start incident sync", err)
+	}
+
+	// Авторы запросов с портала обратной связи получают письма при смене статуса задачи
+	feedbackService := service.NewFeedbackService(
+		application.Repositories.FeedbackRepository,
+		application.Repositories.ProjectRepository,
+		taskService,
+		application.Repositories.TxManager,
+		service.NewEmailSender(&cfg.Notifier.SMTP, logger),
+		cfg.App.BaseURL,
+		logger,
+	)
+
+	if err := feedbackService.StartStatusUpdates(ctx, &cfg.Kafka, cfg.Kafka.Topics.TaskUpdated); err != nil {
+		logger.Fatal("Failed to start feedback status updates", err)
+	}
+
 	// Создаем канал для перехвата сигналов остановки
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"flag"
 	"log"
 	"os"
 	"os/signal"
@@ -9,12 +10,16 @@ import (
 	"time"
 
 	"github.com/nurlyy/task_manager/internal/app"
+	"github.com/nurlyy/task_manager/internal/messaging"
 	"github.com/nurlyy/task_manager/internal/service"
 	"github.com/nurlyy/task_manager/pkg/config"
 	applogger "github.com/nurlyy/task_manager/pkg/logger"
 )
 
 func main() {
+	validateConfig := flag.Bool("validate-config", false, "print the resolved configuration with secrets masked and exit")
+	flag.Parse()
+
 	// Инициализируем контекст приложения
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -25,6 +30,17 @@ func main() {
 		log.Fatalf("Failed to load config: %v", err)
 	}
 
+	// Проверяем конфигурацию: по флагу только выводим результат, иначе не запускаемся с ошибками
+	if *validateConfig {
+		if err := cfg.Report(os.Stdout); err != nil {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("Invalid config: %v", err)
+	}
+
 	// Обновляем контекст приложения в конфигурации
 	cfg.App.Context = ctx
 
@@ -43,17 +59,126 @@ func main() {
 	}
 	defer application.Close()
 
+	// Фоновые задачи изменяют данные, поэтому при несовместимой схеме не запускаются
+	if err := application.RequireCompatibleSchema(); err != nil {
+		logger.Fatal("Refusing to start worker", err)
+	}
+
+	// Отложенные задачи создаются через сервис задач со всеми проверками и уведомлениями
+	projectService := service.NewProjectService(
+		application.Repositories.ProjectRepository,
+		application.Repositories.UserRepository,
+		application.Repositories.TaskRepository,
+		application.Repositories.ProjectMetricsRepository,
+		application.Repositories.NotificationRepository,
+		application.Repositories.TxManager,
+		application.Repositories.CacheRepository,
+		application.Messaging.Producer,
+		logger,
+	)
+
+	taskViewService := service.NewTaskViewService(
+		application.Repositories.TaskViewRepository,
+		application.Repositories.CacheRepository,
+		logger,
+	)
+
+	taskService := service.NewTaskService(
+		application.Repositories.TaskRepository,
+		application.Repositories.ProjectRepository,
+		application.Repositories.UserRepository,
+		application.Repositories.CommentRepository,
+		application.Repositories.AttachmentRepository,
+		application.Repositories.TxManager,
+		application.Repositories.CacheRepository,
+		application.Messaging.Producer,
+		projectService,
+		taskViewService,
+		logger,
+	)
+
+	scheduledTaskService := service.NewScheduledTaskService(
+		application.Repositories.ScheduledTaskRepository,
+		projectService,
+		taskService,
+		logger,
+	)
+
+	taskRecurrenceService := service.NewTaskRecurrenceService(
+		application.Repositories.TaskRecurrenceRepository,
+		application.Repositories.TaskRepository,
+		projectService,
+		taskService,
+		application.Repositories.TxManager,
+		logger,
+	)
+
+	attachmentService := service.NewAttachmentService(
+		application.Repositories.AttachmentRepository,
+		application.Repositories.TaskRepository,
+		application.Repositories.CommentRepository,
+		application.Storage,
+		projectService,
+		taskService,
+		cfg.Storage.MaxFileSize,
+		logger,
+	)
+
+	inboxService := service.NewInboxService(
+		application.Repositories.InboxRepository,
+		application.Repositories.NotificationRepository,
+		application.Repositories.TxManager,
+		taskService,
+		application.Messaging.Producer,
+		logger,
+	)
+
+	consistencyService := service.NewConsistencyService(
+		application.Repositories.ConsistencyRepository,
+		application.Repositories.NotificationRepository,
+		application.Repositories.CacheRepository,
+		logger,
+	)
+
 	// Инициализируем сервис планировщика
 	schedulerService := service.NewSchedulerService(
 		application.Repositories.TaskRepository,
 		application.Repositories.UserRepository,
 		application.Repositories.ProjectRepository,
 		application.Repositories.NotificationRepository,
+		application.Repositories.ProjectMetricsRepository,
+		application.Repositories.NotificationRepository,
+		scheduledTaskService,
+		taskRecurrenceService,
+		attachmentService,
+		inboxService,
+		projectService,
+		consistencyService,
+		taskViewService,
+		application.Repositories.CacheRepository,
+		application.Repositories.JobRunRepository,
 		application.Messaging.Producer,
 		&cfg.Scheduler,
 		logger,
 	)
 
+	// Метрики рассылки дайджестов и очереди отложенных уведомлений отдаются сервером метрик планировщика
+	application.AddMetrics(schedulerService.DigestMetrics())
+	application.AddMetrics(application.Messaging.Delays)
+	application.StartMetricsServer()
+
+	// Запускаем публикацию отложенных уведомлений
+	go application.Messaging.Delays.Run(ctx, application.Messaging.Producer)
+
+	// Запускаем публикацию событий, записанных сервисами в таблицу исходящих событий
+	outboxRelay := messaging.NewOutboxRelay(
+		application.Repositories.OutboxRepository,
+		application.Repositories.TxManager,
+		&cfg.Kafka.Outbox,
+		logger,
+	)
+	go outboxRelay.Run(ctx, application.Messaging.Producer)
+
 	// Запускаем планировщик
 	if err := schedulerService.Start(ctx); err != nil {
 		logger.Fatal("Failed to start scheduler service", err)
@@ -3,6 +3,7 @@ module github.com/nurlyy/task_manager
 go 1.21
 
 require (
+	github.com/andybalholm/brotli v1.0.6
 	github.com/go-chi/chi/v5 v5.0.10
 	github.com/go-chi/cors v1.2.1
 	github.com/go-playground/validator/v10 v10.15.5
@@ -16,6 +17,9 @@ require (
 	github.com/rs/zerolog v1.31.0
 	github.com/segmentio/kafka-go v0.4.44
 	golang.org/x/crypto v0.14.0
+	golang.org/x/net v0.17.0
+	golang.org/x/sync v0.5.0
+	golang.org/x/time v0.5.0
 )
 
 require (
@@ -29,7 +33,6 @@ require (
 	github.com/mattn/go-colorable v0.1.13 // indirect
 	github.com/mattn/go-isatty v0.0.19 // indirect
 	github.com/pierrec/lz4/v4 v4.1.18 // indirect
-	golang.org/x/net v0.17.0 // indirect
 	golang.org/x/sys v0.13.0 // indirect
 	golang.org/x/text v0.13.0 // indirect
 )
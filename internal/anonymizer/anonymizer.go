@@ -0,0 +1,306 @@
+// Package anonymizer переносит данные рабочего окружения на тестовый стенд, заменяя
+// персональные данные и секреты псевдонимами
+package anonymizer
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/nurlyy/task_manager/pkg/logger"
+)
+
+// Anonymizer копирует данные из базы источника в базу стенда. Схема стенда должна быть
+// создана миграциями заранее; ее данные заменяются полностью
+type Anonymizer struct {
+	source *sqlx.DB
+	target *sqlx.DB
+	logger logger.Logger
+}
+
+// New создает новый экземпляр Anonymizer
+func New(source, target *sqlx.DB, logger logger.Logger) *Anonymizer {
+	return &Anonymizer{
+		source: source,
+		target: target,
+		logger: logger,
+	}
+}
+
+// Run заменяет данные стенда обезличенной копией источника. Источник читается одним
+// снимком только для чтения, стенд заполняется в одной транзакции: при ошибке его данные
+// не меняются. Все пользователи стенда получают пароль password
+func (a *Anonymizer) Run(ctx context.Context, password string) error {
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("failed to hash staging password: %w", err)
+	}
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return fmt.Errorf("failed to generate pseudonym key: %w", err)
+	}
+	p := &pseudonymizer{key: key, hashedPassword: string(hashedPassword)}
+
+	tables, err := a.orderedTables(ctx)
+	if err != nil {
+		return err
+	}
+	if err := a.checkTextColumns(ctx, tables); err != nil {
+		return err
+	}
+
+	source, err := a.source.BeginTxx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return fmt.Errorf("failed to begin source snapshot: %w", err)
+	}
+	defer source.Rollback()
+
+	target, err := a.target.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin target transaction: %w", err)
+	}
+	defer target.Rollback()
+
+	quoted := make([]string, len(tables))
+	for i, table := range tables {
+		quoted[i] = pq.QuoteIdentifier(table)
+	}
+	if _, err := target.ExecContext(ctx, "TRUNCATE "+strings.Join(quoted, ", ")); err != nil {
+		return fmt.Errorf("failed to truncate target tables: %w", err)
+	}
+
+	for _, table := range tables {
+		if skippedTables[table] {
+			a.logger.Info("Skipping table", map[string]interface{}{"table": table})
+			continue
+		}
+
+		count, err := a.copyTable(ctx, source, target, table, p)
+		if err != nil {
+			return fmt.Errorf("failed to copy table %s: %w", table, err)
+		}
+		a.logger.Info("Table copied", map[string]interface{}{
+			"table": table,
+			"rows":  count,
+		})
+	}
+
+	if err := target.Commit(); err != nil {
+		return fmt.Errorf("failed to commit target transaction: %w", err)
+	}
+	return nil
+}
+
+// orderedTables возвращает таблицы стенда в порядке, в котором их можно заполнять:
+// таблица идет после таблиц, на которые ссылаются ее внешние ключи
+func (a *Anonymizer) orderedTables(ctx context.Context) ([]string, error) {
+	var tables []string
+	if err := a.target.SelectContext(ctx, &tables, `
+		SELECT table_name
+		FROM information_schema.tables
+		WHERE table_schema = 'public' AND table_type = 'BASE TABLE'
+		ORDER BY table_name
+	`); err != nil {
+		return nil, fmt.Errorf("failed to list target tables: %w", err)
+	}
+
+	var references []struct {
+		Child  string `db:"child"`
+		Parent string `db:"parent"`
+	}
+	if err := a.target.SelectContext(ctx, &references, `
+		SELECT child.relname AS child, parent.relname AS parent
+		FROM pg_constraint c
+		JOIN pg_class child ON child.oid = c.conrelid
+		JOIN pg_class parent ON parent.oid = c.confrelid
+		WHERE c.contype = 'f' AND c.connamespace = 'public'::regnamespace
+	`); err != nil {
+		return nil, fmt.Errorf("failed to list foreign keys: %w", err)
+	}
+
+	parents := make(map[string]map[string]bool, len(tables))
+	for _, table := range tables {
+		parents[table] = make(map[string]bool)
+	}
+	for _, ref := range references {
+		// Ссылки таблицы на саму себя проверяются в конце копирования таблицы
+		if ref.Child != ref.Parent {
+			parents[ref.Child][ref.Parent] = true
+		}
+	}
+
+	ordered := make([]string, 0, len(tables))
+	for len(parents) > 0 {
+		var ready []string
+		for table, deps := range parents {
+			if len(deps) == 0 {
+				ready = append(ready, table)
+			}
+		}
+		if len(ready) == 0 {
+			remaining := make([]string, 0, len(parents))
+			for table := range parents {
+				remaining = append(remaining, table)
+			}
+			sort.Strings(remaining)
+			return nil, fmt.Errorf("foreign keys form a cycle between tables: %s", strings.Join(remaining, ", "))
+		}
+
+		sort.Strings(ready)
+		for _, table := range ready {
+			delete(parents, table)
+			for _, deps := range parents {
+				delete(deps, table)
+			}
+		}
+		ordered = append(ordered, ready...)
+	}
+	return ordered, nil
+}
+
+// checkTextColumns проверяет, что для каждой текстовой колонки переносимых таблиц есть
+// правило замены или она проверена и копируется как есть. Иначе новая колонка
+// с персональными данными попала бы на стенд без изменений
+func (a *Anonymizer) checkTextColumns(ctx context.Context, tables []string) error {
+	var columns []struct {
+		Table  string `db:"table_name"`
+		Column string `db:"column_name"`
+	}
+	if err := a.target.SelectContext(ctx, &columns, `
+		SELECT table_name, column_name
+		FROM information_schema.columns
+		WHERE table_schema = 'public'
+			AND (data_type IN ('text', 'character varying', 'character', 'json', 'jsonb')
+				OR udt_name IN ('_text', '_varchar', '_bpchar'))
+		ORDER BY table_name, ordinal_position
+	`); err != nil {
+		return fmt.Errorf("failed to list text columns: %w", err)
+	}
+
+	copied := make(map[string]bool, len(tables))
+	for _, table := range tables {
+		copied[table] = !skippedTables[table]
+	}
+
+	var unreviewed []string
+	for _, c := range columns {
+		if copied[c.Table] && !reviewed(c.Table, c.Column) {
+			unreviewed = append(unreviewed, c.Table+"."+c.Column)
+		}
+	}
+	if len(unreviewed) > 0 {
+		return fmt.Errorf("text columns without anonymization rule: %s", strings.Join(unreviewed, ", "))
+	}
+	return nil
+}
+
+// copyTable копирует строки таблицы, заменяя значения колонок по правилам. Копируются
+// колонки, которые есть и в источнике, и на стенде; значения передаются в текстовом виде,
+// поэтому копирование не зависит от типов колонок
+func (a *Anonymizer) copyTable(ctx context.Context, source, target *sqlx.Tx, table string, p *pseudonymizer) (int, error) {
+	columns, err := commonColumns(ctx, source, target, table)
+	if err != nil {
+		return 0, err
+	}
+	if len(columns) == 0 {
+		return 0, nil
+	}
+
+	selects := make([]string, len(columns))
+	tableRules := make([]rule, len(columns))
+	for i, column := range columns {
+		selects[i] = pq.QuoteIdentifier(column) + "::text"
+		tableRules[i] = rules[table][column]
+	}
+
+	rows, err := source.QueryContext(ctx, fmt.Sprintf("SELECT %s FROM %s", strings.Join(selects, ", "), pq.QuoteIdentifier(table)))
+	if err != nil {
+		return 0, fmt.Errorf("failed to read source rows: %w", err)
+	}
+	defer rows.Close()
+
+	stmt, err := target.PrepareContext(ctx, pq.CopyIn(table, columns...))
+	if err != nil {
+		return 0, fmt.Errorf("failed to start copy: %w", err)
+	}
+	defer stmt.Close()
+
+	count := 0
+	values := make([]sql.NullString, len(columns))
+	dest := make([]interface{}, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	args := make([]interface{}, len(columns))
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return count, fmt.Errorf("failed to scan source row: %w", err)
+		}
+
+		for i, value := range values {
+			args[i] = nil
+			if !value.Valid {
+				continue
+			}
+			args[i] = value.String
+			if tableRules[i] != nil && value.String != "" {
+				if replaced := tableRules[i](p, value.String); replaced != nil {
+					args[i] = *replaced
+				} else {
+					args[i] = nil
+				}
+			}
+		}
+
+		if _, err := stmt.ExecContext(ctx, args...); err != nil {
+			return count, fmt.Errorf("failed to copy row: %w", err)
+		}
+		count++
+	}
+	if err := rows.Err(); err != nil {
+		return count, fmt.Errorf("failed to read source rows: %w", err)
+	}
+
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		return count, fmt.Errorf("failed to finish copy: %w", err)
+	}
+	return count, nil
+}
+
+// commonColumns возвращает колонки таблицы стенда, которые есть и в источнике.
+// Колонки, добавленные на стенде новыми миграциями, получают значения по умолчанию
+func commonColumns(ctx context.Context, source, target *sqlx.Tx, table string) ([]string, error) {
+	const query = `
+		SELECT column_name
+		FROM information_schema.columns
+		WHERE table_schema = 'public' AND table_name = $1
+		ORDER BY ordinal_position
+	`
+
+	var targetColumns, sourceColumns []string
+	if err := target.SelectContext(ctx, &targetColumns, query, table); err != nil {
+		return nil, fmt.Errorf("failed to list target columns: %w", err)
+	}
+	if err := source.SelectContext(ctx, &sourceColumns, query, table); err != nil {
+		return nil, fmt.Errorf("failed to list source columns: %w", err)
+	}
+
+	inSource := make(map[string]bool, len(sourceColumns))
+	for _, column := range sourceColumns {
+		inSource[column] = true
+	}
+	columns := make([]string, 0, len(targetColumns))
+	for _, column := range targetColumns {
+		if inSource[column] {
+			columns = append(columns, column)
+		}
+	}
+	return columns, nil
+}
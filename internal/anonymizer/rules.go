@@ -0,0 +1,348 @@
+package anonymizer
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+	"unicode/utf8"
+)
+
+// skippedTables - таблицы, данные которых не переносятся: сессии, журналы и служебные
+// очереди рабочего окружения не нужны на стенде и содержат адреса и тела запросов
+var skippedTables = map[string]bool{
+	"refresh_tokens":       true,
+	"audit_log":            true,
+	"job_runs":             true,
+	"attachment_deletions": true,
+	"event_outbox":         true,
+}
+
+// rule заменяет значение колонки; nil - NULL. Пустые значения не заменяются
+type rule func(p *pseudonymizer, value string) *string
+
+// rules перечисляет колонки с персональными данными и секретами по таблицам.
+// ID строк не меняются, поэтому связи между таблицами сохраняются, а одинаковые
+// значения в разных таблицах заменяются одинаково. Свободный текст заменяется целиком:
+// в описания задач попадают подписи авторов обращений и тела входящих писем
+var rules = map[string]map[string]rule{
+	"users": {
+		"email":           (*pseudonymizer).email,
+		"first_name":      (*pseudonymizer).firstName,
+		"last_name":       (*pseudonymizer).lastName,
+		"avatar":          null,
+		"hashed_password": (*pseudonymizer).password,
+		"position":        null,
+		"department":      null,
+	},
+	"user_telegram_links": {
+		"telegram_id": (*pseudonymizer).numericID,
+		"chat_id":     (*pseudonymizer).numericID,
+		"username":    (*pseudonymizer).username,
+		"first_name":  (*pseudonymizer).firstName,
+		"last_name":   (*pseudonymizer).lastName,
+	},
+	"user_sms_settings": {
+		"phone":     (*pseudonymizer).phone,
+		"code_hash": null,
+	},
+	"user_teams_links": {
+		"webhook_url": (*pseudonymizer).url,
+	},
+	"project_teams_channels": {
+		"webhook_url": (*pseudonymizer).url,
+	},
+	"project_discord_channels": {
+		"webhook_url": (*pseudonymizer).url,
+		"channel_id":  (*pseudonymizer).numericID,
+	},
+	"project_matrix_rooms": {
+		"room_id":    (*pseudonymizer).matrixRoom,
+		"room_alias": null,
+	},
+	"project_webhooks": {
+		"secret": (*pseudonymizer).secret,
+	},
+	"incident_integrations": {
+		"secret":     (*pseudonymizer).secret,
+		"api_key":    (*pseudonymizer).secret,
+		"from_email": (*pseudonymizer).email,
+	},
+	"project_inbound_emails": {
+		"token": (*pseudonymizer).secret,
+	},
+	"inbound_email_messages": {
+		"sender":     (*pseudonymizer).email,
+		"message_id": (*pseudonymizer).messageID,
+	},
+	"project_invitations": {
+		"email":      (*pseudonymizer).email,
+		"token_hash": (*pseudonymizer).secret,
+	},
+	"feedback_portals": {
+		"public_key": (*pseudonymizer).secret,
+	},
+	"feedback_submissions": {
+		"submitter_email": (*pseudonymizer).email,
+		"submitter_name":  (*pseudonymizer).fullName,
+		"title":           (*pseudonymizer).text,
+		"description":     (*pseudonymizer).text,
+		"moderation_note": (*pseudonymizer).text,
+	},
+	"tasks": {
+		"title":       (*pseudonymizer).text,
+		"description": (*pseudonymizer).text,
+	},
+	"task_history": {
+		"old_value": (*pseudonymizer).text,
+		"new_value": (*pseudonymizer).text,
+	},
+	"task_form_values": {
+		"form_values": emptyObject,
+	},
+	"scheduled_tasks": {
+		"payload": jsonFields("title", "description"),
+	},
+	"time_logs": {
+		"description": (*pseudonymizer).text,
+	},
+	"inbox_items": {
+		"title":       (*pseudonymizer).text,
+		"description": (*pseudonymizer).text,
+	},
+	"attachments": {
+		"file_name": (*pseudonymizer).fileName,
+	},
+	"comments": {
+		"content": (*pseudonymizer).text,
+	},
+	"epic_comments": {
+		"content": (*pseudonymizer).text,
+	},
+	"notifications": {
+		"title":     (*pseudonymizer).text,
+		"content":   (*pseudonymizer).text,
+		"meta_data": null,
+	},
+	"objectives": {
+		"description": (*pseudonymizer).text,
+	},
+	"status_notes": {
+		"message": (*pseudonymizer).text,
+	},
+	"wiki_pages": {
+		"content": (*pseudonymizer).text,
+	},
+	"wiki_page_revisions": {
+		"content": (*pseudonymizer).text,
+	},
+	"meeting_notes": {
+		"content": (*pseudonymizer).text,
+	},
+	"meeting_note_tasks": {
+		"item_text": (*pseudonymizer).text,
+	},
+	"decisions": {
+		"context":      (*pseudonymizer).text,
+		"decision":     (*pseudonymizer).text,
+		"alternatives": emptyObject,
+	},
+	"project_roadmaps": {
+		"public_key": (*pseudonymizer).secret,
+	},
+}
+
+// reviewedColumns перечисляет текстовые колонки, которые проверены и копируются как есть:
+// названия, настройки и служебные значения без персональных данных. Текстовая колонка,
+// которой нет ни здесь, ни в rules, останавливает перенос, пока ее не проверят
+var reviewedColumns = map[string][]string{
+	"attachments":              {"content_type", "storage_key"},
+	"board_columns":            {"name"},
+	"decisions":                {"title"},
+	"epics":                    {"title", "goal"},
+	"feedback_portals":         {"title", "description"},
+	"feedback_submissions":     {"status", "notified_task_status"},
+	"incident_integrations":    {"provider", "mapping"},
+	"incident_links":           {"incident_id", "status", "url"},
+	"key_results":              {"title"},
+	"meeting_notes":            {"title"},
+	"membership_history":       {"action"},
+	"notifications":            {"entity_type"},
+	"objectives":               {"title"},
+	"ownership_transfers":      {"status"},
+	"project_discord_channels": {"notification_types"},
+	"project_invitations":      {"role"},
+	"project_matrix_rooms":     {"notification_types"},
+	"project_metrics":          {"tasks_by_status", "tasks_by_user"},
+	"project_metrics_history":  {"tasks_by_status"},
+	"project_roadmaps":         {"title", "description", "label"},
+	"project_task_settings":    {"required_fields", "transition_rules"},
+	"project_teams_channels":   {"notification_types"},
+	"project_views":            {"name", "layout", "filters", "sort_by", "sort_dir", "group_by"},
+	"project_webhooks":         {"name", "mapping"},
+	"projects":                 {"name", "description"},
+	"scheduled_tasks":          {"status", "error"},
+	"sprints":                  {"name", "goal"},
+	"status_notes":             {"title", "severity"},
+	"task_forms":               {"fields"},
+	"task_history":             {"field"},
+	"task_recurrences":         {"rule", "status", "last_error"},
+	"task_tags":                {"tag"},
+	"task_templates":           {"name", "title", "description", "checklist", "tags"},
+	"tasks":                    {"rank"},
+	"user_skills":              {"skill"},
+	"wiki_page_revisions":      {"title"},
+	"wiki_pages":               {"title"},
+}
+
+// reviewed сообщает, есть ли для колонки правило замены или она проверена и копируется как есть
+func reviewed(table, column string) bool {
+	if _, ok := rules[table][column]; ok {
+		return true
+	}
+	for _, kept := range reviewedColumns[table] {
+		if kept == column {
+			return true
+		}
+	}
+	return false
+}
+
+// Имена, из которых составляются псевдонимы пользователей
+var (
+	firstNames = []string{"Alex", "Sam", "Robin", "Jordan", "Taylor", "Casey", "Morgan", "Jamie", "Avery", "Riley", "Quinn", "Drew"}
+	lastNames  = []string{"Smith", "Brown", "Miller", "Wilson", "Moore", "Clark", "Lewis", "Walker", "Young", "King", "Hill", "Green"}
+)
+
+// loremWords - текст, которым заменяются описания, комментарии и другой свободный текст
+var loremWords = strings.Fields("lorem ipsum dolor sit amet consectetur adipiscing elit sed do eiusmod tempor incididunt ut labore et dolore magna aliqua")
+
+// pseudonymizer заменяет значения псевдонимами. Псевдоним определяется значением
+// и ключом запуска: одинаковые значения получают одинаковые псевдонимы, а восстановить
+// исходное значение без ключа нельзя
+type pseudonymizer struct {
+	key            []byte
+	hashedPassword string // Хеш пароля, который получают все пользователи стенда
+}
+
+// digest возвращает HMAC значения с ключом запуска
+func (p *pseudonymizer) digest(value string) []byte {
+	mac := hmac.New(sha256.New, p.key)
+	mac.Write([]byte(value))
+	return mac.Sum(nil)
+}
+
+// token возвращает шестнадцатеричный псевдоним значения длиной n символов
+func (p *pseudonymizer) token(value string, n int) string {
+	return hex.EncodeToString(p.digest(value))[:n]
+}
+
+// number возвращает числовой псевдоним значения
+func (p *pseudonymizer) number(value string) uint64 {
+	return binary.BigEndian.Uint64(p.digest(value))
+}
+
+func (p *pseudonymizer) email(value string) *string {
+	return ptr(fmt.Sprintf("user-%s@example.invalid", p.token(strings.ToLower(value), 16)))
+}
+
+func (p *pseudonymizer) firstName(value string) *string {
+	return ptr(firstNames[p.number("first:"+value)%uint64(len(firstNames))])
+}
+
+func (p *pseudonymizer) lastName(value string) *string {
+	return ptr(lastNames[p.number("last:"+value)%uint64(len(lastNames))])
+}
+
+func (p *pseudonymizer) fullName(value string) *string {
+	return ptr(*p.firstName(value) + " " + *p.lastName(value))
+}
+
+func (p *pseudonymizer) username(value string) *string {
+	return ptr("user_" + p.token(value, 10))
+}
+
+func (p *pseudonymizer) password(string) *string {
+	return ptr(p.hashedPassword)
+}
+
+func (p *pseudonymizer) numericID(value string) *string {
+	return ptr(fmt.Sprint(1_000_000_000 + p.number(value)%9_000_000_000))
+}
+
+func (p *pseudonymizer) phone(value string) *string {
+	return ptr(fmt.Sprintf("+1555%07d", p.number(value)%10_000_000))
+}
+
+func (p *pseudonymizer) url(value string) *string {
+	return ptr("https://example.invalid/hooks/" + p.token(value, 32))
+}
+
+func (p *pseudonymizer) matrixRoom(value string) *string {
+	return ptr("!" + p.token(value, 18) + ":example.invalid")
+}
+
+func (p *pseudonymizer) secret(value string) *string {
+	return ptr(p.token(value, 64))
+}
+
+func (p *pseudonymizer) messageID(value string) *string {
+	return ptr("<" + p.token(value, 32) + "@example.invalid>")
+}
+
+// fileName заменяет имя файла, сохраняя расширение
+func (p *pseudonymizer) fileName(value string) *string {
+	return ptr("file-" + p.token(value, 16) + path.Ext(value))
+}
+
+// text заменяет текст на lorem ipsum примерно той же длины
+func (p *pseudonymizer) text(value string) *string {
+	length := utf8.RuneCountInString(value)
+
+	var b strings.Builder
+	for i := 0; b.Len() < length; i++ {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(loremWords[i%len(loremWords)])
+	}
+	return ptr(b.String()[:length])
+}
+
+// jsonFields заменяет текстом строковые значения указанных полей JSON-объекта.
+// Значение, которое не разбирается как объект, заменяется пустым объектом
+func jsonFields(fields ...string) rule {
+	return func(p *pseudonymizer, value string) *string {
+		var object map[string]interface{}
+		if err := json.Unmarshal([]byte(value), &object); err != nil || object == nil {
+			return emptyObject(p, value)
+		}
+		for _, field := range fields {
+			if text, ok := object[field].(string); ok && text != "" {
+				object[field] = *p.text(text)
+			}
+		}
+		data, err := json.Marshal(object)
+		if err != nil {
+			return emptyObject(p, value)
+		}
+		return ptr(string(data))
+	}
+}
+
+// emptyObject заменяет значение пустым объектом JSON или пустым массивом PostgreSQL
+func emptyObject(*pseudonymizer, string) *string {
+	return ptr("{}")
+}
+
+func null(*pseudonymizer, string) *string {
+	return nil
+}
+
+func ptr(value string) *string {
+	return &value
+}
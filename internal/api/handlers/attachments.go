@@ -0,0 +1,275 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/internal/service"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
+)
+
+// attachmentFormMemory - часть multipart-формы, которая держится в памяти; остальное пишется во временные файлы
+const attachmentFormMemory = 8 << 20
+
+// AttachmentHandler обрабатывает запросы, связанные с вложениями задач и комментариев
+type AttachmentHandler struct {
+	BaseHandler
+	attachmentService *service.AttachmentService
+}
+
+// NewAttachmentHandler создает новый экземпляр AttachmentHandler
+func NewAttachmentHandler(base BaseHandler, attachmentService *service.AttachmentService) *AttachmentHandler {
+	return &AttachmentHandler{
+		BaseHandler:       base,
+		attachmentService: attachmentService,
+	}
+}
+
+// UploadTaskAttachment прикрепляет к задаче файл из поля file multipart-формы
+func (h *AttachmentHandler) UploadTaskAttachment(w http.ResponseWriter, r *http.Request) {
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	taskID := h.GetURLParam(r, "id")
+	if taskID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Task ID is required")
+		return
+	}
+
+	upload, cleanup, ok := h.parseUpload(w, r)
+	if !ok {
+		return
+	}
+	defer cleanup()
+
+	attachment, err := h.attachmentService.UploadToTask(r.Context(), taskID, upload, userID)
+	if err != nil {
+		h.handleAttachmentError(w, r, err, taskID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, attachment)
+}
+
+// ListTaskAttachments возвращает вложения задачи, включая вложения ее комментариев
+func (h *AttachmentHandler) ListTaskAttachments(w http.ResponseWriter, r *http.Request) {
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	taskID := h.GetURLParam(r, "id")
+	if taskID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Task ID is required")
+		return
+	}
+
+	attachments, err := h.attachmentService.ListByTask(r.Context(), taskID, userID)
+	if err != nil {
+		h.handleAttachmentError(w, r, err, taskID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, attachments)
+}
+
+// UploadCommentAttachment прикрепляет к комментарию файл из поля file multipart-формы
+func (h *AttachmentHandler) UploadCommentAttachment(w http.ResponseWriter, r *http.Request) {
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	commentID := h.GetURLParam(r, "id")
+	if commentID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Comment ID is required")
+		return
+	}
+
+	upload, cleanup, ok := h.parseUpload(w, r)
+	if !ok {
+		return
+	}
+	defer cleanup()
+
+	attachment, err := h.attachmentService.UploadToComment(r.Context(), commentID, upload, userID)
+	if err != nil {
+		h.handleAttachmentError(w, r, err, commentID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, attachment)
+}
+
+// ListCommentAttachments возвращает вложения комментария
+func (h *AttachmentHandler) ListCommentAttachments(w http.ResponseWriter, r *http.Request) {
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	commentID := h.GetURLParam(r, "id")
+	if commentID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Comment ID is required")
+		return
+	}
+
+	attachments, err := h.attachmentService.ListByComment(r.Context(), commentID, userID)
+	if err != nil {
+		h.handleAttachmentError(w, r, err, commentID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, attachments)
+}
+
+// GetAttachment возвращает метаданные вложения
+func (h *AttachmentHandler) GetAttachment(w http.ResponseWriter, r *http.Request) {
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	attachmentID := h.GetURLParam(r, "id")
+	if attachmentID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Attachment ID is required")
+		return
+	}
+
+	attachment, err := h.attachmentService.GetByID(r.Context(), attachmentID, userID)
+	if err != nil {
+		h.handleAttachmentError(w, r, err, attachmentID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, attachment)
+}
+
+// DownloadAttachment отдает содержимое вложения как файл для сохранения
+func (h *AttachmentHandler) DownloadAttachment(w http.ResponseWriter, r *http.Request) {
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	attachmentID := h.GetURLParam(r, "id")
+	if attachmentID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Attachment ID is required")
+		return
+	}
+
+	attachment, content, err := h.attachmentService.Open(r.Context(), attachmentID, userID)
+	if err != nil {
+		h.handleAttachmentError(w, r, err, attachmentID)
+		return
+	}
+	defer content.Close()
+
+	// Файл всегда скачивается, а не открывается в браузере: тип содержимого задает загрузивший его пользователь
+	w.Header().Set("Content-Type", attachment.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(attachment.Size, 10))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": attachment.FileName}))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+
+	if _, err := io.Copy(w, content); err != nil {
+		// Ответ уже начат, клиент получит обрезанный файл
+		h.Logger.Error("Failed to stream attachment", err, map[string]interface{}{
+			"id": attachmentID,
+		})
+	}
+}
+
+// DeleteAttachment удаляет вложение
+func (h *AttachmentHandler) DeleteAttachment(w http.ResponseWriter, r *http.Request) {
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	attachmentID := h.GetURLParam(r, "id")
+	if attachmentID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Attachment ID is required")
+		return
+	}
+
+	if err := h.attachmentService.Delete(r.Context(), attachmentID, userID); err != nil {
+		h.handleAttachmentError(w, r, err, attachmentID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, map[string]bool{"success": true})
+}
+
+// parseUpload читает файл из поля file multipart-формы. Возвращаемая функция
+// удаляет временные файлы формы и должна быть вызвана после обработки загрузки
+func (h *AttachmentHandler) parseUpload(w http.ResponseWriter, r *http.Request) (domain.AttachmentUpload, func(), bool) {
+	if err := r.ParseMultipartForm(attachmentFormMemory); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			h.RespondWithError(w, r, apperrors.CodeAttachmentTooLarge, "Attachment exceeds the maximum file size")
+			return domain.AttachmentUpload{}, nil, false
+		}
+		h.RespondWithError(w, r, apperrors.CodeInvalidFormat, "Request must be a multipart form")
+		return domain.AttachmentUpload{}, nil, false
+	}
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		r.MultipartForm.RemoveAll()
+		h.RespondWithError(w, r, apperrors.CodeInvalidFormat, "File is required in the file field")
+		return domain.AttachmentUpload{}, nil, false
+	}
+
+	upload := domain.AttachmentUpload{
+		FileName:    header.Filename,
+		ContentType: header.Header.Get("Content-Type"),
+		Size:        header.Size,
+		Content:     file,
+	}
+	cleanup := func() {
+		file.Close()
+		r.MultipartForm.RemoveAll()
+	}
+	return upload, cleanup, true
+}
+
+// handleAttachmentError преобразует ошибки вложений в HTTP-ответы
+func (h *AttachmentHandler) handleAttachmentError(w http.ResponseWriter, r *http.Request, err error, id string) {
+	switch {
+	case errors.Is(err, service.ErrAttachmentNotFound):
+		h.RespondWithError(w, r, apperrors.CodeAttachmentNotFound, "Attachment not found")
+	case errors.Is(err, service.ErrAttachmentTooLarge):
+		h.RespondWithError(w, r, apperrors.CodeAttachmentTooLarge, "Attachment exceeds the maximum file size")
+	case errors.Is(err, service.ErrTaskNotFound):
+		h.RespondWithError(w, r, apperrors.CodeTaskNotFound, "Task not found")
+	case errors.Is(err, service.ErrCommentNotFound):
+		h.RespondWithError(w, r, apperrors.CodeCommentNotFound, "Comment not found")
+	case errors.Is(err, service.ErrTaskAccessDenied):
+		h.RespondWithError(w, r, apperrors.CodeAccessDenied, "Access denied to the task")
+	case errors.Is(err, service.ErrInsufficientRights):
+		h.RespondWithError(w, r, apperrors.CodeInsufficientRights, "Insufficient rights to change attachments")
+	case errors.Is(err, service.ErrProjectNotFound):
+		h.RespondWithError(w, r, apperrors.CodeProjectNotFound, "Project not found")
+	case errors.Is(err, service.ErrProjectArchived):
+		h.RespondWithError(w, r, apperrors.CodeProjectArchived, "Project is archived and read-only")
+	default:
+		h.Logger.Error("Failed to process attachment", err, map[string]interface{}{
+			"id": id,
+		})
+		h.RespondWithError(w, r, apperrors.CodeAttachmentFailed, "Failed to process attachment")
+	}
+}
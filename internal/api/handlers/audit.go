@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/nurlyy/task_manager/internal/api/query"
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/internal/service"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
+)
+
+// AuditHandler обрабатывает запросы журнала аудита
+type AuditHandler struct {
+	BaseHandler
+	auditService *service.AuditService
+}
+
+// NewAuditHandler создает новый экземпляр AuditHandler
+func NewAuditHandler(base BaseHandler, auditService *service.AuditService) *AuditHandler {
+	return &AuditHandler{
+		BaseHandler:  base,
+		auditService: auditService,
+	}
+}
+
+var auditActions = []domain.AuditAction{
+	domain.AuditActionCreate, domain.AuditActionUpdate, domain.AuditActionDelete,
+}
+
+// auditFilterFromQuery разбирает параметры фильтрации журнала аудита
+func auditFilterFromQuery(q *query.Parser) domain.AuditFilterOptions {
+	from, to := q.DateRange("from", "to")
+	return domain.AuditFilterOptions{
+		ActorID:    q.String("actor_id"),
+		Action:     query.Enum(q, "action", auditActions...),
+		EntityType: q.String("entity_type"),
+		EntityID:   q.String("entity_id"),
+		From:       from,
+		To:         to,
+	}
+}
+
+// ListAudit возвращает журнал аудита с фильтрацией по автору, виду изменения,
+// сущности и периоду, начиная с последних записей
+func (h *AuditHandler) ListAudit(w http.ResponseWriter, r *http.Request) {
+	q := query.New(r)
+	filter := auditFilterFromQuery(q)
+
+	page, ok := h.ParseQuery(w, r, q)
+	if !ok {
+		return
+	}
+
+	result, err := h.auditService.List(r.Context(), filter, page)
+	if err != nil {
+		h.handleAuditError(w, r, err)
+		return
+	}
+
+	h.RespondWithPagination(w, r, result.Items, result)
+}
+
+// ExportAudit выгружает журнал аудита по тем же фильтрам потоком в виде JSON-массива
+func (h *AuditHandler) ExportAudit(w http.ResponseWriter, r *http.Request) {
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	q := query.New(r)
+	filter := auditFilterFromQuery(q)
+	if !h.CheckQuery(w, r, q) {
+		return
+	}
+
+	// Заголовок ответа отправляется вместе с первой записью, поэтому отказ в доступе
+	// еще возвращается обычным ответом
+	var stream *JSONArrayStream
+	err = h.auditService.Export(r.Context(), filter, userID, func(record *domain.AuditRecord) error {
+		if stream == nil {
+			stream = h.NewJSONArrayStream(w)
+		}
+		return stream.Write(record)
+	})
+	if err != nil {
+		if stream != nil {
+			// Ответ уже начат: обрываем его без закрывающих скобок, чтобы клиент получил некорректный JSON
+			h.Logger.Error("Failed to stream audit export", err)
+			return
+		}
+		h.handleAuditError(w, r, err)
+		return
+	}
+
+	if stream == nil {
+		stream = h.NewJSONArrayStream(w)
+	}
+	if err := stream.Close(); err != nil {
+		h.Logger.Error("Failed to finish audit export", err)
+	}
+}
+
+// handleAuditError преобразует ошибки сервиса в ответы API
+func (h *AuditHandler) handleAuditError(w http.ResponseWriter, r *http.Request, err error) {
+	h.Logger.Error("Failed to get audit log", err)
+	h.RespondWithError(w, r, apperrors.CodeAuditFetchFailed, "Failed to get audit log")
+}
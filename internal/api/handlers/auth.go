@@ -6,6 +6,7 @@ import (
 
 	"github.com/nurlyy/task_manager/internal/domain"
 	"github.com/nurlyy/task_manager/internal/service"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
 )
 
 // AuthHandler обрабатывает запросы, связанные с аутентификацией
@@ -27,14 +28,14 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 	var req domain.UserCreateRequest
 	if err := h.ParseJSON(r, &req); err != nil {
 		h.Logger.Error("Failed to parse register request", err)
-		h.RespondWithError(w, r, http.StatusBadRequest, "Invalid request format", "invalid_format")
+		h.RespondWithError(w, r, apperrors.CodeInvalidFormat, "Invalid request format")
 		return
 	}
 
 	// Валидация запроса
-	if validationErrors, err := h.ValidateRequest(req); err != nil {
+	if validationErrors, err := h.ValidateRequest(r, req); err != nil {
 		h.Logger.Error("Request validation error", err)
-		h.RespondWithError(w, r, http.StatusInternalServerError, "Validation failed", "validation_error")
+		h.RespondWithError(w, r, apperrors.CodeInternalError, "Validation failed")
 		return
 	} else if len(validationErrors) > 0 {
 		h.RespondWithValidationErrors(w, r, validationErrors)
@@ -45,11 +46,11 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 	user, err := h.userService.Create(r.Context(), req)
 	if err != nil {
 		if errors.Is(err, service.ErrEmailAlreadyExists) {
-			h.RespondWithError(w, r, http.StatusConflict, "Email already exists", "email_exists")
+			h.RespondWithError(w, r, apperrors.CodeEmailExists, "Email already exists")
 			return
 		}
 		h.Logger.Error("Failed to create user", err)
-		h.RespondWithError(w, r, http.StatusInternalServerError, "Failed to create user", "creation_failed")
+		h.RespondWithError(w, r, apperrors.CodeCreationFailed, "Failed to create user")
 		return
 	}
 
@@ -61,14 +62,14 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 	var req domain.LoginRequest
 	if err := h.ParseJSON(r, &req); err != nil {
 		h.Logger.Error("Failed to parse login request", err)
-		h.RespondWithError(w, r, http.StatusBadRequest, "Invalid request format", "invalid_format")
+		h.RespondWithError(w, r, apperrors.CodeInvalidFormat, "Invalid request format")
 		return
 	}
 
 	// Валидация запроса
-	if validationErrors, err := h.ValidateRequest(req); err != nil {
+	if validationErrors, err := h.ValidateRequest(r, req); err != nil {
 		h.Logger.Error("Request validation error", err)
-		h.RespondWithError(w, r, http.StatusInternalServerError, "Validation failed", "validation_error")
+		h.RespondWithError(w, r, apperrors.CodeInternalError, "Validation failed")
 		return
 	} else if len(validationErrors) > 0 {
 		h.RespondWithValidationErrors(w, r, validationErrors)
@@ -79,11 +80,11 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 	response, err := h.userService.Login(r.Context(), req)
 	if err != nil {
 		if errors.Is(err, service.ErrInvalidCredentials) {
-			h.RespondWithError(w, r, http.StatusUnauthorized, "Invalid credentials", "invalid_credentials")
+			h.RespondWithError(w, r, apperrors.CodeInvalidCredentials, "Invalid credentials")
 			return
 		}
 		h.Logger.Error("Login failed", err)
-		h.RespondWithError(w, r, http.StatusInternalServerError, "Login failed", "login_failed")
+		h.RespondWithError(w, r, apperrors.CodeLoginFailed, "Login failed")
 		return
 	}
 
@@ -95,14 +96,14 @@ func (h *AuthHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
 	var req domain.RefreshTokenRequest
 	if err := h.ParseJSON(r, &req); err != nil {
 		h.Logger.Error("Failed to parse refresh token request", err)
-		h.RespondWithError(w, r, http.StatusBadRequest, "Invalid request format", "invalid_format")
+		h.RespondWithError(w, r, apperrors.CodeInvalidFormat, "Invalid request format")
 		return
 	}
 
 	// Валидация запроса
-	if validationErrors, err := h.ValidateRequest(req); err != nil {
+	if validationErrors, err := h.ValidateRequest(r, req); err != nil {
 		h.Logger.Error("Request validation error", err)
-		h.RespondWithError(w, r, http.StatusInternalServerError, "Validation failed", "validation_error")
+		h.RespondWithError(w, r, apperrors.CodeInternalError, "Validation failed")
 		return
 	} else if len(validationErrors) > 0 {
 		h.RespondWithValidationErrors(w, r, validationErrors)
@@ -113,11 +114,11 @@ func (h *AuthHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
 	response, err := h.userService.RefreshToken(r.Context(), req)
 	if err != nil {
 		if errors.Is(err, service.ErrUserNotFound) || errors.Is(err, service.ErrInvalidCredentials) {
-			h.RespondWithError(w, r, http.StatusUnauthorized, "Invalid refresh token", "invalid_token")
+			h.RespondWithError(w, r, apperrors.CodeInvalidToken, "Invalid refresh token")
 			return
 		}
 		h.Logger.Error("Token refresh failed", err)
-		h.RespondWithError(w, r, http.StatusInternalServerError, "Token refresh failed", "refresh_failed")
+		h.RespondWithError(w, r, apperrors.CodeRefreshFailed, "Token refresh failed")
 		return
 	}
 
@@ -128,21 +129,21 @@ func (h *AuthHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
 func (h *AuthHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
 	userID, err := h.GetUserIDFromContext(r)
 	if err != nil {
-		h.RespondWithError(w, r, http.StatusUnauthorized, "Unauthorized", "unauthorized")
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
 		return
 	}
 
 	var req domain.ChangePasswordRequest
 	if err := h.ParseJSON(r, &req); err != nil {
 		h.Logger.Error("Failed to parse change password request", err)
-		h.RespondWithError(w, r, http.StatusBadRequest, "Invalid request format", "invalid_format")
+		h.RespondWithError(w, r, apperrors.CodeInvalidFormat, "Invalid request format")
 		return
 	}
 
 	// Валидация запроса
-	if validationErrors, err := h.ValidateRequest(req); err != nil {
+	if validationErrors, err := h.ValidateRequest(r, req); err != nil {
 		h.Logger.Error("Request validation error", err)
-		h.RespondWithError(w, r, http.StatusInternalServerError, "Validation failed", "validation_error")
+		h.RespondWithError(w, r, apperrors.CodeInternalError, "Validation failed")
 		return
 	} else if len(validationErrors) > 0 {
 		h.RespondWithValidationErrors(w, r, validationErrors)
@@ -152,11 +153,11 @@ func (h *AuthHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
 	// Изменение пароля
 	if err := h.userService.ChangePassword(r.Context(), userID, req); err != nil {
 		if errors.Is(err, service.ErrInvalidPassword) {
-			h.RespondWithError(w, r, http.StatusBadRequest, "Invalid old password", "invalid_password")
+			h.RespondWithError(w, r, apperrors.CodeInvalidPassword, "Invalid old password")
 			return
 		}
 		h.Logger.Error("Change password failed", err)
-		h.RespondWithError(w, r, http.StatusInternalServerError, "Change password failed", "password_change_failed")
+		h.RespondWithError(w, r, apperrors.CodePasswordChangeFailed, "Change password failed")
 		return
 	}
 
@@ -167,18 +168,18 @@ func (h *AuthHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
 func (h *AuthHandler) GetCurrentUser(w http.ResponseWriter, r *http.Request) {
 	userID, err := h.GetUserIDFromContext(r)
 	if err != nil {
-		h.RespondWithError(w, r, http.StatusUnauthorized, "Unauthorized", "unauthorized")
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
 		return
 	}
 
 	user, err := h.userService.GetByID(r.Context(), userID)
 	if err != nil {
 		if errors.Is(err, service.ErrUserNotFound) {
-			h.RespondWithError(w, r, http.StatusNotFound, "User not found", "user_not_found")
+			h.RespondWithError(w, r, apperrors.CodeUserNotFound, "User not found")
 			return
 		}
 		h.Logger.Error("Failed to get current user", err)
-		h.RespondWithError(w, r, http.StatusInternalServerError, "Failed to get user info", "user_fetch_failed")
+		h.RespondWithError(w, r, apperrors.CodeUserFetchFailed, "Failed to get user info")
 		return
 	}
 
@@ -6,13 +6,16 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
-	"github.com/go-playground/validator/v10"
 
+	"github.com/nurlyy/task_manager/internal/api/query"
 	"github.com/nurlyy/task_manager/internal/domain"
 	"github.com/nurlyy/task_manager/pkg/auth"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
 	"github.com/nurlyy/task_manager/pkg/logger"
+	"github.com/nurlyy/task_manager/pkg/validator"
 )
 
 // StandardResponseData представляет стандартную структуру ответа API
@@ -24,38 +27,28 @@ type StandardResponseData struct {
 	Meta         interface{} `json:"meta,omitempty"`
 }
 
-// ErrorResponse представляет структуру ответа с ошибкой
-type ErrorResponse struct {
-	Success      bool   `json:"success"`
-	ErrorMessage string `json:"error"`
-	ErrorCode    string `json:"error_code,omitempty"`
-}
-
-// ValidationError представляет ошибку валидации
-type ValidationError struct {
-	Field   string `json:"field"`
-	Message string `json:"message"`
-}
+// ErrorResponse представляет структуру ответа с ошибкой: сообщение, код из каталога,
+// ссылку на описание кода и ошибки полей
+type ErrorResponse = apperrors.Response
 
-// ValidationErrorResponse представляет структуру ответа с ошибками валидации
-type ValidationErrorResponse struct {
-	Success bool              `json:"success"`
-	Error   string            `json:"error"`
-	Errors  []ValidationError `json:"errors"`
-}
+// ValidationError представляет ошибку валидации поля
+type ValidationError = apperrors.FieldError
 
-// PaginationMeta представляет метаданные для постраничной навигации
+// PaginationMeta представляет метаданные для постраничной навигации. Если клиент
+// отключил подсчет (count=false), итогов нет, а следующую страницу указывает next_cursor
 type PaginationMeta struct {
-	TotalItems  int `json:"total_items"`
-	TotalPages  int `json:"total_pages"`
-	CurrentPage int `json:"current_page"`
-	PageSize    int `json:"page_size"`
+	TotalItems  *int   `json:"total_items,omitempty"`
+	TotalPages  *int   `json:"total_pages,omitempty"`
+	CurrentPage int    `json:"current_page"`
+	PageSize    int    `json:"page_size"`
+	HasMore     bool   `json:"has_more"`
+	NextCursor  string `json:"next_cursor,omitempty"`
 }
 
 // BaseHandler содержит общие методы для всех обработчиков
 type BaseHandler struct {
 	Logger     logger.Logger
-	Validator  *validator.Validate
+	Validator  *validator.CustomValidator
 	JWTManager *auth.JWTManager
 }
 
@@ -63,7 +56,7 @@ type BaseHandler struct {
 func NewBaseHandler(logger logger.Logger, jwtManager *auth.JWTManager) BaseHandler {
 	return BaseHandler{
 		Logger:     logger,
-		Validator:  validator.New(),
+		Validator:  newRequestValidator(),
 		JWTManager: jwtManager,
 	}
 }
@@ -91,35 +84,41 @@ func (h *BaseHandler) RespondWithSuccess(w http.ResponseWriter, r *http.Request,
 	h.Respond(w, r, http.StatusOK, response)
 }
 
-// RespondWithError отправляет ответ с ошибкой
-func (h *BaseHandler) RespondWithError(w http.ResponseWriter, r *http.Request, statusCode int, errorMsg string, errorCode string) {
-	response := ErrorResponse{
-		Success:      false,
-		ErrorMessage: errorMsg,
-		ErrorCode:    errorCode,
-	}
-	h.Respond(w, r, statusCode, response)
+// RespondWithError отправляет ответ с ошибкой. HTTP-статус определяется кодом из каталога
+func (h *BaseHandler) RespondWithError(w http.ResponseWriter, r *http.Request, errorCode apperrors.Code, errorMsg string) {
+	h.respondWithErrorResponse(w, r, apperrors.Status(errorCode), apperrors.NewResponse(errorCode, errorMsg))
 }
 
 // RespondWithValidationErrors отправляет ответ с ошибками валидации
 func (h *BaseHandler) RespondWithValidationErrors(w http.ResponseWriter, r *http.Request, errors []ValidationError) {
-	response := ValidationErrorResponse{
-		Success: false,
-		Error:   "Validation failed",
-		Errors:  errors,
+	h.respondWithErrorResponse(w, r, apperrors.Status(apperrors.CodeValidationFailed),
+		apperrors.NewResponse(apperrors.CodeValidationFailed, "Validation failed", errors...))
+}
+
+// respondWithErrorResponse отправляет ответ с ошибкой в обычном JSON или, если клиент
+// запросил его в Accept, в формате application/problem+json (RFC 7807)
+func (h *BaseHandler) respondWithErrorResponse(w http.ResponseWriter, r *http.Request, statusCode int, response ErrorResponse) {
+	if err := apperrors.WriteResponse(w, r, statusCode, response); err != nil {
+		h.Logger.Error("Failed to encode error response", err)
 	}
-	h.Respond(w, r, http.StatusBadRequest, response)
 }
 
-// RespondWithPagination отправляет ответ с пагинацией
+// RespondWithPagination отправляет ответ с пагинацией и ссылками на соседние
+// страницы в заголовке Link (RFC 8288)
 func (h *BaseHandler) RespondWithPagination(w http.ResponseWriter, r *http.Request, data interface{}, pagedResponse *domain.PagedResponse) {
 	meta := PaginationMeta{
 		TotalItems:  pagedResponse.TotalItems,
 		TotalPages:  pagedResponse.TotalPages,
 		CurrentPage: pagedResponse.Page,
 		PageSize:    pagedResponse.PageSize,
+		HasMore:     pagedResponse.HasMore,
+	}
+	if pagedResponse.HasMore && pagedResponse.TotalItems == nil {
+		meta.NextCursor = query.EncodeCursor(pagedResponse.Page+1, pagedResponse.PageSize)
 	}
 
+	w.Header().Set("Link", strings.Join(paginationLinks(r, pagedResponse), ", "))
+
 	response := StandardResponseData{
 		Success: true,
 		Data:    data,
@@ -129,6 +128,55 @@ func (h *BaseHandler) RespondWithPagination(w http.ResponseWriter, r *http.Reque
 	h.Respond(w, r, http.StatusOK, response)
 }
 
+// RespondWithBatch отправляет результат пакетной операции: 200, если все элементы
+// обработаны успешно, иначе 207 Multi-Status с результатом каждого элемента
+func (h *BaseHandler) RespondWithBatch(w http.ResponseWriter, r *http.Request, result *domain.BatchResult) {
+	response := StandardResponseData{
+		Success: result.Failed == 0,
+		Data:    result,
+	}
+	h.Respond(w, r, result.StatusCode(), response)
+}
+
+// AddBatchItem записывает результат элемента пакета. Внутренние ошибки логируются,
+// так как клиент получает по ним только общий код
+func (h *BaseHandler) AddBatchItem(result *domain.BatchResult, index int, id string, data interface{}, err error) {
+	if err != nil && apperrors.FromError(err).StatusCode >= http.StatusInternalServerError {
+		h.Logger.Error("Failed to process batch item", err, map[string]interface{}{
+			"index": index,
+			"id":    id,
+		})
+	}
+	result.Add(index, id, data, err)
+}
+
+// ValidateBatchItem проверяет элемент пакета. Ошибки валидации возвращаются как ошибка
+// элемента, чтобы не отклонять из-за них весь пакет
+func (h *BaseHandler) ValidateBatchItem(r *http.Request, item interface{}) error {
+	validationErrors, err := h.ValidateRequest(r, item)
+	if err != nil {
+		return err
+	}
+	if len(validationErrors) > 0 {
+		return apperrors.ValidationError(validationErrors...)
+	}
+	return nil
+}
+
+// ValidateBatchRequest проверяет пакет целиком (наличие и число элементов) и отвечает
+// ошибкой, если он некорректен. Элементы проверяются по отдельности при обработке
+func (h *BaseHandler) ValidateBatchRequest(w http.ResponseWriter, r *http.Request, req interface{}) bool {
+	if validationErrors, err := h.ValidateRequest(r, req); err != nil {
+		h.Logger.Error("Request validation error", err)
+		h.RespondWithError(w, r, apperrors.CodeInternalError, "Validation failed")
+		return false
+	} else if len(validationErrors) > 0 {
+		h.RespondWithValidationErrors(w, r, validationErrors)
+		return false
+	}
+	return true
+}
+
 // ParseJSON разбирает JSON из тела запроса
 func (h *BaseHandler) ParseJSON(r *http.Request, dst interface{}) error {
 	if err := json.NewDecoder(r.Body).Decode(dst); err != nil {
@@ -137,52 +185,81 @@ func (h *BaseHandler) ParseJSON(r *http.Request, dst interface{}) error {
 	return nil
 }
 
-// ValidateRequest проверяет валидность структуры запроса
-func (h *BaseHandler) ValidateRequest(data interface{}) ([]ValidationError, error) {
-	if err := h.Validator.Struct(data); err != nil {
-		var validationErrors []ValidationError
-		if errors, ok := err.(validator.ValidationErrors); ok {
-			for _, err := range errors {
-				validationError := ValidationError{
-					Field:   err.Field(),
-					Message: getErrorMsg(err),
-				}
-				validationErrors = append(validationErrors, validationError)
-			}
-			return validationErrors, nil
-		}
-		return nil, err
+// ValidateRequest проверяет валидность структуры запроса. Сообщения об ошибках
+// возвращаются на языке, выбранном по заголовку Accept-Language
+func (h *BaseHandler) ValidateRequest(r *http.Request, data interface{}) ([]ValidationError, error) {
+	err := h.Validator.Validate(r.Context(), data)
+	if err == nil {
+		return nil, nil
 	}
-	return nil, nil
+
+	var verrs *validator.Errors
+	if errors.As(err, &verrs) {
+		return verrs.FieldErrors(), nil
+	}
+	return nil, err
 }
 
-// GetPaginationParams извлекает параметры пагинации из запроса
-func (h *BaseHandler) GetPaginationParams(r *http.Request) (int, int) {
-	page := 1
-	pageSize := 20
+// GetPageRequest извлекает параметры пагинации из запроса: page, page_size, count и cursor.
+// Если параметры некорректны, отвечает ошибкой и возвращает false
+func (h *BaseHandler) GetPageRequest(w http.ResponseWriter, r *http.Request) (domain.PageRequest, bool) {
+	return h.ParseQuery(w, r, query.New(r))
+}
 
-	if pageParam := r.URL.Query().Get("page"); pageParam != "" {
-		if parsed, err := strconv.Atoi(pageParam); err == nil && parsed > 0 {
-			page = parsed
-		}
+// ParseQuery разбирает пагинацию из параметров q и проверяет все уже разобранные
+// параметры фильтров. Если какой-то из них некорректен, отвечает ошибкой и возвращает false
+func (h *BaseHandler) ParseQuery(w http.ResponseWriter, r *http.Request, q *query.Parser) (domain.PageRequest, bool) {
+	page, err := q.Page()
+	if errors.Is(err, query.ErrInvalidCursor) {
+		h.RespondWithError(w, r, apperrors.CodeInvalidCursor, "Invalid pagination cursor")
+		return page, false
+	}
+	return page, h.CheckQuery(w, r, q)
+}
+
+// CheckQuery отвечает ошибками валидации, если параметры запроса в q некорректны
+func (h *BaseHandler) CheckQuery(w http.ResponseWriter, r *http.Request, q *query.Parser) bool {
+	if q.HasErrors() {
+		h.RespondWithValidationErrors(w, r, q.Errors())
+		return false
 	}
+	return true
+}
 
-	if pageSizeParam := r.URL.Query().Get("page_size"); pageSizeParam != "" {
-		if parsed, err := strconv.Atoi(pageSizeParam); err == nil && parsed > 0 && parsed <= 100 {
-			pageSize = parsed
+// paginationLinks формирует ссылки first, prev, next и last на страницы того же списка
+// с теми же фильтрами. Ссылка last доступна, только если известно число страниц
+func paginationLinks(r *http.Request, pagedResponse *domain.PagedResponse) []string {
+	link := func(page int, rel string) string {
+		query := r.URL.Query()
+		query.Del("cursor")
+		query.Set("page", strconv.Itoa(page))
+		query.Set("page_size", strconv.Itoa(pagedResponse.PageSize))
+		if pagedResponse.TotalItems == nil {
+			query.Set("count", "false")
 		}
+		return fmt.Sprintf("<%s?%s>; rel=\"%s\"", r.URL.Path, query.Encode(), rel)
 	}
 
-	return page, pageSize
+	links := []string{link(1, "first")}
+	if pagedResponse.Page > 1 {
+		links = append(links, link(pagedResponse.Page-1, "prev"))
+	}
+	if pagedResponse.HasMore {
+		links = append(links, link(pagedResponse.Page+1, "next"))
+	}
+	if pagedResponse.TotalPages != nil && *pagedResponse.TotalPages > 0 {
+		links = append(links, link(*pagedResponse.TotalPages, "last"))
+	}
+	return links
 }
 
 // GetUserIDFromContext извлекает ID пользователя из контекста запроса
 func (h *BaseHandler) GetUserIDFromContext(r *http.Request) (string, error) {
-	userID, ok := r.Context().Value("user_id").(string)
-	if !ok || userID == "" {
+	actor, ok := auth.ActorFromContext(r.Context())
+	if !ok {
 		return "", errors.New("user ID not found in context")
 	}
-	return userID, nil
+	return actor.ID, nil
 }
 
 // GetURLParam извлекает параметр из URL
@@ -190,70 +267,48 @@ func (h *BaseHandler) GetURLParam(r *http.Request, key string) string {
 	return chi.URLParam(r, key)
 }
 
-// Функция для получения человекочитаемого сообщения об ошибке валидации
-func getErrorMsg(err validator.FieldError) string {
-	switch err.Tag() {
-	case "required":
-		return "This field is required"
-	case "email":
-		return "Invalid email format"
-	case "min":
-		return fmt.Sprintf("Minimum length is %s", err.Param())
-	case "max":
-		return fmt.Sprintf("Maximum length is %s", err.Param())
-	case "uuid":
-		return "Invalid UUID format"
-	case "oneof":
-		return fmt.Sprintf("Value must be one of: %s", err.Param())
-	case "gtfield":
-		return fmt.Sprintf("Value must be greater than %s field", err.Param())
-	default:
-		return fmt.Sprintf("Validation failed on '%s' with value '%v'", err.Tag(), err.Value())
-	}
+// newRequestValidator создает валидатор запросов с перечислениями предметной области
+func newRequestValidator() *validator.CustomValidator {
+	v := validator.NewValidator()
+	v.RegisterEnum("task_status",
+		string(domain.TaskStatusNew),
+		string(domain.TaskStatusInProgress),
+		string(domain.TaskStatusOnHold),
+		string(domain.TaskStatusReview),
+		string(domain.TaskStatusCompleted),
+		string(domain.TaskStatusCancelled),
+	)
+	v.RegisterEnum("task_priority",
+		string(domain.TaskPriorityLow),
+		string(domain.TaskPriorityMedium),
+		string(domain.TaskPriorityHigh),
+		string(domain.TaskPriorityCritical),
+	)
+	return v
 }
 
-// HandleError обрабатывает ошибки и отправляет соответствующий ответ
-func (h *BaseHandler) HandleError(w http.ResponseWriter, r *http.Request, err error, statusCode int) {
-	h.Logger.Error("Request error", err)
-
-	// Определяем сообщение об ошибке и код
-	errorMessage := err.Error()
-	errorCode := "internal_error"
-
-	// Пытаемся определить тип ошибки
-	switch {
-	case errors.Is(err, domain.ErrNotFound):
-		statusCode = http.StatusNotFound
-		errorCode = "not_found"
-	case errors.Is(err, domain.ErrInvalidInput):
-		statusCode = http.StatusBadRequest
-		errorCode = "invalid_input"
-	case errors.Is(err, domain.ErrUnauthorized):
-		statusCode = http.StatusUnauthorized
-		errorCode = "unauthorized"
-	case errors.Is(err, domain.ErrForbidden):
-		statusCode = http.StatusForbidden
-		errorCode = "forbidden"
+// HandleError отправляет ответ по ошибке сервиса: код, статус и ошибки полей берутся
+// из ошибки каталога, прочие ошибки считаются внутренними
+func (h *BaseHandler) HandleError(w http.ResponseWriter, r *http.Request, err error) {
+	appErr := apperrors.FromError(err)
+	if appErr.StatusCode >= http.StatusInternalServerError {
+		h.Logger.Error("Request error", err)
 	}
 
-	h.RespondWithError(w, r, statusCode, errorMessage, errorCode)
+	h.respondWithErrorResponse(w, r, appErr.StatusCode, appErr.Response())
 }
 
-// GetCurrentUser получает текущего пользователя из контекста запроса
+// GetCurrentUser получает текущего пользователя из контекста запроса. Возвращаются только
+// данные из токена доступа: ID и email. Роль для проверок прав нужно читать из базы
 func (h *BaseHandler) GetCurrentUser(r *http.Request) (*domain.User, error) {
-	userID, err := h.GetUserIDFromContext(r)
-	if err != nil {
+	actor, ok := auth.ActorFromContext(r.Context())
+	if !ok {
 		return nil, domain.ErrUnauthorized
 	}
 
-	// Получаем данные пользователя из контекста, если они там есть
-	if user, ok := r.Context().Value("user").(*domain.User); ok && user.ID == userID {
-		return user, nil
-	}
-
-	// Если в контексте нет полных данных о пользователе, возвращаем базовую информацию
 	return &domain.User{
-		ID: userID,
+		ID:    actor.ID,
+		Email: actor.Email,
 	}, nil
 }
 
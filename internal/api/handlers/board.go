@@ -0,0 +1,233 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/internal/service"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
+)
+
+// BoardHandler обрабатывает запросы настройки колонок канбан-доски и переноса карточек
+type BoardHandler struct {
+	BaseHandler
+	boardService *service.BoardService
+}
+
+// NewBoardHandler создает новый экземпляр BoardHandler
+func NewBoardHandler(base BaseHandler, boardService *service.BoardService) *BoardHandler {
+	return &BoardHandler{
+		BaseHandler:  base,
+		boardService: boardService,
+	}
+}
+
+// ListColumns возвращает колонки доски проекта
+func (h *BoardHandler) ListColumns(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID проекта из URL
+	projectID := h.GetURLParam(r, "id")
+	if projectID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID is required")
+		return
+	}
+
+	columns, err := h.boardService.ListColumns(r.Context(), projectID)
+	if err != nil {
+		h.handleBoardError(w, r, err, projectID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, columns)
+}
+
+// CreateColumn добавляет колонку в конец доски проекта
+func (h *BoardHandler) CreateColumn(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID проекта из URL
+	projectID := h.GetURLParam(r, "id")
+	if projectID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID is required")
+		return
+	}
+
+	var req domain.BoardColumnRequest
+	if !h.parseBoardRequest(w, r, &req) {
+		return
+	}
+
+	column, err := h.boardService.CreateColumn(r.Context(), projectID, req, userID)
+	if err != nil {
+		h.handleBoardError(w, r, err, projectID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, column)
+}
+
+// UpdateColumn изменяет название и статус колонки доски
+func (h *BoardHandler) UpdateColumn(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID проекта и колонки из URL
+	projectID := h.GetURLParam(r, "id")
+	columnID := h.GetURLParam(r, "column_id")
+	if projectID == "" || columnID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID and column ID are required")
+		return
+	}
+
+	var req domain.BoardColumnRequest
+	if !h.parseBoardRequest(w, r, &req) {
+		return
+	}
+
+	column, err := h.boardService.UpdateColumn(r.Context(), projectID, columnID, req)
+	if err != nil {
+		h.handleBoardError(w, r, err, columnID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, column)
+}
+
+// DeleteColumn удаляет колонку доски
+func (h *BoardHandler) DeleteColumn(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID проекта и колонки из URL
+	projectID := h.GetURLParam(r, "id")
+	columnID := h.GetURLParam(r, "column_id")
+	if projectID == "" || columnID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID and column ID are required")
+		return
+	}
+
+	if err := h.boardService.DeleteColumn(r.Context(), projectID, columnID); err != nil {
+		h.handleBoardError(w, r, err, columnID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, map[string]bool{"success": true})
+}
+
+// ReorderColumns задает порядок колонок доски
+func (h *BoardHandler) ReorderColumns(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID проекта из URL
+	projectID := h.GetURLParam(r, "id")
+	if projectID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID is required")
+		return
+	}
+
+	var req domain.BoardColumnOrderRequest
+	if !h.parseBoardRequest(w, r, &req) {
+		return
+	}
+
+	columns, err := h.boardService.ReorderColumns(r.Context(), projectID, req)
+	if err != nil {
+		h.handleBoardError(w, r, err, projectID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, columns)
+}
+
+// MoveCard переносит карточку задачи в колонку доски и на место между соседними карточками
+func (h *BoardHandler) MoveCard(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID проекта из URL
+	projectID := h.GetURLParam(r, "id")
+	if projectID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID is required")
+		return
+	}
+
+	var req domain.BoardMoveRequest
+	if !h.parseBoardRequest(w, r, &req) {
+		return
+	}
+
+	task, err := h.boardService.Move(r.Context(), projectID, req, userID)
+	if err != nil {
+		h.handleBoardError(w, r, err, req.TaskID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, task)
+}
+
+// parseBoardRequest разбирает и валидирует тело запроса
+func (h *BoardHandler) parseBoardRequest(w http.ResponseWriter, r *http.Request, req interface{}) bool {
+	if err := h.ParseJSON(r, req); err != nil {
+		h.Logger.Error("Failed to parse board request", err)
+		h.RespondWithError(w, r, apperrors.CodeInvalidFormat, "Invalid request format")
+		return false
+	}
+
+	// Валидация запроса
+	if validationErrors, err := h.ValidateRequest(r, req); err != nil {
+		h.Logger.Error("Request validation error", err)
+		h.RespondWithError(w, r, apperrors.CodeInternalError, "Validation failed")
+		return false
+	} else if len(validationErrors) > 0 {
+		h.RespondWithValidationErrors(w, r, validationErrors)
+		return false
+	}
+
+	return true
+}
+
+// handleBoardError преобразует ошибки доски в HTTP-ответы
+func (h *BoardHandler) handleBoardError(w http.ResponseWriter, r *http.Request, err error, id string) {
+	var validationErr *service.TaskValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		validationErrors := make([]ValidationError, 0, len(validationErr.Violations))
+		for _, violation := range validationErr.Violations {
+			validationErrors = append(validationErrors, ValidationError{
+				Field:   violation.Field,
+				Message: violation.Message,
+			})
+		}
+		h.RespondWithValidationErrors(w, r, validationErrors)
+	case errors.Is(err, service.ErrProjectNotFound):
+		h.RespondWithError(w, r, apperrors.CodeProjectNotFound, "Project not found")
+	case errors.Is(err, service.ErrBoardColumnNotFound):
+		h.RespondWithError(w, r, apperrors.CodeBoardColumnNotFound, "Board column not found")
+	case errors.Is(err, service.ErrBoardColumnExists):
+		h.RespondWithError(w, r, apperrors.CodeBoardColumnExists, "Board column with this name or status already exists")
+	case errors.Is(err, service.ErrBoardLastColumn):
+		h.RespondWithError(w, r, apperrors.CodeBoardLastColumn, "Board must keep at least one column")
+	case errors.Is(err, service.ErrInvalidBoardOrder):
+		h.RespondWithError(w, r, apperrors.CodeInvalidBoardOrder, "Column order must list every board column exactly once")
+	case errors.Is(err, service.ErrTaskNotFound):
+		h.RespondWithError(w, r, apperrors.CodeTaskNotFound, "Task not found")
+	case errors.Is(err, service.ErrTaskAccessDenied):
+		h.RespondWithError(w, r, apperrors.CodeAccessDenied, "Access denied to the task")
+	case errors.Is(err, service.ErrInsufficientRights):
+		h.RespondWithError(w, r, apperrors.CodeInsufficientRights, "Insufficient rights to manage the board")
+	case errors.Is(err, service.ErrProjectArchived):
+		h.RespondWithError(w, r, apperrors.CodeProjectArchived, "Project is archived and read-only")
+	case errors.Is(err, service.ErrInvalidTaskStatus):
+		h.RespondWithError(w, r, apperrors.CodeInvalidStatus, "Invalid status transition")
+	case errors.Is(err, service.ErrOpenSubtasks):
+		h.RespondWithError(w, r, apperrors.CodeOpenSubtasks, "Task cannot be completed while it has open subtasks")
+	case errors.Is(err, service.ErrInvalidTaskMove):
+		h.RespondWithError(w, r, apperrors.CodeInvalidMove, "Specify at most one of after_id, before_id or position; the target must be another task of the same project")
+	default:
+		h.Logger.Error("Failed to process board request", err, map[string]interface{}{
+			"id": id,
+		})
+		h.RespondWithError(w, r, apperrors.CodeBoardFailed, "Failed to process board request")
+	}
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/nurlyy/task_manager/internal/domain"
 	"github.com/nurlyy/task_manager/internal/service"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
 )
 
 // CommentHandler обрабатывает запросы, связанные с комментариями
@@ -27,14 +28,14 @@ func (h *CommentHandler) CreateComment(w http.ResponseWriter, r *http.Request) {
 	// Получаем ID пользователя из контекста
 	userID, err := h.GetUserIDFromContext(r)
 	if err != nil {
-		h.RespondWithError(w, r, http.StatusUnauthorized, "Unauthorized", "unauthorized")
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
 		return
 	}
 
 	// Получаем ID задачи из URL
 	taskID := h.GetURLParam(r, "task_id")
 	if taskID == "" {
-		h.RespondWithError(w, r, http.StatusBadRequest, "Task ID is required", "missing_task_id")
+		h.RespondWithError(w, r, apperrors.CodeMissingTaskID, "Task ID is required")
 		return
 	}
 
@@ -43,14 +44,14 @@ func (h *CommentHandler) CreateComment(w http.ResponseWriter, r *http.Request) {
 
 	if err := h.ParseJSON(r, &req); err != nil {
 		h.Logger.Error("Failed to parse create comment request", err)
-		h.RespondWithError(w, r, http.StatusBadRequest, "Invalid request format", "invalid_format")
+		h.RespondWithError(w, r, apperrors.CodeInvalidFormat, "Invalid request format")
 		return
 	}
 
 	// Валидация запроса
-	if validationErrors, err := h.ValidateRequest(req); err != nil {
+	if validationErrors, err := h.ValidateRequest(r, req); err != nil {
 		h.Logger.Error("Request validation error", err)
-		h.RespondWithError(w, r, http.StatusInternalServerError, "Validation failed", "validation_error")
+		h.RespondWithError(w, r, apperrors.CodeInternalError, "Validation failed")
 		return
 	} else if len(validationErrors) > 0 {
 		h.RespondWithValidationErrors(w, r, validationErrors)
@@ -61,15 +62,23 @@ func (h *CommentHandler) CreateComment(w http.ResponseWriter, r *http.Request) {
 	comment, err := h.commentService.Create(r.Context(), req, userID)
 	if err != nil {
 		if errors.Is(err, service.ErrTaskNotFound) {
-			h.RespondWithError(w, r, http.StatusNotFound, "Task not found", "task_not_found")
+			h.RespondWithError(w, r, apperrors.CodeTaskNotFound, "Task not found")
 			return
 		}
 		if errors.Is(err, service.ErrTaskAccessDenied) {
-			h.RespondWithError(w, r, http.StatusForbidden, "Access denied to the task", "access_denied")
+			h.RespondWithError(w, r, apperrors.CodeAccessDenied, "Access denied to the task")
+			return
+		}
+		if errors.Is(err, service.ErrProjectArchived) {
+			h.RespondWithError(w, r, apperrors.CodeProjectArchived, "Project is archived and read-only")
+			return
+		}
+		if errors.Is(err, service.ErrInvalidParentComment) {
+			h.RespondWithError(w, r, apperrors.CodeInvalidParent, "Parent comment must belong to the same task")
 			return
 		}
 		h.Logger.Error("Failed to create comment", err)
-		h.RespondWithError(w, r, http.StatusInternalServerError, "Failed to create comment", "creation_failed")
+		h.RespondWithError(w, r, apperrors.CodeCreationFailed, "Failed to create comment")
 		return
 	}
 
@@ -81,14 +90,14 @@ func (h *CommentHandler) GetComment(w http.ResponseWriter, r *http.Request) {
 	// Получаем ID пользователя из контекста
 	userID, err := h.GetUserIDFromContext(r)
 	if err != nil {
-		h.RespondWithError(w, r, http.StatusUnauthorized, "Unauthorized", "unauthorized")
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
 		return
 	}
 
 	// Получаем ID комментария из URL
 	commentID := h.GetURLParam(r, "id")
 	if commentID == "" {
-		h.RespondWithError(w, r, http.StatusBadRequest, "Comment ID is required", "missing_id")
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Comment ID is required")
 		return
 	}
 
@@ -96,50 +105,97 @@ func (h *CommentHandler) GetComment(w http.ResponseWriter, r *http.Request) {
 	comment, err := h.commentService.GetByID(r.Context(), commentID, userID)
 	if err != nil {
 		if errors.Is(err, service.ErrCommentNotFound) {
-			h.RespondWithError(w, r, http.StatusNotFound, "Comment not found", "comment_not_found")
+			h.RespondWithError(w, r, apperrors.CodeCommentNotFound, "Comment not found")
 			return
 		}
 		if errors.Is(err, service.ErrCommentAccessDenied) {
-			h.RespondWithError(w, r, http.StatusForbidden, "Access denied to the comment", "access_denied")
+			h.RespondWithError(w, r, apperrors.CodeAccessDenied, "Access denied to the comment")
 			return
 		}
 		h.Logger.Error("Failed to get comment", err, map[string]interface{}{
 			"id": commentID,
 		})
-		h.RespondWithError(w, r, http.StatusInternalServerError, "Failed to get comment info", "comment_fetch_failed")
+		h.RespondWithError(w, r, apperrors.CodeCommentFetchFailed, "Failed to get comment info")
 		return
 	}
 
 	h.RespondWithSuccess(w, r, comment)
 }
 
+// GetCommentReplies возвращает страницу ответов на комментарий с вложенными ответами
+func (h *CommentHandler) GetCommentReplies(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID комментария из URL
+	commentID := h.GetURLParam(r, "id")
+	if commentID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Comment ID is required")
+		return
+	}
+
+	// Параметры пагинации
+	page, ok := h.GetPageRequest(w, r)
+	if !ok {
+		return
+	}
+
+	// Получаем ответы на комментарий
+	result, err := h.commentService.GetReplies(r.Context(), commentID, userID, page)
+	if err != nil {
+		if errors.Is(err, service.ErrCommentNotFound) {
+			h.RespondWithError(w, r, apperrors.CodeCommentNotFound, "Comment not found")
+			return
+		}
+		if errors.Is(err, service.ErrTaskNotFound) {
+			h.RespondWithError(w, r, apperrors.CodeTaskNotFound, "Task not found")
+			return
+		}
+		if errors.Is(err, service.ErrCommentAccessDenied) {
+			h.RespondWithError(w, r, apperrors.CodeAccessDenied, "Access denied to the comment")
+			return
+		}
+		h.Logger.Error("Failed to get comment replies", err, map[string]interface{}{
+			"id": commentID,
+		})
+		h.RespondWithError(w, r, apperrors.CodeCommentsFetchFailed, "Failed to get comments")
+		return
+	}
+
+	h.RespondWithPagination(w, r, result.Items, result)
+}
+
 // UpdateComment обновляет информацию о комментарии
 func (h *CommentHandler) UpdateComment(w http.ResponseWriter, r *http.Request) {
 	// Получаем ID пользователя из контекста
 	userID, err := h.GetUserIDFromContext(r)
 	if err != nil {
-		h.RespondWithError(w, r, http.StatusUnauthorized, "Unauthorized", "unauthorized")
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
 		return
 	}
 
 	// Получаем ID комментария из URL
 	commentID := h.GetURLParam(r, "id")
 	if commentID == "" {
-		h.RespondWithError(w, r, http.StatusBadRequest, "Comment ID is required", "missing_id")
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Comment ID is required")
 		return
 	}
 
 	var req domain.CommentUpdateRequest
 	if err := h.ParseJSON(r, &req); err != nil {
 		h.Logger.Error("Failed to parse update comment request", err)
-		h.RespondWithError(w, r, http.StatusBadRequest, "Invalid request format", "invalid_format")
+		h.RespondWithError(w, r, apperrors.CodeInvalidFormat, "Invalid request format")
 		return
 	}
 
 	// Валидация запроса
-	if validationErrors, err := h.ValidateRequest(req); err != nil {
+	if validationErrors, err := h.ValidateRequest(r, req); err != nil {
 		h.Logger.Error("Request validation error", err)
-		h.RespondWithError(w, r, http.StatusInternalServerError, "Validation failed", "validation_error")
+		h.RespondWithError(w, r, apperrors.CodeInternalError, "Validation failed")
 		return
 	} else if len(validationErrors) > 0 {
 		h.RespondWithValidationErrors(w, r, validationErrors)
@@ -150,17 +206,21 @@ func (h *CommentHandler) UpdateComment(w http.ResponseWriter, r *http.Request) {
 	comment, err := h.commentService.Update(r.Context(), commentID, req, userID)
 	if err != nil {
 		if errors.Is(err, service.ErrCommentNotFound) {
-			h.RespondWithError(w, r, http.StatusNotFound, "Comment not found", "comment_not_found")
+			h.RespondWithError(w, r, apperrors.CodeCommentNotFound, "Comment not found")
 			return
 		}
 		if errors.Is(err, service.ErrInsufficientRights) {
-			h.RespondWithError(w, r, http.StatusForbidden, "Only comment author can update comment", "insufficient_rights")
+			h.RespondWithError(w, r, apperrors.CodeInsufficientRights, "Only comment author can update comment")
+			return
+		}
+		if errors.Is(err, service.ErrProjectArchived) {
+			h.RespondWithError(w, r, apperrors.CodeProjectArchived, "Project is archived and read-only")
 			return
 		}
 		h.Logger.Error("Failed to update comment", err, map[string]interface{}{
 			"id": commentID,
 		})
-		h.RespondWithError(w, r, http.StatusInternalServerError, "Failed to update comment", "update_failed")
+		h.RespondWithError(w, r, apperrors.CodeUpdateFailed, "Failed to update comment")
 		return
 	}
 
@@ -172,31 +232,35 @@ func (h *CommentHandler) DeleteComment(w http.ResponseWriter, r *http.Request) {
 	// Получаем ID пользователя из контекста
 	userID, err := h.GetUserIDFromContext(r)
 	if err != nil {
-		h.RespondWithError(w, r, http.StatusUnauthorized, "Unauthorized", "unauthorized")
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
 		return
 	}
 
 	// Получаем ID комментария из URL
 	commentID := h.GetURLParam(r, "id")
 	if commentID == "" {
-		h.RespondWithError(w, r, http.StatusBadRequest, "Comment ID is required", "missing_id")
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Comment ID is required")
 		return
 	}
 
 	// Удаляем комментарий
 	if err := h.commentService.Delete(r.Context(), commentID, userID); err != nil {
 		if errors.Is(err, service.ErrCommentNotFound) {
-			h.RespondWithError(w, r, http.StatusNotFound, "Comment not found", "comment_not_found")
+			h.RespondWithError(w, r, apperrors.CodeCommentNotFound, "Comment not found")
 			return
 		}
 		if errors.Is(err, service.ErrInsufficientRights) {
-			h.RespondWithError(w, r, http.StatusForbidden, "Only comment author can delete comment", "insufficient_rights")
+			h.RespondWithError(w, r, apperrors.CodeInsufficientRights, "Only comment author can delete comment")
+			return
+		}
+		if errors.Is(err, service.ErrProjectArchived) {
+			h.RespondWithError(w, r, apperrors.CodeProjectArchived, "Project is archived and read-only")
 			return
 		}
 		h.Logger.Error("Failed to delete comment", err, map[string]interface{}{
 			"id": commentID,
 		})
-		h.RespondWithError(w, r, http.StatusInternalServerError, "Failed to delete comment", "delete_failed")
+		h.RespondWithError(w, r, apperrors.CodeDeleteFailed, "Failed to delete comment")
 		return
 	}
 
@@ -208,37 +272,171 @@ func (h *CommentHandler) GetCommentsByTask(w http.ResponseWriter, r *http.Reques
 	// Получаем ID пользователя из контекста
 	userID, err := h.GetUserIDFromContext(r)
 	if err != nil {
-		h.RespondWithError(w, r, http.StatusUnauthorized, "Unauthorized", "unauthorized")
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
 		return
 	}
 
 	// Получаем ID задачи из URL
 	taskID := h.GetURLParam(r, "task_id")
 	if taskID == "" {
-		h.RespondWithError(w, r, http.StatusBadRequest, "Task ID is required", "missing_task_id")
+		h.RespondWithError(w, r, apperrors.CodeMissingTaskID, "Task ID is required")
 		return
 	}
 
 	// Параметры пагинации
-	page, pageSize := h.GetPaginationParams(r)
+	page, ok := h.GetPageRequest(w, r)
+	if !ok {
+		return
+	}
 
 	// Получаем комментарии к задаче
-	result, err := h.commentService.GetCommentsByTask(r.Context(), taskID, userID, page, pageSize)
+	result, err := h.commentService.GetCommentsByTask(r.Context(), taskID, userID, page)
 	if err != nil {
 		if errors.Is(err, service.ErrTaskNotFound) {
-			h.RespondWithError(w, r, http.StatusNotFound, "Task not found", "task_not_found")
+			h.RespondWithError(w, r, apperrors.CodeTaskNotFound, "Task not found")
 			return
 		}
 		if errors.Is(err, service.ErrTaskAccessDenied) {
-			h.RespondWithError(w, r, http.StatusForbidden, "Access denied to the task", "access_denied")
+			h.RespondWithError(w, r, apperrors.CodeAccessDenied, "Access denied to the task")
 			return
 		}
 		h.Logger.Error("Failed to get comments by task", err, map[string]interface{}{
 			"task_id": taskID,
 		})
-		h.RespondWithError(w, r, http.StatusInternalServerError, "Failed to get comments", "comments_fetch_failed")
+		h.RespondWithError(w, r, apperrors.CodeCommentsFetchFailed, "Failed to get comments")
 		return
 	}
 
 	h.RespondWithPagination(w, r, result.Items, result)
 }
+
+// SaveCommentDraft сохраняет черновик комментария к задаче
+func (h *CommentHandler) SaveCommentDraft(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID задачи из URL
+	taskID := h.GetURLParam(r, "task_id")
+	if taskID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingTaskID, "Task ID is required")
+		return
+	}
+
+	var req domain.CommentDraftRequest
+	if err := h.ParseJSON(r, &req); err != nil {
+		h.Logger.Error("Failed to parse comment draft request", err)
+		h.RespondWithError(w, r, apperrors.CodeInvalidFormat, "Invalid request format")
+		return
+	}
+
+	// Валидация запроса
+	if validationErrors, err := h.ValidateRequest(r, req); err != nil {
+		h.Logger.Error("Request validation error", err)
+		h.RespondWithError(w, r, apperrors.CodeInternalError, "Validation failed")
+		return
+	} else if len(validationErrors) > 0 {
+		h.RespondWithValidationErrors(w, r, validationErrors)
+		return
+	}
+
+	// Сохраняем черновик
+	draft, err := h.commentService.SaveDraft(r.Context(), taskID, req, userID)
+	if err != nil {
+		if errors.Is(err, service.ErrTaskNotFound) {
+			h.RespondWithError(w, r, apperrors.CodeTaskNotFound, "Task not found")
+			return
+		}
+		if errors.Is(err, service.ErrTaskAccessDenied) {
+			h.RespondWithError(w, r, apperrors.CodeAccessDenied, "Access denied to the task")
+			return
+		}
+		h.Logger.Error("Failed to save comment draft", err, map[string]interface{}{
+			"task_id": taskID,
+		})
+		h.RespondWithError(w, r, apperrors.CodeDraftSaveFailed, "Failed to save comment draft")
+		return
+	}
+
+	h.RespondWithSuccess(w, r, draft)
+}
+
+// GetCommentDraft возвращает черновик комментария к задаче
+func (h *CommentHandler) GetCommentDraft(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID задачи из URL
+	taskID := h.GetURLParam(r, "task_id")
+	if taskID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingTaskID, "Task ID is required")
+		return
+	}
+
+	// Получаем черновик
+	draft, err := h.commentService.GetDraft(r.Context(), taskID, userID)
+	if err != nil {
+		if errors.Is(err, service.ErrTaskNotFound) {
+			h.RespondWithError(w, r, apperrors.CodeTaskNotFound, "Task not found")
+			return
+		}
+		if errors.Is(err, service.ErrTaskAccessDenied) {
+			h.RespondWithError(w, r, apperrors.CodeAccessDenied, "Access denied to the task")
+			return
+		}
+		if errors.Is(err, service.ErrCommentDraftNotFound) {
+			h.RespondWithError(w, r, apperrors.CodeDraftNotFound, "Comment draft not found")
+			return
+		}
+		h.Logger.Error("Failed to get comment draft", err, map[string]interface{}{
+			"task_id": taskID,
+		})
+		h.RespondWithError(w, r, apperrors.CodeDraftFetchFailed, "Failed to get comment draft")
+		return
+	}
+
+	h.RespondWithSuccess(w, r, draft)
+}
+
+// DeleteCommentDraft удаляет черновик комментария к задаче
+func (h *CommentHandler) DeleteCommentDraft(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID задачи из URL
+	taskID := h.GetURLParam(r, "task_id")
+	if taskID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingTaskID, "Task ID is required")
+		return
+	}
+
+	// Удаляем черновик
+	if err := h.commentService.DeleteDraft(r.Context(), taskID, userID); err != nil {
+		if errors.Is(err, service.ErrTaskNotFound) {
+			h.RespondWithError(w, r, apperrors.CodeTaskNotFound, "Task not found")
+			return
+		}
+		if errors.Is(err, service.ErrTaskAccessDenied) {
+			h.RespondWithError(w, r, apperrors.CodeAccessDenied, "Access denied to the task")
+			return
+		}
+		h.Logger.Error("Failed to delete comment draft", err, map[string]interface{}{
+			"task_id": taskID,
+		})
+		h.RespondWithError(w, r, apperrors.CodeDraftDeleteFailed, "Failed to delete comment draft")
+		return
+	}
+
+	h.RespondWithSuccess(w, r, map[string]bool{"success": true})
+}
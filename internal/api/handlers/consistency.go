@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/internal/service"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
+)
+
+// ConsistencyHandler обрабатывает запросы проверки согласованности данных
+type ConsistencyHandler struct {
+	BaseHandler
+	consistencyService *service.ConsistencyService
+}
+
+// NewConsistencyHandler создает новый экземпляр ConsistencyHandler
+func NewConsistencyHandler(base BaseHandler, consistencyService *service.ConsistencyService) *ConsistencyHandler {
+	return &ConsistencyHandler{
+		BaseHandler:        base,
+		consistencyService: consistencyService,
+	}
+}
+
+// GetConsistencyReport возвращает отчет о последней проверке согласованности
+func (h *ConsistencyHandler) GetConsistencyReport(w http.ResponseWriter, r *http.Request) {
+	report, err := h.consistencyService.GetReport(r.Context())
+	if err != nil {
+		h.handleConsistencyError(w, r, err)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, report)
+}
+
+// StartConsistencyCheck запускает проверку согласованности в фоне
+func (h *ConsistencyHandler) StartConsistencyCheck(w http.ResponseWriter, r *http.Request) {
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req domain.ConsistencyRequest
+	if err := h.ParseJSON(r, &req); err != nil {
+		h.Logger.Error("Failed to parse consistency check request", err)
+		h.RespondWithError(w, r, apperrors.CodeInvalidFormat, "Invalid request format")
+		return
+	}
+
+	if validationErrors, err := h.ValidateRequest(r, req); err != nil {
+		h.Logger.Error("Request validation error", err)
+		h.RespondWithError(w, r, apperrors.CodeInternalError, "Validation failed")
+		return
+	} else if len(validationErrors) > 0 {
+		h.RespondWithValidationErrors(w, r, validationErrors)
+		return
+	}
+
+	report, err := h.consistencyService.Start(r.Context(), req, userID)
+	if err != nil {
+		h.handleConsistencyError(w, r, err)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, report)
+}
+
+// handleConsistencyError преобразует ошибки сервиса в ответы API
+func (h *ConsistencyHandler) handleConsistencyError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, service.ErrConsistencyRunning):
+		h.RespondWithError(w, r, apperrors.CodeConsistencyRunning, "Consistency check is already running")
+	case errors.Is(err, service.ErrConsistencyReportNotFound):
+		h.RespondWithError(w, r, apperrors.CodeConsistencyReportNotFound, "Consistency check has not been run yet")
+	default:
+		h.Logger.Error("Failed to handle consistency check request", err)
+		h.RespondWithError(w, r, apperrors.CodeConsistencyFailed, "Failed to handle consistency check request")
+	}
+}
@@ -0,0 +1,294 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/internal/service"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
+)
+
+// DecisionHandler обрабатывает запросы, связанные с журналом решений проекта
+type DecisionHandler struct {
+	BaseHandler
+	decisionService *service.DecisionService
+}
+
+// NewDecisionHandler создает новый экземпляр DecisionHandler
+func NewDecisionHandler(base BaseHandler, decisionService *service.DecisionService) *DecisionHandler {
+	return &DecisionHandler{
+		BaseHandler:     base,
+		decisionService: decisionService,
+	}
+}
+
+// ListProjectDecisions возвращает журнал решений проекта
+func (h *DecisionHandler) ListProjectDecisions(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID проекта из URL
+	projectID := h.GetURLParam(r, "id")
+	if projectID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID is required")
+		return
+	}
+
+	// Параметры пагинации
+	page, ok := h.GetPageRequest(w, r)
+	if !ok {
+		return
+	}
+
+	// Поиск по тексту и фильтр по задаче
+	query := r.URL.Query()
+	var taskID *string
+	if value := query.Get("task_id"); value != "" {
+		taskID = &value
+	}
+
+	result, err := h.decisionService.List(r.Context(), projectID, query.Get("q"), taskID, page)
+	if err != nil {
+		h.handleDecisionError(w, r, err, projectID)
+		return
+	}
+
+	h.RespondWithPagination(w, r, result.Items, result)
+}
+
+// CreateDecision записывает решение в журнал проекта
+func (h *DecisionHandler) CreateDecision(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID проекта из URL
+	projectID := h.GetURLParam(r, "id")
+	if projectID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID is required")
+		return
+	}
+
+	var req domain.DecisionCreateRequest
+	if !h.parseDecisionRequest(w, r, &req) {
+		return
+	}
+
+	decision, err := h.decisionService.Create(r.Context(), projectID, req, userID)
+	if err != nil {
+		h.handleDecisionError(w, r, err, projectID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, decision)
+}
+
+// GetDecision возвращает решение
+func (h *DecisionHandler) GetDecision(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID решения из URL
+	decisionID := h.GetURLParam(r, "id")
+	if decisionID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Decision ID is required")
+		return
+	}
+
+	decision, err := h.decisionService.Get(r.Context(), decisionID, userID)
+	if err != nil {
+		h.handleDecisionError(w, r, err, decisionID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, decision)
+}
+
+// UpdateDecision обновляет решение
+func (h *DecisionHandler) UpdateDecision(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID решения из URL
+	decisionID := h.GetURLParam(r, "id")
+	if decisionID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Decision ID is required")
+		return
+	}
+
+	var req domain.DecisionUpdateRequest
+	if !h.parseDecisionRequest(w, r, &req) {
+		return
+	}
+
+	decision, err := h.decisionService.Update(r.Context(), decisionID, req, userID)
+	if err != nil {
+		h.handleDecisionError(w, r, err, decisionID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, decision)
+}
+
+// DeleteDecision удаляет решение
+func (h *DecisionHandler) DeleteDecision(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID решения из URL
+	decisionID := h.GetURLParam(r, "id")
+	if decisionID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Decision ID is required")
+		return
+	}
+
+	if err := h.decisionService.Delete(r.Context(), decisionID, userID); err != nil {
+		h.handleDecisionError(w, r, err, decisionID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, map[string]bool{"success": true})
+}
+
+// LinkDecisionTask связывает решение с задачей проекта
+func (h *DecisionHandler) LinkDecisionTask(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID решения из URL
+	decisionID := h.GetURLParam(r, "id")
+	if decisionID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Decision ID is required")
+		return
+	}
+
+	var req domain.DecisionTaskRequest
+	if !h.parseDecisionRequest(w, r, &req) {
+		return
+	}
+
+	decision, err := h.decisionService.LinkTask(r.Context(), decisionID, req, userID)
+	if err != nil {
+		h.handleDecisionError(w, r, err, decisionID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, decision)
+}
+
+// UnlinkDecisionTask удаляет связь решения с задачей
+func (h *DecisionHandler) UnlinkDecisionTask(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID решения и задачи из URL
+	decisionID := h.GetURLParam(r, "id")
+	taskID := h.GetURLParam(r, "task_id")
+	if decisionID == "" || taskID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Decision ID and task ID are required")
+		return
+	}
+
+	if err := h.decisionService.UnlinkTask(r.Context(), decisionID, taskID, userID); err != nil {
+		h.handleDecisionError(w, r, err, decisionID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, map[string]bool{"success": true})
+}
+
+// ListTaskDecisions возвращает решения, связанные с задачей
+func (h *DecisionHandler) ListTaskDecisions(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID задачи из URL
+	taskID := h.GetURLParam(r, "id")
+	if taskID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Task ID is required")
+		return
+	}
+
+	decisions, err := h.decisionService.ListTaskDecisions(r.Context(), taskID, userID)
+	if err != nil {
+		h.handleDecisionError(w, r, err, taskID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, decisions)
+}
+
+// parseDecisionRequest разбирает и валидирует тело запроса
+func (h *DecisionHandler) parseDecisionRequest(w http.ResponseWriter, r *http.Request, req interface{}) bool {
+	if err := h.ParseJSON(r, req); err != nil {
+		h.Logger.Error("Failed to parse decision request", err)
+		h.RespondWithError(w, r, apperrors.CodeInvalidFormat, "Invalid request format")
+		return false
+	}
+
+	// Валидация запроса
+	if validationErrors, err := h.ValidateRequest(r, req); err != nil {
+		h.Logger.Error("Request validation error", err)
+		h.RespondWithError(w, r, apperrors.CodeInternalError, "Validation failed")
+		return false
+	} else if len(validationErrors) > 0 {
+		h.RespondWithValidationErrors(w, r, validationErrors)
+		return false
+	}
+
+	return true
+}
+
+// handleDecisionError преобразует ошибки журнала решений в HTTP-ответы
+func (h *DecisionHandler) handleDecisionError(w http.ResponseWriter, r *http.Request, err error, id string) {
+	switch {
+	case errors.Is(err, service.ErrProjectNotFound):
+		h.RespondWithError(w, r, apperrors.CodeProjectNotFound, "Project not found")
+	case errors.Is(err, service.ErrDecisionNotFound):
+		h.RespondWithError(w, r, apperrors.CodeDecisionNotFound, "Decision not found")
+	case errors.Is(err, service.ErrTaskNotFound):
+		h.RespondWithError(w, r, apperrors.CodeTaskNotFound, "Task not found")
+	case errors.Is(err, service.ErrTaskAccessDenied):
+		h.RespondWithError(w, r, apperrors.CodeAccessDenied, "Access denied to the task")
+	case errors.Is(err, service.ErrDecisionApproverInvalid):
+		h.RespondWithError(w, r, apperrors.CodeInvalidApprover, "Approver must be a member of the project")
+	case errors.Is(err, service.ErrDecisionTaskInvalid):
+		h.RespondWithError(w, r, apperrors.CodeInvalidTask, "Decision can only be linked to tasks of its project")
+	case errors.Is(err, service.ErrDecisionTaskLinked):
+		h.RespondWithError(w, r, apperrors.CodeTaskAlreadyLinked, "Task is already linked to the decision")
+	case errors.Is(err, service.ErrInsufficientRights):
+		h.RespondWithError(w, r, apperrors.CodeInsufficientRights, "Insufficient rights to manage decisions")
+	case errors.Is(err, service.ErrProjectArchived):
+		h.RespondWithError(w, r, apperrors.CodeProjectArchived, "Project is archived and read-only")
+	default:
+		h.Logger.Error("Failed to process decision request", err, map[string]interface{}{
+			"id": id,
+		})
+		h.RespondWithError(w, r, apperrors.CodeDecisionFailed, "Failed to process decision request")
+	}
+}
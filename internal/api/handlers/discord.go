@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/internal/service"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
+)
+
+// DiscordHandler обрабатывает запросы, связанные с каналами Discord проектов
+type DiscordHandler struct {
+	BaseHandler
+	discordService *service.DiscordService
+}
+
+// NewDiscordHandler создает новый экземпляр DiscordHandler
+func NewDiscordHandler(base BaseHandler, discordService *service.DiscordService) *DiscordHandler {
+	return &DiscordHandler{
+		BaseHandler:    base,
+		discordService: discordService,
+	}
+}
+
+// GetProjectDiscordChannel возвращает канал Discord проекта
+func (h *DiscordHandler) GetProjectDiscordChannel(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID проекта из URL
+	projectID := h.GetURLParam(r, "id")
+	if projectID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID is required")
+		return
+	}
+
+	channel, err := h.discordService.GetProjectChannel(r.Context(), projectID)
+	if err != nil {
+		h.handleDiscordError(w, r, err, projectID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, channel)
+}
+
+// SaveProjectDiscordChannel создает или обновляет канал Discord проекта
+func (h *DiscordHandler) SaveProjectDiscordChannel(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID проекта из URL
+	projectID := h.GetURLParam(r, "id")
+	if projectID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID is required")
+		return
+	}
+
+	var req domain.ProjectDiscordChannelRequest
+	if err := h.ParseJSON(r, &req); err != nil {
+		h.Logger.Error("Failed to parse project Discord channel request", err)
+		h.RespondWithError(w, r, apperrors.CodeInvalidFormat, "Invalid request format")
+		return
+	}
+
+	// Валидация запроса
+	if validationErrors, err := h.ValidateRequest(r, req); err != nil {
+		h.Logger.Error("Request validation error", err)
+		h.RespondWithError(w, r, apperrors.CodeInternalError, "Validation failed")
+		return
+	} else if len(validationErrors) > 0 {
+		h.RespondWithValidationErrors(w, r, validationErrors)
+		return
+	}
+
+	channel, err := h.discordService.SaveProjectChannel(r.Context(), projectID, req, userID)
+	if err != nil {
+		h.handleDiscordError(w, r, err, projectID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, channel)
+}
+
+// DeleteProjectDiscordChannel удаляет канал Discord проекта
+func (h *DiscordHandler) DeleteProjectDiscordChannel(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID проекта из URL
+	projectID := h.GetURLParam(r, "id")
+	if projectID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID is required")
+		return
+	}
+
+	if err := h.discordService.DeleteProjectChannel(r.Context(), projectID); err != nil {
+		h.handleDiscordError(w, r, err, projectID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, map[string]bool{"success": true})
+}
+
+// handleDiscordError преобразует ошибки интеграции с Discord в HTTP-ответы
+func (h *DiscordHandler) handleDiscordError(w http.ResponseWriter, r *http.Request, err error, id string) {
+	var validationErr *service.TaskValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		validationErrors := make([]ValidationError, 0, len(validationErr.Violations))
+		for _, violation := range validationErr.Violations {
+			validationErrors = append(validationErrors, ValidationError{
+				Field:   violation.Field,
+				Message: violation.Message,
+			})
+		}
+		h.RespondWithValidationErrors(w, r, validationErrors)
+	case errors.Is(err, service.ErrDiscordChannelNotFound):
+		h.RespondWithError(w, r, apperrors.CodeDiscordChannelNotFound, "Discord channel not found")
+	case errors.Is(err, service.ErrDiscordWebhookNotAllowed):
+		h.RespondWithError(w, r, apperrors.CodeDiscordWebhookNotAllowed, "Webhook URL must be a Discord channel webhook")
+	case errors.Is(err, service.ErrDiscordBotNotConfigured):
+		h.RespondWithError(w, r, apperrors.CodeDiscordBotNotConfigured, "Discord bot is not configured, use a channel webhook")
+	case errors.Is(err, service.ErrDiscordUnreachable):
+		h.RespondWithError(w, r, apperrors.CodeDiscordUnreachable, "Discord rejected the test message")
+	default:
+		h.Logger.Error("Failed to process Discord request", err, map[string]interface{}{
+			"id": id,
+		})
+		h.RespondWithError(w, r, apperrors.CodeDiscordFailed, "Failed to process Discord request")
+	}
+}
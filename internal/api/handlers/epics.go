@@ -0,0 +1,339 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/internal/service"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
+)
+
+// EpicHandler обрабатывает запросы, связанные с эпиками и доской задач проекта
+type EpicHandler struct {
+	BaseHandler
+	epicService *service.EpicService
+}
+
+// NewEpicHandler создает новый экземпляр EpicHandler
+func NewEpicHandler(base BaseHandler, epicService *service.EpicService) *EpicHandler {
+	return &EpicHandler{
+		BaseHandler: base,
+		epicService: epicService,
+	}
+}
+
+// ListProjectEpics возвращает эпики проекта с прогрессом задач
+func (h *EpicHandler) ListProjectEpics(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID проекта из URL
+	projectID := h.GetURLParam(r, "id")
+	if projectID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID is required")
+		return
+	}
+
+	epics, err := h.epicService.List(r.Context(), projectID)
+	if err != nil {
+		h.handleEpicError(w, r, err, projectID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, epics)
+}
+
+// CreateEpic создает эпик в проекте
+func (h *EpicHandler) CreateEpic(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID проекта из URL
+	projectID := h.GetURLParam(r, "id")
+	if projectID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID is required")
+		return
+	}
+
+	var req domain.EpicCreateRequest
+	if !h.parseEpicRequest(w, r, &req) {
+		return
+	}
+
+	epic, err := h.epicService.Create(r.Context(), projectID, req, userID)
+	if err != nil {
+		h.handleEpicError(w, r, err, projectID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, epic)
+}
+
+// GetEpic возвращает эпик с прогрессом задач
+func (h *EpicHandler) GetEpic(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID эпика из URL
+	epicID := h.GetURLParam(r, "id")
+	if epicID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Epic ID is required")
+		return
+	}
+
+	epic, err := h.epicService.Get(r.Context(), epicID, userID)
+	if err != nil {
+		h.handleEpicError(w, r, err, epicID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, epic)
+}
+
+// UpdateEpic обновляет эпик
+func (h *EpicHandler) UpdateEpic(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID эпика из URL
+	epicID := h.GetURLParam(r, "id")
+	if epicID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Epic ID is required")
+		return
+	}
+
+	var req domain.EpicUpdateRequest
+	if !h.parseEpicRequest(w, r, &req) {
+		return
+	}
+
+	epic, err := h.epicService.Update(r.Context(), epicID, req, userID)
+	if err != nil {
+		h.handleEpicError(w, r, err, epicID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, epic)
+}
+
+// DeleteEpic удаляет эпик; задачи эпика остаются в проекте
+func (h *EpicHandler) DeleteEpic(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID эпика из URL
+	epicID := h.GetURLParam(r, "id")
+	if epicID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Epic ID is required")
+		return
+	}
+
+	if err := h.epicService.Delete(r.Context(), epicID, userID); err != nil {
+		h.handleEpicError(w, r, err, epicID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, map[string]bool{"success": true})
+}
+
+// ListEpicComments возвращает комментарии к эпику
+func (h *EpicHandler) ListEpicComments(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID эпика из URL
+	epicID := h.GetURLParam(r, "id")
+	if epicID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Epic ID is required")
+		return
+	}
+
+	comments, err := h.epicService.ListComments(r.Context(), epicID, userID)
+	if err != nil {
+		h.handleEpicError(w, r, err, epicID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, comments)
+}
+
+// CreateEpicComment добавляет комментарий к эпику
+func (h *EpicHandler) CreateEpicComment(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID эпика из URL
+	epicID := h.GetURLParam(r, "id")
+	if epicID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Epic ID is required")
+		return
+	}
+
+	var req domain.EpicCommentRequest
+	if !h.parseEpicRequest(w, r, &req) {
+		return
+	}
+
+	comment, err := h.epicService.AddComment(r.Context(), epicID, req, userID)
+	if err != nil {
+		h.handleEpicError(w, r, err, epicID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, comment)
+}
+
+// DeleteEpicComment удаляет комментарий к эпику
+func (h *EpicHandler) DeleteEpicComment(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID эпика и комментария из URL
+	epicID := h.GetURLParam(r, "id")
+	commentID := h.GetURLParam(r, "comment_id")
+	if epicID == "" || commentID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Epic ID and comment ID are required")
+		return
+	}
+
+	if err := h.epicService.DeleteComment(r.Context(), epicID, commentID, userID); err != nil {
+		h.handleEpicError(w, r, err, commentID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, map[string]bool{"success": true})
+}
+
+// SetTaskEpic привязывает задачу к эпику или отвязывает ее
+func (h *EpicHandler) SetTaskEpic(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID задачи из URL
+	taskID := h.GetURLParam(r, "id")
+	if taskID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Task ID is required")
+		return
+	}
+
+	var req domain.TaskEpicRequest
+	if !h.parseEpicRequest(w, r, &req) {
+		return
+	}
+
+	task, err := h.epicService.SetTaskEpic(r.Context(), taskID, req, userID)
+	if err != nil {
+		h.handleEpicError(w, r, err, taskID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, task)
+}
+
+// GetProjectBoard возвращает канбан-доску проекта; swimlane=epic группирует задачи по эпикам
+func (h *EpicHandler) GetProjectBoard(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID проекта из URL
+	projectID := h.GetURLParam(r, "id")
+	if projectID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID is required")
+		return
+	}
+
+	board, err := h.epicService.GetBoard(r.Context(), projectID, r.URL.Query().Get("swimlane"))
+	if err != nil {
+		h.handleEpicError(w, r, err, projectID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, board)
+}
+
+// parseEpicRequest разбирает и валидирует тело запроса
+func (h *EpicHandler) parseEpicRequest(w http.ResponseWriter, r *http.Request, req interface{}) bool {
+	if err := h.ParseJSON(r, req); err != nil {
+		h.Logger.Error("Failed to parse epic request", err)
+		h.RespondWithError(w, r, apperrors.CodeInvalidFormat, "Invalid request format")
+		return false
+	}
+
+	// Валидация запроса
+	if validationErrors, err := h.ValidateRequest(r, req); err != nil {
+		h.Logger.Error("Request validation error", err)
+		h.RespondWithError(w, r, apperrors.CodeInternalError, "Validation failed")
+		return false
+	} else if len(validationErrors) > 0 {
+		h.RespondWithValidationErrors(w, r, validationErrors)
+		return false
+	}
+
+	return true
+}
+
+// handleEpicError преобразует ошибки эпиков в HTTP-ответы
+func (h *EpicHandler) handleEpicError(w http.ResponseWriter, r *http.Request, err error, id string) {
+	var validationErr *service.TaskValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		validationErrors := make([]ValidationError, 0, len(validationErr.Violations))
+		for _, violation := range validationErr.Violations {
+			validationErrors = append(validationErrors, ValidationError{
+				Field:   violation.Field,
+				Message: violation.Message,
+			})
+		}
+		h.RespondWithValidationErrors(w, r, validationErrors)
+	case errors.Is(err, service.ErrProjectNotFound):
+		h.RespondWithError(w, r, apperrors.CodeProjectNotFound, "Project not found")
+	case errors.Is(err, service.ErrEpicNotFound):
+		h.RespondWithError(w, r, apperrors.CodeEpicNotFound, "Epic not found")
+	case errors.Is(err, service.ErrEpicCommentNotFound):
+		h.RespondWithError(w, r, apperrors.CodeCommentNotFound, "Epic comment not found")
+	case errors.Is(err, service.ErrTaskNotFound):
+		h.RespondWithError(w, r, apperrors.CodeTaskNotFound, "Task not found")
+	case errors.Is(err, service.ErrTaskAccessDenied):
+		h.RespondWithError(w, r, apperrors.CodeAccessDenied, "Access denied to the task")
+	case errors.Is(err, service.ErrEpicOwnerInvalid):
+		h.RespondWithError(w, r, apperrors.CodeInvalidOwner, "Epic owner must be a member of the project")
+	case errors.Is(err, service.ErrEpicProjectMismatch):
+		h.RespondWithError(w, r, apperrors.CodeEpicProjectMismatch, "Epic must belong to the task project")
+	case errors.Is(err, service.ErrInsufficientRights):
+		h.RespondWithError(w, r, apperrors.CodeInsufficientRights, "Insufficient rights to manage epics")
+	case errors.Is(err, service.ErrProjectArchived):
+		h.RespondWithError(w, r, apperrors.CodeProjectArchived, "Project is archived and read-only")
+	default:
+		h.Logger.Error("Failed to process epic request", err, map[string]interface{}{
+			"id": id,
+		})
+		h.RespondWithError(w, r, apperrors.CodeEpicFailed, "Failed to process epic request")
+	}
+}
@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"net/http"
+
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
+)
+
+// ErrorCatalogHandler публикует каталог кодов ошибок API, чтобы клиенты могли
+// обрабатывать ошибки по коду
+type ErrorCatalogHandler struct {
+	BaseHandler
+}
+
+// NewErrorCatalogHandler создает новый экземпляр ErrorCatalogHandler
+func NewErrorCatalogHandler(base BaseHandler) *ErrorCatalogHandler {
+	return &ErrorCatalogHandler{
+		BaseHandler: base,
+	}
+}
+
+// ListErrorCodes возвращает все коды ошибок с HTTP-статусами и описаниями
+func (h *ErrorCatalogHandler) ListErrorCodes(w http.ResponseWriter, r *http.Request) {
+	h.RespondWithSuccess(w, r, apperrors.Catalog())
+}
+
+// GetErrorCode возвращает описание кода ошибки
+func (h *ErrorCatalogHandler) GetErrorCode(w http.ResponseWriter, r *http.Request) {
+	definition, ok := apperrors.Lookup(apperrors.Code(h.GetURLParam(r, "code")))
+	if !ok {
+		h.RespondWithError(w, r, apperrors.CodeNotFound, "Error code not found")
+		return
+	}
+
+	h.RespondWithSuccess(w, r, definition)
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/internal/service"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
+)
+
+// FeatureFlagHandler обрабатывает запросы, связанные с флагами функциональности
+type FeatureFlagHandler struct {
+	BaseHandler
+	featureFlagService *service.FeatureFlagService
+}
+
+// NewFeatureFlagHandler создает новый экземпляр FeatureFlagHandler
+func NewFeatureFlagHandler(base BaseHandler, featureFlagService *service.FeatureFlagService) *FeatureFlagHandler {
+	return &FeatureFlagHandler{
+		BaseHandler:        base,
+		featureFlagService: featureFlagService,
+	}
+}
+
+// GetMyFeatures возвращает значения флагов для текущего пользователя
+func (h *FeatureFlagHandler) GetMyFeatures(w http.ResponseWriter, r *http.Request) {
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	features, err := h.featureFlagService.ListForUser(r.Context(), userID)
+	if err != nil {
+		h.handleFeatureFlagError(w, r, err)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, features)
+}
+
+// ListFeatureFlags возвращает правила всех флагов
+func (h *FeatureFlagHandler) ListFeatureFlags(w http.ResponseWriter, r *http.Request) {
+	flags, err := h.featureFlagService.List(r.Context())
+	if err != nil {
+		h.handleFeatureFlagError(w, r, err)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, flags)
+}
+
+// OverrideFeatureFlag переопределяет правила флага
+func (h *FeatureFlagHandler) OverrideFeatureFlag(w http.ResponseWriter, r *http.Request) {
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	name := h.GetURLParam(r, "name")
+
+	var req domain.FeatureFlagRequest
+	if err := h.ParseJSON(r, &req); err != nil {
+		h.Logger.Error("Failed to parse feature flag request", err)
+		h.RespondWithError(w, r, apperrors.CodeInvalidFormat, "Invalid request format")
+		return
+	}
+
+	if validationErrors, err := h.ValidateRequest(r, req); err != nil {
+		h.Logger.Error("Request validation error", err)
+		h.RespondWithError(w, r, apperrors.CodeInternalError, "Validation failed")
+		return
+	} else if len(validationErrors) > 0 {
+		h.RespondWithValidationErrors(w, r, validationErrors)
+		return
+	}
+
+	flag, err := h.featureFlagService.Override(r.Context(), name, req, userID)
+	if err != nil {
+		h.handleFeatureFlagError(w, r, err)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, flag)
+}
+
+// ResetFeatureFlag удаляет переопределение флага
+func (h *FeatureFlagHandler) ResetFeatureFlag(w http.ResponseWriter, r *http.Request) {
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	if err := h.featureFlagService.ResetOverride(r.Context(), h.GetURLParam(r, "name"), userID); err != nil {
+		h.handleFeatureFlagError(w, r, err)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, map[string]bool{"success": true})
+}
+
+// handleFeatureFlagError преобразует ошибки сервиса в ответы API
+func (h *FeatureFlagHandler) handleFeatureFlagError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidFeatureFlag):
+		h.RespondWithError(w, r, apperrors.CodeInvalidFeatureFlag, "Feature flag name must contain only lowercase letters, digits and underscores")
+	case errors.Is(err, service.ErrUserNotFound):
+		h.RespondWithError(w, r, apperrors.CodeUserNotFound, "User not found")
+	default:
+		h.Logger.Error("Failed to handle feature flag request", err)
+		h.RespondWithError(w, r, apperrors.CodeFeatureFlagFailed, "Failed to handle feature flag request")
+	}
+}
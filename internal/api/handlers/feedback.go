@@ -0,0 +1,349 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/internal/service"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
+)
+
+// FeedbackHandler обрабатывает запросы, связанные с порталом обратной связи
+type FeedbackHandler struct {
+	BaseHandler
+	feedbackService *service.FeedbackService
+}
+
+// NewFeedbackHandler создает новый экземпляр FeedbackHandler
+func NewFeedbackHandler(base BaseHandler, feedbackService *service.FeedbackService) *FeedbackHandler {
+	return &FeedbackHandler{
+		BaseHandler:     base,
+		feedbackService: feedbackService,
+	}
+}
+
+// GetFeedbackPortal возвращает портал обратной связи проекта
+func (h *FeedbackHandler) GetFeedbackPortal(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID проекта из URL
+	projectID := h.GetURLParam(r, "id")
+	if projectID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID is required")
+		return
+	}
+
+	portal, err := h.feedbackService.GetPortal(r.Context(), projectID)
+	if err != nil {
+		h.handleFeedbackError(w, r, err, projectID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, portal)
+}
+
+// SaveFeedbackPortal создает или обновляет портал обратной связи проекта
+func (h *FeedbackHandler) SaveFeedbackPortal(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID проекта из URL
+	projectID := h.GetURLParam(r, "id")
+	if projectID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID is required")
+		return
+	}
+
+	var req domain.FeedbackPortalRequest
+	if !h.parseFeedbackRequest(w, r, &req) {
+		return
+	}
+
+	portal, err := h.feedbackService.SavePortal(r.Context(), projectID, req, userID)
+	if err != nil {
+		h.handleFeedbackError(w, r, err, projectID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, portal)
+}
+
+// RotateFeedbackPortalKey выдает порталу новую публичную ссылку
+func (h *FeedbackHandler) RotateFeedbackPortalKey(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID проекта из URL
+	projectID := h.GetURLParam(r, "id")
+	if projectID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID is required")
+		return
+	}
+
+	portal, err := h.feedbackService.RotatePortalKey(r.Context(), projectID)
+	if err != nil {
+		h.handleFeedbackError(w, r, err, projectID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, portal)
+}
+
+// DeleteFeedbackPortal удаляет портал обратной связи проекта
+func (h *FeedbackHandler) DeleteFeedbackPortal(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID проекта из URL
+	projectID := h.GetURLParam(r, "id")
+	if projectID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID is required")
+		return
+	}
+
+	if err := h.feedbackService.DeletePortal(r.Context(), projectID); err != nil {
+		h.handleFeedbackError(w, r, err, projectID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, map[string]bool{"success": true})
+}
+
+// GetPublicFeedbackPortal возвращает публичные сведения о портале. Маршрут публичный
+func (h *FeedbackHandler) GetPublicFeedbackPortal(w http.ResponseWriter, r *http.Request) {
+	key := h.GetURLParam(r, "key")
+	if key == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Portal key is required")
+		return
+	}
+
+	info, err := h.feedbackService.GetPublicPortal(r.Context(), key)
+	if err != nil {
+		h.handleFeedbackError(w, r, err, key)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, info)
+}
+
+// SubmitFeedback принимает запрос внешнего пользователя. Маршрут публичный
+func (h *FeedbackHandler) SubmitFeedback(w http.ResponseWriter, r *http.Request) {
+	key := h.GetURLParam(r, "key")
+	if key == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Portal key is required")
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxWebhookBodySize)
+
+	var req domain.FeedbackSubmitRequest
+	if !h.parseFeedbackRequest(w, r, &req) {
+		return
+	}
+
+	result, err := h.feedbackService.Submit(r.Context(), key, req)
+	if err != nil {
+		h.handleFeedbackError(w, r, err, key)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, result)
+}
+
+// ListFeedbackSubmissions возвращает очередь запросов проекта
+func (h *FeedbackHandler) ListFeedbackSubmissions(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID проекта из URL
+	projectID := h.GetURLParam(r, "id")
+	if projectID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID is required")
+		return
+	}
+
+	// Параметры пагинации
+	page, ok := h.GetPageRequest(w, r)
+	if !ok {
+		return
+	}
+
+	// Фильтр по статусу
+	var status *domain.FeedbackStatus
+	if value := r.URL.Query().Get("status"); value != "" {
+		feedbackStatus := domain.FeedbackStatus(value)
+		status = &feedbackStatus
+	}
+
+	result, err := h.feedbackService.List(r.Context(), projectID, status, page)
+	if err != nil {
+		h.handleFeedbackError(w, r, err, projectID)
+		return
+	}
+
+	h.RespondWithPagination(w, r, result.Items, result)
+}
+
+// GetFeedbackSubmission возвращает запрос с похожими задачами проекта
+func (h *FeedbackHandler) GetFeedbackSubmission(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID проекта и запроса из URL
+	projectID := h.GetURLParam(r, "id")
+	submissionID := h.GetURLParam(r, "submission_id")
+	if projectID == "" || submissionID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID and submission ID are required")
+		return
+	}
+
+	submission, err := h.feedbackService.Get(r.Context(), projectID, submissionID)
+	if err != nil {
+		h.handleFeedbackError(w, r, err, submissionID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, submission)
+}
+
+// ApproveFeedbackSubmission создает задачу по запросу
+func (h *FeedbackHandler) ApproveFeedbackSubmission(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID проекта и запроса из URL
+	projectID := h.GetURLParam(r, "id")
+	submissionID := h.GetURLParam(r, "submission_id")
+	if projectID == "" || submissionID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID and submission ID are required")
+		return
+	}
+
+	var req domain.FeedbackApproveRequest
+	if !h.parseFeedbackRequest(w, r, &req) {
+		return
+	}
+
+	submission, err := h.feedbackService.Approve(r.Context(), projectID, submissionID, req, userID)
+	if err != nil {
+		h.handleFeedbackError(w, r, err, submissionID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, submission)
+}
+
+// MergeFeedbackSubmission объединяет запрос с существующей задачей
+func (h *FeedbackHandler) MergeFeedbackSubmission(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID проекта и запроса из URL
+	projectID := h.GetURLParam(r, "id")
+	submissionID := h.GetURLParam(r, "submission_id")
+	if projectID == "" || submissionID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID and submission ID are required")
+		return
+	}
+
+	var req domain.FeedbackMergeRequest
+	if !h.parseFeedbackRequest(w, r, &req) {
+		return
+	}
+
+	submission, err := h.feedbackService.Merge(r.Context(), projectID, submissionID, req, userID)
+	if err != nil {
+		h.handleFeedbackError(w, r, err, submissionID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, submission)
+}
+
+// RejectFeedbackSubmission отклоняет запрос
+func (h *FeedbackHandler) RejectFeedbackSubmission(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID проекта и запроса из URL
+	projectID := h.GetURLParam(r, "id")
+	submissionID := h.GetURLParam(r, "submission_id")
+	if projectID == "" || submissionID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID and submission ID are required")
+		return
+	}
+
+	var req domain.FeedbackRejectRequest
+	if !h.parseFeedbackRequest(w, r, &req) {
+		return
+	}
+
+	submission, err := h.feedbackService.Reject(r.Context(), projectID, submissionID, req, userID)
+	if err != nil {
+		h.handleFeedbackError(w, r, err, submissionID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, submission)
+}
+
+// parseFeedbackRequest разбирает и валидирует тело запроса
+func (h *FeedbackHandler) parseFeedbackRequest(w http.ResponseWriter, r *http.Request, req interface{}) bool {
+	if err := h.ParseJSON(r, req); err != nil {
+		h.Logger.Error("Failed to parse feedback request", err)
+		h.RespondWithError(w, r, apperrors.CodeInvalidFormat, "Invalid request format")
+		return false
+	}
+
+	// Валидация запроса
+	if validationErrors, err := h.ValidateRequest(r, req); err != nil {
+		h.Logger.Error("Request validation error", err)
+		h.RespondWithError(w, r, apperrors.CodeInternalError, "Validation failed")
+		return false
+	} else if len(validationErrors) > 0 {
+		h.RespondWithValidationErrors(w, r, validationErrors)
+		return false
+	}
+
+	return true
+}
+
+// handleFeedbackError преобразует ошибки портала обратной связи в HTTP-ответы
+func (h *FeedbackHandler) handleFeedbackError(w http.ResponseWriter, r *http.Request, err error, id string) {
+	var validationErr *service.TaskValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		validationErrors := make([]ValidationError, 0, len(validationErr.Violations))
+		for _, violation := range validationErr.Violations {
+			validationErrors = append(validationErrors, ValidationError{
+				Field:   violation.Field,
+				Message: violation.Message,
+			})
+		}
+		h.RespondWithValidationErrors(w, r, validationErrors)
+	case errors.Is(err, service.ErrProjectNotFound):
+		h.RespondWithError(w, r, apperrors.CodeProjectNotFound, "Project not found")
+	case errors.Is(err, service.ErrFeedbackPortalNotFound):
+		h.RespondWithError(w, r, apperrors.CodeFeedbackPortalNotFound, "Feedback portal not found")
+	case errors.Is(err, service.ErrFeedbackSubmissionNotFound):
+		h.RespondWithError(w, r, apperrors.CodeFeedbackSubmissionNotFound, "Feedback submission not found")
+	case errors.Is(err, service.ErrFeedbackAlreadyModerated):
+		h.RespondWithError(w, r, apperrors.CodeFeedbackAlreadyModerated, "Feedback submission has already been moderated")
+	case errors.Is(err, service.ErrFeedbackTooManyPending):
+		h.RespondWithError(w, r, apperrors.CodeTooManyPending, "Too many requests are awaiting review")
+	case errors.Is(err, service.ErrFeedbackTaskInvalid):
+		h.RespondWithError(w, r, apperrors.CodeInvalidTask, "Task must belong to the project")
+	case errors.Is(err, service.ErrInsufficientRights):
+		h.RespondWithError(w, r, apperrors.CodeInsufficientRights, "Insufficient rights to moderate feedback")
+	case errors.Is(err, service.ErrProjectArchived):
+		h.RespondWithError(w, r, apperrors.CodeProjectArchived, "Project is archived and read-only")
+	default:
+		h.Logger.Error("Failed to process feedback request", err, map[string]interface{}{
+			"id": id,
+		})
+		h.RespondWithError(w, r, apperrors.CodeFeedbackFailed, "Failed to process feedback request")
+	}
+}
@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/internal/service"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
+)
+
+// InboundEmailHandler обрабатывает запросы, связанные с адресом проекта для создания задач по email
+type InboundEmailHandler struct {
+	BaseHandler
+	inboundEmailService *service.InboundEmailService
+}
+
+// NewInboundEmailHandler создает новый экземпляр InboundEmailHandler
+func NewInboundEmailHandler(base BaseHandler, inboundEmailService *service.InboundEmailService) *InboundEmailHandler {
+	return &InboundEmailHandler{
+		BaseHandler:         base,
+		inboundEmailService: inboundEmailService,
+	}
+}
+
+// GetInboundEmail возвращает адрес проекта для создания задач по email
+func (h *InboundEmailHandler) GetInboundEmail(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID проекта из URL
+	projectID := h.GetURLParam(r, "id")
+	if projectID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID is required")
+		return
+	}
+
+	inbound, err := h.inboundEmailService.Get(r.Context(), projectID)
+	if err != nil {
+		h.handleInboundEmailError(w, r, err, projectID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, inbound)
+}
+
+// GenerateInboundEmail создает адрес проекта или заменяет его новым
+func (h *InboundEmailHandler) GenerateInboundEmail(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID проекта из URL
+	projectID := h.GetURLParam(r, "id")
+	if projectID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID is required")
+		return
+	}
+
+	inbound, err := h.inboundEmailService.Generate(r.Context(), projectID, userID)
+	if err != nil {
+		h.handleInboundEmailError(w, r, err, projectID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, inbound)
+}
+
+// UpdateInboundEmail изменяет настройки адреса проекта
+func (h *InboundEmailHandler) UpdateInboundEmail(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID проекта из URL
+	projectID := h.GetURLParam(r, "id")
+	if projectID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID is required")
+		return
+	}
+
+	var req domain.ProjectInboundEmailRequest
+	if err := h.ParseJSON(r, &req); err != nil {
+		h.Logger.Error("Failed to parse inbound email request", err)
+		h.RespondWithError(w, r, apperrors.CodeInvalidFormat, "Invalid request format")
+		return
+	}
+
+	// Валидация запроса
+	if validationErrors, err := h.ValidateRequest(r, req); err != nil {
+		h.Logger.Error("Request validation error", err)
+		h.RespondWithError(w, r, apperrors.CodeInternalError, "Validation failed")
+		return
+	} else if len(validationErrors) > 0 {
+		h.RespondWithValidationErrors(w, r, validationErrors)
+		return
+	}
+
+	inbound, err := h.inboundEmailService.Update(r.Context(), projectID, req)
+	if err != nil {
+		h.handleInboundEmailError(w, r, err, projectID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, inbound)
+}
+
+// DeleteInboundEmail удаляет адрес проекта
+func (h *InboundEmailHandler) DeleteInboundEmail(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID проекта из URL
+	projectID := h.GetURLParam(r, "id")
+	if projectID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID is required")
+		return
+	}
+
+	if err := h.inboundEmailService.Delete(r.Context(), projectID); err != nil {
+		h.handleInboundEmailError(w, r, err, projectID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, map[string]bool{"success": true})
+}
+
+// handleInboundEmailError преобразует ошибки адреса проекта в HTTP-ответы
+func (h *InboundEmailHandler) handleInboundEmailError(w http.ResponseWriter, r *http.Request, err error, projectID string) {
+	switch {
+	case errors.Is(err, service.ErrProjectNotFound):
+		h.RespondWithError(w, r, apperrors.CodeProjectNotFound, "Project not found")
+	case errors.Is(err, service.ErrInboundEmailNotFound):
+		h.RespondWithError(w, r, apperrors.CodeInboundEmailNotFound, "Inbound email address is not generated for this project")
+	default:
+		h.Logger.Error("Failed to process inbound email settings", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		h.RespondWithError(w, r, apperrors.CodeInboundEmailFailed, "Failed to process inbound email settings")
+	}
+}
@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/internal/service"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
+)
+
+// InboxHandler обрабатывает запросы, связанные со входящими задачами пользователя
+type InboxHandler struct {
+	BaseHandler
+	inboxService *service.InboxService
+}
+
+// NewInboxHandler создает новый экземпляр InboxHandler
+func NewInboxHandler(base BaseHandler, inboxService *service.InboxService) *InboxHandler {
+	return &InboxHandler{
+		BaseHandler:  base,
+		inboxService: inboxService,
+	}
+}
+
+// CaptureInboxItem записывает задачу во входящие текущего пользователя
+func (h *InboxHandler) CaptureInboxItem(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req domain.InboxCaptureRequest
+	if err := h.ParseJSON(r, &req); err != nil {
+		h.RespondWithError(w, r, apperrors.CodeInvalidFormat, "Invalid request format")
+		return
+	}
+
+	// Валидация запроса
+	if validationErrors, err := h.ValidateRequest(r, req); err != nil {
+		h.Logger.Error("Request validation error", err)
+		h.RespondWithError(w, r, apperrors.CodeInternalError, "Validation failed")
+		return
+	} else if len(validationErrors) > 0 {
+		h.RespondWithValidationErrors(w, r, validationErrors)
+		return
+	}
+
+	item, err := h.inboxService.Capture(r.Context(), req, userID)
+	if err != nil {
+		h.handleInboxError(w, r, err, userID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, item)
+}
+
+// ListInbox возвращает входящие текущего пользователя, начиная со старых
+func (h *InboxHandler) ListInbox(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Параметры пагинации
+	page, ok := h.GetPageRequest(w, r)
+	if !ok {
+		return
+	}
+
+	result, err := h.inboxService.List(r.Context(), userID, page)
+	if err != nil {
+		h.handleInboxError(w, r, err, userID)
+		return
+	}
+
+	h.RespondWithPagination(w, r, result.Items, result)
+}
+
+// DeleteInboxItem удаляет запись из входящих текущего пользователя
+func (h *InboxHandler) DeleteInboxItem(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID записи из URL
+	id := h.GetURLParam(r, "id")
+	if id == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Inbox item ID is required")
+		return
+	}
+
+	if err := h.inboxService.Delete(r.Context(), id, userID); err != nil {
+		h.handleInboxError(w, r, err, userID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, map[string]bool{"success": true})
+}
+
+// TriageInbox переносит записи входящих в проекты. Каждая запись переносится
+// независимо, результат возвращается по каждой записи
+func (h *InboxHandler) TriageInbox(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req domain.InboxTriageRequest
+	if err := h.ParseJSON(r, &req); err != nil {
+		h.RespondWithError(w, r, apperrors.CodeInvalidFormat, "Invalid request format")
+		return
+	}
+	if !h.ValidateBatchRequest(w, r, req) {
+		return
+	}
+
+	result := domain.NewBatchResult(len(req.Items))
+	for i, item := range req.Items {
+		if err := h.ValidateBatchItem(r, item); err != nil {
+			h.AddBatchItem(result, i, item.ID, nil, err)
+			continue
+		}
+
+		task, err := h.inboxService.Triage(r.Context(), item, userID)
+		h.AddBatchItem(result, i, item.ID, task, err)
+	}
+
+	h.RespondWithBatch(w, r, result)
+}
+
+// handleInboxError отправляет ответ для ошибки сервиса входящих
+func (h *InboxHandler) handleInboxError(w http.ResponseWriter, r *http.Request, err error, userID string) {
+	switch {
+	case errors.Is(err, service.ErrInboxItemNotFound):
+		h.RespondWithError(w, r, apperrors.CodeInboxItemNotFound, "Inbox item not found")
+	default:
+		h.Logger.Error("Failed to process inbox request", err, map[string]interface{}{
+			"user_id": userID,
+		})
+		h.RespondWithError(w, r, apperrors.CodeInboxFailed, "Failed to process inbox request")
+	}
+}
@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/internal/service"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
+)
+
+// IncidentHandler обрабатывает запросы, связанные с интеграциями PagerDuty и Opsgenie
+type IncidentHandler struct {
+	BaseHandler
+	incidentService *service.IncidentService
+}
+
+// NewIncidentHandler создает новый экземпляр IncidentHandler
+func NewIncidentHandler(base BaseHandler, incidentService *service.IncidentService) *IncidentHandler {
+	return &IncidentHandler{
+		BaseHandler:     base,
+		incidentService: incidentService,
+	}
+}
+
+// ListIncidentIntegrations возвращает интеграции проекта с системами управления инцидентами
+func (h *IncidentHandler) ListIncidentIntegrations(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID проекта из URL
+	projectID := h.GetURLParam(r, "id")
+	if projectID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID is required")
+		return
+	}
+
+	integrations, err := h.incidentService.List(r.Context(), projectID)
+	if err != nil {
+		h.handleIncidentError(w, r, err, projectID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, integrations)
+}
+
+// GetIncidentIntegration возвращает интеграцию проекта с системой управления инцидентами
+func (h *IncidentHandler) GetIncidentIntegration(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID проекта и систему из URL
+	projectID := h.GetURLParam(r, "id")
+	provider := domain.IncidentProvider(h.GetURLParam(r, "provider"))
+	if projectID == "" || provider == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID and provider are required")
+		return
+	}
+
+	integration, err := h.incidentService.Get(r.Context(), projectID, provider)
+	if err != nil {
+		h.handleIncidentError(w, r, err, projectID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, integration)
+}
+
+// SaveIncidentIntegration создает или обновляет интеграцию проекта с системой управления инцидентами
+func (h *IncidentHandler) SaveIncidentIntegration(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID проекта и систему из URL
+	projectID := h.GetURLParam(r, "id")
+	provider := domain.IncidentProvider(h.GetURLParam(r, "provider"))
+	if projectID == "" || provider == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID and provider are required")
+		return
+	}
+
+	var req domain.IncidentIntegrationRequest
+	if err := h.ParseJSON(r, &req); err != nil {
+		h.Logger.Error("Failed to parse incident integration request", err)
+		h.RespondWithError(w, r, apperrors.CodeInvalidFormat, "Invalid request format")
+		return
+	}
+
+	// Валидация запроса
+	if validationErrors, err := h.ValidateRequest(r, req); err != nil {
+		h.Logger.Error("Request validation error", err)
+		h.RespondWithError(w, r, apperrors.CodeInternalError, "Validation failed")
+		return
+	} else if len(validationErrors) > 0 {
+		h.RespondWithValidationErrors(w, r, validationErrors)
+		return
+	}
+
+	integration, err := h.incidentService.Save(r.Context(), projectID, provider, req, userID)
+	if err != nil {
+		h.handleIncidentError(w, r, err, projectID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, integration)
+}
+
+// DeleteIncidentIntegration удаляет интеграцию проекта с системой управления инцидентами
+func (h *IncidentHandler) DeleteIncidentIntegration(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID проекта и систему из URL
+	projectID := h.GetURLParam(r, "id")
+	provider := domain.IncidentProvider(h.GetURLParam(r, "provider"))
+	if projectID == "" || provider == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID and provider are required")
+		return
+	}
+
+	if err := h.incidentService.Delete(r.Context(), projectID, provider); err != nil {
+		h.handleIncidentError(w, r, err, projectID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, map[string]bool{"success": true})
+}
+
+// ReceiveIncidentWebhook принимает вебхук PagerDuty или Opsgenie.
+// Маршрут публичный: PagerDuty подписывает запрос заголовком X-PagerDuty-Signature, Opsgenie передает токен в URL
+func (h *IncidentHandler) ReceiveIncidentWebhook(w http.ResponseWriter, r *http.Request) {
+	id := h.GetURLParam(r, "id")
+	if id == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Integration ID is required")
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBodySize))
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodePayloadTooLarge, "Request body is too large")
+		return
+	}
+
+	link, err := h.incidentService.Receive(
+		r.Context(),
+		id,
+		body,
+		r.Header.Get("X-PagerDuty-Signature"),
+		r.URL.Query().Get("token"),
+	)
+	if err != nil {
+		h.handleIncidentError(w, r, err, id)
+		return
+	}
+
+	// Системы инцидентов повторяют доставку при ошибках, поэтому игнорируемые события тоже подтверждаются
+	if link == nil {
+		h.RespondWithSuccess(w, r, map[string]bool{"success": true})
+		return
+	}
+	h.RespondWithSuccess(w, r, link)
+}
+
+// handleIncidentError преобразует ошибки интеграций с системами управления инцидентами в HTTP-ответы
+func (h *IncidentHandler) handleIncidentError(w http.ResponseWriter, r *http.Request, err error, id string) {
+	var validationErr *service.TaskValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		validationErrors := make([]ValidationError, 0, len(validationErr.Violations))
+		for _, violation := range validationErr.Violations {
+			validationErrors = append(validationErrors, ValidationError{
+				Field:   violation.Field,
+				Message: violation.Message,
+			})
+		}
+		h.RespondWithValidationErrors(w, r, validationErrors)
+	case errors.Is(err, service.ErrIncidentIntegrationNotFound):
+		h.RespondWithError(w, r, apperrors.CodeIncidentIntegrationNotFound, "Incident integration not found")
+	case errors.Is(err, service.ErrIncidentProviderUnsupported):
+		h.RespondWithError(w, r, apperrors.CodeUnsupportedProvider, "Supported providers are pagerduty and opsgenie")
+	case errors.Is(err, service.ErrIncidentSignatureInvalid):
+		h.RespondWithError(w, r, apperrors.CodeInvalidSignature, "Invalid webhook signature")
+	case errors.Is(err, service.ErrIncidentPayloadInvalid):
+		h.RespondWithError(w, r, apperrors.CodeInvalidFormat, "Invalid incident webhook payload")
+	case errors.Is(err, service.ErrInvalidAssignee):
+		h.RespondWithError(w, r, apperrors.CodeInvalidAssignee, "Assignee must be a member of the project")
+	case errors.Is(err, service.ErrProjectArchived):
+		h.RespondWithError(w, r, apperrors.CodeProjectArchived, "Project is archived and read-only")
+	default:
+		h.Logger.Error("Failed to process incident integration", err, map[string]interface{}{
+			"id": id,
+		})
+		h.RespondWithError(w, r, apperrors.CodeIncidentIntegrationFailed, "Failed to process incident integration")
+	}
+}
@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/nurlyy/task_manager/internal/api/query"
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/internal/service"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
+)
+
+// JobRunHandler обрабатывает запросы истории запусков задач планировщика
+type JobRunHandler struct {
+	BaseHandler
+	jobRunService *service.JobRunService
+}
+
+// NewJobRunHandler создает новый экземпляр JobRunHandler
+func NewJobRunHandler(base BaseHandler, jobRunService *service.JobRunService) *JobRunHandler {
+	return &JobRunHandler{
+		BaseHandler:   base,
+		jobRunService: jobRunService,
+	}
+}
+
+var jobRunStatuses = []domain.JobRunStatus{
+	domain.JobRunStatusRunning, domain.JobRunStatusSucceeded, domain.JobRunStatusFailed,
+}
+
+// ListJobs возвращает последний запуск каждой задачи планировщика
+func (h *JobRunHandler) ListJobs(w http.ResponseWriter, r *http.Request) {
+	runs, err := h.jobRunService.ListLatest(r.Context())
+	if err != nil {
+		h.handleJobRunError(w, r, err)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, runs)
+}
+
+// ListJobRuns возвращает историю запусков задач планировщика с фильтрацией по задаче,
+// статусу и превышению интервала расписания
+func (h *JobRunHandler) ListJobRuns(w http.ResponseWriter, r *http.Request) {
+	q := query.New(r)
+	filter := domain.JobRunFilterOptions{
+		Job:     q.String("job"),
+		Status:  query.Enum(q, "status", jobRunStatuses...),
+		Overran: q.Bool("overran"),
+	}
+
+	page, ok := h.ParseQuery(w, r, q)
+	if !ok {
+		return
+	}
+
+	result, err := h.jobRunService.ListRuns(r.Context(), filter, page)
+	if err != nil {
+		h.handleJobRunError(w, r, err)
+		return
+	}
+
+	h.RespondWithPagination(w, r, result.Items, result)
+}
+
+// handleJobRunError преобразует ошибки сервиса в ответы API
+func (h *JobRunHandler) handleJobRunError(w http.ResponseWriter, r *http.Request, err error) {
+	h.Logger.Error("Failed to get scheduler job runs", err)
+	h.RespondWithError(w, r, apperrors.CodeJobRunsFetchFailed, "Failed to get scheduler job runs")
+}
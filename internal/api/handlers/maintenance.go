@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/internal/service"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
+)
+
+// MaintenanceHandler обрабатывает запросы управления режимом обслуживания
+type MaintenanceHandler struct {
+	BaseHandler
+	maintenanceService *service.MaintenanceService
+}
+
+// NewMaintenanceHandler создает новый экземпляр MaintenanceHandler
+func NewMaintenanceHandler(base BaseHandler, maintenanceService *service.MaintenanceService) *MaintenanceHandler {
+	return &MaintenanceHandler{
+		BaseHandler:        base,
+		maintenanceService: maintenanceService,
+	}
+}
+
+// GetMaintenance возвращает состояние режима обслуживания
+func (h *MaintenanceHandler) GetMaintenance(w http.ResponseWriter, r *http.Request) {
+	status, err := h.maintenanceService.Get(r.Context())
+	if err != nil {
+		h.handleMaintenanceError(w, r, err)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, status)
+}
+
+// SetMaintenance включает или выключает режим обслуживания
+func (h *MaintenanceHandler) SetMaintenance(w http.ResponseWriter, r *http.Request) {
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req domain.MaintenanceRequest
+	if err := h.ParseJSON(r, &req); err != nil {
+		h.Logger.Error("Failed to parse maintenance request", err)
+		h.RespondWithError(w, r, apperrors.CodeInvalidFormat, "Invalid request format")
+		return
+	}
+
+	if validationErrors, err := h.ValidateRequest(r, req); err != nil {
+		h.Logger.Error("Request validation error", err)
+		h.RespondWithError(w, r, apperrors.CodeInternalError, "Validation failed")
+		return
+	} else if len(validationErrors) > 0 {
+		h.RespondWithValidationErrors(w, r, validationErrors)
+		return
+	}
+
+	status, err := h.maintenanceService.Set(r.Context(), req, userID)
+	if err != nil {
+		h.handleMaintenanceError(w, r, err)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, status)
+}
+
+// handleMaintenanceError преобразует ошибки сервиса в ответы API
+func (h *MaintenanceHandler) handleMaintenanceError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, service.ErrMaintenanceForced):
+		h.RespondWithError(w, r, apperrors.CodeMaintenanceForced, "Read-only mode is forced by instance configuration and ends on restart")
+	default:
+		h.Logger.Error("Failed to handle maintenance request", err)
+		h.RespondWithError(w, r, apperrors.CodeMaintenanceFailed, "Failed to handle maintenance request")
+	}
+}
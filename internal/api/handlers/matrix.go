@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/internal/service"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
+)
+
+// MatrixHandler обрабатывает запросы, связанные с комнатами Matrix проектов
+type MatrixHandler struct {
+	BaseHandler
+	matrixService *service.MatrixService
+}
+
+// NewMatrixHandler создает новый экземпляр MatrixHandler
+func NewMatrixHandler(base BaseHandler, matrixService *service.MatrixService) *MatrixHandler {
+	return &MatrixHandler{
+		BaseHandler:   base,
+		matrixService: matrixService,
+	}
+}
+
+// GetProjectMatrixRoom возвращает комнату Matrix проекта
+func (h *MatrixHandler) GetProjectMatrixRoom(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID проекта из URL
+	projectID := h.GetURLParam(r, "id")
+	if projectID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID is required")
+		return
+	}
+
+	room, err := h.matrixService.GetProjectRoom(r.Context(), projectID)
+	if err != nil {
+		h.handleMatrixError(w, r, err, projectID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, room)
+}
+
+// SaveProjectMatrixRoom создает или обновляет комнату Matrix проекта
+func (h *MatrixHandler) SaveProjectMatrixRoom(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID проекта из URL
+	projectID := h.GetURLParam(r, "id")
+	if projectID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID is required")
+		return
+	}
+
+	var req domain.ProjectMatrixRoomRequest
+	if err := h.ParseJSON(r, &req); err != nil {
+		h.Logger.Error("Failed to parse project Matrix room request", err)
+		h.RespondWithError(w, r, apperrors.CodeInvalidFormat, "Invalid request format")
+		return
+	}
+
+	// Валидация запроса
+	if validationErrors, err := h.ValidateRequest(r, req); err != nil {
+		h.Logger.Error("Request validation error", err)
+		h.RespondWithError(w, r, apperrors.CodeInternalError, "Validation failed")
+		return
+	} else if len(validationErrors) > 0 {
+		h.RespondWithValidationErrors(w, r, validationErrors)
+		return
+	}
+
+	room, err := h.matrixService.SaveProjectRoom(r.Context(), projectID, req, userID)
+	if err != nil {
+		h.handleMatrixError(w, r, err, projectID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, room)
+}
+
+// DeleteProjectMatrixRoom удаляет привязку комнаты Matrix к проекту
+func (h *MatrixHandler) DeleteProjectMatrixRoom(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID проекта из URL
+	projectID := h.GetURLParam(r, "id")
+	if projectID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID is required")
+		return
+	}
+
+	if err := h.matrixService.DeleteProjectRoom(r.Context(), projectID); err != nil {
+		h.handleMatrixError(w, r, err, projectID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, map[string]bool{"success": true})
+}
+
+// handleMatrixError преобразует ошибки интеграции с Matrix в HTTP-ответы
+func (h *MatrixHandler) handleMatrixError(w http.ResponseWriter, r *http.Request, err error, id string) {
+	var validationErr *service.TaskValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		validationErrors := make([]ValidationError, 0, len(validationErr.Violations))
+		for _, violation := range validationErr.Violations {
+			validationErrors = append(validationErrors, ValidationError{
+				Field:   violation.Field,
+				Message: violation.Message,
+			})
+		}
+		h.RespondWithValidationErrors(w, r, validationErrors)
+	case errors.Is(err, service.ErrMatrixRoomNotFound):
+		h.RespondWithError(w, r, apperrors.CodeMatrixRoomNotFound, "Matrix room not found")
+	case errors.Is(err, service.ErrMatrixRoomInvalid):
+		h.RespondWithError(w, r, apperrors.CodeInvalidMatrixRoom, "Room must be a Matrix room ID or alias")
+	case errors.Is(err, service.ErrMatrixNotConfigured):
+		h.RespondWithError(w, r, apperrors.CodeMatrixNotConfigured, "Matrix homeserver is not configured")
+	case errors.Is(err, service.ErrMatrixUnreachable):
+		h.RespondWithError(w, r, apperrors.CodeMatrixUnreachable, "Failed to join the Matrix room or post a test message")
+	default:
+		h.Logger.Error("Failed to process Matrix request", err, map[string]interface{}{
+			"id": id,
+		})
+		h.RespondWithError(w, r, apperrors.CodeMatrixFailed, "Failed to process Matrix request")
+	}
+}
@@ -0,0 +1,281 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/internal/service"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
+)
+
+// MeetingNoteHandler обрабатывает запросы, связанные с заметками встреч
+type MeetingNoteHandler struct {
+	BaseHandler
+	meetingNoteService *service.MeetingNoteService
+}
+
+// NewMeetingNoteHandler создает новый экземпляр MeetingNoteHandler
+func NewMeetingNoteHandler(base BaseHandler, meetingNoteService *service.MeetingNoteService) *MeetingNoteHandler {
+	return &MeetingNoteHandler{
+		BaseHandler:        base,
+		meetingNoteService: meetingNoteService,
+	}
+}
+
+// ListProjectMeetingNotes возвращает заметки встреч проекта
+func (h *MeetingNoteHandler) ListProjectMeetingNotes(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID проекта из URL
+	projectID := h.GetURLParam(r, "id")
+	if projectID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID is required")
+		return
+	}
+
+	// Параметры пагинации
+	page, ok := h.GetPageRequest(w, r)
+	if !ok {
+		return
+	}
+
+	result, err := h.meetingNoteService.List(r.Context(), projectID, page)
+	if err != nil {
+		h.handleMeetingNoteError(w, r, err, projectID)
+		return
+	}
+
+	h.RespondWithPagination(w, r, result.Items, result)
+}
+
+// CreateMeetingNote создает заметку встречи
+func (h *MeetingNoteHandler) CreateMeetingNote(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID проекта из URL
+	projectID := h.GetURLParam(r, "id")
+	if projectID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID is required")
+		return
+	}
+
+	var req domain.MeetingNoteCreateRequest
+	if !h.parseMeetingNoteRequest(w, r, &req) {
+		return
+	}
+
+	note, err := h.meetingNoteService.Create(r.Context(), projectID, req, userID)
+	if err != nil {
+		h.handleMeetingNoteError(w, r, err, projectID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, note)
+}
+
+// GetMeetingNote возвращает заметку встречи
+func (h *MeetingNoteHandler) GetMeetingNote(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID заметки из URL
+	noteID := h.GetURLParam(r, "id")
+	if noteID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Meeting note ID is required")
+		return
+	}
+
+	note, err := h.meetingNoteService.Get(r.Context(), noteID, userID)
+	if err != nil {
+		h.handleMeetingNoteError(w, r, err, noteID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, note)
+}
+
+// UpdateMeetingNote обновляет заметку встречи
+func (h *MeetingNoteHandler) UpdateMeetingNote(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID заметки из URL
+	noteID := h.GetURLParam(r, "id")
+	if noteID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Meeting note ID is required")
+		return
+	}
+
+	var req domain.MeetingNoteUpdateRequest
+	if !h.parseMeetingNoteRequest(w, r, &req) {
+		return
+	}
+
+	note, err := h.meetingNoteService.Update(r.Context(), noteID, req, userID)
+	if err != nil {
+		h.handleMeetingNoteError(w, r, err, noteID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, note)
+}
+
+// DeleteMeetingNote удаляет заметку встречи
+func (h *MeetingNoteHandler) DeleteMeetingNote(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID заметки из URL
+	noteID := h.GetURLParam(r, "id")
+	if noteID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Meeting note ID is required")
+		return
+	}
+
+	if err := h.meetingNoteService.Delete(r.Context(), noteID, userID); err != nil {
+		h.handleMeetingNoteError(w, r, err, noteID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, map[string]bool{"success": true})
+}
+
+// ListMeetingActionItems возвращает поручения заметки встречи
+func (h *MeetingNoteHandler) ListMeetingActionItems(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID заметки из URL
+	noteID := h.GetURLParam(r, "id")
+	if noteID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Meeting note ID is required")
+		return
+	}
+
+	items, err := h.meetingNoteService.ListActionItems(r.Context(), noteID, userID)
+	if err != nil {
+		h.handleMeetingNoteError(w, r, err, noteID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, items)
+}
+
+// ConvertMeetingActionItems создает задачи из поручений заметки встречи
+func (h *MeetingNoteHandler) ConvertMeetingActionItems(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID заметки из URL
+	noteID := h.GetURLParam(r, "id")
+	if noteID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Meeting note ID is required")
+		return
+	}
+
+	var req domain.MeetingActionItemsConvertRequest
+	if !h.parseMeetingNoteRequest(w, r, &req) {
+		return
+	}
+
+	result, err := h.meetingNoteService.ConvertActionItems(r.Context(), noteID, req, userID)
+	if err != nil {
+		h.handleMeetingNoteError(w, r, err, noteID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, result)
+}
+
+// GetTaskMeetingNote возвращает заметку встречи, из которой создана задача
+func (h *MeetingNoteHandler) GetTaskMeetingNote(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID задачи из URL
+	taskID := h.GetURLParam(r, "id")
+	if taskID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Task ID is required")
+		return
+	}
+
+	note, err := h.meetingNoteService.GetTaskNote(r.Context(), taskID, userID)
+	if err != nil {
+		h.handleMeetingNoteError(w, r, err, taskID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, note)
+}
+
+// parseMeetingNoteRequest разбирает и валидирует тело запроса
+func (h *MeetingNoteHandler) parseMeetingNoteRequest(w http.ResponseWriter, r *http.Request, req interface{}) bool {
+	if err := h.ParseJSON(r, req); err != nil {
+		h.Logger.Error("Failed to parse meeting note request", err)
+		h.RespondWithError(w, r, apperrors.CodeInvalidFormat, "Invalid request format")
+		return false
+	}
+
+	// Валидация запроса
+	if validationErrors, err := h.ValidateRequest(r, req); err != nil {
+		h.Logger.Error("Request validation error", err)
+		h.RespondWithError(w, r, apperrors.CodeInternalError, "Validation failed")
+		return false
+	} else if len(validationErrors) > 0 {
+		h.RespondWithValidationErrors(w, r, validationErrors)
+		return false
+	}
+
+	return true
+}
+
+// handleMeetingNoteError преобразует ошибки заметок встреч в HTTP-ответы
+func (h *MeetingNoteHandler) handleMeetingNoteError(w http.ResponseWriter, r *http.Request, err error, id string) {
+	switch {
+	case errors.Is(err, service.ErrProjectNotFound):
+		h.RespondWithError(w, r, apperrors.CodeProjectNotFound, "Project not found")
+	case errors.Is(err, service.ErrMeetingNoteNotFound):
+		h.RespondWithError(w, r, apperrors.CodeMeetingNoteNotFound, "Meeting note not found")
+	case errors.Is(err, service.ErrTaskNotFound):
+		h.RespondWithError(w, r, apperrors.CodeTaskNotFound, "Task not found")
+	case errors.Is(err, service.ErrTaskAccessDenied):
+		h.RespondWithError(w, r, apperrors.CodeAccessDenied, "Access denied to the task")
+	case errors.Is(err, service.ErrInsufficientRights):
+		h.RespondWithError(w, r, apperrors.CodeInsufficientRights, "Insufficient rights to manage meeting notes")
+	case errors.Is(err, service.ErrProjectArchived):
+		h.RespondWithError(w, r, apperrors.CodeProjectArchived, "Project is archived and read-only")
+	default:
+		h.Logger.Error("Failed to process meeting note request", err, map[string]interface{}{
+			"id": id,
+		})
+		h.RespondWithError(w, r, apperrors.CodeMeetingNoteFailed, "Failed to process meeting note request")
+	}
+}
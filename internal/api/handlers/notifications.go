@@ -3,23 +3,28 @@ package handlers
 import (
 	"errors"
 	"net/http"
+	"time"
 
+	"github.com/nurlyy/task_manager/internal/api/query"
 	"github.com/nurlyy/task_manager/internal/domain"
 	"github.com/nurlyy/task_manager/internal/repository"
 	"github.com/nurlyy/task_manager/internal/service"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
 )
 
 // NotificationHandler обрабатывает запросы, связанные с уведомлениями
 type NotificationHandler struct {
 	BaseHandler
 	notificationService *service.NotificationService
+	unreadCountHub      *service.UnreadCountHub
 }
 
 // NewNotificationHandler создает новый экземпляр NotificationHandler
-func NewNotificationHandler(base BaseHandler, notificationService *service.NotificationService) *NotificationHandler {
+func NewNotificationHandler(base BaseHandler, notificationService *service.NotificationService, unreadCountHub *service.UnreadCountHub) *NotificationHandler {
 	return &NotificationHandler{
 		BaseHandler:         base,
 		notificationService: notificationService,
+		unreadCountHub:      unreadCountHub,
 	}
 }
 
@@ -28,14 +33,14 @@ func (h *NotificationHandler) GetNotification(w http.ResponseWriter, r *http.Req
 	// Получаем ID пользователя из контекста
 	userID, err := h.GetUserIDFromContext(r)
 	if err != nil {
-		h.RespondWithError(w, r, http.StatusUnauthorized, "Unauthorized", "unauthorized")
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
 		return
 	}
 
 	// Получаем ID уведомления из URL
 	notificationID := h.GetURLParam(r, "id")
 	if notificationID == "" {
-		h.RespondWithError(w, r, http.StatusBadRequest, "Notification ID is required", "missing_id")
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Notification ID is required")
 		return
 	}
 
@@ -43,13 +48,13 @@ func (h *NotificationHandler) GetNotification(w http.ResponseWriter, r *http.Req
 	notification, err := h.notificationService.GetByID(r.Context(), notificationID, userID)
 	if err != nil {
 		if errors.Is(err, service.ErrNotificationNotFound) {
-			h.RespondWithError(w, r, http.StatusNotFound, "Notification not found", "notification_not_found")
+			h.RespondWithError(w, r, apperrors.CodeNotificationNotFound, "Notification not found")
 			return
 		}
 		h.Logger.Error("Failed to get notification", err, map[string]interface{}{
 			"id": notificationID,
 		})
-		h.RespondWithError(w, r, http.StatusInternalServerError, "Failed to get notification info", "notification_fetch_failed")
+		h.RespondWithError(w, r, apperrors.CodeNotificationFetchFailed, "Failed to get notification info")
 		return
 	}
 
@@ -61,27 +66,27 @@ func (h *NotificationHandler) MarkAsRead(w http.ResponseWriter, r *http.Request)
 	// Получаем ID пользователя из контекста
 	userID, err := h.GetUserIDFromContext(r)
 	if err != nil {
-		h.RespondWithError(w, r, http.StatusUnauthorized, "Unauthorized", "unauthorized")
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
 		return
 	}
 
 	// Получаем ID уведомления из URL
 	notificationID := h.GetURLParam(r, "id")
 	if notificationID == "" {
-		h.RespondWithError(w, r, http.StatusBadRequest, "Notification ID is required", "missing_id")
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Notification ID is required")
 		return
 	}
 
 	// Отмечаем уведомление как прочитанное
 	if err := h.notificationService.MarkAsRead(r.Context(), notificationID, userID); err != nil {
 		if errors.Is(err, service.ErrNotificationNotFound) {
-			h.RespondWithError(w, r, http.StatusNotFound, "Notification not found", "notification_not_found")
+			h.RespondWithError(w, r, apperrors.CodeNotificationNotFound, "Notification not found")
 			return
 		}
 		h.Logger.Error("Failed to mark notification as read", err, map[string]interface{}{
 			"id": notificationID,
 		})
-		h.RespondWithError(w, r, http.StatusInternalServerError, "Failed to mark notification as read", "mark_read_failed")
+		h.RespondWithError(w, r, apperrors.CodeMarkReadFailed, "Failed to mark notification as read")
 		return
 	}
 
@@ -93,7 +98,7 @@ func (h *NotificationHandler) MarkAllAsRead(w http.ResponseWriter, r *http.Reque
 	// Получаем ID пользователя из контекста
 	userID, err := h.GetUserIDFromContext(r)
 	if err != nil {
-		h.RespondWithError(w, r, http.StatusUnauthorized, "Unauthorized", "unauthorized")
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
 		return
 	}
 
@@ -102,91 +107,161 @@ func (h *NotificationHandler) MarkAllAsRead(w http.ResponseWriter, r *http.Reque
 		h.Logger.Error("Failed to mark all notifications as read", err, map[string]interface{}{
 			"user_id": userID,
 		})
-		h.RespondWithError(w, r, http.StatusInternalServerError, "Failed to mark all notifications as read", "mark_all_read_failed")
+		h.RespondWithError(w, r, apperrors.CodeMarkAllReadFailed, "Failed to mark all notifications as read")
 		return
 	}
 
 	h.RespondWithSuccess(w, r, map[string]bool{"success": true})
 }
 
+// SweepNotifications отмечает прочитанными непрочитанные уведомления, подходящие под условия:
+// созданные до даты, указанных типов, относящиеся к сущности. Возвращает число отмеченных уведомлений
+func (h *NotificationHandler) SweepNotifications(w http.ResponseWriter, r *http.Request) {
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req domain.NotificationSweepRequest
+	if err := h.ParseJSON(r, &req); err != nil {
+		h.RespondWithError(w, r, apperrors.CodeInvalidFormat, "Invalid request format")
+		return
+	}
+	if validationErrors, err := h.ValidateRequest(r, req); err != nil {
+		h.Logger.Error("Request validation error", err)
+		h.RespondWithError(w, r, apperrors.CodeInternalError, "Validation failed")
+		return
+	} else if len(validationErrors) > 0 {
+		h.RespondWithValidationErrors(w, r, validationErrors)
+		return
+	}
+	if req.IsEmpty() {
+		h.RespondWithError(w, r, apperrors.CodeValidationFailed, "At least one of before, types, entity_type or entity_id is required")
+		return
+	}
+
+	count, err := h.notificationService.MarkAsReadByFilter(r.Context(), userID, req)
+	if err != nil {
+		h.Logger.Error("Failed to sweep notifications", err, map[string]interface{}{
+			"user_id": userID,
+		})
+		h.RespondWithError(w, r, apperrors.CodeMarkAllReadFailed, "Failed to mark notifications as read")
+		return
+	}
+
+	h.RespondWithSuccess(w, r, domain.NotificationSweepResult{Count: count})
+}
+
+// BatchNotifications отмечает прочитанными или удаляет несколько уведомлений.
+// Каждое уведомление обрабатывается независимо, результат возвращается по каждому
+func (h *NotificationHandler) BatchNotifications(w http.ResponseWriter, r *http.Request) {
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req domain.NotificationBatchRequest
+	if err := h.ParseJSON(r, &req); err != nil {
+		h.RespondWithError(w, r, apperrors.CodeInvalidFormat, "Invalid request format")
+		return
+	}
+	if !h.ValidateBatchRequest(w, r, req) {
+		return
+	}
+
+	result := domain.NewBatchResult(len(req.IDs))
+	for i, id := range req.IDs {
+		switch req.Action {
+		case domain.NotificationBatchRead:
+			err = h.notificationService.MarkAsRead(r.Context(), id, userID)
+		case domain.NotificationBatchDelete:
+			err = h.notificationService.Delete(r.Context(), id, userID)
+		}
+		h.AddBatchItem(result, i, id, nil, err)
+	}
+
+	h.RespondWithBatch(w, r, result)
+}
+
 // DeleteNotification удаляет уведомление
 func (h *NotificationHandler) DeleteNotification(w http.ResponseWriter, r *http.Request) {
 	// Получаем ID пользователя из контекста
 	userID, err := h.GetUserIDFromContext(r)
 	if err != nil {
-		h.RespondWithError(w, r, http.StatusUnauthorized, "Unauthorized", "unauthorized")
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
 		return
 	}
 
 	// Получаем ID уведомления из URL
 	notificationID := h.GetURLParam(r, "id")
 	if notificationID == "" {
-		h.RespondWithError(w, r, http.StatusBadRequest, "Notification ID is required", "missing_id")
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Notification ID is required")
 		return
 	}
 
 	// Удаляем уведомление
 	if err := h.notificationService.Delete(r.Context(), notificationID, userID); err != nil {
 		if errors.Is(err, service.ErrNotificationNotFound) {
-			h.RespondWithError(w, r, http.StatusNotFound, "Notification not found", "notification_not_found")
+			h.RespondWithError(w, r, apperrors.CodeNotificationNotFound, "Notification not found")
 			return
 		}
 		h.Logger.Error("Failed to delete notification", err, map[string]interface{}{
 			"id": notificationID,
 		})
-		h.RespondWithError(w, r, http.StatusInternalServerError, "Failed to delete notification", "delete_failed")
+		h.RespondWithError(w, r, apperrors.CodeDeleteFailed, "Failed to delete notification")
 		return
 	}
 
 	h.RespondWithSuccess(w, r, map[string]bool{"success": true})
 }
 
+// Допустимые значения параметров списка уведомлений
+var (
+	notificationStatuses = []domain.NotificationStatus{
+		domain.NotificationStatusUnread, domain.NotificationStatusRead,
+	}
+	notificationTypes = []domain.NotificationType{
+		domain.NotificationTypeTaskAssigned, domain.NotificationTypeTaskUpdated, domain.NotificationTypeTaskCommented,
+		domain.NotificationTypeTaskDueSoon, domain.NotificationTypeTaskOverdue, domain.NotificationTypeProjectMemberAdded,
+		domain.NotificationTypeProjectUpdated, domain.NotificationTypeDigest,
+	}
+	notificationEntityTypes = []string{"task", "project", "comment", "user"}
+)
+
 // ListNotifications возвращает список уведомлений пользователя
 func (h *NotificationHandler) ListNotifications(w http.ResponseWriter, r *http.Request) {
 	// Получаем ID пользователя из контекста
 	userID, err := h.GetUserIDFromContext(r)
 	if err != nil {
-		h.RespondWithError(w, r, http.StatusUnauthorized, "Unauthorized", "unauthorized")
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
 		return
 	}
 
-	// Параметры пагинации
-	page, pageSize := h.GetPaginationParams(r)
-
-	// Создаем фильтр
+	// Создаем фильтр и параметры пагинации
+	q := query.New(r)
 	filter := domain.NotificationFilterOptions{
-		UserID: &userID,
-	}
-
-	// Фильтр по статусу
-	if status := r.URL.Query().Get("status"); status != "" {
-		notificationStatus := domain.NotificationStatus(status)
-		filter.Status = &notificationStatus
-	}
-
-	// Фильтр по типу
-	if typeName := r.URL.Query().Get("type"); typeName != "" {
-		notificationType := domain.NotificationType(typeName)
-		filter.Type = &notificationType
+		UserID:     &userID,
+		Status:     query.Enum(q, "status", notificationStatuses...),
+		Type:       query.Enum(q, "type", notificationTypes...),
+		EntityID:   q.String("entity_id"),
+		EntityType: query.Enum(q, "entity_type", notificationEntityTypes...),
 	}
+	filter.StartDate, filter.EndDate = q.DateRange("start_date", "end_date")
 
-	// Фильтр по ID сущности
-	if entityID := r.URL.Query().Get("entity_id"); entityID != "" {
-		filter.EntityID = &entityID
-	}
-
-	// Фильтр по типу сущности
-	if entityType := r.URL.Query().Get("entity_type"); entityType != "" {
-		filter.EntityType = &entityType
+	page, ok := h.ParseQuery(w, r, q)
+	if !ok {
+		return
 	}
 
 	// Получаем список уведомлений
-	result, err := h.notificationService.GetUserNotifications(r.Context(), userID, filter, page, pageSize)
+	result, err := h.notificationService.GetUserNotifications(r.Context(), userID, filter, page)
 	if err != nil {
 		h.Logger.Error("Failed to list notifications", err, map[string]interface{}{
 			"user_id": userID,
 		})
-		h.RespondWithError(w, r, http.StatusInternalServerError, "Failed to get notifications", "notifications_fetch_failed")
+		h.RespondWithError(w, r, apperrors.CodeNotificationsFetchFailed, "Failed to get notifications")
 		return
 	}
 
@@ -198,7 +273,7 @@ func (h *NotificationHandler) GetUnreadCount(w http.ResponseWriter, r *http.Requ
 	// Получаем ID пользователя из контекста
 	userID, err := h.GetUserIDFromContext(r)
 	if err != nil {
-		h.RespondWithError(w, r, http.StatusUnauthorized, "Unauthorized", "unauthorized")
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
 		return
 	}
 
@@ -208,19 +283,92 @@ func (h *NotificationHandler) GetUnreadCount(w http.ResponseWriter, r *http.Requ
 		h.Logger.Error("Failed to get unread notifications count", err, map[string]interface{}{
 			"user_id": userID,
 		})
-		h.RespondWithError(w, r, http.StatusInternalServerError, "Failed to get unread count", "unread_count_failed")
+		h.RespondWithError(w, r, apperrors.CodeUnreadCountFailed, "Failed to get unread count")
 		return
 	}
 
 	h.RespondWithSuccess(w, r, map[string]int{"count": count})
 }
 
+// unreadCountPingInterval задает интервал комментариев в простаивающем потоке счетчика
+const unreadCountPingInterval = 25 * time.Second
+
+// unreadCountStreamMargin - запас до срока запроса, за который поток счетчика закрывается сам,
+// чтобы клиент переподключился, а не получил обрыв
+const unreadCountStreamMargin = 5 * time.Second
+
+// unreadCountRetry - пауза перед переподключением клиента после закрытия потока
+const unreadCountRetry = time.Second
+
+// StreamUnreadCount отправляет количество непрочитанных уведомлений потоком Server-Sent Events:
+// текущее значение сразу после подключения и новое значение при каждом изменении
+func (h *NotificationHandler) StreamUnreadCount(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Подписка оформляется до чтения текущего значения, чтобы не пропустить изменения между ними
+	updates, unsubscribe := h.unreadCountHub.Subscribe(userID)
+	defer unsubscribe()
+
+	ctx := r.Context()
+	count, err := h.notificationService.GetUnreadCount(ctx, userID)
+	if err != nil {
+		h.Logger.Error("Failed to get unread notifications count", err, map[string]interface{}{
+			"user_id": userID,
+		})
+		h.RespondWithError(w, r, apperrors.CodeUnreadCountFailed, "Failed to get unread count")
+		return
+	}
+
+	stream, ok := h.NewEventStream(w)
+	if !ok {
+		h.RespondWithError(w, r, apperrors.CodeInternalError, "Streaming is not supported")
+		return
+	}
+	if err := stream.Send("unread_count", map[string]int{"count": count}); err != nil {
+		return
+	}
+
+	// Поток закрывается до срока запроса; клиент переподключается и получает текущее значение
+	var closeAt <-chan time.Time
+	if deadline, ok := ctx.Deadline(); ok {
+		timer := time.NewTimer(time.Until(deadline) - unreadCountStreamMargin)
+		defer timer.Stop()
+		closeAt = timer.C
+	}
+
+	ping := time.NewTicker(unreadCountPingInterval)
+	defer ping.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-closeAt:
+			_ = stream.Retry(unreadCountRetry)
+			return
+		case count := <-updates:
+			if err := stream.Send("unread_count", map[string]int{"count": count}); err != nil {
+				return
+			}
+		case <-ping.C:
+			if err := stream.Ping(); err != nil {
+				return
+			}
+		}
+	}
+}
+
 // GetNotificationSettings возвращает настройки уведомлений пользователя
 func (h *NotificationHandler) GetNotificationSettings(w http.ResponseWriter, r *http.Request) {
 	// Получаем ID пользователя из контекста
 	userID, err := h.GetUserIDFromContext(r)
 	if err != nil {
-		h.RespondWithError(w, r, http.StatusUnauthorized, "Unauthorized", "unauthorized")
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
 		return
 	}
 
@@ -230,7 +378,7 @@ func (h *NotificationHandler) GetNotificationSettings(w http.ResponseWriter, r *
 		h.Logger.Error("Failed to get notification settings", err, map[string]interface{}{
 			"user_id": userID,
 		})
-		h.RespondWithError(w, r, http.StatusInternalServerError, "Failed to get notification settings", "settings_fetch_failed")
+		h.RespondWithError(w, r, apperrors.CodeSettingsFetchFailed, "Failed to get notification settings")
 		return
 	}
 
@@ -242,21 +390,21 @@ func (h *NotificationHandler) UpdateNotificationSettings(w http.ResponseWriter,
 	// Получаем ID пользователя из контекста
 	userID, err := h.GetUserIDFromContext(r)
 	if err != nil {
-		h.RespondWithError(w, r, http.StatusUnauthorized, "Unauthorized", "unauthorized")
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
 		return
 	}
 
 	var settings []*repository.NotificationSetting
 	if err := h.ParseJSON(r, &settings); err != nil {
 		h.Logger.Error("Failed to parse notification settings request", err)
-		h.RespondWithError(w, r, http.StatusBadRequest, "Invalid request format", "invalid_format")
+		h.RespondWithError(w, r, apperrors.CodeInvalidFormat, "Invalid request format")
 		return
 	}
 
 	// Проверка, что все настройки принадлежат текущему пользователю
 	for _, setting := range settings {
 		if setting.UserID != userID {
-			h.RespondWithError(w, r, http.StatusBadRequest, "All settings must belong to the current user", "invalid_user_id")
+			h.RespondWithError(w, r, apperrors.CodeInvalidUserID, "All settings must belong to the current user")
 			return
 		}
 	}
@@ -266,7 +414,7 @@ func (h *NotificationHandler) UpdateNotificationSettings(w http.ResponseWriter,
 		h.Logger.Error("Failed to update notification settings", err, map[string]interface{}{
 			"user_id": userID,
 		})
-		h.RespondWithError(w, r, http.StatusInternalServerError, "Failed to update notification settings", "settings_update_failed")
+		h.RespondWithError(w, r, apperrors.CodeSettingsUpdateFailed, "Failed to update notification settings")
 		return
 	}
 
@@ -0,0 +1,385 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/internal/service"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
+)
+
+// OKRHandler обрабатывает запросы, связанные с целями и ключевыми результатами
+type OKRHandler struct {
+	BaseHandler
+	okrService *service.OKRService
+}
+
+// NewOKRHandler создает новый экземпляр OKRHandler
+func NewOKRHandler(base BaseHandler, okrService *service.OKRService) *OKRHandler {
+	return &OKRHandler{
+		BaseHandler: base,
+		okrService:  okrService,
+	}
+}
+
+// ListObjectives возвращает цели с прогрессом; year и quarter фильтруют по периоду
+func (h *OKRHandler) ListObjectives(w http.ResponseWriter, r *http.Request) {
+	if _, err := h.GetUserIDFromContext(r); err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	year, quarter, ok := h.parsePeriod(w, r, 0, 0)
+	if !ok {
+		return
+	}
+
+	objectives, err := h.okrService.ListObjectives(r.Context(), year, quarter)
+	if err != nil {
+		h.handleOKRError(w, r, err, "")
+		return
+	}
+
+	h.RespondWithSuccess(w, r, objectives)
+}
+
+// CreateObjective создает цель на квартал
+func (h *OKRHandler) CreateObjective(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req domain.ObjectiveCreateRequest
+	if !h.parseOKRRequest(w, r, &req) {
+		return
+	}
+
+	objective, err := h.okrService.CreateObjective(r.Context(), req, userID)
+	if err != nil {
+		h.handleOKRError(w, r, err, "")
+		return
+	}
+
+	h.RespondWithSuccess(w, r, objective)
+}
+
+// GetObjective возвращает цель с ключевыми результатами
+func (h *OKRHandler) GetObjective(w http.ResponseWriter, r *http.Request) {
+	if _, err := h.GetUserIDFromContext(r); err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID цели из URL
+	id := h.GetURLParam(r, "id")
+	if id == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Objective ID is required")
+		return
+	}
+
+	objective, err := h.okrService.GetObjective(r.Context(), id)
+	if err != nil {
+		h.handleOKRError(w, r, err, id)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, objective)
+}
+
+// UpdateObjective обновляет цель
+func (h *OKRHandler) UpdateObjective(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID цели из URL
+	id := h.GetURLParam(r, "id")
+	if id == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Objective ID is required")
+		return
+	}
+
+	var req domain.ObjectiveUpdateRequest
+	if !h.parseOKRRequest(w, r, &req) {
+		return
+	}
+
+	objective, err := h.okrService.UpdateObjective(r.Context(), id, req, userID)
+	if err != nil {
+		h.handleOKRError(w, r, err, id)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, objective)
+}
+
+// DeleteObjective удаляет цель
+func (h *OKRHandler) DeleteObjective(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID цели из URL
+	id := h.GetURLParam(r, "id")
+	if id == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Objective ID is required")
+		return
+	}
+
+	if err := h.okrService.DeleteObjective(r.Context(), id, userID); err != nil {
+		h.handleOKRError(w, r, err, id)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, map[string]bool{"success": true})
+}
+
+// CreateKeyResult добавляет ключевой результат к цели
+func (h *OKRHandler) CreateKeyResult(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID цели из URL
+	objectiveID := h.GetURLParam(r, "id")
+	if objectiveID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Objective ID is required")
+		return
+	}
+
+	var req domain.KeyResultRequest
+	if !h.parseOKRRequest(w, r, &req) {
+		return
+	}
+
+	keyResult, err := h.okrService.AddKeyResult(r.Context(), objectiveID, req, userID)
+	if err != nil {
+		h.handleOKRError(w, r, err, objectiveID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, keyResult)
+}
+
+// UpdateKeyResult переименовывает ключевой результат
+func (h *OKRHandler) UpdateKeyResult(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID ключевого результата из URL
+	id := h.GetURLParam(r, "id")
+	if id == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Key result ID is required")
+		return
+	}
+
+	var req domain.KeyResultRequest
+	if !h.parseOKRRequest(w, r, &req) {
+		return
+	}
+
+	keyResult, err := h.okrService.UpdateKeyResult(r.Context(), id, req, userID)
+	if err != nil {
+		h.handleOKRError(w, r, err, id)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, keyResult)
+}
+
+// DeleteKeyResult удаляет ключевой результат
+func (h *OKRHandler) DeleteKeyResult(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID ключевого результата из URL
+	id := h.GetURLParam(r, "id")
+	if id == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Key result ID is required")
+		return
+	}
+
+	if err := h.okrService.DeleteKeyResult(r.Context(), id, userID); err != nil {
+		h.handleOKRError(w, r, err, id)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, map[string]bool{"success": true})
+}
+
+// LinkKeyResult связывает ключевой результат с проектом или эпиком
+func (h *OKRHandler) LinkKeyResult(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID ключевого результата из URL
+	id := h.GetURLParam(r, "id")
+	if id == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Key result ID is required")
+		return
+	}
+
+	var req domain.KeyResultLinkRequest
+	if !h.parseOKRRequest(w, r, &req) {
+		return
+	}
+
+	keyResult, err := h.okrService.LinkKeyResult(r.Context(), id, req, userID)
+	if err != nil {
+		h.handleOKRError(w, r, err, id)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, keyResult)
+}
+
+// UnlinkKeyResult удаляет связь ключевого результата с проектом или эпиком
+func (h *OKRHandler) UnlinkKeyResult(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID ключевого результата и связи из URL
+	id := h.GetURLParam(r, "id")
+	linkID := h.GetURLParam(r, "link_id")
+	if id == "" || linkID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Key result ID and link ID are required")
+		return
+	}
+
+	if err := h.okrService.UnlinkKeyResult(r.Context(), id, linkID, userID); err != nil {
+		h.handleOKRError(w, r, err, linkID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, map[string]bool{"success": true})
+}
+
+// GetQuarterReport возвращает квартальный отчет по целям; по умолчанию - за текущий квартал
+func (h *OKRHandler) GetQuarterReport(w http.ResponseWriter, r *http.Request) {
+	if _, err := h.GetUserIDFromContext(r); err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	currentYear, currentQuarter := service.CurrentQuarter(time.Now())
+	year, quarter, ok := h.parsePeriod(w, r, currentYear, currentQuarter)
+	if !ok {
+		return
+	}
+
+	report, err := h.okrService.GetQuarterReport(r.Context(), year, quarter)
+	if err != nil {
+		h.handleOKRError(w, r, err, "")
+		return
+	}
+
+	h.RespondWithSuccess(w, r, report)
+}
+
+// parsePeriod разбирает параметры year и quarter, подставляя значения по умолчанию
+func (h *OKRHandler) parsePeriod(w http.ResponseWriter, r *http.Request, defaultYear, defaultQuarter int) (int, int, bool) {
+	year, quarter := defaultYear, defaultQuarter
+
+	if value := r.URL.Query().Get("year"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 2000 || parsed > 2100 {
+			h.RespondWithError(w, r, apperrors.CodeInvalidPeriod, "Invalid year")
+			return 0, 0, false
+		}
+		year = parsed
+	}
+
+	if value := r.URL.Query().Get("quarter"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > 4 {
+			h.RespondWithError(w, r, apperrors.CodeInvalidPeriod, "Invalid quarter")
+			return 0, 0, false
+		}
+		quarter = parsed
+	}
+
+	return year, quarter, true
+}
+
+// parseOKRRequest разбирает и валидирует тело запроса
+func (h *OKRHandler) parseOKRRequest(w http.ResponseWriter, r *http.Request, req interface{}) bool {
+	if err := h.ParseJSON(r, req); err != nil {
+		h.Logger.Error("Failed to parse OKR request", err)
+		h.RespondWithError(w, r, apperrors.CodeInvalidFormat, "Invalid request format")
+		return false
+	}
+
+	// Валидация запроса
+	if validationErrors, err := h.ValidateRequest(r, req); err != nil {
+		h.Logger.Error("Request validation error", err)
+		h.RespondWithError(w, r, apperrors.CodeInternalError, "Validation failed")
+		return false
+	} else if len(validationErrors) > 0 {
+		h.RespondWithValidationErrors(w, r, validationErrors)
+		return false
+	}
+
+	return true
+}
+
+// handleOKRError преобразует ошибки целей в HTTP-ответы
+func (h *OKRHandler) handleOKRError(w http.ResponseWriter, r *http.Request, err error, id string) {
+	switch {
+	case errors.Is(err, service.ErrObjectiveNotFound):
+		h.RespondWithError(w, r, apperrors.CodeObjectiveNotFound, "Objective not found")
+	case errors.Is(err, service.ErrKeyResultNotFound):
+		h.RespondWithError(w, r, apperrors.CodeKeyResultNotFound, "Key result not found")
+	case errors.Is(err, service.ErrKeyResultLinkNotFound):
+		h.RespondWithError(w, r, apperrors.CodeLinkNotFound, "Key result link not found")
+	case errors.Is(err, service.ErrProjectNotFound):
+		h.RespondWithError(w, r, apperrors.CodeProjectNotFound, "Project not found")
+	case errors.Is(err, service.ErrEpicNotFound):
+		h.RespondWithError(w, r, apperrors.CodeEpicNotFound, "Epic not found")
+	case errors.Is(err, service.ErrKeyResultLinkExists):
+		h.RespondWithError(w, r, apperrors.CodeLinkExists, "Key result is already linked to this project or epic")
+	case errors.Is(err, service.ErrKeyResultLinkInvalid):
+		h.RespondWithError(w, r, apperrors.CodeInvalidLink, "Either project_id or epic_id is required")
+	case errors.Is(err, service.ErrObjectiveOwnerInvalid):
+		h.RespondWithError(w, r, apperrors.CodeInvalidOwner, "Objective owner not found")
+	case errors.Is(err, service.ErrOKRInvalidPeriod):
+		h.RespondWithError(w, r, apperrors.CodeInvalidPeriod, "Invalid year or quarter")
+	case errors.Is(err, service.ErrInsufficientRights):
+		h.RespondWithError(w, r, apperrors.CodeInsufficientRights, "Insufficient rights to manage this objective")
+	default:
+		h.Logger.Error("Failed to process OKR request", err, map[string]interface{}{
+			"id": id,
+		})
+		h.RespondWithError(w, r, apperrors.CodeOKRFailed, "Failed to process OKR request")
+	}
+}
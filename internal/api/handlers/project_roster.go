@@ -0,0 +1,238 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/internal/service"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
+)
+
+// ProjectRosterHandler обрабатывает запросы массового добавления участников проекта и приглашений
+type ProjectRosterHandler struct {
+	BaseHandler
+	rosterService *service.ProjectRosterService
+}
+
+// NewProjectRosterHandler создает новый экземпляр ProjectRosterHandler
+func NewProjectRosterHandler(base BaseHandler, rosterService *service.ProjectRosterService) *ProjectRosterHandler {
+	return &ProjectRosterHandler{
+		BaseHandler:   base,
+		rosterService: rosterService,
+	}
+}
+
+// BulkAddMembers добавляет в проект участников по списку email с ролями.
+// На адреса без учетной записи отправляются приглашения
+func (h *ProjectRosterHandler) BulkAddMembers(w http.ResponseWriter, r *http.Request) {
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	projectID := h.GetURLParam(r, "id")
+	if projectID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID is required")
+		return
+	}
+
+	var req domain.BulkAddMembersRequest
+	if err := h.ParseJSON(r, &req); err != nil {
+		h.RespondWithError(w, r, apperrors.CodeInvalidFormat, "Invalid request format")
+		return
+	}
+	if !h.ValidateBatchRequest(w, r, req) {
+		return
+	}
+
+	h.addRoster(w, r, projectID, req.Members, userID)
+}
+
+// ImportMembers добавляет в проект участников из CSV-файла. Первая строка файла -
+// заголовок с колонками email и role; без колонки role участники получают роль member.
+// Файл передается телом запроса (text/csv) или полем file формы multipart/form-data
+func (h *ProjectRosterHandler) ImportMembers(w http.ResponseWriter, r *http.Request) {
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	projectID := h.GetURLParam(r, "id")
+	if projectID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID is required")
+		return
+	}
+
+	body := io.Reader(r.Body)
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType == "multipart/form-data" {
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			h.RespondWithError(w, r, apperrors.CodeInvalidCSV, "CSV file is required in the file field")
+			return
+		}
+		defer file.Close()
+		body = file
+	}
+
+	entries, err := parseRosterCSV(body)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeInvalidCSV, err.Error())
+		return
+	}
+
+	h.addRoster(w, r, projectID, entries, userID)
+}
+
+// AcceptInvitation добавляет текущего пользователя в проект по ссылке из приглашения
+func (h *ProjectRosterHandler) AcceptInvitation(w http.ResponseWriter, r *http.Request) {
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	token := h.GetURLParam(r, "token")
+	if token == "" {
+		h.RespondWithError(w, r, apperrors.CodeInvitationNotFound, "Invitation not found")
+		return
+	}
+
+	member, err := h.rosterService.AcceptInvitation(r.Context(), token, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvitationNotFound):
+			h.RespondWithError(w, r, apperrors.CodeInvitationNotFound, "Invitation not found")
+		case errors.Is(err, service.ErrInvitationExpired):
+			h.RespondWithError(w, r, apperrors.CodeInvitationExpired, "Invitation has expired")
+		case errors.Is(err, service.ErrInvitationEmailMismatch):
+			h.RespondWithError(w, r, apperrors.CodeInvitationEmailMismatch, "Invitation was sent to another email address")
+		case errors.Is(err, service.ErrMemberAlreadyExists):
+			h.RespondWithError(w, r, apperrors.CodeMemberExists, "User is already a member of the project")
+		case errors.Is(err, service.ErrProjectNotFound):
+			h.RespondWithError(w, r, apperrors.CodeProjectNotFound, "Project not found")
+		default:
+			h.Logger.Error("Failed to accept project invitation", err, map[string]interface{}{
+				"user_id": userID,
+			})
+			h.RespondWithError(w, r, apperrors.CodeInvitationFailed, "Failed to accept invitation")
+		}
+		return
+	}
+
+	h.RespondWithSuccess(w, r, member)
+}
+
+// addRoster проверяет участников из списка, добавляет корректных и отправляет
+// результат по каждому: ошибки проверки не мешают добавлению остальных
+func (h *ProjectRosterHandler) addRoster(w http.ResponseWriter, r *http.Request, projectID string, entries []domain.RosterEntry, userID string) {
+	validationErrs := make([]error, len(entries))
+	valid := make([]domain.RosterEntry, 0, len(entries))
+	for i, entry := range entries {
+		if err := h.ValidateBatchItem(r, entry); err != nil {
+			validationErrs[i] = err
+			continue
+		}
+		valid = append(valid, entry)
+	}
+
+	outcomes, err := h.rosterService.AddMembers(r.Context(), projectID, valid, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrProjectNotFound):
+			h.RespondWithError(w, r, apperrors.CodeProjectNotFound, "Project not found")
+		default:
+			h.Logger.Error("Failed to add project members", err, map[string]interface{}{
+				"project_id": projectID,
+			})
+			h.RespondWithError(w, r, apperrors.CodeAddMemberFailed, "Failed to add members")
+		}
+		return
+	}
+
+	result := domain.NewBatchResult(len(entries))
+	next := 0
+	for i, entry := range entries {
+		if validationErrs[i] != nil {
+			h.AddBatchItem(result, i, entry.Email, nil, validationErrs[i])
+			continue
+		}
+		outcome := outcomes[next]
+		next++
+		if outcome.Err != nil {
+			h.AddBatchItem(result, i, entry.Email, nil, outcome.Err)
+			continue
+		}
+		h.AddBatchItem(result, i, entry.Email, outcome.Result, nil)
+	}
+
+	h.RespondWithBatch(w, r, result)
+}
+
+// parseRosterCSV разбирает CSV-файл со списком участников. Колонки ищутся по заголовку
+// без учета регистра, лишние колонки игнорируются
+func parseRosterCSV(body io.Reader) ([]domain.RosterEntry, error) {
+	reader := csv.NewReader(body)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, errors.New("CSV roster is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV roster: %v", err)
+	}
+
+	emailColumn, roleColumn := -1, -1
+	for i, name := range header {
+		// Excel сохраняет CSV в UTF-8 с BOM в начале файла
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		switch name {
+		case "email":
+			emailColumn = i
+		case "role":
+			roleColumn = i
+		}
+	}
+	if emailColumn < 0 {
+		return nil, errors.New("CSV roster header must contain an email column")
+	}
+
+	var entries []domain.RosterEntry
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV roster: %v", err)
+		}
+		if len(entries) == domain.MaxRosterSize {
+			return nil, fmt.Errorf("CSV roster must not contain more than %d rows", domain.MaxRosterSize)
+		}
+
+		entry := domain.RosterEntry{Role: domain.ProjectRoleMember}
+		if emailColumn < len(record) {
+			entry.Email = strings.TrimSpace(record[emailColumn])
+		}
+		if roleColumn >= 0 && roleColumn < len(record) {
+			if role := strings.ToLower(strings.TrimSpace(record[roleColumn])); role != "" {
+				entry.Role = domain.ProjectRole(role)
+			}
+		}
+		entries = append(entries, entry)
+	}
+
+	if len(entries) == 0 {
+		return nil, errors.New("CSV roster contains no rows")
+	}
+	return entries, nil
+}
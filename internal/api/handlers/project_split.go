@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/internal/service"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
+)
+
+// ProjectSplitHandler обрабатывает запросы на разделение проекта
+type ProjectSplitHandler struct {
+	BaseHandler
+	projectSplitService *service.ProjectSplitService
+}
+
+// NewProjectSplitHandler создает новый экземпляр ProjectSplitHandler
+func NewProjectSplitHandler(base BaseHandler, projectSplitService *service.ProjectSplitService) *ProjectSplitHandler {
+	return &ProjectSplitHandler{
+		BaseHandler:         base,
+		projectSplitService: projectSplitService,
+	}
+}
+
+// SplitProject переносит задачи проекта по тегу или эпику в новый проект.
+// С dry_run возвращает предварительный просмотр без изменений
+func (h *ProjectSplitHandler) SplitProject(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID проекта из URL
+	projectID := h.GetURLParam(r, "id")
+	if projectID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID is required")
+		return
+	}
+
+	// Парсим тело запроса
+	var req domain.ProjectSplitRequest
+	if err := h.ParseJSON(r, &req); err != nil {
+		h.Logger.Error("Failed to parse project split request", err)
+		h.RespondWithError(w, r, apperrors.CodeInvalidFormat, "Invalid request format")
+		return
+	}
+
+	// Валидация запроса
+	if validationErrors, err := h.ValidateRequest(r, req); err != nil {
+		h.Logger.Error("Request validation error", err)
+		h.RespondWithError(w, r, apperrors.CodeInternalError, "Validation failed")
+		return
+	} else if len(validationErrors) > 0 {
+		h.RespondWithValidationErrors(w, r, validationErrors)
+		return
+	}
+
+	result, err := h.projectSplitService.Split(r.Context(), projectID, req, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrProjectNotFound):
+			h.RespondWithError(w, r, apperrors.CodeProjectNotFound, "Project not found")
+		case errors.Is(err, service.ErrProjectArchived):
+			h.RespondWithError(w, r, apperrors.CodeProjectArchived, "Project is archived and read-only")
+		case errors.Is(err, service.ErrEpicNotFound):
+			h.RespondWithError(w, r, apperrors.CodeEpicNotFound, "Epic not found")
+		case errors.Is(err, service.ErrEpicProjectMismatch):
+			h.RespondWithError(w, r, apperrors.CodeEpicProjectMismatch, "Epic belongs to another project")
+		case errors.Is(err, service.ErrInvalidProjectSplit):
+			h.RespondWithError(w, r, apperrors.CodeInvalidSplit, "Specify exactly one of tag or epic_id")
+		case errors.Is(err, service.ErrProjectSplitEmpty):
+			h.RespondWithError(w, r, apperrors.CodeSplitEmpty, "No tasks match the split selection")
+		case errors.Is(err, service.ErrProjectSplitTooLarge):
+			h.RespondWithError(w, r, apperrors.CodeSplitTooLarge, "Too many tasks to split at once")
+		default:
+			h.Logger.Error("Failed to split project", err, map[string]interface{}{
+				"project_id": projectID,
+			})
+			h.RespondWithError(w, r, apperrors.CodeSplitFailed, "Failed to split project")
+		}
+		return
+	}
+
+	h.RespondWithSuccess(w, r, result)
+}
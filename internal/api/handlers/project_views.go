@@ -0,0 +1,195 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/internal/service"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
+)
+
+// ProjectViewHandler обрабатывает запросы, связанные с сохраненными представлениями проектов
+type ProjectViewHandler struct {
+	BaseHandler
+	viewService *service.ProjectViewService
+}
+
+// NewProjectViewHandler создает новый экземпляр ProjectViewHandler
+func NewProjectViewHandler(base BaseHandler, viewService *service.ProjectViewService) *ProjectViewHandler {
+	return &ProjectViewHandler{
+		BaseHandler: base,
+		viewService: viewService,
+	}
+}
+
+// ListViews возвращает представления проекта
+func (h *ProjectViewHandler) ListViews(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID проекта из URL
+	projectID := h.GetURLParam(r, "id")
+	if projectID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID is required")
+		return
+	}
+
+	views, err := h.viewService.List(r.Context(), projectID)
+	if err != nil {
+		h.handleViewError(w, r, err, projectID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, views)
+}
+
+// GetDefaultView возвращает представление, которое нужно открыть пользователю по его роли в проекте
+func (h *ProjectViewHandler) GetDefaultView(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID проекта из URL
+	projectID := h.GetURLParam(r, "id")
+	if projectID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID is required")
+		return
+	}
+
+	view, err := h.viewService.GetDefault(r.Context(), projectID, userID)
+	if err != nil {
+		h.handleViewError(w, r, err, projectID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, view)
+}
+
+// CreateView создает представление проекта
+func (h *ProjectViewHandler) CreateView(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID проекта из URL
+	projectID := h.GetURLParam(r, "id")
+	if projectID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID is required")
+		return
+	}
+
+	var req domain.ProjectViewRequest
+	if !h.parseViewRequest(w, r, &req) {
+		return
+	}
+
+	view, err := h.viewService.Create(r.Context(), projectID, req, userID)
+	if err != nil {
+		h.handleViewError(w, r, err, projectID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, view)
+}
+
+// GetView возвращает представление проекта
+func (h *ProjectViewHandler) GetView(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID проекта и представления из URL
+	projectID := h.GetURLParam(r, "id")
+	id := h.GetURLParam(r, "view_id")
+	if projectID == "" || id == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID and view ID are required")
+		return
+	}
+
+	view, err := h.viewService.GetByID(r.Context(), projectID, id)
+	if err != nil {
+		h.handleViewError(w, r, err, id)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, view)
+}
+
+// UpdateView заменяет настройки представления проекта
+func (h *ProjectViewHandler) UpdateView(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID проекта и представления из URL
+	projectID := h.GetURLParam(r, "id")
+	id := h.GetURLParam(r, "view_id")
+	if projectID == "" || id == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID and view ID are required")
+		return
+	}
+
+	var req domain.ProjectViewRequest
+	if !h.parseViewRequest(w, r, &req) {
+		return
+	}
+
+	view, err := h.viewService.Update(r.Context(), projectID, id, req)
+	if err != nil {
+		h.handleViewError(w, r, err, id)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, view)
+}
+
+// DeleteView удаляет представление проекта
+func (h *ProjectViewHandler) DeleteView(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID проекта и представления из URL
+	projectID := h.GetURLParam(r, "id")
+	id := h.GetURLParam(r, "view_id")
+	if projectID == "" || id == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID and view ID are required")
+		return
+	}
+
+	if err := h.viewService.Delete(r.Context(), projectID, id); err != nil {
+		h.handleViewError(w, r, err, id)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, map[string]bool{"success": true})
+}
+
+// parseViewRequest разбирает и проверяет запрос на создание или замену представления.
+// Возвращает false, если ответ с ошибкой уже отправлен
+func (h *ProjectViewHandler) parseViewRequest(w http.ResponseWriter, r *http.Request, req *domain.ProjectViewRequest) bool {
+	if err := h.ParseJSON(r, req); err != nil {
+		h.Logger.Error("Failed to parse project view request", err)
+		h.RespondWithError(w, r, apperrors.CodeInvalidFormat, "Invalid request format")
+		return false
+	}
+
+	// Валидация запроса
+	if validationErrors, err := h.ValidateRequest(r, req); err != nil {
+		h.Logger.Error("Request validation error", err)
+		h.RespondWithError(w, r, apperrors.CodeInternalError, "Validation failed")
+		return false
+	} else if len(validationErrors) > 0 {
+		h.RespondWithValidationErrors(w, r, validationErrors)
+		return false
+	}
+
+	return true
+}
+
+// handleViewError преобразует ошибки представлений проектов в HTTP-ответы
+func (h *ProjectViewHandler) handleViewError(w http.ResponseWriter, r *http.Request, err error, id string) {
+	switch {
+	case errors.Is(err, service.ErrProjectViewNotFound):
+		h.RespondWithError(w, r, apperrors.CodeProjectViewNotFound, "Project view not found")
+	case errors.Is(err, service.ErrProjectViewExists):
+		h.RespondWithError(w, r, apperrors.CodeProjectViewExists, "Project view with this name already exists")
+	default:
+		h.Logger.Error("Failed to process project view", err, map[string]interface{}{
+			"id": id,
+		})
+		h.RespondWithError(w, r, apperrors.CodeProjectViewFailed, "Failed to process project view")
+	}
+}
@@ -0,0 +1,252 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/internal/service"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
+)
+
+// maxWebhookBodySize ограничивает размер тела входящего запроса вебхука
+const maxWebhookBodySize = 1 << 20
+
+// ProjectWebhookHandler обрабатывает запросы, связанные с входящими вебхуками проектов
+type ProjectWebhookHandler struct {
+	BaseHandler
+	webhookService *service.ProjectWebhookService
+}
+
+// NewProjectWebhookHandler создает новый экземпляр ProjectWebhookHandler
+func NewProjectWebhookHandler(base BaseHandler, webhookService *service.ProjectWebhookService) *ProjectWebhookHandler {
+	return &ProjectWebhookHandler{
+		BaseHandler:    base,
+		webhookService: webhookService,
+	}
+}
+
+// ListWebhooks возвращает вебхуки проекта
+func (h *ProjectWebhookHandler) ListWebhooks(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID проекта из URL
+	projectID := h.GetURLParam(r, "id")
+	if projectID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID is required")
+		return
+	}
+
+	webhooks, err := h.webhookService.List(r.Context(), projectID)
+	if err != nil {
+		h.handleWebhookError(w, r, err, projectID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, webhooks)
+}
+
+// CreateWebhook создает вебхук проекта
+func (h *ProjectWebhookHandler) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID проекта из URL
+	projectID := h.GetURLParam(r, "id")
+	if projectID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID is required")
+		return
+	}
+
+	var req domain.ProjectWebhookRequest
+	if !h.parseWebhookRequest(w, r, &req) {
+		return
+	}
+
+	webhook, err := h.webhookService.Create(r.Context(), projectID, req, userID)
+	if err != nil {
+		h.handleWebhookError(w, r, err, projectID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, webhook)
+}
+
+// GetWebhook возвращает вебхук проекта
+func (h *ProjectWebhookHandler) GetWebhook(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID проекта и вебхука из URL
+	projectID := h.GetURLParam(r, "id")
+	id := h.GetURLParam(r, "webhook_id")
+	if projectID == "" || id == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID and webhook ID are required")
+		return
+	}
+
+	webhook, err := h.webhookService.GetByID(r.Context(), projectID, id)
+	if err != nil {
+		h.handleWebhookError(w, r, err, id)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, webhook)
+}
+
+// UpdateWebhook заменяет настройки вебхука проекта
+func (h *ProjectWebhookHandler) UpdateWebhook(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID проекта и вебхука из URL
+	projectID := h.GetURLParam(r, "id")
+	id := h.GetURLParam(r, "webhook_id")
+	if projectID == "" || id == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID and webhook ID are required")
+		return
+	}
+
+	var req domain.ProjectWebhookRequest
+	if !h.parseWebhookRequest(w, r, &req) {
+		return
+	}
+
+	webhook, err := h.webhookService.Update(r.Context(), projectID, id, req)
+	if err != nil {
+		h.handleWebhookError(w, r, err, id)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, webhook)
+}
+
+// RotateWebhookSecret выдает вебхуку новый ключ подписи
+func (h *ProjectWebhookHandler) RotateWebhookSecret(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID проекта и вебхука из URL
+	projectID := h.GetURLParam(r, "id")
+	id := h.GetURLParam(r, "webhook_id")
+	if projectID == "" || id == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID and webhook ID are required")
+		return
+	}
+
+	webhook, err := h.webhookService.RotateSecret(r.Context(), projectID, id, userID)
+	if err != nil {
+		h.handleWebhookError(w, r, err, id)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, webhook)
+}
+
+// DeleteWebhook удаляет вебхук проекта
+func (h *ProjectWebhookHandler) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID проекта и вебхука из URL
+	projectID := h.GetURLParam(r, "id")
+	id := h.GetURLParam(r, "webhook_id")
+	if projectID == "" || id == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID and webhook ID are required")
+		return
+	}
+
+	if err := h.webhookService.Delete(r.Context(), projectID, id); err != nil {
+		h.handleWebhookError(w, r, err, id)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, map[string]bool{"success": true})
+}
+
+// ReceiveWebhook принимает JSON-запрос на URL вебхука и создает задачу.
+// Маршрут публичный: запрос подписывается заголовком X-Webhook-Signature или параметром signature в URL
+func (h *ProjectWebhookHandler) ReceiveWebhook(w http.ResponseWriter, r *http.Request) {
+	id := h.GetURLParam(r, "id")
+	if id == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Webhook ID is required")
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBodySize))
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodePayloadTooLarge, "Request body is too large")
+		return
+	}
+
+	task, err := h.webhookService.Receive(
+		r.Context(),
+		id,
+		body,
+		r.Header.Get("X-Webhook-Signature"),
+		r.URL.Query().Get("signature"),
+	)
+	if err != nil {
+		h.handleWebhookError(w, r, err, id)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, task)
+}
+
+// parseWebhookRequest разбирает и проверяет запрос на создание или замену вебхука.
+// Возвращает false, если ответ с ошибкой уже отправлен
+func (h *ProjectWebhookHandler) parseWebhookRequest(w http.ResponseWriter, r *http.Request, req *domain.ProjectWebhookRequest) bool {
+	if err := h.ParseJSON(r, req); err != nil {
+		h.Logger.Error("Failed to parse webhook request", err)
+		h.RespondWithError(w, r, apperrors.CodeInvalidFormat, "Invalid request format")
+		return false
+	}
+
+	// Валидация запроса
+	if validationErrors, err := h.ValidateRequest(r, req); err != nil {
+		h.Logger.Error("Request validation error", err)
+		h.RespondWithError(w, r, apperrors.CodeInternalError, "Validation failed")
+		return false
+	} else if len(validationErrors) > 0 {
+		h.RespondWithValidationErrors(w, r, validationErrors)
+		return false
+	}
+
+	return true
+}
+
+// handleWebhookError преобразует ошибки вебхуков в HTTP-ответы
+func (h *ProjectWebhookHandler) handleWebhookError(w http.ResponseWriter, r *http.Request, err error, id string) {
+	var validationErr *service.TaskValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		validationErrors := make([]ValidationError, 0, len(validationErr.Violations))
+		for _, violation := range validationErr.Violations {
+			validationErrors = append(validationErrors, ValidationError{
+				Field:   violation.Field,
+				Message: violation.Message,
+			})
+		}
+		h.RespondWithValidationErrors(w, r, validationErrors)
+	case errors.Is(err, service.ErrProjectNotFound):
+		h.RespondWithError(w, r, apperrors.CodeProjectNotFound, "Project not found")
+	case errors.Is(err, service.ErrWebhookNotFound):
+		h.RespondWithError(w, r, apperrors.CodeWebhookNotFound, "Webhook not found")
+	case errors.Is(err, service.ErrWebhookExists):
+		h.RespondWithError(w, r, apperrors.CodeWebhookExists, "Webhook with this name already exists")
+	case errors.Is(err, service.ErrWebhookSignatureInvalid):
+		h.RespondWithError(w, r, apperrors.CodeInvalidSignature, "Invalid webhook signature")
+	case errors.Is(err, service.ErrWebhookDisabled):
+		h.RespondWithError(w, r, apperrors.CodeWebhookDisabled, "Webhook is disabled")
+	case errors.Is(err, service.ErrWebhookPayloadInvalid):
+		h.RespondWithError(w, r, apperrors.CodeInvalidFormat, "Webhook payload must be valid JSON")
+	case errors.Is(err, service.ErrInvalidAssignee):
+		h.RespondWithError(w, r, apperrors.CodeInvalidAssignee, "Assignee must be a member of the project")
+	case errors.Is(err, service.ErrProjectArchived):
+		h.RespondWithError(w, r, apperrors.CodeProjectArchived, "Project is archived and read-only")
+	default:
+		h.Logger.Error("Failed to process webhook", err, map[string]interface{}{
+			"id": id,
+		})
+		h.RespondWithError(w, r, apperrors.CodeWebhookFailed, "Failed to process webhook")
+	}
+}
@@ -1,12 +1,16 @@
 package handlers
 
 import (
+	"context"
 	"errors"
 	"net/http"
+	"strconv"
 
+	"github.com/nurlyy/task_manager/internal/api/query"
 	"github.com/nurlyy/task_manager/internal/domain"
 	"github.com/nurlyy/task_manager/internal/repository"
 	"github.com/nurlyy/task_manager/internal/service"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
 )
 
 // ProjectHandler обрабатывает запросы, связанные с проектами
@@ -28,21 +32,21 @@ func (h *ProjectHandler) CreateProject(w http.ResponseWriter, r *http.Request) {
 	// Получаем ID пользователя из контекста
 	userID, err := h.GetUserIDFromContext(r)
 	if err != nil {
-		h.RespondWithError(w, r, http.StatusUnauthorized, "Unauthorized", "unauthorized")
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
 		return
 	}
 
 	var req domain.ProjectCreateRequest
 	if err := h.ParseJSON(r, &req); err != nil {
 		h.Logger.Error("Failed to parse create project request", err)
-		h.RespondWithError(w, r, http.StatusBadRequest, "Invalid request format", "invalid_format")
+		h.RespondWithError(w, r, apperrors.CodeInvalidFormat, "Invalid request format")
 		return
 	}
 
 	// Валидация запроса
-	if validationErrors, err := h.ValidateRequest(req); err != nil {
+	if validationErrors, err := h.ValidateRequest(r, req); err != nil {
 		h.Logger.Error("Request validation error", err)
-		h.RespondWithError(w, r, http.StatusInternalServerError, "Validation failed", "validation_error")
+		h.RespondWithError(w, r, apperrors.CodeInternalError, "Validation failed")
 		return
 	} else if len(validationErrors) > 0 {
 		h.RespondWithValidationErrors(w, r, validationErrors)
@@ -53,7 +57,7 @@ func (h *ProjectHandler) CreateProject(w http.ResponseWriter, r *http.Request) {
 	project, err := h.projectService.Create(r.Context(), req, userID)
 	if err != nil {
 		h.Logger.Error("Failed to create project", err)
-		h.RespondWithError(w, r, http.StatusInternalServerError, "Failed to create project", "creation_failed")
+		h.RespondWithError(w, r, apperrors.CodeCreationFailed, "Failed to create project")
 		return
 	}
 
@@ -65,70 +69,73 @@ func (h *ProjectHandler) DeleteProject(w http.ResponseWriter, r *http.Request) {
 	// Получаем ID пользователя из контекста
 	userID, err := h.GetUserIDFromContext(r)
 	if err != nil {
-		h.RespondWithError(w, r, http.StatusUnauthorized, "Unauthorized", "unauthorized")
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
 		return
 	}
 
 	// Получаем ID проекта из URL
 	projectID := h.GetURLParam(r, "id")
 	if projectID == "" {
-		h.RespondWithError(w, r, http.StatusBadRequest, "Project ID is required", "missing_id")
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID is required")
 		return
 	}
 
 	// Удаляем проект
 	if err := h.projectService.Delete(r.Context(), projectID, userID); err != nil {
 		if errors.Is(err, service.ErrProjectNotFound) {
-			h.RespondWithError(w, r, http.StatusNotFound, "Project not found", "project_not_found")
+			h.RespondWithError(w, r, apperrors.CodeProjectNotFound, "Project not found")
 			return
 		}
 		if errors.Is(err, service.ErrInsufficientRights) {
-			h.RespondWithError(w, r, http.StatusForbidden, "Only project owner can delete project", "insufficient_rights")
+			h.RespondWithError(w, r, apperrors.CodeInsufficientRights, "Only project owner can delete project")
 			return
 		}
 		h.Logger.Error("Failed to delete project", err, map[string]interface{}{
 			"id": projectID,
 		})
-		h.RespondWithError(w, r, http.StatusInternalServerError, "Failed to delete project", "delete_failed")
+		h.RespondWithError(w, r, apperrors.CodeDeleteFailed, "Failed to delete project")
 		return
 	}
 
 	h.RespondWithSuccess(w, r, map[string]bool{"success": true})
 }
 
+// Допустимые значения параметров списка проектов
+var (
+	projectStatuses = []domain.ProjectStatus{
+		domain.ProjectStatusActive, domain.ProjectStatusOnHold, domain.ProjectStatusCompleted, domain.ProjectStatusArchived,
+	}
+	projectSortFields = []string{"id", "name", "status", "created_by", "start_date", "end_date", "created_at", "updated_at"}
+)
+
 // ListProjects возвращает список проектов пользователя
 func (h *ProjectHandler) ListProjects(w http.ResponseWriter, r *http.Request) {
 	// Получаем ID пользователя из контекста
 	userID, err := h.GetUserIDFromContext(r)
 	if err != nil {
-		h.RespondWithError(w, r, http.StatusUnauthorized, "Unauthorized", "unauthorized")
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
 		return
 	}
 
-	// Параметры пагинации
-	page, pageSize := h.GetPaginationParams(r)
-
-	// Создаем фильтр
+	// Создаем фильтр и параметры пагинации
+	q := query.New(r)
 	filter := repository.ProjectFilter{
-		MemberID: &userID,
-	}
-
-	// Фильтр по статусу
-	if status := r.URL.Query().Get("status"); status != "" {
-		projectStatus := domain.ProjectStatus(status)
-		filter.Status = &projectStatus
+		MemberID:   &userID,
+		Status:     query.Enum(q, "status", projectStatuses...),
+		SearchText: q.String("search"),
 	}
+	filter.OrderBy, filter.OrderDir = q.Sort(projectSortFields...)
 
-	// Фильтр по поисковому тексту
-	if search := r.URL.Query().Get("search"); search != "" {
-		filter.SearchText = &search
+	page, ok := h.ParseQuery(w, r, q)
+	if !ok {
+		return
 	}
 
 	// Получаем список проектов
-	result, err := h.projectService.List(r.Context(), filter, userID, page, pageSize)
+	result, err := h.projectService.List(r.Context(), filter, userID, page)
 	if err != nil {
 		h.Logger.Error("Failed to list projects", err)
-		h.RespondWithError(w, r, http.StatusInternalServerError, "Failed to get projects", "projects_fetch_failed")
+		h.RespondWithError(w, r, apperrors.CodeProjectsFetchFailed, "Failed to get projects")
 		return
 	}
 
@@ -140,28 +147,28 @@ func (h *ProjectHandler) AddProjectMember(w http.ResponseWriter, r *http.Request
 	// Получаем ID пользователя из контекста
 	userID, err := h.GetUserIDFromContext(r)
 	if err != nil {
-		h.RespondWithError(w, r, http.StatusUnauthorized, "Unauthorized", "unauthorized")
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
 		return
 	}
 
 	// Получаем ID проекта из URL
 	projectID := h.GetURLParam(r, "id")
 	if projectID == "" {
-		h.RespondWithError(w, r, http.StatusBadRequest, "Project ID is required", "missing_id")
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID is required")
 		return
 	}
 
 	var req domain.AddMemberRequest
 	if err := h.ParseJSON(r, &req); err != nil {
 		h.Logger.Error("Failed to parse add member request", err)
-		h.RespondWithError(w, r, http.StatusBadRequest, "Invalid request format", "invalid_format")
+		h.RespondWithError(w, r, apperrors.CodeInvalidFormat, "Invalid request format")
 		return
 	}
 
 	// Валидация запроса
-	if validationErrors, err := h.ValidateRequest(req); err != nil {
+	if validationErrors, err := h.ValidateRequest(r, req); err != nil {
 		h.Logger.Error("Request validation error", err)
-		h.RespondWithError(w, r, http.StatusInternalServerError, "Validation failed", "validation_error")
+		h.RespondWithError(w, r, apperrors.CodeInternalError, "Validation failed")
 		return
 	} else if len(validationErrors) > 0 {
 		h.RespondWithValidationErrors(w, r, validationErrors)
@@ -172,25 +179,21 @@ func (h *ProjectHandler) AddProjectMember(w http.ResponseWriter, r *http.Request
 	member, err := h.projectService.AddMember(r.Context(), projectID, req, userID)
 	if err != nil {
 		if errors.Is(err, service.ErrProjectNotFound) {
-			h.RespondWithError(w, r, http.StatusNotFound, "Project not found", "project_not_found")
+			h.RespondWithError(w, r, apperrors.CodeProjectNotFound, "Project not found")
 			return
 		}
 		if errors.Is(err, service.ErrUserNotFound) {
-			h.RespondWithError(w, r, http.StatusNotFound, "User not found", "user_not_found")
-			return
-		}
-		if errors.Is(err, service.ErrInsufficientRights) {
-			h.RespondWithError(w, r, http.StatusForbidden, "Insufficient rights to add members", "insufficient_rights")
+			h.RespondWithError(w, r, apperrors.CodeUserNotFound, "User not found")
 			return
 		}
 		if errors.Is(err, service.ErrMemberAlreadyExists) {
-			h.RespondWithError(w, r, http.StatusConflict, "User is already a member of the project", "member_exists")
+			h.RespondWithError(w, r, apperrors.CodeMemberExists, "User is already a member of the project")
 			return
 		}
 		h.Logger.Error("Failed to add member to project", err, map[string]interface{}{
 			"project_id": projectID,
 		})
-		h.RespondWithError(w, r, http.StatusInternalServerError, "Failed to add member", "add_member_failed")
+		h.RespondWithError(w, r, apperrors.CodeAddMemberFailed, "Failed to add member")
 		return
 	}
 
@@ -202,35 +205,35 @@ func (h *ProjectHandler) UpdateProjectMember(w http.ResponseWriter, r *http.Requ
 	// Получаем ID пользователя из контекста
 	userID, err := h.GetUserIDFromContext(r)
 	if err != nil {
-		h.RespondWithError(w, r, http.StatusUnauthorized, "Unauthorized", "unauthorized")
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
 		return
 	}
 
 	// Получаем ID проекта из URL
 	projectID := h.GetURLParam(r, "id")
 	if projectID == "" {
-		h.RespondWithError(w, r, http.StatusBadRequest, "Project ID is required", "missing_id")
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID is required")
 		return
 	}
 
 	// Получаем ID участника из URL
 	memberID := h.GetURLParam(r, "member_id")
 	if memberID == "" {
-		h.RespondWithError(w, r, http.StatusBadRequest, "Member ID is required", "missing_member_id")
+		h.RespondWithError(w, r, apperrors.CodeMissingMemberID, "Member ID is required")
 		return
 	}
 
 	var req domain.UpdateMemberRequest
 	if err := h.ParseJSON(r, &req); err != nil {
 		h.Logger.Error("Failed to parse update member request", err)
-		h.RespondWithError(w, r, http.StatusBadRequest, "Invalid request format", "invalid_format")
+		h.RespondWithError(w, r, apperrors.CodeInvalidFormat, "Invalid request format")
 		return
 	}
 
 	// Валидация запроса
-	if validationErrors, err := h.ValidateRequest(req); err != nil {
+	if validationErrors, err := h.ValidateRequest(r, req); err != nil {
 		h.Logger.Error("Request validation error", err)
-		h.RespondWithError(w, r, http.StatusInternalServerError, "Validation failed", "validation_error")
+		h.RespondWithError(w, r, apperrors.CodeInternalError, "Validation failed")
 		return
 	} else if len(validationErrors) > 0 {
 		h.RespondWithValidationErrors(w, r, validationErrors)
@@ -241,15 +244,15 @@ func (h *ProjectHandler) UpdateProjectMember(w http.ResponseWriter, r *http.Requ
 	member, err := h.projectService.UpdateMember(r.Context(), projectID, memberID, req, userID)
 	if err != nil {
 		if errors.Is(err, service.ErrProjectNotFound) {
-			h.RespondWithError(w, r, http.StatusNotFound, "Project not found", "project_not_found")
+			h.RespondWithError(w, r, apperrors.CodeProjectNotFound, "Project not found")
 			return
 		}
 		if errors.Is(err, service.ErrMemberNotFound) {
-			h.RespondWithError(w, r, http.StatusNotFound, "Member not found", "member_not_found")
+			h.RespondWithError(w, r, apperrors.CodeMemberNotFound, "Member not found")
 			return
 		}
 		if errors.Is(err, service.ErrInsufficientRights) {
-			h.RespondWithError(w, r, http.StatusForbidden, "Insufficient rights to update member role", "insufficient_rights")
+			h.RespondWithError(w, r, apperrors.CodeInsufficientRights, "Insufficient rights to update member role")
 			return
 		}
 		h.Logger.Error("Failed to update member role", err, map[string]interface{}{
@@ -257,7 +260,7 @@ func (h *ProjectHandler) UpdateProjectMember(w http.ResponseWriter, r *http.Requ
 		}, map[string]interface{}{
 			"member_id": memberID,
 		})
-		h.RespondWithError(w, r, http.StatusInternalServerError, "Failed to update member role", "update_role_failed")
+		h.RespondWithError(w, r, apperrors.CodeUpdateRoleFailed, "Failed to update member role")
 		return
 	}
 
@@ -269,36 +272,40 @@ func (h *ProjectHandler) RemoveProjectMember(w http.ResponseWriter, r *http.Requ
 	// Получаем ID пользователя из контекста
 	userID, err := h.GetUserIDFromContext(r)
 	if err != nil {
-		h.RespondWithError(w, r, http.StatusUnauthorized, "Unauthorized", "unauthorized")
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
 		return
 	}
 
 	// Получаем ID проекта из URL
 	projectID := h.GetURLParam(r, "id")
 	if projectID == "" {
-		h.RespondWithError(w, r, http.StatusBadRequest, "Project ID is required", "missing_id")
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID is required")
 		return
 	}
 
 	// Получаем ID участника из URL
 	memberID := h.GetURLParam(r, "member_id")
 	if memberID == "" {
-		h.RespondWithError(w, r, http.StatusBadRequest, "Member ID is required", "missing_member_id")
+		h.RespondWithError(w, r, apperrors.CodeMissingMemberID, "Member ID is required")
 		return
 	}
 
 	// Удаляем участника из проекта
 	if err := h.projectService.RemoveMember(r.Context(), projectID, memberID, userID); err != nil {
 		if errors.Is(err, service.ErrProjectNotFound) {
-			h.RespondWithError(w, r, http.StatusNotFound, "Project not found", "project_not_found")
+			h.RespondWithError(w, r, apperrors.CodeProjectNotFound, "Project not found")
 			return
 		}
 		if errors.Is(err, service.ErrMemberNotFound) {
-			h.RespondWithError(w, r, http.StatusNotFound, "Member not found", "member_not_found")
+			h.RespondWithError(w, r, apperrors.CodeMemberNotFound, "Member not found")
 			return
 		}
 		if errors.Is(err, service.ErrInsufficientRights) {
-			h.RespondWithError(w, r, http.StatusForbidden, "Insufficient rights to remove members", "insufficient_rights")
+			h.RespondWithError(w, r, apperrors.CodeInsufficientRights, "Insufficient rights to remove members")
+			return
+		}
+		if errors.Is(err, service.ErrOwnerNotRemovable) {
+			h.RespondWithError(w, r, apperrors.CodeOwnerNotRemovable, "Project owner cannot be removed, transfer ownership first")
 			return
 		}
 		h.Logger.Error("Failed to remove member from project", err, map[string]interface{}{
@@ -306,7 +313,7 @@ func (h *ProjectHandler) RemoveProjectMember(w http.ResponseWriter, r *http.Requ
 		}, map[string]interface{}{
 			"member_id": memberID,
 		})
-		h.RespondWithError(w, r, http.StatusInternalServerError, "Failed to remove member", "remove_member_failed")
+		h.RespondWithError(w, r, apperrors.CodeRemoveMemberFailed, "Failed to remove member")
 		return
 	}
 
@@ -315,128 +322,462 @@ func (h *ProjectHandler) RemoveProjectMember(w http.ResponseWriter, r *http.Requ
 
 // GetProjectMetrics возвращает метрики проекта
 func (h *ProjectHandler) GetProjectMetrics(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID проекта из URL
+	projectID := h.GetURLParam(r, "id")
+	if projectID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID is required")
+		return
+	}
+
+	// Получаем метрики проекта
+	metrics, err := h.projectService.GetProjectMetrics(r.Context(), projectID)
+	if err != nil {
+		if errors.Is(err, service.ErrProjectNotFound) {
+			h.RespondWithError(w, r, apperrors.CodeProjectNotFound, "Project not found")
+			return
+		}
+		h.Logger.Error("Failed to get project metrics", err, map[string]interface{}{
+			"id": projectID,
+		})
+		h.RespondWithError(w, r, apperrors.CodeMetricsFetchFailed, "Failed to get project metrics")
+		return
+	}
+
+	h.RespondWithSuccess(w, r, metrics)
+}
+
+// GetProjectMetricsHistory возвращает ежедневные снимки метрик проекта для графиков динамики
+func (h *ProjectHandler) GetProjectMetricsHistory(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID проекта из URL
+	projectID := h.GetURLParam(r, "id")
+	if projectID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID is required")
+		return
+	}
+
+	days := 30
+	if daysParam := r.URL.Query().Get("days"); daysParam != "" {
+		parsed, err := strconv.Atoi(daysParam)
+		if err != nil || parsed <= 0 || parsed > 365 {
+			h.RespondWithError(w, r, apperrors.CodeInvalidDays, "Days must be between 1 and 365")
+			return
+		}
+		days = parsed
+	}
+
+	history, err := h.projectService.GetMetricsHistory(r.Context(), projectID, days)
+	if err != nil {
+		if errors.Is(err, service.ErrProjectNotFound) {
+			h.RespondWithError(w, r, apperrors.CodeProjectNotFound, "Project not found")
+			return
+		}
+		h.Logger.Error("Failed to get project metrics history", err, map[string]interface{}{
+			"id": projectID,
+		})
+		h.RespondWithError(w, r, apperrors.CodeMetricsFetchFailed, "Failed to get project metrics history")
+		return
+	}
+
+	h.RespondWithSuccess(w, r, history)
+}
+
+// GetProject возвращает информацию о проекте по ID
+func (h *ProjectHandler) GetProject(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID проекта из URL
+	projectID := h.GetURLParam(r, "id")
+	if projectID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID is required")
+		return
+	}
+
+	// Получаем данные проекта
+	project, err := h.projectService.GetByID(r.Context(), projectID)
+	if err != nil {
+		if errors.Is(err, service.ErrProjectNotFound) {
+			h.RespondWithError(w, r, apperrors.CodeProjectNotFound, "Project not found")
+			return
+		}
+		h.Logger.Error("Failed to get project", err, map[string]interface{}{
+			"id": projectID,
+		})
+		h.RespondWithError(w, r, apperrors.CodeProjectFetchFailed, "Failed to get project info")
+		return
+	}
+
+	h.RespondWithSuccess(w, r, project)
+}
+
+// UpdateProject обновляет информацию о проекте
+func (h *ProjectHandler) UpdateProject(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID проекта из URL
+	projectID := h.GetURLParam(r, "id")
+	if projectID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID is required")
+		return
+	}
+
+	var req domain.ProjectUpdateRequest
+	if err := h.ParseJSON(r, &req); err != nil {
+		h.Logger.Error("Failed to parse update project request", err)
+		h.RespondWithError(w, r, apperrors.CodeInvalidFormat, "Invalid request format")
+		return
+	}
+
+	// Валидация запроса
+	if validationErrors, err := h.ValidateRequest(r, req); err != nil {
+		h.Logger.Error("Request validation error", err)
+		h.RespondWithError(w, r, apperrors.CodeInternalError, "Validation failed")
+		return
+	} else if len(validationErrors) > 0 {
+		h.RespondWithValidationErrors(w, r, validationErrors)
+		return
+	}
+
+	// Обновляем данные проекта
+	project, err := h.projectService.Update(r.Context(), projectID, req)
+	if err != nil {
+		if errors.Is(err, service.ErrProjectNotFound) {
+			h.RespondWithError(w, r, apperrors.CodeProjectNotFound, "Project not found")
+			return
+		}
+		h.Logger.Error("Failed to update project", err, map[string]interface{}{
+			"id": projectID,
+		})
+		h.RespondWithError(w, r, apperrors.CodeUpdateFailed, "Failed to update project")
+		return
+	}
+
+	h.RespondWithSuccess(w, r, project)
+}
+
+// ArchiveProject переводит проект в архив
+func (h *ProjectHandler) ArchiveProject(w http.ResponseWriter, r *http.Request) {
 	// Получаем ID пользователя из контекста
 	userID, err := h.GetUserIDFromContext(r)
 	if err != nil {
-		h.RespondWithError(w, r, http.StatusUnauthorized, "Unauthorized", "unauthorized")
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
 		return
 	}
 
 	// Получаем ID проекта из URL
 	projectID := h.GetURLParam(r, "id")
 	if projectID == "" {
-		h.RespondWithError(w, r, http.StatusBadRequest, "Project ID is required", "missing_id")
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID is required")
 		return
 	}
 
-	// Получаем метрики проекта
-	metrics, err := h.projectService.GetProjectMetrics(r.Context(), projectID, userID)
+	project, err := h.projectService.Archive(r.Context(), projectID, userID)
 	if err != nil {
 		if errors.Is(err, service.ErrProjectNotFound) {
-			h.RespondWithError(w, r, http.StatusNotFound, "Project not found", "project_not_found")
+			h.RespondWithError(w, r, apperrors.CodeProjectNotFound, "Project not found")
 			return
 		}
-		if errors.Is(err, service.ErrInsufficientRights) {
-			h.RespondWithError(w, r, http.StatusForbidden, "Access denied to the project", "access_denied")
+		if errors.Is(err, service.ErrProjectArchived) {
+			h.RespondWithError(w, r, apperrors.CodeProjectArchived, "Project is already archived")
 			return
 		}
-		h.Logger.Error("Failed to get project metrics", err, map[string]interface{}{
+		h.Logger.Error("Failed to archive project", err, map[string]interface{}{
 			"id": projectID,
 		})
-		h.RespondWithError(w, r, http.StatusInternalServerError, "Failed to get project metrics", "metrics_fetch_failed")
+		h.RespondWithError(w, r, apperrors.CodeArchiveFailed, "Failed to archive project")
 		return
 	}
 
-	h.RespondWithSuccess(w, r, metrics)
+	h.RespondWithSuccess(w, r, project)
 }
 
-// GetProject возвращает информацию о проекте по ID
-func (h *ProjectHandler) GetProject(w http.ResponseWriter, r *http.Request) {
+// RestoreProject возвращает проект из архива
+func (h *ProjectHandler) RestoreProject(w http.ResponseWriter, r *http.Request) {
 	// Получаем ID пользователя из контекста
 	userID, err := h.GetUserIDFromContext(r)
 	if err != nil {
-		h.RespondWithError(w, r, http.StatusUnauthorized, "Unauthorized", "unauthorized")
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
 		return
 	}
 
 	// Получаем ID проекта из URL
 	projectID := h.GetURLParam(r, "id")
 	if projectID == "" {
-		h.RespondWithError(w, r, http.StatusBadRequest, "Project ID is required", "missing_id")
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID is required")
 		return
 	}
 
-	// Получаем данные проекта
-	project, err := h.projectService.GetByID(r.Context(), projectID, userID)
+	// Тело запроса необязательно
+	var req domain.ProjectRestoreRequest
+	if r.ContentLength > 0 {
+		if err := h.ParseJSON(r, &req); err != nil {
+			h.Logger.Error("Failed to parse restore project request", err)
+			h.RespondWithError(w, r, apperrors.CodeInvalidFormat, "Invalid request format")
+			return
+		}
+	}
+
+	// Валидация запроса
+	if validationErrors, err := h.ValidateRequest(r, req); err != nil {
+		h.Logger.Error("Request validation error", err)
+		h.RespondWithError(w, r, apperrors.CodeInternalError, "Validation failed")
+		return
+	} else if len(validationErrors) > 0 {
+		h.RespondWithValidationErrors(w, r, validationErrors)
+		return
+	}
+
+	project, err := h.projectService.Restore(r.Context(), projectID, req, userID)
 	if err != nil {
 		if errors.Is(err, service.ErrProjectNotFound) {
-			h.RespondWithError(w, r, http.StatusNotFound, "Project not found", "project_not_found")
+			h.RespondWithError(w, r, apperrors.CodeProjectNotFound, "Project not found")
 			return
 		}
-		if errors.Is(err, service.ErrInsufficientRights) {
-			h.RespondWithError(w, r, http.StatusForbidden, "Access denied to the project", "access_denied")
+		if errors.Is(err, service.ErrProjectNotArchived) {
+			h.RespondWithError(w, r, apperrors.CodeProjectNotArchived, "Project is not archived")
 			return
 		}
-		h.Logger.Error("Failed to get project", err, map[string]interface{}{
+		h.Logger.Error("Failed to restore project", err, map[string]interface{}{
 			"id": projectID,
 		})
-		h.RespondWithError(w, r, http.StatusInternalServerError, "Failed to get project info", "project_fetch_failed")
+		h.RespondWithError(w, r, apperrors.CodeRestoreFailed, "Failed to restore project")
 		return
 	}
 
 	h.RespondWithSuccess(w, r, project)
 }
 
-// UpdateProject обновляет информацию о проекте
-func (h *ProjectHandler) UpdateProject(w http.ResponseWriter, r *http.Request) {
+// GetTaskSettings возвращает настройки задач проекта по умолчанию
+func (h *ProjectHandler) GetTaskSettings(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID проекта из URL
+	projectID := h.GetURLParam(r, "id")
+	if projectID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID is required")
+		return
+	}
+
+	settings, err := h.projectService.GetTaskSettings(r.Context(), projectID)
+	if err != nil {
+		if errors.Is(err, service.ErrProjectNotFound) {
+			h.RespondWithError(w, r, apperrors.CodeProjectNotFound, "Project not found")
+			return
+		}
+		h.Logger.Error("Failed to get project task settings", err, map[string]interface{}{
+			"id": projectID,
+		})
+		h.RespondWithError(w, r, apperrors.CodeSettingsFetchFailed, "Failed to get task settings")
+		return
+	}
+
+	h.RespondWithSuccess(w, r, settings)
+}
+
+// UpdateTaskSettings изменяет настройки задач проекта по умолчанию
+func (h *ProjectHandler) UpdateTaskSettings(w http.ResponseWriter, r *http.Request) {
 	// Получаем ID пользователя из контекста
 	userID, err := h.GetUserIDFromContext(r)
 	if err != nil {
-		h.RespondWithError(w, r, http.StatusUnauthorized, "Unauthorized", "unauthorized")
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
 		return
 	}
 
 	// Получаем ID проекта из URL
 	projectID := h.GetURLParam(r, "id")
 	if projectID == "" {
-		h.RespondWithError(w, r, http.StatusBadRequest, "Project ID is required", "missing_id")
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID is required")
 		return
 	}
 
-	var req domain.ProjectUpdateRequest
+	var req domain.ProjectTaskSettingsRequest
 	if err := h.ParseJSON(r, &req); err != nil {
-		h.Logger.Error("Failed to parse update project request", err)
-		h.RespondWithError(w, r, http.StatusBadRequest, "Invalid request format", "invalid_format")
+		h.Logger.Error("Failed to parse task settings request", err)
+		h.RespondWithError(w, r, apperrors.CodeInvalidFormat, "Invalid request format")
 		return
 	}
 
 	// Валидация запроса
-	if validationErrors, err := h.ValidateRequest(req); err != nil {
+	if validationErrors, err := h.ValidateRequest(r, req); err != nil {
 		h.Logger.Error("Request validation error", err)
-		h.RespondWithError(w, r, http.StatusInternalServerError, "Validation failed", "validation_error")
+		h.RespondWithError(w, r, apperrors.CodeInternalError, "Validation failed")
 		return
 	} else if len(validationErrors) > 0 {
 		h.RespondWithValidationErrors(w, r, validationErrors)
 		return
 	}
 
-	// Обновляем данные проекта
-	project, err := h.projectService.Update(r.Context(), projectID, req, userID)
+	settings, err := h.projectService.UpdateTaskSettings(r.Context(), projectID, req, userID)
 	if err != nil {
 		if errors.Is(err, service.ErrProjectNotFound) {
-			h.RespondWithError(w, r, http.StatusNotFound, "Project not found", "project_not_found")
+			h.RespondWithError(w, r, apperrors.CodeProjectNotFound, "Project not found")
 			return
 		}
-		if errors.Is(err, service.ErrInsufficientRights) {
-			h.RespondWithError(w, r, http.StatusForbidden, "Insufficient rights to update project", "insufficient_rights")
+		if errors.Is(err, service.ErrMemberNotFound) {
+			h.RespondWithError(w, r, apperrors.CodeInvalidAssignee, "Default assignee must be a member of the project")
 			return
 		}
-		h.Logger.Error("Failed to update project", err, map[string]interface{}{
+		h.Logger.Error("Failed to update project task settings", err, map[string]interface{}{
 			"id": projectID,
 		})
-		h.RespondWithError(w, r, http.StatusInternalServerError, "Failed to update project", "update_failed")
+		h.RespondWithError(w, r, apperrors.CodeSettingsUpdateFailed, "Failed to update task settings")
 		return
 	}
 
-	h.RespondWithSuccess(w, r, project)
+	h.RespondWithSuccess(w, r, settings)
+}
+
+// GetMembershipHistory возвращает историю изменений участников проекта
+func (h *ProjectHandler) GetMembershipHistory(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID проекта из URL
+	projectID := h.GetURLParam(r, "id")
+	if projectID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID is required")
+		return
+	}
+
+	// Параметры пагинации
+	page, ok := h.GetPageRequest(w, r)
+	if !ok {
+		return
+	}
+
+	result, err := h.projectService.GetMembershipHistory(r.Context(), projectID, page)
+	if err != nil {
+		if errors.Is(err, service.ErrProjectNotFound) {
+			h.RespondWithError(w, r, apperrors.CodeProjectNotFound, "Project not found")
+			return
+		}
+		h.Logger.Error("Failed to get membership history", err, map[string]interface{}{
+			"id": projectID,
+		})
+		h.RespondWithError(w, r, apperrors.CodeHistoryFetchFailed, "Failed to get membership history")
+		return
+	}
+
+	h.RespondWithPagination(w, r, result.Items, result)
+}
+
+// GetOwnershipTransfer возвращает ожидающий запрос на передачу владения проектом
+func (h *ProjectHandler) GetOwnershipTransfer(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID проекта из URL
+	projectID := h.GetURLParam(r, "id")
+	if projectID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID is required")
+		return
+	}
+
+	transfer, err := h.projectService.GetPendingOwnershipTransfer(r.Context(), projectID)
+	if err != nil {
+		h.handleOwnershipTransferError(w, r, err, projectID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, transfer)
+}
+
+// InitiateOwnershipTransfer создает запрос на передачу владения проектом
+func (h *ProjectHandler) InitiateOwnershipTransfer(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID проекта из URL
+	projectID := h.GetURLParam(r, "id")
+	if projectID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID is required")
+		return
+	}
+
+	var req domain.OwnershipTransferRequest
+	if err := h.ParseJSON(r, &req); err != nil {
+		h.Logger.Error("Failed to parse ownership transfer request", err)
+		h.RespondWithError(w, r, apperrors.CodeInvalidFormat, "Invalid request format")
+		return
+	}
+
+	// Валидация запроса
+	if validationErrors, err := h.ValidateRequest(r, req); err != nil {
+		h.Logger.Error("Request validation error", err)
+		h.RespondWithError(w, r, apperrors.CodeInternalError, "Validation failed")
+		return
+	} else if len(validationErrors) > 0 {
+		h.RespondWithValidationErrors(w, r, validationErrors)
+		return
+	}
+
+	transfer, err := h.projectService.InitiateOwnershipTransfer(r.Context(), projectID, req, userID)
+	if err != nil {
+		if errors.Is(err, service.ErrMemberAlreadyExists) {
+			h.RespondWithError(w, r, apperrors.CodeAlreadyOwner, "User is already the project owner")
+			return
+		}
+		h.handleOwnershipTransferError(w, r, err, projectID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, transfer)
+}
+
+// AcceptOwnershipTransfer подтверждает передачу владения проектом
+func (h *ProjectHandler) AcceptOwnershipTransfer(w http.ResponseWriter, r *http.Request) {
+	h.resolveOwnershipTransfer(w, r, h.projectService.AcceptOwnershipTransfer)
+}
+
+// DeclineOwnershipTransfer отклоняет передачу владения проектом
+func (h *ProjectHandler) DeclineOwnershipTransfer(w http.ResponseWriter, r *http.Request) {
+	h.resolveOwnershipTransfer(w, r, h.projectService.DeclineOwnershipTransfer)
+}
+
+// CancelOwnershipTransfer отменяет запрос на передачу владения проектом
+func (h *ProjectHandler) CancelOwnershipTransfer(w http.ResponseWriter, r *http.Request) {
+	h.resolveOwnershipTransfer(w, r, h.projectService.CancelOwnershipTransfer)
+}
+
+// resolveOwnershipTransfer выполняет общую обработку запросов, закрывающих передачу владения
+func (h *ProjectHandler) resolveOwnershipTransfer(
+	w http.ResponseWriter,
+	r *http.Request,
+	resolve func(ctx context.Context, projectID string, userID string) (*domain.OwnershipTransfer, error),
+) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID проекта из URL
+	projectID := h.GetURLParam(r, "id")
+	if projectID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID is required")
+		return
+	}
+
+	transfer, err := resolve(r.Context(), projectID, userID)
+	if err != nil {
+		h.handleOwnershipTransferError(w, r, err, projectID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, transfer)
+}
+
+// handleOwnershipTransferError преобразует ошибки передачи владения в HTTP-ответы
+func (h *ProjectHandler) handleOwnershipTransferError(w http.ResponseWriter, r *http.Request, err error, projectID string) {
+	switch {
+	case errors.Is(err, service.ErrProjectNotFound):
+		h.RespondWithError(w, r, apperrors.CodeProjectNotFound, "Project not found")
+	case errors.Is(err, service.ErrMemberNotFound):
+		h.RespondWithError(w, r, apperrors.CodeMemberNotFound, "New owner must be a member of the project")
+	case errors.Is(err, service.ErrInsufficientRights):
+		h.RespondWithError(w, r, apperrors.CodeInsufficientRights, "Insufficient rights to manage ownership transfer")
+	case errors.Is(err, service.ErrOwnershipTransferPending):
+		h.RespondWithError(w, r, apperrors.CodeTransferPending, "Ownership transfer is already pending")
+	case errors.Is(err, service.ErrOwnershipTransferNotFound):
+		h.RespondWithError(w, r, apperrors.CodeTransferNotFound, "Pending ownership transfer not found")
+	case errors.Is(err, service.ErrOwnershipTransferExpired):
+		h.RespondWithError(w, r, apperrors.CodeTransferExpired, "Ownership transfer has expired")
+	default:
+		h.Logger.Error("Failed to process ownership transfer", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		h.RespondWithError(w, r, apperrors.CodeOwnershipTransferFailed, "Failed to process ownership transfer")
+	}
 }
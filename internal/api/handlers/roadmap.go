@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/internal/repository/cache"
+	"github.com/nurlyy/task_manager/internal/service"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
+)
+
+// RoadmapHandler обрабатывает запросы, связанные с публичной дорожной картой проекта
+type RoadmapHandler struct {
+	BaseHandler
+	roadmapService *service.RoadmapService
+}
+
+// NewRoadmapHandler создает новый экземпляр RoadmapHandler
+func NewRoadmapHandler(base BaseHandler, roadmapService *service.RoadmapService) *RoadmapHandler {
+	return &RoadmapHandler{
+		BaseHandler:    base,
+		roadmapService: roadmapService,
+	}
+}
+
+// GetProjectRoadmap возвращает настройки публичной дорожной карты проекта
+func (h *RoadmapHandler) GetProjectRoadmap(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID проекта из URL
+	projectID := h.GetURLParam(r, "id")
+	if projectID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID is required")
+		return
+	}
+
+	roadmap, err := h.roadmapService.GetRoadmap(r.Context(), projectID)
+	if err != nil {
+		h.handleRoadmapError(w, r, err, projectID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, roadmap)
+}
+
+// SaveProjectRoadmap включает или обновляет публичную дорожную карту проекта
+func (h *RoadmapHandler) SaveProjectRoadmap(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID проекта из URL
+	projectID := h.GetURLParam(r, "id")
+	if projectID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID is required")
+		return
+	}
+
+	// Парсим запрос
+	var req domain.ProjectRoadmapRequest
+	if err := h.ParseJSON(r, &req); err != nil {
+		h.Logger.Error("Failed to parse roadmap request", err)
+		h.RespondWithError(w, r, apperrors.CodeInvalidFormat, "Invalid request format")
+		return
+	}
+
+	// Валидация запроса
+	if validationErrors, err := h.ValidateRequest(r, req); err != nil {
+		h.Logger.Error("Request validation error", err)
+		h.RespondWithError(w, r, apperrors.CodeInternalError, "Validation failed")
+		return
+	} else if len(validationErrors) > 0 {
+		h.RespondWithValidationErrors(w, r, validationErrors)
+		return
+	}
+
+	roadmap, err := h.roadmapService.SaveRoadmap(r.Context(), projectID, req, userID)
+	if err != nil {
+		h.handleRoadmapError(w, r, err, projectID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, roadmap)
+}
+
+// RotateProjectRoadmapKey выдает дорожной карте новую публичную ссылку
+func (h *RoadmapHandler) RotateProjectRoadmapKey(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID проекта из URL
+	projectID := h.GetURLParam(r, "id")
+	if projectID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID is required")
+		return
+	}
+
+	roadmap, err := h.roadmapService.RotateRoadmapKey(r.Context(), projectID)
+	if err != nil {
+		h.handleRoadmapError(w, r, err, projectID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, roadmap)
+}
+
+// DeleteProjectRoadmap снимает дорожную карту проекта с публикации
+func (h *RoadmapHandler) DeleteProjectRoadmap(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID проекта из URL
+	projectID := h.GetURLParam(r, "id")
+	if projectID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID is required")
+		return
+	}
+
+	if err := h.roadmapService.DeleteRoadmap(r.Context(), projectID); err != nil {
+		h.handleRoadmapError(w, r, err, projectID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, map[string]bool{"success": true})
+}
+
+// GetPublicRoadmap возвращает опубликованную дорожную карту в JSON или HTML. Маршрут публичный
+func (h *RoadmapHandler) GetPublicRoadmap(w http.ResponseWriter, r *http.Request) {
+	key := h.GetURLParam(r, "key")
+	if key == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Roadmap key is required")
+		return
+	}
+
+	roadmap, err := h.roadmapService.GetPublicRoadmap(r.Context(), key)
+	if err != nil {
+		h.handleRoadmapError(w, r, err, key)
+		return
+	}
+
+	// Кэш ответов сбрасывает дорожную карту и при изменении задач проекта
+	w.Header().Set(cache.SurrogateKeyHeader, cache.ProjectSurrogateKey(roadmap.ProjectID))
+
+	if !wantsRoadmapHTML(r) {
+		h.RespondWithSuccess(w, r, roadmap)
+		return
+	}
+
+	page, err := h.roadmapService.RenderRoadmapHTML(roadmap)
+	if err != nil {
+		h.handleRoadmapError(w, r, err, key)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(page); err != nil {
+		h.Logger.Error("Failed to write roadmap page", err)
+	}
+}
+
+// handleRoadmapError преобразует ошибки дорожной карты в HTTP-ответы
+func (h *RoadmapHandler) handleRoadmapError(w http.ResponseWriter, r *http.Request, err error, id string) {
+	switch {
+	case errors.Is(err, service.ErrRoadmapNotFound):
+		h.RespondWithError(w, r, apperrors.CodeRoadmapNotFound, "Roadmap not found")
+	default:
+		h.Logger.Error("Failed to process roadmap request", err, map[string]interface{}{
+			"id": id,
+		})
+		h.RespondWithError(w, r, apperrors.CodeRoadmapFailed, "Failed to process roadmap request")
+	}
+}
+
+// wantsRoadmapHTML определяет, запрошено ли HTML-представление дорожной карты
+func wantsRoadmapHTML(r *http.Request) bool {
+	if format := r.URL.Query().Get("format"); format != "" {
+		return format == "html"
+	}
+	return strings.Contains(r.Header.Get("Accept"), "text/html")
+}
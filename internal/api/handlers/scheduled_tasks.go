@@ -0,0 +1,215 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/internal/service"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
+)
+
+// ScheduledTaskHandler обрабатывает запросы, связанные с отложенным созданием задач
+type ScheduledTaskHandler struct {
+	BaseHandler
+	scheduledTaskService *service.ScheduledTaskService
+}
+
+// NewScheduledTaskHandler создает новый экземпляр ScheduledTaskHandler
+func NewScheduledTaskHandler(base BaseHandler, scheduledTaskService *service.ScheduledTaskService) *ScheduledTaskHandler {
+	return &ScheduledTaskHandler{
+		BaseHandler:          base,
+		scheduledTaskService: scheduledTaskService,
+	}
+}
+
+// ListScheduledTasks возвращает запланированные задачи проекта
+func (h *ScheduledTaskHandler) ListScheduledTasks(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID проекта из URL
+	projectID := h.GetURLParam(r, "id")
+	if projectID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID is required")
+		return
+	}
+
+	// Фильтр по статусу
+	var status *domain.ScheduledTaskStatus
+	if value := r.URL.Query().Get("status"); value != "" {
+		scheduledStatus := domain.ScheduledTaskStatus(value)
+		status = &scheduledStatus
+	}
+
+	scheduled, err := h.scheduledTaskService.List(r.Context(), projectID, status)
+	if err != nil {
+		h.handleScheduledTaskError(w, r, err, projectID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, scheduled)
+}
+
+// CreateScheduledTask планирует создание задачи в проекте
+func (h *ScheduledTaskHandler) CreateScheduledTask(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID проекта из URL
+	projectID := h.GetURLParam(r, "id")
+	if projectID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID is required")
+		return
+	}
+
+	var req domain.ScheduledTaskRequest
+	if !h.parseScheduledTaskRequest(w, r, projectID, &req) {
+		return
+	}
+
+	scheduled, err := h.scheduledTaskService.Create(r.Context(), projectID, req, userID)
+	if err != nil {
+		h.handleScheduledTaskError(w, r, err, projectID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, scheduled)
+}
+
+// GetScheduledTask возвращает запланированную задачу
+func (h *ScheduledTaskHandler) GetScheduledTask(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID проекта и запланированной задачи из URL
+	projectID := h.GetURLParam(r, "id")
+	id := h.GetURLParam(r, "scheduled_id")
+	if projectID == "" || id == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID and scheduled task ID are required")
+		return
+	}
+
+	scheduled, err := h.scheduledTaskService.GetByID(r.Context(), projectID, id)
+	if err != nil {
+		h.handleScheduledTaskError(w, r, err, id)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, scheduled)
+}
+
+// UpdateScheduledTask изменяет запланированную задачу до ее создания
+func (h *ScheduledTaskHandler) UpdateScheduledTask(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID проекта и запланированной задачи из URL
+	projectID := h.GetURLParam(r, "id")
+	id := h.GetURLParam(r, "scheduled_id")
+	if projectID == "" || id == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID and scheduled task ID are required")
+		return
+	}
+
+	var req domain.ScheduledTaskRequest
+	if !h.parseScheduledTaskRequest(w, r, projectID, &req) {
+		return
+	}
+
+	scheduled, err := h.scheduledTaskService.Update(r.Context(), projectID, id, req, userID)
+	if err != nil {
+		h.handleScheduledTaskError(w, r, err, id)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, scheduled)
+}
+
+// CancelScheduledTask отменяет создание задачи
+func (h *ScheduledTaskHandler) CancelScheduledTask(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID проекта и запланированной задачи из URL
+	projectID := h.GetURLParam(r, "id")
+	id := h.GetURLParam(r, "scheduled_id")
+	if projectID == "" || id == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID and scheduled task ID are required")
+		return
+	}
+
+	scheduled, err := h.scheduledTaskService.Cancel(r.Context(), projectID, id, userID)
+	if err != nil {
+		h.handleScheduledTaskError(w, r, err, id)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, scheduled)
+}
+
+// parseScheduledTaskRequest разбирает и валидирует тело запроса.
+// Проект будущей задачи определяется URL, поэтому project_id в теле не требуется.
+// Возвращает false, если ответ с ошибкой уже отправлен
+func (h *ScheduledTaskHandler) parseScheduledTaskRequest(w http.ResponseWriter, r *http.Request, projectID string, req *domain.ScheduledTaskRequest) bool {
+	if err := h.ParseJSON(r, req); err != nil {
+		h.Logger.Error("Failed to parse scheduled task request", err)
+		h.RespondWithError(w, r, apperrors.CodeInvalidFormat, "Invalid request format")
+		return false
+	}
+
+	req.Task.ProjectID = projectID
+
+	// Валидация запроса
+	if validationErrors, err := h.ValidateRequest(r, req); err != nil {
+		h.Logger.Error("Request validation error", err)
+		h.RespondWithError(w, r, apperrors.CodeInternalError, "Validation failed")
+		return false
+	} else if len(validationErrors) > 0 {
+		h.RespondWithValidationErrors(w, r, validationErrors)
+		return false
+	}
+
+	return true
+}
+
+// handleScheduledTaskError преобразует ошибки отложенного создания задач в HTTP-ответы
+func (h *ScheduledTaskHandler) handleScheduledTaskError(w http.ResponseWriter, r *http.Request, err error, id string) {
+	var validationErr *service.TaskValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		validationErrors := make([]ValidationError, 0, len(validationErr.Violations))
+		for _, violation := range validationErr.Violations {
+			validationErrors = append(validationErrors, ValidationError{
+				Field:   violation.Field,
+				Message: violation.Message,
+			})
+		}
+		h.RespondWithValidationErrors(w, r, validationErrors)
+	case errors.Is(err, service.ErrProjectNotFound):
+		h.RespondWithError(w, r, apperrors.CodeProjectNotFound, "Project not found")
+	case errors.Is(err, service.ErrScheduledTaskNotFound):
+		h.RespondWithError(w, r, apperrors.CodeScheduledTaskNotFound, "Scheduled task not found")
+	case errors.Is(err, service.ErrScheduledTaskProcessed):
+		h.RespondWithError(w, r, apperrors.CodeScheduledTaskProcessed, "Scheduled task has already been processed")
+	case errors.Is(err, service.ErrInvalidScheduleTime):
+		h.RespondWithError(w, r, apperrors.CodeInvalidScheduleTime, "Scheduled creation time must be in the future")
+	case errors.Is(err, service.ErrInsufficientRights):
+		h.RespondWithError(w, r, apperrors.CodeInsufficientRights, "Insufficient rights to manage this scheduled task")
+	case errors.Is(err, service.ErrInvalidAssignee):
+		h.RespondWithError(w, r, apperrors.CodeInvalidAssignee, "Assignee must be a member of the project")
+	case errors.Is(err, service.ErrProjectArchived):
+		h.RespondWithError(w, r, apperrors.CodeProjectArchived, "Project is archived and read-only")
+	default:
+		h.Logger.Error("Failed to process scheduled task", err, map[string]interface{}{
+			"id": id,
+		})
+		h.RespondWithError(w, r, apperrors.CodeScheduledTaskFailed, "Failed to process scheduled task")
+	}
+}
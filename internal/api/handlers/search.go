@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/nurlyy/task_manager/internal/service"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
+)
+
+// SearchHandler обрабатывает запросы быстрого поиска
+type SearchHandler struct {
+	BaseHandler
+	searchService *service.SearchService
+}
+
+// NewSearchHandler создает новый экземпляр SearchHandler
+func NewSearchHandler(base BaseHandler, searchService *service.SearchService) *SearchHandler {
+	return &SearchHandler{
+		BaseHandler:   base,
+		searchService: searchService,
+	}
+}
+
+// Typeahead возвращает первые совпадения среди задач, проектов, пользователей, решений и вики-страниц
+func (h *SearchHandler) Typeahead(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем параметры запроса
+	query := r.URL.Query().Get("q")
+	limit := 0
+	if limitParam := r.URL.Query().Get("limit"); limitParam != "" {
+		parsed, err := strconv.Atoi(limitParam)
+		if err != nil || parsed <= 0 {
+			h.RespondWithError(w, r, apperrors.CodeInvalidLimit, "Invalid limit")
+			return
+		}
+		limit = parsed
+	}
+
+	result, err := h.searchService.Typeahead(r.Context(), query, limit, userID)
+	if err != nil {
+		if errors.Is(err, service.ErrSearchQueryEmpty) {
+			h.RespondWithError(w, r, apperrors.CodeMissingQuery, "Search query is required")
+			return
+		}
+		h.Logger.Error("Failed to perform typeahead search", err, map[string]interface{}{
+			"query": query,
+		})
+		h.RespondWithError(w, r, apperrors.CodeSearchFailed, "Failed to perform search")
+		return
+	}
+
+	h.RespondWithSuccess(w, r, result)
+}
+
+// SearchPeople ищет пользователей по имени и email с нечетким совпадением.
+// Параметр project_id ограничивает поиск участниками проекта
+func (h *SearchHandler) SearchPeople(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем параметры запроса
+	query := r.URL.Query().Get("q")
+	var projectID *string
+	if projectParam := r.URL.Query().Get("project_id"); projectParam != "" {
+		projectID = &projectParam
+	}
+	limit := 0
+	if limitParam := r.URL.Query().Get("limit"); limitParam != "" {
+		parsed, err := strconv.Atoi(limitParam)
+		if err != nil || parsed <= 0 {
+			h.RespondWithError(w, r, apperrors.CodeInvalidLimit, "Invalid limit")
+			return
+		}
+		limit = parsed
+	}
+
+	users, err := h.searchService.SearchPeople(r.Context(), query, projectID, limit, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrSearchQueryEmpty):
+			h.RespondWithError(w, r, apperrors.CodeMissingQuery, "Search query is required")
+		case errors.Is(err, service.ErrProjectNotFound):
+			h.RespondWithError(w, r, apperrors.CodeProjectNotFound, "Project not found")
+		default:
+			h.Logger.Error("Failed to search people", err, map[string]interface{}{
+				"query": query,
+			})
+			h.RespondWithError(w, r, apperrors.CodeSearchFailed, "Failed to perform search")
+		}
+		return
+	}
+
+	h.RespondWithSuccess(w, r, users)
+}
@@ -0,0 +1,189 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/internal/service"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
+)
+
+// SMSHandler обрабатывает запросы, связанные с SMS-уведомлениями
+type SMSHandler struct {
+	BaseHandler
+	smsService *service.SMSService
+}
+
+// NewSMSHandler создает новый экземпляр SMSHandler
+func NewSMSHandler(base BaseHandler, smsService *service.SMSService) *SMSHandler {
+	return &SMSHandler{
+		BaseHandler: base,
+		smsService:  smsService,
+	}
+}
+
+// GetSMSStatus возвращает состояние SMS-уведомлений пользователя
+func (h *SMSHandler) GetSMSStatus(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	status, err := h.smsService.GetStatus(r.Context(), userID)
+	if err != nil {
+		h.handleSMSError(w, r, err, userID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, status)
+}
+
+// SetSMSPhone привязывает телефон и отправляет код подтверждения
+func (h *SMSHandler) SetSMSPhone(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req domain.SMSPhoneRequest
+	if err := h.ParseJSON(r, &req); err != nil {
+		h.Logger.Error("Failed to parse SMS phone request", err)
+		h.RespondWithError(w, r, apperrors.CodeInvalidFormat, "Invalid request format")
+		return
+	}
+
+	// Валидация запроса
+	if validationErrors, err := h.ValidateRequest(r, req); err != nil {
+		h.Logger.Error("Request validation error", err)
+		h.RespondWithError(w, r, apperrors.CodeInternalError, "Validation failed")
+		return
+	} else if len(validationErrors) > 0 {
+		h.RespondWithValidationErrors(w, r, validationErrors)
+		return
+	}
+
+	status, err := h.smsService.SetPhone(r.Context(), userID, req)
+	if err != nil {
+		h.handleSMSError(w, r, err, userID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, status)
+}
+
+// VerifySMSPhone подтверждает телефон кодом из SMS
+func (h *SMSHandler) VerifySMSPhone(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req domain.SMSVerifyRequest
+	if err := h.ParseJSON(r, &req); err != nil {
+		h.Logger.Error("Failed to parse SMS verify request", err)
+		h.RespondWithError(w, r, apperrors.CodeInvalidFormat, "Invalid request format")
+		return
+	}
+
+	// Валидация запроса
+	if validationErrors, err := h.ValidateRequest(r, req); err != nil {
+		h.Logger.Error("Request validation error", err)
+		h.RespondWithError(w, r, apperrors.CodeInternalError, "Validation failed")
+		return
+	} else if len(validationErrors) > 0 {
+		h.RespondWithValidationErrors(w, r, validationErrors)
+		return
+	}
+
+	status, err := h.smsService.Verify(r.Context(), userID, req)
+	if err != nil {
+		h.handleSMSError(w, r, err, userID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, status)
+}
+
+// UpdateSMSSettings включает или отключает SMS-уведомления
+func (h *SMSHandler) UpdateSMSSettings(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req domain.SMSSettingsRequest
+	if err := h.ParseJSON(r, &req); err != nil {
+		h.Logger.Error("Failed to parse SMS settings request", err)
+		h.RespondWithError(w, r, apperrors.CodeInvalidFormat, "Invalid request format")
+		return
+	}
+
+	// Валидация запроса
+	if validationErrors, err := h.ValidateRequest(r, req); err != nil {
+		h.Logger.Error("Request validation error", err)
+		h.RespondWithError(w, r, apperrors.CodeInternalError, "Validation failed")
+		return
+	} else if len(validationErrors) > 0 {
+		h.RespondWithValidationErrors(w, r, validationErrors)
+		return
+	}
+
+	status, err := h.smsService.UpdateSettings(r.Context(), userID, req)
+	if err != nil {
+		h.handleSMSError(w, r, err, userID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, status)
+}
+
+// DeleteSMSPhone отвязывает телефон пользователя
+func (h *SMSHandler) DeleteSMSPhone(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	if err := h.smsService.DeletePhone(r.Context(), userID); err != nil {
+		h.handleSMSError(w, r, err, userID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, map[string]bool{"success": true})
+}
+
+// handleSMSError преобразует ошибки SMS-уведомлений в HTTP-ответы
+func (h *SMSHandler) handleSMSError(w http.ResponseWriter, r *http.Request, err error, userID string) {
+	switch {
+	case errors.Is(err, service.ErrSMSNotConfigured):
+		h.RespondWithError(w, r, apperrors.CodeSMSNotConfigured, "SMS notifications are not available")
+	case errors.Is(err, service.ErrSMSQuotaExceeded):
+		h.RespondWithError(w, r, apperrors.CodeSMSQuotaExceeded, "Monthly SMS quota exceeded")
+	case errors.Is(err, service.ErrSMSPhoneNotSet):
+		h.RespondWithError(w, r, apperrors.CodePhoneNotSet, "Phone number is not set")
+	case errors.Is(err, service.ErrSMSPhoneNotVerified):
+		h.RespondWithError(w, r, apperrors.CodePhoneNotVerified, "Phone number is not verified")
+	case errors.Is(err, service.ErrSMSCodeInvalid):
+		h.RespondWithError(w, r, apperrors.CodeInvalidCode, "Invalid verification code")
+	case errors.Is(err, service.ErrSMSCodeExpired):
+		h.RespondWithError(w, r, apperrors.CodeCodeExpired, "Verification code expired, request a new one")
+	case errors.Is(err, service.ErrSMSTooManyAttempts):
+		h.RespondWithError(w, r, apperrors.CodeTooManyAttempts, "Too many attempts, request a new code")
+	default:
+		h.Logger.Error("Failed to process SMS request", err, map[string]interface{}{
+			"user_id": userID,
+		})
+		h.RespondWithError(w, r, apperrors.CodeSMSFailed, "Failed to process SMS request")
+	}
+}
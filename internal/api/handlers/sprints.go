@@ -0,0 +1,337 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/internal/service"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
+)
+
+// SprintHandler обрабатывает запросы, связанные со спринтами проекта
+type SprintHandler struct {
+	BaseHandler
+	sprintService *service.SprintService
+}
+
+// NewSprintHandler создает новый экземпляр SprintHandler
+func NewSprintHandler(base BaseHandler, sprintService *service.SprintService) *SprintHandler {
+	return &SprintHandler{
+		BaseHandler:   base,
+		sprintService: sprintService,
+	}
+}
+
+// ListProjectSprints возвращает спринты проекта с прогрессом задач
+func (h *SprintHandler) ListProjectSprints(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID проекта из URL
+	projectID := h.GetURLParam(r, "id")
+	if projectID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID is required")
+		return
+	}
+
+	sprints, err := h.sprintService.List(r.Context(), projectID)
+	if err != nil {
+		h.handleSprintError(w, r, err, projectID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, sprints)
+}
+
+// CreateSprint создает спринт в проекте
+func (h *SprintHandler) CreateSprint(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID проекта из URL
+	projectID := h.GetURLParam(r, "id")
+	if projectID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID is required")
+		return
+	}
+
+	var req domain.SprintCreateRequest
+	if !h.parseSprintRequest(w, r, &req) {
+		return
+	}
+
+	sprint, err := h.sprintService.Create(r.Context(), projectID, req, userID)
+	if err != nil {
+		h.handleSprintError(w, r, err, projectID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, sprint)
+}
+
+// GetSprint возвращает спринт с прогрессом задач
+func (h *SprintHandler) GetSprint(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID спринта из URL
+	sprintID := h.GetURLParam(r, "id")
+	if sprintID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Sprint ID is required")
+		return
+	}
+
+	sprint, err := h.sprintService.Get(r.Context(), sprintID, userID)
+	if err != nil {
+		h.handleSprintError(w, r, err, sprintID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, sprint)
+}
+
+// UpdateSprint обновляет спринт
+func (h *SprintHandler) UpdateSprint(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID спринта из URL
+	sprintID := h.GetURLParam(r, "id")
+	if sprintID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Sprint ID is required")
+		return
+	}
+
+	var req domain.SprintUpdateRequest
+	if !h.parseSprintRequest(w, r, &req) {
+		return
+	}
+
+	sprint, err := h.sprintService.Update(r.Context(), sprintID, req, userID)
+	if err != nil {
+		h.handleSprintError(w, r, err, sprintID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, sprint)
+}
+
+// DeleteSprint удаляет спринт; его задачи возвращаются в бэклог
+func (h *SprintHandler) DeleteSprint(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID спринта из URL
+	sprintID := h.GetURLParam(r, "id")
+	if sprintID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Sprint ID is required")
+		return
+	}
+
+	if err := h.sprintService.Delete(r.Context(), sprintID, userID); err != nil {
+		h.handleSprintError(w, r, err, sprintID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, map[string]bool{"success": true})
+}
+
+// StartSprint начинает запланированный спринт
+func (h *SprintHandler) StartSprint(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID спринта из URL
+	sprintID := h.GetURLParam(r, "id")
+	if sprintID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Sprint ID is required")
+		return
+	}
+
+	sprint, err := h.sprintService.Start(r.Context(), sprintID, userID)
+	if err != nil {
+		h.handleSprintError(w, r, err, sprintID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, sprint)
+}
+
+// CloseSprint завершает активный спринт и переносит его незавершенные задачи
+func (h *SprintHandler) CloseSprint(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID спринта из URL
+	sprintID := h.GetURLParam(r, "id")
+	if sprintID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Sprint ID is required")
+		return
+	}
+
+	// Тело запроса необязательно: без него незавершенные задачи возвращаются в бэклог
+	var req domain.SprintCloseRequest
+	if r.ContentLength > 0 && !h.parseSprintRequest(w, r, &req) {
+		return
+	}
+
+	sprint, err := h.sprintService.Close(r.Context(), sprintID, req, userID)
+	if err != nil {
+		h.handleSprintError(w, r, err, sprintID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, sprint)
+}
+
+// AddSprintTasks включает задачи проекта в спринт
+func (h *SprintHandler) AddSprintTasks(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID спринта из URL
+	sprintID := h.GetURLParam(r, "id")
+	if sprintID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Sprint ID is required")
+		return
+	}
+
+	var req domain.SprintTasksRequest
+	if !h.parseSprintRequest(w, r, &req) {
+		return
+	}
+
+	sprint, err := h.sprintService.AddTasks(r.Context(), sprintID, req, userID)
+	if err != nil {
+		h.handleSprintError(w, r, err, sprintID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, sprint)
+}
+
+// RemoveSprintTask возвращает задачу спринта в бэклог
+func (h *SprintHandler) RemoveSprintTask(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID спринта и задачи из URL
+	sprintID := h.GetURLParam(r, "id")
+	taskID := h.GetURLParam(r, "task_id")
+	if sprintID == "" || taskID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Sprint ID and task ID are required")
+		return
+	}
+
+	if err := h.sprintService.RemoveTask(r.Context(), sprintID, taskID, userID); err != nil {
+		h.handleSprintError(w, r, err, sprintID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, map[string]bool{"success": true})
+}
+
+// GetSprintBurndown возвращает диаграмму сгорания спринта
+func (h *SprintHandler) GetSprintBurndown(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID спринта из URL
+	sprintID := h.GetURLParam(r, "id")
+	if sprintID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Sprint ID is required")
+		return
+	}
+
+	burndown, err := h.sprintService.GetBurndown(r.Context(), sprintID, userID)
+	if err != nil {
+		h.handleSprintError(w, r, err, sprintID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, burndown)
+}
+
+// parseSprintRequest разбирает и валидирует тело запроса
+func (h *SprintHandler) parseSprintRequest(w http.ResponseWriter, r *http.Request, req interface{}) bool {
+	if err := h.ParseJSON(r, req); err != nil {
+		h.Logger.Error("Failed to parse sprint request", err)
+		h.RespondWithError(w, r, apperrors.CodeInvalidFormat, "Invalid request format")
+		return false
+	}
+
+	// Валидация запроса
+	if validationErrors, err := h.ValidateRequest(r, req); err != nil {
+		h.Logger.Error("Request validation error", err)
+		h.RespondWithError(w, r, apperrors.CodeInternalError, "Validation failed")
+		return false
+	} else if len(validationErrors) > 0 {
+		h.RespondWithValidationErrors(w, r, validationErrors)
+		return false
+	}
+
+	return true
+}
+
+// handleSprintError преобразует ошибки спринтов в HTTP-ответы
+func (h *SprintHandler) handleSprintError(w http.ResponseWriter, r *http.Request, err error, id string) {
+	switch {
+	case errors.Is(err, service.ErrProjectNotFound):
+		h.RespondWithError(w, r, apperrors.CodeProjectNotFound, "Project not found")
+	case errors.Is(err, service.ErrSprintNotFound):
+		h.RespondWithError(w, r, apperrors.CodeSprintNotFound, "Sprint not found")
+	case errors.Is(err, service.ErrTaskNotFound):
+		h.RespondWithError(w, r, apperrors.CodeTaskNotFound, "Task not found")
+	case errors.Is(err, service.ErrSprintActive):
+		h.RespondWithError(w, r, apperrors.CodeSprintActive, "Project already has an active sprint")
+	case errors.Is(err, service.ErrInvalidSprintState):
+		h.RespondWithError(w, r, apperrors.CodeInvalidSprintState, "Operation is not allowed in the current sprint state")
+	case errors.Is(err, service.ErrInvalidSprintDates):
+		h.RespondWithError(w, r, apperrors.CodeInvalidSprintDates, "Sprint must end after it starts and last at most 90 days")
+	case errors.Is(err, service.ErrSprintProjectMismatch):
+		h.RespondWithError(w, r, apperrors.CodeSprintProjectMismatch, "Tasks and target sprint must belong to the sprint project")
+	case errors.Is(err, service.ErrInsufficientRights):
+		h.RespondWithError(w, r, apperrors.CodeInsufficientRights, "Insufficient rights to manage sprints")
+	case errors.Is(err, service.ErrProjectArchived):
+		h.RespondWithError(w, r, apperrors.CodeProjectArchived, "Project is archived and read-only")
+	default:
+		h.Logger.Error("Failed to process sprint request", err, map[string]interface{}{
+			"id": id,
+		})
+		h.RespondWithError(w, r, apperrors.CodeSprintFailed, "Failed to process sprint request")
+	}
+}
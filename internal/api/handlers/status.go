@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	mw "github.com/nurlyy/task_manager/internal/api/middleware"
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/internal/repository/cache"
+	"github.com/nurlyy/task_manager/internal/service"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
+)
+
+// StatusHandler обрабатывает запросы публичной страницы статуса сервиса
+type StatusHandler struct {
+	BaseHandler
+	statusService *service.StatusService
+}
+
+// NewStatusHandler создает новый экземпляр StatusHandler
+func NewStatusHandler(base BaseHandler, statusService *service.StatusService) *StatusHandler {
+	return &StatusHandler{
+		BaseHandler:   base,
+		statusService: statusService,
+	}
+}
+
+// GetStatus возвращает страницу статуса сервиса в JSON или HTML. Клиентам,
+// превысившим мягкий лимит запросов, отдается последняя собранная страница
+func (h *StatusHandler) GetStatus(w http.ResponseWriter, r *http.Request) {
+	page := h.statusService.Page(r.Context(), mw.IsSoftLimited(r.Context()))
+
+	// Изменение заметок об инцидентах сбрасывает страницу в кэше ответов
+	w.Header().Set(cache.SurrogateKeyHeader, cache.SurrogateKeyStatus)
+
+	if !wantsStatusHTML(r) {
+		h.RespondWithSuccess(w, r, page)
+		return
+	}
+
+	body, err := h.statusService.RenderStatusHTML(page)
+	if err != nil {
+		h.Logger.Error("Failed to render status page", err)
+		h.RespondWithError(w, r, apperrors.CodeInternalError, "Failed to render status page")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(body); err != nil {
+		h.Logger.Error("Failed to write status page", err)
+	}
+}
+
+// ListStatusNotes возвращает все заметки об инцидентах
+func (h *StatusHandler) ListStatusNotes(w http.ResponseWriter, r *http.Request) {
+	notes, err := h.statusService.ListNotes(r.Context())
+	if err != nil {
+		h.handleStatusError(w, r, err, "")
+		return
+	}
+
+	h.RespondWithSuccess(w, r, notes)
+}
+
+// CreateStatusNote создает заметку об инциденте
+func (h *StatusHandler) CreateStatusNote(w http.ResponseWriter, r *http.Request) {
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	req, ok := h.parseStatusNoteRequest(w, r)
+	if !ok {
+		return
+	}
+
+	note, err := h.statusService.CreateNote(r.Context(), req, userID)
+	if err != nil {
+		h.handleStatusError(w, r, err, "")
+		return
+	}
+
+	h.RespondWithSuccess(w, r, note)
+}
+
+// UpdateStatusNote изменяет заметку об инциденте
+func (h *StatusHandler) UpdateStatusNote(w http.ResponseWriter, r *http.Request) {
+	noteID := h.GetURLParam(r, "id")
+	if noteID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Status note ID is required")
+		return
+	}
+
+	req, ok := h.parseStatusNoteRequest(w, r)
+	if !ok {
+		return
+	}
+
+	note, err := h.statusService.UpdateNote(r.Context(), noteID, req)
+	if err != nil {
+		h.handleStatusError(w, r, err, noteID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, note)
+}
+
+// DeleteStatusNote удаляет заметку об инциденте
+func (h *StatusHandler) DeleteStatusNote(w http.ResponseWriter, r *http.Request) {
+	noteID := h.GetURLParam(r, "id")
+	if noteID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Status note ID is required")
+		return
+	}
+
+	if err := h.statusService.DeleteNote(r.Context(), noteID); err != nil {
+		h.handleStatusError(w, r, err, noteID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, map[string]bool{"success": true})
+}
+
+// parseStatusNoteRequest разбирает и проверяет запрос заметки об инциденте
+func (h *StatusHandler) parseStatusNoteRequest(w http.ResponseWriter, r *http.Request) (domain.StatusNoteRequest, bool) {
+	var req domain.StatusNoteRequest
+	if err := h.ParseJSON(r, &req); err != nil {
+		h.Logger.Error("Failed to parse status note request", err)
+		h.RespondWithError(w, r, apperrors.CodeInvalidFormat, "Invalid request format")
+		return req, false
+	}
+
+	if validationErrors, err := h.ValidateRequest(r, req); err != nil {
+		h.Logger.Error("Request validation error", err)
+		h.RespondWithError(w, r, apperrors.CodeInternalError, "Validation failed")
+		return req, false
+	} else if len(validationErrors) > 0 {
+		h.RespondWithValidationErrors(w, r, validationErrors)
+		return req, false
+	}
+
+	return req, true
+}
+
+// handleStatusError преобразует ошибки сервиса в ответы API
+func (h *StatusHandler) handleStatusError(w http.ResponseWriter, r *http.Request, err error, id string) {
+	switch {
+	case errors.Is(err, service.ErrStatusNoteNotFound):
+		h.RespondWithError(w, r, apperrors.CodeStatusNoteNotFound, "Status note not found")
+	default:
+		h.Logger.Error("Failed to process status note request", err, map[string]interface{}{
+			"id": id,
+		})
+		h.RespondWithError(w, r, apperrors.CodeStatusNoteFailed, "Failed to process status note request")
+	}
+}
+
+// wantsStatusHTML определяет, запрошено ли HTML-представление страницы статуса
+func wantsStatusHTML(r *http.Request) bool {
+	if format := r.URL.Query().Get("format"); format != "" {
+		return format == "html"
+	}
+	return strings.Contains(r.Header.Get("Accept"), "text/html")
+}
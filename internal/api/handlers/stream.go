@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// streamFlushInterval определяет, через сколько элементов буфер отправляется клиенту
+const streamFlushInterval = 100
+
+// JSONArrayStream записывает стандартный успешный ответ, данные которого - массив,
+// по одному элементу, не собирая весь ответ в памяти
+type JSONArrayStream struct {
+	w       http.ResponseWriter
+	buf     *bufio.Writer
+	encoder *json.Encoder
+	count   int
+}
+
+// NewJSONArrayStream начинает потоковый ответ с кодом 200
+func (h *BaseHandler) NewJSONArrayStream(w http.ResponseWriter) *JSONArrayStream {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	buf := bufio.NewWriter(w)
+	buf.WriteString(`{"success":true,"data":[`)
+
+	return &JSONArrayStream{
+		w:       w,
+		buf:     buf,
+		encoder: json.NewEncoder(buf),
+	}
+}
+
+// Write добавляет элемент в массив
+func (s *JSONArrayStream) Write(item interface{}) error {
+	if s.count > 0 {
+		if err := s.buf.WriteByte(','); err != nil {
+			return err
+		}
+	}
+	if err := s.encoder.Encode(item); err != nil {
+		return err
+	}
+	s.count++
+
+	if s.count%streamFlushInterval == 0 {
+		return s.flush()
+	}
+	return nil
+}
+
+// Close завершает массив и ответ
+func (s *JSONArrayStream) Close() error {
+	if _, err := s.buf.WriteString("]}\n"); err != nil {
+		return err
+	}
+	return s.flush()
+}
+
+// flush отправляет накопленные данные клиенту
+func (s *JSONArrayStream) flush() error {
+	if err := s.buf.Flush(); err != nil {
+		return err
+	}
+	if flusher, ok := s.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+	return nil
+}
+
+// EventStream записывает ответ в формате Server-Sent Events
+type EventStream struct {
+	w       http.ResponseWriter
+	flusher http.Flusher
+}
+
+// NewEventStream начинает поток событий с кодом 200. Возвращает false, если соединение
+// не поддерживает отправку данных по частям
+func (h *BaseHandler) NewEventStream(w http.ResponseWriter) (*EventStream, bool) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return nil, false
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	// Отключает буферизацию ответа в nginx перед API
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	return &EventStream{w: w, flusher: flusher}, true
+}
+
+// Send отправляет событие с данными в JSON
+func (s *EventStream) Send(event string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(s.w, "event: %s\ndata: %s\n\n", event, payload); err != nil {
+		return err
+	}
+	s.flusher.Flush()
+	return nil
+}
+
+// Ping отправляет комментарий, чтобы прокси не закрывали простаивающее соединение
+func (s *EventStream) Ping() error {
+	if _, err := fmt.Fprint(s.w, ": ping\n\n"); err != nil {
+		return err
+	}
+	s.flusher.Flush()
+	return nil
+}
+
+// Retry сообщает клиенту, через сколько переподключиться после закрытия потока
+func (s *EventStream) Retry(delay time.Duration) error {
+	if _, err := fmt.Fprintf(s.w, "retry: %d\n\n", delay.Milliseconds()); err != nil {
+		return err
+	}
+	s.flusher.Flush()
+	return nil
+}
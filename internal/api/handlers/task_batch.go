@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
+)
+
+// BulkUpdateTasks обновляет несколько задач за один запрос. Каждая задача
+// обновляется независимо, результат возвращается по каждой задаче
+func (h *TaskHandler) BulkUpdateTasks(w http.ResponseWriter, r *http.Request) {
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req domain.TaskBulkUpdateRequest
+	if err := h.ParseJSON(r, &req); err != nil {
+		h.RespondWithError(w, r, apperrors.CodeInvalidFormat, "Invalid request format")
+		return
+	}
+	if !h.ValidateBatchRequest(w, r, req) {
+		return
+	}
+
+	result := domain.NewBatchResult(len(req.Items))
+	for i, item := range req.Items {
+		if err := h.ValidateBatchItem(r, item); err != nil {
+			h.AddBatchItem(result, i, item.ID, nil, err)
+			continue
+		}
+
+		task, err := h.taskService.Update(r.Context(), item.ID, item.TaskUpdateRequest, userID)
+		h.AddBatchItem(result, i, item.ID, task, err)
+	}
+
+	h.RespondWithBatch(w, r, result)
+}
+
+// ImportProjectTasks создает задачи проекта из списка. Задачи с ошибками
+// пропускаются, остальные создаются; результат возвращается по каждой задаче
+func (h *TaskHandler) ImportProjectTasks(w http.ResponseWriter, r *http.Request) {
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	projectID := h.GetURLParam(r, "id")
+	if projectID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID is required")
+		return
+	}
+
+	var req domain.TaskImportRequest
+	if err := h.ParseJSON(r, &req); err != nil {
+		h.RespondWithError(w, r, apperrors.CodeInvalidFormat, "Invalid request format")
+		return
+	}
+	if !h.ValidateBatchRequest(w, r, req) {
+		return
+	}
+
+	result := domain.NewBatchResult(len(req.Tasks))
+	for i, task := range req.Tasks {
+		task.ProjectID = projectID
+		if err := h.ValidateBatchItem(r, task); err != nil {
+			h.AddBatchItem(result, i, "", nil, err)
+			continue
+		}
+
+		created, err := h.taskService.Create(r.Context(), task, userID)
+		if err != nil {
+			h.AddBatchItem(result, i, "", nil, err)
+			continue
+		}
+		h.AddBatchItem(result, i, created.ID, created, nil)
+	}
+
+	h.RespondWithBatch(w, r, result)
+}
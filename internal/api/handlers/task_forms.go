@@ -0,0 +1,200 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/internal/service"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
+)
+
+// TaskFormHandler обрабатывает запросы, связанные с формами создания задач
+type TaskFormHandler struct {
+	BaseHandler
+	taskFormService *service.TaskFormService
+}
+
+// NewTaskFormHandler создает новый экземпляр TaskFormHandler
+func NewTaskFormHandler(base BaseHandler, taskFormService *service.TaskFormService) *TaskFormHandler {
+	return &TaskFormHandler{
+		BaseHandler:     base,
+		taskFormService: taskFormService,
+	}
+}
+
+// GetTaskForm возвращает определение формы создания задачи проекта
+func (h *TaskFormHandler) GetTaskForm(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID проекта из URL
+	projectID := h.GetURLParam(r, "id")
+	if projectID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID is required")
+		return
+	}
+
+	form, err := h.taskFormService.GetForm(r.Context(), projectID)
+	if err != nil {
+		h.handleTaskFormError(w, r, err, projectID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, form)
+}
+
+// UpdateTaskForm сохраняет определение формы создания задачи проекта
+func (h *TaskFormHandler) UpdateTaskForm(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID проекта из URL
+	projectID := h.GetURLParam(r, "id")
+	if projectID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID is required")
+		return
+	}
+
+	var req domain.TaskFormRequest
+	if err := h.ParseJSON(r, &req); err != nil {
+		h.Logger.Error("Failed to parse task form request", err)
+		h.RespondWithError(w, r, apperrors.CodeInvalidFormat, "Invalid request format")
+		return
+	}
+
+	// Валидация запроса
+	if validationErrors, err := h.ValidateRequest(r, req); err != nil {
+		h.Logger.Error("Request validation error", err)
+		h.RespondWithError(w, r, apperrors.CodeInternalError, "Validation failed")
+		return
+	} else if len(validationErrors) > 0 {
+		h.RespondWithValidationErrors(w, r, validationErrors)
+		return
+	}
+
+	form, err := h.taskFormService.SaveForm(r.Context(), projectID, req, userID)
+	if err != nil {
+		h.handleTaskFormError(w, r, err, projectID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, form)
+}
+
+// ResetTaskForm возвращает проекту форму создания задачи по умолчанию
+func (h *TaskFormHandler) ResetTaskForm(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID проекта из URL
+	projectID := h.GetURLParam(r, "id")
+	if projectID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID is required")
+		return
+	}
+
+	form, err := h.taskFormService.ResetForm(r.Context(), projectID)
+	if err != nil {
+		h.handleTaskFormError(w, r, err, projectID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, form)
+}
+
+// SubmitTaskForm создает задачу по заполненной форме проекта
+func (h *TaskFormHandler) SubmitTaskForm(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID проекта из URL
+	projectID := h.GetURLParam(r, "id")
+	if projectID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID is required")
+		return
+	}
+
+	var req domain.TaskFormSubmission
+	if err := h.ParseJSON(r, &req); err != nil {
+		h.Logger.Error("Failed to parse task form submission", err)
+		h.RespondWithError(w, r, apperrors.CodeInvalidFormat, "Invalid request format")
+		return
+	}
+
+	// Валидация запроса
+	if validationErrors, err := h.ValidateRequest(r, req); err != nil {
+		h.Logger.Error("Request validation error", err)
+		h.RespondWithError(w, r, apperrors.CodeInternalError, "Validation failed")
+		return
+	} else if len(validationErrors) > 0 {
+		h.RespondWithValidationErrors(w, r, validationErrors)
+		return
+	}
+
+	result, err := h.taskFormService.Submit(r.Context(), projectID, req, userID)
+	if err != nil {
+		h.handleTaskFormError(w, r, err, projectID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, result)
+}
+
+// GetTaskFormValues возвращает значения пользовательских полей формы задачи
+func (h *TaskFormHandler) GetTaskFormValues(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID задачи из URL
+	taskID := h.GetURLParam(r, "id")
+	if taskID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Task ID is required")
+		return
+	}
+
+	values, err := h.taskFormService.GetValues(r.Context(), taskID, userID)
+	if err != nil {
+		h.handleTaskFormError(w, r, err, taskID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, values)
+}
+
+// handleTaskFormError преобразует ошибки форм создания задач в HTTP-ответы
+func (h *TaskFormHandler) handleTaskFormError(w http.ResponseWriter, r *http.Request, err error, id string) {
+	var validationErr *service.TaskValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		validationErrors := make([]ValidationError, 0, len(validationErr.Violations))
+		for _, violation := range validationErr.Violations {
+			validationErrors = append(validationErrors, ValidationError{
+				Field:   violation.Field,
+				Message: violation.Message,
+			})
+		}
+		h.RespondWithValidationErrors(w, r, validationErrors)
+	case errors.Is(err, service.ErrProjectNotFound):
+		h.RespondWithError(w, r, apperrors.CodeProjectNotFound, "Project not found")
+	case errors.Is(err, service.ErrTaskNotFound):
+		h.RespondWithError(w, r, apperrors.CodeTaskNotFound, "Task not found")
+	case errors.Is(err, service.ErrTaskAccessDenied):
+		h.RespondWithError(w, r, apperrors.CodeAccessDenied, "Access denied to the task")
+	case errors.Is(err, service.ErrInsufficientRights):
+		h.RespondWithError(w, r, apperrors.CodeInsufficientRights, "Insufficient rights to manage task form")
+	case errors.Is(err, service.ErrProjectArchived):
+		h.RespondWithError(w, r, apperrors.CodeProjectArchived, "Project is archived and read-only")
+	default:
+		h.Logger.Error("Failed to process task form", err, map[string]interface{}{
+			"id": id,
+		})
+		h.RespondWithError(w, r, apperrors.CodeTaskFormFailed, "Failed to process task form")
+	}
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/internal/service"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
+)
+
+// GetTaskRecurrence возвращает правило повторения задачи и срок следующей задачи серии
+func (h *TaskHandler) GetTaskRecurrence(w http.ResponseWriter, r *http.Request) {
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	taskID := h.GetURLParam(r, "id")
+	if taskID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Task ID is required")
+		return
+	}
+
+	recurrence, err := h.recurrenceService.Get(r.Context(), taskID, userID)
+	if err != nil {
+		h.handleRecurrenceError(w, r, err, taskID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, recurrence)
+}
+
+// SetTaskRecurrence устанавливает или заменяет правило повторения задачи
+func (h *TaskHandler) SetTaskRecurrence(w http.ResponseWriter, r *http.Request) {
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	taskID := h.GetURLParam(r, "id")
+	if taskID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Task ID is required")
+		return
+	}
+
+	var req domain.TaskRecurrenceRequest
+	if err := h.ParseJSON(r, &req); err != nil {
+		h.RespondWithError(w, r, apperrors.CodeInvalidFormat, "Invalid request format")
+		return
+	}
+
+	if validationErrors, err := h.ValidateRequest(r, req); err != nil {
+		h.Logger.Error("Request validation error", err)
+		h.RespondWithError(w, r, apperrors.CodeInternalError, "Validation failed")
+		return
+	} else if len(validationErrors) > 0 {
+		h.RespondWithValidationErrors(w, r, validationErrors)
+		return
+	}
+
+	recurrence, err := h.recurrenceService.Set(r.Context(), taskID, req, userID)
+	if err != nil {
+		h.handleRecurrenceError(w, r, err, taskID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, recurrence)
+}
+
+// DeleteTaskRecurrence удаляет правило повторения задачи
+func (h *TaskHandler) DeleteTaskRecurrence(w http.ResponseWriter, r *http.Request) {
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	taskID := h.GetURLParam(r, "id")
+	if taskID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Task ID is required")
+		return
+	}
+
+	if err := h.recurrenceService.Delete(r.Context(), taskID, userID); err != nil {
+		h.handleRecurrenceError(w, r, err, taskID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, map[string]bool{"success": true})
+}
+
+// handleRecurrenceError преобразует ошибки повторения задач в HTTP-ответы
+func (h *TaskHandler) handleRecurrenceError(w http.ResponseWriter, r *http.Request, err error, taskID string) {
+	switch {
+	case errors.Is(err, service.ErrTaskNotFound):
+		h.RespondWithError(w, r, apperrors.CodeTaskNotFound, "Task not found")
+	case errors.Is(err, service.ErrTaskAccessDenied):
+		h.RespondWithError(w, r, apperrors.CodeAccessDenied, "Access denied to the task")
+	case errors.Is(err, service.ErrInsufficientRights):
+		h.RespondWithError(w, r, apperrors.CodeInsufficientRights, "Insufficient rights to change task recurrence")
+	case errors.Is(err, service.ErrProjectArchived):
+		h.RespondWithError(w, r, apperrors.CodeProjectArchived, "Project is archived and read-only")
+	case errors.Is(err, service.ErrTaskClosed):
+		h.RespondWithError(w, r, apperrors.CodeTaskClosed, "Recurrence can be set only on an open task")
+	case errors.Is(err, service.ErrTaskRecurrenceNotFound):
+		h.RespondWithError(w, r, apperrors.CodeTaskRecurrenceNotFound, "Task has no recurrence")
+	case errors.Is(err, service.ErrInvalidRecurrence):
+		// Ошибка содержит поле rule с описанием, что именно не так в правиле
+		h.HandleError(w, r, err)
+	default:
+		h.Logger.Error("Failed to process task recurrence", err, map[string]interface{}{
+			"task_id": taskID,
+		})
+		h.RespondWithError(w, r, apperrors.CodeTaskRecurrenceFailed, "Failed to process task recurrence")
+	}
+}
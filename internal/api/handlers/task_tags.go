@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/internal/service"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
+)
+
+// ListProjectTags возвращает теги задач проекта с числом задач
+func (h *TaskHandler) ListProjectTags(w http.ResponseWriter, r *http.Request) {
+	projectID := h.GetURLParam(r, "id")
+	if projectID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID is required")
+		return
+	}
+
+	tags, err := h.taskService.ListProjectTags(r.Context(), projectID)
+	if err != nil {
+		h.handleTagError(w, r, err, projectID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, tags)
+}
+
+// RenameProjectTag переименовывает тег во всех задачах проекта
+func (h *TaskHandler) RenameProjectTag(w http.ResponseWriter, r *http.Request) {
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	projectID := h.GetURLParam(r, "id")
+	tag, ok := h.tagURLParam(w, r)
+	if !ok {
+		return
+	}
+
+	var req domain.TagRenameRequest
+	if err := h.ParseJSON(r, &req); err != nil {
+		h.RespondWithError(w, r, apperrors.CodeInvalidFormat, "Invalid request format")
+		return
+	}
+
+	if validationErrors, err := h.ValidateRequest(r, req); err != nil {
+		h.Logger.Error("Request validation error", err)
+		h.RespondWithError(w, r, apperrors.CodeInternalError, "Validation failed")
+		return
+	} else if len(validationErrors) > 0 {
+		h.RespondWithValidationErrors(w, r, validationErrors)
+		return
+	}
+
+	result, err := h.taskService.RenameProjectTag(r.Context(), projectID, tag, req, userID)
+	if err != nil {
+		h.handleTagError(w, r, err, projectID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, result)
+}
+
+// MergeProjectTags объединяет теги проекта в один
+func (h *TaskHandler) MergeProjectTags(w http.ResponseWriter, r *http.Request) {
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	projectID := h.GetURLParam(r, "id")
+	if projectID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID is required")
+		return
+	}
+
+	var req domain.TagMergeRequest
+	if err := h.ParseJSON(r, &req); err != nil {
+		h.RespondWithError(w, r, apperrors.CodeInvalidFormat, "Invalid request format")
+		return
+	}
+
+	if validationErrors, err := h.ValidateRequest(r, req); err != nil {
+		h.Logger.Error("Request validation error", err)
+		h.RespondWithError(w, r, apperrors.CodeInternalError, "Validation failed")
+		return
+	} else if len(validationErrors) > 0 {
+		h.RespondWithValidationErrors(w, r, validationErrors)
+		return
+	}
+
+	result, err := h.taskService.MergeProjectTags(r.Context(), projectID, req, userID)
+	if err != nil {
+		h.handleTagError(w, r, err, projectID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, result)
+}
+
+// DeleteProjectTag удаляет тег, которого нет у открытых задач проекта
+func (h *TaskHandler) DeleteProjectTag(w http.ResponseWriter, r *http.Request) {
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	projectID := h.GetURLParam(r, "id")
+	tag, ok := h.tagURLParam(w, r)
+	if !ok {
+		return
+	}
+
+	result, err := h.taskService.DeleteProjectTag(r.Context(), projectID, tag, userID)
+	if err != nil {
+		h.handleTagError(w, r, err, projectID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, result)
+}
+
+// tagURLParam извлекает тег из URL. Теги могут содержать пробелы и другие
+// экранируемые символы, поэтому значение декодируется
+func (h *TaskHandler) tagURLParam(w http.ResponseWriter, r *http.Request) (string, bool) {
+	tag, err := url.PathUnescape(h.GetURLParam(r, "tag"))
+	if err != nil || tag == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Tag is required")
+		return "", false
+	}
+	return tag, true
+}
+
+// handleTagError преобразует ошибки управления тегами в HTTP-ответы
+func (h *TaskHandler) handleTagError(w http.ResponseWriter, r *http.Request, err error, projectID string) {
+	switch {
+	case errors.Is(err, service.ErrProjectNotFound):
+		h.RespondWithError(w, r, apperrors.CodeProjectNotFound, "Project not found")
+	case errors.Is(err, service.ErrInsufficientRights):
+		h.RespondWithError(w, r, apperrors.CodeInsufficientRights, "Insufficient rights to manage project tags")
+	case errors.Is(err, service.ErrProjectArchived):
+		h.RespondWithError(w, r, apperrors.CodeProjectArchived, "Project is archived and read-only")
+	case errors.Is(err, service.ErrTagNotFound):
+		h.RespondWithError(w, r, apperrors.CodeTagNotFound, "Tag not found")
+	case errors.Is(err, service.ErrTagExists):
+		h.RespondWithError(w, r, apperrors.CodeTagExists, "Tag already exists in the project, merge the tags instead")
+	case errors.Is(err, service.ErrTagInUse):
+		h.RespondWithError(w, r, apperrors.CodeTagInUse, "Tag is used by open tasks")
+	default:
+		h.Logger.Error("Failed to manage project tags", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		h.RespondWithError(w, r, apperrors.CodeTagsFailed, "Failed to manage project tags")
+	}
+}
@@ -0,0 +1,248 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/internal/service"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
+)
+
+// TaskTemplateHandler обрабатывает запросы, связанные с шаблонами задач
+type TaskTemplateHandler struct {
+	BaseHandler
+	taskTemplateService *service.TaskTemplateService
+}
+
+// NewTaskTemplateHandler создает новый экземпляр TaskTemplateHandler
+func NewTaskTemplateHandler(base BaseHandler, taskTemplateService *service.TaskTemplateService) *TaskTemplateHandler {
+	return &TaskTemplateHandler{
+		BaseHandler:         base,
+		taskTemplateService: taskTemplateService,
+	}
+}
+
+// ListTaskTemplates возвращает шаблоны задач проекта
+func (h *TaskTemplateHandler) ListTaskTemplates(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID проекта из URL
+	projectID := h.GetURLParam(r, "id")
+	if projectID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID is required")
+		return
+	}
+
+	templates, err := h.taskTemplateService.List(r.Context(), projectID)
+	if err != nil {
+		h.handleTaskTemplateError(w, r, err, projectID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, templates)
+}
+
+// CreateTaskTemplate создает шаблон задачи в проекте
+func (h *TaskTemplateHandler) CreateTaskTemplate(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID проекта из URL
+	projectID := h.GetURLParam(r, "id")
+	if projectID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID is required")
+		return
+	}
+
+	var req domain.TaskTemplateRequest
+	if !h.parseTaskTemplateRequest(w, r, &req) {
+		return
+	}
+
+	template, err := h.taskTemplateService.Create(r.Context(), projectID, req, userID)
+	if err != nil {
+		h.handleTaskTemplateError(w, r, err, projectID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, template)
+}
+
+// GetTaskTemplate возвращает шаблон задачи
+func (h *TaskTemplateHandler) GetTaskTemplate(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID шаблона из URL
+	templateID := h.GetURLParam(r, "id")
+	if templateID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Template ID is required")
+		return
+	}
+
+	template, err := h.taskTemplateService.GetByID(r.Context(), templateID, userID)
+	if err != nil {
+		h.handleTaskTemplateError(w, r, err, templateID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, template)
+}
+
+// UpdateTaskTemplate заменяет содержимое шаблона задачи
+func (h *TaskTemplateHandler) UpdateTaskTemplate(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID шаблона из URL
+	templateID := h.GetURLParam(r, "id")
+	if templateID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Template ID is required")
+		return
+	}
+
+	var req domain.TaskTemplateRequest
+	if !h.parseTaskTemplateRequest(w, r, &req) {
+		return
+	}
+
+	template, err := h.taskTemplateService.Update(r.Context(), templateID, req, userID)
+	if err != nil {
+		h.handleTaskTemplateError(w, r, err, templateID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, template)
+}
+
+// DeleteTaskTemplate удаляет шаблон задачи
+func (h *TaskTemplateHandler) DeleteTaskTemplate(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID шаблона из URL
+	templateID := h.GetURLParam(r, "id")
+	if templateID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Template ID is required")
+		return
+	}
+
+	if err := h.taskTemplateService.Delete(r.Context(), templateID, userID); err != nil {
+		h.handleTaskTemplateError(w, r, err, templateID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, map[string]bool{"success": true})
+}
+
+// CreateTaskFromTemplate создает задачу из шаблона с подстановкой переменных
+func (h *TaskTemplateHandler) CreateTaskFromTemplate(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID шаблона из URL
+	templateID := h.GetURLParam(r, "id")
+	if templateID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Template ID is required")
+		return
+	}
+
+	var req domain.TaskFromTemplateRequest
+	if err := h.ParseJSON(r, &req); err != nil {
+		h.Logger.Error("Failed to parse task from template request", err)
+		h.RespondWithError(w, r, apperrors.CodeInvalidFormat, "Invalid request format")
+		return
+	}
+
+	// Валидация запроса
+	if validationErrors, err := h.ValidateRequest(r, req); err != nil {
+		h.Logger.Error("Request validation error", err)
+		h.RespondWithError(w, r, apperrors.CodeInternalError, "Validation failed")
+		return
+	} else if len(validationErrors) > 0 {
+		h.RespondWithValidationErrors(w, r, validationErrors)
+		return
+	}
+
+	task, err := h.taskTemplateService.CreateTask(r.Context(), templateID, req, userID)
+	if err != nil {
+		h.handleTaskTemplateError(w, r, err, templateID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, task)
+}
+
+// parseTaskTemplateRequest разбирает и валидирует тело запроса шаблона.
+// Возвращает false, если ответ с ошибкой уже отправлен
+func (h *TaskTemplateHandler) parseTaskTemplateRequest(w http.ResponseWriter, r *http.Request, req *domain.TaskTemplateRequest) bool {
+	if err := h.ParseJSON(r, req); err != nil {
+		h.Logger.Error("Failed to parse task template request", err)
+		h.RespondWithError(w, r, apperrors.CodeInvalidFormat, "Invalid request format")
+		return false
+	}
+
+	// Валидация запроса
+	if validationErrors, err := h.ValidateRequest(r, req); err != nil {
+		h.Logger.Error("Request validation error", err)
+		h.RespondWithError(w, r, apperrors.CodeInternalError, "Validation failed")
+		return false
+	} else if len(validationErrors) > 0 {
+		h.RespondWithValidationErrors(w, r, validationErrors)
+		return false
+	}
+
+	return true
+}
+
+// handleTaskTemplateError преобразует ошибки шаблонов задач в HTTP-ответы
+func (h *TaskTemplateHandler) handleTaskTemplateError(w http.ResponseWriter, r *http.Request, err error, id string) {
+	var validationErr *service.TaskValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		validationErrors := make([]ValidationError, 0, len(validationErr.Violations))
+		for _, violation := range validationErr.Violations {
+			validationErrors = append(validationErrors, ValidationError{
+				Field:   violation.Field,
+				Message: violation.Message,
+			})
+		}
+		h.RespondWithValidationErrors(w, r, validationErrors)
+	case errors.Is(err, service.ErrProjectNotFound):
+		h.RespondWithError(w, r, apperrors.CodeProjectNotFound, "Project not found")
+	case errors.Is(err, service.ErrTaskTemplateNotFound):
+		h.RespondWithError(w, r, apperrors.CodeTaskTemplateNotFound, "Task template not found")
+	case errors.Is(err, service.ErrTaskTemplateExists):
+		h.RespondWithError(w, r, apperrors.CodeTaskTemplateExists, "Task template with this name already exists")
+	case errors.Is(err, service.ErrInsufficientRights):
+		h.RespondWithError(w, r, apperrors.CodeInsufficientRights, "Insufficient rights to manage task templates")
+	case errors.Is(err, service.ErrInvalidAssignee):
+		h.RespondWithError(w, r, apperrors.CodeInvalidAssignee, "Assignee must be a member of the project")
+	case errors.Is(err, service.ErrProjectArchived):
+		h.RespondWithError(w, r, apperrors.CodeProjectArchived, "Project is archived and read-only")
+	default:
+		h.Logger.Error("Failed to process task template", err, map[string]interface{}{
+			"id": id,
+		})
+		h.RespondWithError(w, r, apperrors.CodeTaskTemplateFailed, "Failed to process task template")
+	}
+}
@@ -2,155 +2,727 @@ package handlers
 
 import (
 	"errors"
+	"math"
 	"net/http"
+	"time"
 
+	"github.com/nurlyy/task_manager/internal/api/query"
 	"github.com/nurlyy/task_manager/internal/domain"
 	"github.com/nurlyy/task_manager/internal/service"
+	"github.com/nurlyy/task_manager/pkg/datefilter"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
 )
 
 // TaskHandler обрабатывает запросы, связанные с задачами
 type TaskHandler struct {
 	BaseHandler
-	taskService *service.TaskService
+	taskService       *service.TaskService
+	inboxService      *service.InboxService
+	recurrenceService *service.TaskRecurrenceService
 }
 
 // NewTaskHandler создает новый экземпляр TaskHandler
-func NewTaskHandler(base BaseHandler, taskService *service.TaskService) *TaskHandler {
+func NewTaskHandler(base BaseHandler, taskService *service.TaskService, inboxService *service.InboxService, recurrenceService *service.TaskRecurrenceService) *TaskHandler {
 	return &TaskHandler{
-		BaseHandler: base,
-		taskService: taskService,
+		BaseHandler:       base,
+		taskService:       taskService,
+		inboxService:      inboxService,
+		recurrenceService: recurrenceService,
 	}
 }
 
-// CreateTask обрабатывает запрос на создание новой задачи
+// CreateTask обрабатывает запрос на создание новой задачи.
+// Задача без проекта записывается во входящие пользователя
 func (h *TaskHandler) CreateTask(w http.ResponseWriter, r *http.Request) {
 	// Получаем ID пользователя из контекста
 	userID, err := h.GetUserIDFromContext(r)
 	if err != nil {
-		h.RespondWithError(w, r, http.StatusUnauthorized, "Unauthorized", "unauthorized")
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
 		return
 	}
 
 	var req domain.TaskCreateRequest
 	if err := h.ParseJSON(r, &req); err != nil {
 		h.Logger.Error("Failed to parse create task request", err)
-		h.RespondWithError(w, r, http.StatusBadRequest, "Invalid request format", "invalid_format")
+		h.RespondWithError(w, r, apperrors.CodeInvalidFormat, "Invalid request format")
 		return
 	}
 
+	if req.ProjectID == "" {
+		h.captureToInbox(w, r, req, userID)
+		return
+	}
+
+	// Валидация запроса
+	if validationErrors, err := h.ValidateRequest(r, req); err != nil {
+		h.Logger.Error("Request validation error", err)
+		h.RespondWithError(w, r, apperrors.CodeInternalError, "Validation failed")
+		return
+	} else if len(validationErrors) > 0 {
+		h.RespondWithValidationErrors(w, r, validationErrors)
+		return
+	}
+
+	// Создаем задачу
+	task, err := h.taskService.Create(r.Context(), req, userID)
+	if err != nil {
+		if errors.Is(err, service.ErrProjectNotFound) {
+			h.RespondWithError(w, r, apperrors.CodeProjectNotFound, "Project not found")
+			return
+		}
+		if h.respondWithTaskValidationError(w, r, err) {
+			return
+		}
+		if errors.Is(err, service.ErrProjectArchived) {
+			h.RespondWithError(w, r, apperrors.CodeProjectArchived, "Project is archived and read-only")
+			return
+		}
+		if errors.Is(err, service.ErrInvalidAssignee) {
+			h.RespondWithError(w, r, apperrors.CodeInvalidAssignee, "Assignee must be a member of the project")
+			return
+		}
+		if errors.Is(err, service.ErrInvalidParentTask) {
+			h.RespondWithError(w, r, apperrors.CodeInvalidParent, "Parent task must be an open task of the same project")
+			return
+		}
+		h.Logger.Error("Failed to create task", err)
+		h.RespondWithError(w, r, apperrors.CodeCreationFailed, "Failed to create task")
+		return
+	}
+
+	h.RespondWithSuccess(w, r, task)
+}
+
+// captureToInbox записывает задачу без проекта во входящие пользователя
+func (h *TaskHandler) captureToInbox(w http.ResponseWriter, r *http.Request, task domain.TaskCreateRequest, userID string) {
+	req := domain.InboxCaptureRequest{
+		Title:       task.Title,
+		Description: task.Description,
+		DueDate:     task.DueDate,
+	}
+	if task.Priority != "" {
+		req.Priority = &task.Priority
+	}
+
 	// Валидация запроса
-	if validationErrors, err := h.ValidateRequest(req); err != nil {
+	if validationErrors, err := h.ValidateRequest(r, req); err != nil {
 		h.Logger.Error("Request validation error", err)
-		h.RespondWithError(w, r, http.StatusInternalServerError, "Validation failed", "validation_error")
+		h.RespondWithError(w, r, apperrors.CodeInternalError, "Validation failed")
+		return
+	} else if len(validationErrors) > 0 {
+		h.RespondWithValidationErrors(w, r, validationErrors)
+		return
+	}
+
+	item, err := h.inboxService.Capture(r.Context(), req, userID)
+	if err != nil {
+		h.Logger.Error("Failed to capture task to inbox", err)
+		h.RespondWithError(w, r, apperrors.CodeInboxFailed, "Failed to process inbox request")
+		return
+	}
+
+	h.RespondWithSuccess(w, r, item)
+}
+
+// LogTime добавляет запись о затраченном времени на задачу
+func (h *TaskHandler) LogTime(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID задачи из URL
+	taskID := h.GetURLParam(r, "id")
+	if taskID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Task ID is required")
+		return
+	}
+
+	var req domain.LogTimeRequest
+	if err := h.ParseJSON(r, &req); err != nil {
+		h.Logger.Error("Failed to parse log time request", err)
+		h.RespondWithError(w, r, apperrors.CodeInvalidFormat, "Invalid request format")
+		return
+	}
+
+	// Валидация запроса
+	if validationErrors, err := h.ValidateRequest(r, req); err != nil {
+		h.Logger.Error("Request validation error", err)
+		h.RespondWithError(w, r, apperrors.CodeInternalError, "Validation failed")
+		return
+	} else if len(validationErrors) > 0 {
+		h.RespondWithValidationErrors(w, r, validationErrors)
+		return
+	}
+
+	// Логируем затраченное время
+	if err := h.taskService.LogTime(r.Context(), taskID, req, userID); err != nil {
+		if errors.Is(err, service.ErrTaskNotFound) {
+			h.RespondWithError(w, r, apperrors.CodeTaskNotFound, "Task not found")
+			return
+		}
+		if errors.Is(err, service.ErrTaskAccessDenied) {
+			h.RespondWithError(w, r, apperrors.CodeAccessDenied, "Access denied to the task")
+			return
+		}
+		if errors.Is(err, service.ErrProjectArchived) {
+			h.RespondWithError(w, r, apperrors.CodeProjectArchived, "Project is archived and read-only")
+			return
+		}
+		h.Logger.Error("Failed to log time", err, map[string]interface{}{
+			"task_id": taskID,
+		})
+		h.RespondWithError(w, r, apperrors.CodeLogTimeFailed, "Failed to log time")
+		return
+	}
+
+	h.RespondWithSuccess(w, r, map[string]bool{"success": true})
+}
+
+// GetTimeLogs возвращает записи о затраченном времени на задачу
+func (h *TaskHandler) GetTimeLogs(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID задачи из URL
+	taskID := h.GetURLParam(r, "id")
+	if taskID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Task ID is required")
+		return
+	}
+
+	// Получаем записи о затраченном времени
+	timeLogs, err := h.taskService.GetTimeLogs(r.Context(), taskID, userID)
+	if err != nil {
+		if errors.Is(err, service.ErrTaskNotFound) {
+			h.RespondWithError(w, r, apperrors.CodeTaskNotFound, "Task not found")
+			return
+		}
+		if errors.Is(err, service.ErrTaskAccessDenied) {
+			h.RespondWithError(w, r, apperrors.CodeAccessDenied, "Access denied to the task")
+			return
+		}
+		h.Logger.Error("Failed to get time logs", err, map[string]interface{}{
+			"task_id": taskID,
+		})
+		h.RespondWithError(w, r, apperrors.CodeTimeLogsFetchFailed, "Failed to get time logs")
+		return
+	}
+
+	h.RespondWithSuccess(w, r, timeLogs)
+}
+
+// CreateSubtask создает подзадачу задачи
+func (h *TaskHandler) CreateSubtask(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID родительской задачи из URL
+	parentID := h.GetURLParam(r, "id")
+	if parentID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Task ID is required")
+		return
+	}
+
+	var req domain.TaskCreateRequest
+	if err := h.ParseJSON(r, &req); err != nil {
+		h.Logger.Error("Failed to parse create subtask request", err)
+		h.RespondWithError(w, r, apperrors.CodeInvalidFormat, "Invalid request format")
+		return
+	}
+
+	// Проект и родитель подзадачи берутся из родительской задачи в сервисе,
+	// до этого проект заполняется ID родителя, чтобы запрос прошел проверку
+	req.ProjectID = parentID
+	req.ParentID = nil
+
+	// Валидация запроса
+	if validationErrors, err := h.ValidateRequest(r, req); err != nil {
+		h.Logger.Error("Request validation error", err)
+		h.RespondWithError(w, r, apperrors.CodeInternalError, "Validation failed")
+		return
+	} else if len(validationErrors) > 0 {
+		h.RespondWithValidationErrors(w, r, validationErrors)
+		return
+	}
+
+	task, err := h.taskService.CreateSubtask(r.Context(), parentID, req, userID)
+	if err != nil {
+		if h.respondWithTaskValidationError(w, r, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, service.ErrTaskNotFound):
+			h.RespondWithError(w, r, apperrors.CodeTaskNotFound, "Task not found")
+		case errors.Is(err, service.ErrTaskAccessDenied), errors.Is(err, service.ErrProjectNotFound):
+			h.RespondWithError(w, r, apperrors.CodeAccessDenied, "Access denied to the task")
+		case errors.Is(err, service.ErrProjectArchived):
+			h.RespondWithError(w, r, apperrors.CodeProjectArchived, "Project is archived and read-only")
+		case errors.Is(err, service.ErrInvalidAssignee):
+			h.RespondWithError(w, r, apperrors.CodeInvalidAssignee, "Assignee must be a member of the project")
+		case errors.Is(err, service.ErrInvalidParentTask):
+			h.RespondWithError(w, r, apperrors.CodeInvalidParent, "Parent task must be an open task of the same project")
+		default:
+			h.Logger.Error("Failed to create subtask", err, map[string]interface{}{
+				"parent_id": parentID,
+			})
+			h.RespondWithError(w, r, apperrors.CodeCreationFailed, "Failed to create subtask")
+		}
+		return
+	}
+
+	h.RespondWithSuccess(w, r, task)
+}
+
+// ListSubtasks возвращает подзадачи задачи со сводкой по их выполнению
+func (h *TaskHandler) ListSubtasks(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID родительской задачи из URL
+	parentID := h.GetURLParam(r, "id")
+	if parentID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Task ID is required")
+		return
+	}
+
+	subtasks, err := h.taskService.ListSubtasks(r.Context(), parentID, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrTaskNotFound):
+			h.RespondWithError(w, r, apperrors.CodeTaskNotFound, "Task not found")
+		case errors.Is(err, service.ErrTaskAccessDenied):
+			h.RespondWithError(w, r, apperrors.CodeAccessDenied, "Access denied to the task")
+		default:
+			h.Logger.Error("Failed to list subtasks", err, map[string]interface{}{
+				"parent_id": parentID,
+			})
+			h.RespondWithError(w, r, apperrors.CodeTasksFetchFailed, "Failed to get subtasks")
+		}
+		return
+	}
+
+	h.RespondWithSuccess(w, r, subtasks)
+}
+
+// GetTimeReconciliation сравнивает затраченное время задачи с суммой ее записей о времени
+func (h *TaskHandler) GetTimeReconciliation(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID задачи из URL
+	taskID := h.GetURLParam(r, "id")
+	if taskID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Task ID is required")
+		return
+	}
+
+	reconciliation, err := h.taskService.GetTimeReconciliation(r.Context(), taskID, userID)
+	if err != nil {
+		h.handleTimeRecalculationError(w, r, err, taskID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, reconciliation)
+}
+
+// RecalculateSpentHours пересчитывает затраченное время задачи по записям о времени
+func (h *TaskHandler) RecalculateSpentHours(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID задачи из URL
+	taskID := h.GetURLParam(r, "id")
+	if taskID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Task ID is required")
+		return
+	}
+
+	reconciliation, err := h.taskService.RecalculateSpentHours(r.Context(), taskID, userID)
+	if err != nil {
+		h.handleTimeRecalculationError(w, r, err, taskID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, reconciliation)
+}
+
+// RecalculateProjectSpentHours пересчитывает затраченное время задач проекта по записям о времени
+func (h *TaskHandler) RecalculateProjectSpentHours(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID проекта из URL
+	projectID := h.GetURLParam(r, "id")
+	if projectID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID is required")
+		return
+	}
+
+	result, err := h.taskService.RecalculateProjectSpentHours(r.Context(), projectID, userID)
+	if err != nil {
+		h.handleTimeRecalculationError(w, r, err, projectID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, result)
+}
+
+// handleTimeRecalculationError преобразует ошибки сверки затраченного времени в HTTP-ответы
+func (h *TaskHandler) handleTimeRecalculationError(w http.ResponseWriter, r *http.Request, err error, id string) {
+	switch {
+	case errors.Is(err, service.ErrTaskNotFound):
+		h.RespondWithError(w, r, apperrors.CodeTaskNotFound, "Task not found")
+	case errors.Is(err, service.ErrProjectNotFound):
+		h.RespondWithError(w, r, apperrors.CodeProjectNotFound, "Project not found")
+	case errors.Is(err, service.ErrTaskAccessDenied):
+		h.RespondWithError(w, r, apperrors.CodeAccessDenied, "Access denied to the task")
+	case errors.Is(err, service.ErrInsufficientRights):
+		h.RespondWithError(w, r, apperrors.CodeInsufficientRights, "Insufficient rights to recalculate spent hours")
+	default:
+		h.Logger.Error("Failed to reconcile spent hours", err, map[string]interface{}{
+			"id": id,
+		})
+		h.RespondWithError(w, r, apperrors.CodeTimeRecalculationFailed, "Failed to reconcile spent hours")
+	}
+}
+
+// MoveTask перемещает задачу при ручной сортировке
+func (h *TaskHandler) MoveTask(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID задачи из URL
+	taskID := h.GetURLParam(r, "id")
+	if taskID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Task ID is required")
+		return
+	}
+
+	// Парсим тело запроса
+	var req domain.TaskMoveRequest
+	if err := h.ParseJSON(r, &req); err != nil {
+		h.Logger.Error("Failed to parse move task request", err)
+		h.RespondWithError(w, r, apperrors.CodeInvalidFormat, "Invalid request format")
+		return
+	}
+
+	// Валидация запроса
+	if validationErrors, err := h.ValidateRequest(r, req); err != nil {
+		h.Logger.Error("Request validation error", err)
+		h.RespondWithError(w, r, apperrors.CodeInternalError, "Validation failed")
+		return
+	} else if len(validationErrors) > 0 {
+		h.RespondWithValidationErrors(w, r, validationErrors)
+		return
+	}
+
+	task, err := h.taskService.Move(r.Context(), taskID, req, userID)
+	if err != nil {
+		if errors.Is(err, service.ErrTaskNotFound) {
+			h.RespondWithError(w, r, apperrors.CodeTaskNotFound, "Task not found")
+			return
+		}
+		if errors.Is(err, service.ErrTaskAccessDenied) {
+			h.RespondWithError(w, r, apperrors.CodeAccessDenied, "Access denied to the task")
+			return
+		}
+		if errors.Is(err, service.ErrInsufficientRights) {
+			h.RespondWithError(w, r, apperrors.CodeInsufficientRights, "Insufficient rights to move task")
+			return
+		}
+		if errors.Is(err, service.ErrProjectArchived) {
+			h.RespondWithError(w, r, apperrors.CodeProjectArchived, "Project is archived and read-only")
+			return
+		}
+		if errors.Is(err, service.ErrInvalidTaskMove) {
+			h.RespondWithError(w, r, apperrors.CodeInvalidMove, "Specify exactly one of after_id, before_id or position; the target must be another task of the same project")
+			return
+		}
+		h.Logger.Error("Failed to move task", err, map[string]interface{}{
+			"id": taskID,
+		})
+		h.RespondWithError(w, r, apperrors.CodeMoveFailed, "Failed to move task")
+		return
+	}
+
+	h.RespondWithSuccess(w, r, task)
+}
+
+// MoveTaskToProject переносит задачу в другой проект
+func (h *TaskHandler) MoveTaskToProject(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID задачи из URL
+	taskID := h.GetURLParam(r, "id")
+	if taskID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Task ID is required")
+		return
+	}
+
+	// Парсим тело запроса
+	var req domain.TaskProjectMoveRequest
+	if err := h.ParseJSON(r, &req); err != nil {
+		h.Logger.Error("Failed to parse task project move request", err)
+		h.RespondWithError(w, r, apperrors.CodeInvalidFormat, "Invalid request format")
+		return
+	}
+
+	// Валидация запроса
+	if validationErrors, err := h.ValidateRequest(r, req); err != nil {
+		h.Logger.Error("Request validation error", err)
+		h.RespondWithError(w, r, apperrors.CodeInternalError, "Validation failed")
+		return
+	} else if len(validationErrors) > 0 {
+		h.RespondWithValidationErrors(w, r, validationErrors)
+		return
+	}
+
+	task, err := h.taskService.MoveToProject(r.Context(), taskID, req, userID)
+	if err != nil {
+		if h.respondWithTaskValidationError(w, r, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, service.ErrTaskNotFound):
+			h.RespondWithError(w, r, apperrors.CodeTaskNotFound, "Task not found")
+		case errors.Is(err, service.ErrTaskAccessDenied):
+			h.RespondWithError(w, r, apperrors.CodeAccessDenied, "Access denied to the task")
+		case errors.Is(err, service.ErrProjectNotFound):
+			h.RespondWithError(w, r, apperrors.CodeProjectNotFound, "Target project not found")
+		case errors.Is(err, service.ErrInsufficientRights):
+			h.RespondWithError(w, r, apperrors.CodeInsufficientRights, "Insufficient rights to move task between projects")
+		case errors.Is(err, service.ErrProjectArchived):
+			h.RespondWithError(w, r, apperrors.CodeProjectArchived, "Project is archived and read-only")
+		case errors.Is(err, service.ErrTaskSameProject):
+			h.RespondWithError(w, r, apperrors.CodeSameProject, "Task already belongs to the target project")
+		default:
+			h.Logger.Error("Failed to move task to project", err, map[string]interface{}{
+				"id": taskID,
+			})
+			h.RespondWithError(w, r, apperrors.CodeMoveFailed, "Failed to move task to project")
+		}
+		return
+	}
+
+	h.RespondWithSuccess(w, r, task)
+}
+
+// ReprioritizeProjectTasks пересчитывает оценки приоритета задач проекта
+func (h *TaskHandler) ReprioritizeProjectTasks(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID проекта из URL
+	projectID := h.GetURLParam(r, "id")
+	if projectID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID is required")
+		return
+	}
+
+	// Тело запроса необязательно
+	var req domain.TaskReprioritizeRequest
+	if r.ContentLength > 0 {
+		if err := h.ParseJSON(r, &req); err != nil {
+			h.Logger.Error("Failed to parse reprioritize request", err)
+			h.RespondWithError(w, r, apperrors.CodeInvalidFormat, "Invalid request format")
+			return
+		}
+	}
+
+	result, err := h.taskService.Reprioritize(r.Context(), projectID, req)
+	if err != nil {
+		if errors.Is(err, service.ErrProjectNotFound) {
+			h.RespondWithError(w, r, apperrors.CodeProjectNotFound, "Project not found")
+			return
+		}
+		if errors.Is(err, service.ErrInsufficientRights) {
+			h.RespondWithError(w, r, apperrors.CodeInsufficientRights, "Insufficient rights to reprioritize tasks")
+			return
+		}
+		if errors.Is(err, service.ErrProjectArchived) {
+			h.RespondWithError(w, r, apperrors.CodeProjectArchived, "Project is archived and read-only")
+			return
+		}
+		h.Logger.Error("Failed to reprioritize project tasks", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		h.RespondWithError(w, r, apperrors.CodeReprioritizeFailed, "Failed to reprioritize tasks")
+		return
+	}
+
+	h.RespondWithSuccess(w, r, result)
+}
+
+// GetBacklogAgeReport возвращает задачи бэклога проекта, сгруппированные по возрасту
+func (h *TaskHandler) GetBacklogAgeReport(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID проекта из URL
+	projectID := h.GetURLParam(r, "id")
+	if projectID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID is required")
+		return
+	}
+
+	report, err := h.taskService.GetBacklogAgeReport(r.Context(), projectID)
+	if err != nil {
+		if errors.Is(err, service.ErrProjectNotFound) {
+			h.RespondWithError(w, r, apperrors.CodeProjectNotFound, "Project not found")
+			return
+		}
+		h.Logger.Error("Failed to get backlog age report", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		h.RespondWithError(w, r, apperrors.CodeBacklogReportFailed, "Failed to get backlog age report")
+		return
+	}
+
+	h.RespondWithSuccess(w, r, report)
+}
+
+// GetEffortSplit возвращает распределение оценки задачи между исполнителями
+func (h *TaskHandler) GetEffortSplit(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
 		return
-	} else if len(validationErrors) > 0 {
-		h.RespondWithValidationErrors(w, r, validationErrors)
+	}
+
+	// Получаем ID задачи из URL
+	taskID := h.GetURLParam(r, "id")
+	if taskID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Task ID is required")
 		return
 	}
 
-	// Создаем задачу
-	task, err := h.taskService.Create(r.Context(), req, userID)
+	split, err := h.taskService.GetEffortSplit(r.Context(), taskID, userID)
 	if err != nil {
-		if errors.Is(err, service.ErrProjectNotFound) {
-			h.RespondWithError(w, r, http.StatusNotFound, "Project not found", "project_not_found")
-			return
-		}
-		h.Logger.Error("Failed to create task", err)
-		h.RespondWithError(w, r, http.StatusInternalServerError, "Failed to create task", "creation_failed")
+		h.handleEffortSplitError(w, r, err, taskID)
 		return
 	}
 
-	h.RespondWithSuccess(w, r, task)
+	h.RespondWithSuccess(w, r, split)
 }
 
-// LogTime добавляет запись о затраченном времени на задачу
-func (h *TaskHandler) LogTime(w http.ResponseWriter, r *http.Request) {
+// UpdateEffortSplit распределяет оценку задачи между исполнителями
+func (h *TaskHandler) UpdateEffortSplit(w http.ResponseWriter, r *http.Request) {
 	// Получаем ID пользователя из контекста
 	userID, err := h.GetUserIDFromContext(r)
 	if err != nil {
-		h.RespondWithError(w, r, http.StatusUnauthorized, "Unauthorized", "unauthorized")
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
 		return
 	}
 
 	// Получаем ID задачи из URL
 	taskID := h.GetURLParam(r, "id")
 	if taskID == "" {
-		h.RespondWithError(w, r, http.StatusBadRequest, "Task ID is required", "missing_id")
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Task ID is required")
 		return
 	}
 
-	var req domain.LogTimeRequest
+	// Парсим тело запроса
+	var req domain.TaskEffortSplitRequest
 	if err := h.ParseJSON(r, &req); err != nil {
-		h.Logger.Error("Failed to parse log time request", err)
-		h.RespondWithError(w, r, http.StatusBadRequest, "Invalid request format", "invalid_format")
+		h.Logger.Error("Failed to parse effort split request", err)
+		h.RespondWithError(w, r, apperrors.CodeInvalidFormat, "Invalid request format")
 		return
 	}
 
 	// Валидация запроса
-	if validationErrors, err := h.ValidateRequest(req); err != nil {
+	if validationErrors, err := h.ValidateRequest(r, req); err != nil {
 		h.Logger.Error("Request validation error", err)
-		h.RespondWithError(w, r, http.StatusInternalServerError, "Validation failed", "validation_error")
+		h.RespondWithError(w, r, apperrors.CodeInternalError, "Validation failed")
 		return
 	} else if len(validationErrors) > 0 {
 		h.RespondWithValidationErrors(w, r, validationErrors)
 		return
 	}
 
-	// Логируем затраченное время
-	if err := h.taskService.LogTime(r.Context(), taskID, req, userID); err != nil {
-		if errors.Is(err, service.ErrTaskNotFound) {
-			h.RespondWithError(w, r, http.StatusNotFound, "Task not found", "task_not_found")
-			return
-		}
-		if errors.Is(err, service.ErrTaskAccessDenied) {
-			h.RespondWithError(w, r, http.StatusForbidden, "Access denied to the task", "access_denied")
-			return
-		}
-		h.Logger.Error("Failed to log time", err, map[string]interface{}{
-			"task_id": taskID,
-		})
-		h.RespondWithError(w, r, http.StatusInternalServerError, "Failed to log time", "log_time_failed")
+	split, err := h.taskService.UpdateEffortSplit(r.Context(), taskID, req, userID)
+	if err != nil {
+		h.handleEffortSplitError(w, r, err, taskID)
 		return
 	}
 
-	h.RespondWithSuccess(w, r, map[string]bool{"success": true})
+	h.RespondWithSuccess(w, r, split)
 }
 
-// GetTimeLogs возвращает записи о затраченном времени на задачу
-func (h *TaskHandler) GetTimeLogs(w http.ResponseWriter, r *http.Request) {
+// ResetEffortSplit сбрасывает распределение оценки задачи
+func (h *TaskHandler) ResetEffortSplit(w http.ResponseWriter, r *http.Request) {
 	// Получаем ID пользователя из контекста
 	userID, err := h.GetUserIDFromContext(r)
 	if err != nil {
-		h.RespondWithError(w, r, http.StatusUnauthorized, "Unauthorized", "unauthorized")
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
 		return
 	}
 
 	// Получаем ID задачи из URL
 	taskID := h.GetURLParam(r, "id")
 	if taskID == "" {
-		h.RespondWithError(w, r, http.StatusBadRequest, "Task ID is required", "missing_id")
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Task ID is required")
 		return
 	}
 
-	// Получаем записи о затраченном времени
-	timeLogs, err := h.taskService.GetTimeLogs(r.Context(), taskID, userID)
+	split, err := h.taskService.ResetEffortSplit(r.Context(), taskID, userID)
 	if err != nil {
-		if errors.Is(err, service.ErrTaskNotFound) {
-			h.RespondWithError(w, r, http.StatusNotFound, "Task not found", "task_not_found")
-			return
-		}
-		if errors.Is(err, service.ErrTaskAccessDenied) {
-			h.RespondWithError(w, r, http.StatusForbidden, "Access denied to the task", "access_denied")
-			return
-		}
-		h.Logger.Error("Failed to get time logs", err, map[string]interface{}{
-			"task_id": taskID,
-		})
-		h.RespondWithError(w, r, http.StatusInternalServerError, "Failed to get time logs", "time_logs_fetch_failed")
+		h.handleEffortSplitError(w, r, err, taskID)
 		return
 	}
 
-	h.RespondWithSuccess(w, r, timeLogs)
+	h.RespondWithSuccess(w, r, split)
+}
+
+// handleEffortSplitError преобразует ошибки распределения оценки в HTTP-ответы
+func (h *TaskHandler) handleEffortSplitError(w http.ResponseWriter, r *http.Request, err error, taskID string) {
+	switch {
+	case errors.Is(err, service.ErrTaskNotFound):
+		h.RespondWithError(w, r, apperrors.CodeTaskNotFound, "Task not found")
+	case errors.Is(err, service.ErrTaskAccessDenied):
+		h.RespondWithError(w, r, apperrors.CodeAccessDenied, "Access denied to the task")
+	case errors.Is(err, service.ErrInsufficientRights):
+		h.RespondWithError(w, r, apperrors.CodeInsufficientRights, "Insufficient rights to split task effort")
+	case errors.Is(err, service.ErrProjectArchived):
+		h.RespondWithError(w, r, apperrors.CodeProjectArchived, "Project is archived and read-only")
+	case errors.Is(err, service.ErrInvalidEffortSplit):
+		h.RespondWithError(w, r, apperrors.CodeInvalidEffortSplit, "Effort can only be split between task assignees, once per assignee")
+	default:
+		h.Logger.Error("Failed to process task effort split", err, map[string]interface{}{
+			"task_id": taskID,
+		})
+		h.RespondWithError(w, r, apperrors.CodeEffortSplitFailed, "Failed to process task effort split")
+	}
 }
 
 // GetTask возвращает информацию о задаче по ID
@@ -158,32 +730,50 @@ func (h *TaskHandler) GetTask(w http.ResponseWriter, r *http.Request) {
 	// Получаем ID пользователя из контекста
 	userID, err := h.GetUserIDFromContext(r)
 	if err != nil {
-		h.RespondWithError(w, r, http.StatusUnauthorized, "Unauthorized", "unauthorized")
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
 		return
 	}
 
 	// Получаем ID задачи из URL
 	taskID := h.GetURLParam(r, "id")
 	if taskID == "" {
-		h.RespondWithError(w, r, http.StatusBadRequest, "Task ID is required", "missing_id")
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Task ID is required")
+		return
+	}
+
+	// Состав ответа: include=comments,history,attachments,subtasks и страница комментариев
+	q := query.New(r)
+	opts := domain.DefaultTaskDetailOptions()
+	opts.Include = query.EnumList(q, "include",
+		domain.TaskIncludeComments,
+		domain.TaskIncludeHistory,
+		domain.TaskIncludeAttachments,
+		domain.TaskIncludeSubtasks,
+	)
+	opts.CommentsPage = q.Int("comments_page", opts.CommentsPage, 1, math.MaxInt32)
+	opts.CommentsPageSize = q.Int("comments_page_size", opts.CommentsPageSize, 1, domain.MaxTaskCommentsPageSize)
+	if order := query.Enum(q, "comments_order", query.SortAsc, query.SortDesc); order != nil {
+		opts.CommentsOrder = *order
+	}
+	if !h.CheckQuery(w, r, q) {
 		return
 	}
 
 	// Получаем данные задачи
-	task, err := h.taskService.GetByID(r.Context(), taskID, userID)
+	task, err := h.taskService.GetDetails(r.Context(), taskID, userID, opts)
 	if err != nil {
 		if errors.Is(err, service.ErrTaskNotFound) {
-			h.RespondWithError(w, r, http.StatusNotFound, "Task not found", "task_not_found")
+			h.RespondWithError(w, r, apperrors.CodeTaskNotFound, "Task not found")
 			return
 		}
 		if errors.Is(err, service.ErrTaskAccessDenied) {
-			h.RespondWithError(w, r, http.StatusForbidden, "Access denied to the task", "access_denied")
+			h.RespondWithError(w, r, apperrors.CodeAccessDenied, "Access denied to the task")
 			return
 		}
 		h.Logger.Error("Failed to get task", err, map[string]interface{}{
 			"id": taskID,
 		})
-		h.RespondWithError(w, r, http.StatusInternalServerError, "Failed to get task info", "task_fetch_failed")
+		h.RespondWithError(w, r, apperrors.CodeTaskFetchFailed, "Failed to get task info")
 		return
 	}
 
@@ -195,28 +785,28 @@ func (h *TaskHandler) UpdateTask(w http.ResponseWriter, r *http.Request) {
 	// Получаем ID пользователя из контекста
 	userID, err := h.GetUserIDFromContext(r)
 	if err != nil {
-		h.RespondWithError(w, r, http.StatusUnauthorized, "Unauthorized", "unauthorized")
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
 		return
 	}
 
 	// Получаем ID задачи из URL
 	taskID := h.GetURLParam(r, "id")
 	if taskID == "" {
-		h.RespondWithError(w, r, http.StatusBadRequest, "Task ID is required", "missing_id")
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Task ID is required")
 		return
 	}
 
 	var req domain.TaskUpdateRequest
 	if err := h.ParseJSON(r, &req); err != nil {
 		h.Logger.Error("Failed to parse update task request", err)
-		h.RespondWithError(w, r, http.StatusBadRequest, "Invalid request format", "invalid_format")
+		h.RespondWithError(w, r, apperrors.CodeInvalidFormat, "Invalid request format")
 		return
 	}
 
 	// Валидация запроса
-	if validationErrors, err := h.ValidateRequest(req); err != nil {
+	if validationErrors, err := h.ValidateRequest(r, req); err != nil {
 		h.Logger.Error("Request validation error", err)
-		h.RespondWithError(w, r, http.StatusInternalServerError, "Validation failed", "validation_error")
+		h.RespondWithError(w, r, apperrors.CodeInternalError, "Validation failed")
 		return
 	} else if len(validationErrors) > 0 {
 		h.RespondWithValidationErrors(w, r, validationErrors)
@@ -227,21 +817,36 @@ func (h *TaskHandler) UpdateTask(w http.ResponseWriter, r *http.Request) {
 	task, err := h.taskService.Update(r.Context(), taskID, req, userID)
 	if err != nil {
 		if errors.Is(err, service.ErrTaskNotFound) {
-			h.RespondWithError(w, r, http.StatusNotFound, "Task not found", "task_not_found")
+			h.RespondWithError(w, r, apperrors.CodeTaskNotFound, "Task not found")
 			return
 		}
 		if errors.Is(err, service.ErrTaskAccessDenied) {
-			h.RespondWithError(w, r, http.StatusForbidden, "Access denied to the task", "access_denied")
+			h.RespondWithError(w, r, apperrors.CodeAccessDenied, "Access denied to the task")
 			return
 		}
 		if errors.Is(err, service.ErrInsufficientRights) {
-			h.RespondWithError(w, r, http.StatusForbidden, "Insufficient rights to update task", "insufficient_rights")
+			h.RespondWithError(w, r, apperrors.CodeInsufficientRights, "Insufficient rights to update task")
+			return
+		}
+		if errors.Is(err, service.ErrProjectArchived) {
+			h.RespondWithError(w, r, apperrors.CodeProjectArchived, "Project is archived and read-only")
+			return
+		}
+		if h.respondWithTaskValidationError(w, r, err) {
+			return
+		}
+		if errors.Is(err, service.ErrInvalidAssignee) {
+			h.RespondWithError(w, r, apperrors.CodeInvalidAssignee, "Assignee must be a member of the project")
+			return
+		}
+		if errors.Is(err, service.ErrOpenSubtasks) {
+			h.RespondWithError(w, r, apperrors.CodeOpenSubtasks, "Task cannot be completed while it has open subtasks")
 			return
 		}
 		h.Logger.Error("Failed to update task", err, map[string]interface{}{
 			"id": taskID,
 		})
-		h.RespondWithError(w, r, http.StatusInternalServerError, "Failed to update task", "update_failed")
+		h.RespondWithError(w, r, apperrors.CodeUpdateFailed, "Failed to update task")
 		return
 	}
 
@@ -253,31 +858,35 @@ func (h *TaskHandler) DeleteTask(w http.ResponseWriter, r *http.Request) {
 	// Получаем ID пользователя из контекста
 	userID, err := h.GetUserIDFromContext(r)
 	if err != nil {
-		h.RespondWithError(w, r, http.StatusUnauthorized, "Unauthorized", "unauthorized")
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
 		return
 	}
 
 	// Получаем ID задачи из URL
 	taskID := h.GetURLParam(r, "id")
 	if taskID == "" {
-		h.RespondWithError(w, r, http.StatusBadRequest, "Task ID is required", "missing_id")
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Task ID is required")
 		return
 	}
 
 	// Удаляем задачу
 	if err := h.taskService.Delete(r.Context(), taskID, userID); err != nil {
 		if errors.Is(err, service.ErrTaskNotFound) {
-			h.RespondWithError(w, r, http.StatusNotFound, "Task not found", "task_not_found")
+			h.RespondWithError(w, r, apperrors.CodeTaskNotFound, "Task not found")
 			return
 		}
 		if errors.Is(err, service.ErrInsufficientRights) {
-			h.RespondWithError(w, r, http.StatusForbidden, "Insufficient rights to delete task", "insufficient_rights")
+			h.RespondWithError(w, r, apperrors.CodeInsufficientRights, "Insufficient rights to delete task")
+			return
+		}
+		if errors.Is(err, service.ErrProjectArchived) {
+			h.RespondWithError(w, r, apperrors.CodeProjectArchived, "Project is archived and read-only")
 			return
 		}
 		h.Logger.Error("Failed to delete task", err, map[string]interface{}{
 			"id": taskID,
 		})
-		h.RespondWithError(w, r, http.StatusInternalServerError, "Failed to delete task", "delete_failed")
+		h.RespondWithError(w, r, apperrors.CodeDeleteFailed, "Failed to delete task")
 		return
 	}
 
@@ -289,78 +898,146 @@ func (h *TaskHandler) ListTasks(w http.ResponseWriter, r *http.Request) {
 	// Получаем ID пользователя из контекста
 	userID, err := h.GetUserIDFromContext(r)
 	if err != nil {
-		h.RespondWithError(w, r, http.StatusUnauthorized, "Unauthorized", "unauthorized")
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
 		return
 	}
 
-	// Параметры пагинации
-	page, pageSize := h.GetPaginationParams(r)
+	// Создаем фильтр и параметры пагинации
+	q := query.New(r)
+	filter := taskFilterFromQuery(q, userID)
+	page, ok := h.ParseQuery(w, r, q)
+	if !ok {
+		return
+	}
+	filter.Page = page.Page
+	filter.PageSize = page.PageSize
 
-	// Создаем фильтр
-	filter := domain.TaskFilterOptions{
-		Page:     page,
-		PageSize: pageSize,
+	// Получаем список задач
+	result, err := h.taskService.List(r.Context(), filter, userID, page)
+	if err != nil {
+		h.Logger.Error("Failed to list tasks", err)
+		h.RespondWithError(w, r, apperrors.CodeTasksFetchFailed, "Failed to get tasks")
+		return
 	}
 
-	// Фильтр по проекту
-	if projectID := r.URL.Query().Get("project_id"); projectID != "" {
-		filter.ProjectID = &projectID
+	h.RespondWithPagination(w, r, result.Items, result)
+}
+
+// ExportTasks выгружает все задачи, подходящие под фильтры списка, без пагинации.
+// Ответ записывается потоком по мере загрузки задач
+func (h *TaskHandler) ExportTasks(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
 	}
 
-	// Фильтр по статусу
-	if status := r.URL.Query().Get("status"); status != "" {
-		taskStatus := domain.TaskStatus(status)
-		filter.Status = &taskStatus
+	q := query.New(r)
+	filter := taskFilterFromQuery(q, userID)
+	if !h.CheckQuery(w, r, q) {
+		return
 	}
 
-	// Фильтр по приоритету
-	if priority := r.URL.Query().Get("priority"); priority != "" {
-		taskPriority := domain.TaskPriority(priority)
-		filter.Priority = &taskPriority
+	// Заголовок ответа отправляется вместе с первой задачей, поэтому ошибка
+	// до ее получения (например, отказ в доступе к проекту) еще возвращается обычным ответом
+	var stream *JSONArrayStream
+	err = h.taskService.Export(r.Context(), filter, userID, func(task domain.TaskResponse) error {
+		if stream == nil {
+			stream = h.NewJSONArrayStream(w)
+		}
+		return stream.Write(task)
+	})
+	if err != nil {
+		if stream != nil {
+			// Ответ уже начат: обрываем его без закрывающих скобок, чтобы клиент получил некорректный JSON
+			h.Logger.Error("Failed to stream tasks export", err)
+			return
+		}
+		if errors.Is(err, service.ErrProjectNotFound) {
+			h.RespondWithError(w, r, apperrors.CodeProjectNotFound, "Project not found")
+			return
+		}
+		h.Logger.Error("Failed to export tasks", err)
+		h.RespondWithError(w, r, apperrors.CodeTasksExportFailed, "Failed to export tasks")
+		return
 	}
 
-	// Фильтр по исполнителю
-	if assigneeID := r.URL.Query().Get("assignee_id"); assigneeID != "" {
-		filter.AssigneeID = &assigneeID
+	if stream == nil {
+		stream = h.NewJSONArrayStream(w)
 	}
+	if err := stream.Close(); err != nil {
+		h.Logger.Error("Failed to finish tasks export", err)
+	}
+}
 
-	// Фильтр только мои задачи
-	if r.URL.Query().Get("my_tasks") == "true" {
-		filter.AssigneeID = &userID
+// maxTaskSortKeys ограничивает число полей сортировки списка задач
+const maxTaskSortKeys = 5
+
+// Допустимые значения параметров списка задач
+var (
+	taskStatuses = []domain.TaskStatus{
+		domain.TaskStatusNew, domain.TaskStatusInProgress, domain.TaskStatusOnHold,
+		domain.TaskStatusReview, domain.TaskStatusCompleted, domain.TaskStatusCancelled,
+	}
+	taskPriorities = []domain.TaskPriority{
+		domain.TaskPriorityLow, domain.TaskPriorityMedium, domain.TaskPriorityHigh, domain.TaskPriorityCritical,
 	}
+	taskDateFilterFields = []datefilter.Field{
+		datefilter.FieldDue, datefilter.FieldCreated, datefilter.FieldUpdated, datefilter.FieldCompleted,
+	}
+	taskSortFields = []string{
+		"id", "title", "status", "priority", "assignee_id", "created_by", "due_date",
+		"created_at", "updated_at", "completed_at", "estimated_hours", "spent_hours", "priority_score", "rank",
+	}
+)
 
-	// Фильтр задачи, созданные пользователем
-	if r.URL.Query().Get("created_by_me") == "true" {
-		filter.CreatedBy = &userID
+// taskFilterFromQuery разбирает фильтры списка задач из параметров запроса
+func taskFilterFromQuery(q *query.Parser, userID string) domain.TaskFilterOptions {
+	filter := domain.TaskFilterOptions{
+		ProjectID:  q.String("project_id"),
+		Statuses:   query.EnumList(q, "status", taskStatuses...),
+		Priorities: query.EnumList(q, "priority", taskPriorities...),
+		SearchText: q.String("search"),
+		Tags:       q.Values("tag"),
+		EpicID:     q.String("epic_id"),
+		SprintID:   q.String("sprint_id"),
+		ParentID:   q.String("parent_id"),
 	}
 
-	// Фильтр по поисковому тексту
-	if search := r.URL.Query().Get("search"); search != "" {
-		filter.SearchText = &search
+	// Фильтр по исполнителю, несколько ID через запятую означают любого из них
+	if assigneeIDs := q.List("assignee_id"); len(assigneeIDs) == 1 {
+		filter.AssigneeID = &assigneeIDs[0]
+	} else if len(assigneeIDs) > 1 {
+		filter.AssigneeIDs = assigneeIDs
 	}
 
-	// Фильтр по тегам
-	if tags := r.URL.Query()["tag"]; len(tags) > 0 {
-		filter.Tags = tags
+	// Фильтр только мои задачи
+	if q.Bool("my_tasks") {
+		filter.AssigneeID = &userID
 	}
 
-	// Настройка сортировки
-	if sortBy := r.URL.Query().Get("sort_by"); sortBy != "" {
-		filter.SortBy = &sortBy
-		if sortOrder := r.URL.Query().Get("sort_order"); sortOrder != "" {
-			filter.SortOrder = &sortOrder
-		}
+	// Фильтр задачи, созданные пользователем
+	if q.Bool("created_by_me") {
+		filter.CreatedBy = &userID
 	}
 
-	// Получаем список задач
-	result, err := h.taskService.List(r.Context(), filter, userID, page, pageSize)
-	if err != nil {
-		h.Logger.Error("Failed to list tasks", err)
-		h.RespondWithError(w, r, http.StatusInternalServerError, "Failed to get tasks", "tasks_fetch_failed")
-		return
+	// Периоды по датам: явные границы поле_from/поле_to и условия when, например when=due:<7d,updated:>30d.
+	// Если для поля заданы и границы, и условия, применяется их пересечение
+	windows := datefilter.Resolve(q.DateFilters("when"), time.Now())
+	for _, field := range taskDateFilterFields {
+		from, to := q.DateRange(string(field)+"_from", string(field)+"_to")
+		windows.Restrict(field, from, to)
 	}
+	filter.DueAfter, filter.DueBefore = windows[datefilter.FieldDue].After, windows[datefilter.FieldDue].Before
+	filter.CreatedAfter, filter.CreatedBefore = windows[datefilter.FieldCreated].After, windows[datefilter.FieldCreated].Before
+	filter.UpdatedAfter, filter.UpdatedBefore = windows[datefilter.FieldUpdated].After, windows[datefilter.FieldUpdated].Before
+	filter.CompletedAfter, filter.CompletedBefore = windows[datefilter.FieldCompleted].After, windows[datefilter.FieldCompleted].Before
 
-	h.RespondWithPagination(w, r, result.Items, result)
+	filter.SortBy, filter.SortOrder = q.Sort(taskSortFields...)
+	filter.Sort = q.SortKeys("sort", maxTaskSortKeys, taskSortFields...)
+
+	return filter
 }
 
 // UpdateTaskStatus обновляет статус задачи
@@ -368,14 +1045,14 @@ func (h *TaskHandler) UpdateTaskStatus(w http.ResponseWriter, r *http.Request) {
 	// Получаем ID пользователя из контекста
 	userID, err := h.GetUserIDFromContext(r)
 	if err != nil {
-		h.RespondWithError(w, r, http.StatusUnauthorized, "Unauthorized", "unauthorized")
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
 		return
 	}
 
 	// Получаем ID задачи из URL
 	taskID := h.GetURLParam(r, "id")
 	if taskID == "" {
-		h.RespondWithError(w, r, http.StatusBadRequest, "Task ID is required", "missing_id")
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Task ID is required")
 		return
 	}
 
@@ -385,14 +1062,14 @@ func (h *TaskHandler) UpdateTaskStatus(w http.ResponseWriter, r *http.Request) {
 	}
 	if err := h.ParseJSON(r, &req); err != nil {
 		h.Logger.Error("Failed to parse update status request", err)
-		h.RespondWithError(w, r, http.StatusBadRequest, "Invalid request format", "invalid_format")
+		h.RespondWithError(w, r, apperrors.CodeInvalidFormat, "Invalid request format")
 		return
 	}
 
 	// Валидация запроса
-	if validationErrors, err := h.ValidateRequest(req); err != nil {
+	if validationErrors, err := h.ValidateRequest(r, req); err != nil {
 		h.Logger.Error("Request validation error", err)
-		h.RespondWithError(w, r, http.StatusInternalServerError, "Validation failed", "validation_error")
+		h.RespondWithError(w, r, apperrors.CodeInternalError, "Validation failed")
 		return
 	} else if len(validationErrors) > 0 {
 		h.RespondWithValidationErrors(w, r, validationErrors)
@@ -403,25 +1080,36 @@ func (h *TaskHandler) UpdateTaskStatus(w http.ResponseWriter, r *http.Request) {
 	task, err := h.taskService.UpdateStatus(r.Context(), taskID, req.Status, userID)
 	if err != nil {
 		if errors.Is(err, service.ErrTaskNotFound) {
-			h.RespondWithError(w, r, http.StatusNotFound, "Task not found", "task_not_found")
+			h.RespondWithError(w, r, apperrors.CodeTaskNotFound, "Task not found")
 			return
 		}
 		if errors.Is(err, service.ErrTaskAccessDenied) {
-			h.RespondWithError(w, r, http.StatusForbidden, "Access denied to the task", "access_denied")
+			h.RespondWithError(w, r, apperrors.CodeAccessDenied, "Access denied to the task")
 			return
 		}
 		if errors.Is(err, service.ErrInsufficientRights) {
-			h.RespondWithError(w, r, http.StatusForbidden, "Insufficient rights to update task status", "insufficient_rights")
+			h.RespondWithError(w, r, apperrors.CodeInsufficientRights, "Insufficient rights to update task status")
 			return
 		}
 		if errors.Is(err, service.ErrInvalidTaskStatus) {
-			h.RespondWithError(w, r, http.StatusBadRequest, "Invalid status transition", "invalid_status")
+			h.RespondWithError(w, r, apperrors.CodeInvalidStatus, "Invalid status transition")
+			return
+		}
+		if errors.Is(err, service.ErrOpenSubtasks) {
+			h.RespondWithError(w, r, apperrors.CodeOpenSubtasks, "Task cannot be completed while it has open subtasks")
+			return
+		}
+		if errors.Is(err, service.ErrProjectArchived) {
+			h.RespondWithError(w, r, apperrors.CodeProjectArchived, "Project is archived and read-only")
+			return
+		}
+		if h.respondWithTaskValidationError(w, r, err) {
 			return
 		}
 		h.Logger.Error("Failed to update task status", err, map[string]interface{}{
 			"id": taskID,
 		})
-		h.RespondWithError(w, r, http.StatusInternalServerError, "Failed to update task status", "status_update_failed")
+		h.RespondWithError(w, r, apperrors.CodeStatusUpdateFailed, "Failed to update task status")
 		return
 	}
 
@@ -433,14 +1121,14 @@ func (h *TaskHandler) UpdateTaskAssignee(w http.ResponseWriter, r *http.Request)
 	// Получаем ID пользователя из контекста
 	userID, err := h.GetUserIDFromContext(r)
 	if err != nil {
-		h.RespondWithError(w, r, http.StatusUnauthorized, "Unauthorized", "unauthorized")
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
 		return
 	}
 
 	// Получаем ID задачи из URL
 	taskID := h.GetURLParam(r, "id")
 	if taskID == "" {
-		h.RespondWithError(w, r, http.StatusBadRequest, "Task ID is required", "missing_id")
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Task ID is required")
 		return
 	}
 
@@ -450,14 +1138,14 @@ func (h *TaskHandler) UpdateTaskAssignee(w http.ResponseWriter, r *http.Request)
 	}
 	if err := h.ParseJSON(r, &req); err != nil {
 		h.Logger.Error("Failed to parse update assignee request", err)
-		h.RespondWithError(w, r, http.StatusBadRequest, "Invalid request format", "invalid_format")
+		h.RespondWithError(w, r, apperrors.CodeInvalidFormat, "Invalid request format")
 		return
 	}
 
 	// Валидация запроса
-	if validationErrors, err := h.ValidateRequest(req); err != nil {
+	if validationErrors, err := h.ValidateRequest(r, req); err != nil {
 		h.Logger.Error("Request validation error", err)
-		h.RespondWithError(w, r, http.StatusInternalServerError, "Validation failed", "validation_error")
+		h.RespondWithError(w, r, apperrors.CodeInternalError, "Validation failed")
 		return
 	} else if len(validationErrors) > 0 {
 		h.RespondWithValidationErrors(w, r, validationErrors)
@@ -468,23 +1156,255 @@ func (h *TaskHandler) UpdateTaskAssignee(w http.ResponseWriter, r *http.Request)
 	task, err := h.taskService.UpdateAssignee(r.Context(), taskID, req.AssigneeID, userID)
 	if err != nil {
 		if errors.Is(err, service.ErrTaskNotFound) {
-			h.RespondWithError(w, r, http.StatusNotFound, "Task not found", "task_not_found")
+			h.RespondWithError(w, r, apperrors.CodeTaskNotFound, "Task not found")
 			return
 		}
 		if errors.Is(err, service.ErrTaskAccessDenied) {
-			h.RespondWithError(w, r, http.StatusForbidden, "Access denied to the task", "access_denied")
+			h.RespondWithError(w, r, apperrors.CodeAccessDenied, "Access denied to the task")
 			return
 		}
 		if errors.Is(err, service.ErrInsufficientRights) {
-			h.RespondWithError(w, r, http.StatusForbidden, "Insufficient rights to update task assignee", "insufficient_rights")
+			h.RespondWithError(w, r, apperrors.CodeInsufficientRights, "Insufficient rights to update task assignee")
+			return
+		}
+		if errors.Is(err, service.ErrProjectArchived) {
+			h.RespondWithError(w, r, apperrors.CodeProjectArchived, "Project is archived and read-only")
+			return
+		}
+		if errors.Is(err, service.ErrInvalidAssignee) {
+			h.RespondWithError(w, r, apperrors.CodeInvalidAssignee, "Assignee must be a member of the project")
 			return
 		}
 		h.Logger.Error("Failed to update task assignee", err, map[string]interface{}{
 			"id": taskID,
 		})
-		h.RespondWithError(w, r, http.StatusInternalServerError, "Failed to update task assignee", "assignee_update_failed")
+		h.RespondWithError(w, r, apperrors.CodeAssigneeUpdateFailed, "Failed to update task assignee")
+		return
+	}
+
+	h.RespondWithSuccess(w, r, task)
+}
+
+// UpdateTaskAssignees заменяет список исполнителей задачи
+func (h *TaskHandler) UpdateTaskAssignees(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID задачи из URL
+	taskID := h.GetURLParam(r, "id")
+	if taskID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Task ID is required")
+		return
+	}
+
+	// Парсим тело запроса
+	var req domain.TaskAssigneesRequest
+	if err := h.ParseJSON(r, &req); err != nil {
+		h.Logger.Error("Failed to parse update assignees request", err)
+		h.RespondWithError(w, r, apperrors.CodeInvalidFormat, "Invalid request format")
+		return
+	}
+
+	// Валидация запроса
+	if validationErrors, err := h.ValidateRequest(r, req); err != nil {
+		h.Logger.Error("Request validation error", err)
+		h.RespondWithError(w, r, apperrors.CodeInternalError, "Validation failed")
+		return
+	} else if len(validationErrors) > 0 {
+		h.RespondWithValidationErrors(w, r, validationErrors)
+		return
+	}
+
+	// Обновляем исполнителей задачи
+	task, err := h.taskService.UpdateAssignees(r.Context(), taskID, req, userID)
+	if err != nil {
+		if errors.Is(err, service.ErrTaskNotFound) {
+			h.RespondWithError(w, r, apperrors.CodeTaskNotFound, "Task not found")
+			return
+		}
+		if errors.Is(err, service.ErrTaskAccessDenied) {
+			h.RespondWithError(w, r, apperrors.CodeAccessDenied, "Access denied to the task")
+			return
+		}
+		if errors.Is(err, service.ErrInsufficientRights) {
+			h.RespondWithError(w, r, apperrors.CodeInsufficientRights, "Insufficient rights to update task assignees")
+			return
+		}
+		if errors.Is(err, service.ErrProjectArchived) {
+			h.RespondWithError(w, r, apperrors.CodeProjectArchived, "Project is archived and read-only")
+			return
+		}
+		if errors.Is(err, service.ErrInvalidAssignee) {
+			h.RespondWithError(w, r, apperrors.CodeInvalidAssignee, "Assignee must be a member of the project")
+			return
+		}
+		h.Logger.Error("Failed to update task assignees", err, map[string]interface{}{
+			"id": taskID,
+		})
+		h.RespondWithError(w, r, apperrors.CodeAssigneeUpdateFailed, "Failed to update task assignees")
 		return
 	}
 
 	h.RespondWithSuccess(w, r, task)
 }
+
+// GetDescriptionLock возвращает текущую блокировку редактирования описания задачи
+func (h *TaskHandler) GetDescriptionLock(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID задачи из URL
+	taskID := h.GetURLParam(r, "id")
+	if taskID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Task ID is required")
+		return
+	}
+
+	lock, err := h.taskService.GetDescriptionLock(r.Context(), taskID, userID)
+	if err != nil {
+		h.handleLockError(w, r, taskID, lock, err)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, lock)
+}
+
+// AcquireDescriptionLock захватывает блокировку редактирования описания задачи
+func (h *TaskHandler) AcquireDescriptionLock(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID задачи из URL
+	taskID := h.GetURLParam(r, "id")
+	if taskID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Task ID is required")
+		return
+	}
+
+	// Тело запроса необязательно
+	var req domain.TaskEditLockRequest
+	if r.ContentLength > 0 {
+		if err := h.ParseJSON(r, &req); err != nil {
+			h.Logger.Error("Failed to parse acquire lock request", err)
+			h.RespondWithError(w, r, apperrors.CodeInvalidFormat, "Invalid request format")
+			return
+		}
+	}
+
+	lock, err := h.taskService.AcquireDescriptionLock(r.Context(), taskID, req.Force, userID)
+	if err != nil {
+		h.handleLockError(w, r, taskID, lock, err)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, lock)
+}
+
+// RenewDescriptionLock продлевает блокировку редактирования описания задачи
+func (h *TaskHandler) RenewDescriptionLock(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID задачи из URL
+	taskID := h.GetURLParam(r, "id")
+	if taskID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Task ID is required")
+		return
+	}
+
+	lock, err := h.taskService.RenewDescriptionLock(r.Context(), taskID, userID)
+	if err != nil {
+		h.handleLockError(w, r, taskID, lock, err)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, lock)
+}
+
+// ReleaseDescriptionLock освобождает блокировку редактирования описания задачи
+func (h *TaskHandler) ReleaseDescriptionLock(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID задачи из URL
+	taskID := h.GetURLParam(r, "id")
+	if taskID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Task ID is required")
+		return
+	}
+
+	if err := h.taskService.ReleaseDescriptionLock(r.Context(), taskID, userID); err != nil {
+		h.handleLockError(w, r, taskID, nil, err)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, map[string]string{"message": "Lock released successfully"})
+}
+
+// respondWithTaskValidationError отправляет нарушения правил проекта в формате ошибок валидации.
+// Возвращает false, если ошибка не связана с правилами заполнения полей
+func (h *TaskHandler) respondWithTaskValidationError(w http.ResponseWriter, r *http.Request, err error) bool {
+	var validationErr *service.TaskValidationError
+	if !errors.As(err, &validationErr) {
+		return false
+	}
+
+	validationErrors := make([]ValidationError, 0, len(validationErr.Violations))
+	for _, violation := range validationErr.Violations {
+		validationErrors = append(validationErrors, ValidationError{
+			Field:   violation.Field,
+			Message: violation.Message,
+		})
+	}
+	h.RespondWithValidationErrors(w, r, validationErrors)
+	return true
+}
+
+// handleLockError преобразует ошибки блокировки редактирования в HTTP-ответ
+func (h *TaskHandler) handleLockError(w http.ResponseWriter, r *http.Request, taskID string, lock *domain.TaskEditLock, err error) {
+	switch {
+	case errors.Is(err, service.ErrTaskNotFound):
+		h.RespondWithError(w, r, apperrors.CodeTaskNotFound, "Task not found")
+	case errors.Is(err, service.ErrTaskAccessDenied):
+		h.RespondWithError(w, r, apperrors.CodeAccessDenied, "Access denied to the task")
+	case errors.Is(err, service.ErrInsufficientRights):
+		h.RespondWithError(w, r, apperrors.CodeInsufficientRights, "Insufficient rights to take over the lock")
+	case errors.Is(err, service.ErrProjectArchived):
+		h.RespondWithError(w, r, apperrors.CodeProjectArchived, "Project is archived and read-only")
+	case errors.Is(err, service.ErrTaskLockNotHeld):
+		h.RespondWithError(w, r, apperrors.CodeLockNotHeld, "Edit lock is not held")
+	case errors.Is(err, service.ErrTaskLocked):
+		// Возвращаем текущего владельца блокировки, чтобы клиент мог показать предупреждение
+		h.Respond(w, r, http.StatusConflict, StandardResponseData{
+			Success:      false,
+			Data:         lock,
+			ErrorMessage: "Task description is being edited by another user",
+			ErrorCode:    string(apperrors.CodeTaskLocked),
+		})
+	default:
+		h.Logger.Error("Failed to process task edit lock", err, map[string]interface{}{
+			"id": taskID,
+		})
+		h.RespondWithError(w, r, apperrors.CodeLockFailed, "Failed to process edit lock")
+	}
+}
@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/internal/service"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
+)
+
+// TeamsHandler обрабатывает запросы, связанные с уведомлениями в Microsoft Teams
+type TeamsHandler struct {
+	BaseHandler
+	teamsService *service.TeamsService
+}
+
+// NewTeamsHandler создает новый экземпляр TeamsHandler
+func NewTeamsHandler(base BaseHandler, teamsService *service.TeamsService) *TeamsHandler {
+	return &TeamsHandler{
+		BaseHandler:  base,
+		teamsService: teamsService,
+	}
+}
+
+// GetTeamsStatus возвращает состояние подключения личного чата Teams
+func (h *TeamsHandler) GetTeamsStatus(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	status, err := h.teamsService.GetStatus(r.Context(), userID)
+	if err != nil {
+		h.handleTeamsError(w, r, err, userID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, status)
+}
+
+// ConnectTeams подключает личный чат Teams по вебхуку
+func (h *TeamsHandler) ConnectTeams(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	var req domain.TeamsLinkRequest
+	if err := h.ParseJSON(r, &req); err != nil {
+		h.Logger.Error("Failed to parse Teams connect request", err)
+		h.RespondWithError(w, r, apperrors.CodeInvalidFormat, "Invalid request format")
+		return
+	}
+
+	// Валидация запроса
+	if validationErrors, err := h.ValidateRequest(r, req); err != nil {
+		h.Logger.Error("Request validation error", err)
+		h.RespondWithError(w, r, apperrors.CodeInternalError, "Validation failed")
+		return
+	} else if len(validationErrors) > 0 {
+		h.RespondWithValidationErrors(w, r, validationErrors)
+		return
+	}
+
+	status, err := h.teamsService.Connect(r.Context(), userID, req)
+	if err != nil {
+		h.handleTeamsError(w, r, err, userID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, status)
+}
+
+// DisconnectTeams отключает личный чат Teams
+func (h *TeamsHandler) DisconnectTeams(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	if err := h.teamsService.Disconnect(r.Context(), userID); err != nil {
+		h.handleTeamsError(w, r, err, userID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, map[string]bool{"success": true})
+}
+
+// GetProjectTeamsChannel возвращает канал Teams проекта
+func (h *TeamsHandler) GetProjectTeamsChannel(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID проекта из URL
+	projectID := h.GetURLParam(r, "id")
+	if projectID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID is required")
+		return
+	}
+
+	channel, err := h.teamsService.GetProjectChannel(r.Context(), projectID)
+	if err != nil {
+		h.handleTeamsError(w, r, err, projectID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, channel)
+}
+
+// SaveProjectTeamsChannel создает или обновляет канал Teams проекта
+func (h *TeamsHandler) SaveProjectTeamsChannel(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID проекта из URL
+	projectID := h.GetURLParam(r, "id")
+	if projectID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID is required")
+		return
+	}
+
+	var req domain.ProjectTeamsChannelRequest
+	if err := h.ParseJSON(r, &req); err != nil {
+		h.Logger.Error("Failed to parse project Teams channel request", err)
+		h.RespondWithError(w, r, apperrors.CodeInvalidFormat, "Invalid request format")
+		return
+	}
+
+	// Валидация запроса
+	if validationErrors, err := h.ValidateRequest(r, req); err != nil {
+		h.Logger.Error("Request validation error", err)
+		h.RespondWithError(w, r, apperrors.CodeInternalError, "Validation failed")
+		return
+	} else if len(validationErrors) > 0 {
+		h.RespondWithValidationErrors(w, r, validationErrors)
+		return
+	}
+
+	channel, err := h.teamsService.SaveProjectChannel(r.Context(), projectID, req, userID)
+	if err != nil {
+		h.handleTeamsError(w, r, err, projectID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, channel)
+}
+
+// DeleteProjectTeamsChannel удаляет канал Teams проекта
+func (h *TeamsHandler) DeleteProjectTeamsChannel(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID проекта из URL
+	projectID := h.GetURLParam(r, "id")
+	if projectID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID is required")
+		return
+	}
+
+	if err := h.teamsService.DeleteProjectChannel(r.Context(), projectID); err != nil {
+		h.handleTeamsError(w, r, err, projectID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, map[string]bool{"success": true})
+}
+
+// handleTeamsError преобразует ошибки интеграции с Teams в HTTP-ответы
+func (h *TeamsHandler) handleTeamsError(w http.ResponseWriter, r *http.Request, err error, id string) {
+	var validationErr *service.TaskValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		validationErrors := make([]ValidationError, 0, len(validationErr.Violations))
+		for _, violation := range validationErr.Violations {
+			validationErrors = append(validationErrors, ValidationError{
+				Field:   violation.Field,
+				Message: violation.Message,
+			})
+		}
+		h.RespondWithValidationErrors(w, r, validationErrors)
+	case errors.Is(err, service.ErrTeamsChannelNotFound):
+		h.RespondWithError(w, r, apperrors.CodeTeamsChannelNotFound, "Teams channel not found")
+	case errors.Is(err, service.ErrTeamsWebhookNotAllowed):
+		h.RespondWithError(w, r, apperrors.CodeTeamsWebhookNotAllowed, "Webhook URL must point to Microsoft Teams")
+	case errors.Is(err, service.ErrTeamsWebhookUnreachable):
+		h.RespondWithError(w, r, apperrors.CodeTeamsWebhookUnreachable, "Teams webhook rejected the test message")
+	default:
+		h.Logger.Error("Failed to process Teams request", err, map[string]interface{}{
+			"id": id,
+		})
+		h.RespondWithError(w, r, apperrors.CodeTeamsFailed, "Failed to process Teams request")
+	}
+}
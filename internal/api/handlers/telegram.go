@@ -1,22 +1,31 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"strings"
 	"time"
 
+	"github.com/nurlyy/task_manager/internal/domain"
 	"github.com/nurlyy/task_manager/internal/repository"
 	"github.com/nurlyy/task_manager/internal/service"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
 )
 
+// telegramSecretHeader - заголовок, в котором Telegram передает секрет webhook
+const telegramSecretHeader = "X-Telegram-Bot-Api-Secret-Token"
+
 // TelegramHandler обрабатывает запросы связанные с Telegram
 type TelegramHandler struct {
 	baseHandler     BaseHandler
 	telegramRepo    repository.TelegramRepository
 	telegramService *service.TelegramSender
 	userService     *service.UserService
+	taskService     *service.TaskService
 }
 
 // NewTelegramHandler создает новый обработчик для Telegram
@@ -25,12 +34,14 @@ func NewTelegramHandler(
 	telegramRepo repository.TelegramRepository,
 	telegramService *service.TelegramSender,
 	userService *service.UserService,
+	taskService *service.TaskService,
 ) *TelegramHandler {
 	return &TelegramHandler{
 		baseHandler:     baseHandler,
 		telegramRepo:    telegramRepo,
 		telegramService: telegramService,
 		userService:     userService,
+		taskService:     taskService,
 	}
 }
 
@@ -56,27 +67,50 @@ type TelegramUpdate struct {
 		Date int    `json:"date"`
 		Text string `json:"text"`
 	} `json:"message,omitempty"`
+	CallbackQuery *TelegramCallbackQuery `json:"callback_query,omitempty"`
+}
+
+// TelegramCallbackQuery представляет нажатие кнопки встроенной клавиатуры
+type TelegramCallbackQuery struct {
+	ID   string `json:"id"`
+	From struct {
+		ID int `json:"id"`
+	} `json:"from"`
+	Data string `json:"data"`
 }
 
 // WebhookHandler обрабатывает webhook запросы от Telegram
 func (h *TelegramHandler) WebhookHandler(w http.ResponseWriter, r *http.Request) {
+	// Проверяем, что запрос пришел от Telegram
+	if !h.telegramService.VerifyWebhookSecret(r.Header.Get(telegramSecretHeader)) {
+		h.baseHandler.RespondWithError(w, r, apperrors.CodeInvalidSignature, "Invalid webhook secret")
+		return
+	}
+
 	// Читаем тело запроса
 	body, err := ioutil.ReadAll(r.Body)
 	if err != nil {
-		h.baseHandler.HandleError(w, r, err, http.StatusBadRequest)
+		h.baseHandler.RespondWithError(w, r, apperrors.CodeInvalidFormat, "Invalid request format")
 		return
 	}
 	defer r.Body.Close()
 
 	// // Логируем полученный запрос
-	// h.baseHandler.logger.Info("Received Telegram webhook", map[string]interface{}{
+	// h.baseHandler.Logger.Info("Received Telegram webhook", map[string]interface{}{
 	// 	"body": string(body),
 	// })
 
 	// Разбираем полученные данные
 	var update TelegramUpdate
 	if err := json.Unmarshal(body, &update); err != nil {
-		h.baseHandler.HandleError(w, r, err, http.StatusBadRequest)
+		h.baseHandler.RespondWithError(w, r, apperrors.CodeInvalidFormat, "Invalid request format")
+		return
+	}
+
+	// Обрабатываем нажатия кнопок под уведомлениями
+	if update.CallbackQuery != nil {
+		h.handleCallbackQuery(r.Context(), update.CallbackQuery)
+		w.WriteHeader(http.StatusOK)
 		return
 	}
 
@@ -146,19 +180,66 @@ func (h *TelegramHandler) WebhookHandler(w http.ResponseWriter, r *http.Request)
 	w.WriteHeader(http.StatusOK)
 }
 
+// handleCallbackQuery выполняет действие нажатой кнопки от имени пользователя,
+// связанного с аккаунтом Telegram, и отвечает на нажатие
+func (h *TelegramHandler) handleCallbackQuery(ctx context.Context, query *TelegramCallbackQuery) {
+	answer := h.callbackAnswer(ctx, query)
+	if err := h.telegramService.AnswerCallbackQuery(ctx, query.ID, answer); err != nil {
+		h.baseHandler.Logger.Warn("Failed to answer Telegram callback query", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+}
+
+// callbackAnswer обрабатывает нажатие кнопки и возвращает текст ответа пользователю
+func (h *TelegramHandler) callbackAnswer(ctx context.Context, query *TelegramCallbackQuery) string {
+	taskID, ok := strings.CutPrefix(query.Data, service.TelegramCallbackMarkDone)
+	if !ok || taskID == "" {
+		return "Неизвестное действие."
+	}
+
+	link, err := h.telegramRepo.GetByTelegramID(ctx, fmt.Sprintf("%d", query.From.ID))
+	if err != nil || link == nil {
+		return "Аккаунт Telegram не связан с Task Manager."
+	}
+
+	_, err = h.taskService.UpdateStatus(ctx, taskID, domain.TaskStatusCompleted, link.UserID)
+	switch {
+	case err == nil:
+		return "Задача отмечена выполненной."
+	case errors.Is(err, service.ErrTaskNotFound):
+		return "Задача не найдена."
+	case errors.Is(err, service.ErrTaskAccessDenied), errors.Is(err, service.ErrInsufficientRights):
+		return "Недостаточно прав для изменения задачи."
+	case errors.Is(err, service.ErrInvalidTaskStatus):
+		return "Задачу нельзя перевести в статус \"выполнена\" из текущего статуса."
+	case errors.Is(err, service.ErrProjectArchived):
+		return "Проект задачи находится в архиве."
+	case errors.Is(err, service.ErrOpenSubtasks):
+		return "Сначала завершите подзадачи."
+	case errors.As(err, new(*service.TaskValidationError)):
+		return "Заполните обязательные поля задачи в веб-интерфейсе."
+	default:
+		h.baseHandler.Logger.Error("Failed to complete task from Telegram", err, map[string]interface{}{
+			"task_id": taskID,
+		})
+		return "Не удалось изменить задачу. Попробуйте позже."
+	}
+}
+
 // GenerateConnectToken генерирует токен для связывания аккаунта с Telegram
 func (h *TelegramHandler) GenerateConnectToken(w http.ResponseWriter, r *http.Request) {
 	// Получаем текущего пользователя из контекста
 	user, err := h.baseHandler.GetCurrentUser(r)
 	if err != nil {
-		h.baseHandler.HandleError(w, r, err, http.StatusUnauthorized)
+		h.baseHandler.HandleError(w, r, err)
 		return
 	}
 
 	// Генерируем токен
 	token, err := h.userService.GenerateTelegramToken(r.Context(), user.ID)
 	if err != nil {
-		h.baseHandler.HandleError(w, r, err, http.StatusInternalServerError)
+		h.baseHandler.HandleError(w, r, err)
 		return
 	}
 
@@ -176,7 +257,7 @@ func (h *TelegramHandler) GetTelegramStatus(w http.ResponseWriter, r *http.Reque
 	// Получаем текущего пользователя из контекста
 	user, err := h.baseHandler.GetCurrentUser(r)
 	if err != nil {
-		h.baseHandler.HandleError(w, r, err, http.StatusUnauthorized)
+		h.baseHandler.HandleError(w, r, err)
 		return
 	}
 
@@ -203,13 +284,13 @@ func (h *TelegramHandler) DisconnectTelegram(w http.ResponseWriter, r *http.Requ
 	// Получаем текущего пользователя из контекста
 	user, err := h.baseHandler.GetCurrentUser(r)
 	if err != nil {
-		h.baseHandler.HandleError(w, r, err, http.StatusUnauthorized)
+		h.baseHandler.HandleError(w, r, err)
 		return
 	}
 
 	// Удаляем связь с Telegram
 	if err := h.telegramRepo.Delete(r.Context(), user.ID); err != nil {
-		h.baseHandler.HandleError(w, r, err, http.StatusInternalServerError)
+		h.baseHandler.HandleError(w, r, err)
 		return
 	}
 
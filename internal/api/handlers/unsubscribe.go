@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/nurlyy/task_manager/internal/service"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
+)
+
+// UnsubscribeHandler обрабатывает запросы на отписку от email-уведомлений
+type UnsubscribeHandler struct {
+	BaseHandler
+	unsubscribeService *service.UnsubscribeService
+}
+
+// NewUnsubscribeHandler создает новый экземпляр UnsubscribeHandler
+func NewUnsubscribeHandler(base BaseHandler, unsubscribeService *service.UnsubscribeService) *UnsubscribeHandler {
+	return &UnsubscribeHandler{
+		BaseHandler:        base,
+		unsubscribeService: unsubscribeService,
+	}
+}
+
+// ConfirmUnsubscribe показывает страницу подтверждения отписки по ссылке из письма.
+// Запрос не меняет настроек: их меняет только POST
+func (h *UnsubscribeHandler) ConfirmUnsubscribe(w http.ResponseWriter, r *http.Request) {
+	// Получаем токен из строки запроса
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingToken, "Unsubscribe token is required")
+		return
+	}
+
+	notificationType, err := h.unsubscribeService.Check(token)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidUnsubscribeLink) {
+			h.RespondWithError(w, r, apperrors.CodeInvalidLink, "Invalid or expired unsubscribe link")
+			return
+		}
+		h.Logger.Error("Failed to check unsubscribe link", err)
+		h.RespondWithError(w, r, apperrors.CodeUnsubscribeFailed, "Failed to unsubscribe")
+		return
+	}
+
+	body, err := h.unsubscribeService.RenderConfirmHTML(token, notificationType)
+	if err != nil {
+		h.Logger.Error("Failed to render unsubscribe page", err)
+		h.RespondWithError(w, r, apperrors.CodeInternalError, "Failed to render unsubscribe page")
+		return
+	}
+
+	h.writeUnsubscribePage(w, body)
+}
+
+// Unsubscribe отключает email-уведомления по подписанной ссылке: форма страницы подтверждения
+// и POST почтового клиента с телом List-Unsubscribe=One-Click (RFC 8058)
+func (h *UnsubscribeHandler) Unsubscribe(w http.ResponseWriter, r *http.Request) {
+	// Получаем токен из строки запроса
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingToken, "Unsubscribe token is required")
+		return
+	}
+
+	// Отключаем email-уведомления
+	result, err := h.unsubscribeService.Unsubscribe(r.Context(), token)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidUnsubscribeLink) {
+			h.RespondWithError(w, r, apperrors.CodeInvalidLink, "Invalid or expired unsubscribe link")
+			return
+		}
+		h.Logger.Error("Failed to unsubscribe", err)
+		h.RespondWithError(w, r, apperrors.CodeUnsubscribeFailed, "Failed to unsubscribe")
+		return
+	}
+
+	// Форме страницы подтверждения отвечаем страницей, почтовому клиенту - JSON
+	if !strings.Contains(r.Header.Get("Accept"), "text/html") {
+		h.RespondWithSuccess(w, r, result)
+		return
+	}
+
+	body, err := h.unsubscribeService.RenderDoneHTML(result)
+	if err != nil {
+		h.Logger.Error("Failed to render unsubscribe page", err)
+		h.RespondWithSuccess(w, r, result)
+		return
+	}
+
+	h.writeUnsubscribePage(w, body)
+}
+
+// writeUnsubscribePage отправляет HTML-страницу отписки; страница с токеном не кэшируется
+func (h *UnsubscribeHandler) writeUnsubscribePage(w http.ResponseWriter, body []byte) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(body); err != nil {
+		h.Logger.Error("Failed to write unsubscribe page", err)
+	}
+}
@@ -3,10 +3,13 @@ package handlers
 import (
 	"errors"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/nurlyy/task_manager/internal/domain"
 	"github.com/nurlyy/task_manager/internal/repository"
 	"github.com/nurlyy/task_manager/internal/service"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
 )
 
 // UserHandler обрабатывает запросы, связанные с пользователями
@@ -28,14 +31,14 @@ func (h *UserHandler) GetUser(w http.ResponseWriter, r *http.Request) {
 	// Получаем ID текущего пользователя из контекста
 	currentUserID, err := h.GetUserIDFromContext(r)
 	if err != nil {
-		h.RespondWithError(w, r, http.StatusUnauthorized, "Unauthorized", "unauthorized")
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
 		return
 	}
 
 	// Получаем ID запрашиваемого пользователя из URL
 	userID := h.GetURLParam(r, "id")
 	if userID == "" {
-		h.RespondWithError(w, r, http.StatusBadRequest, "User ID is required", "missing_id")
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "User ID is required")
 		return
 	}
 
@@ -43,13 +46,13 @@ func (h *UserHandler) GetUser(w http.ResponseWriter, r *http.Request) {
 	user, err := h.userService.GetByID(r.Context(), userID)
 	if err != nil {
 		if errors.Is(err, service.ErrUserNotFound) {
-			h.RespondWithError(w, r, http.StatusNotFound, "User not found", "user_not_found")
+			h.RespondWithError(w, r, apperrors.CodeUserNotFound, "User not found")
 			return
 		}
 		h.Logger.Error("Failed to get user", err, map[string]interface{}{
 			"user_id": currentUserID,
 		})
-		h.RespondWithError(w, r, http.StatusInternalServerError, "Failed to get user info", "user_fetch_failed")
+		h.RespondWithError(w, r, apperrors.CodeUserFetchFailed, "Failed to get user info")
 		return
 	}
 
@@ -61,41 +64,41 @@ func (h *UserHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 	// Получаем ID текущего пользователя из контекста
 	currentUserID, err := h.GetUserIDFromContext(r)
 	if err != nil {
-		h.RespondWithError(w, r, http.StatusUnauthorized, "Unauthorized", "unauthorized")
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
 		return
 	}
 
 	// Получаем ID пользователя для обновления из URL
 	userID := h.GetURLParam(r, "id")
 	if userID == "" {
-		h.RespondWithError(w, r, http.StatusBadRequest, "User ID is required", "missing_id")
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "User ID is required")
 		return
 	}
 
 	// Проверяем, что пользователь обновляет свой профиль или является администратором
 	currentUser, err := h.userService.GetByID(r.Context(), currentUserID)
 	if err != nil {
-		h.RespondWithError(w, r, http.StatusInternalServerError, "Failed to get user info", "user_fetch_failed")
+		h.RespondWithError(w, r, apperrors.CodeUserFetchFailed, "Failed to get user info")
 		return
 	}
 
 	// Только администратор может обновлять других пользователей
 	if userID != currentUserID && currentUser.Role != domain.UserRoleAdmin {
-		h.RespondWithError(w, r, http.StatusForbidden, "Permission denied", "permission_denied")
+		h.RespondWithError(w, r, apperrors.CodePermissionDenied, "Permission denied")
 		return
 	}
 
 	var req domain.UserUpdateRequest
 	if err := h.ParseJSON(r, &req); err != nil {
 		h.Logger.Error("Failed to parse update user request", err)
-		h.RespondWithError(w, r, http.StatusBadRequest, "Invalid request format", "invalid_format")
+		h.RespondWithError(w, r, apperrors.CodeInvalidFormat, "Invalid request format")
 		return
 	}
 
 	// Валидация запроса
-	if validationErrors, err := h.ValidateRequest(req); err != nil {
+	if validationErrors, err := h.ValidateRequest(r, req); err != nil {
 		h.Logger.Error("Request validation error", err)
-		h.RespondWithError(w, r, http.StatusInternalServerError, "Validation failed", "validation_error")
+		h.RespondWithError(w, r, apperrors.CodeInternalError, "Validation failed")
 		return
 	} else if len(validationErrors) > 0 {
 		h.RespondWithValidationErrors(w, r, validationErrors)
@@ -104,7 +107,7 @@ func (h *UserHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 
 	// Только администратор может менять роль пользователя
 	if req.Role != nil && currentUser.Role != domain.UserRoleAdmin {
-		h.RespondWithError(w, r, http.StatusForbidden, "Permission denied to change role", "permission_denied")
+		h.RespondWithError(w, r, apperrors.CodePermissionDenied, "Permission denied to change role")
 		return
 	}
 
@@ -112,13 +115,13 @@ func (h *UserHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 	user, err := h.userService.Update(r.Context(), userID, req)
 	if err != nil {
 		if errors.Is(err, service.ErrUserNotFound) {
-			h.RespondWithError(w, r, http.StatusNotFound, "User not found", "user_not_found")
+			h.RespondWithError(w, r, apperrors.CodeUserNotFound, "User not found")
 			return
 		}
 		h.Logger.Error("Failed to update user", err, map[string]interface{}{
 			"id": userID,
 		})
-		h.RespondWithError(w, r, http.StatusInternalServerError, "Failed to update user", "update_failed")
+		h.RespondWithError(w, r, apperrors.CodeUpdateFailed, "Failed to update user")
 		return
 	}
 
@@ -130,40 +133,40 @@ func (h *UserHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
 	// Получаем ID текущего пользователя из контекста
 	currentUserID, err := h.GetUserIDFromContext(r)
 	if err != nil {
-		h.RespondWithError(w, r, http.StatusUnauthorized, "Unauthorized", "unauthorized")
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
 		return
 	}
 
 	// Получаем ID пользователя для удаления из URL
 	userID := h.GetURLParam(r, "id")
 	if userID == "" {
-		h.RespondWithError(w, r, http.StatusBadRequest, "User ID is required", "missing_id")
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "User ID is required")
 		return
 	}
 
 	// Проверяем, что пользователь удаляет свой профиль или является администратором
 	currentUser, err := h.userService.GetByID(r.Context(), currentUserID)
 	if err != nil {
-		h.RespondWithError(w, r, http.StatusInternalServerError, "Failed to get user info", "user_fetch_failed")
+		h.RespondWithError(w, r, apperrors.CodeUserFetchFailed, "Failed to get user info")
 		return
 	}
 
 	// Только администратор может удалять других пользователей
 	if userID != currentUserID && currentUser.Role != domain.UserRoleAdmin {
-		h.RespondWithError(w, r, http.StatusForbidden, "Permission denied", "permission_denied")
+		h.RespondWithError(w, r, apperrors.CodePermissionDenied, "Permission denied")
 		return
 	}
 
 	// Удаляем пользователя
 	if err := h.userService.Delete(r.Context(), userID); err != nil {
 		if errors.Is(err, service.ErrUserNotFound) {
-			h.RespondWithError(w, r, http.StatusNotFound, "User not found", "user_not_found")
+			h.RespondWithError(w, r, apperrors.CodeUserNotFound, "User not found")
 			return
 		}
 		h.Logger.Error("Failed to delete user", err, map[string]interface{}{
 			"id": userID,
 		})
-		h.RespondWithError(w, r, http.StatusInternalServerError, "Failed to delete user", "delete_failed")
+		h.RespondWithError(w, r, apperrors.CodeDeleteFailed, "Failed to delete user")
 		return
 	}
 
@@ -175,25 +178,28 @@ func (h *UserHandler) ListUsers(w http.ResponseWriter, r *http.Request) {
 	// Получаем ID текущего пользователя из контекста
 	currentUserID, err := h.GetUserIDFromContext(r)
 	if err != nil {
-		h.RespondWithError(w, r, http.StatusUnauthorized, "Unauthorized", "unauthorized")
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
 		return
 	}
 
 	// Проверяем, что пользователь имеет права на просмотр списка пользователей
 	currentUser, err := h.userService.GetByID(r.Context(), currentUserID)
 	if err != nil {
-		h.RespondWithError(w, r, http.StatusInternalServerError, "Failed to get user info", "user_fetch_failed")
+		h.RespondWithError(w, r, apperrors.CodeUserFetchFailed, "Failed to get user info")
 		return
 	}
 
 	// Только администраторы и менеджеры могут просматривать список всех пользователей
 	if currentUser.Role != domain.UserRoleAdmin && currentUser.Role != domain.UserRoleManager {
-		h.RespondWithError(w, r, http.StatusForbidden, "Permission denied", "permission_denied")
+		h.RespondWithError(w, r, apperrors.CodePermissionDenied, "Permission denied")
 		return
 	}
 
 	// Параметры пагинации
-	page, pageSize := h.GetPaginationParams(r)
+	page, ok := h.GetPageRequest(w, r)
+	if !ok {
+		return
+	}
 
 	// Создаем фильтр
 	filter := repository.UserFilter{
@@ -218,16 +224,215 @@ func (h *UserHandler) ListUsers(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Получаем список пользователей
-	result, err := h.userService.List(r.Context(), filter, page, pageSize)
+	result, err := h.userService.List(r.Context(), filter, page)
 	if err != nil {
 		h.Logger.Error("Failed to list users", err)
-		h.RespondWithError(w, r, http.StatusInternalServerError, "Failed to get users", "users_fetch_failed")
+		h.RespondWithError(w, r, apperrors.CodeUsersFetchFailed, "Failed to get users")
 		return
 	}
 
 	h.RespondWithPagination(w, r, result.Items, result)
 }
 
+// GetUserDirectory возвращает справочник пользователей с навыками и свободным временем для выбора исполнителей
+func (h *UserHandler) GetUserDirectory(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID текущего пользователя из контекста
+	currentUserID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Проверяем, что пользователь имеет права на просмотр справочника
+	currentUser, err := h.userService.GetByID(r.Context(), currentUserID)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUserFetchFailed, "Failed to get user info")
+		return
+	}
+
+	// Только администраторы и менеджеры могут подбирать исполнителей по справочнику
+	if currentUser.Role != domain.UserRoleAdmin && currentUser.Role != domain.UserRoleManager {
+		h.RespondWithError(w, r, apperrors.CodePermissionDenied, "Permission denied")
+		return
+	}
+
+	// Параметры пагинации
+	page, ok := h.GetPageRequest(w, r)
+	if !ok {
+		return
+	}
+
+	// Создаем фильтр
+	filter := repository.UserDirectoryFilter{
+		SearchText: getStringPtr(r.URL.Query().Get("search")),
+		Department: getStringPtr(r.URL.Query().Get("department")),
+	}
+
+	// Фильтр по навыкам (через запятую)
+	if skills := r.URL.Query().Get("skills"); skills != "" {
+		filter.Skills = strings.Split(skills, ",")
+	}
+
+	// Фильтр по минимальному свободному времени в часах
+	if minFree := r.URL.Query().Get("min_free_hours"); minFree != "" {
+		hours, err := strconv.ParseFloat(minFree, 64)
+		if err != nil || hours < 0 {
+			h.RespondWithError(w, r, apperrors.CodeInvalidMinFreeHours, "Invalid min_free_hours")
+			return
+		}
+		filter.MinFreeHours = &hours
+	}
+
+	// Фильтр по роли
+	if role := r.URL.Query().Get("role"); role != "" {
+		userRole := domain.UserRole(role)
+		filter.Role = &userRole
+	}
+
+	// Получаем справочник пользователей
+	result, err := h.userService.Directory(r.Context(), filter, page)
+	if err != nil {
+		h.Logger.Error("Failed to get user directory", err)
+		h.RespondWithError(w, r, apperrors.CodeDirectoryFetchFailed, "Failed to get user directory")
+		return
+	}
+
+	h.RespondWithPagination(w, r, result.Items, result)
+}
+
+// GetReportingChain возвращает цепочку руководителей пользователя
+func (h *UserHandler) GetReportingChain(w http.ResponseWriter, r *http.Request) {
+	// Проверяем аутентификацию
+	if _, err := h.GetUserIDFromContext(r); err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID пользователя из URL
+	userID := h.GetURLParam(r, "id")
+	if userID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "User ID is required")
+		return
+	}
+
+	chain, err := h.userService.GetReportingChain(r.Context(), userID)
+	if err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			h.RespondWithError(w, r, apperrors.CodeUserNotFound, "User not found")
+			return
+		}
+		h.Logger.Error("Failed to get reporting chain", err, map[string]interface{}{
+			"id": userID,
+		})
+		h.RespondWithError(w, r, apperrors.CodeReportingChainFailed, "Failed to get reporting chain")
+		return
+	}
+
+	h.RespondWithSuccess(w, r, chain)
+}
+
+// GetUserReports возвращает подчиненных пользователя
+func (h *UserHandler) GetUserReports(w http.ResponseWriter, r *http.Request) {
+	// Проверяем аутентификацию
+	if _, err := h.GetUserIDFromContext(r); err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID пользователя из URL
+	userID := h.GetURLParam(r, "id")
+	if userID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "User ID is required")
+		return
+	}
+
+	// По умолчанию возвращаем только непосредственных подчиненных
+	recursive := r.URL.Query().Get("recursive") == "true"
+
+	reports, err := h.userService.GetReports(r.Context(), userID, recursive)
+	if err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			h.RespondWithError(w, r, apperrors.CodeUserNotFound, "User not found")
+			return
+		}
+		h.Logger.Error("Failed to get user reports", err, map[string]interface{}{
+			"id": userID,
+		})
+		h.RespondWithError(w, r, apperrors.CodeReportsFetchFailed, "Failed to get user reports")
+		return
+	}
+
+	h.RespondWithSuccess(w, r, reports)
+}
+
+// SetUserManager назначает или снимает руководителя пользователя
+func (h *UserHandler) SetUserManager(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID текущего пользователя из контекста
+	currentUserID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID пользователя из URL
+	userID := h.GetURLParam(r, "id")
+	if userID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "User ID is required")
+		return
+	}
+
+	// Только администратор может изменять оргструктуру
+	currentUser, err := h.userService.GetByID(r.Context(), currentUserID)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUserFetchFailed, "Failed to get user info")
+		return
+	}
+	if currentUser.Role != domain.UserRoleAdmin {
+		h.RespondWithError(w, r, apperrors.CodePermissionDenied, "Permission denied")
+		return
+	}
+
+	var req domain.SetManagerRequest
+	if err := h.ParseJSON(r, &req); err != nil {
+		h.Logger.Error("Failed to parse set manager request", err)
+		h.RespondWithError(w, r, apperrors.CodeInvalidFormat, "Invalid request format")
+		return
+	}
+
+	// Валидация запроса
+	if validationErrors, err := h.ValidateRequest(r, req); err != nil {
+		h.Logger.Error("Request validation error", err)
+		h.RespondWithError(w, r, apperrors.CodeInternalError, "Validation failed")
+		return
+	} else if len(validationErrors) > 0 {
+		h.RespondWithValidationErrors(w, r, validationErrors)
+		return
+	}
+
+	user, err := h.userService.SetManager(r.Context(), userID, req.ManagerID)
+	if err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			h.RespondWithError(w, r, apperrors.CodeUserNotFound, "User not found")
+			return
+		}
+		if errors.Is(err, service.ErrManagerNotFound) {
+			h.RespondWithError(w, r, apperrors.CodeManagerNotFound, "Manager not found")
+			return
+		}
+		if errors.Is(err, service.ErrManagerCycle) {
+			h.RespondWithError(w, r, apperrors.CodeManagerCycle, "Manager assignment creates a reporting cycle")
+			return
+		}
+		h.Logger.Error("Failed to set user manager", err, map[string]interface{}{
+			"id": userID,
+		})
+		h.RespondWithError(w, r, apperrors.CodeUpdateFailed, "Failed to set user manager")
+		return
+	}
+
+	h.RespondWithSuccess(w, r, user)
+}
+
 // Вспомогательная функция для получения указателя на строку
 func getStringPtr(s string) *string {
 	if s == "" {
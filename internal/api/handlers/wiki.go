@@ -0,0 +1,406 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/internal/service"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
+)
+
+// WikiHandler обрабатывает запросы, связанные с вики проектов
+type WikiHandler struct {
+	BaseHandler
+	wikiService *service.WikiService
+}
+
+// NewWikiHandler создает новый экземпляр WikiHandler
+func NewWikiHandler(base BaseHandler, wikiService *service.WikiService) *WikiHandler {
+	return &WikiHandler{
+		BaseHandler: base,
+		wikiService: wikiService,
+	}
+}
+
+// GetProjectWiki возвращает дерево вики-страниц проекта
+func (h *WikiHandler) GetProjectWiki(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID проекта из URL
+	projectID := h.GetURLParam(r, "id")
+	if projectID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID is required")
+		return
+	}
+
+	tree, err := h.wikiService.GetTree(r.Context(), projectID)
+	if err != nil {
+		h.handleWikiError(w, r, err, projectID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, tree)
+}
+
+// SearchProjectWiki выполняет полнотекстовый поиск по вики проекта
+func (h *WikiHandler) SearchProjectWiki(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID проекта из URL
+	projectID := h.GetURLParam(r, "id")
+	if projectID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID is required")
+		return
+	}
+
+	// Получаем параметры запроса
+	query := r.URL.Query().Get("q")
+	limit := 0
+	if limitParam := r.URL.Query().Get("limit"); limitParam != "" {
+		parsed, err := strconv.Atoi(limitParam)
+		if err != nil || parsed <= 0 {
+			h.RespondWithError(w, r, apperrors.CodeInvalidLimit, "Invalid limit")
+			return
+		}
+		limit = parsed
+	}
+
+	results, err := h.wikiService.Search(r.Context(), projectID, query, limit)
+	if err != nil {
+		h.handleWikiError(w, r, err, projectID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, results)
+}
+
+// CreateWikiPage создает вики-страницу проекта
+func (h *WikiHandler) CreateWikiPage(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID проекта из URL
+	projectID := h.GetURLParam(r, "id")
+	if projectID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Project ID is required")
+		return
+	}
+
+	var req domain.WikiPageCreateRequest
+	if !h.parseWikiRequest(w, r, &req) {
+		return
+	}
+
+	page, err := h.wikiService.Create(r.Context(), projectID, req, userID)
+	if err != nil {
+		h.handleWikiError(w, r, err, projectID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, page)
+}
+
+// GetWikiPage возвращает вики-страницу
+func (h *WikiHandler) GetWikiPage(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID страницы из URL
+	pageID := h.GetURLParam(r, "id")
+	if pageID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Page ID is required")
+		return
+	}
+
+	page, err := h.wikiService.Get(r.Context(), pageID, userID)
+	if err != nil {
+		h.handleWikiError(w, r, err, pageID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, page)
+}
+
+// UpdateWikiPage сохраняет правку вики-страницы
+func (h *WikiHandler) UpdateWikiPage(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID страницы из URL
+	pageID := h.GetURLParam(r, "id")
+	if pageID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Page ID is required")
+		return
+	}
+
+	var req domain.WikiPageUpdateRequest
+	if !h.parseWikiRequest(w, r, &req) {
+		return
+	}
+
+	page, err := h.wikiService.Update(r.Context(), pageID, req, userID)
+	if err != nil {
+		h.handleWikiError(w, r, err, pageID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, page)
+}
+
+// DeleteWikiPage удаляет вики-страницу
+func (h *WikiHandler) DeleteWikiPage(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID страницы из URL
+	pageID := h.GetURLParam(r, "id")
+	if pageID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Page ID is required")
+		return
+	}
+
+	if err := h.wikiService.Delete(r.Context(), pageID, userID); err != nil {
+		h.handleWikiError(w, r, err, pageID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, map[string]bool{"success": true})
+}
+
+// ListWikiRevisions возвращает историю правок вики-страницы
+func (h *WikiHandler) ListWikiRevisions(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID страницы из URL
+	pageID := h.GetURLParam(r, "id")
+	if pageID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Page ID is required")
+		return
+	}
+
+	revisions, err := h.wikiService.ListRevisions(r.Context(), pageID, userID)
+	if err != nil {
+		h.handleWikiError(w, r, err, pageID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, revisions)
+}
+
+// GetWikiRevision возвращает ревизию вики-страницы
+func (h *WikiHandler) GetWikiRevision(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	pageID, version, ok := h.getRevisionParams(w, r)
+	if !ok {
+		return
+	}
+
+	revision, err := h.wikiService.GetRevision(r.Context(), pageID, version, userID)
+	if err != nil {
+		h.handleWikiError(w, r, err, pageID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, revision)
+}
+
+// RestoreWikiRevision восстанавливает вики-страницу из ревизии
+func (h *WikiHandler) RestoreWikiRevision(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	pageID, version, ok := h.getRevisionParams(w, r)
+	if !ok {
+		return
+	}
+
+	page, err := h.wikiService.RestoreRevision(r.Context(), pageID, version, userID)
+	if err != nil {
+		h.handleWikiError(w, r, err, pageID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, page)
+}
+
+// LinkWikiTask связывает вики-страницу с задачей проекта
+func (h *WikiHandler) LinkWikiTask(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID страницы из URL
+	pageID := h.GetURLParam(r, "id")
+	if pageID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Page ID is required")
+		return
+	}
+
+	var req domain.WikiPageTaskRequest
+	if !h.parseWikiRequest(w, r, &req) {
+		return
+	}
+
+	page, err := h.wikiService.LinkTask(r.Context(), pageID, req, userID)
+	if err != nil {
+		h.handleWikiError(w, r, err, pageID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, page)
+}
+
+// UnlinkWikiTask удаляет связь вики-страницы с задачей
+func (h *WikiHandler) UnlinkWikiTask(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID страницы и задачи из URL
+	pageID := h.GetURLParam(r, "id")
+	taskID := h.GetURLParam(r, "task_id")
+	if pageID == "" || taskID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Page ID and task ID are required")
+		return
+	}
+
+	if err := h.wikiService.UnlinkTask(r.Context(), pageID, taskID, userID); err != nil {
+		h.handleWikiError(w, r, err, pageID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, map[string]bool{"success": true})
+}
+
+// ListTaskWikiPages возвращает вики-страницы, связанные с задачей
+func (h *WikiHandler) ListTaskWikiPages(w http.ResponseWriter, r *http.Request) {
+	// Получаем ID пользователя из контекста
+	userID, err := h.GetUserIDFromContext(r)
+	if err != nil {
+		h.RespondWithError(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+		return
+	}
+
+	// Получаем ID задачи из URL
+	taskID := h.GetURLParam(r, "id")
+	if taskID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Task ID is required")
+		return
+	}
+
+	pages, err := h.wikiService.ListTaskPages(r.Context(), taskID, userID)
+	if err != nil {
+		h.handleWikiError(w, r, err, taskID)
+		return
+	}
+
+	h.RespondWithSuccess(w, r, pages)
+}
+
+// getRevisionParams возвращает ID страницы и номер ревизии из URL
+func (h *WikiHandler) getRevisionParams(w http.ResponseWriter, r *http.Request) (string, int, bool) {
+	pageID := h.GetURLParam(r, "id")
+	if pageID == "" {
+		h.RespondWithError(w, r, apperrors.CodeMissingID, "Page ID is required")
+		return "", 0, false
+	}
+
+	version, err := strconv.Atoi(h.GetURLParam(r, "version"))
+	if err != nil || version <= 0 {
+		h.RespondWithError(w, r, apperrors.CodeInvalidVersion, "Invalid revision version")
+		return "", 0, false
+	}
+
+	return pageID, version, true
+}
+
+// parseWikiRequest разбирает и валидирует тело запроса
+func (h *WikiHandler) parseWikiRequest(w http.ResponseWriter, r *http.Request, req interface{}) bool {
+	if err := h.ParseJSON(r, req); err != nil {
+		h.Logger.Error("Failed to parse wiki request", err)
+		h.RespondWithError(w, r, apperrors.CodeInvalidFormat, "Invalid request format")
+		return false
+	}
+
+	// Валидация запроса
+	if validationErrors, err := h.ValidateRequest(r, req); err != nil {
+		h.Logger.Error("Request validation error", err)
+		h.RespondWithError(w, r, apperrors.CodeInternalError, "Validation failed")
+		return false
+	} else if len(validationErrors) > 0 {
+		h.RespondWithValidationErrors(w, r, validationErrors)
+		return false
+	}
+
+	return true
+}
+
+// handleWikiError преобразует ошибки вики в HTTP-ответы
+func (h *WikiHandler) handleWikiError(w http.ResponseWriter, r *http.Request, err error, id string) {
+	switch {
+	case errors.Is(err, service.ErrSearchQueryEmpty):
+		h.RespondWithError(w, r, apperrors.CodeMissingQuery, "Search query is required")
+	case errors.Is(err, service.ErrProjectNotFound):
+		h.RespondWithError(w, r, apperrors.CodeProjectNotFound, "Project not found")
+	case errors.Is(err, service.ErrWikiPageNotFound):
+		h.RespondWithError(w, r, apperrors.CodeWikiPageNotFound, "Wiki page not found")
+	case errors.Is(err, service.ErrWikiRevisionNotFound):
+		h.RespondWithError(w, r, apperrors.CodeRevisionNotFound, "Wiki page revision not found")
+	case errors.Is(err, service.ErrTaskNotFound):
+		h.RespondWithError(w, r, apperrors.CodeTaskNotFound, "Task not found")
+	case errors.Is(err, service.ErrTaskAccessDenied):
+		h.RespondWithError(w, r, apperrors.CodeAccessDenied, "Access denied to the task")
+	case errors.Is(err, service.ErrWikiParentInvalid):
+		h.RespondWithError(w, r, apperrors.CodeInvalidParent, "Parent page must belong to the project and must not be a descendant of the page")
+	case errors.Is(err, service.ErrWikiTaskInvalid):
+		h.RespondWithError(w, r, apperrors.CodeInvalidTask, "Wiki page can only be linked to tasks of its project")
+	case errors.Is(err, service.ErrWikiTaskLinked):
+		h.RespondWithError(w, r, apperrors.CodeTaskAlreadyLinked, "Task is already linked to the wiki page")
+	case errors.Is(err, service.ErrWikiPageConflict):
+		h.RespondWithError(w, r, apperrors.CodeVersionConflict, "Wiki page was changed by another user, reload it and retry")
+	case errors.Is(err, service.ErrInsufficientRights):
+		h.RespondWithError(w, r, apperrors.CodeInsufficientRights, "Insufficient rights to edit the wiki")
+	case errors.Is(err, service.ErrProjectArchived):
+		h.RespondWithError(w, r, apperrors.CodeProjectArchived, "Project is archived and read-only")
+	default:
+		h.Logger.Error("Failed to process wiki request", err, map[string]interface{}{
+			"id": id,
+		})
+		h.RespondWithError(w, r, apperrors.CodeWikiFailed, "Failed to process wiki request")
+	}
+}
@@ -0,0 +1,162 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/internal/service"
+)
+
+// auditActions сопоставляет изменяющие методы HTTP видам изменений в журнале аудита
+var auditActions = map[string]domain.AuditAction{
+	http.MethodPost:   domain.AuditActionCreate,
+	http.MethodPut:    domain.AuditActionUpdate,
+	http.MethodPatch:  domain.AuditActionUpdate,
+	http.MethodDelete: domain.AuditActionDelete,
+}
+
+// auditRedactedFields - фрагменты названий полей тела запроса и параметров маршрута,
+// значения которых не попадают в журнал аудита
+var auditRedactedFields = []string{"password", "secret", "token", "code"}
+
+// auditRedacted заменяет значения скрытых полей в журнале аудита
+const auditRedacted = "[redacted]"
+
+// Audit записывает в журнал аудита изменения, сделанные запросами POST, PUT, PATCH и DELETE.
+// Сервисы сообщают о своих изменениях с подробностями; если запрос выполнен успешно,
+// а сервисы о нем не сообщили, записывается изменение ресурса из маршрута с телом запроса.
+// Подключается после аутентификации, чтобы записи содержали автора изменений
+func Audit(auditService *service.AuditService) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			action, ok := auditActions[r.Method]
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			body := readAuditBody(r)
+			ctx := auditService.BeginRequest(r.Context(), getClientIP(r))
+			rw := newResponseWriterWithStatus(w)
+
+			next.ServeHTTP(rw, r.WithContext(ctx))
+
+			route := ""
+			rctx := chi.RouteContext(ctx)
+			if rctx != nil {
+				route = rctx.RoutePattern()
+			}
+
+			var fallback *domain.AuditRecord
+			if rw.statusCode < http.StatusBadRequest {
+				entityType, entityID := auditEntity(rctx)
+				// POST с ID в маршруте выполняет действие над существующей сущностью
+				if action == domain.AuditActionCreate && entityID != "" {
+					action = domain.AuditActionUpdate
+				}
+				var changes map[string]interface{}
+				if body != nil {
+					changes = map[string]interface{}{"request": body}
+				}
+				fallback = auditService.NewRecord(ctx, action, entityType, entityID, changes)
+			}
+
+			auditService.FinishRequest(ctx, route, fallback)
+		})
+	}
+}
+
+// readAuditBody читает JSON-тело запроса для журнала аудита, скрывая значения
+// секретных полей, и восстанавливает тело для обработчика. Тела других типов не читаются
+func readAuditBody(r *http.Request) interface{} {
+	if r.Body == nil {
+		return nil
+	}
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || mediaType != "application/json" {
+		return nil
+	}
+
+	data, err := io.ReadAll(r.Body)
+	r.Body = io.NopCloser(bytes.NewReader(data))
+	if err != nil || len(data) == 0 {
+		return nil
+	}
+
+	var body interface{}
+	if err := json.Unmarshal(data, &body); err != nil {
+		return nil
+	}
+	return redactAuditValue(body)
+}
+
+// redactAuditValue скрывает значения секретных полей во вложенных объектах и массивах
+func redactAuditValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for field, fieldValue := range v {
+			if isAuditRedacted(field) {
+				v[field] = auditRedacted
+				continue
+			}
+			v[field] = redactAuditValue(fieldValue)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactAuditValue(item)
+		}
+	}
+	return value
+}
+
+// isAuditRedacted проверяет, скрывается ли значение поля в журнале аудита
+func isAuditRedacted(field string) bool {
+	field = strings.ToLower(field)
+	for _, fragment := range auditRedactedFields {
+		if strings.Contains(field, fragment) {
+			return true
+		}
+	}
+	return false
+}
+
+// auditEntity определяет по маршруту запроса измененный ресурс: тип - сегмент пути
+// перед последним параметром маршрута, ID - значение этого параметра. Для маршрутов
+// без параметров типом считается последний сегмент пути
+func auditEntity(rctx *chi.Context) (string, string) {
+	if rctx == nil {
+		return "", ""
+	}
+
+	var entityType, entityID, lastStatic string
+	for _, segment := range strings.Split(rctx.RoutePattern(), "/") {
+		if segment == "" || segment == "*" {
+			continue
+		}
+		if !strings.HasPrefix(segment, "{") {
+			lastStatic = segment
+			continue
+		}
+
+		name := strings.TrimSuffix(strings.TrimPrefix(segment, "{"), "}")
+		if i := strings.Index(name, ":"); i >= 0 {
+			name = name[:i]
+		}
+		entityType, entityID = lastStatic, rctx.URLParam(name)
+		if isAuditRedacted(name) {
+			entityID = ""
+		}
+	}
+
+	if entityType == "" {
+		entityType = lastStatic
+	}
+	return entityType, entityID
+}
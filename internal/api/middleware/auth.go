@@ -1,11 +1,11 @@
 package middleware
 
 import (
-	"context"
 	"net/http"
 	"strings"
 
 	"github.com/nurlyy/task_manager/pkg/auth"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
 	"github.com/nurlyy/task_manager/pkg/logger"
 )
 
@@ -29,14 +29,14 @@ func (m *AuthMiddleware) Authenticate(next http.Handler) http.Handler {
 		// Получаем токен из заголовка Authorization
 		authHeader := r.Header.Get("Authorization")
 		if authHeader == "" {
-			http.Error(w, "Authorization header required", http.StatusUnauthorized)
+			apperrors.Write(w, r, apperrors.CodeUnauthorized, "Authorization header required")
 			return
 		}
 
 		// Проверяем формат токена
 		parts := strings.Split(authHeader, " ")
 		if len(parts) != 2 || parts[0] != "Bearer" {
-			http.Error(w, "Invalid Authorization header format", http.StatusUnauthorized)
+			apperrors.Write(w, r, apperrors.CodeUnauthorized, "Invalid Authorization header format")
 			return
 		}
 		tokenString := parts[1]
@@ -47,20 +47,18 @@ func (m *AuthMiddleware) Authenticate(next http.Handler) http.Handler {
 			m.logger.Warn("Invalid JWT token", map[string]interface{}{
 				"error": err,
 			})
-			http.Error(w, "Invalid or expired token", http.StatusUnauthorized)
+			apperrors.Write(w, r, apperrors.CodeInvalidToken, "Invalid or expired token")
 			return
 		}
 
 		// Проверяем, что это токен доступа
 		if claims.Type != string(auth.AccessToken) {
-			http.Error(w, "Invalid token type", http.StatusUnauthorized)
+			apperrors.Write(w, r, apperrors.CodeInvalidToken, "Invalid token type")
 			return
 		}
 
-		// Добавляем информацию о пользователе в контекст запроса
-		ctx := context.WithValue(r.Context(), "user_id", claims.UserID)
-		ctx = context.WithValue(ctx, "user_email", claims.Email)
-		ctx = context.WithValue(ctx, "user_role", claims.Role)
+		// Добавляем пользователя запроса в контекст
+		ctx := auth.WithActor(r.Context(), auth.NewActor(claims))
 
 		// Вызываем следующий обработчик с обновленным контекстом
 		next.ServeHTTP(w, r.WithContext(ctx))
@@ -102,35 +100,10 @@ func (m *AuthMiddleware) Optional(next http.Handler) http.Handler {
 			return
 		}
 
-		// Добавляем информацию о пользователе в контекст запроса
-		ctx := context.WithValue(r.Context(), "user_id", claims.UserID)
-		ctx = context.WithValue(ctx, "user_email", claims.Email)
-		ctx = context.WithValue(ctx, "user_role", claims.Role)
+		// Добавляем пользователя запроса в контекст
+		ctx := auth.WithActor(r.Context(), auth.NewActor(claims))
 
 		// Вызываем следующий обработчик с обновленным контекстом
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
-
-// RequireRole проверяет, имеет ли пользователь требуемую роль
-func (m *AuthMiddleware) RequireRole(role string) func(http.Handler) http.Handler {
-	return func(next http.Handler) http.Handler {
-		return m.Authenticate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			// Получаем роль пользователя из контекста
-			userRole, ok := r.Context().Value("user_role").(string)
-			if !ok {
-				http.Error(w, "User role not found in context", http.StatusInternalServerError)
-				return
-			}
-
-			// Проверяем соответствие роли
-			if userRole != role && userRole != "admin" { // админы имеют доступ ко всем ресурсам
-				http.Error(w, "Insufficient permissions", http.StatusForbidden)
-				return
-			}
-
-			// Вызываем следующий обработчик
-			next.ServeHTTP(w, r)
-		}))
-	}
-}
@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/andybalholm/brotli"
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+)
+
+// compressionLevel задает степень сжатия ответов: баланс между размером и нагрузкой на CPU
+const compressionLevel = 5
+
+// Compress сжимает JSON- и текстовые ответы в br, gzip или deflate в зависимости
+// от заголовка Accept-Encoding. Brotli предпочтительнее, если клиент его поддерживает.
+// Потоковые ответы сжимаются по частям при каждом сбросе буфера
+func Compress() func(next http.Handler) http.Handler {
+	compressor := chimiddleware.NewCompressor(compressionLevel,
+		"application/json",
+		"text/html",
+		"text/plain",
+	)
+	compressor.SetEncoder("br", func(w io.Writer, level int) io.Writer {
+		return brotli.NewWriterLevel(w, level)
+	})
+	return compressor.Handler
+}
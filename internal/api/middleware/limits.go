@@ -0,0 +1,137 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
+)
+
+// deadlineKey - ключ контекста, под которым хранится deadlineState
+type deadlineKey struct{}
+
+// deadlineState связывает вложенные Deadline одного запроса
+type deadlineState struct {
+	base    context.Context // Контекст запроса, отменяемый при разрыве соединения
+	current context.Context // Контекст с действующим сроком
+}
+
+// writeDeadlineMargin оставляет время на запись ответа после истечения срока обработки
+const writeDeadlineMargin = 5 * time.Second
+
+// LimitBody ограничивает размер тела запроса. Запросы с заведомо большим
+// Content-Length отклоняются сразу, чтение остальных прерывается на превышении.
+// Пути с указанными окончаниями (загрузка файлов) не ограничиваются: вложенный
+// LimitBody не может увеличить ограничение, поэтому такие маршруты задают свое
+func LimitBody(maxBytes int64, exemptSuffixes ...string) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if hasAnySuffix(r.URL.Path, exemptSuffixes) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if r.ContentLength > maxBytes {
+				apperrors.Write(w, r, apperrors.CodePayloadTooLarge, "Request body too large")
+				return
+			}
+			r.Body = http.MaxBytesReader(w, r.Body, maxBytes)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// ReadTimeout продлевает срок чтения тела запроса для маршрутов, принимающих большие
+// тела. Таймаут чтения сервера рассчитан на небольшие JSON-запросы
+func ReadTimeout(timeout time.Duration) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Ошибка означает, что исходный ResponseWriter не поддерживает сроки чтения,
+			// и тогда действует таймаут чтения сервера
+			_ = http.NewResponseController(w).SetReadDeadline(time.Now().Add(timeout))
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// hasAnySuffix проверяет, оканчивается ли путь одним из окончаний
+func hasAnySuffix(path string, suffixes []string) bool {
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(path, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Deadline задает срок обработки запроса. Срок передается через контекст, поэтому
+// запросы к базе и Redis прерываются вместе с ним. Вложенный Deadline на отдельном
+// маршруте заменяет срок, заданный выше, в том числе продлевая его, и продлевает
+// таймаут записи ответа сервера. Если срок истек, а обработчик не начал запись ответа,
+// клиент получает 504 с ошибкой из каталога
+func Deadline(timeout time.Duration) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			state, ok := ctx.Value(deadlineKey{}).(*deadlineState)
+			if !ok {
+				state = &deadlineState{base: ctx}
+				ctx = context.WithValue(ctx, deadlineKey{}, state)
+			}
+
+			// Срок, заданный выше, отбрасывается вместе с отменой, но значения контекста
+			// сохраняются. Отмена запроса клиентом по-прежнему прерывает обработку
+			ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), timeout)
+			defer cancel()
+			stop := context.AfterFunc(state.base, cancel)
+			defer stop()
+			state.current = ctx
+
+			// Ошибка означает, что исходный ResponseWriter не поддерживает сроки записи,
+			// и тогда действует таймаут записи сервера
+			_ = http.NewResponseController(w).SetWriteDeadline(time.Now().Add(timeout + writeDeadlineMargin))
+
+			dw := &deadlineWriter{ResponseWriter: w}
+			next.ServeHTTP(dw, r.WithContext(ctx))
+
+			// Ответ об истечении срока отправляет только Deadline, чей срок действовал,
+			// и только если обработчик еще ничего не записал
+			if !dw.written && state.current == ctx && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				apperrors.Write(dw, r, apperrors.CodeRequestTimeout, "Request timed out")
+			}
+		})
+	}
+}
+
+// deadlineWriter - обертка для http.ResponseWriter, которая отслеживает, начата ли запись ответа
+type deadlineWriter struct {
+	http.ResponseWriter
+	written bool
+}
+
+// WriteHeader отправляет заголовки ответа
+func (w *deadlineWriter) WriteHeader(statusCode int) {
+	w.written = true
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+// Write записывает тело ответа; первая запись отправляет заголовки
+func (w *deadlineWriter) Write(b []byte) (int, error) {
+	w.written = true
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush отправляет записанную часть ответа, если исходный ResponseWriter это поддерживает
+func (w *deadlineWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		w.written = true
+		flusher.Flush()
+	}
+}
+
+// Unwrap возвращает исходный ResponseWriter для http.ResponseController
+func (w *deadlineWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
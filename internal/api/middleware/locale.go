@@ -0,0 +1,16 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/nurlyy/task_manager/pkg/validator"
+)
+
+// Locale определяет язык сообщений об ошибках по заголовку Accept-Language
+// и сохраняет его в контексте запроса
+func Locale(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		locale := validator.ParseAcceptLanguage(r.Header.Get("Accept-Language"))
+		next.ServeHTTP(w, r.WithContext(validator.WithLocale(r.Context(), locale)))
+	})
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/nurlyy/task_manager/pkg/auth"
 	"github.com/nurlyy/task_manager/pkg/logger"
 )
 
@@ -58,7 +59,7 @@ func (m *LoggingMiddleware) LogRequest(next http.Handler) http.Handler {
 		)
 
 		// Получаем информацию о пользователе из контекста (если есть)
-		userID, userExists := r.Context().Value("user_id").(string)
+		actor, userExists := auth.ActorFromContext(r.Context())
 
 		// Добавляем ID запроса в заголовок ответа
 		w.Header().Set("X-Request-ID", requestID)
@@ -81,7 +82,7 @@ func (m *LoggingMiddleware) LogRequest(next http.Handler) http.Handler {
 
 		// Добавляем информацию о пользователе, если она есть
 		if userExists {
-			logData["user_id"] = userID
+			logData["user_id"] = actor.ID
 		}
 
 		// 	// Выбираем уровень логирования в зависимости от кода статуса
@@ -137,6 +138,11 @@ func (rw *responseWriterWithStatus) Flush() {
 	}
 }
 
+// Unwrap возвращает исходный ResponseWriter для http.ResponseController
+func (rw *responseWriterWithStatus) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
 func (rw *responseWriterWithStatus) CloseNotify() <-chan bool {
 	if closeNotifier, ok := rw.ResponseWriter.(http.CloseNotifier); ok {
 		return closeNotifier.CloseNotify()
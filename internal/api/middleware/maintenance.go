@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
+)
+
+// maintenanceRetryAfter - значение заголовка Retry-After (в секундах) для запросов,
+// отклоненных во время обслуживания
+const maintenanceRetryAfter = "60"
+
+// MaintenanceState сообщает текущее состояние режима обслуживания
+type MaintenanceState interface {
+	Status(ctx context.Context) domain.MaintenanceStatus
+}
+
+// Maintenance отклоняет изменяющие запросы с кодом 503, пока включен режим обслуживания.
+// Запросы на чтение и пути с указанными префиксами (вход в систему, управление
+// самим режимом) обрабатываются как обычно
+func Maintenance(state MaintenanceState, exemptPrefixes ...string) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if isReadRequest(r.Method) || hasAnyPrefix(r.URL.Path, exemptPrefixes) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			status := state.Status(r.Context())
+			if !status.Enabled {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set("Retry-After", maintenanceRetryAfter)
+			apperrors.Write(w, r, apperrors.CodeMaintenance, status.Message)
+		})
+	}
+}
+
+// isReadRequest проверяет, что метод запроса не изменяет данные
+func isReadRequest(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
+
+// hasAnyPrefix проверяет, начинается ли путь с одного из префиксов
+func hasAnyPrefix(path string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
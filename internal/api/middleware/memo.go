@@ -0,0 +1,15 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/nurlyy/task_manager/internal/repository/memo"
+)
+
+// RequestMemo подключает к контексту запроса хранилище прочитанных сущностей,
+// чтобы повторные чтения пользователей и участников проектов не обращались к базе
+func RequestMemo(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(memo.WithMemo(r.Context())))
+	})
+}
@@ -0,0 +1,140 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/pkg/auth"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
+	"github.com/nurlyy/task_manager/pkg/logger"
+)
+
+// ProjectAccess определяет уровень доступа к проекту, которого требует маршрут
+type ProjectAccess int
+
+const (
+	// ProjectAccessNone - маршрут не относится к проекту
+	ProjectAccessNone ProjectAccess = iota
+	// ProjectAccessMember - пользователь должен участвовать в проекте
+	ProjectAccessMember
+	// ProjectAccessEdit - пользователь должен участвовать в проекте с правом изменять его задачи
+	ProjectAccessEdit
+	// ProjectAccessManage - пользователь должен владеть проектом или управлять им
+	ProjectAccessManage
+)
+
+// defaultProjectParam - параметр маршрута с ID проекта, если в политике не указан другой
+const defaultProjectParam = "id"
+
+// Policy описывает права, которые требуются для маршрута. Проверки выполняются по порядку:
+// аутентификация, роль, области доступа токена, доступ к проекту из параметра маршрута
+type Policy struct {
+	Roles        []domain.UserRole // Допустимые роли; администраторам доступны все маршруты
+	Scopes       []string          // Области доступа, которые должен разрешать токен
+	Project      ProjectAccess     // Требуемый доступ к проекту
+	ProjectParam string            // Параметр маршрута с ID проекта; по умолчанию "id"
+}
+
+// AccessChecker проверяет права пользователя по текущим данным
+type AccessChecker interface {
+	UserRole(ctx context.Context, userID string) (domain.UserRole, error)
+	HasProjectAccess(ctx context.Context, projectID string, userID string) bool
+	CanEditProject(ctx context.Context, projectID string, userID string) bool
+	CanManageProject(ctx context.Context, projectID string, userID string) bool
+}
+
+// Authorizer проверяет политики доступа маршрутов
+type Authorizer struct {
+	access AccessChecker
+	logger logger.Logger
+}
+
+// NewAuthorizer создает новый экземпляр Authorizer
+func NewAuthorizer(access AccessChecker, logger logger.Logger) *Authorizer {
+	return &Authorizer{
+		access: access,
+		logger: logger,
+	}
+}
+
+// Require отклоняет запросы, не удовлетворяющие политике. Подключается после аутентификации;
+// пользователь без доступа к проекту получает ответ "проект не найден", чтобы не раскрывать
+// существование чужих проектов
+func (a *Authorizer) Require(policy Policy) func(http.Handler) http.Handler {
+	projectParam := policy.ProjectParam
+	if projectParam == "" {
+		projectParam = defaultProjectParam
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			actor, ok := auth.ActorFromContext(ctx)
+			if !ok {
+				apperrors.Write(w, r, apperrors.CodeUnauthorized, "Unauthorized")
+				return
+			}
+
+			// Роль читается из базы: роль в токене могла быть отозвана после его выпуска
+			if len(policy.Roles) > 0 {
+				role, err := a.access.UserRole(ctx, actor.ID)
+				if err != nil {
+					a.logger.Warn("Failed to get user role for route policy", map[string]interface{}{
+						"user_id": actor.ID,
+					}, map[string]interface{}{
+						"error": err,
+					})
+					apperrors.Write(w, r, apperrors.CodeInsufficientRights, "Insufficient rights to perform this action")
+					return
+				}
+				if !hasRole(policy.Roles, role) {
+					apperrors.Write(w, r, apperrors.CodeInsufficientRights, "Insufficient rights to perform this action")
+					return
+				}
+			}
+
+			if !actor.HasScopes(policy.Scopes...) {
+				apperrors.Write(w, r, apperrors.CodeInsufficientScope, "Token scopes do not allow this action")
+				return
+			}
+
+			if policy.Project != ProjectAccessNone {
+				projectID := chi.URLParam(r, projectParam)
+				if projectID == "" {
+					apperrors.Write(w, r, apperrors.CodeMissingID, "Project ID is required")
+					return
+				}
+				if !a.access.HasProjectAccess(ctx, projectID, actor.ID) {
+					apperrors.Write(w, r, apperrors.CodeProjectNotFound, "Project not found")
+					return
+				}
+				if policy.Project == ProjectAccessEdit && !a.access.CanEditProject(ctx, projectID, actor.ID) {
+					apperrors.Write(w, r, apperrors.CodeInsufficientRights, "Insufficient rights to perform this action")
+					return
+				}
+				if policy.Project == ProjectAccessManage && !a.access.CanManageProject(ctx, projectID, actor.ID) {
+					apperrors.Write(w, r, apperrors.CodeInsufficientRights, "Insufficient rights to perform this action")
+					return
+				}
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// hasRole проверяет, что роль входит в список допустимых. Администраторы проходят всегда
+func hasRole(roles []domain.UserRole, role domain.UserRole) bool {
+	if role == domain.UserRoleAdmin {
+		return true
+	}
+	for _, allowed := range roles {
+		if allowed == role {
+			return true
+		}
+	}
+	return false
+}
@@ -11,6 +11,8 @@ import (
 	"time"
 
 	"github.com/go-redis/redis/v8"
+	"github.com/nurlyy/task_manager/pkg/auth"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
 	"github.com/nurlyy/task_manager/pkg/logger"
 )
 
@@ -71,7 +73,7 @@ func (m *RateLimiter) Limit(next http.Handler) http.Handler {
 		remaining, resetTime, limited, err := m.isLimited(r.Context(), key)
 		if err != nil {
 			m.logger.Error("Rate limiter error", err)
-			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			apperrors.Write(w, r, apperrors.CodeInternalError, "Internal Server Error")
 			return
 		}
 
@@ -83,7 +85,7 @@ func (m *RateLimiter) Limit(next http.Handler) http.Handler {
 		// Если лимит превышен, возвращаем ошибку
 		if limited {
 			w.Header().Set("Retry-After", strconv.Itoa(int(resetTime.Sub(time.Now()).Seconds())))
-			http.Error(w, "Rate limit exceeded", http.StatusTooManyRequests)
+			apperrors.Write(w, r, apperrors.CodeRateLimited, "Rate limit exceeded")
 			return
 		}
 
@@ -92,6 +94,31 @@ func (m *RateLimiter) Limit(next http.Handler) http.Handler {
 	})
 }
 
+// softLimitedKey - ключ контекста, которым отмечаются запросы сверх мягкого лимита
+type softLimitedKey struct{}
+
+// SoftLimit, в отличие от Limit, не отклоняет запросы сверх лимита, а отмечает их в контексте.
+// Обработчик отвечает таким запросам дешевле, например ранее подготовленными данными
+func (m *RateLimiter) SoftLimit(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _, limited, err := m.isLimited(r.Context(), m.getKey(r))
+		if err != nil {
+			m.logger.Error("Rate limiter error", err)
+		}
+		if limited {
+			r = r.WithContext(context.WithValue(r.Context(), softLimitedKey{}, true))
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// IsSoftLimited сообщает, что запрос превысил мягкий лимит SoftLimit
+func IsSoftLimited(ctx context.Context) bool {
+	limited, _ := ctx.Value(softLimitedKey{}).(bool)
+	return limited
+}
+
 // getKey формирует ключ для ограничения в зависимости от стратегии
 func (m *RateLimiter) getKey(r *http.Request) string {
 	var key string
@@ -100,7 +127,7 @@ func (m *RateLimiter) getKey(r *http.Request) string {
 	switch m.config.Strategy {
 	case RateLimitUser:
 		// Если пользователь аутентифицирован, используем его ID
-		if userID, ok := r.Context().Value("user_id").(string); ok && userID != "" {
+		if userID := auth.ActorID(r.Context()); userID != "" {
 			key = fmt.Sprintf("rate_limit:user:%s", userID)
 		} else {
 			// Если пользователь не аутентифицирован, используем IP
@@ -108,7 +135,7 @@ func (m *RateLimiter) getKey(r *http.Request) string {
 		}
 	case RateLimitCombined:
 		// Комбинируем IP и ID пользователя (если есть)
-		if userID, ok := r.Context().Value("user_id").(string); ok && userID != "" {
+		if userID := auth.ActorID(r.Context()); userID != "" {
 			key = fmt.Sprintf("rate_limit:combined:%s:%s", ip, userID)
 		} else {
 			key = fmt.Sprintf("rate_limit:ip:%s", ip)
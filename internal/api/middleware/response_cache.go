@@ -0,0 +1,248 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nurlyy/task_manager/internal/repository/cache"
+	"github.com/nurlyy/task_manager/pkg/auth"
+	"github.com/nurlyy/task_manager/pkg/logger"
+)
+
+// cacheStatusHeader сообщает, как получен ответ: HIT, STALE или MISS
+const cacheStatusHeader = "X-Cache"
+
+// revalidateTimeout ограничивает фоновое обновление устаревшего ответа
+const revalidateTimeout = 30 * time.Second
+
+// ResponseCacheRule описывает кэширование ответов эндпоинта
+type ResponseCacheRule struct {
+	// Name отличает ключи эндпоинта в кэше
+	Name string
+	// TTL - время, в течение которого ответ отдается без обновления
+	TTL time.Duration
+	// StaleWhileRevalidate - время после TTL, в течение которого отдается
+	// устаревший ответ, а свежий готовится в фоне
+	StaleWhileRevalidate time.Duration
+	// Public означает, что ответ одинаков для всех; иначе ответы кэшируются для каждого пользователя
+	Public bool
+	// SurrogateKeys возвращает суррогатные ключи ответа, по которым его сбрасывают изменения данных
+	SurrogateKeys func(r *http.Request) []string
+}
+
+// ResponseCache кэширует в Redis успешные ответы на GET-запросы часто читаемых эндпоинтов.
+// Ответы связываются с суррогатными ключами (например, project:{id}) и удаляются,
+// когда события об изменении задач и проектов сбрасывают эти ключи
+type ResponseCache struct {
+	store  *cache.RedisRepository
+	logger logger.Logger
+}
+
+// NewResponseCache создает новый экземпляр ResponseCache
+func NewResponseCache(store *cache.RedisRepository, logger logger.Logger) *ResponseCache {
+	return &ResponseCache{
+		store:  store,
+		logger: logger,
+	}
+}
+
+// Cache возвращает middleware, кэширующий ответы эндпоинта по правилу rule
+func (c *ResponseCache) Cache(rule ResponseCacheRule) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			key, ok := responseCacheKey(rule, r)
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			cached, err := c.store.GetHTTPResponse(r.Context(), key)
+			if err == nil {
+				if cached.IsFresh(time.Now()) {
+					c.write(w, rule, cached, "HIT")
+					return
+				}
+				c.revalidate(rule, next, r, key)
+				c.write(w, rule, cached, "STALE")
+				return
+			}
+			if !errors.Is(err, cache.ErrKeyNotFound) {
+				c.logger.Warn("Failed to read cached response", map[string]interface{}{
+					"cache": rule.Name,
+				}, map[string]interface{}{
+					"error": err,
+				})
+			}
+
+			response := record(next, r)
+			c.write(w, rule, response, "MISS")
+			c.save(r.Context(), rule, r, key, response)
+		})
+	}
+}
+
+// revalidate обновляет устаревший ответ в фоне. Блокировка в Redis не дает
+// нескольким запросам обновлять один ответ одновременно
+func (c *ResponseCache) revalidate(rule ResponseCacheRule, next http.Handler, r *http.Request, key string) {
+	lockKey := "http:revalidate:" + key
+	acquired, err := c.store.AcquireLock(r.Context(), lockKey, revalidateTimeout)
+	if err != nil || !acquired {
+		return
+	}
+
+	// Клиент получает устаревший ответ сразу, поэтому запрос на обновление
+	// не должен отменяться вместе с исходным запросом
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(r.Context()), revalidateTimeout)
+	req := r.Clone(ctx)
+
+	go func() {
+		defer cancel()
+		defer func() {
+			if err := c.store.ReleaseLock(ctx, lockKey); err != nil {
+				c.logger.Warn("Failed to release revalidation lock", map[string]interface{}{
+					"cache": rule.Name,
+				}, map[string]interface{}{
+					"error": err,
+				})
+			}
+		}()
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				c.logger.Error("Panic while revalidating cached response", fmt.Errorf("%v", recovered), map[string]interface{}{
+					"cache": rule.Name,
+				})
+			}
+		}()
+
+		c.save(ctx, rule, req, key, record(next, req))
+	}()
+}
+
+// save сохраняет успешный ответ вместе с его суррогатными ключами
+func (c *ResponseCache) save(ctx context.Context, rule ResponseCacheRule, r *http.Request, key string, response *cache.CachedResponse) {
+	if response.StatusCode != http.StatusOK {
+		return
+	}
+
+	var surrogateKeys []string
+	if rule.SurrogateKeys != nil {
+		surrogateKeys = append(surrogateKeys, rule.SurrogateKeys(r)...)
+	}
+	surrogateKeys = append(surrogateKeys, strings.Fields(response.Header.Get(cache.SurrogateKeyHeader))...)
+	response.Header.Del(cache.SurrogateKeyHeader)
+
+	now := time.Now()
+	response.StoredAt = now
+	response.FreshUntil = now.Add(rule.TTL)
+
+	if err := c.store.SetHTTPResponse(ctx, key, response, surrogateKeys, rule.TTL+rule.StaleWhileRevalidate); err != nil {
+		c.logger.Warn("Failed to cache response", map[string]interface{}{
+			"cache": rule.Name,
+		}, map[string]interface{}{
+			"error": err,
+		})
+	}
+}
+
+// write отправляет клиенту сохраненный или только что полученный ответ
+func (c *ResponseCache) write(w http.ResponseWriter, rule ResponseCacheRule, response *cache.CachedResponse, status string) {
+	header := w.Header()
+	for name, values := range response.Header {
+		if name == cache.SurrogateKeyHeader {
+			continue
+		}
+		header[name] = values
+	}
+	header.Set(cacheStatusHeader, status)
+	if !response.StoredAt.IsZero() {
+		header.Set("Age", strconv.Itoa(int(time.Since(response.StoredAt).Seconds())))
+	}
+	if response.StatusCode == http.StatusOK && header.Get("Cache-Control") == "" {
+		header.Set("Cache-Control", cacheControl(rule))
+	}
+
+	w.WriteHeader(response.StatusCode)
+	if _, err := w.Write(response.Body); err != nil {
+		c.logger.Error("Failed to write response", err, map[string]interface{}{
+			"cache": rule.Name,
+		})
+	}
+}
+
+// cacheControl возвращает заголовок Cache-Control для клиентов и прокси. Персональные
+// ответы браузер должен перепроверять, так как сброс кэша на сервере до него не доходит
+func cacheControl(rule ResponseCacheRule) string {
+	if !rule.Public {
+		return "private, no-cache"
+	}
+	return fmt.Sprintf("public, max-age=%d, stale-while-revalidate=%d",
+		int(rule.TTL.Seconds()), int(rule.StaleWhileRevalidate.Seconds()))
+}
+
+// responseCacheKey возвращает ключ ответа: эндпоинт, владелец ответа и хеш URL
+// с заголовком Accept, от которого зависит формат ответа
+func responseCacheKey(rule ResponseCacheRule, r *http.Request) (string, bool) {
+	owner := "public"
+	if !rule.Public {
+		owner = auth.ActorID(r.Context())
+		if owner == "" {
+			return "", false
+		}
+	}
+
+	hash := sha256.Sum256([]byte(r.URL.Path + "?" + r.URL.Query().Encode() + "\n" + r.Header.Get("Accept")))
+	return rule.Name + ":" + owner + ":" + hex.EncodeToString(hash[:]), true
+}
+
+// responseRecorder запоминает ответ обработчика, чтобы сохранить его в кэш
+type responseRecorder struct {
+	header     http.Header
+	statusCode int
+	body       bytes.Buffer
+}
+
+// Header возвращает заголовки ответа
+func (rec *responseRecorder) Header() http.Header {
+	return rec.header
+}
+
+// WriteHeader запоминает код ответа
+func (rec *responseRecorder) WriteHeader(statusCode int) {
+	if rec.statusCode == 0 {
+		rec.statusCode = statusCode
+	}
+}
+
+// Write запоминает тело ответа
+func (rec *responseRecorder) Write(data []byte) (int, error) {
+	rec.WriteHeader(http.StatusOK)
+	return rec.body.Write(data)
+}
+
+// record выполняет обработчик и возвращает его ответ
+func record(next http.Handler, r *http.Request) *cache.CachedResponse {
+	rec := &responseRecorder{header: make(http.Header)}
+	next.ServeHTTP(rec, r)
+	if rec.statusCode == 0 {
+		rec.statusCode = http.StatusOK
+	}
+
+	return &cache.CachedResponse{
+		StatusCode: rec.statusCode,
+		Header:     rec.header,
+		Body:       rec.body.Bytes(),
+	}
+}
@@ -0,0 +1,350 @@
+// Package query разбирает параметры фильтрации, сортировки и пагинации списков
+// одинаково для всех обработчиков. Некорректные значения не отбрасываются молча,
+// а накапливаются как ошибки полей, чтобы клиент получил ответ validation_failed
+package query
+
+import (
+	"encoding/base64"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/pkg/datefilter"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
+	"github.com/nurlyy/task_manager/pkg/validator"
+)
+
+// Параметры пагинации по умолчанию
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+// Допустимые направления сортировки
+const (
+	SortAsc  = "asc"
+	SortDesc = "desc"
+)
+
+// dateLayout - формат даты без времени
+const dateLayout = "2006-01-02"
+
+// ErrInvalidCursor возвращается, если курсор пагинации не создан сервером
+var ErrInvalidCursor = apperrors.New(apperrors.CodeInvalidCursor, "invalid pagination cursor")
+
+// Parser разбирает параметры запроса и накапливает ошибки полей
+type Parser struct {
+	values url.Values
+	errs   *validator.Errors
+}
+
+// New создает парсер параметров запроса; сообщения об ошибках переводятся
+// на язык из контекста запроса
+func New(r *http.Request) *Parser {
+	return &Parser{
+		values: r.URL.Query(),
+		errs:   validator.NewErrors(r.Context()),
+	}
+}
+
+// Errors возвращает ошибки разобранных параметров
+func (p *Parser) Errors() []apperrors.FieldError {
+	return p.errs.FieldErrors()
+}
+
+// HasErrors сообщает, были ли некорректные параметры
+func (p *Parser) HasErrors() bool {
+	return p.errs.Len() > 0
+}
+
+// String возвращает значение параметра или nil, если он не задан
+func (p *Parser) String(name string) *string {
+	value := strings.TrimSpace(p.values.Get(name))
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+// Values возвращает непустые значения повторяющегося параметра (?tag=a&tag=b)
+func (p *Parser) Values(name string) []string {
+	var values []string
+	for _, value := range p.values[name] {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// List возвращает значения параметра, заданные через запятую или повтором параметра
+func (p *Parser) List(name string) []string {
+	var values []string
+	for _, value := range p.values[name] {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				values = append(values, item)
+			}
+		}
+	}
+	return values
+}
+
+// Bool возвращает значение логического параметра; незаданный параметр равен false
+func (p *Parser) Bool(name string) bool {
+	value := p.String(name)
+	if value == nil {
+		return false
+	}
+	parsed, err := strconv.ParseBool(*value)
+	if err != nil {
+		p.errs.AddRule(name, "boolean", "")
+		return false
+	}
+	return parsed
+}
+
+// Int возвращает целое значение параметра в границах [min, max] или def, если он не задан
+func (p *Parser) Int(name string, def, min, max int) int {
+	value := p.String(name)
+	if value == nil {
+		return def
+	}
+	parsed, err := strconv.Atoi(*value)
+	switch {
+	case err != nil:
+		p.errs.AddRule(name, "numeric", "")
+		return def
+	case parsed < min:
+		p.errs.AddRule(name, "min", strconv.Itoa(min))
+		return def
+	case parsed > max:
+		p.errs.AddRule(name, "max", strconv.Itoa(max))
+		return def
+	}
+	return parsed
+}
+
+// Date возвращает дату из параметра в формате YYYY-MM-DD или RFC 3339
+func (p *Parser) Date(name string) *time.Time {
+	date, _ := p.date(name)
+	return date
+}
+
+// DateRange возвращает границы периода. Конечная дата без времени включает весь день,
+// а конец периода раньше начала считается ошибкой
+func (p *Parser) DateRange(fromName, toName string) (*time.Time, *time.Time) {
+	from, _ := p.date(fromName)
+	to, dateOnly := p.date(toName)
+	if to != nil && dateOnly {
+		endOfDay := to.AddDate(0, 0, 1).Add(-time.Nanosecond)
+		to = &endOfDay
+	}
+	if from != nil && to != nil && to.Before(*from) {
+		p.errs.AddRule(toName, "after_field", fromName)
+		return from, nil
+	}
+	return from, to
+}
+
+// date разбирает дату и сообщает, была ли она задана без времени
+func (p *Parser) date(name string) (*time.Time, bool) {
+	value := p.String(name)
+	if value == nil {
+		return nil, false
+	}
+	if parsed, err := time.Parse(dateLayout, *value); err == nil {
+		return &parsed, true
+	}
+	if parsed, err := time.Parse(time.RFC3339, *value); err == nil {
+		return &parsed, false
+	}
+	p.errs.AddRule(name, "date", "")
+	return nil, false
+}
+
+// DateFilters разбирает условия по датам, заданные через запятую или повтором параметра,
+// например when=due:<7d,updated:>30d. Формат условий описан в пакете datefilter
+func (p *Parser) DateFilters(name string) []datefilter.Condition {
+	var conditions []datefilter.Condition
+	for _, value := range p.List(name) {
+		cond, err := datefilter.Parse(value)
+		if err != nil {
+			p.errs.AddRule(name, "date_filter", "")
+			return nil
+		}
+		conditions = append(conditions, cond)
+	}
+	return conditions
+}
+
+// Enum возвращает значение параметра, если оно входит в список допустимых
+func Enum[T ~string](p *Parser, name string, allowed ...T) *T {
+	value := p.String(name)
+	if value == nil {
+		return nil
+	}
+	for _, candidate := range allowed {
+		if string(candidate) == *value {
+			return &candidate
+		}
+	}
+	p.errs.AddRule(name, "oneof", joinValues(allowed))
+	return nil
+}
+
+// EnumList возвращает значения параметра, заданные через запятую, если все они допустимы
+func EnumList[T ~string](p *Parser, name string, allowed ...T) []T {
+	var values []T
+	for _, value := range p.List(name) {
+		found := false
+		for _, candidate := range allowed {
+			if string(candidate) == value {
+				values = append(values, candidate)
+				found = true
+				break
+			}
+		}
+		if !found {
+			p.errs.AddRule(name, "oneof", joinValues(allowed))
+			return nil
+		}
+	}
+	return values
+}
+
+// Sort возвращает поле (sort_by) и направление (sort_order) сортировки.
+// Поле проверяется по списку допустимых, направление приводится к нижнему регистру
+func (p *Parser) Sort(allowed ...string) (*string, *string) {
+	sortBy := Enum(p, "sort_by", allowed...)
+
+	order := p.String("sort_order")
+	if order == nil {
+		return sortBy, nil
+	}
+	lowered := strings.ToLower(*order)
+	if lowered != SortAsc && lowered != SortDesc {
+		p.errs.AddRule("sort_order", "oneof", SortAsc+" "+SortDesc)
+		return sortBy, nil
+	}
+	return sortBy, &lowered
+}
+
+// SortKeys разбирает сортировку по нескольким полям: поля через запятую в виде
+// поле[:asc|desc][:nulls_first|nulls_last], например priority:desc,due_date:asc:nulls_last.
+// Поля проверяются по списку допустимых и не должны повторяться
+func (p *Parser) SortKeys(name string, maxKeys int, allowed ...string) []domain.SortKey {
+	items := p.List(name)
+	if len(items) > maxKeys {
+		p.errs.AddRule(name, "max_items", strconv.Itoa(maxKeys))
+		return nil
+	}
+
+	keys := make([]domain.SortKey, 0, len(items))
+	seen := make(map[string]bool, len(items))
+	for _, item := range items {
+		parts := strings.Split(strings.ToLower(item), ":")
+		key := domain.SortKey{Field: parts[0]}
+		if !contains(allowed, key.Field) {
+			p.errs.AddRule(name, "oneof", strings.Join(allowed, " "))
+			return nil
+		}
+		if seen[key.Field] {
+			p.errs.AddRule(name, "invalid", "")
+			return nil
+		}
+		seen[key.Field] = true
+
+		for _, modifier := range parts[1:] {
+			switch modifier {
+			case SortAsc:
+				key.Desc = false
+			case SortDesc:
+				key.Desc = true
+			case "nulls_first":
+				key.Nulls = domain.NullsFirst
+			case "nulls_last":
+				key.Nulls = domain.NullsLast
+			default:
+				p.errs.AddRule(name, "oneof", "asc desc nulls_first nulls_last")
+				return nil
+			}
+		}
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// Page разбирает параметры пагинации: page, page_size, count и cursor.
+// Курсор из next_cursor задает страницу и ее размер и по умолчанию отключает подсчет.
+// Для некорректного курсора возвращает ErrInvalidCursor
+func (p *Parser) Page() (domain.PageRequest, error) {
+	page := domain.PageRequest{
+		Page:     p.Int("page", 1, 1, math.MaxInt32),
+		PageSize: p.Int("page_size", DefaultPageSize, 1, MaxPageSize),
+	}
+
+	if cursor := p.String("cursor"); cursor != nil {
+		number, size, ok := DecodeCursor(*cursor)
+		if !ok {
+			return page, ErrInvalidCursor
+		}
+		page.Page, page.PageSize, page.SkipCount = number, size, true
+	}
+
+	if p.String("count") != nil {
+		page.SkipCount = !p.Bool("count")
+	}
+
+	return page, nil
+}
+
+// EncodeCursor кодирует номер и размер страницы в непрозрачный курсор
+func EncodeCursor(page, pageSize int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d:%d", page, pageSize)))
+}
+
+// DecodeCursor разбирает курсор, созданный EncodeCursor
+func DecodeCursor(cursor string) (int, int, bool) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, 0, false
+	}
+	pageParam, sizeParam, found := strings.Cut(string(raw), ":")
+	if !found {
+		return 0, 0, false
+	}
+	page, err := strconv.Atoi(pageParam)
+	if err != nil || page < 1 {
+		return 0, 0, false
+	}
+	pageSize, err := strconv.Atoi(sizeParam)
+	if err != nil || pageSize < 1 || pageSize > MaxPageSize {
+		return 0, 0, false
+	}
+	return page, pageSize, true
+}
+
+// contains проверяет, входит ли значение в список
+func contains(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
+
+// joinValues перечисляет допустимые значения для сообщения об ошибке
+func joinValues[T ~string](values []T) string {
+	items := make([]string, len(values))
+	for i, value := range values {
+		items[i] = string(value)
+	}
+	return strings.Join(items, " ")
+}
@@ -2,16 +2,21 @@ package api
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/cors"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
 
 	"github.com/nurlyy/task_manager/internal/api/handlers"
 	mw "github.com/nurlyy/task_manager/internal/api/middleware"
+	"github.com/nurlyy/task_manager/internal/domain"
 	"github.com/nurlyy/task_manager/internal/repository"
+	"github.com/nurlyy/task_manager/internal/repository/cache"
 	"github.com/nurlyy/task_manager/internal/service"
 	"github.com/nurlyy/task_manager/pkg/auth"
 	"github.com/nurlyy/task_manager/pkg/config"
@@ -27,20 +32,57 @@ type Server struct {
 	baseHandler  handlers.BaseHandler
 	services     *Services
 	repositories *Repositories
+	httpServer   *http.Server
 }
 
 // Services содержит все сервисы для обработчиков API
 type Services struct {
-	UserService         *service.UserService
-	ProjectService      *service.ProjectService
-	TaskService         *service.TaskService
-	CommentService      *service.CommentService
-	NotificationService *service.NotificationService
-	TelegramService     *service.TelegramSender
+	UserService           *service.UserService
+	ProjectService        *service.ProjectService
+	TaskService           *service.TaskService
+	CommentService        *service.CommentService
+	NotificationService   *service.NotificationService
+	TelegramService       *service.TelegramSender
+	UnsubscribeService    *service.UnsubscribeService
+	SearchService         *service.SearchService
+	TaskFormService       *service.TaskFormService
+	TaskTemplateService   *service.TaskTemplateService
+	ScheduledTaskService  *service.ScheduledTaskService
+	InboundEmailService   *service.InboundEmailService
+	ProjectWebhookService *service.ProjectWebhookService
+	IncidentService       *service.IncidentService
+	TeamsService          *service.TeamsService
+	DiscordService        *service.DiscordService
+	MatrixService         *service.MatrixService
+	SMSService            *service.SMSService
+	FeedbackService       *service.FeedbackService
+	RoadmapService        *service.RoadmapService
+	EpicService           *service.EpicService
+	BoardService          *service.BoardService
+	SprintService         *service.SprintService
+	OKRService            *service.OKRService
+	DecisionService       *service.DecisionService
+	WikiService           *service.WikiService
+	MeetingNoteService    *service.MeetingNoteService
+	ProjectSplitService   *service.ProjectSplitService
+	MaintenanceService    *service.MaintenanceService
+	FeatureFlagService    *service.FeatureFlagService
+	ProjectRosterService  *service.ProjectRosterService
+	InboxService          *service.InboxService
+	StatusService         *service.StatusService
+	ConsistencyService    *service.ConsistencyService
+	JobRunService         *service.JobRunService
+	AuditService          *service.AuditService
+	ProjectViewService    *service.ProjectViewService
+	TaskRecurrenceService *service.TaskRecurrenceService
+	AttachmentService     *service.AttachmentService
+	AccessService         *service.AccessService
+	UnreadCountHub        *service.UnreadCountHub
 }
 
 type Repositories struct {
 	TelegramRepository repository.TelegramRepository
+	CacheRepository    *cache.RedisRepository
 }
 
 // NewServer создает новый экземпляр сервера API
@@ -60,6 +102,18 @@ func NewServer(config *config.Config, logger logger.Logger, jwtManager *auth.JWT
 	// Настраиваем маршрутизацию
 	server.setupRoutes()
 
+	// Таймаут записи задает верхнюю границу по умолчанию; маршруты с собственным
+	// сроком обработки продлевают его для своих запросов
+	server.httpServer = &http.Server{
+		Addr:              ":" + config.HTTP.Port,
+		Handler:           server.handler(),
+		ReadTimeout:       config.HTTP.ReadTimeout,
+		ReadHeaderTimeout: config.HTTP.ReadHeaderTimeout,
+		WriteTimeout:      config.HTTP.WriteTimeout,
+		IdleTimeout:       config.HTTP.IdleTimeout,
+		MaxHeaderBytes:    config.HTTP.MaxHeaderBytes,
+	}
+
 	return server
 }
 
@@ -69,21 +123,102 @@ func (s *Server) setupRoutes() {
 	authHandler := handlers.NewAuthHandler(s.baseHandler, s.services.UserService)
 	userHandler := handlers.NewUserHandler(s.baseHandler, s.services.UserService)
 	projectHandler := handlers.NewProjectHandler(s.baseHandler, s.services.ProjectService)
-	taskHandler := handlers.NewTaskHandler(s.baseHandler, s.services.TaskService)
+	taskHandler := handlers.NewTaskHandler(s.baseHandler, s.services.TaskService, s.services.InboxService, s.services.TaskRecurrenceService)
 	commentHandler := handlers.NewCommentHandler(s.baseHandler, s.services.CommentService)
-	notificationHandler := handlers.NewNotificationHandler(s.baseHandler, s.services.NotificationService)
+	attachmentHandler := handlers.NewAttachmentHandler(s.baseHandler, s.services.AttachmentService)
+	notificationHandler := handlers.NewNotificationHandler(s.baseHandler, s.services.NotificationService, s.services.UnreadCountHub)
+	unsubscribeHandler := handlers.NewUnsubscribeHandler(s.baseHandler, s.services.UnsubscribeService)
+	searchHandler := handlers.NewSearchHandler(s.baseHandler, s.services.SearchService)
+	taskFormHandler := handlers.NewTaskFormHandler(s.baseHandler, s.services.TaskFormService)
+	taskTemplateHandler := handlers.NewTaskTemplateHandler(s.baseHandler, s.services.TaskTemplateService)
+	scheduledTaskHandler := handlers.NewScheduledTaskHandler(s.baseHandler, s.services.ScheduledTaskService)
+	inboundEmailHandler := handlers.NewInboundEmailHandler(s.baseHandler, s.services.InboundEmailService)
+	projectWebhookHandler := handlers.NewProjectWebhookHandler(s.baseHandler, s.services.ProjectWebhookService)
+	incidentHandler := handlers.NewIncidentHandler(s.baseHandler, s.services.IncidentService)
+	teamsHandler := handlers.NewTeamsHandler(s.baseHandler, s.services.TeamsService)
+	discordHandler := handlers.NewDiscordHandler(s.baseHandler, s.services.DiscordService)
+	matrixHandler := handlers.NewMatrixHandler(s.baseHandler, s.services.MatrixService)
+	smsHandler := handlers.NewSMSHandler(s.baseHandler, s.services.SMSService)
+	feedbackHandler := handlers.NewFeedbackHandler(s.baseHandler, s.services.FeedbackService)
+	roadmapHandler := handlers.NewRoadmapHandler(s.baseHandler, s.services.RoadmapService)
+	epicHandler := handlers.NewEpicHandler(s.baseHandler, s.services.EpicService)
+	boardHandler := handlers.NewBoardHandler(s.baseHandler, s.services.BoardService)
+	sprintHandler := handlers.NewSprintHandler(s.baseHandler, s.services.SprintService)
+	okrHandler := handlers.NewOKRHandler(s.baseHandler, s.services.OKRService)
+	decisionHandler := handlers.NewDecisionHandler(s.baseHandler, s.services.DecisionService)
+	wikiHandler := handlers.NewWikiHandler(s.baseHandler, s.services.WikiService)
+	meetingNoteHandler := handlers.NewMeetingNoteHandler(s.baseHandler, s.services.MeetingNoteService)
+	projectSplitHandler := handlers.NewProjectSplitHandler(s.baseHandler, s.services.ProjectSplitService)
+	maintenanceHandler := handlers.NewMaintenanceHandler(s.baseHandler, s.services.MaintenanceService)
+	featureFlagHandler := handlers.NewFeatureFlagHandler(s.baseHandler, s.services.FeatureFlagService)
+	projectRosterHandler := handlers.NewProjectRosterHandler(s.baseHandler, s.services.ProjectRosterService)
+	inboxHandler := handlers.NewInboxHandler(s.baseHandler, s.services.InboxService)
+	statusHandler := handlers.NewStatusHandler(s.baseHandler, s.services.StatusService)
+	consistencyHandler := handlers.NewConsistencyHandler(s.baseHandler, s.services.ConsistencyService)
+	jobRunHandler := handlers.NewJobRunHandler(s.baseHandler, s.services.JobRunService)
+	auditHandler := handlers.NewAuditHandler(s.baseHandler, s.services.AuditService)
+	projectViewHandler := handlers.NewProjectViewHandler(s.baseHandler, s.services.ProjectViewService)
+	errorCatalogHandler := handlers.NewErrorCatalogHandler(s.baseHandler)
+
+	// Кэш ответов часто читаемых эндпоинтов; изменения задач и проектов сбрасывают
+	// ответы по суррогатным ключам, а TTL ограничивает устаревание остальных данных
+	responseCache := mw.NewResponseCache(s.repositories.CacheRepository, s.logger)
+	projectMetricsCache := responseCache.Cache(mw.ResponseCacheRule{
+		Name:                 "project_metrics",
+		TTL:                  time.Minute,
+		StaleWhileRevalidate: 5 * time.Minute,
+		SurrogateKeys: func(r *http.Request) []string {
+			return []string{cache.ProjectSurrogateKey(chi.URLParam(r, "id"))}
+		},
+	})
+	publicRoadmapCache := responseCache.Cache(mw.ResponseCacheRule{
+		Name:                 "public_roadmap",
+		TTL:                  service.RoadmapCacheTTL,
+		StaleWhileRevalidate: service.RoadmapCacheTTL,
+		Public:               true,
+		SurrogateKeys: func(r *http.Request) []string {
+			return []string{cache.RoadmapSurrogateKey(chi.URLParam(r, "key"))}
+		},
+	})
+	statusCache := responseCache.Cache(mw.ResponseCacheRule{
+		Name:                 "status",
+		TTL:                  service.StatusCacheTTL,
+		StaleWhileRevalidate: service.StatusCacheTTL,
+		Public:               true,
+		SurrogateKeys: func(r *http.Request) []string {
+			return []string{cache.SurrogateKeyStatus}
+		},
+	})
+	typeaheadCache := responseCache.Cache(mw.ResponseCacheRule{
+		Name:                 "typeahead",
+		TTL:                  30 * time.Second,
+		StaleWhileRevalidate: 30 * time.Second,
+		SurrogateKeys: func(r *http.Request) []string {
+			return []string{cache.SurrogateKeySearch}
+		},
+	})
 
 	telegramHandler := handlers.NewTelegramHandler(
 		s.baseHandler,
 		s.repositories.TelegramRepository,
 		s.services.TelegramService,
 		s.services.UserService,
+		s.services.TaskService,
 	)
 
 	// Инициализируем middleware
 	authMiddleware := mw.NewAuthMiddleware(s.jwtManager, s.logger)
 	loggingMiddleware := mw.NewLoggingMiddleware(s.logger)
 
+	// Политики доступа маршрутов: роль и участие в проекте проверяются по текущим данным, области
+	// доступа берутся из токена. Проверки выполняются до вызова обработчиков, поэтому обработчики
+	// и сервисы их не повторяют
+	authorizer := mw.NewAuthorizer(s.services.AccessService, s.logger)
+	adminOnly := authorizer.Require(mw.Policy{Roles: []domain.UserRole{domain.UserRoleAdmin}})
+	projectMember := authorizer.Require(mw.Policy{Project: mw.ProjectAccessMember})
+	projectEditor := authorizer.Require(mw.Policy{Project: mw.ProjectAccessEdit})
+	projectManager := authorizer.Require(mw.Policy{Project: mw.ProjectAccessManage})
+
 	// Настраиваем Rate Limiter с параметрами из конфигурации
 	rateLimiter := mw.NewRateLimiter(mw.RateLimiterConfig{
 		Limit:    100,            // Ограничение запросов
@@ -91,23 +226,52 @@ func (s *Server) setupRoutes() {
 		Strategy: mw.RateLimitIP, // Стратегия по IP
 	}, nil, s.logger) // nil - без Redis, используем in-memory
 
+	// Страница статуса не отклоняет частые запросы, а отдает им последнюю собранную страницу
+	statusLimiter := mw.NewRateLimiter(mw.RateLimiterConfig{
+		Limit:    10,
+		Period:   60,
+		Strategy: mw.RateLimitIP,
+	}, nil, s.logger)
+
+	// Загрузка вложений: тело ограничено размером файла с запасом на остальные поля формы,
+	// а чтение тела и обработка могут длиться дольше обычных запросов
+	uploadLimits := []func(http.Handler) http.Handler{
+		mw.Deadline(s.config.HTTP.UploadTimeout),
+		mw.ReadTimeout(s.config.HTTP.UploadTimeout),
+		mw.LimitBody(s.config.Storage.MaxFileSize + s.config.HTTP.MaxBodyBytes),
+	}
+
 	// Запускаем задачу очистки для Rate Limiter
 	go rateLimiter.StartCleanupTask(s.config.App.Context)
+	go statusLimiter.StartCleanupTask(s.config.App.Context)
 
 	// Настраиваем middleware для всех запросов
 	s.router.Use(middleware.RequestID)
 	s.router.Use(middleware.RealIP)
 	s.router.Use(loggingMiddleware.LogRequest)
 	s.router.Use(middleware.Recoverer)
-	s.router.Use(middleware.Timeout(60 * time.Second))
+	s.router.Use(mw.Deadline(s.config.HTTP.RequestTimeout))
+	// Загрузка вложений ограничивается на своих маршрутах размером файла
+	s.router.Use(mw.LimitBody(s.config.HTTP.MaxBodyBytes, "/attachments"))
 	s.router.Use(rateLimiter.Limit)
+	// Во время обслуживания остаются доступны чтение, вход в систему, выключение режима
+	// и заметки об инцидентах на странице статуса
+	s.router.Use(mw.Maintenance(s.services.MaintenanceService,
+		"/api/v1/auth/login",
+		"/api/v1/auth/refresh",
+		"/api/v1/admin/maintenance",
+		"/api/v1/admin/status-notes",
+	))
+	s.router.Use(mw.RequestMemo)
+	s.router.Use(mw.Locale)
+	s.router.Use(mw.Compress())
 
 	// Настраиваем CORS
 	s.router.Use(cors.Handler(cors.Options{
 		AllowedOrigins:   []string{"*"}, // Разрешаем все источники
-		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
 		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
-		ExposedHeaders:   []string{"Link"},
+		ExposedHeaders:   []string{"Link", "X-Cache"},
 		AllowCredentials: true,
 		MaxAge:           300, // Максимальное время кеширования CORS preflight запросов
 	}))
@@ -118,6 +282,9 @@ func (s *Server) setupRoutes() {
 		w.Write([]byte(`{"status":"OK"}`))
 	})
 
+	// Публичная страница статуса сервиса в JSON или HTML
+	s.router.With(statusCache, statusLimiter.SoftLimit).Get("/status", statusHandler.GetStatus)
+
 	// API v1
 	s.router.Route("/api/v1", func(r chi.Router) {
 		// Публичные маршруты (без аутентификации)
@@ -125,38 +292,246 @@ func (s *Server) setupRoutes() {
 			r.Post("/auth/register", authHandler.Register)
 			r.Post("/auth/login", authHandler.Login)
 			r.Post("/auth/refresh", authHandler.RefreshToken)
-			// r.Post("/webhook/telegram", telegramHandler.WebhookHandler)
+
+			// Отписка от email-уведомлений по подписанной ссылке: GET показывает страницу
+			// подтверждения, настройки меняет только POST
+			r.Get("/unsubscribe", unsubscribeHandler.ConfirmUnsubscribe)
+			r.Post("/unsubscribe", unsubscribeHandler.Unsubscribe)
+
+			// Входящие вебхуки проектов: запрос подписывается ключом вебхука
+			r.Post("/hooks/{id}", projectWebhookHandler.ReceiveWebhook)
+
+			// Вебхуки PagerDuty и Opsgenie
+			r.Post("/integrations/incidents/{id}", incidentHandler.ReceiveIncidentWebhook)
+
+			// Публичный портал обратной связи проекта
+			r.Get("/public/feedback/{key}", feedbackHandler.GetPublicFeedbackPortal)
+			r.Post("/public/feedback/{key}", feedbackHandler.SubmitFeedback)
+
+			// Публичная дорожная карта проекта в JSON или HTML
+			r.With(publicRoadmapCache).Get("/public/roadmap/{key}", roadmapHandler.GetPublicRoadmap)
+
+			// Webhook Telegram-бота: команды и нажатия кнопок под уведомлениями
+			r.Post("/webhook/telegram", telegramHandler.WebhookHandler)
+
+			// Каталог кодов ошибок API; на него ссылается поле docs_url ответов с ошибкой
+			r.Get("/errors", errorCatalogHandler.ListErrorCodes)
+			r.Get("/errors/{code}", errorCatalogHandler.GetErrorCode)
 		})
 
 		// Защищенные маршруты (требуют аутентификации)
 		r.Group(func(r chi.Router) {
 			r.Use(authMiddleware.Authenticate)
+			r.Use(mw.Audit(s.services.AuditService))
 
 			// Маршруты для текущего пользователя
 			r.Get("/auth/me", authHandler.GetCurrentUser)
 			r.Post("/auth/change-password", authHandler.ChangePassword)
 
+			// Принятие приглашения в проект
+			r.Post("/invitations/{token}/accept", projectRosterHandler.AcceptInvitation)
+
 			// Маршруты для пользователей
 			r.Route("/users", func(r chi.Router) {
+				r.Get("/directory", userHandler.GetUserDirectory)
+				r.Get("/search", searchHandler.SearchPeople)
 				r.Get("/{id}", userHandler.GetUser)
 				r.Put("/{id}", userHandler.UpdateUser)
 				r.Delete("/{id}", userHandler.DeleteUser)
 				r.Get("/", userHandler.ListUsers)
+				r.Get("/{id}/manager-chain", userHandler.GetReportingChain)
+				r.Get("/{id}/reports", userHandler.GetUserReports)
+				r.Put("/{id}/manager", userHandler.SetUserManager)
+			})
+
+			// Журнал аудита изменений (только для администраторов)
+			r.With(adminOnly).Get("/audit", auditHandler.ListAudit)
+			r.With(adminOnly, mw.Deadline(s.config.HTTP.ExportTimeout)).Get("/audit/export", auditHandler.ExportAudit)
+
+			// Флаги функциональности для текущего пользователя
+			r.Get("/features", featureFlagHandler.GetMyFeatures)
+
+			// Администрирование: режим обслуживания, флаги функциональности, заметки страницы статуса
+			// проверка согласованности данных и история запусков планировщика
+			r.Route("/admin", func(r chi.Router) {
+				r.Use(adminOnly)
+				r.Get("/maintenance", maintenanceHandler.GetMaintenance)
+				r.Put("/maintenance", maintenanceHandler.SetMaintenance)
+				r.Get("/features", featureFlagHandler.ListFeatureFlags)
+				r.Put("/features/{name}", featureFlagHandler.OverrideFeatureFlag)
+				r.Delete("/features/{name}", featureFlagHandler.ResetFeatureFlag)
+				r.Get("/status-notes", statusHandler.ListStatusNotes)
+				r.Post("/status-notes", statusHandler.CreateStatusNote)
+				r.Put("/status-notes/{id}", statusHandler.UpdateStatusNote)
+				r.Delete("/status-notes/{id}", statusHandler.DeleteStatusNote)
+				r.Get("/consistency", consistencyHandler.GetConsistencyReport)
+				r.Post("/consistency", consistencyHandler.StartConsistencyCheck)
+				r.Get("/jobs", jobRunHandler.ListJobs)
+				r.Get("/jobs/runs", jobRunHandler.ListJobRuns)
 			})
 
-			// Маршруты для проектов
+			// Маршруты для проектов. Маршруты конкретного проекта сгруппированы по уровню доступа,
+			// который проверяется политикой по ID проекта из URL
 			r.Route("/projects", func(r chi.Router) {
 				r.Post("/", projectHandler.CreateProject)
-				r.Get("/{id}", projectHandler.GetProject)
-				r.Put("/{id}", projectHandler.UpdateProject)
-				r.Delete("/{id}", projectHandler.DeleteProject)
 				r.Get("/", projectHandler.ListProjects)
-				r.Get("/{id}/metrics", projectHandler.GetProjectMetrics)
 
-				// Маршруты для участников проекта
-				r.Post("/{id}/members", projectHandler.AddProjectMember)
-				r.Put("/{id}/members/{member_id}", projectHandler.UpdateProjectMember)
-				r.Delete("/{id}/members/{member_id}", projectHandler.RemoveProjectMember)
+				// Разделение проекта (только для администраторов)
+				r.With(adminOnly).Post("/{id}/split", projectSplitHandler.SplitProject)
+
+				// Просмотр проекта и действия, доступные всем участникам
+				r.Group(func(r chi.Router) {
+					r.Use(projectMember)
+					r.Get("/{id}", projectHandler.GetProject)
+					r.With(projectMetricsCache).Get("/{id}/metrics", projectHandler.GetProjectMetrics)
+					r.Get("/{id}/metrics/history", projectHandler.GetProjectMetricsHistory)
+					r.Post("/{id}/tasks/import", taskHandler.ImportProjectTasks)
+					r.Get("/{id}/backlog/age", taskHandler.GetBacklogAgeReport)
+					r.Get("/{id}/board", epicHandler.GetProjectBoard)
+					r.Get("/{id}/board/columns", boardHandler.ListColumns)
+					r.Patch("/{id}/board/move", boardHandler.MoveCard)
+					r.Get("/{id}/tags", taskHandler.ListProjectTags)
+					r.Get("/{id}/epics", epicHandler.ListProjectEpics)
+					r.Get("/{id}/sprints", sprintHandler.ListProjectSprints)
+					r.Get("/{id}/decisions", decisionHandler.ListProjectDecisions)
+					r.Get("/{id}/wiki", wikiHandler.GetProjectWiki)
+					r.Get("/{id}/wiki/search", wikiHandler.SearchProjectWiki)
+					r.Get("/{id}/meeting-notes", meetingNoteHandler.ListProjectMeetingNotes)
+
+					// Участник может покинуть проект сам
+					r.Delete("/{id}/members/{member_id}", projectHandler.RemoveProjectMember)
+
+					// Настройки проекта, открытые для чтения
+					r.Get("/{id}/settings/tasks", projectHandler.GetTaskSettings)
+					r.Get("/{id}/settings/inbound-email", inboundEmailHandler.GetInboundEmail)
+
+					// Форма создания задачи
+					r.Get("/{id}/task-form", taskFormHandler.GetTaskForm)
+					r.Post("/{id}/task-form/submit", taskFormHandler.SubmitTaskForm)
+
+					// Шаблоны задач проекта
+					r.Get("/{id}/task-templates", taskTemplateHandler.ListTaskTemplates)
+
+					// Отложенное создание задач; изменять и отменять их могут автор и менеджеры проекта
+					r.Get("/{id}/scheduled-tasks", scheduledTaskHandler.ListScheduledTasks)
+					r.Post("/{id}/scheduled-tasks", scheduledTaskHandler.CreateScheduledTask)
+					r.Get("/{id}/scheduled-tasks/{scheduled_id}", scheduledTaskHandler.GetScheduledTask)
+					r.Put("/{id}/scheduled-tasks/{scheduled_id}", scheduledTaskHandler.UpdateScheduledTask)
+					r.Delete("/{id}/scheduled-tasks/{scheduled_id}", scheduledTaskHandler.CancelScheduledTask)
+
+					// Сохраненные представления проекта
+					r.Get("/{id}/views", projectViewHandler.ListViews)
+					r.Get("/{id}/views/default", projectViewHandler.GetDefaultView)
+					r.Get("/{id}/views/{view_id}", projectViewHandler.GetView)
+
+					// Очередь запросов с портала обратной связи
+					r.Get("/{id}/feedback", feedbackHandler.ListFeedbackSubmissions)
+					r.Get("/{id}/feedback/{submission_id}", feedbackHandler.GetFeedbackSubmission)
+
+					// Передача владения проектом; инициатора и получателя проверяет сервис
+					r.Get("/{id}/ownership-transfer", projectHandler.GetOwnershipTransfer)
+					r.Post("/{id}/ownership-transfer", projectHandler.InitiateOwnershipTransfer)
+					r.Delete("/{id}/ownership-transfer", projectHandler.CancelOwnershipTransfer)
+					r.Post("/{id}/ownership-transfer/accept", projectHandler.AcceptOwnershipTransfer)
+					r.Post("/{id}/ownership-transfer/decline", projectHandler.DeclineOwnershipTransfer)
+				})
+
+				// Эпики, спринты, журнал решений, вики и заметки встреч (для участников, кроме наблюдателей)
+				r.Group(func(r chi.Router) {
+					r.Use(projectEditor)
+					r.Post("/{id}/epics", epicHandler.CreateEpic)
+					r.Post("/{id}/sprints", sprintHandler.CreateSprint)
+					r.Post("/{id}/decisions", decisionHandler.CreateDecision)
+					r.Post("/{id}/wiki", wikiHandler.CreateWikiPage)
+					r.Post("/{id}/meeting-notes", meetingNoteHandler.CreateMeetingNote)
+				})
+
+				// Управление проектом (для владельцев и менеджеров проекта)
+				r.Group(func(r chi.Router) {
+					r.Use(projectManager)
+					r.Put("/{id}", projectHandler.UpdateProject)
+					r.Delete("/{id}", projectHandler.DeleteProject)
+					r.Post("/{id}/archive", projectHandler.ArchiveProject)
+					r.Post("/{id}/restore", projectHandler.RestoreProject)
+					r.Post("/{id}/reprioritize", taskHandler.ReprioritizeProjectTasks)
+					r.Post("/{id}/time/recalculate", taskHandler.RecalculateProjectSpentHours)
+
+					// Колонки доски проекта
+					r.Post("/{id}/board/columns", boardHandler.CreateColumn)
+					r.Put("/{id}/board/columns/order", boardHandler.ReorderColumns)
+					r.Put("/{id}/board/columns/{column_id}", boardHandler.UpdateColumn)
+					r.Delete("/{id}/board/columns/{column_id}", boardHandler.DeleteColumn)
+
+					// Теги задач проекта
+					r.Post("/{id}/tags/merge", taskHandler.MergeProjectTags)
+					r.Put("/{id}/tags/{tag}", taskHandler.RenameProjectTag)
+					r.Delete("/{id}/tags/{tag}", taskHandler.DeleteProjectTag)
+
+					// Маршруты для участников проекта
+					r.Post("/{id}/members", projectHandler.AddProjectMember)
+					r.Post("/{id}/members/bulk", projectRosterHandler.BulkAddMembers)
+					r.Post("/{id}/members/import", projectRosterHandler.ImportMembers)
+					r.Put("/{id}/members/{member_id}", projectHandler.UpdateProjectMember)
+
+					// Настройки проекта
+					r.Get("/{id}/settings/membership-history", projectHandler.GetMembershipHistory)
+					r.Put("/{id}/settings/tasks", projectHandler.UpdateTaskSettings)
+					r.Put("/{id}/settings/task-form", taskFormHandler.UpdateTaskForm)
+					r.Delete("/{id}/settings/task-form", taskFormHandler.ResetTaskForm)
+					r.Post("/{id}/settings/inbound-email", inboundEmailHandler.GenerateInboundEmail)
+					r.Put("/{id}/settings/inbound-email", inboundEmailHandler.UpdateInboundEmail)
+					r.Delete("/{id}/settings/inbound-email", inboundEmailHandler.DeleteInboundEmail)
+					r.Get("/{id}/settings/feedback-portal", feedbackHandler.GetFeedbackPortal)
+					r.Put("/{id}/settings/feedback-portal", feedbackHandler.SaveFeedbackPortal)
+					r.Delete("/{id}/settings/feedback-portal", feedbackHandler.DeleteFeedbackPortal)
+					r.Post("/{id}/settings/feedback-portal/rotate-key", feedbackHandler.RotateFeedbackPortalKey)
+					r.Get("/{id}/settings/roadmap", roadmapHandler.GetProjectRoadmap)
+					r.Put("/{id}/settings/roadmap", roadmapHandler.SaveProjectRoadmap)
+					r.Delete("/{id}/settings/roadmap", roadmapHandler.DeleteProjectRoadmap)
+					r.Post("/{id}/settings/roadmap/rotate-key", roadmapHandler.RotateProjectRoadmapKey)
+
+					// Интеграции проекта с инцидентами и мессенджерами
+					r.Get("/{id}/settings/incidents", incidentHandler.ListIncidentIntegrations)
+					r.Get("/{id}/settings/incidents/{provider}", incidentHandler.GetIncidentIntegration)
+					r.Put("/{id}/settings/incidents/{provider}", incidentHandler.SaveIncidentIntegration)
+					r.Delete("/{id}/settings/incidents/{provider}", incidentHandler.DeleteIncidentIntegration)
+					r.Get("/{id}/settings/teams", teamsHandler.GetProjectTeamsChannel)
+					r.Put("/{id}/settings/teams", teamsHandler.SaveProjectTeamsChannel)
+					r.Delete("/{id}/settings/teams", teamsHandler.DeleteProjectTeamsChannel)
+					r.Get("/{id}/settings/discord", discordHandler.GetProjectDiscordChannel)
+					r.Put("/{id}/settings/discord", discordHandler.SaveProjectDiscordChannel)
+					r.Delete("/{id}/settings/discord", discordHandler.DeleteProjectDiscordChannel)
+					r.Get("/{id}/settings/matrix", matrixHandler.GetProjectMatrixRoom)
+					r.Put("/{id}/settings/matrix", matrixHandler.SaveProjectMatrixRoom)
+					r.Delete("/{id}/settings/matrix", matrixHandler.DeleteProjectMatrixRoom)
+
+					// Шаблоны и представления проекта
+					r.Post("/{id}/task-templates", taskTemplateHandler.CreateTaskTemplate)
+					r.Post("/{id}/views", projectViewHandler.CreateView)
+					r.Put("/{id}/views/{view_id}", projectViewHandler.UpdateView)
+					r.Delete("/{id}/views/{view_id}", projectViewHandler.DeleteView)
+
+					// Входящие вебхуки проекта
+					r.Get("/{id}/webhooks", projectWebhookHandler.ListWebhooks)
+					r.Post("/{id}/webhooks", projectWebhookHandler.CreateWebhook)
+					r.Get("/{id}/webhooks/{webhook_id}", projectWebhookHandler.GetWebhook)
+					r.Put("/{id}/webhooks/{webhook_id}", projectWebhookHandler.UpdateWebhook)
+					r.Delete("/{id}/webhooks/{webhook_id}", projectWebhookHandler.DeleteWebhook)
+					r.Post("/{id}/webhooks/{webhook_id}/rotate-secret", projectWebhookHandler.RotateWebhookSecret)
+
+					// Модерация запросов с портала обратной связи
+					r.Post("/{id}/feedback/{submission_id}/approve", feedbackHandler.ApproveFeedbackSubmission)
+					r.Post("/{id}/feedback/{submission_id}/merge", feedbackHandler.MergeFeedbackSubmission)
+					r.Post("/{id}/feedback/{submission_id}/reject", feedbackHandler.RejectFeedbackSubmission)
+				})
+			})
+
+			// Маршруты для входящих текущего пользователя
+			r.Route("/inbox", func(r chi.Router) {
+				r.Get("/", inboxHandler.ListInbox)
+				r.Post("/", inboxHandler.CaptureInboxItem)
+				r.Post("/triage", inboxHandler.TriageInbox)
+				r.Delete("/{id}", inboxHandler.DeleteInboxItem)
 			})
 
 			// Маршруты для задач
@@ -166,43 +541,179 @@ func (s *Server) setupRoutes() {
 				r.Put("/{id}", taskHandler.UpdateTask)
 				r.Delete("/{id}", taskHandler.DeleteTask)
 				r.Get("/", taskHandler.ListTasks)
+				r.Put("/bulk", taskHandler.BulkUpdateTasks)
+				r.With(mw.Deadline(s.config.HTTP.ExportTimeout)).Get("/export", taskHandler.ExportTasks)
 				r.Put("/{id}/status", taskHandler.UpdateTaskStatus)
 				r.Put("/{id}/assignee", taskHandler.UpdateTaskAssignee)
+				r.Put("/{id}/assignees", taskHandler.UpdateTaskAssignees)
+				r.Post("/{id}/move", taskHandler.MoveTask)
+				r.Post("/{id}/move-project", taskHandler.MoveTaskToProject)
 				r.Post("/{id}/time", taskHandler.LogTime)
 				r.Get("/{id}/time", taskHandler.GetTimeLogs)
+				r.Get("/{id}/time/reconciliation", taskHandler.GetTimeReconciliation)
+				r.Get("/{id}/subtasks", taskHandler.ListSubtasks)
+				r.Post("/{id}/subtasks", taskHandler.CreateSubtask)
+				r.Get("/{id}/recurrence", taskHandler.GetTaskRecurrence)
+				r.Put("/{id}/recurrence", taskHandler.SetTaskRecurrence)
+				r.Delete("/{id}/recurrence", taskHandler.DeleteTaskRecurrence)
+				r.Get("/{id}/attachments", attachmentHandler.ListTaskAttachments)
+				r.With(uploadLimits...).Post("/{id}/attachments", attachmentHandler.UploadTaskAttachment)
+				r.Post("/{id}/time/recalculate", taskHandler.RecalculateSpentHours)
+				r.Get("/{id}/effort", taskHandler.GetEffortSplit)
+				r.Put("/{id}/effort", taskHandler.UpdateEffortSplit)
+				r.Delete("/{id}/effort", taskHandler.ResetEffortSplit)
+				r.Get("/{id}/form-values", taskFormHandler.GetTaskFormValues)
+				r.Get("/{id}/lock", taskHandler.GetDescriptionLock)
+				r.Post("/{id}/lock", taskHandler.AcquireDescriptionLock)
+				r.Put("/{id}/lock", taskHandler.RenewDescriptionLock)
+				r.Delete("/{id}/lock", taskHandler.ReleaseDescriptionLock)
+				r.Put("/{id}/epic", epicHandler.SetTaskEpic)
+				r.Get("/{id}/decisions", decisionHandler.ListTaskDecisions)
+				r.Get("/{id}/wiki", wikiHandler.ListTaskWikiPages)
+				r.Get("/{id}/meeting-note", meetingNoteHandler.GetTaskMeetingNote)
+			})
+
+			// Маршруты для эпиков
+			r.Route("/epics", func(r chi.Router) {
+				r.Get("/{id}", epicHandler.GetEpic)
+				r.Put("/{id}", epicHandler.UpdateEpic)
+				r.Delete("/{id}", epicHandler.DeleteEpic)
+				r.Get("/{id}/comments", epicHandler.ListEpicComments)
+				r.Post("/{id}/comments", epicHandler.CreateEpicComment)
+				r.Delete("/{id}/comments/{comment_id}", epicHandler.DeleteEpicComment)
+			})
+
+			// Маршруты для спринтов
+			r.Route("/sprints", func(r chi.Router) {
+				r.Get("/{id}", sprintHandler.GetSprint)
+				r.Put("/{id}", sprintHandler.UpdateSprint)
+				r.Delete("/{id}", sprintHandler.DeleteSprint)
+				r.Post("/{id}/start", sprintHandler.StartSprint)
+				r.Post("/{id}/close", sprintHandler.CloseSprint)
+				r.Post("/{id}/tasks", sprintHandler.AddSprintTasks)
+				r.Delete("/{id}/tasks/{task_id}", sprintHandler.RemoveSprintTask)
+				r.Get("/{id}/burndown", sprintHandler.GetSprintBurndown)
+			})
+
+			// Маршруты для журнала решений
+			r.Route("/decisions", func(r chi.Router) {
+				r.Get("/{id}", decisionHandler.GetDecision)
+				r.Put("/{id}", decisionHandler.UpdateDecision)
+				r.Delete("/{id}", decisionHandler.DeleteDecision)
+				r.Post("/{id}/tasks", decisionHandler.LinkDecisionTask)
+				r.Delete("/{id}/tasks/{task_id}", decisionHandler.UnlinkDecisionTask)
+			})
+
+			// Маршруты для вики-страниц
+			r.Route("/wiki", func(r chi.Router) {
+				r.Get("/{id}", wikiHandler.GetWikiPage)
+				r.Put("/{id}", wikiHandler.UpdateWikiPage)
+				r.Delete("/{id}", wikiHandler.DeleteWikiPage)
+				r.Get("/{id}/revisions", wikiHandler.ListWikiRevisions)
+				r.Get("/{id}/revisions/{version}", wikiHandler.GetWikiRevision)
+				r.Post("/{id}/revisions/{version}/restore", wikiHandler.RestoreWikiRevision)
+				r.Post("/{id}/tasks", wikiHandler.LinkWikiTask)
+				r.Delete("/{id}/tasks/{task_id}", wikiHandler.UnlinkWikiTask)
+			})
+
+			// Маршруты для заметок встреч
+			r.Route("/meeting-notes", func(r chi.Router) {
+				r.Get("/{id}", meetingNoteHandler.GetMeetingNote)
+				r.Put("/{id}", meetingNoteHandler.UpdateMeetingNote)
+				r.Delete("/{id}", meetingNoteHandler.DeleteMeetingNote)
+				r.Get("/{id}/action-items", meetingNoteHandler.ListMeetingActionItems)
+				r.Post("/{id}/action-items/convert", meetingNoteHandler.ConvertMeetingActionItems)
+			})
+
+			// Цели и ключевые результаты
+			r.Route("/okrs", func(r chi.Router) {
+				r.Get("/report", okrHandler.GetQuarterReport)
+				r.Get("/objectives", okrHandler.ListObjectives)
+				r.Post("/objectives", okrHandler.CreateObjective)
+				r.Get("/objectives/{id}", okrHandler.GetObjective)
+				r.Put("/objectives/{id}", okrHandler.UpdateObjective)
+				r.Delete("/objectives/{id}", okrHandler.DeleteObjective)
+				r.Post("/objectives/{id}/key-results", okrHandler.CreateKeyResult)
+				r.Put("/key-results/{id}", okrHandler.UpdateKeyResult)
+				r.Delete("/key-results/{id}", okrHandler.DeleteKeyResult)
+				r.Post("/key-results/{id}/links", okrHandler.LinkKeyResult)
+				r.Delete("/key-results/{id}/links/{link_id}", okrHandler.UnlinkKeyResult)
+			})
+
+			// Маршруты для шаблонов задач
+			r.Route("/task-templates", func(r chi.Router) {
+				r.Get("/{id}", taskTemplateHandler.GetTaskTemplate)
+				r.Put("/{id}", taskTemplateHandler.UpdateTaskTemplate)
+				r.Delete("/{id}", taskTemplateHandler.DeleteTaskTemplate)
+				r.Post("/{id}/tasks", taskTemplateHandler.CreateTaskFromTemplate)
 			})
 
 			// Маршруты для комментариев
 			r.Route("/comments", func(r chi.Router) {
 				r.Get("/{id}", commentHandler.GetComment)
+				r.Get("/{id}/replies", commentHandler.GetCommentReplies)
 				r.Put("/{id}", commentHandler.UpdateComment)
 				r.Delete("/{id}", commentHandler.DeleteComment)
+				r.Get("/{id}/attachments", attachmentHandler.ListCommentAttachments)
+				r.With(uploadLimits...).Post("/{id}/attachments", attachmentHandler.UploadCommentAttachment)
+			})
+
+			// Маршруты для вложений
+			r.Route("/attachments", func(r chi.Router) {
+				r.Get("/{id}", attachmentHandler.GetAttachment)
+				r.With(mw.Deadline(s.config.HTTP.UploadTimeout)).Get("/{id}/download", attachmentHandler.DownloadAttachment)
+				r.Delete("/{id}", attachmentHandler.DeleteAttachment)
 			})
 
 			// Комментарии к задаче
 			r.Route("/tasks/{task_id}/comments", func(r chi.Router) {
 				r.Post("/", commentHandler.CreateComment)
 				r.Get("/", commentHandler.GetCommentsByTask)
+				r.Get("/draft", commentHandler.GetCommentDraft)
+				r.Put("/draft", commentHandler.SaveCommentDraft)
+				r.Delete("/draft", commentHandler.DeleteCommentDraft)
 			})
 
 			// Маршруты для уведомлений
 			r.Route("/notifications", func(r chi.Router) {
 				r.Get("/", notificationHandler.ListNotifications)
 				r.Get("/count", notificationHandler.GetUnreadCount)
+				r.With(mw.Deadline(s.config.HTTP.StreamTimeout)).Get("/count/stream", notificationHandler.StreamUnreadCount)
 				r.Get("/{id}", notificationHandler.GetNotification)
 				r.Put("/{id}/read", notificationHandler.MarkAsRead)
 				r.Put("/read-all", notificationHandler.MarkAllAsRead)
+				r.Post("/sweep", notificationHandler.SweepNotifications)
+				r.Post("/batch", notificationHandler.BatchNotifications)
 				r.Delete("/{id}", notificationHandler.DeleteNotification)
 				r.Get("/settings", notificationHandler.GetNotificationSettings)
 				r.Put("/settings", notificationHandler.UpdateNotificationSettings)
 			})
 
+			// Быстрый поиск
+			r.With(typeaheadCache).Get("/typeahead", searchHandler.Typeahead)
+
 			// Маршруты для Telegram
 			r.Route("/telegram", func(r chi.Router) {
 				r.Get("/status", telegramHandler.GetTelegramStatus)
 				r.Post("/connect", telegramHandler.GenerateConnectToken)
 				r.Delete("/disconnect", telegramHandler.DisconnectTelegram)
 			})
+
+			// Маршруты для Microsoft Teams
+			r.Route("/teams", func(r chi.Router) {
+				r.Get("/status", teamsHandler.GetTeamsStatus)
+				r.Post("/connect", teamsHandler.ConnectTeams)
+				r.Delete("/disconnect", teamsHandler.DisconnectTeams)
+			})
+
+			// Маршруты для SMS-уведомлений о критических событиях
+			r.Route("/sms", func(r chi.Router) {
+				r.Get("/status", smsHandler.GetSMSStatus)
+				r.Put("/phone", smsHandler.SetSMSPhone)
+				r.Delete("/phone", smsHandler.DeleteSMSPhone)
+				r.Post("/verify", smsHandler.VerifySMSPhone)
+				r.Put("/settings", smsHandler.UpdateSMSSettings)
+			})
 		})
 	})
 }
@@ -218,22 +729,26 @@ func (s *Server) Start() error {
 		"port": s.config.HTTP.Port,
 	})
 
-	server := &http.Server{
-		Addr:         ":" + s.config.HTTP.Port,
-		Handler:      s.router,
-		ReadTimeout:  s.config.HTTP.ReadTimeout,
-		WriteTimeout: s.config.HTTP.WriteTimeout,
+	if err := s.httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
 	}
+	return nil
+}
 
-	return server.ListenAndServe()
+// handler возвращает обработчик сервера. При включенном H2C сервер принимает
+// HTTP/2 без TLS от балансировщика, сохраняя поддержку HTTP/1.1
+func (s *Server) handler() http.Handler {
+	if !s.config.HTTP.H2C {
+		return s.router
+	}
+	return h2c.NewHandler(s.router, &http2.Server{
+		IdleTimeout: s.config.HTTP.IdleTimeout,
+	})
 }
 
-// Shutdown корректно останавливает HTTP сервер
+// Shutdown корректно останавливает HTTP сервер, дожидаясь завершения текущих запросов
 func (s *Server) Shutdown(ctx context.Context) error {
 	s.logger.Info("Shutting down API server")
 
-	// Здесь можно добавить дополнительную логику для корректного завершения работы
-	// Например, ожидание завершения текущих запросов и т.д.
-
-	return nil
+	return s.httpServer.Shutdown(ctx)
 }
@@ -2,52 +2,110 @@ package app
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/nurlyy/task_manager/internal/messaging"
 	"github.com/nurlyy/task_manager/internal/repository/cache"
+	"github.com/nurlyy/task_manager/internal/repository/memo"
 	"github.com/nurlyy/task_manager/internal/repository/postgres"
 	redisClient "github.com/nurlyy/task_manager/pkg/cache"
+	"github.com/nurlyy/task_manager/pkg/circuit"
 	"github.com/nurlyy/task_manager/pkg/config"
 	"github.com/nurlyy/task_manager/pkg/database"
 	"github.com/nurlyy/task_manager/pkg/logger"
+	"github.com/nurlyy/task_manager/pkg/storage"
 )
 
 // Repositories содержит все репозитории для работы с хранилищами данных
 type Repositories struct {
-	UserRepository         *postgres.UserRepository
-	ProjectRepository      *postgres.ProjectRepository
-	TaskRepository         *postgres.TaskRepository
-	CommentRepository      *postgres.CommentRepository
-	NotificationRepository *postgres.NotificationRepository
-	CacheRepository        *cache.RedisRepository
-	TelegramRepository     *postgres.TelegramRepository
+	UserRepository           *memo.UserRepository
+	ProjectRepository        *memo.ProjectRepository
+	TaskRepository           *postgres.TaskRepository
+	CommentRepository        *postgres.CommentRepository
+	NotificationRepository   *cache.CountingNotificationRepository
+	CacheRepository          *cache.RedisRepository
+	TelegramRepository       *postgres.TelegramRepository
+	SearchRepository         *postgres.SearchRepository
+	TaskFormRepository       *postgres.TaskFormRepository
+	TaskTemplateRepository   *postgres.TaskTemplateRepository
+	ScheduledTaskRepository  *postgres.ScheduledTaskRepository
+	InboundEmailRepository   *postgres.InboundEmailRepository
+	ProjectWebhookRepository *postgres.ProjectWebhookRepository
+	IncidentRepository       *postgres.IncidentRepository
+	TeamsRepository          *postgres.TeamsRepository
+	DiscordRepository        *postgres.DiscordRepository
+	MatrixRepository         *postgres.MatrixRepository
+	SMSRepository            *postgres.SMSRepository
+	FeedbackRepository       *postgres.FeedbackRepository
+	RoadmapRepository        *postgres.RoadmapRepository
+	EpicRepository           *postgres.EpicRepository
+	BoardRepository          *postgres.BoardRepository
+	SprintRepository         *postgres.SprintRepository
+	OKRRepository            *postgres.OKRRepository
+	DecisionRepository       *postgres.DecisionRepository
+	WikiRepository           *postgres.WikiRepository
+	MeetingNoteRepository    *postgres.MeetingNoteRepository
+	ProjectMetricsRepository *postgres.ProjectMetricsRepository
+	InvitationRepository     *postgres.ProjectInvitationRepository
+	InboxRepository          *postgres.InboxRepository
+	StatusNoteRepository     *postgres.StatusNoteRepository
+	ConsistencyRepository    *postgres.ConsistencyRepository
+	ProjectViewRepository    *postgres.ProjectViewRepository
+	TaskRecurrenceRepository *postgres.TaskRecurrenceRepository
+	AttachmentRepository     *postgres.AttachmentRepository
+	JobRunRepository         *postgres.JobRunRepository
+	AuditRepository          *postgres.AuditRepository
+	TaskViewRepository       *postgres.TaskViewRepository
+	OutboxRepository         *postgres.OutboxRepository
+	TxManager                *postgres.TxManager
 }
 
 // Messaging содержит все клиенты для работы с сообщениями
 type Messaging struct {
 	Producer *messaging.KafkaProducer
+	Delays   *messaging.DelayQueue
 }
 
 // Application содержит все компоненты приложения
 type Application struct {
 	Config       *config.Config
 	DB           *sqlx.DB
+	QueryMetrics *database.QueryMetrics
 	Redis        *redisClient.Redis
 	Logger       logger.Logger
 	Repositories *Repositories
 	Messaging    *Messaging
+	Storage      storage.Storage
+	Schema       *database.SchemaStatus // Совместимость схемы базы с кодом
+
+	metricsServer  *http.Server
+	metricsSources []MetricsSource
+}
+
+// MetricsSource отдает метрики сервиса в текстовом формате Prometheus
+type MetricsSource interface {
+	WritePrometheus(w io.Writer) error
 }
 
 // NewApplication создает новое приложение с инициализированными компонентами
 func NewApplication(ctx context.Context, cfg *config.Config, log logger.Logger) (*Application, error) {
 	// Инициализация базы данных PostgreSQL
-	postgresDB, err := initPostgres(ctx, &cfg.Database, log)
+	postgresDB, err := database.NewPostgres(ctx, &cfg.Database, log)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize PostgreSQL: %w", err)
 	}
 
+	// Проверка совместимости схемы: при выкатке новые и старые экземпляры работают с одной базой
+	schema, err := checkSchema(ctx, postgresDB.DB, &cfg.Database, log)
+	if err != nil {
+		return nil, err
+	}
+
 	// Инициализация Redis
 	redisCache, err := initRedis(ctx, &cfg.Redis, log)
 	if err != nil {
@@ -55,29 +113,139 @@ func NewApplication(ctx context.Context, cfg *config.Config, log logger.Logger)
 	}
 
 	// Инициализация репозиториев
-	repos, err := initRepositories(postgresDB, redisCache, log, cfg)
+	repos, err := initRepositories(postgresDB.DB, redisCache, log, cfg)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize repositories: %w", err)
 	}
 
+	// Выключатели внешних сервисов уведомлений создаются отправителями при первом обращении
+	circuit.Default.Configure(circuit.Settings{
+		FailureThreshold: cfg.Notifier.Breaker.FailureThreshold,
+		OpenTimeout:      cfg.Notifier.Breaker.OpenTimeout,
+		MaxConcurrent:    cfg.Notifier.Breaker.MaxConcurrent,
+	})
+
 	// Инициализация Kafka
-	msgClients, err := initMessaging(cfg, log)
+	msgClients, err := initMessaging(cfg, redisCache, log)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize messaging: %w", err)
 	}
 
+	// События из транзакций сервисов записываются в таблицу исходящих событий
+	msgClients.Producer.SetOutbox(repos.OutboxRepository)
+
+	// Инициализация хранилища вложений
+	fileStorage, err := storage.New(&cfg.Storage)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize storage: %w", err)
+	}
+
 	return &Application{
 		Config:       cfg,
-		DB:           postgresDB,
+		DB:           postgresDB.DB,
+		QueryMetrics: postgresDB.Metrics,
 		Redis:        redisCache,
 		Logger:       log,
 		Repositories: repos,
 		Messaging:    msgClients,
+		Storage:      fileStorage,
+		Schema:       schema,
 	}, nil
 }
 
+// checkSchema сравнивает версию схемы базы с версией, на которую рассчитан код. При
+// несовместимости запуск прерывается, если в конфигурации не разрешен режим только для чтения
+func checkSchema(ctx context.Context, db *sqlx.DB, cfg *config.DatabaseConfig, log logger.Logger) (*database.SchemaStatus, error) {
+	schema, err := database.CheckSchema(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := map[string]interface{}{
+		"expected":        schema.Expected,
+		"version":         schema.Version,
+		"compatible_from": schema.CompatibleFrom,
+	}
+	if schema.Compatible() {
+		log.Info("Database schema is compatible", fields)
+		return schema, nil
+	}
+	if cfg.SchemaMismatch != "readonly" {
+		return nil, fmt.Errorf("incompatible database schema: %s", schema.Problem())
+	}
+
+	fields["problem"] = schema.Problem()
+	log.Warn("Database schema is incompatible, starting read-only", fields)
+	return schema, nil
+}
+
+// RequireCompatibleSchema возвращает ошибку, если схема базы несовместима с кодом.
+// Фоновые сервисы изменяют данные и не могут работать только для чтения
+func (app *Application) RequireCompatibleSchema() error {
+	if app.Schema.Compatible() {
+		return nil
+	}
+	return fmt.Errorf("incompatible database schema: %s", app.Schema.Problem())
+}
+
+// AddMetrics добавляет метрики сервиса к метрикам, отдаваемым сервером метрик.
+// Вызывается до StartMetricsServer
+func (app *Application) AddMetrics(source MetricsSource) {
+	app.metricsSources = append(app.metricsSources, source)
+}
+
+// StartMetricsServer запускает HTTP-сервер метрик Prometheus, если он включен в конфигурации
+func (app *Application) StartMetricsServer() {
+	if !app.Config.Monitoring.PrometheusEnabled {
+		return
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		if err := app.QueryMetrics.WritePrometheus(w); err != nil {
+			app.Logger.Error("Failed to write query metrics", err)
+			return
+		}
+		if err := circuit.Default.WritePrometheus(w); err != nil {
+			app.Logger.Error("Failed to write circuit breaker metrics", err)
+			return
+		}
+		for _, source := range app.metricsSources {
+			if err := source.WritePrometheus(w); err != nil {
+				app.Logger.Error("Failed to write service metrics", err)
+				return
+			}
+		}
+	})
+
+	app.metricsServer = &http.Server{
+		Addr:        ":" + app.Config.Monitoring.PrometheusPort,
+		Handler:     mux,
+		ReadTimeout: 5 * time.Second,
+	}
+
+	app.Logger.Info("Starting metrics server", map[string]interface{}{
+		"port": app.Config.Monitoring.PrometheusPort,
+	})
+
+	go func() {
+		if err := app.metricsServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			app.Logger.Error("Metrics server error", err)
+		}
+	}()
+}
+
 // Close закрывает все соединения с внешними сервисами
 func (app *Application) Close() {
+	if app.metricsServer != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := app.metricsServer.Shutdown(ctx); err != nil {
+			app.Logger.Error("Error stopping metrics server", err)
+		}
+	}
+
 	if app.DB != nil {
 		if err := app.DB.Close(); err != nil {
 			app.Logger.Error("Error closing PostgreSQL connection", err)
@@ -97,15 +265,6 @@ func (app *Application) Close() {
 	}
 }
 
-// Инициализация PostgreSQL
-func initPostgres(ctx context.Context, cfg *config.DatabaseConfig, log logger.Logger) (*sqlx.DB, error) {
-	postgres, err := database.NewPostgres(ctx, cfg, log)
-	if err != nil {
-		return nil, err
-	}
-	return postgres.DB, nil
-}
-
 // Инициализация Redis
 func initRedis(ctx context.Context, cfg *config.RedisConfig, log logger.Logger) (*redisClient.Redis, error) {
 	redis, err := redisClient.NewRedis(ctx, cfg, log)
@@ -117,40 +276,121 @@ func initRedis(ctx context.Context, cfg *config.RedisConfig, log logger.Logger)
 
 // Инициализация репозиториев
 func initRepositories(db *sqlx.DB, redis *redisClient.Redis, log logger.Logger, cfg *config.Config) (*Repositories, error) {
+	// Инициализация Redis репозитория
+	cacheRepo := cache.NewRedisRepository(redis.Client, log, cfg.Redis.DefaultTTL)
+
 	// Инициализация PostgreSQL репозиториев
-	userRepo := postgres.NewUserRepository(db, log)
-	projectRepo := postgres.NewProjectRepository(db, log)
+	// Пользователи и участники проектов читаются в каждом запросе многократно:
+	// повторные чтения запоминаются в пределах запроса, одновременные объединяются.
+	// Членство в проектах, кроме того, кэшируется в Redis между запросами
+	userRepo := memo.NewUserRepository(postgres.NewUserRepository(db, log))
+	projectRepo := memo.NewProjectRepository(
+		cache.NewMemberCachingProjectRepository(postgres.NewProjectRepository(db, log), cacheRepo, cfg.Redis.MemberTTL, log),
+	)
 	taskRepo := postgres.NewTaskRepository(db, log)
 	commentRepo := postgres.NewCommentRepository(db, log)
-	notificationRepo := postgres.NewNotificationRepository(db, log)
 	telegramRepo := postgres.NewTelegramRepository(db, log)
+	searchRepo := postgres.NewSearchRepository(db, log)
+	taskFormRepo := postgres.NewTaskFormRepository(db, log)
+	taskTemplateRepo := postgres.NewTaskTemplateRepository(db, log)
+	scheduledTaskRepo := postgres.NewScheduledTaskRepository(db, log)
+	inboundEmailRepo := postgres.NewInboundEmailRepository(db, log)
+	projectWebhookRepo := postgres.NewProjectWebhookRepository(db, log)
+	incidentRepo := postgres.NewIncidentRepository(db, log)
+	teamsRepo := postgres.NewTeamsRepository(db, log)
+	discordRepo := postgres.NewDiscordRepository(db, log)
+	matrixRepo := postgres.NewMatrixRepository(db, log)
+	smsRepo := postgres.NewSMSRepository(db, log)
+	feedbackRepo := postgres.NewFeedbackRepository(db, log)
+	roadmapRepo := postgres.NewRoadmapRepository(db, log)
+	epicRepo := postgres.NewEpicRepository(db, log)
+	boardRepo := postgres.NewBoardRepository(db, log)
+	sprintRepo := postgres.NewSprintRepository(db, log)
+	okrRepo := postgres.NewOKRRepository(db, log)
+	decisionRepo := postgres.NewDecisionRepository(db, log)
+	wikiRepo := postgres.NewWikiRepository(db, log)
+	meetingNoteRepo := postgres.NewMeetingNoteRepository(db, log)
+	projectMetricsRepo := postgres.NewProjectMetricsRepository(db, log)
+	invitationRepo := postgres.NewProjectInvitationRepository(db, log)
+	inboxRepo := postgres.NewInboxRepository(db, log)
+	statusNoteRepo := postgres.NewStatusNoteRepository(db, log)
+	consistencyRepo := postgres.NewConsistencyRepository(db, log)
+	projectViewRepo := postgres.NewProjectViewRepository(db, log)
+	taskRecurrenceRepo := postgres.NewTaskRecurrenceRepository(db, log)
+	attachmentRepo := postgres.NewAttachmentRepository(db, log)
+	jobRunRepo := postgres.NewJobRunRepository(db, log)
+	auditRepo := postgres.NewAuditRepository(db, log)
+	taskViewRepo := postgres.NewTaskViewRepository(db, log)
+	outboxRepo := postgres.NewOutboxRepository(db, log)
+	txManager := postgres.NewTxManager(db, log)
 
-	// Инициализация Redis репозитория
-	cacheRepo := cache.NewRedisRepository(redis.Client, log, cfg.Redis.DefaultTTL)
+	// Счетчики непрочитанных уведомлений поддерживаются в Redis при любых изменениях уведомлений,
+	// настройки уведомлений кэшируются в Redis до их изменения
+	notificationRepo := cache.NewCountingNotificationRepository(
+		cache.NewSettingsCachingNotificationRepository(postgres.NewNotificationRepository(db, log), cacheRepo, cfg.Redis.SettingsTTL, log),
+		cacheRepo,
+		log,
+	)
 
 	return &Repositories{
-		UserRepository:         userRepo,
-		ProjectRepository:      projectRepo,
-		TaskRepository:         taskRepo,
-		CommentRepository:      commentRepo,
-		NotificationRepository: notificationRepo,
-		CacheRepository:        cacheRepo,
-		TelegramRepository:     telegramRepo,
+		UserRepository:           userRepo,
+		ProjectRepository:        projectRepo,
+		TaskRepository:           taskRepo,
+		CommentRepository:        commentRepo,
+		NotificationRepository:   notificationRepo,
+		CacheRepository:          cacheRepo,
+		TelegramRepository:       telegramRepo,
+		SearchRepository:         searchRepo,
+		TaskFormRepository:       taskFormRepo,
+		TaskTemplateRepository:   taskTemplateRepo,
+		ScheduledTaskRepository:  scheduledTaskRepo,
+		InboundEmailRepository:   inboundEmailRepo,
+		ProjectWebhookRepository: projectWebhookRepo,
+		IncidentRepository:       incidentRepo,
+		TeamsRepository:          teamsRepo,
+		DiscordRepository:        discordRepo,
+		MatrixRepository:         matrixRepo,
+		SMSRepository:            smsRepo,
+		FeedbackRepository:       feedbackRepo,
+		RoadmapRepository:        roadmapRepo,
+		EpicRepository:           epicRepo,
+		BoardRepository:          boardRepo,
+		SprintRepository:         sprintRepo,
+		OKRRepository:            okrRepo,
+		DecisionRepository:       decisionRepo,
+		WikiRepository:           wikiRepo,
+		MeetingNoteRepository:    meetingNoteRepo,
+		ProjectMetricsRepository: projectMetricsRepo,
+		InvitationRepository:     invitationRepo,
+		InboxRepository:          inboxRepo,
+		StatusNoteRepository:     statusNoteRepo,
+		ConsistencyRepository:    consistencyRepo,
+		ProjectViewRepository:    projectViewRepo,
+		TaskRecurrenceRepository: taskRecurrenceRepo,
+		AttachmentRepository:     attachmentRepo,
+		JobRunRepository:         jobRunRepo,
+		AuditRepository:          auditRepo,
+		TaskViewRepository:       taskViewRepo,
+		OutboxRepository:         outboxRepo,
+		TxManager:                txManager,
 	}, nil
 }
 
 // Инициализация Kafka
-func initMessaging(cfg *config.Config, log logger.Logger) (*Messaging, error) {
+func initMessaging(cfg *config.Config, redisCache *redisClient.Redis, log logger.Logger) (*Messaging, error) {
 	// Определяем топики Kafka
 	topics := map[string]string{
-		"task_created":         cfg.Kafka.Topics.TaskCreated,
-		"task_updated":         cfg.Kafka.Topics.TaskUpdated,
-		"task_assigned":        cfg.Kafka.Topics.TaskAssigned,
-		"task_commented":       cfg.Kafka.Topics.TaskCommented,
-		"project_created":      "project_created",
-		"project_updated":      "project_updated",
-		"project_member_added": "project_member_added",
-		"notifications":        cfg.Kafka.Topics.Notifications,
+		"task_created":           cfg.Kafka.Topics.TaskCreated,
+		"task_updated":           cfg.Kafka.Topics.TaskUpdated,
+		"task_assigned":          cfg.Kafka.Topics.TaskAssigned,
+		"task_commented":         cfg.Kafka.Topics.TaskCommented,
+		"project_created":        cfg.Kafka.Topics.ProjectCreated,
+		"project_updated":        cfg.Kafka.Topics.ProjectUpdated,
+		"project_member_added":   cfg.Kafka.Topics.ProjectMemberAdded,
+		"project_member_removed": cfg.Kafka.Topics.ProjectMemberRemoved,
+		"notifications_critical": cfg.Kafka.Topics.NotificationsCritical,
+		"notifications":          cfg.Kafka.Topics.Notifications,
+		"notifications_digest":   cfg.Kafka.Topics.NotificationsDigest,
 	}
 
 	// Инициализация Kafka продюсера
@@ -167,7 +407,12 @@ func initMessaging(cfg *config.Config, log logger.Logger) (*Messaging, error) {
 		})
 	}
 
+	// Отложенные уведомления ждут в Redis и публикуются диспетчером планировщика
+	delays := messaging.NewDelayQueue(redisCache.Client, &cfg.Kafka.Delays, log)
+	producer.SetDelayQueue(delays)
+
 	return &Messaging{
 		Producer: producer,
+		Delays:   delays,
 	}, nil
 }
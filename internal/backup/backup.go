@@ -0,0 +1,292 @@
+// Package backup создает согласованные резервные копии данных приложения и восстанавливает их:
+// снимок PostgreSQL, ключи Redis с состоянием, которого нет в базе, и список файлов вложений
+package backup
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/nurlyy/task_manager/pkg/config"
+	"github.com/nurlyy/task_manager/pkg/logger"
+	"github.com/nurlyy/task_manager/pkg/storage"
+)
+
+// formatVersion - версия формата резервной копии; восстанавливаются только копии этой версии
+const formatVersion = 1
+
+// Файлы резервной копии. Файл метаданных записывается последним: копия без него не завершена
+const (
+	fileMetadata    = "metadata.json"
+	filePostgres    = "postgres.dump"
+	fileRedis       = "redis.jsonl"
+	fileAttachments = "attachments.jsonl"
+)
+
+// Metadata описывает резервную копию и момент, на который она сделана
+type Metadata struct {
+	FormatVersion   int               `json:"format_version"`
+	CreatedAt       time.Time         `json:"created_at"`     // Момент снимка базы по часам PostgreSQL
+	Database        string            `json:"database"`       // Имя скопированной базы
+	ServerVersion   string            `json:"server_version"` // Версия PostgreSQL
+	WALLSN          string            `json:"wal_lsn"`        // Позиция WAL снимка: с нее можно доиграть архив WAL до нужного момента
+	RedisCapturedAt time.Time         `json:"redis_captured_at"`
+	RedisKeys       int               `json:"redis_keys"`
+	Attachments     int               `json:"attachments"`
+	AttachmentBytes int64             `json:"attachment_bytes"`
+	Files           map[string]string `json:"files"` // SHA-256 файлов копии
+}
+
+// attachmentEntry - запись списка файлов вложений. Сами файлы копируются средствами хранилища
+type attachmentEntry struct {
+	ID          string    `json:"id" db:"id"`
+	TaskID      string    `json:"task_id" db:"task_id"`
+	StorageKey  string    `json:"storage_key" db:"storage_key"`
+	Size        int64     `json:"size" db:"size"`
+	ContentType string    `json:"content_type" db:"content_type"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+// Service создает и восстанавливает резервные копии
+type Service struct {
+	db       *sqlx.DB
+	dbConfig *config.DatabaseConfig
+	redis    *redis.Client
+	delayKey string // Ключ очереди отложенных уведомлений
+	storage  storage.Storage
+	logger   logger.Logger
+}
+
+// NewService создает новый экземпляр Service
+func NewService(
+	db *sqlx.DB,
+	dbConfig *config.DatabaseConfig,
+	redis *redis.Client,
+	delayKey string,
+	storage storage.Storage,
+	logger logger.Logger,
+) *Service {
+	return &Service{
+		db:       db,
+		dbConfig: dbConfig,
+		redis:    redis,
+		delayKey: delayKey,
+		storage:  storage,
+		logger:   logger,
+	}
+}
+
+// Create записывает резервную копию в каталог dir. База копируется через pg_dump из снимка,
+// экспортированного транзакцией, в которой читается и список вложений, поэтому они согласованы
+// между собой. Ключи Redis копируются сразу после снимка базы
+func (s *Service) Create(ctx context.Context, dir string) (*Metadata, error) {
+	if _, err := os.Stat(filepath.Join(dir, fileMetadata)); err == nil {
+		return nil, fmt.Errorf("backup already exists in %s", dir)
+	}
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create backup directory: %w", err)
+	}
+
+	tx, err := s.db.BeginTxx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin snapshot transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var snapshot struct {
+		ID            string    `db:"snapshot"`
+		CreatedAt     time.Time `db:"created_at"`
+		WALLSN        string    `db:"wal_lsn"`
+		ServerVersion string    `db:"server_version"`
+	}
+	if err := tx.GetContext(ctx, &snapshot, `
+		SELECT
+			pg_export_snapshot() AS snapshot,
+			now() AS created_at,
+			(CASE WHEN pg_is_in_recovery() THEN pg_last_wal_replay_lsn() ELSE pg_current_wal_lsn() END)::text AS wal_lsn,
+			current_setting('server_version') AS server_version
+	`); err != nil {
+		return nil, fmt.Errorf("failed to export database snapshot: %w", err)
+	}
+
+	metadata := &Metadata{
+		FormatVersion: formatVersion,
+		CreatedAt:     snapshot.CreatedAt,
+		Database:      s.dbConfig.Database,
+		ServerVersion: snapshot.ServerVersion,
+		WALLSN:        snapshot.WALLSN,
+		Files:         make(map[string]string),
+	}
+	s.logger.Info("Database snapshot exported", map[string]interface{}{
+		"snapshot": snapshot.ID,
+		"wal_lsn":  snapshot.WALLSN,
+	})
+
+	// Снимок действует, пока открыта транзакция, поэтому pg_dump запускается внутри нее
+	if err := s.runPostgresTool(ctx, "pg_dump",
+		"--format=custom",
+		"--no-owner",
+		"--snapshot="+snapshot.ID,
+		"--file="+filepath.Join(dir, filePostgres),
+	); err != nil {
+		return nil, err
+	}
+
+	metadata.Attachments, metadata.AttachmentBytes, err = writeAttachmentManifest(ctx, tx, filepath.Join(dir, fileAttachments))
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Rollback(); err != nil {
+		return nil, fmt.Errorf("failed to release database snapshot: %w", err)
+	}
+
+	metadata.RedisCapturedAt = time.Now()
+	metadata.RedisKeys, err = s.dumpRedis(ctx, filepath.Join(dir, fileRedis))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range []string{filePostgres, fileAttachments, fileRedis} {
+		sum, err := fileChecksum(filepath.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+		metadata.Files[name] = sum
+	}
+
+	data, err := json.MarshalIndent(metadata, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal backup metadata: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, fileMetadata), data, 0o640); err != nil {
+		return nil, fmt.Errorf("failed to write backup metadata: %w", err)
+	}
+
+	return metadata, nil
+}
+
+// writeAttachmentManifest записывает список вложений из снимка базы
+func writeAttachmentManifest(ctx context.Context, tx *sqlx.Tx, path string) (int, int64, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to create attachment manifest: %w", err)
+	}
+	defer file.Close()
+
+	rows, err := tx.QueryxContext(ctx, `
+		SELECT id, task_id, storage_key, size, content_type, created_at
+		FROM attachments
+		ORDER BY created_at, id
+	`)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to list attachments: %w", err)
+	}
+	defer rows.Close()
+
+	count, total := 0, int64(0)
+	encoder := json.NewEncoder(file)
+	for rows.Next() {
+		var entry attachmentEntry
+		if err := rows.StructScan(&entry); err != nil {
+			return count, total, fmt.Errorf("failed to scan attachment: %w", err)
+		}
+		if err := encoder.Encode(entry); err != nil {
+			return count, total, fmt.Errorf("failed to write attachment manifest: %w", err)
+		}
+		count++
+		total += entry.Size
+	}
+	if err := rows.Err(); err != nil {
+		return count, total, fmt.Errorf("failed to list attachments: %w", err)
+	}
+
+	return count, total, file.Sync()
+}
+
+// runPostgresTool запускает pg_dump или pg_restore с параметрами подключения из конфигурации
+func (s *Service) runPostgresTool(ctx context.Context, name string, args ...string) error {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Env = append(os.Environ(),
+		"PGHOST="+s.dbConfig.Host,
+		"PGPORT="+s.dbConfig.Port,
+		"PGUSER="+s.dbConfig.Username,
+		"PGPASSWORD="+s.dbConfig.Password,
+		"PGDATABASE="+s.dbConfig.Database,
+		"PGSSLMODE="+s.dbConfig.SSLMode,
+	)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	start := time.Now()
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s failed: %w: %s", name, err, bytes.TrimSpace(stderr.Bytes()))
+	}
+
+	s.logger.Info("PostgreSQL tool finished", map[string]interface{}{
+		"tool":    name,
+		"elapsed": time.Since(start).String(),
+	})
+	return nil
+}
+
+// fileChecksum возвращает SHA-256 файла
+func fileChecksum(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open %s: %w", filepath.Base(path), err)
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", filepath.Base(path), err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// readMetadata читает и проверяет метаданные резервной копии и контрольные суммы ее файлов
+func readMetadata(dir string) (*Metadata, error) {
+	data, err := os.ReadFile(filepath.Join(dir, fileMetadata))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("no complete backup in %s", dir)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backup metadata: %w", err)
+	}
+
+	var metadata Metadata
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		return nil, fmt.Errorf("failed to decode backup metadata: %w", err)
+	}
+	if metadata.FormatVersion != formatVersion {
+		return nil, fmt.Errorf("unsupported backup format version %d", metadata.FormatVersion)
+	}
+
+	for _, name := range []string{filePostgres, fileAttachments, fileRedis} {
+		expected, ok := metadata.Files[name]
+		if !ok {
+			return nil, fmt.Errorf("backup metadata has no checksum for %s", name)
+		}
+		sum, err := fileChecksum(filepath.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+		if sum != expected {
+			return nil, fmt.Errorf("checksum mismatch for %s", name)
+		}
+	}
+	return &metadata, nil
+}
@@ -0,0 +1,135 @@
+package backup
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+
+	"github.com/nurlyy/task_manager/internal/repository/cache"
+)
+
+// redisScanCount - число ключей, запрашиваемых за один шаг SCAN
+const redisScanCount = 1000
+
+// redisEntry - ключ Redis в резервной копии. Значение хранится в формате DUMP,
+// поэтому восстанавливается без учета типа ключа
+type redisEntry struct {
+	Key   string `json:"key"`
+	TTLMs int64  `json:"ttl_ms,omitempty"` // Оставшееся время жизни; 0 - без ограничения
+	Value []byte `json:"value"`
+}
+
+// statePatterns возвращает шаблоны ключей, которые сохраняются в резервной копии
+func (s *Service) statePatterns() []string {
+	return append(cache.StateKeyPatterns(), s.delayKey)
+}
+
+// dumpRedis записывает ключи с состоянием в файл и возвращает их количество
+func (s *Service) dumpRedis(ctx context.Context, path string) (int, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create Redis dump: %w", err)
+	}
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+	encoder := json.NewEncoder(writer)
+	count := 0
+	for _, pattern := range s.statePatterns() {
+		err := s.scanKeys(ctx, pattern, func(key string) error {
+			value, err := s.redis.Dump(ctx, key).Result()
+			if errors.Is(err, redis.Nil) {
+				// Ключ истек между SCAN и DUMP
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("failed to dump Redis key %s: %w", key, err)
+			}
+			ttl, err := s.redis.PTTL(ctx, key).Result()
+			if err != nil {
+				return fmt.Errorf("failed to get TTL of Redis key %s: %w", key, err)
+			}
+
+			entry := redisEntry{Key: key, Value: []byte(value)}
+			if ttl > 0 {
+				entry.TTLMs = ttl.Milliseconds()
+			}
+			if err := encoder.Encode(entry); err != nil {
+				return fmt.Errorf("failed to write Redis dump: %w", err)
+			}
+			count++
+			return nil
+		})
+		if err != nil {
+			return count, err
+		}
+	}
+
+	if err := writer.Flush(); err != nil {
+		return count, fmt.Errorf("failed to write Redis dump: %w", err)
+	}
+	return count, file.Sync()
+}
+
+// restoreRedis восстанавливает ключи из файла, заменяя существующие, и возвращает их количество
+func (s *Service) restoreRedis(ctx context.Context, path string) (int, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open Redis dump: %w", err)
+	}
+	defer file.Close()
+
+	decoder := json.NewDecoder(bufio.NewReader(file))
+	count := 0
+	for decoder.More() {
+		var entry redisEntry
+		if err := decoder.Decode(&entry); err != nil {
+			return count, fmt.Errorf("failed to read Redis dump: %w", err)
+		}
+
+		ttl := time.Duration(entry.TTLMs) * time.Millisecond
+		if err := s.redis.RestoreReplace(ctx, entry.Key, ttl, string(entry.Value)).Err(); err != nil {
+			return count, fmt.Errorf("failed to restore Redis key %s: %w", entry.Key, err)
+		}
+		count++
+	}
+	return count, nil
+}
+
+// deleteKeys удаляет ключи по шаблонам и возвращает их количество
+func (s *Service) deleteKeys(ctx context.Context, patterns []string) (int, error) {
+	count := 0
+	for _, pattern := range patterns {
+		err := s.scanKeys(ctx, pattern, func(key string) error {
+			if err := s.redis.Del(ctx, key).Err(); err != nil {
+				return fmt.Errorf("failed to delete Redis key %s: %w", key, err)
+			}
+			count++
+			return nil
+		})
+		if err != nil {
+			return count, err
+		}
+	}
+	return count, nil
+}
+
+// scanKeys вызывает fn для каждого ключа, подходящего под шаблон
+func (s *Service) scanKeys(ctx context.Context, pattern string, fn func(key string) error) error {
+	iter := s.redis.Scan(ctx, 0, pattern, redisScanCount).Iterator()
+	for iter.Next(ctx) {
+		if err := fn(iter.Val()); err != nil {
+			return err
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return fmt.Errorf("failed to scan Redis keys %s: %w", pattern, err)
+	}
+	return nil
+}
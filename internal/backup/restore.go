@@ -0,0 +1,115 @@
+package backup
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/nurlyy/task_manager/internal/repository/cache"
+)
+
+// RestoreOptions содержит параметры восстановления
+type RestoreOptions struct {
+	// ReplayDue отправляет отложенные уведомления, срок которых наступил до восстановления.
+	// По умолчанию они удаляются: после снятия копии они могли быть уже отправлены
+	ReplayDue bool
+	// VerifyAttachments проверяет, что файлы всех вложений из копии есть в хранилище
+	VerifyAttachments bool
+}
+
+// RestoreReport содержит результат восстановления
+type RestoreReport struct {
+	Metadata           *Metadata
+	RedisKeys          int      // Восстановлено ключей Redis с состоянием
+	CacheKeysDeleted   int      // Удалено ключей кэша, устаревших после восстановления базы
+	DelayedDropped     int64    // Удалено отложенных уведомлений с наступившим сроком
+	MissingAttachments []string // Ключи вложений, файлов которых нет в хранилище
+}
+
+// Restore восстанавливает резервную копию из каталога dir. База восстанавливается через
+// pg_restore одной транзакцией, кэш Redis очищается и заполняется заново при обращении,
+// ключи с состоянием заменяются значениями из копии. Сервисы приложения на время
+// восстановления должны быть остановлены
+func (s *Service) Restore(ctx context.Context, dir string, opts RestoreOptions) (*RestoreReport, error) {
+	metadata, err := readMetadata(dir)
+	if err != nil {
+		return nil, err
+	}
+	report := &RestoreReport{Metadata: metadata}
+
+	s.logger.Info("Restoring backup", map[string]interface{}{
+		"created_at": metadata.CreatedAt,
+		"wal_lsn":    metadata.WALLSN,
+		"database":   metadata.Database,
+	})
+
+	if err := s.runPostgresTool(ctx, "pg_restore",
+		"--clean",
+		"--if-exists",
+		"--no-owner",
+		"--single-transaction",
+		"--exit-on-error",
+		"--dbname="+s.dbConfig.Database,
+		filepath.Join(dir, filePostgres),
+	); err != nil {
+		return report, err
+	}
+
+	report.CacheKeysDeleted, err = s.deleteKeys(ctx, cache.CacheKeyPatterns())
+	if err != nil {
+		return report, err
+	}
+
+	report.RedisKeys, err = s.restoreRedis(ctx, filepath.Join(dir, fileRedis))
+	if err != nil {
+		return report, err
+	}
+
+	if !opts.ReplayDue {
+		dropped, err := s.redis.ZRemRangeByScore(ctx, s.delayKey, "-inf", strconv.FormatInt(time.Now().UnixMilli(), 10)).Result()
+		if err != nil {
+			return report, fmt.Errorf("failed to drop due delayed notifications: %w", err)
+		}
+		report.DelayedDropped = dropped
+	}
+
+	if opts.VerifyAttachments {
+		report.MissingAttachments, err = s.verifyAttachments(ctx, filepath.Join(dir, fileAttachments))
+		if err != nil {
+			return report, err
+		}
+	}
+
+	return report, nil
+}
+
+// verifyAttachments возвращает ключи вложений из списка, файлов которых нет в хранилище
+func (s *Service) verifyAttachments(ctx context.Context, path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open attachment manifest: %w", err)
+	}
+	defer file.Close()
+
+	var missing []string
+	decoder := json.NewDecoder(bufio.NewReader(file))
+	for decoder.More() {
+		var entry attachmentEntry
+		if err := decoder.Decode(&entry); err != nil {
+			return missing, fmt.Errorf("failed to read attachment manifest: %w", err)
+		}
+
+		object, err := s.storage.Get(ctx, entry.StorageKey)
+		if err != nil {
+			missing = append(missing, entry.StorageKey)
+			continue
+		}
+		object.Close()
+	}
+	return missing, nil
+}
@@ -0,0 +1,28 @@
+package domain
+
+import (
+	"io"
+	"time"
+)
+
+// Attachment представляет файл, прикрепленный к задаче или комментарию.
+// Содержимое хранится в хранилище файлов под ключом StorageKey
+type Attachment struct {
+	ID          string    `json:"id" db:"id"`
+	TaskID      string    `json:"task_id" db:"task_id"`
+	CommentID   *string   `json:"comment_id,omitempty" db:"comment_id"` // Задан для вложений комментариев
+	FileName    string    `json:"file_name" db:"file_name"`
+	ContentType string    `json:"content_type" db:"content_type"`
+	Size        int64     `json:"size" db:"size"`
+	StorageKey  string    `json:"-" db:"storage_key"`
+	UploadedBy  string    `json:"uploaded_by" db:"uploaded_by"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+// AttachmentUpload представляет загружаемый файл
+type AttachmentUpload struct {
+	FileName    string
+	ContentType string
+	Size        int64
+	Content     io.Reader
+}
@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"time"
+)
+
+// AuditAction определяет вид изменения в журнале аудита
+type AuditAction string
+
+const (
+	// AuditActionCreate - создание сущности
+	AuditActionCreate AuditAction = "create"
+	// AuditActionUpdate - изменение сущности, в том числе действия над ней
+	AuditActionUpdate AuditAction = "update"
+	// AuditActionDelete - удаление сущности
+	AuditActionDelete AuditAction = "delete"
+)
+
+// AuditRecord представляет запись журнала аудита об изменении сущности.
+// Changes содержит изменения полей в виде {"поле": {"old": ..., "new": ...}}; для изменений,
+// о которых сервисы не сообщают подробно, в поле request сохраняется тело запроса
+type AuditRecord struct {
+	ID         string                 `json:"id" db:"id"`
+	ActorID    *string                `json:"actor_id,omitempty" db:"actor_id"` // Пустой для изменений, сделанных системой
+	Action     AuditAction            `json:"action" db:"action"`
+	EntityType string                 `json:"entity_type" db:"entity_type"`
+	EntityID   string                 `json:"entity_id,omitempty" db:"entity_id"`
+	Changes    map[string]interface{} `json:"changes,omitempty" db:"-"`
+	IP         *string                `json:"ip,omitempty" db:"ip"`
+	Route      *string                `json:"route,omitempty" db:"route"` // Шаблон маршрута API, через который сделано изменение
+	CreatedAt  time.Time              `json:"created_at" db:"created_at"`
+}
+
+// AuditFilterOptions содержит параметры фильтрации журнала аудита
+type AuditFilterOptions struct {
+	ActorID    *string      `json:"actor_id,omitempty"`
+	Action     *AuditAction `json:"action,omitempty"`
+	EntityType *string      `json:"entity_type,omitempty"`
+	EntityID   *string      `json:"entity_id,omitempty"`
+	From       *time.Time   `json:"from,omitempty"`
+	To         *time.Time   `json:"to,omitempty"`
+}
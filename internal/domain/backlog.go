@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"time"
+)
+
+// TaskTagStale - тег, которым планировщик помечает задачи, слишком долго лежащие в бэклоге
+const TaskTagStale = "stale"
+
+// BacklogStatuses перечисляет статусы задач, которые считаются бэклогом
+var BacklogStatuses = []TaskStatus{TaskStatusNew, TaskStatusOnHold}
+
+// BacklogAgeBand описывает диапазон возраста задач бэклога в днях.
+// MaxDays = nil означает диапазон без верхней границы
+type BacklogAgeBand struct {
+	Label   string `json:"label"`
+	MinDays int    `json:"min_days"`
+	MaxDays *int   `json:"max_days,omitempty"`
+}
+
+// BacklogAgeBands - диапазоны отчета о возрасте бэклога
+var BacklogAgeBands = []BacklogAgeBand{
+	{Label: "0-7", MinDays: 0, MaxDays: intPtr(7)},
+	{Label: "8-30", MinDays: 8, MaxDays: intPtr(30)},
+	{Label: "31-90", MinDays: 31, MaxDays: intPtr(90)},
+	{Label: "90+", MinDays: 91},
+}
+
+// Contains проверяет, попадает ли возраст в диапазон
+func (b BacklogAgeBand) Contains(ageDays int) bool {
+	return ageDays >= b.MinDays && (b.MaxDays == nil || ageDays <= *b.MaxDays)
+}
+
+// BacklogTask представляет задачу бэклога в отчете о возрасте
+type BacklogTask struct {
+	ID         string       `json:"id"`
+	Title      string       `json:"title"`
+	Status     TaskStatus   `json:"status"`
+	Priority   TaskPriority `json:"priority"`
+	AssigneeID *string      `json:"assignee_id,omitempty"`
+	CreatedAt  time.Time    `json:"created_at"`
+	AgeDays    int          `json:"age_days"`
+	Stale      bool         `json:"stale"`
+	Tags       []string     `json:"tags,omitempty"`
+}
+
+// BacklogAgeGroup содержит задачи бэклога одного диапазона возраста
+type BacklogAgeGroup struct {
+	BacklogAgeBand
+	Count int            `json:"count"`
+	Tasks []*BacklogTask `json:"tasks"`
+}
+
+// BacklogAgeReport представляет отчет о возрасте бэклога проекта
+type BacklogAgeReport struct {
+	ProjectID   string             `json:"project_id"`
+	Total       int                `json:"total"`
+	StaleCount  int                `json:"stale_count"`
+	Bands       []*BacklogAgeGroup `json:"bands"`
+	GeneratedAt time.Time          `json:"generated_at"`
+}
+
+// StaleTaskLabel описывает задачу, помеченную как устаревшая
+type StaleTaskLabel struct {
+	TaskID    string `json:"task_id" db:"task_id"`
+	ProjectID string `json:"project_id" db:"project_id"`
+}
+
+func intPtr(v int) *int {
+	return &v
+}
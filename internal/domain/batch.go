@@ -0,0 +1,97 @@
+package domain
+
+import (
+	"net/http"
+
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
+)
+
+// MaxBatchSize - максимальное число элементов в одном пакетном запросе
+const MaxBatchSize = 100
+
+// BatchItemResult описывает результат обработки одного элемента пакета
+type BatchItemResult struct {
+	Index  int                 `json:"index"`
+	ID     string              `json:"id,omitempty"`
+	Status int                 `json:"status"`
+	Data   interface{}         `json:"data,omitempty"`
+	Error  *apperrors.Response `json:"error,omitempty"`
+}
+
+// BatchResult описывает результат пакетной операции. Элементы обрабатываются
+// независимо: ошибка одного элемента не отменяет обработку остальных
+type BatchResult struct {
+	Total     int               `json:"total"`
+	Succeeded int               `json:"succeeded"`
+	Failed    int               `json:"failed"`
+	Items     []BatchItemResult `json:"items"`
+}
+
+// NewBatchResult создает результат пакетной операции на size элементов
+func NewBatchResult(size int) *BatchResult {
+	return &BatchResult{
+		Total: size,
+		Items: make([]BatchItemResult, 0, size),
+	}
+}
+
+// Add записывает результат элемента. Код и статус ошибки берутся из каталога ошибок
+func (r *BatchResult) Add(index int, id string, data interface{}, err error) {
+	item := BatchItemResult{
+		Index:  index,
+		ID:     id,
+		Status: http.StatusOK,
+	}
+
+	if err != nil {
+		appErr := apperrors.FromError(err)
+		response := appErr.Response()
+		item.Status = appErr.StatusCode
+		item.Error = &response
+		r.Failed++
+	} else {
+		item.Data = data
+		r.Succeeded++
+	}
+
+	r.Items = append(r.Items, item)
+}
+
+// StatusCode возвращает HTTP-статус ответа: 200, если все элементы обработаны
+// успешно, и 207 Multi-Status, если хотя бы один элемент завершился ошибкой
+func (r *BatchResult) StatusCode() int {
+	if r.Failed > 0 {
+		return http.StatusMultiStatus
+	}
+	return http.StatusOK
+}
+
+// TaskBulkUpdateItem описывает изменение одной задачи в пакетном обновлении
+type TaskBulkUpdateItem struct {
+	ID string `json:"id" validate:"required,uuid"`
+	TaskUpdateRequest
+}
+
+// TaskBulkUpdateRequest представляет запрос на пакетное обновление задач
+type TaskBulkUpdateRequest struct {
+	Items []TaskBulkUpdateItem `json:"items" validate:"required,min=1,max=100"`
+}
+
+// TaskImportRequest представляет запрос на импорт задач в проект
+type TaskImportRequest struct {
+	Tasks []TaskCreateRequest `json:"tasks" validate:"required,min=1,max=100"`
+}
+
+// NotificationBatchAction - действие пакетной операции с уведомлениями
+type NotificationBatchAction string
+
+const (
+	NotificationBatchRead   NotificationBatchAction = "read"
+	NotificationBatchDelete NotificationBatchAction = "delete"
+)
+
+// NotificationBatchRequest представляет пакетную операцию с уведомлениями
+type NotificationBatchRequest struct {
+	Action NotificationBatchAction `json:"action" validate:"required,oneof=read delete"`
+	IDs    []string                `json:"ids" validate:"required,min=1,max=100"`
+}
@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"time"
+)
+
+// ProjectBoardColumn представляет настроенную колонку канбан-доски проекта. Колонка со статусом
+// содержит задачи в этом статусе, колонка без статуса - задачи, перенесенные в нее вручную
+type ProjectBoardColumn struct {
+	ID        string      `json:"id" db:"id"`
+	ProjectID string      `json:"project_id" db:"project_id"`
+	Name      string      `json:"name" db:"name"`
+	Status    *TaskStatus `json:"status,omitempty" db:"status"`
+	Position  int         `json:"position" db:"position"`
+	CreatedAt time.Time   `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time   `json:"updated_at" db:"updated_at"`
+}
+
+// BoardColumnRequest представляет данные для создания или изменения колонки доски
+type BoardColumnRequest struct {
+	Name   string      `json:"name" validate:"required,min=1,max=100"`
+	Status *TaskStatus `json:"status,omitempty" validate:"omitempty,task_status"` // Без статуса колонка пользовательская
+}
+
+// BoardColumnOrderRequest задает новый порядок колонок доски; перечисляются все колонки
+type BoardColumnOrderRequest struct {
+	ColumnIDs []string `json:"column_ids" validate:"required,min=1,max=50,uuid_list"`
+}
+
+// BoardMoveRequest представляет перенос карточки задачи в колонку доски.
+// Место в колонке задается не больше чем одним из полей after_id, before_id и position;
+// без них задача сохраняет свое место в порядке проекта
+type BoardMoveRequest struct {
+	TaskID   string  `json:"task_id" validate:"required,uuid"`
+	ColumnID string  `json:"column_id" validate:"required,uuid"`
+	AfterID  *string `json:"after_id,omitempty" validate:"omitempty,uuid"`  // Поставить сразу после задачи
+	BeforeID *string `json:"before_id,omitempty" validate:"omitempty,uuid"` // Поставить сразу перед задачей
+	Position *string `json:"position,omitempty" validate:"omitempty,oneof=top bottom"`
+}
@@ -9,6 +9,7 @@ type Comment struct {
 	ID        string    `json:"id" db:"id"`
 	TaskID    string    `json:"task_id" db:"task_id"`
 	UserID    string    `json:"user_id" db:"user_id"`
+	ParentCommentID *string `json:"parent_comment_id,omitempty" db:"parent_comment_id"`
 	Content   string    `json:"content" db:"content"`
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
@@ -17,6 +18,7 @@ type Comment struct {
 // CommentCreateRequest представляет данные для создания комментария
 type CommentCreateRequest struct {
 	TaskID  string `json:"task_id" validate:"required,uuid"`
+	ParentCommentID *string `json:"parent_comment_id,omitempty" validate:"omitempty,uuid"` // Комментарий той же задачи, на который дается ответ
 	Content string `json:"content" validate:"required,min=1"`
 }
 
@@ -25,15 +27,31 @@ type CommentUpdateRequest struct {
 	Content string `json:"content" validate:"required,min=1"`
 }
 
+// CommentDraft представляет черновик комментария пользователя к задаче
+type CommentDraft struct {
+	TaskID    string    `json:"task_id"`
+	UserID    string    `json:"user_id"`
+	Content   string    `json:"content"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// CommentDraftRequest представляет данные для сохранения черновика комментария
+type CommentDraftRequest struct {
+	Content string `json:"content" validate:"required,max=10000"`
+}
+
 // CommentResponse представляет данные комментария для API-ответов
 type CommentResponse struct {
 	ID        string    `json:"id"`
 	TaskID    string    `json:"task_id"`
 	UserID    string    `json:"user_id"`
+	ParentCommentID *string `json:"parent_comment_id,omitempty"`
 	User      UserBrief `json:"user"`
 	Content   string    `json:"content"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
+	Replies   []CommentResponse `json:"replies,omitempty"` // Ответы на комментарий, только в ответах с деревом комментариев
+	ReplyCount int `json:"reply_count,omitempty"` // Всего прямых ответов; ответы сверх вложенных загружаются через /comments/{id}/replies
 }
 
 // ToResponse преобразует Comment в CommentResponse
@@ -42,6 +60,7 @@ func (c *Comment) ToResponse(user UserBrief) CommentResponse {
 		ID:        c.ID,
 		TaskID:    c.TaskID,
 		UserID:    c.UserID,
+		ParentCommentID: c.ParentCommentID,
 		User:      user,
 		Content:   c.Content,
 		CreatedAt: c.CreatedAt,
@@ -49,6 +68,31 @@ func (c *Comment) ToResponse(user UserBrief) CommentResponse {
 	}
 }
 
+// NestCommentReplies раскладывает ответы по родительским комментариям.
+// Ответы, родитель которых не входит ни в roots, ни в replies, отбрасываются;
+// порядок ответов внутри ветки сохраняется таким же, как в replies
+func NestCommentReplies(roots []CommentResponse, replies []CommentResponse) []CommentResponse {
+	children := make(map[string][]CommentResponse)
+	for _, reply := range replies {
+		if reply.ParentCommentID != nil {
+			children[*reply.ParentCommentID] = append(children[*reply.ParentCommentID], reply)
+		}
+	}
+
+	var attach func(comment *CommentResponse)
+	attach = func(comment *CommentResponse) {
+		comment.Replies = children[comment.ID]
+		for i := range comment.Replies {
+			attach(&comment.Replies[i])
+		}
+	}
+
+	for i := range roots {
+		attach(&roots[i])
+	}
+	return roots
+}
+
 // CommentFilterOptions представляет параметры для фильтрации комментариев
 type CommentFilterOptions struct {
 	TaskID    *string    `json:"task_id,omitempty"`
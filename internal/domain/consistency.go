@@ -0,0 +1,81 @@
+package domain
+
+import "time"
+
+// ConsistencyCheck определяет проверку согласованности данных между хранилищами
+type ConsistencyCheck string
+
+const (
+	// ConsistencyCheckTaskCache сверяет задачи в кэше Redis с базой
+	ConsistencyCheckTaskCache ConsistencyCheck = "task_cache"
+	// ConsistencyCheckProjectCache сверяет проекты в кэше Redis с базой
+	ConsistencyCheckProjectCache ConsistencyCheck = "project_cache"
+	// ConsistencyCheckSearchIndex проверяет поисковые индексы PostgreSQL
+	ConsistencyCheckSearchIndex ConsistencyCheck = "search_index"
+	// ConsistencyCheckUnreadCounts сверяет счетчики непрочитанных уведомлений с базой
+	ConsistencyCheckUnreadCounts ConsistencyCheck = "unread_counts"
+	// ConsistencyCheckSpentHours сверяет затраченное время задач с суммой записей о времени
+	ConsistencyCheckSpentHours ConsistencyCheck = "spent_hours"
+)
+
+// ConsistencyChecks содержит все проверки в порядке выполнения
+var ConsistencyChecks = []ConsistencyCheck{
+	ConsistencyCheckTaskCache,
+	ConsistencyCheckProjectCache,
+	ConsistencyCheckSearchIndex,
+	ConsistencyCheckUnreadCounts,
+	ConsistencyCheckSpentHours,
+}
+
+// ConsistencyRunStatus определяет состояние запуска проверки согласованности
+type ConsistencyRunStatus string
+
+const (
+	// ConsistencyRunRunning - проверка выполняется
+	ConsistencyRunRunning ConsistencyRunStatus = "running"
+	// ConsistencyRunCompleted - все проверки выполнены
+	ConsistencyRunCompleted ConsistencyRunStatus = "completed"
+	// ConsistencyRunFailed - часть проверок завершилась ошибкой
+	ConsistencyRunFailed ConsistencyRunStatus = "failed"
+)
+
+// ConsistencyRequest представляет запрос на запуск проверки согласованности
+type ConsistencyRequest struct {
+	Checks  []ConsistencyCheck `json:"checks" validate:"dive,oneof=task_cache project_cache search_index unread_counts spent_hours"` // Пустой список означает все проверки
+	Repair  bool               `json:"repair"`                                                                                       // Исправлять найденные расхождения
+	Reindex bool               `json:"reindex"`                                                                                      // Перестраивать поисковые индексы, даже если они исправны
+}
+
+// ConsistencyCheckResult содержит результат одной проверки
+type ConsistencyCheckResult struct {
+	Check    ConsistencyCheck `json:"check"`
+	Checked  int              `json:"checked"`           // Количество проверенных записей
+	Drifted  int              `json:"drifted"`           // Количество найденных расхождений
+	Repaired int              `json:"repaired"`          // Количество исправленных расхождений
+	Samples  []string         `json:"samples,omitempty"` // Примеры расхождений
+	Error    string           `json:"error,omitempty"`
+}
+
+// ConsistencyReport представляет отчет о запуске проверки согласованности
+type ConsistencyReport struct {
+	ID         string                    `json:"id"`
+	Status     ConsistencyRunStatus      `json:"status"`
+	Repair     bool                      `json:"repair"`
+	StartedBy  *string                   `json:"started_by,omitempty"` // ID администратора; пусто для запуска по расписанию
+	StartedAt  time.Time                 `json:"started_at"`
+	FinishedAt *time.Time                `json:"finished_at,omitempty"`
+	Results    []*ConsistencyCheckResult `json:"results"`
+}
+
+// SpentHoursDrift описывает расхождение затраченного времени задачи с суммой записей о времени
+type SpentHoursDrift struct {
+	TaskID      string  `json:"task_id" db:"task_id"`
+	SpentHours  float64 `json:"spent_hours" db:"spent_hours"`   // Значение в задаче
+	LoggedHours float64 `json:"logged_hours" db:"logged_hours"` // Сумма записей о времени
+}
+
+// SearchIndexState описывает состояние поискового индекса
+type SearchIndexState struct {
+	Name  string `db:"name"`
+	Valid bool   `db:"valid"`
+}
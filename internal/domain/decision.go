@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"time"
+)
+
+// Decision представляет запись журнала решений проекта
+type Decision struct {
+	ID           string     `json:"id" db:"id"`
+	ProjectID    string     `json:"project_id" db:"project_id"`
+	Title        string     `json:"title" db:"title"`
+	Context      string     `json:"context" db:"context"`
+	Decision     string     `json:"decision" db:"decision"`
+	Alternatives []string   `json:"alternatives" db:"-"` // Хранятся в массиве alternatives
+	ApproverID   *string    `json:"approver_id,omitempty" db:"approver_id"`
+	DecidedAt    *time.Time `json:"decided_at,omitempty" db:"decided_at"`
+	CreatedBy    string     `json:"created_by" db:"created_by"`
+	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at" db:"updated_at"`
+	TaskIDs      []string   `json:"task_ids" db:"-"`
+}
+
+// DecisionCreateRequest представляет данные для записи решения
+type DecisionCreateRequest struct {
+	Title        string     `json:"title" validate:"required,min=3,max=200"`
+	Context      string     `json:"context" validate:"max=10000"`
+	Decision     string     `json:"decision" validate:"required,min=1,max=10000"`
+	Alternatives []string   `json:"alternatives,omitempty" validate:"omitempty,max=20,dive,min=1,max=2000"`
+	ApproverID   *string    `json:"approver_id,omitempty" validate:"omitempty,uuid"`
+	DecidedAt    *time.Time `json:"decided_at,omitempty"` // По умолчанию - момент записи
+	TaskIDs      []string   `json:"task_ids,omitempty" validate:"omitempty,max=50,uuid_list"`
+}
+
+// DecisionUpdateRequest представляет данные для обновления решения
+type DecisionUpdateRequest struct {
+	Title        *string    `json:"title,omitempty" validate:"omitempty,min=3,max=200"`
+	Context      *string    `json:"context,omitempty" validate:"omitempty,max=10000"`
+	Decision     *string    `json:"decision,omitempty" validate:"omitempty,min=1,max=10000"`
+	Alternatives *[]string  `json:"alternatives,omitempty" validate:"omitempty,max=20,dive,min=1,max=2000"`
+	ApproverID   *string    `json:"approver_id,omitempty" validate:"omitempty,uuid"`
+	DecidedAt    *time.Time `json:"decided_at,omitempty"`
+}
+
+// DecisionTaskRequest представляет запрос на связь решения с задачей
+type DecisionTaskRequest struct {
+	TaskID string `json:"task_id" validate:"required,uuid"`
+}
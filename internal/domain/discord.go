@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"time"
+)
+
+// ProjectDiscordChannel представляет канал Discord проекта.
+// Уведомления доставляются через вебхук канала либо ботом по ID канала
+type ProjectDiscordChannel struct {
+	ProjectID         string             `json:"project_id" db:"project_id"`
+	WebhookURL        string             `json:"-" db:"webhook_url"`
+	ChannelID         string             `json:"channel_id,omitempty" db:"channel_id"`
+	Enabled           bool               `json:"enabled" db:"enabled"`
+	NotificationTypes []NotificationType `json:"notification_types" db:"-"`
+	CreatedBy         string             `json:"created_by" db:"created_by"`
+	CreatedAt         time.Time          `json:"created_at" db:"created_at"`
+	UpdatedAt         time.Time          `json:"updated_at" db:"updated_at"`
+}
+
+// HasNotificationType проверяет, публикуются ли в канал уведомления указанного типа
+func (c *ProjectDiscordChannel) HasNotificationType(notificationType NotificationType) bool {
+	for _, t := range c.NotificationTypes {
+		if t == notificationType {
+			return true
+		}
+	}
+	return false
+}
+
+// ProjectDiscordChannelRequest представляет данные для настройки канала Discord проекта.
+// Указывается либо webhook_url, либо channel_id; если не указано ни то ни другое, сохраняется текущий способ доставки
+type ProjectDiscordChannelRequest struct {
+	WebhookURL        string             `json:"webhook_url,omitempty" validate:"omitempty,url,startswith=https://,max=2000,excluded_with=ChannelID"`
+	ChannelID         string             `json:"channel_id,omitempty" validate:"omitempty,numeric,max=32"`
+	Enabled           *bool              `json:"enabled,omitempty"`
+	NotificationTypes []NotificationType `json:"notification_types" validate:"required,min=1,dive,oneof=task_assigned task_updated task_commented task_due_soon task_overdue project_member_added project_updated"`
+}
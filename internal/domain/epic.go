@@ -0,0 +1,101 @@
+package domain
+
+import (
+	"time"
+)
+
+// Epic представляет эпик - группу задач проекта, объединенных общей целью
+type Epic struct {
+	ID         string        `json:"id" db:"id"`
+	ProjectID  string        `json:"project_id" db:"project_id"`
+	Title      string        `json:"title" db:"title"`
+	Goal       string        `json:"goal" db:"goal"`
+	OwnerID    *string       `json:"owner_id,omitempty" db:"owner_id"`
+	TargetDate *time.Time    `json:"target_date,omitempty" db:"target_date"`
+	CreatedBy  string        `json:"created_by" db:"created_by"`
+	CreatedAt  time.Time     `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time     `json:"updated_at" db:"updated_at"`
+	Progress   *EpicProgress `json:"progress,omitempty" db:"-"`
+}
+
+// EpicProgress представляет сводный прогресс задач эпика.
+// Отмененные задачи не учитываются
+type EpicProgress struct {
+	EpicID          string  `json:"-" db:"epic_id"`
+	TotalTasks      int     `json:"total_tasks" db:"total_tasks"`
+	CompletedTasks  int     `json:"completed_tasks" db:"completed_tasks"`
+	InProgressTasks int     `json:"in_progress_tasks" db:"in_progress_tasks"`
+	OverdueTasks    int     `json:"overdue_tasks" db:"overdue_tasks"`
+	EstimatedHours  float64 `json:"estimated_hours" db:"estimated_hours"`
+	SpentHours      float64 `json:"spent_hours" db:"spent_hours"`
+	Percent         float64 `json:"percent" db:"-"`
+}
+
+// EpicCreateRequest представляет данные для создания эпика
+type EpicCreateRequest struct {
+	Title      string     `json:"title" validate:"required,min=3,max=200"`
+	Goal       string     `json:"goal" validate:"max=5000"`
+	OwnerID    *string    `json:"owner_id,omitempty" validate:"omitempty,uuid"`
+	TargetDate *time.Time `json:"target_date,omitempty"`
+}
+
+// EpicUpdateRequest представляет данные для обновления эпика
+type EpicUpdateRequest struct {
+	Title           *string    `json:"title,omitempty" validate:"omitempty,min=3,max=200"`
+	Goal            *string    `json:"goal,omitempty" validate:"omitempty,max=5000"`
+	OwnerID         *string    `json:"owner_id,omitempty" validate:"omitempty,uuid"`
+	TargetDate      *time.Time `json:"target_date,omitempty"`
+	ClearOwner      bool       `json:"clear_owner,omitempty"`
+	ClearTargetDate bool       `json:"clear_target_date,omitempty"`
+}
+
+// TaskEpicRequest представляет запрос на привязку задачи к эпику.
+// Пустой epic_id отвязывает задачу
+type TaskEpicRequest struct {
+	EpicID *string `json:"epic_id" validate:"omitempty,uuid"`
+}
+
+// EpicComment представляет комментарий к эпику
+type EpicComment struct {
+	ID        string     `json:"id" db:"id"`
+	EpicID    string     `json:"epic_id" db:"epic_id"`
+	UserID    string     `json:"user_id" db:"user_id"`
+	User      *UserBrief `json:"user,omitempty" db:"-"`
+	Content   string     `json:"content" db:"content"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// EpicCommentRequest представляет данные для комментария к эпику
+type EpicCommentRequest struct {
+	Content string `json:"content" validate:"required,min=1,max=10000"`
+}
+
+// BoardSwimlaneEpic - группировка доски задач по эпикам
+const BoardSwimlaneEpic = "epic"
+
+// BoardColumn представляет колонку доски задач с ее задачами в порядке ранга.
+// Статус не задан у пользовательских колонок
+type BoardColumn struct {
+	ID     string         `json:"id"`
+	Name   string         `json:"name"`
+	Status *TaskStatus    `json:"status,omitempty"`
+	Tasks  []TaskResponse `json:"tasks"`
+}
+
+// BoardSwimlane представляет горизонтальную полосу доски задач.
+// При группировке по эпикам полоса без эпика имеет пустой EpicID
+type BoardSwimlane struct {
+	EpicID   *string        `json:"epic_id,omitempty"`
+	Title    string         `json:"title"`
+	Progress *EpicProgress  `json:"progress,omitempty"`
+	Columns  []*BoardColumn `json:"columns"`
+}
+
+// ProjectBoard представляет канбан-доску задач проекта
+type ProjectBoard struct {
+	ProjectID string           `json:"project_id"`
+	Swimlane  string           `json:"swimlane,omitempty"`
+	Swimlanes []*BoardSwimlane `json:"swimlanes"`
+	Truncated bool             `json:"truncated"`
+}
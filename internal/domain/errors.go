@@ -1,27 +1,31 @@
 package domain
 
-import "errors"
+import apperrors "github.com/nurlyy/task_manager/pkg/errors"
 
 // Стандартные ошибки приложения
 var (
 	// ErrNotFound возвращается, когда запрашиваемый ресурс не найден
-	ErrNotFound = errors.New("resource not found")
+	ErrNotFound = apperrors.New(apperrors.CodeNotFound, "resource not found")
 
 	// ErrInvalidInput возвращается при невалидных входных данных
-	ErrInvalidInput = errors.New("invalid input")
+	ErrInvalidInput = apperrors.New(apperrors.CodeInvalidInput, "invalid input")
 
 	// ErrUnauthorized возвращается при отсутствии аутентификации
-	ErrUnauthorized = errors.New("unauthorized")
+	ErrUnauthorized = apperrors.New(apperrors.CodeUnauthorized, "unauthorized")
 
 	// ErrForbidden возвращается при недостаточных правах доступа
-	ErrForbidden = errors.New("forbidden")
+	ErrForbidden = apperrors.New(apperrors.CodeForbidden, "forbidden")
 
 	// ErrConflict возвращается при конфликте данных
-	ErrConflict = errors.New("conflict")
+	ErrConflict = apperrors.New(apperrors.CodeConflict, "conflict")
 
 	// ErrInternal возвращается при внутренней ошибке сервера
-	ErrInternal = errors.New("internal server error")
+	ErrInternal = apperrors.New(apperrors.CodeInternalError, "internal server error")
 
 	// ErrBadRequest возвращается при некорректном запросе
-	ErrBadRequest = errors.New("bad request")
+	ErrBadRequest = apperrors.New(apperrors.CodeBadRequest, "bad request")
 )
+
+// FieldViolation описывает нарушение правила заполнения поля. Это та же структура,
+// что и ошибки полей в ответах API, поэтому нарушения передаются клиенту без преобразования
+type FieldViolation = apperrors.FieldError
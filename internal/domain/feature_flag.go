@@ -0,0 +1,37 @@
+package domain
+
+import "time"
+
+// FeatureFlagSource указывает, откуда взято действующее значение флага
+type FeatureFlagSource string
+
+const (
+	// FeatureFlagSourceConfig - значение из конфигурации приложения
+	FeatureFlagSourceConfig FeatureFlagSource = "config"
+	// FeatureFlagSourceOverride - значение, переопределенное администратором
+	FeatureFlagSourceOverride FeatureFlagSource = "override"
+)
+
+// FeatureFlag представляет флаг функциональности с правилами постепенного включения.
+// Флаг включен для пользователя, если он указан явно, подходит по роли или отделу,
+// либо попадает в процент раскатки. Выключенный флаг не включается ни для кого
+type FeatureFlag struct {
+	Name        string            `json:"name"`
+	Enabled     bool              `json:"enabled"`
+	Percentage  int               `json:"percentage"`            // Доля пользователей, 0-100
+	UserIDs     []string          `json:"user_ids,omitempty"`    // Пользователи, для которых флаг включен всегда
+	Roles       []UserRole        `json:"roles,omitempty"`       // Роли, для которых флаг включен всегда
+	Departments []string          `json:"departments,omitempty"` // Отделы, для которых флаг включен всегда
+	Source      FeatureFlagSource `json:"source"`
+	UpdatedBy   *string           `json:"updated_by,omitempty"`
+	UpdatedAt   *time.Time        `json:"updated_at,omitempty"`
+}
+
+// FeatureFlagRequest представляет запрос на переопределение флага администратором
+type FeatureFlagRequest struct {
+	Enabled     bool       `json:"enabled"`
+	Percentage  int        `json:"percentage" validate:"min=0,max=100"`
+	UserIDs     []string   `json:"user_ids" validate:"omitempty,max=1000,uuid_list"`
+	Roles       []UserRole `json:"roles" validate:"omitempty,dive,oneof=admin manager developer viewer"`
+	Departments []string   `json:"departments" validate:"omitempty,max=100,dive,min=1,max=100"`
+}
@@ -0,0 +1,100 @@
+package domain
+
+import (
+	"time"
+)
+
+// FeedbackPortal представляет публичный портал обратной связи проекта.
+// Внешние пользователи отправляют через него запросы без учетной записи
+type FeedbackPortal struct {
+	ProjectID   string    `json:"project_id" db:"project_id"`
+	PublicKey   string    `json:"public_key" db:"public_key"`
+	URL         string    `json:"url" db:"-"`
+	Title       string    `json:"title" db:"title"`
+	Description string    `json:"description" db:"description"`
+	Enabled     bool      `json:"enabled" db:"enabled"`
+	CreatedBy   string    `json:"created_by" db:"created_by"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// FeedbackPortalRequest представляет данные для настройки портала обратной связи
+type FeedbackPortalRequest struct {
+	Title       string `json:"title" validate:"required,min=3,max=200"`
+	Description string `json:"description" validate:"max=2000"`
+	Enabled     *bool  `json:"enabled,omitempty"`
+}
+
+// FeedbackPortalInfo представляет публичные сведения о портале
+type FeedbackPortalInfo struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	ProjectName string `json:"project_name"`
+}
+
+// FeedbackStatus определяет статус запроса обратной связи
+type FeedbackStatus string
+
+const (
+	// FeedbackStatusPending - запрос ожидает модерации
+	FeedbackStatusPending FeedbackStatus = "pending"
+	// FeedbackStatusApproved - по запросу создана задача
+	FeedbackStatusApproved FeedbackStatus = "approved"
+	// FeedbackStatusMerged - запрос объединен с существующей задачей
+	FeedbackStatusMerged FeedbackStatus = "merged"
+	// FeedbackStatusRejected - запрос отклонен
+	FeedbackStatusRejected FeedbackStatus = "rejected"
+)
+
+// FeedbackSubmission представляет запрос, отправленный через портал обратной связи
+type FeedbackSubmission struct {
+	ID                 string           `json:"id" db:"id"`
+	ProjectID          string           `json:"project_id" db:"project_id"`
+	Title              string           `json:"title" db:"title"`
+	Description        string           `json:"description" db:"description"`
+	SubmitterEmail     string           `json:"submitter_email" db:"submitter_email"`
+	SubmitterName      string           `json:"submitter_name,omitempty" db:"submitter_name"`
+	Status             FeedbackStatus   `json:"status" db:"status"`
+	TaskID             *string          `json:"task_id,omitempty" db:"task_id"`
+	ModerationNote     string           `json:"moderation_note,omitempty" db:"moderation_note"`
+	ModeratedBy        *string          `json:"moderated_by,omitempty" db:"moderated_by"`
+	ModeratedAt        *time.Time       `json:"moderated_at,omitempty" db:"moderated_at"`
+	NotifiedTaskStatus *string          `json:"-" db:"notified_task_status"`
+	SimilarTasks       []*TypeaheadItem `json:"similar_tasks,omitempty" db:"-"` // Кандидаты для объединения
+	CreatedAt          time.Time        `json:"created_at" db:"created_at"`
+	UpdatedAt          time.Time        `json:"updated_at" db:"updated_at"`
+}
+
+// FeedbackSubmitRequest представляет запрос внешнего пользователя.
+// Website - ловушка для ботов: поле скрыто в форме, и люди его не заполняют
+type FeedbackSubmitRequest struct {
+	Title       string `json:"title" validate:"required,min=3,max=200"`
+	Description string `json:"description" validate:"required,max=10000"`
+	Email       string `json:"email" validate:"required,email,max=255"`
+	Name        string `json:"name" validate:"max=100"`
+	Website     string `json:"website,omitempty"`
+}
+
+// FeedbackSubmitResponse представляет ответ на отправку запроса
+type FeedbackSubmitResponse struct {
+	ID     string         `json:"id"`
+	Status FeedbackStatus `json:"status"`
+}
+
+// FeedbackApproveRequest представляет данные для создания задачи по запросу
+type FeedbackApproveRequest struct {
+	Title    string       `json:"title,omitempty" validate:"omitempty,min=3,max=200"` // По умолчанию - заголовок запроса
+	Priority TaskPriority `json:"priority,omitempty" validate:"omitempty,task_priority"`
+	Note     string       `json:"note,omitempty" validate:"max=2000"`
+}
+
+// FeedbackMergeRequest представляет данные для объединения запроса с существующей задачей
+type FeedbackMergeRequest struct {
+	TaskID string `json:"task_id" validate:"required,uuid"`
+	Note   string `json:"note,omitempty" validate:"max=2000"`
+}
+
+// FeedbackRejectRequest представляет данные для отклонения запроса; причина отправляется автору
+type FeedbackRejectRequest struct {
+	Reason string `json:"reason" validate:"max=2000"`
+}
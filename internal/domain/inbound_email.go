@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"time"
+)
+
+// ProjectInboundEmail представляет адрес проекта для создания задач по email.
+// Письмо на адрес создает задачу от имени отправителя, если он участник проекта
+type ProjectInboundEmail struct {
+	ProjectID     string    `json:"project_id" db:"project_id"`
+	Token         string    `json:"-" db:"token"`
+	Address       string    `json:"address" db:"-"`
+	Enabled       bool      `json:"enabled" db:"enabled"`
+	SpamThreshold *float64  `json:"spam_threshold,omitempty" db:"spam_threshold"` // nil - порог по умолчанию
+	CreatedBy     string    `json:"created_by" db:"created_by"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// ProjectInboundEmailRequest представляет данные для изменения настроек адреса проекта.
+// 0 в spam_threshold возвращает порог по умолчанию
+type ProjectInboundEmailRequest struct {
+	Enabled       *bool    `json:"enabled,omitempty"`
+	SpamThreshold *float64 `json:"spam_threshold,omitempty" validate:"omitempty,gte=0,lte=100"`
+}
+
+// InboundEmailMessage представляет обработанное входящее письмо
+type InboundEmailMessage struct {
+	MessageID  string    `json:"message_id" db:"message_id"`
+	ProjectID  string    `json:"project_id" db:"project_id"`
+	TaskID     *string   `json:"task_id,omitempty" db:"task_id"`
+	Sender     string    `json:"sender" db:"sender"`
+	ReceivedAt time.Time `json:"received_at" db:"received_at"`
+}
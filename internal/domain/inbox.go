@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"time"
+)
+
+// InboxItem представляет задачу, записанную без проекта. Она ждет разбора во входящих
+// пользователя, пока не будет перенесена в проект
+type InboxItem struct {
+	ID          string        `json:"id" db:"id"`
+	UserID      string        `json:"user_id" db:"user_id"`
+	Title       string        `json:"title" db:"title"`
+	Description string        `json:"description" db:"description"`
+	Priority    *TaskPriority `json:"priority,omitempty" db:"priority"`
+	DueDate     *time.Time    `json:"due_date,omitempty" db:"due_date"`
+	CreatedAt   time.Time     `json:"created_at" db:"created_at"`
+}
+
+// InboxCaptureRequest представляет запрос на быструю запись задачи во входящие
+type InboxCaptureRequest struct {
+	Title       string        `json:"title" validate:"required,min=3,max=200"`
+	Description string        `json:"description" validate:"max=10000"`
+	Priority    *TaskPriority `json:"priority,omitempty" validate:"omitempty,task_priority"`
+	DueDate     *time.Time    `json:"due_date,omitempty"`
+}
+
+// InboxTriageItem описывает перенос одной записи из входящих в проект.
+// Приоритет и срок, если указаны, заменяют записанные при захвате
+type InboxTriageItem struct {
+	ID         string        `json:"id" validate:"required,uuid"`
+	ProjectID  string        `json:"project_id" validate:"required,uuid"`
+	Priority   *TaskPriority `json:"priority,omitempty" validate:"omitempty,task_priority"`
+	AssigneeID *string       `json:"assignee_id,omitempty" validate:"omitempty,uuid"`
+	DueDate    *time.Time    `json:"due_date,omitempty"`
+}
+
+// InboxTriageRequest представляет запрос на разбор входящих
+type InboxTriageRequest struct {
+	Items []InboxTriageItem `json:"items" validate:"required,min=1,max=100"`
+}
+
+// InboxReminder содержит сведения для напоминания о неразобранных входящих пользователя
+type InboxReminder struct {
+	UserID      string    `db:"user_id"`
+	StaleItems  int       `db:"stale_items"`
+	OldestSince time.Time `db:"oldest_since"`
+}
@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"time"
+)
+
+// IncidentProvider определяет систему управления инцидентами
+type IncidentProvider string
+
+const (
+	// IncidentProviderPagerDuty - PagerDuty (webhooks v3, REST API v2)
+	IncidentProviderPagerDuty IncidentProvider = "pagerduty"
+	// IncidentProviderOpsgenie - Opsgenie (webhook integration, Alert API v2)
+	IncidentProviderOpsgenie IncidentProvider = "opsgenie"
+)
+
+// IncidentStatus определяет состояние инцидента
+type IncidentStatus string
+
+const (
+	// IncidentStatusTriggered - инцидент открыт
+	IncidentStatusTriggered IncidentStatus = "triggered"
+	// IncidentStatusAcknowledged - инцидент подтвержден
+	IncidentStatusAcknowledged IncidentStatus = "acknowledged"
+	// IncidentStatusResolved - инцидент закрыт
+	IncidentStatusResolved IncidentStatus = "resolved"
+)
+
+// IncidentIntegration представляет интеграцию проекта с системой управления инцидентами.
+// Открытый инцидент создает задачу от имени создателя интеграции; подтверждение и закрытие
+// инцидента переводят задачу в статусы из сопоставления, и наоборот
+type IncidentIntegration struct {
+	ID         string           `json:"id" db:"id"`
+	ProjectID  string           `json:"project_id" db:"project_id"`
+	Provider   IncidentProvider `json:"provider" db:"provider"`
+	Secret     string           `json:"-" db:"secret"` // Ключ подписи PagerDuty или токен URL для Opsgenie
+	APIKey     string           `json:"-" db:"api_key"`
+	APIKeySet  bool             `json:"api_key_set" db:"-"` // Без ключа API синхронизация в систему инцидентов отключена
+	FromEmail  string           `json:"from_email,omitempty" db:"from_email"`
+	WebhookURL string           `json:"webhook_url" db:"-"`
+	Mapping    IncidentMapping  `json:"mapping" db:"-"`
+	Enabled    bool             `json:"enabled" db:"enabled"`
+	CreatedBy  string           `json:"created_by" db:"created_by"`
+	CreatedAt  time.Time        `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time        `json:"updated_at" db:"updated_at"`
+}
+
+// IncidentMapping описывает сопоставление инцидентов и задач проекта
+type IncidentMapping struct {
+	AcknowledgeStatus TaskStatus              `json:"acknowledge_status" validate:"omitempty,oneof=in_progress on_hold review"`                        // По умолчанию in_progress
+	ResolveStatuses   []TaskStatus            `json:"resolve_statuses" validate:"omitempty,dive,oneof=in_progress on_hold review completed cancelled"` // Первый статус ставится при закрытии инцидента; по умолчанию completed
+	PriorityMap       map[string]TaskPriority `json:"priority_map,omitempty" validate:"omitempty,dive,keys,min=1,max=50,endkeys,task_priority"`        // Срочность или приоритет инцидента -> приоритет задачи
+	AssigneeID        *string                 `json:"assignee_id,omitempty" validate:"omitempty,uuid"`
+	Tags              []string                `json:"tags,omitempty" validate:"omitempty,max=20,dive,min=1,max=50"`
+}
+
+// IncidentIntegrationRequest представляет данные для настройки интеграции.
+// Пустые secret и api_key оставляют сохраненные значения
+type IncidentIntegrationRequest struct {
+	Enabled   *bool           `json:"enabled,omitempty"`
+	Secret    *string         `json:"secret,omitempty" validate:"omitempty,max=255"` // Ключ подписи вебхука PagerDuty
+	APIKey    *string         `json:"api_key,omitempty" validate:"omitempty,max=255"`
+	FromEmail *string         `json:"from_email,omitempty" validate:"omitempty,email"` // Обязателен для REST API PagerDuty
+	Mapping   IncidentMapping `json:"mapping"`
+}
+
+// IncidentLink представляет связь инцидента с задачей
+type IncidentLink struct {
+	IntegrationID string         `json:"integration_id" db:"integration_id"`
+	IncidentID    string         `json:"incident_id" db:"incident_id"`
+	TaskID        string         `json:"task_id" db:"task_id"`
+	Status        IncidentStatus `json:"status" db:"status"`
+	URL           string         `json:"url" db:"url"`
+	CreatedAt     time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at" db:"updated_at"`
+}
+
+// IncidentEvent представляет событие инцидента, приведенное к общему виду
+type IncidentEvent struct {
+	IncidentID  string
+	Status      IncidentStatus
+	Title       string
+	Description string
+	URL         string
+	Severity    string // Срочность PagerDuty (high, low) или приоритет (P1) и приоритет Opsgenie (P1-P5)
+	Tags        []string
+}
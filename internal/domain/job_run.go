@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"time"
+)
+
+// JobRunStatus определяет состояние запуска задачи планировщика
+type JobRunStatus string
+
+const (
+	// JobRunStatusRunning - задача выполняется
+	JobRunStatusRunning JobRunStatus = "running"
+	// JobRunStatusSucceeded - задача завершилась; отдельные элементы могли обработаться с ошибками
+	JobRunStatusSucceeded JobRunStatus = "succeeded"
+	// JobRunStatusFailed - задача прервалась с ошибкой
+	JobRunStatusFailed JobRunStatus = "failed"
+)
+
+// JobRun представляет один запуск задачи планировщика
+type JobRun struct {
+	ID             string       `json:"id" db:"id"`
+	Job            string       `json:"job" db:"job"`
+	Status         JobRunStatus `json:"status" db:"status"`
+	StartedAt      time.Time    `json:"started_at" db:"started_at"`
+	FinishedAt     *time.Time   `json:"finished_at,omitempty" db:"finished_at"`
+	ItemsProcessed int          `json:"items_processed" db:"items_processed"`
+	Errors         int          `json:"errors" db:"errors"`         // Число элементов, обработанных с ошибкой
+	Error          *string      `json:"error,omitempty" db:"error"` // Ошибка, прервавшая запуск, или последняя ошибка элемента
+	Overran        bool         `json:"overran" db:"overran"`       // Запуск не уложился в интервал до следующего запуска по расписанию
+}
+
+// JobRunFilterOptions содержит параметры фильтрации истории запусков планировщика
+type JobRunFilterOptions struct {
+	Job     *string       `json:"job,omitempty"`
+	Status  *JobRunStatus `json:"status,omitempty"`
+	Overran bool          `json:"overran,omitempty"` // Только запуски, превысившие интервал расписания
+}
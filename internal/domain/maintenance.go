@@ -0,0 +1,19 @@
+package domain
+
+import "time"
+
+// MaintenanceStatus представляет состояние режима обслуживания.
+// Во время обслуживания API принимает только запросы на чтение
+type MaintenanceStatus struct {
+	Enabled   bool       `json:"enabled"`
+	Message   string     `json:"message,omitempty"`    // Сообщение для пользователей
+	StartedAt *time.Time `json:"started_at,omitempty"` // Время включения режима
+	StartedBy *string    `json:"started_by,omitempty"` // ID администратора, включившего режим
+	Forced    bool       `json:"forced,omitempty"`     // Режим включен экземпляром при запуске и не выключается администратором
+}
+
+// MaintenanceRequest представляет запрос на включение или выключение режима обслуживания
+type MaintenanceRequest struct {
+	Enabled bool   `json:"enabled"`
+	Message string `json:"message" validate:"max=500"`
+}
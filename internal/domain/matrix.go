@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"time"
+)
+
+// ProjectMatrixRoom представляет комнату Matrix, в которую публикуются уведомления проекта
+type ProjectMatrixRoom struct {
+	ProjectID         string             `json:"project_id" db:"project_id"`
+	RoomID            string             `json:"room_id" db:"room_id"`
+	RoomAlias         string             `json:"room_alias,omitempty" db:"room_alias"`
+	Enabled           bool               `json:"enabled" db:"enabled"`
+	NotificationTypes []NotificationType `json:"notification_types" db:"-"`
+	CreatedBy         string             `json:"created_by" db:"created_by"`
+	CreatedAt         time.Time          `json:"created_at" db:"created_at"`
+	UpdatedAt         time.Time          `json:"updated_at" db:"updated_at"`
+}
+
+// HasNotificationType проверяет, публикуются ли в комнату уведомления указанного типа
+func (r *ProjectMatrixRoom) HasNotificationType(notificationType NotificationType) bool {
+	for _, t := range r.NotificationTypes {
+		if t == notificationType {
+			return true
+		}
+	}
+	return false
+}
+
+// ProjectMatrixRoomRequest представляет данные для настройки комнаты Matrix проекта.
+// Room задается ID комнаты (!abc:example.org) или псевдонимом (#team:example.org); пустое значение оставляет текущую комнату
+type ProjectMatrixRoomRequest struct {
+	Room              string             `json:"room,omitempty" validate:"omitempty,max=255"`
+	Enabled           *bool              `json:"enabled,omitempty"`
+	NotificationTypes []NotificationType `json:"notification_types" validate:"required,min=1,dive,oneof=task_assigned task_updated task_commented task_due_soon task_overdue project_member_added project_updated"`
+}
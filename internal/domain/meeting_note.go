@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"time"
+)
+
+// MeetingNote представляет заметку встречи проекта в формате Markdown.
+// Пункты чек-листа "- [ ] ..." считаются поручениями и могут быть превращены в задачи
+type MeetingNote struct {
+	ID        string    `json:"id" db:"id"`
+	ProjectID string    `json:"project_id" db:"project_id"`
+	Title     string    `json:"title" db:"title"`
+	HeldAt    time.Time `json:"held_at" db:"held_at"`
+	Content   string    `json:"content" db:"content"`
+	CreatedBy string    `json:"created_by" db:"created_by"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// MeetingNoteCreateRequest представляет данные для создания заметки встречи
+type MeetingNoteCreateRequest struct {
+	Title   string     `json:"title" validate:"required,min=3,max=200"`
+	HeldAt  *time.Time `json:"held_at,omitempty"` // По умолчанию - момент создания
+	Content string     `json:"content" validate:"max=100000"`
+}
+
+// MeetingNoteUpdateRequest представляет данные для обновления заметки встречи
+type MeetingNoteUpdateRequest struct {
+	Title   *string    `json:"title,omitempty" validate:"omitempty,min=3,max=200"`
+	HeldAt  *time.Time `json:"held_at,omitempty"`
+	Content *string    `json:"content,omitempty" validate:"omitempty,max=100000"`
+}
+
+// MeetingNoteTask представляет задачу, созданную из пункта заметки
+type MeetingNoteTask struct {
+	NoteID    string    `json:"note_id" db:"note_id"`
+	TaskID    string    `json:"task_id" db:"task_id"`
+	ItemText  string    `json:"item_text" db:"item_text"`
+	CreatedBy string    `json:"created_by" db:"created_by"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// MeetingActionItem представляет поручение, разобранное из пункта чек-листа заметки.
+// Исполнитель указывается как @email или @имя-до-собаки, срок - как due:2026-01-31 или due:31.01.2026
+type MeetingActionItem struct {
+	Line       int        `json:"line"`                  // Номер строки в заметке, начиная с 1
+	Text       string     `json:"text"`                  // Исходный текст пункта
+	Title      string     `json:"title"`                 // Название будущей задачи
+	Assignee   string     `json:"assignee,omitempty"`    // Упоминание исполнителя из текста
+	AssigneeID *string    `json:"assignee_id,omitempty"` // Найденный участник проекта
+	DueDate    *time.Time `json:"due_date,omitempty"`
+	TaskID     *string    `json:"task_id,omitempty"` // Задача, уже созданная из пункта
+	Warnings   []string   `json:"warnings,omitempty"`
+}
+
+// MeetingActionItemsConvertRequest представляет запрос на создание задач из поручений.
+// Если строки не указаны, создаются задачи для всех еще не перенесенных поручений
+type MeetingActionItemsConvertRequest struct {
+	Lines    []int        `json:"lines,omitempty" validate:"omitempty,max=100,dive,min=1"`
+	Priority TaskPriority `json:"priority,omitempty" validate:"omitempty,task_priority"`
+}
+
+// MeetingActionItemResult представляет результат создания задачи из поручения
+type MeetingActionItemResult struct {
+	Line  int           `json:"line"`
+	Title string        `json:"title"`
+	Task  *TaskResponse `json:"task,omitempty"`
+	Error string        `json:"error,omitempty"`
+}
+
+// MeetingActionItemsConvertResult представляет итог пакетного создания задач из заметки
+type MeetingActionItemsConvertResult struct {
+	Created int                        `json:"created"`
+	Failed  int                        `json:"failed"`
+	Items   []*MeetingActionItemResult `json:"items"`
+}
@@ -119,6 +119,25 @@ type NotificationFilterOptions struct {
 	PageSize   int                `json:"page_size"`
 }
 
+// NotificationSweepRequest задает уведомления, которые нужно отметить прочитанными.
+// Условия объединяются через И; хотя бы одно условие обязательно, для всех уведомлений есть read-all
+type NotificationSweepRequest struct {
+	Before     *time.Time         `json:"before,omitempty"` // Созданные не позже этого момента
+	Types      []NotificationType `json:"types,omitempty" validate:"omitempty,max=20,dive,oneof=task_assigned task_updated task_commented task_due_soon task_overdue project_member_added project_updated digest"`
+	EntityType *string            `json:"entity_type,omitempty" validate:"omitempty,oneof=task project comment user"`
+	EntityID   *string            `json:"entity_id,omitempty" validate:"omitempty,uuid"`
+}
+
+// IsEmpty сообщает, что в запросе не задано ни одного условия
+func (r NotificationSweepRequest) IsEmpty() bool {
+	return r.Before == nil && len(r.Types) == 0 && r.EntityType == nil && r.EntityID == nil
+}
+
+// NotificationSweepResult содержит число уведомлений, отмеченных прочитанными
+type NotificationSweepResult struct {
+	Count int `json:"count"`
+}
+
 // NotificationEvent представляет событие для генерации уведомления
 type NotificationEvent struct {
 	Type       NotificationType  `json:"type"`
@@ -129,4 +148,21 @@ type NotificationEvent struct {
 	EntityType string            `json:"entity_type"`
 	MetaData   map[string]string `json:"meta_data,omitempty"`
 	CreatedAt  time.Time         `json:"created_at"`
-}
\ No newline at end of file
+}
+// DigestCheckpoint представляет состояние рассылки ежедневных дайджестов. Сохраняется
+// после каждой страницы пользователей, чтобы прерванная рассылка продолжилась с места остановки
+type DigestCheckpoint struct {
+	RunDate     string    `json:"run_date"`      // Дата запуска рассылки в формате 2006-01-02
+	StartedAt   time.Time `json:"started_at"`    // Момент запуска, от которого считается период задач дайджеста
+	AfterUserID string    `json:"after_user_id"` // ID последнего обработанного пользователя
+	Processed   int       `json:"processed"`
+	Sent        int       `json:"sent"`
+	Failed      int       `json:"failed"`
+}
+
+// UnreadCountEvent сообщает об изменении количества непрочитанных уведомлений пользователя.
+// Публикуется в Redis при каждом изменении счетчика и доставляется открытым потокам клиентов
+type UnreadCountEvent struct {
+	UserID string `json:"user_id"`
+	Count  int    `json:"count"`
+}
@@ -0,0 +1,106 @@
+package domain
+
+import (
+	"time"
+)
+
+// OKRStatus определяет оценку хода выполнения цели относительно прошедшей части квартала
+type OKRStatus string
+
+const (
+	// OKRStatusOnTrack - прогресс не отстает от прошедшей части квартала
+	OKRStatusOnTrack OKRStatus = "on_track"
+	// OKRStatusAtRisk - прогресс отстает, но цель еще достижима
+	OKRStatusAtRisk OKRStatus = "at_risk"
+	// OKRStatusOffTrack - прогресс значительно отстает
+	OKRStatusOffTrack OKRStatus = "off_track"
+)
+
+// Objective представляет цель на квартал
+type Objective struct {
+	ID          string       `json:"id" db:"id"`
+	Title       string       `json:"title" db:"title"`
+	Description string       `json:"description" db:"description"`
+	OwnerID     string       `json:"owner_id" db:"owner_id"`
+	Year        int          `json:"year" db:"year"`
+	Quarter     int          `json:"quarter" db:"quarter"`
+	CreatedBy   string       `json:"created_by" db:"created_by"`
+	CreatedAt   time.Time    `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at" db:"updated_at"`
+	Progress    float64      `json:"progress" db:"-"` // Среднее по ключевым результатам, в процентах
+	Status      OKRStatus    `json:"status,omitempty" db:"-"`
+	KeyResults  []*KeyResult `json:"key_results" db:"-"`
+}
+
+// KeyResult представляет ключевой результат цели.
+// Прогресс вычисляется по завершенным задачам связанных проектов и эпиков
+type KeyResult struct {
+	ID             string           `json:"id" db:"id"`
+	ObjectiveID    string           `json:"objective_id" db:"objective_id"`
+	Title          string           `json:"title" db:"title"`
+	CreatedAt      time.Time        `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time        `json:"updated_at" db:"updated_at"`
+	TotalTasks     int              `json:"total_tasks" db:"-"`
+	CompletedTasks int              `json:"completed_tasks" db:"-"`
+	Progress       float64          `json:"progress" db:"-"`
+	Links          []*KeyResultLink `json:"links" db:"-"`
+}
+
+// KeyResultLink представляет связь ключевого результата с проектом или эпиком
+type KeyResultLink struct {
+	ID          string    `json:"id" db:"id"`
+	KeyResultID string    `json:"key_result_id" db:"key_result_id"`
+	ProjectID   *string   `json:"project_id,omitempty" db:"project_id"`
+	EpicID      *string   `json:"epic_id,omitempty" db:"epic_id"`
+	CreatedBy   string    `json:"created_by" db:"created_by"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+// KeyResultTaskCount содержит число задач, учитываемых в ключевом результате
+type KeyResultTaskCount struct {
+	KeyResultID    string `db:"key_result_id"`
+	TotalTasks     int    `db:"total_tasks"`
+	CompletedTasks int    `db:"completed_tasks"`
+}
+
+// ObjectiveCreateRequest представляет данные для создания цели
+type ObjectiveCreateRequest struct {
+	Title       string  `json:"title" validate:"required,min=3,max=200"`
+	Description string  `json:"description" validate:"max=5000"`
+	OwnerID     *string `json:"owner_id,omitempty" validate:"omitempty,uuid"` // По умолчанию - автор цели
+	Year        int     `json:"year" validate:"required,min=2000,max=2100"`
+	Quarter     int     `json:"quarter" validate:"required,min=1,max=4"`
+}
+
+// ObjectiveUpdateRequest представляет данные для обновления цели
+type ObjectiveUpdateRequest struct {
+	Title       *string `json:"title,omitempty" validate:"omitempty,min=3,max=200"`
+	Description *string `json:"description,omitempty" validate:"omitempty,max=5000"`
+	OwnerID     *string `json:"owner_id,omitempty" validate:"omitempty,uuid"`
+	Year        *int    `json:"year,omitempty" validate:"omitempty,min=2000,max=2100"`
+	Quarter     *int    `json:"quarter,omitempty" validate:"omitempty,min=1,max=4"`
+}
+
+// KeyResultRequest представляет данные для создания или обновления ключевого результата
+type KeyResultRequest struct {
+	Title string `json:"title" validate:"required,min=3,max=200"`
+}
+
+// KeyResultLinkRequest представляет запрос на связь ключевого результата с проектом или эпиком
+type KeyResultLinkRequest struct {
+	ProjectID *string `json:"project_id,omitempty" validate:"omitempty,uuid,excluded_with=EpicID"`
+	EpicID    *string `json:"epic_id,omitempty" validate:"omitempty,uuid"`
+}
+
+// OKRReport представляет квартальный отчет по целям
+type OKRReport struct {
+	Year            int          `json:"year"`
+	Quarter         int          `json:"quarter"`
+	ElapsedPercent  float64      `json:"elapsed_percent"` // Прошедшая часть квартала
+	AverageProgress float64      `json:"average_progress"`
+	OnTrack         int          `json:"on_track"`
+	AtRisk          int          `json:"at_risk"`
+	OffTrack        int          `json:"off_track"`
+	Objectives      []*Objective `json:"objectives"`
+	GeneratedAt     time.Time    `json:"generated_at"`
+}
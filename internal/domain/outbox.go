@@ -0,0 +1,18 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// OutboxMessage представляет событие, записанное в таблицу исходящих событий
+// и ожидающее публикации в Kafka
+type OutboxMessage struct {
+	ID        int64           `json:"id" db:"id"`
+	Topic     string          `json:"topic" db:"topic"`
+	Key       string          `json:"key" db:"message_key"`
+	Payload   json.RawMessage `json:"payload" db:"payload"`
+	Attempts  int             `json:"attempts" db:"attempts"`
+	LastError *string         `json:"last_error,omitempty" db:"last_error"`
+	CreatedAt time.Time       `json:"created_at" db:"created_at"`
+}
@@ -2,9 +2,60 @@ package domain
 
 // PagedResponse представляет ответ с пагинацией для API
 type PagedResponse struct {
-	Items      interface{} `json:"items"`           // Элементы на текущей странице
-	TotalItems int         `json:"total_items"`     // Общее количество элементов
-	Page       int         `json:"page"`            // Текущая страница
-	PageSize   int         `json:"page_size"`       // Размер страницы
-	TotalPages int         `json:"total_pages"`     // Общее количество страниц
-}
\ No newline at end of file
+	Items      interface{} `json:"items"`                 // Элементы на текущей странице
+	TotalItems *int        `json:"total_items,omitempty"` // Общее количество элементов, если подсчет не отключен
+	Page       int         `json:"page"`                  // Текущая страница
+	PageSize   int         `json:"page_size"`             // Размер страницы
+	TotalPages *int        `json:"total_pages,omitempty"` // Общее количество страниц, если подсчет не отключен
+	HasMore    bool        `json:"has_more"`              // Есть ли следующая страница
+}
+
+// PageRequest описывает запрошенную страницу списка
+type PageRequest struct {
+	Page     int
+	PageSize int
+	// SkipCount отключает подсчет общего количества элементов: на больших выборках
+	// он дороже самой страницы. Вместо итогов ответ сообщает только о следующей странице
+	SkipCount bool
+}
+
+// Offset возвращает смещение первого элемента страницы
+func (p PageRequest) Offset() int {
+	return (p.Page - 1) * p.PageSize
+}
+
+// Limit возвращает число элементов, запрашиваемых из хранилища. Без подсчета
+// запрашивается на один элемент больше, чтобы узнать, есть ли следующая страница
+func (p PageRequest) Limit() int {
+	if p.SkipCount {
+		return p.PageSize + 1
+	}
+	return p.PageSize
+}
+
+// PageItems обрезает элементы, запрошенные через Limit, до размера страницы
+// и сообщает, есть ли следующая страница
+func PageItems[T any](items []T, page PageRequest) ([]T, bool) {
+	if len(items) > page.PageSize {
+		return items[:page.PageSize], true
+	}
+	return items, false
+}
+
+// NewPagedResponse формирует ответ с пагинацией. Если подсчет не отключен, итоги и
+// признак следующей страницы вычисляются по total, иначе используется hasMore
+func NewPagedResponse(items interface{}, page PageRequest, total int, hasMore bool) *PagedResponse {
+	response := &PagedResponse{
+		Items:    items,
+		Page:     page.Page,
+		PageSize: page.PageSize,
+		HasMore:  hasMore,
+	}
+	if !page.SkipCount {
+		totalPages := (total + page.PageSize - 1) / page.PageSize
+		response.TotalItems = &total
+		response.TotalPages = &totalPages
+		response.HasMore = page.Page < totalPages
+	}
+	return response
+}
@@ -54,13 +54,90 @@ type ProjectMember struct {
 	InvitedBy  string      `json:"invited_by" db:"invited_by"`
 }
 
+// MembershipAction определяет тип изменения состава участников проекта
+type MembershipAction string
+
+const (
+	// MembershipActionAdded - участник добавлен в проект
+	MembershipActionAdded MembershipAction = "added"
+	// MembershipActionRemoved - участник удален из проекта
+	MembershipActionRemoved MembershipAction = "removed"
+	// MembershipActionRoleChanged - изменена роль участника
+	MembershipActionRoleChanged MembershipAction = "role_changed"
+)
+
+// MembershipHistory представляет запись об изменении состава участников проекта
+type MembershipHistory struct {
+	ID        string           `json:"id" db:"id"`
+	ProjectID string           `json:"project_id" db:"project_id"`
+	UserID    string           `json:"user_id" db:"user_id"`
+	ActorID   string           `json:"actor_id" db:"actor_id"`
+	Action    MembershipAction `json:"action" db:"action"`
+	OldRole   *ProjectRole     `json:"old_role,omitempty" db:"old_role"`
+	NewRole   *ProjectRole     `json:"new_role,omitempty" db:"new_role"`
+	ChangedAt time.Time        `json:"changed_at" db:"changed_at"`
+}
+
+// MembershipHistoryResponse представляет запись истории участников для API-ответов
+type MembershipHistoryResponse struct {
+	ID        string           `json:"id"`
+	User      UserBrief        `json:"user"`
+	Actor     UserBrief        `json:"actor"`
+	Action    MembershipAction `json:"action"`
+	OldRole   *ProjectRole     `json:"old_role,omitempty"`
+	NewRole   *ProjectRole     `json:"new_role,omitempty"`
+	ChangedAt time.Time        `json:"changed_at"`
+}
+
+// OwnershipTransferStatus определяет статус запроса на передачу владения проектом
+type OwnershipTransferStatus string
+
+const (
+	// OwnershipTransferStatusPending - ожидает подтверждения новым владельцем
+	OwnershipTransferStatusPending OwnershipTransferStatus = "pending"
+	// OwnershipTransferStatusAccepted - принят новым владельцем
+	OwnershipTransferStatusAccepted OwnershipTransferStatus = "accepted"
+	// OwnershipTransferStatusDeclined - отклонен новым владельцем
+	OwnershipTransferStatusDeclined OwnershipTransferStatus = "declined"
+	// OwnershipTransferStatusCancelled - отменен текущим владельцем
+	OwnershipTransferStatusCancelled OwnershipTransferStatus = "cancelled"
+	// OwnershipTransferStatusExpired - истек срок подтверждения
+	OwnershipTransferStatusExpired OwnershipTransferStatus = "expired"
+)
+
+// OwnershipTransferTTL определяет срок, в течение которого новый владелец должен принять проект
+const OwnershipTransferTTL = 72 * time.Hour
+
+// OwnershipTransfer представляет запрос на передачу владения проектом
+type OwnershipTransfer struct {
+	ID         string                  `json:"id" db:"id"`
+	ProjectID  string                  `json:"project_id" db:"project_id"`
+	FromUserID string                  `json:"from_user_id" db:"from_user_id"`
+	ToUserID   string                  `json:"to_user_id" db:"to_user_id"`
+	Status     OwnershipTransferStatus `json:"status" db:"status"`
+	CreatedAt  time.Time               `json:"created_at" db:"created_at"`
+	ExpiresAt  time.Time               `json:"expires_at" db:"expires_at"`
+	ResolvedAt *time.Time              `json:"resolved_at,omitempty" db:"resolved_at"`
+	ResolvedBy *string                 `json:"resolved_by,omitempty" db:"resolved_by"`
+}
+
+// IsExpired проверяет, истек ли срок подтверждения передачи владения
+func (t *OwnershipTransfer) IsExpired() bool {
+	return time.Now().After(t.ExpiresAt)
+}
+
+// OwnershipTransferRequest представляет запрос на передачу владения проектом
+type OwnershipTransferRequest struct {
+	NewOwnerID string `json:"new_owner_id" validate:"required,uuid"`
+}
+
 // ProjectCreateRequest представляет данные для создания проекта
 type ProjectCreateRequest struct {
 	Name        string        `json:"name" validate:"required,min=3,max=100"`
 	Description string        `json:"description" validate:"required"`
 	Status      ProjectStatus `json:"status" validate:"required,oneof=active on_hold completed archived"`
 	StartDate   *time.Time    `json:"start_date,omitempty"`
-	EndDate     *time.Time    `json:"end_date,omitempty" validate:"omitempty,gtfield=StartDate"`
+	EndDate     *time.Time    `json:"end_date,omitempty" validate:"omitempty,after_field=StartDate"`
 }
 
 // ProjectUpdateRequest представляет данные для обновления проекта
@@ -69,7 +146,58 @@ type ProjectUpdateRequest struct {
 	Description *string        `json:"description,omitempty"`
 	Status      *ProjectStatus `json:"status,omitempty" validate:"omitempty,oneof=active on_hold completed archived"`
 	StartDate   *time.Time     `json:"start_date,omitempty"`
-	EndDate     *time.Time     `json:"end_date,omitempty" validate:"omitempty,gtfield=StartDate"`
+	EndDate     *time.Time     `json:"end_date,omitempty" validate:"omitempty,after_field=StartDate"`
+}
+
+// ProjectRestoreRequest представляет данные для восстановления проекта из архива
+type ProjectRestoreRequest struct {
+	Status *ProjectStatus `json:"status,omitempty" validate:"omitempty,oneof=active on_hold completed"`
+}
+
+// TaskField определяет поле задачи, которое может быть обязательным по настройкам проекта
+type TaskField string
+
+const (
+	// TaskFieldAssignee - исполнитель задачи
+	TaskFieldAssignee TaskField = "assignee"
+	// TaskFieldDueDate - срок выполнения задачи
+	TaskFieldDueDate TaskField = "due_date"
+	// TaskFieldEstimate - оценка трудозатрат
+	TaskFieldEstimate TaskField = "estimated_hours"
+	// TaskFieldTags - метки задачи
+	TaskFieldTags TaskField = "tags"
+)
+
+// ProjectTaskSettings представляет настройки задач проекта по умолчанию.
+// TransitionRules задает поля, обязательные для перевода задачи в указанный статус
+type ProjectTaskSettings struct {
+	ProjectID            string                     `json:"project_id" db:"project_id"`
+	DefaultAssigneeID    *string                    `json:"default_assignee_id,omitempty" db:"default_assignee_id"`
+	DefaultPriority      *TaskPriority              `json:"default_priority,omitempty" db:"default_priority"`
+	DefaultDueOffsetDays *int                       `json:"default_due_offset_days,omitempty" db:"default_due_offset_days"`
+	RequiredFields       []TaskField                `json:"required_fields" db:"-"`
+	TransitionRules      map[TaskStatus][]TaskField `json:"transition_rules" db:"-"`
+	AutoLabelStale       bool                       `json:"auto_label_stale" db:"auto_label_stale"` // Помечать устаревшие задачи бэклога
+	AssigneeOpenTaskLimit int                       `json:"assignee_open_task_limit" db:"assignee_open_task_limit"` // Порог предупреждения о загрузке исполнителя; 0 - без проверки
+	UpdatedBy            *string                    `json:"updated_by,omitempty" db:"updated_by"`
+	UpdatedAt            time.Time                  `json:"updated_at" db:"updated_at"`
+}
+
+// DefaultAssigneeOpenTaskLimit - порог предупреждения о загрузке исполнителя для проектов
+// без собственных настроек задач
+const DefaultAssigneeOpenTaskLimit = 10
+
+// ProjectTaskSettingsRequest представляет данные для изменения настроек задач проекта.
+// Пустая строка в default_assignee_id и default_priority, а также 0 в default_due_offset_days
+// сбрасывают соответствующее значение
+type ProjectTaskSettingsRequest struct {
+	DefaultAssigneeID    *string                     `json:"default_assignee_id,omitempty" validate:"omitempty,uuid"`
+	DefaultPriority      *string                     `json:"default_priority,omitempty" validate:"omitempty,task_priority"`
+	DefaultDueOffsetDays *int                        `json:"default_due_offset_days,omitempty" validate:"omitempty,gte=0,lte=365"`
+	RequiredFields       *[]TaskField                `json:"required_fields,omitempty" validate:"omitempty,dive,oneof=assignee due_date estimated_hours tags"`
+	TransitionRules      *map[TaskStatus][]TaskField `json:"transition_rules,omitempty" validate:"omitempty,dive,keys,task_status,endkeys,dive,oneof=assignee due_date estimated_hours tags"`
+	AutoLabelStale       *bool                       `json:"auto_label_stale,omitempty"`
+	AssigneeOpenTaskLimit *int                       `json:"assignee_open_task_limit,omitempty" validate:"omitempty,gte=0,lte=1000"`
 }
 
 // ProjectResponse представляет данные проекта для API-ответов
@@ -104,6 +232,7 @@ type ProjectMetrics struct {
 	OverdueTasks   int            `json:"overdue_tasks"`
 	TasksByStatus  map[string]int `json:"tasks_by_status"`
 	TasksByUser    map[string]int `json:"tasks_by_user,omitempty"`
+	RefreshedAt    *time.Time     `json:"refreshed_at,omitempty"` // Время последнего пересчета
 }
 
 // AddMemberRequest представляет запрос на добавление участника в проект
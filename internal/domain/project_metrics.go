@@ -0,0 +1,13 @@
+package domain
+
+import "time"
+
+// ProjectMetricsSnapshot представляет ежедневный снимок метрик проекта для графиков динамики
+type ProjectMetricsSnapshot struct {
+	ProjectID      string         `json:"project_id"`
+	SnapshotDate   time.Time      `json:"snapshot_date"`
+	TaskCount      int            `json:"task_count"`
+	CompletedTasks int            `json:"completed_tasks"`
+	OverdueTasks   int            `json:"overdue_tasks"`
+	TasksByStatus  map[string]int `json:"tasks_by_status"`
+}
@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"time"
+)
+
+// MaxRosterSize - максимальное число участников в одном запросе массового добавления
+// или в одном импортируемом CSV-файле
+const MaxRosterSize = 500
+
+// ProjectInvitation представляет приглашение в проект для email, у которого еще нет учетной записи
+type ProjectInvitation struct {
+	ID         string      `json:"id" db:"id"`
+	ProjectID  string      `json:"project_id" db:"project_id"`
+	Email      string      `json:"email" db:"email"`
+	Role       ProjectRole `json:"role" db:"role"`
+	TokenHash  string      `json:"-" db:"token_hash"`
+	InvitedBy  string      `json:"invited_by" db:"invited_by"`
+	CreatedAt  time.Time   `json:"created_at" db:"created_at"`
+	ExpiresAt  time.Time   `json:"expires_at" db:"expires_at"`
+	AcceptedAt *time.Time  `json:"accepted_at,omitempty" db:"accepted_at"`
+}
+
+// IsExpired проверяет, истек ли срок действия приглашения
+func (i *ProjectInvitation) IsExpired(now time.Time) bool {
+	return now.After(i.ExpiresAt)
+}
+
+// RosterEntry описывает одного участника в массовом добавлении. Владелец проекта
+// назначается только передачей владения, поэтому роль owner здесь недоступна
+type RosterEntry struct {
+	Email string      `json:"email" validate:"required,email,max=255"`
+	Role  ProjectRole `json:"role" validate:"required,oneof=manager member viewer"`
+}
+
+// BulkAddMembersRequest представляет запрос на массовое добавление участников проекта
+type BulkAddMembersRequest struct {
+	Members []RosterEntry `json:"members" validate:"required,min=1,max=500"`
+}
+
+// RosterEntryStatus определяет результат обработки участника из списка
+type RosterEntryStatus string
+
+const (
+	// RosterEntryAdded - пользователь найден и добавлен в проект
+	RosterEntryAdded RosterEntryStatus = "added"
+	// RosterEntryInvited - пользователь не найден, на email отправлено приглашение
+	RosterEntryInvited RosterEntryStatus = "invited"
+)
+
+// RosterEntryResult представляет результат обработки участника из списка
+type RosterEntryResult struct {
+	Email      string                 `json:"email"`
+	Status     RosterEntryStatus      `json:"status"`
+	Member     *ProjectMemberResponse `json:"member,omitempty"`
+	Invitation *ProjectInvitation     `json:"invitation,omitempty"`
+}
@@ -0,0 +1,29 @@
+package domain
+
+// ProjectSplitRequest представляет запрос на выделение части задач проекта в новый проект.
+// Задачи отбираются по тегу или по эпику - указывается ровно одно из полей
+type ProjectSplitRequest struct {
+	Name        string  `json:"name" validate:"required,min=3,max=100"`
+	Description string  `json:"description" validate:"required"`
+	Tag         *string `json:"tag,omitempty" validate:"omitempty,min=1,max=50"`
+	EpicID      *string `json:"epic_id,omitempty" validate:"omitempty,uuid"`
+	DryRun      bool    `json:"dry_run"` // Только показать, что будет перенесено
+}
+
+// ProjectSplitTask описывает задачу, переносимую в новый проект
+type ProjectSplitTask struct {
+	ID         string     `json:"id"`
+	Title      string     `json:"title"`
+	Status     TaskStatus `json:"status"`
+	LeavesEpic bool       `json:"leaves_epic"` // Задача будет исключена из эпика исходного проекта
+}
+
+// ProjectSplitResult представляет результат (или предварительный просмотр) разделения проекта
+type ProjectSplitResult struct {
+	DryRun      bool               `json:"dry_run"`
+	Project     *ProjectResponse   `json:"project,omitempty"` // Созданный проект; не заполняется при dry_run
+	EpicID      *string            `json:"epic_id,omitempty"` // Эпик, переносимый вместе с задачами
+	MemberCount int                `json:"member_count"`      // Участники, копируемые в новый проект
+	TaskCount   int                `json:"task_count"`
+	Tasks       []ProjectSplitTask `json:"tasks"`
+}
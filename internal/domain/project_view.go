@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"time"
+)
+
+// ProjectViewLayout определяет способ отображения задач в представлении
+type ProjectViewLayout string
+
+const (
+	// ProjectViewLayoutBoard - доска с колонками
+	ProjectViewLayoutBoard ProjectViewLayout = "board"
+	// ProjectViewLayoutList - список задач
+	ProjectViewLayoutList ProjectViewLayout = "list"
+)
+
+// ProjectView представляет сохраненное представление задач проекта: фильтры, сортировку и группировку.
+// Представление, назначенное роли по умолчанию, открывается участникам с этой ролью при первом входе в проект
+type ProjectView struct {
+	ID           string             `json:"id" db:"id"`
+	ProjectID    string             `json:"project_id" db:"project_id"`
+	Name         string             `json:"name" db:"name"`
+	Layout       ProjectViewLayout  `json:"layout" db:"layout"`
+	Filters      ProjectViewFilters `json:"filters" db:"-"`
+	SortBy       string             `json:"sort_by" db:"sort_by"`
+	SortDir      string             `json:"sort_dir" db:"sort_dir"`
+	GroupBy      string             `json:"group_by" db:"group_by"`
+	DefaultRoles []ProjectRole      `json:"default_roles" db:"-"`
+	CreatedBy    string             `json:"created_by" db:"created_by"`
+	CreatedAt    time.Time          `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time          `json:"updated_at" db:"updated_at"`
+}
+
+// ProjectViewFilters содержит фильтры задач представления. Пустой фильтр не ограничивает выборку
+type ProjectViewFilters struct {
+	Statuses    []TaskStatus   `json:"statuses,omitempty" validate:"omitempty,dive,task_status"`
+	Priorities  []TaskPriority `json:"priorities,omitempty" validate:"omitempty,dive,task_priority"`
+	AssigneeIDs []string       `json:"assignee_ids,omitempty" validate:"omitempty,max=50,dive,uuid"`
+	Tags        []string       `json:"tags,omitempty" validate:"omitempty,max=20,dive,min=1,max=50"`
+	EpicID      *string        `json:"epic_id,omitempty" validate:"omitempty,uuid"`
+	OverdueOnly bool           `json:"overdue_only,omitempty"`
+	When        []string       `json:"when,omitempty" validate:"omitempty,max=10,dive,date_filter"` // Условия по датам, например due:<7d
+}
+
+// ProjectViewRequest представляет данные для создания или замены представления проекта
+type ProjectViewRequest struct {
+	Name         string             `json:"name" validate:"required,min=1,max=100"`
+	Layout       ProjectViewLayout  `json:"layout" validate:"required,oneof=board list"`
+	Filters      ProjectViewFilters `json:"filters"`
+	SortBy       string             `json:"sort_by,omitempty" validate:"omitempty,oneof=rank priority_score priority due_date created_at updated_at title"`
+	SortDir      string             `json:"sort_dir,omitempty" validate:"omitempty,oneof=asc desc"`
+	GroupBy      string             `json:"group_by,omitempty" validate:"omitempty,oneof=none status priority assignee epic"`
+	DefaultRoles []ProjectRole      `json:"default_roles,omitempty" validate:"omitempty,dive,oneof=owner manager member viewer"`
+}
+
+// DefaultProjectView возвращает встроенное представление, которое открывается,
+// если менеджеры проекта не назначили представление роли пользователя
+func DefaultProjectView(projectID string) *ProjectView {
+	return &ProjectView{
+		ProjectID:    projectID,
+		Name:         "All tasks",
+		Layout:       ProjectViewLayoutBoard,
+		SortBy:       "rank",
+		SortDir:      "asc",
+		GroupBy:      "status",
+		DefaultRoles: []ProjectRole{},
+	}
+}
@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"time"
+)
+
+// ProjectWebhook представляет входящий вебхук проекта.
+// JSON-запрос на подписанный URL вебхука создает задачу по правилам сопоставления от имени создателя вебхука
+type ProjectWebhook struct {
+	ID        string         `json:"id" db:"id"`
+	ProjectID string         `json:"project_id" db:"project_id"`
+	Name      string         `json:"name" db:"name"`
+	Secret    string         `json:"secret" db:"secret"` // Ключ подписи, виден только менеджерам проекта
+	URL       string         `json:"url" db:"-"`         // Подписанный URL для систем, которые не умеют подписывать тело запроса
+	Mapping   WebhookMapping `json:"mapping" db:"-"`
+	Enabled   bool           `json:"enabled" db:"enabled"`
+	CreatedBy string         `json:"created_by" db:"created_by"`
+	CreatedAt time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at" db:"updated_at"`
+}
+
+// WebhookMapping описывает, как из JSON-запроса получить поля задачи.
+// Текстовые поля являются шаблонами с путями JSONPath: "[{{ $.labels.severity }}] {{ $.alerts[0].annotations.summary }}"
+type WebhookMapping struct {
+	Title       string                  `json:"title" validate:"required,min=1,max=1000"`
+	Description string                  `json:"description,omitempty" validate:"max=10000"` // Пустое описание - исходный JSON запроса
+	Priority    string                  `json:"priority,omitempty" validate:"max=1000"`
+	PriorityMap map[string]TaskPriority `json:"priority_map,omitempty" validate:"omitempty,dive,keys,min=1,max=100,endkeys,task_priority"` // Значение шаблона priority -> приоритет задачи
+	AssigneeID  *string                 `json:"assignee_id,omitempty" validate:"omitempty,uuid"`
+	Tags        []string                `json:"tags,omitempty" validate:"omitempty,max=20,dive,min=1,max=1000"`
+	DueInHours  *int                    `json:"due_in_hours,omitempty" validate:"omitempty,min=1,max=8760"`
+}
+
+// ProjectWebhookRequest представляет данные для создания или замены входящего вебхука
+type ProjectWebhookRequest struct {
+	Name    string         `json:"name" validate:"required,min=1,max=100"`
+	Enabled *bool          `json:"enabled,omitempty"` // По умолчанию вебхук включен
+	Mapping WebhookMapping `json:"mapping"`
+}
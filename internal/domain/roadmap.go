@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"time"
+)
+
+// DefaultRoadmapLabel - тег, которым по умолчанию отмечаются задачи публичной дорожной карты
+const DefaultRoadmapLabel = "public-roadmap"
+
+// ProjectRoadmap представляет настройки публичной дорожной карты проекта
+type ProjectRoadmap struct {
+	ProjectID   string    `json:"project_id" db:"project_id"`
+	PublicKey   string    `json:"public_key" db:"public_key"`
+	URL         string    `json:"url" db:"-"`
+	Title       string    `json:"title" db:"title"`
+	Description string    `json:"description" db:"description"`
+	Label       string    `json:"label" db:"label"`
+	Enabled     bool      `json:"enabled" db:"enabled"`
+	CreatedBy   string    `json:"created_by" db:"created_by"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// ProjectRoadmapRequest представляет данные для настройки публичной дорожной карты
+type ProjectRoadmapRequest struct {
+	Title       string `json:"title" validate:"required,min=3,max=200"`
+	Description string `json:"description" validate:"max=2000"`
+	Label       string `json:"label" validate:"omitempty,min=1,max=50"`
+	Enabled     *bool  `json:"enabled,omitempty"`
+}
+
+// RoadmapStage определяет укрупненный статус элемента дорожной карты
+type RoadmapStage string
+
+const (
+	// RoadmapStagePlanned - работа запланирована
+	RoadmapStagePlanned RoadmapStage = "planned"
+	// RoadmapStageInProgress - работа ведется
+	RoadmapStageInProgress RoadmapStage = "in_progress"
+	// RoadmapStageShipped - работа завершена
+	RoadmapStageShipped RoadmapStage = "shipped"
+)
+
+// RoadmapTask представляет задачу проекта, отмеченную тегом дорожной карты
+type RoadmapTask struct {
+	Title       string     `db:"title"`
+	Status      TaskStatus `db:"status"`
+	DueDate     *time.Time `db:"due_date"`
+	CompletedAt *time.Time `db:"completed_at"`
+}
+
+// RoadmapItem представляет элемент публичной дорожной карты.
+// Содержит только укрупненные сведения: точные сроки и внутренние статусы не раскрываются
+type RoadmapItem struct {
+	Title  string       `json:"title"`
+	Stage  RoadmapStage `json:"stage"`
+	Target string       `json:"target,omitempty"` // Квартал, например "2026 Q3"
+}
+
+// PublicRoadmap представляет опубликованную дорожную карту
+type PublicRoadmap struct {
+	ProjectID   string         `json:"-"`
+	Title       string         `json:"title"`
+	Description string         `json:"description,omitempty"`
+	ProjectName string         `json:"project_name"`
+	Items       []*RoadmapItem `json:"items"`
+	GeneratedAt time.Time      `json:"generated_at"`
+}
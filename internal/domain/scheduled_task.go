@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"time"
+)
+
+// ScheduledTaskStatus определяет статус отложенного создания задачи
+type ScheduledTaskStatus string
+
+const (
+	// ScheduledTaskStatusPending - ожидает наступления времени создания
+	ScheduledTaskStatusPending ScheduledTaskStatus = "pending"
+	// ScheduledTaskStatusProcessing - задача создается планировщиком
+	ScheduledTaskStatusProcessing ScheduledTaskStatus = "processing"
+	// ScheduledTaskStatusCreated - задача создана
+	ScheduledTaskStatusCreated ScheduledTaskStatus = "created"
+	// ScheduledTaskStatusFailed - задачу не удалось создать, причина в поле Error
+	ScheduledTaskStatusFailed ScheduledTaskStatus = "failed"
+	// ScheduledTaskStatusCancelled - отменено пользователем
+	ScheduledTaskStatusCancelled ScheduledTaskStatus = "cancelled"
+)
+
+// ScheduledTask представляет задачу, которая будет создана в указанное время.
+// Задача создается от имени автора с теми же проверками, что и при обычном создании
+type ScheduledTask struct {
+	ID          string              `json:"id" db:"id"`
+	ProjectID   string              `json:"project_id" db:"project_id"`
+	Task        TaskCreateRequest   `json:"task" db:"-"`
+	CreateAt    time.Time           `json:"create_at" db:"create_at"`
+	Status      ScheduledTaskStatus `json:"status" db:"status"`
+	TaskID      *string             `json:"task_id,omitempty" db:"task_id"`
+	Error       *string             `json:"error,omitempty" db:"error"`
+	CreatedBy   string              `json:"created_by" db:"created_by"`
+	CreatedAt   time.Time           `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time           `json:"updated_at" db:"updated_at"`
+	ProcessedAt *time.Time          `json:"processed_at,omitempty" db:"processed_at"`
+}
+
+// ScheduledTaskRequest представляет данные для планирования создания задачи
+type ScheduledTaskRequest struct {
+	CreateAt time.Time         `json:"create_at" validate:"required"`
+	Task     TaskCreateRequest `json:"task"`
+}
@@ -0,0 +1,19 @@
+package domain
+
+// TypeaheadItem представляет элемент результата быстрого поиска
+type TypeaheadItem struct {
+	ID       string  `json:"id" db:"id"`
+	Title    string  `json:"title" db:"title"`
+	Subtitle *string `json:"subtitle,omitempty" db:"subtitle"`
+}
+
+// TypeaheadResult представляет результат быстрого поиска по задачам, проектам, пользователям,
+// решениям и вики-страницам
+type TypeaheadResult struct {
+	Query     string           `json:"query"`
+	Tasks     []*TypeaheadItem `json:"tasks"`
+	Projects  []*TypeaheadItem `json:"projects"`
+	Users     []*TypeaheadItem `json:"users"`
+	Decisions []*TypeaheadItem `json:"decisions"`
+	WikiPages []*TypeaheadItem `json:"wiki_pages"`
+}
@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"time"
+)
+
+// SMSSettings представляет телефон пользователя для SMS-уведомлений о критических событиях
+type SMSSettings struct {
+	UserID        string     `json:"user_id" db:"user_id"`
+	Phone         string     `json:"phone" db:"phone"`
+	VerifiedAt    *time.Time `json:"verified_at,omitempty" db:"verified_at"`
+	Enabled       bool       `json:"enabled" db:"enabled"`
+	CodeHash      string     `json:"-" db:"code_hash"`
+	CodeExpiresAt *time.Time `json:"-" db:"code_expires_at"`
+	CodeAttempts  int        `json:"-" db:"code_attempts"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// Verified сообщает, подтвержден ли телефон
+func (s *SMSSettings) Verified() bool {
+	return s.VerifiedAt != nil
+}
+
+// SMSStatus представляет состояние SMS-уведомлений пользователя
+type SMSStatus struct {
+	Available     bool       `json:"available"` // Настроен ли провайдер SMS
+	Phone         string     `json:"phone,omitempty"`
+	Verified      bool       `json:"verified"`
+	VerifiedAt    *time.Time `json:"verified_at,omitempty"`
+	Enabled       bool       `json:"enabled"`
+	SentThisMonth int        `json:"sent_this_month"`
+	MonthlyQuota  int        `json:"monthly_quota"`
+}
+
+// SMSPhoneRequest представляет данные для привязки телефона в формате E.164
+type SMSPhoneRequest struct {
+	Phone string `json:"phone" validate:"required,e164"`
+}
+
+// SMSVerifyRequest представляет код подтверждения телефона
+type SMSVerifyRequest struct {
+	Code string `json:"code" validate:"required,len=6,numeric"`
+}
+
+// SMSSettingsRequest представляет данные для включения и отключения SMS-уведомлений
+type SMSSettingsRequest struct {
+	Enabled *bool `json:"enabled" validate:"required"`
+}
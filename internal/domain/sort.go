@@ -0,0 +1,21 @@
+package domain
+
+// NullsOrder определяет положение пустых значений при сортировке
+type NullsOrder string
+
+const (
+	// NullsDefault - как принято в базе: в конце при сортировке по возрастанию и в начале по убыванию
+	NullsDefault NullsOrder = ""
+	// NullsFirst - пустые значения в начале
+	NullsFirst NullsOrder = "first"
+	// NullsLast - пустые значения в конце
+	NullsLast NullsOrder = "last"
+)
+
+// SortKey представляет одно поле сортировки списка. Поле проверяется по списку
+// допустимых полей в обработчике и еще раз в репозитории
+type SortKey struct {
+	Field string     `json:"field"`
+	Desc  bool       `json:"desc,omitempty"`
+	Nulls NullsOrder `json:"nulls,omitempty"`
+}
@@ -0,0 +1,102 @@
+package domain
+
+import (
+	"time"
+)
+
+// SprintStatus определяет состояние спринта
+type SprintStatus string
+
+const (
+	// SprintStatusPlanned - спринт запланирован и еще не начат
+	SprintStatusPlanned SprintStatus = "planned"
+	// SprintStatusActive - спринт идет; в проекте может быть только один активный спринт
+	SprintStatusActive SprintStatus = "active"
+	// SprintStatusClosed - спринт завершен
+	SprintStatusClosed SprintStatus = "closed"
+)
+
+// Sprint представляет спринт - итерацию проекта с целью и плановыми датами
+type Sprint struct {
+	ID        string          `json:"id" db:"id"`
+	ProjectID string          `json:"project_id" db:"project_id"`
+	Name      string          `json:"name" db:"name"`
+	Goal      string          `json:"goal" db:"goal"`
+	Status    SprintStatus    `json:"status" db:"status"`
+	StartDate time.Time       `json:"start_date" db:"start_date"`
+	EndDate   time.Time       `json:"end_date" db:"end_date"`
+	StartedAt *time.Time      `json:"started_at,omitempty" db:"started_at"`
+	ClosedAt  *time.Time      `json:"closed_at,omitempty" db:"closed_at"`
+	CreatedBy string          `json:"created_by" db:"created_by"`
+	CreatedAt time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at" db:"updated_at"`
+	Progress  *SprintProgress `json:"progress,omitempty" db:"-"`
+}
+
+// SprintProgress представляет сводный прогресс задач спринта.
+// Отмененные задачи не учитываются
+type SprintProgress struct {
+	SprintID       string  `json:"-" db:"sprint_id"`
+	TotalTasks     int     `json:"total_tasks" db:"total_tasks"`
+	CompletedTasks int     `json:"completed_tasks" db:"completed_tasks"`
+	EstimatedHours float64 `json:"estimated_hours" db:"estimated_hours"`
+	RemainingHours float64 `json:"remaining_hours" db:"remaining_hours"`
+}
+
+// SprintCreateRequest представляет данные для создания спринта
+type SprintCreateRequest struct {
+	Name      string    `json:"name" validate:"required,min=1,max=200"`
+	Goal      string    `json:"goal" validate:"max=5000"`
+	StartDate time.Time `json:"start_date" validate:"required"`
+	EndDate   time.Time `json:"end_date" validate:"required,gtfield=StartDate"`
+}
+
+// SprintUpdateRequest представляет данные для обновления спринта.
+// Даты завершенного спринта не меняются
+type SprintUpdateRequest struct {
+	Name      *string    `json:"name,omitempty" validate:"omitempty,min=1,max=200"`
+	Goal      *string    `json:"goal,omitempty" validate:"omitempty,max=5000"`
+	StartDate *time.Time `json:"start_date,omitempty"`
+	EndDate   *time.Time `json:"end_date,omitempty"`
+}
+
+// SprintTasksRequest представляет запрос на включение задач в спринт
+type SprintTasksRequest struct {
+	TaskIDs []string `json:"task_ids" validate:"required,min=1,max=500,uuid_list"`
+}
+
+// SprintCloseRequest представляет запрос на завершение спринта. Незавершенные задачи
+// переносятся в указанный спринт или, если он не указан, возвращаются в бэклог
+type SprintCloseRequest struct {
+	MoveToSprintID *string `json:"move_to_sprint_id,omitempty" validate:"omitempty,uuid"`
+}
+
+// SprintTaskChange представляет изменение задачи, влияющее на диаграмму сгорания:
+// смену статуса или включение в спринт и исключение из него
+type SprintTaskChange struct {
+	TaskID    string    `db:"task_id"`
+	Field     string    `db:"field"`
+	OldValue  *string   `db:"old_value"`
+	NewValue  *string   `db:"new_value"`
+	ChangedAt time.Time `db:"changed_at"`
+}
+
+// BurndownPoint представляет состояние спринта на конец дня
+type BurndownPoint struct {
+	Date           time.Time `json:"date"`
+	ScopeTasks     int       `json:"scope_tasks"`
+	RemainingTasks int       `json:"remaining_tasks"`
+	ScopeHours     float64   `json:"scope_hours"`
+	RemainingHours float64   `json:"remaining_hours"`
+	IdealTasks     float64   `json:"ideal_tasks"` // Идеальная линия от объема на начало спринта до нуля
+	IdealHours     float64   `json:"ideal_hours"`
+}
+
+// SprintBurndown представляет диаграмму сгорания спринта по дням.
+// Точки строятся от начала спринта до его окончания, но не позже текущего момента
+type SprintBurndown struct {
+	SprintID  string          `json:"sprint_id"`
+	StartDate time.Time       `json:"start_date"`
+	EndDate   time.Time       `json:"end_date"`
+	Points    []BurndownPoint `json:"points"`
+}
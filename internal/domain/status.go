@@ -0,0 +1,76 @@
+package domain
+
+import "time"
+
+// ComponentStatus определяет состояние компонента сервиса на странице статуса
+type ComponentStatus string
+
+const (
+	// ComponentStatusOperational - компонент работает
+	ComponentStatusOperational ComponentStatus = "operational"
+	// ComponentStatusDegraded - компонент работает с перебоями
+	ComponentStatusDegraded ComponentStatus = "degraded"
+	// ComponentStatusDown - компонент недоступен
+	ComponentStatusDown ComponentStatus = "down"
+)
+
+// StatusNoteSeverity определяет важность заметки об инциденте
+type StatusNoteSeverity string
+
+const (
+	// StatusNoteSeverityInfo - информационное сообщение (например, плановые работы)
+	StatusNoteSeverityInfo StatusNoteSeverity = "info"
+	// StatusNoteSeverityMinor - частичное нарушение работы
+	StatusNoteSeverityMinor StatusNoteSeverity = "minor"
+	// StatusNoteSeverityMajor - серьезное нарушение работы
+	StatusNoteSeverityMajor StatusNoteSeverity = "major"
+)
+
+// StatusComponent представляет результат последней проверки компонента сервиса
+type StatusComponent struct {
+	Name      string          `json:"name"`
+	Status    ComponentStatus `json:"status"`
+	LatencyMs int64           `json:"latency_ms"`
+	CheckedAt time.Time       `json:"checked_at"`
+}
+
+// StatusNote представляет заметку об инциденте, которую ведут администраторы
+type StatusNote struct {
+	ID         string             `json:"id" db:"id"`
+	Title      string             `json:"title" db:"title"`
+	Message    string             `json:"message" db:"message"`
+	Severity   StatusNoteSeverity `json:"severity" db:"severity"`
+	CreatedBy  *string            `json:"-" db:"created_by"`
+	CreatedAt  time.Time          `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time          `json:"updated_at" db:"updated_at"`
+	ResolvedAt *time.Time         `json:"resolved_at,omitempty" db:"resolved_at"`
+}
+
+// StatusNoteRequest представляет запрос на создание или изменение заметки об инциденте
+type StatusNoteRequest struct {
+	Title    string             `json:"title" validate:"required,min=3,max=200"`
+	Message  string             `json:"message" validate:"max=5000"`
+	Severity StatusNoteSeverity `json:"severity" validate:"required,oneof=info minor major"`
+	Resolved bool               `json:"resolved"`
+}
+
+// StatusUptimeDay содержит долю успешных проверок сервиса за день
+type StatusUptimeDay struct {
+	Date    string  `json:"date"`
+	Percent float64 `json:"percent"`
+}
+
+// StatusUptime содержит доступность API по результатам периодических проверок
+type StatusUptime struct {
+	Percent float64           `json:"percent"` // Доля успешных проверок за весь период
+	Days    []StatusUptimeDay `json:"days"`
+}
+
+// StatusPage представляет публичную страницу статуса сервиса
+type StatusPage struct {
+	Status      ComponentStatus   `json:"status"`
+	Components  []StatusComponent `json:"components"`
+	Notes       []*StatusNote     `json:"notes"`
+	Uptime      StatusUptime      `json:"uptime"`
+	GeneratedAt time.Time         `json:"generated_at"`
+}
@@ -0,0 +1,29 @@
+package domain
+
+// TagUsage содержит статистику использования тега в задачах проекта
+type TagUsage struct {
+	Tag           string `json:"tag" db:"tag"`
+	TaskCount     int    `json:"task_count" db:"task_count"`           // Все задачи с тегом
+	OpenTaskCount int    `json:"open_task_count" db:"open_task_count"` // Незавершенные и неотмененные задачи
+	Unused        bool   `json:"unused" db:"-"`                        // Тег остался только у закрытых задач
+}
+
+// TagRenameRequest представляет запрос на переименование тега во всех задачах проекта
+type TagRenameRequest struct {
+	Name string `json:"name" validate:"required,min=1,max=50"`
+}
+
+// TagMergeRequest представляет запрос на слияние тегов проекта.
+// Задачи с любым из тегов Sources получают тег Target, исходные теги удаляются
+type TagMergeRequest struct {
+	Sources []string `json:"sources" validate:"required,min=1,max=50,dive,min=1,max=50"`
+	Target  string   `json:"target" validate:"required,min=1,max=50"`
+}
+
+// TagOperationResult содержит результат массовой операции над тегом проекта
+type TagOperationResult struct {
+	ProjectID     string   `json:"project_id"`
+	Tags          []string `json:"tags"`             // Затронутые исходные теги
+	Target        string   `json:"target,omitempty"` // Новое имя тега, пусто при удалении
+	TasksAffected int      `json:"tasks_affected"`
+}
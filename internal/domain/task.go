@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"math"
 	"time"
 )
 
@@ -22,6 +23,9 @@ const (
 	TaskStatusCancelled TaskStatus = "cancelled"
 )
 
+// OpenTaskStatuses перечисляет статусы незавершенных задач
+var OpenTaskStatuses = []TaskStatus{TaskStatusNew, TaskStatusInProgress, TaskStatusOnHold, TaskStatusReview}
+
 // TaskPriority определяет приоритет задачи
 type TaskPriority string
 
@@ -52,7 +56,15 @@ type Task struct {
 	CreatedAt    time.Time    `json:"created_at" db:"created_at"`
 	UpdatedAt    time.Time    `json:"updated_at" db:"updated_at"`
 	CompletedAt  *time.Time   `json:"completed_at,omitempty" db:"completed_at"`
+	Impact       *int         `json:"impact,omitempty" db:"impact"`
+	Urgency      *int         `json:"urgency,omitempty" db:"urgency"`
+	PriorityScore float64     `json:"priority_score" db:"priority_score"`
+	Rank         string       `json:"rank" db:"rank"` // Ранг ручной сортировки внутри проекта
 	Tags         []string     `json:"tags,omitempty" db:"-"` // Теги хранятся в отдельной таблице
+	AssigneeIDs  []string     `json:"assignee_ids,omitempty" db:"-"` // Все исполнители, включая основного
+	EpicID       *string      `json:"epic_id,omitempty" db:"epic_id"`
+	SprintID     *string      `json:"sprint_id,omitempty" db:"sprint_id"`
+	ParentID     *string      `json:"parent_id,omitempty" db:"parent_id"` // Родительская задача, если это подзадача
 }
 
 // TaskHistory представляет запись об изменении задачи
@@ -71,23 +83,29 @@ type TaskCreateRequest struct {
 	Title        string       `json:"title" validate:"required,min=3,max=200"`
 	Description  string       `json:"description" validate:"required"`
 	ProjectID    string       `json:"project_id" validate:"required,uuid"`
-	Priority     TaskPriority `json:"priority" validate:"required,oneof=low medium high critical"`
+	Priority     TaskPriority `json:"priority,omitempty" validate:"omitempty,task_priority"` // По умолчанию берется из настроек проекта
 	AssigneeID   *string      `json:"assignee_id,omitempty" validate:"omitempty,uuid"`
+	AssigneeIDs  []string     `json:"assignee_ids,omitempty" validate:"omitempty,uuid_list"` // Дополнительные исполнители
 	DueDate      *time.Time   `json:"due_date,omitempty"`
 	EstimatedHours *float64   `json:"estimated_hours,omitempty" validate:"omitempty,gte=0"`
+	Impact       *int         `json:"impact,omitempty" validate:"omitempty,min=1,max=5"`
+	Urgency      *int         `json:"urgency,omitempty" validate:"omitempty,min=1,max=5"`
 	Tags         []string     `json:"tags,omitempty" validate:"omitempty,dive,min=1,max=50"`
+	ParentID     *string      `json:"parent_id,omitempty" validate:"omitempty,uuid"` // Открытая задача того же проекта
 }
 
 // TaskUpdateRequest представляет данные для обновления задачи
 type TaskUpdateRequest struct {
 	Title        *string       `json:"title,omitempty" validate:"omitempty,min=3,max=200"`
 	Description  *string       `json:"description,omitempty"`
-	Status       *TaskStatus   `json:"status,omitempty" validate:"omitempty,oneof=new in_progress on_hold review completed cancelled"`
-	Priority     *TaskPriority `json:"priority,omitempty" validate:"omitempty,oneof=low medium high critical"`
+	Status       *TaskStatus   `json:"status,omitempty" validate:"omitempty,task_status"`
+	Priority     *TaskPriority `json:"priority,omitempty" validate:"omitempty,task_priority"`
 	AssigneeID   *string       `json:"assignee_id,omitempty" validate:"omitempty,uuid"`
 	DueDate      *time.Time    `json:"due_date,omitempty"`
 	EstimatedHours *float64    `json:"estimated_hours,omitempty" validate:"omitempty,gte=0"`
 	SpentHours   *float64      `json:"spent_hours,omitempty" validate:"omitempty,gte=0"`
+	Impact       *int          `json:"impact,omitempty" validate:"omitempty,min=1,max=5"`
+	Urgency      *int          `json:"urgency,omitempty" validate:"omitempty,min=1,max=5"`
 	Tags         *[]string     `json:"tags,omitempty" validate:"omitempty,dive,min=1,max=50"`
 }
 
@@ -101,6 +119,8 @@ type TaskResponse struct {
 	Priority     TaskPriority `json:"priority"`
 	AssigneeID   *string      `json:"assignee_id,omitempty"`
 	Assignee     *UserBrief   `json:"assignee,omitempty"`
+	AssigneeIDs  []string     `json:"assignee_ids,omitempty"`
+	Assignees    []UserBrief  `json:"assignees,omitempty"`
 	CreatedBy    string       `json:"created_by"`
 	Creator      *UserBrief   `json:"creator,omitempty"`
 	DueDate      *time.Time   `json:"due_date,omitempty"`
@@ -109,9 +129,85 @@ type TaskResponse struct {
 	CreatedAt    time.Time    `json:"created_at"`
 	UpdatedAt    time.Time    `json:"updated_at"`
 	CompletedAt  *time.Time   `json:"completed_at,omitempty"`
+	Impact       *int         `json:"impact,omitempty"`
+	Urgency      *int         `json:"urgency,omitempty"`
+	PriorityScore float64     `json:"priority_score"`
+	Rank         string       `json:"rank"`
+	EpicID       *string      `json:"epic_id,omitempty"`
+	SprintID     *string      `json:"sprint_id,omitempty"`
+	ParentID     *string      `json:"parent_id,omitempty"`
+	Subtasks     *SubtaskProgress `json:"subtasks,omitempty"` // Только в ответе с подробностями задачи
+	LastViewedAt *time.Time   `json:"last_viewed_at,omitempty"`     // Последний просмотр задачи пользователем, только в списках задач
+	ChangedSinceView bool     `json:"changed_since_view,omitempty"` // Задача изменилась после последнего просмотра, только в списках задач
 	Tags         []string     `json:"tags,omitempty"`
-	Comments     []CommentResponse `json:"comments,omitempty"`
+	Comments     []CommentResponse `json:"comments,omitempty"` // Ответы вложены в родительские комментарии
+	CommentCount *int         `json:"comment_count,omitempty"`      // Всего комментариев верхнего уровня к задаче, только в ответе с комментариями
+	CommentsHasMore bool      `json:"comments_has_more,omitempty"` // Есть комментарии за пределами страницы
+	Attachments  []Attachment `json:"attachments,omitempty"` // Вложения задачи и ее комментариев, только в ответе с подробностями задачи
 	History      []TaskHistoryResponse `json:"history,omitempty"`
+	Warnings     []AssignmentWarning `json:"warnings,omitempty"` // Предупреждения о загрузке исполнителя, только в ответе на назначение
+}
+
+// TaskAssigneesRequest представляет запрос на замену списка исполнителей задачи
+type TaskAssigneesRequest struct {
+	AssigneeIDs []string `json:"assignee_ids" validate:"omitempty,max=20,uuid_list"`
+	PrimaryID   *string  `json:"primary_id,omitempty" validate:"omitempty,uuid"` // По умолчанию сохраняется текущий основной исполнитель
+}
+
+// TaskEffortShare представляет долю оценки задачи, приходящуюся на исполнителя
+type TaskEffortShare struct {
+	UserID         string     `json:"user_id" db:"user_id"`
+	User           *UserBrief `json:"user,omitempty" db:"-"`
+	EstimatedHours *float64   `json:"estimated_hours,omitempty" db:"estimated_hours"` // nil, если доля не задана
+	SpentHours     float64    `json:"spent_hours" db:"spent_hours"`                   // Время, списанное исполнителем на задачу
+	RemainingHours *float64   `json:"remaining_hours,omitempty" db:"-"`
+}
+
+// TaskEffortSplit представляет распределение оценки задачи между исполнителями
+type TaskEffortSplit struct {
+	TaskID                string            `json:"task_id"`
+	EstimatedHours        *float64          `json:"estimated_hours,omitempty"`
+	SpentHours            *float64          `json:"spent_hours,omitempty"`
+	Shares                []TaskEffortShare `json:"shares"`
+	UnallocatedSpentHours float64           `json:"unallocated_spent_hours"` // Время, списанное пользователями без доли
+}
+
+// TaskEffortShareRequest представляет долю исполнителя в запросе на распределение оценки
+type TaskEffortShareRequest struct {
+	UserID         string  `json:"user_id" validate:"required,uuid"`
+	EstimatedHours float64 `json:"estimated_hours" validate:"gte=0"`
+}
+
+// TaskEffortSplitRequest представляет запрос на распределение оценки задачи.
+// Оценка задачи становится равной сумме долей
+type TaskEffortSplitRequest struct {
+	Shares []TaskEffortShareRequest `json:"shares" validate:"required,min=1,dive"`
+}
+
+// SubtaskProgress содержит сводку по подзадачам задачи
+type SubtaskProgress struct {
+	Total          int     `json:"total" db:"total"`
+	Open           int     `json:"open" db:"open"`
+	Completed      int     `json:"completed" db:"completed"`
+	Cancelled      int     `json:"cancelled" db:"cancelled"`
+	Percent        float64 `json:"percent" db:"-"` // Доля завершенных среди неотмененных подзадач
+	EstimatedHours float64 `json:"estimated_hours" db:"estimated_hours"`
+	SpentHours     float64 `json:"spent_hours" db:"spent_hours"`
+}
+
+// ComputePercent вычисляет долю завершенных подзадач без учета отмененных
+func (p *SubtaskProgress) ComputePercent() {
+	p.Percent = 0
+	if active := p.Total - p.Cancelled; active > 0 {
+		p.Percent = math.Round(float64(p.Completed)/float64(active)*1000) / 10
+	}
+}
+
+// SubtaskList представляет подзадачи задачи вместе со сводкой по ним
+type SubtaskList struct {
+	ParentID string          `json:"parent_id"`
+	Progress SubtaskProgress `json:"progress"`
+	Tasks    []TaskResponse  `json:"tasks"`
 }
 
 // UserBrief представляет краткую информацию о пользователе
@@ -152,6 +248,14 @@ func (t *Task) ToResponse() TaskResponse {
 		UpdatedAt:     t.UpdatedAt,
 		CompletedAt:   t.CompletedAt,
 		Tags:          t.Tags,
+		AssigneeIDs:   t.AssigneeIDs,
+		Impact:        t.Impact,
+		Urgency:       t.Urgency,
+		PriorityScore: t.PriorityScore,
+		Rank:          t.Rank,
+		EpicID:        t.EpicID,
+		SprintID:      t.SprintID,
+		ParentID:      t.ParentID,
 	}
 }
 
@@ -168,6 +272,93 @@ func (t *Task) IsOverdue() bool {
 	return time.Now().After(*t.DueDate)
 }
 
+// defaultMatrixLevel используется, если влияние или срочность задачи не указаны
+const defaultMatrixLevel = 3
+
+// ComputePriorityScore вычисляет производную оценку приоритета задачи.
+// Основной вклад дает произведение влияния и срочности, затем приоритет и близость срока
+func (t *Task) ComputePriorityScore(now time.Time) float64 {
+	impact, urgency := defaultMatrixLevel, defaultMatrixLevel
+	if t.Impact != nil {
+		impact = *t.Impact
+	}
+	if t.Urgency != nil {
+		urgency = *t.Urgency
+	}
+
+	score := float64(impact*urgency) * 10
+
+	switch t.Priority {
+	case TaskPriorityLow:
+		score += 5
+	case TaskPriorityMedium:
+		score += 10
+	case TaskPriorityHigh:
+		score += 15
+	case TaskPriorityCritical:
+		score += 20
+	}
+
+	// Чем ближе срок, тем выше оценка незавершенной задачи
+	if t.DueDate != nil && t.Status != TaskStatusCompleted && t.Status != TaskStatusCancelled {
+		left := t.DueDate.Sub(now)
+		switch {
+		case left < 0:
+			score += 30
+		case left <= 24*time.Hour:
+			score += 20
+		case left <= 3*24*time.Hour:
+			score += 10
+		case left <= 7*24*time.Hour:
+			score += 5
+		}
+	}
+
+	return score
+}
+
+// MatrixPriority возвращает приоритет, соответствующий ячейке матрицы влияния и срочности
+func MatrixPriority(impact, urgency int) TaskPriority {
+	switch product := impact * urgency; {
+	case product >= 16:
+		return TaskPriorityCritical
+	case product >= 9:
+		return TaskPriorityHigh
+	case product >= 4:
+		return TaskPriorityMedium
+	default:
+		return TaskPriorityLow
+	}
+}
+
+// TaskReprioritizeRequest представляет запрос на пересчет оценок приоритета задач проекта
+type TaskReprioritizeRequest struct {
+	ApplyPriority bool `json:"apply_priority"` // Выставить приоритет по матрице задачам с заданными влиянием и срочностью
+}
+
+// TaskReprioritizeResult представляет результат пересчета оценок приоритета
+type TaskReprioritizeResult struct {
+	ProjectID       string `json:"project_id"`
+	TasksScored     int    `json:"tasks_scored"`
+	PriorityChanged int    `json:"priority_changed"`
+}
+
+// TaskMoveRequest представляет запрос на перемещение задачи при ручной сортировке.
+// Указывается ровно одно из полей
+type TaskMoveRequest struct {
+	AfterID  *string `json:"after_id,omitempty" validate:"omitempty,uuid"`  // Поставить сразу после задачи
+	BeforeID *string `json:"before_id,omitempty" validate:"omitempty,uuid"` // Поставить сразу перед задачей
+	Position *string `json:"position,omitempty" validate:"omitempty,oneof=top bottom"`
+}
+
+// TaskProjectMoveRequest представляет запрос на перенос задачи в другой проект.
+// Статусы и теги без сопоставления переносятся как есть, пустое значение в TagMapping удаляет тег
+type TaskProjectMoveRequest struct {
+	ProjectID     string                    `json:"project_id" validate:"required,uuid"`
+	StatusMapping map[TaskStatus]TaskStatus `json:"status_mapping,omitempty" validate:"omitempty,dive,keys,task_status,endkeys,task_status"`
+	TagMapping    map[string]string         `json:"tag_mapping,omitempty" validate:"omitempty,dive,keys,min=1,max=50,endkeys,max=50"`
+}
+
 // TaskTag представляет связь задачи с тегом
 type TaskTag struct {
 	TaskID string `json:"task_id" db:"task_id"`
@@ -186,19 +377,163 @@ type LogTimeRequest struct {
 	Date        *time.Time `json:"date,omitempty"`
 }
 
+// TimeReconciliation сравнивает затраченное время задачи с суммой ее записей о времени
+type TimeReconciliation struct {
+	TaskID       string                   `json:"task_id" db:"task_id"`
+	SpentHours   float64                  `json:"spent_hours" db:"spent_hours"`   // Значение в задаче
+	LoggedHours  float64                  `json:"logged_hours" db:"logged_hours"` // Сумма записей о времени
+	Difference   float64                  `json:"difference" db:"difference"`     // SpentHours - LoggedHours
+	LogCount     int                      `json:"log_count" db:"log_count"`
+	Users        []TimeReconciliationUser `json:"users,omitempty"`
+	Recalculated bool                     `json:"recalculated"` // Затраченное время было перезаписано суммой записей
+}
+
+// TimeReconciliationUser содержит сумму записей о времени одного пользователя
+type TimeReconciliationUser struct {
+	UserID   string  `json:"user_id" db:"user_id"`
+	Hours    float64 `json:"hours" db:"hours"`
+	LogCount int     `json:"log_count" db:"log_count"`
+}
+
+// ProjectTimeRecalculation представляет результат пересчета затраченного времени задач проекта
+type ProjectTimeRecalculation struct {
+	ProjectID string                `json:"project_id"`
+	Tasks     []*TimeReconciliation `json:"tasks"` // Задачи, время которых изменилось
+}
+
 // TaskFilterOptions представляет параметры для фильтрации задач
 type TaskFilterOptions struct {
-	ProjectID  *string       `json:"project_id,omitempty"`
-	Status     *TaskStatus   `json:"status,omitempty"`
-	Priority   *TaskPriority `json:"priority,omitempty"`
-	AssigneeID *string       `json:"assignee_id,omitempty"`
-	CreatedBy  *string       `json:"created_by,omitempty"`
-	DueBefore  *time.Time    `json:"due_before,omitempty"`
-	DueAfter   *time.Time    `json:"due_after,omitempty"`
-	Tags       []string      `json:"tags,omitempty"`
-	SearchText *string       `json:"search_text,omitempty"`
-	SortBy     *string       `json:"sort_by,omitempty"`
-	SortOrder  *string       `json:"sort_order,omitempty"`
-	Page       int           `json:"page"`
-	PageSize   int           `json:"page_size"`
-}
\ No newline at end of file
+	ProjectID       *string        `json:"project_id,omitempty"`
+	Statuses        []TaskStatus   `json:"statuses,omitempty"`   // Любой из перечисленных статусов
+	Priorities      []TaskPriority `json:"priorities,omitempty"` // Любой из перечисленных приоритетов
+	AssigneeID      *string        `json:"assignee_id,omitempty"`
+	AssigneeIDs     []string       `json:"assignee_ids,omitempty"` // Любой из перечисленных исполнителей
+	CreatedBy       *string        `json:"created_by,omitempty"`
+	DueBefore       *time.Time     `json:"due_before,omitempty"`
+	DueAfter        *time.Time     `json:"due_after,omitempty"`
+	CreatedBefore   *time.Time     `json:"created_before,omitempty"`
+	CreatedAfter    *time.Time     `json:"created_after,omitempty"`
+	UpdatedBefore   *time.Time     `json:"updated_before,omitempty"`
+	UpdatedAfter    *time.Time     `json:"updated_after,omitempty"`
+	CompletedBefore *time.Time     `json:"completed_before,omitempty"` // Незавершенные задачи не попадают в выборку
+	CompletedAfter  *time.Time     `json:"completed_after,omitempty"`
+	Tags            []string       `json:"tags,omitempty"`
+	EpicID          *string        `json:"epic_id,omitempty"`
+	SprintID        *string        `json:"sprint_id,omitempty"`
+	ParentID        *string        `json:"parent_id,omitempty"`
+	SearchText      *string        `json:"search_text,omitempty"`
+	SortBy          *string        `json:"sort_by,omitempty"`
+	SortOrder       *string        `json:"sort_order,omitempty"`
+	Sort            []SortKey      `json:"sort,omitempty"` // Сортировка по нескольким полям, имеет приоритет над SortBy
+	Page            int            `json:"page"`
+	PageSize        int            `json:"page_size"`
+}
+
+// TaskEditLock представляет мягкую блокировку редактирования описания задачи
+type TaskEditLock struct {
+	TaskID     string    `json:"task_id"`
+	UserID     string    `json:"user_id"`
+	AcquiredAt time.Time `json:"acquired_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// TaskEditLockRequest представляет запрос на получение блокировки редактирования
+type TaskEditLockRequest struct {
+	Force bool `json:"force"`
+}
+
+// TaskRecipient определяет адресата действия по задаче (эскалации, согласования)
+type TaskRecipient string
+
+const (
+	// TaskRecipientAssignee - исполнитель задачи
+	TaskRecipientAssignee TaskRecipient = "assignee"
+	// TaskRecipientAssigneeManager - руководитель исполнителя задачи
+	TaskRecipientAssigneeManager TaskRecipient = "assignee_manager"
+	// TaskRecipientCreator - автор задачи
+	TaskRecipientCreator TaskRecipient = "creator"
+)
+
+// TaskInclude - часть ответа с подробностями задачи, которую можно запросить параметром include
+type TaskInclude string
+
+const (
+	// TaskIncludeComments - страница комментариев и их общее количество
+	TaskIncludeComments TaskInclude = "comments"
+	// TaskIncludeHistory - история изменений
+	TaskIncludeHistory TaskInclude = "history"
+	// TaskIncludeAttachments - вложения задачи и ее комментариев
+	TaskIncludeAttachments TaskInclude = "attachments"
+	// TaskIncludeSubtasks - сводка по подзадачам
+	TaskIncludeSubtasks TaskInclude = "subtasks"
+)
+
+// Страница комментариев в ответе с подробностями задачи по умолчанию
+const (
+	DefaultTaskCommentsPageSize = 50
+	MaxTaskCommentsPageSize     = 100
+)
+
+// TaskDetailOptions задает состав ответа с подробностями задачи
+type TaskDetailOptions struct {
+	Include          []TaskInclude // Пустой список - все части
+	CommentsPage     int
+	CommentsPageSize int
+	CommentsOrder    string // asc - сначала старые, desc - сначала новые
+}
+
+// DefaultTaskDetailOptions возвращает состав ответа по умолчанию: все части
+// и первая страница из 50 новых комментариев
+func DefaultTaskDetailOptions() TaskDetailOptions {
+	return TaskDetailOptions{
+		CommentsPage:     1,
+		CommentsPageSize: DefaultTaskCommentsPageSize,
+		CommentsOrder:    "desc",
+	}
+}
+
+// Includes проверяет, что часть ответа запрошена
+func (o TaskDetailOptions) Includes(part TaskInclude) bool {
+	if len(o.Include) == 0 {
+		return true
+	}
+	for _, included := range o.Include {
+		if included == part {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultComments проверяет, что запрошена страница комментариев по умолчанию
+func (o TaskDetailOptions) DefaultComments() bool {
+	defaults := DefaultTaskDetailOptions()
+	return o.CommentsPage == defaults.CommentsPage &&
+		o.CommentsPageSize == defaults.CommentsPageSize &&
+		o.CommentsOrder == defaults.CommentsOrder
+}
+
+// AssigneeLoad описывает загрузку исполнителя незавершенными задачами всех проектов
+type AssigneeLoad struct {
+	OpenTasks        int `db:"open_tasks"`
+	OverlappingTasks int `db:"overlapping_tasks"` // Задачи со сроком рядом со сроком назначаемой задачи
+}
+
+// AssignmentWarningCode определяет вид предупреждения о загрузке исполнителя
+type AssignmentWarningCode string
+
+const (
+	// AssignmentWarningOpenTasks - у исполнителя слишком много незавершенных задач
+	AssignmentWarningOpenTasks AssignmentWarningCode = "assignee_open_tasks"
+	// AssignmentWarningOverlappingDueDates - у исполнителя есть задачи с тем же сроком
+	AssignmentWarningOverlappingDueDates AssignmentWarningCode = "assignee_overlapping_due_dates"
+)
+
+// AssignmentWarning - предупреждение о загрузке исполнителя. Назначение при этом выполняется
+type AssignmentWarning struct {
+	Code       AssignmentWarningCode `json:"code"`
+	Message    string                `json:"message"`
+	AssigneeID string                `json:"assignee_id"`
+	Count      int                   `json:"count"`
+	Limit      int                   `json:"limit,omitempty"`
+}
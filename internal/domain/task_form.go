@@ -0,0 +1,115 @@
+package domain
+
+import (
+	"time"
+)
+
+// TaskFormFieldType определяет тип поля формы создания задачи
+type TaskFormFieldType string
+
+const (
+	// TaskFormFieldText - однострочный текст
+	TaskFormFieldText TaskFormFieldType = "text"
+	// TaskFormFieldTextarea - многострочный текст
+	TaskFormFieldTextarea TaskFormFieldType = "textarea"
+	// TaskFormFieldNumber - число
+	TaskFormFieldNumber TaskFormFieldType = "number"
+	// TaskFormFieldDate - дата
+	TaskFormFieldDate TaskFormFieldType = "date"
+	// TaskFormFieldSelect - выбор одного значения из списка
+	TaskFormFieldSelect TaskFormFieldType = "select"
+	// TaskFormFieldMultiselect - выбор нескольких значений
+	TaskFormFieldMultiselect TaskFormFieldType = "multiselect"
+	// TaskFormFieldUser - участник проекта
+	TaskFormFieldUser TaskFormFieldType = "user"
+	// TaskFormFieldCheckbox - флажок
+	TaskFormFieldCheckbox TaskFormFieldType = "checkbox"
+)
+
+// Ключи встроенных полей формы, которые соответствуют полям задачи
+const (
+	TaskFormKeyTitle          = "title"
+	TaskFormKeyDescription    = "description"
+	TaskFormKeyPriority       = "priority"
+	TaskFormKeyAssignee       = "assignee_id"
+	TaskFormKeyDueDate        = "due_date"
+	TaskFormKeyEstimatedHours = "estimated_hours"
+	TaskFormKeyTags           = "tags"
+)
+
+// TaskFormBuiltinTypes задает допустимый тип для каждого встроенного поля формы
+var TaskFormBuiltinTypes = map[string][]TaskFormFieldType{
+	TaskFormKeyTitle:          {TaskFormFieldText},
+	TaskFormKeyDescription:    {TaskFormFieldText, TaskFormFieldTextarea},
+	TaskFormKeyPriority:       {TaskFormFieldSelect},
+	TaskFormKeyAssignee:       {TaskFormFieldUser},
+	TaskFormKeyDueDate:        {TaskFormFieldDate},
+	TaskFormKeyEstimatedHours: {TaskFormFieldNumber},
+	TaskFormKeyTags:           {TaskFormFieldMultiselect},
+}
+
+// TaskFormCondition описывает условие видимости поля:
+// поле показывается, если значение поля Field входит в Values
+type TaskFormCondition struct {
+	Field  string   `json:"field" validate:"required"`
+	Values []string `json:"values" validate:"required,min=1"`
+}
+
+// TaskFormField представляет поле формы создания задачи
+type TaskFormField struct {
+	Key       string             `json:"key" validate:"required,min=1,max=50"`
+	Label     string             `json:"label" validate:"required,max=100"`
+	Type      TaskFormFieldType  `json:"type" validate:"required,oneof=text textarea number date select multiselect user checkbox"`
+	Required  bool               `json:"required"`
+	HelpText  string             `json:"help_text,omitempty" validate:"max=500"`
+	Options   []string           `json:"options,omitempty" validate:"omitempty,dive,min=1,max=100"`
+	VisibleIf *TaskFormCondition `json:"visible_if,omitempty"`
+}
+
+// TaskForm представляет определение формы создания задачи в проекте.
+// Порядок полей соответствует порядку отображения
+type TaskForm struct {
+	ProjectID string          `json:"project_id" db:"project_id"`
+	Fields    []TaskFormField `json:"fields" db:"-"`
+	IsDefault bool            `json:"is_default" db:"-"`
+	UpdatedBy *string         `json:"updated_by,omitempty" db:"updated_by"`
+	UpdatedAt time.Time       `json:"updated_at" db:"updated_at"`
+}
+
+// TaskFormRequest представляет данные для сохранения формы создания задачи
+type TaskFormRequest struct {
+	Fields []TaskFormField `json:"fields" validate:"required,min=1,max=50,dive"`
+}
+
+// TaskFormSubmission представляет заполненную форму создания задачи
+type TaskFormSubmission struct {
+	Values map[string]interface{} `json:"values" validate:"required"`
+}
+
+// TaskFormSubmissionResponse представляет результат отправки формы
+type TaskFormSubmissionResponse struct {
+	Task   *TaskResponse          `json:"task"`
+	Values map[string]interface{} `json:"values,omitempty"`
+}
+
+// DefaultTaskForm возвращает форму, которая используется, если проект не задал собственную
+func DefaultTaskForm(projectID string) *TaskForm {
+	return &TaskForm{
+		ProjectID: projectID,
+		IsDefault: true,
+		Fields: []TaskFormField{
+			{Key: TaskFormKeyTitle, Label: "Title", Type: TaskFormFieldText, Required: true},
+			{Key: TaskFormKeyDescription, Label: "Description", Type: TaskFormFieldTextarea},
+			{
+				Key:     TaskFormKeyPriority,
+				Label:   "Priority",
+				Type:    TaskFormFieldSelect,
+				Options: []string{string(TaskPriorityLow), string(TaskPriorityMedium), string(TaskPriorityHigh), string(TaskPriorityCritical)},
+			},
+			{Key: TaskFormKeyAssignee, Label: "Assignee", Type: TaskFormFieldUser},
+			{Key: TaskFormKeyDueDate, Label: "Due date", Type: TaskFormFieldDate},
+			{Key: TaskFormKeyEstimatedHours, Label: "Estimate, hours", Type: TaskFormFieldNumber},
+			{Key: TaskFormKeyTags, Label: "Tags", Type: TaskFormFieldMultiselect},
+		},
+	}
+}
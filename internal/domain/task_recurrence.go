@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"time"
+)
+
+// TaskRecurrenceStatus определяет состояние серии повторяющихся задач
+type TaskRecurrenceStatus string
+
+const (
+	// TaskRecurrenceStatusActive - ожидает завершения текущей задачи серии
+	TaskRecurrenceStatusActive TaskRecurrenceStatus = "active"
+	// TaskRecurrenceStatusProcessing - планировщик создает следующую задачу
+	TaskRecurrenceStatusProcessing TaskRecurrenceStatus = "processing"
+	// TaskRecurrenceStatusFinished - повторения исчерпаны или следующую задачу не удалось создать
+	TaskRecurrenceStatusFinished TaskRecurrenceStatus = "finished"
+)
+
+// TaskRecurrence представляет правило повторения задачи. Правило привязано к текущей
+// задаче серии: когда она завершается, планировщик создает следующую и переносит правило на нее
+type TaskRecurrence struct {
+	ID          string               `json:"id" db:"id"`
+	TaskID      string               `json:"task_id" db:"task_id"`         // Текущая задача серии
+	Rule        string               `json:"rule" db:"rule"`               // Правило в формате RRULE
+	Occurrences int                  `json:"occurrences" db:"occurrences"` // Число созданных задач серии, включая первую
+	Status      TaskRecurrenceStatus `json:"status" db:"status"`
+	LastError   *string              `json:"last_error,omitempty" db:"last_error"`
+	NextDueDate *time.Time           `json:"next_due_date,omitempty" db:"-"` // Срок следующей задачи, если она будет создана
+	CreatedBy   string               `json:"created_by" db:"created_by"`
+	CreatedAt   time.Time            `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time            `json:"updated_at" db:"updated_at"`
+}
+
+// TaskRecurrenceRequest представляет запрос на установку правила повторения задачи
+type TaskRecurrenceRequest struct {
+	Rule string `json:"rule" validate:"required,max=255"` // Например FREQ=WEEKLY;BYDAY=MO,TH;COUNT=10
+}
@@ -0,0 +1,89 @@
+package domain
+
+import (
+	"regexp"
+	"time"
+)
+
+// templateVariablePattern описывает переменную шаблона: {{name}}, пробелы внутри скобок допускаются
+var templateVariablePattern = regexp.MustCompile(`\{\{\s*([a-zA-Z_][a-zA-Z0-9_]*)\s*\}\}`)
+
+// TaskTemplate представляет шаблон задачи проекта
+type TaskTemplate struct {
+	ID             string        `json:"id"`
+	ProjectID      string        `json:"project_id"`
+	Name           string        `json:"name"`
+	Title          string        `json:"title"`
+	Description    string        `json:"description"`
+	Checklist      []string      `json:"checklist,omitempty"`
+	Tags           []string      `json:"tags,omitempty"`
+	Priority       *TaskPriority `json:"priority,omitempty"`
+	AssigneeID     *string       `json:"assignee_id,omitempty"`
+	EstimatedHours *float64      `json:"estimated_hours,omitempty"`
+	CreatedBy      string        `json:"created_by"`
+	CreatedAt      time.Time     `json:"created_at"`
+	UpdatedAt      time.Time     `json:"updated_at"`
+}
+
+// TaskTemplateRequest представляет данные для создания или замены шаблона задачи
+type TaskTemplateRequest struct {
+	Name           string        `json:"name" validate:"required,min=1,max=100"`
+	Title          string        `json:"title" validate:"required,min=3,max=200"`
+	Description    string        `json:"description"`
+	Checklist      []string      `json:"checklist,omitempty" validate:"omitempty,max=100,dive,min=1,max=500"`
+	Tags           []string      `json:"tags,omitempty" validate:"omitempty,dive,min=1,max=50"`
+	Priority       *TaskPriority `json:"priority,omitempty" validate:"omitempty,task_priority"`
+	AssigneeID     *string       `json:"assignee_id,omitempty" validate:"omitempty,uuid"`
+	EstimatedHours *float64      `json:"estimated_hours,omitempty" validate:"omitempty,gte=0"`
+}
+
+// TaskTemplateResponse представляет шаблон задачи вместе с переменными, которые нужно передать при создании задачи
+type TaskTemplateResponse struct {
+	TaskTemplate
+	Variables []string `json:"variables"`
+}
+
+// TaskFromTemplateRequest представляет запрос на создание задачи из шаблона
+type TaskFromTemplateRequest struct {
+	Variables  map[string]string `json:"variables,omitempty"`
+	AssigneeID *string           `json:"assignee_id,omitempty" validate:"omitempty,uuid"` // Заменяет исполнителя из шаблона
+	DueDate    *time.Time        `json:"due_date,omitempty"`
+}
+
+// Variables возвращает имена переменных шаблона в порядке первого появления
+func (t *TaskTemplate) Variables() []string {
+	texts := append([]string{t.Title, t.Description}, t.Checklist...)
+	texts = append(texts, t.Tags...)
+
+	variables := []string{}
+	seen := make(map[string]bool)
+	for _, text := range texts {
+		for _, match := range templateVariablePattern.FindAllStringSubmatch(text, -1) {
+			if !seen[match[1]] {
+				seen[match[1]] = true
+				variables = append(variables, match[1])
+			}
+		}
+	}
+	return variables
+}
+
+// ToResponse преобразует TaskTemplate в TaskTemplateResponse
+func (t *TaskTemplate) ToResponse() TaskTemplateResponse {
+	return TaskTemplateResponse{
+		TaskTemplate: *t,
+		Variables:    t.Variables(),
+	}
+}
+
+// ExpandTemplate подставляет значения переменных в текст шаблона.
+// Переменные без значения остаются в тексте как есть
+func ExpandTemplate(text string, variables map[string]string) string {
+	return templateVariablePattern.ReplaceAllStringFunc(text, func(placeholder string) string {
+		name := templateVariablePattern.FindStringSubmatch(placeholder)[1]
+		if value, ok := variables[name]; ok {
+			return value
+		}
+		return placeholder
+	})
+}
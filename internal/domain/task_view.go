@@ -0,0 +1,12 @@
+package domain
+
+import (
+	"time"
+)
+
+// TaskView - последний просмотр задачи пользователем
+type TaskView struct {
+	UserID   string    `json:"user_id" db:"user_id"`
+	TaskID   string    `json:"task_id" db:"task_id"`
+	ViewedAt time.Time `json:"viewed_at" db:"viewed_at"`
+}
@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"time"
+)
+
+// TeamsLink представляет вебхук личного чата пользователя в Microsoft Teams
+type TeamsLink struct {
+	UserID     string    `json:"user_id" db:"user_id"`
+	WebhookURL string    `json:"-" db:"webhook_url"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// TeamsStatus представляет состояние подключения пользователя к Teams
+type TeamsStatus struct {
+	Connected bool       `json:"connected"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+// TeamsLinkRequest представляет данные для подключения личного чата Teams
+type TeamsLinkRequest struct {
+	WebhookURL string `json:"webhook_url" validate:"required,url,startswith=https://,max=2000"`
+}
+
+// ProjectTeamsChannel представляет канал Teams проекта.
+// В канал публикуются уведомления о задачах и проекте выбранных типов
+type ProjectTeamsChannel struct {
+	ProjectID         string             `json:"project_id" db:"project_id"`
+	WebhookURL        string             `json:"-" db:"webhook_url"`
+	Enabled           bool               `json:"enabled" db:"enabled"`
+	NotificationTypes []NotificationType `json:"notification_types" db:"-"`
+	CreatedBy         string             `json:"created_by" db:"created_by"`
+	CreatedAt         time.Time          `json:"created_at" db:"created_at"`
+	UpdatedAt         time.Time          `json:"updated_at" db:"updated_at"`
+}
+
+// ProjectTeamsChannelRequest представляет данные для настройки канала Teams проекта.
+// Пустой webhook_url оставляет сохраненный вебхук
+type ProjectTeamsChannelRequest struct {
+	WebhookURL        string             `json:"webhook_url,omitempty" validate:"omitempty,url,startswith=https://,max=2000"`
+	Enabled           *bool              `json:"enabled,omitempty"`
+	NotificationTypes []NotificationType `json:"notification_types" validate:"required,min=1,dive,oneof=task_assigned task_updated task_commented task_due_soon task_overdue project_member_added project_updated"`
+}
+
+// HasNotificationType проверяет, публикуются ли в канал уведомления указанного типа
+func (c *ProjectTeamsChannel) HasNotificationType(notificationType NotificationType) bool {
+	for _, t := range c.NotificationTypes {
+		if t == notificationType {
+			return true
+		}
+	}
+	return false
+}
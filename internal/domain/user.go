@@ -31,10 +31,16 @@ type User struct {
 	Department     *string   `json:"department,omitempty" db:"department"`
 	IsActive       bool      `json:"is_active" db:"is_active"`
 	LastLoginAt    *time.Time `json:"last_login_at,omitempty" db:"last_login_at"`
+	WeeklyCapacityHours float64 `json:"weekly_capacity_hours" db:"weekly_capacity_hours"`
+	ManagerID      *string   `json:"manager_id,omitempty" db:"manager_id"`
 	CreatedAt      time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt      time.Time `json:"updated_at" db:"updated_at"`
+	Skills         []string  `json:"skills,omitempty" db:"-"` // Навыки хранятся в отдельной таблице
 }
 
+// DefaultWeeklyCapacityHours определяет доступность нового пользователя в часах в неделю
+const DefaultWeeklyCapacityHours = 40.0
+
 // UserCreateRequest представляет данные для создания пользователя
 type UserCreateRequest struct {
 	Email     string   `json:"email" validate:"required,email"`
@@ -56,6 +62,8 @@ type UserUpdateRequest struct {
 	Department *string   `json:"department,omitempty"`
 	Avatar     *string   `json:"avatar,omitempty"`
 	IsActive   *bool     `json:"is_active,omitempty"`
+	Skills     *[]string `json:"skills,omitempty" validate:"omitempty,max=50,dive,required,max=50"`
+	WeeklyCapacityHours *float64 `json:"weekly_capacity_hours,omitempty" validate:"omitempty,gte=0,lte=168"`
 }
 
 // UserResponse представляет данные пользователя для API-ответов
@@ -69,6 +77,9 @@ type UserResponse struct {
 	Position   *string   `json:"position,omitempty"`
 	Department *string   `json:"department,omitempty"`
 	IsActive   bool      `json:"is_active"`
+	Skills     []string  `json:"skills,omitempty"`
+	WeeklyCapacityHours float64 `json:"weekly_capacity_hours"`
+	ManagerID  *string   `json:"manager_id,omitempty"`
 	CreatedAt  time.Time `json:"created_at"`
 	UpdatedAt  time.Time `json:"updated_at"`
 }
@@ -85,11 +96,22 @@ func (u *User) ToResponse() UserResponse {
 		Position:   u.Position,
 		Department: u.Department,
 		IsActive:   u.IsActive,
+		Skills:     u.Skills,
+		WeeklyCapacityHours: u.WeeklyCapacityHours,
+		ManagerID:  u.ManagerID,
 		CreatedAt:  u.CreatedAt,
 		UpdatedAt:  u.UpdatedAt,
 	}
 }
 
+// UserDirectoryEntry представляет пользователя в справочнике с его текущей загрузкой
+type UserDirectoryEntry struct {
+	UserResponse
+	OpenTasks     int     `json:"open_tasks"`
+	WorkloadHours float64 `json:"workload_hours"`
+	FreeHours     float64 `json:"free_hours"`
+}
+
 // FullName возвращает полное имя пользователя
 func (u *User) FullName() string {
 	return u.FirstName + " " + u.LastName
@@ -105,6 +127,17 @@ func (u *User) IsAdmin() bool {
 	return u.Role == UserRoleAdmin
 }
 
+// SetManagerRequest представляет запрос на назначение руководителя пользователя
+type SetManagerRequest struct {
+	ManagerID *string `json:"manager_id" validate:"omitempty,uuid"`
+}
+
+// ReportingChainEntry представляет руководителя в цепочке подчинения
+type ReportingChainEntry struct {
+	Level int          `json:"level"`
+	User  UserResponse `json:"user"`
+}
+
 // LoginRequest представляет данные для входа пользователя
 type LoginRequest struct {
 	Email    string `json:"email" validate:"required,email"`
@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"time"
+)
+
+// WikiPage представляет вики-страницу проекта в формате Markdown
+type WikiPage struct {
+	ID        string    `json:"id" db:"id"`
+	ProjectID string    `json:"project_id" db:"project_id"`
+	ParentID  *string   `json:"parent_id,omitempty" db:"parent_id"`
+	Title     string    `json:"title" db:"title"`
+	Content   string    `json:"content" db:"content"`
+	Version   int       `json:"version" db:"version"`
+	CreatedBy string    `json:"created_by" db:"created_by"`
+	UpdatedBy string    `json:"updated_by" db:"updated_by"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+	TaskIDs   []string  `json:"task_ids" db:"-"`
+}
+
+// WikiPageNode представляет страницу в дереве вики проекта (без содержимого)
+type WikiPageNode struct {
+	ID        string          `json:"id" db:"id"`
+	ParentID  *string         `json:"parent_id,omitempty" db:"parent_id"`
+	Title     string          `json:"title" db:"title"`
+	Version   int             `json:"version" db:"version"`
+	UpdatedAt time.Time       `json:"updated_at" db:"updated_at"`
+	Children  []*WikiPageNode `json:"children" db:"-"`
+}
+
+// WikiSearchResult представляет найденную вики-страницу с фрагментом текста
+type WikiSearchResult struct {
+	ID        string    `json:"id" db:"id"`
+	ParentID  *string   `json:"parent_id,omitempty" db:"parent_id"`
+	Title     string    `json:"title" db:"title"`
+	Snippet   string    `json:"snippet" db:"snippet"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// WikiPageRevision представляет снимок вики-страницы после правки
+type WikiPageRevision struct {
+	ID        string    `json:"id" db:"id"`
+	PageID    string    `json:"page_id" db:"page_id"`
+	Version   int       `json:"version" db:"version"`
+	Title     string    `json:"title" db:"title"`
+	Content   string    `json:"content,omitempty" db:"content"`
+	EditedBy  string    `json:"edited_by" db:"edited_by"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// WikiPageCreateRequest представляет данные для создания вики-страницы
+type WikiPageCreateRequest struct {
+	Title    string   `json:"title" validate:"required,min=1,max=200"`
+	Content  string   `json:"content" validate:"max=200000"`
+	ParentID *string  `json:"parent_id,omitempty" validate:"omitempty,uuid"`
+	TaskIDs  []string `json:"task_ids,omitempty" validate:"omitempty,max=50,uuid_list"`
+}
+
+// WikiPageUpdateRequest представляет данные для правки вики-страницы.
+// Version - версия, на основе которой сделана правка; при расхождении правка отклоняется
+type WikiPageUpdateRequest struct {
+	Version    int     `json:"version" validate:"required,min=1"`
+	Title      *string `json:"title,omitempty" validate:"omitempty,min=1,max=200"`
+	Content    *string `json:"content,omitempty" validate:"omitempty,max=200000"`
+	ParentID   *string `json:"parent_id,omitempty" validate:"omitempty,uuid"`
+	MoveToRoot bool    `json:"move_to_root,omitempty"` // Сделать страницу корневой
+}
+
+// WikiPageTaskRequest представляет запрос на связь вики-страницы с задачей
+type WikiPageTaskRequest struct {
+	TaskID string `json:"task_id" validate:"required,uuid"`
+}
@@ -0,0 +1,206 @@
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+
+	"github.com/nurlyy/task_manager/pkg/config"
+	"github.com/nurlyy/task_manager/pkg/logger"
+)
+
+// claimDueScript забирает из очереди наступившие уведомления: возвращает до ARGV[2] элементов
+// со временем отправки не позже ARGV[1] и переносит их на ARGV[3], чтобы их не забрал другой
+// диспетчер. Если отправка не подтверждена, элементы вернутся в выдачу после этого времени
+var claimDueScript = redis.NewScript(`
+local items = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, tonumber(ARGV[2]))
+for _, item in ipairs(items) do
+	redis.call('ZADD', KEYS[1], ARGV[3], item)
+end
+return items
+`)
+
+// delayedNotification - элемент очереди отложенных уведомлений. ID делает одинаковые
+// уведомления разными элементами множества
+type delayedNotification struct {
+	ID           string             `json:"id"`
+	Notification *NotificationEvent `json:"notification"`
+}
+
+// DelayQueue хранит отложенные уведомления в отсортированном множестве Redis по времени
+// отправки. Диспетчер забирает наступившие уведомления и публикует их в Kafka, поэтому
+// напоминания и перенос уведомлений не требуют периодического поиска по базе данных.
+// Уведомление удаляется из очереди только после публикации, так что при сбое диспетчера
+// оно будет отправлено повторно
+type DelayQueue struct {
+	client *redis.Client
+	config *config.DelayQueueConfig
+	logger logger.Logger
+
+	dispatched uint64
+	failed     uint64
+}
+
+// NewDelayQueue создает новый экземпляр DelayQueue
+func NewDelayQueue(client *redis.Client, config *config.DelayQueueConfig, logger logger.Logger) *DelayQueue {
+	return &DelayQueue{
+		client: client,
+		config: config,
+		logger: logger,
+	}
+}
+
+// Schedule откладывает уведомление до времени at
+func (q *DelayQueue) Schedule(ctx context.Context, notification *NotificationEvent, at time.Time) error {
+	member, err := json.Marshal(delayedNotification{
+		ID:           uuid.New().String(),
+		Notification: notification,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal delayed notification: %w", err)
+	}
+
+	if err := q.client.ZAdd(ctx, q.config.Key, &redis.Z{
+		Score:  float64(at.UnixMilli()),
+		Member: string(member),
+	}).Err(); err != nil {
+		q.logger.Error("Failed to schedule notification", err, map[string]interface{}{
+			"type":      notification.Type,
+			"entity_id": notification.EntityID,
+			"at":        at,
+		})
+		return fmt.Errorf("failed to schedule notification: %w", err)
+	}
+
+	q.logger.Debug("Notification scheduled", map[string]interface{}{
+		"type":      notification.Type,
+		"entity_id": notification.EntityID,
+		"at":        at,
+	})
+	return nil
+}
+
+// Run публикует наступившие уведомления через producer, пока не завершится контекст.
+// Несколько диспетчеров могут работать с одной очередью одновременно
+func (q *DelayQueue) Run(ctx context.Context, producer *KafkaProducer) {
+	q.logger.Info("Starting delayed notification dispatcher", map[string]interface{}{
+		"key":           q.config.Key,
+		"poll_interval": q.config.PollInterval.String(),
+	})
+
+	ticker := time.NewTicker(q.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			q.logger.Info("Delayed notification dispatcher stopped")
+			return
+		case <-ticker.C:
+			q.dispatchDue(ctx, producer)
+		}
+	}
+}
+
+// dispatchDue публикует все наступившие уведомления порциями
+func (q *DelayQueue) dispatchDue(ctx context.Context, producer *KafkaProducer) {
+	for ctx.Err() == nil {
+		now := time.Now()
+		items, err := claimDueScript.Run(ctx, q.client, []string{q.config.Key},
+			now.UnixMilli(), q.config.BatchSize, now.Add(q.config.Lease).UnixMilli(),
+		).StringSlice()
+		if err != nil {
+			if ctx.Err() == nil {
+				q.logger.Error("Failed to claim delayed notifications", err)
+			}
+			return
+		}
+
+		for _, item := range items {
+			q.dispatch(ctx, producer, item)
+		}
+
+		if len(items) < q.config.BatchSize {
+			return
+		}
+	}
+}
+
+// dispatch публикует забранное уведомление и удаляет его из очереди. Если публикация
+// не удалась, уведомление остается в очереди и будет забрано повторно после истечения аренды
+func (q *DelayQueue) dispatch(ctx context.Context, producer *KafkaProducer, item string) {
+	var delayed delayedNotification
+	if err := json.Unmarshal([]byte(item), &delayed); err != nil || delayed.Notification == nil {
+		// Повтор такого элемента ничего не исправит
+		q.logger.Error("Dropping malformed delayed notification", err, map[string]interface{}{
+			"item": item,
+		})
+		q.remove(ctx, item)
+		return
+	}
+
+	if err := producer.PublishNotification(ctx, delayed.Notification); err != nil {
+		atomic.AddUint64(&q.failed, 1)
+		q.logger.Warn("Failed to publish delayed notification, will retry", map[string]interface{}{
+			"id":        delayed.ID,
+			"type":      delayed.Notification.Type,
+			"retry_in":  q.config.Lease.String(),
+			"entity_id": delayed.Notification.EntityID,
+		}, map[string]interface{}{
+			"error": err,
+		})
+		return
+	}
+
+	atomic.AddUint64(&q.dispatched, 1)
+	q.remove(ctx, item)
+}
+
+// remove удаляет элемент из очереди. Не удаленный элемент будет отправлен повторно
+func (q *DelayQueue) remove(ctx context.Context, item string) {
+	if err := q.client.ZRem(context.WithoutCancel(ctx), q.config.Key, item).Err(); err != nil {
+		q.logger.Error("Failed to remove delayed notification from queue", err)
+	}
+}
+
+// WritePrometheus записывает метрики очереди отложенных уведомлений в текстовом формате Prometheus
+func (q *DelayQueue) WritePrometheus(w io.Writer) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	pending, err := q.client.ZCard(ctx, q.config.Key).Result()
+	if err != nil {
+		return fmt.Errorf("failed to count delayed notifications: %w", err)
+	}
+	due, err := q.client.ZCount(ctx, q.config.Key, "-inf", fmt.Sprint(time.Now().UnixMilli())).Result()
+	if err != nil {
+		return fmt.Errorf("failed to count due delayed notifications: %w", err)
+	}
+
+	var b strings.Builder
+	b.WriteString("# HELP delayed_notifications_pending Notifications waiting in the delay queue.\n")
+	b.WriteString("# TYPE delayed_notifications_pending gauge\n")
+	fmt.Fprintf(&b, "delayed_notifications_pending %d\n", pending)
+
+	b.WriteString("# HELP delayed_notifications_due Notifications whose delivery time has come but which are not yet published.\n")
+	b.WriteString("# TYPE delayed_notifications_due gauge\n")
+	fmt.Fprintf(&b, "delayed_notifications_due %d\n", due)
+
+	b.WriteString("# HELP delayed_notifications_dispatched_total Delayed notifications published to Kafka.\n")
+	b.WriteString("# TYPE delayed_notifications_dispatched_total counter\n")
+	fmt.Fprintf(&b, "delayed_notifications_dispatched_total %d\n", atomic.LoadUint64(&q.dispatched))
+
+	b.WriteString("# HELP delayed_notifications_failed_total Failed attempts to publish delayed notifications.\n")
+	b.WriteString("# TYPE delayed_notifications_failed_total counter\n")
+	fmt.Fprintf(&b, "delayed_notifications_failed_total %d\n", atomic.LoadUint64(&q.failed))
+
+	_, err = io.WriteString(w, b.String())
+	return err
+}
@@ -10,13 +10,33 @@ const (
 	EventTypeTaskUpdated          = "task_updated"
 	EventTypeTaskAssigned         = "task_assigned"
 	EventTypeTaskCommented        = "task_commented"
+	EventTypeTaskMoved            = "task_moved"
+	EventTypeTaskDeleted          = "task_deleted"
 	EventTypeProjectCreated       = "project_created"
 	EventTypeProjectUpdated       = "project_updated"
+	EventTypeProjectArchived      = "project_archived"
+	EventTypeProjectRestored      = "project_restored"
+	EventTypeProjectDeleted       = "project_deleted"
 	EventTypeProjectMemberAdded   = "project_member_added"
 	EventTypeProjectMemberRemoved = "project_member_removed"
 	EventTypeNotification         = "notification"
 )
 
+// Уровни приоритета уведомлений. Уведомления каждого уровня публикуются в свой топик
+// и читаются отдельной группой потребителей, чтобы срочные не ждали за дайджестами
+const (
+	NotificationPriorityCritical = "critical"
+	NotificationPriorityNormal   = "normal"
+	NotificationPriorityDigest   = "digest"
+)
+
+// NotificationPriorities перечисляет уровни приоритета уведомлений от самого срочного
+var NotificationPriorities = []string{
+	NotificationPriorityCritical,
+	NotificationPriorityNormal,
+	NotificationPriorityDigest,
+}
+
 // Event представляет базовое событие
 type Event struct {
 	Type      string    `json:"type"`
@@ -32,6 +52,7 @@ type TaskEvent struct {
 	Status      string                 `json:"status"`
 	Priority    string                 `json:"priority"`
 	AssigneeID  *string                `json:"assignee_id,omitempty"`
+	AssigneeIDs []string               `json:"assignee_ids,omitempty"`
 	CreatedBy   string                 `json:"created_by,omitempty"`
 	AssignerID  string                 `json:"assigner_id,omitempty"`
 	DueDate     *time.Time             `json:"due_date,omitempty"`
@@ -76,14 +97,39 @@ type ProjectMemberEvent struct {
 	Type        string    `json:"type"`
 }
 
-// NotificationEvent представляет событие уведомления
+// NotificationEvent представляет событие уведомления.
+// Для рассылки всем участникам проекта указывается RecipientProjectID: список получателей
+// раскрывается в сервисе уведомлений, и одно сообщение заменяет отдельное событие на каждого участника
 type NotificationEvent struct {
-	UserIDs    []string          `json:"user_ids"`
-	Title      string            `json:"title"`
-	Content    string            `json:"content"`
-	Type       string            `json:"type"`
-	EntityID   string            `json:"entity_id"`
-	EntityType string            `json:"entity_type"`
-	CreatedAt  time.Time         `json:"created_at"`
-	MetaData   map[string]string `json:"meta_data,omitempty"`
+	UserIDs            []string          `json:"user_ids"`
+	RecipientProjectID string            `json:"recipient_project_id,omitempty"` // Получатели - все участники проекта
+	ExcludeUserIDs     []string          `json:"exclude_user_ids,omitempty"`     // Исключаемые из рассылки пользователи
+	Persist            bool              `json:"persist,omitempty"`              // Сервис уведомлений сохраняет уведомления пакетом
+	Title              string            `json:"title"`
+	Content            string            `json:"content"`
+	Contents           map[string]string `json:"contents,omitempty"` // Индивидуальное содержимое по ID получателя
+	Type               string            `json:"type"`
+	EntityID           string            `json:"entity_id"`
+	EntityType         string            `json:"entity_type"`
+	CreatedAt          time.Time         `json:"created_at"`
+	MetaData           map[string]string `json:"meta_data,omitempty"`
+	Priority           string            `json:"priority,omitempty"` // Уровень приоритета; если не задан, определяется по типу
+}
+
+// ResolvePriority возвращает уровень приоритета уведомления: заданный явно или
+// определенный по типу. Срочными считаются уведомления о просроченных задачах
+func (e *NotificationEvent) ResolvePriority() string {
+	switch e.Priority {
+	case NotificationPriorityCritical, NotificationPriorityNormal, NotificationPriorityDigest:
+		return e.Priority
+	}
+
+	switch e.Type {
+	case "task_overdue":
+		return NotificationPriorityCritical
+	case "digest":
+		return NotificationPriorityDigest
+	default:
+		return NotificationPriorityNormal
+	}
 }
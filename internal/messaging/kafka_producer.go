@@ -3,6 +3,7 @@ package messaging
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
@@ -11,11 +12,23 @@ import (
 	"github.com/segmentio/kafka-go"
 )
 
+// EventListener получает события, публикуемые этим экземпляром приложения
+type EventListener func(ctx context.Context, event interface{})
+
 // KafkaProducer реализует интерфейс продюсера для отправки сообщений в Kafka
 type KafkaProducer struct {
-	writer *kafka.Writer
-	topics map[string]string
-	logger logger.Logger
+	writer    *kafka.Writer
+	brokers   []string
+	topics    map[string]string
+	logger    logger.Logger
+	listeners []EventListener
+	delays    *DelayQueue // Очередь отложенных уведомлений; без нее уведомления публикуются сразу
+	outbox    Outbox      // Таблица исходящих событий для публикации из транзакций, см. WithOutbox
+}
+
+// Outbox сохраняет события в таблицу исходящих событий в транзакции из контекста
+type Outbox interface {
+	Add(ctx context.Context, message *domain.OutboxMessage) error
 }
 
 // NewKafkaProducer создает новый экземпляр KafkaProducer
@@ -31,10 +44,42 @@ func NewKafkaProducer(brokers []string, topics map[string]string, logger logger.
 	}
 
 	return &KafkaProducer{
-		writer: writer,
-		topics: topics,
-		logger: logger,
+		writer:  writer,
+		brokers: brokers,
+		topics:  topics,
+		logger:  logger,
+	}
+}
+
+// AddListener подписывает listener на публикуемые события. Слушатели вызываются до отправки
+// в Kafka, поэтому срабатывают и при недоступности брокера. Подписка выполняется при запуске
+func (p *KafkaProducer) AddListener(listener EventListener) {
+	p.listeners = append(p.listeners, listener)
+}
+
+// SetDelayQueue подключает очередь отложенных уведомлений. Подключение выполняется при запуске
+func (p *KafkaProducer) SetDelayQueue(delays *DelayQueue) {
+	p.delays = delays
+}
+
+// SetOutbox подключает таблицу исходящих событий. Подключение выполняется при запуске
+func (p *KafkaProducer) SetOutbox(outbox Outbox) {
+	p.outbox = outbox
+}
+
+// Ping проверяет, что доступен хотя бы один брокер Kafka
+func (p *KafkaProducer) Ping(ctx context.Context) error {
+	lastErr := errors.New("no brokers configured")
+	for _, broker := range p.brokers {
+		conn, err := kafka.DialContext(ctx, "tcp", broker)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		conn.Close()
+		return nil
 	}
+	return fmt.Errorf("no Kafka brokers available: %w", lastErr)
 }
 
 // Close закрывает соединение с Kafka
@@ -53,6 +98,7 @@ func (p *KafkaProducer) PublishTaskCreated(ctx context.Context, task *TaskEvent)
 		Status:      string(task.Status),
 		Priority:    string(task.Priority),
 		AssigneeID:  task.AssigneeID,
+		AssigneeIDs: task.AssigneeIDs,
 		CreatedBy:   task.CreatedBy,
 		DueDate:     task.DueDate,
 		CreatedAt:   task.CreatedAt,
@@ -66,15 +112,49 @@ func (p *KafkaProducer) PublishTaskCreated(ctx context.Context, task *TaskEvent)
 // PublishTaskUpdated публикует событие об обновлении задачи
 func (p *KafkaProducer) PublishTaskUpdated(ctx context.Context, task *TaskEvent, changes map[string]interface{}) error {
 	event := TaskEvent{
-		ID:         task.ID,
-		Title:      task.Title,
-		ProjectID:  task.ProjectID,
-		Status:     string(task.Status),
-		Priority:   string(task.Priority),
-		AssigneeID: task.AssigneeID,
-		UpdatedAt:  task.UpdatedAt,
-		Type:       EventTypeTaskUpdated,
-		Changes:    changes,
+		ID:          task.ID,
+		Title:       task.Title,
+		ProjectID:   task.ProjectID,
+		Status:      string(task.Status),
+		Priority:    string(task.Priority),
+		AssigneeID:  task.AssigneeID,
+		AssigneeIDs: task.AssigneeIDs,
+		UpdatedAt:   task.UpdatedAt,
+		Type:        EventTypeTaskUpdated,
+		Changes:     changes,
+	}
+
+	return p.publishEvent(ctx, p.topics["task_updated"], task.ID, event)
+}
+
+// PublishTaskMoved публикует событие о переносе задачи в другой проект
+func (p *KafkaProducer) PublishTaskMoved(ctx context.Context, task *TaskEvent, changes map[string]interface{}) error {
+	event := TaskEvent{
+		ID:          task.ID,
+		Title:       task.Title,
+		ProjectID:   task.ProjectID,
+		Status:      string(task.Status),
+		Priority:    string(task.Priority),
+		AssigneeID:  task.AssigneeID,
+		AssigneeIDs: task.AssigneeIDs,
+		UpdatedAt:   task.UpdatedAt,
+		Type:        EventTypeTaskMoved,
+		Changes:     changes,
+	}
+
+	return p.publishEvent(ctx, p.topics["task_updated"], task.ID, event)
+}
+
+// PublishTaskDeleted публикует событие об удалении задачи
+func (p *KafkaProducer) PublishTaskDeleted(ctx context.Context, task *TaskEvent) error {
+	event := TaskEvent{
+		ID:        task.ID,
+		Title:     task.Title,
+		ProjectID: task.ProjectID,
+		Status:    string(task.Status),
+		Priority:  string(task.Priority),
+		UpdatedAt: task.UpdatedAt,
+		Type:      EventTypeTaskDeleted,
 	}
 
 	return p.publishEvent(ctx, p.topics["task_updated"], task.ID, event)
@@ -83,15 +163,16 @@ func (p *KafkaProducer) PublishTaskUpdated(ctx context.Context, task *TaskEvent,
 // PublishTaskAssigned публикует событие о назначении задачи
 func (p *KafkaProducer) PublishTaskAssigned(ctx context.Context, task *domain.Task, assignerID string) error {
 	event := TaskEvent{
-		ID:         task.ID,
-		Title:      task.Title,
-		ProjectID:  task.ProjectID,
-		Status:     string(task.Status),
-		Priority:   string(task.Priority),
-		AssigneeID: task.AssigneeID,
-		UpdatedAt:  task.UpdatedAt,
-		Type:       EventTypeTaskAssigned,
-		AssignerID: assignerID,
+		ID:          task.ID,
+		Title:       task.Title,
+		ProjectID:   task.ProjectID,
+		Status:      string(task.Status),
+		Priority:    string(task.Priority),
+		AssigneeID:  task.AssigneeID,
+		AssigneeIDs: task.AssigneeIDs,
+		UpdatedAt:   task.UpdatedAt,
+		Type:        EventTypeTaskAssigned,
+		AssignerID:  assignerID,
 	}
 
 	return p.publishEvent(ctx, p.topics["task_assigned"], task.ID, event)
@@ -142,6 +223,47 @@ func (p *KafkaProducer) PublishProjectUpdated(ctx context.Context, project *Proj
 	return p.publishEvent(ctx, p.topics["project_updated"], project.ID, event)
 }
 
+// PublishProjectArchived публикует событие об архивировании проекта
+func (p *KafkaProducer) PublishProjectArchived(ctx context.Context, project *ProjectEvent, changes map[string]interface{}) error {
+	event := ProjectEvent{
+		ID:        project.ID,
+		Name:      project.Name,
+		Status:    string(project.Status),
+		UpdatedAt: project.UpdatedAt,
+		Type:      EventTypeProjectArchived,
+		Changes:   changes,
+	}
+
+	return p.publishEvent(ctx, p.topics["project_updated"], project.ID, event)
+}
+
+// PublishProjectRestored публикует событие о восстановлении проекта из архива
+func (p *KafkaProducer) PublishProjectRestored(ctx context.Context, project *ProjectEvent, changes map[string]interface{}) error {
+	event := ProjectEvent{
+		ID:        project.ID,
+		Name:      project.Name,
+		Status:    string(project.Status),
+		UpdatedAt: project.UpdatedAt,
+		Type:      EventTypeProjectRestored,
+		Changes:   changes,
+	}
+
+	return p.publishEvent(ctx, p.topics["project_updated"], project.ID, event)
+}
+
+// PublishProjectDeleted публикует событие об удалении проекта
+func (p *KafkaProducer) PublishProjectDeleted(ctx context.Context, project *ProjectEvent) error {
+	event := ProjectEvent{
+		ID:        project.ID,
+		Name:      project.Name,
+		Status:    string(project.Status),
+		UpdatedAt: project.UpdatedAt,
+		Type:      EventTypeProjectDeleted,
+	}
+
+	return p.publishEvent(ctx, p.topics["project_updated"], project.ID, event)
+}
+
 // PublishProjectMemberAdded публикует событие о добавлении участника в проект
 func (p *KafkaProducer) PublishProjectMemberAdded(ctx context.Context, projectID, projectName string, member *ProjectMemberEvent) error {
 	event := ProjectMemberEvent{
@@ -172,9 +294,36 @@ func (p *KafkaProducer) PublishProjectMemberRemoved(ctx context.Context, member
 	return p.publishEvent(ctx, p.topics["project_member_removed"], member.UserID, event)
 }
 
-// PublishNotification публикует уведомление
+// PublishNotification публикует уведомление в топик его уровня приоритета
 func (p *KafkaProducer) PublishNotification(ctx context.Context, notification *NotificationEvent) error {
-	return p.publishEvent(ctx, p.topics["notifications"], notification.EntityID, notification)
+	notification.Priority = notification.ResolvePriority()
+	return p.publishEvent(ctx, p.topics[NotificationTopicKey(notification.Priority)], notification.EntityID, notification)
+}
+
+// PublishNotificationAt публикует уведомление в момент at: до него уведомление ждет
+// в очереди отложенных уведомлений. Наступившие уведомления публикуются сразу
+func (p *KafkaProducer) PublishNotificationAt(ctx context.Context, notification *NotificationEvent, at time.Time) error {
+	if p.delays == nil || !at.After(time.Now()) {
+		return p.PublishNotification(ctx, notification)
+	}
+	return p.delays.Schedule(ctx, notification, at)
+}
+
+// PublishNotificationAfter публикует уведомление через delay, например для напоминаний
+func (p *KafkaProducer) PublishNotificationAfter(ctx context.Context, notification *NotificationEvent, delay time.Duration) error {
+	return p.PublishNotificationAt(ctx, notification, time.Now().Add(delay))
+}
+
+// NotificationTopicKey возвращает ключ топика уведомлений уровня приоритета в списке топиков продюсера
+func NotificationTopicKey(priority string) string {
+	switch priority {
+	case NotificationPriorityCritical:
+		return "notifications_critical"
+	case NotificationPriorityDigest:
+		return "notifications_digest"
+	default:
+		return "notifications"
+	}
 }
 
 // AddEnsureTopicsMethod добавьте этот метод в файл с KafkaProducer
@@ -223,9 +372,20 @@ func (p *KafkaProducer) EnsureTopicsExist(ctx context.Context, topics []string)
 	return nil
 }
 
+// PublishMessage публикует в Kafka событие из таблицы исходящих событий
+func (p *KafkaProducer) PublishMessage(ctx context.Context, message *domain.OutboxMessage) error {
+	return p.write(ctx, message.Topic, message.Key, message.Payload)
+}
+
 // Вспомогательный метод для публикации событий
 
+// publishEvent публикует событие в Kafka или, если контекст создан WithOutbox, записывает
+// его в таблицу исходящих событий в транзакции вызывающего кода
 func (p *KafkaProducer) publishEvent(ctx context.Context, topic, key string, event interface{}) error {
+	for _, listener := range p.listeners {
+		listener(ctx, event)
+	}
+
 	value, err := json.Marshal(event)
 	if err != nil {
 		p.logger.Error("Failed to marshal event", err, map[string]interface{}{
@@ -235,10 +395,22 @@ func (p *KafkaProducer) publishEvent(ctx context.Context, topic, key string, eve
 		return fmt.Errorf("failed to marshal event: %w", err)
 	}
 
+	if p.outbox != nil && usesOutbox(ctx) {
+		if err := p.outbox.Add(ctx, &domain.OutboxMessage{Topic: topic, Key: key, Payload: value}); err != nil {
+			return fmt.Errorf("failed to add event to outbox: %w", err)
+		}
+		return nil
+	}
+
+	return p.write(ctx, topic, key, value)
+}
+
+// write отправляет сообщение в топик Kafka
+func (p *KafkaProducer) write(ctx context.Context, topic, key string, value []byte) error {
 	p.writer.Topic = topic
 
 	start := time.Now()
-	err = p.writer.WriteMessages(ctx,
+	err := p.writer.WriteMessages(ctx,
 		kafka.Message{
 			Key:   []byte(key),
 			Value: value,
@@ -0,0 +1,125 @@
+package messaging
+
+import (
+	"context"
+	"time"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/internal/repository"
+	"github.com/nurlyy/task_manager/pkg/config"
+	"github.com/nurlyy/task_manager/pkg/logger"
+)
+
+// outboxKey - ключ признака публикации через таблицу исходящих событий в контексте
+type outboxKey struct{}
+
+// WithOutbox возвращает контекст, события которого продюсер записывает в таблицу исходящих
+// событий вместо отправки в Kafka. Вызывается внутри TxManager.WithinTx: событие сохраняется
+// в той же транзакции, что и изменение данных, и публикуется ретранслятором после фиксации.
+// Слушатели продюсера получают событие сразу, а потребители Kafka - после публикации
+func WithOutbox(ctx context.Context) context.Context {
+	return context.WithValue(ctx, outboxKey{}, true)
+}
+
+// usesOutbox сообщает, нужно ли записать события контекста в таблицу исходящих событий
+func usesOutbox(ctx context.Context) bool {
+	enabled, _ := ctx.Value(outboxKey{}).(bool)
+	return enabled
+}
+
+// OutboxRelay публикует в Kafka события из таблицы исходящих событий. Событие удаляется
+// только после публикации, поэтому при сбое оно будет опубликовано повторно. Несколько
+// ретрансляторов не публикуют одно событие дважды: события блокируются на время публикации
+type OutboxRelay struct {
+	outboxRepo repository.OutboxRepository
+	txManager  repository.TxManager
+	config     *config.OutboxConfig
+	logger     logger.Logger
+}
+
+// NewOutboxRelay создает новый экземпляр OutboxRelay
+func NewOutboxRelay(outboxRepo repository.OutboxRepository, txManager repository.TxManager, config *config.OutboxConfig, logger logger.Logger) *OutboxRelay {
+	return &OutboxRelay{
+		outboxRepo: outboxRepo,
+		txManager:  txManager,
+		config:     config,
+		logger:     logger,
+	}
+}
+
+// Run периодически публикует накопившиеся события до отмены контекста
+func (r *OutboxRelay) Run(ctx context.Context, producer *KafkaProducer) {
+	r.logger.Info("Starting outbox relay", map[string]interface{}{
+		"poll_interval": r.config.PollInterval.String(),
+	})
+
+	ticker := time.NewTicker(r.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			r.logger.Info("Outbox relay stopped")
+			return
+		case <-ticker.C:
+			r.relayPending(ctx, producer)
+		}
+	}
+}
+
+// relayPending публикует накопившиеся события порциями
+func (r *OutboxRelay) relayPending(ctx context.Context, producer *KafkaProducer) {
+	for ctx.Err() == nil {
+		claimed, err := r.relayBatch(ctx, producer)
+		if err != nil {
+			if ctx.Err() == nil {
+				r.logger.Error("Failed to relay outbox messages", err)
+			}
+			return
+		}
+		if claimed < r.config.BatchSize {
+			return
+		}
+	}
+}
+
+// relayBatch публикует порцию событий в одной транзакции и возвращает число забранных событий.
+// Публикация останавливается на первой ошибке, чтобы следующие события не обгоняли
+// неопубликованное; порция с ошибкой считается последней
+func (r *OutboxRelay) relayBatch(ctx context.Context, producer *KafkaProducer) (int, error) {
+	claimed := 0
+	err := r.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		messages, err := r.outboxRepo.ClaimPending(ctx, r.config.BatchSize, r.config.MaxAttempts)
+		if err != nil {
+			return err
+		}
+		claimed = len(messages)
+
+		published := make([]int64, 0, len(messages))
+		var failed *domain.OutboxMessage
+		var publishErr error
+		for _, message := range messages {
+			if publishErr = producer.PublishMessage(ctx, message); publishErr != nil {
+				failed = message
+				break
+			}
+			published = append(published, message.ID)
+		}
+
+		if err := r.outboxRepo.Delete(ctx, published); err != nil {
+			return err
+		}
+		if failed != nil {
+			r.logger.Warn("Failed to publish outbox message, will retry", map[string]interface{}{
+				"id":    failed.ID,
+				"topic": failed.Topic,
+			}, map[string]interface{}{
+				"error": publishErr,
+			})
+			claimed = 0
+			return r.outboxRepo.Fail(ctx, failed.ID, publishErr.Error())
+		}
+		return nil
+	})
+	return claimed, err
+}
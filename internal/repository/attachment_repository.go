@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+)
+
+// AttachmentRepository определяет интерфейс для работы с вложениями задач и комментариев
+type AttachmentRepository interface {
+	// Create сохраняет метаданные вложения
+	Create(ctx context.Context, attachment *domain.Attachment) error
+
+	// GetByID возвращает вложение или nil, если его нет
+	GetByID(ctx context.Context, id string) (*domain.Attachment, error)
+
+	// ListByTask возвращает вложения задачи, включая вложения ее комментариев, по времени загрузки
+	ListByTask(ctx context.Context, taskID string) ([]*domain.Attachment, error)
+
+	// ListByComment возвращает вложения комментария по времени загрузки
+	ListByComment(ctx context.Context, commentID string) ([]*domain.Attachment, error)
+
+	// Delete удаляет вложение; файл ставится в очередь удаления из хранилища.
+	// Возвращает false, если вложения не было
+	Delete(ctx context.Context, id string) (bool, error)
+
+	// ListPendingDeletions возвращает ключи файлов из очереди удаления
+	ListPendingDeletions(ctx context.Context, limit int) ([]string, error)
+
+	// CompleteDeletion убирает ключ из очереди удаления после удаления файла
+	CompleteDeletion(ctx context.Context, storageKey string) error
+
+	// FailDeletion фиксирует неудачную попытку удаления файла
+	FailDeletion(ctx context.Context, storageKey string, errMsg string) error
+}
@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+)
+
+// AuditRepository определяет интерфейс для работы с журналом аудита
+type AuditRepository interface {
+	// Create сохраняет записи журнала одним запросом
+	Create(ctx context.Context, records []*domain.AuditRecord) error
+
+	// List возвращает записи с фильтрацией, начиная с последних
+	List(ctx context.Context, filter AuditFilter) ([]*domain.AuditRecord, error)
+
+	// Count возвращает количество записей с фильтрацией
+	Count(ctx context.Context, filter AuditFilter) (int, error)
+}
+
+// AuditFilter содержит параметры для фильтрации журнала аудита
+type AuditFilter struct {
+	ActorID    *string
+	Action     *domain.AuditAction
+	EntityType *string
+	EntityID   *string
+	From       *time.Time
+	To         *time.Time
+	Limit      int
+	Offset     int
+}
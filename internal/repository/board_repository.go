@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+)
+
+// BoardRepository определяет интерфейс для работы с колонками канбан-досок проектов
+type BoardRepository interface {
+	// SeedColumns создает колонки доски проекта, если у проекта еще нет ни одной колонки
+	SeedColumns(ctx context.Context, projectID string, columns []*domain.ProjectBoardColumn) error
+
+	// CreateColumn создает колонку в конце доски
+	CreateColumn(ctx context.Context, column *domain.ProjectBoardColumn) error
+
+	// GetColumn возвращает колонку по ID (nil, если колонка не найдена)
+	GetColumn(ctx context.Context, id string) (*domain.ProjectBoardColumn, error)
+
+	// ListColumns возвращает колонки доски проекта по порядку
+	ListColumns(ctx context.Context, projectID string) ([]*domain.ProjectBoardColumn, error)
+
+	// UpdateColumn обновляет название и статус колонки. Задачи, перенесенные в колонку,
+	// возвращаются в колонки своих статусов, если колонке назначен статус
+	UpdateColumn(ctx context.Context, column *domain.ProjectBoardColumn) error
+
+	// DeleteColumn удаляет колонку; перенесенные в нее задачи возвращаются в колонки своих статусов
+	DeleteColumn(ctx context.Context, id string) error
+
+	// ReorderColumns расставляет колонки проекта в указанном порядке
+	ReorderColumns(ctx context.Context, projectID string, columnIDs []string) error
+
+	// ListPlacements возвращает колонки без статуса, в которые перенесены задачи проекта, по ID задач
+	ListPlacements(ctx context.Context, projectID string) (map[string]string, error)
+
+	// SetPlacement переносит задачу в колонку без статуса
+	SetPlacement(ctx context.Context, taskID, columnID string) error
+
+	// DeletePlacement возвращает задачу в колонку ее статуса
+	DeletePlacement(ctx context.Context, taskID string) error
+}
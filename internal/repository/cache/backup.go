@@ -0,0 +1,35 @@
+package cache
+
+// StateKeyPatterns возвращает шаблоны ключей Redis с состоянием, которое не восстанавливается
+// из базы данных: режим обслуживания, флаги функций, контрольная точка рассылки дайджестов,
+// счетчики доступности, черновики комментариев и просмотры задач, еще не перенесенные в базу.
+// Эти ключи сохраняются в резервной копии
+func StateKeyPatterns() []string {
+	return []string{
+		keyMaintenance,
+		keyFeatureFlags,
+		keyDigestCheckpoint,
+		keyPrefixStatusUptime + "*",
+		keyPrefixCommentDraft + "*",
+		keyPrefixTaskViews + "*",
+		keyTaskViewsDirty,
+	}
+}
+
+// CacheKeyPatterns возвращает шаблоны ключей Redis с данными, производными от базы данных.
+// После восстановления базы они удаляются и заполняются заново при обращении
+func CacheKeyPatterns() []string {
+	return []string{
+		keyPrefixUser + "*",
+		keyPrefixTask + "*",
+		keyTaskListIndex,
+		keyPrefixProject + "*",
+		keyPrefixNotifications + "*",
+		keyPrefixUnreadCount + "*",
+		keyPrefixNotifySettings + "*",
+		keyPrefixMemberRole + "*",
+		keyPrefixHTTPResponse + "*",
+		keyPrefixSurrogateKey + "*",
+		keyConsistencyReport,
+	}
+}
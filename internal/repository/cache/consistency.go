@@ -0,0 +1,92 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+)
+
+// keyConsistencyReport - ключ отчета о последней проверке согласованности
+const keyConsistencyReport = "consistency:report"
+
+// ListCachedTaskVersions возвращает время изменения задач, сохраненных в кэше, по их ID
+func (r *RedisRepository) ListCachedTaskVersions(ctx context.Context) (map[string]time.Time, error) {
+	return r.listCachedVersions(ctx, keyPrefixTask)
+}
+
+// ListCachedProjectVersions возвращает время изменения проектов, сохраненных в кэше, по их ID
+func (r *RedisRepository) ListCachedProjectVersions(ctx context.Context) (map[string]time.Time, error) {
+	return r.listCachedVersions(ctx, keyPrefixProject)
+}
+
+// InvalidateTasks удаляет задачи с указанными ID из кэша
+func (r *RedisRepository) InvalidateTasks(ctx context.Context, ids []string) error {
+	return r.DeleteMany(ctx, prefixKeys(keyPrefixTask, ids))
+}
+
+// InvalidateProjects удаляет проекты с указанными ID из кэша
+func (r *RedisRepository) InvalidateProjects(ctx context.Context, ids []string) error {
+	return r.DeleteMany(ctx, prefixKeys(keyPrefixProject, ids))
+}
+
+// SaveConsistencyReport сохраняет отчет о последней проверке согласованности
+func (r *RedisRepository) SaveConsistencyReport(ctx context.Context, report *domain.ConsistencyReport) error {
+	return r.cacheValueWithTTL(ctx, keyConsistencyReport, report, 0)
+}
+
+// GetConsistencyReport возвращает отчет о последней проверке согласованности
+func (r *RedisRepository) GetConsistencyReport(ctx context.Context) (*domain.ConsistencyReport, error) {
+	var report domain.ConsistencyReport
+	if err := r.getValue(ctx, keyConsistencyReport, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// listCachedVersions перебирает сущности в кэше с указанным префиксом и возвращает
+// их поле updated_at. Ключи вложенных данных (списки, блокировки) пропускаются
+func (r *RedisRepository) listCachedVersions(ctx context.Context, prefix string) (map[string]time.Time, error) {
+	versions := make(map[string]time.Time)
+	err := r.scanKeys(ctx, prefix+"*", func(keys []string) error {
+		entityKeys := keys[:0]
+		for _, key := range keys {
+			if !strings.Contains(strings.TrimPrefix(key, prefix), ":") {
+				entityKeys = append(entityKeys, key)
+			}
+		}
+
+		values, err := r.GetMany(ctx, entityKeys)
+		if err != nil {
+			return err
+		}
+		for i, value := range values {
+			if value == nil {
+				continue
+			}
+			var entity struct {
+				UpdatedAt time.Time `json:"updated_at"`
+			}
+			// Нечитаемая запись считается устаревшей и будет удалена при исправлении
+			_ = json.Unmarshal(value, &entity)
+			versions[strings.TrimPrefix(entityKeys[i], prefix)] = entity.UpdatedAt
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cached versions: %w", err)
+	}
+	return versions, nil
+}
+
+// prefixKeys добавляет префикс к идентификаторам
+func prefixKeys(prefix string, ids []string) []string {
+	keys := make([]string, 0, len(ids))
+	for _, id := range ids {
+		keys = append(keys, prefix+id)
+	}
+	return keys
+}
@@ -0,0 +1,152 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/nurlyy/task_manager/internal/messaging"
+)
+
+// Префиксы ключей HTTP-кэша ответов
+const (
+	keyPrefixHTTPResponse = "http:response:"
+	keyPrefixSurrogateKey = "http:surrogate:"
+)
+
+// surrogateKeyTTL - время жизни множеств ключей ответов по суррогатным ключам.
+// Оно заведомо больше времени жизни самих ответов, поэтому множество не исчезает раньше них
+const surrogateKeyTTL = 24 * time.Hour
+
+// SurrogateKeyHeader - заголовок, в котором обработчик может указать через пробел
+// дополнительные суррогатные ключи ответа. Клиенту заголовок не передается
+const SurrogateKeyHeader = "Surrogate-Key"
+
+// SurrogateKeySearch помечает результаты поиска: они зависят от всех задач и проектов
+const SurrogateKeySearch = "search"
+
+// ProjectSurrogateKey возвращает суррогатный ключ ответов, построенных по данным проекта
+func ProjectSurrogateKey(projectID string) string {
+	return "project:" + projectID
+}
+
+// RoadmapSurrogateKey возвращает суррогатный ключ опубликованной дорожной карты
+func RoadmapSurrogateKey(publicKey string) string {
+	return "roadmap:" + publicKey
+}
+
+// CachedResponse представляет ответ, сохраненный HTTP-кэшем
+type CachedResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header"`
+	Body       []byte      `json:"body"`
+	StoredAt   time.Time   `json:"stored_at"`
+	FreshUntil time.Time   `json:"fresh_until"`
+}
+
+// IsFresh проверяет, можно ли отдать ответ без обновления
+func (c *CachedResponse) IsFresh(now time.Time) bool {
+	return now.Before(c.FreshUntil)
+}
+
+// GetHTTPResponse получает сохраненный ответ
+func (r *RedisRepository) GetHTTPResponse(ctx context.Context, key string) (*CachedResponse, error) {
+	var response CachedResponse
+	if err := r.getValue(ctx, keyPrefixHTTPResponse+key, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// SetHTTPResponse сохраняет ответ на ttl и связывает его с суррогатными ключами,
+// чтобы при изменении данных его можно было удалить через PurgeSurrogateKeys
+func (r *RedisRepository) SetHTTPResponse(ctx context.Context, key string, response *CachedResponse, surrogateKeys []string, ttl time.Duration) error {
+	if err := r.cacheValueWithTTL(ctx, keyPrefixHTTPResponse+key, response, ttl); err != nil {
+		return err
+	}
+
+	pipe := r.client.TxPipeline()
+	for _, surrogateKey := range surrogateKeys {
+		pipe.SAdd(ctx, keyPrefixSurrogateKey+surrogateKey, key)
+		pipe.Expire(ctx, keyPrefixSurrogateKey+surrogateKey, surrogateKeyTTL)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		r.logger.Error("Failed to tag cached response", err, map[string]interface{}{
+			"key": key,
+		})
+		return fmt.Errorf("failed to tag cached response: %w", err)
+	}
+	return nil
+}
+
+// PurgeSurrogateKeys удаляет все ответы, связанные с суррогатными ключами
+func (r *RedisRepository) PurgeSurrogateKeys(ctx context.Context, surrogateKeys ...string) error {
+	for _, surrogateKey := range surrogateKeys {
+		setKey := keyPrefixSurrogateKey + surrogateKey
+		members, err := r.client.SMembers(ctx, setKey).Result()
+		if err != nil {
+			r.logger.Error("Failed to get cached responses by surrogate key", err, map[string]interface{}{
+				"surrogate_key": surrogateKey,
+			})
+			return fmt.Errorf("failed to get cached responses by surrogate key: %w", err)
+		}
+
+		keys := make([]string, 0, len(members)+1)
+		for _, member := range members {
+			keys = append(keys, keyPrefixHTTPResponse+member)
+		}
+		keys = append(keys, setKey)
+		if err := r.DeleteMany(ctx, keys); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PurgeEvent удаляет ответы HTTP-кэша, построенные по данным, которые изменило событие.
+// Подключается к продюсеру событий, поэтому ошибки только логируются
+func (r *RedisRepository) PurgeEvent(ctx context.Context, event interface{}) {
+	surrogateKeys := eventSurrogateKeys(event)
+	if len(surrogateKeys) == 0 {
+		return
+	}
+
+	if err := r.PurgeSurrogateKeys(ctx, surrogateKeys...); err != nil {
+		r.logger.Warn("Failed to purge cached responses", map[string]interface{}{
+			"surrogate_keys": surrogateKeys,
+		}, map[string]interface{}{
+			"error": err,
+		})
+	}
+}
+
+// eventSurrogateKeys возвращает суррогатные ключи ответов, зависящих от данных события.
+// Любое изменение задач, проектов и их участников сбрасывает и результаты поиска
+func eventSurrogateKeys(event interface{}) []string {
+	var projectIDs []string
+	switch e := event.(type) {
+	case messaging.TaskEvent:
+		projectIDs = append(projectIDs, e.ProjectID)
+		// При переносе задачи меняются метрики и исходного проекта
+		if change, ok := e.Changes["project_id"].(map[string]interface{}); ok {
+			if oldID, ok := change["old"].(string); ok {
+				projectIDs = append(projectIDs, oldID)
+			}
+		}
+	case messaging.ProjectEvent:
+		projectIDs = append(projectIDs, e.ID)
+	case messaging.ProjectMemberEvent:
+		projectIDs = append(projectIDs, e.ProjectID)
+	default:
+		return nil
+	}
+
+	surrogateKeys := []string{SurrogateKeySearch}
+	for _, projectID := range projectIDs {
+		if projectID != "" {
+			surrogateKeys = append(surrogateKeys, ProjectSurrogateKey(projectID))
+		}
+	}
+	return surrogateKeys
+}
@@ -0,0 +1,69 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nurlyy/task_manager/internal/messaging"
+)
+
+// InvalidateEvent удаляет из кэша данные, которые изменило событие: задачи, проекты
+// с участниками, членство участников, списки задач и зависящие от них ответы HTTP-кэша.
+// Вызывается продюсером событий и потребителем событий других процессов, поэтому ошибки только логируются
+func (r *RedisRepository) InvalidateEvent(ctx context.Context, event interface{}) {
+	keys, taskLists := eventCacheKeys(event)
+	if len(keys) == 0 {
+		return
+	}
+
+	if err := r.DeleteMany(ctx, keys); err != nil {
+		r.logger.Warn("Failed to invalidate cached entities", map[string]interface{}{
+			"keys": keys,
+		}, map[string]interface{}{
+			"error": err,
+		})
+	}
+
+	if taskLists {
+		if err := r.InvalidateTaskLists(ctx); err != nil {
+			r.logger.Warn("Failed to invalidate cached task lists", map[string]interface{}{
+				"error": err,
+			})
+		}
+	}
+
+	r.PurgeEvent(ctx, event)
+}
+
+// eventCacheKeys возвращает ключи кэша, зависящие от данных события, и признак того,
+// что событие меняет списки задач. Ответ проекта содержит его участников и метрики,
+// поэтому изменения задач тоже сбрасывают проект
+func eventCacheKeys(event interface{}) ([]string, bool) {
+	switch e := event.(type) {
+	case messaging.TaskEvent:
+		keys := []string{keyPrefixTask + e.ID}
+		if e.ProjectID != "" {
+			keys = append(keys, keyPrefixProject+e.ProjectID)
+		}
+		// При переносе задачи меняются метрики и исходного проекта
+		if change, ok := e.Changes["project_id"].(map[string]interface{}); ok {
+			if oldID, ok := change["old"].(string); ok && oldID != "" {
+				keys = append(keys, keyPrefixProject+oldID)
+			}
+		}
+		return keys, true
+	case messaging.CommentEvent:
+		// Подробности задачи содержат страницу комментариев и их число
+		return []string{keyPrefixTask + e.TaskID}, false
+	case messaging.ProjectEvent:
+		return []string{keyPrefixProject + e.ID, keyPrefixProjectMembers + e.ID}, false
+	case messaging.ProjectMemberEvent:
+		return []string{
+			keyPrefixProject + e.ProjectID,
+			keyPrefixProjectMembers + e.ProjectID,
+			fmt.Sprintf("%s%s:%s", keyPrefixMemberRole, e.ProjectID, e.UserID),
+		}, false
+	default:
+		return nil, false
+	}
+}
@@ -0,0 +1,269 @@
+package cache
+
+import (
+	"context"
+	"errors"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/internal/repository"
+	"github.com/nurlyy/task_manager/pkg/logger"
+)
+
+// CountingNotificationRepository поддерживает счетчики непрочитанных уведомлений в Redis
+// при изменении уведомлений через вложенный репозиторий. Счетчик загружается из базы
+// при первом чтении и дальше изменяется атомарно; расхождения, возникшие из-за сбоев
+// Redis или гонки с загрузкой, исправляет периодическая сверка. Каждое изменение счетчика
+// публикуется в Redis, чтобы открытые клиенты обновляли значок без опроса
+type CountingNotificationRepository struct {
+	repository.NotificationRepository
+	cache  *RedisRepository
+	logger logger.Logger
+}
+
+// NewCountingNotificationRepository создает новый экземпляр CountingNotificationRepository
+func NewCountingNotificationRepository(repo repository.NotificationRepository, cache *RedisRepository, logger logger.Logger) *CountingNotificationRepository {
+	return &CountingNotificationRepository{
+		NotificationRepository: repo,
+		cache:                  cache,
+		logger:                 logger,
+	}
+}
+
+// Create создает новое уведомление и увеличивает счетчик непрочитанных
+func (r *CountingNotificationRepository) Create(ctx context.Context, notification *domain.Notification) error {
+	if err := r.NotificationRepository.Create(ctx, notification); err != nil {
+		return err
+	}
+
+	if notification.Status == domain.NotificationStatusUnread {
+		r.adjust(ctx, notification.UserID, 1)
+	}
+
+	return nil
+}
+
+// CreateBatch создает несколько уведомлений и увеличивает счетчики их получателей
+func (r *CountingNotificationRepository) CreateBatch(ctx context.Context, notifications []*domain.Notification) error {
+	if err := r.NotificationRepository.CreateBatch(ctx, notifications); err != nil {
+		return err
+	}
+
+	deltas := make(map[string]int)
+	for _, notification := range notifications {
+		if notification.Status == domain.NotificationStatusUnread {
+			deltas[notification.UserID]++
+		}
+	}
+	for userID, delta := range deltas {
+		r.adjust(ctx, userID, delta)
+	}
+
+	return nil
+}
+
+// Update обновляет уведомление. Статус мог измениться, поэтому счетчик сбрасывается
+func (r *CountingNotificationRepository) Update(ctx context.Context, notification *domain.Notification) error {
+	if err := r.NotificationRepository.Update(ctx, notification); err != nil {
+		return err
+	}
+
+	r.invalidate(ctx, notification.UserID)
+
+	return nil
+}
+
+// Delete удаляет уведомление и уменьшает счетчик, если оно было непрочитанным
+func (r *CountingNotificationRepository) Delete(ctx context.Context, id string) error {
+	notification, _ := r.NotificationRepository.GetByID(ctx, id)
+
+	if err := r.NotificationRepository.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	if notification != nil && notification.Status == domain.NotificationStatusUnread {
+		r.adjust(ctx, notification.UserID, -1)
+	}
+
+	return nil
+}
+
+// MarkAsRead отмечает уведомление как прочитанное и уменьшает счетчик
+func (r *CountingNotificationRepository) MarkAsRead(ctx context.Context, id string) error {
+	notification, _ := r.NotificationRepository.GetByID(ctx, id)
+
+	if err := r.NotificationRepository.MarkAsRead(ctx, id); err != nil {
+		return err
+	}
+
+	if notification != nil && notification.Status == domain.NotificationStatusUnread {
+		r.adjust(ctx, notification.UserID, -1)
+	}
+
+	return nil
+}
+
+// MarkAllAsRead отмечает все уведомления пользователя как прочитанные.
+// Счетчик сбрасывается, а не обнуляется, чтобы не потерять параллельно созданные уведомления
+func (r *CountingNotificationRepository) MarkAllAsRead(ctx context.Context, userID string) error {
+	if err := r.NotificationRepository.MarkAllAsRead(ctx, userID); err != nil {
+		return err
+	}
+
+	r.invalidate(ctx, userID)
+
+	return nil
+}
+
+// MarkAsReadByFilter отмечает прочитанными уведомления пользователя по фильтру и уменьшает счетчик
+func (r *CountingNotificationRepository) MarkAsReadByFilter(ctx context.Context, userID string, filter repository.NotificationFilter) (int, error) {
+	count, err := r.NotificationRepository.MarkAsReadByFilter(ctx, userID, filter)
+	if err != nil {
+		return 0, err
+	}
+
+	if count > 0 {
+		r.adjust(ctx, userID, -count)
+	}
+
+	return count, nil
+}
+
+// DeleteAllByUser удаляет все уведомления пользователя и сбрасывает его счетчик
+func (r *CountingNotificationRepository) DeleteAllByUser(ctx context.Context, userID string) error {
+	if err := r.NotificationRepository.DeleteAllByUser(ctx, userID); err != nil {
+		return err
+	}
+
+	r.invalidate(ctx, userID)
+
+	return nil
+}
+
+// GetUserUnreadCount возвращает количество непрочитанных уведомлений из счетчика,
+// загружая его из базы при отсутствии
+func (r *CountingNotificationRepository) GetUserUnreadCount(ctx context.Context, userID string) (int, error) {
+	if count, err := r.cache.GetUnreadCount(ctx, userID); err == nil {
+		return count, nil
+	}
+
+	count, err := r.NotificationRepository.GetUserUnreadCount(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := r.cache.InitUnreadCount(ctx, userID, count); err != nil {
+		r.logger.Warn("Failed to cache unread count", map[string]interface{}{
+			"user_id": userID,
+		}, map[string]interface{}{
+			"error": err,
+		})
+	}
+
+	return count, nil
+}
+
+// ReconcileUnreadCounts сверяет счетчики в Redis с базой и удаляет разошедшиеся:
+// они будут загружены заново при следующем чтении. Возвращает число удаленных счетчиков
+func (r *CountingNotificationRepository) ReconcileUnreadCounts(ctx context.Context, batchSize int) (int, error) {
+	_, drifted, err := r.FindDriftedUnreadCounts(ctx, batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, userID := range drifted {
+		// Удаление безопасно и при гонке с изменением счетчика: значение будет перечитано из базы
+		if err := r.cache.InvalidateUnreadCount(ctx, userID); err != nil {
+			return i, err
+		}
+	}
+
+	return len(drifted), nil
+}
+
+// FindDriftedUnreadCounts сверяет счетчики в Redis с базой, не изменяя их.
+// Возвращает число проверенных счетчиков и ID пользователей с разошедшимися счетчиками
+func (r *CountingNotificationRepository) FindDriftedUnreadCounts(ctx context.Context, batchSize int) (int, []string, error) {
+	cached, err := r.cache.ListUnreadCounts(ctx)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	userIDs := make([]string, 0, len(cached))
+	for userID := range cached {
+		userIDs = append(userIDs, userID)
+	}
+
+	var drifted []string
+	for start := 0; start < len(userIDs); start += batchSize {
+		end := start + batchSize
+		if end > len(userIDs) {
+			end = len(userIDs)
+		}
+
+		counts, err := r.NotificationRepository.GetUnreadCounts(ctx, userIDs[start:end])
+		if err != nil {
+			return len(cached), drifted, err
+		}
+
+		for _, userID := range userIDs[start:end] {
+			if cached[userID] != counts[userID] {
+				drifted = append(drifted, userID)
+			}
+		}
+	}
+
+	return len(cached), drifted, nil
+}
+
+// adjust изменяет счетчик пользователя и публикует новое значение. Отсутствующий счетчик
+// загружается из базы; при ошибке Redis счетчик сбрасывается
+func (r *CountingNotificationRepository) adjust(ctx context.Context, userID string, delta int) {
+	count, err := r.cache.IncrUnreadCount(ctx, userID, delta)
+	if errors.Is(err, ErrKeyNotFound) {
+		r.reload(ctx, userID)
+		return
+	}
+	if err != nil {
+		r.invalidate(ctx, userID)
+		return
+	}
+	r.publish(ctx, userID, count)
+}
+
+// invalidate удаляет счетчик пользователя и публикует значение, заново загруженное из базы
+func (r *CountingNotificationRepository) invalidate(ctx context.Context, userID string) {
+	if err := r.cache.InvalidateUnreadCount(ctx, userID); err != nil {
+		r.logger.Warn("Failed to invalidate unread count", map[string]interface{}{
+			"user_id": userID,
+		}, map[string]interface{}{
+			"error": err,
+		})
+	}
+	r.reload(ctx, userID)
+}
+
+// reload загружает счетчик пользователя из базы и публикует его
+func (r *CountingNotificationRepository) reload(ctx context.Context, userID string) {
+	count, err := r.GetUserUnreadCount(ctx, userID)
+	if err != nil {
+		r.logger.Warn("Failed to reload unread count", map[string]interface{}{
+			"user_id": userID,
+		}, map[string]interface{}{
+			"error": err,
+		})
+		return
+	}
+	r.publish(ctx, userID, count)
+}
+
+// publish сообщает подписчикам новое значение счетчика. Ошибка публикации только логируется:
+// клиенты получат актуальное значение при следующем изменении или переподключении
+func (r *CountingNotificationRepository) publish(ctx context.Context, userID string, count int) {
+	if err := r.cache.PublishUnreadCount(ctx, userID, count); err != nil {
+		r.logger.Warn("Failed to publish unread count", map[string]interface{}{
+			"user_id": userID,
+		}, map[string]interface{}{
+			"error": err,
+		})
+	}
+}
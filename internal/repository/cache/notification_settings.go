@@ -0,0 +1,125 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/internal/repository"
+	"github.com/nurlyy/task_manager/pkg/logger"
+)
+
+// SettingsCachingNotificationRepository кэширует в Redis настройки уведомлений пользователей,
+// которые читаются при каждой рассылке и каждом запуске планировщика. Изменение настроек
+// через репозиторий сразу удаляет запись из кэша; пользователи без настроек кэшируются
+// пустым списком, чтобы не обращаться за ними к базе повторно
+type SettingsCachingNotificationRepository struct {
+	repository.NotificationRepository
+	cache  *RedisRepository
+	ttl    time.Duration
+	logger logger.Logger
+}
+
+// NewSettingsCachingNotificationRepository создает новый экземпляр SettingsCachingNotificationRepository
+func NewSettingsCachingNotificationRepository(repo repository.NotificationRepository, cache *RedisRepository, ttl time.Duration, logger logger.Logger) *SettingsCachingNotificationRepository {
+	return &SettingsCachingNotificationRepository{
+		NotificationRepository: repo,
+		cache:                  cache,
+		ttl:                    ttl,
+		logger:                 logger,
+	}
+}
+
+// GetUserNotificationSettings возвращает настройки уведомлений пользователя из кэша,
+// загружая их из базы при отсутствии
+func (r *SettingsCachingNotificationRepository) GetUserNotificationSettings(ctx context.Context, userID string) ([]*repository.NotificationSetting, error) {
+	settings, err := r.GetSettingsForUsers(ctx, []string{userID})
+	if err != nil {
+		return nil, err
+	}
+
+	if userSettings, ok := settings[userID]; ok {
+		return userSettings, nil
+	}
+	return []*repository.NotificationSetting{}, nil
+}
+
+// GetSettingsForUsers возвращает настройки уведомлений нескольких пользователей. Отсутствующие
+// в кэше настройки загружаются из базы одним запросом и сохраняются в кэш
+func (r *SettingsCachingNotificationRepository) GetSettingsForUsers(ctx context.Context, userIDs []string) (map[string][]*repository.NotificationSetting, error) {
+	settings, err := r.cache.GetNotificationSettings(ctx, userIDs)
+	if err != nil {
+		// Недоступность Redis не должна мешать рассылке
+		settings = make(map[string][]*repository.NotificationSetting, len(userIDs))
+	}
+
+	var missing []string
+	for _, userID := range userIDs {
+		if _, ok := settings[userID]; !ok {
+			missing = append(missing, userID)
+		}
+	}
+	if len(missing) == 0 {
+		return settings, nil
+	}
+
+	loaded, err := r.NotificationRepository.GetSettingsForUsers(ctx, missing)
+	if err != nil {
+		return nil, err
+	}
+
+	fetched := make(map[string][]*repository.NotificationSetting, len(missing))
+	for _, userID := range missing {
+		userSettings, ok := loaded[userID]
+		if !ok {
+			userSettings = []*repository.NotificationSetting{}
+		}
+		fetched[userID] = userSettings
+		if len(userSettings) > 0 {
+			settings[userID] = userSettings
+		}
+	}
+
+	if err := r.cache.CacheNotificationSettings(ctx, fetched, r.ttl); err != nil {
+		r.logger.Warn("Failed to cache notification settings", map[string]interface{}{
+			"users": len(fetched),
+		}, map[string]interface{}{
+			"error": err,
+		})
+	}
+
+	return settings, nil
+}
+
+// UpdateUserNotificationSettings обновляет настройки уведомлений пользователя и сбрасывает их кэш
+func (r *SettingsCachingNotificationRepository) UpdateUserNotificationSettings(ctx context.Context, userID string, settings []*repository.NotificationSetting) error {
+	if err := r.NotificationRepository.UpdateUserNotificationSettings(ctx, userID, settings); err != nil {
+		return err
+	}
+
+	r.invalidate(ctx, userID)
+
+	return nil
+}
+
+// SetEmailEnabled включает или отключает email-уведомления указанного типа и сбрасывает кэш настроек
+func (r *SettingsCachingNotificationRepository) SetEmailEnabled(ctx context.Context, userID string, notificationType domain.NotificationType, enabled bool) error {
+	if err := r.NotificationRepository.SetEmailEnabled(ctx, userID, notificationType, enabled); err != nil {
+		return err
+	}
+
+	r.invalidate(ctx, userID)
+
+	return nil
+}
+
+// invalidate удаляет настройки уведомлений пользователя из кэша
+func (r *SettingsCachingNotificationRepository) invalidate(ctx context.Context, userID string) {
+	if err := r.cache.InvalidateNotificationSettings(ctx, userID); err != nil {
+		r.logger.Warn("Failed to invalidate notification settings", map[string]interface{}{
+			"user_id": userID,
+		}, map[string]interface{}{
+			"error": err,
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/internal/repository"
+	"github.com/nurlyy/task_manager/pkg/logger"
+)
+
+// MemberCachingProjectRepository кэширует в Redis членство пользователей в проектах,
+// которое читается при проверке доступа почти в каждом запросе. Изменения состава
+// участников через репозиторий сразу удаляют запись из кэша; короткое время жизни
+// ограничивает устаревание при гонке чтения с изменением
+type MemberCachingProjectRepository struct {
+	repository.ProjectRepository
+	cache  *RedisRepository
+	ttl    time.Duration
+	logger logger.Logger
+}
+
+// NewMemberCachingProjectRepository создает новый экземпляр MemberCachingProjectRepository
+func NewMemberCachingProjectRepository(repo repository.ProjectRepository, cache *RedisRepository, ttl time.Duration, logger logger.Logger) *MemberCachingProjectRepository {
+	return &MemberCachingProjectRepository{
+		ProjectRepository: repo,
+		cache:             cache,
+		ttl:               ttl,
+		logger:            logger,
+	}
+}
+
+// GetMember возвращает участника проекта (nil, если пользователь не участник)
+func (r *MemberCachingProjectRepository) GetMember(ctx context.Context, projectID, userID string) (*domain.ProjectMember, error) {
+	if member, found, err := r.cache.GetProjectMember(ctx, projectID, userID); err == nil && found {
+		return member, nil
+	}
+
+	member, err := r.ProjectRepository.GetMember(ctx, projectID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.cache.CacheProjectMember(ctx, projectID, userID, member, r.ttl); err != nil {
+		r.logger.Warn("Failed to cache project member", map[string]interface{}{
+			"project_id": projectID,
+			"user_id":    userID,
+		}, map[string]interface{}{
+			"error": err,
+		})
+	}
+
+	return member, nil
+}
+
+// Delete удаляет проект и сбрасывает членство всех его участников
+func (r *MemberCachingProjectRepository) Delete(ctx context.Context, id string) error {
+	if err := r.ProjectRepository.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	if err := r.cache.InvalidateProjectMemberRoles(ctx, id); err != nil {
+		r.logger.Warn("Failed to invalidate project member roles", map[string]interface{}{
+			"project_id": id,
+		}, map[string]interface{}{
+			"error": err,
+		})
+	}
+
+	return nil
+}
+
+// AddMember добавляет участника в проект
+func (r *MemberCachingProjectRepository) AddMember(ctx context.Context, member *domain.ProjectMember) error {
+	if err := r.ProjectRepository.AddMember(ctx, member); err != nil {
+		return err
+	}
+
+	r.invalidate(ctx, member.ProjectID, member.UserID)
+
+	return nil
+}
+
+// AddMembers добавляет участников в проекты. Кэш сбрасывается один раз на проект,
+// а не по каждому участнику
+func (r *MemberCachingProjectRepository) AddMembers(ctx context.Context, members []*domain.ProjectMember) error {
+	if err := r.ProjectRepository.AddMembers(ctx, members); err != nil {
+		return err
+	}
+
+	invalidated := make(map[string]bool)
+	for _, member := range members {
+		if invalidated[member.ProjectID] {
+			continue
+		}
+		invalidated[member.ProjectID] = true
+
+		if err := r.cache.InvalidateProjectMemberRoles(ctx, member.ProjectID); err != nil {
+			r.logger.Warn("Failed to invalidate project member roles", map[string]interface{}{
+				"project_id": member.ProjectID,
+			}, map[string]interface{}{
+				"error": err,
+			})
+		}
+	}
+
+	return nil
+}
+
+// UpdateMember обновляет роль участника проекта
+func (r *MemberCachingProjectRepository) UpdateMember(ctx context.Context, projectID, userID string, role domain.ProjectRole) error {
+	if err := r.ProjectRepository.UpdateMember(ctx, projectID, userID, role); err != nil {
+		return err
+	}
+
+	r.invalidate(ctx, projectID, userID)
+
+	return nil
+}
+
+// RemoveMember удаляет участника из проекта
+func (r *MemberCachingProjectRepository) RemoveMember(ctx context.Context, projectID, userID string) error {
+	if err := r.ProjectRepository.RemoveMember(ctx, projectID, userID); err != nil {
+		return err
+	}
+
+	r.invalidate(ctx, projectID, userID)
+
+	return nil
+}
+
+// invalidate удаляет членство пользователя в проекте из кэша
+func (r *MemberCachingProjectRepository) invalidate(ctx context.Context, projectID, userID string) {
+	if err := r.cache.InvalidateProjectMember(ctx, projectID, userID); err != nil {
+		r.logger.Warn("Failed to invalidate project member", map[string]interface{}{
+			"project_id": projectID,
+			"user_id":    userID,
+		}, map[string]interface{}{
+			"error": err,
+		})
+	}
+}
@@ -5,10 +5,13 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-redis/redis/v8"
 	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/internal/repository"
 	"github.com/nurlyy/task_manager/pkg/logger"
 )
 
@@ -19,15 +22,31 @@ const (
 	keyPrefixProject        = "project:"
 	keyPrefixProjectMembers = "project:members:"
 	keyPrefixTaskList       = "task:list:"
+	keyTaskListIndex        = "task:lists"
 	keyPrefixProjectList    = "project:list:"
 	keyPrefixUserTasks      = "user:tasks:"
 	keyPrefixUserProjects   = "user:projects:"
 	keyPrefixTaskComments   = "task:comments:"
 	keyPrefixNotifications  = "notifications:"
 	keyPrefixUnreadCount    = "unread:count:"
+	keyPrefixNotifySettings = "notification:settings:"
 	keyPrefixLock           = "lock:"
+	keyPrefixCommentDraft   = "comment:draft:"
+	keyPrefixTaskEditLock   = "task:edit_lock:"
+	keyPrefixMemberRole     = "member:role:"
+	keyPrefixTaskViews      = "views:task:"
+	keyTaskViewsDirty       = "views:dirty"
+	keyMaintenance          = "maintenance"
+	keyFeatureFlags         = "feature:flags"
+	keyDigestCheckpoint     = "scheduler:digest:checkpoint"
 )
 
+// channelUnreadCounts - канал pub/sub с изменениями счетчиков непрочитанных уведомлений
+const channelUnreadCounts = "events:unread_counts"
+
+// ErrKeyNotFound возвращается, когда ключ отсутствует в кэше
+var ErrKeyNotFound = errors.New("key not found")
+
 // RedisRepository реализует репозиторий кэширования с использованием Redis
 type RedisRepository struct {
 	client *redis.Client
@@ -132,10 +151,56 @@ func (r *RedisRepository) InvalidateProjectMembers(ctx context.Context, projectI
 	return r.deleteValue(ctx, key)
 }
 
-// CacheTaskList сохраняет список задач в кэш
+// CacheProjectMember сохраняет членство пользователя в проекте.
+// nil сохраняется как отсутствие членства, чтобы проверки доступа посторонних тоже не шли в базу
+func (r *RedisRepository) CacheProjectMember(ctx context.Context, projectID, userID string, member *domain.ProjectMember, ttl time.Duration) error {
+	key := fmt.Sprintf("%s%s:%s", keyPrefixMemberRole, projectID, userID)
+	return r.cacheValueWithTTL(ctx, key, member, ttl)
+}
+
+// GetProjectMember получает членство пользователя в проекте из кэша.
+// Второе значение сообщает, найдена ли запись; найденная запись может быть nil для не участника
+func (r *RedisRepository) GetProjectMember(ctx context.Context, projectID, userID string) (*domain.ProjectMember, bool, error) {
+	key := fmt.Sprintf("%s%s:%s", keyPrefixMemberRole, projectID, userID)
+	var member *domain.ProjectMember
+	if err := r.getValue(ctx, key, &member); err != nil {
+		if errors.Is(err, ErrKeyNotFound) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	return member, true, nil
+}
+
+// InvalidateProjectMember удаляет из кэша членство пользователя в проекте
+func (r *RedisRepository) InvalidateProjectMember(ctx context.Context, projectID, userID string) error {
+	key := fmt.Sprintf("%s%s:%s", keyPrefixMemberRole, projectID, userID)
+	return r.deleteValue(ctx, key)
+}
+
+// InvalidateProjectMemberRoles удаляет из кэша членство всех пользователей в проекте
+func (r *RedisRepository) InvalidateProjectMemberRoles(ctx context.Context, projectID string) error {
+	return r.InvalidateAll(ctx, fmt.Sprintf("%s%s:", keyPrefixMemberRole, projectID))
+}
+
+// CacheTaskList сохраняет список задач в кэш. Ключ списка запоминается в индексе,
+// чтобы InvalidateTaskLists сбрасывал все списки без обхода ключей
 func (r *RedisRepository) CacheTaskList(ctx context.Context, filter string, tasks []*domain.Task) error {
 	key := fmt.Sprintf("%s%s", keyPrefixTaskList, filter)
-	return r.cacheValue(ctx, key, tasks)
+	if err := r.cacheValue(ctx, key, tasks); err != nil {
+		return err
+	}
+
+	pipe := r.client.TxPipeline()
+	pipe.SAdd(ctx, keyTaskListIndex, key)
+	pipe.Expire(ctx, keyTaskListIndex, r.ttl)
+	if _, err := pipe.Exec(ctx); err != nil {
+		r.logger.Error("Failed to index cached task list", err, map[string]interface{}{
+			"key": key,
+		})
+		return fmt.Errorf("failed to index cached task list: %w", err)
+	}
+	return nil
 }
 
 // GetTaskList получает список задач из кэша
@@ -154,6 +219,17 @@ func (r *RedisRepository) InvalidateTaskList(ctx context.Context, filter string)
 	return r.deleteValue(ctx, key)
 }
 
+// InvalidateTaskLists удаляет из кэша все списки задач
+func (r *RedisRepository) InvalidateTaskLists(ctx context.Context) error {
+	keys, err := r.client.SMembers(ctx, keyTaskListIndex).Result()
+	if err != nil {
+		r.logger.Error("Failed to get cached task lists", err)
+		return fmt.Errorf("failed to get cached task lists: %w", err)
+	}
+
+	return r.DeleteMany(ctx, append(keys, keyTaskListIndex))
+}
+
 // CacheProjectList сохраняет список проектов в кэш
 func (r *RedisRepository) CacheProjectList(ctx context.Context, filter string, projects []*domain.Project) error {
 	key := fmt.Sprintf("%s%s", keyPrefixProjectList, filter)
@@ -198,18 +274,68 @@ func (r *RedisRepository) InvalidateNotifications(ctx context.Context, userID st
 	return r.deleteValue(ctx, key)
 }
 
+// CacheNotificationSettings сохраняет настройки уведомлений нескольких пользователей
+func (r *RedisRepository) CacheNotificationSettings(ctx context.Context, settings map[string][]*repository.NotificationSetting, ttl time.Duration) error {
+	values := make(map[string]interface{}, len(settings))
+	for userID, userSettings := range settings {
+		values[fmt.Sprintf("%s%s", keyPrefixNotifySettings, userID)] = userSettings
+	}
+	return r.SetMany(ctx, values, ttl)
+}
+
+// GetNotificationSettings получает закэшированные настройки уведомлений нескольких пользователей
+// одним запросом. Пользователи, отсутствующие в кэше, в результат не попадают
+func (r *RedisRepository) GetNotificationSettings(ctx context.Context, userIDs []string) (map[string][]*repository.NotificationSetting, error) {
+	keys := make([]string, len(userIDs))
+	for i, userID := range userIDs {
+		keys[i] = fmt.Sprintf("%s%s", keyPrefixNotifySettings, userID)
+	}
+
+	values, err := r.GetMany(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	settings := make(map[string][]*repository.NotificationSetting, len(userIDs))
+	for i, value := range values {
+		if value == nil {
+			continue
+		}
+		var userSettings []*repository.NotificationSetting
+		if err := json.Unmarshal(value, &userSettings); err != nil {
+			continue
+		}
+		settings[userIDs[i]] = userSettings
+	}
+	return settings, nil
+}
+
+// InvalidateNotificationSettings удаляет настройки уведомлений пользователя из кэша
+func (r *RedisRepository) InvalidateNotificationSettings(ctx context.Context, userID string) error {
+	key := fmt.Sprintf("%s%s", keyPrefixNotifySettings, userID)
+	return r.deleteValue(ctx, key)
+}
+
 // CacheUnreadCount сохраняет количество непрочитанных уведомлений пользователя
 func (r *RedisRepository) CacheUnreadCount(ctx context.Context, userID string, count int) error {
 	key := fmt.Sprintf("%s%s", keyPrefixUnreadCount, userID)
 	return r.client.Set(ctx, key, count, r.ttl).Err()
 }
 
-// GetUnreadCount получает количество непрочитанных уведомлений пользователя
+// InitUnreadCount сохраняет загруженное из базы количество непрочитанных уведомлений,
+// если счетчик еще не создан параллельным запросом
+func (r *RedisRepository) InitUnreadCount(ctx context.Context, userID string, count int) error {
+	key := fmt.Sprintf("%s%s", keyPrefixUnreadCount, userID)
+	return r.client.SetNX(ctx, key, count, r.ttl).Err()
+}
+
+// GetUnreadCount получает количество непрочитанных уведомлений пользователя.
+// Если счетчик отсутствует в кэше, возвращает ErrKeyNotFound
 func (r *RedisRepository) GetUnreadCount(ctx context.Context, userID string) (int, error) {
 	key := fmt.Sprintf("%s%s", keyPrefixUnreadCount, userID)
 	val, err := r.client.Get(ctx, key).Int()
 	if err == redis.Nil {
-		return 0, nil
+		return 0, ErrKeyNotFound
 	}
 	if err != nil {
 		r.logger.Error("Failed to get unread count from Redis", err, map[string]interface{}{
@@ -220,6 +346,448 @@ func (r *RedisRepository) GetUnreadCount(ctx context.Context, userID string) (in
 	return val, nil
 }
 
+// incrUnreadCountScript изменяет существующий счетчик. Отсутствующий счетчик не создается:
+// он будет загружен из базы при следующем чтении. Отрицательное значение означает
+// расхождение с базой, поэтому такой счетчик удаляется
+var incrUnreadCountScript = redis.NewScript(`
+if redis.call("EXISTS", KEYS[1]) == 0 then
+	return nil
+end
+local value = redis.call("INCRBY", KEYS[1], ARGV[1])
+if value < 0 then
+	redis.call("DEL", KEYS[1])
+end
+return value
+`)
+
+// IncrUnreadCount атомарно изменяет количество непрочитанных уведомлений пользователя на delta
+// и возвращает новое значение. Если счетчика нет в кэше или он удален из-за расхождения,
+// возвращает ErrKeyNotFound
+func (r *RedisRepository) IncrUnreadCount(ctx context.Context, userID string, delta int) (int, error) {
+	key := fmt.Sprintf("%s%s", keyPrefixUnreadCount, userID)
+	value, err := incrUnreadCountScript.Run(ctx, r.client, []string{key}, delta).Int()
+	if err == redis.Nil {
+		return 0, ErrKeyNotFound
+	}
+	if err != nil {
+		r.logger.Error("Failed to increment unread count in Redis", err, map[string]interface{}{
+			"user_id": userID,
+			"delta":   delta,
+		})
+		return 0, fmt.Errorf("failed to increment unread count: %w", err)
+	}
+	if value < 0 {
+		return 0, ErrKeyNotFound
+	}
+	return value, nil
+}
+
+// PublishUnreadCount сообщает подписчикам о новом количестве непрочитанных уведомлений пользователя
+func (r *RedisRepository) PublishUnreadCount(ctx context.Context, userID string, count int) error {
+	data, err := json.Marshal(domain.UnreadCountEvent{UserID: userID, Count: count})
+	if err != nil {
+		return fmt.Errorf("failed to marshal unread count event: %w", err)
+	}
+
+	if err := r.client.Publish(ctx, channelUnreadCounts, data).Err(); err != nil {
+		r.logger.Error("Failed to publish unread count", err, map[string]interface{}{
+			"user_id": userID,
+		})
+		return fmt.Errorf("failed to publish unread count: %w", err)
+	}
+	return nil
+}
+
+// SubscribeUnreadCounts передает handle изменения счетчиков непрочитанных уведомлений
+// до отмены ctx. После разрыва соединения с Redis подписка восстанавливается автоматически;
+// изменения, опубликованные во время разрыва, не доставляются
+func (r *RedisRepository) SubscribeUnreadCounts(ctx context.Context, handle func(event *domain.UnreadCountEvent)) error {
+	pubsub := r.client.Subscribe(ctx, channelUnreadCounts)
+	defer pubsub.Close()
+
+	// Дожидаемся подтверждения подписки, чтобы сообщить об ошибке подключения сразу
+	if _, err := pubsub.Receive(ctx); err != nil {
+		if ctx.Err() != nil {
+			return nil
+		}
+		r.logger.Error("Failed to subscribe to unread counts", err)
+		return fmt.Errorf("failed to subscribe to unread counts: %w", err)
+	}
+
+	messages := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case message, ok := <-messages:
+			if !ok {
+				return nil
+			}
+			var event domain.UnreadCountEvent
+			if err := json.Unmarshal([]byte(message.Payload), &event); err != nil {
+				r.logger.Error("Failed to unmarshal unread count event", err)
+				continue
+			}
+			handle(&event)
+		}
+	}
+}
+
+// InvalidateUnreadCount удаляет счетчик непрочитанных уведомлений пользователя
+func (r *RedisRepository) InvalidateUnreadCount(ctx context.Context, userID string) error {
+	key := fmt.Sprintf("%s%s", keyPrefixUnreadCount, userID)
+	return r.deleteValue(ctx, key)
+}
+
+// ListUnreadCounts возвращает все счетчики непрочитанных уведомлений, находящиеся в кэше
+func (r *RedisRepository) ListUnreadCounts(ctx context.Context) (map[string]int, error) {
+	counts := make(map[string]int)
+	err := r.scanKeys(ctx, keyPrefixUnreadCount+"*", func(keys []string) error {
+		values, err := r.GetMany(ctx, keys)
+		if err != nil {
+			return err
+		}
+		for i, value := range values {
+			if value == nil {
+				continue
+			}
+			count, err := strconv.Atoi(string(value))
+			if err != nil {
+				continue
+			}
+			counts[strings.TrimPrefix(keys[i], keyPrefixUnreadCount)] = count
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list unread counts: %w", err)
+	}
+	return counts, nil
+}
+
+// SaveCommentDraft сохраняет черновик комментария пользователя с указанным временем жизни
+func (r *RedisRepository) SaveCommentDraft(ctx context.Context, draft *domain.CommentDraft, ttl time.Duration) error {
+	key := fmt.Sprintf("%s%s:%s", keyPrefixCommentDraft, draft.TaskID, draft.UserID)
+	return r.cacheValueWithTTL(ctx, key, draft, ttl)
+}
+
+// GetCommentDraft получает черновик комментария пользователя к задаче
+func (r *RedisRepository) GetCommentDraft(ctx context.Context, taskID, userID string) (*domain.CommentDraft, error) {
+	key := fmt.Sprintf("%s%s:%s", keyPrefixCommentDraft, taskID, userID)
+	var draft domain.CommentDraft
+	if err := r.getValue(ctx, key, &draft); err != nil {
+		return nil, err
+	}
+	return &draft, nil
+}
+
+// DeleteCommentDraft удаляет черновик комментария пользователя к задаче
+func (r *RedisRepository) DeleteCommentDraft(ctx context.Context, taskID, userID string) error {
+	key := fmt.Sprintf("%s%s:%s", keyPrefixCommentDraft, taskID, userID)
+	return r.deleteValue(ctx, key)
+}
+
+// TryAcquireTaskEditLock пытается атомарно установить блокировку редактирования задачи,
+// возвращает false, если блокировка уже удерживается
+func (r *RedisRepository) TryAcquireTaskEditLock(ctx context.Context, lock *domain.TaskEditLock, ttl time.Duration) (bool, error) {
+	key := fmt.Sprintf("%s%s", keyPrefixTaskEditLock, lock.TaskID)
+	data, err := json.Marshal(lock)
+	if err != nil {
+		r.logger.Error("Failed to marshal task edit lock", err, map[string]interface{}{
+			"key": key,
+		})
+		return false, fmt.Errorf("failed to marshal task edit lock: %w", err)
+	}
+
+	ok, err := r.client.SetNX(ctx, key, data, ttl).Result()
+	if err != nil {
+		r.logger.Error("Failed to acquire task edit lock", err, map[string]interface{}{
+			"key": key,
+		})
+		return false, fmt.Errorf("failed to acquire task edit lock: %w", err)
+	}
+	return ok, nil
+}
+
+// replaceTaskEditLockScript заменяет блокировку значением ARGV[2] со временем жизни ARGV[3] мс,
+// только если ее владелец - ARGV[1]. Возвращает 1, если блокировка заменена
+var replaceTaskEditLockScript = redis.NewScript(`
+local current = redis.call("GET", KEYS[1])
+if not current or cjson.decode(current).user_id ~= ARGV[1] then
+	return 0
+end
+redis.call("SET", KEYS[1], ARGV[2], "PX", ARGV[3])
+return 1
+`)
+
+// deleteTaskEditLockScript удаляет блокировку, только если ее владелец - ARGV[1].
+// Возвращает 1, если блокировка удалена
+var deleteTaskEditLockScript = redis.NewScript(`
+local current = redis.call("GET", KEYS[1])
+if not current or cjson.decode(current).user_id ~= ARGV[1] then
+	return 0
+end
+return redis.call("DEL", KEYS[1])
+`)
+
+// ReplaceTaskEditLock атомарно перезаписывает блокировку редактирования задачи, если ее удерживает
+// ownerID. Возвращает false, если блокировки нет или она уже принадлежит другому пользователю
+func (r *RedisRepository) ReplaceTaskEditLock(ctx context.Context, lock *domain.TaskEditLock, ownerID string, ttl time.Duration) (bool, error) {
+	key := fmt.Sprintf("%s%s", keyPrefixTaskEditLock, lock.TaskID)
+	data, err := json.Marshal(lock)
+	if err != nil {
+		r.logger.Error("Failed to marshal task edit lock", err, map[string]interface{}{
+			"key": key,
+		})
+		return false, fmt.Errorf("failed to marshal task edit lock: %w", err)
+	}
+
+	replaced, err := replaceTaskEditLockScript.Run(ctx, r.client, []string{key}, ownerID, data, ttl.Milliseconds()).Int()
+	if err != nil {
+		r.logger.Error("Failed to replace task edit lock", err, map[string]interface{}{
+			"key": key,
+		})
+		return false, fmt.Errorf("failed to replace task edit lock: %w", err)
+	}
+	return replaced == 1, nil
+}
+
+// GetTaskEditLock получает текущую блокировку редактирования задачи
+func (r *RedisRepository) GetTaskEditLock(ctx context.Context, taskID string) (*domain.TaskEditLock, error) {
+	key := fmt.Sprintf("%s%s", keyPrefixTaskEditLock, taskID)
+	var lock domain.TaskEditLock
+	if err := r.getValue(ctx, key, &lock); err != nil {
+		return nil, err
+	}
+	return &lock, nil
+}
+
+// DeleteTaskEditLock атомарно удаляет блокировку редактирования задачи, если ее удерживает ownerID.
+// Возвращает false, если блокировки нет или она уже принадлежит другому пользователю
+func (r *RedisRepository) DeleteTaskEditLock(ctx context.Context, taskID string, ownerID string) (bool, error) {
+	key := fmt.Sprintf("%s%s", keyPrefixTaskEditLock, taskID)
+	deleted, err := deleteTaskEditLockScript.Run(ctx, r.client, []string{key}, ownerID).Int()
+	if err != nil {
+		r.logger.Error("Failed to delete task edit lock", err, map[string]interface{}{
+			"key": key,
+		})
+		return false, fmt.Errorf("failed to delete task edit lock: %w", err)
+	}
+	return deleted == 1, nil
+}
+
+// RecordTaskView сохраняет время просмотра задачи пользователем и помечает просмотр
+// для переноса в базу. Просмотры пользователя хранятся в одном хэше, время жизни
+// которого продлевается при каждом просмотре
+func (r *RedisRepository) RecordTaskView(ctx context.Context, view *domain.TaskView, ttl time.Duration) error {
+	key := fmt.Sprintf("%s%s", keyPrefixTaskViews, view.UserID)
+
+	pipe := r.client.TxPipeline()
+	pipe.HSet(ctx, key, view.TaskID, view.ViewedAt.UnixMilli())
+	pipe.Expire(ctx, key, ttl)
+	pipe.SAdd(ctx, keyTaskViewsDirty, taskViewMember(view.UserID, view.TaskID))
+	if _, err := pipe.Exec(ctx); err != nil {
+		r.logger.Error("Failed to record task view", err, map[string]interface{}{
+			"user_id": view.UserID,
+			"task_id": view.TaskID,
+		})
+		return fmt.Errorf("failed to record task view: %w", err)
+	}
+	return nil
+}
+
+// GetTaskViews получает время просмотра задач пользователем. Задачи, просмотр которых
+// отсутствует в кэше, в результат не попадают
+func (r *RedisRepository) GetTaskViews(ctx context.Context, userID string, taskIDs []string) (map[string]time.Time, error) {
+	views := make(map[string]time.Time, len(taskIDs))
+	if len(taskIDs) == 0 {
+		return views, nil
+	}
+
+	key := fmt.Sprintf("%s%s", keyPrefixTaskViews, userID)
+	values, err := r.client.HMGet(ctx, key, taskIDs...).Result()
+	if err != nil {
+		r.logger.Error("Failed to get task views from Redis", err, map[string]interface{}{
+			"user_id": userID,
+		})
+		return nil, fmt.Errorf("failed to get task views: %w", err)
+	}
+
+	for i, value := range values {
+		str, ok := value.(string)
+		if !ok {
+			continue
+		}
+		millis, err := strconv.ParseInt(str, 10, 64)
+		if err != nil {
+			continue
+		}
+		views[taskIDs[i]] = time.UnixMilli(millis)
+	}
+	return views, nil
+}
+
+// PopDirtyTaskViews извлекает до count просмотров, еще не перенесенных в базу.
+// Просмотры, вытесненные из кэша до переноса, пропускаются
+func (r *RedisRepository) PopDirtyTaskViews(ctx context.Context, count int) ([]*domain.TaskView, error) {
+	members, err := r.client.SPopN(ctx, keyTaskViewsDirty, int64(count)).Result()
+	if err != nil && err != redis.Nil {
+		r.logger.Error("Failed to pop dirty task views", err)
+		return nil, fmt.Errorf("failed to pop dirty task views: %w", err)
+	}
+	if len(members) == 0 {
+		return nil, nil
+	}
+
+	pipe := r.client.Pipeline()
+	cmds := make([]*redis.StringCmd, len(members))
+	views := make([]*domain.TaskView, len(members))
+	for i, member := range members {
+		userID, taskID, _ := strings.Cut(member, ":")
+		views[i] = &domain.TaskView{UserID: userID, TaskID: taskID}
+		cmds[i] = pipe.HGet(ctx, fmt.Sprintf("%s%s", keyPrefixTaskViews, userID), taskID)
+	}
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		// Просмотры возвращаются в очередь, чтобы не потерять их при сбое чтения
+		if restoreErr := r.client.SAdd(ctx, keyTaskViewsDirty, members).Err(); restoreErr != nil {
+			r.logger.Error("Failed to restore dirty task views", restoreErr, map[string]interface{}{
+				"count": len(members),
+			})
+		}
+		r.logger.Error("Failed to get dirty task views", err, map[string]interface{}{
+			"count": len(members),
+		})
+		return nil, fmt.Errorf("failed to get dirty task views: %w", err)
+	}
+
+	result := make([]*domain.TaskView, 0, len(views))
+	for i, cmd := range cmds {
+		millis, err := cmd.Int64()
+		if err != nil {
+			continue
+		}
+		views[i].ViewedAt = time.UnixMilli(millis)
+		result = append(result, views[i])
+	}
+	return result, nil
+}
+
+// MarkTaskViewsDirty возвращает просмотры в очередь переноса в базу, например после
+// неудачной записи
+func (r *RedisRepository) MarkTaskViewsDirty(ctx context.Context, views []*domain.TaskView) error {
+	if len(views) == 0 {
+		return nil
+	}
+
+	members := make([]interface{}, len(views))
+	for i, view := range views {
+		members[i] = taskViewMember(view.UserID, view.TaskID)
+	}
+	if err := r.client.SAdd(ctx, keyTaskViewsDirty, members...).Err(); err != nil {
+		r.logger.Error("Failed to mark task views dirty", err, map[string]interface{}{
+			"count": len(views),
+		})
+		return fmt.Errorf("failed to mark task views dirty: %w", err)
+	}
+	return nil
+}
+
+// taskViewMember формирует элемент очереди просмотров, ожидающих переноса в базу
+func taskViewMember(userID, taskID string) string {
+	return userID + ":" + taskID
+}
+
+// SetMaintenance сохраняет состояние режима обслуживания без ограничения времени жизни
+func (r *RedisRepository) SetMaintenance(ctx context.Context, status *domain.MaintenanceStatus) error {
+	return r.cacheValueWithTTL(ctx, keyMaintenance, status, 0)
+}
+
+// GetMaintenance получает состояние режима обслуживания. Если режим ни разу
+// не включался, возвращает выключенное состояние
+func (r *RedisRepository) GetMaintenance(ctx context.Context) (*domain.MaintenanceStatus, error) {
+	var status domain.MaintenanceStatus
+	if err := r.getValue(ctx, keyMaintenance, &status); err != nil {
+		if errors.Is(err, ErrKeyNotFound) {
+			return &domain.MaintenanceStatus{}, nil
+		}
+		return nil, err
+	}
+	return &status, nil
+}
+
+// SetFeatureFlag сохраняет переопределение флага функциональности
+func (r *RedisRepository) SetFeatureFlag(ctx context.Context, flag *domain.FeatureFlag) error {
+	data, err := json.Marshal(flag)
+	if err != nil {
+		return fmt.Errorf("failed to marshal feature flag: %w", err)
+	}
+
+	if err := r.client.HSet(ctx, keyFeatureFlags, flag.Name, data).Err(); err != nil {
+		r.logger.Error("Failed to save feature flag", err, map[string]interface{}{
+			"flag": flag.Name,
+		})
+		return fmt.Errorf("failed to save feature flag: %w", err)
+	}
+	return nil
+}
+
+// GetFeatureFlags получает все переопределения флагов функциональности.
+// Поврежденные записи пропускаются
+func (r *RedisRepository) GetFeatureFlags(ctx context.Context) (map[string]*domain.FeatureFlag, error) {
+	values, err := r.client.HGetAll(ctx, keyFeatureFlags).Result()
+	if err != nil {
+		r.logger.Error("Failed to get feature flags", err)
+		return nil, fmt.Errorf("failed to get feature flags: %w", err)
+	}
+
+	flags := make(map[string]*domain.FeatureFlag, len(values))
+	for name, value := range values {
+		var flag domain.FeatureFlag
+		if err := json.Unmarshal([]byte(value), &flag); err != nil {
+			r.logger.Error("Failed to unmarshal feature flag", err, map[string]interface{}{
+				"flag": name,
+			})
+			continue
+		}
+		flag.Name = name
+		flags[name] = &flag
+	}
+	return flags, nil
+}
+
+// DeleteFeatureFlag удаляет переопределение флага функциональности
+func (r *RedisRepository) DeleteFeatureFlag(ctx context.Context, name string) error {
+	if err := r.client.HDel(ctx, keyFeatureFlags, name).Err(); err != nil {
+		r.logger.Error("Failed to delete feature flag", err, map[string]interface{}{
+			"flag": name,
+		})
+		return fmt.Errorf("failed to delete feature flag: %w", err)
+	}
+	return nil
+}
+
+// SaveDigestCheckpoint сохраняет состояние рассылки дайджестов с указанным временем жизни
+func (r *RedisRepository) SaveDigestCheckpoint(ctx context.Context, checkpoint *domain.DigestCheckpoint, ttl time.Duration) error {
+	return r.cacheValueWithTTL(ctx, keyDigestCheckpoint, checkpoint, ttl)
+}
+
+// GetDigestCheckpoint получает состояние незавершенной рассылки дайджестов (nil, если его нет)
+func (r *RedisRepository) GetDigestCheckpoint(ctx context.Context) (*domain.DigestCheckpoint, error) {
+	var checkpoint domain.DigestCheckpoint
+	if err := r.getValue(ctx, keyDigestCheckpoint, &checkpoint); err != nil {
+		if errors.Is(err, ErrKeyNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &checkpoint, nil
+}
+
+// DeleteDigestCheckpoint удаляет состояние завершенной рассылки дайджестов
+func (r *RedisRepository) DeleteDigestCheckpoint(ctx context.Context) error {
+	return r.deleteValue(ctx, keyDigestCheckpoint)
+}
+
 // AcquireLock получает блокировку с таймаутом
 func (r *RedisRepository) AcquireLock(ctx context.Context, key string, ttl time.Duration) (bool, error) {
 	lockKey := fmt.Sprintf("%s%s", keyPrefixLock, key)
@@ -239,31 +807,145 @@ func (r *RedisRepository) ReleaseLock(ctx context.Context, key string) error {
 	return r.deleteValue(ctx, lockKey)
 }
 
-// InvalidateAll удаляет все данные из кэша для указанного типа
+// InvalidateAll удаляет все данные из кэша для указанного типа.
+// Ключи перебираются через SCAN, чтобы не блокировать Redis на большом числе ключей
 func (r *RedisRepository) InvalidateAll(ctx context.Context, prefix string) error {
 	pattern := fmt.Sprintf("%s*", prefix)
-	keys, err := r.client.Keys(ctx, pattern).Result()
-	if err != nil {
-		r.logger.Error("Failed to get keys for pattern", err, map[string]interface{}{
-			"pattern": pattern,
-		})
-		return fmt.Errorf("failed to get keys for pattern: %w", err)
-	}
-
-	if len(keys) > 0 {
+	return r.scanKeys(ctx, pattern, func(keys []string) error {
 		if err := r.client.Del(ctx, keys...).Err(); err != nil {
 			r.logger.Error("Failed to delete keys", err, map[string]interface{}{
 				"count": len(keys),
 			})
 			return fmt.Errorf("failed to delete keys: %w", err)
 		}
+		return nil
+	})
+}
+
+// GetMany получает значения нескольких ключей одной командой MGET.
+// Для отсутствующих ключей в результате возвращается nil
+func (r *RedisRepository) GetMany(ctx context.Context, keys []string) ([][]byte, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	values, err := r.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		r.logger.Error("Failed to get values from Redis", err, map[string]interface{}{
+			"count": len(keys),
+		})
+		return nil, fmt.Errorf("failed to get values from Redis: %w", err)
+	}
+
+	result := make([][]byte, len(keys))
+	for i, value := range values {
+		if str, ok := value.(string); ok {
+			result[i] = []byte(str)
+		}
+	}
+	return result, nil
+}
+
+// SetMany сохраняет несколько значений в JSON за один проход по сети
+func (r *RedisRepository) SetMany(ctx context.Context, values map[string]interface{}, ttl time.Duration) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	pipe := r.client.Pipeline()
+	for key, value := range values {
+		data, err := json.Marshal(value)
+		if err != nil {
+			r.logger.Error("Failed to marshal value", err, map[string]interface{}{
+				"key": key,
+			})
+			return fmt.Errorf("failed to marshal value: %w", err)
+		}
+		pipe.Set(ctx, key, data, ttl)
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		r.logger.Error("Failed to set values in Redis", err, map[string]interface{}{
+			"count": len(values),
+		})
+		return fmt.Errorf("failed to set values in Redis: %w", err)
 	}
 
 	return nil
 }
 
+// DeleteMany удаляет несколько ключей одной командой
+func (r *RedisRepository) DeleteMany(ctx context.Context, keys []string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	if err := r.client.Del(ctx, keys...).Err(); err != nil {
+		r.logger.Error("Failed to delete keys from Redis", err, map[string]interface{}{
+			"count": len(keys),
+		})
+		return fmt.Errorf("failed to delete keys from Redis: %w", err)
+	}
+	return nil
+}
+
+// GetUserResponses получает закэшированные данные нескольких пользователей одним запросом.
+// Пользователи, отсутствующие в кэше, в результат не попадают
+func (r *RedisRepository) GetUserResponses(ctx context.Context, ids []string) (map[string]*domain.UserResponse, error) {
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = fmt.Sprintf("%s%s", keyPrefixUser, id)
+	}
+
+	values, err := r.GetMany(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	users := make(map[string]*domain.UserResponse, len(ids))
+	for i, value := range values {
+		if value == nil {
+			continue
+		}
+		var user domain.UserResponse
+		if err := json.Unmarshal(value, &user); err != nil {
+			continue
+		}
+		users[ids[i]] = &user
+	}
+	return users, nil
+}
+
 // Вспомогательные методы
 
+// scanBatchSize задает число ключей, обрабатываемых за одну итерацию SCAN
+const scanBatchSize = 500
+
+// scanKeys перебирает ключи по шаблону через SCAN и передает их обработчику пачками
+func (r *RedisRepository) scanKeys(ctx context.Context, pattern string, handle func(keys []string) error) error {
+	var cursor uint64
+	for {
+		keys, next, err := r.client.Scan(ctx, cursor, pattern, scanBatchSize).Result()
+		if err != nil {
+			r.logger.Error("Failed to scan keys for pattern", err, map[string]interface{}{
+				"pattern": pattern,
+			})
+			return fmt.Errorf("failed to scan keys for pattern: %w", err)
+		}
+
+		if len(keys) > 0 {
+			if err := handle(keys); err != nil {
+				return err
+			}
+		}
+
+		if next == 0 {
+			return nil
+		}
+		cursor = next
+	}
+}
+
 // cacheValue сохраняет значение в кэш
 func (r *RedisRepository) cacheValue(ctx context.Context, key string, value interface{}) error {
 	data, err := json.Marshal(value)
@@ -284,11 +966,31 @@ func (r *RedisRepository) cacheValue(ctx context.Context, key string, value inte
 	return nil
 }
 
+// cacheValueWithTTL сохраняет значение в кэше с указанным временем жизни
+func (r *RedisRepository) cacheValueWithTTL(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		r.logger.Error("Failed to marshal value", err, map[string]interface{}{
+			"key": key,
+		})
+		return fmt.Errorf("failed to marshal value: %w", err)
+	}
+
+	if err := r.client.Set(ctx, key, data, ttl).Err(); err != nil {
+		r.logger.Error("Failed to set value in Redis", err, map[string]interface{}{
+			"key": key,
+		})
+		return fmt.Errorf("failed to set value in Redis: %w", err)
+	}
+
+	return nil
+}
+
 // getValue получает значение из кэша
 func (r *RedisRepository) getValue(ctx context.Context, key string, dest interface{}) error {
 	data, err := r.client.Get(ctx, key).Bytes()
 	if err == redis.Nil {
-		return fmt.Errorf("key not found")
+		return ErrKeyNotFound
 	}
 	if err != nil {
 		r.logger.Error("Failed to get value from Redis", err, map[string]interface{}{
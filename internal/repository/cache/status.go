@@ -0,0 +1,75 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// keyPrefixStatusUptime - префикс счетчиков проверок сервиса по дням
+const keyPrefixStatusUptime = "status:uptime:"
+
+// statusUptimeTTL - время хранения дневных счетчиков проверок
+const statusUptimeTTL = 100 * 24 * time.Hour
+
+// SurrogateKeyStatus помечает ответы страницы статуса сервиса
+const SurrogateKeyStatus = "status"
+
+// StatusUptimeSample содержит количество проверок сервиса за день
+type StatusUptimeSample struct {
+	Day   time.Time
+	Total int
+	OK    int
+}
+
+// Ping проверяет соединение с Redis
+func (r *RedisRepository) Ping(ctx context.Context) error {
+	return r.client.Ping(ctx).Err()
+}
+
+// RecordStatusSample учитывает результат проверки сервиса в счетчиках дня
+func (r *RedisRepository) RecordStatusSample(ctx context.Context, at time.Time, ok bool) error {
+	key := statusUptimeKey(at)
+
+	pipe := r.client.TxPipeline()
+	pipe.HIncrBy(ctx, key, "total", 1)
+	if ok {
+		pipe.HIncrBy(ctx, key, "ok", 1)
+	}
+	pipe.Expire(ctx, key, statusUptimeTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to record status sample: %w", err)
+	}
+	return nil
+}
+
+// GetStatusSamples возвращает счетчики проверок сервиса за последние days дней, начиная со старого.
+// Дни без проверок возвращаются с нулевыми счетчиками
+func (r *RedisRepository) GetStatusSamples(ctx context.Context, now time.Time, days int) ([]StatusUptimeSample, error) {
+	pipe := r.client.Pipeline()
+	samples := make([]StatusUptimeSample, days)
+	cmds := make([]*redis.StringStringMapCmd, days)
+	for i := range samples {
+		samples[i].Day = now.UTC().AddDate(0, 0, i-days+1).Truncate(24 * time.Hour)
+		cmds[i] = pipe.HGetAll(ctx, statusUptimeKey(samples[i].Day))
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, fmt.Errorf("failed to get status samples: %w", err)
+	}
+
+	for i, cmd := range cmds {
+		values := cmd.Val()
+		samples[i].Total, _ = strconv.Atoi(values["total"])
+		samples[i].OK, _ = strconv.Atoi(values["ok"])
+	}
+	return samples, nil
+}
+
+// statusUptimeKey возвращает ключ счетчиков проверок за день (по UTC)
+func statusUptimeKey(at time.Time) string {
+	return keyPrefixStatusUptime + at.UTC().Format("2006-01-02")
+}
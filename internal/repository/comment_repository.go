@@ -32,6 +32,15 @@ type CommentRepository interface {
 	// CountCommentsByTask возвращает количество комментариев к задаче
 	CountCommentsByTask(ctx context.Context, taskID string) (int, error)
 
+	// GetReplies возвращает ответы на указанные комментарии вместе с вложенными ответами
+	// не глубже depth уровней, не более limit ответов под каждым из комментариев parentIDs.
+	// Ответы упорядочены по времени создания, поэтому родитель ответа идет раньше него
+	GetReplies(ctx context.Context, parentIDs []string, depth int, limit int) ([]*domain.Comment, error)
+
+	// CountReplies возвращает число прямых ответов на каждый из комментариев;
+	// комментарии без ответов в результат не входят
+	CountReplies(ctx context.Context, ids []string) (map[string]int, error)
+
 	// GetCommentsByUser возвращает комментарии пользователя
 	GetCommentsByUser(ctx context.Context, userID string, filter CommentFilter) ([]*domain.Comment, error)
 
@@ -45,6 +54,8 @@ type CommentFilter struct {
 	TaskIDs    []string `json:"task_ids,omitempty"`
 	UserIDs    []string `json:"user_ids,omitempty"`
 	SearchText *string  `json:"search_text,omitempty"`
+	ParentIDs  []string `json:"parent_ids,omitempty"` // Только прямые ответы на указанные комментарии
+	RootsOnly  bool     `json:"roots_only,omitempty"` // Только комментарии верхнего уровня, без ответов
 	OrderBy    *string  `json:"order_by,omitempty"`
 	OrderDir   *string  `json:"order_dir,omitempty"`
 	Limit      int      `json:"limit"`
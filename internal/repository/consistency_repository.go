@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+)
+
+// ConsistencyRepository определяет интерфейс для сверки производных данных с базой
+type ConsistencyRepository interface {
+	// GetTaskVersions возвращает время последнего изменения задач с указанными ID.
+	// Удаленных задач в результате нет
+	GetTaskVersions(ctx context.Context, ids []string) (map[string]time.Time, error)
+
+	// GetProjectVersions возвращает время последнего изменения проектов с указанными ID.
+	// Удаленных проектов в результате нет
+	GetProjectVersions(ctx context.Context, ids []string) (map[string]time.Time, error)
+
+	// ListSpentHoursDrift возвращает до limit задач, затраченное время которых расходится
+	// с суммой записей о времени, а также количество проверенных задач и задач с расхождением
+	ListSpentHoursDrift(ctx context.Context, limit int) (drifts []*domain.SpentHoursDrift, checked int, drifted int, err error)
+
+	// RecalculateSpentHours записывает в задачи с расхождением сумму их записей о времени
+	// и возвращает ID исправленных задач
+	RecalculateSpentHours(ctx context.Context) ([]string, error)
+
+	// ListSearchIndexes возвращает состояние поисковых индексов
+	ListSearchIndexes(ctx context.Context) ([]*domain.SearchIndexState, error)
+
+	// Reindex перестраивает индекс, не блокируя запись в таблицу
+	Reindex(ctx context.Context, name string) error
+}
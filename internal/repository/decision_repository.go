@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+)
+
+// DecisionFilter содержит параметры фильтрации журнала решений
+type DecisionFilter struct {
+	Search *string // Подстрока названия, контекста, решения или альтернатив
+	TaskID *string // Только решения, связанные с задачей
+	Limit  int
+	Offset int
+}
+
+// DecisionRepository определяет интерфейс для работы с журналом решений
+type DecisionRepository interface {
+	// Create сохраняет решение вместе со связями с задачами
+	Create(ctx context.Context, decision *domain.Decision) error
+
+	// GetByID возвращает решение по ID (nil, если решение не найдено)
+	GetByID(ctx context.Context, id string) (*domain.Decision, error)
+
+	// Update обновляет решение
+	Update(ctx context.Context, decision *domain.Decision) error
+
+	// Delete удаляет решение
+	Delete(ctx context.Context, id string) error
+
+	// List возвращает решения проекта, сначала последние
+	List(ctx context.Context, projectID string, filter DecisionFilter) ([]*domain.Decision, error)
+
+	// Count возвращает количество решений проекта по фильтру
+	Count(ctx context.Context, projectID string, filter DecisionFilter) (int, error)
+
+	// ListByTask возвращает решения, связанные с задачей
+	ListByTask(ctx context.Context, taskID string) ([]*domain.Decision, error)
+
+	// LinkTask связывает решение с задачей. Возвращает false, если связь уже есть
+	LinkTask(ctx context.Context, decisionID, taskID, userID string) (bool, error)
+
+	// UnlinkTask удаляет связь решения с задачей
+	UnlinkTask(ctx context.Context, decisionID, taskID string) error
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+)
+
+// DiscordRepository определяет интерфейс для работы с каналами Discord проектов
+type DiscordRepository interface {
+	// GetProjectChannel возвращает канал проекта (nil, если канал не настроен)
+	GetProjectChannel(ctx context.Context, projectID string) (*domain.ProjectDiscordChannel, error)
+
+	// SaveProjectChannel создает или обновляет канал проекта
+	SaveProjectChannel(ctx context.Context, channel *domain.ProjectDiscordChannel) error
+
+	// DeleteProjectChannel удаляет канал проекта
+	DeleteProjectChannel(ctx context.Context, projectID string) error
+}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+)
+
+// EpicRepository определяет интерфейс для работы с эпиками проектов
+type EpicRepository interface {
+	// Create создает новый эпик
+	Create(ctx context.Context, epic *domain.Epic) error
+
+	// GetByID возвращает эпик по ID (nil, если эпик не найден)
+	GetByID(ctx context.Context, id string) (*domain.Epic, error)
+
+	// Update обновляет данные эпика
+	Update(ctx context.Context, epic *domain.Epic) error
+
+	// Delete удаляет эпик; задачи эпика остаются в проекте без эпика
+	Delete(ctx context.Context, id string) error
+
+	// ListByProject возвращает эпики проекта, упорядоченные по целевой дате
+	ListByProject(ctx context.Context, projectID string) ([]*domain.Epic, error)
+
+	// GetProgress возвращает сводный прогресс задач указанных эпиков по ID эпика
+	GetProgress(ctx context.Context, epicIDs []string) (map[string]*domain.EpicProgress, error)
+
+	// SetTaskEpic привязывает задачу к эпику или отвязывает ее при epicID == nil
+	SetTaskEpic(ctx context.Context, taskID string, epicID *string) error
+
+	// CreateComment сохраняет комментарий к эпику
+	CreateComment(ctx context.Context, comment *domain.EpicComment) error
+
+	// GetComment возвращает комментарий по ID (nil, если комментарий не найден)
+	GetComment(ctx context.Context, id string) (*domain.EpicComment, error)
+
+	// ListComments возвращает комментарии эпика в порядке создания
+	ListComments(ctx context.Context, epicID string) ([]*domain.EpicComment, error)
+
+	// DeleteComment удаляет комментарий к эпику
+	DeleteComment(ctx context.Context, id string) error
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+)
+
+// FeedbackRepository определяет интерфейс для работы с порталами обратной связи и запросами
+type FeedbackRepository interface {
+	// GetPortal возвращает портал проекта (nil, если портал не настроен)
+	GetPortal(ctx context.Context, projectID string) (*domain.FeedbackPortal, error)
+
+	// GetPortalByKey возвращает портал по публичному ключу (nil, если портал не найден)
+	GetPortalByKey(ctx context.Context, publicKey string) (*domain.FeedbackPortal, error)
+
+	// SavePortal создает или обновляет портал проекта
+	SavePortal(ctx context.Context, portal *domain.FeedbackPortal) error
+
+	// DeletePortal удаляет портал проекта; отправленные запросы сохраняются
+	DeletePortal(ctx context.Context, projectID string) error
+
+	// CreateSubmission сохраняет новый запрос
+	CreateSubmission(ctx context.Context, submission *domain.FeedbackSubmission) error
+
+	// GetSubmission возвращает запрос по ID (nil, если запрос не найден)
+	GetSubmission(ctx context.Context, id string) (*domain.FeedbackSubmission, error)
+
+	// ListSubmissions возвращает запросы проекта, сначала новые
+	ListSubmissions(ctx context.Context, projectID string, filter FeedbackFilter) ([]*domain.FeedbackSubmission, error)
+
+	// CountSubmissions возвращает количество запросов проекта по фильтру
+	CountSubmissions(ctx context.Context, projectID string, filter FeedbackFilter) (int, error)
+
+	// CountPendingByEmail возвращает количество запросов автора, ожидающих модерации
+	CountPendingByEmail(ctx context.Context, projectID string, email string) (int, error)
+
+	// ModerateSubmission сохраняет решение модератора, если запрос еще ожидает модерации.
+	// Возвращает false, если запрос уже обработан
+	ModerateSubmission(ctx context.Context, submission *domain.FeedbackSubmission) (bool, error)
+
+	// ListSubmissionsByTask возвращает принятые и объединенные запросы, связанные с задачей
+	ListSubmissionsByTask(ctx context.Context, taskID string) ([]*domain.FeedbackSubmission, error)
+
+	// SetNotifiedTaskStatus запоминает статус задачи, о котором сообщили автору запроса
+	SetNotifiedTaskStatus(ctx context.Context, id string, status domain.TaskStatus) error
+
+	// FindSimilarTasks возвращает задачи проекта с похожими названиями
+	FindSimilarTasks(ctx context.Context, projectID string, title string, limit int) ([]*domain.TypeaheadItem, error)
+}
+
+// FeedbackFilter представляет фильтр очереди запросов
+type FeedbackFilter struct {
+	Status *domain.FeedbackStatus
+	Limit  int
+	Offset int
+}
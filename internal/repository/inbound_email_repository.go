@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+)
+
+// InboundEmailRepository определяет интерфейс для работы с адресами проектов для создания задач по email
+type InboundEmailRepository interface {
+	// GetByProject возвращает адрес проекта (nil, если адрес не создан)
+	GetByProject(ctx context.Context, projectID string) (*domain.ProjectInboundEmail, error)
+
+	// GetByToken возвращает адрес проекта по токену (nil, если токен неизвестен)
+	GetByToken(ctx context.Context, token string) (*domain.ProjectInboundEmail, error)
+
+	// Save создает или обновляет адрес проекта
+	Save(ctx context.Context, inbound *domain.ProjectInboundEmail) error
+
+	// Delete удаляет адрес проекта
+	Delete(ctx context.Context, projectID string) error
+
+	// IsMessageProcessed проверяет, было ли письмо уже обработано
+	IsMessageProcessed(ctx context.Context, messageID string) (bool, error)
+
+	// RecordMessage сохраняет запись об обработанном письме
+	RecordMessage(ctx context.Context, message *domain.InboundEmailMessage) error
+}
@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+)
+
+// InboxRepository определяет интерфейс для работы со входящими задачами пользователей
+type InboxRepository interface {
+	// Create записывает задачу во входящие
+	Create(ctx context.Context, item *domain.InboxItem) error
+
+	// GetByID возвращает запись входящих по ID (nil, если она не найдена)
+	GetByID(ctx context.Context, id string) (*domain.InboxItem, error)
+
+	// ListByUser возвращает входящие пользователя, начиная со старых
+	ListByUser(ctx context.Context, userID string, limit, offset int) ([]*domain.InboxItem, error)
+
+	// CountByUser возвращает количество записей во входящих пользователя
+	CountByUser(ctx context.Context, userID string) (int, error)
+
+	// Delete удаляет запись входящих
+	Delete(ctx context.Context, id string) error
+
+	// Claim удаляет запись входящих пользователя и возвращает ее. Возвращает nil, если записи
+	// нет или ее уже забрал другой запрос
+	Claim(ctx context.Context, id string, userID string) (*domain.InboxItem, error)
+
+	// ListStaleOwners возвращает пользователей, у которых во входящих есть записи,
+	// созданные раньше createdBefore, с количеством таких записей
+	ListStaleOwners(ctx context.Context, createdBefore time.Time) ([]*domain.InboxReminder, error)
+}
@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+)
+
+// IncidentRepository определяет интерфейс для работы с интеграциями систем управления инцидентами
+type IncidentRepository interface {
+	// GetIntegration возвращает интеграцию по ID (nil, если интеграция не найдена)
+	GetIntegration(ctx context.Context, id string) (*domain.IncidentIntegration, error)
+
+	// GetIntegrationByProject возвращает интеграцию проекта с системой (nil, если интеграция не настроена)
+	GetIntegrationByProject(ctx context.Context, projectID string, provider domain.IncidentProvider) (*domain.IncidentIntegration, error)
+
+	// ListIntegrations возвращает интеграции проекта
+	ListIntegrations(ctx context.Context, projectID string) ([]*domain.IncidentIntegration, error)
+
+	// SaveIntegration создает или обновляет интеграцию проекта с системой
+	SaveIntegration(ctx context.Context, integration *domain.IncidentIntegration) error
+
+	// DeleteIntegration удаляет интеграцию вместе со связями инцидентов
+	DeleteIntegration(ctx context.Context, id string) error
+
+	// GetLink возвращает связь инцидента с задачей (nil, если инцидент не связан)
+	GetLink(ctx context.Context, integrationID, incidentID string) (*domain.IncidentLink, error)
+
+	// GetLinkByTask возвращает связь задачи с инцидентом (nil, если задача не связана)
+	GetLinkByTask(ctx context.Context, taskID string) (*domain.IncidentLink, error)
+
+	// CreateLink сохраняет связь инцидента с задачей
+	CreateLink(ctx context.Context, link *domain.IncidentLink) error
+
+	// UpdateLinkStatus переводит связь в новое состояние инцидента, если она находится в одном из ожидаемых состояний.
+	// Возвращает false, если состояние уже изменено
+	UpdateLinkStatus(ctx context.Context, integrationID, incidentID string, from []domain.IncidentStatus, to domain.IncidentStatus) (bool, error)
+}
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+)
+
+// JobRunRepository определяет интерфейс для работы с историей запусков планировщика
+type JobRunRepository interface {
+	// Create сохраняет начатый запуск
+	Create(ctx context.Context, run *domain.JobRun) error
+
+	// Finish сохраняет итоги запуска: состояние, время окончания, счетчики и ошибку
+	Finish(ctx context.Context, run *domain.JobRun) error
+
+	// MarkOverran помечает выполняющийся запуск как превысивший интервал расписания
+	MarkOverran(ctx context.Context, id string) error
+
+	// FailRunning завершает с ошибкой запуски, начатые до before и оставшиеся выполняющимися,
+	// например после остановки планировщика. Возвращает число таких запусков
+	FailRunning(ctx context.Context, before time.Time, reason string) (int, error)
+
+	// List возвращает запуски с фильтрацией, начиная с последних
+	List(ctx context.Context, filter JobRunFilter) ([]*domain.JobRun, error)
+
+	// Count возвращает количество запусков с фильтрацией
+	Count(ctx context.Context, filter JobRunFilter) (int, error)
+
+	// ListLatest возвращает последний запуск каждой задачи
+	ListLatest(ctx context.Context) ([]*domain.JobRun, error)
+
+	// DeleteBefore удаляет запуски, начатые до before, и возвращает их количество
+	DeleteBefore(ctx context.Context, before time.Time) (int, error)
+}
+
+// JobRunFilter содержит параметры для фильтрации истории запусков
+type JobRunFilter struct {
+	Job     *string
+	Status  *domain.JobRunStatus
+	Overran bool
+	Limit   int
+	Offset  int
+}
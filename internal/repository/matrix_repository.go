@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+)
+
+// MatrixRepository определяет интерфейс для работы с комнатами Matrix проектов
+type MatrixRepository interface {
+	// GetProjectRoom возвращает комнату проекта (nil, если комната не настроена)
+	GetProjectRoom(ctx context.Context, projectID string) (*domain.ProjectMatrixRoom, error)
+
+	// SaveProjectRoom создает или обновляет комнату проекта
+	SaveProjectRoom(ctx context.Context, room *domain.ProjectMatrixRoom) error
+
+	// DeleteProjectRoom удаляет комнату проекта
+	DeleteProjectRoom(ctx context.Context, projectID string) error
+}
@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+)
+
+// MeetingNoteRepository определяет интерфейс для работы с заметками встреч
+type MeetingNoteRepository interface {
+	// Create сохраняет заметку
+	Create(ctx context.Context, note *domain.MeetingNote) error
+
+	// GetByID возвращает заметку по ID (nil, если заметка не найдена)
+	GetByID(ctx context.Context, id string) (*domain.MeetingNote, error)
+
+	// Update обновляет заметку
+	Update(ctx context.Context, note *domain.MeetingNote) error
+
+	// Delete удаляет заметку
+	Delete(ctx context.Context, id string) error
+
+	// ListByProject возвращает заметки проекта, сначала последние встречи
+	ListByProject(ctx context.Context, projectID string, limit, offset int) ([]*domain.MeetingNote, error)
+
+	// CountByProject возвращает количество заметок проекта
+	CountByProject(ctx context.Context, projectID string) (int, error)
+
+	// CreateTaskLink сохраняет связь задачи с пунктом заметки, из которого она создана
+	CreateTaskLink(ctx context.Context, link *domain.MeetingNoteTask) error
+
+	// ListTaskLinks возвращает задачи, созданные из пунктов заметки
+	ListTaskLinks(ctx context.Context, noteID string) ([]*domain.MeetingNoteTask, error)
+
+	// GetByTask возвращает заметку, из которой создана задача (nil, если такой нет)
+	GetByTask(ctx context.Context, taskID string) (*domain.MeetingNote, error)
+}
@@ -0,0 +1,152 @@
+package memo
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// flightTimeout ограничивает общее чтение: оно не зависит от отмены запроса,
+// который его начал, поэтому нуждается в собственном сроке
+const flightTimeout = 5 * time.Second
+
+// memoKey - ключ хранилища результатов в контексте запроса
+type memoKey struct{}
+
+// store хранит результаты чтений в пределах одного запроса
+type store struct {
+	mu      sync.Mutex
+	entries map[string]interface{}
+}
+
+// WithMemo возвращает контекст с пустым хранилищем результатов чтений.
+// Повторные чтения одной сущности в рамках контекста не обращаются к базе
+func WithMemo(ctx context.Context) context.Context {
+	return context.WithValue(ctx, memoKey{}, &store{entries: make(map[string]interface{})})
+}
+
+// fromContext возвращает хранилище запроса или nil, если оно не подключено
+func fromContext(ctx context.Context) *store {
+	s, _ := ctx.Value(memoKey{}).(*store)
+	return s
+}
+
+// get возвращает сохраненный результат
+func (s *store) get(key string) (interface{}, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	value, ok := s.entries[key]
+	return value, ok
+}
+
+// set сохраняет результат
+func (s *store) set(key string, value interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[key] = value
+}
+
+// forget удаляет результаты, ключи которых начинаются с prefix
+func (s *store) forget(prefix string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key := range s.entries {
+		if strings.HasPrefix(key, prefix) {
+			delete(s.entries, key)
+		}
+	}
+}
+
+// flightCall - выполняющееся чтение, результат которого ждут все одновременные вызовы
+type flightCall struct {
+	done  chan struct{}
+	value interface{}
+	err   error
+}
+
+// flightGroup объединяет одновременные чтения одного ключа в одно обращение к базе,
+// чтобы промахи кэша под нагрузкой не создавали лавину одинаковых запросов
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall
+}
+
+// do выполняет fn для ключа; вызовы, пришедшие во время выполнения, получают тот же результат.
+// fn получает контекст без отмены вызова-инициатора, чтобы его отмена или дедлайн не
+// превращались в ошибку для остальных ожидающих. Каждый вызов ждет результата не дольше
+// собственного контекста
+func (g *flightGroup) do(ctx context.Context, key string, fn func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*flightCall)
+	}
+	if call, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		select {
+		case <-call.done:
+			return call.value, call.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	call := &flightCall{done: make(chan struct{})}
+	g.calls[key] = call
+	g.mu.Unlock()
+
+	defer func() {
+		g.mu.Lock()
+		if g.calls[key] == call {
+			delete(g.calls, key)
+		}
+		g.mu.Unlock()
+		close(call.done)
+	}()
+
+	flightCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), flightTimeout)
+	defer cancel()
+
+	call.value, call.err = fn(flightCtx)
+	return call.value, call.err
+}
+
+// forget отвязывает выполняющиеся чтения с ключами, начинающимися с prefix:
+// вызовы после изменения данных не присоединятся к чтению, начатому до него
+func (g *flightGroup) forget(prefix string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for key := range g.calls {
+		if strings.HasPrefix(key, prefix) {
+			delete(g.calls, key)
+		}
+	}
+}
+
+// load возвращает результат из хранилища запроса, а при его отсутствии выполняет чтение,
+// объединяя его с одновременными чтениями того же ключа. Ошибки не сохраняются
+func load(ctx context.Context, group *flightGroup, key string, fn func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	s := fromContext(ctx)
+	if s != nil {
+		if value, ok := s.get(key); ok {
+			return value, nil
+		}
+	}
+
+	value, err := group.do(ctx, key, fn)
+	if err != nil {
+		return nil, err
+	}
+
+	if s != nil {
+		s.set(key, value)
+	}
+	return value, nil
+}
+
+// forget удаляет результаты из хранилища запроса и отвязывает выполняющиеся чтения
+func forget(ctx context.Context, group *flightGroup, prefix string) {
+	if s := fromContext(ctx); s != nil {
+		s.forget(prefix)
+	}
+	group.forget(prefix)
+}
@@ -0,0 +1,78 @@
+package memo
+
+import (
+	"context"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/internal/repository"
+)
+
+// ProjectRepository запоминает участников проектов, прочитанных через GetMember, в пределах
+// запроса и объединяет одновременные чтения одного участника. Остальные методы
+// передаются вложенному репозиторию
+type ProjectRepository struct {
+	repository.ProjectRepository
+	flights flightGroup
+}
+
+// NewProjectRepository создает новый экземпляр ProjectRepository
+func NewProjectRepository(repo repository.ProjectRepository) *ProjectRepository {
+	return &ProjectRepository{ProjectRepository: repo}
+}
+
+// GetMember возвращает участника проекта (nil, если пользователь не участник).
+// Каждый вызов получает собственную копию
+func (r *ProjectRepository) GetMember(ctx context.Context, projectID, userID string) (*domain.ProjectMember, error) {
+	value, err := load(ctx, &r.flights, memberKey(projectID, userID), func(ctx context.Context) (interface{}, error) {
+		return r.ProjectRepository.GetMember(ctx, projectID, userID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	member := value.(*domain.ProjectMember)
+	if member == nil {
+		return nil, nil
+	}
+	memberCopy := *member
+	return &memberCopy, nil
+}
+
+// Delete удаляет проект вместе с участниками
+func (r *ProjectRepository) Delete(ctx context.Context, id string) error {
+	defer forget(ctx, &r.flights, memberKey(id, ""))
+	return r.ProjectRepository.Delete(ctx, id)
+}
+
+// AddMember добавляет участника в проект
+func (r *ProjectRepository) AddMember(ctx context.Context, member *domain.ProjectMember) error {
+	defer forget(ctx, &r.flights, memberKey(member.ProjectID, member.UserID))
+	return r.ProjectRepository.AddMember(ctx, member)
+}
+
+// AddMembers добавляет участников в проекты
+func (r *ProjectRepository) AddMembers(ctx context.Context, members []*domain.ProjectMember) error {
+	defer func() {
+		for _, member := range members {
+			forget(ctx, &r.flights, memberKey(member.ProjectID, member.UserID))
+		}
+	}()
+	return r.ProjectRepository.AddMembers(ctx, members)
+}
+
+// UpdateMember обновляет роль участника проекта
+func (r *ProjectRepository) UpdateMember(ctx context.Context, projectID, userID string, role domain.ProjectRole) error {
+	defer forget(ctx, &r.flights, memberKey(projectID, userID))
+	return r.ProjectRepository.UpdateMember(ctx, projectID, userID, role)
+}
+
+// RemoveMember удаляет участника из проекта
+func (r *ProjectRepository) RemoveMember(ctx context.Context, projectID, userID string) error {
+	defer forget(ctx, &r.flights, memberKey(projectID, userID))
+	return r.ProjectRepository.RemoveMember(ctx, projectID, userID)
+}
+
+// memberKey возвращает ключ участника проекта; с пустым userID - префикс всех участников проекта
+func memberKey(projectID, userID string) string {
+	return "member:" + projectID + ":" + userID
+}
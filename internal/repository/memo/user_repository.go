@@ -0,0 +1,109 @@
+package memo
+
+import (
+	"context"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/internal/repository"
+)
+
+// UserRepository запоминает пользователей, прочитанных по ID, в пределах запроса
+// и объединяет одновременные чтения одного пользователя. Остальные методы
+// передаются вложенному репозиторию
+type UserRepository struct {
+	repository.UserRepository
+	flights flightGroup
+}
+
+// NewUserRepository создает новый экземпляр UserRepository
+func NewUserRepository(repo repository.UserRepository) *UserRepository {
+	return &UserRepository{UserRepository: repo}
+}
+
+// GetByID возвращает пользователя по ID. Каждый вызов получает собственную копию
+func (r *UserRepository) GetByID(ctx context.Context, id string) (*domain.User, error) {
+	value, err := load(ctx, &r.flights, userKey(id), func(ctx context.Context) (interface{}, error) {
+		return r.UserRepository.GetByID(ctx, id)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	user := value.(*domain.User)
+	if user == nil {
+		return nil, nil
+	}
+	userCopy := *user
+	return &userCopy, nil
+}
+
+// GetByIDs возвращает пользователей по списку ID. Запомненные в запросе пользователи
+// берутся из хранилища, остальные загружаются одним запросом и запоминаются
+func (r *UserRepository) GetByIDs(ctx context.Context, ids []string) (map[string]*domain.User, error) {
+	s := fromContext(ctx)
+	if s == nil {
+		return r.UserRepository.GetByIDs(ctx, ids)
+	}
+
+	users := make(map[string]*domain.User, len(ids))
+
+	missing := make([]string, 0, len(ids))
+	for _, id := range ids {
+		value, ok := s.get(userKey(id))
+		if !ok {
+			missing = append(missing, id)
+			continue
+		}
+		if user := value.(*domain.User); user != nil {
+			userCopy := *user
+			users[id] = &userCopy
+		}
+	}
+	if len(missing) == 0 {
+		return users, nil
+	}
+
+	loaded, err := r.UserRepository.GetByIDs(ctx, missing)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range missing {
+		user := loaded[id]
+		s.set(userKey(id), user)
+		if user != nil {
+			userCopy := *user
+			users[id] = &userCopy
+		}
+	}
+
+	return users, nil
+}
+
+// Update обновляет данные пользователя
+func (r *UserRepository) Update(ctx context.Context, user *domain.User) error {
+	defer forget(ctx, &r.flights, userKey(user.ID))
+	return r.UserRepository.Update(ctx, user)
+}
+
+// Delete удаляет пользователя
+func (r *UserRepository) Delete(ctx context.Context, id string) error {
+	defer forget(ctx, &r.flights, userKey(id))
+	return r.UserRepository.Delete(ctx, id)
+}
+
+// UpdateLastLogin обновляет время последнего входа пользователя
+func (r *UserRepository) UpdateLastLogin(ctx context.Context, id string) error {
+	defer forget(ctx, &r.flights, userKey(id))
+	return r.UserRepository.UpdateLastLogin(ctx, id)
+}
+
+// UpdateManager назначает или снимает руководителя пользователя
+func (r *UserRepository) UpdateManager(ctx context.Context, userID string, managerID *string) error {
+	defer forget(ctx, &r.flights, userKey(userID))
+	return r.UserRepository.UpdateManager(ctx, userID, managerID)
+}
+
+// userKey возвращает ключ пользователя
+func userKey(id string) string {
+	return "user:" + id
+}
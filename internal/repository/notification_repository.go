@@ -36,17 +36,32 @@ type NotificationRepository interface {
 	// MarkAllAsRead отмечает все уведомления пользователя как прочитанные
 	MarkAllAsRead(ctx context.Context, userID string) error
 
+	// MarkAsReadByFilter отмечает прочитанными непрочитанные уведомления пользователя,
+	// подходящие под фильтр, и возвращает их количество
+	MarkAsReadByFilter(ctx context.Context, userID string, filter NotificationFilter) (int, error)
+
 	// DeleteAllByUser удаляет все уведомления пользователя
 	DeleteAllByUser(ctx context.Context, userID string) error
 
 	// GetUserUnreadCount возвращает количество непрочитанных уведомлений пользователя
 	GetUserUnreadCount(ctx context.Context, userID string) (int, error)
 
+	// GetUnreadCounts возвращает количество непрочитанных уведомлений указанных пользователей.
+	// Пользователи без непрочитанных уведомлений в результат не попадают
+	GetUnreadCounts(ctx context.Context, userIDs []string) (map[string]int, error)
+
 	// GetUserNotificationSettings возвращает настройки уведомлений пользователя
 	GetUserNotificationSettings(ctx context.Context, userID string) ([]*NotificationSetting, error)
 
+	// GetSettingsForUsers возвращает настройки уведомлений нескольких пользователей одним запросом.
+	// Пользователи без настроек в результат не попадают
+	GetSettingsForUsers(ctx context.Context, userIDs []string) (map[string][]*NotificationSetting, error)
+
 	// UpdateUserNotificationSettings обновляет настройки уведомлений пользователя
 	UpdateUserNotificationSettings(ctx context.Context, userID string, settings []*NotificationSetting) error
+
+	// SetEmailEnabled включает или отключает email-уведомления указанного типа для пользователя
+	SetEmailEnabled(ctx context.Context, userID string, notificationType domain.NotificationType, enabled bool) error
 }
 
 // NotificationSetting представляет настройки уведомлений для пользователя
@@ -56,6 +71,7 @@ type NotificationSetting struct {
 	EmailEnabled     bool                     `json:"email_enabled" db:"email_enabled"`
 	WebEnabled       bool                     `json:"web_enabled" db:"web_enabled"`
 	TelegramEnabled  bool                     `json:"telegram_enabled" db:"telegram_enabled"`
+	TeamsEnabled     bool                     `json:"teams_enabled" db:"teams_enabled"`
 }
 
 // NotificationFilter содержит параметры для фильтрации уведомлений
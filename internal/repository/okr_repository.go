@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+)
+
+// OKRRepository определяет интерфейс для работы с целями и ключевыми результатами
+type OKRRepository interface {
+	// CreateObjective создает новую цель
+	CreateObjective(ctx context.Context, objective *domain.Objective) error
+
+	// GetObjective возвращает цель по ID (nil, если цель не найдена)
+	GetObjective(ctx context.Context, id string) (*domain.Objective, error)
+
+	// UpdateObjective обновляет цель
+	UpdateObjective(ctx context.Context, objective *domain.Objective) error
+
+	// DeleteObjective удаляет цель вместе с ключевыми результатами
+	DeleteObjective(ctx context.Context, id string) error
+
+	// ListObjectives возвращает цели квартала; нулевые year и quarter отключают фильтр
+	ListObjectives(ctx context.Context, year, quarter int) ([]*domain.Objective, error)
+
+	// CreateKeyResult создает ключевой результат
+	CreateKeyResult(ctx context.Context, keyResult *domain.KeyResult) error
+
+	// GetKeyResult возвращает ключевой результат по ID (nil, если он не найден)
+	GetKeyResult(ctx context.Context, id string) (*domain.KeyResult, error)
+
+	// UpdateKeyResult обновляет ключевой результат
+	UpdateKeyResult(ctx context.Context, keyResult *domain.KeyResult) error
+
+	// DeleteKeyResult удаляет ключевой результат
+	DeleteKeyResult(ctx context.Context, id string) error
+
+	// ListKeyResults возвращает ключевые результаты указанных целей
+	ListKeyResults(ctx context.Context, objectiveIDs []string) ([]*domain.KeyResult, error)
+
+	// CreateLink связывает ключевой результат с проектом или эпиком.
+	// Возвращает false, если такая связь уже есть
+	CreateLink(ctx context.Context, link *domain.KeyResultLink) (bool, error)
+
+	// GetLink возвращает связь по ID (nil, если связь не найдена)
+	GetLink(ctx context.Context, id string) (*domain.KeyResultLink, error)
+
+	// DeleteLink удаляет связь
+	DeleteLink(ctx context.Context, id string) error
+
+	// ListLinks возвращает связи указанных ключевых результатов
+	ListLinks(ctx context.Context, keyResultIDs []string) ([]*domain.KeyResultLink, error)
+
+	// CountKeyResultTasks возвращает число неотмененных и завершенных задач связанных проектов и эпиков.
+	// Задача, попадающая в несколько связей одного ключевого результата, учитывается один раз
+	CountKeyResultTasks(ctx context.Context, keyResultIDs []string) ([]*domain.KeyResultTaskCount, error)
+}
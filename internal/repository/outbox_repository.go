@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+)
+
+// OutboxRepository определяет интерфейс для работы с таблицей исходящих событий
+type OutboxRepository interface {
+	// Add записывает событие; вызванный внутри TxManager.WithinTx, записывает его в той же транзакции
+	Add(ctx context.Context, message *domain.OutboxMessage) error
+
+	// ClaimPending возвращает самые старые неопубликованные события, блокируя их до конца
+	// транзакции; события, заблокированные другим ретранслятором, пропускаются
+	ClaimPending(ctx context.Context, limit, maxAttempts int) ([]*domain.OutboxMessage, error)
+
+	// Delete удаляет опубликованные события
+	Delete(ctx context.Context, ids []int64) error
+
+	// Fail фиксирует неудачную попытку публикации события
+	Fail(ctx context.Context, id int64, errMsg string) error
+}
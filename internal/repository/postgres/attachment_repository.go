@@ -0,0 +1,184 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/pkg/logger"
+)
+
+// attachmentColumns перечисляет колонки вложения для выборок
+const attachmentColumns = `
+	id, task_id, comment_id, file_name, content_type, size, storage_key, uploaded_by, created_at
+`
+
+// maxAttachmentDeletionAttempts ограничивает число попыток удалить файл из хранилища;
+// после него ключ остается в очереди для ручного разбора
+const maxAttachmentDeletionAttempts = 10
+
+// AttachmentRepository реализует репозиторий вложений с использованием PostgreSQL
+type AttachmentRepository struct {
+	db     *sqlx.DB
+	logger logger.Logger
+}
+
+// NewAttachmentRepository создает новый экземпляр AttachmentRepository
+func NewAttachmentRepository(db *sqlx.DB, logger logger.Logger) *AttachmentRepository {
+	return &AttachmentRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Create сохраняет метаданные вложения
+func (r *AttachmentRepository) Create(ctx context.Context, attachment *domain.Attachment) error {
+	query := `
+		INSERT INTO attachments (
+			id, task_id, comment_id, file_name, content_type, size, storage_key, uploaded_by, created_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9
+		)
+	`
+
+	_, err := conn(ctx, r.db).ExecContext(
+		ctx,
+		query,
+		attachment.ID,
+		attachment.TaskID,
+		attachment.CommentID,
+		attachment.FileName,
+		attachment.ContentType,
+		attachment.Size,
+		attachment.StorageKey,
+		attachment.UploadedBy,
+		attachment.CreatedAt,
+	)
+	if err != nil {
+		r.logger.Error("Failed to create attachment", err, map[string]interface{}{
+			"task_id": attachment.TaskID,
+		})
+		return fmt.Errorf("failed to create attachment: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID возвращает вложение по ID
+func (r *AttachmentRepository) GetByID(ctx context.Context, id string) (*domain.Attachment, error) {
+	query := `SELECT ` + attachmentColumns + ` FROM attachments WHERE id = $1`
+
+	var attachment domain.Attachment
+	if err := conn(ctx, r.db).GetContext(ctx, &attachment, query, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		r.logger.Error("Failed to get attachment", err, map[string]interface{}{
+			"id": id,
+		})
+		return nil, fmt.Errorf("failed to get attachment: %w", err)
+	}
+
+	return &attachment, nil
+}
+
+// ListByTask возвращает вложения задачи и ее комментариев
+func (r *AttachmentRepository) ListByTask(ctx context.Context, taskID string) ([]*domain.Attachment, error) {
+	query := `SELECT ` + attachmentColumns + ` FROM attachments WHERE task_id = $1 ORDER BY created_at, id`
+
+	var attachments []*domain.Attachment
+	if err := conn(ctx, r.db).SelectContext(ctx, &attachments, query, taskID); err != nil {
+		r.logger.Error("Failed to list task attachments", err, map[string]interface{}{
+			"task_id": taskID,
+		})
+		return nil, fmt.Errorf("failed to list task attachments: %w", err)
+	}
+
+	return attachments, nil
+}
+
+// ListByComment возвращает вложения комментария
+func (r *AttachmentRepository) ListByComment(ctx context.Context, commentID string) ([]*domain.Attachment, error) {
+	query := `SELECT ` + attachmentColumns + ` FROM attachments WHERE comment_id = $1 ORDER BY created_at, id`
+
+	var attachments []*domain.Attachment
+	if err := conn(ctx, r.db).SelectContext(ctx, &attachments, query, commentID); err != nil {
+		r.logger.Error("Failed to list comment attachments", err, map[string]interface{}{
+			"comment_id": commentID,
+		})
+		return nil, fmt.Errorf("failed to list comment attachments: %w", err)
+	}
+
+	return attachments, nil
+}
+
+// Delete удаляет вложение. Ключ файла ставит в очередь удаления триггер таблицы,
+// как и при каскадном удалении вместе с задачей или комментарием
+func (r *AttachmentRepository) Delete(ctx context.Context, id string) (bool, error) {
+	result, err := conn(ctx, r.db).ExecContext(ctx, `DELETE FROM attachments WHERE id = $1`, id)
+	if err != nil {
+		r.logger.Error("Failed to delete attachment", err, map[string]interface{}{
+			"id": id,
+		})
+		return false, fmt.Errorf("failed to delete attachment: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// ListPendingDeletions возвращает ключи файлов из очереди удаления, начиная с самых старых.
+// Ключи, попытки удаления которых исчерпаны, пропускаются
+func (r *AttachmentRepository) ListPendingDeletions(ctx context.Context, limit int) ([]string, error) {
+	query := `
+		SELECT storage_key
+		FROM attachment_deletions
+		WHERE attempts < $1
+		ORDER BY created_at
+		LIMIT $2
+	`
+
+	var keys []string
+	if err := conn(ctx, r.db).SelectContext(ctx, &keys, query, maxAttachmentDeletionAttempts, limit); err != nil {
+		r.logger.Error("Failed to list pending attachment deletions", err)
+		return nil, fmt.Errorf("failed to list pending attachment deletions: %w", err)
+	}
+
+	return keys, nil
+}
+
+// CompleteDeletion убирает ключ из очереди удаления
+func (r *AttachmentRepository) CompleteDeletion(ctx context.Context, storageKey string) error {
+	if _, err := conn(ctx, r.db).ExecContext(ctx, `DELETE FROM attachment_deletions WHERE storage_key = $1`, storageKey); err != nil {
+		r.logger.Error("Failed to complete attachment deletion", err, map[string]interface{}{
+			"storage_key": storageKey,
+		})
+		return fmt.Errorf("failed to complete attachment deletion: %w", err)
+	}
+
+	return nil
+}
+
+// FailDeletion увеличивает счетчик попыток удаления файла
+func (r *AttachmentRepository) FailDeletion(ctx context.Context, storageKey string, errMsg string) error {
+	query := `
+		UPDATE attachment_deletions
+		SET attempts = attempts + 1, last_error = $1
+		WHERE storage_key = $2
+	`
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query, errMsg, storageKey); err != nil {
+		r.logger.Error("Failed to record attachment deletion failure", err, map[string]interface{}{
+			"storage_key": storageKey,
+		})
+		return fmt.Errorf("failed to record attachment deletion failure: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,175 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/internal/repository"
+	"github.com/nurlyy/task_manager/pkg/logger"
+)
+
+// auditColumns - колонки журнала аудита в порядке вставки
+const auditColumns = "id, actor_id, action, entity_type, entity_id, changes, ip, route, created_at"
+
+// auditRow представляет строку журнала аудита с изменениями в JSON
+type auditRow struct {
+	domain.AuditRecord
+	Changes []byte `db:"changes"`
+}
+
+// toRecord возвращает запись журнала с разобранными изменениями
+func (row *auditRow) toRecord() (*domain.AuditRecord, error) {
+	record := row.AuditRecord
+	if len(row.Changes) > 0 {
+		if err := json.Unmarshal(row.Changes, &record.Changes); err != nil {
+			return nil, fmt.Errorf("failed to decode audit changes: %w", err)
+		}
+	}
+	return &record, nil
+}
+
+// AuditRepository реализует репозиторий журнала аудита с использованием PostgreSQL
+type AuditRepository struct {
+	db     *sqlx.DB
+	logger logger.Logger
+}
+
+// NewAuditRepository создает новый экземпляр AuditRepository
+func NewAuditRepository(db *sqlx.DB, logger logger.Logger) *AuditRepository {
+	return &AuditRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Create сохраняет записи журнала одним запросом
+func (r *AuditRepository) Create(ctx context.Context, records []*domain.AuditRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	const columnCount = 9
+	placeholders := make([]string, 0, len(records))
+	args := make([]interface{}, 0, len(records)*columnCount)
+	for i, record := range records {
+		var changes []byte
+		if len(record.Changes) > 0 {
+			encoded, err := json.Marshal(record.Changes)
+			if err != nil {
+				return fmt.Errorf("failed to encode audit changes: %w", err)
+			}
+			changes = encoded
+		}
+
+		base := i * columnCount
+		placeholders = append(placeholders, fmt.Sprintf("($%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d)",
+			base+1, base+2, base+3, base+4, base+5, base+6, base+7, base+8, base+9))
+		args = append(args,
+			record.ID,
+			record.ActorID,
+			record.Action,
+			record.EntityType,
+			record.EntityID,
+			changes,
+			record.IP,
+			record.Route,
+			record.CreatedAt,
+		)
+	}
+
+	query := fmt.Sprintf(`INSERT INTO audit_log (%s) VALUES %s`, auditColumns, strings.Join(placeholders, ", "))
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query, args...); err != nil {
+		r.logger.Error("Failed to create audit records", err, map[string]interface{}{
+			"count": len(records),
+		})
+		return fmt.Errorf("failed to create audit records: %w", err)
+	}
+
+	return nil
+}
+
+// List возвращает записи с фильтрацией, начиная с последних
+func (r *AuditRepository) List(ctx context.Context, filter repository.AuditFilter) ([]*domain.AuditRecord, error) {
+	whereClause, args := buildAuditWhere(filter)
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM audit_log
+		%s
+		ORDER BY created_at DESC, id
+		LIMIT %d OFFSET %d
+	`, auditColumns, whereClause, filter.Limit, filter.Offset)
+
+	var rows []auditRow
+	if err := conn(ctx, r.db).SelectContext(ctx, &rows, query, args...); err != nil {
+		r.logger.Error("Failed to list audit records", err)
+		return nil, fmt.Errorf("failed to list audit records: %w", err)
+	}
+
+	records := make([]*domain.AuditRecord, 0, len(rows))
+	for i := range rows {
+		record, err := rows[i].toRecord()
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+// Count возвращает количество записей с фильтрацией
+func (r *AuditRepository) Count(ctx context.Context, filter repository.AuditFilter) (int, error) {
+	whereClause, args := buildAuditWhere(filter)
+
+	query := fmt.Sprintf(`SELECT COUNT(*) FROM audit_log %s`, whereClause)
+
+	var count int
+	if err := conn(ctx, r.db).GetContext(ctx, &count, query, args...); err != nil {
+		r.logger.Error("Failed to count audit records", err)
+		return 0, fmt.Errorf("failed to count audit records: %w", err)
+	}
+
+	return count, nil
+}
+
+// buildAuditWhere формирует условие WHERE для фильтра журнала аудита
+func buildAuditWhere(filter repository.AuditFilter) (string, []interface{}) {
+	conditions := []string{}
+	args := []interface{}{}
+
+	if filter.ActorID != nil {
+		args = append(args, *filter.ActorID)
+		conditions = append(conditions, fmt.Sprintf("actor_id = $%d", len(args)))
+	}
+	if filter.Action != nil {
+		args = append(args, *filter.Action)
+		conditions = append(conditions, fmt.Sprintf("action = $%d", len(args)))
+	}
+	if filter.EntityType != nil {
+		args = append(args, *filter.EntityType)
+		conditions = append(conditions, fmt.Sprintf("entity_type = $%d", len(args)))
+	}
+	if filter.EntityID != nil {
+		args = append(args, *filter.EntityID)
+		conditions = append(conditions, fmt.Sprintf("entity_id = $%d", len(args)))
+	}
+	if filter.From != nil {
+		args = append(args, *filter.From)
+		conditions = append(conditions, fmt.Sprintf("created_at >= $%d", len(args)))
+	}
+	if filter.To != nil {
+		args = append(args, *filter.To)
+		conditions = append(conditions, fmt.Sprintf("created_at <= $%d", len(args)))
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return "WHERE " + strings.Join(conditions, " AND "), args
+}
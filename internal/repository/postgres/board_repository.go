@@ -0,0 +1,274 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/pkg/logger"
+)
+
+// boardColumnColumns перечисляет колонки таблицы board_columns для выборок
+const boardColumnColumns = `id, project_id, name, status, position, created_at, updated_at`
+
+// BoardRepository реализует репозиторий колонок канбан-досок с использованием PostgreSQL
+type BoardRepository struct {
+	db     *sqlx.DB
+	logger logger.Logger
+}
+
+// NewBoardRepository создает новый экземпляр BoardRepository
+func NewBoardRepository(db *sqlx.DB, logger logger.Logger) *BoardRepository {
+	return &BoardRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// SeedColumns создает колонки доски проекта, если у проекта еще нет ни одной колонки.
+// При одновременном заполнении одной доски лишние колонки отбрасываются по уникальности имени
+func (r *BoardRepository) SeedColumns(ctx context.Context, projectID string, columns []*domain.ProjectBoardColumn) error {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				r.logger.Error("Failed to rollback transaction", rbErr)
+			}
+		}
+	}()
+
+	var exists bool
+	if err = tx.GetContext(ctx, &exists, `SELECT EXISTS (SELECT 1 FROM board_columns WHERE project_id = $1)`, projectID); err != nil {
+		return fmt.Errorf("failed to check board columns: %w", err)
+	}
+	if exists {
+		return tx.Rollback()
+	}
+
+	for _, column := range columns {
+		if _, err = tx.ExecContext(ctx, `
+			INSERT INTO board_columns (id, project_id, name, status, position, created_at, updated_at)
+			VALUES ($1, $2, $3, $4::task_status, $5, $6, $7)
+			ON CONFLICT DO NOTHING
+		`, column.ID, projectID, column.Name, column.Status, column.Position, column.CreatedAt, column.UpdatedAt); err != nil {
+			r.logger.Error("Failed to seed board column", err, map[string]interface{}{
+				"project_id": projectID,
+				"name":       column.Name,
+			})
+			return fmt.Errorf("failed to seed board column: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// CreateColumn создает колонку в конце доски
+func (r *BoardRepository) CreateColumn(ctx context.Context, column *domain.ProjectBoardColumn) error {
+	query := `
+		INSERT INTO board_columns (id, project_id, name, status, position, created_at, updated_at)
+		VALUES (
+			$1, $2, $3, $4::task_status,
+			(SELECT COALESCE(MAX(position), -1) + 1 FROM board_columns WHERE project_id = $2),
+			$5, $6
+		)
+		RETURNING position
+	`
+
+	err := conn(ctx, r.db).GetContext(ctx, &column.Position, query,
+		column.ID, column.ProjectID, column.Name, column.Status, column.CreatedAt, column.UpdatedAt)
+	if err != nil {
+		r.logger.Error("Failed to create board column", err, map[string]interface{}{
+			"project_id": column.ProjectID,
+			"name":       column.Name,
+		})
+		return fmt.Errorf("failed to create board column: %w", err)
+	}
+
+	return nil
+}
+
+// GetColumn возвращает колонку по ID (nil, если колонка не найдена)
+func (r *BoardRepository) GetColumn(ctx context.Context, id string) (*domain.ProjectBoardColumn, error) {
+	query := `SELECT ` + boardColumnColumns + ` FROM board_columns WHERE id = $1`
+
+	var column domain.ProjectBoardColumn
+	if err := conn(ctx, r.db).GetContext(ctx, &column, query, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		r.logger.Error("Failed to get board column", err, map[string]interface{}{
+			"id": id,
+		})
+		return nil, fmt.Errorf("failed to get board column: %w", err)
+	}
+
+	return &column, nil
+}
+
+// ListColumns возвращает колонки доски проекта по порядку
+func (r *BoardRepository) ListColumns(ctx context.Context, projectID string) ([]*domain.ProjectBoardColumn, error) {
+	query := `SELECT ` + boardColumnColumns + ` FROM board_columns WHERE project_id = $1 ORDER BY position, created_at`
+
+	var columns []*domain.ProjectBoardColumn
+	if err := conn(ctx, r.db).SelectContext(ctx, &columns, query, projectID); err != nil {
+		r.logger.Error("Failed to list board columns", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return nil, fmt.Errorf("failed to list board columns: %w", err)
+	}
+
+	return columns, nil
+}
+
+// UpdateColumn обновляет название и статус колонки. Колонка со статусом не хранит
+// перенесенных задач: они возвращаются в колонки своих статусов
+func (r *BoardRepository) UpdateColumn(ctx context.Context, column *domain.ProjectBoardColumn) error {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				r.logger.Error("Failed to rollback transaction", rbErr)
+			}
+		}
+	}()
+
+	if _, err = tx.ExecContext(ctx,
+		`UPDATE board_columns SET name = $1, status = $2::task_status, updated_at = $3 WHERE id = $4`,
+		column.Name, column.Status, column.UpdatedAt, column.ID,
+	); err != nil {
+		r.logger.Error("Failed to update board column", err, map[string]interface{}{
+			"id": column.ID,
+		})
+		return fmt.Errorf("failed to update board column: %w", err)
+	}
+
+	if column.Status != nil {
+		if _, err = tx.ExecContext(ctx, `DELETE FROM board_column_tasks WHERE column_id = $1`, column.ID); err != nil {
+			r.logger.Error("Failed to release board column tasks", err, map[string]interface{}{
+				"id": column.ID,
+			})
+			return fmt.Errorf("failed to release board column tasks: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteColumn удаляет колонку; перенесенные в нее задачи возвращаются в колонки своих статусов
+func (r *BoardRepository) DeleteColumn(ctx context.Context, id string) error {
+	if _, err := conn(ctx, r.db).ExecContext(ctx, `DELETE FROM board_columns WHERE id = $1`, id); err != nil {
+		r.logger.Error("Failed to delete board column", err, map[string]interface{}{
+			"id": id,
+		})
+		return fmt.Errorf("failed to delete board column: %w", err)
+	}
+
+	return nil
+}
+
+// ReorderColumns расставляет колонки проекта в указанном порядке
+func (r *BoardRepository) ReorderColumns(ctx context.Context, projectID string, columnIDs []string) error {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				r.logger.Error("Failed to rollback transaction", rbErr)
+			}
+		}
+	}()
+
+	for position, id := range columnIDs {
+		if _, err = tx.ExecContext(ctx,
+			`UPDATE board_columns SET position = $1 WHERE id = $2 AND project_id = $3`,
+			position, id, projectID,
+		); err != nil {
+			r.logger.Error("Failed to reorder board columns", err, map[string]interface{}{
+				"project_id": projectID,
+			})
+			return fmt.Errorf("failed to reorder board columns: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// ListPlacements возвращает колонки без статуса, в которые перенесены задачи проекта, по ID задач
+func (r *BoardRepository) ListPlacements(ctx context.Context, projectID string) (map[string]string, error) {
+	query := `
+		SELECT p.task_id, p.column_id
+		FROM board_column_tasks p
+		JOIN board_columns c ON c.id = p.column_id
+		WHERE c.project_id = $1
+	`
+
+	var rows []struct {
+		TaskID   string `db:"task_id"`
+		ColumnID string `db:"column_id"`
+	}
+	if err := conn(ctx, r.db).SelectContext(ctx, &rows, query, projectID); err != nil {
+		r.logger.Error("Failed to list board placements", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return nil, fmt.Errorf("failed to list board placements: %w", err)
+	}
+
+	placements := make(map[string]string, len(rows))
+	for _, row := range rows {
+		placements[row.TaskID] = row.ColumnID
+	}
+
+	return placements, nil
+}
+
+// SetPlacement переносит задачу в колонку без статуса
+func (r *BoardRepository) SetPlacement(ctx context.Context, taskID, columnID string) error {
+	query := `
+		INSERT INTO board_column_tasks (task_id, column_id) VALUES ($1, $2)
+		ON CONFLICT (task_id) DO UPDATE SET column_id = EXCLUDED.column_id
+	`
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query, taskID, columnID); err != nil {
+		r.logger.Error("Failed to set board placement", err, map[string]interface{}{
+			"task_id":   taskID,
+			"column_id": columnID,
+		})
+		return fmt.Errorf("failed to set board placement: %w", err)
+	}
+
+	return nil
+}
+
+// DeletePlacement возвращает задачу в колонку ее статуса
+func (r *BoardRepository) DeletePlacement(ctx context.Context, taskID string) error {
+	if _, err := conn(ctx, r.db).ExecContext(ctx, `DELETE FROM board_column_tasks WHERE task_id = $1`, taskID); err != nil {
+		r.logger.Error("Failed to delete board placement", err, map[string]interface{}{
+			"task_id": taskID,
+		})
+		return fmt.Errorf("failed to delete board placement: %w", err)
+	}
+
+	return nil
+}
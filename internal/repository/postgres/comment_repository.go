@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/nurlyy/task_manager/internal/domain"
 	"github.com/nurlyy/task_manager/internal/repository"
 	"github.com/nurlyy/task_manager/pkg/logger"
@@ -31,18 +32,19 @@ func NewCommentRepository(db *sqlx.DB, logger logger.Logger) *CommentRepository
 func (r *CommentRepository) Create(ctx context.Context, comment *domain.Comment) error {
 	query := `
 		INSERT INTO comments (
-			id, task_id, user_id, content, created_at, updated_at
+			id, task_id, user_id, parent_comment_id, content, created_at, updated_at
 		) VALUES (
-			$1, $2, $3, $4, $5, $6
+			$1, $2, $3, $4, $5, $6, $7
 		) RETURNING id
 	`
 
-	err := r.db.QueryRowxContext(
+	err := conn(ctx, r.db).QueryRowxContext(
 		ctx,
 		query,
 		comment.ID,
 		comment.TaskID,
 		comment.UserID,
+		comment.ParentCommentID,
 		comment.Content,
 		comment.CreatedAt,
 		comment.UpdatedAt,
@@ -63,13 +65,13 @@ func (r *CommentRepository) Create(ctx context.Context, comment *domain.Comment)
 func (r *CommentRepository) GetByID(ctx context.Context, id string) (*domain.Comment, error) {
 	query := `
 		SELECT 
-			id, task_id, user_id, content, created_at, updated_at
+			id, task_id, user_id, parent_comment_id, content, created_at, updated_at
 		FROM comments 
 		WHERE id = $1
 	`
 
 	var comment domain.Comment
-	err := r.db.GetContext(ctx, &comment, query, id)
+	err := conn(ctx, r.db).GetContext(ctx, &comment, query, id)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -95,7 +97,7 @@ func (r *CommentRepository) Update(ctx context.Context, comment *domain.Comment)
 
 	comment.UpdatedAt = time.Now()
 
-	result, err := r.db.ExecContext(
+	result, err := conn(ctx, r.db).ExecContext(
 		ctx,
 		query,
 		comment.Content,
@@ -126,7 +128,7 @@ func (r *CommentRepository) Update(ctx context.Context, comment *domain.Comment)
 func (r *CommentRepository) Delete(ctx context.Context, id string) error {
 	query := `DELETE FROM comments WHERE id = $1`
 
-	result, err := r.db.ExecContext(ctx, query, id)
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, id)
 	if err != nil {
 		r.logger.Error("Failed to delete comment", err, map[string]interface{}{
 			"id": id,
@@ -154,7 +156,7 @@ func (r *CommentRepository) List(ctx context.Context, filter repository.CommentF
 
 	query := fmt.Sprintf(`
 		SELECT 
-			id, task_id, user_id, content, created_at, updated_at
+			id, task_id, user_id, parent_comment_id, content, created_at, updated_at
 		FROM comments
 		%s
 		%s
@@ -162,7 +164,7 @@ func (r *CommentRepository) List(ctx context.Context, filter repository.CommentF
 	`, whereClause, orderClause, limitOffset)
 
 	comments := []*domain.Comment{}
-	err := r.db.SelectContext(ctx, &comments, query, args...)
+	err := conn(ctx, r.db).SelectContext(ctx, &comments, query, args...)
 	if err != nil {
 		r.logger.Error("Failed to list comments", err)
 		return nil, fmt.Errorf("failed to list comments: %w", err)
@@ -182,7 +184,7 @@ func (r *CommentRepository) Count(ctx context.Context, filter repository.Comment
 	`, whereClause)
 
 	var count int
-	err := r.db.GetContext(ctx, &count, query, args...)
+	err := conn(ctx, r.db).GetContext(ctx, &count, query, args...)
 	if err != nil {
 		r.logger.Error("Failed to count comments", err)
 		return 0, fmt.Errorf("failed to count comments: %w", err)
@@ -204,7 +206,7 @@ func (r *CommentRepository) CountCommentsByTask(ctx context.Context, taskID stri
 	query := `SELECT COUNT(*) FROM comments WHERE task_id = $1`
 
 	var count int
-	err := r.db.GetContext(ctx, &count, query, taskID)
+	err := conn(ctx, r.db).GetContext(ctx, &count, query, taskID)
 	if err != nil {
 		r.logger.Error("Failed to count comments by task", err, map[string]interface{}{
 			"task_id": taskID,
@@ -215,6 +217,80 @@ func (r *CommentRepository) CountCommentsByTask(ctx context.Context, taskID stri
 	return count, nil
 }
 
+// GetReplies возвращает ответы на указанные комментарии не глубже depth уровней,
+// не более limit ответов под каждым комментарием
+func (r *CommentRepository) GetReplies(ctx context.Context, parentIDs []string, depth int, limit int) ([]*domain.Comment, error) {
+	comments := []*domain.Comment{}
+	if len(parentIDs) == 0 || depth <= 0 || limit <= 0 {
+		return comments, nil
+	}
+
+	// Первые limit ответов ветки по времени создания всегда включают родителей каждого
+	// из них, поэтому обрезанная ветка остается деревом
+	query := `
+		WITH RECURSIVE replies AS (
+			SELECT id, task_id, user_id, parent_comment_id, content, created_at, updated_at,
+				parent_comment_id AS root_id, 1 AS depth
+			FROM comments
+			WHERE parent_comment_id = ANY($1)
+			UNION ALL
+			SELECT c.id, c.task_id, c.user_id, c.parent_comment_id, c.content, c.created_at, c.updated_at,
+				r.root_id, r.depth + 1
+			FROM comments c
+			JOIN replies r ON c.parent_comment_id = r.id
+			WHERE r.depth < $2
+		)
+		SELECT id, task_id, user_id, parent_comment_id, content, created_at, updated_at
+		FROM (
+			SELECT *, ROW_NUMBER() OVER (PARTITION BY root_id ORDER BY created_at, id) AS n
+			FROM replies
+		) ranked
+		WHERE n <= $3
+		ORDER BY created_at ASC, id ASC
+	`
+
+	err := conn(ctx, r.db).SelectContext(ctx, &comments, query, pq.Array(parentIDs), depth, limit)
+	if err != nil {
+		r.logger.Error("Failed to get comment replies", err, map[string]interface{}{
+			"parent_ids": parentIDs,
+		})
+		return nil, fmt.Errorf("failed to get comment replies: %w", err)
+	}
+
+	return comments, nil
+}
+
+// CountReplies возвращает число прямых ответов на каждый из комментариев
+func (r *CommentRepository) CountReplies(ctx context.Context, ids []string) (map[string]int, error) {
+	counts := make(map[string]int)
+	if len(ids) == 0 {
+		return counts, nil
+	}
+
+	query := `
+		SELECT parent_comment_id, COUNT(*) AS count
+		FROM comments
+		WHERE parent_comment_id = ANY($1)
+		GROUP BY parent_comment_id
+	`
+
+	var rows []struct {
+		ParentCommentID string `db:"parent_comment_id"`
+		Count           int    `db:"count"`
+	}
+	if err := conn(ctx, r.db).SelectContext(ctx, &rows, query, pq.Array(ids)); err != nil {
+		r.logger.Error("Failed to count comment replies", err, map[string]interface{}{
+			"ids": ids,
+		})
+		return nil, fmt.Errorf("failed to count comment replies: %w", err)
+	}
+
+	for _, row := range rows {
+		counts[row.ParentCommentID] = row.Count
+	}
+	return counts, nil
+}
+
 // GetCommentsByUser возвращает комментарии пользователя
 func (r *CommentRepository) GetCommentsByUser(ctx context.Context, userID string, filter repository.CommentFilter) ([]*domain.Comment, error) {
 	// Создаем копию фильтра, чтобы не изменять исходный
@@ -228,7 +304,7 @@ func (r *CommentRepository) CountCommentsByUser(ctx context.Context, userID stri
 	query := `SELECT COUNT(*) FROM comments WHERE user_id = $1`
 
 	var count int
-	err := r.db.GetContext(ctx, &count, query, userID)
+	err := conn(ctx, r.db).GetContext(ctx, &count, query, userID)
 	if err != nil {
 		r.logger.Error("Failed to count comments by user", err, map[string]interface{}{
 			"user_id": userID,
@@ -282,6 +358,16 @@ func (r *CommentRepository) buildWhereClause(filter repository.CommentFilter) (s
 		argIndex++
 	}
 
+	if len(filter.ParentIDs) > 0 {
+		conditions = append(conditions, fmt.Sprintf("parent_comment_id = ANY($%d)", argIndex))
+		args = append(args, pq.Array(filter.ParentIDs))
+		argIndex++
+	}
+
+	if filter.RootsOnly {
+		conditions = append(conditions, "parent_comment_id IS NULL")
+	}
+
 	if len(conditions) > 0 {
 		return "WHERE " + strings.Join(conditions, " AND "), args
 	}
@@ -304,11 +390,12 @@ func (r *CommentRepository) buildOrderClause(filter repository.CommentFilter) st
 			"updated_at": true,
 		}
 
+		// ID завершает сортировку, чтобы страницы не пересекались при равных значениях поля
 		if allowedFields[*filter.OrderBy] {
-			return fmt.Sprintf("ORDER BY %s %s", *filter.OrderBy, direction)
+			return fmt.Sprintf("ORDER BY %s %s, id %s", *filter.OrderBy, direction, direction)
 		}
 	}
 
 	// По умолчанию сортируем по дате создания
-	return "ORDER BY created_at DESC"
+	return "ORDER BY created_at DESC, id DESC"
 }
\ No newline at end of file
@@ -0,0 +1,183 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/pkg/logger"
+)
+
+// searchIndexes - индексы, по которым работают полнотекстовый и быстрый поиск
+var searchIndexes = []string{
+	"idx_tasks_search",
+	"idx_tasks_title_trgm",
+	"idx_projects_name_trgm",
+	"idx_users_full_name_trgm",
+	"idx_users_email_trgm",
+	"idx_decisions_title_trgm",
+	"idx_wiki_pages_title_trgm",
+	"idx_wiki_pages_search",
+}
+
+// ConsistencyRepository реализует сверку производных данных с базой с использованием PostgreSQL
+type ConsistencyRepository struct {
+	db     *sqlx.DB
+	logger logger.Logger
+}
+
+// NewConsistencyRepository создает новый экземпляр ConsistencyRepository
+func NewConsistencyRepository(db *sqlx.DB, logger logger.Logger) *ConsistencyRepository {
+	return &ConsistencyRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// GetTaskVersions возвращает время последнего изменения задач с указанными ID
+func (r *ConsistencyRepository) GetTaskVersions(ctx context.Context, ids []string) (map[string]time.Time, error) {
+	return r.getVersions(ctx, "tasks", ids)
+}
+
+// GetProjectVersions возвращает время последнего изменения проектов с указанными ID
+func (r *ConsistencyRepository) GetProjectVersions(ctx context.Context, ids []string) (map[string]time.Time, error) {
+	return r.getVersions(ctx, "projects", ids)
+}
+
+// ListSpentHoursDrift возвращает задачи, затраченное время которых расходится с суммой записей о времени
+func (r *ConsistencyRepository) ListSpentHoursDrift(ctx context.Context, limit int) ([]*domain.SpentHoursDrift, int, int, error) {
+	statsQuery := `
+		SELECT
+			COUNT(*) AS checked,
+			COUNT(*) FILTER (WHERE COALESCE(t.spent_hours, 0) <> COALESCE(l.hours, 0)) AS drifted
+		FROM tasks t
+		LEFT JOIN (
+			SELECT task_id, SUM(hours) AS hours
+			FROM time_logs
+			GROUP BY task_id
+		) l ON l.task_id = t.id
+	`
+
+	var stats struct {
+		Checked int `db:"checked"`
+		Drifted int `db:"drifted"`
+	}
+	if err := conn(ctx, r.db).GetContext(ctx, &stats, statsQuery); err != nil {
+		r.logger.Error("Failed to count spent hours drift", err)
+		return nil, 0, 0, fmt.Errorf("failed to count spent hours drift: %w", err)
+	}
+
+	query := `
+		SELECT
+			t.id AS task_id,
+			COALESCE(t.spent_hours, 0) AS spent_hours,
+			COALESCE(l.hours, 0) AS logged_hours
+		FROM tasks t
+		LEFT JOIN (
+			SELECT task_id, SUM(hours) AS hours
+			FROM time_logs
+			GROUP BY task_id
+		) l ON l.task_id = t.id
+		WHERE COALESCE(t.spent_hours, 0) <> COALESCE(l.hours, 0)
+		ORDER BY t.updated_at DESC
+		LIMIT $1
+	`
+
+	var drifts []*domain.SpentHoursDrift
+	if err := conn(ctx, r.db).SelectContext(ctx, &drifts, query, limit); err != nil {
+		r.logger.Error("Failed to list spent hours drift", err)
+		return nil, 0, 0, fmt.Errorf("failed to list spent hours drift: %w", err)
+	}
+
+	return drifts, stats.Checked, stats.Drifted, nil
+}
+
+// RecalculateSpentHours записывает в задачи с расхождением сумму их записей о времени
+func (r *ConsistencyRepository) RecalculateSpentHours(ctx context.Context) ([]string, error) {
+	query := `
+		WITH sums AS (
+			SELECT t.id, COALESCE(SUM(tl.hours), 0) AS hours
+			FROM tasks t
+			LEFT JOIN time_logs tl ON tl.task_id = t.id
+			GROUP BY t.id
+		)
+		UPDATE tasks t
+		SET spent_hours = sums.hours, updated_at = $1
+		FROM sums
+		WHERE sums.id = t.id AND COALESCE(t.spent_hours, 0) <> sums.hours
+		RETURNING t.id
+	`
+
+	var ids []string
+	if err := conn(ctx, r.db).SelectContext(ctx, &ids, query, time.Now()); err != nil {
+		r.logger.Error("Failed to recalculate spent hours", err)
+		return nil, fmt.Errorf("failed to recalculate spent hours: %w", err)
+	}
+
+	return ids, nil
+}
+
+// ListSearchIndexes возвращает состояние поисковых индексов. Индекс, построение которого
+// было прервано, остается в каталоге невалидным и не используется запросами
+func (r *ConsistencyRepository) ListSearchIndexes(ctx context.Context) ([]*domain.SearchIndexState, error) {
+	query := `
+		SELECT c.relname AS name, i.indisvalid AS valid
+		FROM pg_index i
+		JOIN pg_class c ON c.oid = i.indexrelid
+		WHERE c.relname = ANY($1)
+		ORDER BY c.relname
+	`
+
+	var indexes []*domain.SearchIndexState
+	if err := conn(ctx, r.db).SelectContext(ctx, &indexes, query, pq.Array(searchIndexes)); err != nil {
+		r.logger.Error("Failed to list search indexes", err)
+		return nil, fmt.Errorf("failed to list search indexes: %w", err)
+	}
+
+	return indexes, nil
+}
+
+// Reindex перестраивает индекс, не блокируя запись в таблицу
+func (r *ConsistencyRepository) Reindex(ctx context.Context, name string) error {
+	// Имя индекса нельзя передать параметром, поэтому оно экранируется
+	query := "REINDEX INDEX CONCURRENTLY " + pq.QuoteIdentifier(name)
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query); err != nil {
+		r.logger.Error("Failed to reindex", err, map[string]interface{}{
+			"index": name,
+		})
+		return fmt.Errorf("failed to reindex %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// getVersions возвращает время последнего изменения записей таблицы с указанными ID
+func (r *ConsistencyRepository) getVersions(ctx context.Context, table string, ids []string) (map[string]time.Time, error) {
+	versions := make(map[string]time.Time, len(ids))
+	if len(ids) == 0 {
+		return versions, nil
+	}
+
+	query := fmt.Sprintf(`SELECT id, updated_at FROM %s WHERE id = ANY($1)`, table)
+
+	var rows []struct {
+		ID        string    `db:"id"`
+		UpdatedAt time.Time `db:"updated_at"`
+	}
+	if err := conn(ctx, r.db).SelectContext(ctx, &rows, query, pq.Array(ids)); err != nil {
+		r.logger.Error("Failed to get record versions", err, map[string]interface{}{
+			"table": table,
+			"count": len(ids),
+		})
+		return nil, fmt.Errorf("failed to get %s versions: %w", table, err)
+	}
+
+	for _, row := range rows {
+		versions[row.ID] = row.UpdatedAt
+	}
+	return versions, nil
+}
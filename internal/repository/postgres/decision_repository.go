@@ -0,0 +1,320 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/internal/repository"
+	"github.com/nurlyy/task_manager/pkg/logger"
+)
+
+// decisionColumns содержит колонки выборки решений вместе со связанными задачами
+const decisionColumns = `
+	d.id, d.project_id, d.title, d.context, d.decision, d.alternatives, d.approver_id, d.decided_at,
+	d.created_by, d.created_at, d.updated_at,
+	ARRAY(SELECT dt.task_id::text FROM decision_tasks dt WHERE dt.decision_id = d.id ORDER BY dt.created_at) AS task_ids
+`
+
+// decisionRow представляет строку таблицы decisions
+type decisionRow struct {
+	domain.Decision
+	AlternativesArray pq.StringArray `db:"alternatives"`
+	TaskIDArray       pq.StringArray `db:"task_ids"`
+}
+
+// toDomain преобразует строку таблицы в решение
+func (row *decisionRow) toDomain() *domain.Decision {
+	decision := row.Decision
+	decision.Alternatives = []string(row.AlternativesArray)
+	decision.TaskIDs = []string(row.TaskIDArray)
+	if decision.Alternatives == nil {
+		decision.Alternatives = []string{}
+	}
+	if decision.TaskIDs == nil {
+		decision.TaskIDs = []string{}
+	}
+	return &decision
+}
+
+// DecisionRepository реализует репозиторий журнала решений с использованием PostgreSQL
+type DecisionRepository struct {
+	db     *sqlx.DB
+	logger logger.Logger
+}
+
+// NewDecisionRepository создает новый экземпляр DecisionRepository
+func NewDecisionRepository(db *sqlx.DB, logger logger.Logger) *DecisionRepository {
+	return &DecisionRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Create сохраняет решение вместе со связями с задачами
+func (r *DecisionRepository) Create(ctx context.Context, decision *domain.Decision) error {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				r.logger.Error("Failed to rollback transaction", rbErr)
+			}
+		}
+	}()
+
+	query := `
+		INSERT INTO decisions (
+			id, project_id, title, context, decision, alternatives, approver_id, decided_at,
+			created_by, created_at, updated_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+		)
+	`
+
+	if _, err = tx.ExecContext(
+		ctx,
+		query,
+		decision.ID,
+		decision.ProjectID,
+		decision.Title,
+		decision.Context,
+		decision.Decision,
+		pq.StringArray(decision.Alternatives),
+		decision.ApproverID,
+		decision.DecidedAt,
+		decision.CreatedBy,
+		decision.CreatedAt,
+		decision.UpdatedAt,
+	); err != nil {
+		r.logger.Error("Failed to create decision", err, map[string]interface{}{
+			"project_id": decision.ProjectID,
+		})
+		return fmt.Errorf("failed to create decision: %w", err)
+	}
+
+	for _, taskID := range decision.TaskIDs {
+		if _, err = tx.ExecContext(
+			ctx,
+			"INSERT INTO decision_tasks (decision_id, task_id, created_by, created_at) VALUES ($1, $2, $3, $4) ON CONFLICT DO NOTHING",
+			decision.ID,
+			taskID,
+			decision.CreatedBy,
+			decision.CreatedAt,
+		); err != nil {
+			r.logger.Error("Failed to link decision task", err, map[string]interface{}{
+				"decision_id": decision.ID,
+				"task_id":     taskID,
+			})
+			return fmt.Errorf("failed to link decision task: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID возвращает решение по ID
+func (r *DecisionRepository) GetByID(ctx context.Context, id string) (*domain.Decision, error) {
+	query := fmt.Sprintf(`SELECT %s FROM decisions d WHERE d.id = $1`, decisionColumns)
+
+	var row decisionRow
+	if err := conn(ctx, r.db).GetContext(ctx, &row, query, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		r.logger.Error("Failed to get decision", err, map[string]interface{}{
+			"id": id,
+		})
+		return nil, fmt.Errorf("failed to get decision: %w", err)
+	}
+
+	return row.toDomain(), nil
+}
+
+// Update обновляет решение
+func (r *DecisionRepository) Update(ctx context.Context, decision *domain.Decision) error {
+	query := `
+		UPDATE decisions
+		SET title = $1, context = $2, decision = $3, alternatives = $4, approver_id = $5,
+			decided_at = $6, updated_at = $7
+		WHERE id = $8
+	`
+
+	_, err := conn(ctx, r.db).ExecContext(
+		ctx,
+		query,
+		decision.Title,
+		decision.Context,
+		decision.Decision,
+		pq.StringArray(decision.Alternatives),
+		decision.ApproverID,
+		decision.DecidedAt,
+		decision.UpdatedAt,
+		decision.ID,
+	)
+	if err != nil {
+		r.logger.Error("Failed to update decision", err, map[string]interface{}{
+			"id": decision.ID,
+		})
+		return fmt.Errorf("failed to update decision: %w", err)
+	}
+
+	return nil
+}
+
+// Delete удаляет решение
+func (r *DecisionRepository) Delete(ctx context.Context, id string) error {
+	query := `DELETE FROM decisions WHERE id = $1`
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query, id); err != nil {
+		r.logger.Error("Failed to delete decision", err, map[string]interface{}{
+			"id": id,
+		})
+		return fmt.Errorf("failed to delete decision: %w", err)
+	}
+
+	return nil
+}
+
+// List возвращает решения проекта, сначала последние
+func (r *DecisionRepository) List(ctx context.Context, projectID string, filter repository.DecisionFilter) ([]*domain.Decision, error) {
+	whereClause, args := r.buildWhereClause(projectID, filter)
+	args = append(args, filter.Limit, filter.Offset)
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM decisions d
+		%s
+		ORDER BY COALESCE(d.decided_at, d.created_at) DESC, d.id
+		LIMIT $%d OFFSET $%d
+	`, decisionColumns, whereClause, len(args)-1, len(args))
+
+	var rows []*decisionRow
+	if err := conn(ctx, r.db).SelectContext(ctx, &rows, query, args...); err != nil {
+		r.logger.Error("Failed to list decisions", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return nil, fmt.Errorf("failed to list decisions: %w", err)
+	}
+
+	decisions := make([]*domain.Decision, 0, len(rows))
+	for _, row := range rows {
+		decisions = append(decisions, row.toDomain())
+	}
+
+	return decisions, nil
+}
+
+// Count возвращает количество решений проекта по фильтру
+func (r *DecisionRepository) Count(ctx context.Context, projectID string, filter repository.DecisionFilter) (int, error) {
+	whereClause, args := r.buildWhereClause(projectID, filter)
+	query := fmt.Sprintf(`SELECT COUNT(*) FROM decisions d %s`, whereClause)
+
+	var count int
+	if err := conn(ctx, r.db).GetContext(ctx, &count, query, args...); err != nil {
+		r.logger.Error("Failed to count decisions", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return 0, fmt.Errorf("failed to count decisions: %w", err)
+	}
+
+	return count, nil
+}
+
+// ListByTask возвращает решения, связанные с задачей
+func (r *DecisionRepository) ListByTask(ctx context.Context, taskID string) ([]*domain.Decision, error) {
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM decisions d
+		JOIN decision_tasks link ON link.decision_id = d.id
+		WHERE link.task_id = $1
+		ORDER BY COALESCE(d.decided_at, d.created_at) DESC, d.id
+	`, decisionColumns)
+
+	var rows []*decisionRow
+	if err := conn(ctx, r.db).SelectContext(ctx, &rows, query, taskID); err != nil {
+		r.logger.Error("Failed to list task decisions", err, map[string]interface{}{
+			"task_id": taskID,
+		})
+		return nil, fmt.Errorf("failed to list task decisions: %w", err)
+	}
+
+	decisions := make([]*domain.Decision, 0, len(rows))
+	for _, row := range rows {
+		decisions = append(decisions, row.toDomain())
+	}
+
+	return decisions, nil
+}
+
+// LinkTask связывает решение с задачей
+func (r *DecisionRepository) LinkTask(ctx context.Context, decisionID, taskID, userID string) (bool, error) {
+	query := `
+		INSERT INTO decision_tasks (decision_id, task_id, created_by, created_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT DO NOTHING
+	`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, decisionID, taskID, userID)
+	if err != nil {
+		r.logger.Error("Failed to link decision task", err, map[string]interface{}{
+			"decision_id": decisionID,
+			"task_id":     taskID,
+		})
+		return false, fmt.Errorf("failed to link decision task: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// UnlinkTask удаляет связь решения с задачей
+func (r *DecisionRepository) UnlinkTask(ctx context.Context, decisionID, taskID string) error {
+	query := `DELETE FROM decision_tasks WHERE decision_id = $1 AND task_id = $2`
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query, decisionID, taskID); err != nil {
+		r.logger.Error("Failed to unlink decision task", err, map[string]interface{}{
+			"decision_id": decisionID,
+			"task_id":     taskID,
+		})
+		return fmt.Errorf("failed to unlink decision task: %w", err)
+	}
+
+	return nil
+}
+
+// buildWhereClause формирует условие выборки решений проекта
+func (r *DecisionRepository) buildWhereClause(projectID string, filter repository.DecisionFilter) (string, []interface{}) {
+	conditions := []string{"d.project_id = $1"}
+	args := []interface{}{projectID}
+
+	if filter.Search != nil {
+		args = append(args, *filter.Search)
+		n := len(args)
+		conditions = append(conditions, fmt.Sprintf(
+			"(d.title ILIKE '%%' || $%d || '%%' OR d.context ILIKE '%%' || $%d || '%%' OR d.decision ILIKE '%%' || $%d || '%%' OR array_to_string(d.alternatives, ' ') ILIKE '%%' || $%d || '%%')",
+			n, n, n, n,
+		))
+	}
+
+	if filter.TaskID != nil {
+		args = append(args, *filter.TaskID)
+		conditions = append(conditions, fmt.Sprintf("EXISTS (SELECT 1 FROM decision_tasks dt WHERE dt.decision_id = d.id AND dt.task_id = $%d)", len(args)))
+	}
+
+	return "WHERE " + strings.Join(conditions, " AND "), args
+}
@@ -0,0 +1,118 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/pkg/logger"
+)
+
+// projectDiscordChannelRow представляет строку таблицы project_discord_channels
+type projectDiscordChannelRow struct {
+	domain.ProjectDiscordChannel
+	Types pq.StringArray `db:"notification_types"`
+}
+
+// DiscordRepository реализует репозиторий каналов Discord с использованием PostgreSQL
+type DiscordRepository struct {
+	db     *sqlx.DB
+	logger logger.Logger
+}
+
+// NewDiscordRepository создает новый экземпляр DiscordRepository
+func NewDiscordRepository(db *sqlx.DB, logger logger.Logger) *DiscordRepository {
+	return &DiscordRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// GetProjectChannel возвращает канал проекта
+func (r *DiscordRepository) GetProjectChannel(ctx context.Context, projectID string) (*domain.ProjectDiscordChannel, error) {
+	query := `
+		SELECT project_id, COALESCE(webhook_url, '') AS webhook_url, COALESCE(channel_id, '') AS channel_id,
+			enabled, notification_types, created_by, created_at, updated_at
+		FROM project_discord_channels
+		WHERE project_id = $1
+	`
+
+	var row projectDiscordChannelRow
+	if err := conn(ctx, r.db).GetContext(ctx, &row, query, projectID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		r.logger.Error("Failed to get project Discord channel", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return nil, fmt.Errorf("failed to get project Discord channel: %w", err)
+	}
+
+	channel := row.ProjectDiscordChannel
+	channel.NotificationTypes = make([]domain.NotificationType, 0, len(row.Types))
+	for _, notificationType := range row.Types {
+		channel.NotificationTypes = append(channel.NotificationTypes, domain.NotificationType(notificationType))
+	}
+
+	return &channel, nil
+}
+
+// SaveProjectChannel создает или обновляет канал проекта
+func (r *DiscordRepository) SaveProjectChannel(ctx context.Context, channel *domain.ProjectDiscordChannel) error {
+	query := `
+		INSERT INTO project_discord_channels (
+			project_id, webhook_url, channel_id, enabled, notification_types, created_by, created_at, updated_at
+		) VALUES (
+			$1, NULLIF($2, ''), NULLIF($3, ''), $4, $5, $6, $7, $8
+		)
+		ON CONFLICT (project_id) DO UPDATE SET
+			webhook_url = EXCLUDED.webhook_url,
+			channel_id = EXCLUDED.channel_id,
+			enabled = EXCLUDED.enabled,
+			notification_types = EXCLUDED.notification_types,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	types := make(pq.StringArray, 0, len(channel.NotificationTypes))
+	for _, notificationType := range channel.NotificationTypes {
+		types = append(types, string(notificationType))
+	}
+
+	_, err := conn(ctx, r.db).ExecContext(
+		ctx,
+		query,
+		channel.ProjectID,
+		channel.WebhookURL,
+		channel.ChannelID,
+		channel.Enabled,
+		types,
+		channel.CreatedBy,
+		channel.CreatedAt,
+		channel.UpdatedAt,
+	)
+	if err != nil {
+		r.logger.Error("Failed to save project Discord channel", err, map[string]interface{}{
+			"project_id": channel.ProjectID,
+		})
+		return fmt.Errorf("failed to save project Discord channel: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteProjectChannel удаляет канал проекта
+func (r *DiscordRepository) DeleteProjectChannel(ctx context.Context, projectID string) error {
+	query := `DELETE FROM project_discord_channels WHERE project_id = $1`
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query, projectID); err != nil {
+		r.logger.Error("Failed to delete project Discord channel", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return fmt.Errorf("failed to delete project Discord channel: %w", err)
+	}
+
+	return nil
+}
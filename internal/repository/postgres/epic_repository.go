@@ -0,0 +1,292 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/pkg/logger"
+)
+
+// EpicRepository реализует репозиторий эпиков с использованием PostgreSQL
+type EpicRepository struct {
+	db     *sqlx.DB
+	logger logger.Logger
+}
+
+// NewEpicRepository создает новый экземпляр EpicRepository
+func NewEpicRepository(db *sqlx.DB, logger logger.Logger) *EpicRepository {
+	return &EpicRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Create создает новый эпик
+func (r *EpicRepository) Create(ctx context.Context, epic *domain.Epic) error {
+	query := `
+		INSERT INTO epics (
+			id, project_id, title, goal, owner_id, target_date, created_by, created_at, updated_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9
+		)
+	`
+
+	_, err := conn(ctx, r.db).ExecContext(
+		ctx,
+		query,
+		epic.ID,
+		epic.ProjectID,
+		epic.Title,
+		epic.Goal,
+		epic.OwnerID,
+		epic.TargetDate,
+		epic.CreatedBy,
+		epic.CreatedAt,
+		epic.UpdatedAt,
+	)
+	if err != nil {
+		r.logger.Error("Failed to create epic", err, map[string]interface{}{
+			"project_id": epic.ProjectID,
+		})
+		return fmt.Errorf("failed to create epic: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID возвращает эпик по ID
+func (r *EpicRepository) GetByID(ctx context.Context, id string) (*domain.Epic, error) {
+	query := `
+		SELECT id, project_id, title, goal, owner_id, target_date, created_by, created_at, updated_at
+		FROM epics
+		WHERE id = $1
+	`
+
+	var epic domain.Epic
+	if err := conn(ctx, r.db).GetContext(ctx, &epic, query, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		r.logger.Error("Failed to get epic by ID", err, map[string]interface{}{
+			"id": id,
+		})
+		return nil, fmt.Errorf("failed to get epic by ID: %w", err)
+	}
+
+	return &epic, nil
+}
+
+// Update обновляет данные эпика
+func (r *EpicRepository) Update(ctx context.Context, epic *domain.Epic) error {
+	query := `
+		UPDATE epics
+		SET title = $1, goal = $2, owner_id = $3, target_date = $4, updated_at = $5
+		WHERE id = $6
+	`
+
+	result, err := conn(ctx, r.db).ExecContext(
+		ctx,
+		query,
+		epic.Title,
+		epic.Goal,
+		epic.OwnerID,
+		epic.TargetDate,
+		epic.UpdatedAt,
+		epic.ID,
+	)
+	if err != nil {
+		r.logger.Error("Failed to update epic", err, map[string]interface{}{
+			"id": epic.ID,
+		})
+		return fmt.Errorf("failed to update epic: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("epic not found")
+	}
+
+	return nil
+}
+
+// Delete удаляет эпик
+func (r *EpicRepository) Delete(ctx context.Context, id string) error {
+	query := `DELETE FROM epics WHERE id = $1`
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query, id); err != nil {
+		r.logger.Error("Failed to delete epic", err, map[string]interface{}{
+			"id": id,
+		})
+		return fmt.Errorf("failed to delete epic: %w", err)
+	}
+
+	return nil
+}
+
+// ListByProject возвращает эпики проекта, упорядоченные по целевой дате
+func (r *EpicRepository) ListByProject(ctx context.Context, projectID string) ([]*domain.Epic, error) {
+	query := `
+		SELECT id, project_id, title, goal, owner_id, target_date, created_by, created_at, updated_at
+		FROM epics
+		WHERE project_id = $1
+		ORDER BY target_date ASC NULLS LAST, created_at ASC
+	`
+
+	epics := []*domain.Epic{}
+	if err := conn(ctx, r.db).SelectContext(ctx, &epics, query, projectID); err != nil {
+		r.logger.Error("Failed to list epics", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return nil, fmt.Errorf("failed to list epics: %w", err)
+	}
+
+	return epics, nil
+}
+
+// GetProgress возвращает сводный прогресс задач указанных эпиков
+func (r *EpicRepository) GetProgress(ctx context.Context, epicIDs []string) (map[string]*domain.EpicProgress, error) {
+	result := make(map[string]*domain.EpicProgress, len(epicIDs))
+	if len(epicIDs) == 0 {
+		return result, nil
+	}
+
+	query := `
+		SELECT
+			epic_id,
+			COUNT(*) AS total_tasks,
+			COUNT(*) FILTER (WHERE status = 'completed') AS completed_tasks,
+			COUNT(*) FILTER (WHERE status IN ('in_progress', 'review')) AS in_progress_tasks,
+			COUNT(*) FILTER (WHERE due_date < $2 AND status != 'completed') AS overdue_tasks,
+			COALESCE(SUM(estimated_hours), 0) AS estimated_hours,
+			COALESCE(SUM(spent_hours), 0) AS spent_hours
+		FROM tasks
+		WHERE epic_id = ANY($1) AND status != 'cancelled'
+		GROUP BY epic_id
+	`
+
+	var rows []*domain.EpicProgress
+	if err := conn(ctx, r.db).SelectContext(ctx, &rows, query, pq.Array(epicIDs), time.Now()); err != nil {
+		r.logger.Error("Failed to get epic progress", err)
+		return nil, fmt.Errorf("failed to get epic progress: %w", err)
+	}
+
+	for _, row := range rows {
+		result[row.EpicID] = row
+	}
+
+	return result, nil
+}
+
+// SetTaskEpic привязывает задачу к эпику или отвязывает ее
+func (r *EpicRepository) SetTaskEpic(ctx context.Context, taskID string, epicID *string) error {
+	query := `UPDATE tasks SET epic_id = $1, updated_at = $2 WHERE id = $3`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, epicID, time.Now(), taskID)
+	if err != nil {
+		r.logger.Error("Failed to set task epic", err, map[string]interface{}{
+			"task_id": taskID,
+		})
+		return fmt.Errorf("failed to set task epic: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("task not found")
+	}
+
+	return nil
+}
+
+// CreateComment сохраняет комментарий к эпику
+func (r *EpicRepository) CreateComment(ctx context.Context, comment *domain.EpicComment) error {
+	query := `
+		INSERT INTO epic_comments (id, epic_id, user_id, content, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	_, err := conn(ctx, r.db).ExecContext(
+		ctx,
+		query,
+		comment.ID,
+		comment.EpicID,
+		comment.UserID,
+		comment.Content,
+		comment.CreatedAt,
+		comment.UpdatedAt,
+	)
+	if err != nil {
+		r.logger.Error("Failed to create epic comment", err, map[string]interface{}{
+			"epic_id": comment.EpicID,
+		})
+		return fmt.Errorf("failed to create epic comment: %w", err)
+	}
+
+	return nil
+}
+
+// GetComment возвращает комментарий по ID
+func (r *EpicRepository) GetComment(ctx context.Context, id string) (*domain.EpicComment, error) {
+	query := `
+		SELECT id, epic_id, user_id, content, created_at, updated_at
+		FROM epic_comments
+		WHERE id = $1
+	`
+
+	var comment domain.EpicComment
+	if err := conn(ctx, r.db).GetContext(ctx, &comment, query, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		r.logger.Error("Failed to get epic comment", err, map[string]interface{}{
+			"id": id,
+		})
+		return nil, fmt.Errorf("failed to get epic comment: %w", err)
+	}
+
+	return &comment, nil
+}
+
+// ListComments возвращает комментарии эпика в порядке создания
+func (r *EpicRepository) ListComments(ctx context.Context, epicID string) ([]*domain.EpicComment, error) {
+	query := `
+		SELECT id, epic_id, user_id, content, created_at, updated_at
+		FROM epic_comments
+		WHERE epic_id = $1
+		ORDER BY created_at ASC
+	`
+
+	comments := []*domain.EpicComment{}
+	if err := conn(ctx, r.db).SelectContext(ctx, &comments, query, epicID); err != nil {
+		r.logger.Error("Failed to list epic comments", err, map[string]interface{}{
+			"epic_id": epicID,
+		})
+		return nil, fmt.Errorf("failed to list epic comments: %w", err)
+	}
+
+	return comments, nil
+}
+
+// DeleteComment удаляет комментарий к эпику
+func (r *EpicRepository) DeleteComment(ctx context.Context, id string) error {
+	query := `DELETE FROM epic_comments WHERE id = $1`
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query, id); err != nil {
+		r.logger.Error("Failed to delete epic comment", err, map[string]interface{}{
+			"id": id,
+		})
+		return fmt.Errorf("failed to delete epic comment: %w", err)
+	}
+
+	return nil
+}
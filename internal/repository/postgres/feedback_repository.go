@@ -0,0 +1,333 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/internal/repository"
+	"github.com/nurlyy/task_manager/pkg/logger"
+)
+
+// feedbackSubmissionColumns содержит колонки таблицы feedback_submissions
+const feedbackSubmissionColumns = `
+	id, project_id, title, description, submitter_email, submitter_name, status, task_id,
+	moderation_note, moderated_by, moderated_at, notified_task_status, created_at, updated_at
+`
+
+// FeedbackRepository реализует репозиторий порталов обратной связи с использованием PostgreSQL
+type FeedbackRepository struct {
+	db     *sqlx.DB
+	logger logger.Logger
+}
+
+// NewFeedbackRepository создает новый экземпляр FeedbackRepository
+func NewFeedbackRepository(db *sqlx.DB, logger logger.Logger) *FeedbackRepository {
+	return &FeedbackRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// GetPortal возвращает портал проекта
+func (r *FeedbackRepository) GetPortal(ctx context.Context, projectID string) (*domain.FeedbackPortal, error) {
+	query := `
+		SELECT project_id, public_key, title, description, enabled, created_by, created_at, updated_at
+		FROM feedback_portals
+		WHERE project_id = $1
+	`
+
+	var portal domain.FeedbackPortal
+	if err := conn(ctx, r.db).GetContext(ctx, &portal, query, projectID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		r.logger.Error("Failed to get feedback portal", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return nil, fmt.Errorf("failed to get feedback portal: %w", err)
+	}
+
+	return &portal, nil
+}
+
+// GetPortalByKey возвращает портал по публичному ключу
+func (r *FeedbackRepository) GetPortalByKey(ctx context.Context, publicKey string) (*domain.FeedbackPortal, error) {
+	query := `
+		SELECT project_id, public_key, title, description, enabled, created_by, created_at, updated_at
+		FROM feedback_portals
+		WHERE public_key = $1
+	`
+
+	var portal domain.FeedbackPortal
+	if err := conn(ctx, r.db).GetContext(ctx, &portal, query, publicKey); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		r.logger.Error("Failed to get feedback portal by key", err)
+		return nil, fmt.Errorf("failed to get feedback portal: %w", err)
+	}
+
+	return &portal, nil
+}
+
+// SavePortal создает или обновляет портал проекта
+func (r *FeedbackRepository) SavePortal(ctx context.Context, portal *domain.FeedbackPortal) error {
+	query := `
+		INSERT INTO feedback_portals (
+			project_id, public_key, title, description, enabled, created_by, created_at, updated_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8
+		)
+		ON CONFLICT (project_id) DO UPDATE SET
+			public_key = EXCLUDED.public_key,
+			title = EXCLUDED.title,
+			description = EXCLUDED.description,
+			enabled = EXCLUDED.enabled,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	_, err := conn(ctx, r.db).ExecContext(
+		ctx,
+		query,
+		portal.ProjectID,
+		portal.PublicKey,
+		portal.Title,
+		portal.Description,
+		portal.Enabled,
+		portal.CreatedBy,
+		portal.CreatedAt,
+		portal.UpdatedAt,
+	)
+	if err != nil {
+		r.logger.Error("Failed to save feedback portal", err, map[string]interface{}{
+			"project_id": portal.ProjectID,
+		})
+		return fmt.Errorf("failed to save feedback portal: %w", err)
+	}
+
+	return nil
+}
+
+// DeletePortal удаляет портал проекта
+func (r *FeedbackRepository) DeletePortal(ctx context.Context, projectID string) error {
+	query := `DELETE FROM feedback_portals WHERE project_id = $1`
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query, projectID); err != nil {
+		r.logger.Error("Failed to delete feedback portal", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return fmt.Errorf("failed to delete feedback portal: %w", err)
+	}
+
+	return nil
+}
+
+// CreateSubmission сохраняет новый запрос
+func (r *FeedbackRepository) CreateSubmission(ctx context.Context, submission *domain.FeedbackSubmission) error {
+	query := `
+		INSERT INTO feedback_submissions (
+			id, project_id, title, description, submitter_email, submitter_name, status, created_at, updated_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9
+		)
+	`
+
+	_, err := conn(ctx, r.db).ExecContext(
+		ctx,
+		query,
+		submission.ID,
+		submission.ProjectID,
+		submission.Title,
+		submission.Description,
+		submission.SubmitterEmail,
+		submission.SubmitterName,
+		submission.Status,
+		submission.CreatedAt,
+		submission.UpdatedAt,
+	)
+	if err != nil {
+		r.logger.Error("Failed to create feedback submission", err, map[string]interface{}{
+			"project_id": submission.ProjectID,
+		})
+		return fmt.Errorf("failed to create feedback submission: %w", err)
+	}
+
+	return nil
+}
+
+// GetSubmission возвращает запрос по ID
+func (r *FeedbackRepository) GetSubmission(ctx context.Context, id string) (*domain.FeedbackSubmission, error) {
+	query := `SELECT ` + feedbackSubmissionColumns + ` FROM feedback_submissions WHERE id = $1`
+
+	var submission domain.FeedbackSubmission
+	if err := conn(ctx, r.db).GetContext(ctx, &submission, query, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		r.logger.Error("Failed to get feedback submission", err, map[string]interface{}{
+			"id": id,
+		})
+		return nil, fmt.Errorf("failed to get feedback submission: %w", err)
+	}
+
+	return &submission, nil
+}
+
+// ListSubmissions возвращает запросы проекта, сначала новые
+func (r *FeedbackRepository) ListSubmissions(ctx context.Context, projectID string, filter repository.FeedbackFilter) ([]*domain.FeedbackSubmission, error) {
+	query := `
+		SELECT ` + feedbackSubmissionColumns + `
+		FROM feedback_submissions
+		WHERE project_id = $1 AND ($2::VARCHAR IS NULL OR status = $2)
+		ORDER BY created_at DESC
+		LIMIT $3 OFFSET $4
+	`
+
+	var submissions []*domain.FeedbackSubmission
+	if err := conn(ctx, r.db).SelectContext(ctx, &submissions, query, projectID, filter.Status, filter.Limit, filter.Offset); err != nil {
+		r.logger.Error("Failed to list feedback submissions", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return nil, fmt.Errorf("failed to list feedback submissions: %w", err)
+	}
+
+	return submissions, nil
+}
+
+// CountSubmissions возвращает количество запросов проекта по фильтру
+func (r *FeedbackRepository) CountSubmissions(ctx context.Context, projectID string, filter repository.FeedbackFilter) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM feedback_submissions
+		WHERE project_id = $1 AND ($2::VARCHAR IS NULL OR status = $2)
+	`
+
+	var count int
+	if err := conn(ctx, r.db).GetContext(ctx, &count, query, projectID, filter.Status); err != nil {
+		r.logger.Error("Failed to count feedback submissions", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return 0, fmt.Errorf("failed to count feedback submissions: %w", err)
+	}
+
+	return count, nil
+}
+
+// CountPendingByEmail возвращает количество запросов автора, ожидающих модерации
+func (r *FeedbackRepository) CountPendingByEmail(ctx context.Context, projectID string, email string) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM feedback_submissions
+		WHERE project_id = $1 AND LOWER(submitter_email) = LOWER($2) AND status = 'pending'
+	`
+
+	var count int
+	if err := conn(ctx, r.db).GetContext(ctx, &count, query, projectID, email); err != nil {
+		r.logger.Error("Failed to count pending feedback submissions", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return 0, fmt.Errorf("failed to count pending feedback submissions: %w", err)
+	}
+
+	return count, nil
+}
+
+// ModerateSubmission сохраняет решение модератора, если запрос еще ожидает модерации
+func (r *FeedbackRepository) ModerateSubmission(ctx context.Context, submission *domain.FeedbackSubmission) (bool, error) {
+	query := `
+		UPDATE feedback_submissions SET
+			status = $2,
+			task_id = $3,
+			moderation_note = $4,
+			moderated_by = $5,
+			moderated_at = $6,
+			notified_task_status = $7,
+			updated_at = $8
+		WHERE id = $1 AND status = 'pending'
+	`
+
+	result, err := conn(ctx, r.db).ExecContext(
+		ctx,
+		query,
+		submission.ID,
+		submission.Status,
+		submission.TaskID,
+		submission.ModerationNote,
+		submission.ModeratedBy,
+		submission.ModeratedAt,
+		submission.NotifiedTaskStatus,
+		submission.UpdatedAt,
+	)
+	if err != nil {
+		r.logger.Error("Failed to moderate feedback submission", err, map[string]interface{}{
+			"id": submission.ID,
+		})
+		return false, fmt.Errorf("failed to moderate feedback submission: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return rows > 0, nil
+}
+
+// ListSubmissionsByTask возвращает принятые и объединенные запросы, связанные с задачей
+func (r *FeedbackRepository) ListSubmissionsByTask(ctx context.Context, taskID string) ([]*domain.FeedbackSubmission, error) {
+	query := `
+		SELECT ` + feedbackSubmissionColumns + `
+		FROM feedback_submissions
+		WHERE task_id = $1 AND status IN ('approved', 'merged')
+	`
+
+	var submissions []*domain.FeedbackSubmission
+	if err := conn(ctx, r.db).SelectContext(ctx, &submissions, query, taskID); err != nil {
+		r.logger.Error("Failed to list feedback submissions by task", err, map[string]interface{}{
+			"task_id": taskID,
+		})
+		return nil, fmt.Errorf("failed to list feedback submissions by task: %w", err)
+	}
+
+	return submissions, nil
+}
+
+// SetNotifiedTaskStatus запоминает статус задачи, о котором сообщили автору запроса
+func (r *FeedbackRepository) SetNotifiedTaskStatus(ctx context.Context, id string, status domain.TaskStatus) error {
+	query := `UPDATE feedback_submissions SET notified_task_status = $2, updated_at = NOW() WHERE id = $1`
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query, id, status); err != nil {
+		r.logger.Error("Failed to update notified task status", err, map[string]interface{}{
+			"id": id,
+		})
+		return fmt.Errorf("failed to update notified task status: %w", err)
+	}
+
+	return nil
+}
+
+// FindSimilarTasks возвращает незакрытые задачи проекта, название которых похоже на заголовок запроса
+func (r *FeedbackRepository) FindSimilarTasks(ctx context.Context, projectID string, title string, limit int) ([]*domain.TypeaheadItem, error) {
+	query := `
+		SELECT t.id, t.title, t.status AS subtitle
+		FROM tasks t
+		WHERE t.project_id = $1
+		AND t.status <> 'cancelled'
+		AND similarity(t.title, $2) > 0.3
+		ORDER BY similarity(t.title, $2) DESC, t.updated_at DESC
+		LIMIT $3
+	`
+
+	var items []*domain.TypeaheadItem
+	if err := conn(ctx, r.db).SelectContext(ctx, &items, query, projectID, title, limit); err != nil {
+		r.logger.Error("Failed to find similar tasks", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return nil, fmt.Errorf("failed to find similar tasks: %w", err)
+	}
+
+	return items, nil
+}
@@ -0,0 +1,141 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/pkg/logger"
+)
+
+// InboundEmailRepository реализует репозиторий адресов проектов для создания задач по email с использованием PostgreSQL
+type InboundEmailRepository struct {
+	db     *sqlx.DB
+	logger logger.Logger
+}
+
+// NewInboundEmailRepository создает новый экземпляр InboundEmailRepository
+func NewInboundEmailRepository(db *sqlx.DB, logger logger.Logger) *InboundEmailRepository {
+	return &InboundEmailRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// GetByProject возвращает адрес проекта
+func (r *InboundEmailRepository) GetByProject(ctx context.Context, projectID string) (*domain.ProjectInboundEmail, error) {
+	return r.get(ctx, "project_id", projectID)
+}
+
+// GetByToken возвращает адрес проекта по токену
+func (r *InboundEmailRepository) GetByToken(ctx context.Context, token string) (*domain.ProjectInboundEmail, error) {
+	return r.get(ctx, "token", token)
+}
+
+// Save создает или обновляет адрес проекта
+func (r *InboundEmailRepository) Save(ctx context.Context, inbound *domain.ProjectInboundEmail) error {
+	query := `
+		INSERT INTO project_inbound_emails (
+			project_id, token, enabled, spam_threshold, created_by, created_at, updated_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7
+		)
+		ON CONFLICT (project_id) DO UPDATE SET
+			token = EXCLUDED.token,
+			enabled = EXCLUDED.enabled,
+			spam_threshold = EXCLUDED.spam_threshold,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	_, err := conn(ctx, r.db).ExecContext(
+		ctx,
+		query,
+		inbound.ProjectID,
+		inbound.Token,
+		inbound.Enabled,
+		inbound.SpamThreshold,
+		inbound.CreatedBy,
+		inbound.CreatedAt,
+		inbound.UpdatedAt,
+	)
+	if err != nil {
+		r.logger.Error("Failed to save project inbound email", err, map[string]interface{}{
+			"project_id": inbound.ProjectID,
+		})
+		return fmt.Errorf("failed to save project inbound email: %w", err)
+	}
+
+	return nil
+}
+
+// Delete удаляет адрес проекта
+func (r *InboundEmailRepository) Delete(ctx context.Context, projectID string) error {
+	query := `DELETE FROM project_inbound_emails WHERE project_id = $1`
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query, projectID); err != nil {
+		r.logger.Error("Failed to delete project inbound email", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return fmt.Errorf("failed to delete project inbound email: %w", err)
+	}
+
+	return nil
+}
+
+// IsMessageProcessed проверяет, было ли письмо уже обработано
+func (r *InboundEmailRepository) IsMessageProcessed(ctx context.Context, messageID string) (bool, error) {
+	query := `SELECT EXISTS(SELECT 1 FROM inbound_email_messages WHERE message_id = $1)`
+
+	var exists bool
+	if err := conn(ctx, r.db).GetContext(ctx, &exists, query, messageID); err != nil {
+		r.logger.Error("Failed to check inbound email message", err, map[string]interface{}{
+			"message_id": messageID,
+		})
+		return false, fmt.Errorf("failed to check inbound email message: %w", err)
+	}
+
+	return exists, nil
+}
+
+// RecordMessage сохраняет запись об обработанном письме
+func (r *InboundEmailRepository) RecordMessage(ctx context.Context, message *domain.InboundEmailMessage) error {
+	query := `
+		INSERT INTO inbound_email_messages (message_id, project_id, task_id, sender, received_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (message_id) DO NOTHING
+	`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, message.MessageID, message.ProjectID, message.TaskID, message.Sender, message.ReceivedAt)
+	if err != nil {
+		r.logger.Error("Failed to record inbound email message", err, map[string]interface{}{
+			"message_id": message.MessageID,
+		})
+		return fmt.Errorf("failed to record inbound email message: %w", err)
+	}
+
+	return nil
+}
+
+// get возвращает адрес проекта по значению колонки
+func (r *InboundEmailRepository) get(ctx context.Context, column, value string) (*domain.ProjectInboundEmail, error) {
+	query := fmt.Sprintf(`
+		SELECT project_id, token, enabled, spam_threshold, created_by, created_at, updated_at
+		FROM project_inbound_emails
+		WHERE %s = $1
+	`, column)
+
+	var inbound domain.ProjectInboundEmail
+	if err := conn(ctx, r.db).GetContext(ctx, &inbound, query, value); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		r.logger.Error("Failed to get project inbound email", err, map[string]interface{}{
+			column: value,
+		})
+		return nil, fmt.Errorf("failed to get project inbound email: %w", err)
+	}
+
+	return &inbound, nil
+}
@@ -0,0 +1,168 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/pkg/logger"
+)
+
+// InboxRepository реализует репозиторий входящих задач с использованием PostgreSQL
+type InboxRepository struct {
+	db     *sqlx.DB
+	logger logger.Logger
+}
+
+// NewInboxRepository создает новый экземпляр InboxRepository
+func NewInboxRepository(db *sqlx.DB, logger logger.Logger) *InboxRepository {
+	return &InboxRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Create записывает задачу во входящие
+func (r *InboxRepository) Create(ctx context.Context, item *domain.InboxItem) error {
+	query := `
+		INSERT INTO inbox_items (id, user_id, title, description, priority, due_date, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	_, err := conn(ctx, r.db).ExecContext(
+		ctx,
+		query,
+		item.ID,
+		item.UserID,
+		item.Title,
+		item.Description,
+		item.Priority,
+		item.DueDate,
+		item.CreatedAt,
+	)
+	if err != nil {
+		r.logger.Error("Failed to create inbox item", err, map[string]interface{}{
+			"user_id": item.UserID,
+		})
+		return fmt.Errorf("failed to create inbox item: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID возвращает запись входящих по ID
+func (r *InboxRepository) GetByID(ctx context.Context, id string) (*domain.InboxItem, error) {
+	query := `
+		SELECT id, user_id, title, description, priority, due_date, created_at
+		FROM inbox_items
+		WHERE id = $1
+	`
+
+	var item domain.InboxItem
+	if err := conn(ctx, r.db).GetContext(ctx, &item, query, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		r.logger.Error("Failed to get inbox item", err, map[string]interface{}{
+			"id": id,
+		})
+		return nil, fmt.Errorf("failed to get inbox item: %w", err)
+	}
+
+	return &item, nil
+}
+
+// ListByUser возвращает входящие пользователя, начиная со старых
+func (r *InboxRepository) ListByUser(ctx context.Context, userID string, limit, offset int) ([]*domain.InboxItem, error) {
+	query := `
+		SELECT id, user_id, title, description, priority, due_date, created_at
+		FROM inbox_items
+		WHERE user_id = $1
+		ORDER BY created_at ASC, id ASC
+		LIMIT $2 OFFSET $3
+	`
+
+	var items []*domain.InboxItem
+	if err := conn(ctx, r.db).SelectContext(ctx, &items, query, userID, limit, offset); err != nil {
+		r.logger.Error("Failed to list inbox items", err, map[string]interface{}{
+			"user_id": userID,
+		})
+		return nil, fmt.Errorf("failed to list inbox items: %w", err)
+	}
+
+	return items, nil
+}
+
+// CountByUser возвращает количество записей во входящих пользователя
+func (r *InboxRepository) CountByUser(ctx context.Context, userID string) (int, error) {
+	query := `SELECT COUNT(*) FROM inbox_items WHERE user_id = $1`
+
+	var count int
+	if err := conn(ctx, r.db).GetContext(ctx, &count, query, userID); err != nil {
+		r.logger.Error("Failed to count inbox items", err, map[string]interface{}{
+			"user_id": userID,
+		})
+		return 0, fmt.Errorf("failed to count inbox items: %w", err)
+	}
+
+	return count, nil
+}
+
+// Delete удаляет запись входящих
+func (r *InboxRepository) Delete(ctx context.Context, id string) error {
+	query := `DELETE FROM inbox_items WHERE id = $1`
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query, id); err != nil {
+		r.logger.Error("Failed to delete inbox item", err, map[string]interface{}{
+			"id": id,
+		})
+		return fmt.Errorf("failed to delete inbox item: %w", err)
+	}
+
+	return nil
+}
+
+// Claim удаляет запись входящих пользователя и возвращает ее. Удаление условное, поэтому
+// из одновременных запросов запись получит только один
+func (r *InboxRepository) Claim(ctx context.Context, id string, userID string) (*domain.InboxItem, error) {
+	query := `
+		DELETE FROM inbox_items
+		WHERE id = $1 AND user_id = $2
+		RETURNING id, user_id, title, description, priority, due_date, created_at
+	`
+
+	var item domain.InboxItem
+	if err := conn(ctx, r.db).GetContext(ctx, &item, query, id, userID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		r.logger.Error("Failed to claim inbox item", err, map[string]interface{}{
+			"id": id,
+		})
+		return nil, fmt.Errorf("failed to claim inbox item: %w", err)
+	}
+
+	return &item, nil
+}
+
+// ListStaleOwners возвращает пользователей с записями входящих старше createdBefore
+func (r *InboxRepository) ListStaleOwners(ctx context.Context, createdBefore time.Time) ([]*domain.InboxReminder, error) {
+	query := `
+		SELECT i.user_id, COUNT(*) AS stale_items, MIN(i.created_at) AS oldest_since
+		FROM inbox_items i
+		JOIN users u ON u.id = i.user_id
+		WHERE i.created_at < $1 AND u.is_active = TRUE
+		GROUP BY i.user_id
+	`
+
+	var reminders []*domain.InboxReminder
+	if err := conn(ctx, r.db).SelectContext(ctx, &reminders, query, createdBefore); err != nil {
+		r.logger.Error("Failed to list stale inbox owners", err)
+		return nil, fmt.Errorf("failed to list stale inbox owners: %w", err)
+	}
+
+	return reminders, nil
+}
@@ -0,0 +1,281 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/pkg/logger"
+)
+
+// incidentIntegrationColumns перечисляет колонки интеграции для выборок
+const incidentIntegrationColumns = `
+	id, project_id, provider, secret, api_key, from_email, mapping,
+	enabled, created_by, created_at, updated_at
+`
+
+// incidentLinkColumns перечисляет колонки связи инцидента с задачей для выборок
+const incidentLinkColumns = `
+	integration_id, incident_id, task_id, status, url, created_at, updated_at
+`
+
+// incidentIntegrationRow представляет строку таблицы incident_integrations
+type incidentIntegrationRow struct {
+	domain.IncidentIntegration
+	MappingJSON []byte `db:"mapping"`
+}
+
+func (row *incidentIntegrationRow) toDomain() (*domain.IncidentIntegration, error) {
+	integration := row.IncidentIntegration
+	if err := json.Unmarshal(row.MappingJSON, &integration.Mapping); err != nil {
+		return nil, fmt.Errorf("failed to decode incident integration mapping: %w", err)
+	}
+	return &integration, nil
+}
+
+// IncidentRepository реализует репозиторий интеграций систем управления инцидентами с использованием PostgreSQL
+type IncidentRepository struct {
+	db     *sqlx.DB
+	logger logger.Logger
+}
+
+// NewIncidentRepository создает новый экземпляр IncidentRepository
+func NewIncidentRepository(db *sqlx.DB, logger logger.Logger) *IncidentRepository {
+	return &IncidentRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// GetIntegration возвращает интеграцию по ID
+func (r *IncidentRepository) GetIntegration(ctx context.Context, id string) (*domain.IncidentIntegration, error) {
+	query := `SELECT ` + incidentIntegrationColumns + ` FROM incident_integrations WHERE id = $1`
+
+	var row incidentIntegrationRow
+	if err := conn(ctx, r.db).GetContext(ctx, &row, query, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		r.logger.Error("Failed to get incident integration", err, map[string]interface{}{
+			"id": id,
+		})
+		return nil, fmt.Errorf("failed to get incident integration: %w", err)
+	}
+
+	return row.toDomain()
+}
+
+// GetIntegrationByProject возвращает интеграцию проекта с системой
+func (r *IncidentRepository) GetIntegrationByProject(ctx context.Context, projectID string, provider domain.IncidentProvider) (*domain.IncidentIntegration, error) {
+	query := `SELECT ` + incidentIntegrationColumns + ` FROM incident_integrations WHERE project_id = $1 AND provider = $2`
+
+	var row incidentIntegrationRow
+	if err := conn(ctx, r.db).GetContext(ctx, &row, query, projectID, provider); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		r.logger.Error("Failed to get project incident integration", err, map[string]interface{}{
+			"project_id": projectID,
+			"provider":   provider,
+		})
+		return nil, fmt.Errorf("failed to get project incident integration: %w", err)
+	}
+
+	return row.toDomain()
+}
+
+// ListIntegrations возвращает интеграции проекта
+func (r *IncidentRepository) ListIntegrations(ctx context.Context, projectID string) ([]*domain.IncidentIntegration, error) {
+	query := `
+		SELECT ` + incidentIntegrationColumns + `
+		FROM incident_integrations
+		WHERE project_id = $1
+		ORDER BY provider
+	`
+
+	var rows []incidentIntegrationRow
+	if err := conn(ctx, r.db).SelectContext(ctx, &rows, query, projectID); err != nil {
+		r.logger.Error("Failed to list incident integrations", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return nil, fmt.Errorf("failed to list incident integrations: %w", err)
+	}
+
+	integrations := make([]*domain.IncidentIntegration, 0, len(rows))
+	for i := range rows {
+		integration, err := rows[i].toDomain()
+		if err != nil {
+			return nil, err
+		}
+		integrations = append(integrations, integration)
+	}
+
+	return integrations, nil
+}
+
+// SaveIntegration создает или обновляет интеграцию проекта с системой
+func (r *IncidentRepository) SaveIntegration(ctx context.Context, integration *domain.IncidentIntegration) error {
+	query := `
+		INSERT INTO incident_integrations (
+			id, project_id, provider, secret, api_key, from_email, mapping,
+			enabled, created_by, created_at, updated_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+		)
+		ON CONFLICT (project_id, provider) DO UPDATE SET
+			secret = EXCLUDED.secret,
+			api_key = EXCLUDED.api_key,
+			from_email = EXCLUDED.from_email,
+			mapping = EXCLUDED.mapping,
+			enabled = EXCLUDED.enabled,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	mapping, err := json.Marshal(integration.Mapping)
+	if err != nil {
+		return fmt.Errorf("failed to encode incident integration mapping: %w", err)
+	}
+
+	_, err = conn(ctx, r.db).ExecContext(
+		ctx,
+		query,
+		integration.ID,
+		integration.ProjectID,
+		integration.Provider,
+		integration.Secret,
+		integration.APIKey,
+		integration.FromEmail,
+		mapping,
+		integration.Enabled,
+		integration.CreatedBy,
+		integration.CreatedAt,
+		integration.UpdatedAt,
+	)
+	if err != nil {
+		r.logger.Error("Failed to save incident integration", err, map[string]interface{}{
+			"project_id": integration.ProjectID,
+			"provider":   integration.Provider,
+		})
+		return fmt.Errorf("failed to save incident integration: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteIntegration удаляет интеграцию вместе со связями инцидентов
+func (r *IncidentRepository) DeleteIntegration(ctx context.Context, id string) error {
+	query := `DELETE FROM incident_integrations WHERE id = $1`
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query, id); err != nil {
+		r.logger.Error("Failed to delete incident integration", err, map[string]interface{}{
+			"id": id,
+		})
+		return fmt.Errorf("failed to delete incident integration: %w", err)
+	}
+
+	return nil
+}
+
+// GetLink возвращает связь инцидента с задачей
+func (r *IncidentRepository) GetLink(ctx context.Context, integrationID, incidentID string) (*domain.IncidentLink, error) {
+	query := `SELECT ` + incidentLinkColumns + ` FROM incident_links WHERE integration_id = $1 AND incident_id = $2`
+
+	var link domain.IncidentLink
+	if err := conn(ctx, r.db).GetContext(ctx, &link, query, integrationID, incidentID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		r.logger.Error("Failed to get incident link", err, map[string]interface{}{
+			"integration_id": integrationID,
+			"incident_id":    incidentID,
+		})
+		return nil, fmt.Errorf("failed to get incident link: %w", err)
+	}
+
+	return &link, nil
+}
+
+// GetLinkByTask возвращает связь задачи с инцидентом
+func (r *IncidentRepository) GetLinkByTask(ctx context.Context, taskID string) (*domain.IncidentLink, error) {
+	query := `SELECT ` + incidentLinkColumns + ` FROM incident_links WHERE task_id = $1`
+
+	var link domain.IncidentLink
+	if err := conn(ctx, r.db).GetContext(ctx, &link, query, taskID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		r.logger.Error("Failed to get incident link by task", err, map[string]interface{}{
+			"task_id": taskID,
+		})
+		return nil, fmt.Errorf("failed to get incident link by task: %w", err)
+	}
+
+	return &link, nil
+}
+
+// CreateLink сохраняет связь инцидента с задачей
+func (r *IncidentRepository) CreateLink(ctx context.Context, link *domain.IncidentLink) error {
+	query := `
+		INSERT INTO incident_links (
+			integration_id, incident_id, task_id, status, url, created_at, updated_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7
+		)
+	`
+
+	_, err := conn(ctx, r.db).ExecContext(
+		ctx,
+		query,
+		link.IntegrationID,
+		link.IncidentID,
+		link.TaskID,
+		link.Status,
+		link.URL,
+		link.CreatedAt,
+		link.UpdatedAt,
+	)
+	if err != nil {
+		r.logger.Error("Failed to create incident link", err, map[string]interface{}{
+			"integration_id": link.IntegrationID,
+			"incident_id":    link.IncidentID,
+		})
+		return fmt.Errorf("failed to create incident link: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateLinkStatus переводит связь в новое состояние инцидента, если она находится в одном из ожидаемых состояний
+func (r *IncidentRepository) UpdateLinkStatus(ctx context.Context, integrationID, incidentID string, from []domain.IncidentStatus, to domain.IncidentStatus) (bool, error) {
+	query := `
+		UPDATE incident_links
+		SET status = $1, updated_at = $2
+		WHERE integration_id = $3 AND incident_id = $4 AND status = ANY($5)
+	`
+
+	statuses := make([]string, 0, len(from))
+	for _, status := range from {
+		statuses = append(statuses, string(status))
+	}
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, to, time.Now(), integrationID, incidentID, pq.StringArray(statuses))
+	if err != nil {
+		r.logger.Error("Failed to update incident link status", err, map[string]interface{}{
+			"integration_id": integrationID,
+			"incident_id":    incidentID,
+		})
+		return false, fmt.Errorf("failed to update incident link status: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
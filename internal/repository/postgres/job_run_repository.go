@@ -0,0 +1,206 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/internal/repository"
+	"github.com/nurlyy/task_manager/pkg/logger"
+)
+
+// JobRunRepository реализует репозиторий истории запусков планировщика с использованием PostgreSQL
+type JobRunRepository struct {
+	db     *sqlx.DB
+	logger logger.Logger
+}
+
+// NewJobRunRepository создает новый экземпляр JobRunRepository
+func NewJobRunRepository(db *sqlx.DB, logger logger.Logger) *JobRunRepository {
+	return &JobRunRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Create сохраняет начатый запуск
+func (r *JobRunRepository) Create(ctx context.Context, run *domain.JobRun) error {
+	query := `
+		INSERT INTO job_runs (id, job, status, started_at)
+		VALUES ($1, $2, $3, $4)
+	`
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query, run.ID, run.Job, run.Status, run.StartedAt); err != nil {
+		r.logger.Error("Failed to create job run", err, map[string]interface{}{
+			"job": run.Job,
+		})
+		return fmt.Errorf("failed to create job run: %w", err)
+	}
+
+	return nil
+}
+
+// Finish сохраняет итоги запуска
+func (r *JobRunRepository) Finish(ctx context.Context, run *domain.JobRun) error {
+	query := `
+		UPDATE job_runs
+		SET status = $1, finished_at = $2, items_processed = $3, errors = $4, error = $5,
+			overran = overran OR $6
+		WHERE id = $7
+	`
+
+	_, err := conn(ctx, r.db).ExecContext(
+		ctx,
+		query,
+		run.Status,
+		run.FinishedAt,
+		run.ItemsProcessed,
+		run.Errors,
+		run.Error,
+		run.Overran,
+		run.ID,
+	)
+	if err != nil {
+		r.logger.Error("Failed to finish job run", err, map[string]interface{}{
+			"id":  run.ID,
+			"job": run.Job,
+		})
+		return fmt.Errorf("failed to finish job run: %w", err)
+	}
+
+	return nil
+}
+
+// MarkOverran помечает выполняющийся запуск как превысивший интервал расписания
+func (r *JobRunRepository) MarkOverran(ctx context.Context, id string) error {
+	query := `UPDATE job_runs SET overran = TRUE WHERE id = $1`
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query, id); err != nil {
+		r.logger.Error("Failed to mark job run overran", err, map[string]interface{}{
+			"id": id,
+		})
+		return fmt.Errorf("failed to mark job run overran: %w", err)
+	}
+
+	return nil
+}
+
+// FailRunning завершает с ошибкой оставшиеся выполняющимися запуски, начатые до before
+func (r *JobRunRepository) FailRunning(ctx context.Context, before time.Time, reason string) (int, error) {
+	query := `
+		UPDATE job_runs
+		SET status = $1, finished_at = NOW(), error = $2
+		WHERE status = $3 AND started_at < $4
+	`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, domain.JobRunStatusFailed, reason, domain.JobRunStatusRunning, before)
+	if err != nil {
+		r.logger.Error("Failed to fail running job runs", err)
+		return 0, fmt.Errorf("failed to fail running job runs: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return int(rowsAffected), nil
+}
+
+// List возвращает запуски с фильтрацией, начиная с последних
+func (r *JobRunRepository) List(ctx context.Context, filter repository.JobRunFilter) ([]*domain.JobRun, error) {
+	whereClause, args := buildJobRunWhere(filter)
+
+	query := fmt.Sprintf(`
+		SELECT id, job, status, started_at, finished_at, items_processed, errors, error, overran
+		FROM job_runs
+		%s
+		ORDER BY started_at DESC, id
+		LIMIT %d OFFSET %d
+	`, whereClause, filter.Limit, filter.Offset)
+
+	runs := []*domain.JobRun{}
+	if err := conn(ctx, r.db).SelectContext(ctx, &runs, query, args...); err != nil {
+		r.logger.Error("Failed to list job runs", err)
+		return nil, fmt.Errorf("failed to list job runs: %w", err)
+	}
+
+	return runs, nil
+}
+
+// Count возвращает количество запусков с фильтрацией
+func (r *JobRunRepository) Count(ctx context.Context, filter repository.JobRunFilter) (int, error) {
+	whereClause, args := buildJobRunWhere(filter)
+
+	query := fmt.Sprintf(`SELECT COUNT(*) FROM job_runs %s`, whereClause)
+
+	var count int
+	if err := conn(ctx, r.db).GetContext(ctx, &count, query, args...); err != nil {
+		r.logger.Error("Failed to count job runs", err)
+		return 0, fmt.Errorf("failed to count job runs: %w", err)
+	}
+
+	return count, nil
+}
+
+// ListLatest возвращает последний запуск каждой задачи
+func (r *JobRunRepository) ListLatest(ctx context.Context) ([]*domain.JobRun, error) {
+	query := `
+		SELECT DISTINCT ON (job)
+			id, job, status, started_at, finished_at, items_processed, errors, error, overran
+		FROM job_runs
+		ORDER BY job, started_at DESC
+	`
+
+	runs := []*domain.JobRun{}
+	if err := conn(ctx, r.db).SelectContext(ctx, &runs, query); err != nil {
+		r.logger.Error("Failed to list latest job runs", err)
+		return nil, fmt.Errorf("failed to list latest job runs: %w", err)
+	}
+
+	return runs, nil
+}
+
+// DeleteBefore удаляет запуски, начатые до before
+func (r *JobRunRepository) DeleteBefore(ctx context.Context, before time.Time) (int, error) {
+	query := `DELETE FROM job_runs WHERE started_at < $1 AND status != $2`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, before, domain.JobRunStatusRunning)
+	if err != nil {
+		r.logger.Error("Failed to delete job runs", err)
+		return 0, fmt.Errorf("failed to delete job runs: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return int(rowsAffected), nil
+}
+
+// buildJobRunWhere формирует условие WHERE для фильтра истории запусков
+func buildJobRunWhere(filter repository.JobRunFilter) (string, []interface{}) {
+	conditions := []string{}
+	args := []interface{}{}
+
+	if filter.Job != nil {
+		args = append(args, *filter.Job)
+		conditions = append(conditions, fmt.Sprintf("job = $%d", len(args)))
+	}
+	if filter.Status != nil {
+		args = append(args, *filter.Status)
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+	if filter.Overran {
+		conditions = append(conditions, "overran")
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return "WHERE " + strings.Join(conditions, " AND "), args
+}
@@ -0,0 +1,118 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/pkg/logger"
+)
+
+// projectMatrixRoomRow представляет строку таблицы project_matrix_rooms
+type projectMatrixRoomRow struct {
+	domain.ProjectMatrixRoom
+	Types pq.StringArray `db:"notification_types"`
+}
+
+// MatrixRepository реализует репозиторий комнат Matrix с использованием PostgreSQL
+type MatrixRepository struct {
+	db     *sqlx.DB
+	logger logger.Logger
+}
+
+// NewMatrixRepository создает новый экземпляр MatrixRepository
+func NewMatrixRepository(db *sqlx.DB, logger logger.Logger) *MatrixRepository {
+	return &MatrixRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// GetProjectRoom возвращает комнату проекта
+func (r *MatrixRepository) GetProjectRoom(ctx context.Context, projectID string) (*domain.ProjectMatrixRoom, error) {
+	query := `
+		SELECT project_id, room_id, COALESCE(room_alias, '') AS room_alias, enabled, notification_types,
+			created_by, created_at, updated_at
+		FROM project_matrix_rooms
+		WHERE project_id = $1
+	`
+
+	var row projectMatrixRoomRow
+	if err := conn(ctx, r.db).GetContext(ctx, &row, query, projectID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		r.logger.Error("Failed to get project Matrix room", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return nil, fmt.Errorf("failed to get project Matrix room: %w", err)
+	}
+
+	room := row.ProjectMatrixRoom
+	room.NotificationTypes = make([]domain.NotificationType, 0, len(row.Types))
+	for _, notificationType := range row.Types {
+		room.NotificationTypes = append(room.NotificationTypes, domain.NotificationType(notificationType))
+	}
+
+	return &room, nil
+}
+
+// SaveProjectRoom создает или обновляет комнату проекта
+func (r *MatrixRepository) SaveProjectRoom(ctx context.Context, room *domain.ProjectMatrixRoom) error {
+	query := `
+		INSERT INTO project_matrix_rooms (
+			project_id, room_id, room_alias, enabled, notification_types, created_by, created_at, updated_at
+		) VALUES (
+			$1, $2, NULLIF($3, ''), $4, $5, $6, $7, $8
+		)
+		ON CONFLICT (project_id) DO UPDATE SET
+			room_id = EXCLUDED.room_id,
+			room_alias = EXCLUDED.room_alias,
+			enabled = EXCLUDED.enabled,
+			notification_types = EXCLUDED.notification_types,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	types := make(pq.StringArray, 0, len(room.NotificationTypes))
+	for _, notificationType := range room.NotificationTypes {
+		types = append(types, string(notificationType))
+	}
+
+	_, err := conn(ctx, r.db).ExecContext(
+		ctx,
+		query,
+		room.ProjectID,
+		room.RoomID,
+		room.RoomAlias,
+		room.Enabled,
+		types,
+		room.CreatedBy,
+		room.CreatedAt,
+		room.UpdatedAt,
+	)
+	if err != nil {
+		r.logger.Error("Failed to save project Matrix room", err, map[string]interface{}{
+			"project_id": room.ProjectID,
+		})
+		return fmt.Errorf("failed to save project Matrix room: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteProjectRoom удаляет комнату проекта
+func (r *MatrixRepository) DeleteProjectRoom(ctx context.Context, projectID string) error {
+	query := `DELETE FROM project_matrix_rooms WHERE project_id = $1`
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query, projectID); err != nil {
+		r.logger.Error("Failed to delete project Matrix room", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return fmt.Errorf("failed to delete project Matrix room: %w", err)
+	}
+
+	return nil
+}
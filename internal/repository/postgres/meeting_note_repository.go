@@ -0,0 +1,223 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/pkg/logger"
+)
+
+// MeetingNoteRepository реализует репозиторий заметок встреч с использованием PostgreSQL
+type MeetingNoteRepository struct {
+	db     *sqlx.DB
+	logger logger.Logger
+}
+
+// NewMeetingNoteRepository создает новый экземпляр MeetingNoteRepository
+func NewMeetingNoteRepository(db *sqlx.DB, logger logger.Logger) *MeetingNoteRepository {
+	return &MeetingNoteRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Create сохраняет заметку
+func (r *MeetingNoteRepository) Create(ctx context.Context, note *domain.MeetingNote) error {
+	query := `
+		INSERT INTO meeting_notes (
+			id, project_id, title, held_at, content, created_by, created_at, updated_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8
+		)
+	`
+
+	if _, err := conn(ctx, r.db).ExecContext(
+		ctx,
+		query,
+		note.ID,
+		note.ProjectID,
+		note.Title,
+		note.HeldAt,
+		note.Content,
+		note.CreatedBy,
+		note.CreatedAt,
+		note.UpdatedAt,
+	); err != nil {
+		r.logger.Error("Failed to create meeting note", err, map[string]interface{}{
+			"project_id": note.ProjectID,
+		})
+		return fmt.Errorf("failed to create meeting note: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID возвращает заметку по ID
+func (r *MeetingNoteRepository) GetByID(ctx context.Context, id string) (*domain.MeetingNote, error) {
+	query := `
+		SELECT id, project_id, title, held_at, content, created_by, created_at, updated_at
+		FROM meeting_notes
+		WHERE id = $1
+	`
+
+	var note domain.MeetingNote
+	if err := conn(ctx, r.db).GetContext(ctx, &note, query, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		r.logger.Error("Failed to get meeting note", err, map[string]interface{}{
+			"id": id,
+		})
+		return nil, fmt.Errorf("failed to get meeting note: %w", err)
+	}
+
+	return &note, nil
+}
+
+// Update обновляет заметку
+func (r *MeetingNoteRepository) Update(ctx context.Context, note *domain.MeetingNote) error {
+	query := `
+		UPDATE meeting_notes
+		SET title = $1, held_at = $2, content = $3, updated_at = $4
+		WHERE id = $5
+	`
+
+	if _, err := conn(ctx, r.db).ExecContext(
+		ctx,
+		query,
+		note.Title,
+		note.HeldAt,
+		note.Content,
+		note.UpdatedAt,
+		note.ID,
+	); err != nil {
+		r.logger.Error("Failed to update meeting note", err, map[string]interface{}{
+			"id": note.ID,
+		})
+		return fmt.Errorf("failed to update meeting note: %w", err)
+	}
+
+	return nil
+}
+
+// Delete удаляет заметку
+func (r *MeetingNoteRepository) Delete(ctx context.Context, id string) error {
+	query := `DELETE FROM meeting_notes WHERE id = $1`
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query, id); err != nil {
+		r.logger.Error("Failed to delete meeting note", err, map[string]interface{}{
+			"id": id,
+		})
+		return fmt.Errorf("failed to delete meeting note: %w", err)
+	}
+
+	return nil
+}
+
+// ListByProject возвращает заметки проекта, сначала последние встречи
+func (r *MeetingNoteRepository) ListByProject(ctx context.Context, projectID string, limit, offset int) ([]*domain.MeetingNote, error) {
+	query := `
+		SELECT id, project_id, title, held_at, content, created_by, created_at, updated_at
+		FROM meeting_notes
+		WHERE project_id = $1
+		ORDER BY held_at DESC, id
+		LIMIT $2 OFFSET $3
+	`
+
+	notes := []*domain.MeetingNote{}
+	if err := conn(ctx, r.db).SelectContext(ctx, &notes, query, projectID, limit, offset); err != nil {
+		r.logger.Error("Failed to list meeting notes", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return nil, fmt.Errorf("failed to list meeting notes: %w", err)
+	}
+
+	return notes, nil
+}
+
+// CountByProject возвращает количество заметок проекта
+func (r *MeetingNoteRepository) CountByProject(ctx context.Context, projectID string) (int, error) {
+	query := `SELECT COUNT(*) FROM meeting_notes WHERE project_id = $1`
+
+	var count int
+	if err := conn(ctx, r.db).GetContext(ctx, &count, query, projectID); err != nil {
+		r.logger.Error("Failed to count meeting notes", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return 0, fmt.Errorf("failed to count meeting notes: %w", err)
+	}
+
+	return count, nil
+}
+
+// CreateTaskLink сохраняет связь задачи с пунктом заметки
+func (r *MeetingNoteRepository) CreateTaskLink(ctx context.Context, link *domain.MeetingNoteTask) error {
+	query := `
+		INSERT INTO meeting_note_tasks (note_id, task_id, item_text, created_by, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	if _, err := conn(ctx, r.db).ExecContext(
+		ctx,
+		query,
+		link.NoteID,
+		link.TaskID,
+		link.ItemText,
+		link.CreatedBy,
+		link.CreatedAt,
+	); err != nil {
+		r.logger.Error("Failed to link meeting note task", err, map[string]interface{}{
+			"note_id": link.NoteID,
+			"task_id": link.TaskID,
+		})
+		return fmt.Errorf("failed to link meeting note task: %w", err)
+	}
+
+	return nil
+}
+
+// ListTaskLinks возвращает задачи, созданные из пунктов заметки
+func (r *MeetingNoteRepository) ListTaskLinks(ctx context.Context, noteID string) ([]*domain.MeetingNoteTask, error) {
+	query := `
+		SELECT note_id, task_id, item_text, created_by, created_at
+		FROM meeting_note_tasks
+		WHERE note_id = $1
+		ORDER BY created_at
+	`
+
+	links := []*domain.MeetingNoteTask{}
+	if err := conn(ctx, r.db).SelectContext(ctx, &links, query, noteID); err != nil {
+		r.logger.Error("Failed to list meeting note tasks", err, map[string]interface{}{
+			"note_id": noteID,
+		})
+		return nil, fmt.Errorf("failed to list meeting note tasks: %w", err)
+	}
+
+	return links, nil
+}
+
+// GetByTask возвращает заметку, из которой создана задача
+func (r *MeetingNoteRepository) GetByTask(ctx context.Context, taskID string) (*domain.MeetingNote, error) {
+	query := `
+		SELECT n.id, n.project_id, n.title, n.held_at, n.content, n.created_by, n.created_at, n.updated_at
+		FROM meeting_notes n
+		JOIN meeting_note_tasks mt ON mt.note_id = n.id
+		WHERE mt.task_id = $1
+	`
+
+	var note domain.MeetingNote
+	if err := conn(ctx, r.db).GetContext(ctx, &note, query, taskID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		r.logger.Error("Failed to get task meeting note", err, map[string]interface{}{
+			"task_id": taskID,
+		})
+		return nil, fmt.Errorf("failed to get task meeting note: %w", err)
+	}
+
+	return &note, nil
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/nurlyy/task_manager/internal/domain"
 	"github.com/nurlyy/task_manager/internal/repository"
 	"github.com/nurlyy/task_manager/pkg/logger"
@@ -47,7 +48,7 @@ func (r *NotificationRepository) Create(ctx context.Context, notification *domai
 		return fmt.Errorf("failed to marshal meta data: %w", err)
 	}
 
-	err = r.db.QueryRowxContext(
+	err = conn(ctx, r.db).QueryRowxContext(
 		ctx,
 		query,
 		notification.ID,
@@ -79,7 +80,7 @@ func (r *NotificationRepository) CreateBatch(ctx context.Context, notifications
 		return nil
 	}
 
-	tx, err := r.db.BeginTxx(ctx, nil)
+	tx, err := beginTx(ctx, r.db)
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
@@ -107,8 +108,10 @@ func (r *NotificationRepository) CreateBatch(ctx context.Context, notifications
 	defer stmt.Close()
 
 	for _, notification := range notifications {
-		// Сериализуем метаданные в JSON
-		metaDataJSON, err := json.Marshal(notification.MetaData)
+		// Сериализуем метаданные в JSON. Ошибка присваивается внешней переменной,
+		// чтобы отложенная функция откатила транзакцию
+		var metaDataJSON []byte
+		metaDataJSON, err = json.Marshal(notification.MetaData)
 		if err != nil {
 			r.logger.Error("Failed to marshal meta data", err, map[string]interface{}{
 				"notification_id": notification.ID,
@@ -158,7 +161,7 @@ func (r *NotificationRepository) GetByID(ctx context.Context, id string) (*domai
 	var notification domain.Notification
 	var metaDataJSON []byte
 
-	err := r.db.QueryRowxContext(ctx, query, id).Scan(
+	err := conn(ctx, r.db).QueryRowxContext(ctx, query, id).Scan(
 		&notification.ID,
 		&notification.UserID,
 		&notification.Type,
@@ -221,7 +224,7 @@ func (r *NotificationRepository) Update(ctx context.Context, notification *domai
 		return fmt.Errorf("failed to marshal meta data: %w", err)
 	}
 
-	result, err := r.db.ExecContext(
+	result, err := conn(ctx, r.db).ExecContext(
 		ctx,
 		query,
 		notification.Type,
@@ -258,7 +261,7 @@ func (r *NotificationRepository) Update(ctx context.Context, notification *domai
 func (r *NotificationRepository) Delete(ctx context.Context, id string) error {
 	query := `UPDATE notifications SET status = 'deleted' WHERE id = $1`
 
-	result, err := r.db.ExecContext(ctx, query, id)
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, id)
 	if err != nil {
 		r.logger.Error("Failed to delete notification", err, map[string]interface{}{
 			"id": id,
@@ -352,7 +355,7 @@ func (r *NotificationRepository) GetUserNotifications(ctx context.Context, userI
 		%s
 	`, whereClause, orderClause, limitOffset)
 
-	rows, err := r.db.QueryContext(ctx, query, args...)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
 	if err != nil {
 		r.logger.Error("Failed to get user notifications", err, map[string]interface{}{
 			"user_id": userID,
@@ -440,7 +443,7 @@ func (r *NotificationRepository) CountUserNotifications(ctx context.Context, use
 	`, whereClause)
 
 	var count int
-	err := r.db.GetContext(ctx, &count, query, args...)
+	err := conn(ctx, r.db).GetContext(ctx, &count, query, args...)
 	if err != nil {
 		r.logger.Error("Failed to count user notifications", err, map[string]interface{}{
 			"user_id": userID,
@@ -455,7 +458,7 @@ func (r *NotificationRepository) CountUserNotifications(ctx context.Context, use
 func (r *NotificationRepository) MarkAsRead(ctx context.Context, id string) error {
 	query := `UPDATE notifications SET status = 'read', read_at = $1 WHERE id = $2`
 
-	result, err := r.db.ExecContext(ctx, query, time.Now(), id)
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, time.Now(), id)
 	if err != nil {
 		r.logger.Error("Failed to mark notification as read", err, map[string]interface{}{
 			"id": id,
@@ -479,7 +482,7 @@ func (r *NotificationRepository) MarkAsRead(ctx context.Context, id string) erro
 func (r *NotificationRepository) MarkAllAsRead(ctx context.Context, userID string) error {
 	query := `UPDATE notifications SET status = 'read', read_at = $1 WHERE user_id = $2 AND status = 'unread'`
 
-	_, err := r.db.ExecContext(ctx, query, time.Now(), userID)
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, time.Now(), userID)
 	if err != nil {
 		r.logger.Error("Failed to mark all notifications as read", err, map[string]interface{}{
 			"user_id": userID,
@@ -490,11 +493,64 @@ func (r *NotificationRepository) MarkAllAsRead(ctx context.Context, userID strin
 	return nil
 }
 
+// MarkAsReadByFilter отмечает прочитанными непрочитанные уведомления пользователя,
+// подходящие под фильтр, и возвращает их количество. ID, статус и сортировка фильтра не учитываются
+func (r *NotificationRepository) MarkAsReadByFilter(ctx context.Context, userID string, filter repository.NotificationFilter) (int, error) {
+	whereClause := "WHERE user_id = $2 AND status = 'unread'"
+	args := []interface{}{time.Now(), userID}
+
+	if len(filter.Types) > 0 {
+		placeholders := make([]string, len(filter.Types))
+		for i, t := range filter.Types {
+			placeholders[i] = fmt.Sprintf("$%d", len(args)+i+1)
+			args = append(args, t)
+		}
+		whereClause = whereClause + " AND type IN (" + strings.Join(placeholders, ", ") + ")"
+	}
+
+	if filter.EntityID != nil {
+		whereClause = whereClause + " AND entity_id = $" + fmt.Sprintf("%d", len(args)+1)
+		args = append(args, *filter.EntityID)
+	}
+
+	if filter.EntityType != nil {
+		whereClause = whereClause + " AND entity_type = $" + fmt.Sprintf("%d", len(args)+1)
+		args = append(args, *filter.EntityType)
+	}
+
+	if filter.StartDate != nil {
+		whereClause = whereClause + " AND created_at >= $" + fmt.Sprintf("%d", len(args)+1)
+		args = append(args, *filter.StartDate)
+	}
+
+	if filter.EndDate != nil {
+		whereClause = whereClause + " AND created_at <= $" + fmt.Sprintf("%d", len(args)+1)
+		args = append(args, *filter.EndDate)
+	}
+
+	query := `UPDATE notifications SET status = 'read', read_at = $1 ` + whereClause
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		r.logger.Error("Failed to mark notifications as read by filter", err, map[string]interface{}{
+			"user_id": userID,
+		})
+		return 0, fmt.Errorf("failed to mark notifications as read by filter: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return int(rowsAffected), nil
+}
+
 // DeleteAllByUser удаляет все уведомления пользователя
 func (r *NotificationRepository) DeleteAllByUser(ctx context.Context, userID string) error {
 	query := `UPDATE notifications SET status = 'deleted' WHERE user_id = $1`
 
-	_, err := r.db.ExecContext(ctx, query, userID)
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, userID)
 	if err != nil {
 		r.logger.Error("Failed to delete all notifications", err, map[string]interface{}{
 			"user_id": userID,
@@ -510,7 +566,7 @@ func (r *NotificationRepository) GetUserUnreadCount(ctx context.Context, userID
 	query := `SELECT COUNT(*) FROM notifications WHERE user_id = $1 AND status = 'unread'`
 
 	var count int
-	err := r.db.GetContext(ctx, &count, query, userID)
+	err := conn(ctx, r.db).GetContext(ctx, &count, query, userID)
 	if err != nil {
 		r.logger.Error("Failed to get unread count", err, map[string]interface{}{
 			"user_id": userID,
@@ -521,17 +577,47 @@ func (r *NotificationRepository) GetUserUnreadCount(ctx context.Context, userID
 	return count, nil
 }
 
+// GetUnreadCounts возвращает количество непрочитанных уведомлений указанных пользователей
+func (r *NotificationRepository) GetUnreadCounts(ctx context.Context, userIDs []string) (map[string]int, error) {
+	query := `
+		SELECT user_id, COUNT(*) AS count
+		FROM notifications
+		WHERE user_id = ANY($1::uuid[]) AND status = 'unread'
+		GROUP BY user_id
+	`
+
+	type userCount struct {
+		UserID string `db:"user_id"`
+		Count  int    `db:"count"`
+	}
+
+	var rows []userCount
+	if err := conn(ctx, r.db).SelectContext(ctx, &rows, query, pq.Array(userIDs)); err != nil {
+		r.logger.Error("Failed to get unread counts", err, map[string]interface{}{
+			"users": len(userIDs),
+		})
+		return nil, fmt.Errorf("failed to get unread counts: %w", err)
+	}
+
+	counts := make(map[string]int, len(rows))
+	for _, row := range rows {
+		counts[row.UserID] = row.Count
+	}
+
+	return counts, nil
+}
+
 // GetUserNotificationSettings возвращает настройки уведомлений пользователя
 func (r *NotificationRepository) GetUserNotificationSettings(ctx context.Context, userID string) ([]*repository.NotificationSetting, error) {
 	query := `
 		SELECT 
-			user_id, notification_type, email_enabled, web_enabled, telegram_enabled
+			user_id, notification_type, email_enabled, web_enabled, telegram_enabled, teams_enabled
 		FROM user_notification_settings
 		WHERE user_id = $1
 	`
 
 	settings := []*repository.NotificationSetting{}
-	err := r.db.SelectContext(ctx, &settings, query, userID)
+	err := conn(ctx, r.db).SelectContext(ctx, &settings, query, userID)
 	if err != nil {
 		r.logger.Error("Failed to get notification settings", err, map[string]interface{}{
 			"user_id": userID,
@@ -542,9 +628,38 @@ func (r *NotificationRepository) GetUserNotificationSettings(ctx context.Context
 	return settings, nil
 }
 
+// GetSettingsForUsers возвращает настройки уведомлений нескольких пользователей
+func (r *NotificationRepository) GetSettingsForUsers(ctx context.Context, userIDs []string) (map[string][]*repository.NotificationSetting, error) {
+	if len(userIDs) == 0 {
+		return map[string][]*repository.NotificationSetting{}, nil
+	}
+
+	query := `
+		SELECT
+			user_id, notification_type, email_enabled, web_enabled, telegram_enabled, teams_enabled
+		FROM user_notification_settings
+		WHERE user_id = ANY($1::uuid[])
+	`
+
+	var rows []*repository.NotificationSetting
+	if err := conn(ctx, r.db).SelectContext(ctx, &rows, query, pq.Array(userIDs)); err != nil {
+		r.logger.Error("Failed to get notification settings for users", err, map[string]interface{}{
+			"users": len(userIDs),
+		})
+		return nil, fmt.Errorf("failed to get notification settings for users: %w", err)
+	}
+
+	settings := make(map[string][]*repository.NotificationSetting, len(userIDs))
+	for _, row := range rows {
+		settings[row.UserID] = append(settings[row.UserID], row)
+	}
+
+	return settings, nil
+}
+
 // UpdateUserNotificationSettings обновляет настройки уведомлений пользователя
 func (r *NotificationRepository) UpdateUserNotificationSettings(ctx context.Context, userID string, settings []*repository.NotificationSetting) error {
-	tx, err := r.db.BeginTxx(ctx, nil)
+	tx, err := beginTx(ctx, r.db)
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
@@ -569,9 +684,9 @@ func (r *NotificationRepository) UpdateUserNotificationSettings(ctx context.Cont
 	// Добавляем новые настройки
 	query := `
 		INSERT INTO user_notification_settings (
-			user_id, notification_type, email_enabled, web_enabled, telegram_enabled
+			user_id, notification_type, email_enabled, web_enabled, telegram_enabled, teams_enabled
 		) VALUES (
-			$1, $2, $3, $4, $5
+			$1, $2, $3, $4, $5, $6
 		)
 	`
 
@@ -589,6 +704,7 @@ func (r *NotificationRepository) UpdateUserNotificationSettings(ctx context.Cont
 			setting.EmailEnabled,
 			setting.WebEnabled,
 			setting.TelegramEnabled,
+			setting.TeamsEnabled,
 		)
 
 		if err != nil {
@@ -607,6 +723,29 @@ func (r *NotificationRepository) UpdateUserNotificationSettings(ctx context.Cont
 	return nil
 }
 
+// SetEmailEnabled включает или отключает email-уведомления указанного типа для пользователя
+func (r *NotificationRepository) SetEmailEnabled(ctx context.Context, userID string, notificationType domain.NotificationType, enabled bool) error {
+	query := `
+		INSERT INTO user_notification_settings (
+			user_id, notification_type, email_enabled
+		) VALUES (
+			$1, $2, $3
+		) ON CONFLICT (user_id, notification_type) DO UPDATE
+		SET email_enabled = $3
+	`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, userID, notificationType, enabled)
+	if err != nil {
+		r.logger.Error("Failed to set email notification setting", err, map[string]interface{}{
+			"user_id": userID,
+			"type":    notificationType,
+		})
+		return fmt.Errorf("failed to set email notification setting: %w", err)
+	}
+
+	return nil
+}
+
 // Вспомогательные функции
 
 func (r *NotificationRepository) buildWhereClause(filter repository.NotificationFilter) (string, []interface{}) {
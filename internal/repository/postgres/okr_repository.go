@@ -0,0 +1,360 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/pkg/logger"
+)
+
+// OKRRepository реализует репозиторий целей и ключевых результатов с использованием PostgreSQL
+type OKRRepository struct {
+	db     *sqlx.DB
+	logger logger.Logger
+}
+
+// NewOKRRepository создает новый экземпляр OKRRepository
+func NewOKRRepository(db *sqlx.DB, logger logger.Logger) *OKRRepository {
+	return &OKRRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// CreateObjective создает новую цель
+func (r *OKRRepository) CreateObjective(ctx context.Context, objective *domain.Objective) error {
+	query := `
+		INSERT INTO objectives (
+			id, title, description, owner_id, year, quarter, created_by, created_at, updated_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9
+		)
+	`
+
+	_, err := conn(ctx, r.db).ExecContext(
+		ctx,
+		query,
+		objective.ID,
+		objective.Title,
+		objective.Description,
+		objective.OwnerID,
+		objective.Year,
+		objective.Quarter,
+		objective.CreatedBy,
+		objective.CreatedAt,
+		objective.UpdatedAt,
+	)
+	if err != nil {
+		r.logger.Error("Failed to create objective", err, map[string]interface{}{
+			"title": objective.Title,
+		})
+		return fmt.Errorf("failed to create objective: %w", err)
+	}
+
+	return nil
+}
+
+// GetObjective возвращает цель по ID
+func (r *OKRRepository) GetObjective(ctx context.Context, id string) (*domain.Objective, error) {
+	query := `
+		SELECT id, title, description, owner_id, year, quarter, created_by, created_at, updated_at
+		FROM objectives
+		WHERE id = $1
+	`
+
+	var objective domain.Objective
+	if err := conn(ctx, r.db).GetContext(ctx, &objective, query, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		r.logger.Error("Failed to get objective", err, map[string]interface{}{
+			"id": id,
+		})
+		return nil, fmt.Errorf("failed to get objective: %w", err)
+	}
+
+	return &objective, nil
+}
+
+// UpdateObjective обновляет цель
+func (r *OKRRepository) UpdateObjective(ctx context.Context, objective *domain.Objective) error {
+	query := `
+		UPDATE objectives
+		SET title = $1, description = $2, owner_id = $3, year = $4, quarter = $5, updated_at = $6
+		WHERE id = $7
+	`
+
+	_, err := conn(ctx, r.db).ExecContext(
+		ctx,
+		query,
+		objective.Title,
+		objective.Description,
+		objective.OwnerID,
+		objective.Year,
+		objective.Quarter,
+		objective.UpdatedAt,
+		objective.ID,
+	)
+	if err != nil {
+		r.logger.Error("Failed to update objective", err, map[string]interface{}{
+			"id": objective.ID,
+		})
+		return fmt.Errorf("failed to update objective: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteObjective удаляет цель
+func (r *OKRRepository) DeleteObjective(ctx context.Context, id string) error {
+	query := `DELETE FROM objectives WHERE id = $1`
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query, id); err != nil {
+		r.logger.Error("Failed to delete objective", err, map[string]interface{}{
+			"id": id,
+		})
+		return fmt.Errorf("failed to delete objective: %w", err)
+	}
+
+	return nil
+}
+
+// ListObjectives возвращает цели квартала
+func (r *OKRRepository) ListObjectives(ctx context.Context, year, quarter int) ([]*domain.Objective, error) {
+	query := `
+		SELECT id, title, description, owner_id, year, quarter, created_by, created_at, updated_at
+		FROM objectives
+		WHERE ($1 = 0 OR year = $1) AND ($2 = 0 OR quarter = $2)
+		ORDER BY year DESC, quarter DESC, created_at ASC
+	`
+
+	objectives := []*domain.Objective{}
+	if err := conn(ctx, r.db).SelectContext(ctx, &objectives, query, year, quarter); err != nil {
+		r.logger.Error("Failed to list objectives", err, map[string]interface{}{
+			"year":    year,
+			"quarter": quarter,
+		})
+		return nil, fmt.Errorf("failed to list objectives: %w", err)
+	}
+
+	return objectives, nil
+}
+
+// CreateKeyResult создает ключевой результат
+func (r *OKRRepository) CreateKeyResult(ctx context.Context, keyResult *domain.KeyResult) error {
+	query := `
+		INSERT INTO key_results (id, objective_id, title, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	_, err := conn(ctx, r.db).ExecContext(
+		ctx,
+		query,
+		keyResult.ID,
+		keyResult.ObjectiveID,
+		keyResult.Title,
+		keyResult.CreatedAt,
+		keyResult.UpdatedAt,
+	)
+	if err != nil {
+		r.logger.Error("Failed to create key result", err, map[string]interface{}{
+			"objective_id": keyResult.ObjectiveID,
+		})
+		return fmt.Errorf("failed to create key result: %w", err)
+	}
+
+	return nil
+}
+
+// GetKeyResult возвращает ключевой результат по ID
+func (r *OKRRepository) GetKeyResult(ctx context.Context, id string) (*domain.KeyResult, error) {
+	query := `
+		SELECT id, objective_id, title, created_at, updated_at
+		FROM key_results
+		WHERE id = $1
+	`
+
+	var keyResult domain.KeyResult
+	if err := conn(ctx, r.db).GetContext(ctx, &keyResult, query, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		r.logger.Error("Failed to get key result", err, map[string]interface{}{
+			"id": id,
+		})
+		return nil, fmt.Errorf("failed to get key result: %w", err)
+	}
+
+	return &keyResult, nil
+}
+
+// UpdateKeyResult обновляет ключевой результат
+func (r *OKRRepository) UpdateKeyResult(ctx context.Context, keyResult *domain.KeyResult) error {
+	query := `UPDATE key_results SET title = $1, updated_at = $2 WHERE id = $3`
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query, keyResult.Title, keyResult.UpdatedAt, keyResult.ID); err != nil {
+		r.logger.Error("Failed to update key result", err, map[string]interface{}{
+			"id": keyResult.ID,
+		})
+		return fmt.Errorf("failed to update key result: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteKeyResult удаляет ключевой результат
+func (r *OKRRepository) DeleteKeyResult(ctx context.Context, id string) error {
+	query := `DELETE FROM key_results WHERE id = $1`
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query, id); err != nil {
+		r.logger.Error("Failed to delete key result", err, map[string]interface{}{
+			"id": id,
+		})
+		return fmt.Errorf("failed to delete key result: %w", err)
+	}
+
+	return nil
+}
+
+// ListKeyResults возвращает ключевые результаты указанных целей
+func (r *OKRRepository) ListKeyResults(ctx context.Context, objectiveIDs []string) ([]*domain.KeyResult, error) {
+	keyResults := []*domain.KeyResult{}
+	if len(objectiveIDs) == 0 {
+		return keyResults, nil
+	}
+
+	query := `
+		SELECT id, objective_id, title, created_at, updated_at
+		FROM key_results
+		WHERE objective_id = ANY($1)
+		ORDER BY created_at ASC
+	`
+
+	if err := conn(ctx, r.db).SelectContext(ctx, &keyResults, query, pq.Array(objectiveIDs)); err != nil {
+		r.logger.Error("Failed to list key results", err)
+		return nil, fmt.Errorf("failed to list key results: %w", err)
+	}
+
+	return keyResults, nil
+}
+
+// CreateLink связывает ключевой результат с проектом или эпиком
+func (r *OKRRepository) CreateLink(ctx context.Context, link *domain.KeyResultLink) (bool, error) {
+	query := `
+		INSERT INTO key_result_links (id, key_result_id, project_id, epic_id, created_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT DO NOTHING
+	`
+
+	result, err := conn(ctx, r.db).ExecContext(
+		ctx,
+		query,
+		link.ID,
+		link.KeyResultID,
+		link.ProjectID,
+		link.EpicID,
+		link.CreatedBy,
+		link.CreatedAt,
+	)
+	if err != nil {
+		r.logger.Error("Failed to create key result link", err, map[string]interface{}{
+			"key_result_id": link.KeyResultID,
+		})
+		return false, fmt.Errorf("failed to create key result link: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// GetLink возвращает связь по ID
+func (r *OKRRepository) GetLink(ctx context.Context, id string) (*domain.KeyResultLink, error) {
+	query := `
+		SELECT id, key_result_id, project_id, epic_id, created_by, created_at
+		FROM key_result_links
+		WHERE id = $1
+	`
+
+	var link domain.KeyResultLink
+	if err := conn(ctx, r.db).GetContext(ctx, &link, query, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		r.logger.Error("Failed to get key result link", err, map[string]interface{}{
+			"id": id,
+		})
+		return nil, fmt.Errorf("failed to get key result link: %w", err)
+	}
+
+	return &link, nil
+}
+
+// DeleteLink удаляет связь
+func (r *OKRRepository) DeleteLink(ctx context.Context, id string) error {
+	query := `DELETE FROM key_result_links WHERE id = $1`
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query, id); err != nil {
+		r.logger.Error("Failed to delete key result link", err, map[string]interface{}{
+			"id": id,
+		})
+		return fmt.Errorf("failed to delete key result link: %w", err)
+	}
+
+	return nil
+}
+
+// ListLinks возвращает связи указанных ключевых результатов
+func (r *OKRRepository) ListLinks(ctx context.Context, keyResultIDs []string) ([]*domain.KeyResultLink, error) {
+	links := []*domain.KeyResultLink{}
+	if len(keyResultIDs) == 0 {
+		return links, nil
+	}
+
+	query := `
+		SELECT id, key_result_id, project_id, epic_id, created_by, created_at
+		FROM key_result_links
+		WHERE key_result_id = ANY($1)
+		ORDER BY created_at ASC
+	`
+
+	if err := conn(ctx, r.db).SelectContext(ctx, &links, query, pq.Array(keyResultIDs)); err != nil {
+		r.logger.Error("Failed to list key result links", err)
+		return nil, fmt.Errorf("failed to list key result links: %w", err)
+	}
+
+	return links, nil
+}
+
+// CountKeyResultTasks возвращает число задач связанных проектов и эпиков
+func (r *OKRRepository) CountKeyResultTasks(ctx context.Context, keyResultIDs []string) ([]*domain.KeyResultTaskCount, error) {
+	counts := []*domain.KeyResultTaskCount{}
+	if len(keyResultIDs) == 0 {
+		return counts, nil
+	}
+
+	query := `
+		SELECT
+			l.key_result_id,
+			COUNT(DISTINCT t.id) AS total_tasks,
+			COUNT(DISTINCT t.id) FILTER (WHERE t.status = 'completed') AS completed_tasks
+		FROM key_result_links l
+		JOIN tasks t ON t.project_id = l.project_id OR t.epic_id = l.epic_id
+		WHERE l.key_result_id = ANY($1) AND t.status != 'cancelled'
+		GROUP BY l.key_result_id
+	`
+
+	if err := conn(ctx, r.db).SelectContext(ctx, &counts, query, pq.Array(keyResultIDs)); err != nil {
+		r.logger.Error("Failed to count key result tasks", err)
+		return nil, fmt.Errorf("failed to count key result tasks: %w", err)
+	}
+
+	return counts, nil
+}
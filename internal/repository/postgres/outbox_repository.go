@@ -0,0 +1,101 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/pkg/logger"
+)
+
+// OutboxRepository реализует репозиторий исходящих событий с использованием PostgreSQL
+type OutboxRepository struct {
+	db     *sqlx.DB
+	logger logger.Logger
+}
+
+// NewOutboxRepository создает новый экземпляр OutboxRepository
+func NewOutboxRepository(db *sqlx.DB, logger logger.Logger) *OutboxRepository {
+	return &OutboxRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Add записывает событие в транзакции из контекста
+func (r *OutboxRepository) Add(ctx context.Context, message *domain.OutboxMessage) error {
+	query := `
+		INSERT INTO event_outbox (topic, message_key, payload)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at
+	`
+
+	err := conn(ctx, r.db).QueryRowxContext(ctx, query, message.Topic, message.Key, string(message.Payload)).
+		Scan(&message.ID, &message.CreatedAt)
+	if err != nil {
+		r.logger.Error("Failed to add outbox message", err, map[string]interface{}{
+			"topic": message.Topic,
+			"key":   message.Key,
+		})
+		return fmt.Errorf("failed to add outbox message: %w", err)
+	}
+
+	return nil
+}
+
+// ClaimPending возвращает самые старые неопубликованные события и блокирует их до конца транзакции.
+// События, попытки публикации которых исчерпаны, остаются в таблице для разбора
+func (r *OutboxRepository) ClaimPending(ctx context.Context, limit, maxAttempts int) ([]*domain.OutboxMessage, error) {
+	query := `
+		SELECT id, topic, message_key, payload, attempts, last_error, created_at
+		FROM event_outbox
+		WHERE attempts < $1
+		ORDER BY id
+		LIMIT $2
+		FOR UPDATE SKIP LOCKED
+	`
+
+	messages := []*domain.OutboxMessage{}
+	if err := conn(ctx, r.db).SelectContext(ctx, &messages, query, maxAttempts, limit); err != nil {
+		r.logger.Error("Failed to claim outbox messages", err)
+		return nil, fmt.Errorf("failed to claim outbox messages: %w", err)
+	}
+
+	return messages, nil
+}
+
+// Delete удаляет опубликованные события
+func (r *OutboxRepository) Delete(ctx context.Context, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, `DELETE FROM event_outbox WHERE id = ANY($1)`, pq.Array(ids)); err != nil {
+		r.logger.Error("Failed to delete outbox messages", err, map[string]interface{}{
+			"count": len(ids),
+		})
+		return fmt.Errorf("failed to delete outbox messages: %w", err)
+	}
+
+	return nil
+}
+
+// Fail увеличивает счетчик попыток публикации события
+func (r *OutboxRepository) Fail(ctx context.Context, id int64, errMsg string) error {
+	query := `
+		UPDATE event_outbox
+		SET attempts = attempts + 1, last_error = $1
+		WHERE id = $2
+	`
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query, errMsg, id); err != nil {
+		r.logger.Error("Failed to record outbox publish failure", err, map[string]interface{}{
+			"id": id,
+		})
+		return fmt.Errorf("failed to record outbox publish failure: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,99 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/pkg/logger"
+)
+
+// ProjectInvitationRepository реализует репозиторий приглашений в проекты с использованием PostgreSQL
+type ProjectInvitationRepository struct {
+	db     *sqlx.DB
+	logger logger.Logger
+}
+
+// NewProjectInvitationRepository создает новый экземпляр ProjectInvitationRepository
+func NewProjectInvitationRepository(db *sqlx.DB, logger logger.Logger) *ProjectInvitationRepository {
+	return &ProjectInvitationRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// SaveInvitation создает приглашение или обновляет неиспользованное приглашение того же email.
+// ID и дата создания существующего приглашения записываются в invitation
+func (r *ProjectInvitationRepository) SaveInvitation(ctx context.Context, invitation *domain.ProjectInvitation) error {
+	query := `
+		INSERT INTO project_invitations (
+			id, project_id, email, role, token_hash, invited_by, created_at, expires_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8
+		)
+		ON CONFLICT (project_id, email) WHERE accepted_at IS NULL DO UPDATE SET
+			role = EXCLUDED.role,
+			token_hash = EXCLUDED.token_hash,
+			invited_by = EXCLUDED.invited_by,
+			expires_at = EXCLUDED.expires_at
+		RETURNING id, created_at
+	`
+
+	err := conn(ctx, r.db).QueryRowxContext(
+		ctx,
+		query,
+		invitation.ID,
+		invitation.ProjectID,
+		invitation.Email,
+		invitation.Role,
+		invitation.TokenHash,
+		invitation.InvitedBy,
+		invitation.CreatedAt,
+		invitation.ExpiresAt,
+	).Scan(&invitation.ID, &invitation.CreatedAt)
+	if err != nil {
+		r.logger.Error("Failed to save project invitation", err, map[string]interface{}{
+			"project_id": invitation.ProjectID,
+		})
+		return fmt.Errorf("failed to save project invitation: %w", err)
+	}
+
+	return nil
+}
+
+// GetInvitationByToken возвращает приглашение по хешу токена
+func (r *ProjectInvitationRepository) GetInvitationByToken(ctx context.Context, tokenHash string) (*domain.ProjectInvitation, error) {
+	query := `
+		SELECT id, project_id, email, role, token_hash, invited_by, created_at, expires_at, accepted_at
+		FROM project_invitations
+		WHERE token_hash = $1
+	`
+
+	var invitation domain.ProjectInvitation
+	if err := conn(ctx, r.db).GetContext(ctx, &invitation, query, tokenHash); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		r.logger.Error("Failed to get project invitation by token", err)
+		return nil, fmt.Errorf("failed to get project invitation: %w", err)
+	}
+
+	return &invitation, nil
+}
+
+// MarkInvitationAccepted отмечает приглашение принятым
+func (r *ProjectInvitationRepository) MarkInvitationAccepted(ctx context.Context, id string, acceptedAt time.Time) error {
+	query := `UPDATE project_invitations SET accepted_at = $2 WHERE id = $1`
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query, id, acceptedAt); err != nil {
+		r.logger.Error("Failed to mark project invitation accepted", err, map[string]interface{}{
+			"id": id,
+		})
+		return fmt.Errorf("failed to mark project invitation accepted: %w", err)
+	}
+
+	return nil
+}
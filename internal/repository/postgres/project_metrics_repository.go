@@ -0,0 +1,270 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/pkg/logger"
+)
+
+// ProjectMetricsRepository реализует репозиторий материализованных метрик проектов с использованием PostgreSQL
+type ProjectMetricsRepository struct {
+	db     *sqlx.DB
+	logger logger.Logger
+}
+
+// NewProjectMetricsRepository создает новый экземпляр ProjectMetricsRepository
+func NewProjectMetricsRepository(db *sqlx.DB, logger logger.Logger) *ProjectMetricsRepository {
+	return &ProjectMetricsRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Get возвращает сохраненные метрики проекта (nil, если метрики еще не рассчитывались)
+func (r *ProjectMetricsRepository) Get(ctx context.Context, projectID string) (*domain.ProjectMetrics, error) {
+	query := `
+		SELECT task_count, completed_tasks, overdue_tasks, tasks_by_status, tasks_by_user, refreshed_at
+		FROM project_metrics
+		WHERE project_id = $1 AND refreshed_at IS NOT NULL
+	`
+
+	var metrics domain.ProjectMetrics
+	var tasksByStatus, tasksByUser []byte
+	var refreshedAt time.Time
+	err := conn(ctx, r.db).QueryRowxContext(ctx, query, projectID).Scan(
+		&metrics.TaskCount,
+		&metrics.CompletedTasks,
+		&metrics.OverdueTasks,
+		&tasksByStatus,
+		&tasksByUser,
+		&refreshedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		r.logger.Error("Failed to get project metrics", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return nil, fmt.Errorf("failed to get project metrics: %w", err)
+	}
+
+	if err := json.Unmarshal(tasksByStatus, &metrics.TasksByStatus); err != nil {
+		r.logger.Error("Failed to decode project metrics by status", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return nil, fmt.Errorf("failed to decode project metrics by status: %w", err)
+	}
+	if err := json.Unmarshal(tasksByUser, &metrics.TasksByUser); err != nil {
+		r.logger.Error("Failed to decode project metrics by user", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return nil, fmt.Errorf("failed to decode project metrics by user: %w", err)
+	}
+	metrics.RefreshedAt = &refreshedAt
+
+	return &metrics, nil
+}
+
+// ClearDirty снимает пометку устаревания перед пересчетом метрик.
+// Обновление строки ждет фиксации транзакций, которые пометили ее устаревшей
+func (r *ProjectMetricsRepository) ClearDirty(ctx context.Context, projectID string) error {
+	query := `
+		INSERT INTO project_metrics (project_id, dirty)
+		VALUES ($1, FALSE)
+		ON CONFLICT (project_id) DO UPDATE SET dirty = FALSE
+	`
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query, projectID); err != nil {
+		r.logger.Error("Failed to clear project metrics dirty flag", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return fmt.Errorf("failed to clear project metrics dirty flag: %w", err)
+	}
+
+	return nil
+}
+
+// Save сохраняет рассчитанные метрики проекта. Пометку устаревания не трогает:
+// если задачи изменились во время пересчета, метрики будут пересчитаны снова.
+// Результат более раннего параллельного пересчета не перезаписывает более поздний
+func (r *ProjectMetricsRepository) Save(ctx context.Context, projectID string, metrics *domain.ProjectMetrics) error {
+	tasksByStatus, err := json.Marshal(metrics.TasksByStatus)
+	if err != nil {
+		return fmt.Errorf("failed to encode project metrics by status: %w", err)
+	}
+	tasksByUser, err := json.Marshal(metrics.TasksByUser)
+	if err != nil {
+		return fmt.Errorf("failed to encode project metrics by user: %w", err)
+	}
+
+	query := `
+		UPDATE project_metrics
+		SET task_count = $2, completed_tasks = $3, overdue_tasks = $4,
+			tasks_by_status = $5, tasks_by_user = $6, refreshed_at = $7
+		WHERE project_id = $1 AND (refreshed_at IS NULL OR refreshed_at <= $7)
+	`
+
+	_, err = conn(ctx, r.db).ExecContext(
+		ctx,
+		query,
+		projectID,
+		metrics.TaskCount,
+		metrics.CompletedTasks,
+		metrics.OverdueTasks,
+		tasksByStatus,
+		tasksByUser,
+		metrics.RefreshedAt,
+	)
+	if err != nil {
+		r.logger.Error("Failed to save project metrics", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return fmt.Errorf("failed to save project metrics: %w", err)
+	}
+
+	return nil
+}
+
+// ListDirty возвращает ID проектов с устаревшими метриками, начиная с давно не пересчитанных
+func (r *ProjectMetricsRepository) ListDirty(ctx context.Context, limit int) ([]string, error) {
+	query := `
+		SELECT project_id
+		FROM project_metrics
+		WHERE dirty
+		ORDER BY refreshed_at NULLS FIRST
+		LIMIT $1
+	`
+
+	var projectIDs []string
+	if err := conn(ctx, r.db).SelectContext(ctx, &projectIDs, query, limit); err != nil {
+		r.logger.Error("Failed to list dirty project metrics", err)
+		return nil, fmt.Errorf("failed to list dirty project metrics: %w", err)
+	}
+
+	return projectIDs, nil
+}
+
+// MarkOverdueDirty помечает устаревшими метрики проектов, в которых после пересчета
+// наступил срок незавершенных задач, и возвращает количество таких проектов
+func (r *ProjectMetricsRepository) MarkOverdueDirty(ctx context.Context) (int, error) {
+	query := `
+		UPDATE project_metrics m
+		SET dirty = TRUE
+		WHERE NOT m.dirty AND EXISTS (
+			SELECT 1 FROM tasks t
+			WHERE t.project_id = m.project_id
+				AND t.status != 'completed'
+				AND t.due_date > m.refreshed_at
+				AND t.due_date <= NOW()
+		)
+	`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query)
+	if err != nil {
+		r.logger.Error("Failed to mark overdue project metrics dirty", err)
+		return 0, fmt.Errorf("failed to mark overdue project metrics dirty: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return int(rowsAffected), nil
+}
+
+// SaveSnapshots сохраняет снимки текущих метрик всех проектов за указанную дату.
+// Повторный вызов за ту же дату перезаписывает снимки
+func (r *ProjectMetricsRepository) SaveSnapshots(ctx context.Context, date time.Time) (int, error) {
+	query := `
+		INSERT INTO project_metrics_history (
+			project_id, snapshot_date, task_count, completed_tasks, overdue_tasks, tasks_by_status
+		)
+		SELECT project_id, $1::date, task_count, completed_tasks, overdue_tasks, tasks_by_status
+		FROM project_metrics
+		WHERE refreshed_at IS NOT NULL
+		ON CONFLICT (project_id, snapshot_date) DO UPDATE SET
+			task_count = EXCLUDED.task_count,
+			completed_tasks = EXCLUDED.completed_tasks,
+			overdue_tasks = EXCLUDED.overdue_tasks,
+			tasks_by_status = EXCLUDED.tasks_by_status
+	`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, date.Format("2006-01-02"))
+	if err != nil {
+		r.logger.Error("Failed to save project metrics snapshots", err, map[string]interface{}{
+			"date": date.Format("2006-01-02"),
+		})
+		return 0, fmt.Errorf("failed to save project metrics snapshots: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return int(rowsAffected), nil
+}
+
+// GetHistory возвращает снимки метрик проекта начиная с указанной даты в хронологическом порядке
+func (r *ProjectMetricsRepository) GetHistory(ctx context.Context, projectID string, from time.Time) ([]*domain.ProjectMetricsSnapshot, error) {
+	query := `
+		SELECT project_id, snapshot_date, task_count, completed_tasks, overdue_tasks, tasks_by_status
+		FROM project_metrics_history
+		WHERE project_id = $1 AND snapshot_date >= $2::date
+		ORDER BY snapshot_date
+	`
+
+	rows, err := conn(ctx, r.db).QueryxContext(ctx, query, projectID, from.Format("2006-01-02"))
+	if err != nil {
+		r.logger.Error("Failed to get project metrics history", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return nil, fmt.Errorf("failed to get project metrics history: %w", err)
+	}
+	defer rows.Close()
+
+	snapshots := make([]*domain.ProjectMetricsSnapshot, 0)
+	for rows.Next() {
+		var snapshot domain.ProjectMetricsSnapshot
+		var tasksByStatus []byte
+		if err := rows.Scan(
+			&snapshot.ProjectID,
+			&snapshot.SnapshotDate,
+			&snapshot.TaskCount,
+			&snapshot.CompletedTasks,
+			&snapshot.OverdueTasks,
+			&tasksByStatus,
+		); err != nil {
+			r.logger.Error("Failed to scan project metrics snapshot", err, map[string]interface{}{
+				"project_id": projectID,
+			})
+			return nil, fmt.Errorf("failed to scan project metrics snapshot: %w", err)
+		}
+
+		if err := json.Unmarshal(tasksByStatus, &snapshot.TasksByStatus); err != nil {
+			r.logger.Error("Failed to decode project metrics snapshot", err, map[string]interface{}{
+				"project_id": projectID,
+			})
+			return nil, fmt.Errorf("failed to decode project metrics snapshot: %w", err)
+		}
+
+		snapshots = append(snapshots, &snapshot)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.Error("Failed to iterate project metrics history", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return nil, fmt.Errorf("failed to iterate project metrics history: %w", err)
+	}
+
+	return snapshots, nil
+}
@@ -3,11 +3,13 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/nurlyy/task_manager/internal/domain"
 	"github.com/nurlyy/task_manager/internal/repository"
 	"github.com/nurlyy/task_manager/pkg/logger"
@@ -37,7 +39,7 @@ func (r *ProjectRepository) Create(ctx context.Context, project *domain.Project)
 		) RETURNING id
 	`
 
-	err := r.db.QueryRowxContext(
+	err := conn(ctx, r.db).QueryRowxContext(
 		ctx,
 		query,
 		project.ID,
@@ -71,7 +73,7 @@ func (r *ProjectRepository) GetByID(ctx context.Context, id string) (*domain.Pro
 	`
 
 	var project domain.Project
-	err := r.db.GetContext(ctx, &project, query, id)
+	err := conn(ctx, r.db).GetContext(ctx, &project, query, id)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -101,7 +103,7 @@ func (r *ProjectRepository) Update(ctx context.Context, project *domain.Project)
 
 	project.UpdatedAt = time.Now()
 
-	result, err := r.db.ExecContext(
+	result, err := conn(ctx, r.db).ExecContext(
 		ctx,
 		query,
 		project.Name,
@@ -136,7 +138,7 @@ func (r *ProjectRepository) Update(ctx context.Context, project *domain.Project)
 func (r *ProjectRepository) Delete(ctx context.Context, id string) error {
 	query := `DELETE FROM projects WHERE id = $1`
 
-	result, err := r.db.ExecContext(ctx, query, id)
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, id)
 	if err != nil {
 		r.logger.Error("Failed to delete project", err, map[string]interface{}{
 			"id": id,
@@ -172,7 +174,7 @@ func (r *ProjectRepository) List(ctx context.Context, filter repository.ProjectF
 	`, whereClause, orderClause, limitOffset)
 
 	projects := []*domain.Project{}
-	err := r.db.SelectContext(ctx, &projects, query, args...)
+	err := conn(ctx, r.db).SelectContext(ctx, &projects, query, args...)
 	if err != nil {
 		r.logger.Error("Failed to list projects", err)
 		return nil, fmt.Errorf("failed to list projects: %w", err)
@@ -192,7 +194,7 @@ func (r *ProjectRepository) Count(ctx context.Context, filter repository.Project
 	`, whereClause)
 
 	var count int
-	err := r.db.GetContext(ctx, &count, query, args...)
+	err := conn(ctx, r.db).GetContext(ctx, &count, query, args...)
 	if err != nil {
 		r.logger.Error("Failed to count projects", err)
 		return 0, fmt.Errorf("failed to count projects: %w", err)
@@ -212,7 +214,7 @@ func (r *ProjectRepository) AddMember(ctx context.Context, member *domain.Projec
 		SET role = $3, invited_by = $5
 	`
 
-	_, err := r.db.ExecContext(
+	_, err := conn(ctx, r.db).ExecContext(
 		ctx,
 		query,
 		member.ProjectID,
@@ -233,6 +235,53 @@ func (r *ProjectRepository) AddMember(ctx context.Context, member *domain.Projec
 	return nil
 }
 
+// AddMembers добавляет пользователей в проекты одним запросом
+func (r *ProjectRepository) AddMembers(ctx context.Context, members []*domain.ProjectMember) error {
+	if len(members) == 0 {
+		return nil
+	}
+
+	projectIDs := make([]string, len(members))
+	userIDs := make([]string, len(members))
+	roles := make([]string, len(members))
+	joinedAt := make([]time.Time, len(members))
+	invitedBy := make([]string, len(members))
+	for i, member := range members {
+		projectIDs[i] = member.ProjectID
+		userIDs[i] = member.UserID
+		roles[i] = string(member.Role)
+		joinedAt[i] = member.JoinedAt
+		invitedBy[i] = member.InvitedBy
+	}
+
+	query := `
+		INSERT INTO project_members (project_id, user_id, role, joined_at, invited_by)
+		SELECT m.project_id, m.user_id, m.role, m.joined_at, m.invited_by
+		FROM unnest($1::uuid[], $2::uuid[], $3::project_role[], $4::timestamptz[], $5::uuid[])
+			AS m(project_id, user_id, role, joined_at, invited_by)
+		ON CONFLICT (project_id, user_id) DO UPDATE
+		SET role = EXCLUDED.role, invited_by = EXCLUDED.invited_by
+	`
+
+	_, err := conn(ctx, r.db).ExecContext(
+		ctx,
+		query,
+		pq.Array(projectIDs),
+		pq.Array(userIDs),
+		pq.Array(roles),
+		pq.Array(joinedAt),
+		pq.Array(invitedBy),
+	)
+	if err != nil {
+		r.logger.Error("Failed to add project members", err, map[string]interface{}{
+			"count": len(members),
+		})
+		return fmt.Errorf("failed to add project members: %w", err)
+	}
+
+	return nil
+}
+
 // UpdateMember обновляет роль пользователя в проекте
 func (r *ProjectRepository) UpdateMember(ctx context.Context, projectID, userID string, role domain.ProjectRole) error {
 	query := `
@@ -241,7 +290,7 @@ func (r *ProjectRepository) UpdateMember(ctx context.Context, projectID, userID
 		WHERE project_id = $2 AND user_id = $3
 	`
 
-	result, err := r.db.ExecContext(ctx, query, role, projectID, userID)
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, role, projectID, userID)
 	if err != nil {
 		r.logger.Error("Failed to update project member", err, map[string]interface{}{
 			"project_id": projectID,
@@ -266,7 +315,7 @@ func (r *ProjectRepository) UpdateMember(ctx context.Context, projectID, userID
 func (r *ProjectRepository) RemoveMember(ctx context.Context, projectID, userID string) error {
 	query := `DELETE FROM project_members WHERE project_id = $1 AND user_id = $2`
 
-	result, err := r.db.ExecContext(ctx, query, projectID, userID)
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, projectID, userID)
 	if err != nil {
 		r.logger.Error("Failed to remove project member", err, map[string]interface{}{
 			"project_id": projectID,
@@ -297,7 +346,7 @@ func (r *ProjectRepository) GetMembers(ctx context.Context, projectID string) ([
 	`
 
 	members := []*domain.ProjectMember{}
-	err := r.db.SelectContext(ctx, &members, query, projectID)
+	err := conn(ctx, r.db).SelectContext(ctx, &members, query, projectID)
 	if err != nil {
 		r.logger.Error("Failed to get project members", err, map[string]interface{}{
 			"project_id": projectID,
@@ -318,7 +367,7 @@ func (r *ProjectRepository) GetMember(ctx context.Context, projectID, userID str
 	`
 
 	var member domain.ProjectMember
-	err := r.db.GetContext(ctx, &member, query, projectID, userID)
+	err := conn(ctx, r.db).GetContext(ctx, &member, query, projectID, userID)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -357,7 +406,7 @@ func (r *ProjectRepository) GetUserProjects(ctx context.Context, userID string,
 	`, whereClause, orderClause, limitOffset)
 
 	projects := []*domain.Project{}
-	err := r.db.SelectContext(ctx, &projects, query, args...)
+	err := conn(ctx, r.db).SelectContext(ctx, &projects, query, args...)
 	if err != nil {
 		r.logger.Error("Failed to get user projects", err, map[string]interface{}{
 			"user_id": userID,
@@ -386,7 +435,7 @@ func (r *ProjectRepository) CountUserProjects(ctx context.Context, userID string
 	`, whereClause)
 
 	var count int
-	err := r.db.GetContext(ctx, &count, query, args...)
+	err := conn(ctx, r.db).GetContext(ctx, &count, query, args...)
 	if err != nil {
 		r.logger.Error("Failed to count user projects", err, map[string]interface{}{
 			"user_id": userID,
@@ -397,6 +446,180 @@ func (r *ProjectRepository) CountUserProjects(ctx context.Context, userID string
 	return count, nil
 }
 
+// LogMembershipHistory добавляет запись в историю изменений участников проекта
+func (r *ProjectRepository) LogMembershipHistory(ctx context.Context, entry *domain.MembershipHistory) error {
+	query := `
+		INSERT INTO membership_history (
+			id, project_id, user_id, actor_id, action, old_role, new_role, changed_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8
+		)
+	`
+
+	_, err := conn(ctx, r.db).ExecContext(
+		ctx,
+		query,
+		entry.ID,
+		entry.ProjectID,
+		entry.UserID,
+		entry.ActorID,
+		entry.Action,
+		entry.OldRole,
+		entry.NewRole,
+		entry.ChangedAt,
+	)
+
+	if err != nil {
+		r.logger.Error("Failed to log membership history", err, map[string]interface{}{
+			"project_id": entry.ProjectID,
+			"user_id":    entry.UserID,
+			"action":     entry.Action,
+		})
+		return fmt.Errorf("failed to log membership history: %w", err)
+	}
+
+	return nil
+}
+
+// GetMembershipHistory возвращает историю изменений участников проекта, начиная с последних
+func (r *ProjectRepository) GetMembershipHistory(ctx context.Context, projectID string, limit, offset int) ([]*domain.MembershipHistory, error) {
+	query := `
+		SELECT
+			id, project_id, user_id, actor_id, action, old_role, new_role, changed_at
+		FROM membership_history
+		WHERE project_id = $1
+		ORDER BY changed_at DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	history := []*domain.MembershipHistory{}
+	err := conn(ctx, r.db).SelectContext(ctx, &history, query, projectID, limit, offset)
+	if err != nil {
+		r.logger.Error("Failed to get membership history", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return nil, fmt.Errorf("failed to get membership history: %w", err)
+	}
+
+	return history, nil
+}
+
+// CountMembershipHistory возвращает количество записей в истории участников проекта
+func (r *ProjectRepository) CountMembershipHistory(ctx context.Context, projectID string) (int, error) {
+	query := `SELECT COUNT(*) FROM membership_history WHERE project_id = $1`
+
+	var count int
+	err := conn(ctx, r.db).GetContext(ctx, &count, query, projectID)
+	if err != nil {
+		r.logger.Error("Failed to count membership history", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return 0, fmt.Errorf("failed to count membership history: %w", err)
+	}
+
+	return count, nil
+}
+
+// CreateOwnershipTransfer создает запрос на передачу владения проектом
+func (r *ProjectRepository) CreateOwnershipTransfer(ctx context.Context, transfer *domain.OwnershipTransfer) error {
+	query := `
+		INSERT INTO ownership_transfers (
+			id, project_id, from_user_id, to_user_id, status, created_at, expires_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7
+		)
+	`
+
+	_, err := conn(ctx, r.db).ExecContext(
+		ctx,
+		query,
+		transfer.ID,
+		transfer.ProjectID,
+		transfer.FromUserID,
+		transfer.ToUserID,
+		transfer.Status,
+		transfer.CreatedAt,
+		transfer.ExpiresAt,
+	)
+
+	if err != nil {
+		r.logger.Error("Failed to create ownership transfer", err, map[string]interface{}{
+			"project_id": transfer.ProjectID,
+		})
+		return fmt.Errorf("failed to create ownership transfer: %w", err)
+	}
+
+	return nil
+}
+
+// GetPendingOwnershipTransfer возвращает ожидающий запрос на передачу владения проектом
+func (r *ProjectRepository) GetPendingOwnershipTransfer(ctx context.Context, projectID string) (*domain.OwnershipTransfer, error) {
+	query := `
+		SELECT
+			id, project_id, from_user_id, to_user_id, status, created_at, expires_at, resolved_at, resolved_by
+		FROM ownership_transfers
+		WHERE project_id = $1 AND status = 'pending'
+	`
+
+	var transfer domain.OwnershipTransfer
+	err := conn(ctx, r.db).GetContext(ctx, &transfer, query, projectID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		r.logger.Error("Failed to get pending ownership transfer", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return nil, fmt.Errorf("failed to get pending ownership transfer: %w", err)
+	}
+
+	return &transfer, nil
+}
+
+// ResolveOwnershipTransfer переводит ожидающий запрос в итоговый статус
+func (r *ProjectRepository) ResolveOwnershipTransfer(ctx context.Context, id string, status domain.OwnershipTransferStatus, resolvedBy *string) (bool, error) {
+	query := `
+		UPDATE ownership_transfers
+		SET status = $1, resolved_at = NOW(), resolved_by = $2
+		WHERE id = $3 AND status = 'pending'
+	`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, status, resolvedBy, id)
+	if err != nil {
+		r.logger.Error("Failed to resolve ownership transfer", err, map[string]interface{}{
+			"id":     id,
+			"status": status,
+		})
+		return false, fmt.Errorf("failed to resolve ownership transfer: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// ExpireOwnershipTransfers помечает просроченные запросы на передачу владения и возвращает их
+func (r *ProjectRepository) ExpireOwnershipTransfers(ctx context.Context, now time.Time) ([]*domain.OwnershipTransfer, error) {
+	query := `
+		UPDATE ownership_transfers
+		SET status = 'expired', resolved_at = $1
+		WHERE status = 'pending' AND expires_at <= $1
+		RETURNING id, project_id, from_user_id, to_user_id, status, created_at, expires_at, resolved_at, resolved_by
+	`
+
+	transfers := []*domain.OwnershipTransfer{}
+	err := conn(ctx, r.db).SelectContext(ctx, &transfers, query, now)
+	if err != nil {
+		r.logger.Error("Failed to expire ownership transfers", err)
+		return nil, fmt.Errorf("failed to expire ownership transfers: %w", err)
+	}
+
+	return transfers, nil
+}
+
 // Вспомогательные функции
 
 func (r *ProjectRepository) buildWhereClause(filter repository.ProjectFilter) (string, []interface{}) {
@@ -477,4 +700,284 @@ func (r *ProjectRepository) buildOrderClause(filter repository.ProjectFilter) st
 
 	// По умолчанию сортируем по дате создания
 	return "ORDER BY created_at DESC"
-}
\ No newline at end of file
+}
+
+// GetTaskSettings возвращает настройки задач проекта
+func (r *ProjectRepository) GetTaskSettings(ctx context.Context, projectID string) (*domain.ProjectTaskSettings, error) {
+	query := `
+		SELECT
+			project_id, default_assignee_id, default_priority, default_due_offset_days,
+			required_fields, transition_rules, auto_label_stale, assignee_open_task_limit, updated_by, updated_at
+		FROM project_task_settings
+		WHERE project_id = $1
+	`
+
+	var settings domain.ProjectTaskSettings
+	var requiredFields pq.StringArray
+	var transitionRules []byte
+	err := conn(ctx, r.db).QueryRowxContext(ctx, query, projectID).Scan(
+		&settings.ProjectID,
+		&settings.DefaultAssigneeID,
+		&settings.DefaultPriority,
+		&settings.DefaultDueOffsetDays,
+		&requiredFields,
+		&transitionRules,
+		&settings.AutoLabelStale,
+		&settings.AssigneeOpenTaskLimit,
+		&settings.UpdatedBy,
+		&settings.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		r.logger.Error("Failed to get project task settings", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return nil, fmt.Errorf("failed to get project task settings: %w", err)
+	}
+
+	settings.RequiredFields = make([]domain.TaskField, 0, len(requiredFields))
+	for _, field := range requiredFields {
+		settings.RequiredFields = append(settings.RequiredFields, domain.TaskField(field))
+	}
+
+	settings.TransitionRules = make(map[domain.TaskStatus][]domain.TaskField)
+	if err := json.Unmarshal(transitionRules, &settings.TransitionRules); err != nil {
+		r.logger.Error("Failed to decode task transition rules", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return nil, fmt.Errorf("failed to decode task transition rules: %w", err)
+	}
+
+	return &settings, nil
+}
+
+// SaveTaskSettings создает или обновляет настройки задач проекта
+func (r *ProjectRepository) SaveTaskSettings(ctx context.Context, settings *domain.ProjectTaskSettings) error {
+	query := `
+		INSERT INTO project_task_settings (
+			project_id, default_assignee_id, default_priority, default_due_offset_days,
+			required_fields, transition_rules, auto_label_stale, assignee_open_task_limit, updated_by, updated_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+		)
+		ON CONFLICT (project_id) DO UPDATE SET
+			default_assignee_id = EXCLUDED.default_assignee_id,
+			default_priority = EXCLUDED.default_priority,
+			default_due_offset_days = EXCLUDED.default_due_offset_days,
+			required_fields = EXCLUDED.required_fields,
+			transition_rules = EXCLUDED.transition_rules,
+			auto_label_stale = EXCLUDED.auto_label_stale,
+			assignee_open_task_limit = EXCLUDED.assignee_open_task_limit,
+			updated_by = EXCLUDED.updated_by,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	requiredFields := make(pq.StringArray, 0, len(settings.RequiredFields))
+	for _, field := range settings.RequiredFields {
+		requiredFields = append(requiredFields, string(field))
+	}
+
+	transitionRules := settings.TransitionRules
+	if transitionRules == nil {
+		transitionRules = map[domain.TaskStatus][]domain.TaskField{}
+	}
+	transitionRulesJSON, err := json.Marshal(transitionRules)
+	if err != nil {
+		return fmt.Errorf("failed to encode task transition rules: %w", err)
+	}
+
+	_, err = conn(ctx, r.db).ExecContext(
+		ctx,
+		query,
+		settings.ProjectID,
+		settings.DefaultAssigneeID,
+		settings.DefaultPriority,
+		settings.DefaultDueOffsetDays,
+		requiredFields,
+		transitionRulesJSON,
+		settings.AutoLabelStale,
+		settings.AssigneeOpenTaskLimit,
+		settings.UpdatedBy,
+		settings.UpdatedAt,
+	)
+
+	if err != nil {
+		r.logger.Error("Failed to save project task settings", err, map[string]interface{}{
+			"project_id": settings.ProjectID,
+		})
+		return fmt.Errorf("failed to save project task settings: %w", err)
+	}
+
+	return nil
+}
+
+// Split создает новый проект и переносит в него задачи исходного проекта
+// в одной транзакции
+func (r *ProjectRepository) Split(ctx context.Context, split *repository.ProjectSplit) error {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				r.logger.Error("Failed to rollback transaction", rbErr)
+			}
+			return
+		}
+	}()
+
+	project := split.Project
+	if _, err = tx.ExecContext(ctx, `
+		INSERT INTO projects (
+			id, name, description, status, created_by, start_date, end_date, created_at, updated_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9
+		)
+	`,
+		project.ID,
+		project.Name,
+		project.Description,
+		project.Status,
+		project.CreatedBy,
+		project.StartDate,
+		project.EndDate,
+		project.CreatedAt,
+		project.UpdatedAt,
+	); err != nil {
+		r.logger.Error("Failed to create split project", err, map[string]interface{}{
+			"source_project_id": split.SourceProjectID,
+		})
+		return fmt.Errorf("failed to create split project: %w", err)
+	}
+
+	// Участники получают в новом проекте те же роли
+	if _, err = tx.ExecContext(ctx, `
+		INSERT INTO project_members (project_id, user_id, role, joined_at, invited_by)
+		SELECT $1, user_id, role, $2, $3
+		FROM project_members
+		WHERE project_id = $4
+	`, project.ID, project.CreatedAt, split.UserID, split.SourceProjectID); err != nil {
+		r.logger.Error("Failed to copy project members", err, map[string]interface{}{
+			"project_id": project.ID,
+		})
+		return fmt.Errorf("failed to copy project members: %w", err)
+	}
+
+	if _, err = tx.ExecContext(ctx, `
+		INSERT INTO project_task_settings (
+			project_id, default_assignee_id, default_priority, default_due_offset_days,
+			required_fields, transition_rules, auto_label_stale, assignee_open_task_limit, updated_by, updated_at
+		)
+		SELECT
+			$1, default_assignee_id, default_priority, default_due_offset_days,
+			required_fields, transition_rules, auto_label_stale, assignee_open_task_limit, $2, $3
+		FROM project_task_settings
+		WHERE project_id = $4
+	`, project.ID, split.UserID, project.CreatedAt, split.SourceProjectID); err != nil {
+		r.logger.Error("Failed to copy project task settings", err, map[string]interface{}{
+			"project_id": project.ID,
+		})
+		return fmt.Errorf("failed to copy project task settings: %w", err)
+	}
+
+	if split.EpicID != nil {
+		if _, err = tx.ExecContext(
+			ctx,
+			"UPDATE epics SET project_id = $1, updated_at = $2 WHERE id = $3 AND project_id = $4",
+			project.ID,
+			project.CreatedAt,
+			*split.EpicID,
+			split.SourceProjectID,
+		); err != nil {
+			r.logger.Error("Failed to move epic", err, map[string]interface{}{
+				"epic_id": *split.EpicID,
+			})
+			return fmt.Errorf("failed to move epic: %w", err)
+		}
+	}
+
+	// Задачи остаются в эпике, только если он переносится вместе с ними, и под родительской
+	// задачей, только если она тоже переносится; спринты остаются в исходном проекте
+	result, err := tx.ExecContext(ctx, `
+		UPDATE tasks
+		SET
+			project_id = $1,
+			epic_id = CASE WHEN epic_id IS NOT DISTINCT FROM $2::uuid THEN epic_id END,
+			sprint_id = NULL,
+			parent_id = CASE WHEN parent_id = ANY($4::uuid[]) THEN parent_id END,
+			updated_at = $3
+		WHERE id = ANY($4::uuid[]) AND project_id = $5
+	`, project.ID, split.EpicID, project.CreatedAt, pq.Array(split.TaskIDs), split.SourceProjectID)
+	if err != nil {
+		r.logger.Error("Failed to move tasks to split project", err, map[string]interface{}{
+			"project_id": project.ID,
+		})
+		return fmt.Errorf("failed to move tasks to split project: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if int(rowsAffected) != len(split.TaskIDs) {
+		err = fmt.Errorf("tasks changed during project split")
+		return err
+	}
+
+	// Задачи эпика, добавленные в исходном проекте после отбора, выходят из эпика
+	if split.EpicID != nil {
+		if _, err = tx.ExecContext(
+			ctx,
+			"UPDATE tasks SET epic_id = NULL WHERE epic_id = $1 AND project_id = $2",
+			*split.EpicID,
+			split.SourceProjectID,
+		); err != nil {
+			return fmt.Errorf("failed to detach remaining epic tasks: %w", err)
+		}
+	}
+
+	// Подзадачи перенесенных задач, оставшиеся в исходном проекте, становятся самостоятельными задачами
+	if _, err = tx.ExecContext(
+		ctx,
+		"UPDATE tasks SET parent_id = NULL WHERE parent_id = ANY($1::uuid[]) AND project_id = $2",
+		pq.Array(split.TaskIDs),
+		split.SourceProjectID,
+	); err != nil {
+		r.logger.Error("Failed to detach remaining subtasks", err, map[string]interface{}{
+			"project_id": split.SourceProjectID,
+		})
+		return fmt.Errorf("failed to detach remaining subtasks: %w", err)
+	}
+
+	// Решения, страницы вики и заметки встреч остаются в исходном проекте
+	for _, table := range []string{"decision_tasks", "wiki_page_tasks", "meeting_note_tasks"} {
+		if _, err = tx.ExecContext(ctx, "DELETE FROM "+table+" WHERE task_id = ANY($1::uuid[])", pq.Array(split.TaskIDs)); err != nil {
+			r.logger.Error("Failed to unlink moved tasks", err, map[string]interface{}{
+				"project_id": project.ID,
+				"table":      table,
+			})
+			return fmt.Errorf("failed to unlink moved tasks: %w", err)
+		}
+	}
+
+	if _, err = tx.ExecContext(ctx, `
+		INSERT INTO task_history (task_id, user_id, field, old_value, new_value, changed_at)
+		SELECT task_id, $2, 'project_id', $3, $4, $5
+		FROM unnest($1::uuid[]) AS task_id
+	`, pq.Array(split.TaskIDs), split.UserID, split.SourceProjectID, project.ID, project.CreatedAt); err != nil {
+		r.logger.Error("Failed to log split task history", err, map[string]interface{}{
+			"project_id": project.ID,
+		})
+		return fmt.Errorf("failed to log split task history: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,278 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/pkg/logger"
+)
+
+// projectViewColumns перечисляет колонки представления для выборок
+const projectViewColumns = `
+	id, project_id, name, layout, filters, sort_by, sort_dir, group_by,
+	default_roles, created_by, created_at, updated_at
+`
+
+// projectViewRow представляет строку таблицы project_views
+type projectViewRow struct {
+	domain.ProjectView
+	FiltersJSON  []byte         `db:"filters"`
+	DefaultRoles pq.StringArray `db:"default_roles"`
+}
+
+func (row *projectViewRow) toDomain() (*domain.ProjectView, error) {
+	view := row.ProjectView
+	if err := json.Unmarshal(row.FiltersJSON, &view.Filters); err != nil {
+		return nil, fmt.Errorf("failed to decode project view filters: %w", err)
+	}
+
+	view.DefaultRoles = make([]domain.ProjectRole, 0, len(row.DefaultRoles))
+	for _, role := range row.DefaultRoles {
+		view.DefaultRoles = append(view.DefaultRoles, domain.ProjectRole(role))
+	}
+	return &view, nil
+}
+
+// ProjectViewRepository реализует репозиторий представлений проектов с использованием PostgreSQL
+type ProjectViewRepository struct {
+	db     *sqlx.DB
+	logger logger.Logger
+}
+
+// NewProjectViewRepository создает новый экземпляр ProjectViewRepository
+func NewProjectViewRepository(db *sqlx.DB, logger logger.Logger) *ProjectViewRepository {
+	return &ProjectViewRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Create создает представление. Роли по умолчанию снимаются с других представлений проекта
+func (r *ProjectViewRepository) Create(ctx context.Context, view *domain.ProjectView) error {
+	query := `
+		INSERT INTO project_views (
+			id, project_id, name, layout, filters, sort_by, sort_dir, group_by,
+			default_roles, created_by, created_at, updated_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9::project_role[], $10, $11, $12
+		)
+	`
+
+	filters, err := json.Marshal(view.Filters)
+	if err != nil {
+		return fmt.Errorf("failed to encode project view filters: %w", err)
+	}
+
+	return r.withDefaultRoles(ctx, view, func(tx queryer, roles pq.StringArray) error {
+		_, err := tx.ExecContext(
+			ctx,
+			query,
+			view.ID,
+			view.ProjectID,
+			view.Name,
+			view.Layout,
+			filters,
+			view.SortBy,
+			view.SortDir,
+			view.GroupBy,
+			roles,
+			view.CreatedBy,
+			view.CreatedAt,
+			view.UpdatedAt,
+		)
+		if err != nil {
+			r.logger.Error("Failed to create project view", err, map[string]interface{}{
+				"project_id": view.ProjectID,
+				"name":       view.Name,
+			})
+			return fmt.Errorf("failed to create project view: %w", err)
+		}
+		return nil
+	})
+}
+
+// GetByID возвращает представление по ID (nil, если представление не найдено)
+func (r *ProjectViewRepository) GetByID(ctx context.Context, id string) (*domain.ProjectView, error) {
+	query := `SELECT ` + projectViewColumns + ` FROM project_views WHERE id = $1`
+
+	var row projectViewRow
+	if err := conn(ctx, r.db).GetContext(ctx, &row, query, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		r.logger.Error("Failed to get project view", err, map[string]interface{}{
+			"id": id,
+		})
+		return nil, fmt.Errorf("failed to get project view: %w", err)
+	}
+
+	return row.toDomain()
+}
+
+// GetByName возвращает представление проекта по имени (nil, если представление не найдено)
+func (r *ProjectViewRepository) GetByName(ctx context.Context, projectID, name string) (*domain.ProjectView, error) {
+	query := `SELECT ` + projectViewColumns + ` FROM project_views WHERE project_id = $1 AND name = $2`
+
+	var row projectViewRow
+	if err := conn(ctx, r.db).GetContext(ctx, &row, query, projectID, name); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		r.logger.Error("Failed to get project view by name", err, map[string]interface{}{
+			"project_id": projectID,
+			"name":       name,
+		})
+		return nil, fmt.Errorf("failed to get project view by name: %w", err)
+	}
+
+	return row.toDomain()
+}
+
+// GetDefaultForRole возвращает представление проекта по умолчанию для роли (nil, если не назначено)
+func (r *ProjectViewRepository) GetDefaultForRole(ctx context.Context, projectID string, role domain.ProjectRole) (*domain.ProjectView, error) {
+	query := `
+		SELECT ` + projectViewColumns + `
+		FROM project_views
+		WHERE project_id = $1 AND $2::project_role = ANY(default_roles)
+		LIMIT 1
+	`
+
+	var row projectViewRow
+	if err := conn(ctx, r.db).GetContext(ctx, &row, query, projectID, role); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		r.logger.Error("Failed to get default project view", err, map[string]interface{}{
+			"project_id": projectID,
+			"role":       role,
+		})
+		return nil, fmt.Errorf("failed to get default project view: %w", err)
+	}
+
+	return row.toDomain()
+}
+
+// ListByProject возвращает представления проекта, отсортированные по имени
+func (r *ProjectViewRepository) ListByProject(ctx context.Context, projectID string) ([]*domain.ProjectView, error) {
+	query := `SELECT ` + projectViewColumns + ` FROM project_views WHERE project_id = $1 ORDER BY name`
+
+	var rows []projectViewRow
+	if err := conn(ctx, r.db).SelectContext(ctx, &rows, query, projectID); err != nil {
+		r.logger.Error("Failed to list project views", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return nil, fmt.Errorf("failed to list project views: %w", err)
+	}
+
+	views := make([]*domain.ProjectView, 0, len(rows))
+	for i := range rows {
+		view, err := rows[i].toDomain()
+		if err != nil {
+			return nil, err
+		}
+		views = append(views, view)
+	}
+
+	return views, nil
+}
+
+// Update обновляет представление. Роли по умолчанию снимаются с других представлений проекта
+func (r *ProjectViewRepository) Update(ctx context.Context, view *domain.ProjectView) error {
+	query := `
+		UPDATE project_views
+		SET name = $1, layout = $2, filters = $3, sort_by = $4, sort_dir = $5,
+			group_by = $6, default_roles = $7::project_role[], updated_at = $8
+		WHERE id = $9
+	`
+
+	filters, err := json.Marshal(view.Filters)
+	if err != nil {
+		return fmt.Errorf("failed to encode project view filters: %w", err)
+	}
+
+	return r.withDefaultRoles(ctx, view, func(tx queryer, roles pq.StringArray) error {
+		_, err := tx.ExecContext(
+			ctx,
+			query,
+			view.Name,
+			view.Layout,
+			filters,
+			view.SortBy,
+			view.SortDir,
+			view.GroupBy,
+			roles,
+			view.UpdatedAt,
+			view.ID,
+		)
+		if err != nil {
+			r.logger.Error("Failed to update project view", err, map[string]interface{}{
+				"id": view.ID,
+			})
+			return fmt.Errorf("failed to update project view: %w", err)
+		}
+		return nil
+	})
+}
+
+// Delete удаляет представление по ID
+func (r *ProjectViewRepository) Delete(ctx context.Context, id string) error {
+	if _, err := conn(ctx, r.db).ExecContext(ctx, `DELETE FROM project_views WHERE id = $1`, id); err != nil {
+		r.logger.Error("Failed to delete project view", err, map[string]interface{}{
+			"id": id,
+		})
+		return fmt.Errorf("failed to delete project view: %w", err)
+	}
+
+	return nil
+}
+
+// withDefaultRoles сохраняет представление в транзакции, предварительно сняв его роли
+// по умолчанию с остальных представлений проекта: у каждой роли не больше одного представления
+func (r *ProjectViewRepository) withDefaultRoles(ctx context.Context, view *domain.ProjectView, save func(tx queryer, roles pq.StringArray) error) error {
+	roles := make(pq.StringArray, 0, len(view.DefaultRoles))
+	for _, role := range view.DefaultRoles {
+		roles = append(roles, string(role))
+	}
+
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				r.logger.Error("Failed to rollback transaction", rbErr)
+			}
+		}
+	}()
+
+	if len(roles) > 0 {
+		if _, err = tx.ExecContext(ctx, `
+			UPDATE project_views
+			SET default_roles = ARRAY(
+				SELECT role FROM unnest(default_roles) AS role WHERE role <> ALL($3::project_role[])
+			)
+			WHERE project_id = $1 AND id <> $2 AND default_roles && $3::project_role[]
+		`, view.ProjectID, view.ID, roles); err != nil {
+			r.logger.Error("Failed to reassign default project views", err, map[string]interface{}{
+				"project_id": view.ProjectID,
+			})
+			return fmt.Errorf("failed to reassign default project views: %w", err)
+		}
+	}
+
+	if err = save(tx, roles); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
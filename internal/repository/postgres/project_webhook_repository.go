@@ -0,0 +1,187 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/pkg/logger"
+)
+
+// projectWebhookColumns перечисляет колонки вебхука для выборок
+const projectWebhookColumns = `
+	id, project_id, name, secret, mapping, enabled, created_by, created_at, updated_at
+`
+
+// projectWebhookRow представляет строку таблицы project_webhooks
+type projectWebhookRow struct {
+	domain.ProjectWebhook
+	MappingJSON []byte `db:"mapping"`
+}
+
+func (row *projectWebhookRow) toDomain() (*domain.ProjectWebhook, error) {
+	webhook := row.ProjectWebhook
+	if err := json.Unmarshal(row.MappingJSON, &webhook.Mapping); err != nil {
+		return nil, fmt.Errorf("failed to decode project webhook mapping: %w", err)
+	}
+	return &webhook, nil
+}
+
+// ProjectWebhookRepository реализует репозиторий входящих вебхуков проектов с использованием PostgreSQL
+type ProjectWebhookRepository struct {
+	db     *sqlx.DB
+	logger logger.Logger
+}
+
+// NewProjectWebhookRepository создает новый экземпляр ProjectWebhookRepository
+func NewProjectWebhookRepository(db *sqlx.DB, logger logger.Logger) *ProjectWebhookRepository {
+	return &ProjectWebhookRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Create создает вебхук
+func (r *ProjectWebhookRepository) Create(ctx context.Context, webhook *domain.ProjectWebhook) error {
+	query := `
+		INSERT INTO project_webhooks (
+			id, project_id, name, secret, mapping, enabled, created_by, created_at, updated_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9
+		)
+	`
+
+	mapping, err := json.Marshal(webhook.Mapping)
+	if err != nil {
+		return fmt.Errorf("failed to encode project webhook mapping: %w", err)
+	}
+
+	_, err = conn(ctx, r.db).ExecContext(
+		ctx,
+		query,
+		webhook.ID,
+		webhook.ProjectID,
+		webhook.Name,
+		webhook.Secret,
+		mapping,
+		webhook.Enabled,
+		webhook.CreatedBy,
+		webhook.CreatedAt,
+		webhook.UpdatedAt,
+	)
+	if err != nil {
+		r.logger.Error("Failed to create project webhook", err, map[string]interface{}{
+			"project_id": webhook.ProjectID,
+		})
+		return fmt.Errorf("failed to create project webhook: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID возвращает вебхук по ID
+func (r *ProjectWebhookRepository) GetByID(ctx context.Context, id string) (*domain.ProjectWebhook, error) {
+	query := `SELECT ` + projectWebhookColumns + ` FROM project_webhooks WHERE id = $1`
+
+	var row projectWebhookRow
+	if err := conn(ctx, r.db).GetContext(ctx, &row, query, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		r.logger.Error("Failed to get project webhook", err, map[string]interface{}{
+			"id": id,
+		})
+		return nil, fmt.Errorf("failed to get project webhook: %w", err)
+	}
+
+	return row.toDomain()
+}
+
+// GetByName возвращает вебхук проекта по имени
+func (r *ProjectWebhookRepository) GetByName(ctx context.Context, projectID, name string) (*domain.ProjectWebhook, error) {
+	query := `SELECT ` + projectWebhookColumns + ` FROM project_webhooks WHERE project_id = $1 AND name = $2`
+
+	var row projectWebhookRow
+	if err := conn(ctx, r.db).GetContext(ctx, &row, query, projectID, name); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		r.logger.Error("Failed to get project webhook by name", err, map[string]interface{}{
+			"project_id": projectID,
+			"name":       name,
+		})
+		return nil, fmt.Errorf("failed to get project webhook by name: %w", err)
+	}
+
+	return row.toDomain()
+}
+
+// ListByProject возвращает вебхуки проекта в порядке создания
+func (r *ProjectWebhookRepository) ListByProject(ctx context.Context, projectID string) ([]*domain.ProjectWebhook, error) {
+	query := `
+		SELECT ` + projectWebhookColumns + `
+		FROM project_webhooks
+		WHERE project_id = $1
+		ORDER BY created_at
+	`
+
+	var rows []projectWebhookRow
+	if err := conn(ctx, r.db).SelectContext(ctx, &rows, query, projectID); err != nil {
+		r.logger.Error("Failed to list project webhooks", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return nil, fmt.Errorf("failed to list project webhooks: %w", err)
+	}
+
+	webhooks := make([]*domain.ProjectWebhook, 0, len(rows))
+	for i := range rows {
+		webhook, err := rows[i].toDomain()
+		if err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, webhook)
+	}
+
+	return webhooks, nil
+}
+
+// Update обновляет вебхук
+func (r *ProjectWebhookRepository) Update(ctx context.Context, webhook *domain.ProjectWebhook) error {
+	query := `
+		UPDATE project_webhooks
+		SET name = $1, secret = $2, mapping = $3, enabled = $4, updated_at = $5
+		WHERE id = $6
+	`
+
+	mapping, err := json.Marshal(webhook.Mapping)
+	if err != nil {
+		return fmt.Errorf("failed to encode project webhook mapping: %w", err)
+	}
+
+	_, err = conn(ctx, r.db).ExecContext(ctx, query, webhook.Name, webhook.Secret, mapping, webhook.Enabled, webhook.UpdatedAt, webhook.ID)
+	if err != nil {
+		r.logger.Error("Failed to update project webhook", err, map[string]interface{}{
+			"id": webhook.ID,
+		})
+		return fmt.Errorf("failed to update project webhook: %w", err)
+	}
+
+	return nil
+}
+
+// Delete удаляет вебхук
+func (r *ProjectWebhookRepository) Delete(ctx context.Context, id string) error {
+	query := `DELETE FROM project_webhooks WHERE id = $1`
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query, id); err != nil {
+		r.logger.Error("Failed to delete project webhook", err, map[string]interface{}{
+			"id": id,
+		})
+		return fmt.Errorf("failed to delete project webhook: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,144 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/pkg/logger"
+)
+
+// RoadmapRepository реализует репозиторий публичных дорожных карт с использованием PostgreSQL
+type RoadmapRepository struct {
+	db     *sqlx.DB
+	logger logger.Logger
+}
+
+// NewRoadmapRepository создает новый экземпляр RoadmapRepository
+func NewRoadmapRepository(db *sqlx.DB, logger logger.Logger) *RoadmapRepository {
+	return &RoadmapRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// GetRoadmap возвращает дорожную карту проекта
+func (r *RoadmapRepository) GetRoadmap(ctx context.Context, projectID string) (*domain.ProjectRoadmap, error) {
+	query := `
+		SELECT project_id, public_key, title, description, label, enabled, created_by, created_at, updated_at
+		FROM project_roadmaps
+		WHERE project_id = $1
+	`
+
+	var roadmap domain.ProjectRoadmap
+	if err := conn(ctx, r.db).GetContext(ctx, &roadmap, query, projectID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		r.logger.Error("Failed to get project roadmap", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return nil, fmt.Errorf("failed to get project roadmap: %w", err)
+	}
+
+	return &roadmap, nil
+}
+
+// GetRoadmapByKey возвращает дорожную карту по публичному ключу
+func (r *RoadmapRepository) GetRoadmapByKey(ctx context.Context, publicKey string) (*domain.ProjectRoadmap, error) {
+	query := `
+		SELECT project_id, public_key, title, description, label, enabled, created_by, created_at, updated_at
+		FROM project_roadmaps
+		WHERE public_key = $1
+	`
+
+	var roadmap domain.ProjectRoadmap
+	if err := conn(ctx, r.db).GetContext(ctx, &roadmap, query, publicKey); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		r.logger.Error("Failed to get project roadmap by key", err)
+		return nil, fmt.Errorf("failed to get project roadmap: %w", err)
+	}
+
+	return &roadmap, nil
+}
+
+// SaveRoadmap создает или обновляет дорожную карту проекта
+func (r *RoadmapRepository) SaveRoadmap(ctx context.Context, roadmap *domain.ProjectRoadmap) error {
+	query := `
+		INSERT INTO project_roadmaps (
+			project_id, public_key, title, description, label, enabled, created_by, created_at, updated_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9
+		)
+		ON CONFLICT (project_id) DO UPDATE SET
+			public_key = EXCLUDED.public_key,
+			title = EXCLUDED.title,
+			description = EXCLUDED.description,
+			label = EXCLUDED.label,
+			enabled = EXCLUDED.enabled,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	_, err := conn(ctx, r.db).ExecContext(
+		ctx,
+		query,
+		roadmap.ProjectID,
+		roadmap.PublicKey,
+		roadmap.Title,
+		roadmap.Description,
+		roadmap.Label,
+		roadmap.Enabled,
+		roadmap.CreatedBy,
+		roadmap.CreatedAt,
+		roadmap.UpdatedAt,
+	)
+	if err != nil {
+		r.logger.Error("Failed to save project roadmap", err, map[string]interface{}{
+			"project_id": roadmap.ProjectID,
+		})
+		return fmt.Errorf("failed to save project roadmap: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteRoadmap удаляет дорожную карту проекта
+func (r *RoadmapRepository) DeleteRoadmap(ctx context.Context, projectID string) error {
+	query := `DELETE FROM project_roadmaps WHERE project_id = $1`
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query, projectID); err != nil {
+		r.logger.Error("Failed to delete project roadmap", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return fmt.Errorf("failed to delete project roadmap: %w", err)
+	}
+
+	return nil
+}
+
+// ListRoadmapTasks возвращает неотмененные задачи проекта с указанным тегом
+func (r *RoadmapRepository) ListRoadmapTasks(ctx context.Context, projectID string, label string, limit int) ([]*domain.RoadmapTask, error) {
+	query := `
+		SELECT t.title, t.status, t.due_date, t.completed_at
+		FROM tasks t
+		JOIN task_tags tt ON tt.task_id = t.id
+		WHERE t.project_id = $1 AND tt.tag = $2 AND t.status <> $3
+		ORDER BY t.due_date ASC NULLS LAST, t.title ASC
+		LIMIT $4
+	`
+
+	var tasks []*domain.RoadmapTask
+	if err := conn(ctx, r.db).SelectContext(ctx, &tasks, query, projectID, label, domain.TaskStatusCancelled, limit); err != nil {
+		r.logger.Error("Failed to list roadmap tasks", err, map[string]interface{}{
+			"project_id": projectID,
+			"label":      label,
+		})
+		return nil, fmt.Errorf("failed to list roadmap tasks: %w", err)
+	}
+
+	return tasks, nil
+}
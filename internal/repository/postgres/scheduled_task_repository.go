@@ -0,0 +1,225 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/pkg/logger"
+)
+
+// scheduledTaskColumns перечисляет колонки запланированной задачи для выборок
+const scheduledTaskColumns = `
+	id, project_id, payload, create_at, status, task_id, error,
+	created_by, created_at, updated_at, processed_at
+`
+
+// scheduledTaskRow представляет строку таблицы scheduled_tasks
+type scheduledTaskRow struct {
+	domain.ScheduledTask
+	Payload []byte `db:"payload"`
+}
+
+func (row *scheduledTaskRow) toDomain() (*domain.ScheduledTask, error) {
+	scheduled := row.ScheduledTask
+	if err := json.Unmarshal(row.Payload, &scheduled.Task); err != nil {
+		return nil, fmt.Errorf("failed to decode scheduled task payload: %w", err)
+	}
+	return &scheduled, nil
+}
+
+// ScheduledTaskRepository реализует репозиторий отложенного создания задач с использованием PostgreSQL
+type ScheduledTaskRepository struct {
+	db     *sqlx.DB
+	logger logger.Logger
+}
+
+// NewScheduledTaskRepository создает новый экземпляр ScheduledTaskRepository
+func NewScheduledTaskRepository(db *sqlx.DB, logger logger.Logger) *ScheduledTaskRepository {
+	return &ScheduledTaskRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Create сохраняет запланированную задачу
+func (r *ScheduledTaskRepository) Create(ctx context.Context, scheduled *domain.ScheduledTask) error {
+	query := `
+		INSERT INTO scheduled_tasks (
+			id, project_id, payload, create_at, status, created_by, created_at, updated_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8
+		)
+	`
+
+	payload, err := json.Marshal(scheduled.Task)
+	if err != nil {
+		return fmt.Errorf("failed to encode scheduled task payload: %w", err)
+	}
+
+	_, err = conn(ctx, r.db).ExecContext(
+		ctx,
+		query,
+		scheduled.ID,
+		scheduled.ProjectID,
+		payload,
+		scheduled.CreateAt,
+		scheduled.Status,
+		scheduled.CreatedBy,
+		scheduled.CreatedAt,
+		scheduled.UpdatedAt,
+	)
+	if err != nil {
+		r.logger.Error("Failed to create scheduled task", err, map[string]interface{}{
+			"project_id": scheduled.ProjectID,
+		})
+		return fmt.Errorf("failed to create scheduled task: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID возвращает запланированную задачу по ID
+func (r *ScheduledTaskRepository) GetByID(ctx context.Context, id string) (*domain.ScheduledTask, error) {
+	query := `SELECT ` + scheduledTaskColumns + ` FROM scheduled_tasks WHERE id = $1`
+
+	var row scheduledTaskRow
+	if err := conn(ctx, r.db).GetContext(ctx, &row, query, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		r.logger.Error("Failed to get scheduled task", err, map[string]interface{}{
+			"id": id,
+		})
+		return nil, fmt.Errorf("failed to get scheduled task: %w", err)
+	}
+
+	return row.toDomain()
+}
+
+// ListByProject возвращает запланированные задачи проекта в порядке времени создания
+func (r *ScheduledTaskRepository) ListByProject(ctx context.Context, projectID string, status *domain.ScheduledTaskStatus) ([]*domain.ScheduledTask, error) {
+	query := `
+		SELECT ` + scheduledTaskColumns + `
+		FROM scheduled_tasks
+		WHERE project_id = $1 AND ($2::text IS NULL OR status = $2)
+		ORDER BY create_at, created_at
+	`
+
+	var rows []scheduledTaskRow
+	if err := conn(ctx, r.db).SelectContext(ctx, &rows, query, projectID, status); err != nil {
+		r.logger.Error("Failed to list scheduled tasks", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return nil, fmt.Errorf("failed to list scheduled tasks: %w", err)
+	}
+
+	return scheduledTasksFromRows(rows)
+}
+
+// UpdatePending обновляет запланированную задачу, пока она ожидает создания
+func (r *ScheduledTaskRepository) UpdatePending(ctx context.Context, scheduled *domain.ScheduledTask) (bool, error) {
+	query := `
+		UPDATE scheduled_tasks
+		SET payload = $1, create_at = $2, status = $3, updated_at = $4
+		WHERE id = $5 AND status = 'pending'
+	`
+
+	payload, err := json.Marshal(scheduled.Task)
+	if err != nil {
+		return false, fmt.Errorf("failed to encode scheduled task payload: %w", err)
+	}
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, payload, scheduled.CreateAt, scheduled.Status, scheduled.UpdatedAt, scheduled.ID)
+	if err != nil {
+		r.logger.Error("Failed to update scheduled task", err, map[string]interface{}{
+			"id": scheduled.ID,
+		})
+		return false, fmt.Errorf("failed to update scheduled task: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// ClaimDue переводит в обработку ожидающие задачи, время создания которых наступило.
+// Строки, захваченные другим экземпляром планировщика, пропускаются
+func (r *ScheduledTaskRepository) ClaimDue(ctx context.Context, now time.Time, limit int) ([]*domain.ScheduledTask, error) {
+	query := `
+		UPDATE scheduled_tasks
+		SET status = 'processing', updated_at = $1
+		WHERE id IN (
+			SELECT id FROM scheduled_tasks
+			WHERE status = 'pending' AND create_at <= $1
+			ORDER BY create_at
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + scheduledTaskColumns
+
+	var rows []scheduledTaskRow
+	if err := conn(ctx, r.db).SelectContext(ctx, &rows, query, now, limit); err != nil {
+		r.logger.Error("Failed to claim due scheduled tasks", err)
+		return nil, fmt.Errorf("failed to claim due scheduled tasks: %w", err)
+	}
+
+	return scheduledTasksFromRows(rows)
+}
+
+// Release возвращает задачу из обработки в ожидание
+func (r *ScheduledTaskRepository) Release(ctx context.Context, id string, errMsg string) error {
+	query := `
+		UPDATE scheduled_tasks
+		SET status = 'pending', error = $1, updated_at = NOW()
+		WHERE id = $2 AND status = 'processing'
+	`
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query, errMsg, id); err != nil {
+		r.logger.Error("Failed to release scheduled task", err, map[string]interface{}{
+			"id": id,
+		})
+		return fmt.Errorf("failed to release scheduled task: %w", err)
+	}
+
+	return nil
+}
+
+// Complete фиксирует результат обработки запланированной задачи
+func (r *ScheduledTaskRepository) Complete(ctx context.Context, id string, status domain.ScheduledTaskStatus, taskID *string, errMsg *string, processedAt time.Time) error {
+	query := `
+		UPDATE scheduled_tasks
+		SET status = $1, task_id = $2, error = $3, processed_at = $4, updated_at = $4
+		WHERE id = $5
+	`
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query, status, taskID, errMsg, processedAt, id); err != nil {
+		r.logger.Error("Failed to complete scheduled task", err, map[string]interface{}{
+			"id":     id,
+			"status": status,
+		})
+		return fmt.Errorf("failed to complete scheduled task: %w", err)
+	}
+
+	return nil
+}
+
+// scheduledTasksFromRows преобразует строки таблицы в доменные модели
+func scheduledTasksFromRows(rows []scheduledTaskRow) ([]*domain.ScheduledTask, error) {
+	scheduled := make([]*domain.ScheduledTask, 0, len(rows))
+	for i := range rows {
+		item, err := rows[i].toDomain()
+		if err != nil {
+			return nil, err
+		}
+		scheduled = append(scheduled, item)
+	}
+	return scheduled, nil
+}
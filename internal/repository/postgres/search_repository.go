@@ -0,0 +1,170 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/pkg/logger"
+)
+
+// SearchRepository реализует быстрый поиск с использованием триграммных индексов PostgreSQL
+type SearchRepository struct {
+	db     *sqlx.DB
+	logger logger.Logger
+}
+
+// NewSearchRepository создает новый экземпляр SearchRepository
+func NewSearchRepository(db *sqlx.DB, logger logger.Logger) *SearchRepository {
+	return &SearchRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// SearchTasks возвращает задачи, доступные пользователю, название которых совпадает с запросом.
+// Совпадения по префиксу идут первыми, остальные сортируются по триграммному сходству
+func (r *SearchRepository) SearchTasks(ctx context.Context, query string, userID string, allProjects bool, limit int) ([]*domain.TypeaheadItem, error) {
+	sqlQuery := `
+		SELECT t.id, t.title, p.name AS subtitle
+		FROM tasks t
+		JOIN projects p ON p.id = t.project_id
+		WHERE t.title ILIKE '%' || $1 || '%'
+		AND ($3 OR EXISTS (
+			SELECT 1 FROM project_members pm
+			WHERE pm.project_id = t.project_id AND pm.user_id = $2
+		))
+		ORDER BY (t.title ILIKE $1 || '%') DESC, similarity(t.title, $1) DESC, t.updated_at DESC
+		LIMIT $4
+	`
+
+	var items []*domain.TypeaheadItem
+	if err := conn(ctx, r.db).SelectContext(ctx, &items, sqlQuery, query, userID, allProjects, limit); err != nil {
+		r.logger.Error("Failed to search tasks", err, map[string]interface{}{
+			"query":   query,
+			"user_id": userID,
+		})
+		return nil, fmt.Errorf("failed to search tasks: %w", err)
+	}
+
+	return items, nil
+}
+
+// SearchProjects возвращает проекты, доступные пользователю, название которых совпадает с запросом
+func (r *SearchRepository) SearchProjects(ctx context.Context, query string, userID string, allProjects bool, limit int) ([]*domain.TypeaheadItem, error) {
+	sqlQuery := `
+		SELECT p.id, p.name AS title, p.status::text AS subtitle
+		FROM projects p
+		WHERE p.name ILIKE '%' || $1 || '%'
+		AND ($3 OR EXISTS (
+			SELECT 1 FROM project_members pm
+			WHERE pm.project_id = p.id AND pm.user_id = $2
+		))
+		ORDER BY (p.name ILIKE $1 || '%') DESC, similarity(p.name, $1) DESC, p.updated_at DESC
+		LIMIT $4
+	`
+
+	var items []*domain.TypeaheadItem
+	if err := conn(ctx, r.db).SelectContext(ctx, &items, sqlQuery, query, userID, allProjects, limit); err != nil {
+		r.logger.Error("Failed to search projects", err, map[string]interface{}{
+			"query":   query,
+			"user_id": userID,
+		})
+		return nil, fmt.Errorf("failed to search projects: %w", err)
+	}
+
+	return items, nil
+}
+
+// SearchUsers возвращает активных пользователей из общих с пользователем проектов,
+// имя или email которых совпадает с запросом
+func (r *SearchRepository) SearchUsers(ctx context.Context, query string, userID string, allProjects bool, limit int) ([]*domain.TypeaheadItem, error) {
+	sqlQuery := `
+		SELECT u.id, u.first_name || ' ' || u.last_name AS title, u.email AS subtitle
+		FROM users u
+		WHERE u.is_active = TRUE
+		AND (
+			(u.first_name || ' ' || u.last_name) ILIKE '%' || $1 || '%'
+			OR u.email ILIKE '%' || $1 || '%'
+		)
+		AND ($3 OR EXISTS (
+			SELECT 1 FROM project_members pm
+			JOIN project_members own ON own.project_id = pm.project_id
+			WHERE pm.user_id = u.id AND own.user_id = $2
+		))
+		ORDER BY (u.first_name ILIKE $1 || '%' OR u.last_name ILIKE $1 || '%' OR u.email ILIKE $1 || '%') DESC,
+			similarity(u.first_name || ' ' || u.last_name, $1) DESC
+		LIMIT $4
+	`
+
+	var items []*domain.TypeaheadItem
+	if err := conn(ctx, r.db).SelectContext(ctx, &items, sqlQuery, query, userID, allProjects, limit); err != nil {
+		r.logger.Error("Failed to search users", err, map[string]interface{}{
+			"query":   query,
+			"user_id": userID,
+		})
+		return nil, fmt.Errorf("failed to search users: %w", err)
+	}
+
+	return items, nil
+}
+
+// SearchDecisions возвращает решения из журналов доступных пользователю проектов,
+// название или текст решения которых совпадает с запросом
+func (r *SearchRepository) SearchDecisions(ctx context.Context, query string, userID string, allProjects bool, limit int) ([]*domain.TypeaheadItem, error) {
+	sqlQuery := `
+		SELECT d.id, d.title, p.name AS subtitle
+		FROM decisions d
+		JOIN projects p ON p.id = d.project_id
+		WHERE (d.title ILIKE '%' || $1 || '%' OR d.decision ILIKE '%' || $1 || '%')
+		AND ($3 OR EXISTS (
+			SELECT 1 FROM project_members pm
+			WHERE pm.project_id = d.project_id AND pm.user_id = $2
+		))
+		ORDER BY (d.title ILIKE $1 || '%') DESC, similarity(d.title, $1) DESC, d.updated_at DESC
+		LIMIT $4
+	`
+
+	var items []*domain.TypeaheadItem
+	if err := conn(ctx, r.db).SelectContext(ctx, &items, sqlQuery, query, userID, allProjects, limit); err != nil {
+		r.logger.Error("Failed to search decisions", err, map[string]interface{}{
+			"query":   query,
+			"user_id": userID,
+		})
+		return nil, fmt.Errorf("failed to search decisions: %w", err)
+	}
+
+	return items, nil
+}
+
+// SearchWikiPages возвращает вики-страницы доступных пользователю проектов.
+// Страница находится по названию или полнотекстовым индексом по содержимому
+func (r *SearchRepository) SearchWikiPages(ctx context.Context, query string, userID string, allProjects bool, limit int) ([]*domain.TypeaheadItem, error) {
+	sqlQuery := `
+		SELECT w.id, w.title, p.name AS subtitle
+		FROM wiki_pages w
+		JOIN projects p ON p.id = w.project_id
+		WHERE (
+			w.title ILIKE '%' || $1 || '%'
+			OR to_tsvector('russian', w.title || ' ' || w.content) @@ plainto_tsquery('russian', $1)
+		)
+		AND ($3 OR EXISTS (
+			SELECT 1 FROM project_members pm
+			WHERE pm.project_id = w.project_id AND pm.user_id = $2
+		))
+		ORDER BY (w.title ILIKE $1 || '%') DESC, similarity(w.title, $1) DESC, w.updated_at DESC
+		LIMIT $4
+	`
+
+	var items []*domain.TypeaheadItem
+	if err := conn(ctx, r.db).SelectContext(ctx, &items, sqlQuery, query, userID, allProjects, limit); err != nil {
+		r.logger.Error("Failed to search wiki pages", err, map[string]interface{}{
+			"query":   query,
+			"user_id": userID,
+		})
+		return nil, fmt.Errorf("failed to search wiki pages: %w", err)
+	}
+
+	return items, nil
+}
@@ -0,0 +1,26 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// settingCurrentUserID - параметр транзакции с ID пользователя, от имени которого вносятся
+// изменения. Его читает функция app_current_user_id() в триггерах истории задач
+const settingCurrentUserID = "app.current_user_id"
+
+// setLocal задает параметр конфигурации до конца транзакции. SET LOCAL не принимает
+// параметры запроса, поэтому значение передается через set_config, а не подставляется в текст
+func setLocal(ctx context.Context, tx sqlx.ExecerContext, name, value string) error {
+	if _, err := tx.ExecContext(ctx, "SELECT set_config($1, $2, true)", name, value); err != nil {
+		return fmt.Errorf("failed to set %s: %w", name, err)
+	}
+	return nil
+}
+
+// setCurrentUser задает пользователя транзакции для триггеров истории изменений
+func setCurrentUser(ctx context.Context, tx sqlx.ExecerContext, userID string) error {
+	return setLocal(ctx, tx, settingCurrentUserID, userID)
+}
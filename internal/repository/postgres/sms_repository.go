@@ -0,0 +1,151 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/pkg/logger"
+)
+
+// SMSRepository реализует репозиторий SMS-настроек с использованием PostgreSQL
+type SMSRepository struct {
+	db     *sqlx.DB
+	logger logger.Logger
+}
+
+// NewSMSRepository создает новый экземпляр SMSRepository
+func NewSMSRepository(db *sqlx.DB, logger logger.Logger) *SMSRepository {
+	return &SMSRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// GetSettings возвращает SMS-настройки пользователя
+func (r *SMSRepository) GetSettings(ctx context.Context, userID string) (*domain.SMSSettings, error) {
+	query := `
+		SELECT user_id, phone, verified_at, enabled, COALESCE(code_hash, '') AS code_hash, code_expires_at,
+			code_attempts, created_at, updated_at
+		FROM user_sms_settings
+		WHERE user_id = $1
+	`
+
+	var settings domain.SMSSettings
+	if err := conn(ctx, r.db).GetContext(ctx, &settings, query, userID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		r.logger.Error("Failed to get SMS settings", err, map[string]interface{}{
+			"user_id": userID,
+		})
+		return nil, fmt.Errorf("failed to get SMS settings: %w", err)
+	}
+
+	return &settings, nil
+}
+
+// SaveSettings создает или обновляет SMS-настройки пользователя
+func (r *SMSRepository) SaveSettings(ctx context.Context, settings *domain.SMSSettings) error {
+	query := `
+		INSERT INTO user_sms_settings (
+			user_id, phone, verified_at, enabled, code_hash, code_expires_at, code_attempts, created_at, updated_at
+		) VALUES (
+			$1, $2, $3, $4, NULLIF($5, ''), $6, $7, $8, $9
+		)
+		ON CONFLICT (user_id) DO UPDATE SET
+			phone = EXCLUDED.phone,
+			verified_at = EXCLUDED.verified_at,
+			enabled = EXCLUDED.enabled,
+			code_hash = EXCLUDED.code_hash,
+			code_expires_at = EXCLUDED.code_expires_at,
+			code_attempts = EXCLUDED.code_attempts,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	_, err := conn(ctx, r.db).ExecContext(
+		ctx,
+		query,
+		settings.UserID,
+		settings.Phone,
+		settings.VerifiedAt,
+		settings.Enabled,
+		settings.CodeHash,
+		settings.CodeExpiresAt,
+		settings.CodeAttempts,
+		settings.CreatedAt,
+		settings.UpdatedAt,
+	)
+	if err != nil {
+		r.logger.Error("Failed to save SMS settings", err, map[string]interface{}{
+			"user_id": settings.UserID,
+		})
+		return fmt.Errorf("failed to save SMS settings: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteSettings удаляет SMS-настройки пользователя
+func (r *SMSRepository) DeleteSettings(ctx context.Context, userID string) error {
+	query := `DELETE FROM user_sms_settings WHERE user_id = $1`
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query, userID); err != nil {
+		r.logger.Error("Failed to delete SMS settings", err, map[string]interface{}{
+			"user_id": userID,
+		})
+		return fmt.Errorf("failed to delete SMS settings: %w", err)
+	}
+
+	return nil
+}
+
+// GetMonthlyUsage возвращает количество SMS, отправленных пользователю за месяц
+func (r *SMSRepository) GetMonthlyUsage(ctx context.Context, userID string, month time.Time) (int, error) {
+	query := `SELECT sent FROM sms_usage WHERE user_id = $1 AND month = $2`
+
+	var sent int
+	if err := conn(ctx, r.db).GetContext(ctx, &sent, query, userID, month); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, nil
+		}
+		r.logger.Error("Failed to get SMS usage", err, map[string]interface{}{
+			"user_id": userID,
+		})
+		return 0, fmt.Errorf("failed to get SMS usage: %w", err)
+	}
+
+	return sent, nil
+}
+
+// ReserveMonthlyUsage атомарно учитывает еще одно SMS за месяц, если квота не исчерпана
+func (r *SMSRepository) ReserveMonthlyUsage(ctx context.Context, userID string, month time.Time, quota int) (bool, error) {
+	if quota <= 0 {
+		return false, nil
+	}
+
+	// Строка обновляется только при sent < quota, поэтому параллельные отправки не превысят квоту
+	query := `
+		INSERT INTO sms_usage (user_id, month, sent)
+		VALUES ($1, $2, 1)
+		ON CONFLICT (user_id, month) DO UPDATE SET sent = sms_usage.sent + 1
+		WHERE sms_usage.sent < $3
+		RETURNING sent
+	`
+
+	var sent int
+	if err := conn(ctx, r.db).GetContext(ctx, &sent, query, userID, month, quota); err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		r.logger.Error("Failed to reserve SMS usage", err, map[string]interface{}{
+			"user_id": userID,
+		})
+		return false, fmt.Errorf("failed to reserve SMS usage: %w", err)
+	}
+
+	return true, nil
+}
@@ -0,0 +1,331 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/pkg/logger"
+)
+
+// SprintRepository реализует репозиторий спринтов с использованием PostgreSQL
+type SprintRepository struct {
+	db     *sqlx.DB
+	logger logger.Logger
+}
+
+// NewSprintRepository создает новый экземпляр SprintRepository
+func NewSprintRepository(db *sqlx.DB, logger logger.Logger) *SprintRepository {
+	return &SprintRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Create создает новый спринт
+func (r *SprintRepository) Create(ctx context.Context, sprint *domain.Sprint) error {
+	query := `
+		INSERT INTO sprints (
+			id, project_id, name, goal, status, start_date, end_date, created_by, created_at, updated_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+		)
+	`
+
+	_, err := conn(ctx, r.db).ExecContext(
+		ctx,
+		query,
+		sprint.ID,
+		sprint.ProjectID,
+		sprint.Name,
+		sprint.Goal,
+		sprint.Status,
+		sprint.StartDate,
+		sprint.EndDate,
+		sprint.CreatedBy,
+		sprint.CreatedAt,
+		sprint.UpdatedAt,
+	)
+	if err != nil {
+		r.logger.Error("Failed to create sprint", err, map[string]interface{}{
+			"project_id": sprint.ProjectID,
+		})
+		return fmt.Errorf("failed to create sprint: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID возвращает спринт по ID
+func (r *SprintRepository) GetByID(ctx context.Context, id string) (*domain.Sprint, error) {
+	query := `
+		SELECT
+			id, project_id, name, goal, status, start_date, end_date, started_at, closed_at,
+			created_by, created_at, updated_at
+		FROM sprints
+		WHERE id = $1
+	`
+
+	var sprint domain.Sprint
+	if err := conn(ctx, r.db).GetContext(ctx, &sprint, query, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		r.logger.Error("Failed to get sprint by ID", err, map[string]interface{}{
+			"id": id,
+		})
+		return nil, fmt.Errorf("failed to get sprint by ID: %w", err)
+	}
+
+	return &sprint, nil
+}
+
+// Update обновляет данные спринта
+func (r *SprintRepository) Update(ctx context.Context, sprint *domain.Sprint) error {
+	query := `
+		UPDATE sprints
+		SET name = $1, goal = $2, status = $3, start_date = $4, end_date = $5,
+			started_at = $6, closed_at = $7, updated_at = $8
+		WHERE id = $9
+	`
+
+	result, err := conn(ctx, r.db).ExecContext(
+		ctx,
+		query,
+		sprint.Name,
+		sprint.Goal,
+		sprint.Status,
+		sprint.StartDate,
+		sprint.EndDate,
+		sprint.StartedAt,
+		sprint.ClosedAt,
+		sprint.UpdatedAt,
+		sprint.ID,
+	)
+	if err != nil {
+		r.logger.Error("Failed to update sprint", err, map[string]interface{}{
+			"id": sprint.ID,
+		})
+		return fmt.Errorf("failed to update sprint: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("sprint not found")
+	}
+
+	return nil
+}
+
+// Delete удаляет спринт
+func (r *SprintRepository) Delete(ctx context.Context, id string) error {
+	query := `DELETE FROM sprints WHERE id = $1`
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query, id); err != nil {
+		r.logger.Error("Failed to delete sprint", err, map[string]interface{}{
+			"id": id,
+		})
+		return fmt.Errorf("failed to delete sprint: %w", err)
+	}
+
+	return nil
+}
+
+// ListByProject возвращает спринты проекта, упорядоченные по дате начала
+func (r *SprintRepository) ListByProject(ctx context.Context, projectID string) ([]*domain.Sprint, error) {
+	query := `
+		SELECT
+			id, project_id, name, goal, status, start_date, end_date, started_at, closed_at,
+			created_by, created_at, updated_at
+		FROM sprints
+		WHERE project_id = $1
+		ORDER BY start_date ASC, created_at ASC
+	`
+
+	sprints := []*domain.Sprint{}
+	if err := conn(ctx, r.db).SelectContext(ctx, &sprints, query, projectID); err != nil {
+		r.logger.Error("Failed to list sprints", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return nil, fmt.Errorf("failed to list sprints: %w", err)
+	}
+
+	return sprints, nil
+}
+
+// GetProgress возвращает сводный прогресс задач указанных спринтов
+func (r *SprintRepository) GetProgress(ctx context.Context, sprintIDs []string) (map[string]*domain.SprintProgress, error) {
+	result := make(map[string]*domain.SprintProgress, len(sprintIDs))
+	if len(sprintIDs) == 0 {
+		return result, nil
+	}
+
+	query := `
+		SELECT
+			sprint_id,
+			COUNT(*) AS total_tasks,
+			COUNT(*) FILTER (WHERE status = 'completed') AS completed_tasks,
+			COALESCE(SUM(estimated_hours), 0) AS estimated_hours,
+			COALESCE(SUM(estimated_hours) FILTER (WHERE status != 'completed'), 0) AS remaining_hours
+		FROM tasks
+		WHERE sprint_id = ANY($1) AND status != 'cancelled'
+		GROUP BY sprint_id
+	`
+
+	var rows []*domain.SprintProgress
+	if err := conn(ctx, r.db).SelectContext(ctx, &rows, query, pq.Array(sprintIDs)); err != nil {
+		r.logger.Error("Failed to get sprint progress", err)
+		return nil, fmt.Errorf("failed to get sprint progress: %w", err)
+	}
+
+	for _, row := range rows {
+		result[row.SprintID] = row
+	}
+
+	return result, nil
+}
+
+// SetTasksSprint включает задачи в спринт или возвращает их в бэклог
+func (r *SprintRepository) SetTasksSprint(ctx context.Context, taskIDs []string, sprintID *string, userID string) error {
+	if err := setTasksSprint(ctx, r.db, taskIDs, sprintID, userID); err != nil {
+		r.logger.Error("Failed to set tasks sprint", err, map[string]interface{}{
+			"tasks": len(taskIDs),
+		})
+		return err
+	}
+
+	return nil
+}
+
+// Close завершает спринт и переносит его незавершенные задачи
+func (r *SprintRepository) Close(ctx context.Context, sprint *domain.Sprint, moveToSprintID *string, userID string) ([]string, error) {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				r.logger.Error("Failed to rollback transaction", rbErr)
+			}
+		}
+	}()
+
+	taskIDs := []string{}
+	if err = tx.SelectContext(ctx, &taskIDs, `
+		SELECT id FROM tasks
+		WHERE sprint_id = $1 AND status = ANY($2::task_status[])
+		FOR UPDATE
+	`, sprint.ID, stringArray(domain.OpenTaskStatuses)); err != nil {
+		r.logger.Error("Failed to get open sprint tasks", err, map[string]interface{}{
+			"sprint_id": sprint.ID,
+		})
+		return nil, fmt.Errorf("failed to get open sprint tasks: %w", err)
+	}
+
+	if len(taskIDs) > 0 {
+		if err = setTasksSprint(ctx, tx, taskIDs, moveToSprintID, userID); err != nil {
+			r.logger.Error("Failed to move open sprint tasks", err, map[string]interface{}{
+				"sprint_id": sprint.ID,
+			})
+			return nil, err
+		}
+	}
+
+	if _, err = tx.ExecContext(ctx, `
+		UPDATE sprints SET status = $1, closed_at = $2, updated_at = $3 WHERE id = $4
+	`, sprint.Status, sprint.ClosedAt, sprint.UpdatedAt, sprint.ID); err != nil {
+		r.logger.Error("Failed to close sprint", err, map[string]interface{}{
+			"sprint_id": sprint.ID,
+		})
+		return nil, fmt.Errorf("failed to close sprint: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return taskIDs, nil
+}
+
+// ListScopeTasks возвращает задачи, которые входят или когда-либо входили в спринт
+func (r *SprintRepository) ListScopeTasks(ctx context.Context, sprintID string) ([]*domain.Task, error) {
+	query := `
+		SELECT
+			id, title, description, project_id, status, priority,
+			assignee_id, created_by, due_date, estimated_hours, spent_hours,
+			created_at, updated_at, completed_at, impact, urgency, priority_score, rank, epic_id, sprint_id, parent_id
+		FROM tasks
+		WHERE sprint_id = $1
+			OR id IN (SELECT task_id FROM task_history WHERE field = 'sprint_id' AND new_value = $2)
+		ORDER BY rank, id
+	`
+
+	tasks := []*domain.Task{}
+	if err := conn(ctx, r.db).SelectContext(ctx, &tasks, query, sprintID, sprintID); err != nil {
+		r.logger.Error("Failed to list sprint scope tasks", err, map[string]interface{}{
+			"sprint_id": sprintID,
+		})
+		return nil, fmt.Errorf("failed to list sprint scope tasks: %w", err)
+	}
+
+	return tasks, nil
+}
+
+// ListTaskChanges возвращает смены статуса и спринта задач, входивших в спринт, после его начала
+func (r *SprintRepository) ListTaskChanges(ctx context.Context, sprintID string) ([]*domain.SprintTaskChange, error) {
+	query := `
+		SELECT h.task_id, h.field, h.old_value, h.new_value, h.changed_at
+		FROM task_history h
+		JOIN sprints s ON s.id = $1
+		WHERE h.field IN ('status', 'sprint_id')
+			AND h.changed_at > s.start_date
+			AND h.task_id IN (
+				SELECT id FROM tasks WHERE sprint_id = $1
+				UNION
+				SELECT task_id FROM task_history WHERE field = 'sprint_id' AND new_value = $2
+			)
+		ORDER BY h.changed_at, h.id
+	`
+
+	changes := []*domain.SprintTaskChange{}
+	if err := conn(ctx, r.db).SelectContext(ctx, &changes, query, sprintID, sprintID); err != nil {
+		r.logger.Error("Failed to list sprint task changes", err, map[string]interface{}{
+			"sprint_id": sprintID,
+		})
+		return nil, fmt.Errorf("failed to list sprint task changes: %w", err)
+	}
+
+	return changes, nil
+}
+
+// setTasksSprint переносит задачи в спринт или в бэклог и записывает перенос в историю задач.
+// Задачи, уже находящиеся в целевом спринте, не изменяются
+func setTasksSprint(ctx context.Context, db sqlx.ExecerContext, taskIDs []string, sprintID *string, userID string) error {
+	_, err := db.ExecContext(ctx, `
+		WITH moved AS (
+			SELECT id, sprint_id FROM tasks
+			WHERE id = ANY($1::uuid[]) AND sprint_id IS DISTINCT FROM $2::uuid
+			FOR UPDATE
+		), updated AS (
+			UPDATE tasks t SET sprint_id = $2::uuid, updated_at = $3
+			FROM moved
+			WHERE t.id = moved.id
+		)
+		INSERT INTO task_history (task_id, user_id, field, old_value, new_value, changed_at)
+		SELECT id, $4, 'sprint_id', sprint_id::text, $2::text, $3
+		FROM moved
+	`, pq.Array(taskIDs), sprintID, time.Now(), userID)
+	if err != nil {
+		return fmt.Errorf("failed to set tasks sprint: %w", err)
+	}
+
+	return nil
+}
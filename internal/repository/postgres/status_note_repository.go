@@ -0,0 +1,144 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/pkg/logger"
+)
+
+// StatusNoteRepository реализует репозиторий заметок страницы статуса с использованием PostgreSQL
+type StatusNoteRepository struct {
+	db     *sqlx.DB
+	logger logger.Logger
+}
+
+// NewStatusNoteRepository создает новый экземпляр StatusNoteRepository
+func NewStatusNoteRepository(db *sqlx.DB, logger logger.Logger) *StatusNoteRepository {
+	return &StatusNoteRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Create создает заметку
+func (r *StatusNoteRepository) Create(ctx context.Context, note *domain.StatusNote) error {
+	query := `
+		INSERT INTO status_notes (id, title, message, severity, created_by, created_at, updated_at, resolved_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	_, err := conn(ctx, r.db).ExecContext(
+		ctx,
+		query,
+		note.ID,
+		note.Title,
+		note.Message,
+		note.Severity,
+		note.CreatedBy,
+		note.CreatedAt,
+		note.UpdatedAt,
+		note.ResolvedAt,
+	)
+	if err != nil {
+		r.logger.Error("Failed to create status note", err)
+		return fmt.Errorf("failed to create status note: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID возвращает заметку по ID
+func (r *StatusNoteRepository) GetByID(ctx context.Context, id string) (*domain.StatusNote, error) {
+	query := `
+		SELECT id, title, message, severity, created_by, created_at, updated_at, resolved_at
+		FROM status_notes
+		WHERE id = $1
+	`
+
+	var note domain.StatusNote
+	if err := conn(ctx, r.db).GetContext(ctx, &note, query, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		r.logger.Error("Failed to get status note", err, map[string]interface{}{
+			"id": id,
+		})
+		return nil, fmt.Errorf("failed to get status note: %w", err)
+	}
+
+	return &note, nil
+}
+
+// Update обновляет заметку
+func (r *StatusNoteRepository) Update(ctx context.Context, note *domain.StatusNote) error {
+	query := `
+		UPDATE status_notes
+		SET title = $1, message = $2, severity = $3, updated_at = $4, resolved_at = $5
+		WHERE id = $6
+	`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, note.Title, note.Message, note.Severity, note.UpdatedAt, note.ResolvedAt, note.ID)
+	if err != nil {
+		r.logger.Error("Failed to update status note", err, map[string]interface{}{
+			"id": note.ID,
+		})
+		return fmt.Errorf("failed to update status note: %w", err)
+	}
+
+	return nil
+}
+
+// Delete удаляет заметку
+func (r *StatusNoteRepository) Delete(ctx context.Context, id string) error {
+	query := `DELETE FROM status_notes WHERE id = $1`
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query, id); err != nil {
+		r.logger.Error("Failed to delete status note", err, map[string]interface{}{
+			"id": id,
+		})
+		return fmt.Errorf("failed to delete status note: %w", err)
+	}
+
+	return nil
+}
+
+// List возвращает все заметки, начиная с новых
+func (r *StatusNoteRepository) List(ctx context.Context) ([]*domain.StatusNote, error) {
+	query := `
+		SELECT id, title, message, severity, created_by, created_at, updated_at, resolved_at
+		FROM status_notes
+		ORDER BY created_at DESC
+	`
+
+	var notes []*domain.StatusNote
+	if err := conn(ctx, r.db).SelectContext(ctx, &notes, query); err != nil {
+		r.logger.Error("Failed to list status notes", err)
+		return nil, fmt.Errorf("failed to list status notes: %w", err)
+	}
+
+	return notes, nil
+}
+
+// ListRecent возвращает нерешенные заметки и заметки, решенные после resolvedAfter
+func (r *StatusNoteRepository) ListRecent(ctx context.Context, resolvedAfter time.Time, limit int) ([]*domain.StatusNote, error) {
+	query := `
+		SELECT id, title, message, severity, created_by, created_at, updated_at, resolved_at
+		FROM status_notes
+		WHERE resolved_at IS NULL OR resolved_at > $1
+		ORDER BY created_at DESC
+		LIMIT $2
+	`
+
+	var notes []*domain.StatusNote
+	if err := conn(ctx, r.db).SelectContext(ctx, &notes, query, resolvedAfter, limit); err != nil {
+		r.logger.Error("Failed to list recent status notes", err)
+		return nil, fmt.Errorf("failed to list recent status notes: %w", err)
+	}
+
+	return notes, nil
+}
@@ -0,0 +1,151 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/pkg/logger"
+)
+
+// TaskFormRepository реализует репозиторий форм создания задач с использованием PostgreSQL
+type TaskFormRepository struct {
+	db     *sqlx.DB
+	logger logger.Logger
+}
+
+// NewTaskFormRepository создает новый экземпляр TaskFormRepository
+func NewTaskFormRepository(db *sqlx.DB, logger logger.Logger) *TaskFormRepository {
+	return &TaskFormRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// GetByProject возвращает форму проекта
+func (r *TaskFormRepository) GetByProject(ctx context.Context, projectID string) (*domain.TaskForm, error) {
+	query := `
+		SELECT project_id, fields, updated_by, updated_at
+		FROM task_forms
+		WHERE project_id = $1
+	`
+
+	var form domain.TaskForm
+	var fields []byte
+	err := conn(ctx, r.db).QueryRowxContext(ctx, query, projectID).Scan(
+		&form.ProjectID,
+		&fields,
+		&form.UpdatedBy,
+		&form.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		r.logger.Error("Failed to get task form", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return nil, fmt.Errorf("failed to get task form: %w", err)
+	}
+
+	if err := json.Unmarshal(fields, &form.Fields); err != nil {
+		r.logger.Error("Failed to decode task form fields", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return nil, fmt.Errorf("failed to decode task form fields: %w", err)
+	}
+
+	return &form, nil
+}
+
+// Save создает или обновляет форму проекта
+func (r *TaskFormRepository) Save(ctx context.Context, form *domain.TaskForm) error {
+	query := `
+		INSERT INTO task_forms (project_id, fields, updated_by, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (project_id) DO UPDATE SET
+			fields = EXCLUDED.fields,
+			updated_by = EXCLUDED.updated_by,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	fields, err := json.Marshal(form.Fields)
+	if err != nil {
+		return fmt.Errorf("failed to encode task form fields: %w", err)
+	}
+
+	_, err = conn(ctx, r.db).ExecContext(ctx, query, form.ProjectID, fields, form.UpdatedBy, form.UpdatedAt)
+	if err != nil {
+		r.logger.Error("Failed to save task form", err, map[string]interface{}{
+			"project_id": form.ProjectID,
+		})
+		return fmt.Errorf("failed to save task form: %w", err)
+	}
+
+	return nil
+}
+
+// Delete удаляет форму проекта
+func (r *TaskFormRepository) Delete(ctx context.Context, projectID string) error {
+	query := `DELETE FROM task_forms WHERE project_id = $1`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, projectID)
+	if err != nil {
+		r.logger.Error("Failed to delete task form", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return fmt.Errorf("failed to delete task form: %w", err)
+	}
+
+	return nil
+}
+
+// SaveValues сохраняет значения пользовательских полей формы для задачи
+func (r *TaskFormRepository) SaveValues(ctx context.Context, taskID string, values map[string]interface{}) error {
+	query := `
+		INSERT INTO task_form_values (task_id, form_values)
+		VALUES ($1, $2)
+		ON CONFLICT (task_id) DO UPDATE SET form_values = EXCLUDED.form_values
+	`
+
+	data, err := json.Marshal(values)
+	if err != nil {
+		return fmt.Errorf("failed to encode task form values: %w", err)
+	}
+
+	_, err = conn(ctx, r.db).ExecContext(ctx, query, taskID, data)
+	if err != nil {
+		r.logger.Error("Failed to save task form values", err, map[string]interface{}{
+			"task_id": taskID,
+		})
+		return fmt.Errorf("failed to save task form values: %w", err)
+	}
+
+	return nil
+}
+
+// GetValues возвращает значения пользовательских полей формы для задачи
+func (r *TaskFormRepository) GetValues(ctx context.Context, taskID string) (map[string]interface{}, error) {
+	query := `SELECT form_values FROM task_form_values WHERE task_id = $1`
+
+	var data []byte
+	if err := conn(ctx, r.db).GetContext(ctx, &data, query, taskID); err != nil {
+		if err == sql.ErrNoRows {
+			return map[string]interface{}{}, nil
+		}
+		r.logger.Error("Failed to get task form values", err, map[string]interface{}{
+			"task_id": taskID,
+		})
+		return nil, fmt.Errorf("failed to get task form values: %w", err)
+	}
+
+	values := make(map[string]interface{})
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("failed to decode task form values: %w", err)
+	}
+
+	return values, nil
+}
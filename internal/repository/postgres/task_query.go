@@ -0,0 +1,149 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/nurlyy/task_manager/pkg/logger"
+)
+
+// maxCachedStatements ограничивает число различных форм запросов, для которых
+// держатся подготовленные выражения
+const maxCachedStatements = 64
+
+// filterQuery собирает нормализованный запрос с именованными параметрами.
+// Списки значений передаются одним параметром-массивом, поэтому текст запроса
+// зависит только от набора примененных фильтров, а не от их значений
+type filterQuery struct {
+	conditions []string
+	names      map[string]int
+	args       []interface{}
+}
+
+// newFilterQuery создает пустой запрос
+func newFilterQuery() *filterQuery {
+	return &filterQuery{names: make(map[string]int)}
+}
+
+// param связывает значение с именем и возвращает позиционный плейсхолдер.
+// Повторное использование имени возвращает тот же плейсхолдер
+func (q *filterQuery) param(name string, value interface{}) string {
+	if index, ok := q.names[name]; ok {
+		return fmt.Sprintf("$%d", index)
+	}
+	q.args = append(q.args, value)
+	q.names[name] = len(q.args)
+	return fmt.Sprintf("$%d", len(q.args))
+}
+
+// where добавляет условие, соединяемое через AND
+func (q *filterQuery) where(condition string) {
+	q.conditions = append(q.conditions, condition)
+}
+
+// whereClause возвращает условие WHERE или пустую строку
+func (q *filterQuery) whereClause() string {
+	if len(q.conditions) == 0 {
+		return ""
+	}
+	return "WHERE " + strings.Join(q.conditions, " AND ")
+}
+
+// stmtCache хранит подготовленные выражения по тексту запроса, чтобы частые формы
+// запросов не разбирались и не планировались заново при каждом вызове
+type stmtCache struct {
+	db     *sqlx.DB
+	logger logger.Logger
+	mu     sync.Mutex
+	stmts  map[string]*cachedStmt
+}
+
+// cachedStmt - подготовленное выражение формы запроса. ready закрывается, когда
+// подготовка завершена; stmt остается nil, если она не удалась
+type cachedStmt struct {
+	ready chan struct{}
+	stmt  *sqlx.Stmt
+}
+
+// newStmtCache создает пустой кэш подготовленных выражений
+func newStmtCache(db *sqlx.DB, logger logger.Logger) *stmtCache {
+	return &stmtCache{
+		db:     db,
+		logger: logger,
+		stmts:  make(map[string]*cachedStmt),
+	}
+}
+
+// get возвращает подготовленное выражение для запроса или nil, если запрос нужно выполнить
+// без подготовки: кэш заполнен, подготовка не удалась или контекст отменен во время ожидания.
+// Выражение готовит первый вызов с новой формой запроса вне блокировки кэша, остальные
+// вызовы той же формы ждут его, не задерживая запросы других форм
+func (c *stmtCache) get(ctx context.Context, query string) *sqlx.Stmt {
+	c.mu.Lock()
+	entry, ok := c.stmts[query]
+	if !ok {
+		if len(c.stmts) >= maxCachedStatements {
+			c.mu.Unlock()
+			return nil
+		}
+		entry = &cachedStmt{ready: make(chan struct{})}
+		c.stmts[query] = entry
+	}
+	c.mu.Unlock()
+
+	if ok {
+		select {
+		case <-entry.ready:
+			return entry.stmt
+		case <-ctx.Done():
+			return nil
+		}
+	}
+
+	stmt, err := c.db.PreparexContext(ctx, query)
+	if err != nil {
+		// Неудачная подготовка не запоминается: следующий вызов попробует снова,
+		// а запрос выполняется без подготовки и сам сообщит об ошибке, если она в запросе
+		c.logger.Warn("Failed to prepare statement, running query unprepared", map[string]interface{}{
+			"query": query,
+		}, map[string]interface{}{
+			"error": err,
+		})
+		c.mu.Lock()
+		delete(c.stmts, query)
+		c.mu.Unlock()
+	}
+	entry.stmt = stmt
+	close(entry.ready)
+
+	return stmt
+}
+
+// selectContext выполняет запрос, возвращающий строки, через подготовленное выражение.
+// В транзакции из контекста запрос выполняется без подготовки
+func (c *stmtCache) selectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	if tx, ok := txFromContext(ctx); ok {
+		return tx.SelectContext(ctx, dest, query, args...)
+	}
+	stmt := c.get(ctx, query)
+	if stmt == nil {
+		return c.db.SelectContext(ctx, dest, query, args...)
+	}
+	return stmt.SelectContext(ctx, dest, args...)
+}
+
+// getContext выполняет запрос, возвращающий одну строку, через подготовленное выражение.
+// В транзакции из контекста запрос выполняется без подготовки
+func (c *stmtCache) getContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	if tx, ok := txFromContext(ctx); ok {
+		return tx.GetContext(ctx, dest, query, args...)
+	}
+	stmt := c.get(ctx, query)
+	if stmt == nil {
+		return c.db.GetContext(ctx, dest, query, args...)
+	}
+	return stmt.GetContext(ctx, dest, args...)
+}
@@ -0,0 +1,192 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/pkg/logger"
+)
+
+// taskRecurrenceColumns перечисляет колонки правила повторения для выборок
+const taskRecurrenceColumns = `
+	id, task_id, rule, occurrences, status, last_error, created_by, created_at, updated_at
+`
+
+// TaskRecurrenceRepository реализует репозиторий правил повторения задач с использованием PostgreSQL
+type TaskRecurrenceRepository struct {
+	db     *sqlx.DB
+	logger logger.Logger
+}
+
+// NewTaskRecurrenceRepository создает новый экземпляр TaskRecurrenceRepository
+func NewTaskRecurrenceRepository(db *sqlx.DB, logger logger.Logger) *TaskRecurrenceRepository {
+	return &TaskRecurrenceRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Upsert сохраняет правило повторения задачи. При замене правила число созданных задач
+// серии сохраняется, а автор и время создания остаются прежними
+func (r *TaskRecurrenceRepository) Upsert(ctx context.Context, recurrence *domain.TaskRecurrence) error {
+	query := `
+		INSERT INTO task_recurrences (
+			id, task_id, rule, occurrences, status, created_by, created_at, updated_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8
+		)
+		ON CONFLICT (task_id) DO UPDATE
+		SET rule = EXCLUDED.rule, status = EXCLUDED.status, last_error = NULL, updated_at = EXCLUDED.updated_at
+		RETURNING ` + taskRecurrenceColumns
+
+	err := conn(ctx, r.db).GetContext(
+		ctx,
+		recurrence,
+		query,
+		recurrence.ID,
+		recurrence.TaskID,
+		recurrence.Rule,
+		recurrence.Occurrences,
+		recurrence.Status,
+		recurrence.CreatedBy,
+		recurrence.CreatedAt,
+		recurrence.UpdatedAt,
+	)
+	if err != nil {
+		r.logger.Error("Failed to save task recurrence", err, map[string]interface{}{
+			"task_id": recurrence.TaskID,
+		})
+		return fmt.Errorf("failed to save task recurrence: %w", err)
+	}
+
+	return nil
+}
+
+// GetByTaskID возвращает правило повторения задачи
+func (r *TaskRecurrenceRepository) GetByTaskID(ctx context.Context, taskID string) (*domain.TaskRecurrence, error) {
+	query := `SELECT ` + taskRecurrenceColumns + ` FROM task_recurrences WHERE task_id = $1`
+
+	var recurrence domain.TaskRecurrence
+	if err := conn(ctx, r.db).GetContext(ctx, &recurrence, query, taskID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		r.logger.Error("Failed to get task recurrence", err, map[string]interface{}{
+			"task_id": taskID,
+		})
+		return nil, fmt.Errorf("failed to get task recurrence: %w", err)
+	}
+
+	return &recurrence, nil
+}
+
+// Delete удаляет правило повторения задачи
+func (r *TaskRecurrenceRepository) Delete(ctx context.Context, taskID string) (bool, error) {
+	result, err := conn(ctx, r.db).ExecContext(ctx, `DELETE FROM task_recurrences WHERE task_id = $1`, taskID)
+	if err != nil {
+		r.logger.Error("Failed to delete task recurrence", err, map[string]interface{}{
+			"task_id": taskID,
+		})
+		return false, fmt.Errorf("failed to delete task recurrence: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// ClaimDue переводит в обработку активные правила, текущая задача которых завершена, и правила,
+// захват которых истек. Строки, захваченные другим экземпляром планировщика, пропускаются
+func (r *TaskRecurrenceRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*domain.TaskRecurrence, error) {
+	query := `
+		UPDATE task_recurrences
+		SET status = 'processing', updated_at = $1
+		WHERE id IN (
+			SELECT tr.id
+			FROM task_recurrences tr
+			JOIN tasks t ON t.id = tr.task_id
+			WHERE (tr.status = 'active' OR (tr.status = 'processing' AND tr.updated_at < $3))
+				AND t.status = 'completed'
+			ORDER BY t.completed_at
+			LIMIT $2
+			FOR UPDATE OF tr SKIP LOCKED
+		)
+		RETURNING ` + taskRecurrenceColumns
+
+	var recurrences []*domain.TaskRecurrence
+	if err := conn(ctx, r.db).SelectContext(ctx, &recurrences, query, now, limit, now.Add(-lease)); err != nil {
+		r.logger.Error("Failed to claim due task recurrences", err)
+		return nil, fmt.Errorf("failed to claim due task recurrences: %w", err)
+	}
+
+	return recurrences, nil
+}
+
+// Advance переносит правило на следующую задачу серии, если оно все еще в обработке
+// и указывает на задачу prevTaskID
+func (r *TaskRecurrenceRepository) Advance(ctx context.Context, id string, prevTaskID string, taskID string, occurrences int, now time.Time) (bool, error) {
+	query := `
+		UPDATE task_recurrences
+		SET task_id = $1, occurrences = $2, status = 'active', last_error = NULL, updated_at = $3
+		WHERE id = $4 AND task_id = $5 AND status = 'processing'
+	`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, taskID, occurrences, now, id, prevTaskID)
+	if err != nil {
+		r.logger.Error("Failed to advance task recurrence", err, map[string]interface{}{
+			"id":      id,
+			"task_id": taskID,
+		})
+		return false, fmt.Errorf("failed to advance task recurrence: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// Release возвращает правило из обработки в активные
+func (r *TaskRecurrenceRepository) Release(ctx context.Context, id string, errMsg string) error {
+	query := `
+		UPDATE task_recurrences
+		SET status = 'active', last_error = $1, updated_at = NOW()
+		WHERE id = $2 AND status = 'processing'
+	`
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query, errMsg, id); err != nil {
+		r.logger.Error("Failed to release task recurrence", err, map[string]interface{}{
+			"id": id,
+		})
+		return fmt.Errorf("failed to release task recurrence: %w", err)
+	}
+
+	return nil
+}
+
+// Finish завершает серию повторяющихся задач
+func (r *TaskRecurrenceRepository) Finish(ctx context.Context, id string, errMsg *string, now time.Time) error {
+	query := `
+		UPDATE task_recurrences
+		SET status = 'finished', last_error = $1, updated_at = $2
+		WHERE id = $3
+	`
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query, errMsg, now, id); err != nil {
+		r.logger.Error("Failed to finish task recurrence", err, map[string]interface{}{
+			"id": id,
+		})
+		return fmt.Errorf("failed to finish task recurrence: %w", err)
+	}
+
+	return nil
+}
@@ -8,14 +8,17 @@ import (
 	"time"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/nurlyy/task_manager/internal/domain"
 	"github.com/nurlyy/task_manager/internal/repository"
+	"github.com/nurlyy/task_manager/pkg/lexorank"
 	"github.com/nurlyy/task_manager/pkg/logger"
 )
 
 // TaskRepository реализует репозиторий задач с использованием PostgreSQL
 type TaskRepository struct {
 	db     *sqlx.DB
+	stmts  *stmtCache
 	logger logger.Logger
 }
 
@@ -23,13 +26,14 @@ type TaskRepository struct {
 func NewTaskRepository(db *sqlx.DB, logger logger.Logger) *TaskRepository {
 	return &TaskRepository{
 		db:     db,
+		stmts:  newStmtCache(db, logger),
 		logger: logger,
 	}
 }
 
 // Create создает новую задачу
 func (r *TaskRepository) Create(ctx context.Context, task *domain.Task) error {
-	tx, err := r.db.BeginTxx(ctx, nil)
+	tx, err := beginTx(ctx, r.db)
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
@@ -42,20 +46,19 @@ func (r *TaskRepository) Create(ctx context.Context, task *domain.Task) error {
 		}
 	}()
 
-	// Устанавливаем значение app.current_user_id для триггера
-	qq := fmt.Sprintf(`SET LOCAL app.current_user_id = '%s'`, task.CreatedBy)
-
-	if _, err = tx.ExecContext(ctx, qq); err != nil {
-		return fmt.Errorf("failed to set local variable: %w", err)
+	// Задаем пользователя транзакции для триггера истории
+	if err = setCurrentUser(ctx, tx, task.CreatedBy); err != nil {
+		return err
 	}
 
 	// Сохраняем основные данные задачи
 	query := `
 		INSERT INTO tasks (
 			id, title, description, project_id, status, priority, 
-			assignee_id, created_by, due_date, estimated_hours, created_at, updated_at
+			assignee_id, created_by, due_date, estimated_hours, created_at, updated_at,
+			impact, urgency, priority_score, rank, parent_id
 		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17
 		) RETURNING id
 	`
 
@@ -74,6 +77,11 @@ func (r *TaskRepository) Create(ctx context.Context, task *domain.Task) error {
 		task.EstimatedHours,
 		task.CreatedAt,
 		task.UpdatedAt,
+		task.Impact,
+		task.Urgency,
+		task.PriorityScore,
+		task.Rank,
+		task.ParentID,
 	).Scan(&task.ID); err != nil {
 		r.logger.Error("Failed to create task", err, map[string]interface{}{
 			"title": task.Title,
@@ -99,6 +107,11 @@ func (r *TaskRepository) Create(ctx context.Context, task *domain.Task) error {
 		}
 	}
 
+	// Сохраняем исполнителей задачи
+	if err = r.insertAssignees(ctx, tx, task.ID, taskAssigneeIDs(task), task.AssigneeID, &task.CreatedBy); err != nil {
+		return err
+	}
+
 	if err = tx.Commit(); err != nil {
 		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
@@ -112,13 +125,13 @@ func (r *TaskRepository) GetByID(ctx context.Context, id string) (*domain.Task,
 		SELECT 
 			id, title, description, project_id, status, priority, 
 			assignee_id, created_by, due_date, estimated_hours, spent_hours, 
-			created_at, updated_at, completed_at
+			created_at, updated_at, completed_at, impact, urgency, priority_score, rank, epic_id, sprint_id, parent_id
 		FROM tasks 
 		WHERE id = $1
 	`
 
 	var task domain.Task
-	err := r.db.GetContext(ctx, &task, query, id)
+	err := conn(ctx, r.db).GetContext(ctx, &task, query, id)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -136,12 +149,19 @@ func (r *TaskRepository) GetByID(ctx context.Context, id string) (*domain.Task,
 	}
 	task.Tags = tags
 
+	// Получаем исполнителей задачи
+	assigneeIDs, err := r.GetAssignees(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	task.AssigneeIDs = assigneeIDs
+
 	return &task, nil
 }
 
 // Update обновляет данные задачи
 func (r *TaskRepository) Update(ctx context.Context, task *domain.Task) error {
-	tx, err := r.db.BeginTxx(ctx, nil)
+	tx, err := beginTx(ctx, r.db)
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
@@ -154,9 +174,9 @@ func (r *TaskRepository) Update(ctx context.Context, task *domain.Task) error {
 		}
 	}()
 
-	// Устанавливаем значение app.current_user_id для триггера
-	if _, err = tx.ExecContext(ctx, "SET LOCAL app.current_user_id = $1", task.CreatedBy); err != nil {
-		return fmt.Errorf("failed to set local variable: %w", err)
+	// Задаем пользователя транзакции для триггера истории
+	if err = setCurrentUser(ctx, tx, task.CreatedBy); err != nil {
+		return err
 	}
 
 	// Обновляем основные данные задачи
@@ -171,8 +191,11 @@ func (r *TaskRepository) Update(ctx context.Context, task *domain.Task) error {
 			due_date = $6,
 			estimated_hours = $7,
 			spent_hours = $8,
-			updated_at = $9
-		WHERE id = $10
+			updated_at = $9,
+			impact = $10,
+			urgency = $11,
+			priority_score = $12
+		WHERE id = $13
 	`
 
 	task.UpdatedAt = time.Now()
@@ -189,6 +212,9 @@ func (r *TaskRepository) Update(ctx context.Context, task *domain.Task) error {
 		task.EstimatedHours,
 		task.SpentHours,
 		task.UpdatedAt,
+		task.Impact,
+		task.Urgency,
+		task.PriorityScore,
 		task.ID,
 	)
 
@@ -208,6 +234,11 @@ func (r *TaskRepository) Update(ctx context.Context, task *domain.Task) error {
 		return fmt.Errorf("task not found")
 	}
 
+	// Синхронизируем основного исполнителя с таблицей исполнителей
+	if err = r.replacePrimaryAssignee(ctx, tx, task.ID, task.AssigneeID, nil); err != nil {
+		return err
+	}
+
 	if err = tx.Commit(); err != nil {
 		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
@@ -219,7 +250,7 @@ func (r *TaskRepository) Update(ctx context.Context, task *domain.Task) error {
 func (r *TaskRepository) Delete(ctx context.Context, id string) error {
 	query := `DELETE FROM tasks WHERE id = $1`
 
-	result, err := r.db.ExecContext(ctx, query, id)
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, id)
 	if err != nil {
 		r.logger.Error("Failed to delete task", err, map[string]interface{}{
 			"id": id,
@@ -239,37 +270,87 @@ func (r *TaskRepository) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
+// GetSubtaskProgress возвращает сводку по подзадачам задачи
+func (r *TaskRepository) GetSubtaskProgress(ctx context.Context, parentID string) (*domain.SubtaskProgress, error) {
+	query := `
+		SELECT
+			COUNT(*) AS total,
+			COUNT(*) FILTER (WHERE status <> ALL($2)) AS open,
+			COUNT(*) FILTER (WHERE status = $3) AS completed,
+			COUNT(*) FILTER (WHERE status = $4) AS cancelled,
+			COALESCE(SUM(estimated_hours), 0) AS estimated_hours,
+			COALESCE(SUM(spent_hours), 0) AS spent_hours
+		FROM tasks
+		WHERE parent_id = $1
+	`
+
+	var progress domain.SubtaskProgress
+	if err := conn(ctx, r.db).GetContext(
+		ctx,
+		&progress,
+		query,
+		parentID,
+		closedTaskStatuses,
+		domain.TaskStatusCompleted,
+		domain.TaskStatusCancelled,
+	); err != nil {
+		r.logger.Error("Failed to get subtask progress", err, map[string]interface{}{
+			"parent_id": parentID,
+		})
+		return nil, fmt.Errorf("failed to get subtask progress: %w", err)
+	}
+	progress.ComputePercent()
+
+	return &progress, nil
+}
+
 // List возвращает список задач с фильтрацией
 func (r *TaskRepository) List(ctx context.Context, filter repository.TaskFilter) ([]*domain.Task, error) {
-	whereClause, args := r.buildWhereClause(filter)
+	q := r.buildFilterQuery(filter)
 	orderClause := r.buildOrderClause(filter)
-	limitOffset := fmt.Sprintf("LIMIT %d OFFSET %d", filter.Limit, filter.Offset)
+	limitOffset := fmt.Sprintf("LIMIT %s OFFSET %s", q.param("limit", filter.Limit), q.param("offset", filter.Offset))
 
 	query := fmt.Sprintf(`
 		SELECT 
 			id, title, description, project_id, status, priority, 
 			assignee_id, created_by, due_date, estimated_hours, spent_hours, 
-			created_at, updated_at, completed_at
+			created_at, updated_at, completed_at, impact, urgency, priority_score, rank, epic_id, sprint_id, parent_id
 		FROM tasks
 		%s
 		%s
 		%s
-	`, whereClause, orderClause, limitOffset)
+	`, q.whereClause(), orderClause, limitOffset)
 
 	tasks := []*domain.Task{}
-	err := r.db.SelectContext(ctx, &tasks, query, args...)
+	err := r.stmts.selectContext(ctx, &tasks, query, q.args...)
 	if err != nil {
 		r.logger.Error("Failed to list tasks", err)
 		return nil, fmt.Errorf("failed to list tasks: %w", err)
 	}
 
-	// Получаем теги для каждой задачи
+	// Теги и исполнители всех задач страницы загружаются двумя запросами
+	taskIDs := make([]string, len(tasks))
+	for i, task := range tasks {
+		taskIDs[i] = task.ID
+	}
+	tags, err := r.GetTagsByTasks(ctx, taskIDs)
+	if err != nil {
+		return nil, err
+	}
+	assignees, err := r.GetAssigneesByTasks(ctx, taskIDs)
+	if err != nil {
+		return nil, err
+	}
+
 	for _, task := range tasks {
-		tags, err := r.GetTags(ctx, task.ID)
-		if err != nil {
-			return nil, err
+		task.Tags = tags[task.ID]
+		if task.Tags == nil {
+			task.Tags = []string{}
+		}
+		task.AssigneeIDs = assignees[task.ID]
+		if task.AssigneeIDs == nil {
+			task.AssigneeIDs = []string{}
 		}
-		task.Tags = tags
 	}
 
 	return tasks, nil
@@ -277,16 +358,16 @@ func (r *TaskRepository) List(ctx context.Context, filter repository.TaskFilter)
 
 // Count возвращает количество задач с фильтрацией
 func (r *TaskRepository) Count(ctx context.Context, filter repository.TaskFilter) (int, error) {
-	whereClause, args := r.buildWhereClause(filter)
+	q := r.buildFilterQuery(filter)
 
 	query := fmt.Sprintf(`
 		SELECT COUNT(*) 
 		FROM tasks
 		%s
-	`, whereClause)
+	`, q.whereClause())
 
 	var count int
-	err := r.db.GetContext(ctx, &count, query, args...)
+	err := r.stmts.getContext(ctx, &count, query, q.args...)
 	if err != nil {
 		r.logger.Error("Failed to count tasks", err)
 		return 0, fmt.Errorf("failed to count tasks: %w", err)
@@ -300,7 +381,7 @@ func (r *TaskRepository) GetTags(ctx context.Context, taskID string) ([]string,
 	query := `SELECT tag FROM task_tags WHERE task_id = $1`
 
 	tags := []string{}
-	err := r.db.SelectContext(ctx, &tags, query, taskID)
+	err := conn(ctx, r.db).SelectContext(ctx, &tags, query, taskID)
 	if err != nil {
 		r.logger.Error("Failed to get task tags", err, map[string]interface{}{
 			"task_id": taskID,
@@ -311,11 +392,38 @@ func (r *TaskRepository) GetTags(ctx context.Context, taskID string) ([]string,
 	return tags, nil
 }
 
+// GetTagsByTasks возвращает теги нескольких задач одним запросом
+func (r *TaskRepository) GetTagsByTasks(ctx context.Context, taskIDs []string) (map[string][]string, error) {
+	tags := make(map[string][]string, len(taskIDs))
+	if len(taskIDs) == 0 {
+		return tags, nil
+	}
+
+	query := `SELECT task_id, tag FROM task_tags WHERE task_id = ANY($1)`
+
+	var rows []struct {
+		TaskID string `db:"task_id"`
+		Tag    string `db:"tag"`
+	}
+	if err := conn(ctx, r.db).SelectContext(ctx, &rows, query, pq.Array(taskIDs)); err != nil {
+		r.logger.Error("Failed to get tags of tasks", err, map[string]interface{}{
+			"count": len(taskIDs),
+		})
+		return nil, fmt.Errorf("failed to get tags of tasks: %w", err)
+	}
+
+	for _, row := range rows {
+		tags[row.TaskID] = append(tags[row.TaskID], row.Tag)
+	}
+
+	return tags, nil
+}
+
 // AddTag добавляет тег к задаче
 func (r *TaskRepository) AddTag(ctx context.Context, taskID, tag string) error {
 	query := `INSERT INTO task_tags (task_id, tag) VALUES ($1, $2) ON CONFLICT DO NOTHING`
 
-	_, err := r.db.ExecContext(ctx, query, taskID, tag)
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, taskID, tag)
 	if err != nil {
 		r.logger.Error("Failed to add task tag", err, map[string]interface{}{
 			"task_id": taskID,
@@ -331,7 +439,7 @@ func (r *TaskRepository) AddTag(ctx context.Context, taskID, tag string) error {
 func (r *TaskRepository) RemoveTag(ctx context.Context, taskID, tag string) error {
 	query := `DELETE FROM task_tags WHERE task_id = $1 AND tag = $2`
 
-	result, err := r.db.ExecContext(ctx, query, taskID, tag)
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, taskID, tag)
 	if err != nil {
 		r.logger.Error("Failed to remove task tag", err, map[string]interface{}{
 			"task_id": taskID,
@@ -354,7 +462,7 @@ func (r *TaskRepository) RemoveTag(ctx context.Context, taskID, tag string) erro
 
 // UpdateTags обновляет теги задачи
 func (r *TaskRepository) UpdateTags(ctx context.Context, taskID string, tags []string) error {
-	tx, err := r.db.BeginTxx(ctx, nil)
+	tx, err := beginTx(ctx, r.db)
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
@@ -398,6 +506,126 @@ func (r *TaskRepository) UpdateTags(ctx context.Context, taskID string, tags []s
 	return nil
 }
 
+// closedTaskStatuses перечисляет статусы задач, теги которых не считаются используемыми
+var closedTaskStatuses = pq.Array([]string{
+	string(domain.TaskStatusCompleted),
+	string(domain.TaskStatusCancelled),
+})
+
+// GetTagUsage возвращает теги задач проекта с числом задач, отсортированные по частоте
+func (r *TaskRepository) GetTagUsage(ctx context.Context, projectID string) ([]*domain.TagUsage, error) {
+	query := `
+		SELECT tt.tag,
+			COUNT(*) AS task_count,
+			COUNT(*) FILTER (WHERE t.status <> ALL($2)) AS open_task_count
+		FROM task_tags tt
+		JOIN tasks t ON t.id = tt.task_id
+		WHERE t.project_id = $1
+		GROUP BY tt.tag
+		ORDER BY task_count DESC, tt.tag
+	`
+
+	usage := []*domain.TagUsage{}
+	if err := conn(ctx, r.db).SelectContext(ctx, &usage, query, projectID, closedTaskStatuses); err != nil {
+		r.logger.Error("Failed to get tag usage", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return nil, fmt.Errorf("failed to get tag usage: %w", err)
+	}
+
+	for _, u := range usage {
+		u.Unused = u.OpenTaskCount == 0
+	}
+
+	return usage, nil
+}
+
+// RenameTag переименовывает тег во всех задачах проекта и возвращает ID измененных задач
+func (r *TaskRepository) RenameTag(ctx context.Context, projectID, tag, name string) ([]string, error) {
+	return r.MergeTags(ctx, projectID, []string{tag}, name)
+}
+
+// MergeTags заменяет теги sources тегом target во всех задачах проекта в одной транзакции
+// и возвращает ID измененных задач
+func (r *TaskRepository) MergeTags(ctx context.Context, projectID string, sources []string, target string) ([]string, error) {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				r.logger.Error("Failed to rollback transaction", rbErr)
+			}
+		}
+	}()
+
+	// Удаляем исходные теги, запоминая задачи, у которых они были
+	var taskIDs []string
+	if err = tx.SelectContext(ctx, &taskIDs, `
+		WITH removed AS (
+			DELETE FROM task_tags tt
+			USING tasks t
+			WHERE t.id = tt.task_id AND t.project_id = $1 AND tt.tag = ANY($2) AND tt.tag <> $3
+			RETURNING tt.task_id
+		)
+		SELECT DISTINCT task_id FROM removed
+	`, projectID, pq.Array(sources), target); err != nil {
+		r.logger.Error("Failed to remove merged tags", err, map[string]interface{}{
+			"project_id": projectID,
+			"target":     target,
+		})
+		return nil, fmt.Errorf("failed to remove merged tags: %w", err)
+	}
+
+	// Задача могла уже иметь целевой тег
+	if _, err = tx.ExecContext(ctx, `
+		INSERT INTO task_tags (task_id, tag)
+		SELECT id, $2 FROM UNNEST($1::uuid[]) AS id
+		ON CONFLICT DO NOTHING
+	`, pq.Array(taskIDs), target); err != nil {
+		r.logger.Error("Failed to add merged tag", err, map[string]interface{}{
+			"project_id": projectID,
+			"target":     target,
+		})
+		return nil, fmt.Errorf("failed to add merged tag: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return taskIDs, nil
+}
+
+// DeleteUnusedTag удаляет тег из задач проекта, если его нет ни у одной открытой задачи.
+// Проверка и удаление выполняются одним запросом, поэтому тег, добавленный открытой задаче
+// параллельно, не будет удален. Возвращает ID измененных задач
+func (r *TaskRepository) DeleteUnusedTag(ctx context.Context, projectID, tag string) ([]string, error) {
+	query := `
+		DELETE FROM task_tags tt
+		USING tasks t
+		WHERE t.id = tt.task_id AND t.project_id = $1 AND tt.tag = $2
+			AND NOT EXISTS (
+				SELECT 1 FROM task_tags ot
+				JOIN tasks o ON o.id = ot.task_id
+				WHERE o.project_id = $1 AND ot.tag = $2 AND o.status <> ALL($3)
+			)
+		RETURNING tt.task_id
+	`
+
+	taskIDs := []string{}
+	if err := conn(ctx, r.db).SelectContext(ctx, &taskIDs, query, projectID, tag, closedTaskStatuses); err != nil {
+		r.logger.Error("Failed to delete unused tag", err, map[string]interface{}{
+			"project_id": projectID,
+			"tag":        tag,
+		})
+		return nil, fmt.Errorf("failed to delete unused tag: %w", err)
+	}
+
+	return taskIDs, nil
+}
+
 // LogTaskHistory добавляет запись в историю изменений задачи
 func (r *TaskRepository) LogTaskHistory(ctx context.Context, history *domain.TaskHistory) error {
 	query := `
@@ -408,7 +636,7 @@ func (r *TaskRepository) LogTaskHistory(ctx context.Context, history *domain.Tas
 		)
 	`
 
-	_, err := r.db.ExecContext(
+	_, err := conn(ctx, r.db).ExecContext(
 		ctx,
 		query,
 		history.ID,
@@ -442,7 +670,7 @@ func (r *TaskRepository) GetTaskHistory(ctx context.Context, taskID string) ([]*
 	`
 
 	history := []*domain.TaskHistory{}
-	err := r.db.SelectContext(ctx, &history, query, taskID)
+	err := conn(ctx, r.db).SelectContext(ctx, &history, query, taskID)
 	if err != nil {
 		r.logger.Error("Failed to get task history", err, map[string]interface{}{
 			"task_id": taskID,
@@ -501,7 +729,7 @@ func (r *TaskRepository) GetUpcomingTasks(ctx context.Context, daysThreshold int
 
 // UpdateStatus обновляет статус задачи
 func (r *TaskRepository) UpdateStatus(ctx context.Context, taskID string, status domain.TaskStatus, userID string) error {
-	tx, err := r.db.BeginTxx(ctx, nil)
+	tx, err := beginTx(ctx, r.db)
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
@@ -514,9 +742,9 @@ func (r *TaskRepository) UpdateStatus(ctx context.Context, taskID string, status
 		}
 	}()
 
-	// Устанавливаем значение app.current_user_id для триггера
-	if _, err = tx.ExecContext(ctx, "SET LOCAL app.current_user_id = $1", userID); err != nil {
-		return fmt.Errorf("failed to set local variable: %w", err)
+	// Задаем пользователя транзакции для триггера истории
+	if err = setCurrentUser(ctx, tx, userID); err != nil {
+		return err
 	}
 
 	query := `
@@ -554,7 +782,7 @@ func (r *TaskRepository) UpdateStatus(ctx context.Context, taskID string, status
 
 // UpdatePriority обновляет приоритет задачи
 func (r *TaskRepository) UpdatePriority(ctx context.Context, taskID string, priority domain.TaskPriority, userID string) error {
-	tx, err := r.db.BeginTxx(ctx, nil)
+	tx, err := beginTx(ctx, r.db)
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
@@ -567,9 +795,9 @@ func (r *TaskRepository) UpdatePriority(ctx context.Context, taskID string, prio
 		}
 	}()
 
-	// Устанавливаем значение app.current_user_id для триггера
-	if _, err = tx.ExecContext(ctx, "SET LOCAL app.current_user_id = $1", userID); err != nil {
-		return fmt.Errorf("failed to set local variable: %w", err)
+	// Задаем пользователя транзакции для триггера истории
+	if err = setCurrentUser(ctx, tx, userID); err != nil {
+		return err
 	}
 
 	query := `
@@ -607,7 +835,7 @@ func (r *TaskRepository) UpdatePriority(ctx context.Context, taskID string, prio
 
 // UpdateAssignee обновляет исполнителя задачи
 func (r *TaskRepository) UpdateAssignee(ctx context.Context, taskID string, assigneeID *string, userID string) error {
-	tx, err := r.db.BeginTxx(ctx, nil)
+	tx, err := beginTx(ctx, r.db)
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
@@ -620,9 +848,9 @@ func (r *TaskRepository) UpdateAssignee(ctx context.Context, taskID string, assi
 		}
 	}()
 
-	// Устанавливаем значение app.current_user_id для триггера
-	if _, err = tx.ExecContext(ctx, "SET LOCAL app.current_user_id = $1", userID); err != nil {
-		return fmt.Errorf("failed to set local variable: %w", err)
+	// Задаем пользователя транзакции для триггера истории
+	if err = setCurrentUser(ctx, tx, userID); err != nil {
+		return err
 	}
 
 	query := `
@@ -651,6 +879,10 @@ func (r *TaskRepository) UpdateAssignee(ctx context.Context, taskID string, assi
 		return fmt.Errorf("task not found")
 	}
 
+	if err = r.replacePrimaryAssignee(ctx, tx, taskID, assigneeID, &userID); err != nil {
+		return err
+	}
+
 	if err = tx.Commit(); err != nil {
 		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
@@ -658,293 +890,1164 @@ func (r *TaskRepository) UpdateAssignee(ctx context.Context, taskID string, assi
 	return nil
 }
 
-// LogTime добавляет запись о затраченном времени
-func (r *TaskRepository) LogTime(ctx context.Context, timeLog *repository.TimeLog) error {
+// GetAssigneeLoad возвращает загрузку пользователя незавершенными задачами. Учитываются
+// задачи, где пользователь основной или дополнительный исполнитель
+func (r *TaskRepository) GetAssigneeLoad(ctx context.Context, userID, excludeTaskID string, dueFrom, dueTo *time.Time) (*domain.AssigneeLoad, error) {
 	query := `
-		INSERT INTO time_logs (
-			id, task_id, user_id, hours, description, logged_at, log_date
-		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7
-		)
-	`
-
-	_, err := r.db.ExecContext(
-		ctx,
-		query,
-		timeLog.ID,
-		timeLog.TaskID,
-		timeLog.UserID,
-		timeLog.Hours,
-		timeLog.Description,
-		timeLog.LoggedAt,
-		timeLog.LogDate,
-	)
-
-	if err != nil {
-		r.logger.Error("Failed to log time", err, map[string]interface{}{
-			"task_id": timeLog.TaskID,
-			"user_id": timeLog.UserID,
-			"hours":   timeLog.Hours,
-		})
-		return fmt.Errorf("failed to log time: %w", err)
-	}
-
-	// Обновляем общее затраченное время в задаче
-	updateQuery := `
-		UPDATE tasks
-		SET spent_hours = spent_hours + $1, updated_at = $2
-		WHERE id = $3
+		SELECT
+			COUNT(*) AS open_tasks,
+			COUNT(*) FILTER (WHERE t.due_date BETWEEN $3::timestamptz AND $4::timestamptz) AS overlapping_tasks
+		FROM tasks t
+		JOIN task_assignees ta ON ta.task_id = t.id
+		WHERE ta.user_id = $1 AND t.id <> $2 AND t.status = ANY($5)
 	`
 
-	_, err = r.db.ExecContext(ctx, updateQuery, timeLog.Hours, time.Now(), timeLog.TaskID)
+	var load domain.AssigneeLoad
+	err := conn(ctx, r.db).GetContext(ctx, &load, query, userID, excludeTaskID, dueFrom, dueTo, stringArray(domain.OpenTaskStatuses))
 	if err != nil {
-		r.logger.Error("Failed to update task spent hours", err, map[string]interface{}{
-			"task_id": timeLog.TaskID,
-			"hours":   timeLog.Hours,
+		r.logger.Error("Failed to get assignee load", err, map[string]interface{}{
+			"user_id": userID,
 		})
-		return fmt.Errorf("failed to update task spent hours: %w", err)
+		return nil, fmt.Errorf("failed to get assignee load: %w", err)
 	}
 
-	return nil
+	return &load, nil
 }
 
-// GetTimeLogs возвращает записи о затраченном времени
-func (r *TaskRepository) GetTimeLogs(ctx context.Context, taskID string) ([]*repository.TimeLog, error) {
+// GetAssignees возвращает всех исполнителей задачи, основной исполнитель идет первым
+func (r *TaskRepository) GetAssignees(ctx context.Context, taskID string) ([]string, error) {
 	query := `
-		SELECT 
-			id, task_id, user_id, hours, description, logged_at, log_date
-		FROM time_logs
+		SELECT user_id
+		FROM task_assignees
 		WHERE task_id = $1
-		ORDER BY logged_at DESC
+		ORDER BY is_primary DESC, assigned_at, user_id
 	`
 
-	logs := []*repository.TimeLog{}
-	err := r.db.SelectContext(ctx, &logs, query, taskID)
+	assigneeIDs := []string{}
+	err := conn(ctx, r.db).SelectContext(ctx, &assigneeIDs, query, taskID)
 	if err != nil {
-		r.logger.Error("Failed to get time logs", err, map[string]interface{}{
+		r.logger.Error("Failed to get task assignees", err, map[string]interface{}{
 			"task_id": taskID,
 		})
-		return nil, fmt.Errorf("failed to get time logs: %w", err)
+		return nil, fmt.Errorf("failed to get task assignees: %w", err)
 	}
 
-	return logs, nil
+	return assigneeIDs, nil
 }
 
-// GetTaskMetrics возвращает метрики по задачам
-func (r *TaskRepository) GetTaskMetrics(ctx context.Context, projectID string) (*domain.ProjectMetrics, error) {
-	metrics := &domain.ProjectMetrics{
-		TasksByStatus: make(map[string]int),
-		TasksByUser:   make(map[string]int),
+// GetAssigneesByTasks возвращает исполнителей нескольких задач одним запросом.
+// Основной исполнитель каждой задачи идет первым
+func (r *TaskRepository) GetAssigneesByTasks(ctx context.Context, taskIDs []string) (map[string][]string, error) {
+	assignees := make(map[string][]string, len(taskIDs))
+	if len(taskIDs) == 0 {
+		return assignees, nil
 	}
 
-	// Получаем общее количество задач и количество завершенных
 	query := `
-		SELECT 
-			COUNT(*) as total,
-			SUM(CASE WHEN status = 'completed' THEN 1 ELSE 0 END) as completed,
-			SUM(CASE WHEN due_date < NOW() AND status != 'completed' THEN 1 ELSE 0 END) as overdue
-		FROM tasks
-		WHERE project_id = $1
+		SELECT task_id, user_id
+		FROM task_assignees
+		WHERE task_id = ANY($1)
+		ORDER BY task_id, is_primary DESC, assigned_at, user_id
 	`
 
-	type result struct {
-		Total     int `db:"total"`
-		Completed int `db:"completed"`
-		Overdue   int `db:"overdue"`
+	var rows []struct {
+		TaskID string `db:"task_id"`
+		UserID string `db:"user_id"`
 	}
-
-	var res result
-	err := r.db.GetContext(ctx, &res, query, projectID)
-	if err != nil {
-		r.logger.Error("Failed to get task metrics", err, map[string]interface{}{
-			"project_id": projectID,
+	if err := conn(ctx, r.db).SelectContext(ctx, &rows, query, pq.Array(taskIDs)); err != nil {
+		r.logger.Error("Failed to get assignees of tasks", err, map[string]interface{}{
+			"count": len(taskIDs),
 		})
-		return nil, fmt.Errorf("failed to get task metrics: %w", err)
+		return nil, fmt.Errorf("failed to get assignees of tasks: %w", err)
 	}
 
-	metrics.TaskCount = res.Total
-	metrics.CompletedTasks = res.Completed
-	metrics.OverdueTasks = res.Overdue
-
-	// Получаем количество задач по статусам
-	statusQuery := `
-		SELECT 
-			status, COUNT(*) as count
-		FROM tasks
-		WHERE project_id = $1
-		GROUP BY status
-	`
-
-	type statusCount struct {
-		Status string `db:"status"`
-		Count  int    `db:"count"`
+	for _, row := range rows {
+		assignees[row.TaskID] = append(assignees[row.TaskID], row.UserID)
 	}
 
-	statusCounts := []statusCount{}
-	err = r.db.SelectContext(ctx, &statusCounts, statusQuery, projectID)
-	if err != nil {
-		r.logger.Error("Failed to get task status counts", err, map[string]interface{}{
-			"project_id": projectID,
-		})
-		return nil, fmt.Errorf("failed to get task status counts: %w", err)
-	}
+	return assignees, nil
+}
 
-	for _, sc := range statusCounts {
-		metrics.TasksByStatus[sc.Status] = sc.Count
+// SetAssignees заменяет список исполнителей задачи и основного исполнителя
+func (r *TaskRepository) SetAssignees(ctx context.Context, taskID string, assigneeIDs []string, primaryID *string, userID string) error {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				r.logger.Error("Failed to rollback transaction", rbErr)
+			}
+			return
+		}
+	}()
 
-	// Получаем количество задач по пользователям
-	userQuery := `
-		SELECT 
-			assignee_id, COUNT(*) as count
-		FROM tasks
-		WHERE project_id = $1 AND assignee_id IS NOT NULL
-		GROUP BY assignee_id
-	`
-
-	type userCount struct {
-		AssigneeID string `db:"assignee_id"`
-		Count      int    `db:"count"`
+	// Задаем пользователя транзакции для триггера истории
+	if err = setCurrentUser(ctx, tx, userID); err != nil {
+		return err
 	}
 
-	userCounts := []userCount{}
-	err = r.db.SelectContext(ctx, &userCounts, userQuery, projectID)
+	result, err := tx.ExecContext(ctx, `
+		UPDATE tasks 
+		SET 
+			assignee_id = $1,
+			updated_at = $2
+		WHERE id = $3
+	`, primaryID, time.Now(), taskID)
 	if err != nil {
-		r.logger.Error("Failed to get task user counts", err, map[string]interface{}{
-			"project_id": projectID,
+		r.logger.Error("Failed to update task assignee", err, map[string]interface{}{
+			"task_id":     taskID,
+			"assignee_id": primaryID,
 		})
-		return nil, fmt.Errorf("failed to get task user counts: %w", err)
+		return fmt.Errorf("failed to update task assignee: %w", err)
 	}
 
-	for _, uc := range userCounts {
-		metrics.TasksByUser[uc.AssigneeID] = uc.Count
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
 	}
 
-	return metrics, nil
-}
-
-// Вспомогательные функции
-
-func (r *TaskRepository) buildWhereClause(filter repository.TaskFilter) (string, []interface{}) {
-	conditions := []string{}
-	args := []interface{}{}
-	argIndex := 1
-
-	if len(filter.IDs) > 0 {
-		placeholders := make([]string, len(filter.IDs))
-		for i, id := range filter.IDs {
-			placeholders[i] = fmt.Sprintf("$%d", argIndex)
-			args = append(args, id)
-			argIndex++
-		}
-		conditions = append(conditions, fmt.Sprintf("id IN (%s)", strings.Join(placeholders, ", ")))
+	if rowsAffected == 0 {
+		return fmt.Errorf("task not found")
 	}
 
-	if len(filter.ProjectIDs) > 0 {
-		placeholders := make([]string, len(filter.ProjectIDs))
-		for i, id := range filter.ProjectIDs {
-			placeholders[i] = fmt.Sprintf("$%d", argIndex)
-			args = append(args, id)
-			argIndex++
-		}
-		conditions = append(conditions, fmt.Sprintf("project_id IN (%s)", strings.Join(placeholders, ", ")))
+	// Удаляем исполнителей, которых нет в новом списке
+	if _, err = tx.ExecContext(
+		ctx,
+		"DELETE FROM task_assignees WHERE task_id = $1 AND NOT (user_id = ANY($2::uuid[]))",
+		taskID,
+		pq.Array(assigneeIDs),
+	); err != nil {
+		r.logger.Error("Failed to remove task assignees", err, map[string]interface{}{
+			"task_id": taskID,
+		})
+		return fmt.Errorf("failed to remove task assignees: %w", err)
 	}
 
-	if filter.Status != nil {
-		conditions = append(conditions, fmt.Sprintf("status = $%d", argIndex))
-		args = append(args, *filter.Status)
-		argIndex++
+	if err = r.insertAssignees(ctx, tx, taskID, assigneeIDs, primaryID, &userID); err != nil {
+		return err
 	}
 
-	if filter.Priority != nil {
-		conditions = append(conditions, fmt.Sprintf("priority = $%d", argIndex))
-		args = append(args, *filter.Priority)
-		argIndex++
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
-	if filter.AssigneeID != nil {
-		conditions = append(conditions, fmt.Sprintf("assignee_id = $%d", argIndex))
-		args = append(args, *filter.AssigneeID)
-		argIndex++
-	}
+	return nil
+}
 
-	if filter.CreatedBy != nil {
-		conditions = append(conditions, fmt.Sprintf("created_by = $%d", argIndex))
-		args = append(args, *filter.CreatedBy)
-		argIndex++
-	}
+// GetEffortShares возвращает доли оценки исполнителей и списанное ими время
+func (r *TaskRepository) GetEffortShares(ctx context.Context, taskID string) ([]*domain.TaskEffortShare, error) {
+	query := `
+		SELECT
+			ta.user_id, ta.estimated_hours,
+			COALESCE((
+				SELECT SUM(tl.hours) FROM time_logs tl
+				WHERE tl.task_id = ta.task_id AND tl.user_id = ta.user_id
+			), 0) AS spent_hours
+		FROM task_assignees ta
+		WHERE ta.task_id = $1
+		ORDER BY ta.is_primary DESC, ta.assigned_at, ta.user_id
+	`
 
-	if filter.DueBefore != nil {
-		conditions = append(conditions, fmt.Sprintf("due_date <= $%d", argIndex))
-		args = append(args, *filter.DueBefore)
-		argIndex++
+	shares := []*domain.TaskEffortShare{}
+	err := conn(ctx, r.db).SelectContext(ctx, &shares, query, taskID)
+	if err != nil {
+		r.logger.Error("Failed to get task effort shares", err, map[string]interface{}{
+			"task_id": taskID,
+		})
+		return nil, fmt.Errorf("failed to get task effort shares: %w", err)
 	}
 
-	if filter.DueAfter != nil {
-		conditions = append(conditions, fmt.Sprintf("due_date >= $%d", argIndex))
-		args = append(args, *filter.DueAfter)
-		argIndex++
-	}
+	return shares, nil
+}
+
+// SetEffortSplit задает доли оценки исполнителей и обновляет оценку задачи.
+// Пустой список сбрасывает распределение
+func (r *TaskRepository) SetEffortSplit(ctx context.Context, taskID string, shares map[string]float64, userID string) error {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				r.logger.Error("Failed to rollback transaction", rbErr)
+			}
+			return
+		}
+	}()
+
+	// Задаем пользователя транзакции для триггера истории
+	if err = setCurrentUser(ctx, tx, userID); err != nil {
+		return err
+	}
+
+	if _, err = tx.ExecContext(ctx, "UPDATE task_assignees SET estimated_hours = NULL WHERE task_id = $1", taskID); err != nil {
+		r.logger.Error("Failed to reset task effort split", err, map[string]interface{}{
+			"task_id": taskID,
+		})
+		return fmt.Errorf("failed to reset task effort split: %w", err)
+	}
+
+	if len(shares) > 0 {
+		var total float64
+		for assigneeID, hours := range shares {
+			if _, err = tx.ExecContext(
+				ctx,
+				"UPDATE task_assignees SET estimated_hours = $1 WHERE task_id = $2 AND user_id = $3",
+				hours,
+				taskID,
+				assigneeID,
+			); err != nil {
+				r.logger.Error("Failed to set task effort share", err, map[string]interface{}{
+					"task_id": taskID,
+					"user_id": assigneeID,
+				})
+				return fmt.Errorf("failed to set task effort share: %w", err)
+			}
+			total += hours
+		}
+
+		// Оценка задачи равна сумме долей исполнителей
+		if _, err = tx.ExecContext(
+			ctx,
+			"UPDATE tasks SET estimated_hours = $1, updated_at = $2 WHERE id = $3",
+			total,
+			time.Now(),
+			taskID,
+		); err != nil {
+			r.logger.Error("Failed to update task estimated hours", err, map[string]interface{}{
+				"task_id": taskID,
+			})
+			return fmt.Errorf("failed to update task estimated hours: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// UpdatePriorityScores сохраняет пересчитанные оценки приоритета задач
+func (r *TaskRepository) UpdatePriorityScores(ctx context.Context, updates []repository.TaskScoreUpdate) error {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				r.logger.Error("Failed to rollback transaction", rbErr)
+			}
+			return
+		}
+	}()
+
+	// Приоритет меняется только при явном указании, время обновления задачи не трогаем
+	query := `
+		UPDATE tasks
+		SET
+			priority_score = $1,
+			priority = COALESCE($2, priority)
+		WHERE id = $3
+	`
+
+	for _, update := range updates {
+		if _, err = tx.ExecContext(ctx, query, update.Score, update.Priority, update.TaskID); err != nil {
+			r.logger.Error("Failed to update task priority score", err, map[string]interface{}{
+				"task_id": update.TaskID,
+			})
+			return fmt.Errorf("failed to update task priority score: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// NextRank возвращает ближайший ранг задачи проекта, больший rank.
+// Пустой rank означает начало списка, пустой результат - что таких задач нет
+func (r *TaskRepository) NextRank(ctx context.Context, projectID, rank, excludeTaskID string) (string, error) {
+	query := `
+		SELECT rank FROM tasks
+		WHERE project_id = $1 AND rank > $2 AND id::text <> $3
+		ORDER BY rank
+		LIMIT 1
+	`
+
+	return r.neighborRank(ctx, query, projectID, rank, excludeTaskID)
+}
+
+// PrevRank возвращает ближайший ранг задачи проекта, меньший rank.
+// Пустой rank означает конец списка, пустой результат - что таких задач нет
+func (r *TaskRepository) PrevRank(ctx context.Context, projectID, rank, excludeTaskID string) (string, error) {
+	query := `
+		SELECT rank FROM tasks
+		WHERE project_id = $1 AND ($2 = '' OR rank < $2) AND rank <> '' AND id::text <> $3
+		ORDER BY rank DESC
+		LIMIT 1
+	`
+
+	return r.neighborRank(ctx, query, projectID, rank, excludeTaskID)
+}
+
+// UpdateRank обновляет ранг задачи
+func (r *TaskRepository) UpdateRank(ctx context.Context, taskID, rank string) error {
+	result, err := conn(ctx, r.db).ExecContext(ctx, "UPDATE tasks SET rank = $1, updated_at = $2 WHERE id = $3", rank, time.Now(), taskID)
+	if err != nil {
+		r.logger.Error("Failed to update task rank", err, map[string]interface{}{
+			"task_id": taskID,
+		})
+		return fmt.Errorf("failed to update task rank: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("task not found")
+	}
+
+	return nil
+}
+
+// RebalanceRanks равномерно перераспределяет ранги задач проекта, сохраняя порядок
+func (r *TaskRepository) RebalanceRanks(ctx context.Context, projectID string) error {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				r.logger.Error("Failed to rollback transaction", rbErr)
+			}
+			return
+		}
+	}()
+
+	// Блокируем задачи проекта, чтобы параллельные перемещения не нарушили порядок
+	ids := []string{}
+	if err = tx.SelectContext(ctx, &ids, "SELECT id FROM tasks WHERE project_id = $1 ORDER BY rank, created_at, id FOR UPDATE", projectID); err != nil {
+		r.logger.Error("Failed to get project task ranks", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return fmt.Errorf("failed to get project task ranks: %w", err)
+	}
+
+	ranks := lexorank.Spread(len(ids))
+	for i, id := range ids {
+		if _, err = tx.ExecContext(ctx, "UPDATE tasks SET rank = $1 WHERE id = $2", ranks[i], id); err != nil {
+			r.logger.Error("Failed to rebalance task rank", err, map[string]interface{}{
+				"task_id": id,
+			})
+			return fmt.Errorf("failed to rebalance task rank: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// MoveToProject переносит задачу в другой проект вместе с комментариями, списаниями времени
+// и историей. Связи задачи с материалами исходного проекта удаляются
+func (r *TaskRepository) MoveToProject(ctx context.Context, move *repository.TaskProjectMove) error {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				r.logger.Error("Failed to rollback transaction", rbErr)
+			}
+			return
+		}
+	}()
+
+	// Задаем пользователя транзакции для триггера истории
+	if err = setCurrentUser(ctx, tx, move.UserID); err != nil {
+		return err
+	}
+
+	// Эпики, спринты и родительская задача принадлежат исходному проекту, поэтому задача выходит из них
+	result, err := tx.ExecContext(ctx, `
+		UPDATE tasks
+		SET
+			project_id = $1,
+			status = $2,
+			rank = $3,
+			assignee_id = $4,
+			epic_id = NULL,
+			sprint_id = NULL,
+			parent_id = NULL,
+			updated_at = $5
+		WHERE id = $6 AND project_id = $7
+	`, move.ToProjectID, move.Status, move.Rank, move.PrimaryID, time.Now(), move.TaskID, move.FromProjectID)
+	if err != nil {
+		r.logger.Error("Failed to move task to project", err, map[string]interface{}{
+			"task_id":    move.TaskID,
+			"project_id": move.ToProjectID,
+		})
+		return fmt.Errorf("failed to move task to project: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		err = fmt.Errorf("task not found")
+		return err
+	}
+
+	// Подзадачи остаются в исходном проекте и становятся самостоятельными задачами
+	if _, err = tx.ExecContext(ctx, "UPDATE tasks SET parent_id = NULL WHERE parent_id = $1", move.TaskID); err != nil {
+		r.logger.Error("Failed to detach subtasks", err, map[string]interface{}{
+			"task_id": move.TaskID,
+		})
+		return fmt.Errorf("failed to detach subtasks: %w", err)
+	}
+
+	// Исполнители, не состоящие в новом проекте, снимаются с задачи
+	if _, err = tx.ExecContext(
+		ctx,
+		"DELETE FROM task_assignees WHERE task_id = $1 AND NOT (user_id = ANY($2::uuid[]))",
+		move.TaskID,
+		pq.Array(move.AssigneeIDs),
+	); err != nil {
+		r.logger.Error("Failed to remove task assignees", err, map[string]interface{}{
+			"task_id": move.TaskID,
+		})
+		return fmt.Errorf("failed to remove task assignees: %w", err)
+	}
+
+	if err = r.insertAssignees(ctx, tx, move.TaskID, move.AssigneeIDs, move.PrimaryID, &move.UserID); err != nil {
+		return err
+	}
+
+	if _, err = tx.ExecContext(ctx, "DELETE FROM task_tags WHERE task_id = $1", move.TaskID); err != nil {
+		return fmt.Errorf("failed to delete task tags: %w", err)
+	}
+
+	for _, tag := range move.Tags {
+		if _, err = tx.ExecContext(ctx, "INSERT INTO task_tags (task_id, tag) VALUES ($1, $2)", move.TaskID, tag); err != nil {
+			r.logger.Error("Failed to add task tag", err, map[string]interface{}{
+				"task_id": move.TaskID,
+				"tag":     tag,
+			})
+			return fmt.Errorf("failed to add task tag: %w", err)
+		}
+	}
+
+	// Решения, страницы вики и заметки встреч остаются в исходном проекте
+	for _, table := range []string{"decision_tasks", "wiki_page_tasks", "meeting_note_tasks"} {
+		if _, err = tx.ExecContext(ctx, "DELETE FROM "+table+" WHERE task_id = $1", move.TaskID); err != nil {
+			r.logger.Error("Failed to unlink moved task", err, map[string]interface{}{
+				"task_id": move.TaskID,
+				"table":   table,
+			})
+			return fmt.Errorf("failed to unlink moved task: %w", err)
+		}
+	}
+
+	if _, err = tx.ExecContext(ctx, `
+		INSERT INTO task_history (task_id, user_id, field, old_value, new_value)
+		VALUES ($1, $2, 'project_id', $3, $4)
+	`, move.TaskID, move.UserID, move.FromProjectID, move.ToProjectID); err != nil {
+		r.logger.Error("Failed to log task move", err, map[string]interface{}{
+			"task_id": move.TaskID,
+		})
+		return fmt.Errorf("failed to log task move: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// ListBacklog возвращает задачи бэклога проекта, начиная с самых старых
+func (r *TaskRepository) ListBacklog(ctx context.Context, projectID string) ([]*domain.Task, error) {
+	query := `
+		SELECT
+			id, title, description, project_id, status, priority,
+			assignee_id, created_by, due_date, estimated_hours, spent_hours,
+			created_at, updated_at, completed_at, impact, urgency, priority_score, rank, epic_id, sprint_id, parent_id
+		FROM tasks
+		WHERE project_id = $1 AND status = ANY($2)
+		ORDER BY created_at, id
+	`
+
+	tasks := []*domain.Task{}
+	if err := conn(ctx, r.db).SelectContext(ctx, &tasks, query, projectID, backlogStatuses()); err != nil {
+		r.logger.Error("Failed to list backlog tasks", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return nil, fmt.Errorf("failed to list backlog tasks: %w", err)
+	}
+
+	for _, task := range tasks {
+		tags, err := r.GetTags(ctx, task.ID)
+		if err != nil {
+			return nil, err
+		}
+		task.Tags = tags
+	}
+
+	return tasks, nil
+}
+
+// LabelStaleTasks добавляет тег устаревшим задачам бэклога одним запросом.
+// Проекты без строки настроек считаются не отключившими пометку
+func (r *TaskRepository) LabelStaleTasks(ctx context.Context, createdBefore time.Time, tag string) ([]*domain.StaleTaskLabel, error) {
+	query := `
+		WITH labeled AS (
+			INSERT INTO task_tags (task_id, tag)
+			SELECT t.id, $3
+			FROM tasks t
+			JOIN projects p ON p.id = t.project_id
+			LEFT JOIN project_task_settings s ON s.project_id = t.project_id
+			WHERE t.status = ANY($1)
+				AND t.created_at < $2
+				AND p.status <> 'archived'
+				AND COALESCE(s.auto_label_stale, TRUE)
+			ON CONFLICT (task_id, tag) DO NOTHING
+			RETURNING task_id
+		)
+		SELECT l.task_id, t.project_id
+		FROM labeled l
+		JOIN tasks t ON t.id = l.task_id
+	`
+
+	labels := []*domain.StaleTaskLabel{}
+	if err := conn(ctx, r.db).SelectContext(ctx, &labels, query, backlogStatuses(), createdBefore, tag); err != nil {
+		r.logger.Error("Failed to label stale tasks", err, map[string]interface{}{
+			"tag": tag,
+		})
+		return nil, fmt.Errorf("failed to label stale tasks: %w", err)
+	}
+
+	return labels, nil
+}
+
+// stringArray преобразует список строковых значений (статусов, приоритетов) в массив для запроса
+func stringArray[T ~string](values []T) pq.StringArray {
+	array := make(pq.StringArray, len(values))
+	for i, value := range values {
+		array[i] = string(value)
+	}
+	return array
+}
+
+// backlogStatuses возвращает статусы бэклога в виде массива для запроса
+func backlogStatuses() pq.StringArray {
+	statuses := make(pq.StringArray, 0, len(domain.BacklogStatuses))
+	for _, status := range domain.BacklogStatuses {
+		statuses = append(statuses, string(status))
+	}
+	return statuses
+}
+
+// neighborRank выполняет запрос поиска соседнего ранга
+func (r *TaskRepository) neighborRank(ctx context.Context, query, projectID, rank, excludeTaskID string) (string, error) {
+	var neighbor string
+	err := conn(ctx, r.db).GetContext(ctx, &neighbor, query, projectID, rank, excludeTaskID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", nil
+		}
+		r.logger.Error("Failed to get neighbor task rank", err, map[string]interface{}{
+			"project_id": projectID,
+			"rank":       rank,
+		})
+		return "", fmt.Errorf("failed to get neighbor task rank: %w", err)
+	}
+
+	return neighbor, nil
+}
+
+// LogTime добавляет запись о затраченном времени и увеличивает затраченное время задачи
+// в одной транзакции. Строка задачи блокируется, поэтому запись не теряется при
+// одновременном пересчете затраченного времени
+func (r *TaskRepository) LogTime(ctx context.Context, timeLog *repository.TimeLog) error {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				r.logger.Error("Failed to rollback transaction", rbErr)
+			}
+		}
+	}()
+
+	// Обновляем общее затраченное время в задаче
+	updateQuery := `
+		UPDATE tasks
+		SET spent_hours = COALESCE(spent_hours, 0) + $1, updated_at = $2
+		WHERE id = $3
+	`
+
+	if _, err = tx.ExecContext(ctx, updateQuery, timeLog.Hours, time.Now(), timeLog.TaskID); err != nil {
+		r.logger.Error("Failed to update task spent hours", err, map[string]interface{}{
+			"task_id": timeLog.TaskID,
+			"hours":   timeLog.Hours,
+		})
+		return fmt.Errorf("failed to update task spent hours: %w", err)
+	}
+
+	query := `
+		INSERT INTO time_logs (
+			id, task_id, user_id, hours, description, logged_at, log_date
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7
+		)
+	`
+
+	_, err = tx.ExecContext(
+		ctx,
+		query,
+		timeLog.ID,
+		timeLog.TaskID,
+		timeLog.UserID,
+		timeLog.Hours,
+		timeLog.Description,
+		timeLog.LoggedAt,
+		timeLog.LogDate,
+	)
+	if err != nil {
+		r.logger.Error("Failed to log time", err, map[string]interface{}{
+			"task_id": timeLog.TaskID,
+			"user_id": timeLog.UserID,
+			"hours":   timeLog.Hours,
+		})
+		return fmt.Errorf("failed to log time: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// GetTimeReconciliation сравнивает затраченное время задачи с суммой ее записей о времени
+func (r *TaskRepository) GetTimeReconciliation(ctx context.Context, taskID string) (*domain.TimeReconciliation, error) {
+	return r.timeReconciliation(ctx, conn(ctx, r.db), taskID, false)
+}
+
+// RecalculateSpentHours записывает в задачу сумму ее записей о времени и возвращает
+// сверку до пересчета. На время пересчета строка задачи блокируется, поэтому
+// одновременные списания времени и изменения задачи дожидаются его окончания
+func (r *TaskRepository) RecalculateSpentHours(ctx context.Context, taskID string) (*domain.TimeReconciliation, error) {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				r.logger.Error("Failed to rollback transaction", rbErr)
+			}
+		}
+	}()
+
+	var reconciliation *domain.TimeReconciliation
+	reconciliation, err = r.timeReconciliation(ctx, tx, taskID, true)
+	if err != nil {
+		return nil, err
+	}
+	if reconciliation == nil {
+		return nil, tx.Rollback()
+	}
 
-	if filter.IsOverdue != nil && *filter.IsOverdue {
-		conditions = append(conditions, fmt.Sprintf("(due_date < $%d AND status != 'completed')", argIndex))
-		args = append(args, time.Now())
-		argIndex++
+	if reconciliation.Difference != 0 {
+		query := `UPDATE tasks SET spent_hours = $1, updated_at = $2 WHERE id = $3`
+		if _, err = tx.ExecContext(ctx, query, reconciliation.LoggedHours, time.Now(), taskID); err != nil {
+			r.logger.Error("Failed to recalculate task spent hours", err, map[string]interface{}{
+				"task_id": taskID,
+			})
+			return nil, fmt.Errorf("failed to recalculate task spent hours: %w", err)
+		}
+		reconciliation.Recalculated = true
 	}
 
-	if len(filter.Tags) > 0 {
-		// Подзапрос для фильтрации по тегам
-		tagConditions := make([]string, len(filter.Tags))
-		for i, tag := range filter.Tags {
-			tagConditions[i] = fmt.Sprintf("$%d", argIndex)
-			args = append(args, tag)
-			argIndex++
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return reconciliation, nil
+}
+
+// RecalculateProjectSpentHours пересчитывает затраченное время всех задач проекта
+// и возвращает сверку до пересчета для задач, в которых время изменилось
+func (r *TaskRepository) RecalculateProjectSpentHours(ctx context.Context, projectID string) ([]*domain.TimeReconciliation, error) {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				r.logger.Error("Failed to rollback transaction", rbErr)
+			}
 		}
-		conditions = append(conditions, fmt.Sprintf("id IN (SELECT task_id FROM task_tags WHERE tag IN (%s) GROUP BY task_id HAVING COUNT(DISTINCT tag) = %d)",
-			strings.Join(tagConditions, ", "), len(filter.Tags)))
+	}()
+
+	// Блокируем задачи проекта в одном порядке, чтобы не взаимоблокироваться с другим пересчетом
+	lockQuery := `SELECT id FROM tasks WHERE project_id = $1 ORDER BY id FOR UPDATE`
+	if _, err = tx.ExecContext(ctx, lockQuery, projectID); err != nil {
+		r.logger.Error("Failed to lock project tasks", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return nil, fmt.Errorf("failed to lock project tasks: %w", err)
 	}
 
-	if filter.SearchText != nil {
-		conditions = append(conditions, fmt.Sprintf("(title ILIKE $%d OR description ILIKE $%d)", argIndex, argIndex))
-		searchPattern := "%" + *filter.SearchText + "%"
-		args = append(args, searchPattern)
-		argIndex++
+	query := `
+		UPDATE tasks t
+		SET spent_hours = sums.logged_hours, updated_at = $2
+		FROM (
+			SELECT
+				pt.id,
+				COALESCE(pt.spent_hours, 0) AS spent_hours,
+				COALESCE(SUM(tl.hours), 0) AS logged_hours,
+				COUNT(tl.id) AS log_count
+			FROM tasks pt
+			LEFT JOIN time_logs tl ON tl.task_id = pt.id
+			WHERE pt.project_id = $1
+			GROUP BY pt.id
+		) sums
+		WHERE sums.id = t.id AND sums.spent_hours <> sums.logged_hours
+		RETURNING
+			t.id AS task_id,
+			sums.spent_hours,
+			sums.logged_hours,
+			sums.spent_hours - sums.logged_hours AS difference,
+			sums.log_count
+	`
+
+	reconciliations := []*domain.TimeReconciliation{}
+	if err = tx.SelectContext(ctx, &reconciliations, query, projectID, time.Now()); err != nil {
+		r.logger.Error("Failed to recalculate project spent hours", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return nil, fmt.Errorf("failed to recalculate project spent hours: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
-	if len(conditions) > 0 {
-		return "WHERE " + strings.Join(conditions, " AND "), args
+	for _, reconciliation := range reconciliations {
+		reconciliation.Recalculated = true
 	}
-	return "", args
+
+	return reconciliations, nil
 }
 
-func (r *TaskRepository) buildOrderClause(filter repository.TaskFilter) string {
-	if filter.OrderBy != nil {
-		direction := "ASC"
-		if filter.OrderDir != nil && strings.ToUpper(*filter.OrderDir) == "DESC" {
-			direction = "DESC"
+// timeReconciliation читает затраченное время задачи и сводку ее записей о времени
+// (nil, если задача не найдена). С lock строка задачи блокируется до конца транзакции
+func (r *TaskRepository) timeReconciliation(ctx context.Context, q sqlx.QueryerContext, taskID string, lock bool) (*domain.TimeReconciliation, error) {
+	taskQuery := `SELECT id AS task_id, COALESCE(spent_hours, 0) AS spent_hours FROM tasks WHERE id = $1`
+	if lock {
+		taskQuery += ` FOR UPDATE`
+	}
+
+	var reconciliation domain.TimeReconciliation
+	if err := sqlx.GetContext(ctx, q, &reconciliation, taskQuery, taskID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		r.logger.Error("Failed to get task spent hours", err, map[string]interface{}{
+			"task_id": taskID,
+		})
+		return nil, fmt.Errorf("failed to get task spent hours: %w", err)
+	}
+
+	usersQuery := `
+		SELECT user_id, SUM(hours) AS hours, COUNT(*) AS log_count
+		FROM time_logs
+		WHERE task_id = $1
+		GROUP BY user_id
+		ORDER BY SUM(hours) DESC
+	`
+
+	reconciliation.Users = []domain.TimeReconciliationUser{}
+	if err := sqlx.SelectContext(ctx, q, &reconciliation.Users, usersQuery, taskID); err != nil {
+		r.logger.Error("Failed to get task time log totals", err, map[string]interface{}{
+			"task_id": taskID,
+		})
+		return nil, fmt.Errorf("failed to get task time log totals: %w", err)
+	}
+
+	for _, user := range reconciliation.Users {
+		reconciliation.LoggedHours += user.Hours
+		reconciliation.LogCount += user.LogCount
+	}
+	reconciliation.Difference = reconciliation.SpentHours - reconciliation.LoggedHours
+
+	return &reconciliation, nil
+}
+
+// GetTimeLogs возвращает записи о затраченном времени
+func (r *TaskRepository) GetTimeLogs(ctx context.Context, taskID string) ([]*repository.TimeLog, error) {
+	query := `
+		SELECT 
+			id, task_id, user_id, hours, description, logged_at, log_date
+		FROM time_logs
+		WHERE task_id = $1
+		ORDER BY logged_at DESC
+	`
+
+	logs := []*repository.TimeLog{}
+	err := conn(ctx, r.db).SelectContext(ctx, &logs, query, taskID)
+	if err != nil {
+		r.logger.Error("Failed to get time logs", err, map[string]interface{}{
+			"task_id": taskID,
+		})
+		return nil, fmt.Errorf("failed to get time logs: %w", err)
+	}
+
+	return logs, nil
+}
+
+// GetTaskMetrics возвращает метрики по задачам
+func (r *TaskRepository) GetTaskMetrics(ctx context.Context, projectID string) (*domain.ProjectMetrics, error) {
+	metrics := &domain.ProjectMetrics{
+		TasksByStatus: make(map[string]int),
+		TasksByUser:   make(map[string]int),
+	}
+
+	// Получаем общее количество задач и количество завершенных
+	query := `
+		SELECT 
+			COUNT(*) as total,
+			SUM(CASE WHEN status = 'completed' THEN 1 ELSE 0 END) as completed,
+			SUM(CASE WHEN due_date < NOW() AND status != 'completed' THEN 1 ELSE 0 END) as overdue
+		FROM tasks
+		WHERE project_id = $1
+	`
+
+	type result struct {
+		Total     int `db:"total"`
+		Completed int `db:"completed"`
+		Overdue   int `db:"overdue"`
+	}
+
+	var res result
+	err := conn(ctx, r.db).GetContext(ctx, &res, query, projectID)
+	if err != nil {
+		r.logger.Error("Failed to get task metrics", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return nil, fmt.Errorf("failed to get task metrics: %w", err)
+	}
+
+	metrics.TaskCount = res.Total
+	metrics.CompletedTasks = res.Completed
+	metrics.OverdueTasks = res.Overdue
+
+	// Получаем количество задач по статусам
+	statusQuery := `
+		SELECT 
+			status, COUNT(*) as count
+		FROM tasks
+		WHERE project_id = $1
+		GROUP BY status
+	`
+
+	type statusCount struct {
+		Status string `db:"status"`
+		Count  int    `db:"count"`
+	}
+
+	statusCounts := []statusCount{}
+	err = conn(ctx, r.db).SelectContext(ctx, &statusCounts, statusQuery, projectID)
+	if err != nil {
+		r.logger.Error("Failed to get task status counts", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return nil, fmt.Errorf("failed to get task status counts: %w", err)
+	}
+
+	for _, sc := range statusCounts {
+		metrics.TasksByStatus[sc.Status] = sc.Count
+	}
+
+	// Получаем количество задач по пользователям
+	userQuery := `
+		SELECT 
+			ta.user_id AS assignee_id, COUNT(*) as count
+		FROM task_assignees ta
+		JOIN tasks t ON t.id = ta.task_id
+		WHERE t.project_id = $1
+		GROUP BY ta.user_id
+	`
+
+	type userCount struct {
+		AssigneeID string `db:"assignee_id"`
+		Count      int    `db:"count"`
+	}
+
+	userCounts := []userCount{}
+	err = conn(ctx, r.db).SelectContext(ctx, &userCounts, userQuery, projectID)
+	if err != nil {
+		r.logger.Error("Failed to get task user counts", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return nil, fmt.Errorf("failed to get task user counts: %w", err)
+	}
+
+	for _, uc := range userCounts {
+		metrics.TasksByUser[uc.AssigneeID] = uc.Count
+	}
+
+	return metrics, nil
+}
+
+// Вспомогательные функции
+
+// insertAssignees добавляет исполнителей задачи, отмечая основного
+func (r *TaskRepository) insertAssignees(ctx context.Context, tx *repoTx, taskID string, assigneeIDs []string, primaryID *string, assignedBy *string) error {
+	// Сначала снимаем отметку, чтобы не нарушить уникальность основного исполнителя
+	if _, err := tx.ExecContext(ctx, "UPDATE task_assignees SET is_primary = FALSE WHERE task_id = $1 AND is_primary", taskID); err != nil {
+		return fmt.Errorf("failed to reset primary assignee: %w", err)
+	}
+
+	query := `
+		INSERT INTO task_assignees (task_id, user_id, is_primary, assigned_by)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (task_id, user_id) DO UPDATE SET is_primary = EXCLUDED.is_primary
+	`
+
+	for _, assigneeID := range assigneeIDs {
+		isPrimary := primaryID != nil && *primaryID == assigneeID
+		if _, err := tx.ExecContext(ctx, query, taskID, assigneeID, isPrimary, assignedBy); err != nil {
+			r.logger.Error("Failed to add task assignee", err, map[string]interface{}{
+				"task_id": taskID,
+				"user_id": assigneeID,
+			})
+			return fmt.Errorf("failed to add task assignee: %w", err)
 		}
+	}
+
+	return nil
+}
+
+// replacePrimaryAssignee заменяет основного исполнителя, не трогая остальных
+func (r *TaskRepository) replacePrimaryAssignee(ctx context.Context, tx *repoTx, taskID string, assigneeID *string, assignedBy *string) error {
+	if _, err := tx.ExecContext(
+		ctx,
+		"DELETE FROM task_assignees WHERE task_id = $1 AND is_primary AND user_id IS DISTINCT FROM $2",
+		taskID,
+		assigneeID,
+	); err != nil {
+		r.logger.Error("Failed to remove primary assignee", err, map[string]interface{}{
+			"task_id": taskID,
+		})
+		return fmt.Errorf("failed to remove primary assignee: %w", err)
+	}
+
+	if assigneeID == nil {
+		return nil
+	}
+
+	query := `
+		INSERT INTO task_assignees (task_id, user_id, is_primary, assigned_by)
+		VALUES ($1, $2, TRUE, $3)
+		ON CONFLICT (task_id, user_id) DO UPDATE SET is_primary = TRUE
+	`
+
+	if _, err := tx.ExecContext(ctx, query, taskID, *assigneeID, assignedBy); err != nil {
+		r.logger.Error("Failed to set primary assignee", err, map[string]interface{}{
+			"task_id": taskID,
+			"user_id": *assigneeID,
+		})
+		return fmt.Errorf("failed to set primary assignee: %w", err)
+	}
+
+	return nil
+}
 
-		// Проверяем, что поле сортировки допустимо
-		allowedFields := map[string]bool{
-			"id":              true,
-			"title":           true,
-			"status":          true,
-			"priority":        true,
-			"assignee_id":     true,
-			"created_by":      true,
-			"due_date":        true,
-			"created_at":      true,
-			"updated_at":      true,
-			"completed_at":    true,
-			"estimated_hours": true,
-			"spent_hours":     true,
+// taskAssigneeIDs возвращает список исполнителей задачи с основным исполнителем в начале
+func taskAssigneeIDs(task *domain.Task) []string {
+	ids := []string{}
+	seen := map[string]bool{}
+	if task.AssigneeID != nil {
+		ids = append(ids, *task.AssigneeID)
+		seen[*task.AssigneeID] = true
+	}
+	for _, id := range task.AssigneeIDs {
+		if !seen[id] {
+			ids = append(ids, id)
+			seen[id] = true
 		}
+	}
+	return ids
+}
+
+// buildFilterQuery строит нормализованное условие отбора задач по фильтру
+func (r *TaskRepository) buildFilterQuery(filter repository.TaskFilter) *filterQuery {
+	q := newFilterQuery()
+
+	if len(filter.IDs) > 0 {
+		q.where(fmt.Sprintf("id = ANY(%s::uuid[])", q.param("ids", pq.Array(filter.IDs))))
+	}
+
+	if len(filter.ProjectIDs) > 0 {
+		q.where(fmt.Sprintf("project_id = ANY(%s::uuid[])", q.param("project_ids", pq.Array(filter.ProjectIDs))))
+	}
+
+	if len(filter.Statuses) > 0 {
+		q.where(fmt.Sprintf("status = ANY(%s::task_status[])", q.param("statuses", stringArray(filter.Statuses))))
+	}
+
+	if len(filter.Priorities) > 0 {
+		q.where(fmt.Sprintf("priority = ANY(%s::task_priority[])", q.param("priorities", stringArray(filter.Priorities))))
+	}
+
+	if filter.AssigneeID != nil {
+		q.where(fmt.Sprintf("id IN (SELECT task_id FROM task_assignees WHERE user_id = %s)", q.param("assignee_id", *filter.AssigneeID)))
+	}
+
+	if len(filter.AssigneeIDs) > 0 {
+		// Подзапрос для фильтрации по любому из исполнителей
+		q.where(fmt.Sprintf("id IN (SELECT task_id FROM task_assignees WHERE user_id = ANY(%s::uuid[]))", q.param("assignee_ids", pq.Array(filter.AssigneeIDs))))
+	}
+
+	if filter.CreatedBy != nil {
+		q.where(fmt.Sprintf("created_by = %s", q.param("created_by", *filter.CreatedBy)))
+	}
+
+	if filter.DueBefore != nil {
+		q.where(fmt.Sprintf("due_date <= %s", q.param("due_before", *filter.DueBefore)))
+	}
+
+	if filter.DueAfter != nil {
+		q.where(fmt.Sprintf("due_date >= %s", q.param("due_after", *filter.DueAfter)))
+	}
+
+	if filter.CreatedBefore != nil {
+		q.where(fmt.Sprintf("created_at <= %s", q.param("created_before", *filter.CreatedBefore)))
+	}
+
+	if filter.CreatedAfter != nil {
+		q.where(fmt.Sprintf("created_at >= %s", q.param("created_after", *filter.CreatedAfter)))
+	}
 
-		if allowedFields[*filter.OrderBy] {
-			return fmt.Sprintf("ORDER BY %s %s", *filter.OrderBy, direction)
+	if filter.UpdatedBefore != nil {
+		q.where(fmt.Sprintf("updated_at <= %s", q.param("updated_before", *filter.UpdatedBefore)))
+	}
+
+	if filter.UpdatedAfter != nil {
+		q.where(fmt.Sprintf("updated_at >= %s", q.param("updated_after", *filter.UpdatedAfter)))
+	}
+
+	if filter.CompletedBefore != nil {
+		q.where(fmt.Sprintf("completed_at <= %s", q.param("completed_before", *filter.CompletedBefore)))
+	}
+
+	if filter.CompletedAfter != nil {
+		q.where(fmt.Sprintf("completed_at >= %s", q.param("completed_after", *filter.CompletedAfter)))
+	}
+
+	if filter.IsOverdue != nil && *filter.IsOverdue {
+		q.where(fmt.Sprintf("(due_date < %s AND status != 'completed')", q.param("now", time.Now())))
+	}
+
+	if len(filter.Tags) > 0 {
+		// Подзапрос для фильтрации по тегам: задача должна иметь все указанные теги
+		q.where(fmt.Sprintf("id IN (SELECT task_id FROM task_tags WHERE tag = ANY(%s::text[]) GROUP BY task_id HAVING COUNT(DISTINCT tag) = %s)",
+			q.param("tags", pq.Array(filter.Tags)), q.param("tag_count", len(filter.Tags))))
+	}
+
+	if filter.EpicID != nil {
+		q.where(fmt.Sprintf("epic_id = %s", q.param("epic_id", *filter.EpicID)))
+	}
+
+	if filter.SprintID != nil {
+		q.where(fmt.Sprintf("sprint_id = %s", q.param("sprint_id", *filter.SprintID)))
+	}
+
+	if filter.ParentID != nil {
+		q.where(fmt.Sprintf("parent_id = %s", q.param("parent_id", *filter.ParentID)))
+	}
+
+	if filter.SearchText != nil {
+		search := q.param("search", "%"+*filter.SearchText+"%")
+		q.where(fmt.Sprintf("(title ILIKE %s OR description ILIKE %s)", search, search))
+	}
+
+	return q
+}
+
+// taskSortColumns - поля, по которым допускается сортировка задач
+var taskSortColumns = map[string]bool{
+	"id":              true,
+	"title":           true,
+	"status":          true,
+	"priority":        true,
+	"assignee_id":     true,
+	"created_by":      true,
+	"due_date":        true,
+	"created_at":      true,
+	"updated_at":      true,
+	"completed_at":    true,
+	"estimated_hours": true,
+	"spent_hours":     true,
+	"priority_score":  true,
+	"rank":            true,
+}
+
+// buildOrderClause формирует сортировку по полям Sort или, если они не заданы, по OrderBy.
+// Поля не из списка допустимых пропускаются, поэтому в запрос попадают только известные колонки
+func (r *TaskRepository) buildOrderClause(filter repository.TaskFilter) string {
+	keys := filter.Sort
+	if len(keys) == 0 && filter.OrderBy != nil {
+		keys = []domain.SortKey{{
+			Field: *filter.OrderBy,
+			Desc:  filter.OrderDir != nil && strings.ToUpper(*filter.OrderDir) == "DESC",
+		}}
+	}
+
+	terms := make([]string, 0, len(keys)+1)
+	for _, key := range keys {
+		if !taskSortColumns[key.Field] {
+			continue
 		}
+		term := key.Field + " ASC"
+		if key.Desc {
+			term = key.Field + " DESC"
+		}
+		switch key.Nulls {
+		case domain.NullsFirst:
+			term += " NULLS FIRST"
+		case domain.NullsLast:
+			term += " NULLS LAST"
+		}
+		terms = append(terms, term)
+	}
+
+	// id делает порядок однозначным, чтобы постраничная выборка не теряла и не повторяла задачи
+	if len(terms) > 0 {
+		return "ORDER BY " + strings.Join(terms, ", ") + ", id"
 	}
 
-	// По умолчанию сортируем по приоритету и дате создания
-	return "ORDER BY priority DESC, created_at DESC"
+	// По умолчанию сортируем по оценке приоритета и дате создания
+	return "ORDER BY priority_score DESC, created_at DESC, id"
 }
@@ -0,0 +1,236 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/pkg/logger"
+)
+
+// taskTemplateColumns перечисляет колонки шаблона задачи для выборок
+const taskTemplateColumns = `
+	id, project_id, name, title, description, checklist, tags,
+	priority, assignee_id, estimated_hours, created_by, created_at, updated_at
+`
+
+// taskTemplateRow представляет строку таблицы task_templates
+type taskTemplateRow struct {
+	ID             string               `db:"id"`
+	ProjectID      string               `db:"project_id"`
+	Name           string               `db:"name"`
+	Title          string               `db:"title"`
+	Description    string               `db:"description"`
+	Checklist      pq.StringArray       `db:"checklist"`
+	Tags           pq.StringArray       `db:"tags"`
+	Priority       *domain.TaskPriority `db:"priority"`
+	AssigneeID     *string              `db:"assignee_id"`
+	EstimatedHours *float64             `db:"estimated_hours"`
+	CreatedBy      string               `db:"created_by"`
+	CreatedAt      time.Time            `db:"created_at"`
+	UpdatedAt      time.Time            `db:"updated_at"`
+}
+
+func (row *taskTemplateRow) toDomain() *domain.TaskTemplate {
+	return &domain.TaskTemplate{
+		ID:             row.ID,
+		ProjectID:      row.ProjectID,
+		Name:           row.Name,
+		Title:          row.Title,
+		Description:    row.Description,
+		Checklist:      []string(row.Checklist),
+		Tags:           []string(row.Tags),
+		Priority:       row.Priority,
+		AssigneeID:     row.AssigneeID,
+		EstimatedHours: row.EstimatedHours,
+		CreatedBy:      row.CreatedBy,
+		CreatedAt:      row.CreatedAt,
+		UpdatedAt:      row.UpdatedAt,
+	}
+}
+
+// TaskTemplateRepository реализует репозиторий шаблонов задач с использованием PostgreSQL
+type TaskTemplateRepository struct {
+	db     *sqlx.DB
+	logger logger.Logger
+}
+
+// NewTaskTemplateRepository создает новый экземпляр TaskTemplateRepository
+func NewTaskTemplateRepository(db *sqlx.DB, logger logger.Logger) *TaskTemplateRepository {
+	return &TaskTemplateRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Create создает новый шаблон
+func (r *TaskTemplateRepository) Create(ctx context.Context, template *domain.TaskTemplate) error {
+	query := `
+		INSERT INTO task_templates (
+			id, project_id, name, title, description, checklist, tags,
+			priority, assignee_id, estimated_hours, created_by, created_at, updated_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13
+		)
+	`
+
+	_, err := conn(ctx, r.db).ExecContext(
+		ctx,
+		query,
+		template.ID,
+		template.ProjectID,
+		template.Name,
+		template.Title,
+		template.Description,
+		pq.StringArray(template.Checklist),
+		pq.StringArray(template.Tags),
+		template.Priority,
+		template.AssigneeID,
+		template.EstimatedHours,
+		template.CreatedBy,
+		template.CreatedAt,
+		template.UpdatedAt,
+	)
+	if err != nil {
+		r.logger.Error("Failed to create task template", err, map[string]interface{}{
+			"project_id": template.ProjectID,
+			"name":       template.Name,
+		})
+		return fmt.Errorf("failed to create task template: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID возвращает шаблон по ID
+func (r *TaskTemplateRepository) GetByID(ctx context.Context, id string) (*domain.TaskTemplate, error) {
+	query := `SELECT ` + taskTemplateColumns + ` FROM task_templates WHERE id = $1`
+
+	var row taskTemplateRow
+	if err := conn(ctx, r.db).GetContext(ctx, &row, query, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		r.logger.Error("Failed to get task template by ID", err, map[string]interface{}{
+			"id": id,
+		})
+		return nil, fmt.Errorf("failed to get task template by ID: %w", err)
+	}
+
+	return row.toDomain(), nil
+}
+
+// GetByName возвращает шаблон проекта по имени
+func (r *TaskTemplateRepository) GetByName(ctx context.Context, projectID, name string) (*domain.TaskTemplate, error) {
+	query := `SELECT ` + taskTemplateColumns + ` FROM task_templates WHERE project_id = $1 AND name = $2`
+
+	var row taskTemplateRow
+	if err := conn(ctx, r.db).GetContext(ctx, &row, query, projectID, name); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		r.logger.Error("Failed to get task template by name", err, map[string]interface{}{
+			"project_id": projectID,
+			"name":       name,
+		})
+		return nil, fmt.Errorf("failed to get task template by name: %w", err)
+	}
+
+	return row.toDomain(), nil
+}
+
+// ListByProject возвращает шаблоны проекта, отсортированные по имени
+func (r *TaskTemplateRepository) ListByProject(ctx context.Context, projectID string) ([]*domain.TaskTemplate, error) {
+	query := `SELECT ` + taskTemplateColumns + ` FROM task_templates WHERE project_id = $1 ORDER BY name`
+
+	rows := []taskTemplateRow{}
+	if err := conn(ctx, r.db).SelectContext(ctx, &rows, query, projectID); err != nil {
+		r.logger.Error("Failed to list task templates", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return nil, fmt.Errorf("failed to list task templates: %w", err)
+	}
+
+	templates := make([]*domain.TaskTemplate, 0, len(rows))
+	for i := range rows {
+		templates = append(templates, rows[i].toDomain())
+	}
+
+	return templates, nil
+}
+
+// Update обновляет шаблон
+func (r *TaskTemplateRepository) Update(ctx context.Context, template *domain.TaskTemplate) error {
+	query := `
+		UPDATE task_templates
+		SET
+			name = $1,
+			title = $2,
+			description = $3,
+			checklist = $4,
+			tags = $5,
+			priority = $6,
+			assignee_id = $7,
+			estimated_hours = $8,
+			updated_at = $9
+		WHERE id = $10
+	`
+
+	result, err := conn(ctx, r.db).ExecContext(
+		ctx,
+		query,
+		template.Name,
+		template.Title,
+		template.Description,
+		pq.StringArray(template.Checklist),
+		pq.StringArray(template.Tags),
+		template.Priority,
+		template.AssigneeID,
+		template.EstimatedHours,
+		template.UpdatedAt,
+		template.ID,
+	)
+	if err != nil {
+		r.logger.Error("Failed to update task template", err, map[string]interface{}{
+			"id": template.ID,
+		})
+		return fmt.Errorf("failed to update task template: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("task template not found")
+	}
+
+	return nil
+}
+
+// Delete удаляет шаблон по ID
+func (r *TaskTemplateRepository) Delete(ctx context.Context, id string) error {
+	result, err := conn(ctx, r.db).ExecContext(ctx, `DELETE FROM task_templates WHERE id = $1`, id)
+	if err != nil {
+		r.logger.Error("Failed to delete task template", err, map[string]interface{}{
+			"id": id,
+		})
+		return fmt.Errorf("failed to delete task template: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("task template not found")
+	}
+
+	return nil
+}
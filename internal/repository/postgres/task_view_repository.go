@@ -0,0 +1,92 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/pkg/logger"
+)
+
+// TaskViewRepository реализует репозиторий просмотров задач с использованием PostgreSQL
+type TaskViewRepository struct {
+	db     *sqlx.DB
+	logger logger.Logger
+}
+
+// NewTaskViewRepository создает новый экземпляр TaskViewRepository
+func NewTaskViewRepository(db *sqlx.DB, logger logger.Logger) *TaskViewRepository {
+	return &TaskViewRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// SaveViews сохраняет просмотры одним запросом. Строки соединяются с задачами и пользователями,
+// чтобы просмотр задачи, удаленной до сохранения, не нарушал внешний ключ
+func (r *TaskViewRepository) SaveViews(ctx context.Context, views []*domain.TaskView) error {
+	if len(views) == 0 {
+		return nil
+	}
+
+	userIDs := make([]string, len(views))
+	taskIDs := make([]string, len(views))
+	viewedAt := make([]time.Time, len(views))
+	for i, view := range views {
+		userIDs[i] = view.UserID
+		taskIDs[i] = view.TaskID
+		viewedAt[i] = view.ViewedAt
+	}
+
+	query := `
+		INSERT INTO task_views (user_id, task_id, viewed_at)
+		SELECT v.user_id, v.task_id, MAX(v.viewed_at)
+		FROM unnest($1::uuid[], $2::uuid[], $3::timestamptz[]) AS v(user_id, task_id, viewed_at)
+		JOIN users u ON u.id = v.user_id
+		JOIN tasks t ON t.id = v.task_id
+		GROUP BY v.user_id, v.task_id
+		ON CONFLICT (user_id, task_id)
+		DO UPDATE SET viewed_at = GREATEST(task_views.viewed_at, EXCLUDED.viewed_at)
+	`
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query, pq.Array(userIDs), pq.Array(taskIDs), pq.Array(viewedAt)); err != nil {
+		r.logger.Error("Failed to save task views", err, map[string]interface{}{
+			"count": len(views),
+		})
+		return fmt.Errorf("failed to save task views: %w", err)
+	}
+
+	return nil
+}
+
+// GetViews возвращает время последнего просмотра задач пользователем
+func (r *TaskViewRepository) GetViews(ctx context.Context, userID string, taskIDs []string) (map[string]time.Time, error) {
+	views := make(map[string]time.Time, len(taskIDs))
+	if len(taskIDs) == 0 {
+		return views, nil
+	}
+
+	query := `
+		SELECT task_id, viewed_at
+		FROM task_views
+		WHERE user_id = $1 AND task_id = ANY($2)
+	`
+
+	var rows []*domain.TaskView
+	if err := conn(ctx, r.db).SelectContext(ctx, &rows, query, userID, pq.Array(taskIDs)); err != nil {
+		r.logger.Error("Failed to get task views", err, map[string]interface{}{
+			"user_id": userID,
+			"count":   len(taskIDs),
+		})
+		return nil, fmt.Errorf("failed to get task views: %w", err)
+	}
+
+	for _, row := range rows {
+		views[row.TaskID] = row.ViewedAt
+	}
+
+	return views, nil
+}
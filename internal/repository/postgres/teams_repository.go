@@ -0,0 +1,167 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/pkg/logger"
+)
+
+// projectTeamsChannelRow представляет строку таблицы project_teams_channels
+type projectTeamsChannelRow struct {
+	domain.ProjectTeamsChannel
+	Types pq.StringArray `db:"notification_types"`
+}
+
+// TeamsRepository реализует репозиторий вебхуков Microsoft Teams с использованием PostgreSQL
+type TeamsRepository struct {
+	db     *sqlx.DB
+	logger logger.Logger
+}
+
+// NewTeamsRepository создает новый экземпляр TeamsRepository
+func NewTeamsRepository(db *sqlx.DB, logger logger.Logger) *TeamsRepository {
+	return &TeamsRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// GetUserLink возвращает вебхук личного чата пользователя
+func (r *TeamsRepository) GetUserLink(ctx context.Context, userID string) (*domain.TeamsLink, error) {
+	query := `SELECT user_id, webhook_url, created_at, updated_at FROM user_teams_links WHERE user_id = $1`
+
+	var link domain.TeamsLink
+	if err := conn(ctx, r.db).GetContext(ctx, &link, query, userID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		r.logger.Error("Failed to get Teams link", err, map[string]interface{}{
+			"user_id": userID,
+		})
+		return nil, fmt.Errorf("failed to get Teams link: %w", err)
+	}
+
+	return &link, nil
+}
+
+// SaveUserLink создает или обновляет вебхук личного чата пользователя
+func (r *TeamsRepository) SaveUserLink(ctx context.Context, link *domain.TeamsLink) error {
+	query := `
+		INSERT INTO user_teams_links (user_id, webhook_url, created_at, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id) DO UPDATE SET
+			webhook_url = EXCLUDED.webhook_url,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query, link.UserID, link.WebhookURL, link.CreatedAt, link.UpdatedAt); err != nil {
+		r.logger.Error("Failed to save Teams link", err, map[string]interface{}{
+			"user_id": link.UserID,
+		})
+		return fmt.Errorf("failed to save Teams link: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteUserLink удаляет вебхук личного чата пользователя
+func (r *TeamsRepository) DeleteUserLink(ctx context.Context, userID string) error {
+	query := `DELETE FROM user_teams_links WHERE user_id = $1`
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query, userID); err != nil {
+		r.logger.Error("Failed to delete Teams link", err, map[string]interface{}{
+			"user_id": userID,
+		})
+		return fmt.Errorf("failed to delete Teams link: %w", err)
+	}
+
+	return nil
+}
+
+// GetProjectChannel возвращает канал проекта
+func (r *TeamsRepository) GetProjectChannel(ctx context.Context, projectID string) (*domain.ProjectTeamsChannel, error) {
+	query := `
+		SELECT project_id, webhook_url, enabled, notification_types, created_by, created_at, updated_at
+		FROM project_teams_channels
+		WHERE project_id = $1
+	`
+
+	var row projectTeamsChannelRow
+	if err := conn(ctx, r.db).GetContext(ctx, &row, query, projectID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		r.logger.Error("Failed to get project Teams channel", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return nil, fmt.Errorf("failed to get project Teams channel: %w", err)
+	}
+
+	channel := row.ProjectTeamsChannel
+	channel.NotificationTypes = make([]domain.NotificationType, 0, len(row.Types))
+	for _, notificationType := range row.Types {
+		channel.NotificationTypes = append(channel.NotificationTypes, domain.NotificationType(notificationType))
+	}
+
+	return &channel, nil
+}
+
+// SaveProjectChannel создает или обновляет канал проекта
+func (r *TeamsRepository) SaveProjectChannel(ctx context.Context, channel *domain.ProjectTeamsChannel) error {
+	query := `
+		INSERT INTO project_teams_channels (
+			project_id, webhook_url, enabled, notification_types, created_by, created_at, updated_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7
+		)
+		ON CONFLICT (project_id) DO UPDATE SET
+			webhook_url = EXCLUDED.webhook_url,
+			enabled = EXCLUDED.enabled,
+			notification_types = EXCLUDED.notification_types,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	types := make(pq.StringArray, 0, len(channel.NotificationTypes))
+	for _, notificationType := range channel.NotificationTypes {
+		types = append(types, string(notificationType))
+	}
+
+	_, err := conn(ctx, r.db).ExecContext(
+		ctx,
+		query,
+		channel.ProjectID,
+		channel.WebhookURL,
+		channel.Enabled,
+		types,
+		channel.CreatedBy,
+		channel.CreatedAt,
+		channel.UpdatedAt,
+	)
+	if err != nil {
+		r.logger.Error("Failed to save project Teams channel", err, map[string]interface{}{
+			"project_id": channel.ProjectID,
+		})
+		return fmt.Errorf("failed to save project Teams channel: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteProjectChannel удаляет канал проекта
+func (r *TeamsRepository) DeleteProjectChannel(ctx context.Context, projectID string) error {
+	query := `DELETE FROM project_teams_channels WHERE project_id = $1`
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query, projectID); err != nil {
+		r.logger.Error("Failed to delete project Teams channel", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return fmt.Errorf("failed to delete project Teams channel: %w", err)
+	}
+
+	return nil
+}
@@ -51,7 +51,7 @@ func (r *TelegramRepository) CreateOrUpdate(ctx context.Context, link *repositor
 
 	link.UpdatedAt = now.Format(time.RFC3339)
 
-	err := r.db.QueryRowxContext(
+	err := conn(ctx, r.db).QueryRowxContext(
 		ctx,
 		query,
 		link.UserID,
@@ -85,7 +85,7 @@ func (r *TelegramRepository) GetByUserID(ctx context.Context, userID string) (*r
 	`
 
 	var link repository.TelegramLink
-	err := r.db.GetContext(ctx, &link, query, userID)
+	err := conn(ctx, r.db).GetContext(ctx, &link, query, userID)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -109,7 +109,7 @@ func (r *TelegramRepository) GetByTelegramID(ctx context.Context, telegramID str
 	`
 
 	var link repository.TelegramLink
-	err := r.db.GetContext(ctx, &link, query, telegramID)
+	err := conn(ctx, r.db).GetContext(ctx, &link, query, telegramID)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -127,7 +127,7 @@ func (r *TelegramRepository) GetByTelegramID(ctx context.Context, telegramID str
 func (r *TelegramRepository) Delete(ctx context.Context, userID string) error {
 	query := `DELETE FROM user_telegram_links WHERE user_id = $1`
 
-	result, err := r.db.ExecContext(ctx, query, userID)
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, userID)
 	if err != nil {
 		r.logger.Error("Failed to delete telegram link", err, map[string]interface{}{
 			"user_id": userID,
@@ -158,7 +158,7 @@ func (r *TelegramRepository) List(ctx context.Context, limit, offset int) ([]*re
 	`
 
 	links := []*repository.TelegramLink{}
-	err := r.db.SelectContext(ctx, &links, query, limit, offset)
+	err := conn(ctx, r.db).SelectContext(ctx, &links, query, limit, offset)
 	if err != nil {
 		r.logger.Error("Failed to list telegram links", err, map[string]interface{}{
 			"limit":  limit,
@@ -175,7 +175,7 @@ func (r *TelegramRepository) Count(ctx context.Context) (int, error) {
 	query := `SELECT COUNT(*) FROM user_telegram_links`
 
 	var count int
-	err := r.db.GetContext(ctx, &count, query)
+	err := conn(ctx, r.db).GetContext(ctx, &count, query)
 	if err != nil {
 		r.logger.Error("Failed to count telegram links", err)
 		return 0, fmt.Errorf("failed to count telegram links: %w", err)
@@ -0,0 +1,119 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/nurlyy/task_manager/pkg/logger"
+)
+
+// txKey - ключ транзакции TxManager в контексте
+type txKey struct{}
+
+// queryer - общие методы *sqlx.DB и *sqlx.Tx, через которые репозитории выполняют запросы
+type queryer interface {
+	sqlx.ExtContext
+	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+}
+
+// TxManager реализует repository.TxManager: хранит транзакцию в контексте,
+// чтобы репозитории выполняли в ней свои запросы
+type TxManager struct {
+	db     *sqlx.DB
+	logger logger.Logger
+}
+
+// NewTxManager создает новый экземпляр TxManager
+func NewTxManager(db *sqlx.DB, logger logger.Logger) *TxManager {
+	return &TxManager{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// WithinTx выполняет fn в транзакции. Транзакция откатывается, если fn вернула ошибку
+// или завершилась паникой
+func (m *TxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	if _, ok := txFromContext(ctx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := m.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				m.logger.Error("Failed to rollback transaction", rbErr)
+			}
+			panic(p)
+		}
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				m.logger.Error("Failed to rollback transaction", rbErr)
+			}
+		}
+	}()
+
+	if err = fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// txFromContext возвращает транзакцию TxManager из контекста
+func txFromContext(ctx context.Context) (*sqlx.Tx, bool) {
+	tx, ok := ctx.Value(txKey{}).(*sqlx.Tx)
+	return tx, ok
+}
+
+// conn возвращает транзакцию из контекста или подключение к базе, если транзакции нет
+func conn(ctx context.Context, db *sqlx.DB) queryer {
+	if tx, ok := txFromContext(ctx); ok {
+		return tx
+	}
+	return db
+}
+
+// repoTx - транзакция метода репозитория. Если метод вызван внутри WithinTx, он выполняется
+// в транзакции из контекста, а ее фиксация и откат остаются за TxManager
+type repoTx struct {
+	*sqlx.Tx
+	joined bool
+}
+
+// beginTx начинает транзакцию метода репозитория или присоединяется к транзакции из контекста
+func beginTx(ctx context.Context, db *sqlx.DB) (*repoTx, error) {
+	if tx, ok := txFromContext(ctx); ok {
+		return &repoTx{Tx: tx, joined: true}, nil
+	}
+
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &repoTx{Tx: tx}, nil
+}
+
+// Commit фиксирует собственную транзакцию метода
+func (t *repoTx) Commit() error {
+	if t.joined {
+		return nil
+	}
+	return t.Tx.Commit()
+}
+
+// Rollback откатывает собственную транзакцию метода. Ошибка метода в общей транзакции
+// возвращается вызывающему коду, и транзакцию откатывает TxManager
+func (t *repoTx) Rollback() error {
+	if t.joined {
+		return nil
+	}
+	return t.Tx.Rollback()
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/nurlyy/task_manager/internal/domain"
 	"github.com/nurlyy/task_manager/internal/repository"
 	"github.com/nurlyy/task_manager/pkg/logger"
@@ -32,13 +33,13 @@ func (r *UserRepository) Create(ctx context.Context, user *domain.User) error {
 	query := `
 		INSERT INTO users (
 			id, email, hashed_password, first_name, last_name, role, 
-			avatar, position, department, is_active, created_at, updated_at
+			avatar, position, department, is_active, weekly_capacity_hours, manager_id, created_at, updated_at
 		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14
 		) RETURNING id
 	`
 
-	err := r.db.QueryRowxContext(
+	err := conn(ctx, r.db).QueryRowxContext(
 		ctx,
 		query,
 		user.ID,
@@ -51,6 +52,8 @@ func (r *UserRepository) Create(ctx context.Context, user *domain.User) error {
 		user.Position,
 		user.Department,
 		user.IsActive,
+		user.WeeklyCapacityHours,
+		user.ManagerID,
 		user.CreatedAt,
 		user.UpdatedAt,
 	).Scan(&user.ID)
@@ -70,13 +73,13 @@ func (r *UserRepository) GetByID(ctx context.Context, id string) (*domain.User,
 	query := `
 		SELECT 
 			id, email, hashed_password, first_name, last_name, role, 
-			avatar, position, department, is_active, last_login_at, created_at, updated_at
+			avatar, position, department, is_active, last_login_at, weekly_capacity_hours, manager_id, created_at, updated_at
 		FROM users 
 		WHERE id = $1
 	`
 
 	var user domain.User
-	err := r.db.GetContext(ctx, &user, query, id)
+	err := conn(ctx, r.db).GetContext(ctx, &user, query, id)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -90,18 +93,48 @@ func (r *UserRepository) GetByID(ctx context.Context, id string) (*domain.User,
 	return &user, nil
 }
 
+// GetByIDs возвращает пользователей по списку ID одним запросом
+func (r *UserRepository) GetByIDs(ctx context.Context, ids []string) (map[string]*domain.User, error) {
+	users := make(map[string]*domain.User, len(ids))
+	if len(ids) == 0 {
+		return users, nil
+	}
+
+	query := `
+		SELECT 
+			id, email, hashed_password, first_name, last_name, role, 
+			avatar, position, department, is_active, last_login_at, weekly_capacity_hours, manager_id, created_at, updated_at
+		FROM users 
+		WHERE id = ANY($1)
+	`
+
+	var rows []*domain.User
+	if err := conn(ctx, r.db).SelectContext(ctx, &rows, query, pq.Array(ids)); err != nil {
+		r.logger.Error("Failed to get users by IDs", err, map[string]interface{}{
+			"count": len(ids),
+		})
+		return nil, fmt.Errorf("failed to get users by IDs: %w", err)
+	}
+
+	for _, user := range rows {
+		users[user.ID] = user
+	}
+
+	return users, nil
+}
+
 // GetByEmail возвращает пользователя по email
 func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
 	query := `
 		SELECT 
 			id, email, hashed_password, first_name, last_name, role, 
-			avatar, position, department, is_active, last_login_at, created_at, updated_at
+			avatar, position, department, is_active, last_login_at, weekly_capacity_hours, manager_id, created_at, updated_at
 		FROM users 
 		WHERE email = $1
 	`
 
 	var user domain.User
-	err := r.db.GetContext(ctx, &user, query, email)
+	err := conn(ctx, r.db).GetContext(ctx, &user, query, email)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -128,13 +161,14 @@ func (r *UserRepository) Update(ctx context.Context, user *domain.User) error {
 			position = $6,
 			department = $7,
 			is_active = $8,
-			updated_at = $9
-		WHERE id = $10
+			weekly_capacity_hours = $9,
+			updated_at = $10
+		WHERE id = $11
 	`
 
 	user.UpdatedAt = time.Now()
 
-	result, err := r.db.ExecContext(
+	result, err := conn(ctx, r.db).ExecContext(
 		ctx,
 		query,
 		user.Email,
@@ -145,6 +179,7 @@ func (r *UserRepository) Update(ctx context.Context, user *domain.User) error {
 		user.Position,
 		user.Department,
 		user.IsActive,
+		user.WeeklyCapacityHours,
 		user.UpdatedAt,
 		user.ID,
 	)
@@ -172,7 +207,7 @@ func (r *UserRepository) Update(ctx context.Context, user *domain.User) error {
 func (r *UserRepository) Delete(ctx context.Context, id string) error {
 	query := `DELETE FROM users WHERE id = $1`
 
-	result, err := r.db.ExecContext(ctx, query, id)
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, id)
 	if err != nil {
 		r.logger.Error("Failed to delete user", err, map[string]interface{}{
 			"id": id,
@@ -201,7 +236,7 @@ func (r *UserRepository) List(ctx context.Context, filter repository.UserFilter)
 	query := fmt.Sprintf(`
 		SELECT 
 			id, email, hashed_password, first_name, last_name, role, 
-			avatar, position, department, is_active, last_login_at, created_at, updated_at
+			avatar, position, department, is_active, last_login_at, weekly_capacity_hours, manager_id, created_at, updated_at
 		FROM users
 		%s
 		%s
@@ -209,7 +244,7 @@ func (r *UserRepository) List(ctx context.Context, filter repository.UserFilter)
 	`, whereClause, orderClause, limitOffset)
 
 	users := []*domain.User{}
-	err := r.db.SelectContext(ctx, &users, query, args...)
+	err := conn(ctx, r.db).SelectContext(ctx, &users, query, args...)
 	if err != nil {
 		r.logger.Error("Failed to list users", err)
 		return nil, fmt.Errorf("failed to list users: %w", err)
@@ -229,7 +264,7 @@ func (r *UserRepository) Count(ctx context.Context, filter repository.UserFilter
 	`, whereClause)
 
 	var count int
-	err := r.db.GetContext(ctx, &count, query, args...)
+	err := conn(ctx, r.db).GetContext(ctx, &count, query, args...)
 	if err != nil {
 		r.logger.Error("Failed to count users", err)
 		return 0, fmt.Errorf("failed to count users: %w", err)
@@ -242,7 +277,7 @@ func (r *UserRepository) Count(ctx context.Context, filter repository.UserFilter
 func (r *UserRepository) UpdateLastLogin(ctx context.Context, id string) error {
 	query := `UPDATE users SET last_login_at = NOW() WHERE id = $1`
 
-	_, err := r.db.ExecContext(ctx, query, id)
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, id)
 	if err != nil {
 		r.logger.Error("Failed to update last login", err, map[string]interface{}{
 			"id": id,
@@ -253,8 +288,419 @@ func (r *UserRepository) UpdateLastLogin(ctx context.Context, id string) error {
 	return nil
 }
 
+// UpdateManager назначает или снимает руководителя пользователя
+func (r *UserRepository) UpdateManager(ctx context.Context, userID string, managerID *string) error {
+	query := `UPDATE users SET manager_id = $1, updated_at = NOW() WHERE id = $2`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, managerID, userID)
+	if err != nil {
+		r.logger.Error("Failed to update user manager", err, map[string]interface{}{
+			"id": userID,
+		})
+		return fmt.Errorf("failed to update user manager: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("user not found")
+	}
+
+	return nil
+}
+
+// GetReportingChain возвращает цепочку руководителей пользователя, начиная с непосредственного
+func (r *UserRepository) GetReportingChain(ctx context.Context, userID string) ([]*domain.User, error) {
+	query := fmt.Sprintf(`
+		WITH RECURSIVE chain AS (
+			SELECT u.manager_id, 1 AS level
+			FROM users u
+			WHERE u.id = $1 AND u.manager_id IS NOT NULL
+			UNION ALL
+			SELECT m.manager_id, c.level + 1
+			FROM chain c
+			JOIN users m ON m.id = c.manager_id
+			WHERE m.manager_id IS NOT NULL AND c.level < %d
+		)
+		SELECT
+			u.id, u.email, u.hashed_password, u.first_name, u.last_name, u.role,
+			u.avatar, u.position, u.department, u.is_active, u.last_login_at,
+			u.weekly_capacity_hours, u.manager_id, u.created_at, u.updated_at
+		FROM chain c
+		JOIN users u ON u.id = c.manager_id
+		ORDER BY c.level
+	`, maxReportingDepth)
+
+	users := []*domain.User{}
+	err := conn(ctx, r.db).SelectContext(ctx, &users, query, userID)
+	if err != nil {
+		r.logger.Error("Failed to get reporting chain", err, map[string]interface{}{
+			"user_id": userID,
+		})
+		return nil, fmt.Errorf("failed to get reporting chain: %w", err)
+	}
+
+	return users, nil
+}
+
+// GetReports возвращает подчиненных руководителя; при recursive = true - всех подчиненных по иерархии
+func (r *UserRepository) GetReports(ctx context.Context, managerID string, recursive bool) ([]*domain.User, error) {
+	maxLevel := 1
+	if recursive {
+		maxLevel = maxReportingDepth
+	}
+
+	query := `
+		WITH RECURSIVE reports AS (
+			SELECT u.id, 1 AS level
+			FROM users u
+			WHERE u.manager_id = $1
+			UNION ALL
+			SELECT u.id, r.level + 1
+			FROM reports r
+			JOIN users u ON u.manager_id = r.id
+			WHERE r.level < $2
+		)
+		SELECT
+			u.id, u.email, u.hashed_password, u.first_name, u.last_name, u.role,
+			u.avatar, u.position, u.department, u.is_active, u.last_login_at,
+			u.weekly_capacity_hours, u.manager_id, u.created_at, u.updated_at
+		FROM users u
+		WHERE u.id IN (SELECT id FROM reports)
+		ORDER BY u.last_name, u.first_name
+	`
+
+	users := []*domain.User{}
+	err := conn(ctx, r.db).SelectContext(ctx, &users, query, managerID, maxLevel)
+	if err != nil {
+		r.logger.Error("Failed to get user reports", err, map[string]interface{}{
+			"manager_id": managerID,
+		})
+		return nil, fmt.Errorf("failed to get user reports: %w", err)
+	}
+
+	return users, nil
+}
+
+// GetSkills возвращает навыки пользователя
+func (r *UserRepository) GetSkills(ctx context.Context, userID string) ([]string, error) {
+	query := `SELECT skill FROM user_skills WHERE user_id = $1 ORDER BY skill`
+
+	skills := []string{}
+	err := conn(ctx, r.db).SelectContext(ctx, &skills, query, userID)
+	if err != nil {
+		r.logger.Error("Failed to get user skills", err, map[string]interface{}{
+			"user_id": userID,
+		})
+		return nil, fmt.Errorf("failed to get user skills: %w", err)
+	}
+
+	return skills, nil
+}
+
+// GetSkillsByUserIDs возвращает навыки нескольких пользователей
+func (r *UserRepository) GetSkillsByUserIDs(ctx context.Context, userIDs []string) (map[string][]string, error) {
+	result := make(map[string][]string, len(userIDs))
+	if len(userIDs) == 0 {
+		return result, nil
+	}
+
+	placeholders := make([]string, len(userIDs))
+	args := make([]interface{}, len(userIDs))
+	for i, id := range userIDs {
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+		args[i] = id
+	}
+
+	query := fmt.Sprintf(`
+		SELECT user_id, skill
+		FROM user_skills
+		WHERE user_id IN (%s)
+		ORDER BY skill
+	`, strings.Join(placeholders, ", "))
+
+	var rows []struct {
+		UserID string `db:"user_id"`
+		Skill  string `db:"skill"`
+	}
+	if err := conn(ctx, r.db).SelectContext(ctx, &rows, query, args...); err != nil {
+		r.logger.Error("Failed to get skills for users", err, map[string]interface{}{
+			"count": len(userIDs),
+		})
+		return nil, fmt.Errorf("failed to get skills for users: %w", err)
+	}
+
+	for _, row := range rows {
+		result[row.UserID] = append(result[row.UserID], row.Skill)
+	}
+
+	return result, nil
+}
+
+// UpdateSkills обновляет навыки пользователя
+func (r *UserRepository) UpdateSkills(ctx context.Context, userID string, skills []string) error {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				r.logger.Error("Failed to rollback transaction", rbErr)
+			}
+			return
+		}
+	}()
+
+	// Удаляем все текущие навыки
+	if _, err = tx.ExecContext(ctx, "DELETE FROM user_skills WHERE user_id = $1", userID); err != nil {
+		r.logger.Error("Failed to delete user skills", err, map[string]interface{}{
+			"user_id": userID,
+		})
+		return fmt.Errorf("failed to delete user skills: %w", err)
+	}
+
+	// Добавляем новые навыки
+	for _, skill := range skills {
+		if _, err = tx.ExecContext(
+			ctx,
+			"INSERT INTO user_skills (user_id, skill) VALUES ($1, $2) ON CONFLICT DO NOTHING",
+			userID,
+			skill,
+		); err != nil {
+			r.logger.Error("Failed to add user skill", err, map[string]interface{}{
+				"user_id": userID,
+				"skill":   skill,
+			})
+			return fmt.Errorf("failed to add user skill: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// ListDirectory возвращает активных пользователей с их текущей загрузкой,
+// отсортированных по убыванию свободного времени
+func (r *UserRepository) ListDirectory(ctx context.Context, filter repository.UserDirectoryFilter) ([]*repository.UserWorkload, error) {
+	whereClause, args := r.buildDirectoryWhereClause(filter)
+	limitOffset := fmt.Sprintf("LIMIT %d OFFSET %d", filter.Limit, filter.Offset)
+
+	query := fmt.Sprintf(`
+		SELECT
+			u.id, u.email, u.hashed_password, u.first_name, u.last_name, u.role,
+			u.avatar, u.position, u.department, u.is_active, u.last_login_at,
+			u.weekly_capacity_hours, u.manager_id, u.created_at, u.updated_at,
+			COALESCE(w.open_tasks, 0) AS open_tasks,
+			COALESCE(w.workload_hours, 0) AS workload_hours
+		FROM users u
+		%s
+		%s
+		ORDER BY u.weekly_capacity_hours - COALESCE(w.workload_hours, 0) DESC, u.last_name, u.first_name
+		%s
+	`, directoryWorkloadJoin, whereClause, limitOffset)
+
+	users := []*repository.UserWorkload{}
+	err := conn(ctx, r.db).SelectContext(ctx, &users, query, args...)
+	if err != nil {
+		r.logger.Error("Failed to list user directory", err)
+		return nil, fmt.Errorf("failed to list user directory: %w", err)
+	}
+
+	return users, nil
+}
+
+// CountDirectory возвращает количество пользователей в справочнике с фильтрацией
+func (r *UserRepository) CountDirectory(ctx context.Context, filter repository.UserDirectoryFilter) (int, error) {
+	whereClause, args := r.buildDirectoryWhereClause(filter)
+
+	query := fmt.Sprintf(`
+		SELECT COUNT(*)
+		FROM users u
+		%s
+		%s
+	`, directoryWorkloadJoin, whereClause)
+
+	var count int
+	err := conn(ctx, r.db).GetContext(ctx, &count, query, args...)
+	if err != nil {
+		r.logger.Error("Failed to count user directory", err)
+		return 0, fmt.Errorf("failed to count user directory: %w", err)
+	}
+
+	return count, nil
+}
+
+// peopleCollaborationWindow - период, за который учитывается совместная работа над задачами
+const peopleCollaborationWindow = "90 days"
+
+// SearchPeople ищет активных пользователей по имени и email с нечетким совпадением (pg_trgm).
+// Без проекта в выдачу попадают только пользователи, состоящие хотя бы в одном общем
+// проекте с автором запроса. К оценке совпадения добавляется бонус за задачи, над которыми пользователь недавно
+// работал вместе с автором запроса: создавал, исполнял или комментировал
+func (r *UserRepository) SearchPeople(ctx context.Context, filter repository.UserSearchFilter) ([]*domain.UserBrief, error) {
+	query := `
+		WITH recent_tasks AS (
+			SELECT t.id
+			FROM tasks t
+			WHERE t.updated_at > NOW() - $5::interval
+			AND (
+				t.created_by = $2
+				OR EXISTS (SELECT 1 FROM task_assignees ta WHERE ta.task_id = t.id AND ta.user_id = $2)
+				OR EXISTS (SELECT 1 FROM comments c WHERE c.task_id = t.id AND c.user_id = $2)
+			)
+		),
+		collaborators AS (
+			SELECT p.user_id, COUNT(DISTINCT p.task_id) AS shared_tasks
+			FROM (
+				SELECT t.created_by AS user_id, t.id AS task_id
+				FROM tasks t JOIN recent_tasks rt ON rt.id = t.id
+				UNION ALL
+				SELECT ta.user_id, ta.task_id
+				FROM task_assignees ta JOIN recent_tasks rt ON rt.id = ta.task_id
+				UNION ALL
+				SELECT c.user_id, c.task_id
+				FROM comments c JOIN recent_tasks rt ON rt.id = c.task_id
+			) p
+			WHERE p.user_id <> $2
+			GROUP BY p.user_id
+		)
+		SELECT u.id, u.email, u.first_name, u.last_name, u.avatar
+		FROM users u
+		LEFT JOIN collaborators col ON col.user_id = u.id
+		WHERE u.is_active = TRUE
+		AND (
+			$1 <% (u.first_name || ' ' || u.last_name)
+			OR u.email % $1
+			OR strpos(lower(u.first_name || ' ' || u.last_name), $1) > 0
+			OR strpos(lower(u.email), $1) > 0
+		)
+		AND ($3::uuid IS NULL OR EXISTS (
+			SELECT 1 FROM project_members pm
+			WHERE pm.project_id = $3 AND pm.user_id = u.id
+		))
+		AND ($3::uuid IS NOT NULL OR EXISTS (
+			SELECT 1 FROM project_members pm
+			JOIN project_members own ON own.project_id = pm.project_id
+			WHERE pm.user_id = u.id AND own.user_id = $2
+		))
+		ORDER BY GREATEST(
+				word_similarity($1, u.first_name || ' ' || u.last_name),
+				similarity(u.first_name || ' ' || u.last_name, $1),
+				similarity(u.email, $1)
+			) + LEAST(COALESCE(col.shared_tasks, 0), 10) * 0.05 DESC,
+			u.last_name, u.first_name
+		LIMIT $4
+	`
+
+	var users []*domain.User
+	err := conn(ctx, r.db).SelectContext(ctx, &users, query, filter.Query, filter.RequesterID, filter.ProjectID, filter.Limit, peopleCollaborationWindow)
+	if err != nil {
+		r.logger.Error("Failed to search people", err, map[string]interface{}{
+			"query": filter.Query,
+		})
+		return nil, fmt.Errorf("failed to search people: %w", err)
+	}
+
+	result := make([]*domain.UserBrief, len(users))
+	for i, user := range users {
+		result[i] = &domain.UserBrief{
+			ID:        user.ID,
+			Email:     user.Email,
+			FirstName: user.FirstName,
+			LastName:  user.LastName,
+			Avatar:    user.Avatar,
+		}
+	}
+	return result, nil
+}
+
 // Вспомогательные функции для построения SQL-запросов
 
+// maxReportingDepth ограничивает глубину обхода оргструктуры
+const maxReportingDepth = 32
+
+// directoryWorkloadJoin присоединяет загрузку пользователя: количество незавершенных задач
+// и оставшиеся по ним оценочные часы
+const directoryWorkloadJoin = `
+		LEFT JOIN (
+			SELECT
+				ta.user_id AS assignee_id,
+				COUNT(*) AS open_tasks,
+				-- При заданной доле учитывается остаток доли исполнителя,
+				-- иначе остаток оценки задачи делится поровну между исполнителями
+				SUM(CASE
+					WHEN ta.estimated_hours IS NOT NULL
+						THEN GREATEST(ta.estimated_hours - COALESCE(tl.hours, 0), 0)
+					ELSE GREATEST(COALESCE(t.estimated_hours, 0) - COALESCE(t.spent_hours, 0), 0) / ac.assignees
+				END) AS workload_hours
+			FROM task_assignees ta
+			JOIN tasks t ON t.id = ta.task_id
+			JOIN (
+				SELECT task_id, COUNT(*) AS assignees FROM task_assignees GROUP BY task_id
+			) ac ON ac.task_id = ta.task_id
+			LEFT JOIN (
+				SELECT task_id, user_id, SUM(hours) AS hours FROM time_logs GROUP BY task_id, user_id
+			) tl ON tl.task_id = ta.task_id AND tl.user_id = ta.user_id
+			WHERE t.status NOT IN ('completed', 'cancelled')
+			GROUP BY ta.user_id
+		) w ON w.assignee_id = u.id
+`
+
+func (r *UserRepository) buildDirectoryWhereClause(filter repository.UserDirectoryFilter) (string, []interface{}) {
+	conditions := []string{"u.is_active = TRUE"}
+	args := []interface{}{}
+	argIndex := 1
+
+	if len(filter.Skills) > 0 {
+		placeholders := make([]string, len(filter.Skills))
+		for i, skill := range filter.Skills {
+			placeholders[i] = fmt.Sprintf("$%d", argIndex)
+			args = append(args, skill)
+			argIndex++
+		}
+		// Пользователь должен обладать всеми указанными навыками
+		conditions = append(conditions, fmt.Sprintf(
+			"(SELECT COUNT(*) FROM user_skills us WHERE us.user_id = u.id AND us.skill IN (%s)) = %d",
+			strings.Join(placeholders, ", "), len(filter.Skills),
+		))
+	}
+
+	if filter.MinFreeHours != nil {
+		conditions = append(conditions, fmt.Sprintf("u.weekly_capacity_hours - COALESCE(w.workload_hours, 0) >= $%d", argIndex))
+		args = append(args, *filter.MinFreeHours)
+		argIndex++
+	}
+
+	if filter.Role != nil {
+		conditions = append(conditions, fmt.Sprintf("u.role = $%d", argIndex))
+		args = append(args, *filter.Role)
+		argIndex++
+	}
+
+	if filter.Department != nil {
+		conditions = append(conditions, fmt.Sprintf("u.department = $%d", argIndex))
+		args = append(args, *filter.Department)
+		argIndex++
+	}
+
+	if filter.SearchText != nil {
+		conditions = append(conditions, fmt.Sprintf("(u.first_name ILIKE $%d OR u.last_name ILIKE $%d OR u.email ILIKE $%d)", argIndex, argIndex, argIndex))
+		searchPattern := "%" + *filter.SearchText + "%"
+		args = append(args, searchPattern)
+		argIndex++
+	}
+
+	return "WHERE " + strings.Join(conditions, " AND "), args
+}
+
+
 func (r *UserRepository) buildWhereClause(filter repository.UserFilter) (string, []interface{}) {
 	conditions := []string{}
 	args := []interface{}{}
@@ -301,6 +747,12 @@ func (r *UserRepository) buildWhereClause(filter repository.UserFilter) (string,
 		argIndex++
 	}
 
+	if filter.AfterID != nil {
+		conditions = append(conditions, fmt.Sprintf("id > $%d", argIndex))
+		args = append(args, *filter.AfterID)
+		argIndex++
+	}
+
 	if len(conditions) > 0 {
 		return "WHERE " + strings.Join(conditions, " AND "), args
 	}
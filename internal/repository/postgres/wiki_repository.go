@@ -0,0 +1,437 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/pkg/logger"
+)
+
+// wikiPageRow представляет строку таблицы wiki_pages вместе со связанными задачами
+type wikiPageRow struct {
+	domain.WikiPage
+	TaskIDArray pq.StringArray `db:"task_ids"`
+}
+
+// WikiRepository реализует репозиторий вики проектов с использованием PostgreSQL
+type WikiRepository struct {
+	db     *sqlx.DB
+	logger logger.Logger
+}
+
+// NewWikiRepository создает новый экземпляр WikiRepository
+func NewWikiRepository(db *sqlx.DB, logger logger.Logger) *WikiRepository {
+	return &WikiRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Create сохраняет страницу, ее первую ревизию и связи с задачами
+func (r *WikiRepository) Create(ctx context.Context, page *domain.WikiPage) error {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				r.logger.Error("Failed to rollback transaction", rbErr)
+			}
+		}
+	}()
+
+	query := `
+		INSERT INTO wiki_pages (
+			id, project_id, parent_id, title, content, version,
+			created_by, updated_by, created_at, updated_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+		)
+	`
+
+	if _, err = tx.ExecContext(
+		ctx,
+		query,
+		page.ID,
+		page.ProjectID,
+		page.ParentID,
+		page.Title,
+		page.Content,
+		page.Version,
+		page.CreatedBy,
+		page.UpdatedBy,
+		page.CreatedAt,
+		page.UpdatedAt,
+	); err != nil {
+		r.logger.Error("Failed to create wiki page", err, map[string]interface{}{
+			"project_id": page.ProjectID,
+		})
+		return fmt.Errorf("failed to create wiki page: %w", err)
+	}
+
+	if err = r.insertRevision(ctx, tx, page); err != nil {
+		return err
+	}
+
+	for _, taskID := range page.TaskIDs {
+		if _, err = tx.ExecContext(
+			ctx,
+			"INSERT INTO wiki_page_tasks (page_id, task_id, created_by, created_at) VALUES ($1, $2, $3, $4) ON CONFLICT DO NOTHING",
+			page.ID,
+			taskID,
+			page.CreatedBy,
+			page.CreatedAt,
+		); err != nil {
+			r.logger.Error("Failed to link wiki page task", err, map[string]interface{}{
+				"page_id": page.ID,
+				"task_id": taskID,
+			})
+			return fmt.Errorf("failed to link wiki page task: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID возвращает страницу по ID
+func (r *WikiRepository) GetByID(ctx context.Context, id string) (*domain.WikiPage, error) {
+	query := `
+		SELECT
+			w.id, w.project_id, w.parent_id, w.title, w.content, w.version,
+			w.created_by, w.updated_by, w.created_at, w.updated_at,
+			ARRAY(SELECT wt.task_id::text FROM wiki_page_tasks wt WHERE wt.page_id = w.id ORDER BY wt.created_at) AS task_ids
+		FROM wiki_pages w
+		WHERE w.id = $1
+	`
+
+	var row wikiPageRow
+	if err := conn(ctx, r.db).GetContext(ctx, &row, query, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		r.logger.Error("Failed to get wiki page", err, map[string]interface{}{
+			"id": id,
+		})
+		return nil, fmt.Errorf("failed to get wiki page: %w", err)
+	}
+
+	page := row.WikiPage
+	page.TaskIDs = []string(row.TaskIDArray)
+	if page.TaskIDs == nil {
+		page.TaskIDs = []string{}
+	}
+
+	return &page, nil
+}
+
+// Update сохраняет правку страницы и ее ревизию, если текущая версия равна expectedVersion
+func (r *WikiRepository) Update(ctx context.Context, page *domain.WikiPage, expectedVersion int) (updated bool, err error) {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil || !updated {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				r.logger.Error("Failed to rollback transaction", rbErr)
+			}
+		}
+	}()
+
+	query := `
+		UPDATE wiki_pages
+		SET parent_id = $1, title = $2, content = $3, version = $4, updated_by = $5, updated_at = $6
+		WHERE id = $7 AND version = $8
+	`
+
+	result, err := tx.ExecContext(
+		ctx,
+		query,
+		page.ParentID,
+		page.Title,
+		page.Content,
+		page.Version,
+		page.UpdatedBy,
+		page.UpdatedAt,
+		page.ID,
+		expectedVersion,
+	)
+	if err != nil {
+		r.logger.Error("Failed to update wiki page", err, map[string]interface{}{
+			"id": page.ID,
+		})
+		return false, fmt.Errorf("failed to update wiki page: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return false, nil
+	}
+
+	if err = r.insertRevision(ctx, tx, page); err != nil {
+		return false, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return true, nil
+}
+
+// Delete удаляет страницу; дочерние страницы переходят к ее родителю
+func (r *WikiRepository) Delete(ctx context.Context, id string) error {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				r.logger.Error("Failed to rollback transaction", rbErr)
+			}
+		}
+	}()
+
+	if _, err = tx.ExecContext(
+		ctx,
+		"UPDATE wiki_pages SET parent_id = (SELECT parent_id FROM wiki_pages WHERE id = $1) WHERE parent_id = $1",
+		id,
+	); err != nil {
+		r.logger.Error("Failed to reparent wiki pages", err, map[string]interface{}{
+			"id": id,
+		})
+		return fmt.Errorf("failed to reparent wiki pages: %w", err)
+	}
+
+	if _, err = tx.ExecContext(ctx, "DELETE FROM wiki_pages WHERE id = $1", id); err != nil {
+		r.logger.Error("Failed to delete wiki page", err, map[string]interface{}{
+			"id": id,
+		})
+		return fmt.Errorf("failed to delete wiki page: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// ListByProject возвращает все страницы проекта без содержимого
+func (r *WikiRepository) ListByProject(ctx context.Context, projectID string) ([]*domain.WikiPageNode, error) {
+	query := `
+		SELECT id, parent_id, title, version, updated_at
+		FROM wiki_pages
+		WHERE project_id = $1
+		ORDER BY title
+	`
+
+	pages := []*domain.WikiPageNode{}
+	if err := conn(ctx, r.db).SelectContext(ctx, &pages, query, projectID); err != nil {
+		r.logger.Error("Failed to list wiki pages", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return nil, fmt.Errorf("failed to list wiki pages: %w", err)
+	}
+
+	return pages, nil
+}
+
+// Search выполняет полнотекстовый поиск по страницам проекта.
+// Выражение to_tsvector совпадает с индексом idx_wiki_pages_search
+func (r *WikiRepository) Search(ctx context.Context, projectID string, query string, pattern string, limit int) ([]*domain.WikiSearchResult, error) {
+	sqlQuery := `
+		SELECT
+			w.id, w.parent_id, w.title, w.updated_at,
+			ts_headline('russian', w.content, plainto_tsquery('russian', $2), 'MaxFragments=1, MaxWords=30, MinWords=10') AS snippet
+		FROM wiki_pages w
+		WHERE w.project_id = $1
+		AND (
+			to_tsvector('russian', w.title || ' ' || w.content) @@ plainto_tsquery('russian', $2)
+			OR w.title ILIKE '%' || $3 || '%'
+		)
+		ORDER BY (w.title ILIKE $3 || '%') DESC,
+			ts_rank(to_tsvector('russian', w.title || ' ' || w.content), plainto_tsquery('russian', $2)) DESC,
+			w.updated_at DESC
+		LIMIT $4
+	`
+
+	results := []*domain.WikiSearchResult{}
+	if err := conn(ctx, r.db).SelectContext(ctx, &results, sqlQuery, projectID, query, pattern, limit); err != nil {
+		r.logger.Error("Failed to search wiki pages", err, map[string]interface{}{
+			"project_id": projectID,
+			"query":      query,
+		})
+		return nil, fmt.Errorf("failed to search wiki pages: %w", err)
+	}
+
+	return results, nil
+}
+
+// GetAncestorIDs возвращает ID всех предков страницы
+func (r *WikiRepository) GetAncestorIDs(ctx context.Context, id string) ([]string, error) {
+	query := `
+		WITH RECURSIVE ancestors AS (
+			SELECT parent_id FROM wiki_pages WHERE id = $1
+			UNION
+			SELECT w.parent_id
+			FROM wiki_pages w
+			JOIN ancestors a ON w.id = a.parent_id
+		)
+		SELECT parent_id::text FROM ancestors WHERE parent_id IS NOT NULL
+	`
+
+	ids := []string{}
+	if err := conn(ctx, r.db).SelectContext(ctx, &ids, query, id); err != nil {
+		r.logger.Error("Failed to get wiki page ancestors", err, map[string]interface{}{
+			"id": id,
+		})
+		return nil, fmt.Errorf("failed to get wiki page ancestors: %w", err)
+	}
+
+	return ids, nil
+}
+
+// ListRevisions возвращает ревизии страницы без содержимого, сначала последние
+func (r *WikiRepository) ListRevisions(ctx context.Context, pageID string) ([]*domain.WikiPageRevision, error) {
+	query := `
+		SELECT id, page_id, version, title, edited_by, created_at
+		FROM wiki_page_revisions
+		WHERE page_id = $1
+		ORDER BY version DESC
+	`
+
+	revisions := []*domain.WikiPageRevision{}
+	if err := conn(ctx, r.db).SelectContext(ctx, &revisions, query, pageID); err != nil {
+		r.logger.Error("Failed to list wiki page revisions", err, map[string]interface{}{
+			"page_id": pageID,
+		})
+		return nil, fmt.Errorf("failed to list wiki page revisions: %w", err)
+	}
+
+	return revisions, nil
+}
+
+// GetRevision возвращает ревизию страницы
+func (r *WikiRepository) GetRevision(ctx context.Context, pageID string, version int) (*domain.WikiPageRevision, error) {
+	query := `
+		SELECT id, page_id, version, title, content, edited_by, created_at
+		FROM wiki_page_revisions
+		WHERE page_id = $1 AND version = $2
+	`
+
+	var revision domain.WikiPageRevision
+	if err := conn(ctx, r.db).GetContext(ctx, &revision, query, pageID, version); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		r.logger.Error("Failed to get wiki page revision", err, map[string]interface{}{
+			"page_id": pageID,
+			"version": version,
+		})
+		return nil, fmt.Errorf("failed to get wiki page revision: %w", err)
+	}
+
+	return &revision, nil
+}
+
+// ListByTask возвращает страницы, связанные с задачей
+func (r *WikiRepository) ListByTask(ctx context.Context, taskID string) ([]*domain.WikiPageNode, error) {
+	query := `
+		SELECT w.id, w.parent_id, w.title, w.version, w.updated_at
+		FROM wiki_pages w
+		JOIN wiki_page_tasks wt ON wt.page_id = w.id
+		WHERE wt.task_id = $1
+		ORDER BY w.title
+	`
+
+	pages := []*domain.WikiPageNode{}
+	if err := conn(ctx, r.db).SelectContext(ctx, &pages, query, taskID); err != nil {
+		r.logger.Error("Failed to list task wiki pages", err, map[string]interface{}{
+			"task_id": taskID,
+		})
+		return nil, fmt.Errorf("failed to list task wiki pages: %w", err)
+	}
+
+	return pages, nil
+}
+
+// LinkTask связывает страницу с задачей
+func (r *WikiRepository) LinkTask(ctx context.Context, pageID, taskID, userID string) (bool, error) {
+	query := `
+		INSERT INTO wiki_page_tasks (page_id, task_id, created_by, created_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT DO NOTHING
+	`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, pageID, taskID, userID)
+	if err != nil {
+		r.logger.Error("Failed to link wiki page task", err, map[string]interface{}{
+			"page_id": pageID,
+			"task_id": taskID,
+		})
+		return false, fmt.Errorf("failed to link wiki page task: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// UnlinkTask удаляет связь страницы с задачей
+func (r *WikiRepository) UnlinkTask(ctx context.Context, pageID, taskID string) error {
+	query := `DELETE FROM wiki_page_tasks WHERE page_id = $1 AND task_id = $2`
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query, pageID, taskID); err != nil {
+		r.logger.Error("Failed to unlink wiki page task", err, map[string]interface{}{
+			"page_id": pageID,
+			"task_id": taskID,
+		})
+		return fmt.Errorf("failed to unlink wiki page task: %w", err)
+	}
+
+	return nil
+}
+
+// insertRevision сохраняет снимок текущей версии страницы
+func (r *WikiRepository) insertRevision(ctx context.Context, tx queryer, page *domain.WikiPage) error {
+	query := `
+		INSERT INTO wiki_page_revisions (page_id, version, title, content, edited_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	if _, err := tx.ExecContext(
+		ctx,
+		query,
+		page.ID,
+		page.Version,
+		page.Title,
+		page.Content,
+		page.UpdatedBy,
+		page.UpdatedAt,
+	); err != nil {
+		r.logger.Error("Failed to create wiki page revision", err, map[string]interface{}{
+			"page_id": page.ID,
+			"version": page.Version,
+		})
+		return fmt.Errorf("failed to create wiki page revision: %w", err)
+	}
+
+	return nil
+}
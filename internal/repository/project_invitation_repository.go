@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+)
+
+// ProjectInvitationRepository определяет интерфейс для работы с приглашениями в проекты
+type ProjectInvitationRepository interface {
+	// SaveInvitation создает приглашение или обновляет неиспользованное приглашение
+	// того же email в тот же проект: роль, токен и срок действия заменяются
+	SaveInvitation(ctx context.Context, invitation *domain.ProjectInvitation) error
+
+	// GetInvitationByToken возвращает приглашение по хешу токена (nil, если оно не найдено)
+	GetInvitationByToken(ctx context.Context, tokenHash string) (*domain.ProjectInvitation, error)
+
+	// MarkInvitationAccepted отмечает приглашение принятым
+	MarkInvitationAccepted(ctx context.Context, id string, acceptedAt time.Time) error
+}
@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+)
+
+// ProjectMetricsRepository определяет интерфейс для работы с материализованными метриками проектов
+type ProjectMetricsRepository interface {
+	// Get возвращает сохраненные метрики проекта (nil, если метрики еще не рассчитывались)
+	Get(ctx context.Context, projectID string) (*domain.ProjectMetrics, error)
+
+	// ClearDirty снимает пометку устаревания перед пересчетом метрик. Изменения задач,
+	// зафиксированные после этого, снова пометят метрики как устаревшие
+	ClearDirty(ctx context.Context, projectID string) error
+
+	// Save сохраняет рассчитанные метрики проекта
+	Save(ctx context.Context, projectID string, metrics *domain.ProjectMetrics) error
+
+	// ListDirty возвращает ID проектов с устаревшими метриками
+	ListDirty(ctx context.Context, limit int) ([]string, error)
+
+	// MarkOverdueDirty помечает устаревшими метрики проектов, в которых после пересчета
+	// наступил срок незавершенных задач, и возвращает количество таких проектов
+	MarkOverdueDirty(ctx context.Context) (int, error)
+
+	// SaveSnapshots сохраняет снимки текущих метрик всех проектов за указанную дату
+	// и возвращает их количество
+	SaveSnapshots(ctx context.Context, date time.Time) (int, error)
+
+	// GetHistory возвращает снимки метрик проекта начиная с указанной даты
+	GetHistory(ctx context.Context, projectID string, from time.Time) ([]*domain.ProjectMetricsSnapshot, error)
+}
@@ -14,6 +14,9 @@ type UserRepository interface {
 	// GetByID возвращает пользователя по ID
 	GetByID(ctx context.Context, id string) (*domain.User, error)
 
+	// GetByIDs возвращает пользователей по списку ID одним запросом; отсутствующие пропускаются
+	GetByIDs(ctx context.Context, ids []string) (map[string]*domain.User, error)
+
 	// GetByEmail возвращает пользователя по email
 	GetByEmail(ctx context.Context, email string) (*domain.User, error)
 
@@ -31,6 +34,60 @@ type UserRepository interface {
 
 	// UpdateLastLogin обновляет время последнего входа пользователя
 	UpdateLastLogin(ctx context.Context, id string) error
+
+	// UpdateManager назначает или снимает руководителя пользователя
+	UpdateManager(ctx context.Context, userID string, managerID *string) error
+
+	// GetReportingChain возвращает цепочку руководителей пользователя, начиная с непосредственного
+	GetReportingChain(ctx context.Context, userID string) ([]*domain.User, error)
+
+	// GetReports возвращает подчиненных руководителя
+	GetReports(ctx context.Context, managerID string, recursive bool) ([]*domain.User, error)
+
+	// GetSkills возвращает навыки пользователя
+	GetSkills(ctx context.Context, userID string) ([]string, error)
+
+	// GetSkillsByUserIDs возвращает навыки нескольких пользователей
+	GetSkillsByUserIDs(ctx context.Context, userIDs []string) (map[string][]string, error)
+
+	// UpdateSkills обновляет навыки пользователя
+	UpdateSkills(ctx context.Context, userID string, skills []string) error
+
+	// ListDirectory возвращает активных пользователей с их текущей загрузкой
+	ListDirectory(ctx context.Context, filter UserDirectoryFilter) ([]*UserWorkload, error)
+
+	// CountDirectory возвращает количество пользователей в справочнике с фильтрацией
+	CountDirectory(ctx context.Context, filter UserDirectoryFilter) (int, error)
+
+	// SearchPeople ищет активных пользователей по имени и email с нечетким совпадением.
+	// Результаты упорядочены по качеству совпадения и недавней совместной работе
+	SearchPeople(ctx context.Context, filter UserSearchFilter) ([]*domain.UserBrief, error)
+}
+
+// UserWorkload представляет пользователя с текущей загрузкой по незавершенным задачам
+type UserWorkload struct {
+	domain.User
+	OpenTasks     int     `db:"open_tasks"`
+	WorkloadHours float64 `db:"workload_hours"`
+}
+
+// UserDirectoryFilter содержит параметры для поиска пользователей в справочнике
+type UserDirectoryFilter struct {
+	Skills       []string         `json:"skills,omitempty"`
+	MinFreeHours *float64         `json:"min_free_hours,omitempty"`
+	Role         *domain.UserRole `json:"role,omitempty"`
+	Department   *string          `json:"department,omitempty"`
+	SearchText   *string          `json:"search_text,omitempty"`
+	Limit        int              `json:"limit"`
+	Offset       int              `json:"offset"`
+}
+
+// UserSearchFilter содержит параметры поиска пользователей для выбора исполнителей и участников
+type UserSearchFilter struct {
+	Query       string  // Нормализованная поисковая строка
+	RequesterID string  // Пользователь, совместная работа с которым поднимает результат выше
+	ProjectID   *string // Если задан, ищутся только участники проекта
+	Limit       int
 }
 
 // UserFilter содержит параметры для фильтрации пользователей
@@ -41,6 +98,7 @@ type UserFilter struct {
 	IsActive   *bool           `json:"is_active,omitempty"`
 	Department *string         `json:"department,omitempty"`
 	SearchText *string         `json:"search_text,omitempty"`
+	AfterID    *string         `json:"after_id,omitempty"` // Только пользователи с ID больше указанного, для постраничного обхода с сортировкой по id
 	OrderBy    *string         `json:"order_by,omitempty"`
 	OrderDir   *string         `json:"order_dir,omitempty"`
 	Limit      int             `json:"limit"`
@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+)
+
+// ProjectViewRepository определяет интерфейс для работы с сохраненными представлениями проектов
+type ProjectViewRepository interface {
+	// Create создает представление. Роли по умолчанию снимаются с других представлений проекта
+	Create(ctx context.Context, view *domain.ProjectView) error
+
+	// GetByID возвращает представление по ID (nil, если представление не найдено)
+	GetByID(ctx context.Context, id string) (*domain.ProjectView, error)
+
+	// GetByName возвращает представление проекта по имени (nil, если представление не найдено)
+	GetByName(ctx context.Context, projectID, name string) (*domain.ProjectView, error)
+
+	// GetDefaultForRole возвращает представление проекта по умолчанию для роли (nil, если не назначено)
+	GetDefaultForRole(ctx context.Context, projectID string, role domain.ProjectRole) (*domain.ProjectView, error)
+
+	// ListByProject возвращает представления проекта, отсортированные по имени
+	ListByProject(ctx context.Context, projectID string) ([]*domain.ProjectView, error)
+
+	// Update обновляет представление. Роли по умолчанию снимаются с других представлений проекта
+	Update(ctx context.Context, view *domain.ProjectView) error
+
+	// Delete удаляет представление по ID
+	Delete(ctx context.Context, id string) error
+}
@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+)
+
+// ProjectWebhookRepository определяет интерфейс для работы с входящими вебхуками проектов
+type ProjectWebhookRepository interface {
+	// Create создает вебхук
+	Create(ctx context.Context, webhook *domain.ProjectWebhook) error
+
+	// GetByID возвращает вебхук по ID (nil, если вебхук не найден)
+	GetByID(ctx context.Context, id string) (*domain.ProjectWebhook, error)
+
+	// GetByName возвращает вебхук проекта по имени (nil, если вебхук не найден)
+	GetByName(ctx context.Context, projectID, name string) (*domain.ProjectWebhook, error)
+
+	// ListByProject возвращает вебхуки проекта
+	ListByProject(ctx context.Context, projectID string) ([]*domain.ProjectWebhook, error)
+
+	// Update обновляет вебхук
+	Update(ctx context.Context, webhook *domain.ProjectWebhook) error
+
+	// Delete удаляет вебхук
+	Delete(ctx context.Context, id string) error
+}
@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+)
+
+// RoadmapRepository определяет интерфейс для работы с публичными дорожными картами проектов
+type RoadmapRepository interface {
+	// GetRoadmap возвращает дорожную карту проекта (nil, если она не настроена)
+	GetRoadmap(ctx context.Context, projectID string) (*domain.ProjectRoadmap, error)
+
+	// GetRoadmapByKey возвращает дорожную карту по публичному ключу (nil, если она не найдена)
+	GetRoadmapByKey(ctx context.Context, publicKey string) (*domain.ProjectRoadmap, error)
+
+	// SaveRoadmap создает или обновляет дорожную карту проекта
+	SaveRoadmap(ctx context.Context, roadmap *domain.ProjectRoadmap) error
+
+	// DeleteRoadmap удаляет дорожную карту проекта
+	DeleteRoadmap(ctx context.Context, projectID string) error
+
+	// ListRoadmapTasks возвращает неотмененные задачи проекта с указанным тегом
+	ListRoadmapTasks(ctx context.Context, projectID string, label string, limit int) ([]*domain.RoadmapTask, error)
+}
@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+)
+
+// ScheduledTaskRepository определяет интерфейс для работы с отложенным созданием задач
+type ScheduledTaskRepository interface {
+	// Create сохраняет запланированную задачу
+	Create(ctx context.Context, scheduled *domain.ScheduledTask) error
+
+	// GetByID возвращает запланированную задачу по ID
+	GetByID(ctx context.Context, id string) (*domain.ScheduledTask, error)
+
+	// ListByProject возвращает запланированные задачи проекта, при status != nil только с этим статусом
+	ListByProject(ctx context.Context, projectID string, status *domain.ScheduledTaskStatus) ([]*domain.ScheduledTask, error)
+
+	// UpdatePending обновляет данные и статус запланированной задачи, пока она ожидает создания;
+	// возвращает false, если задача уже обработана планировщиком или отменена
+	UpdatePending(ctx context.Context, scheduled *domain.ScheduledTask) (bool, error)
+
+	// ClaimDue переводит в обработку ожидающие задачи, время создания которых наступило, и возвращает их
+	ClaimDue(ctx context.Context, now time.Time, limit int) ([]*domain.ScheduledTask, error)
+
+	// Release возвращает задачу из обработки в ожидание, чтобы планировщик повторил попытку
+	Release(ctx context.Context, id string, errMsg string) error
+
+	// Complete фиксирует результат обработки запланированной задачи
+	Complete(ctx context.Context, id string, status domain.ScheduledTaskStatus, taskID *string, errMsg *string, processedAt time.Time) error
+}
@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+)
+
+// SearchRepository определяет интерфейс для быстрого поиска по сущностям
+type SearchRepository interface {
+	// SearchTasks возвращает задачи, доступные пользователю, название которых совпадает с запросом
+	SearchTasks(ctx context.Context, query string, userID string, allProjects bool, limit int) ([]*domain.TypeaheadItem, error)
+
+	// SearchProjects возвращает проекты, доступные пользователю, название которых совпадает с запросом
+	SearchProjects(ctx context.Context, query string, userID string, allProjects bool, limit int) ([]*domain.TypeaheadItem, error)
+
+	// SearchUsers возвращает активных пользователей из общих с пользователем проектов,
+	// имя или email которых совпадает с запросом
+	SearchUsers(ctx context.Context, query string, userID string, allProjects bool, limit int) ([]*domain.TypeaheadItem, error)
+
+	// SearchDecisions возвращает решения из журналов доступных пользователю проектов, совпадающие с запросом
+	SearchDecisions(ctx context.Context, query string, userID string, allProjects bool, limit int) ([]*domain.TypeaheadItem, error)
+
+	// SearchWikiPages возвращает вики-страницы доступных пользователю проектов, совпадающие с запросом
+	SearchWikiPages(ctx context.Context, query string, userID string, allProjects bool, limit int) ([]*domain.TypeaheadItem, error)
+}
@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+)
+
+// SMSRepository определяет интерфейс для работы с телефонами пользователей и учетом отправленных SMS
+type SMSRepository interface {
+	// GetSettings возвращает SMS-настройки пользователя (nil, если телефон не привязан)
+	GetSettings(ctx context.Context, userID string) (*domain.SMSSettings, error)
+
+	// SaveSettings создает или обновляет SMS-настройки пользователя
+	SaveSettings(ctx context.Context, settings *domain.SMSSettings) error
+
+	// DeleteSettings удаляет SMS-настройки пользователя
+	DeleteSettings(ctx context.Context, userID string) error
+
+	// GetMonthlyUsage возвращает количество SMS, отправленных пользователю за месяц
+	GetMonthlyUsage(ctx context.Context, userID string, month time.Time) (int, error)
+
+	// ReserveMonthlyUsage атомарно учитывает еще одно SMS за месяц.
+	// Возвращает false, если квота пользователя исчерпана
+	ReserveMonthlyUsage(ctx context.Context, userID string, month time.Time, quota int) (bool, error)
+}
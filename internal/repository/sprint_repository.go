@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+)
+
+// SprintRepository определяет интерфейс для работы со спринтами проектов
+type SprintRepository interface {
+	// Create создает новый спринт
+	Create(ctx context.Context, sprint *domain.Sprint) error
+
+	// GetByID возвращает спринт по ID (nil, если спринт не найден)
+	GetByID(ctx context.Context, id string) (*domain.Sprint, error)
+
+	// Update обновляет данные спринта, включая его состояние
+	Update(ctx context.Context, sprint *domain.Sprint) error
+
+	// Delete удаляет спринт; задачи спринта возвращаются в бэклог
+	Delete(ctx context.Context, id string) error
+
+	// ListByProject возвращает спринты проекта, упорядоченные по дате начала
+	ListByProject(ctx context.Context, projectID string) ([]*domain.Sprint, error)
+
+	// GetProgress возвращает сводный прогресс задач указанных спринтов по ID спринта
+	GetProgress(ctx context.Context, sprintIDs []string) (map[string]*domain.SprintProgress, error)
+
+	// SetTasksSprint включает задачи в спринт или возвращает их в бэклог при sprintID == nil
+	// и записывает изменения в историю задач
+	SetTasksSprint(ctx context.Context, taskIDs []string, sprintID *string, userID string) error
+
+	// Close завершает спринт и переносит его незавершенные задачи в другой спринт
+	// или в бэклог при moveToSprintID == nil. Возвращает ID перенесенных задач
+	Close(ctx context.Context, sprint *domain.Sprint, moveToSprintID *string, userID string) ([]string, error)
+
+	// ListScopeTasks возвращает задачи, которые входят или когда-либо входили в спринт
+	ListScopeTasks(ctx context.Context, sprintID string) ([]*domain.Task, error)
+
+	// ListTaskChanges возвращает смены статуса и спринта задач, входивших в спринт,
+	// в порядке изменений
+	ListTaskChanges(ctx context.Context, sprintID string) ([]*domain.SprintTaskChange, error)
+}
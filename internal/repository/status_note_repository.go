@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+)
+
+// StatusNoteRepository определяет интерфейс для работы с заметками об инцидентах страницы статуса
+type StatusNoteRepository interface {
+	// Create создает заметку
+	Create(ctx context.Context, note *domain.StatusNote) error
+
+	// GetByID возвращает заметку по ID (nil, если она не найдена)
+	GetByID(ctx context.Context, id string) (*domain.StatusNote, error)
+
+	// Update обновляет заметку
+	Update(ctx context.Context, note *domain.StatusNote) error
+
+	// Delete удаляет заметку
+	Delete(ctx context.Context, id string) error
+
+	// List возвращает все заметки, начиная с новых
+	List(ctx context.Context) ([]*domain.StatusNote, error)
+
+	// ListRecent возвращает нерешенные заметки и заметки, решенные после resolvedAfter, начиная с новых
+	ListRecent(ctx context.Context, resolvedAfter time.Time, limit int) ([]*domain.StatusNote, error)
+}
@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+)
+
+// TaskFormRepository определяет интерфейс для работы с формами создания задач
+type TaskFormRepository interface {
+	// GetByProject возвращает форму проекта (nil, если проект использует форму по умолчанию)
+	GetByProject(ctx context.Context, projectID string) (*domain.TaskForm, error)
+
+	// Save создает или обновляет форму проекта
+	Save(ctx context.Context, form *domain.TaskForm) error
+
+	// Delete удаляет форму проекта, возвращая проект к форме по умолчанию
+	Delete(ctx context.Context, projectID string) error
+
+	// SaveValues сохраняет значения пользовательских полей формы для задачи
+	SaveValues(ctx context.Context, taskID string, values map[string]interface{}) error
+
+	// GetValues возвращает значения пользовательских полей формы для задачи
+	GetValues(ctx context.Context, taskID string) (map[string]interface{}, error)
+}
@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+)
+
+// TaskRecurrenceRepository определяет интерфейс для работы с правилами повторения задач
+type TaskRecurrenceRepository interface {
+	// Upsert сохраняет правило повторения задачи; существующее правило задачи заменяется,
+	// а серия снова становится активной
+	Upsert(ctx context.Context, recurrence *domain.TaskRecurrence) error
+
+	// GetByTaskID возвращает правило повторения задачи или nil, если его нет
+	GetByTaskID(ctx context.Context, taskID string) (*domain.TaskRecurrence, error)
+
+	// Delete удаляет правило повторения задачи; возвращает false, если правила не было
+	Delete(ctx context.Context, taskID string) (bool, error)
+
+	// ClaimDue переводит в обработку активные правила, текущая задача которых завершена, и возвращает их.
+	// Правила, оставшиеся в обработке дольше lease (планировщик упал до их завершения), захватываются снова
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*domain.TaskRecurrence, error)
+
+	// Advance переносит правило, находящееся в обработке, с задачи prevTaskID на следующую задачу серии
+	// и снова делает его активным. Возвращает false, если правило уже перенесено или изменено
+	Advance(ctx context.Context, id string, prevTaskID string, taskID string, occurrences int, now time.Time) (bool, error)
+
+	// Release возвращает правило из обработки в активные, чтобы планировщик повторил попытку
+	Release(ctx context.Context, id string, errMsg string) error
+
+	// Finish завершает серию; errMsg задается, если следующую задачу не удалось создать
+	Finish(ctx context.Context, id string, errMsg *string, now time.Time) error
+}
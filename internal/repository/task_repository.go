@@ -27,9 +27,15 @@ type TaskRepository interface {
 	// Count возвращает количество задач с фильтрацией
 	Count(ctx context.Context, filter TaskFilter) (int, error)
 
+	// GetSubtaskProgress возвращает сводку по подзадачам задачи
+	GetSubtaskProgress(ctx context.Context, parentID string) (*domain.SubtaskProgress, error)
+
 	// GetTags возвращает теги задачи
 	GetTags(ctx context.Context, taskID string) ([]string, error)
 
+	// GetTagsByTasks возвращает теги нескольких задач одним запросом
+	GetTagsByTasks(ctx context.Context, taskIDs []string) (map[string][]string, error)
+
 	// AddTag добавляет тег к задаче
 	AddTag(ctx context.Context, taskID, tag string) error
 
@@ -39,6 +45,20 @@ type TaskRepository interface {
 	// UpdateTags обновляет теги задачи
 	UpdateTags(ctx context.Context, taskID string, tags []string) error
 
+	// GetTagUsage возвращает теги задач проекта с числом задач, отсортированные по частоте
+	GetTagUsage(ctx context.Context, projectID string) ([]*domain.TagUsage, error)
+
+	// RenameTag переименовывает тег во всех задачах проекта и возвращает ID измененных задач
+	RenameTag(ctx context.Context, projectID, tag, name string) ([]string, error)
+
+	// MergeTags заменяет теги sources тегом target во всех задачах проекта в одной транзакции
+	// и возвращает ID измененных задач
+	MergeTags(ctx context.Context, projectID string, sources []string, target string) ([]string, error)
+
+	// DeleteUnusedTag удаляет тег из задач проекта, если его нет ни у одной открытой задачи.
+	// Возвращает ID измененных задач
+	DeleteUnusedTag(ctx context.Context, projectID, tag string) ([]string, error)
+
 	// LogTaskHistory добавляет запись в историю изменений задачи
 	LogTaskHistory(ctx context.Context, history *domain.TaskHistory) error
 
@@ -72,9 +92,68 @@ type TaskRepository interface {
 	// UpdateAssignee обновляет исполнителя задачи
 	UpdateAssignee(ctx context.Context, taskID string, assigneeID *string, userID string) error
 
-	// LogTime добавляет запись о затраченном времени
+	// GetAssigneeLoad возвращает число незавершенных задач пользователя во всех проектах, кроме
+	// excludeTaskID, и число тех из них, срок которых попадает в интервал [dueFrom, dueTo].
+	// Без интервала пересечения сроков не считаются
+	GetAssigneeLoad(ctx context.Context, userID, excludeTaskID string, dueFrom, dueTo *time.Time) (*domain.AssigneeLoad, error)
+
+	// GetAssignees возвращает всех исполнителей задачи, основной исполнитель идет первым
+	GetAssignees(ctx context.Context, taskID string) ([]string, error)
+
+	// GetAssigneesByTasks возвращает исполнителей нескольких задач одним запросом
+	GetAssigneesByTasks(ctx context.Context, taskIDs []string) (map[string][]string, error)
+
+	// SetAssignees заменяет список исполнителей задачи и основного исполнителя
+	SetAssignees(ctx context.Context, taskID string, assigneeIDs []string, primaryID *string, userID string) error
+
+	// GetEffortShares возвращает доли оценки исполнителей и списанное ими время
+	GetEffortShares(ctx context.Context, taskID string) ([]*domain.TaskEffortShare, error)
+
+	// SetEffortSplit задает доли оценки исполнителей и обновляет оценку задачи.
+	// Пустой список сбрасывает распределение
+	SetEffortSplit(ctx context.Context, taskID string, shares map[string]float64, userID string) error
+
+	// UpdatePriorityScores сохраняет пересчитанные оценки приоритета задач
+	UpdatePriorityScores(ctx context.Context, updates []TaskScoreUpdate) error
+
+	// NextRank возвращает ближайший ранг задачи проекта, больший rank
+	NextRank(ctx context.Context, projectID, rank, excludeTaskID string) (string, error)
+
+	// PrevRank возвращает ближайший ранг задачи проекта, меньший rank
+	PrevRank(ctx context.Context, projectID, rank, excludeTaskID string) (string, error)
+
+	// UpdateRank обновляет ранг задачи
+	UpdateRank(ctx context.Context, taskID, rank string) error
+
+	// RebalanceRanks равномерно перераспределяет ранги задач проекта, сохраняя порядок
+	RebalanceRanks(ctx context.Context, projectID string) error
+
+	// MoveToProject переносит задачу в другой проект вместе с комментариями, списаниями времени
+	// и историей. Связи задачи с материалами исходного проекта удаляются
+	MoveToProject(ctx context.Context, move *TaskProjectMove) error
+
+	// ListBacklog возвращает задачи бэклога проекта, начиная с самых старых
+	ListBacklog(ctx context.Context, projectID string) ([]*domain.Task, error)
+
+	// LabelStaleTasks добавляет тег tag задачам бэклога, созданным раньше createdBefore,
+	// в активных проектах, не отключивших автоматическую пометку. Возвращает помеченные задачи
+	LabelStaleTasks(ctx context.Context, createdBefore time.Time, tag string) ([]*domain.StaleTaskLabel, error)
+
+	// LogTime добавляет запись о затраченном времени и увеличивает затраченное время задачи
 	LogTime(ctx context.Context, timeLog *TimeLog) error
 
+	// GetTimeReconciliation сравнивает затраченное время задачи с суммой ее записей
+	// о времени (nil, если задача не найдена)
+	GetTimeReconciliation(ctx context.Context, taskID string) (*domain.TimeReconciliation, error)
+
+	// RecalculateSpentHours записывает в задачу сумму ее записей о времени, блокируя
+	// задачу на время пересчета. Возвращает сверку до пересчета (nil, если задача не найдена)
+	RecalculateSpentHours(ctx context.Context, taskID string) (*domain.TimeReconciliation, error)
+
+	// RecalculateProjectSpentHours пересчитывает затраченное время задач проекта
+	// и возвращает сверку до пересчета для измененных задач
+	RecalculateProjectSpentHours(ctx context.Context, projectID string) ([]*domain.TimeReconciliation, error)
+
 	// GetTimeLogs возвращает записи о затраченном времени
 	GetTimeLogs(ctx context.Context, taskID string) ([]*TimeLog, error)
 
@@ -84,21 +163,52 @@ type TaskRepository interface {
 
 // TaskFilter содержит параметры для фильтрации задач
 type TaskFilter struct {
-	IDs         []string           `json:"ids,omitempty"`
-	ProjectIDs  []string           `json:"project_ids,omitempty"`
-	Status      *domain.TaskStatus `json:"status,omitempty"`
-	Priority    *domain.TaskPriority `json:"priority,omitempty"`
-	AssigneeID  *string            `json:"assignee_id,omitempty"`
-	CreatedBy   *string            `json:"created_by,omitempty"`
-	DueBefore   *time.Time         `json:"due_before,omitempty"`
-	DueAfter    *time.Time         `json:"due_after,omitempty"`
-	Tags        []string           `json:"tags,omitempty"`
-	SearchText  *string            `json:"search_text,omitempty"`
-	IsOverdue   *bool              `json:"is_overdue,omitempty"`
-	OrderBy     *string            `json:"order_by,omitempty"`
-	OrderDir    *string            `json:"order_dir,omitempty"`
-	Limit       int                `json:"limit"`
-	Offset      int                `json:"offset"`
+	IDs             []string              `json:"ids,omitempty"`
+	ProjectIDs      []string              `json:"project_ids,omitempty"`
+	Statuses        []domain.TaskStatus   `json:"statuses,omitempty"`   // Любой из перечисленных статусов
+	Priorities      []domain.TaskPriority `json:"priorities,omitempty"` // Любой из перечисленных приоритетов
+	AssigneeID      *string               `json:"assignee_id,omitempty"`
+	AssigneeIDs     []string              `json:"assignee_ids,omitempty"` // Любой из исполнителей, не только основной
+	CreatedBy       *string               `json:"created_by,omitempty"`
+	DueBefore       *time.Time            `json:"due_before,omitempty"`
+	DueAfter        *time.Time            `json:"due_after,omitempty"`
+	CreatedBefore   *time.Time            `json:"created_before,omitempty"`
+	CreatedAfter    *time.Time            `json:"created_after,omitempty"`
+	UpdatedBefore   *time.Time            `json:"updated_before,omitempty"`
+	UpdatedAfter    *time.Time            `json:"updated_after,omitempty"`
+	CompletedBefore *time.Time            `json:"completed_before,omitempty"`
+	CompletedAfter  *time.Time            `json:"completed_after,omitempty"`
+	Tags            []string              `json:"tags,omitempty"`
+	EpicID          *string               `json:"epic_id,omitempty"`
+	SprintID        *string               `json:"sprint_id,omitempty"`
+	ParentID        *string               `json:"parent_id,omitempty"`
+	SearchText      *string               `json:"search_text,omitempty"`
+	IsOverdue       *bool                 `json:"is_overdue,omitempty"`
+	OrderBy         *string               `json:"order_by,omitempty"`
+	OrderDir        *string               `json:"order_dir,omitempty"`
+	Sort            []domain.SortKey      `json:"sort,omitempty"` // Сортировка по нескольким полям, имеет приоритет над OrderBy
+	Limit           int                   `json:"limit"`
+	Offset          int                   `json:"offset"`
+}
+
+// TaskScoreUpdate содержит новую оценку приоритета задачи
+type TaskScoreUpdate struct {
+	TaskID   string               `json:"task_id"`
+	Score    float64              `json:"score"`
+	Priority *domain.TaskPriority `json:"priority,omitempty"` // nil, если приоритет не меняется
+}
+
+// TaskProjectMove содержит данные для переноса задачи в другой проект
+type TaskProjectMove struct {
+	TaskID        string            `json:"task_id"`
+	FromProjectID string            `json:"from_project_id"`
+	ToProjectID   string            `json:"to_project_id"`
+	Status        domain.TaskStatus `json:"status"`
+	Rank          string            `json:"rank"`
+	Tags          []string          `json:"tags"`
+	AssigneeIDs   []string          `json:"assignee_ids"` // Исполнители, остающиеся у задачи
+	PrimaryID     *string           `json:"primary_id,omitempty"`
+	UserID        string            `json:"user_id"`
 }
 
 // TimeLog содержит информацию о затраченном времени
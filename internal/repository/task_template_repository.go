@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+)
+
+// TaskTemplateRepository определяет интерфейс для работы с шаблонами задач
+type TaskTemplateRepository interface {
+	// Create создает новый шаблон
+	Create(ctx context.Context, template *domain.TaskTemplate) error
+
+	// GetByID возвращает шаблон по ID
+	GetByID(ctx context.Context, id string) (*domain.TaskTemplate, error)
+
+	// GetByName возвращает шаблон проекта по имени
+	GetByName(ctx context.Context, projectID, name string) (*domain.TaskTemplate, error)
+
+	// ListByProject возвращает шаблоны проекта, отсортированные по имени
+	ListByProject(ctx context.Context, projectID string) ([]*domain.TaskTemplate, error)
+
+	// Update обновляет шаблон
+	Update(ctx context.Context, template *domain.TaskTemplate) error
+
+	// Delete удаляет шаблон по ID
+	Delete(ctx context.Context, id string) error
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+)
+
+// TaskViewRepository определяет интерфейс для работы с хранилищем просмотров задач
+type TaskViewRepository interface {
+	// SaveViews сохраняет просмотры; более ранний просмотр не заменяет сохраненный более поздний.
+	// Просмотры удаленных задач и пользователей пропускаются
+	SaveViews(ctx context.Context, views []*domain.TaskView) error
+
+	// GetViews возвращает время последнего просмотра задач пользователем; непросмотренные задачи
+	// в результат не попадают
+	GetViews(ctx context.Context, userID string, taskIDs []string) (map[string]time.Time, error)
+}
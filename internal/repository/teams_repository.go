@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+)
+
+// TeamsRepository определяет интерфейс для работы с вебхуками Microsoft Teams пользователей и проектов
+type TeamsRepository interface {
+	// GetUserLink возвращает вебхук личного чата пользователя (nil, если пользователь не подключен)
+	GetUserLink(ctx context.Context, userID string) (*domain.TeamsLink, error)
+
+	// SaveUserLink создает или обновляет вебхук личного чата пользователя
+	SaveUserLink(ctx context.Context, link *domain.TeamsLink) error
+
+	// DeleteUserLink удаляет вебхук личного чата пользователя
+	DeleteUserLink(ctx context.Context, userID string) error
+
+	// GetProjectChannel возвращает канал проекта (nil, если канал не настроен)
+	GetProjectChannel(ctx context.Context, projectID string) (*domain.ProjectTeamsChannel, error)
+
+	// SaveProjectChannel создает или обновляет канал проекта
+	SaveProjectChannel(ctx context.Context, channel *domain.ProjectTeamsChannel) error
+
+	// DeleteProjectChannel удаляет канал проекта
+	DeleteProjectChannel(ctx context.Context, projectID string) error
+}
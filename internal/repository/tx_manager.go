@@ -0,0 +1,12 @@
+package repository
+
+import "context"
+
+// TxManager выполняет несколько вызовов репозиториев в одной транзакции базы
+type TxManager interface {
+	// WithinTx выполняет fn в транзакции и фиксирует ее, если fn не вернула ошибку.
+	// Репозитории, вызванные с переданным в fn контекстом, работают в этой транзакции,
+	// а их собственные транзакции становятся ее частью. Вложенный вызов WithinTx
+	// присоединяется к внешней транзакции
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
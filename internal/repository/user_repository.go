@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/nurlyy/task_manager/internal/domain"
 )
@@ -29,6 +30,9 @@ type ProjectRepository interface {
 	// AddMember добавляет пользователя в проект
 	AddMember(ctx context.Context, member *domain.ProjectMember) error
 
+	// AddMembers добавляет пользователей в проекты одним запросом
+	AddMembers(ctx context.Context, members []*domain.ProjectMember) error
+
 	// UpdateMember обновляет роль пользователя в проекте
 	UpdateMember(ctx context.Context, projectID, userID string, role domain.ProjectRole) error
 
@@ -46,6 +50,38 @@ type ProjectRepository interface {
 
 	// CountUserProjects возвращает количество проектов пользователя
 	CountUserProjects(ctx context.Context, userID string, filter ProjectFilter) (int, error)
+
+	// LogMembershipHistory добавляет запись в историю изменений участников проекта
+	LogMembershipHistory(ctx context.Context, entry *domain.MembershipHistory) error
+
+	// GetMembershipHistory возвращает историю изменений участников проекта
+	GetMembershipHistory(ctx context.Context, projectID string, limit, offset int) ([]*domain.MembershipHistory, error)
+
+	// CountMembershipHistory возвращает количество записей в истории участников проекта
+	CountMembershipHistory(ctx context.Context, projectID string) (int, error)
+
+	// CreateOwnershipTransfer создает запрос на передачу владения проектом
+	CreateOwnershipTransfer(ctx context.Context, transfer *domain.OwnershipTransfer) error
+
+	// GetPendingOwnershipTransfer возвращает ожидающий запрос на передачу владения проектом
+	GetPendingOwnershipTransfer(ctx context.Context, projectID string) (*domain.OwnershipTransfer, error)
+
+	// ResolveOwnershipTransfer переводит ожидающий запрос в итоговый статус;
+	// возвращает false, если запрос уже не находится в ожидании
+	ResolveOwnershipTransfer(ctx context.Context, id string, status domain.OwnershipTransferStatus, resolvedBy *string) (bool, error)
+
+	// ExpireOwnershipTransfers помечает просроченные запросы и возвращает их
+	ExpireOwnershipTransfers(ctx context.Context, now time.Time) ([]*domain.OwnershipTransfer, error)
+
+	// GetTaskSettings возвращает настройки задач проекта (nil, если настройки не заданы)
+	GetTaskSettings(ctx context.Context, projectID string) (*domain.ProjectTaskSettings, error)
+
+	// SaveTaskSettings создает или обновляет настройки задач проекта
+	SaveTaskSettings(ctx context.Context, settings *domain.ProjectTaskSettings) error
+
+	// Split создает новый проект и переносит в него задачи исходного проекта
+	// в одной транзакции
+	Split(ctx context.Context, split *ProjectSplit) error
 }
 
 // ProjectFilter содержит параметры для фильтрации проектов
@@ -62,3 +98,13 @@ type ProjectFilter struct {
 	Offset     int                   `json:"offset"`
 	MemberID   *string               `json:"member_id,omitempty"`
 }
+
+// ProjectSplit содержит данные для выделения части задач проекта в новый проект.
+// Участники и настройки задач копируются из исходного проекта
+type ProjectSplit struct {
+	SourceProjectID string          `json:"source_project_id"`
+	Project         *domain.Project `json:"project"`
+	TaskIDs         []string        `json:"task_ids"`
+	EpicID          *string         `json:"epic_id,omitempty"` // Эпик, переносимый вместе с задачами
+	UserID          string          `json:"user_id"`
+}
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+)
+
+// WikiRepository определяет интерфейс для работы с вики проектов
+type WikiRepository interface {
+	// Create сохраняет страницу, ее первую ревизию и связи с задачами
+	Create(ctx context.Context, page *domain.WikiPage) error
+
+	// GetByID возвращает страницу по ID (nil, если страница не найдена)
+	GetByID(ctx context.Context, id string) (*domain.WikiPage, error)
+
+	// Update сохраняет правку страницы и ее ревизию, если текущая версия равна expectedVersion.
+	// Возвращает false, если страницу успели изменить
+	Update(ctx context.Context, page *domain.WikiPage, expectedVersion int) (bool, error)
+
+	// Delete удаляет страницу; дочерние страницы переходят к ее родителю
+	Delete(ctx context.Context, id string) error
+
+	// ListByProject возвращает все страницы проекта без содержимого
+	ListByProject(ctx context.Context, projectID string) ([]*domain.WikiPageNode, error)
+
+	// Search выполняет полнотекстовый поиск по страницам проекта.
+	// pattern - экранированный для LIKE запрос для поиска по названию
+	Search(ctx context.Context, projectID string, query string, pattern string, limit int) ([]*domain.WikiSearchResult, error)
+
+	// GetAncestorIDs возвращает ID всех предков страницы
+	GetAncestorIDs(ctx context.Context, id string) ([]string, error)
+
+	// ListRevisions возвращает ревизии страницы без содержимого, сначала последние
+	ListRevisions(ctx context.Context, pageID string) ([]*domain.WikiPageRevision, error)
+
+	// GetRevision возвращает ревизию страницы (nil, если ревизия не найдена)
+	GetRevision(ctx context.Context, pageID string, version int) (*domain.WikiPageRevision, error)
+
+	// ListByTask возвращает страницы, связанные с задачей
+	ListByTask(ctx context.Context, taskID string) ([]*domain.WikiPageNode, error)
+
+	// LinkTask связывает страницу с задачей. Возвращает false, если связь уже есть
+	LinkTask(ctx context.Context, pageID, taskID, userID string) (bool, error)
+
+	// UnlinkTask удаляет связь страницы с задачей
+	UnlinkTask(ctx context.Context, pageID, taskID string) error
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/internal/repository"
+)
+
+// AccessService отвечает на вопросы о правах пользователя для политик доступа маршрутов.
+// Роль и участие в проектах читаются из базы, поэтому изменения прав действуют сразу,
+// не дожидаясь выпуска нового токена
+type AccessService struct {
+	userRepo   repository.UserRepository
+	projectSvc *ProjectService
+}
+
+// NewAccessService создает новый экземпляр AccessService
+func NewAccessService(userRepo repository.UserRepository, projectSvc *ProjectService) *AccessService {
+	return &AccessService{
+		userRepo:   userRepo,
+		projectSvc: projectSvc,
+	}
+}
+
+// UserRole возвращает текущую роль пользователя
+func (s *AccessService) UserRole(ctx context.Context, userID string) (domain.UserRole, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	if user == nil {
+		return "", ErrUserNotFound
+	}
+	return user.Role, nil
+}
+
+// HasProjectAccess проверяет, что пользователь участвует в проекте или является администратором
+func (s *AccessService) HasProjectAccess(ctx context.Context, projectID string, userID string) bool {
+	return s.projectSvc.hasAccessToProject(ctx, projectID, userID)
+}
+
+// CanEditProject проверяет, что пользователь может изменять задачи проекта: владеет им,
+// управляет им, участвует в нем не только как наблюдатель или является администратором
+func (s *AccessService) CanEditProject(ctx context.Context, projectID string, userID string) bool {
+	return s.projectSvc.canEditProject(ctx, projectID, userID)
+}
+
+// CanManageProject проверяет, что пользователь владеет проектом, управляет им или является администратором
+func (s *AccessService) CanManageProject(ctx context.Context, projectID string, userID string) bool {
+	return s.projectSvc.canManageProject(ctx, projectID, userID)
+}
+
+// isAdminUser проверяет, что пользователь - администратор. Роль читается из базы, а не из токена:
+// отозванная роль перестает действовать сразу, а не после истечения токена. Повторные чтения
+// пользователя в пределах запроса не обращаются к базе (см. memo.UserRepository)
+func isAdminUser(ctx context.Context, userRepo repository.UserRepository, userID string) bool {
+	user, err := userRepo.GetByID(ctx, userID)
+	return err == nil && user != nil && user.IsAdmin()
+}
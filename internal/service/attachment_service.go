@@ -0,0 +1,323 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"path"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/internal/repository"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
+	"github.com/nurlyy/task_manager/pkg/logger"
+	"github.com/nurlyy/task_manager/pkg/storage"
+)
+
+// attachmentDeletionBatchSize ограничивает число файлов, удаляемых из хранилища за один запуск планировщика
+const attachmentDeletionBatchSize = 200
+
+// maxAttachmentFileNameLength ограничивает длину имени файла вложения в символах
+const maxAttachmentFileNameLength = 255
+
+// defaultAttachmentContentType используется, если клиент не передал тип содержимого файла
+const defaultAttachmentContentType = "application/octet-stream"
+
+var (
+	ErrAttachmentNotFound = apperrors.New(apperrors.CodeAttachmentNotFound, "attachment not found")
+	ErrAttachmentTooLarge = apperrors.New(apperrors.CodeAttachmentTooLarge, "attachment exceeds the maximum file size")
+)
+
+// AttachmentService представляет бизнес-логику вложений задач и комментариев
+type AttachmentService struct {
+	attachmentRepo repository.AttachmentRepository
+	taskRepo       repository.TaskRepository
+	commentRepo    repository.CommentRepository
+	storage        storage.Storage
+	projectSvc     *ProjectService
+	taskSvc        *TaskService
+	maxFileSize    int64
+	logger         logger.Logger
+}
+
+// NewAttachmentService создает новый экземпляр AttachmentService
+func NewAttachmentService(
+	attachmentRepo repository.AttachmentRepository,
+	taskRepo repository.TaskRepository,
+	commentRepo repository.CommentRepository,
+	storage storage.Storage,
+	projectSvc *ProjectService,
+	taskSvc *TaskService,
+	maxFileSize int64,
+	logger logger.Logger,
+) *AttachmentService {
+	return &AttachmentService{
+		attachmentRepo: attachmentRepo,
+		taskRepo:       taskRepo,
+		commentRepo:    commentRepo,
+		storage:        storage,
+		projectSvc:     projectSvc,
+		taskSvc:        taskSvc,
+		maxFileSize:    maxFileSize,
+		logger:         logger,
+	}
+}
+
+// MaxFileSize возвращает максимальный размер вложения в байтах
+func (s *AttachmentService) MaxFileSize() int64 {
+	return s.maxFileSize
+}
+
+// UploadToTask прикрепляет файл к задаче. Прикреплять файлы может любой участник проекта с доступом к задаче
+func (s *AttachmentService) UploadToTask(ctx context.Context, taskID string, upload domain.AttachmentUpload, userID string) (*domain.Attachment, error) {
+	task, err := s.getTask(ctx, taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.projectSvc.ensureProjectWritable(ctx, task.ProjectID); err != nil {
+		return nil, err
+	}
+
+	return s.upload(ctx, task.ID, nil, upload, userID)
+}
+
+// UploadToComment прикрепляет файл к комментарию. Прикреплять файлы может только автор комментария
+func (s *AttachmentService) UploadToComment(ctx context.Context, commentID string, upload domain.AttachmentUpload, userID string) (*domain.Attachment, error) {
+	comment, err := s.getComment(ctx, commentID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if comment.UserID != userID {
+		return nil, ErrInsufficientRights
+	}
+
+	task, err := s.taskRepo.GetByID(ctx, comment.TaskID)
+	if err != nil || task == nil {
+		return nil, ErrTaskNotFound
+	}
+	if err := s.projectSvc.ensureProjectWritable(ctx, task.ProjectID); err != nil {
+		return nil, err
+	}
+
+	return s.upload(ctx, task.ID, &comment.ID, upload, userID)
+}
+
+// ListByTask возвращает вложения задачи, включая вложения ее комментариев
+func (s *AttachmentService) ListByTask(ctx context.Context, taskID string, userID string) ([]*domain.Attachment, error) {
+	if _, err := s.getTask(ctx, taskID, userID); err != nil {
+		return nil, err
+	}
+
+	return s.attachmentRepo.ListByTask(ctx, taskID)
+}
+
+// ListByComment возвращает вложения комментария
+func (s *AttachmentService) ListByComment(ctx context.Context, commentID string, userID string) ([]*domain.Attachment, error) {
+	if _, err := s.getComment(ctx, commentID, userID); err != nil {
+		return nil, err
+	}
+
+	return s.attachmentRepo.ListByComment(ctx, commentID)
+}
+
+// GetByID возвращает метаданные вложения
+func (s *AttachmentService) GetByID(ctx context.Context, id string, userID string) (*domain.Attachment, error) {
+	attachment, err := s.attachmentRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if attachment == nil {
+		return nil, ErrAttachmentNotFound
+	}
+
+	if _, err := s.getTask(ctx, attachment.TaskID, userID); err != nil {
+		return nil, err
+	}
+
+	return attachment, nil
+}
+
+// Open возвращает метаданные вложения и открытый на чтение файл; вызывающий должен закрыть его
+func (s *AttachmentService) Open(ctx context.Context, id string, userID string) (*domain.Attachment, io.ReadCloser, error) {
+	attachment, err := s.GetByID(ctx, id, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	content, err := s.storage.Get(ctx, attachment.StorageKey)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			s.logger.Error("Attachment file is missing in storage", err, map[string]interface{}{
+				"id":          attachment.ID,
+				"storage_key": attachment.StorageKey,
+			})
+			return nil, nil, ErrAttachmentNotFound
+		}
+		return nil, nil, err
+	}
+
+	return attachment, content, nil
+}
+
+// Delete удаляет вложение. Удалить вложение может загрузивший его пользователь
+// или пользователь, управляющий задачами проекта. Файл удаляется из хранилища планировщиком
+func (s *AttachmentService) Delete(ctx context.Context, id string, userID string) error {
+	attachment, err := s.GetByID(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+
+	task, err := s.taskRepo.GetByID(ctx, attachment.TaskID)
+	if err != nil || task == nil {
+		return ErrTaskNotFound
+	}
+	if attachment.UploadedBy != userID && !s.taskSvc.canManageTask(ctx, task.ProjectID, userID) {
+		return ErrInsufficientRights
+	}
+	if err := s.projectSvc.ensureProjectWritable(ctx, task.ProjectID); err != nil {
+		return err
+	}
+
+	deleted, err := s.attachmentRepo.Delete(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrAttachmentNotFound
+	}
+
+	s.logger.Info("Attachment deleted", map[string]interface{}{
+		"id":      id,
+		"task_id": attachment.TaskID,
+		"user_id": userID,
+	})
+
+	return nil
+}
+
+// PurgeDeleted удаляет из хранилища файлы удаленных вложений, в том числе удаленных
+// каскадно вместе с задачами и комментариями, и возвращает число удаленных файлов
+func (s *AttachmentService) PurgeDeleted(ctx context.Context) (int, error) {
+	keys, err := s.attachmentRepo.ListPendingDeletions(ctx, attachmentDeletionBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, key := range keys {
+		if err := s.storage.Delete(ctx, key); err != nil {
+			s.logger.Warn("Failed to delete attachment file, will retry", map[string]interface{}{
+				"storage_key": key,
+				"error":       err.Error(),
+			})
+			if err := s.attachmentRepo.FailDeletion(ctx, key, err.Error()); err != nil {
+				return purged, err
+			}
+			continue
+		}
+
+		if err := s.attachmentRepo.CompleteDeletion(ctx, key); err != nil {
+			return purged, err
+		}
+		purged++
+	}
+
+	return purged, nil
+}
+
+// upload сохраняет файл в хранилище и метаданные вложения в базе
+func (s *AttachmentService) upload(ctx context.Context, taskID string, commentID *string, upload domain.AttachmentUpload, userID string) (*domain.Attachment, error) {
+	if upload.Size > s.maxFileSize {
+		return nil, ErrAttachmentTooLarge
+	}
+
+	contentType := strings.TrimSpace(upload.ContentType)
+	if contentType == "" {
+		contentType = defaultAttachmentContentType
+	}
+
+	id := uuid.New().String()
+	attachment := &domain.Attachment{
+		ID:          id,
+		TaskID:      taskID,
+		CommentID:   commentID,
+		FileName:    attachmentFileName(upload.FileName),
+		ContentType: contentType,
+		Size:        upload.Size,
+		StorageKey:  "attachments/" + taskID + "/" + id,
+		UploadedBy:  userID,
+		CreatedAt:   time.Now(),
+	}
+
+	if err := s.storage.Put(ctx, attachment.StorageKey, upload.Content, upload.Size, contentType); err != nil {
+		s.logger.Error("Failed to store attachment file", err, map[string]interface{}{
+			"task_id": taskID,
+		})
+		return nil, err
+	}
+
+	if err := s.attachmentRepo.Create(ctx, attachment); err != nil {
+		// Файл без метаданных недоступен, удаляем его сразу
+		if delErr := s.storage.Delete(ctx, attachment.StorageKey); delErr != nil {
+			s.logger.Warn("Failed to delete orphaned attachment file", map[string]interface{}{
+				"storage_key": attachment.StorageKey,
+				"error":       delErr.Error(),
+			})
+		}
+		return nil, err
+	}
+
+	s.logger.Info("Attachment uploaded", map[string]interface{}{
+		"id":      attachment.ID,
+		"task_id": taskID,
+		"size":    attachment.Size,
+		"user_id": userID,
+	})
+
+	return attachment, nil
+}
+
+// getTask возвращает задачу, если пользователь имеет к ней доступ
+func (s *AttachmentService) getTask(ctx context.Context, taskID string, userID string) (*domain.Task, error) {
+	task, err := s.taskRepo.GetByID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if task == nil {
+		return nil, ErrTaskNotFound
+	}
+	if !s.taskSvc.hasAccessToTask(ctx, task.ProjectID, userID) {
+		return nil, ErrTaskAccessDenied
+	}
+	return task, nil
+}
+
+// getComment возвращает комментарий, если пользователь имеет доступ к его задаче
+func (s *AttachmentService) getComment(ctx context.Context, commentID string, userID string) (*domain.Comment, error) {
+	comment, err := s.commentRepo.GetByID(ctx, commentID)
+	if err != nil || comment == nil {
+		return nil, ErrCommentNotFound
+	}
+	if _, err := s.getTask(ctx, comment.TaskID, userID); err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+// attachmentFileName оставляет от имени файла только последний сегмент пути
+// и ограничивает его длину; пустое имя заменяется на "file"
+func attachmentFileName(name string) string {
+	name = strings.TrimSpace(path.Base(strings.ReplaceAll(name, "\\", "/")))
+	if name == "" || name == "." || name == "/" {
+		return "file"
+	}
+	if utf8.RuneCountInString(name) > maxAttachmentFileNameLength {
+		runes := []rune(name)
+		name = string(runes[len(runes)-maxAttachmentFileNameLength:])
+	}
+	return name
+}
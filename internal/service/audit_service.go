@@ -0,0 +1,290 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/internal/messaging"
+	"github.com/nurlyy/task_manager/internal/repository"
+	"github.com/nurlyy/task_manager/pkg/auth"
+	"github.com/nurlyy/task_manager/pkg/logger"
+)
+
+// Типы сущностей в журнале аудита для изменений, о которых сервисы сообщают событиями.
+// Они совпадают с названиями ресурсов в маршрутах API, по которым записываются остальные изменения
+const (
+	AuditEntityTasks    = "tasks"
+	AuditEntityProjects = "projects"
+	AuditEntityMembers  = "members"
+	AuditEntityComments = "comments"
+)
+
+// auditExportBatchSize - число записей журнала, загружаемых за один запрос при экспорте
+const auditExportBatchSize = 500
+
+// auditScopeKey - ключ записей аудита запроса в контексте
+type auditScopeKey struct{}
+
+// auditScope накапливает записи аудита, сделанные при обработке одного запроса,
+// чтобы сохранить их одним запросом вместе с адресом клиента и маршрутом
+type auditScope struct {
+	mu       sync.Mutex
+	ip       string
+	records  []*domain.AuditRecord
+	finished bool
+}
+
+// add добавляет запись к запросу; после завершения запроса записи не принимаются
+func (s *auditScope) add(record *domain.AuditRecord) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.finished {
+		return false
+	}
+	s.records = append(s.records, record)
+	return true
+}
+
+// finish завершает запрос и возвращает накопленные записи
+func (s *auditScope) finish() []*domain.AuditRecord {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.finished = true
+	return s.records
+}
+
+// AuditService ведет журнал аудита изменений и предоставляет его администраторам
+type AuditService struct {
+	auditRepo repository.AuditRepository
+	logger    logger.Logger
+}
+
+// NewAuditService создает новый экземпляр AuditService
+func NewAuditService(
+	auditRepo repository.AuditRepository,
+	logger logger.Logger,
+) *AuditService {
+	return &AuditService{
+		auditRepo: auditRepo,
+		logger:    logger,
+	}
+}
+
+// BeginRequest возвращает контекст, в котором записи аудита накапливаются до FinishRequest
+func (s *AuditService) BeginRequest(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, auditScopeKey{}, &auditScope{ip: ip})
+}
+
+// FinishRequest сохраняет записи аудита запроса, дополняя их адресом клиента и маршрутом.
+// Если сервисы не сообщили об изменениях, сохраняется запись fallback, если она задана.
+// Записи, сделанные после завершения запроса, сохраняются сразу
+func (s *AuditService) FinishRequest(ctx context.Context, route string, fallback *domain.AuditRecord) {
+	scope, _ := ctx.Value(auditScopeKey{}).(*auditScope)
+	if scope == nil {
+		return
+	}
+
+	records := scope.finish()
+	if len(records) == 0 && fallback != nil {
+		records = []*domain.AuditRecord{fallback}
+	}
+	for _, record := range records {
+		if record.IP == nil && scope.ip != "" {
+			record.IP = &scope.ip
+		}
+		if record.Route == nil && route != "" {
+			record.Route = &route
+		}
+	}
+
+	s.save(ctx, records)
+}
+
+// NewRecord создает запись аудита от имени пользователя из контекста
+func (s *AuditService) NewRecord(ctx context.Context, action domain.AuditAction, entityType, entityID string, changes map[string]interface{}) *domain.AuditRecord {
+	record := &domain.AuditRecord{
+		ID:         uuid.New().String(),
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Changes:    changes,
+		CreatedAt:  time.Now(),
+	}
+	if actorID := auth.ActorID(ctx); actorID != "" {
+		record.ActorID = &actorID
+	}
+	return record
+}
+
+// Record записывает изменение сущности в журнал аудита. В запросе API запись сохраняется
+// по его завершении, вне запроса - сразу. Ошибки сохранения только логируются
+func (s *AuditService) Record(ctx context.Context, action domain.AuditAction, entityType, entityID string, changes map[string]interface{}) {
+	record := s.NewRecord(ctx, action, entityType, entityID, changes)
+
+	if scope, _ := ctx.Value(auditScopeKey{}).(*auditScope); scope != nil && scope.add(record) {
+		return
+	}
+	s.save(ctx, []*domain.AuditRecord{record})
+}
+
+// RecordEvent записывает в журнал аудита изменение, о котором сервис сообщил событием.
+// Подписывается на события продюсера, поэтому получает их до отправки в Kafka
+func (s *AuditService) RecordEvent(ctx context.Context, event interface{}) {
+	switch e := event.(type) {
+	case messaging.TaskEvent:
+		action := auditEventAction(e.Type, messaging.EventTypeTaskCreated, messaging.EventTypeTaskDeleted)
+		changes := e.Changes
+		if len(changes) == 0 {
+			changes = auditSnapshot(action, map[string]interface{}{
+				"title":      e.Title,
+				"project_id": e.ProjectID,
+				"status":     e.Status,
+				"priority":   e.Priority,
+			})
+		}
+		s.Record(ctx, action, AuditEntityTasks, e.ID, changes)
+	case messaging.ProjectEvent:
+		action := auditEventAction(e.Type, messaging.EventTypeProjectCreated, messaging.EventTypeProjectDeleted)
+		changes := e.Changes
+		if len(changes) == 0 {
+			changes = auditSnapshot(action, map[string]interface{}{
+				"name":   e.Name,
+				"status": e.Status,
+			})
+		}
+		s.Record(ctx, action, AuditEntityProjects, e.ID, changes)
+	case messaging.ProjectMemberEvent:
+		action := auditEventAction(e.Type, messaging.EventTypeProjectMemberAdded, messaging.EventTypeProjectMemberRemoved)
+		s.Record(ctx, action, AuditEntityMembers, e.ProjectID+":"+e.UserID, auditSnapshot(action, map[string]interface{}{
+			"project_id": e.ProjectID,
+			"user_id":    e.UserID,
+			"role":       e.Role,
+		}))
+	case messaging.CommentEvent:
+		s.Record(ctx, domain.AuditActionCreate, AuditEntityComments, e.CommentID, auditSnapshot(domain.AuditActionCreate, map[string]interface{}{
+			"task_id": e.TaskID,
+			"content": e.Content,
+		}))
+	}
+}
+
+// auditEventAction определяет вид изменения по типу события
+func auditEventAction(eventType, createdType, deletedType string) domain.AuditAction {
+	switch eventType {
+	case createdType:
+		return domain.AuditActionCreate
+	case deletedType:
+		return domain.AuditActionDelete
+	default:
+		return domain.AuditActionUpdate
+	}
+}
+
+// auditSnapshot представляет поля созданной или удаленной сущности в виде изменений:
+// у созданной известны новые значения, у удаленной - прежние
+func auditSnapshot(action domain.AuditAction, fields map[string]interface{}) map[string]interface{} {
+	side := "new"
+	if action == domain.AuditActionDelete {
+		side = "old"
+	}
+
+	changes := make(map[string]interface{}, len(fields))
+	for field, value := range fields {
+		if value == "" {
+			continue
+		}
+		changes[field] = map[string]interface{}{side: value}
+	}
+	return changes
+}
+
+// save сохраняет записи аудита. Запись не должна теряться из-за отмены запроса клиентом,
+// поэтому сохранение не зависит от отмены контекста
+func (s *AuditService) save(ctx context.Context, records []*domain.AuditRecord) {
+	if len(records) == 0 {
+		return
+	}
+
+	if err := s.auditRepo.Create(context.WithoutCancel(ctx), records); err != nil {
+		s.logger.Error("Failed to save audit records", err, map[string]interface{}{
+			"count":       len(records),
+			"entity_type": records[0].EntityType,
+			"entity_id":   records[0].EntityID,
+		})
+	}
+}
+
+// List возвращает журнал аудита с фильтрацией, начиная с последних записей
+func (s *AuditService) List(ctx context.Context, filterOptions domain.AuditFilterOptions, page domain.PageRequest) (*domain.PagedResponse, error) {
+	filter := auditFilter(filterOptions)
+	filter.Limit = page.Limit()
+	filter.Offset = page.Offset()
+
+	records, err := s.auditRepo.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	records, hasMore := domain.PageItems(records, page)
+	total := 0
+	if !page.SkipCount {
+		total, err = s.auditRepo.Count(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return domain.NewPagedResponse(records, page, total, hasMore), nil
+}
+
+// Export передает в fn все записи журнала аудита по фильтру, начиная с последних.
+// Записи загружаются порциями, поэтому экспорт не держит весь журнал в памяти
+func (s *AuditService) Export(ctx context.Context, filterOptions domain.AuditFilterOptions, userID string, fn func(*domain.AuditRecord) error) error {
+	filter := auditFilter(filterOptions)
+	filter.Limit = auditExportBatchSize
+
+	// Новые записи появляются в начале журнала и сдвигают порции, поэтому экспорт
+	// ограничивается записями, сделанными до его начала
+	if filter.To == nil || filter.To.After(time.Now()) {
+		now := time.Now()
+		filter.To = &now
+	}
+
+	for {
+		records, err := s.auditRepo.List(ctx, filter)
+		if err != nil {
+			s.logger.Error("Failed to list audit records for export", err, map[string]interface{}{
+				"user_id": userID,
+				"offset":  filter.Offset,
+			})
+			return err
+		}
+
+		for _, record := range records {
+			if err := fn(record); err != nil {
+				return err
+			}
+		}
+
+		if len(records) < auditExportBatchSize {
+			return nil
+		}
+		filter.Offset += len(records)
+	}
+}
+
+// auditFilter преобразует фильтр доменной модели в фильтр репозитория
+func auditFilter(filterOptions domain.AuditFilterOptions) repository.AuditFilter {
+	return repository.AuditFilter{
+		ActorID:    filterOptions.ActorID,
+		Action:     filterOptions.Action,
+		EntityType: filterOptions.EntityType,
+		EntityID:   filterOptions.EntityID,
+		From:       filterOptions.From,
+		To:         filterOptions.To,
+	}
+}
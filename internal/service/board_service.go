@@ -0,0 +1,316 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/internal/repository"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
+	"github.com/nurlyy/task_manager/pkg/logger"
+)
+
+var (
+	ErrBoardColumnNotFound = apperrors.New(apperrors.CodeBoardColumnNotFound, "board column not found")
+	ErrBoardColumnExists   = apperrors.New(apperrors.CodeBoardColumnExists, "board column with this name or status already exists")
+	ErrBoardLastColumn     = apperrors.New(apperrors.CodeBoardLastColumn, "board must keep at least one column")
+	ErrInvalidBoardOrder   = apperrors.New(apperrors.CodeInvalidBoardOrder, "column order must list every board column exactly once")
+)
+
+// boardColumnNames задает названия колонок, которыми заполняется доска проекта
+var boardColumnNames = map[domain.TaskStatus]string{
+	domain.TaskStatusNew:        "New",
+	domain.TaskStatusInProgress: "In progress",
+	domain.TaskStatusOnHold:     "On hold",
+	domain.TaskStatusReview:     "Review",
+	domain.TaskStatusCompleted:  "Completed",
+}
+
+// BoardService представляет бизнес-логику колонок канбан-доски проекта и переноса карточек.
+// Порядок карточек задается рангом задач проекта, поэтому перенос меняет ранг только
+// перемещаемой задачи и не перенумеровывает остальные
+type BoardService struct {
+	boardRepo  repository.BoardRepository
+	taskRepo   repository.TaskRepository
+	projectSvc *ProjectService
+	taskSvc    *TaskService
+	logger     logger.Logger
+}
+
+// NewBoardService создает новый экземпляр BoardService
+func NewBoardService(
+	boardRepo repository.BoardRepository,
+	taskRepo repository.TaskRepository,
+	projectSvc *ProjectService,
+	taskSvc *TaskService,
+	logger logger.Logger,
+) *BoardService {
+	return &BoardService{
+		boardRepo:  boardRepo,
+		taskRepo:   taskRepo,
+		projectSvc: projectSvc,
+		taskSvc:    taskSvc,
+		logger:     logger,
+	}
+}
+
+// ListColumns возвращает колонки доски проекта по порядку
+func (s *BoardService) ListColumns(ctx context.Context, projectID string) ([]*domain.ProjectBoardColumn, error) {
+	return s.columns(ctx, projectID)
+}
+
+// CreateColumn добавляет колонку в конец доски проекта
+func (s *BoardService) CreateColumn(ctx context.Context, projectID string, req domain.BoardColumnRequest, userID string) (*domain.ProjectBoardColumn, error) {
+	columns, err := s.columns(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkBoardColumnConflict(columns, "", req); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	column := &domain.ProjectBoardColumn{
+		ID:        uuid.New().String(),
+		ProjectID: projectID,
+		Name:      req.Name,
+		Status:    req.Status,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.boardRepo.CreateColumn(ctx, column); err != nil {
+		s.logger.Error("Failed to create board column", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return nil, err
+	}
+
+	s.logger.Info("Board column created", map[string]interface{}{
+		"column_id":  column.ID,
+		"project_id": projectID,
+		"user_id":    userID,
+	})
+
+	return column, nil
+}
+
+// UpdateColumn изменяет название и статус колонки. Если колонке назначается статус,
+// перенесенные в нее задачи возвращаются в колонки своих статусов
+func (s *BoardService) UpdateColumn(ctx context.Context, projectID, id string, req domain.BoardColumnRequest) (*domain.ProjectBoardColumn, error) {
+	columns, err := s.columns(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	column := findBoardColumn(columns, id)
+	if column == nil {
+		return nil, ErrBoardColumnNotFound
+	}
+	if err := checkBoardColumnConflict(columns, id, req); err != nil {
+		return nil, err
+	}
+
+	column.Name = req.Name
+	column.Status = req.Status
+	column.UpdatedAt = time.Now()
+	if err := s.boardRepo.UpdateColumn(ctx, column); err != nil {
+		s.logger.Error("Failed to update board column", err, map[string]interface{}{
+			"id": id,
+		})
+		return nil, err
+	}
+
+	return column, nil
+}
+
+// DeleteColumn удаляет колонку доски. Задачи, перенесенные в нее, возвращаются в колонки
+// своих статусов. Последнюю колонку удалить нельзя
+func (s *BoardService) DeleteColumn(ctx context.Context, projectID, id string) error {
+	columns, err := s.columns(ctx, projectID)
+	if err != nil {
+		return err
+	}
+	if findBoardColumn(columns, id) == nil {
+		return ErrBoardColumnNotFound
+	}
+	if len(columns) == 1 {
+		return ErrBoardLastColumn
+	}
+
+	if err := s.boardRepo.DeleteColumn(ctx, id); err != nil {
+		s.logger.Error("Failed to delete board column", err, map[string]interface{}{
+			"id": id,
+		})
+		return err
+	}
+
+	return nil
+}
+
+// ReorderColumns расставляет колонки доски в указанном порядке; перечислены должны быть все колонки
+func (s *BoardService) ReorderColumns(ctx context.Context, projectID string, req domain.BoardColumnOrderRequest) ([]*domain.ProjectBoardColumn, error) {
+	columns, err := s.columns(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if len(req.ColumnIDs) != len(columns) {
+		return nil, ErrInvalidBoardOrder
+	}
+	for _, id := range req.ColumnIDs {
+		if findBoardColumn(columns, id) == nil {
+			return nil, ErrInvalidBoardOrder
+		}
+	}
+
+	if err := s.boardRepo.ReorderColumns(ctx, projectID, req.ColumnIDs); err != nil {
+		s.logger.Error("Failed to reorder board columns", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return nil, err
+	}
+
+	return s.boardRepo.ListColumns(ctx, projectID)
+}
+
+// Move переносит карточку задачи в колонку доски. Перенос в колонку со статусом меняет
+// статус задачи с проверкой допустимости перехода, перенос в колонку без статуса статус
+// не меняет. Место в колонке задается дробным рангом между соседними карточками
+func (s *BoardService) Move(ctx context.Context, projectID string, req domain.BoardMoveRequest, userID string) (*domain.TaskResponse, error) {
+	task, err := s.taskRepo.GetByID(ctx, req.TaskID)
+	if err != nil {
+		return nil, err
+	}
+	if task == nil || task.ProjectID != projectID {
+		return nil, ErrTaskNotFound
+	}
+
+	// Задачи архивного проекта доступны только для чтения
+	if err := s.projectSvc.ensureProjectWritable(ctx, projectID); err != nil {
+		return nil, err
+	}
+	if !s.taskSvc.canManageTask(ctx, projectID, userID) {
+		return nil, ErrInsufficientRights
+	}
+
+	// Допускается не больше одного ориентира
+	targets := 0
+	for _, set := range []bool{req.AfterID != nil, req.BeforeID != nil, req.Position != nil} {
+		if set {
+			targets++
+		}
+	}
+	if targets > 1 {
+		return nil, ErrInvalidTaskMove
+	}
+
+	columns, err := s.columns(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	column := findBoardColumn(columns, req.ColumnID)
+	if column == nil {
+		return nil, ErrBoardColumnNotFound
+	}
+
+	if column.Status != nil {
+		if task.Status != *column.Status {
+			if _, err := s.taskSvc.UpdateStatus(ctx, task.ID, *column.Status, userID); err != nil {
+				return nil, err
+			}
+		}
+		if err := s.boardRepo.DeletePlacement(ctx, task.ID); err != nil {
+			return nil, err
+		}
+	} else if err := s.boardRepo.SetPlacement(ctx, task.ID, column.ID); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Board card moved", map[string]interface{}{
+		"task_id":   task.ID,
+		"column_id": column.ID,
+		"user_id":   userID,
+	})
+
+	if targets == 0 {
+		return s.taskSvc.GetByID(ctx, task.ID, userID)
+	}
+	return s.taskSvc.Move(ctx, task.ID, domain.TaskMoveRequest{
+		AfterID:  req.AfterID,
+		BeforeID: req.BeforeID,
+		Position: req.Position,
+	}, userID)
+}
+
+// layout возвращает колонки доски проекта и колонки без статуса, в которые перенесены задачи
+func (s *BoardService) layout(ctx context.Context, projectID string) ([]*domain.ProjectBoardColumn, map[string]string, error) {
+	columns, err := s.columns(ctx, projectID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	placements, err := s.boardRepo.ListPlacements(ctx, projectID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return columns, placements, nil
+}
+
+// columns возвращает колонки доски проекта. Доска без колонок заполняется колонками
+// по статусам задач, как до настройки доски
+func (s *BoardService) columns(ctx context.Context, projectID string) ([]*domain.ProjectBoardColumn, error) {
+	columns, err := s.boardRepo.ListColumns(ctx, projectID)
+	if err != nil || len(columns) > 0 {
+		return columns, err
+	}
+
+	now := time.Now()
+	defaults := make([]*domain.ProjectBoardColumn, len(boardStatuses))
+	for i, status := range boardStatuses {
+		status := status
+		defaults[i] = &domain.ProjectBoardColumn{
+			ID:        uuid.New().String(),
+			ProjectID: projectID,
+			Name:      boardColumnNames[status],
+			Status:    &status,
+			Position:  i,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+	}
+	if err := s.boardRepo.SeedColumns(ctx, projectID, defaults); err != nil {
+		s.logger.Error("Failed to seed board columns", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return nil, err
+	}
+
+	return s.boardRepo.ListColumns(ctx, projectID)
+}
+
+// checkBoardColumnConflict проверяет, что имя и статус колонки не заняты другими колонками доски
+func checkBoardColumnConflict(columns []*domain.ProjectBoardColumn, columnID string, req domain.BoardColumnRequest) error {
+	for _, column := range columns {
+		if column.ID == columnID {
+			continue
+		}
+		if column.Name == req.Name {
+			return ErrBoardColumnExists
+		}
+		if column.Status != nil && req.Status != nil && *column.Status == *req.Status {
+			return ErrBoardColumnExists
+		}
+	}
+	return nil
+}
+
+// findBoardColumn ищет колонку по ID
+func findBoardColumn(columns []*domain.ProjectBoardColumn, id string) *domain.ProjectBoardColumn {
+	for _, column := range columns {
+		if column.ID == id {
+			return column
+		}
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/nurlyy/task_manager/internal/messaging"
+	"github.com/nurlyy/task_manager/internal/repository/cache"
+	"github.com/nurlyy/task_manager/pkg/config"
+	"github.com/nurlyy/task_manager/pkg/logger"
+	"github.com/segmentio/kafka-go"
+)
+
+// CacheInvalidationTopics содержит топики событий, по которым сбрасывается кэш, по типам событий
+type CacheInvalidationTopics struct {
+	Task    []string
+	Comment []string
+	Project []string
+	Member  []string
+}
+
+// CacheInvalidationService сбрасывает кэш задач и проектов по событиям из Kafka.
+// Продюсер API сбрасывает кэш сам при публикации, а потребитель дополняет его событиями
+// других процессов (планировщика, почтового шлюза) и повторно сбрасывает ключи,
+// которые успели заполниться устаревшими данными между изменением и публикацией
+type CacheInvalidationService struct {
+	cacheRepo *cache.RedisRepository
+	logger    logger.Logger
+}
+
+// NewCacheInvalidationService создает новый экземпляр CacheInvalidationService
+func NewCacheInvalidationService(cacheRepo *cache.RedisRepository, logger logger.Logger) *CacheInvalidationService {
+	return &CacheInvalidationService{
+		cacheRepo: cacheRepo,
+		logger:    logger,
+	}
+}
+
+// Start запускает чтение событий в фоне до отмены контекста. Экземпляры API читают
+// события одной группой: кэш общий, и каждое событие достаточно обработать один раз
+func (s *CacheInvalidationService) Start(ctx context.Context, kafkaConfig *config.KafkaConfig, topics CacheInvalidationTopics) error {
+	decoders := make(map[string]func([]byte) (interface{}, error))
+	for _, topic := range topics.Task {
+		decoders[topic] = decodeEvent[messaging.TaskEvent]
+	}
+	for _, topic := range topics.Comment {
+		decoders[topic] = decodeEvent[messaging.CommentEvent]
+	}
+	for _, topic := range topics.Project {
+		decoders[topic] = decodeEvent[messaging.ProjectEvent]
+	}
+	for _, topic := range topics.Member {
+		decoders[topic] = decodeEvent[messaging.ProjectMemberEvent]
+	}
+
+	groupTopics := make([]string, 0, len(decoders))
+	for topic := range decoders {
+		groupTopics = append(groupTopics, topic)
+	}
+
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:         kafkaConfig.Brokers,
+		GroupTopics:     groupTopics,
+		GroupID:         kafkaConfig.Namespaced("cache-invalidation-group"),
+		MinBytes:        1,
+		MaxBytes:        10e6, // 10MB
+		MaxWait:         100 * time.Millisecond,
+		CommitInterval:  time.Second,
+		ReadLagInterval: -1,
+	})
+
+	s.logger.Info("Starting cache invalidation", map[string]interface{}{
+		"topics": groupTopics,
+	})
+
+	go func() {
+		defer reader.Close()
+
+		for {
+			message, err := reader.ReadMessage(ctx)
+			if err != nil {
+				if ctx.Err() != nil {
+					s.logger.Info("Stopping cache invalidation")
+					return
+				}
+				s.logger.Error("Failed to read event for cache invalidation", err)
+				continue
+			}
+
+			decode, ok := decoders[message.Topic]
+			if !ok {
+				continue
+			}
+			event, err := decode(message.Value)
+			if err != nil {
+				s.logger.Error("Failed to decode event for cache invalidation", err, map[string]interface{}{
+					"topic": message.Topic,
+					"key":   string(message.Key),
+				})
+				continue
+			}
+
+			s.cacheRepo.InvalidateEvent(ctx, event)
+		}
+	}()
+
+	return nil
+}
+
+// decodeEvent десериализует событие в значение того типа, который публикует продюсер
+func decodeEvent[T any](data []byte) (interface{}, error) {
+	var event T
+	if err := json.Unmarshal(data, &event); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal event: %w", err)
+	}
+	return event, nil
+}
@@ -0,0 +1,18 @@
+package service
+
+import (
+	"net/http"
+
+	"github.com/nurlyy/task_manager/pkg/circuit"
+)
+
+// deliveryStatusError классифицирует ошибку ответа внешнего сервиса для выключателя.
+// Ответы 4xx, кроме таймаута и превышения лимита запросов, относятся к конкретному
+// запросу (удаленный вебхук, заблокированный бот) и не говорят о неисправности сервиса
+func deliveryStatusError(statusCode int, err error) error {
+	if statusCode >= 400 && statusCode < 500 &&
+		statusCode != http.StatusRequestTimeout && statusCode != http.StatusTooManyRequests {
+		return circuit.Exclude(err)
+	}
+	return err
+}
@@ -10,21 +10,36 @@ import (
 	"github.com/nurlyy/task_manager/internal/domain"
 	"github.com/nurlyy/task_manager/internal/messaging"
 	"github.com/nurlyy/task_manager/internal/repository"
+	"github.com/nurlyy/task_manager/internal/repository/cache"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
 	"github.com/nurlyy/task_manager/pkg/logger"
 )
 
 // Стандартные ошибки
 var (
-	ErrCommentNotFound     = errors.New("comment not found")
-	ErrCommentAccessDenied = errors.New("access to comment denied")
+	ErrCommentNotFound      = apperrors.New(apperrors.CodeCommentNotFound, "comment not found")
+	ErrCommentAccessDenied  = apperrors.New(apperrors.CodeAccessDenied, "access to comment denied")
+	ErrCommentDraftNotFound = apperrors.New(apperrors.CodeDraftNotFound, "comment draft not found")
+	ErrInvalidParentComment = apperrors.New(apperrors.CodeInvalidParent, "parent comment must belong to the same task")
 )
 
+// commentDraftTTL определяет время хранения черновика комментария
+const commentDraftTTL = 7 * 24 * time.Hour
+
+// commentReplyDepth ограничивает глубину ответов, вложенных в комментарий в дереве комментариев
+const commentReplyDepth = 3
+
+// commentRepliesPerRoot ограничивает число ответов, вложенных в один комментарий верхнего уровня
+// дерева. Остальные ответы клиент загружает постранично через GetReplies
+const commentRepliesPerRoot = 20
+
 // CommentService представляет бизнес-логику для работы с комментариями
 type CommentService struct {
 	commentRepo repository.CommentRepository
 	taskRepo    repository.TaskRepository
 	userRepo    repository.UserRepository
 	taskSvc     *TaskService
+	cacheRepo   *cache.RedisRepository
 	producer    *messaging.KafkaProducer
 	logger      logger.Logger
 }
@@ -35,6 +50,7 @@ func NewCommentService(
 	taskRepo repository.TaskRepository,
 	userRepo repository.UserRepository,
 	taskSvc *TaskService,
+	cacheRepo *cache.RedisRepository,
 	producer *messaging.KafkaProducer,
 	logger logger.Logger,
 ) *CommentService {
@@ -43,6 +59,7 @@ func NewCommentService(
 		taskRepo:    taskRepo,
 		userRepo:    userRepo,
 		taskSvc:     taskSvc,
+		cacheRepo:   cacheRepo,
 		producer:    producer,
 		logger:      logger,
 	}
@@ -64,15 +81,36 @@ func (s *CommentService) Create(ctx context.Context, req domain.CommentCreateReq
 		return nil, ErrTaskAccessDenied
 	}
 
+	// Комментировать задачи архивного проекта нельзя
+	if err := s.taskSvc.projectSvc.ensureProjectWritable(ctx, task.ProjectID); err != nil {
+		return nil, err
+	}
+
+	// Ответить можно только на комментарий той же задачи
+	var parent *domain.Comment
+	if req.ParentCommentID != nil {
+		parent, err = s.commentRepo.GetByID(ctx, *req.ParentCommentID)
+		if err != nil {
+			s.logger.Error("Failed to get parent comment", err, map[string]interface{}{
+				"parent_comment_id": *req.ParentCommentID,
+			})
+			return nil, err
+		}
+		if parent == nil || parent.TaskID != req.TaskID {
+			return nil, ErrInvalidParentComment
+		}
+	}
+
 	// Создаем новый комментарий
 	now := time.Now()
 	comment := &domain.Comment{
-		ID:        uuid.New().String(),
-		TaskID:    req.TaskID,
-		UserID:    userID,
-		Content:   req.Content,
-		CreatedAt: now,
-		UpdatedAt: now,
+		ID:              uuid.New().String(),
+		TaskID:          req.TaskID,
+		UserID:          userID,
+		ParentCommentID: req.ParentCommentID,
+		Content:         req.Content,
+		CreatedAt:       now,
+		UpdatedAt:       now,
 	}
 
 	// Сохраняем комментарий в БД
@@ -81,6 +119,9 @@ func (s *CommentService) Create(ctx context.Context, req domain.CommentCreateReq
 		return nil, err
 	}
 
+	// Подробности задачи в кэше содержат страницу комментариев и их число
+	s.taskSvc.invalidateTaskCache(ctx, []string{comment.TaskID})
+
 	// Получаем данные пользователя
 	user, err := s.userRepo.GetByID(ctx, userID)
 	if err != nil {
@@ -118,8 +159,19 @@ func (s *CommentService) Create(ctx context.Context, req domain.CommentCreateReq
 		})
 	}
 
-	// Отправляем уведомление о комментарии автору и исполнителю задачи (если они не являются автором комментария)
-	s.notifyAboutComment(ctx, task, comment, userID)
+	// Отправляем уведомление о комментарии автору и исполнителю задачи, а для ответа - и автору
+	// родительского комментария (если они не являются автором комментария)
+	s.notifyAboutComment(ctx, task, comment, parent, userID)
+
+	// Удаляем черновик комментария после успешной публикации
+	if err := s.cacheRepo.DeleteCommentDraft(ctx, req.TaskID, userID); err != nil {
+		s.logger.Warn("Failed to delete comment draft", map[string]interface{}{
+			"task_id": req.TaskID,
+			"user_id": userID,
+		}, map[string]interface{}{
+			"error": err,
+		})
+	}
 
 	// Формируем ответ
 	resp := comment.ToResponse(userBrief)
@@ -174,6 +226,72 @@ func (s *CommentService) GetByID(ctx context.Context, id string, userID string)
 	return &resp, nil
 }
 
+// GetReplies возвращает страницу прямых ответов на комментарий, от старых к новым,
+// с вложенными в них ответами в пределах ограничений дерева комментариев
+func (s *CommentService) GetReplies(ctx context.Context, id string, userID string, page domain.PageRequest) (*domain.PagedResponse, error) {
+	comment, err := s.commentRepo.GetByID(ctx, id)
+	if err != nil {
+		s.logger.Error("Failed to get comment", err, map[string]interface{}{
+			"id": id,
+		})
+		return nil, err
+	}
+	if comment == nil {
+		return nil, ErrCommentNotFound
+	}
+
+	// Проверяем доступ пользователя к задаче комментария
+	task, err := s.taskRepo.GetByID(ctx, comment.TaskID)
+	if err != nil {
+		s.logger.Error("Failed to get task for comment access check", err, map[string]interface{}{
+			"task_id": comment.TaskID,
+		})
+		return nil, ErrTaskNotFound
+	}
+
+	if !s.taskSvc.hasAccessToTask(ctx, task.ProjectID, userID) {
+		return nil, ErrCommentAccessDenied
+	}
+
+	orderBy, orderDir := "created_at", "asc"
+	filter := repository.CommentFilter{
+		ParentIDs: []string{id},
+		OrderBy:   &orderBy,
+		OrderDir:  &orderDir,
+		Limit:     page.Limit(),
+		Offset:    page.Offset(),
+	}
+
+	replies, err := s.commentRepo.List(ctx, filter)
+	if err != nil {
+		s.logger.Error("Failed to get comment replies", err, map[string]interface{}{
+			"id": id,
+		})
+		return nil, err
+	}
+
+	replies, hasMore := domain.PageItems(replies, page)
+
+	// Получаем общее количество ответов, если клиент не отказался от подсчета
+	total := 0
+	if !page.SkipCount {
+		total, err = s.commentRepo.Count(ctx, repository.CommentFilter{ParentIDs: []string{id}})
+		if err != nil {
+			s.logger.Error("Failed to count comment replies", err, map[string]interface{}{
+				"id": id,
+			})
+			return nil, err
+		}
+	}
+
+	items, err := s.taskSvc.commentTree(ctx, replies)
+	if err != nil {
+		return nil, err
+	}
+
+	return domain.NewPagedResponse(items, page, total, hasMore), nil
+}
+
 // Update обновляет комментарий
 func (s *CommentService) Update(ctx context.Context, id string, req domain.CommentUpdateRequest, userID string) (*domain.CommentResponse, error) {
 	// Получаем комментарий из БД
@@ -188,8 +306,7 @@ func (s *CommentService) Update(ctx context.Context, id string, req domain.Comme
 	// Проверяем, является ли пользователь автором комментария
 	if comment.UserID != userID {
 		// Проверяем, является ли пользователь администратором
-		user, err := s.userRepo.GetByID(ctx, userID)
-		if err != nil || user.Role != domain.UserRoleAdmin {
+		if !isAdminUser(ctx, s.userRepo, userID) {
 			s.logger.Warn("User attempted to update another user's comment", map[string]interface{}{
 				"user_id": userID,
 			}, map[string]interface{}{
@@ -199,6 +316,11 @@ func (s *CommentService) Update(ctx context.Context, id string, req domain.Comme
 		}
 	}
 
+	// Комментарии архивного проекта доступны только для чтения
+	if err := s.ensureTaskWritable(ctx, comment.TaskID); err != nil {
+		return nil, err
+	}
+
 	// Обновляем содержимое комментария
 	comment.Content = req.Content
 	comment.UpdatedAt = time.Now()
@@ -210,6 +332,7 @@ func (s *CommentService) Update(ctx context.Context, id string, req domain.Comme
 		})
 		return nil, err
 	}
+	s.taskSvc.invalidateTaskCache(ctx, []string{comment.TaskID})
 
 	// Получаем данные пользователя-автора комментария
 	user, err := s.userRepo.GetByID(ctx, comment.UserID)
@@ -248,8 +371,7 @@ func (s *CommentService) Delete(ctx context.Context, id string, userID string) e
 	// Проверяем, является ли пользователь автором комментария
 	if comment.UserID != userID {
 		// Проверяем, является ли пользователь администратором
-		user, err := s.userRepo.GetByID(ctx, userID)
-		if err != nil || user.Role != domain.UserRoleAdmin {
+		if !isAdminUser(ctx, s.userRepo, userID) {
 			s.logger.Warn("User attempted to delete another user's comment", map[string]interface{}{
 				"user_id": userID,
 			}, map[string]interface{}{
@@ -259,6 +381,11 @@ func (s *CommentService) Delete(ctx context.Context, id string, userID string) e
 		}
 	}
 
+	// Комментарии архивного проекта доступны только для чтения
+	if err := s.ensureTaskWritable(ctx, comment.TaskID); err != nil {
+		return err
+	}
+
 	// Удаляем комментарий из БД
 	if err := s.commentRepo.Delete(ctx, id); err != nil {
 		s.logger.Error("Failed to delete comment", err, map[string]interface{}{
@@ -266,12 +393,13 @@ func (s *CommentService) Delete(ctx context.Context, id string, userID string) e
 		})
 		return err
 	}
+	s.taskSvc.invalidateTaskCache(ctx, []string{comment.TaskID})
 
 	return nil
 }
 
 // GetCommentsByTask возвращает комментарии к задаче
-func (s *CommentService) GetCommentsByTask(ctx context.Context, taskID string, userID string, page, pageSize int) (*domain.PagedResponse, error) {
+func (s *CommentService) GetCommentsByTask(ctx context.Context, taskID string, userID string, page domain.PageRequest) (*domain.PagedResponse, error) {
 	// Проверяем, существует ли задача
 	task, err := s.taskRepo.GetByID(ctx, taskID)
 	if err != nil {
@@ -291,8 +419,8 @@ func (s *CommentService) GetCommentsByTask(ctx context.Context, taskID string, u
 		TaskIDs:  []string{taskID},
 		OrderBy:  func() *string { s := "created_at"; return &s }(),
 		OrderDir: func() *string { s := "desc"; return &s }(),
-		Limit:    pageSize,
-		Offset:   (page - 1) * pageSize,
+		Limit:    page.Limit(),
+		Offset:   page.Offset(),
 	}
 
 	// Получаем комментарии к задаче
@@ -304,13 +432,18 @@ func (s *CommentService) GetCommentsByTask(ctx context.Context, taskID string, u
 		return nil, err
 	}
 
-	// Получаем общее количество комментариев
-	total, err := s.commentRepo.CountCommentsByTask(ctx, taskID)
-	if err != nil {
-		s.logger.Error("Failed to count comments by task", err, map[string]interface{}{
-			"task_id": taskID,
-		})
-		return nil, err
+	comments, hasMore := domain.PageItems(comments, page)
+
+	// Получаем общее количество комментариев, если клиент не отказался от подсчета
+	total := 0
+	if !page.SkipCount {
+		total, err = s.commentRepo.CountCommentsByTask(ctx, taskID)
+		if err != nil {
+			s.logger.Error("Failed to count comments by task", err, map[string]interface{}{
+				"task_id": taskID,
+			})
+			return nil, err
+		}
 	}
 
 	// Формируем ответы для комментариев
@@ -338,29 +471,125 @@ func (s *CommentService) GetCommentsByTask(ctx context.Context, taskID string, u
 	}
 
 	// Формируем ответ с пагинацией
-	return &domain.PagedResponse{
-		Items:      commentResponses,
-		TotalItems: total,
-		Page:       page,
-		PageSize:   pageSize,
-		TotalPages: (total + pageSize - 1) / pageSize,
-	}, nil
+	return domain.NewPagedResponse(commentResponses, page, total, hasMore), nil
+}
+
+// SaveDraft сохраняет черновик комментария пользователя к задаче
+func (s *CommentService) SaveDraft(ctx context.Context, taskID string, req domain.CommentDraftRequest, userID string) (*domain.CommentDraft, error) {
+	// Проверяем доступ пользователя к задаче
+	if err := s.checkTaskAccess(ctx, taskID, userID); err != nil {
+		return nil, err
+	}
+
+	draft := &domain.CommentDraft{
+		TaskID:    taskID,
+		UserID:    userID,
+		Content:   req.Content,
+		UpdatedAt: time.Now(),
+	}
+
+	// Сохраняем черновик в кэше
+	if err := s.cacheRepo.SaveCommentDraft(ctx, draft, commentDraftTTL); err != nil {
+		s.logger.Error("Failed to save comment draft", err, map[string]interface{}{
+			"task_id": taskID,
+			"user_id": userID,
+		})
+		return nil, err
+	}
+
+	return draft, nil
+}
+
+// GetDraft возвращает черновик комментария пользователя к задаче
+func (s *CommentService) GetDraft(ctx context.Context, taskID string, userID string) (*domain.CommentDraft, error) {
+	// Проверяем доступ пользователя к задаче
+	if err := s.checkTaskAccess(ctx, taskID, userID); err != nil {
+		return nil, err
+	}
+
+	// Получаем черновик из кэша
+	draft, err := s.cacheRepo.GetCommentDraft(ctx, taskID, userID)
+	if err != nil {
+		if errors.Is(err, cache.ErrKeyNotFound) {
+			return nil, ErrCommentDraftNotFound
+		}
+		s.logger.Error("Failed to get comment draft", err, map[string]interface{}{
+			"task_id": taskID,
+			"user_id": userID,
+		})
+		return nil, err
+	}
+
+	return draft, nil
+}
+
+// DeleteDraft удаляет черновик комментария пользователя к задаче
+func (s *CommentService) DeleteDraft(ctx context.Context, taskID string, userID string) error {
+	// Проверяем доступ пользователя к задаче
+	if err := s.checkTaskAccess(ctx, taskID, userID); err != nil {
+		return err
+	}
+
+	// Удаляем черновик из кэша
+	if err := s.cacheRepo.DeleteCommentDraft(ctx, taskID, userID); err != nil {
+		s.logger.Error("Failed to delete comment draft", err, map[string]interface{}{
+			"task_id": taskID,
+			"user_id": userID,
+		})
+		return err
+	}
+
+	return nil
+}
+
+// checkTaskAccess проверяет существование задачи и доступ пользователя к ней
+func (s *CommentService) checkTaskAccess(ctx context.Context, taskID string, userID string) error {
+	task, err := s.taskRepo.GetByID(ctx, taskID)
+	if err != nil || task == nil {
+		s.logger.Error("Failed to get task by ID for comment draft", err, map[string]interface{}{
+			"task_id": taskID,
+		})
+		return ErrTaskNotFound
+	}
+
+	if !s.taskSvc.hasAccessToTask(ctx, task.ProjectID, userID) {
+		return ErrTaskAccessDenied
+	}
+
+	return nil
+}
+
+// ensureTaskWritable проверяет, что проект задачи не находится в архиве
+func (s *CommentService) ensureTaskWritable(ctx context.Context, taskID string) error {
+	task, err := s.taskRepo.GetByID(ctx, taskID)
+	if err != nil || task == nil {
+		return ErrTaskNotFound
+	}
+
+	return s.taskSvc.projectSvc.ensureProjectWritable(ctx, task.ProjectID)
 }
 
 // notifyAboutComment отправляет уведомление о новом комментарии
-func (s *CommentService) notifyAboutComment(ctx context.Context, task *domain.Task, comment *domain.Comment, userID string) {
+func (s *CommentService) notifyAboutComment(ctx context.Context, task *domain.Task, comment *domain.Comment, parent *domain.Comment, userID string) {
 	// Формируем список получателей уведомления
-	recipients := make([]string, 0, 2)
+	recipients := make([]string, 0, 3)
 
 	// Добавляем автора задачи, если он не является автором комментария
 	if task.CreatedBy != userID {
 		recipients = append(recipients, task.CreatedBy)
 	}
 
-	// Добавляем исполнителя задачи, если он не является автором комментария
-	// и не является автором задачи (чтобы избежать дублирования)
-	if task.AssigneeID != nil && *task.AssigneeID != userID && *task.AssigneeID != task.CreatedBy {
-		recipients = append(recipients, *task.AssigneeID)
+	// Добавляем исполнителей задачи, если они не являются автором комментария
+	// и не являются автором задачи (чтобы избежать дублирования)
+	for _, assigneeID := range task.AssigneeIDs {
+		if assigneeID != userID && assigneeID != task.CreatedBy {
+			recipients = append(recipients, assigneeID)
+		}
+	}
+
+	// Добавляем автора родительского комментария, если он еще не в списке
+	if parent != nil && parent.UserID != userID && !containsString(recipients, parent.UserID) {
+		recipients = append(recipients, parent.UserID)
 	}
 
 	// Если нет получателей, выходим
@@ -395,6 +624,9 @@ func (s *CommentService) notifyAboutComment(ctx context.Context, task *domain.Ta
 			"project_id": task.ProjectID,
 		},
 	}
+	if parent != nil {
+		notificationEvent.MetaData["parent_comment_id"] = parent.ID
+	}
 
 	if err := s.producer.PublishNotification(ctx, notificationEvent); err != nil {
 		s.logger.Error("Failed to publish notification event", err, map[string]interface{}{
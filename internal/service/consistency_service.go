@@ -0,0 +1,369 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/internal/repository"
+	"github.com/nurlyy/task_manager/internal/repository/cache"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
+	"github.com/nurlyy/task_manager/pkg/logger"
+)
+
+const (
+	// consistencyLockKey не дает запускать проверки согласованности одновременно
+	consistencyLockKey = "consistency:run"
+	// consistencyRunTimeout ограничивает один запуск проверки; блокировка живет столько же
+	consistencyRunTimeout = 30 * time.Minute
+	// consistencySampleLimit ограничивает количество примеров расхождений в отчете
+	consistencySampleLimit = 20
+	// consistencyBatchSize - размер пачки при сверке записей из кэша с базой
+	consistencyBatchSize = 500
+)
+
+var (
+	ErrConsistencyRunning        = apperrors.New(apperrors.CodeConsistencyRunning, "consistency check is already running")
+	ErrConsistencyReportNotFound = apperrors.New(apperrors.CodeConsistencyReportNotFound, "consistency check has not been run yet")
+)
+
+// ConsistencyService сверяет производные данные с базой: кэш задач и проектов,
+// поисковые индексы, счетчики непрочитанных уведомлений и затраченное время задач.
+// Расхождения исправляются по запросу, результат сохраняется в отчете
+type ConsistencyService struct {
+	consistencyRepo repository.ConsistencyRepository
+	unreadCounter   *cache.CountingNotificationRepository
+	cacheRepo       *cache.RedisRepository
+	logger          logger.Logger
+}
+
+// NewConsistencyService создает новый экземпляр ConsistencyService
+func NewConsistencyService(
+	consistencyRepo repository.ConsistencyRepository,
+	unreadCounter *cache.CountingNotificationRepository,
+	cacheRepo *cache.RedisRepository,
+	logger logger.Logger,
+) *ConsistencyService {
+	return &ConsistencyService{
+		consistencyRepo: consistencyRepo,
+		unreadCounter:   unreadCounter,
+		cacheRepo:       cacheRepo,
+		logger:          logger,
+	}
+}
+
+// Start запускает проверку согласованности в фоне и возвращает отчет о начатом запуске
+func (s *ConsistencyService) Start(ctx context.Context, req domain.ConsistencyRequest, userID string) (*domain.ConsistencyReport, error) {
+	report, err := s.begin(ctx, req, &userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Проверка продолжается после ответа администратору, поэтому не зависит от запроса
+	runCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), consistencyRunTimeout)
+	snapshot := *report
+	go func() {
+		defer cancel()
+		s.execute(runCtx, req, report)
+	}()
+
+	return &snapshot, nil
+}
+
+// Run выполняет проверку согласованности синхронно; используется планировщиком
+func (s *ConsistencyService) Run(ctx context.Context, req domain.ConsistencyRequest) (*domain.ConsistencyReport, error) {
+	report, err := s.begin(ctx, req, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, consistencyRunTimeout)
+	defer cancel()
+	s.execute(ctx, req, report)
+
+	return report, nil
+}
+
+// GetReport возвращает отчет о последнем запуске проверки согласованности
+func (s *ConsistencyService) GetReport(ctx context.Context) (*domain.ConsistencyReport, error) {
+	report, err := s.cacheRepo.GetConsistencyReport(ctx)
+	if err != nil {
+		if errors.Is(err, cache.ErrKeyNotFound) {
+			return nil, ErrConsistencyReportNotFound
+		}
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// begin занимает блокировку запуска и сохраняет отчет о начатой проверке
+func (s *ConsistencyService) begin(ctx context.Context, req domain.ConsistencyRequest, startedBy *string) (*domain.ConsistencyReport, error) {
+	acquired, err := s.cacheRepo.AcquireLock(ctx, consistencyLockKey, consistencyRunTimeout)
+	if err != nil {
+		return nil, err
+	}
+	if !acquired {
+		return nil, ErrConsistencyRunning
+	}
+
+	report := &domain.ConsistencyReport{
+		ID:        uuid.New().String(),
+		Status:    domain.ConsistencyRunRunning,
+		Repair:    req.Repair,
+		StartedBy: startedBy,
+		StartedAt: time.Now(),
+		Results:   []*domain.ConsistencyCheckResult{},
+	}
+	if err := s.cacheRepo.SaveConsistencyReport(ctx, report); err != nil {
+		s.releaseLock(ctx)
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// execute выполняет выбранные проверки по очереди, сохраняя отчет после каждой,
+// чтобы администратор видел ход длительного запуска
+func (s *ConsistencyService) execute(ctx context.Context, req domain.ConsistencyRequest, report *domain.ConsistencyReport) {
+	defer s.releaseLock(ctx)
+
+	selected := make(map[domain.ConsistencyCheck]bool, len(req.Checks))
+	for _, check := range req.Checks {
+		selected[check] = true
+	}
+
+	status := domain.ConsistencyRunCompleted
+	for _, check := range domain.ConsistencyChecks {
+		if len(selected) > 0 && !selected[check] {
+			continue
+		}
+
+		result := s.runCheck(ctx, check, req)
+		if result.Error != "" {
+			status = domain.ConsistencyRunFailed
+		}
+		report.Results = append(report.Results, result)
+
+		if err := s.cacheRepo.SaveConsistencyReport(ctx, report); err != nil {
+			s.logger.Warn("Failed to save consistency report", map[string]interface{}{
+				"check": check,
+			}, map[string]interface{}{
+				"error": err,
+			})
+		}
+	}
+
+	finishedAt := time.Now()
+	report.Status = status
+	report.FinishedAt = &finishedAt
+	if err := s.cacheRepo.SaveConsistencyReport(ctx, report); err != nil {
+		s.logger.Error("Failed to save consistency report", err, map[string]interface{}{
+			"id": report.ID,
+		})
+	}
+
+	fields := map[string]interface{}{
+		"id":     report.ID,
+		"status": report.Status,
+		"repair": report.Repair,
+	}
+	for _, result := range report.Results {
+		fields[string(result.Check)] = fmt.Sprintf("drifted=%d repaired=%d", result.Drifted, result.Repaired)
+	}
+	s.logger.Info("Consistency check finished", fields)
+}
+
+// runCheck выполняет одну проверку
+func (s *ConsistencyService) runCheck(ctx context.Context, check domain.ConsistencyCheck, req domain.ConsistencyRequest) *domain.ConsistencyCheckResult {
+	result := &domain.ConsistencyCheckResult{Check: check}
+
+	var err error
+	switch check {
+	case domain.ConsistencyCheckTaskCache:
+		err = s.checkCachedEntities(ctx, result, req.Repair,
+			s.cacheRepo.ListCachedTaskVersions, s.consistencyRepo.GetTaskVersions, s.cacheRepo.InvalidateTasks)
+	case domain.ConsistencyCheckProjectCache:
+		err = s.checkCachedEntities(ctx, result, req.Repair,
+			s.cacheRepo.ListCachedProjectVersions, s.consistencyRepo.GetProjectVersions, s.cacheRepo.InvalidateProjects)
+	case domain.ConsistencyCheckSearchIndex:
+		err = s.checkSearchIndexes(ctx, result, req.Repair, req.Reindex)
+	case domain.ConsistencyCheckUnreadCounts:
+		err = s.checkUnreadCounts(ctx, result, req.Repair)
+	case domain.ConsistencyCheckSpentHours:
+		err = s.checkSpentHours(ctx, result, req.Repair)
+	}
+
+	if err != nil {
+		s.logger.Error("Consistency check failed", err, map[string]interface{}{
+			"check": check,
+		})
+		result.Error = err.Error()
+	}
+
+	return result
+}
+
+// checkCachedEntities сверяет время изменения сущностей в кэше с базой. Запись
+// удаленной сущности или отстающая от базы запись удаляется из кэша при исправлении
+func (s *ConsistencyService) checkCachedEntities(
+	ctx context.Context,
+	result *domain.ConsistencyCheckResult,
+	repair bool,
+	listCached func(ctx context.Context) (map[string]time.Time, error),
+	getVersions func(ctx context.Context, ids []string) (map[string]time.Time, error),
+	invalidate func(ctx context.Context, ids []string) error,
+) error {
+	cached, err := listCached(ctx)
+	if err != nil {
+		return err
+	}
+
+	ids := make([]string, 0, len(cached))
+	for id := range cached {
+		ids = append(ids, id)
+	}
+	result.Checked = len(ids)
+
+	var drifted []string
+	for start := 0; start < len(ids); start += consistencyBatchSize {
+		end := start + consistencyBatchSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+
+		versions, err := getVersions(ctx, ids[start:end])
+		if err != nil {
+			return err
+		}
+		for _, id := range ids[start:end] {
+			version, ok := versions[id]
+			if !ok || !version.Equal(cached[id]) {
+				drifted = append(drifted, id)
+			}
+		}
+	}
+
+	result.Drifted = len(drifted)
+	result.Samples = sampleOf(drifted)
+	if !repair || len(drifted) == 0 {
+		return nil
+	}
+
+	if err := invalidate(ctx, drifted); err != nil {
+		return err
+	}
+	result.Repaired = len(drifted)
+
+	return nil
+}
+
+// checkSearchIndexes находит невалидные поисковые индексы и перестраивает их при исправлении.
+// С reindex перестраиваются все поисковые индексы
+func (s *ConsistencyService) checkSearchIndexes(ctx context.Context, result *domain.ConsistencyCheckResult, repair, reindex bool) error {
+	indexes, err := s.consistencyRepo.ListSearchIndexes(ctx)
+	if err != nil {
+		return err
+	}
+	result.Checked = len(indexes)
+
+	for _, index := range indexes {
+		if !index.Valid {
+			result.Drifted++
+			result.Samples = append(result.Samples, index.Name)
+		}
+		if !(reindex || repair && !index.Valid) {
+			continue
+		}
+
+		if err := s.consistencyRepo.Reindex(ctx, index.Name); err != nil {
+			return err
+		}
+		if !index.Valid {
+			result.Repaired++
+		}
+	}
+
+	return nil
+}
+
+// checkUnreadCounts сверяет счетчики непрочитанных уведомлений с базой. Разошедшиеся
+// счетчики удаляются при исправлении и загружаются заново при следующем чтении
+func (s *ConsistencyService) checkUnreadCounts(ctx context.Context, result *domain.ConsistencyCheckResult, repair bool) error {
+	checked, drifted, err := s.unreadCounter.FindDriftedUnreadCounts(ctx, consistencyBatchSize)
+	result.Checked = checked
+	if err != nil {
+		return err
+	}
+
+	result.Drifted = len(drifted)
+	result.Samples = sampleOf(drifted)
+	if !repair {
+		return nil
+	}
+
+	for _, userID := range drifted {
+		if err := s.cacheRepo.InvalidateUnreadCount(ctx, userID); err != nil {
+			return err
+		}
+		result.Repaired++
+	}
+
+	return nil
+}
+
+// checkSpentHours сверяет затраченное время задач с суммой записей о времени.
+// При исправлении источником истины считаются записи о времени
+func (s *ConsistencyService) checkSpentHours(ctx context.Context, result *domain.ConsistencyCheckResult, repair bool) error {
+	drifts, checked, drifted, err := s.consistencyRepo.ListSpentHoursDrift(ctx, consistencySampleLimit)
+	if err != nil {
+		return err
+	}
+
+	result.Checked = checked
+	result.Drifted = drifted
+	for _, drift := range drifts {
+		result.Samples = append(result.Samples, fmt.Sprintf("%s: spent %.2f, logged %.2f",
+			drift.TaskID, drift.SpentHours, drift.LoggedHours))
+	}
+	if !repair || drifted == 0 {
+		return nil
+	}
+
+	ids, err := s.consistencyRepo.RecalculateSpentHours(ctx)
+	if err != nil {
+		return err
+	}
+	result.Repaired = len(ids)
+
+	if err := s.cacheRepo.InvalidateTasks(ctx, ids); err != nil {
+		s.logger.Warn("Failed to invalidate recalculated tasks", map[string]interface{}{
+			"count": len(ids),
+		}, map[string]interface{}{
+			"error": err,
+		})
+	}
+
+	return nil
+}
+
+// releaseLock освобождает блокировку запуска проверки
+func (s *ConsistencyService) releaseLock(ctx context.Context) {
+	if err := s.cacheRepo.ReleaseLock(context.WithoutCancel(ctx), consistencyLockKey); err != nil {
+		s.logger.Warn("Failed to release consistency lock", nil, map[string]interface{}{
+			"error": err,
+		})
+	}
+}
+
+// sampleOf возвращает не больше consistencySampleLimit первых значений
+func sampleOf(values []string) []string {
+	if len(values) > consistencySampleLimit {
+		return values[:consistencySampleLimit]
+	}
+	return values
+}
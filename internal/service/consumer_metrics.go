@@ -0,0 +1,155 @@
+package service
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// consumerPartition определяет партицию топика, из которой читает потребитель
+type consumerPartition struct {
+	topic     string
+	partition int
+}
+
+// ConsumerMetrics собирает показатели потребителя Kafka: отставание по партициям,
+// число обрабатываемых сообщений и приостановки чтения. Отдает метрики в текстовом
+// формате Prometheus с префиксом name
+type ConsumerMetrics struct {
+	name        string
+	maxInFlight int
+
+	mu        sync.Mutex
+	lag       map[consumerPartition]int64
+	inFlight  int
+	paused    string // Канал, из-за которого приостановлено чтение; пустой, если чтение идет
+	pauses    map[string]uint64
+	processed uint64
+	failed    uint64
+}
+
+// newConsumerMetrics создает новый экземпляр ConsumerMetrics
+func newConsumerMetrics(name string, maxInFlight int) *ConsumerMetrics {
+	return &ConsumerMetrics{
+		name:        name,
+		maxInFlight: maxInFlight,
+		lag:         make(map[consumerPartition]int64),
+		pauses:      make(map[string]uint64),
+	}
+}
+
+// received учитывает прочитанное сообщение: отставание партиции - число сообщений
+// после него до конца партиции на момент чтения
+func (m *ConsumerMetrics) received(message kafka.Message) int64 {
+	lag := message.HighWaterMark - message.Offset - 1
+	if lag < 0 {
+		lag = 0
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.lag[consumerPartition{topic: message.Topic, partition: message.Partition}] = lag
+	m.inFlight++
+	return lag
+}
+
+// done учитывает завершение обработки сообщения
+func (m *ConsumerMetrics) done(err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.inFlight--
+	m.processed++
+	if err != nil {
+		m.failed++
+	}
+}
+
+// pause отмечает приостановку чтения из-за ограничения частоты канала
+func (m *ConsumerMetrics) pause(channel string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.paused = channel
+	m.pauses[channel]++
+}
+
+// resume отмечает возобновление чтения
+func (m *ConsumerMetrics) resume() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.paused = ""
+}
+
+// WritePrometheus записывает метрики потребителя в текстовом формате Prometheus
+func (m *ConsumerMetrics) WritePrometheus(w io.Writer) error {
+	m.mu.Lock()
+	partitions := make([]consumerPartition, 0, len(m.lag))
+	lag := make(map[consumerPartition]int64, len(m.lag))
+	for partition, value := range m.lag {
+		partitions = append(partitions, partition)
+		lag[partition] = value
+	}
+	channels := make([]string, 0, len(m.pauses))
+	pauses := make(map[string]uint64, len(m.pauses))
+	for channel, count := range m.pauses {
+		channels = append(channels, channel)
+		pauses[channel] = count
+	}
+	inFlight, processed, failed := m.inFlight, m.processed, m.failed
+	paused := 0
+	if m.paused != "" {
+		paused = 1
+	}
+	m.mu.Unlock()
+
+	sort.Slice(partitions, func(i, j int) bool {
+		if partitions[i].topic != partitions[j].topic {
+			return partitions[i].topic < partitions[j].topic
+		}
+		return partitions[i].partition < partitions[j].partition
+	})
+	sort.Strings(channels)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# HELP %s_consumer_lag Messages left in the partition after the last one read.\n", m.name)
+	fmt.Fprintf(&b, "# TYPE %s_consumer_lag gauge\n", m.name)
+	for _, partition := range partitions {
+		fmt.Fprintf(&b, "%s_consumer_lag{topic=%q,partition=\"%d\"} %d\n", m.name, partition.topic, partition.partition, lag[partition])
+	}
+
+	fmt.Fprintf(&b, "# HELP %s_consumer_in_flight Messages being processed.\n", m.name)
+	fmt.Fprintf(&b, "# TYPE %s_consumer_in_flight gauge\n", m.name)
+	fmt.Fprintf(&b, "%s_consumer_in_flight %d\n", m.name, inFlight)
+
+	fmt.Fprintf(&b, "# HELP %s_consumer_max_in_flight Limit of messages processed at once.\n", m.name)
+	fmt.Fprintf(&b, "# TYPE %s_consumer_max_in_flight gauge\n", m.name)
+	fmt.Fprintf(&b, "%s_consumer_max_in_flight %d\n", m.name, m.maxInFlight)
+
+	fmt.Fprintf(&b, "# HELP %s_consumer_paused Whether consumption is paused because a delivery channel is rate limited.\n", m.name)
+	fmt.Fprintf(&b, "# TYPE %s_consumer_paused gauge\n", m.name)
+	fmt.Fprintf(&b, "%s_consumer_paused %d\n", m.name, paused)
+
+	fmt.Fprintf(&b, "# HELP %s_consumer_pauses_total Consumption pauses by rate limited delivery channel.\n", m.name)
+	fmt.Fprintf(&b, "# TYPE %s_consumer_pauses_total counter\n", m.name)
+	for _, channel := range channels {
+		fmt.Fprintf(&b, "%s_consumer_pauses_total{channel=%q} %d\n", m.name, channel, pauses[channel])
+	}
+
+	fmt.Fprintf(&b, "# HELP %s_consumer_processed_total Messages processed.\n", m.name)
+	fmt.Fprintf(&b, "# TYPE %s_consumer_processed_total counter\n", m.name)
+	fmt.Fprintf(&b, "%s_consumer_processed_total %d\n", m.name, processed)
+
+	fmt.Fprintf(&b, "# HELP %s_consumer_failed_total Messages whose processing failed.\n", m.name)
+	fmt.Fprintf(&b, "# TYPE %s_consumer_failed_total counter\n", m.name)
+	fmt.Fprintf(&b, "%s_consumer_failed_total %d\n", m.name, failed)
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,309 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/internal/repository"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
+	"github.com/nurlyy/task_manager/pkg/logger"
+)
+
+var (
+	ErrDecisionNotFound        = apperrors.New(apperrors.CodeDecisionNotFound, "decision not found")
+	ErrDecisionApproverInvalid = apperrors.New(apperrors.CodeInvalidApprover, "decision approver must be a member of the project")
+	ErrDecisionTaskInvalid     = apperrors.New(apperrors.CodeInvalidTask, "decision can only be linked to tasks of its project")
+	ErrDecisionTaskLinked      = apperrors.New(apperrors.CodeTaskAlreadyLinked, "task is already linked to the decision")
+)
+
+// DecisionService представляет бизнес-логику журнала решений проекта
+type DecisionService struct {
+	decisionRepo repository.DecisionRepository
+	taskRepo     repository.TaskRepository
+	projectRepo  repository.ProjectRepository
+	projectSvc   *ProjectService
+	taskSvc      *TaskService
+	logger       logger.Logger
+}
+
+// NewDecisionService создает новый экземпляр DecisionService
+func NewDecisionService(
+	decisionRepo repository.DecisionRepository,
+	taskRepo repository.TaskRepository,
+	projectRepo repository.ProjectRepository,
+	projectSvc *ProjectService,
+	taskSvc *TaskService,
+	logger logger.Logger,
+) *DecisionService {
+	return &DecisionService{
+		decisionRepo: decisionRepo,
+		taskRepo:     taskRepo,
+		projectRepo:  projectRepo,
+		projectSvc:   projectSvc,
+		taskSvc:      taskSvc,
+		logger:       logger,
+	}
+}
+
+// Create записывает решение в журнал проекта
+func (s *DecisionService) Create(ctx context.Context, projectID string, req domain.DecisionCreateRequest, userID string) (*domain.Decision, error) {
+	if err := s.projectSvc.ensureProjectWritable(ctx, projectID); err != nil {
+		return nil, err
+	}
+
+	if req.ApproverID != nil {
+		if err := s.checkApprover(ctx, projectID, *req.ApproverID); err != nil {
+			return nil, err
+		}
+	}
+
+	taskIDs := uniqueIDs(req.TaskIDs)
+	for _, taskID := range taskIDs {
+		if err := s.checkTask(ctx, projectID, taskID); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	decidedAt := now
+	if req.DecidedAt != nil {
+		decidedAt = *req.DecidedAt
+	}
+
+	decision := &domain.Decision{
+		ID:           uuid.New().String(),
+		ProjectID:    projectID,
+		Title:        req.Title,
+		Context:      req.Context,
+		Decision:     req.Decision,
+		Alternatives: req.Alternatives,
+		ApproverID:   req.ApproverID,
+		DecidedAt:    &decidedAt,
+		CreatedBy:    userID,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+		TaskIDs:      taskIDs,
+	}
+	if decision.Alternatives == nil {
+		decision.Alternatives = []string{}
+	}
+
+	if err := s.decisionRepo.Create(ctx, decision); err != nil {
+		s.logger.Error("Failed to create decision", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return nil, err
+	}
+
+	return decision, nil
+}
+
+// List возвращает журнал решений проекта с поиском по тексту и фильтром по задаче
+func (s *DecisionService) List(ctx context.Context, projectID string, search string, taskID *string, page domain.PageRequest) (*domain.PagedResponse, error) {
+	filter := repository.DecisionFilter{
+		TaskID: taskID,
+		Limit:  page.Limit(),
+		Offset: page.Offset(),
+	}
+	if search = strings.TrimSpace(search); search != "" {
+		pattern := escapeLikePattern(search)
+		filter.Search = &pattern
+	}
+
+	decisions, err := s.decisionRepo.List(ctx, projectID, filter)
+	if err != nil {
+		return nil, err
+	}
+	decisions, hasMore := domain.PageItems(decisions, page)
+	total := 0
+	if !page.SkipCount {
+		total, err = s.decisionRepo.Count(ctx, projectID, filter)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return domain.NewPagedResponse(decisions, page, total, hasMore), nil
+}
+
+// Get возвращает решение
+func (s *DecisionService) Get(ctx context.Context, id string, userID string) (*domain.Decision, error) {
+	return s.getAccessibleDecision(ctx, id, userID)
+}
+
+// Update обновляет решение
+func (s *DecisionService) Update(ctx context.Context, id string, req domain.DecisionUpdateRequest, userID string) (*domain.Decision, error) {
+	decision, err := s.getAccessibleDecision(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkCanEdit(ctx, decision.ProjectID, userID); err != nil {
+		return nil, err
+	}
+
+	if req.Title != nil {
+		decision.Title = *req.Title
+	}
+	if req.Context != nil {
+		decision.Context = *req.Context
+	}
+	if req.Decision != nil {
+		decision.Decision = *req.Decision
+	}
+	if req.Alternatives != nil {
+		decision.Alternatives = *req.Alternatives
+		if decision.Alternatives == nil {
+			decision.Alternatives = []string{}
+		}
+	}
+	if req.ApproverID != nil {
+		if err := s.checkApprover(ctx, decision.ProjectID, *req.ApproverID); err != nil {
+			return nil, err
+		}
+		decision.ApproverID = req.ApproverID
+	}
+	if req.DecidedAt != nil {
+		decision.DecidedAt = req.DecidedAt
+	}
+	decision.UpdatedAt = time.Now()
+
+	if err := s.decisionRepo.Update(ctx, decision); err != nil {
+		s.logger.Error("Failed to update decision", err, map[string]interface{}{
+			"id": id,
+		})
+		return nil, err
+	}
+
+	return decision, nil
+}
+
+// Delete удаляет решение. Удалить решение может его автор или менеджер проекта
+func (s *DecisionService) Delete(ctx context.Context, id string, userID string) error {
+	decision, err := s.getAccessibleDecision(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+
+	if decision.CreatedBy != userID && !s.projectSvc.canManageProject(ctx, decision.ProjectID, userID) {
+		return ErrInsufficientRights
+	}
+
+	if err := s.projectSvc.ensureProjectWritable(ctx, decision.ProjectID); err != nil {
+		return err
+	}
+
+	return s.decisionRepo.Delete(ctx, id)
+}
+
+// LinkTask связывает решение с задачей того же проекта
+func (s *DecisionService) LinkTask(ctx context.Context, id string, req domain.DecisionTaskRequest, userID string) (*domain.Decision, error) {
+	decision, err := s.getAccessibleDecision(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkCanEdit(ctx, decision.ProjectID, userID); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkTask(ctx, decision.ProjectID, req.TaskID); err != nil {
+		return nil, err
+	}
+
+	linked, err := s.decisionRepo.LinkTask(ctx, id, req.TaskID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !linked {
+		return nil, ErrDecisionTaskLinked
+	}
+
+	return s.decisionRepo.GetByID(ctx, id)
+}
+
+// UnlinkTask удаляет связь решения с задачей
+func (s *DecisionService) UnlinkTask(ctx context.Context, id string, taskID string, userID string) error {
+	decision, err := s.getAccessibleDecision(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.checkCanEdit(ctx, decision.ProjectID, userID); err != nil {
+		return err
+	}
+
+	return s.decisionRepo.UnlinkTask(ctx, id, taskID)
+}
+
+// ListTaskDecisions возвращает решения, связанные с задачей
+func (s *DecisionService) ListTaskDecisions(ctx context.Context, taskID string, userID string) ([]*domain.Decision, error) {
+	task, err := s.taskRepo.GetByID(ctx, taskID)
+	if err != nil || task == nil {
+		return nil, ErrTaskNotFound
+	}
+
+	if !s.taskSvc.hasAccessToTask(ctx, task.ProjectID, userID) {
+		return nil, ErrTaskAccessDenied
+	}
+
+	return s.decisionRepo.ListByTask(ctx, taskID)
+}
+
+// getAccessibleDecision возвращает решение, если у пользователя есть доступ к его проекту
+func (s *DecisionService) getAccessibleDecision(ctx context.Context, id string, userID string) (*domain.Decision, error) {
+	decision, err := s.decisionRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if decision == nil || !s.projectSvc.hasAccessToProject(ctx, decision.ProjectID, userID) {
+		return nil, ErrDecisionNotFound
+	}
+	return decision, nil
+}
+
+// checkCanEdit проверяет, что пользователь может вести журнал решений проекта
+func (s *DecisionService) checkCanEdit(ctx context.Context, projectID string, userID string) error {
+	if !s.projectSvc.hasAccessToProject(ctx, projectID, userID) {
+		return ErrProjectNotFound
+	}
+	if !s.taskSvc.canManageTask(ctx, projectID, userID) {
+		return ErrInsufficientRights
+	}
+	return s.projectSvc.ensureProjectWritable(ctx, projectID)
+}
+
+// checkApprover проверяет, что утвердивший решение является участником проекта
+func (s *DecisionService) checkApprover(ctx context.Context, projectID string, approverID string) error {
+	member, err := s.projectRepo.GetMember(ctx, projectID, approverID)
+	if err != nil || member == nil {
+		return ErrDecisionApproverInvalid
+	}
+	return nil
+}
+
+// checkTask проверяет, что задача существует и принадлежит проекту решения
+func (s *DecisionService) checkTask(ctx context.Context, projectID string, taskID string) error {
+	task, err := s.taskRepo.GetByID(ctx, taskID)
+	if err != nil || task == nil || task.ProjectID != projectID {
+		return ErrDecisionTaskInvalid
+	}
+	return nil
+}
+
+// uniqueIDs удаляет повторяющиеся ID, сохраняя порядок
+func uniqueIDs(ids []string) []string {
+	result := make([]string, 0, len(ids))
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			result = append(result, id)
+		}
+	}
+	return result
+}
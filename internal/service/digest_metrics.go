@@ -0,0 +1,114 @@
+package service
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+)
+
+// DigestMetrics собирает показатели рассылки ежедневных дайджестов: ход текущей
+// рассылки и накопленные счетчики. Отдает метрики в текстовом формате Prometheus
+type DigestMetrics struct {
+	mu            sync.Mutex
+	running       bool
+	runProcessed  int
+	processed     uint64
+	sent          uint64
+	failed        uint64
+	completed     uint64
+	lastCompleted time.Time
+}
+
+// newDigestMetrics создает новый экземпляр DigestMetrics
+func newDigestMetrics() *DigestMetrics {
+	return &DigestMetrics{}
+}
+
+// start отмечает начало рассылки; при продолжении учитываются уже обработанные пользователи
+func (m *DigestMetrics) start(checkpoint *domain.DigestCheckpoint) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.running = true
+	m.runProcessed = checkpoint.Processed
+}
+
+// page учитывает обработанную страницу пользователей
+func (m *DigestMetrics) page(processed, sent, failed int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.runProcessed += processed
+	m.processed += uint64(processed)
+	m.sent += uint64(sent)
+	m.failed += uint64(failed)
+}
+
+// complete отмечает успешное завершение рассылки
+func (m *DigestMetrics) complete() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.completed++
+	m.lastCompleted = time.Now()
+}
+
+// finish отмечает окончание рассылки, в том числе прерванной
+func (m *DigestMetrics) finish() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.running = false
+}
+
+// WritePrometheus записывает метрики рассылки дайджестов в текстовом формате Prometheus
+func (m *DigestMetrics) WritePrometheus(w io.Writer) error {
+	m.mu.Lock()
+	running := 0
+	if m.running {
+		running = 1
+	}
+	runProcessed := m.runProcessed
+	processed, sent, failed, completed := m.processed, m.sent, m.failed, m.completed
+	var lastCompleted int64
+	if !m.lastCompleted.IsZero() {
+		lastCompleted = m.lastCompleted.Unix()
+	}
+	m.mu.Unlock()
+
+	var b strings.Builder
+	b.WriteString("# HELP scheduler_digest_running Whether the daily digest run is in progress.\n")
+	b.WriteString("# TYPE scheduler_digest_running gauge\n")
+	fmt.Fprintf(&b, "scheduler_digest_running %d\n", running)
+
+	b.WriteString("# HELP scheduler_digest_run_users_processed Users processed by the current or last daily digest run.\n")
+	b.WriteString("# TYPE scheduler_digest_run_users_processed gauge\n")
+	fmt.Fprintf(&b, "scheduler_digest_run_users_processed %d\n", runProcessed)
+
+	b.WriteString("# HELP scheduler_digest_users_processed_total Users processed by daily digest runs.\n")
+	b.WriteString("# TYPE scheduler_digest_users_processed_total counter\n")
+	fmt.Fprintf(&b, "scheduler_digest_users_processed_total %d\n", processed)
+
+	b.WriteString("# HELP scheduler_digest_sent_total Daily digests created and published.\n")
+	b.WriteString("# TYPE scheduler_digest_sent_total counter\n")
+	fmt.Fprintf(&b, "scheduler_digest_sent_total %d\n", sent)
+
+	b.WriteString("# HELP scheduler_digest_failed_total Users whose daily digest could not be built.\n")
+	b.WriteString("# TYPE scheduler_digest_failed_total counter\n")
+	fmt.Fprintf(&b, "scheduler_digest_failed_total %d\n", failed)
+
+	b.WriteString("# HELP scheduler_digest_runs_completed_total Daily digest runs completed.\n")
+	b.WriteString("# TYPE scheduler_digest_runs_completed_total counter\n")
+	fmt.Fprintf(&b, "scheduler_digest_runs_completed_total %d\n", completed)
+
+	b.WriteString("# HELP scheduler_digest_last_completed_timestamp_seconds Time the last daily digest run completed.\n")
+	b.WriteString("# TYPE scheduler_digest_last_completed_timestamp_seconds gauge\n")
+	fmt.Fprintf(&b, "scheduler_digest_last_completed_timestamp_seconds %d\n", lastCompleted)
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
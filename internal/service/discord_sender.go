@@ -0,0 +1,240 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/pkg/circuit"
+	"github.com/nurlyy/task_manager/pkg/config"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
+	"github.com/nurlyy/task_manager/pkg/logger"
+)
+
+var (
+	ErrDiscordWebhookNotAllowed = apperrors.New(apperrors.CodeDiscordWebhookNotAllowed, "discord webhook url is not allowed")
+	ErrDiscordBotNotConfigured  = apperrors.New(apperrors.CodeDiscordBotNotConfigured, "discord bot is not configured")
+)
+
+// discordWebhookHosts содержит хосты, на которые Discord выдает вебхуки каналов
+var discordWebhookHosts = []string{"discord.com", "discordapp.com", "ptb.discord.com", "canary.discord.com"}
+
+// Ограничения Discord на размер embed
+const (
+	discordTitleLimit       = 256
+	discordDescriptionLimit = 4096
+	discordFieldValueLimit  = 1024
+)
+
+// discordField описывает поле embed: название и ключ метаданных уведомления со значением
+type discordField struct {
+	Name    string
+	MetaKey string
+	Inline  bool
+}
+
+// discordEmbedTemplate описывает embed для типа уведомления
+type discordEmbedTemplate struct {
+	Color  int
+	Fields []discordField
+}
+
+// discordEmbedTemplates содержит шаблоны embed по типам уведомлений
+var discordEmbedTemplates = map[domain.NotificationType]discordEmbedTemplate{
+	domain.NotificationTypeTaskAssigned: {
+		Color:  0x3498DB,
+		Fields: []discordField{{"Задача", "task_title", false}, {"Приоритет", "priority", true}, {"Срок выполнения", "due_date", true}},
+	},
+	domain.NotificationTypeTaskUpdated: {
+		Color:  0x3498DB,
+		Fields: []discordField{{"Задача", "task_title", false}, {"Статус", "status", true}, {"Исполнитель", "assignee_name", true}},
+	},
+	domain.NotificationTypeTaskCommented: {
+		Color:  0x95A5A6,
+		Fields: []discordField{{"Задача", "task_title", false}, {"Автор комментария", "user_name", true}, {"Комментарий", "comment_content", false}},
+	},
+	domain.NotificationTypeTaskDueSoon: {
+		Color:  0xF1C40F,
+		Fields: []discordField{{"Задача", "task_title", false}, {"Срок выполнения", "due_date", true}, {"Осталось часов", "hours_left", true}},
+	},
+	domain.NotificationTypeTaskOverdue: {
+		Color:  0xE74C3C,
+		Fields: []discordField{{"Задача", "task_title", false}, {"Срок выполнения истек", "due_date", true}},
+	},
+	domain.NotificationTypeProjectMemberAdded: {
+		Color:  0x2ECC71,
+		Fields: []discordField{{"Проект", "project_name", false}, {"Роль", "role", true}},
+	},
+	domain.NotificationTypeProjectUpdated: {
+		Color:  0x3498DB,
+		Fields: []discordField{{"Проект", "project_name", false}, {"Статус", "status", true}},
+	},
+}
+
+// DiscordSender обеспечивает отправку уведомлений в каналы Discord через вебхук или бота
+type DiscordSender struct {
+	client   *http.Client
+	breaker  *circuit.Breaker
+	botToken string
+	apiURL   string
+	baseURL  string
+	logger   logger.Logger
+}
+
+// NewDiscordSender создает новый экземпляр DiscordSender
+func NewDiscordSender(config *config.DiscordConfig, baseURL string, logger logger.Logger) *DiscordSender {
+	return &DiscordSender{
+		client: &http.Client{
+			Timeout: config.Timeout,
+		},
+		breaker:  circuit.Default.Get("discord"),
+		botToken: config.BotToken,
+		apiURL:   strings.TrimRight(config.APIURL, "/"),
+		baseURL:  strings.TrimRight(baseURL, "/"),
+		logger:   logger,
+	}
+}
+
+// BotEnabled сообщает, настроен ли токен бота для отправки по ID канала
+func (s *DiscordSender) BotEnabled() bool {
+	return s.botToken != ""
+}
+
+// ValidateWebhookURL проверяет, что URL является вебхуком канала Discord
+func (s *DiscordSender) ValidateWebhookURL(rawURL string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Scheme != "https" || !strings.HasPrefix(parsed.Path, "/api/webhooks/") {
+		return ErrDiscordWebhookNotAllowed
+	}
+
+	host := strings.ToLower(parsed.Hostname())
+	for _, allowed := range discordWebhookHosts {
+		if host == allowed {
+			return nil
+		}
+	}
+	return ErrDiscordWebhookNotAllowed
+}
+
+// SendNotification публикует уведомление в канал проекта: через вебхук, если он задан, иначе ботом
+func (s *DiscordSender) SendNotification(ctx context.Context, channel *domain.ProjectDiscordChannel, notification *domain.Notification) error {
+	payload := map[string]interface{}{
+		"embeds": []map[string]interface{}{s.buildEmbed(notification)},
+		// Упоминания из текста задач не должны оповещать участников сервера
+		"allowed_mentions": map[string]interface{}{"parse": []string{}},
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode Discord message: %w", err)
+	}
+
+	var req *http.Request
+	if channel.WebhookURL != "" {
+		if err := s.ValidateWebhookURL(channel.WebhookURL); err != nil {
+			return err
+		}
+		req, err = http.NewRequestWithContext(ctx, http.MethodPost, channel.WebhookURL, bytes.NewReader(body))
+		if err != nil {
+			return fmt.Errorf("failed to create Discord request: %w", err)
+		}
+	} else {
+		if !s.BotEnabled() {
+			return ErrDiscordBotNotConfigured
+		}
+		endpoint := s.apiURL + "/channels/" + url.PathEscape(channel.ChannelID) + "/messages"
+		req, err = http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+		if err != nil {
+			return fmt.Errorf("failed to create Discord request: %w", err)
+		}
+		req.Header.Set("Authorization", "Bot "+s.botToken)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	err = s.breaker.Do(func() error {
+		resp, err := s.client.Do(req)
+		if err != nil {
+			return fmt.Errorf("failed to send Discord notification: %w", err)
+		}
+		defer resp.Body.Close()
+
+		// Вебхук отвечает 204, API бота - 200
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+			return deliveryStatusError(resp.StatusCode, fmt.Errorf("discord returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody))))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	s.logger.Debug("Discord notification sent", map[string]interface{}{
+		"project_id":        channel.ProjectID,
+		"notification_type": notification.Type,
+		"entity_id":         notification.EntityID,
+	})
+	return nil
+}
+
+// buildEmbed формирует embed по шаблону типа уведомления
+func (s *DiscordSender) buildEmbed(notification *domain.Notification) map[string]interface{} {
+	template, ok := discordEmbedTemplates[notification.Type]
+	if !ok {
+		template = discordEmbedTemplate{Color: 0x95A5A6}
+	}
+
+	fields := []map[string]interface{}{}
+	for _, field := range template.Fields {
+		if value := notification.MetaData[field.MetaKey]; value != "" {
+			fields = append(fields, map[string]interface{}{
+				"name":   field.Name,
+				"value":  truncateRunes(value, discordFieldValueLimit),
+				"inline": field.Inline,
+			})
+		}
+	}
+
+	createdAt := notification.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = time.Now()
+	}
+
+	embed := map[string]interface{}{
+		"title":       truncateRunes(notification.Title, discordTitleLimit),
+		"description": truncateRunes(notification.Content, discordDescriptionLimit),
+		"color":       template.Color,
+		"timestamp":   createdAt.UTC().Format(time.RFC3339),
+		"footer":      map[string]string{"text": "Task Manager"},
+	}
+	if len(fields) > 0 {
+		embed["fields"] = fields
+	}
+	if link := s.entityURL(notification); link != "" {
+		embed["url"] = link
+	}
+
+	return embed
+}
+
+// entityURL возвращает ссылку на задачу или проект уведомления
+func (s *DiscordSender) entityURL(notification *domain.Notification) string {
+	if s.baseURL == "" || notification.EntityID == "" {
+		return ""
+	}
+
+	switch notification.EntityType {
+	case "task":
+		return s.baseURL + "/tasks/" + url.PathEscape(notification.EntityID)
+	case "project":
+		return s.baseURL + "/projects/" + url.PathEscape(notification.EntityID)
+	}
+	return ""
+}
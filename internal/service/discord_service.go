@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/internal/repository"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
+	"github.com/nurlyy/task_manager/pkg/logger"
+)
+
+var (
+	ErrDiscordChannelNotFound = apperrors.New(apperrors.CodeDiscordChannelNotFound, "discord channel not found")
+	ErrDiscordUnreachable     = apperrors.New(apperrors.CodeDiscordUnreachable, "discord channel is unreachable")
+)
+
+// DiscordService представляет бизнес-логику подключения каналов Discord к проектам
+type DiscordService struct {
+	discordRepo   repository.DiscordRepository
+	discordSender *DiscordSender
+	logger        logger.Logger
+}
+
+// NewDiscordService создает новый экземпляр DiscordService
+func NewDiscordService(
+	discordRepo repository.DiscordRepository,
+	discordSender *DiscordSender,
+	logger logger.Logger,
+) *DiscordService {
+	return &DiscordService{
+		discordRepo:   discordRepo,
+		discordSender: discordSender,
+		logger:        logger,
+	}
+}
+
+// GetProjectChannel возвращает канал Discord проекта
+func (s *DiscordService) GetProjectChannel(ctx context.Context, projectID string) (*domain.ProjectDiscordChannel, error) {
+	channel, err := s.discordRepo.GetProjectChannel(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if channel == nil {
+		return nil, ErrDiscordChannelNotFound
+	}
+	return channel, nil
+}
+
+// SaveProjectChannel создает или обновляет канал Discord проекта.
+// При смене вебхука или ID канала в него отправляется тестовое сообщение
+func (s *DiscordService) SaveProjectChannel(ctx context.Context, projectID string, req domain.ProjectDiscordChannelRequest, userID string) (*domain.ProjectDiscordChannel, error) {
+	channel, err := s.discordRepo.GetProjectChannel(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if channel == nil {
+		// Новому каналу нужен способ доставки
+		if req.WebhookURL == "" && req.ChannelID == "" {
+			return nil, &TaskValidationError{Violations: []domain.FieldViolation{{
+				Field:   "webhook_url",
+				Message: "Webhook URL or channel ID is required",
+			}}}
+		}
+		channel = &domain.ProjectDiscordChannel{
+			ProjectID: projectID,
+			Enabled:   true,
+			CreatedBy: userID,
+			CreatedAt: now,
+		}
+	}
+
+	targetChanged := false
+	if req.WebhookURL != "" && req.WebhookURL != channel.WebhookURL {
+		if err := s.discordSender.ValidateWebhookURL(req.WebhookURL); err != nil {
+			return nil, err
+		}
+		channel.WebhookURL = req.WebhookURL
+		channel.ChannelID = ""
+		targetChanged = true
+	}
+	if req.ChannelID != "" && req.ChannelID != channel.ChannelID {
+		if !s.discordSender.BotEnabled() {
+			return nil, ErrDiscordBotNotConfigured
+		}
+		channel.ChannelID = req.ChannelID
+		channel.WebhookURL = ""
+		targetChanged = true
+	}
+	if req.Enabled != nil {
+		channel.Enabled = *req.Enabled
+	}
+	channel.NotificationTypes = req.NotificationTypes
+	channel.UpdatedAt = now
+
+	if targetChanged {
+		notification := &domain.Notification{
+			Title:     "Канал подключен",
+			Content:   "Уведомления проекта будут публиковаться в этот канал",
+			CreatedAt: now,
+		}
+		if err := s.discordSender.SendNotification(ctx, channel, notification); err != nil {
+			s.logger.Warn("Discord channel check failed", map[string]interface{}{
+				"project_id": projectID,
+				"error":      err.Error(),
+			})
+			return nil, fmt.Errorf("%w: %v", ErrDiscordUnreachable, err)
+		}
+	}
+
+	if err := s.discordRepo.SaveProjectChannel(ctx, channel); err != nil {
+		s.logger.Error("Failed to save project Discord channel", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return nil, err
+	}
+
+	return channel, nil
+}
+
+// DeleteProjectChannel удаляет канал Discord проекта
+func (s *DiscordService) DeleteProjectChannel(ctx context.Context, projectID string) error {
+	return s.discordRepo.DeleteProjectChannel(ctx, projectID)
+}
@@ -0,0 +1,233 @@
+package service
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nurlyy/task_manager/pkg/circuit"
+	"github.com/nurlyy/task_manager/pkg/config"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
+	"github.com/nurlyy/task_manager/pkg/logger"
+)
+
+var (
+	ErrInvalidEmailHeader = apperrors.New(apperrors.CodeInvalidEmailHeader, "invalid email header")
+)
+
+// EmailSender обеспечивает отправку писем через SMTP-сервер
+type EmailSender struct {
+	config  *config.SMTPConfig
+	breaker *circuit.Breaker
+	logger  logger.Logger
+}
+
+// EmailMessage представляет письмо с HTML-версией и текстовой альтернативой
+type EmailMessage struct {
+	To      string
+	Subject string
+	HTML    string
+	Text    string
+	Headers map[string]string // Дополнительные заголовки, например List-Unsubscribe
+}
+
+// NewEmailSender создает новый экземпляр EmailSender
+func NewEmailSender(config *config.SMTPConfig, logger logger.Logger) *EmailSender {
+	return &EmailSender{
+		config:  config,
+		breaker: circuit.Default.Get("smtp"),
+		logger:  logger,
+	}
+}
+
+// Send отправляет текстовое письмо одному получателю
+func (s *EmailSender) Send(to, subject, body string) error {
+	var msg bytes.Buffer
+	if err := writeBase64Lines(&msg, body); err != nil {
+		return err
+	}
+
+	return s.deliver(to, subject, map[string]string{
+		"Content-Type":              "text/plain; charset=utf-8",
+		"Content-Transfer-Encoding": "base64",
+	}, msg.Bytes())
+}
+
+// SendMessage отправляет письмо из HTML-версии и текстовой альтернативы (multipart/alternative).
+// Почтовые клиенты без поддержки HTML показывают текстовую версию
+func (s *EmailSender) SendMessage(message *EmailMessage) error {
+	var body bytes.Buffer
+	parts := multipart.NewWriter(&body)
+	// Части перечисляются от простой к предпочтительной (RFC 2046)
+	for _, part := range []struct{ contentType, content string }{
+		{"text/plain; charset=utf-8", message.Text},
+		{"text/html; charset=utf-8", message.HTML},
+	} {
+		w, err := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"base64"},
+		})
+		if err != nil {
+			return err
+		}
+		if err := writeBase64Lines(w, part.content); err != nil {
+			return err
+		}
+	}
+	if err := parts.Close(); err != nil {
+		return err
+	}
+
+	headers := make(map[string]string, len(message.Headers)+1)
+	for name, value := range message.Headers {
+		headers[name] = value
+	}
+	headers["Content-Type"] = "multipart/alternative; boundary=" + parts.Boundary()
+
+	return s.deliver(message.To, message.Subject, headers, body.Bytes())
+}
+
+// deliver дополняет тело письма заголовками и передает его SMTP-серверу
+func (s *EmailSender) deliver(to, subject string, headers map[string]string, body []byte) error {
+	// Переводы строк в заголовках позволили бы подставить произвольные заголовки
+	if strings.ContainsAny(to, "\r\n") || strings.ContainsAny(subject, "\r\n") {
+		return ErrInvalidEmailHeader
+	}
+	for name, value := range headers {
+		if strings.ContainsAny(name, "\r\n:") || strings.ContainsAny(value, "\r\n") {
+			return ErrInvalidEmailHeader
+		}
+	}
+	if _, err := mail.ParseAddress(to); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidEmailHeader, err)
+	}
+
+	var msg bytes.Buffer
+	msg.WriteString("From: " + s.config.From + "\r\n")
+	msg.WriteString("To: " + to + "\r\n")
+	msg.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", subject) + "\r\n")
+	msg.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	msg.WriteString("MIME-Version: 1.0\r\n")
+
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		msg.WriteString(name + ": " + headers[name] + "\r\n")
+	}
+	msg.WriteString("\r\n")
+	msg.Write(body)
+
+	var auth smtp.Auth
+	if s.config.Username != "" {
+		auth = smtp.PlainAuth("", s.config.Username, s.config.Password, s.config.Host)
+	}
+
+	// В конверте SMTP указывается только адрес отправителя, без отображаемого имени
+	envelopeFrom := s.config.From
+	if from, err := mail.ParseAddress(s.config.From); err == nil {
+		envelopeFrom = from.Address
+	}
+
+	err := s.breaker.Do(func() error {
+		return s.sendMail(auth, envelopeFrom, to, msg.Bytes())
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+
+	s.logger.Debug("Email sent", map[string]interface{}{
+		"subject": subject,
+	})
+	return nil
+}
+
+// writeBase64Lines записывает содержимое в base64 строками по 76 символов (RFC 2045)
+func writeBase64Lines(w io.Writer, content string) error {
+	encoded := base64.StdEncoding.EncodeToString([]byte(content))
+	for len(encoded) > 76 {
+		if _, err := io.WriteString(w, encoded[:76]+"\r\n"); err != nil {
+			return err
+		}
+		encoded = encoded[76:]
+	}
+	_, err := io.WriteString(w, encoded+"\r\n")
+	return err
+}
+
+// sendMail передает письмо SMTP-серверу так же, как smtp.SendMail, но с ограничением
+// времени на весь сеанс, чтобы зависший сервер не удерживал отправителя.
+// Отказ сервера принять письмо не говорит о его неисправности и не учитывается выключателем
+func (s *EmailSender) sendMail(auth smtp.Auth, from, to string, msg []byte) error {
+	addr := net.JoinHostPort(s.config.Host, s.config.Port)
+	conn, err := net.DialTimeout("tcp", addr, s.config.Timeout)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if s.config.Timeout > 0 {
+		if err := conn.SetDeadline(time.Now().Add(s.config.Timeout)); err != nil {
+			return err
+		}
+	}
+
+	client, err := smtp.NewClient(conn, s.config.Host)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: s.config.Host}); err != nil {
+			return err
+		}
+	}
+	if auth != nil {
+		if err := client.Auth(auth); err != nil {
+			return excludeSMTPReply(err)
+		}
+	}
+	if err := client.Mail(from); err != nil {
+		return excludeSMTPReply(err)
+	}
+	if err := client.Rcpt(to); err != nil {
+		return excludeSMTPReply(err)
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return excludeSMTPReply(err)
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return excludeSMTPReply(err)
+	}
+
+	return client.Quit()
+}
+
+// excludeSMTPReply исключает из учета выключателем постоянные отказы сервера (коды 5xx)
+func excludeSMTPReply(err error) error {
+	var reply *textproto.Error
+	if errors.As(err, &reply) && reply.Code >= 500 {
+		return circuit.Exclude(err)
+	}
+	return err
+}
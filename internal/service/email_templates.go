@@ -0,0 +1,147 @@
+package service
+
+import (
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+)
+
+// emailField - поле письма с уведомлением: подпись и ключ значения в метаданных уведомления
+type emailField struct {
+	Label  string
+	Key    string
+	Suffix string // Единицы измерения после значения
+}
+
+// emailFields содержит поля писем по типам уведомлений; поля без значения не выводятся
+var emailFields = map[domain.NotificationType][]emailField{
+	domain.NotificationTypeTaskAssigned: {
+		{Label: "Задача", Key: "task_title"},
+		{Label: "Приоритет", Key: "priority"},
+		{Label: "Срок выполнения", Key: "due_date"},
+	},
+	domain.NotificationTypeTaskUpdated: {
+		{Label: "Задача", Key: "task_title"},
+		{Label: "Статус", Key: "status"},
+		{Label: "Исполнитель", Key: "assignee_name"},
+	},
+	domain.NotificationTypeTaskCommented: {
+		{Label: "Задача", Key: "task_title"},
+		{Label: "Автор комментария", Key: "user_name"},
+		{Label: "Комментарий", Key: "comment_content"},
+	},
+	domain.NotificationTypeTaskDueSoon: {
+		{Label: "Задача", Key: "task_title"},
+		{Label: "Срок выполнения", Key: "due_date"},
+		{Label: "Осталось времени", Key: "hours_left", Suffix: " часов"},
+	},
+	domain.NotificationTypeTaskOverdue: {
+		{Label: "Задача", Key: "task_title"},
+		{Label: "Срок выполнения истек", Key: "due_date"},
+	},
+	domain.NotificationTypeProjectMemberAdded: {
+		{Label: "Проект", Key: "project_name"},
+		{Label: "Роль", Key: "role"},
+	},
+	domain.NotificationTypeProjectUpdated: {
+		{Label: "Проект", Key: "project_name"},
+		{Label: "Статус", Key: "status"},
+	},
+}
+
+// emailHTMLLayout - HTML-версия письма. Стили заданы в атрибутах, так как почтовые
+// клиенты часто удаляют блоки <style>; значения экранируются html/template
+const emailHTMLLayout = `<!DOCTYPE html>
+<html lang="ru">
+<head><meta charset="utf-8"><title>{{.Title}}</title></head>
+<body style="margin:0;padding:24px;background:#f4f5f7;font-family:Arial,sans-serif;color:#172b4d">
+<div style="max-width:600px;margin:0 auto;background:#ffffff;border-radius:6px;padding:24px">
+<h2 style="margin:0 0 16px;font-size:20px">{{.Title}}</h2>
+{{- with .Content}}
+<p style="margin:0 0 16px;line-height:1.5;white-space:pre-line">{{.}}</p>
+{{- end}}
+{{- with .Fields}}
+<table style="border-collapse:collapse;margin:0 0 16px">
+{{- range .}}
+<tr><td style="padding:4px 16px 4px 0;color:#5e6c84;vertical-align:top">{{.Label}}</td><td style="padding:4px 0">{{.Value}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+{{- with .Link}}
+<p style="margin:0 0 16px"><a href="{{.}}" style="display:inline-block;padding:8px 16px;background:#0052cc;color:#ffffff;text-decoration:none;border-radius:4px">Открыть задачу</a></p>
+{{- end}}
+<p style="margin:24px 0 0;font-size:12px;color:#7a869a">Отправлено: {{.SentAt}}
+{{- with .UnsubscribeLink}}<br><a href="{{.}}" style="color:#7a869a">Отписаться от уведомлений этого типа</a>{{end}}</p>
+</div>
+</body>
+</html>
+`
+
+// emailTextLayout - текстовая версия письма для клиентов без поддержки HTML
+const emailTextLayout = `{{.Title}}
+{{with .Content}}
+{{.}}
+{{end}}
+{{- range .Fields}}
+{{.Label}}: {{.Value}}
+{{- end}}
+{{with .Link}}
+Открыть задачу: {{.}}
+{{end}}
+Отправлено: {{.SentAt}}
+{{- with .UnsubscribeLink}}
+Отписаться от уведомлений этого типа: {{.}}
+{{- end}}
+`
+
+var (
+	emailHTMLTemplate = htmltemplate.Must(htmltemplate.New("email_html").Parse(emailHTMLLayout))
+	emailTextTemplate = texttemplate.Must(texttemplate.New("email_text").Parse(emailTextLayout))
+)
+
+// emailFieldValue - заполненное поле письма
+type emailFieldValue struct {
+	Label string
+	Value string
+}
+
+// emailTemplateData - данные, подставляемые в шаблоны письма
+type emailTemplateData struct {
+	Title           string
+	Content         string
+	Fields          []emailFieldValue
+	Link            string // Ссылка на задачу уведомления
+	UnsubscribeLink string
+	SentAt          string
+}
+
+// newEmailTemplateData собирает данные письма из уведомления
+func newEmailTemplateData(notification *domain.Notification, link, unsubscribeLink string) emailTemplateData {
+	data := emailTemplateData{
+		Title:           notification.Title,
+		Content:         notification.Content,
+		Link:            link,
+		UnsubscribeLink: unsubscribeLink,
+		SentAt:          notification.CreatedAt.Format("02.01.2006 15:04"),
+	}
+	for _, field := range emailFields[notification.Type] {
+		if value := notification.MetaData[field.Key]; value != "" {
+			data.Fields = append(data.Fields, emailFieldValue{Label: field.Label, Value: value + field.Suffix})
+		}
+	}
+	return data
+}
+
+// renderNotificationEmail формирует HTML-версию и текстовую альтернативу письма с уведомлением
+func renderNotificationEmail(data emailTemplateData) (string, string, error) {
+	var html, text strings.Builder
+	if err := emailHTMLTemplate.Execute(&html, data); err != nil {
+		return "", "", err
+	}
+	if err := emailTextTemplate.Execute(&text, data); err != nil {
+		return "", "", err
+	}
+	return html.String(), text.String(), nil
+}
@@ -0,0 +1,494 @@
+package service
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/internal/repository"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
+	"github.com/nurlyy/task_manager/pkg/logger"
+)
+
+var (
+	ErrEpicNotFound        = apperrors.New(apperrors.CodeEpicNotFound, "epic not found")
+	ErrEpicCommentNotFound = apperrors.New(apperrors.CodeCommentNotFound, "epic comment not found")
+	ErrEpicOwnerInvalid    = apperrors.New(apperrors.CodeInvalidOwner, "epic owner must be a member of the project")
+	ErrEpicProjectMismatch = apperrors.New(apperrors.CodeEpicProjectMismatch, "epic belongs to another project")
+)
+
+// boardMaxTasks ограничивает число задач на доске проекта
+const boardMaxTasks = 500
+
+// boardStatuses определяет колонки, которыми заполняется доска задач проекта;
+// отмененные задачи на доске по умолчанию не показываются
+var boardStatuses = []domain.TaskStatus{
+	domain.TaskStatusNew,
+	domain.TaskStatusInProgress,
+	domain.TaskStatusOnHold,
+	domain.TaskStatusReview,
+	domain.TaskStatusCompleted,
+}
+
+// EpicService представляет бизнес-логику эпиков и доски задач проекта
+type EpicService struct {
+	epicRepo    repository.EpicRepository
+	taskRepo    repository.TaskRepository
+	projectRepo repository.ProjectRepository
+	userRepo    repository.UserRepository
+	projectSvc  *ProjectService
+	taskSvc     *TaskService
+	boardSvc    *BoardService
+	logger      logger.Logger
+}
+
+// NewEpicService создает новый экземпляр EpicService
+func NewEpicService(
+	epicRepo repository.EpicRepository,
+	taskRepo repository.TaskRepository,
+	projectRepo repository.ProjectRepository,
+	userRepo repository.UserRepository,
+	projectSvc *ProjectService,
+	taskSvc *TaskService,
+	boardSvc *BoardService,
+	logger logger.Logger,
+) *EpicService {
+	return &EpicService{
+		epicRepo:    epicRepo,
+		taskRepo:    taskRepo,
+		projectRepo: projectRepo,
+		userRepo:    userRepo,
+		projectSvc:  projectSvc,
+		taskSvc:     taskSvc,
+		boardSvc:    boardSvc,
+		logger:      logger,
+	}
+}
+
+// Create создает эпик в проекте
+func (s *EpicService) Create(ctx context.Context, projectID string, req domain.EpicCreateRequest, userID string) (*domain.Epic, error) {
+	if err := s.projectSvc.ensureProjectWritable(ctx, projectID); err != nil {
+		return nil, err
+	}
+
+	if req.OwnerID != nil {
+		if err := s.checkOwner(ctx, projectID, *req.OwnerID); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	epic := &domain.Epic{
+		ID:         uuid.New().String(),
+		ProjectID:  projectID,
+		Title:      req.Title,
+		Goal:       req.Goal,
+		OwnerID:    req.OwnerID,
+		TargetDate: req.TargetDate,
+		CreatedBy:  userID,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+		Progress:   &domain.EpicProgress{},
+	}
+
+	if err := s.epicRepo.Create(ctx, epic); err != nil {
+		s.logger.Error("Failed to create epic", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return nil, err
+	}
+
+	return epic, nil
+}
+
+// List возвращает эпики проекта с прогрессом задач
+func (s *EpicService) List(ctx context.Context, projectID string) ([]*domain.Epic, error) {
+	epics, err := s.epicRepo.ListByProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.fillProgress(ctx, epics); err != nil {
+		return nil, err
+	}
+
+	return epics, nil
+}
+
+// Get возвращает эпик с прогрессом задач
+func (s *EpicService) Get(ctx context.Context, id string, userID string) (*domain.Epic, error) {
+	epic, err := s.getAccessibleEpic(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.fillProgress(ctx, []*domain.Epic{epic}); err != nil {
+		return nil, err
+	}
+
+	return epic, nil
+}
+
+// Update обновляет эпик
+func (s *EpicService) Update(ctx context.Context, id string, req domain.EpicUpdateRequest, userID string) (*domain.Epic, error) {
+	epic, err := s.getAccessibleEpic(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkCanEdit(ctx, epic.ProjectID, userID); err != nil {
+		return nil, err
+	}
+
+	if req.Title != nil {
+		epic.Title = *req.Title
+	}
+	if req.Goal != nil {
+		epic.Goal = *req.Goal
+	}
+	if req.ClearOwner {
+		epic.OwnerID = nil
+	} else if req.OwnerID != nil {
+		if err := s.checkOwner(ctx, epic.ProjectID, *req.OwnerID); err != nil {
+			return nil, err
+		}
+		epic.OwnerID = req.OwnerID
+	}
+	if req.ClearTargetDate {
+		epic.TargetDate = nil
+	} else if req.TargetDate != nil {
+		epic.TargetDate = req.TargetDate
+	}
+	epic.UpdatedAt = time.Now()
+
+	if err := s.epicRepo.Update(ctx, epic); err != nil {
+		s.logger.Error("Failed to update epic", err, map[string]interface{}{
+			"id": id,
+		})
+		return nil, err
+	}
+
+	if err := s.fillProgress(ctx, []*domain.Epic{epic}); err != nil {
+		return nil, err
+	}
+
+	return epic, nil
+}
+
+// Delete удаляет эпик; задачи эпика остаются в проекте
+func (s *EpicService) Delete(ctx context.Context, id string, userID string) error {
+	epic, err := s.getAccessibleEpic(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+
+	if !s.projectSvc.canManageProject(ctx, epic.ProjectID, userID) {
+		return ErrInsufficientRights
+	}
+
+	if err := s.projectSvc.ensureProjectWritable(ctx, epic.ProjectID); err != nil {
+		return err
+	}
+
+	return s.epicRepo.Delete(ctx, id)
+}
+
+// SetTaskEpic привязывает задачу к эпику того же проекта или отвязывает ее
+func (s *EpicService) SetTaskEpic(ctx context.Context, taskID string, req domain.TaskEpicRequest, userID string) (*domain.TaskResponse, error) {
+	task, err := s.taskRepo.GetByID(ctx, taskID)
+	if err != nil || task == nil {
+		return nil, ErrTaskNotFound
+	}
+
+	if !s.taskSvc.hasAccessToTask(ctx, task.ProjectID, userID) {
+		return nil, ErrTaskAccessDenied
+	}
+
+	if err := s.checkCanEdit(ctx, task.ProjectID, userID); err != nil {
+		return nil, err
+	}
+
+	if req.EpicID != nil {
+		epic, err := s.epicRepo.GetByID(ctx, *req.EpicID)
+		if err != nil {
+			return nil, err
+		}
+		if epic == nil {
+			return nil, ErrEpicNotFound
+		}
+		if epic.ProjectID != task.ProjectID {
+			return nil, ErrEpicProjectMismatch
+		}
+	}
+
+	if err := s.epicRepo.SetTaskEpic(ctx, taskID, req.EpicID); err != nil {
+		return nil, err
+	}
+	oldEpicID := task.EpicID
+	task.EpicID = req.EpicID
+
+	s.taskSvc.publishTaskChanges(ctx, task, map[string]interface{}{
+		"epic_id": map[string]interface{}{"old": oldEpicID, "new": req.EpicID},
+	})
+
+	resp := task.ToResponse()
+	s.taskSvc.fillAssignees(ctx, &resp)
+	return &resp, nil
+}
+
+// AddComment добавляет комментарий к эпику
+func (s *EpicService) AddComment(ctx context.Context, epicID string, req domain.EpicCommentRequest, userID string) (*domain.EpicComment, error) {
+	epic, err := s.getAccessibleEpic(ctx, epicID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.projectSvc.ensureProjectWritable(ctx, epic.ProjectID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	comment := &domain.EpicComment{
+		ID:        uuid.New().String(),
+		EpicID:    epicID,
+		UserID:    userID,
+		Content:   req.Content,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err := s.epicRepo.CreateComment(ctx, comment); err != nil {
+		s.logger.Error("Failed to create epic comment", err, map[string]interface{}{
+			"epic_id": epicID,
+		})
+		return nil, err
+	}
+
+	s.fillCommentAuthors(ctx, []*domain.EpicComment{comment})
+	return comment, nil
+}
+
+// ListComments возвращает комментарии эпика
+func (s *EpicService) ListComments(ctx context.Context, epicID string, userID string) ([]*domain.EpicComment, error) {
+	if _, err := s.getAccessibleEpic(ctx, epicID, userID); err != nil {
+		return nil, err
+	}
+
+	comments, err := s.epicRepo.ListComments(ctx, epicID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.fillCommentAuthors(ctx, comments)
+	return comments, nil
+}
+
+// DeleteComment удаляет комментарий к эпику. Удалить комментарий может автор или менеджер проекта
+func (s *EpicService) DeleteComment(ctx context.Context, epicID string, commentID string, userID string) error {
+	epic, err := s.getAccessibleEpic(ctx, epicID, userID)
+	if err != nil {
+		return err
+	}
+
+	comment, err := s.epicRepo.GetComment(ctx, commentID)
+	if err != nil {
+		return err
+	}
+	if comment == nil || comment.EpicID != epicID {
+		return ErrEpicCommentNotFound
+	}
+
+	if comment.UserID != userID && !s.projectSvc.canManageProject(ctx, epic.ProjectID, userID) {
+		return ErrInsufficientRights
+	}
+
+	return s.epicRepo.DeleteComment(ctx, commentID)
+}
+
+// GetBoard возвращает канбан-доску задач проекта.
+// При swimlane == "epic" задачи разбиваются на полосы по эпикам, задачи без эпика идут последней полосой
+func (s *EpicService) GetBoard(ctx context.Context, projectID string, swimlane string) (*domain.ProjectBoard, error) {
+	if swimlane != "" && swimlane != domain.BoardSwimlaneEpic {
+		return nil, &TaskValidationError{Violations: []domain.FieldViolation{{
+			Field:   "swimlane",
+			Message: "Supported swimlanes: epic",
+		}}}
+	}
+
+	columns, placements, err := s.boardSvc.layout(ctx, projectID)
+	if err != nil {
+		s.logger.Error("Failed to load board columns", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return nil, err
+	}
+
+	orderBy := "rank"
+	tasks, err := s.taskRepo.List(ctx, repository.TaskFilter{
+		ProjectIDs: []string{projectID},
+		OrderBy:    &orderBy,
+		Limit:      boardMaxTasks + 1,
+	})
+	if err != nil {
+		s.logger.Error("Failed to list board tasks", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return nil, err
+	}
+
+	board := &domain.ProjectBoard{
+		ProjectID: projectID,
+		Swimlane:  swimlane,
+	}
+	if len(tasks) > boardMaxTasks {
+		tasks = tasks[:boardMaxTasks]
+		board.Truncated = true
+	}
+
+	if swimlane != domain.BoardSwimlaneEpic {
+		board.Swimlanes = []*domain.BoardSwimlane{newBoardSwimlane(columns, placements, tasks)}
+		return board, nil
+	}
+
+	epics, err := s.epicRepo.ListByProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.fillProgress(ctx, epics); err != nil {
+		return nil, err
+	}
+
+	tasksByEpic := make(map[string][]*domain.Task, len(epics))
+	var unassigned []*domain.Task
+	for _, task := range tasks {
+		if task.EpicID == nil {
+			unassigned = append(unassigned, task)
+			continue
+		}
+		tasksByEpic[*task.EpicID] = append(tasksByEpic[*task.EpicID], task)
+	}
+
+	board.Swimlanes = make([]*domain.BoardSwimlane, 0, len(epics)+1)
+	for _, epic := range epics {
+		lane := newBoardSwimlane(columns, placements, tasksByEpic[epic.ID])
+		epicID := epic.ID
+		lane.EpicID = &epicID
+		lane.Title = epic.Title
+		lane.Progress = epic.Progress
+		board.Swimlanes = append(board.Swimlanes, lane)
+	}
+	board.Swimlanes = append(board.Swimlanes, newBoardSwimlane(columns, placements, unassigned))
+
+	return board, nil
+}
+
+// getAccessibleEpic возвращает эпик, если у пользователя есть доступ к его проекту
+func (s *EpicService) getAccessibleEpic(ctx context.Context, id string, userID string) (*domain.Epic, error) {
+	epic, err := s.epicRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if epic == nil || !s.projectSvc.hasAccessToProject(ctx, epic.ProjectID, userID) {
+		return nil, ErrEpicNotFound
+	}
+	return epic, nil
+}
+
+// checkCanEdit проверяет, что пользователь может изменять эпики и задачи проекта
+func (s *EpicService) checkCanEdit(ctx context.Context, projectID string, userID string) error {
+	if !s.projectSvc.hasAccessToProject(ctx, projectID, userID) {
+		return ErrProjectNotFound
+	}
+	if !s.taskSvc.canManageTask(ctx, projectID, userID) {
+		return ErrInsufficientRights
+	}
+	return s.projectSvc.ensureProjectWritable(ctx, projectID)
+}
+
+// checkOwner проверяет, что владелец эпика является участником проекта
+func (s *EpicService) checkOwner(ctx context.Context, projectID string, ownerID string) error {
+	member, err := s.projectRepo.GetMember(ctx, projectID, ownerID)
+	if err != nil || member == nil {
+		return ErrEpicOwnerInvalid
+	}
+	return nil
+}
+
+// fillProgress заполняет прогресс задач эпиков
+func (s *EpicService) fillProgress(ctx context.Context, epics []*domain.Epic) error {
+	ids := make([]string, 0, len(epics))
+	for _, epic := range epics {
+		ids = append(ids, epic.ID)
+	}
+
+	progress, err := s.epicRepo.GetProgress(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	for _, epic := range epics {
+		p, ok := progress[epic.ID]
+		if !ok {
+			p = &domain.EpicProgress{}
+		}
+		if p.TotalTasks > 0 {
+			p.Percent = math.Round(float64(p.CompletedTasks)/float64(p.TotalTasks)*1000) / 10
+		}
+		epic.Progress = p
+	}
+
+	return nil
+}
+
+// fillCommentAuthors заполняет сведения об авторах комментариев
+func (s *EpicService) fillCommentAuthors(ctx context.Context, comments []*domain.EpicComment) {
+	users := make(map[string]*domain.UserBrief)
+	for _, comment := range comments {
+		brief, ok := users[comment.UserID]
+		if !ok {
+			if user, err := s.userRepo.GetByID(ctx, comment.UserID); err == nil && user != nil {
+				brief = &domain.UserBrief{
+					ID:        user.ID,
+					Email:     user.Email,
+					FirstName: user.FirstName,
+					LastName:  user.LastName,
+					Avatar:    user.Avatar,
+				}
+			}
+			users[comment.UserID] = brief
+		}
+		comment.User = brief
+	}
+}
+
+// newBoardSwimlane раскладывает задачи полосы по колонкам доски. Задача, перенесенная
+// в колонку без статуса, показывается в ней, остальные - в колонке своего статуса.
+// Задачи со статусом без колонки на доске не показываются
+func newBoardSwimlane(layout []*domain.ProjectBoardColumn, placements map[string]string, tasks []*domain.Task) *domain.BoardSwimlane {
+	columns := make([]*domain.BoardColumn, 0, len(layout))
+	byID := make(map[string]*domain.BoardColumn, len(layout))
+	byStatus := make(map[domain.TaskStatus]*domain.BoardColumn, len(layout))
+	for _, c := range layout {
+		column := &domain.BoardColumn{ID: c.ID, Name: c.Name, Status: c.Status, Tasks: []domain.TaskResponse{}}
+		columns = append(columns, column)
+		byID[c.ID] = column
+		if c.Status != nil {
+			byStatus[*c.Status] = column
+		}
+	}
+
+	for _, task := range tasks {
+		column, ok := byID[placements[task.ID]]
+		if !ok {
+			column, ok = byStatus[task.Status]
+		}
+		if ok {
+			column.Tasks = append(column.Tasks, task.ToResponse())
+		}
+	}
+
+	return &domain.BoardSwimlane{Columns: columns}
+}
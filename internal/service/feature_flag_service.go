@@ -0,0 +1,288 @@
+package service
+
+import (
+	"context"
+	"hash/fnv"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/internal/repository"
+	"github.com/nurlyy/task_manager/internal/repository/cache"
+	"github.com/nurlyy/task_manager/pkg/config"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
+	"github.com/nurlyy/task_manager/pkg/logger"
+)
+
+var (
+	ErrInvalidFeatureFlag = apperrors.New(apperrors.CodeInvalidFeatureFlag, "invalid feature flag name")
+)
+
+// Флаги функциональности, проверяемые в коде
+const (
+	// FeatureTypeaheadExtended включает поиск решений и вики-страниц в быстром поиске
+	FeatureTypeaheadExtended = "typeahead_extended"
+)
+
+// defaultFeatureRollouts задает доли раскатки флагов, если они не указаны в конфигурации
+var defaultFeatureRollouts = map[string]int{
+	FeatureTypeaheadExtended: 100,
+}
+
+// featureFlagNamePattern ограничивает имена флагов, переопределяемых через API
+var featureFlagNamePattern = regexp.MustCompile(`^[a-z0-9_]{1,64}$`)
+
+// FeatureFlagService вычисляет флаги функциональности для пользователей. Значения по
+// умолчанию берутся из конфигурации, администраторы переопределяют их в Redis,
+// и переопределения действуют на все экземпляры приложения
+type FeatureFlagService struct {
+	defaults        map[string]*domain.FeatureFlag
+	refreshInterval time.Duration
+	cacheRepo       *cache.RedisRepository
+	userRepo        repository.UserRepository
+	logger          logger.Logger
+
+	mu          sync.Mutex
+	overrides   map[string]*domain.FeatureFlag
+	refreshedAt time.Time
+}
+
+// NewFeatureFlagService создает новый экземпляр FeatureFlagService
+func NewFeatureFlagService(
+	config *config.FeaturesConfig,
+	cacheRepo *cache.RedisRepository,
+	userRepo repository.UserRepository,
+	logger logger.Logger,
+) *FeatureFlagService {
+	defaults := make(map[string]*domain.FeatureFlag)
+	for name, percentage := range defaultFeatureRollouts {
+		defaults[name] = configFeatureFlag(name, percentage)
+	}
+	for name, percentage := range config.Rollouts {
+		defaults[name] = configFeatureFlag(name, percentage)
+	}
+
+	return &FeatureFlagService{
+		defaults:        defaults,
+		refreshInterval: config.RefreshInterval,
+		cacheRepo:       cacheRepo,
+		userRepo:        userRepo,
+		logger:          logger,
+		overrides:       make(map[string]*domain.FeatureFlag),
+	}
+}
+
+// configFeatureFlag создает флаг по доле раскатки из конфигурации
+func configFeatureFlag(name string, percentage int) *domain.FeatureFlag {
+	return &domain.FeatureFlag{
+		Name:       name,
+		Enabled:    percentage > 0,
+		Percentage: percentage,
+		Source:     domain.FeatureFlagSourceConfig,
+	}
+}
+
+// IsEnabled проверяет, включен ли флаг для пользователя. Неизвестные флаги выключены
+func (s *FeatureFlagService) IsEnabled(ctx context.Context, name string, user *domain.User) bool {
+	flag := s.flag(ctx, name)
+	return flag != nil && user != nil && featureEnabledFor(flag, user)
+}
+
+// IsEnabledForUser проверяет флаг для пользователя по его ID
+func (s *FeatureFlagService) IsEnabledForUser(ctx context.Context, name string, userID string) bool {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil || user == nil {
+		return false
+	}
+	return s.IsEnabled(ctx, name, user)
+}
+
+// ListForUser возвращает значения всех флагов для текущего пользователя,
+// чтобы клиенты могли включать соответствующие части интерфейса
+func (s *FeatureFlagService) ListForUser(ctx context.Context, userID string) (map[string]bool, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil || user == nil {
+		return nil, ErrUserNotFound
+	}
+
+	result := make(map[string]bool)
+	for name, flag := range s.flags(ctx) {
+		result[name] = featureEnabledFor(flag, user)
+	}
+	return result, nil
+}
+
+// List возвращает действующие правила всех флагов для администратора
+func (s *FeatureFlagService) List(ctx context.Context) ([]*domain.FeatureFlag, error) {
+	// Читаем переопределения напрямую, чтобы администратор сразу видел свои изменения
+	overrides, err := s.cacheRepo.GetFeatureFlags(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.setOverrides(overrides)
+
+	flags := make([]*domain.FeatureFlag, 0, len(s.defaults)+len(overrides))
+	for _, flag := range s.merge(overrides) {
+		flags = append(flags, flag)
+	}
+	sort.Slice(flags, func(i, j int) bool {
+		return flags[i].Name < flags[j].Name
+	})
+	return flags, nil
+}
+
+// Override переопределяет правила флага для всех экземпляров приложения
+func (s *FeatureFlagService) Override(ctx context.Context, name string, req domain.FeatureFlagRequest, userID string) (*domain.FeatureFlag, error) {
+	if !featureFlagNamePattern.MatchString(name) {
+		return nil, ErrInvalidFeatureFlag
+	}
+
+	now := time.Now()
+	flag := &domain.FeatureFlag{
+		Name:        name,
+		Enabled:     req.Enabled,
+		Percentage:  req.Percentage,
+		UserIDs:     req.UserIDs,
+		Roles:       req.Roles,
+		Departments: req.Departments,
+		Source:      domain.FeatureFlagSourceOverride,
+		UpdatedBy:   &userID,
+		UpdatedAt:   &now,
+	}
+
+	if err := s.cacheRepo.SetFeatureFlag(ctx, flag); err != nil {
+		return nil, err
+	}
+	s.invalidate()
+
+	s.logger.Info("Feature flag overridden", map[string]interface{}{
+		"flag":       name,
+		"enabled":    flag.Enabled,
+		"percentage": flag.Percentage,
+		"user_id":    userID,
+	})
+
+	return flag, nil
+}
+
+// ResetOverride удаляет переопределение, возвращая флагу значение из конфигурации
+func (s *FeatureFlagService) ResetOverride(ctx context.Context, name string, userID string) error {
+	if err := s.cacheRepo.DeleteFeatureFlag(ctx, name); err != nil {
+		return err
+	}
+	s.invalidate()
+
+	s.logger.Info("Feature flag override removed", map[string]interface{}{
+		"flag":    name,
+		"user_id": userID,
+	})
+
+	return nil
+}
+
+// flag возвращает действующие правила флага или nil для неизвестного флага
+func (s *FeatureFlagService) flag(ctx context.Context, name string) *domain.FeatureFlag {
+	overrides := s.currentOverrides(ctx)
+	if flag, ok := overrides[name]; ok {
+		return flag
+	}
+	return s.defaults[name]
+}
+
+// flags возвращает действующие правила всех флагов
+func (s *FeatureFlagService) flags(ctx context.Context) map[string]*domain.FeatureFlag {
+	return s.merge(s.currentOverrides(ctx))
+}
+
+// merge накладывает переопределения на значения из конфигурации
+func (s *FeatureFlagService) merge(overrides map[string]*domain.FeatureFlag) map[string]*domain.FeatureFlag {
+	flags := make(map[string]*domain.FeatureFlag, len(s.defaults)+len(overrides))
+	for name, flag := range s.defaults {
+		flags[name] = flag
+	}
+	for name, flag := range overrides {
+		flags[name] = flag
+	}
+	return flags
+}
+
+// currentOverrides возвращает переопределения, перечитывая их из Redis не чаще
+// refreshInterval. Если Redis недоступен, действуют последние прочитанные значения
+func (s *FeatureFlagService) currentOverrides(ctx context.Context) map[string]*domain.FeatureFlag {
+	s.mu.Lock()
+	if time.Since(s.refreshedAt) < s.refreshInterval {
+		overrides := s.overrides
+		s.mu.Unlock()
+		return overrides
+	}
+	s.refreshedAt = time.Now()
+	overrides := s.overrides
+	s.mu.Unlock()
+
+	fresh, err := s.cacheRepo.GetFeatureFlags(ctx)
+	if err != nil {
+		s.logger.Warn("Failed to refresh feature flags", map[string]interface{}{
+			"overrides": len(overrides),
+		}, map[string]interface{}{
+			"error": err,
+		})
+		return overrides
+	}
+	s.setOverrides(fresh)
+
+	return fresh
+}
+
+// setOverrides заменяет известные экземпляру переопределения
+func (s *FeatureFlagService) setOverrides(overrides map[string]*domain.FeatureFlag) {
+	s.mu.Lock()
+	s.overrides = overrides
+	s.refreshedAt = time.Now()
+	s.mu.Unlock()
+}
+
+// invalidate заставляет перечитать переопределения при следующей проверке
+func (s *FeatureFlagService) invalidate() {
+	s.mu.Lock()
+	s.refreshedAt = time.Time{}
+	s.mu.Unlock()
+}
+
+// featureEnabledFor применяет правила флага к пользователю
+func featureEnabledFor(flag *domain.FeatureFlag, user *domain.User) bool {
+	if !flag.Enabled {
+		return false
+	}
+
+	for _, id := range flag.UserIDs {
+		if id == user.ID {
+			return true
+		}
+	}
+	for _, role := range flag.Roles {
+		if role == user.Role {
+			return true
+		}
+	}
+	if user.Department != nil {
+		for _, department := range flag.Departments {
+			if department == *user.Department {
+				return true
+			}
+		}
+	}
+
+	return featureBucket(flag.Name, user.ID) < flag.Percentage
+}
+
+// featureBucket относит пользователя к одной из 100 групп раскатки. Группа зависит
+// и от флага, чтобы разные флаги раскатывались на разных пользователей
+func featureBucket(name, userID string) int {
+	h := fnv.New32a()
+	h.Write([]byte(name))
+	h.Write([]byte{':'})
+	h.Write([]byte(userID))
+	return int(h.Sum32() % 100)
+}
@@ -0,0 +1,586 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/segmentio/kafka-go"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/internal/messaging"
+	"github.com/nurlyy/task_manager/internal/repository"
+	"github.com/nurlyy/task_manager/pkg/config"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
+	"github.com/nurlyy/task_manager/pkg/logger"
+)
+
+var (
+	ErrFeedbackPortalNotFound     = apperrors.New(apperrors.CodeFeedbackPortalNotFound, "feedback portal not found")
+	ErrFeedbackSubmissionNotFound = apperrors.New(apperrors.CodeFeedbackSubmissionNotFound, "feedback submission not found")
+	ErrFeedbackAlreadyModerated   = apperrors.New(apperrors.CodeFeedbackAlreadyModerated, "feedback submission already moderated")
+	ErrFeedbackTooManyPending     = apperrors.New(apperrors.CodeTooManyPending, "too many pending feedback submissions")
+	ErrFeedbackTaskInvalid        = apperrors.New(apperrors.CodeInvalidTask, "task does not belong to the project")
+)
+
+const (
+	// feedbackMaxPendingPerEmail ограничивает число запросов одного автора в очереди модерации
+	feedbackMaxPendingPerEmail = 5
+	// feedbackSimilarTasksLimit ограничивает число кандидатов для объединения
+	feedbackSimilarTasksLimit = 5
+)
+
+// feedbackTaskStatusLabels содержит названия статусов задач для писем авторам запросов
+var feedbackTaskStatusLabels = map[domain.TaskStatus]string{
+	domain.TaskStatusNew:        "принят",
+	domain.TaskStatusInProgress: "в работе",
+	domain.TaskStatusOnHold:     "приостановлен",
+	domain.TaskStatusReview:     "на проверке",
+	domain.TaskStatusCompleted:  "выполнен",
+	domain.TaskStatusCancelled:  "отменен",
+}
+
+// FeedbackService представляет бизнес-логику публичного портала обратной связи
+type FeedbackService struct {
+	feedbackRepo repository.FeedbackRepository
+	projectRepo  repository.ProjectRepository
+	taskSvc      *TaskService
+	txManager    repository.TxManager
+	emailSender  *EmailSender
+	baseURL      string
+	logger       logger.Logger
+}
+
+// NewFeedbackService создает новый экземпляр FeedbackService
+func NewFeedbackService(
+	feedbackRepo repository.FeedbackRepository,
+	projectRepo repository.ProjectRepository,
+	taskSvc *TaskService,
+	txManager repository.TxManager,
+	emailSender *EmailSender,
+	baseURL string,
+	logger logger.Logger,
+) *FeedbackService {
+	return &FeedbackService{
+		feedbackRepo: feedbackRepo,
+		projectRepo:  projectRepo,
+		taskSvc:      taskSvc,
+		txManager:    txManager,
+		emailSender:  emailSender,
+		baseURL:      strings.TrimRight(baseURL, "/"),
+		logger:       logger,
+	}
+}
+
+// GetPortal возвращает портал обратной связи проекта
+func (s *FeedbackService) GetPortal(ctx context.Context, projectID string) (*domain.FeedbackPortal, error) {
+	portal, err := s.feedbackRepo.GetPortal(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if portal == nil {
+		return nil, ErrFeedbackPortalNotFound
+	}
+
+	portal.URL = s.portalURL(portal.PublicKey)
+	return portal, nil
+}
+
+// SavePortal создает или обновляет портал обратной связи проекта
+func (s *FeedbackService) SavePortal(ctx context.Context, projectID string, req domain.FeedbackPortalRequest, userID string) (*domain.FeedbackPortal, error) {
+	portal, err := s.feedbackRepo.GetPortal(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if portal == nil {
+		key, err := generateFeedbackKey()
+		if err != nil {
+			return nil, err
+		}
+		portal = &domain.FeedbackPortal{
+			ProjectID: projectID,
+			PublicKey: key,
+			Enabled:   true,
+			CreatedBy: userID,
+			CreatedAt: now,
+		}
+	}
+
+	portal.Title = req.Title
+	portal.Description = req.Description
+	if req.Enabled != nil {
+		portal.Enabled = *req.Enabled
+	}
+	portal.UpdatedAt = now
+
+	if err := s.feedbackRepo.SavePortal(ctx, portal); err != nil {
+		s.logger.Error("Failed to save feedback portal", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return nil, err
+	}
+
+	portal.URL = s.portalURL(portal.PublicKey)
+	return portal, nil
+}
+
+// RotatePortalKey выдает порталу новый публичный ключ; старая ссылка перестает работать
+func (s *FeedbackService) RotatePortalKey(ctx context.Context, projectID string) (*domain.FeedbackPortal, error) {
+	portal, err := s.feedbackRepo.GetPortal(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if portal == nil {
+		return nil, ErrFeedbackPortalNotFound
+	}
+
+	key, err := generateFeedbackKey()
+	if err != nil {
+		return nil, err
+	}
+	portal.PublicKey = key
+	portal.UpdatedAt = time.Now()
+
+	if err := s.feedbackRepo.SavePortal(ctx, portal); err != nil {
+		s.logger.Error("Failed to rotate feedback portal key", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return nil, err
+	}
+
+	portal.URL = s.portalURL(portal.PublicKey)
+	return portal, nil
+}
+
+// DeletePortal удаляет портал обратной связи проекта
+func (s *FeedbackService) DeletePortal(ctx context.Context, projectID string) error {
+	return s.feedbackRepo.DeletePortal(ctx, projectID)
+}
+
+// GetPublicPortal возвращает публичные сведения о включенном портале
+func (s *FeedbackService) GetPublicPortal(ctx context.Context, publicKey string) (*domain.FeedbackPortalInfo, error) {
+	portal, err := s.enabledPortal(ctx, publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	project, err := s.projectRepo.GetByID(ctx, portal.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	if project == nil {
+		return nil, ErrFeedbackPortalNotFound
+	}
+
+	return &domain.FeedbackPortalInfo{
+		Title:       portal.Title,
+		Description: portal.Description,
+		ProjectName: project.Name,
+	}, nil
+}
+
+// Submit принимает запрос внешнего пользователя в очередь модерации. Письмо о получении
+// не отправляется: иначе анонимный запрос позволял бы слать письма на любой адрес.
+// Автор получает письмо только после решения модератора
+func (s *FeedbackService) Submit(ctx context.Context, publicKey string, req domain.FeedbackSubmitRequest) (*domain.FeedbackSubmitResponse, error) {
+	portal, err := s.enabledPortal(ctx, publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	// Боту, заполнившему поле-ловушку, отвечаем как обычно, но запрос не сохраняем
+	if req.Website != "" {
+		s.logger.Info("Feedback submission discarded by honeypot", map[string]interface{}{
+			"project_id": portal.ProjectID,
+		})
+		return &domain.FeedbackSubmitResponse{ID: uuid.New().String(), Status: domain.FeedbackStatusPending}, nil
+	}
+
+	pending, err := s.feedbackRepo.CountPendingByEmail(ctx, portal.ProjectID, req.Email)
+	if err != nil {
+		return nil, err
+	}
+	if pending >= feedbackMaxPendingPerEmail {
+		return nil, ErrFeedbackTooManyPending
+	}
+
+	now := time.Now()
+	submission := &domain.FeedbackSubmission{
+		ID:             uuid.New().String(),
+		ProjectID:      portal.ProjectID,
+		Title:          strings.TrimSpace(req.Title),
+		Description:    strings.TrimSpace(req.Description),
+		SubmitterEmail: strings.TrimSpace(req.Email),
+		SubmitterName:  strings.TrimSpace(req.Name),
+		Status:         domain.FeedbackStatusPending,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	if err := s.feedbackRepo.CreateSubmission(ctx, submission); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Feedback submission received", map[string]interface{}{
+		"project_id":    portal.ProjectID,
+		"submission_id": submission.ID,
+	})
+
+	return &domain.FeedbackSubmitResponse{ID: submission.ID, Status: submission.Status}, nil
+}
+
+// List возвращает очередь запросов проекта
+func (s *FeedbackService) List(ctx context.Context, projectID string, status *domain.FeedbackStatus, page domain.PageRequest) (*domain.PagedResponse, error) {
+	filter := repository.FeedbackFilter{
+		Status: status,
+		Limit:  page.Limit(),
+		Offset: page.Offset(),
+	}
+
+	submissions, err := s.feedbackRepo.ListSubmissions(ctx, projectID, filter)
+	if err != nil {
+		return nil, err
+	}
+	submissions, hasMore := domain.PageItems(submissions, page)
+	total := 0
+	if !page.SkipCount {
+		total, err = s.feedbackRepo.CountSubmissions(ctx, projectID, filter)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return domain.NewPagedResponse(submissions, page, total, hasMore), nil
+}
+
+// Get возвращает запрос; для ожидающих модерации запросов добавляются похожие задачи проекта
+func (s *FeedbackService) Get(ctx context.Context, projectID string, id string) (*domain.FeedbackSubmission, error) {
+	submission, err := s.getSubmission(ctx, projectID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if submission.Status == domain.FeedbackStatusPending {
+		similar, err := s.feedbackRepo.FindSimilarTasks(ctx, projectID, submission.Title, feedbackSimilarTasksLimit)
+		if err != nil {
+			return nil, err
+		}
+		submission.SimilarTasks = similar
+	}
+
+	return submission, nil
+}
+
+// Approve создает задачу по запросу от имени модератора
+func (s *FeedbackService) Approve(ctx context.Context, projectID string, id string, req domain.FeedbackApproveRequest, userID string) (*domain.FeedbackSubmission, error) {
+	submission, err := s.getPendingSubmission(ctx, projectID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	title := submission.Title
+	if req.Title != "" {
+		title = req.Title
+	}
+	taskReq := domain.TaskCreateRequest{
+		Title:       truncateRunes(title, 200),
+		Description: submission.Description + "\n\n---\nЗапрос с портала обратной связи от " + submitterLabel(submission),
+		ProjectID:   projectID,
+		Priority:    req.Priority,
+	}
+
+	// Задача и решение модератора сохраняются в одной транзакции, а события задачи публикуются
+	// через таблицу исходящих событий: если запрос одновременно отклонили или приняли,
+	// условное обновление не найдет ожидающий запрос и созданная задача откатится
+	err = s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		task, err := s.taskSvc.Create(messaging.WithOutbox(ctx), taskReq, userID)
+		if err != nil {
+			return err
+		}
+		return s.moderate(ctx, submission, domain.FeedbackStatusApproved, &task.ID, task.Status, req.Note, userID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.notifySubmitter(submission,
+		fmt.Sprintf("Запрос принят: %s", submission.Title),
+		withNote(fmt.Sprintf("Ваш запрос \"%s\" принят в работу. Мы сообщим, когда его статус изменится.", submission.Title), req.Note),
+	)
+
+	return submission, nil
+}
+
+// Merge объединяет запрос с существующей задачей проекта
+func (s *FeedbackService) Merge(ctx context.Context, projectID string, id string, req domain.FeedbackMergeRequest, userID string) (*domain.FeedbackSubmission, error) {
+	submission, err := s.getPendingSubmission(ctx, projectID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	task, err := s.taskSvc.GetByID(ctx, req.TaskID, userID)
+	if err != nil {
+		if errors.Is(err, ErrTaskNotFound) {
+			return nil, ErrFeedbackTaskInvalid
+		}
+		return nil, err
+	}
+	if task.ProjectID != projectID {
+		return nil, ErrFeedbackTaskInvalid
+	}
+
+	if err := s.moderate(ctx, submission, domain.FeedbackStatusMerged, &task.ID, task.Status, req.Note, userID); err != nil {
+		return nil, err
+	}
+
+	s.notifySubmitter(submission,
+		fmt.Sprintf("Запрос принят: %s", submission.Title),
+		withNote(fmt.Sprintf(
+			"Ваш запрос \"%s\" объединен с похожей задачей \"%s\", которая сейчас %s. Мы сообщим, когда ее статус изменится.",
+			submission.Title, task.Title, taskStatusLabel(task.Status),
+		), req.Note),
+	)
+
+	return submission, nil
+}
+
+// Reject отклоняет запрос; причина отправляется автору
+func (s *FeedbackService) Reject(ctx context.Context, projectID string, id string, req domain.FeedbackRejectRequest, userID string) (*domain.FeedbackSubmission, error) {
+	submission, err := s.getPendingSubmission(ctx, projectID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.moderate(ctx, submission, domain.FeedbackStatusRejected, nil, "", req.Reason, userID); err != nil {
+		return nil, err
+	}
+
+	s.notifySubmitter(submission,
+		fmt.Sprintf("Запрос отклонен: %s", submission.Title),
+		withNote(fmt.Sprintf("К сожалению, ваш запрос \"%s\" отклонен.", submission.Title), req.Reason),
+	)
+
+	return submission, nil
+}
+
+// StartStatusUpdates запускает отправку писем авторам запросов при смене статуса связанных задач
+func (s *FeedbackService) StartStatusUpdates(ctx context.Context, kafkaConfig *config.KafkaConfig, topic string) error {
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:         kafkaConfig.Brokers,
+		Topic:           topic,
+		GroupID:         kafkaConfig.Namespaced("feedback-status-group"),
+		MinBytes:        10e3, // 10KB
+		MaxBytes:        10e6, // 10MB
+		MaxWait:         time.Second,
+		CommitInterval:  time.Second,
+		ReadLagInterval: -1,
+	})
+
+	s.logger.Info("Starting feedback status updates", map[string]interface{}{
+		"topic": topic,
+	})
+
+	go func() {
+		defer reader.Close()
+
+		for {
+			message, err := reader.ReadMessage(ctx)
+			if err != nil {
+				if ctx.Err() != nil {
+					s.logger.Info("Stopping feedback status updates")
+					return
+				}
+				s.logger.Error("Failed to read task event for feedback updates", err)
+				continue
+			}
+
+			if err := s.handleTaskEvent(ctx, message.Value); err != nil {
+				s.logger.Error("Failed to send feedback status update", err, map[string]interface{}{
+					"task_id": string(message.Key),
+				})
+			}
+		}
+	}()
+
+	return nil
+}
+
+// handleTaskEvent сообщает авторам связанных запросов о новом статусе задачи
+func (s *FeedbackService) handleTaskEvent(ctx context.Context, data []byte) error {
+	var event messaging.TaskEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		return fmt.Errorf("failed to unmarshal task event: %w", err)
+	}
+	if _, ok := event.Changes["status"]; !ok {
+		return nil
+	}
+
+	submissions, err := s.feedbackRepo.ListSubmissionsByTask(ctx, event.ID)
+	if err != nil {
+		return err
+	}
+
+	status := domain.TaskStatus(event.Status)
+	for _, submission := range submissions {
+		if submission.NotifiedTaskStatus != nil && domain.TaskStatus(*submission.NotifiedTaskStatus) == status {
+			continue
+		}
+
+		body := fmt.Sprintf("Статус вашего запроса \"%s\" изменен: %s.", submission.Title, taskStatusLabel(status))
+		if err := s.sendSubmitterEmail(submission, fmt.Sprintf("Обновление по запросу: %s", submission.Title), body); err != nil {
+			s.logger.Error("Failed to send feedback status email", err, map[string]interface{}{
+				"submission_id": submission.ID,
+			})
+			continue
+		}
+
+		if err := s.feedbackRepo.SetNotifiedTaskStatus(ctx, submission.ID, status); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// moderate сохраняет решение модератора
+func (s *FeedbackService) moderate(
+	ctx context.Context,
+	submission *domain.FeedbackSubmission,
+	status domain.FeedbackStatus,
+	taskID *string,
+	taskStatus domain.TaskStatus,
+	note string,
+	userID string,
+) error {
+	now := time.Now()
+	submission.Status = status
+	submission.TaskID = taskID
+	submission.ModerationNote = note
+	submission.ModeratedBy = &userID
+	submission.ModeratedAt = &now
+	submission.UpdatedAt = now
+	submission.NotifiedTaskStatus = nil
+	if taskStatus != "" {
+		// Автор узнает о текущем статусе задачи из письма о модерации
+		notified := string(taskStatus)
+		submission.NotifiedTaskStatus = &notified
+	}
+
+	updated, err := s.feedbackRepo.ModerateSubmission(ctx, submission)
+	if err != nil {
+		return err
+	}
+	if !updated {
+		return ErrFeedbackAlreadyModerated
+	}
+
+	s.logger.Info("Feedback submission moderated", map[string]interface{}{
+		"submission_id": submission.ID,
+		"status":        status,
+		"user_id":       userID,
+	})
+	return nil
+}
+
+// getSubmission возвращает запрос проекта
+func (s *FeedbackService) getSubmission(ctx context.Context, projectID string, id string) (*domain.FeedbackSubmission, error) {
+	submission, err := s.feedbackRepo.GetSubmission(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if submission == nil || submission.ProjectID != projectID {
+		return nil, ErrFeedbackSubmissionNotFound
+	}
+	return submission, nil
+}
+
+// getPendingSubmission возвращает запрос проекта, ожидающий модерации
+func (s *FeedbackService) getPendingSubmission(ctx context.Context, projectID string, id string) (*domain.FeedbackSubmission, error) {
+	submission, err := s.getSubmission(ctx, projectID, id)
+	if err != nil {
+		return nil, err
+	}
+	if submission.Status != domain.FeedbackStatusPending {
+		return nil, ErrFeedbackAlreadyModerated
+	}
+	return submission, nil
+}
+
+// enabledPortal возвращает включенный портал по публичному ключу
+func (s *FeedbackService) enabledPortal(ctx context.Context, publicKey string) (*domain.FeedbackPortal, error) {
+	portal, err := s.feedbackRepo.GetPortalByKey(ctx, publicKey)
+	if err != nil {
+		return nil, err
+	}
+	if portal == nil || !portal.Enabled {
+		return nil, ErrFeedbackPortalNotFound
+	}
+	return portal, nil
+}
+
+// notifySubmitter отправляет письмо автору запроса в фоне, не задерживая ответ API
+func (s *FeedbackService) notifySubmitter(submission *domain.FeedbackSubmission, subject, body string) {
+	go func() {
+		if err := s.sendSubmitterEmail(submission, subject, body); err != nil {
+			s.logger.Error("Failed to send feedback email", err, map[string]interface{}{
+				"submission_id": submission.ID,
+			})
+		}
+	}()
+}
+
+// sendSubmitterEmail отправляет письмо автору запроса
+func (s *FeedbackService) sendSubmitterEmail(submission *domain.FeedbackSubmission, subject, body string) error {
+	greeting := "Здравствуйте!"
+	if submission.SubmitterName != "" {
+		greeting = fmt.Sprintf("Здравствуйте, %s!", submission.SubmitterName)
+	}
+	text := greeting + "\n\n" + body + "\n\nЭто письмо отправлено автоматически, отвечать на него не нужно."
+
+	return s.emailSender.Send(submission.SubmitterEmail, truncateRunes(subject, 150), text)
+}
+
+// portalURL возвращает ссылку на публичную страницу портала
+func (s *FeedbackService) portalURL(publicKey string) string {
+	return s.baseURL + "/feedback/" + publicKey
+}
+
+// generateFeedbackKey генерирует публичный ключ портала
+func generateFeedbackKey() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate feedback portal key: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// submitterLabel возвращает имя и адрес автора запроса
+func submitterLabel(submission *domain.FeedbackSubmission) string {
+	if submission.SubmitterName == "" {
+		return submission.SubmitterEmail
+	}
+	return fmt.Sprintf("%s <%s>", submission.SubmitterName, submission.SubmitterEmail)
+}
+
+// taskStatusLabel возвращает название статуса задачи для писем
+func taskStatusLabel(status domain.TaskStatus) string {
+	if label, ok := feedbackTaskStatusLabels[status]; ok {
+		return label
+	}
+	return string(status)
+}
+
+// withNote дополняет текст письма комментарием модератора
+func withNote(body, note string) string {
+	if note = strings.TrimSpace(note); note == "" {
+		return body
+	}
+	return body + "\n\nКомментарий команды: " + note
+}
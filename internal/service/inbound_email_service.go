@@ -0,0 +1,572 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/internal/repository"
+	"github.com/nurlyy/task_manager/pkg/config"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
+	"github.com/nurlyy/task_manager/pkg/logger"
+)
+
+var (
+	ErrInboundEmailNotFound = apperrors.New(apperrors.CodeInboundEmailNotFound, "inbound email address not found")
+	ErrInboundEmailRejected = apperrors.New(apperrors.CodeInboundEmailRejected, "inbound email rejected")
+)
+
+var (
+	// spamStatusScorePattern извлекает оценку из заголовка X-Spam-Status: "Yes, score=7.1 required=5.0"
+	spamStatusScorePattern = regexp.MustCompile(`score=(-?[0-9]+(?:\.[0-9]+)?)`)
+	// htmlTagPattern используется для извлечения текста из HTML-писем без текстовой части
+	htmlTagPattern = regexp.MustCompile(`<[^>]*>`)
+	// headerCommentPattern удаляет комментарии в скобках из заголовка Authentication-Results
+	headerCommentPattern = regexp.MustCompile(`\([^()]*\)`)
+)
+
+// InboundEmailService представляет бизнес-логику создания задач по email
+type InboundEmailService struct {
+	inboundRepo repository.InboundEmailRepository
+	userRepo    repository.UserRepository
+	taskSvc     *TaskService
+	config      *config.InboundEmailConfig
+	logger      logger.Logger
+}
+
+// NewInboundEmailService создает новый экземпляр InboundEmailService
+func NewInboundEmailService(
+	inboundRepo repository.InboundEmailRepository,
+	userRepo repository.UserRepository,
+	taskSvc *TaskService,
+	config *config.InboundEmailConfig,
+	logger logger.Logger,
+) *InboundEmailService {
+	return &InboundEmailService{
+		inboundRepo: inboundRepo,
+		userRepo:    userRepo,
+		taskSvc:     taskSvc,
+		config:      config,
+		logger:      logger,
+	}
+}
+
+// Get возвращает адрес проекта для создания задач по email
+func (s *InboundEmailService) Get(ctx context.Context, projectID string) (*domain.ProjectInboundEmail, error) {
+	inbound, err := s.inboundRepo.GetByProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if inbound == nil {
+		return nil, ErrInboundEmailNotFound
+	}
+
+	inbound.Address = s.address(inbound.Token)
+	return inbound, nil
+}
+
+// Generate создает адрес проекта или выдает новый токен для существующего адреса.
+// Старый адрес после этого перестает принимать письма
+func (s *InboundEmailService) Generate(ctx context.Context, projectID string, userID string) (*domain.ProjectInboundEmail, error) {
+	inbound, err := s.inboundRepo.GetByProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if inbound == nil {
+		inbound = &domain.ProjectInboundEmail{
+			ProjectID: projectID,
+			Enabled:   true,
+			CreatedBy: userID,
+			CreatedAt: now,
+		}
+	}
+
+	token, err := generateInboundToken()
+	if err != nil {
+		return nil, err
+	}
+	inbound.Token = token
+	inbound.UpdatedAt = now
+
+	if err := s.inboundRepo.Save(ctx, inbound); err != nil {
+		s.logger.Error("Failed to generate project inbound email", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return nil, err
+	}
+
+	s.logger.Info("Project inbound email generated", map[string]interface{}{
+		"project_id": projectID,
+		"user_id":    userID,
+	})
+
+	inbound.Address = s.address(inbound.Token)
+	return inbound, nil
+}
+
+// Update изменяет настройки адреса проекта
+func (s *InboundEmailService) Update(ctx context.Context, projectID string, req domain.ProjectInboundEmailRequest) (*domain.ProjectInboundEmail, error) {
+	inbound, err := s.inboundRepo.GetByProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if inbound == nil {
+		return nil, ErrInboundEmailNotFound
+	}
+
+	if req.Enabled != nil {
+		inbound.Enabled = *req.Enabled
+	}
+	if req.SpamThreshold != nil {
+		if *req.SpamThreshold == 0 {
+			inbound.SpamThreshold = nil
+		} else {
+			threshold := *req.SpamThreshold
+			inbound.SpamThreshold = &threshold
+		}
+	}
+	inbound.UpdatedAt = time.Now()
+
+	if err := s.inboundRepo.Save(ctx, inbound); err != nil {
+		s.logger.Error("Failed to update project inbound email", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return nil, err
+	}
+
+	inbound.Address = s.address(inbound.Token)
+	return inbound, nil
+}
+
+// Delete удаляет адрес проекта
+func (s *InboundEmailService) Delete(ctx context.Context, projectID string) error {
+	return s.inboundRepo.Delete(ctx, projectID)
+}
+
+// Start запускает воркер приема почты, который периодически обрабатывает новые письма в каталоге Maildir.
+// Принятые и отклоненные письма переносятся в cur, письма с временными ошибками остаются в new до следующего прохода
+func (s *InboundEmailService) Start(ctx context.Context) error {
+	if s.config.Maildir == "" {
+		return fmt.Errorf("inbound email maildir is not configured")
+	}
+
+	for _, dir := range []string{"new", "cur", "tmp"} {
+		if err := os.MkdirAll(filepath.Join(s.config.Maildir, dir), 0o750); err != nil {
+			return fmt.Errorf("failed to prepare maildir: %w", err)
+		}
+	}
+
+	s.logger.Info("Starting inbound email worker", map[string]interface{}{
+		"maildir": s.config.Maildir,
+		"domain":  s.config.Domain,
+	})
+
+	go func() {
+		ticker := time.NewTicker(s.config.PollInterval)
+		defer ticker.Stop()
+
+		for {
+			s.processMaildir(ctx)
+
+			select {
+			case <-ctx.Done():
+				s.logger.Info("Stopping inbound email worker")
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return nil
+}
+
+// Ingest обрабатывает одно письмо и создает задачу в проекте, на адрес которого оно отправлено.
+// Возвращает ошибку, обернутую в ErrInboundEmailRejected, если письмо не может быть принято
+func (s *InboundEmailService) Ingest(ctx context.Context, r io.Reader) (*domain.InboundEmailMessage, error) {
+	raw, err := io.ReadAll(io.LimitReader(r, s.config.MaxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read inbound email: %w", err)
+	}
+	if int64(len(raw)) > s.config.MaxSize {
+		return nil, fmt.Errorf("%w: message exceeds %d bytes", ErrInboundEmailRejected, s.config.MaxSize)
+	}
+
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed message: %v", ErrInboundEmailRejected, err)
+	}
+
+	// Письма без Message-ID различаем по содержимому
+	messageID := strings.TrimSpace(msg.Header.Get("Message-Id"))
+	if messageID == "" {
+		sum := sha256.Sum256(raw)
+		messageID = "sha256:" + hex.EncodeToString(sum[:])
+	}
+
+	processed, err := s.inboundRepo.IsMessageProcessed(ctx, messageID)
+	if err != nil {
+		return nil, err
+	}
+	if processed {
+		return nil, fmt.Errorf("%w: message %s has already been processed", ErrInboundEmailRejected, messageID)
+	}
+
+	inbound, err := s.findRecipient(ctx, msg.Header)
+	if err != nil {
+		return nil, err
+	}
+
+	// Проверяем оценку спама, выставленную почтовым сервером
+	threshold := s.config.SpamThreshold
+	if inbound.SpamThreshold != nil {
+		threshold = *inbound.SpamThreshold
+	}
+	if spam, score := isSpam(msg.Header, threshold); spam {
+		return nil, fmt.Errorf("%w: spam score %.2f reaches threshold %.2f", ErrInboundEmailRejected, score, threshold)
+	}
+
+	// Отправитель должен быть пользователем системы; задача создается от его имени
+	from, err := mail.ParseAddress(msg.Header.Get("From"))
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid sender: %v", ErrInboundEmailRejected, err)
+	}
+	// Адрес в From может подделать кто угодно: доверяем ему, только если доверенный почтовый
+	// сервер подтвердил домен отправителя проверкой DKIM или DMARC
+	if s.config.AuthServID == "" {
+		return nil, fmt.Errorf("%w: sender authentication is not configured", ErrInboundEmailRejected)
+	}
+	if !senderAuthenticated(msg.Header, s.config.AuthServID, from.Address) {
+		return nil, fmt.Errorf("%w: sender %s failed DKIM/DMARC authentication", ErrInboundEmailRejected, from.Address)
+	}
+	sender, err := s.userRepo.GetByEmail(ctx, from.Address)
+	if err != nil {
+		return nil, err
+	}
+	if sender == nil || !sender.IsActive {
+		return nil, fmt.Errorf("%w: sender %s is not a user", ErrInboundEmailRejected, from.Address)
+	}
+
+	body, err := extractTextBody(msg.Header, msg.Body)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to read message body: %v", ErrInboundEmailRejected, err)
+	}
+
+	req := domain.TaskCreateRequest{
+		Title:       inboundTaskTitle(decodeHeader(msg.Header.Get("Subject")), from.Address),
+		Description: body,
+		ProjectID:   inbound.ProjectID,
+	}
+	if req.Description == "" {
+		req.Description = "(empty message)"
+	}
+
+	task, err := s.taskSvc.Create(ctx, req, sender.ID)
+	if err != nil {
+		var validationErr *TaskValidationError
+		if errors.As(err, &validationErr) ||
+			errors.Is(err, ErrProjectNotFound) ||
+			errors.Is(err, ErrProjectArchived) {
+			return nil, fmt.Errorf("%w: %v", ErrInboundEmailRejected, err)
+		}
+		return nil, err
+	}
+
+	message := &domain.InboundEmailMessage{
+		MessageID:  messageID,
+		ProjectID:  inbound.ProjectID,
+		TaskID:     &task.ID,
+		Sender:     from.Address,
+		ReceivedAt: time.Now(),
+	}
+	if err := s.inboundRepo.RecordMessage(ctx, message); err != nil {
+		s.logger.Warn("Failed to record inbound email message", map[string]interface{}{
+			"message_id": messageID,
+			"task_id":    task.ID,
+			"error":      err.Error(),
+		})
+	}
+
+	s.logger.Info("Task created from email", map[string]interface{}{
+		"project_id": inbound.ProjectID,
+		"task_id":    task.ID,
+		"user_id":    sender.ID,
+	})
+
+	return message, nil
+}
+
+// processMaildir обрабатывает все письма из каталога new
+func (s *InboundEmailService) processMaildir(ctx context.Context) {
+	newDir := filepath.Join(s.config.Maildir, "new")
+	entries, err := os.ReadDir(newDir)
+	if err != nil {
+		s.logger.Error("Failed to read maildir", err, map[string]interface{}{
+			"dir": newDir,
+		})
+		return
+	}
+
+	for _, entry := range entries {
+		if ctx.Err() != nil {
+			return
+		}
+		if entry.IsDir() {
+			continue
+		}
+
+		path := filepath.Join(newDir, entry.Name())
+		file, err := os.Open(path)
+		if err != nil {
+			s.logger.Error("Failed to open inbound email", err, map[string]interface{}{
+				"file": entry.Name(),
+			})
+			continue
+		}
+		_, err = s.Ingest(ctx, file)
+		file.Close()
+
+		// Флаги Maildir: S - обработано, T - отклонено
+		flag := "S"
+		if err != nil {
+			if !errors.Is(err, ErrInboundEmailRejected) {
+				s.logger.Error("Failed to process inbound email, will retry", err, map[string]interface{}{
+					"file": entry.Name(),
+				})
+				continue
+			}
+			s.logger.Warn("Inbound email rejected", map[string]interface{}{
+				"file":   entry.Name(),
+				"reason": err.Error(),
+			})
+			flag = "T"
+		}
+
+		target := filepath.Join(s.config.Maildir, "cur", entry.Name()+":2,"+flag)
+		if err := os.Rename(path, target); err != nil {
+			s.logger.Error("Failed to move processed inbound email", err, map[string]interface{}{
+				"file": entry.Name(),
+			})
+		}
+	}
+}
+
+// findRecipient находит включенный адрес проекта среди получателей письма
+func (s *InboundEmailService) findRecipient(ctx context.Context, header mail.Header) (*domain.ProjectInboundEmail, error) {
+	prefix := strings.ToLower(s.config.Mailbox) + "+"
+	domainSuffix := "@" + strings.ToLower(s.config.Domain)
+
+	for _, key := range []string{"Delivered-To", "X-Original-To", "To", "Cc"} {
+		for _, value := range header[key] {
+			addresses, err := mail.ParseAddressList(value)
+			if err != nil {
+				continue
+			}
+			for _, address := range addresses {
+				email := strings.ToLower(address.Address)
+				if !strings.HasPrefix(email, prefix) || !strings.HasSuffix(email, domainSuffix) {
+					continue
+				}
+
+				token := strings.TrimSuffix(strings.TrimPrefix(email, prefix), domainSuffix)
+				inbound, err := s.inboundRepo.GetByToken(ctx, token)
+				if err != nil {
+					return nil, err
+				}
+				if inbound != nil && inbound.Enabled {
+					return inbound, nil
+				}
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("%w: no enabled project address among recipients", ErrInboundEmailRejected)
+}
+
+// address формирует email-адрес проекта по токену
+func (s *InboundEmailService) address(token string) string {
+	return s.config.Mailbox + "+" + token + "@" + s.config.Domain
+}
+
+// generateInboundToken генерирует токен адреса; токен в нижнем регистре,
+// так как почтовые серверы могут не сохранять регистр локальной части
+func generateInboundToken() (string, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate inbound email token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// isSpam проверяет заголовки оценки спама, выставленные почтовым сервером
+func isSpam(header mail.Header, threshold float64) (bool, float64) {
+	if strings.EqualFold(strings.TrimSpace(header.Get("X-Spam-Flag")), "yes") {
+		return true, threshold
+	}
+
+	value := strings.TrimSpace(header.Get("X-Spam-Score"))
+	if value == "" {
+		if match := spamStatusScorePattern.FindStringSubmatch(header.Get("X-Spam-Status")); match != nil {
+			value = match[1]
+		}
+	}
+
+	score, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return false, 0
+	}
+	return score >= threshold, score
+}
+
+// senderAuthenticated проверяет по заголовкам Authentication-Results сервера authServID, что домен
+// адреса from прошел DMARC или подписан DKIM. Заголовки других серверов не учитываются
+func senderAuthenticated(header mail.Header, authServID string, from string) bool {
+	at := strings.LastIndex(from, "@")
+	if at < 0 {
+		return false
+	}
+	fromDomain := strings.ToLower(from[at+1:])
+
+	for _, value := range header["Authentication-Results"] {
+		parts := strings.Split(headerCommentPattern.ReplaceAllString(value, ""), ";")
+		if fields := strings.Fields(parts[0]); len(fields) == 0 || !strings.EqualFold(fields[0], authServID) {
+			continue
+		}
+
+		for _, part := range parts[1:] {
+			fields := strings.Fields(strings.ToLower(part))
+			if len(fields) == 0 {
+				continue
+			}
+			props := make(map[string]string, len(fields)-1)
+			for _, field := range fields[1:] {
+				if key, val, ok := strings.Cut(field, "="); ok {
+					props[key] = strings.Trim(val, `"`)
+				}
+			}
+
+			switch fields[0] {
+			case "dmarc=pass":
+				if props["header.from"] == fromDomain {
+					return true
+				}
+			case "dkim=pass":
+				if props["header.d"] == fromDomain || strings.HasSuffix(props["header.i"], "@"+fromDomain) {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+// inboundTaskTitle формирует заголовок задачи из темы письма с учетом ограничений длины заголовка
+func inboundTaskTitle(subject, sender string) string {
+	title := strings.Join(strings.Fields(subject), " ")
+	if len([]rune(title)) < 3 {
+		title = "Email from " + sender
+	}
+	if runes := []rune(title); len(runes) > 200 {
+		title = string(runes[:200])
+	}
+	return title
+}
+
+// decodeHeader декодирует заголовок в формате RFC 2047
+func decodeHeader(value string) string {
+	decoded, err := new(mime.WordDecoder).DecodeHeader(value)
+	if err != nil {
+		return value
+	}
+	return decoded
+}
+
+// extractTextBody возвращает текст письма: text/plain, а при его отсутствии - text/html без разметки
+func extractTextBody(header mail.Header, body io.Reader) (string, error) {
+	text, html, err := readTextParts(header.Get("Content-Type"), header.Get("Content-Transfer-Encoding"), body)
+	if err != nil {
+		return "", err
+	}
+	if text == "" && html != "" {
+		text = htmlTagPattern.ReplaceAllString(html, "")
+	}
+	return strings.TrimSpace(text), nil
+}
+
+// readTextParts рекурсивно обходит части письма и возвращает первые найденные text/plain и text/html
+func readTextParts(contentType, transferEncoding string, body io.Reader) (string, string, error) {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = "text/plain"
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		var text, html string
+		reader := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := reader.NextPart()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return "", "", err
+			}
+			// Вложения не переносятся в описание задачи
+			if part.FileName() != "" {
+				continue
+			}
+
+			partText, partHTML, err := readTextParts(part.Header.Get("Content-Type"), part.Header.Get("Content-Transfer-Encoding"), part)
+			if err != nil {
+				return "", "", err
+			}
+			if text == "" {
+				text = partText
+			}
+			if html == "" {
+				html = partHTML
+			}
+		}
+		return text, html, nil
+	}
+
+	if mediaType != "text/plain" && mediaType != "text/html" {
+		return "", "", nil
+	}
+
+	switch strings.ToLower(strings.TrimSpace(transferEncoding)) {
+	case "base64":
+		body = base64.NewDecoder(base64.StdEncoding, body)
+	case "quoted-printable":
+		body = quotedprintable.NewReader(body)
+	}
+
+	content, err := io.ReadAll(body)
+	if err != nil {
+		return "", "", err
+	}
+
+	if mediaType == "text/html" {
+		return "", string(content), nil
+	}
+	return string(content), "", nil
+}
@@ -0,0 +1,212 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/internal/messaging"
+	"github.com/nurlyy/task_manager/internal/repository"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
+	"github.com/nurlyy/task_manager/pkg/logger"
+)
+
+var (
+	ErrInboxItemNotFound = apperrors.New(apperrors.CodeInboxItemNotFound, "inbox item not found")
+)
+
+// InboxService представляет бизнес-логику входящих: быстрой записи задач без проекта
+// и их последующего разбора по проектам
+type InboxService struct {
+	inboxRepo        repository.InboxRepository
+	notificationRepo repository.NotificationRepository
+	txManager        repository.TxManager
+	taskSvc          *TaskService
+	producer         *messaging.KafkaProducer
+	logger           logger.Logger
+}
+
+// NewInboxService создает новый экземпляр InboxService
+func NewInboxService(
+	inboxRepo repository.InboxRepository,
+	notificationRepo repository.NotificationRepository,
+	txManager repository.TxManager,
+	taskSvc *TaskService,
+	producer *messaging.KafkaProducer,
+	logger logger.Logger,
+) *InboxService {
+	return &InboxService{
+		inboxRepo:        inboxRepo,
+		notificationRepo: notificationRepo,
+		txManager:        txManager,
+		taskSvc:          taskSvc,
+		producer:         producer,
+		logger:           logger,
+	}
+}
+
+// Capture записывает задачу во входящие пользователя
+func (s *InboxService) Capture(ctx context.Context, req domain.InboxCaptureRequest, userID string) (*domain.InboxItem, error) {
+	item := &domain.InboxItem{
+		ID:          uuid.New().String(),
+		UserID:      userID,
+		Title:       strings.TrimSpace(req.Title),
+		Description: req.Description,
+		Priority:    req.Priority,
+		DueDate:     req.DueDate,
+		CreatedAt:   time.Now(),
+	}
+
+	if err := s.inboxRepo.Create(ctx, item); err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+// List возвращает входящие пользователя, начиная со старых
+func (s *InboxService) List(ctx context.Context, userID string, page domain.PageRequest) (*domain.PagedResponse, error) {
+	items, err := s.inboxRepo.ListByUser(ctx, userID, page.Limit(), page.Offset())
+	if err != nil {
+		return nil, err
+	}
+	items, hasMore := domain.PageItems(items, page)
+	if items == nil {
+		items = []*domain.InboxItem{}
+	}
+
+	total := 0
+	if !page.SkipCount {
+		total, err = s.inboxRepo.CountByUser(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return domain.NewPagedResponse(items, page, total, hasMore), nil
+}
+
+// Delete удаляет запись из входящих пользователя
+func (s *InboxService) Delete(ctx context.Context, id string, userID string) error {
+	if _, err := s.getOwnItem(ctx, id, userID); err != nil {
+		return err
+	}
+	return s.inboxRepo.Delete(ctx, id)
+}
+
+// Triage переносит запись входящих в проект: забирает запись и создает задачу со всеми
+// проверками сервиса задач в одной транзакции. Одновременные запросы не создадут две задачи
+// из одной записи, а если задачу создать не удалось, запись остается во входящих
+func (s *InboxService) Triage(ctx context.Context, req domain.InboxTriageItem, userID string) (*domain.TaskResponse, error) {
+	var task *domain.TaskResponse
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		item, err := s.inboxRepo.Claim(ctx, req.ID, userID)
+		if err != nil {
+			return err
+		}
+		if item == nil {
+			return ErrInboxItemNotFound
+		}
+
+		// События задачи публикуются через таблицу исходящих событий и не уйдут при откате
+		task, err = s.taskSvc.Create(messaging.WithOutbox(ctx), triageTaskRequest(item, req), userID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return task, nil
+}
+
+// triageTaskRequest собирает запрос на создание задачи из записи входящих и параметров разбора
+func triageTaskRequest(item *domain.InboxItem, req domain.InboxTriageItem) domain.TaskCreateRequest {
+	taskReq := domain.TaskCreateRequest{
+		Title:       item.Title,
+		Description: item.Description,
+		ProjectID:   req.ProjectID,
+		AssigneeID:  req.AssigneeID,
+		DueDate:     item.DueDate,
+	}
+	if item.Priority != nil {
+		taskReq.Priority = *item.Priority
+	}
+	if req.Priority != nil {
+		taskReq.Priority = *req.Priority
+	}
+	if req.DueDate != nil {
+		taskReq.DueDate = req.DueDate
+	}
+	return taskReq
+}
+
+// RemindStale напоминает пользователям о записях входящих, созданных раньше createdBefore.
+// Возвращает количество отправленных напоминаний
+func (s *InboxService) RemindStale(ctx context.Context, createdBefore time.Time) (int, error) {
+	reminders, err := s.inboxRepo.ListStaleOwners(ctx, createdBefore)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, reminder := range reminders {
+		notification := &domain.Notification{
+			ID:         uuid.New().String(),
+			UserID:     reminder.UserID,
+			Type:       domain.NotificationTypeDigest,
+			Title:      "Неразобранные входящие",
+			Content:    fmt.Sprintf("Во входящих ждут разбора задач: %d, самая старая записана %s", reminder.StaleItems, reminder.OldestSince.Format("02.01.2006")),
+			Status:     domain.NotificationStatusUnread,
+			EntityType: "inbox",
+			EntityID:   reminder.UserID,
+			CreatedAt:  time.Now(),
+			MetaData: map[string]string{
+				"stale_items": strconv.Itoa(reminder.StaleItems),
+			},
+		}
+
+		if err := s.notificationRepo.Create(ctx, notification); err != nil {
+			s.logger.Error("Failed to create inbox reminder", err, map[string]interface{}{
+				"user_id": reminder.UserID,
+			})
+			continue
+		}
+
+		event := &messaging.NotificationEvent{
+			UserIDs:    []string{reminder.UserID},
+			Title:      notification.Title,
+			Content:    notification.Content,
+			Type:       string(notification.Type),
+			EntityID:   notification.EntityID,
+			EntityType: notification.EntityType,
+			CreatedAt:  notification.CreatedAt,
+			MetaData:   notification.MetaData,
+		}
+
+		if err := s.producer.PublishNotification(ctx, event); err != nil {
+			s.logger.Error("Failed to publish inbox reminder", err, map[string]interface{}{
+				"user_id": reminder.UserID,
+			})
+		}
+		sent++
+	}
+
+	return sent, nil
+}
+
+// getOwnItem возвращает запись входящих, если она принадлежит пользователю
+func (s *InboxService) getOwnItem(ctx context.Context, id string, userID string) (*domain.InboxItem, error) {
+	item, err := s.inboxRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if item == nil || item.UserID != userID {
+		return nil, ErrInboxItemNotFound
+	}
+	return item, nil
+}
@@ -0,0 +1,259 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+)
+
+// incidentProvider описывает работу с конкретной системой управления инцидентами
+type incidentProvider interface {
+	// verify проверяет подлинность входящего вебхука
+	verify(integration *domain.IncidentIntegration, body []byte, signature, token string) bool
+	// parseEvent приводит вебхук к общему виду; nil означает событие, не влияющее на задачу
+	parseEvent(body []byte) (*domain.IncidentEvent, error)
+	// acknowledge подтверждает инцидент
+	acknowledge(ctx context.Context, integration *domain.IncidentIntegration, incidentID string) error
+	// resolve закрывает инцидент
+	resolve(ctx context.Context, integration *domain.IncidentIntegration, incidentID string) error
+}
+
+// newIncidentProviders создает клиентов поддерживаемых систем управления инцидентами
+func newIncidentProviders(client *http.Client, pagerDutyAPIURL, opsgenieAPIURL string) map[domain.IncidentProvider]incidentProvider {
+	return map[domain.IncidentProvider]incidentProvider{
+		domain.IncidentProviderPagerDuty: &pagerDutyProvider{client: client, apiURL: strings.TrimRight(pagerDutyAPIURL, "/")},
+		domain.IncidentProviderOpsgenie:  &opsgenieProvider{client: client, apiURL: strings.TrimRight(opsgenieAPIURL, "/")},
+	}
+}
+
+// pagerDutyProvider работает с вебхуками v3 и REST API v2 PagerDuty
+type pagerDutyProvider struct {
+	client *http.Client
+	apiURL string
+}
+
+// pagerDutyWebhook представляет вебхук PagerDuty v3
+type pagerDutyWebhook struct {
+	Event struct {
+		EventType string `json:"event_type"`
+		Data      struct {
+			ID          string `json:"id"`
+			Type        string `json:"type"`
+			Title       string `json:"title"`
+			Description string `json:"description"`
+			HTMLURL     string `json:"html_url"`
+			Urgency     string `json:"urgency"`
+			Priority    *struct {
+				Summary string `json:"summary"`
+			} `json:"priority"`
+			Service *struct {
+				Summary string `json:"summary"`
+			} `json:"service"`
+		} `json:"data"`
+	} `json:"event"`
+}
+
+// verify проверяет заголовок X-PagerDuty-Signature: "v1=<hex>[,v1=<hex>]".
+// Несколько подписей приходят во время смены ключа подписи
+func (p *pagerDutyProvider) verify(integration *domain.IncidentIntegration, body []byte, signature, token string) bool {
+	mac := hmac.New(sha256.New, []byte(integration.Secret))
+	mac.Write(body)
+	expected := "v1=" + hex.EncodeToString(mac.Sum(nil))
+
+	for _, candidate := range strings.Split(signature, ",") {
+		if hmac.Equal([]byte(strings.TrimSpace(candidate)), []byte(expected)) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseEvent разбирает события инцидентов PagerDuty
+func (p *pagerDutyProvider) parseEvent(body []byte) (*domain.IncidentEvent, error) {
+	var webhook pagerDutyWebhook
+	if err := json.Unmarshal(body, &webhook); err != nil {
+		return nil, err
+	}
+
+	var status domain.IncidentStatus
+	switch webhook.Event.EventType {
+	case "incident.triggered", "incident.reopened":
+		status = domain.IncidentStatusTriggered
+	case "incident.acknowledged":
+		status = domain.IncidentStatusAcknowledged
+	case "incident.resolved":
+		status = domain.IncidentStatusResolved
+	default:
+		return nil, nil
+	}
+
+	data := webhook.Event.Data
+	if data.ID == "" {
+		return nil, fmt.Errorf("incident id is missing")
+	}
+
+	event := &domain.IncidentEvent{
+		IncidentID:  data.ID,
+		Status:      status,
+		Title:       data.Title,
+		Description: data.Description,
+		URL:         data.HTMLURL,
+		Severity:    data.Urgency,
+	}
+	if data.Priority != nil && data.Priority.Summary != "" {
+		event.Severity = data.Priority.Summary
+	}
+	if data.Service != nil && data.Service.Summary != "" {
+		event.Tags = []string{data.Service.Summary}
+	}
+	return event, nil
+}
+
+// acknowledge подтверждает инцидент PagerDuty
+func (p *pagerDutyProvider) acknowledge(ctx context.Context, integration *domain.IncidentIntegration, incidentID string) error {
+	return p.updateStatus(ctx, integration, incidentID, "acknowledged")
+}
+
+// resolve закрывает инцидент PagerDuty
+func (p *pagerDutyProvider) resolve(ctx context.Context, integration *domain.IncidentIntegration, incidentID string) error {
+	return p.updateStatus(ctx, integration, incidentID, "resolved")
+}
+
+// updateStatus изменяет статус инцидента через REST API. API требует email пользователя PagerDuty в заголовке From
+func (p *pagerDutyProvider) updateStatus(ctx context.Context, integration *domain.IncidentIntegration, incidentID, status string) error {
+	payload := map[string]interface{}{
+		"incident": map[string]string{
+			"type":   "incident_reference",
+			"status": status,
+		},
+	}
+
+	headers := map[string]string{
+		"Authorization": "Token token=" + integration.APIKey,
+		"Accept":        "application/vnd.pagerduty+json;version=2",
+		"From":          integration.FromEmail,
+	}
+
+	return sendIncidentRequest(ctx, p.client, http.MethodPut, p.apiURL+"/incidents/"+url.PathEscape(incidentID), headers, payload)
+}
+
+// opsgenieProvider работает с webhook-интеграцией и Alert API v2 Opsgenie
+type opsgenieProvider struct {
+	client *http.Client
+	apiURL string
+}
+
+// opsgenieWebhook представляет вебхук Opsgenie
+type opsgenieWebhook struct {
+	Action string `json:"action"`
+	Alert  struct {
+		AlertID     string   `json:"alertId"`
+		Message     string   `json:"message"`
+		Description string   `json:"description"`
+		Priority    string   `json:"priority"`
+		Tags        []string `json:"tags"`
+	} `json:"alert"`
+}
+
+// verify сравнивает токен из URL вебхука: Opsgenie не подписывает запросы
+func (p *opsgenieProvider) verify(integration *domain.IncidentIntegration, body []byte, signature, token string) bool {
+	return token != "" && hmac.Equal([]byte(token), []byte(integration.Secret))
+}
+
+// parseEvent разбирает события алертов Opsgenie
+func (p *opsgenieProvider) parseEvent(body []byte) (*domain.IncidentEvent, error) {
+	var webhook opsgenieWebhook
+	if err := json.Unmarshal(body, &webhook); err != nil {
+		return nil, err
+	}
+
+	var status domain.IncidentStatus
+	switch webhook.Action {
+	case "Create":
+		status = domain.IncidentStatusTriggered
+	case "Acknowledge":
+		status = domain.IncidentStatusAcknowledged
+	case "Close":
+		status = domain.IncidentStatusResolved
+	default:
+		return nil, nil
+	}
+
+	if webhook.Alert.AlertID == "" {
+		return nil, fmt.Errorf("alert id is missing")
+	}
+
+	return &domain.IncidentEvent{
+		IncidentID:  webhook.Alert.AlertID,
+		Status:      status,
+		Title:       webhook.Alert.Message,
+		Description: webhook.Alert.Description,
+		Severity:    webhook.Alert.Priority,
+		Tags:        webhook.Alert.Tags,
+	}, nil
+}
+
+// acknowledge подтверждает алерт Opsgenie
+func (p *opsgenieProvider) acknowledge(ctx context.Context, integration *domain.IncidentIntegration, incidentID string) error {
+	return p.alertAction(ctx, integration, incidentID, "acknowledge")
+}
+
+// resolve закрывает алерт Opsgenie
+func (p *opsgenieProvider) resolve(ctx context.Context, integration *domain.IncidentIntegration, incidentID string) error {
+	return p.alertAction(ctx, integration, incidentID, "close")
+}
+
+// alertAction выполняет действие над алертом через Alert API
+func (p *opsgenieProvider) alertAction(ctx context.Context, integration *domain.IncidentIntegration, alertID, action string) error {
+	payload := map[string]string{
+		"source": "task-tracker",
+		"note":   "Updated from the linked task",
+	}
+
+	headers := map[string]string{
+		"Authorization": "GenieKey " + integration.APIKey,
+	}
+
+	endpoint := fmt.Sprintf("%s/v2/alerts/%s/%s?identifierType=id", p.apiURL, url.PathEscape(alertID), action)
+	return sendIncidentRequest(ctx, p.client, http.MethodPost, endpoint, headers, payload)
+}
+
+// sendIncidentRequest отправляет JSON-запрос в API системы управления инцидентами
+func sendIncidentRequest(ctx context.Context, client *http.Client, method, endpoint string, headers map[string]string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode incident request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create incident request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send incident request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("incident API returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+
+	return nil
+}
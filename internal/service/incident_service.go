@@ -0,0 +1,525 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/internal/messaging"
+	"github.com/nurlyy/task_manager/internal/repository"
+	"github.com/nurlyy/task_manager/pkg/config"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
+	"github.com/nurlyy/task_manager/pkg/logger"
+	"github.com/segmentio/kafka-go"
+)
+
+var (
+	ErrIncidentIntegrationNotFound = apperrors.New(apperrors.CodeIncidentIntegrationNotFound, "incident integration not found")
+	ErrIncidentProviderUnsupported = apperrors.New(apperrors.CodeUnsupportedProvider, "unsupported incident provider")
+	ErrIncidentSignatureInvalid    = apperrors.New(apperrors.CodeInvalidSignature, "invalid incident webhook signature")
+	ErrIncidentPayloadInvalid      = apperrors.New(apperrors.CodeInvalidFormat, "invalid incident webhook payload")
+)
+
+// IncidentService представляет бизнес-логику двусторонней синхронизации инцидентов PagerDuty и Opsgenie с задачами
+type IncidentService struct {
+	incidentRepo repository.IncidentRepository
+	taskSvc      *TaskService
+	providers    map[domain.IncidentProvider]incidentProvider
+	baseURL      string
+	logger       logger.Logger
+}
+
+// NewIncidentService создает новый экземпляр IncidentService
+func NewIncidentService(
+	incidentRepo repository.IncidentRepository,
+	taskSvc *TaskService,
+	config *config.IncidentConfig,
+	baseURL string,
+	logger logger.Logger,
+) *IncidentService {
+	client := &http.Client{
+		Timeout: config.Timeout,
+	}
+
+	return &IncidentService{
+		incidentRepo: incidentRepo,
+		taskSvc:      taskSvc,
+		providers:    newIncidentProviders(client, config.PagerDutyAPIURL, config.OpsgenieAPIURL),
+		baseURL:      strings.TrimRight(baseURL, "/"),
+		logger:       logger,
+	}
+}
+
+// List возвращает интеграции проекта с системами управления инцидентами
+func (s *IncidentService) List(ctx context.Context, projectID string) ([]*domain.IncidentIntegration, error) {
+	integrations, err := s.incidentRepo.ListIntegrations(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, integration := range integrations {
+		s.fillIntegration(integration)
+	}
+	return integrations, nil
+}
+
+// Get возвращает интеграцию проекта с системой управления инцидентами
+func (s *IncidentService) Get(ctx context.Context, projectID string, provider domain.IncidentProvider) (*domain.IncidentIntegration, error) {
+	if _, ok := s.providers[provider]; !ok {
+		return nil, ErrIncidentProviderUnsupported
+	}
+
+	integration, err := s.incidentRepo.GetIntegrationByProject(ctx, projectID, provider)
+	if err != nil {
+		return nil, err
+	}
+	if integration == nil {
+		return nil, ErrIncidentIntegrationNotFound
+	}
+
+	s.fillIntegration(integration)
+	return integration, nil
+}
+
+// Save создает или обновляет интеграцию проекта. Для PagerDuty нужен ключ подписи вебхука из настроек подписки,
+// для Opsgenie токен URL генерируется автоматически
+func (s *IncidentService) Save(ctx context.Context, projectID string, provider domain.IncidentProvider, req domain.IncidentIntegrationRequest, userID string) (*domain.IncidentIntegration, error) {
+	if _, ok := s.providers[provider]; !ok {
+		return nil, ErrIncidentProviderUnsupported
+	}
+
+	integration, err := s.incidentRepo.GetIntegrationByProject(ctx, projectID, provider)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if integration == nil {
+		integration = &domain.IncidentIntegration{
+			ID:        uuid.New().String(),
+			ProjectID: projectID,
+			Provider:  provider,
+			Enabled:   true,
+			CreatedBy: userID,
+			CreatedAt: now,
+		}
+	}
+
+	if req.Enabled != nil {
+		integration.Enabled = *req.Enabled
+	}
+	if req.Secret != nil && *req.Secret != "" {
+		integration.Secret = *req.Secret
+	}
+	if req.APIKey != nil && *req.APIKey != "" {
+		integration.APIKey = *req.APIKey
+	}
+	if req.FromEmail != nil {
+		integration.FromEmail = *req.FromEmail
+	}
+	integration.Mapping = normalizeIncidentMapping(req.Mapping)
+	integration.UpdatedAt = now
+
+	if integration.Secret == "" && provider == domain.IncidentProviderOpsgenie {
+		token, err := generateWebhookSecret()
+		if err != nil {
+			return nil, err
+		}
+		integration.Secret = token
+	}
+
+	if err := s.checkIntegration(ctx, integration); err != nil {
+		return nil, err
+	}
+
+	if err := s.incidentRepo.SaveIntegration(ctx, integration); err != nil {
+		s.logger.Error("Failed to save incident integration", err, map[string]interface{}{
+			"project_id": projectID,
+			"provider":   provider,
+		})
+		return nil, err
+	}
+
+	s.logger.Info("Incident integration saved", map[string]interface{}{
+		"project_id": projectID,
+		"provider":   provider,
+		"user_id":    userID,
+	})
+
+	s.fillIntegration(integration)
+	return integration, nil
+}
+
+// Delete удаляет интеграцию проекта вместе со связями инцидентов; задачи остаются
+func (s *IncidentService) Delete(ctx context.Context, projectID string, provider domain.IncidentProvider) error {
+	integration, err := s.Get(ctx, projectID, provider)
+	if err != nil {
+		return err
+	}
+
+	return s.incidentRepo.DeleteIntegration(ctx, integration.ID)
+}
+
+// Receive обрабатывает вебхук системы управления инцидентами: открытый инцидент создает задачу,
+// подтверждение и закрытие переводят связанную задачу в статусы из сопоставления.
+// Возвращает nil, если событие не относится к задачам
+func (s *IncidentService) Receive(ctx context.Context, id string, body []byte, signature, token string) (*domain.IncidentLink, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, ErrIncidentIntegrationNotFound
+	}
+
+	integration, err := s.incidentRepo.GetIntegration(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if integration == nil {
+		return nil, ErrIncidentIntegrationNotFound
+	}
+
+	provider, ok := s.providers[integration.Provider]
+	if !ok {
+		return nil, ErrIncidentProviderUnsupported
+	}
+	if !provider.verify(integration, body, signature, token) {
+		s.logger.Warn("Rejected incident webhook with invalid signature", map[string]interface{}{
+			"integration_id": integration.ID,
+		})
+		return nil, ErrIncidentSignatureInvalid
+	}
+	if !integration.Enabled {
+		return nil, nil
+	}
+
+	event, err := provider.parseEvent(body)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrIncidentPayloadInvalid, err)
+	}
+	if event == nil {
+		return nil, nil
+	}
+
+	link, err := s.incidentRepo.GetLink(ctx, integration.ID, event.IncidentID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch event.Status {
+	case domain.IncidentStatusTriggered:
+		if link != nil {
+			// Повторно открытый инцидент продолжает вести ту же задачу
+			if _, err := s.incidentRepo.UpdateLinkStatus(ctx, integration.ID, event.IncidentID, []domain.IncidentStatus{domain.IncidentStatusResolved}, domain.IncidentStatusTriggered); err != nil {
+				return nil, err
+			}
+			link.Status = domain.IncidentStatusTriggered
+			return link, nil
+		}
+		return s.createIncidentTask(ctx, integration, event)
+	case domain.IncidentStatusAcknowledged:
+		if link == nil {
+			return nil, nil
+		}
+		// Состояние связи меняется до задачи, чтобы синхронизация не отправила подтверждение обратно
+		updated, err := s.incidentRepo.UpdateLinkStatus(ctx, integration.ID, event.IncidentID, []domain.IncidentStatus{domain.IncidentStatusTriggered}, domain.IncidentStatusAcknowledged)
+		if err != nil {
+			return nil, err
+		}
+		if updated {
+			link.Status = domain.IncidentStatusAcknowledged
+			s.moveIncidentTask(ctx, integration, link.TaskID, []domain.TaskStatus{integration.Mapping.AcknowledgeStatus}, true)
+		}
+		return link, nil
+	case domain.IncidentStatusResolved:
+		if link == nil {
+			return nil, nil
+		}
+		updated, err := s.incidentRepo.UpdateLinkStatus(ctx, integration.ID, event.IncidentID, []domain.IncidentStatus{domain.IncidentStatusTriggered, domain.IncidentStatusAcknowledged}, domain.IncidentStatusResolved)
+		if err != nil {
+			return nil, err
+		}
+		if updated {
+			link.Status = domain.IncidentStatusResolved
+			s.moveIncidentTask(ctx, integration, link.TaskID, integration.Mapping.ResolveStatuses, false)
+		}
+		return link, nil
+	}
+
+	return nil, nil
+}
+
+// StartSync запускает чтение событий изменения задач: переход связанной задачи
+// в статус подтверждения или закрытия подтверждает или закрывает инцидент
+func (s *IncidentService) StartSync(ctx context.Context, kafkaConfig *config.KafkaConfig, topic string) error {
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:         kafkaConfig.Brokers,
+		Topic:           topic,
+		GroupID:         kafkaConfig.Namespaced("incident-sync-group"),
+		MinBytes:        10e3, // 10KB
+		MaxBytes:        10e6, // 10MB
+		MaxWait:         time.Second,
+		CommitInterval:  time.Second,
+		ReadLagInterval: -1,
+	})
+
+	s.logger.Info("Starting incident sync", map[string]interface{}{
+		"topic": topic,
+	})
+
+	go func() {
+		defer reader.Close()
+
+		for {
+			message, err := reader.ReadMessage(ctx)
+			if err != nil {
+				if ctx.Err() != nil {
+					s.logger.Info("Stopping incident sync")
+					return
+				}
+				s.logger.Error("Failed to read task event for incident sync", err)
+				continue
+			}
+
+			// События обрабатываются последовательно, чтобы переходы одной задачи не менялись местами
+			if err := s.syncTaskEvent(ctx, message.Value); err != nil {
+				s.logger.Error("Failed to sync incident", err, map[string]interface{}{
+					"task_id": string(message.Key),
+				})
+			}
+		}
+	}()
+
+	return nil
+}
+
+// syncTaskEvent подтверждает или закрывает инцидент, связанный с задачей из события
+func (s *IncidentService) syncTaskEvent(ctx context.Context, data []byte) error {
+	var event messaging.TaskEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		return fmt.Errorf("failed to unmarshal task event: %w", err)
+	}
+	if _, ok := event.Changes["status"]; !ok {
+		return nil
+	}
+
+	link, err := s.incidentRepo.GetLinkByTask(ctx, event.ID)
+	if err != nil || link == nil {
+		return err
+	}
+
+	integration, err := s.incidentRepo.GetIntegration(ctx, link.IntegrationID)
+	if err != nil || integration == nil {
+		return err
+	}
+	// Без ключа API синхронизация работает только в сторону задач
+	if !integration.Enabled || integration.APIKey == "" {
+		return nil
+	}
+
+	provider, ok := s.providers[integration.Provider]
+	if !ok {
+		return ErrIncidentProviderUnsupported
+	}
+
+	status := domain.TaskStatus(event.Status)
+	switch {
+	case containsTaskStatus(integration.Mapping.ResolveStatuses, status) && link.Status != domain.IncidentStatusResolved:
+		if err := provider.resolve(ctx, integration, link.IncidentID); err != nil {
+			return err
+		}
+		_, err = s.incidentRepo.UpdateLinkStatus(ctx, link.IntegrationID, link.IncidentID, []domain.IncidentStatus{domain.IncidentStatusTriggered, domain.IncidentStatusAcknowledged}, domain.IncidentStatusResolved)
+	case status == integration.Mapping.AcknowledgeStatus && link.Status == domain.IncidentStatusTriggered:
+		if err := provider.acknowledge(ctx, integration, link.IncidentID); err != nil {
+			return err
+		}
+		_, err = s.incidentRepo.UpdateLinkStatus(ctx, link.IntegrationID, link.IncidentID, []domain.IncidentStatus{domain.IncidentStatusTriggered}, domain.IncidentStatusAcknowledged)
+	default:
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	s.logger.Info("Incident synced with task status", map[string]interface{}{
+		"task_id":     event.ID,
+		"incident_id": link.IncidentID,
+		"provider":    integration.Provider,
+		"status":      event.Status,
+	})
+	return nil
+}
+
+// createIncidentTask создает задачу по открытому инциденту от имени создателя интеграции
+func (s *IncidentService) createIncidentTask(ctx context.Context, integration *domain.IncidentIntegration, event *domain.IncidentEvent) (*domain.IncidentLink, error) {
+	mapping := integration.Mapping
+
+	title := truncateRunes(strings.Join(strings.Fields(event.Title), " "), 200)
+	if len(title) < 3 {
+		title = "Incident " + event.IncidentID
+	}
+
+	description := strings.TrimSpace(event.Description)
+	if event.URL != "" {
+		description = strings.TrimSpace(description + "\n\n" + event.URL)
+	}
+	if description == "" {
+		description = title
+	}
+
+	req := domain.TaskCreateRequest{
+		Title:       title,
+		Description: description,
+		ProjectID:   integration.ProjectID,
+		Priority:    incidentTaskPriority(mapping.PriorityMap, event.Severity),
+		AssigneeID:  mapping.AssigneeID,
+		Tags:        []string{},
+	}
+	for _, tag := range append(append([]string{}, mapping.Tags...), event.Tags...) {
+		if tag = truncateRunes(strings.TrimSpace(tag), 50); tag != "" && !containsString(req.Tags, tag) {
+			req.Tags = append(req.Tags, tag)
+		}
+	}
+
+	task, err := s.taskSvc.Create(ctx, req, integration.CreatedBy)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	link := &domain.IncidentLink{
+		IntegrationID: integration.ID,
+		IncidentID:    event.IncidentID,
+		TaskID:        task.ID,
+		Status:        domain.IncidentStatusTriggered,
+		URL:           event.URL,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if err := s.incidentRepo.CreateLink(ctx, link); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Task created from incident", map[string]interface{}{
+		"project_id":  integration.ProjectID,
+		"task_id":     task.ID,
+		"incident_id": event.IncidentID,
+		"provider":    integration.Provider,
+	})
+
+	return link, nil
+}
+
+// moveIncidentTask переводит задачу в первый из целевых статусов, если она еще не в одном из них.
+// Если прямой переход запрещен, задача проходит через статус in_progress. onlyNew ограничивает
+// перевод новыми задачами, чтобы подтверждение инцидента не возвращало задачу назад.
+// Ошибки перевода не прерывают обработку вебхука
+func (s *IncidentService) moveIncidentTask(ctx context.Context, integration *domain.IncidentIntegration, taskID string, targets []domain.TaskStatus, onlyNew bool) {
+	task, err := s.taskSvc.GetByID(ctx, taskID, integration.CreatedBy)
+	if err != nil {
+		s.logger.Warn("Failed to get incident task", map[string]interface{}{
+			"task_id": taskID,
+			"error":   err.Error(),
+		})
+		return
+	}
+	if containsTaskStatus(targets, task.Status) || (onlyNew && task.Status != domain.TaskStatusNew) {
+		return
+	}
+
+	target := targets[0]
+	path := []domain.TaskStatus{target}
+	if !s.taskSvc.isValidStatusTransition(task.Status, target) &&
+		s.taskSvc.isValidStatusTransition(task.Status, domain.TaskStatusInProgress) &&
+		s.taskSvc.isValidStatusTransition(domain.TaskStatusInProgress, target) {
+		path = []domain.TaskStatus{domain.TaskStatusInProgress, target}
+	}
+
+	for _, status := range path {
+		if _, err := s.taskSvc.UpdateStatus(ctx, taskID, status, integration.CreatedBy); err != nil {
+			s.logger.Warn("Failed to update incident task status", map[string]interface{}{
+				"task_id": taskID,
+				"status":  status,
+				"error":   err.Error(),
+			})
+			return
+		}
+	}
+}
+
+// checkIntegration проверяет настройки, обязательные для системы, и исполнителя по умолчанию
+func (s *IncidentService) checkIntegration(ctx context.Context, integration *domain.IncidentIntegration) error {
+	var violations []domain.FieldViolation
+	if integration.Secret == "" {
+		violations = append(violations, domain.FieldViolation{
+			Field:   "secret",
+			Message: "Webhook signing secret from the PagerDuty subscription is required",
+		})
+	}
+	if integration.Provider == domain.IncidentProviderPagerDuty && integration.APIKey != "" && integration.FromEmail == "" {
+		violations = append(violations, domain.FieldViolation{
+			Field:   "from_email",
+			Message: "PagerDuty REST API requires the email of a PagerDuty user",
+		})
+	}
+	if len(violations) > 0 {
+		return &TaskValidationError{Violations: violations}
+	}
+
+	if integration.Mapping.AssigneeID != nil {
+		return s.taskSvc.checkAssigneesMembership(ctx, integration.ProjectID, []string{*integration.Mapping.AssigneeID})
+	}
+	return nil
+}
+
+// fillIntegration заполняет вычисляемые поля интеграции
+func (s *IncidentService) fillIntegration(integration *domain.IncidentIntegration) {
+	integration.APIKeySet = integration.APIKey != ""
+	integration.WebhookURL = fmt.Sprintf("%s/api/v1/integrations/incidents/%s", s.baseURL, integration.ID)
+	if integration.Provider == domain.IncidentProviderOpsgenie {
+		integration.WebhookURL += "?token=" + integration.Secret
+	}
+}
+
+// normalizeIncidentMapping подставляет статусы по умолчанию
+func normalizeIncidentMapping(mapping domain.IncidentMapping) domain.IncidentMapping {
+	if mapping.AcknowledgeStatus == "" {
+		mapping.AcknowledgeStatus = domain.TaskStatusInProgress
+	}
+	if len(mapping.ResolveStatuses) == 0 {
+		mapping.ResolveStatuses = []domain.TaskStatus{domain.TaskStatusCompleted}
+	}
+	return mapping
+}
+
+// incidentTaskPriority определяет приоритет задачи по срочности или приоритету инцидента.
+// Значения без сопоставления переводятся по умолчанию: P1 - critical, P2 и high - high, P3 - medium, P4, P5 и low - low
+func incidentTaskPriority(priorityMap map[string]domain.TaskPriority, severity string) domain.TaskPriority {
+	if priority, ok := priorityMap[severity]; ok {
+		return priority
+	}
+
+	switch strings.ToLower(severity) {
+	case "p1", "critical":
+		return domain.TaskPriorityCritical
+	case "p2", "high":
+		return domain.TaskPriorityHigh
+	case "p3", "medium":
+		return domain.TaskPriorityMedium
+	case "p4", "p5", "low":
+		return domain.TaskPriorityLow
+	}
+	return ""
+}
+
+// containsTaskStatus проверяет, входит ли статус в список
+func containsTaskStatus(statuses []domain.TaskStatus, status domain.TaskStatus) bool {
+	for _, s := range statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
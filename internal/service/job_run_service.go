@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/internal/repository"
+	"github.com/nurlyy/task_manager/pkg/logger"
+)
+
+// JobRunService предоставляет администраторам историю запусков задач планировщика
+type JobRunService struct {
+	jobRunRepo repository.JobRunRepository
+	logger     logger.Logger
+}
+
+// NewJobRunService создает новый экземпляр JobRunService
+func NewJobRunService(
+	jobRunRepo repository.JobRunRepository,
+	logger logger.Logger,
+) *JobRunService {
+	return &JobRunService{
+		jobRunRepo: jobRunRepo,
+		logger:     logger,
+	}
+}
+
+// ListLatest возвращает последний запуск каждой задачи планировщика
+func (s *JobRunService) ListLatest(ctx context.Context) ([]*domain.JobRun, error) {
+	return s.jobRunRepo.ListLatest(ctx)
+}
+
+// ListRuns возвращает историю запусков с фильтрацией, начиная с последних
+func (s *JobRunService) ListRuns(ctx context.Context, filterOptions domain.JobRunFilterOptions, page domain.PageRequest) (*domain.PagedResponse, error) {
+	filter := repository.JobRunFilter{
+		Job:     filterOptions.Job,
+		Status:  filterOptions.Status,
+		Overran: filterOptions.Overran,
+		Limit:   page.Limit(),
+		Offset:  page.Offset(),
+	}
+
+	runs, err := s.jobRunRepo.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	runs, hasMore := domain.PageItems(runs, page)
+	total := 0
+	if !page.SkipCount {
+		total, err = s.jobRunRepo.Count(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return domain.NewPagedResponse(runs, page, total, hasMore), nil
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/internal/repository/cache"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
+	"github.com/nurlyy/task_manager/pkg/logger"
+)
+
+// Стандартные ошибки
+var (
+	ErrMaintenanceForced = apperrors.New(apperrors.CodeMaintenanceForced, "maintenance mode is forced by instance configuration")
+)
+
+// maintenanceRefreshInterval задает, как долго экземпляр API использует прочитанное
+// из Redis состояние режима обслуживания, не обращаясь к Redis на каждый запрос
+const maintenanceRefreshInterval = 2 * time.Second
+
+// defaultMaintenanceMessage показывается пользователям, если администратор не указал сообщение
+const defaultMaintenanceMessage = "Сервис находится на техническом обслуживании. Изменения временно недоступны, просмотр данных работает."
+
+// MaintenanceService управляет режимом обслуживания. Состояние хранится в Redis,
+// поэтому переключение действует на все экземпляры API
+type MaintenanceService struct {
+	cacheRepo *cache.RedisRepository
+	logger    logger.Logger
+
+	mu        sync.Mutex
+	status    domain.MaintenanceStatus
+	checkedAt time.Time
+	forced    *domain.MaintenanceStatus // Режим, включенный для этого экземпляра при запуске
+}
+
+// NewMaintenanceService создает новый экземпляр MaintenanceService
+func NewMaintenanceService(cacheRepo *cache.RedisRepository, logger logger.Logger) *MaintenanceService {
+	return &MaintenanceService{
+		cacheRepo: cacheRepo,
+		logger:    logger,
+	}
+}
+
+// ForceReadOnly включает режим обслуживания только для этого экземпляра: по флагу конфигурации
+// или когда схема базы несовместима с кодом. Состояние в Redis не меняется, и выключить режим нельзя
+func (s *MaintenanceService) ForceReadOnly(message string) {
+	if message == "" {
+		message = defaultMaintenanceMessage
+	}
+
+	now := time.Now()
+	s.mu.Lock()
+	s.forced = &domain.MaintenanceStatus{
+		Enabled:   true,
+		Message:   message,
+		StartedAt: &now,
+		Forced:    true,
+	}
+	s.mu.Unlock()
+
+	s.logger.Warn("Maintenance mode forced for this instance", map[string]interface{}{
+		"message": message,
+	})
+}
+
+// Status возвращает текущее состояние режима обслуживания для проверки запросов.
+// Пока один запрос обновляет состояние из Redis, остальные используют прежнее;
+// если Redis недоступен, действует последнее известное состояние
+func (s *MaintenanceService) Status(ctx context.Context) domain.MaintenanceStatus {
+	s.mu.Lock()
+	if s.forced != nil {
+		status := *s.forced
+		s.mu.Unlock()
+		return status
+	}
+	if time.Since(s.checkedAt) < maintenanceRefreshInterval {
+		status := s.status
+		s.mu.Unlock()
+		return status
+	}
+	s.checkedAt = time.Now()
+	s.mu.Unlock()
+
+	status, err := s.cacheRepo.GetMaintenance(ctx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err != nil {
+		s.logger.Warn("Failed to read maintenance status", map[string]interface{}{
+			"enabled": s.status.Enabled,
+		}, map[string]interface{}{
+			"error": err,
+		})
+		return s.status
+	}
+	s.status = *status
+
+	return s.status
+}
+
+// Get возвращает состояние режима обслуживания для администратора
+func (s *MaintenanceService) Get(ctx context.Context) (*domain.MaintenanceStatus, error) {
+	s.mu.Lock()
+	forced := s.forced
+	s.mu.Unlock()
+	if forced != nil {
+		status := *forced
+		return &status, nil
+	}
+
+	return s.cacheRepo.GetMaintenance(ctx)
+}
+
+// Set включает или выключает режим обслуживания
+func (s *MaintenanceService) Set(ctx context.Context, req domain.MaintenanceRequest, userID string) (*domain.MaintenanceStatus, error) {
+	// Режим, включенный при запуске, не отменяется через Redis, иначе администратор
+	// увидел бы выключенный режим, а экземпляр продолжил бы отклонять изменения
+	s.mu.Lock()
+	forced := s.forced != nil
+	s.mu.Unlock()
+	if forced {
+		return nil, ErrMaintenanceForced
+	}
+
+	status := &domain.MaintenanceStatus{}
+	if req.Enabled {
+		now := time.Now()
+		status.Enabled = true
+		status.Message = req.Message
+		if status.Message == "" {
+			status.Message = defaultMaintenanceMessage
+		}
+		status.StartedAt = &now
+		status.StartedBy = &userID
+	}
+
+	if err := s.cacheRepo.SetMaintenance(ctx, status); err != nil {
+		s.logger.Error("Failed to save maintenance status", err, map[string]interface{}{
+			"enabled": req.Enabled,
+		})
+		return nil, err
+	}
+
+	// Обновляем состояние экземпляра сразу, остальные экземпляры увидят его
+	// не позже чем через maintenanceRefreshInterval
+	s.mu.Lock()
+	s.status = *status
+	s.checkedAt = time.Now()
+	s.mu.Unlock()
+
+	s.logger.Info("Maintenance mode changed", map[string]interface{}{
+		"enabled": status.Enabled,
+		"user_id": userID,
+	})
+
+	return status, nil
+}
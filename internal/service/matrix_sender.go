@@ -0,0 +1,246 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/pkg/circuit"
+	"github.com/nurlyy/task_manager/pkg/config"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
+	"github.com/nurlyy/task_manager/pkg/logger"
+)
+
+var (
+	ErrMatrixNotConfigured = apperrors.New(apperrors.CodeMatrixNotConfigured, "matrix homeserver is not configured")
+	ErrMatrixRoomInvalid   = apperrors.New(apperrors.CodeInvalidMatrixRoom, "invalid matrix room id or alias")
+)
+
+// matrixField описывает строку сообщения: подпись и ключ метаданных уведомления со значением
+type matrixField struct {
+	Label   string
+	MetaKey string
+}
+
+// matrixMessageTemplate описывает сообщение Matrix для типа уведомления
+type matrixMessageTemplate struct {
+	Icon   string
+	Fields []matrixField
+}
+
+// matrixMessageTemplates содержит шаблоны сообщений по типам уведомлений
+var matrixMessageTemplates = map[domain.NotificationType]matrixMessageTemplate{
+	domain.NotificationTypeTaskAssigned: {
+		Icon:   "📌",
+		Fields: []matrixField{{"Задача", "task_title"}, {"Приоритет", "priority"}, {"Срок выполнения", "due_date"}},
+	},
+	domain.NotificationTypeTaskUpdated: {
+		Icon:   "🔄",
+		Fields: []matrixField{{"Задача", "task_title"}, {"Статус", "status"}, {"Исполнитель", "assignee_name"}},
+	},
+	domain.NotificationTypeTaskCommented: {
+		Icon:   "💬",
+		Fields: []matrixField{{"Задача", "task_title"}, {"Автор комментария", "user_name"}, {"Комментарий", "comment_content"}},
+	},
+	domain.NotificationTypeTaskDueSoon: {
+		Icon:   "⏰",
+		Fields: []matrixField{{"Задача", "task_title"}, {"Срок выполнения", "due_date"}, {"Осталось часов", "hours_left"}},
+	},
+	domain.NotificationTypeTaskOverdue: {
+		Icon:   "🔥",
+		Fields: []matrixField{{"Задача", "task_title"}, {"Срок выполнения истек", "due_date"}},
+	},
+	domain.NotificationTypeProjectMemberAdded: {
+		Icon:   "👥",
+		Fields: []matrixField{{"Проект", "project_name"}, {"Роль", "role"}},
+	},
+	domain.NotificationTypeProjectUpdated: {
+		Icon:   "📁",
+		Fields: []matrixField{{"Проект", "project_name"}, {"Статус", "status"}},
+	},
+}
+
+// MatrixSender обеспечивает отправку уведомлений в комнаты Matrix через Client-Server API
+type MatrixSender struct {
+	client        *http.Client
+	breaker       *circuit.Breaker
+	homeserverURL string
+	accessToken   string
+	baseURL       string
+	logger        logger.Logger
+}
+
+// NewMatrixSender создает новый экземпляр MatrixSender
+func NewMatrixSender(config *config.MatrixConfig, baseURL string, logger logger.Logger) *MatrixSender {
+	return &MatrixSender{
+		client: &http.Client{
+			Timeout: config.Timeout,
+		},
+		breaker:       circuit.Default.Get("matrix"),
+		homeserverURL: strings.TrimRight(config.HomeserverURL, "/"),
+		accessToken:   config.AccessToken,
+		baseURL:       strings.TrimRight(baseURL, "/"),
+		logger:        logger,
+	}
+}
+
+// Enabled сообщает, настроено ли подключение к homeserver
+func (s *MatrixSender) Enabled() bool {
+	return s.homeserverURL != "" && s.accessToken != ""
+}
+
+// JoinRoom входит в комнату по ID или псевдониму и возвращает ID комнаты.
+// Для приватных комнат пользователь уведомлений должен быть заранее приглашен
+func (s *MatrixSender) JoinRoom(ctx context.Context, room string) (string, error) {
+	if !s.Enabled() {
+		return "", ErrMatrixNotConfigured
+	}
+	if !isMatrixRoomRef(room) {
+		return "", ErrMatrixRoomInvalid
+	}
+
+	var result struct {
+		RoomID string `json:"room_id"`
+	}
+	if err := s.do(ctx, http.MethodPost, "/join/"+url.PathEscape(room), map[string]interface{}{}, &result); err != nil {
+		return "", fmt.Errorf("failed to join Matrix room: %w", err)
+	}
+	if result.RoomID == "" {
+		return "", fmt.Errorf("failed to join Matrix room: empty room_id in response")
+	}
+
+	return result.RoomID, nil
+}
+
+// SendNotification публикует уведомление в комнату как m.notice, чтобы на него не реагировали другие боты
+func (s *MatrixSender) SendNotification(ctx context.Context, roomID string, notification *domain.Notification) error {
+	if !s.Enabled() {
+		return ErrMatrixNotConfigured
+	}
+
+	plain, formatted := s.buildMessage(notification)
+	content := map[string]interface{}{
+		"msgtype":        "m.notice",
+		"body":           plain,
+		"format":         "org.matrix.custom.html",
+		"formatted_body": formatted,
+	}
+
+	// ID транзакции делает повторную отправку того же запроса идемпотентной
+	path := "/rooms/" + url.PathEscape(roomID) + "/send/m.room.message/" + uuid.New().String()
+	if err := s.do(ctx, http.MethodPut, path, content, nil); err != nil {
+		return fmt.Errorf("failed to send Matrix notification: %w", err)
+	}
+
+	s.logger.Debug("Matrix notification sent", map[string]interface{}{
+		"room_id":           roomID,
+		"notification_type": notification.Type,
+		"entity_id":         notification.EntityID,
+	})
+	return nil
+}
+
+// do выполняет запрос к Client-Server API homeserver
+func (s *MatrixSender) do(ctx context.Context, method, path string, payload interface{}, result interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, s.homeserverURL+"/_matrix/client/v3"+path, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+s.accessToken)
+
+	return s.breaker.Do(func() error {
+		resp, err := s.client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+			return deliveryStatusError(resp.StatusCode, fmt.Errorf("homeserver returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody))))
+		}
+
+		if result != nil {
+			if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+				return fmt.Errorf("failed to decode response: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// buildMessage формирует текстовую и HTML-версии сообщения по шаблону типа уведомления
+func (s *MatrixSender) buildMessage(notification *domain.Notification) (string, string) {
+	template := matrixMessageTemplates[notification.Type]
+
+	title := notification.Title
+	if template.Icon != "" {
+		title = template.Icon + " " + title
+	}
+
+	var plain, formatted strings.Builder
+	plain.WriteString(title)
+	formatted.WriteString("<p><strong>" + html.EscapeString(title) + "</strong></p>")
+
+	if notification.Content != "" {
+		plain.WriteString("\n" + notification.Content)
+		formatted.WriteString("<p>" + html.EscapeString(notification.Content) + "</p>")
+	}
+
+	var items []string
+	for _, field := range template.Fields {
+		if value := notification.MetaData[field.MetaKey]; value != "" {
+			plain.WriteString("\n" + field.Label + ": " + value)
+			items = append(items, "<li><strong>"+html.EscapeString(field.Label)+":</strong> "+html.EscapeString(value)+"</li>")
+		}
+	}
+	if len(items) > 0 {
+		formatted.WriteString("<ul>" + strings.Join(items, "") + "</ul>")
+	}
+
+	if link := s.entityURL(notification); link != "" {
+		plain.WriteString("\n" + link)
+		formatted.WriteString(`<p><a href="` + html.EscapeString(link) + `">Открыть</a></p>`)
+	}
+
+	return plain.String(), formatted.String()
+}
+
+// entityURL возвращает ссылку на задачу или проект уведомления
+func (s *MatrixSender) entityURL(notification *domain.Notification) string {
+	if s.baseURL == "" || notification.EntityID == "" {
+		return ""
+	}
+
+	switch notification.EntityType {
+	case "task":
+		return s.baseURL + "/tasks/" + url.PathEscape(notification.EntityID)
+	case "project":
+		return s.baseURL + "/projects/" + url.PathEscape(notification.EntityID)
+	}
+	return ""
+}
+
+// isMatrixRoomRef проверяет формат ID комнаты (!opaque:server) или псевдонима (#alias:server)
+func isMatrixRoomRef(room string) bool {
+	if len(room) < 4 || (room[0] != '!' && room[0] != '#') {
+		return false
+	}
+	sep := strings.IndexByte(room, ':')
+	return sep > 1 && sep < len(room)-1
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/internal/repository"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
+	"github.com/nurlyy/task_manager/pkg/logger"
+)
+
+var (
+	ErrMatrixRoomNotFound = apperrors.New(apperrors.CodeMatrixRoomNotFound, "matrix room not found")
+	ErrMatrixUnreachable  = apperrors.New(apperrors.CodeMatrixUnreachable, "matrix room is unreachable")
+)
+
+// MatrixService представляет бизнес-логику привязки комнат Matrix к проектам
+type MatrixService struct {
+	matrixRepo   repository.MatrixRepository
+	matrixSender *MatrixSender
+	logger       logger.Logger
+}
+
+// NewMatrixService создает новый экземпляр MatrixService
+func NewMatrixService(
+	matrixRepo repository.MatrixRepository,
+	matrixSender *MatrixSender,
+	logger logger.Logger,
+) *MatrixService {
+	return &MatrixService{
+		matrixRepo:   matrixRepo,
+		matrixSender: matrixSender,
+		logger:       logger,
+	}
+}
+
+// GetProjectRoom возвращает комнату Matrix проекта
+func (s *MatrixService) GetProjectRoom(ctx context.Context, projectID string) (*domain.ProjectMatrixRoom, error) {
+	room, err := s.matrixRepo.GetProjectRoom(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if room == nil {
+		return nil, ErrMatrixRoomNotFound
+	}
+	return room, nil
+}
+
+// SaveProjectRoom создает или обновляет комнату Matrix проекта.
+// При смене комнаты пользователь уведомлений входит в нее и отправляет тестовое сообщение
+func (s *MatrixService) SaveProjectRoom(ctx context.Context, projectID string, req domain.ProjectMatrixRoomRequest, userID string) (*domain.ProjectMatrixRoom, error) {
+	if !s.matrixSender.Enabled() {
+		return nil, ErrMatrixNotConfigured
+	}
+
+	room, err := s.matrixRepo.GetProjectRoom(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if room == nil {
+		// Новой привязке нужна комната
+		if req.Room == "" {
+			return nil, &TaskValidationError{Violations: []domain.FieldViolation{{
+				Field:   "room",
+				Message: "Room ID or alias is required",
+			}}}
+		}
+		room = &domain.ProjectMatrixRoom{
+			ProjectID: projectID,
+			Enabled:   true,
+			CreatedBy: userID,
+			CreatedAt: now,
+		}
+	}
+
+	if req.Room != "" && req.Room != room.RoomID && req.Room != room.RoomAlias {
+		roomID, err := s.matrixSender.JoinRoom(ctx, req.Room)
+		if err != nil {
+			if errors.Is(err, ErrMatrixRoomInvalid) {
+				return nil, err
+			}
+			s.logger.Warn("Matrix room join failed", map[string]interface{}{
+				"project_id": projectID,
+				"error":      err.Error(),
+			})
+			return nil, fmt.Errorf("%w: %v", ErrMatrixUnreachable, err)
+		}
+
+		notification := &domain.Notification{
+			Title:     "Комната подключена",
+			Content:   "Уведомления проекта будут публиковаться в эту комнату",
+			CreatedAt: now,
+		}
+		if err := s.matrixSender.SendNotification(ctx, roomID, notification); err != nil {
+			s.logger.Warn("Matrix room check failed", map[string]interface{}{
+				"project_id": projectID,
+				"error":      err.Error(),
+			})
+			return nil, fmt.Errorf("%w: %v", ErrMatrixUnreachable, err)
+		}
+
+		room.RoomID = roomID
+		room.RoomAlias = ""
+		if strings.HasPrefix(req.Room, "#") {
+			room.RoomAlias = req.Room
+		}
+	}
+	if req.Enabled != nil {
+		room.Enabled = *req.Enabled
+	}
+	room.NotificationTypes = req.NotificationTypes
+	room.UpdatedAt = now
+
+	if err := s.matrixRepo.SaveProjectRoom(ctx, room); err != nil {
+		s.logger.Error("Failed to save project Matrix room", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return nil, err
+	}
+
+	return room, nil
+}
+
+// DeleteProjectRoom удаляет привязку комнаты Matrix к проекту
+func (s *MatrixService) DeleteProjectRoom(ctx context.Context, projectID string) error {
+	return s.matrixRepo.DeleteProjectRoom(ctx, projectID)
+}
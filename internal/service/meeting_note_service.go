@@ -0,0 +1,482 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/internal/repository"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
+	"github.com/nurlyy/task_manager/pkg/logger"
+)
+
+var (
+	ErrMeetingNoteNotFound = apperrors.New(apperrors.CodeMeetingNoteNotFound, "meeting note not found")
+)
+
+// meetingActionItemsMaxBatch ограничивает число задач, создаваемых из заметки за один запрос
+const meetingActionItemsMaxBatch = 100
+
+// meetingChecklistItemRe находит открытые пункты чек-листа Markdown: "- [ ] текст"
+var meetingChecklistItemRe = regexp.MustCompile(`^\s*[-*+]\s+\[ \]\s+(.+?)\s*$`)
+
+// meetingDueDateLayouts перечисляет поддерживаемые форматы срока в поручениях
+var meetingDueDateLayouts = []string{"2006-01-02", "02.01.2006"}
+
+// MeetingNoteService представляет бизнес-логику заметок встреч
+type MeetingNoteService struct {
+	noteRepo    repository.MeetingNoteRepository
+	taskRepo    repository.TaskRepository
+	projectRepo repository.ProjectRepository
+	userRepo    repository.UserRepository
+	projectSvc  *ProjectService
+	taskSvc     *TaskService
+	logger      logger.Logger
+}
+
+// NewMeetingNoteService создает новый экземпляр MeetingNoteService
+func NewMeetingNoteService(
+	noteRepo repository.MeetingNoteRepository,
+	taskRepo repository.TaskRepository,
+	projectRepo repository.ProjectRepository,
+	userRepo repository.UserRepository,
+	projectSvc *ProjectService,
+	taskSvc *TaskService,
+	logger logger.Logger,
+) *MeetingNoteService {
+	return &MeetingNoteService{
+		noteRepo:    noteRepo,
+		taskRepo:    taskRepo,
+		projectRepo: projectRepo,
+		userRepo:    userRepo,
+		projectSvc:  projectSvc,
+		taskSvc:     taskSvc,
+		logger:      logger,
+	}
+}
+
+// Create создает заметку встречи
+func (s *MeetingNoteService) Create(ctx context.Context, projectID string, req domain.MeetingNoteCreateRequest, userID string) (*domain.MeetingNote, error) {
+	if err := s.projectSvc.ensureProjectWritable(ctx, projectID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	heldAt := now
+	if req.HeldAt != nil {
+		heldAt = *req.HeldAt
+	}
+
+	note := &domain.MeetingNote{
+		ID:        uuid.New().String(),
+		ProjectID: projectID,
+		Title:     req.Title,
+		HeldAt:    heldAt,
+		Content:   req.Content,
+		CreatedBy: userID,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err := s.noteRepo.Create(ctx, note); err != nil {
+		s.logger.Error("Failed to create meeting note", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return nil, err
+	}
+
+	return note, nil
+}
+
+// List возвращает заметки встреч проекта
+func (s *MeetingNoteService) List(ctx context.Context, projectID string, page domain.PageRequest) (*domain.PagedResponse, error) {
+	notes, err := s.noteRepo.ListByProject(ctx, projectID, page.Limit(), page.Offset())
+	if err != nil {
+		return nil, err
+	}
+	notes, hasMore := domain.PageItems(notes, page)
+	total := 0
+	if !page.SkipCount {
+		total, err = s.noteRepo.CountByProject(ctx, projectID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return domain.NewPagedResponse(notes, page, total, hasMore), nil
+}
+
+// Get возвращает заметку встречи
+func (s *MeetingNoteService) Get(ctx context.Context, id string, userID string) (*domain.MeetingNote, error) {
+	return s.getAccessibleNote(ctx, id, userID)
+}
+
+// Update обновляет заметку встречи
+func (s *MeetingNoteService) Update(ctx context.Context, id string, req domain.MeetingNoteUpdateRequest, userID string) (*domain.MeetingNote, error) {
+	note, err := s.getAccessibleNote(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkCanEdit(ctx, note.ProjectID, userID); err != nil {
+		return nil, err
+	}
+
+	if req.Title != nil {
+		note.Title = *req.Title
+	}
+	if req.HeldAt != nil {
+		note.HeldAt = *req.HeldAt
+	}
+	if req.Content != nil {
+		note.Content = *req.Content
+	}
+	note.UpdatedAt = time.Now()
+
+	if err := s.noteRepo.Update(ctx, note); err != nil {
+		s.logger.Error("Failed to update meeting note", err, map[string]interface{}{
+			"id": id,
+		})
+		return nil, err
+	}
+
+	return note, nil
+}
+
+// Delete удаляет заметку встречи; созданные из нее задачи остаются в проекте.
+// Удалить заметку может ее автор или менеджер проекта
+func (s *MeetingNoteService) Delete(ctx context.Context, id string, userID string) error {
+	note, err := s.getAccessibleNote(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+
+	if note.CreatedBy != userID && !s.projectSvc.canManageProject(ctx, note.ProjectID, userID) {
+		return ErrInsufficientRights
+	}
+
+	if err := s.projectSvc.ensureProjectWritable(ctx, note.ProjectID); err != nil {
+		return err
+	}
+
+	return s.noteRepo.Delete(ctx, id)
+}
+
+// ListActionItems возвращает поручения заметки с разобранными исполнителями и сроками
+func (s *MeetingNoteService) ListActionItems(ctx context.Context, id string, userID string) ([]*domain.MeetingActionItem, error) {
+	note, err := s.getAccessibleNote(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.actionItems(ctx, note)
+}
+
+// ConvertActionItems создает задачи из поручений заметки. Каждая задача получает обратную ссылку на заметку.
+// Ошибка в одном поручении не мешает создать задачи из остальных
+func (s *MeetingNoteService) ConvertActionItems(ctx context.Context, id string, req domain.MeetingActionItemsConvertRequest, userID string) (*domain.MeetingActionItemsConvertResult, error) {
+	note, err := s.getAccessibleNote(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkCanEdit(ctx, note.ProjectID, userID); err != nil {
+		return nil, err
+	}
+
+	items, err := s.actionItems(ctx, note)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &domain.MeetingActionItemsConvertResult{Items: []*domain.MeetingActionItemResult{}}
+
+	// Выбираем поручения для переноса
+	var selected []*domain.MeetingActionItem
+	if len(req.Lines) == 0 {
+		for _, item := range items {
+			if item.TaskID == nil {
+				selected = append(selected, item)
+			}
+		}
+	} else {
+		itemsByLine := make(map[int]*domain.MeetingActionItem, len(items))
+		for _, item := range items {
+			itemsByLine[item.Line] = item
+		}
+		for _, line := range req.Lines {
+			item, ok := itemsByLine[line]
+			switch {
+			case !ok:
+				result.Items = append(result.Items, &domain.MeetingActionItemResult{
+					Line:  line,
+					Error: "line is not an open checklist item",
+				})
+			case item.TaskID != nil:
+				result.Items = append(result.Items, &domain.MeetingActionItemResult{
+					Line:  line,
+					Title: item.Title,
+					Error: "action item is already converted to a task",
+				})
+			default:
+				selected = append(selected, item)
+			}
+		}
+	}
+	if len(selected) > meetingActionItemsMaxBatch {
+		selected = selected[:meetingActionItemsMaxBatch]
+	}
+
+	for _, item := range selected {
+		itemResult := &domain.MeetingActionItemResult{Line: item.Line, Title: item.Title}
+		result.Items = append(result.Items, itemResult)
+
+		if len(item.Warnings) > 0 {
+			itemResult.Error = strings.Join(item.Warnings, "; ")
+			continue
+		}
+
+		task, err := s.taskSvc.Create(ctx, domain.TaskCreateRequest{
+			Title: item.Title,
+			Description: fmt.Sprintf("Поручение со встречи «%s» от %s:\n\n%s",
+				note.Title, note.HeldAt.Format("02.01.2006"), item.Text),
+			ProjectID:  note.ProjectID,
+			Priority:   req.Priority,
+			AssigneeID: item.AssigneeID,
+			DueDate:    item.DueDate,
+		}, userID)
+		if err != nil {
+			var validationErr *TaskValidationError
+			if errors.As(err, &validationErr) {
+				itemResult.Error = err.Error()
+				continue
+			}
+			s.logger.Error("Failed to create task from meeting note", err, map[string]interface{}{
+				"note_id": note.ID,
+				"line":    item.Line,
+			})
+			itemResult.Error = "failed to create task"
+			continue
+		}
+
+		if err := s.noteRepo.CreateTaskLink(ctx, &domain.MeetingNoteTask{
+			NoteID:    note.ID,
+			TaskID:    task.ID,
+			ItemText:  item.Text,
+			CreatedBy: userID,
+			CreatedAt: time.Now(),
+		}); err != nil {
+			s.logger.Warn("Failed to link task to meeting note", map[string]interface{}{
+				"note_id": note.ID,
+				"task_id": task.ID,
+				"error":   err.Error(),
+			})
+		}
+
+		itemResult.Task = task
+	}
+
+	for _, itemResult := range result.Items {
+		if itemResult.Task != nil {
+			result.Created++
+		} else {
+			result.Failed++
+		}
+	}
+
+	return result, nil
+}
+
+// GetTaskNote возвращает заметку встречи, из которой создана задача
+func (s *MeetingNoteService) GetTaskNote(ctx context.Context, taskID string, userID string) (*domain.MeetingNote, error) {
+	task, err := s.taskRepo.GetByID(ctx, taskID)
+	if err != nil || task == nil {
+		return nil, ErrTaskNotFound
+	}
+
+	if !s.taskSvc.hasAccessToTask(ctx, task.ProjectID, userID) {
+		return nil, ErrTaskAccessDenied
+	}
+
+	note, err := s.noteRepo.GetByTask(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if note == nil {
+		return nil, ErrMeetingNoteNotFound
+	}
+
+	return note, nil
+}
+
+// actionItems разбирает поручения заметки и отмечает уже перенесенные в задачи
+func (s *MeetingNoteService) actionItems(ctx context.Context, note *domain.MeetingNote) ([]*domain.MeetingActionItem, error) {
+	items := parseMeetingActionItems(note.Content, time.Now().Location())
+	if len(items) == 0 {
+		return items, nil
+	}
+
+	links, err := s.noteRepo.ListTaskLinks(ctx, note.ID)
+	if err != nil {
+		return nil, err
+	}
+	taskByText := make(map[string]string, len(links))
+	for _, link := range links {
+		taskByText[link.ItemText] = link.TaskID
+	}
+
+	var members []*domain.User
+	membersLoaded := false
+	for _, item := range items {
+		if taskID, ok := taskByText[item.Text]; ok {
+			item.TaskID = &taskID
+		}
+		if item.Assignee == "" {
+			continue
+		}
+		if !membersLoaded {
+			if members, err = s.projectMembers(ctx, note.ProjectID); err != nil {
+				return nil, err
+			}
+			membersLoaded = true
+		}
+		resolveMeetingAssignee(item, members)
+	}
+
+	return items, nil
+}
+
+// projectMembers возвращает активных пользователей - участников проекта
+func (s *MeetingNoteService) projectMembers(ctx context.Context, projectID string) ([]*domain.User, error) {
+	members, err := s.projectRepo.GetMembers(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]*domain.User, 0, len(members))
+	for _, member := range members {
+		user, err := s.userRepo.GetByID(ctx, member.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if user != nil && user.IsActive {
+			users = append(users, user)
+		}
+	}
+
+	return users, nil
+}
+
+// getAccessibleNote возвращает заметку, если у пользователя есть доступ к ее проекту
+func (s *MeetingNoteService) getAccessibleNote(ctx context.Context, id string, userID string) (*domain.MeetingNote, error) {
+	note, err := s.noteRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if note == nil || !s.projectSvc.hasAccessToProject(ctx, note.ProjectID, userID) {
+		return nil, ErrMeetingNoteNotFound
+	}
+	return note, nil
+}
+
+// checkCanEdit проверяет, что пользователь может вести заметки и создавать задачи проекта
+func (s *MeetingNoteService) checkCanEdit(ctx context.Context, projectID string, userID string) error {
+	if !s.projectSvc.hasAccessToProject(ctx, projectID, userID) {
+		return ErrProjectNotFound
+	}
+	if !s.taskSvc.canManageTask(ctx, projectID, userID) {
+		return ErrInsufficientRights
+	}
+	return s.projectSvc.ensureProjectWritable(ctx, projectID)
+}
+
+// parseMeetingActionItems находит открытые пункты чек-листа и выделяет из них
+// упоминание исполнителя (@email или @имя) и срок (due:2026-01-31 или due:31.01.2026)
+func parseMeetingActionItems(content string, loc *time.Location) []*domain.MeetingActionItem {
+	items := []*domain.MeetingActionItem{}
+
+	for i, line := range strings.Split(content, "\n") {
+		match := meetingChecklistItemRe.FindStringSubmatch(strings.TrimRight(line, "\r"))
+		if match == nil {
+			continue
+		}
+
+		item := &domain.MeetingActionItem{Line: i + 1, Text: match[1]}
+		var titleWords []string
+		for _, word := range strings.Fields(match[1]) {
+			switch {
+			case strings.HasPrefix(word, "@") && len(word) > 1:
+				if item.Assignee != "" {
+					item.Warnings = append(item.Warnings, "only one assignee can be mentioned")
+					continue
+				}
+				item.Assignee = strings.TrimRight(word[1:], ",.;:")
+			case strings.HasPrefix(strings.ToLower(word), "due:"):
+				value := strings.TrimRight(word[len("due:"):], ",.;")
+				dueDate, ok := parseMeetingDueDate(value, loc)
+				if !ok {
+					item.Warnings = append(item.Warnings, fmt.Sprintf("invalid due date %q", value))
+					continue
+				}
+				item.DueDate = &dueDate
+			default:
+				titleWords = append(titleWords, word)
+			}
+		}
+
+		item.Title = strings.Join(titleWords, " ")
+		if length := utf8.RuneCountInString(item.Title); length < 3 {
+			item.Warnings = append(item.Warnings, "task title must be at least 3 characters")
+		} else if length > 200 {
+			item.Warnings = append(item.Warnings, "task title must be at most 200 characters")
+		}
+
+		items = append(items, item)
+	}
+
+	return items
+}
+
+// parseMeetingDueDate разбирает срок поручения; срок приходится на конец указанного дня
+func parseMeetingDueDate(value string, loc *time.Location) (time.Time, bool) {
+	for _, layout := range meetingDueDateLayouts {
+		if date, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return date.Add(24*time.Hour - time.Second), true
+		}
+	}
+	return time.Time{}, false
+}
+
+// resolveMeetingAssignee находит упомянутого исполнителя среди участников проекта
+// по полному email или по части email до символа @
+func resolveMeetingAssignee(item *domain.MeetingActionItem, members []*domain.User) {
+	mention := strings.ToLower(item.Assignee)
+
+	var found []*domain.User
+	for _, member := range members {
+		email := strings.ToLower(member.Email)
+		localPart := email
+		if at := strings.Index(email, "@"); at >= 0 {
+			localPart = email[:at]
+		}
+		if mention == email || mention == localPart {
+			found = append(found, member)
+		}
+	}
+
+	switch len(found) {
+	case 0:
+		item.Warnings = append(item.Warnings, fmt.Sprintf("assignee @%s is not a member of the project", item.Assignee))
+	case 1:
+		item.AssigneeID = &found[0].ID
+	default:
+		item.Warnings = append(item.Warnings, fmt.Sprintf("assignee @%s is ambiguous, use the full email", item.Assignee))
+	}
+}
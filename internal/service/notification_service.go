@@ -2,42 +2,39 @@ package service
 
 import (
 	"context"
-	"errors"
 	"time"
 
 	"github.com/google/uuid"
 
 	"github.com/nurlyy/task_manager/internal/domain"
 	"github.com/nurlyy/task_manager/internal/repository"
-	"github.com/nurlyy/task_manager/internal/repository/cache"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
 	"github.com/nurlyy/task_manager/pkg/logger"
 )
 
 // Стандартные ошибки
 var (
-	ErrNotificationNotFound = errors.New("notification not found")
+	ErrNotificationNotFound      = apperrors.New(apperrors.CodeNotificationNotFound, "notification not found")
+	ErrNotificationSettingsOwner = apperrors.New(apperrors.CodeInvalidUserID, "settings must belong to the specified user")
 )
 
 // NotificationService представляет бизнес-логику для работы с уведомлениями
 type NotificationService struct {
-	repo      repository.NotificationRepository
-	userRepo  repository.UserRepository
-	cacheRepo *cache.RedisRepository
-	logger    logger.Logger
+	repo     repository.NotificationRepository
+	userRepo repository.UserRepository
+	logger   logger.Logger
 }
 
 // NewNotificationService создает новый экземпляр NotificationService
 func NewNotificationService(
 	repo repository.NotificationRepository,
 	userRepo repository.UserRepository,
-	cacheRepo *cache.RedisRepository,
 	logger logger.Logger,
 ) *NotificationService {
 	return &NotificationService{
-		repo:      repo,
-		userRepo:  userRepo,
-		cacheRepo: cacheRepo,
-		logger:    logger,
+		repo:     repo,
+		userRepo: userRepo,
+		logger:   logger,
 	}
 }
 
@@ -71,16 +68,6 @@ func (s *NotificationService) Create(ctx context.Context, req domain.Notificatio
 		return nil, err
 	}
 
-	// Удаляем счетчик непрочитанных уведомлений из кэша
-	cacheKey := "unread_count:" + req.UserID
-	if err := s.cacheRepo.Delete(ctx, cacheKey); err != nil {
-		s.logger.Warn("Failed to delete unread count from cache", map[string]interface{}{
-			"user_id": req.UserID,
-		}, map[string]interface{}{
-			"error": err,
-		})
-	}
-
 	resp := notification.ToResponse()
 	return &resp, nil
 }
@@ -92,12 +79,8 @@ func (s *NotificationService) CreateBatch(ctx context.Context, requests []domain
 	}
 
 	notifications := make([]*domain.Notification, len(requests))
-	userIDs := make(map[string]struct{})
 
 	for i, req := range requests {
-		// Добавляем ID пользователя в множество для последующей очистки кэша
-		userIDs[req.UserID] = struct{}{}
-
 		notifications[i] = &domain.Notification{
 			ID:         uuid.New().String(),
 			UserID:     req.UserID,
@@ -118,18 +101,6 @@ func (s *NotificationService) CreateBatch(ctx context.Context, requests []domain
 		return err
 	}
 
-	// Удаляем счетчики непрочитанных уведомлений из кэша для всех пользователей
-	for userID := range userIDs {
-		cacheKey := "unread_count:" + userID
-		if err := s.cacheRepo.Delete(ctx, cacheKey); err != nil {
-			s.logger.Warn("Failed to delete unread count from cache", map[string]interface{}{
-				"user_id": userID,
-			}, map[string]interface{}{
-				"error": err,
-			})
-		}
-	}
-
 	return nil
 }
 
@@ -182,16 +153,6 @@ func (s *NotificationService) MarkAsRead(ctx context.Context, id string, userID
 		return err
 	}
 
-	// Удаляем счетчик непрочитанных уведомлений из кэша
-	cacheKey := "unread_count:" + userID
-	if err := s.cacheRepo.Delete(ctx, cacheKey); err != nil {
-		s.logger.Warn("Failed to delete unread count from cache", map[string]interface{}{
-			"user_id": userID,
-		}, map[string]interface{}{
-			"error": err,
-		})
-	}
-
 	return nil
 }
 
@@ -205,17 +166,28 @@ func (s *NotificationService) MarkAllAsRead(ctx context.Context, userID string)
 		return err
 	}
 
-	// Удаляем счетчик непрочитанных уведомлений из кэша
-	cacheKey := "unread_count:" + userID
-	if err := s.cacheRepo.Delete(ctx, cacheKey); err != nil {
-		s.logger.Warn("Failed to delete unread count from cache", map[string]interface{}{
+	return nil
+}
+
+// MarkAsReadByFilter отмечает прочитанными непрочитанные уведомления пользователя,
+// подходящие под условия запроса, и возвращает их количество
+func (s *NotificationService) MarkAsReadByFilter(ctx context.Context, userID string, req domain.NotificationSweepRequest) (int, error) {
+	filter := repository.NotificationFilter{
+		Types:      req.Types,
+		EntityID:   req.EntityID,
+		EntityType: req.EntityType,
+		EndDate:    req.Before,
+	}
+
+	count, err := s.repo.MarkAsReadByFilter(ctx, userID, filter)
+	if err != nil {
+		s.logger.Error("Failed to mark notifications as read by filter", err, map[string]interface{}{
 			"user_id": userID,
-		}, map[string]interface{}{
-			"error": err,
 		})
+		return 0, err
 	}
 
-	return nil
+	return count, nil
 }
 
 // Delete удаляет уведомление
@@ -242,23 +214,11 @@ func (s *NotificationService) Delete(ctx context.Context, id string, userID stri
 		return err
 	}
 
-	// Если уведомление было непрочитанным, удаляем счетчик из кэша
-	if !notification.IsRead() {
-		cacheKey := "unread_count:" + userID
-		if err := s.cacheRepo.Delete(ctx, cacheKey); err != nil {
-			s.logger.Warn("Failed to delete unread count from cache", map[string]interface{}{
-				"user_id": userID,
-			}, map[string]interface{}{
-				"error": err,
-			})
-		}
-	}
-
 	return nil
 }
 
 // GetUserNotifications возвращает уведомления пользователя с фильтрацией
-func (s *NotificationService) GetUserNotifications(ctx context.Context, userID string, filter domain.NotificationFilterOptions, page, pageSize int) (*domain.PagedResponse, error) {
+func (s *NotificationService) GetUserNotifications(ctx context.Context, userID string, filter domain.NotificationFilterOptions, page domain.PageRequest) (*domain.PagedResponse, error) {
 	// Преобразуем фильтр доменной модели в фильтр репозитория
 	repoFilter := repository.NotificationFilter{
 		Status:     filter.Status,
@@ -267,8 +227,8 @@ func (s *NotificationService) GetUserNotifications(ctx context.Context, userID s
 		EntityType: filter.EntityType,
 		StartDate:  filter.StartDate,
 		EndDate:    filter.EndDate,
-		Limit:      pageSize,
-		Offset:     (page - 1) * pageSize,
+		Limit:      page.Limit(),
+		Offset:     page.Offset(),
 	}
 
 	// Если указан тип, добавляем его в фильтр
@@ -291,13 +251,18 @@ func (s *NotificationService) GetUserNotifications(ctx context.Context, userID s
 		return nil, err
 	}
 
-	// Получаем общее количество уведомлений
-	total, err := s.repo.CountUserNotifications(ctx, userID, repoFilter)
-	if err != nil {
-		s.logger.Error("Failed to count user notifications", err, map[string]interface{}{
-			"user_id": userID,
-		})
-		return nil, err
+	notifications, hasMore := domain.PageItems(notifications, page)
+
+	// Получаем общее количество уведомлений, если клиент не отказался от подсчета
+	total := 0
+	if !page.SkipCount {
+		total, err = s.repo.CountUserNotifications(ctx, userID, repoFilter)
+		if err != nil {
+			s.logger.Error("Failed to count user notifications", err, map[string]interface{}{
+				"user_id": userID,
+			})
+			return nil, err
+		}
 	}
 
 	// Преобразуем к NotificationResponse
@@ -307,25 +272,12 @@ func (s *NotificationService) GetUserNotifications(ctx context.Context, userID s
 	}
 
 	// Формируем ответ с пагинацией
-	return &domain.PagedResponse{
-		Items:      notificationResponses,
-		TotalItems: total,
-		Page:       page,
-		PageSize:   pageSize,
-		TotalPages: (total + pageSize - 1) / pageSize,
-	}, nil
+	return domain.NewPagedResponse(notificationResponses, page, total, hasMore), nil
 }
 
 // GetUnreadCount возвращает количество непрочитанных уведомлений
 func (s *NotificationService) GetUnreadCount(ctx context.Context, userID string) (int, error) {
-	// Пытаемся получить из кэша
-	cacheKey := "unread_count:" + userID
-	var count int
-	if err := s.cacheRepo.Get(ctx, cacheKey, &count); err == nil {
-		return count, nil
-	}
-
-	// Получаем количество непрочитанных уведомлений
+	// Счетчик поддерживается репозиторием в Redis и читается из базы только при отсутствии
 	count, err := s.repo.GetUserUnreadCount(ctx, userID)
 	if err != nil {
 		s.logger.Error("Failed to get unread notification count", err, map[string]interface{}{
@@ -334,15 +286,6 @@ func (s *NotificationService) GetUnreadCount(ctx context.Context, userID string)
 		return 0, err
 	}
 
-	// Сохраняем в кэш
-	if err := s.cacheRepo.Set(ctx, cacheKey, count); err != nil {
-		s.logger.Warn("Failed to cache unread count", map[string]interface{}{
-			"user_id": userID,
-		}, map[string]interface{}{
-			"error": err,
-		})
-	}
-
 	return count, nil
 }
 
@@ -365,7 +308,7 @@ func (s *NotificationService) UpdateUserNotificationSettings(ctx context.Context
 	// Проверяем, что все настройки принадлежат указанному пользователю
 	for _, setting := range settings {
 		if setting.UserID != userID {
-			return errors.New("settings must belong to the specified user")
+			return ErrNotificationSettingsOwner
 		}
 	}
 
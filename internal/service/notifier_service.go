@@ -3,7 +3,10 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -11,6 +14,7 @@ import (
 	"github.com/nurlyy/task_manager/internal/domain"
 	"github.com/nurlyy/task_manager/internal/messaging"
 	"github.com/nurlyy/task_manager/internal/repository"
+	"github.com/nurlyy/task_manager/pkg/auth"
 	"github.com/nurlyy/task_manager/pkg/config"
 	"github.com/nurlyy/task_manager/pkg/logger"
 	"github.com/segmentio/kafka-go"
@@ -22,12 +26,60 @@ type NotifierService struct {
 	userRepo         repository.UserRepository
 	taskRepo         repository.TaskRepository
 	projectRepo      repository.ProjectRepository
+	emailSender      *EmailSender
+	unsubscribe      *UnsubscribeService
 	telegramSender   *TelegramSender
-	kafkaReader      *kafka.Reader
+	teamsRepo        repository.TeamsRepository
+	teamsSender      *TeamsSender
+	discordRepo      repository.DiscordRepository
+	discordSender    *DiscordSender
+	matrixRepo       repository.MatrixRepository
+	matrixSender     *MatrixSender
+	smsSender        *SMSSender
+	consumers        []*notificationConsumer // Потребители уведомлений по уровням приоритета
+	baseURL          string
 	logger           logger.Logger
 	config           *config.NotifierConfig
 }
 
+// notificationConsumer читает уведомления одного уровня приоритета из своего топика
+// отдельной группой потребителей со своим ограничением одновременной обработки
+type notificationConsumer struct {
+	priority string
+	reader   *kafka.Reader
+	inFlight chan struct{} // Занятые места для одновременной обработки уведомлений
+	metrics  *ConsumerMetrics
+}
+
+// newNotificationConsumer создает потребителя уведомлений уровня приоритета
+func newNotificationConsumer(kafkaConfig *config.KafkaConfig, topic, priority string, maxInFlight int) *notificationConsumer {
+	name := notificationConsumerName(priority)
+	return &notificationConsumer{
+		priority: priority,
+		reader: kafka.NewReader(kafka.ReaderConfig{
+			Brokers:         kafkaConfig.Brokers,
+			Topic:           topic,
+			GroupID:         kafkaConfig.Namespaced(strings.ReplaceAll(name, "_", "-") + "-group"),
+			MinBytes:        10e3, // 10KB
+			MaxBytes:        10e6, // 10MB
+			MaxWait:         time.Second,
+			CommitInterval:  time.Second,
+			ReadLagInterval: -1,
+		}),
+		inFlight: make(chan struct{}, maxInFlight),
+		metrics:  newConsumerMetrics(name, maxInFlight),
+	}
+}
+
+// notificationConsumerName возвращает имя потребителя уровня приоритета для группы и метрик.
+// Обычные уведомления сохраняют прежнее имя, чтобы группа продолжила чтение с сохраненных смещений
+func notificationConsumerName(priority string) string {
+	if priority == messaging.NotificationPriorityNormal {
+		return "notifier"
+	}
+	return "notifier_" + priority
+}
+
 // NewNotifierService создает новый экземпляр сервиса уведомлений
 func NewNotifierService(
 	notificationRepo repository.NotificationRepository,
@@ -35,43 +87,94 @@ func NewNotifierService(
 	taskRepo repository.TaskRepository,
 	projectRepo repository.ProjectRepository,
 	telegramRepo repository.TelegramRepository,
-	kafkaBrokers []string,
+	teamsRepo repository.TeamsRepository,
+	discordRepo repository.DiscordRepository,
+	matrixRepo repository.MatrixRepository,
+	smsRepo repository.SMSRepository,
+	kafkaConfig *config.KafkaConfig,
+	baseURL string,
 	config *config.NotifierConfig,
 	logger logger.Logger,
 ) *NotifierService {
-	// Создаем Kafka reader для чтения уведомлений
-	kafkaReader := kafka.NewReader(kafka.ReaderConfig{
-		Brokers:         kafkaBrokers,
-		Topic:           "notifications",
-		GroupID:         "notifier-group",
-		MinBytes:        10e3, // 10KB
-		MaxBytes:        10e6, // 10MB
-		MaxWait:         time.Second,
-		CommitInterval:  time.Second,
-		ReadLagInterval: -1,
-	})
+	// Создаем Kafka reader для каждого уровня приоритета уведомлений
+	priorityTopics := map[string]string{
+		messaging.NotificationPriorityCritical: kafkaConfig.Topics.NotificationsCritical,
+		messaging.NotificationPriorityNormal:   kafkaConfig.Topics.Notifications,
+		messaging.NotificationPriorityDigest:   kafkaConfig.Topics.NotificationsDigest,
+	}
+	priorityMaxInFlight := map[string]int{
+		messaging.NotificationPriorityCritical: config.CriticalMaxInFlight,
+		messaging.NotificationPriorityNormal:   config.MaxInFlight,
+		messaging.NotificationPriorityDigest:   config.DigestMaxInFlight,
+	}
+	consumers := make([]*notificationConsumer, 0, len(messaging.NotificationPriorities))
+	for _, priority := range messaging.NotificationPriorities {
+		consumers = append(consumers, newNotificationConsumer(kafkaConfig, priorityTopics[priority], priority, priorityMaxInFlight[priority]))
+	}
+
+	// Инициализируем отправителя писем и подписанные ссылки для отписки от них
+	emailSender := NewEmailSender(&config.SMTP, logger)
+	unsubscribe := NewUnsubscribeService(
+		notificationRepo,
+		auth.NewUnsubscribeManager(config.Unsubscribe.Secret, config.Unsubscribe.ExpiresIn),
+		baseURL,
+		logger,
+	)
 
 	// Инициализируем отправителя уведомлений Telegram
-	telegramSender := NewTelegramSender(config.Telegram.Token, telegramRepo, logger)
+	telegramSender := NewTelegramSender(&config.Telegram, baseURL, telegramRepo, logger)
+
+	// Инициализируем отправителя карточек Microsoft Teams
+	teamsSender := NewTeamsSender(&config.Teams, baseURL, logger)
+
+	// Инициализируем отправителя сообщений Discord
+	discordSender := NewDiscordSender(&config.Discord, baseURL, logger)
+
+	// Инициализируем отправителя сообщений Matrix
+	matrixSender := NewMatrixSender(&config.Matrix, baseURL, logger)
+
+	// Инициализируем отправителя SMS о критических событиях
+	smsSender := NewSMSSender(&config.SMS, smsRepo, logger)
 
 	return &NotifierService{
 		notificationRepo: notificationRepo,
 		userRepo:         userRepo,
 		taskRepo:         taskRepo,
 		projectRepo:      projectRepo,
+		emailSender:      emailSender,
+		unsubscribe:      unsubscribe,
 		telegramSender:   telegramSender,
-		kafkaReader:      kafkaReader,
+		teamsRepo:        teamsRepo,
+		teamsSender:      teamsSender,
+		discordRepo:      discordRepo,
+		discordSender:    discordSender,
+		matrixRepo:       matrixRepo,
+		matrixSender:     matrixSender,
+		smsSender:        smsSender,
+		consumers:        consumers,
+		baseURL:          strings.TrimRight(baseURL, "/"),
 		logger:           logger,
 		config:           config,
 	}
 }
 
+// Metrics возвращает метрики чтения уведомлений из Kafka по уровням приоритета
+func (s *NotifierService) Metrics() []*ConsumerMetrics {
+	metrics := make([]*ConsumerMetrics, 0, len(s.consumers))
+	for _, consumer := range s.consumers {
+		metrics = append(metrics, consumer.metrics)
+	}
+	return metrics
+}
+
 // Start запускает сервис уведомлений
 func (s *NotifierService) Start(ctx context.Context) error {
 	s.logger.Info("Starting notifier service")
 
-	// Запускаем чтение сообщений из Kafka
-	go s.consumeNotifications(ctx)
+	// Запускаем чтение сообщений из Kafka: уровни приоритета читаются независимо
+	for _, consumer := range s.consumers {
+		go s.consumeNotifications(ctx, consumer)
+	}
 
 	return nil
 }
@@ -79,43 +182,119 @@ func (s *NotifierService) Start(ctx context.Context) error {
 // Stop останавливает сервис уведомлений
 func (s *NotifierService) Stop() error {
 	s.logger.Info("Stopping notifier service")
-	return s.kafkaReader.Close()
+
+	var errs []error
+	for _, consumer := range s.consumers {
+		if err := consumer.reader.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to close %s notification reader: %w", consumer.priority, err))
+		}
+	}
+	return errors.Join(errs...)
 }
 
-// consumeNotifications читает и обрабатывает уведомления из Kafka
-func (s *NotifierService) consumeNotifications(ctx context.Context) {
+// consumeNotifications читает и обрабатывает уведомления уровня приоритета из Kafka.
+// Одновременно обрабатывается не больше уведомлений, чем задано для уровня, а пока канал
+// доставки ограничивает частоту отправок, чтение приостанавливается, чтобы необработанные
+// уведомления не копились в памяти
+func (s *NotifierService) consumeNotifications(ctx context.Context, c *notificationConsumer) {
 	for {
 		// Проверяем, не завершен ли контекст
 		select {
 		case <-ctx.Done():
-			s.logger.Info("Notification consumer stopped due to context cancellation")
+			s.logger.Info("Notification consumer stopped due to context cancellation", map[string]interface{}{
+				"priority": c.priority,
+			})
 			return
 		default:
 			// Продолжаем работу
 		}
 
+		if err := s.waitForChannels(ctx, c); err != nil {
+			continue
+		}
+
+		// Занимаем место для обработки до чтения, чтобы не читать сообщения, которые некому обработать
+		select {
+		case c.inFlight <- struct{}{}:
+		case <-ctx.Done():
+			continue
+		}
+
 		// Читаем сообщение из Kafka
-		message, err := s.kafkaReader.ReadMessage(ctx)
+		message, err := c.reader.ReadMessage(ctx)
 		if err != nil {
-			s.logger.Error("Failed to read message from Kafka", err)
+			<-c.inFlight
+			if ctx.Err() == nil {
+				s.logger.Error("Failed to read message from Kafka", err, map[string]interface{}{
+					"priority": c.priority,
+				})
+			}
 			continue
 		}
 
-		// Обрабатываем сообщение
-		// s.logger.Info("Received notification event",
-		// 	"offset", message.Offset,
-		// 	"partition", message.Partition,
-		// 	"key", string(message.Key))
+		lag := c.metrics.received(message)
+		s.logger.Debug("Received notification event", map[string]interface{}{
+			"priority":  c.priority,
+			"topic":     message.Topic,
+			"partition": message.Partition,
+			"offset":    message.Offset,
+			"lag":       lag,
+		})
 
 		// Обрабатываем уведомление асинхронно
 		go func(m kafka.Message) {
-			if err := s.processNotificationEvent(ctx, m.Value); err != nil {
-				s.logger.Error("Failed to process notification event", err)
+			defer func() { <-c.inFlight }()
+
+			err := s.processNotificationEvent(ctx, m.Value)
+			if err != nil {
+				s.logger.Error("Failed to process notification event", err, map[string]interface{}{
+					"priority": c.priority,
+				})
 			}
+			c.metrics.done(err)
 		}(message)
 	}
 }
 
+// waitForChannels приостанавливает чтение уведомлений, пока канал доставки ограничивает частоту отправок
+func (s *NotifierService) waitForChannels(ctx context.Context, c *notificationConsumer) error {
+	channel, delay := s.throttledChannel()
+	if delay <= 0 {
+		return nil
+	}
+
+	c.metrics.pause(channel)
+	s.logger.Warn("Pausing notification consumption: delivery channel is rate limited", map[string]interface{}{
+		"priority":  c.priority,
+		"channel":   channel,
+		"resume_in": delay.String(),
+	})
+
+	for delay > 0 {
+		if err := sleepContext(ctx, delay); err != nil {
+			c.metrics.resume()
+			return err
+		}
+		_, delay = s.throttledChannel()
+	}
+
+	c.metrics.resume()
+	s.logger.Info("Resuming notification consumption", map[string]interface{}{
+		"priority": c.priority,
+		"channel":  channel,
+	})
+	return nil
+}
+
+// throttledChannel возвращает канал доставки, отправки в который приостановлены
+// из-за ограничения частоты, и оставшееся время приостановки
+func (s *NotifierService) throttledChannel() (string, time.Duration) {
+	if delay := s.telegramSender.ThrottledFor(); delay > 0 {
+		return "telegram", delay
+	}
+	return "", 0
+}
+
 // processNotificationEvent обрабатывает событие уведомления
 func (s *NotifierService) processNotificationEvent(ctx context.Context, data []byte) error {
 	// Разбираем событие
@@ -124,25 +303,56 @@ func (s *NotifierService) processNotificationEvent(ctx context.Context, data []b
 		return fmt.Errorf("failed to unmarshal notification event: %w", err)
 	}
 
+	// Публикуем уведомление в каналы Teams, Discord и комнату Matrix проекта
+	if projectID := s.eventProjectID(ctx, &event); projectID != "" {
+		s.sendProjectTeamsNotification(ctx, projectID, &event)
+		s.sendProjectDiscordNotification(ctx, projectID, &event)
+		s.sendProjectMatrixNotification(ctx, projectID, &event)
+	}
+
+	// Критические уведомления дополнительно отправляются по SMS
+	smsCritical := s.smsSender.Enabled() && s.isSMSCritical(ctx, &event)
+
+	recipients, err := s.resolveRecipients(ctx, &event)
+	if err != nil {
+		return err
+	}
+
+	// Сохраняем уведомления всех получателей пакетами
+	if event.Persist {
+		s.persistNotifications(ctx, &event, recipients)
+	}
+
+	// Сообщения Telegram собираются по всем получателям и отправляются одним пакетом
+	var (
+		telegramMessages []TelegramMessage
+		telegramUserIDs  []string
+	)
+
+	// Настройки уведомлений всех получателей загружаются одним запросом. Уведомления уже
+	// сохранены, поэтому при ошибке событие не возвращается на повторную обработку
+	settingsByUser, err := s.notificationRepo.GetSettingsForUsers(ctx, recipients)
+	if err != nil {
+		s.logger.Error("Failed to get notification settings for recipients", err, map[string]interface{}{
+			"recipients": len(recipients),
+		})
+		return nil
+	}
+
 	// Обрабатываем уведомление для каждого пользователя
-	for _, userID := range event.UserIDs {
-		// Получаем настройки уведомлений пользователя
-		settings, err := s.notificationRepo.GetUserNotificationSettings(ctx, userID)
-		if err != nil {
-			s.logger.Error("Failed to get user notification settings", err, map[string]interface{}{
-				"user_id": userID,
-			})
-			continue
-		}
+	for _, userID := range recipients {
+		settings := settingsByUser[userID]
 
 		// Определяем тип уведомления и каналы отправки
 		notificationType := domain.NotificationType(event.Type)
-		var telegramEnabled bool
+		var emailEnabled, telegramEnabled, teamsEnabled bool
 
 		// Находим настройку для данного типа уведомлений
 		for _, setting := range settings {
 			if setting.NotificationType == notificationType {
+				emailEnabled = setting.EmailEnabled
 				telegramEnabled = setting.TelegramEnabled
+				teamsEnabled = setting.TeamsEnabled
 				break
 			}
 		}
@@ -157,12 +367,16 @@ func (s *NotifierService) processNotificationEvent(ctx context.Context, data []b
 		}
 
 		// Формируем уведомление
+		content := event.Content
+		if userContent, ok := event.Contents[userID]; ok {
+			content = userContent
+		}
 		notification := &domain.Notification{
 			ID:         uuid.New().String(),
 			UserID:     userID,
 			Type:       notificationType,
 			Title:      event.Title,
-			Content:    event.Content,
+			Content:    content,
 			Status:     domain.NotificationStatusUnread,
 			EntityID:   event.EntityID,
 			EntityType: event.EntityType,
@@ -170,12 +384,41 @@ func (s *NotifierService) processNotificationEvent(ctx context.Context, data []b
 			CreatedAt:  event.CreatedAt,
 		}
 
-		// Отправляем Telegram, если включено
+		// Отправляем письмо, если включено. Неактивные пользователи писем не получают
+		if emailEnabled && user.IsActive {
+			s.sendUserEmailNotification(user, notification)
+		}
+
+		// Готовим сообщение Telegram, если включено
 		if telegramEnabled {
-			if err := s.telegramSender.SendNotification(ctx, user, notification); err != nil {
+			message, err := s.telegramSender.NotificationMessage(ctx, user, notification)
+			if err != nil {
 				s.logger.Error("Failed to send Telegram notification", err, map[string]interface{}{
 					"user_id": userID,
 				})
+			} else {
+				telegramMessages = append(telegramMessages, message)
+				telegramUserIDs = append(telegramUserIDs, userID)
+			}
+		}
+
+		// Отправляем в личный чат Teams, если включено
+		if teamsEnabled {
+			s.sendUserTeamsNotification(ctx, userID, notification)
+		}
+
+		// Отправляем SMS о критическом событии
+		if smsCritical {
+			if err := s.smsSender.SendNotification(ctx, userID, notification); err != nil {
+				if errors.Is(err, ErrSMSQuotaExceeded) {
+					s.logger.Warn("SMS quota exceeded", map[string]interface{}{
+						"user_id": userID,
+					})
+				} else {
+					s.logger.Error("Failed to send SMS notification", err, map[string]interface{}{
+						"user_id": userID,
+					})
+				}
 			}
 		}
 
@@ -215,5 +458,251 @@ func (s *NotifierService) processNotificationEvent(ctx context.Context, data []b
 		}
 	}
 
+	// Отправляем сообщения Telegram с соблюдением ограничений частоты
+	for i, err := range s.telegramSender.SendBatch(ctx, telegramMessages) {
+		if err != nil {
+			s.logger.Error("Failed to send Telegram notification", err, map[string]interface{}{
+				"user_id": telegramUserIDs[i],
+			})
+		}
+	}
+
 	return nil
 }
+
+// notificationBatchSize ограничивает число уведомлений, сохраняемых одним пакетом
+const notificationBatchSize = 500
+
+// resolveRecipients раскрывает список получателей события: явно указанные пользователи
+// и участники проекта без повторов и без исключенных пользователей
+func (s *NotifierService) resolveRecipients(ctx context.Context, event *messaging.NotificationEvent) ([]string, error) {
+	userIDs := event.UserIDs
+	if event.RecipientProjectID != "" {
+		members, err := s.projectRepo.GetMembers(ctx, event.RecipientProjectID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get notification recipients: %w", err)
+		}
+		userIDs = make([]string, 0, len(event.UserIDs)+len(members))
+		userIDs = append(userIDs, event.UserIDs...)
+		for _, member := range members {
+			userIDs = append(userIDs, member.UserID)
+		}
+	}
+
+	skip := make(map[string]bool, len(userIDs)+len(event.ExcludeUserIDs))
+	for _, userID := range event.ExcludeUserIDs {
+		skip[userID] = true
+	}
+
+	recipients := make([]string, 0, len(userIDs))
+	for _, userID := range userIDs {
+		if skip[userID] {
+			continue
+		}
+		skip[userID] = true
+		recipients = append(recipients, userID)
+	}
+
+	return recipients, nil
+}
+
+// persistNotifications сохраняет уведомления получателей в базе данных пакетами
+func (s *NotifierService) persistNotifications(ctx context.Context, event *messaging.NotificationEvent, recipients []string) {
+	for start := 0; start < len(recipients); start += notificationBatchSize {
+		end := start + notificationBatchSize
+		if end > len(recipients) {
+			end = len(recipients)
+		}
+
+		notifications := make([]*domain.Notification, 0, end-start)
+		for _, userID := range recipients[start:end] {
+			notifications = append(notifications, &domain.Notification{
+				ID:         uuid.New().String(),
+				UserID:     userID,
+				Type:       domain.NotificationType(event.Type),
+				Title:      event.Title,
+				Content:    event.Content,
+				Status:     domain.NotificationStatusUnread,
+				EntityID:   event.EntityID,
+				EntityType: event.EntityType,
+				MetaData:   event.MetaData,
+				CreatedAt:  event.CreatedAt,
+			})
+		}
+
+		if err := s.notificationRepo.CreateBatch(ctx, notifications); err != nil {
+			s.logger.Error("Failed to save notification batch", err, map[string]interface{}{
+				"entity_id":  event.EntityID,
+				"recipients": len(notifications),
+			})
+		}
+	}
+}
+
+// sendUserEmailNotification отправляет письмо с уведомлением со ссылкой для отписки от его типа
+func (s *NotifierService) sendUserEmailNotification(user *domain.User, notification *domain.Notification) {
+	// Ошибка подписи ссылки уже записана в журнал; без ссылки письмо все равно отправляется,
+	// тип уведомлений можно отключить в настройках
+	unsubscribeLink, _ := s.unsubscribe.BuildLink(user.ID, notification.Type)
+
+	var link string
+	if notification.EntityType == "task" && notification.EntityID != "" && s.baseURL != "" {
+		link = s.baseURL + "/tasks/" + url.PathEscape(notification.EntityID)
+	}
+
+	html, text, err := renderNotificationEmail(newEmailTemplateData(notification, link, unsubscribeLink))
+	if err != nil {
+		s.logger.Error("Failed to render notification email", err, map[string]interface{}{
+			"user_id":           user.ID,
+			"notification_type": notification.Type,
+		})
+		return
+	}
+
+	message := &EmailMessage{
+		To:      user.Email,
+		Subject: truncateRunes(notification.Title, 150),
+		HTML:    html,
+		Text:    text,
+	}
+	if unsubscribeLink != "" {
+		message.Headers = unsubscribeHeaders(unsubscribeLink)
+	}
+
+	if err := s.emailSender.SendMessage(message); err != nil {
+		s.logger.Error("Failed to send email notification", err, map[string]interface{}{
+			"user_id":           user.ID,
+			"notification_type": notification.Type,
+		})
+	}
+}
+
+// sendUserTeamsNotification отправляет уведомление в личный чат Teams пользователя
+func (s *NotifierService) sendUserTeamsNotification(ctx context.Context, userID string, notification *domain.Notification) {
+	link, err := s.teamsRepo.GetUserLink(ctx, userID)
+	if err != nil {
+		s.logger.Error("Failed to get Teams link", err, map[string]interface{}{
+			"user_id": userID,
+		})
+		return
+	}
+	if link == nil {
+		return
+	}
+
+	if err := s.teamsSender.SendNotification(ctx, link.WebhookURL, notification); err != nil {
+		s.logger.Error("Failed to send Teams notification", err, map[string]interface{}{
+			"user_id": userID,
+		})
+	}
+}
+
+// eventProjectID возвращает ID проекта, к которому относится уведомление
+func (s *NotifierService) eventProjectID(ctx context.Context, event *messaging.NotificationEvent) string {
+	if event.EntityID == "" {
+		return ""
+	}
+
+	switch event.EntityType {
+	case "task":
+		task, err := s.taskRepo.GetByID(ctx, event.EntityID)
+		if err != nil || task == nil {
+			return ""
+		}
+		return task.ProjectID
+	case "project":
+		return event.EntityID
+	}
+	return ""
+}
+
+// sendProjectTeamsNotification публикует уведомление в канал Teams проекта, если тип уведомления в нем включен
+func (s *NotifierService) sendProjectTeamsNotification(ctx context.Context, projectID string, event *messaging.NotificationEvent) {
+	channel, err := s.teamsRepo.GetProjectChannel(ctx, projectID)
+	if err != nil {
+		s.logger.Error("Failed to get project Teams channel", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return
+	}
+	if channel == nil || !channel.Enabled || !channel.HasNotificationType(domain.NotificationType(event.Type)) {
+		return
+	}
+
+	if err := s.teamsSender.SendNotification(ctx, channel.WebhookURL, channelNotification(event)); err != nil {
+		s.logger.Error("Failed to send project Teams notification", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+	}
+}
+
+// sendProjectDiscordNotification публикует уведомление в канал Discord проекта, если тип уведомления в нем включен
+func (s *NotifierService) sendProjectDiscordNotification(ctx context.Context, projectID string, event *messaging.NotificationEvent) {
+	channel, err := s.discordRepo.GetProjectChannel(ctx, projectID)
+	if err != nil {
+		s.logger.Error("Failed to get project Discord channel", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return
+	}
+	if channel == nil || !channel.Enabled || !channel.HasNotificationType(domain.NotificationType(event.Type)) {
+		return
+	}
+
+	if err := s.discordSender.SendNotification(ctx, channel, channelNotification(event)); err != nil {
+		s.logger.Error("Failed to send project Discord notification", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+	}
+}
+
+// sendProjectMatrixNotification публикует уведомление в комнату Matrix проекта, если тип уведомления в ней включен
+func (s *NotifierService) sendProjectMatrixNotification(ctx context.Context, projectID string, event *messaging.NotificationEvent) {
+	if !s.matrixSender.Enabled() {
+		return
+	}
+
+	room, err := s.matrixRepo.GetProjectRoom(ctx, projectID)
+	if err != nil {
+		s.logger.Error("Failed to get project Matrix room", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return
+	}
+	if room == nil || !room.Enabled || !room.HasNotificationType(domain.NotificationType(event.Type)) {
+		return
+	}
+
+	if err := s.matrixSender.SendNotification(ctx, room.RoomID, channelNotification(event)); err != nil {
+		s.logger.Error("Failed to send project Matrix notification", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+	}
+}
+
+// isSMSCritical проверяет, относится ли уведомление к критическим типам, отправляемым по SMS
+func (s *NotifierService) isSMSCritical(ctx context.Context, event *messaging.NotificationEvent) bool {
+	notificationType := domain.NotificationType(event.Type)
+
+	var priority domain.TaskPriority
+	if s.smsSender.NeedsPriority(notificationType) && event.EntityType == "task" && event.EntityID != "" {
+		if task, err := s.taskRepo.GetByID(ctx, event.EntityID); err == nil && task != nil {
+			priority = task.Priority
+		}
+	}
+
+	return s.smsSender.IsCritical(notificationType, priority)
+}
+
+// channelNotification формирует уведомление для публикации в канал проекта, не привязанное к пользователю
+func channelNotification(event *messaging.NotificationEvent) *domain.Notification {
+	return &domain.Notification{
+		Type:       domain.NotificationType(event.Type),
+		Title:      event.Title,
+		Content:    event.Content,
+		EntityID:   event.EntityID,
+		EntityType: event.EntityType,
+		MetaData:   event.MetaData,
+		CreatedAt:  event.CreatedAt,
+	}
+}
@@ -0,0 +1,548 @@
+package service
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/internal/repository"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
+	"github.com/nurlyy/task_manager/pkg/logger"
+)
+
+var (
+	ErrObjectiveNotFound     = apperrors.New(apperrors.CodeObjectiveNotFound, "objective not found")
+	ErrKeyResultNotFound     = apperrors.New(apperrors.CodeKeyResultNotFound, "key result not found")
+	ErrKeyResultLinkNotFound = apperrors.New(apperrors.CodeLinkNotFound, "key result link not found")
+	ErrKeyResultLinkExists   = apperrors.New(apperrors.CodeLinkExists, "key result is already linked to this project or epic")
+	ErrKeyResultLinkInvalid  = apperrors.New(apperrors.CodeInvalidLink, "either project_id or epic_id is required")
+	ErrObjectiveOwnerInvalid = apperrors.New(apperrors.CodeInvalidOwner, "objective owner not found")
+	ErrOKRInvalidPeriod      = apperrors.New(apperrors.CodeInvalidPeriod, "invalid OKR period")
+)
+
+const (
+	// okrAtRiskGap - отставание прогресса от прошедшей части квартала (в процентных пунктах), при котором цель под угрозой
+	okrAtRiskGap = 10
+	// okrOffTrackGap - отставание, при котором цель считается сорванной
+	okrOffTrackGap = 30
+)
+
+// OKRService представляет бизнес-логику целей и ключевых результатов
+type OKRService struct {
+	okrRepo    repository.OKRRepository
+	epicRepo   repository.EpicRepository
+	userRepo   repository.UserRepository
+	projectSvc *ProjectService
+	logger     logger.Logger
+}
+
+// NewOKRService создает новый экземпляр OKRService
+func NewOKRService(
+	okrRepo repository.OKRRepository,
+	epicRepo repository.EpicRepository,
+	userRepo repository.UserRepository,
+	projectSvc *ProjectService,
+	logger logger.Logger,
+) *OKRService {
+	return &OKRService{
+		okrRepo:    okrRepo,
+		epicRepo:   epicRepo,
+		userRepo:   userRepo,
+		projectSvc: projectSvc,
+		logger:     logger,
+	}
+}
+
+// CreateObjective создает цель на квартал
+func (s *OKRService) CreateObjective(ctx context.Context, req domain.ObjectiveCreateRequest, userID string) (*domain.Objective, error) {
+	ownerID := userID
+	if req.OwnerID != nil {
+		if err := s.checkOwner(ctx, *req.OwnerID); err != nil {
+			return nil, err
+		}
+		ownerID = *req.OwnerID
+	}
+
+	now := time.Now()
+	objective := &domain.Objective{
+		ID:          uuid.New().String(),
+		Title:       req.Title,
+		Description: req.Description,
+		OwnerID:     ownerID,
+		Year:        req.Year,
+		Quarter:     req.Quarter,
+		CreatedBy:   userID,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+		KeyResults:  []*domain.KeyResult{},
+	}
+
+	if err := s.okrRepo.CreateObjective(ctx, objective); err != nil {
+		s.logger.Error("Failed to create objective", err, map[string]interface{}{
+			"user_id": userID,
+		})
+		return nil, err
+	}
+
+	objective.Status = okrStatus(objective.Progress, quarterElapsed(objective.Year, objective.Quarter, now))
+	return objective, nil
+}
+
+// ListObjectives возвращает цели квартала с ключевыми результатами и прогрессом
+func (s *OKRService) ListObjectives(ctx context.Context, year, quarter int) ([]*domain.Objective, error) {
+	objectives, err := s.okrRepo.ListObjectives(ctx, year, quarter)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.fillObjectives(ctx, objectives, time.Now()); err != nil {
+		return nil, err
+	}
+
+	return objectives, nil
+}
+
+// GetObjective возвращает цель с ключевыми результатами и прогрессом
+func (s *OKRService) GetObjective(ctx context.Context, id string) (*domain.Objective, error) {
+	objective, err := s.okrRepo.GetObjective(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if objective == nil {
+		return nil, ErrObjectiveNotFound
+	}
+
+	if err := s.fillObjectives(ctx, []*domain.Objective{objective}, time.Now()); err != nil {
+		return nil, err
+	}
+
+	return objective, nil
+}
+
+// UpdateObjective обновляет цель
+func (s *OKRService) UpdateObjective(ctx context.Context, id string, req domain.ObjectiveUpdateRequest, userID string) (*domain.Objective, error) {
+	objective, err := s.getEditableObjective(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Title != nil {
+		objective.Title = *req.Title
+	}
+	if req.Description != nil {
+		objective.Description = *req.Description
+	}
+	if req.OwnerID != nil {
+		if err := s.checkOwner(ctx, *req.OwnerID); err != nil {
+			return nil, err
+		}
+		objective.OwnerID = *req.OwnerID
+	}
+	if req.Year != nil {
+		objective.Year = *req.Year
+	}
+	if req.Quarter != nil {
+		objective.Quarter = *req.Quarter
+	}
+	objective.UpdatedAt = time.Now()
+
+	if err := s.okrRepo.UpdateObjective(ctx, objective); err != nil {
+		s.logger.Error("Failed to update objective", err, map[string]interface{}{
+			"id": id,
+		})
+		return nil, err
+	}
+
+	if err := s.fillObjectives(ctx, []*domain.Objective{objective}, time.Now()); err != nil {
+		return nil, err
+	}
+
+	return objective, nil
+}
+
+// DeleteObjective удаляет цель вместе с ключевыми результатами
+func (s *OKRService) DeleteObjective(ctx context.Context, id string, userID string) error {
+	if _, err := s.getEditableObjective(ctx, id, userID); err != nil {
+		return err
+	}
+
+	return s.okrRepo.DeleteObjective(ctx, id)
+}
+
+// AddKeyResult добавляет ключевой результат к цели
+func (s *OKRService) AddKeyResult(ctx context.Context, objectiveID string, req domain.KeyResultRequest, userID string) (*domain.KeyResult, error) {
+	if _, err := s.getEditableObjective(ctx, objectiveID, userID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	keyResult := &domain.KeyResult{
+		ID:          uuid.New().String(),
+		ObjectiveID: objectiveID,
+		Title:       req.Title,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+		Links:       []*domain.KeyResultLink{},
+	}
+
+	if err := s.okrRepo.CreateKeyResult(ctx, keyResult); err != nil {
+		s.logger.Error("Failed to create key result", err, map[string]interface{}{
+			"objective_id": objectiveID,
+		})
+		return nil, err
+	}
+
+	return keyResult, nil
+}
+
+// UpdateKeyResult переименовывает ключевой результат
+func (s *OKRService) UpdateKeyResult(ctx context.Context, id string, req domain.KeyResultRequest, userID string) (*domain.KeyResult, error) {
+	keyResult, err := s.getEditableKeyResult(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	keyResult.Title = req.Title
+	keyResult.UpdatedAt = time.Now()
+
+	if err := s.okrRepo.UpdateKeyResult(ctx, keyResult); err != nil {
+		s.logger.Error("Failed to update key result", err, map[string]interface{}{
+			"id": id,
+		})
+		return nil, err
+	}
+
+	if err := s.fillKeyResults(ctx, []*domain.KeyResult{keyResult}); err != nil {
+		return nil, err
+	}
+
+	return keyResult, nil
+}
+
+// DeleteKeyResult удаляет ключевой результат
+func (s *OKRService) DeleteKeyResult(ctx context.Context, id string, userID string) error {
+	if _, err := s.getEditableKeyResult(ctx, id, userID); err != nil {
+		return err
+	}
+
+	return s.okrRepo.DeleteKeyResult(ctx, id)
+}
+
+// LinkKeyResult связывает ключевой результат с проектом или эпиком.
+// Связать можно только проект, которым пользователь управляет
+func (s *OKRService) LinkKeyResult(ctx context.Context, keyResultID string, req domain.KeyResultLinkRequest, userID string) (*domain.KeyResult, error) {
+	keyResult, err := s.getEditableKeyResult(ctx, keyResultID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var projectID string
+	switch {
+	case req.ProjectID != nil:
+		projectID = *req.ProjectID
+	case req.EpicID != nil:
+		epic, err := s.epicRepo.GetByID(ctx, *req.EpicID)
+		if err != nil {
+			return nil, err
+		}
+		if epic == nil || !s.projectSvc.hasAccessToProject(ctx, epic.ProjectID, userID) {
+			return nil, ErrEpicNotFound
+		}
+		projectID = epic.ProjectID
+	default:
+		return nil, ErrKeyResultLinkInvalid
+	}
+
+	if !s.projectSvc.hasAccessToProject(ctx, projectID, userID) {
+		return nil, ErrProjectNotFound
+	}
+	if !s.projectSvc.canManageProject(ctx, projectID, userID) {
+		return nil, ErrInsufficientRights
+	}
+
+	link := &domain.KeyResultLink{
+		ID:          uuid.New().String(),
+		KeyResultID: keyResultID,
+		ProjectID:   req.ProjectID,
+		EpicID:      req.EpicID,
+		CreatedBy:   userID,
+		CreatedAt:   time.Now(),
+	}
+
+	created, err := s.okrRepo.CreateLink(ctx, link)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		return nil, ErrKeyResultLinkExists
+	}
+
+	if err := s.fillKeyResults(ctx, []*domain.KeyResult{keyResult}); err != nil {
+		return nil, err
+	}
+
+	return keyResult, nil
+}
+
+// UnlinkKeyResult удаляет связь ключевого результата.
+// Удалить связь может редактор цели или менеджер связанного проекта
+func (s *OKRService) UnlinkKeyResult(ctx context.Context, keyResultID string, linkID string, userID string) error {
+	keyResult, err := s.okrRepo.GetKeyResult(ctx, keyResultID)
+	if err != nil {
+		return err
+	}
+	if keyResult == nil {
+		return ErrKeyResultNotFound
+	}
+
+	link, err := s.okrRepo.GetLink(ctx, linkID)
+	if err != nil {
+		return err
+	}
+	if link == nil || link.KeyResultID != keyResultID {
+		return ErrKeyResultLinkNotFound
+	}
+
+	objective, err := s.okrRepo.GetObjective(ctx, keyResult.ObjectiveID)
+	if err != nil {
+		return err
+	}
+	if objective == nil {
+		return ErrObjectiveNotFound
+	}
+
+	if !s.canEditObjective(ctx, objective, userID) && !s.canManageLinkedProject(ctx, link, userID) {
+		return ErrInsufficientRights
+	}
+
+	return s.okrRepo.DeleteLink(ctx, linkID)
+}
+
+// GetQuarterReport возвращает отчет по целям квартала с оценкой хода выполнения
+func (s *OKRService) GetQuarterReport(ctx context.Context, year, quarter int) (*domain.OKRReport, error) {
+	if year < 2000 || year > 2100 || quarter < 1 || quarter > 4 {
+		return nil, ErrOKRInvalidPeriod
+	}
+
+	objectives, err := s.okrRepo.ListObjectives(ctx, year, quarter)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if err := s.fillObjectives(ctx, objectives, now); err != nil {
+		return nil, err
+	}
+
+	report := &domain.OKRReport{
+		Year:           year,
+		Quarter:        quarter,
+		ElapsedPercent: roundPercent(quarterElapsed(year, quarter, now)),
+		Objectives:     objectives,
+		GeneratedAt:    now,
+	}
+
+	var total float64
+	for _, objective := range objectives {
+		total += objective.Progress
+		switch objective.Status {
+		case domain.OKRStatusOnTrack:
+			report.OnTrack++
+		case domain.OKRStatusAtRisk:
+			report.AtRisk++
+		case domain.OKRStatusOffTrack:
+			report.OffTrack++
+		}
+	}
+	if len(objectives) > 0 {
+		report.AverageProgress = roundPercent(total / float64(len(objectives)) / 100)
+	}
+
+	return report, nil
+}
+
+// CurrentQuarter возвращает год и квартал указанного момента
+func CurrentQuarter(now time.Time) (int, int) {
+	return now.Year(), (int(now.Month())-1)/3 + 1
+}
+
+// fillObjectives заполняет ключевые результаты, прогресс и оценку хода выполнения целей
+func (s *OKRService) fillObjectives(ctx context.Context, objectives []*domain.Objective, now time.Time) error {
+	ids := make([]string, 0, len(objectives))
+	for _, objective := range objectives {
+		ids = append(ids, objective.ID)
+	}
+
+	keyResults, err := s.okrRepo.ListKeyResults(ctx, ids)
+	if err != nil {
+		return err
+	}
+	if err := s.fillKeyResults(ctx, keyResults); err != nil {
+		return err
+	}
+
+	byObjective := make(map[string][]*domain.KeyResult, len(objectives))
+	for _, keyResult := range keyResults {
+		byObjective[keyResult.ObjectiveID] = append(byObjective[keyResult.ObjectiveID], keyResult)
+	}
+
+	for _, objective := range objectives {
+		objective.KeyResults = byObjective[objective.ID]
+		if objective.KeyResults == nil {
+			objective.KeyResults = []*domain.KeyResult{}
+		}
+
+		var total float64
+		for _, keyResult := range objective.KeyResults {
+			total += keyResult.Progress
+		}
+		objective.Progress = 0
+		if len(objective.KeyResults) > 0 {
+			objective.Progress = roundPercent(total / float64(len(objective.KeyResults)) / 100)
+		}
+		objective.Status = okrStatus(objective.Progress, quarterElapsed(objective.Year, objective.Quarter, now))
+	}
+
+	return nil
+}
+
+// fillKeyResults заполняет связи и прогресс ключевых результатов
+func (s *OKRService) fillKeyResults(ctx context.Context, keyResults []*domain.KeyResult) error {
+	ids := make([]string, 0, len(keyResults))
+	for _, keyResult := range keyResults {
+		ids = append(ids, keyResult.ID)
+	}
+
+	links, err := s.okrRepo.ListLinks(ctx, ids)
+	if err != nil {
+		return err
+	}
+	counts, err := s.okrRepo.CountKeyResultTasks(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	linksByKeyResult := make(map[string][]*domain.KeyResultLink, len(keyResults))
+	for _, link := range links {
+		linksByKeyResult[link.KeyResultID] = append(linksByKeyResult[link.KeyResultID], link)
+	}
+	countsByKeyResult := make(map[string]*domain.KeyResultTaskCount, len(counts))
+	for _, count := range counts {
+		countsByKeyResult[count.KeyResultID] = count
+	}
+
+	for _, keyResult := range keyResults {
+		keyResult.Links = linksByKeyResult[keyResult.ID]
+		if keyResult.Links == nil {
+			keyResult.Links = []*domain.KeyResultLink{}
+		}
+
+		keyResult.TotalTasks, keyResult.CompletedTasks, keyResult.Progress = 0, 0, 0
+		if count, ok := countsByKeyResult[keyResult.ID]; ok {
+			keyResult.TotalTasks = count.TotalTasks
+			keyResult.CompletedTasks = count.CompletedTasks
+			if count.TotalTasks > 0 {
+				keyResult.Progress = roundPercent(float64(count.CompletedTasks) / float64(count.TotalTasks))
+			}
+		}
+	}
+
+	return nil
+}
+
+// getEditableObjective возвращает цель, если пользователь может ее изменять
+func (s *OKRService) getEditableObjective(ctx context.Context, id string, userID string) (*domain.Objective, error) {
+	objective, err := s.okrRepo.GetObjective(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if objective == nil {
+		return nil, ErrObjectiveNotFound
+	}
+	if !s.canEditObjective(ctx, objective, userID) {
+		return nil, ErrInsufficientRights
+	}
+	return objective, nil
+}
+
+// getEditableKeyResult возвращает ключевой результат, если пользователь может изменять его цель
+func (s *OKRService) getEditableKeyResult(ctx context.Context, id string, userID string) (*domain.KeyResult, error) {
+	keyResult, err := s.okrRepo.GetKeyResult(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if keyResult == nil {
+		return nil, ErrKeyResultNotFound
+	}
+	if _, err := s.getEditableObjective(ctx, keyResult.ObjectiveID, userID); err != nil {
+		return nil, err
+	}
+	return keyResult, nil
+}
+
+// canEditObjective проверяет, что пользователь - владелец или автор цели либо администратор
+func (s *OKRService) canEditObjective(ctx context.Context, objective *domain.Objective, userID string) bool {
+	if objective.OwnerID == userID || objective.CreatedBy == userID {
+		return true
+	}
+	return isAdminUser(ctx, s.userRepo, userID)
+}
+
+// canManageLinkedProject проверяет, что пользователь управляет проектом связи
+func (s *OKRService) canManageLinkedProject(ctx context.Context, link *domain.KeyResultLink, userID string) bool {
+	projectID := ""
+	if link.ProjectID != nil {
+		projectID = *link.ProjectID
+	} else if link.EpicID != nil {
+		epic, err := s.epicRepo.GetByID(ctx, *link.EpicID)
+		if err != nil || epic == nil {
+			return false
+		}
+		projectID = epic.ProjectID
+	}
+	return projectID != "" && s.projectSvc.canManageProject(ctx, projectID, userID)
+}
+
+// checkOwner проверяет, что владелец цели существует
+func (s *OKRService) checkOwner(ctx context.Context, ownerID string) error {
+	user, err := s.userRepo.GetByID(ctx, ownerID)
+	if err != nil || user == nil {
+		return ErrObjectiveOwnerInvalid
+	}
+	return nil
+}
+
+// quarterElapsed возвращает прошедшую часть квартала от 0 до 1
+func quarterElapsed(year, quarter int, now time.Time) float64 {
+	start := time.Date(year, time.Month(3*(quarter-1)+1), 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 3, 0)
+	switch {
+	case now.Before(start):
+		return 0
+	case !now.Before(end):
+		return 1
+	default:
+		return float64(now.Sub(start)) / float64(end.Sub(start))
+	}
+}
+
+// okrStatus оценивает ход выполнения по отставанию прогресса от прошедшей части квартала
+func okrStatus(progress float64, elapsed float64) domain.OKRStatus {
+	gap := elapsed*100 - progress
+	switch {
+	case gap >= okrOffTrackGap:
+		return domain.OKRStatusOffTrack
+	case gap >= okrAtRiskGap:
+		return domain.OKRStatusAtRisk
+	default:
+		return domain.OKRStatusOnTrack
+	}
+}
+
+// roundPercent переводит долю в проценты с точностью до десятой
+func roundPercent(ratio float64) float64 {
+	return math.Round(ratio*1000) / 10
+}
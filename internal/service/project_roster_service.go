@@ -0,0 +1,251 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/internal/repository"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
+	"github.com/nurlyy/task_manager/pkg/logger"
+)
+
+var (
+	ErrInvitationNotFound      = apperrors.New(apperrors.CodeInvitationNotFound, "invitation not found")
+	ErrInvitationExpired       = apperrors.New(apperrors.CodeInvitationExpired, "invitation has expired")
+	ErrInvitationEmailMismatch = apperrors.New(apperrors.CodeInvitationEmailMismatch, "invitation was sent to another email")
+	ErrRosterDuplicateEmail    = apperrors.New(apperrors.CodeConflict, "email is listed more than once")
+)
+
+// projectInvitationTTL - срок действия приглашения в проект
+const projectInvitationTTL = 14 * 24 * time.Hour
+
+// RosterOutcome представляет результат добавления одного участника из списка
+type RosterOutcome struct {
+	Result *domain.RosterEntryResult
+	Err    error
+}
+
+// ProjectRosterService представляет бизнес-логику массового добавления участников
+// проектов и приглашений для пользователей, у которых еще нет учетной записи
+type ProjectRosterService struct {
+	invitationRepo repository.ProjectInvitationRepository
+	projectRepo    repository.ProjectRepository
+	userRepo       repository.UserRepository
+	projectSvc     *ProjectService
+	emailSender    *EmailSender
+	baseURL        string
+	logger         logger.Logger
+}
+
+// NewProjectRosterService создает новый экземпляр ProjectRosterService
+func NewProjectRosterService(
+	invitationRepo repository.ProjectInvitationRepository,
+	projectRepo repository.ProjectRepository,
+	userRepo repository.UserRepository,
+	projectSvc *ProjectService,
+	emailSender *EmailSender,
+	baseURL string,
+	logger logger.Logger,
+) *ProjectRosterService {
+	return &ProjectRosterService{
+		invitationRepo: invitationRepo,
+		projectRepo:    projectRepo,
+		userRepo:       userRepo,
+		projectSvc:     projectSvc,
+		emailSender:    emailSender,
+		baseURL:        strings.TrimRight(baseURL, "/"),
+		logger:         logger,
+	}
+}
+
+// AddMembers добавляет в проект участников из списка. Зарегистрированные пользователи
+// добавляются сразу, на остальные адреса отправляются приглашения. Участники
+// проверяются независимо, результат возвращается по каждому в порядке списка.
+// Прошедшие проверку пользователи добавляются одним запросом, чтобы кэш членства
+// и кэш проекта сбрасывались один раз на список, а не по каждому участнику
+func (s *ProjectRosterService) AddMembers(ctx context.Context, projectID string, entries []domain.RosterEntry, userID string) ([]RosterOutcome, error) {
+	project, err := s.projectRepo.GetByID(ctx, projectID)
+	if err != nil || project == nil {
+		return nil, ErrProjectNotFound
+	}
+	outcomes := make([]RosterOutcome, len(entries))
+	seen := make(map[string]bool, len(entries))
+	var members []*domain.ProjectMember
+	var memberOutcomes []int
+	for i, entry := range entries {
+		email := strings.TrimSpace(entry.Email)
+		key := strings.ToLower(email)
+		if seen[key] {
+			outcomes[i] = RosterOutcome{Err: ErrRosterDuplicateEmail}
+			continue
+		}
+		seen[key] = true
+
+		result, member, err := s.prepareEntry(ctx, project, email, entry.Role, userID)
+		outcomes[i] = RosterOutcome{Result: result, Err: err}
+		if member != nil {
+			members = append(members, member)
+			memberOutcomes = append(memberOutcomes, i)
+		}
+	}
+
+	if err := s.projectSvc.addMembers(ctx, project, members, userID); err != nil {
+		for _, i := range memberOutcomes {
+			outcomes[i] = RosterOutcome{Err: err}
+		}
+	}
+
+	s.logger.Info("Project roster processed", map[string]interface{}{
+		"project_id": projectID,
+		"entries":    len(entries),
+		"user_id":    userID,
+	})
+
+	return outcomes, nil
+}
+
+// prepareEntry приглашает в проект адрес без учетной записи, а для зарегистрированного
+// пользователя возвращает запись участника, которую AddMembers добавит вместе с остальными
+func (s *ProjectRosterService) prepareEntry(ctx context.Context, project *domain.Project, email string, role domain.ProjectRole, userID string) (*domain.RosterEntryResult, *domain.ProjectMember, error) {
+	user, err := s.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if user == nil {
+		invitation, err := s.invite(ctx, project, email, role, userID)
+		if err != nil {
+			return nil, nil, err
+		}
+		return &domain.RosterEntryResult{
+			Email:      email,
+			Status:     domain.RosterEntryInvited,
+			Invitation: invitation,
+		}, nil, nil
+	}
+
+	member, memberUser, err := s.projectSvc.newMember(ctx, project, user.ID, role, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &domain.RosterEntryResult{
+		Email:  email,
+		Status: domain.RosterEntryAdded,
+		Member: memberResponse(memberUser, member),
+	}, member, nil
+}
+
+// invite создает приглашение в проект и отправляет его на email. Повторное приглашение
+// того же адреса заменяет ссылку из предыдущего письма
+func (s *ProjectRosterService) invite(ctx context.Context, project *domain.Project, email string, role domain.ProjectRole, userID string) (*domain.ProjectInvitation, error) {
+	token, err := generateInvitationToken()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	invitation := &domain.ProjectInvitation{
+		ID:        uuid.New().String(),
+		ProjectID: project.ID,
+		Email:     strings.ToLower(email),
+		Role:      role,
+		TokenHash: hashInvitationToken(token),
+		InvitedBy: userID,
+		CreatedAt: now,
+		ExpiresAt: now.Add(projectInvitationTTL),
+	}
+	if err := s.invitationRepo.SaveInvitation(ctx, invitation); err != nil {
+		return nil, err
+	}
+
+	s.sendInvitation(invitation, project, token)
+
+	return invitation, nil
+}
+
+// AcceptInvitation добавляет текущего пользователя в проект по приглашению.
+// Приглашение принимает только владелец адреса, на который оно отправлено
+func (s *ProjectRosterService) AcceptInvitation(ctx context.Context, token string, userID string) (*domain.ProjectMemberResponse, error) {
+	invitation, err := s.invitationRepo.GetInvitationByToken(ctx, hashInvitationToken(token))
+	if err != nil {
+		return nil, err
+	}
+	if invitation == nil || invitation.AcceptedAt != nil {
+		return nil, ErrInvitationNotFound
+	}
+	if invitation.IsExpired(time.Now()) {
+		return nil, ErrInvitationExpired
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil || user == nil {
+		return nil, ErrUserNotFound
+	}
+	if !strings.EqualFold(user.Email, invitation.Email) {
+		return nil, ErrInvitationEmailMismatch
+	}
+
+	project, err := s.projectRepo.GetByID(ctx, invitation.ProjectID)
+	if err != nil || project == nil {
+		return nil, ErrProjectNotFound
+	}
+
+	member, err := s.projectSvc.addMember(ctx, project, userID, invitation.Role, invitation.InvitedBy)
+	if err != nil && !errors.Is(err, ErrMemberAlreadyExists) {
+		return nil, err
+	}
+
+	// Приглашение больше не нужно и тогда, когда пользователя уже добавили в проект
+	if markErr := s.invitationRepo.MarkInvitationAccepted(ctx, invitation.ID, time.Now()); markErr != nil {
+		return nil, markErr
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return member, nil
+}
+
+// sendInvitation отправляет письмо с приглашением в фоне, чтобы не задерживать обработку списка
+func (s *ProjectRosterService) sendInvitation(invitation *domain.ProjectInvitation, project *domain.Project, token string) {
+	subject := fmt.Sprintf("Приглашение в проект «%s»", project.Name)
+	body := fmt.Sprintf(
+		"Здравствуйте!\n\nВас пригласили в проект «%s». Чтобы присоединиться, зарегистрируйтесь с этим адресом и перейдите по ссылке:\n%s\n\nСсылка действительна до %s.\n\nЭто письмо отправлено автоматически, отвечать на него не нужно.",
+		project.Name,
+		s.baseURL+"/invitations/"+token,
+		invitation.ExpiresAt.Format("02.01.2006"),
+	)
+
+	go func() {
+		if err := s.emailSender.Send(invitation.Email, truncateRunes(subject, 150), body); err != nil {
+			s.logger.Error("Failed to send project invitation", err, map[string]interface{}{
+				"invitation_id": invitation.ID,
+				"project_id":    invitation.ProjectID,
+			})
+		}
+	}()
+}
+
+// generateInvitationToken генерирует токен ссылки приглашения
+func generateInvitationToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate invitation token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// hashInvitationToken возвращает хеш токена, который хранится вместо самого токена
+func hashInvitationToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -2,7 +2,7 @@ package service
 
 import (
 	"context"
-	"errors"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -11,25 +11,36 @@ import (
 	"github.com/nurlyy/task_manager/internal/messaging"
 	"github.com/nurlyy/task_manager/internal/repository"
 	"github.com/nurlyy/task_manager/internal/repository/cache"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
 	"github.com/nurlyy/task_manager/pkg/logger"
 )
 
 // Стандартные ошибки
 var (
-	ErrProjectNotFound     = errors.New("project not found")
-	ErrMemberAlreadyExists = errors.New("member already exists in project")
-	ErrMemberNotFound      = errors.New("member not found in project")
-	ErrInsufficientRights  = errors.New("insufficient rights to perform this action")
+	ErrProjectNotFound     = apperrors.New(apperrors.CodeProjectNotFound, "project not found")
+	ErrMemberAlreadyExists = apperrors.New(apperrors.CodeMemberExists, "member already exists in project")
+	ErrMemberNotFound      = apperrors.New(apperrors.CodeMemberNotFound, "member not found in project")
+	ErrInsufficientRights  = apperrors.New(apperrors.CodeInsufficientRights, "insufficient rights to perform this action")
+	ErrProjectArchived     = apperrors.New(apperrors.CodeProjectArchived, "project is archived")
+	ErrProjectNotArchived  = apperrors.New(apperrors.CodeProjectNotArchived, "project is not archived")
+	ErrOwnerNotRemovable   = apperrors.New(apperrors.CodeOwnerNotRemovable, "cannot remove project owner, transfer ownership first")
+
+	ErrOwnershipTransferPending  = apperrors.New(apperrors.CodeTransferPending, "ownership transfer is already pending")
+	ErrOwnershipTransferNotFound = apperrors.New(apperrors.CodeTransferNotFound, "pending ownership transfer not found")
+	ErrOwnershipTransferExpired  = apperrors.New(apperrors.CodeTransferExpired, "ownership transfer has expired")
 )
 
 // ProjectService представляет бизнес-логику для работы с проектами
 type ProjectService struct {
-	projectRepo repository.ProjectRepository
-	userRepo    repository.UserRepository
-	taskRepo    repository.TaskRepository
-	cacheRepo   *cache.RedisRepository
-	producer    *messaging.KafkaProducer
-	logger      logger.Logger
+	projectRepo      repository.ProjectRepository
+	userRepo         repository.UserRepository
+	taskRepo         repository.TaskRepository
+	metricsRepo      repository.ProjectMetricsRepository
+	notificationRepo repository.NotificationRepository
+	txManager        repository.TxManager
+	cacheRepo        *cache.RedisRepository
+	producer         *messaging.KafkaProducer
+	logger           logger.Logger
 }
 
 // NewProjectService создает новый экземпляр ProjectService
@@ -37,17 +48,23 @@ func NewProjectService(
 	projectRepo repository.ProjectRepository,
 	userRepo repository.UserRepository,
 	taskRepo repository.TaskRepository,
+	metricsRepo repository.ProjectMetricsRepository,
+	notificationRepo repository.NotificationRepository,
+	txManager repository.TxManager,
 	cacheRepo *cache.RedisRepository,
 	producer *messaging.KafkaProducer,
 	logger logger.Logger,
 ) *ProjectService {
 	return &ProjectService{
-		projectRepo: projectRepo,
-		userRepo:    userRepo,
-		taskRepo:    taskRepo,
-		cacheRepo:   cacheRepo,
-		producer:    producer,
-		logger:      logger,
+		projectRepo:      projectRepo,
+		userRepo:         userRepo,
+		taskRepo:         taskRepo,
+		metricsRepo:      metricsRepo,
+		notificationRepo: notificationRepo,
+		txManager:        txManager,
+		cacheRepo:        cacheRepo,
+		producer:         producer,
+		logger:           logger,
 	}
 }
 
@@ -100,6 +117,9 @@ func (s *ProjectService) Create(ctx context.Context, req domain.ProjectCreateReq
 		return nil, err
 	}
 
+	// Записываем добавление владельца в историю участников
+	s.logMembershipChange(ctx, project.ID, userID, userID, domain.MembershipActionAdded, nil, &member.Role)
+
 	// Преобразуем к ProjectResponse
 	resp := project.ToResponse()
 
@@ -127,16 +147,12 @@ func (s *ProjectService) Create(ctx context.Context, req domain.ProjectCreateReq
 }
 
 // GetByID возвращает проект по ID
-func (s *ProjectService) GetByID(ctx context.Context, id string, userID string) (*domain.ProjectResponse, error) {
+func (s *ProjectService) GetByID(ctx context.Context, id string) (*domain.ProjectResponse, error) {
 	// Пытаемся получить из кэша
 	cacheKey := "project:" + id
 	var projectResp domain.ProjectResponse
 	if err := s.cacheRepo.Get(ctx, cacheKey, &projectResp); err == nil {
-		// Проверяем, имеет ли пользователь доступ к проекту
-		if s.hasAccessToProject(ctx, id, userID) {
-			return &projectResp, nil
-		}
-		return nil, ErrInsufficientRights
+		return &projectResp, nil
 	}
 
 	// Получаем проект из БД
@@ -148,11 +164,6 @@ func (s *ProjectService) GetByID(ctx context.Context, id string, userID string)
 		return nil, ErrProjectNotFound
 	}
 
-	// Проверяем, имеет ли пользователь доступ к проекту
-	if !s.hasAccessToProject(ctx, id, userID) {
-		return nil, ErrInsufficientRights
-	}
-
 	// Получаем участников проекта
 	members, err := s.projectRepo.GetMembers(ctx, id)
 	if err != nil {
@@ -163,7 +174,7 @@ func (s *ProjectService) GetByID(ctx context.Context, id string, userID string)
 	}
 
 	// Получаем метрики проекта
-	metrics, err := s.taskRepo.GetTaskMetrics(ctx, id)
+	metrics, err := s.getMetrics(ctx, id)
 	if err != nil {
 		s.logger.Warn("Failed to get project metrics", map[string]interface{}{
 			"project_id": id,
@@ -172,9 +183,35 @@ func (s *ProjectService) GetByID(ctx context.Context, id string, userID string)
 		})
 	}
 
+	// Данные участников, найденные в кэше, получаем одним запросом
+	memberIDs := make([]string, len(members))
+	for i, member := range members {
+		memberIDs[i] = member.UserID
+	}
+	cachedUsers, err := s.cacheRepo.GetUserResponses(ctx, memberIDs)
+	if err != nil {
+		s.logger.Warn("Failed to get project members from cache", map[string]interface{}{
+			"project_id": id,
+		}, map[string]interface{}{
+			"error": err,
+		})
+	}
+
 	// Преобразуем участников к ProjectMemberResponse
 	memberResponses := make([]domain.ProjectMemberResponse, len(members))
 	for i, member := range members {
+		if user, ok := cachedUsers[member.UserID]; ok {
+			memberResponses[i] = domain.ProjectMemberResponse{
+				UserID:    user.ID,
+				Email:     user.Email,
+				FirstName: user.FirstName,
+				LastName:  user.LastName,
+				Role:      member.Role,
+				JoinedAt:  member.JoinedAt,
+			}
+			continue
+		}
+
 		user, err := s.userRepo.GetByID(ctx, member.UserID)
 		if err != nil {
 			s.logger.Error("Failed to get user for project member", err, map[string]interface{}{
@@ -211,7 +248,7 @@ func (s *ProjectService) GetByID(ctx context.Context, id string, userID string)
 }
 
 // Update обновляет данные проекта
-func (s *ProjectService) Update(ctx context.Context, id string, req domain.ProjectUpdateRequest, userID string) (*domain.ProjectResponse, error) {
+func (s *ProjectService) Update(ctx context.Context, id string, req domain.ProjectUpdateRequest) (*domain.ProjectResponse, error) {
 	// Получаем проект из БД
 	project, err := s.projectRepo.GetByID(ctx, id)
 	if err != nil {
@@ -221,11 +258,6 @@ func (s *ProjectService) Update(ctx context.Context, id string, req domain.Proje
 		return nil, ErrProjectNotFound
 	}
 
-	// Проверяем права на редактирование
-	if !s.canManageProject(ctx, id, userID) {
-		return nil, ErrInsufficientRights
-	}
-
 	// Фиксируем изменения для события
 	changes := make(map[string]interface{})
 
@@ -261,16 +293,6 @@ func (s *ProjectService) Update(ctx context.Context, id string, req domain.Proje
 		return nil, err
 	}
 
-	// Удаляем проект из кэша
-	cacheKey := "project:" + id
-	if err := s.cacheRepo.Delete(ctx, cacheKey); err != nil {
-		s.logger.Warn("Failed to delete project from cache", map[string]interface{}{
-			"id": id,
-		}, map[string]interface{}{
-			"error": err,
-		})
-	}
-
 	// Отправляем событие об обновлении проекта, если были изменения
 	if len(changes) > 0 {
 		event := &messaging.ProjectEvent{
@@ -328,24 +350,163 @@ func (s *ProjectService) Delete(ctx context.Context, id string, userID string) e
 		return err
 	}
 
-	// Удаляем проект из кэша
-	cacheKey := "project:" + id
-	if err := s.cacheRepo.Delete(ctx, cacheKey); err != nil {
-		s.logger.Warn("Failed to delete project from cache", map[string]interface{}{
+	// Отправляем событие об удалении проекта; по нему сбрасывается кэш проекта
+	event := &messaging.ProjectEvent{
+		ID:        project.ID,
+		Name:      project.Name,
+		Status:    string(project.Status),
+		CreatedBy: project.CreatedBy,
+		UpdatedAt: time.Now(),
+		Type:      messaging.EventTypeProjectDeleted,
+	}
+
+	if err := s.producer.PublishProjectDeleted(ctx, event); err != nil {
+		s.logger.Warn("Failed to publish project delete event", map[string]interface{}{
+			"project_id": project.ID,
+		}, map[string]interface{}{
+			"error": err,
+		})
+	}
+
+	return nil
+}
+
+// Archive переводит проект в архив. Задачи архивного проекта доступны только для чтения
+func (s *ProjectService) Archive(ctx context.Context, id string, userID string) (*domain.ProjectResponse, error) {
+	// Получаем проект из БД
+	project, err := s.projectRepo.GetByID(ctx, id)
+	if err != nil || project == nil {
+		s.logger.Error("Failed to get project by ID for archiving", err, map[string]interface{}{
+			"id": id,
+		})
+		return nil, ErrProjectNotFound
+	}
+
+	if project.Status == domain.ProjectStatusArchived {
+		return nil, ErrProjectArchived
+	}
+
+	changes := map[string]interface{}{
+		"status":      map[string]interface{}{"old": project.Status, "new": domain.ProjectStatusArchived},
+		"archived_by": userID,
+	}
+
+	project.Status = domain.ProjectStatusArchived
+	project.UpdatedAt = time.Now()
+
+	if err := s.saveArchiveState(ctx, project); err != nil {
+		return nil, err
+	}
+
+	event := &messaging.ProjectEvent{
+		ID:        project.ID,
+		Name:      project.Name,
+		Status:    string(project.Status),
+		UpdatedAt: project.UpdatedAt,
+	}
+	if err := s.producer.PublishProjectArchived(ctx, event, changes); err != nil {
+		s.logger.Warn("Failed to publish project archived event", map[string]interface{}{
+			"project_id": project.ID,
+		}, map[string]interface{}{
+			"error": err,
+		})
+	}
+
+	s.logger.Info("Project archived", map[string]interface{}{
+		"project_id": project.ID,
+		"user_id":    userID,
+	})
+
+	resp := project.ToResponse()
+	return &resp, nil
+}
+
+// Restore возвращает проект из архива. Если статус не указан, проект становится активным
+func (s *ProjectService) Restore(ctx context.Context, id string, req domain.ProjectRestoreRequest, userID string) (*domain.ProjectResponse, error) {
+	// Получаем проект из БД
+	project, err := s.projectRepo.GetByID(ctx, id)
+	if err != nil || project == nil {
+		s.logger.Error("Failed to get project by ID for restoring", err, map[string]interface{}{
 			"id": id,
+		})
+		return nil, ErrProjectNotFound
+	}
+
+	if project.Status != domain.ProjectStatusArchived {
+		return nil, ErrProjectNotArchived
+	}
+
+	newStatus := domain.ProjectStatusActive
+	if req.Status != nil {
+		newStatus = *req.Status
+	}
+
+	changes := map[string]interface{}{
+		"status":      map[string]interface{}{"old": project.Status, "new": newStatus},
+		"restored_by": userID,
+	}
+
+	project.Status = newStatus
+	project.UpdatedAt = time.Now()
+
+	if err := s.saveArchiveState(ctx, project); err != nil {
+		return nil, err
+	}
+
+	event := &messaging.ProjectEvent{
+		ID:        project.ID,
+		Name:      project.Name,
+		Status:    string(project.Status),
+		UpdatedAt: project.UpdatedAt,
+	}
+	if err := s.producer.PublishProjectRestored(ctx, event, changes); err != nil {
+		s.logger.Warn("Failed to publish project restored event", map[string]interface{}{
+			"project_id": project.ID,
 		}, map[string]interface{}{
 			"error": err,
 		})
 	}
 
+	s.logger.Info("Project restored", map[string]interface{}{
+		"project_id": project.ID,
+		"user_id":    userID,
+	})
+
+	resp := project.ToResponse()
+	return &resp, nil
+}
+
+// saveArchiveState сохраняет статус проекта; кэш проекта сбрасывает событие об архивировании или восстановлении
+func (s *ProjectService) saveArchiveState(ctx context.Context, project *domain.Project) error {
+	if err := s.projectRepo.Update(ctx, project); err != nil {
+		s.logger.Error("Failed to update project archive state", err, map[string]interface{}{
+			"id": project.ID,
+		})
+		return err
+	}
+
+	return nil
+}
+
+// ensureProjectWritable проверяет, что в проекте можно изменять задачи
+func (s *ProjectService) ensureProjectWritable(ctx context.Context, projectID string) error {
+	project, err := s.projectRepo.GetByID(ctx, projectID)
+	if err != nil || project == nil {
+		return ErrProjectNotFound
+	}
+
+	if project.Status == domain.ProjectStatusArchived {
+		return ErrProjectArchived
+	}
+
 	return nil
 }
 
 // List возвращает список проектов с фильтрацией
-func (s *ProjectService) List(ctx context.Context, filter repository.ProjectFilter, userID string, page, pageSize int) (*domain.PagedResponse, error) {
+func (s *ProjectService) List(ctx context.Context, filter repository.ProjectFilter, userID string, page domain.PageRequest) (*domain.PagedResponse, error) {
 	// Настраиваем пагинацию
-	filter.Limit = pageSize
-	filter.Offset = (page - 1) * pageSize
+	filter.Limit = page.Limit()
+	filter.Offset = page.Offset()
 
 	// Если не передан фильтр по участнику, добавляем текущего пользователя
 	if filter.MemberID == nil {
@@ -358,12 +519,16 @@ func (s *ProjectService) List(ctx context.Context, filter repository.ProjectFilt
 		s.logger.Error("Failed to list projects", err)
 		return nil, err
 	}
+	projects, hasMore := domain.PageItems(projects, page)
 
-	// Получаем общее количество проектов
-	total, err := s.projectRepo.Count(ctx, filter)
-	if err != nil {
-		s.logger.Error("Failed to count projects", err)
-		return nil, err
+	// Получаем общее количество проектов, если клиент не отказался от подсчета
+	total := 0
+	if !page.SkipCount {
+		total, err = s.projectRepo.Count(ctx, filter)
+		if err != nil {
+			s.logger.Error("Failed to count projects", err)
+			return nil, err
+		}
 	}
 
 	// Преобразуем к ProjectResponse
@@ -373,13 +538,7 @@ func (s *ProjectService) List(ctx context.Context, filter repository.ProjectFilt
 	}
 
 	// Формируем ответ с пагинацией
-	return &domain.PagedResponse{
-		Items:      projectResponses,
-		TotalItems: total,
-		Page:       page,
-		PageSize:   pageSize,
-		TotalPages: (total + pageSize - 1) / pageSize,
-	}, nil
+	return domain.NewPagedResponse(projectResponses, page, total, hasMore), nil
 }
 
 // AddMember добавляет участника в проект
@@ -393,98 +552,159 @@ func (s *ProjectService) AddMember(ctx context.Context, projectID string, req do
 		return nil, ErrProjectNotFound
 	}
 
-	// Проверяем права на добавление участников
-	if !s.canManageProject(ctx, projectID, userID) {
-		return nil, ErrInsufficientRights
+	return s.addMember(ctx, project, req.UserID, req.Role, userID)
+}
+
+// addMember добавляет пользователя в проект без проверки прав, записывает изменение
+// в историю и публикует событие, по которому сбрасывается кэш проекта
+func (s *ProjectService) addMember(ctx context.Context, project *domain.Project, memberID string, role domain.ProjectRole, userID string) (*domain.ProjectMemberResponse, error) {
+	projectID := project.ID
+
+	member, newUser, err := s.newMember(ctx, project, memberID, role, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.projectRepo.AddMember(ctx, member); err != nil {
+		s.logger.Error("Failed to add member to project", err, map[string]interface{}{
+			"project_id": projectID,
+		}, map[string]interface{}{
+			"user_id": memberID,
+		})
+		return nil, err
+	}
+
+	// Записываем добавление участника в историю
+	s.logMembershipChange(ctx, projectID, memberID, userID, domain.MembershipActionAdded, nil, &member.Role)
+
+	// Отправляем событие о добавлении участника
+	event := &messaging.ProjectMemberEvent{
+		ProjectID:   projectID,
+		ProjectName: project.Name,
+		UserID:      memberID,
+		Role:        string(role),
+		InvitedBy:   userID,
+		JoinedAt:    member.JoinedAt,
+		Type:        messaging.EventTypeProjectMemberAdded,
+	}
+
+	if err := s.producer.PublishProjectMemberAdded(ctx, projectID, event.ProjectName, event); err != nil {
+		s.logger.Warn("Failed to publish project member added event", map[string]interface{}{
+			"project_id": projectID,
+		}, map[string]interface{}{
+			"user_id": memberID,
+		}, map[string]interface{}{
+			"error": err,
+		})
 	}
 
+	return memberResponse(newUser, member), nil
+}
+
+// newMember проверяет, что пользователь существует и еще не участвует в проекте,
+// и возвращает запись участника для добавления вместе с пользователем
+func (s *ProjectService) newMember(ctx context.Context, project *domain.Project, memberID string, role domain.ProjectRole, userID string) (*domain.ProjectMember, *domain.User, error) {
+	projectID := project.ID
+
 	// Проверяем, существует ли пользователь, которого добавляем
-	newUser, err := s.userRepo.GetByID(ctx, req.UserID)
+	newUser, err := s.userRepo.GetByID(ctx, memberID)
 	if err != nil {
 		s.logger.Error("Failed to get user by ID for adding to project", err, map[string]interface{}{
-			"user_id": req.UserID,
+			"user_id": memberID,
 		})
-		return nil, ErrUserNotFound
+		return nil, nil, ErrUserNotFound
 	}
 
 	// Проверяем, не является ли пользователь уже участником проекта
-	memberCheck, err := s.projectRepo.GetMember(ctx, projectID, req.UserID)
+	memberCheck, err := s.projectRepo.GetMember(ctx, projectID, memberID)
 
 	s.logger.Info("Project member check result", map[string]interface{}{
 		"project_id":    projectID,
-		"user_id":       req.UserID,
+		"user_id":       memberID,
 		"member":        memberCheck,
 		"error":         err,
 		"is_member_nil": memberCheck == nil,
 	})
 
 	if err != nil {
-		return nil, err // Возвращаем ошибку запроса
+		return nil, nil, err // Возвращаем ошибку запроса
 	}
 
 	// Если member не nil, значит пользователь уже состоит в проекте
 	if memberCheck != nil {
-		return nil, ErrMemberAlreadyExists
+		return nil, nil, ErrMemberAlreadyExists
 	}
 
-	// Добавляем участника в проект
 	member := &domain.ProjectMember{
 		ProjectID: projectID,
-		UserID:    req.UserID,
-		Role:      req.Role,
+		UserID:    memberID,
+		Role:      role,
 		JoinedAt:  time.Now(),
 		InvitedBy: userID,
 	}
+	return member, newUser, nil
+}
 
-	if err := s.projectRepo.AddMember(ctx, member); err != nil {
-		s.logger.Error("Failed to add member to project", err, map[string]interface{}{
-			"project_id": projectID,
-		}, map[string]interface{}{
-			"user_id": req.UserID,
-		})
-		return nil, err
+// addMembers добавляет в проект участников, подготовленных newMember, одним запросом.
+// Вместо события на каждого участника публикуется одно событие проекта со списком
+// добавленных, поэтому кэш проекта сбрасывается один раз
+func (s *ProjectService) addMembers(ctx context.Context, project *domain.Project, members []*domain.ProjectMember, userID string) error {
+	if len(members) == 0 {
+		return nil
 	}
 
-	// Удаляем проект из кэша
-	cacheKey := "project:" + projectID
-	if err := s.cacheRepo.Delete(ctx, cacheKey); err != nil {
-		s.logger.Warn("Failed to delete project from cache", map[string]interface{}{
-			"id": projectID,
-		}, map[string]interface{}{
-			"error": err,
+	if err := s.projectRepo.AddMembers(ctx, members); err != nil {
+		s.logger.Error("Failed to add members to project", err, map[string]interface{}{
+			"project_id": project.ID,
+			"count":      len(members),
 		})
+		return err
 	}
 
-	// Отправляем событие о добавлении участника
-	event := &messaging.ProjectMemberEvent{
-		ProjectID:   projectID,
-		ProjectName: project.Name,
-		UserID:      req.UserID,
-		Role:        string(req.Role),
-		InvitedBy:   userID,
-		JoinedAt:    member.JoinedAt,
-		Type:        messaging.EventTypeProjectMemberAdded,
+	added := make([]map[string]interface{}, len(members))
+	for i, member := range members {
+		s.logMembershipChange(ctx, project.ID, member.UserID, userID, domain.MembershipActionAdded, nil, &member.Role)
+		added[i] = map[string]interface{}{
+			"user_id": member.UserID,
+			"role":    member.Role,
+		}
 	}
 
-	if err := s.producer.PublishProjectMemberAdded(ctx, projectID, event.ProjectName, event); err != nil {
-		s.logger.Warn("Failed to publish project member added event", map[string]interface{}{
-			"project_id": projectID,
-		}, map[string]interface{}{
-			"user_id": req.UserID,
+	event := &messaging.ProjectEvent{
+		ID:          project.ID,
+		Name:        project.Name,
+		Description: project.Description,
+		Status:      string(project.Status),
+		CreatedBy:   project.CreatedBy,
+		UpdatedAt:   time.Now(),
+		Type:        messaging.EventTypeProjectUpdated,
+		Changes: map[string]interface{}{
+			"members_added": added,
+			"added_by":      userID,
+		},
+	}
+
+	if err := s.producer.PublishProjectUpdated(ctx, event, event.Changes); err != nil {
+		s.logger.Warn("Failed to publish project members added event", map[string]interface{}{
+			"project_id": project.ID,
 		}, map[string]interface{}{
 			"error": err,
 		})
 	}
 
-	// Формируем ответ
+	return nil
+}
+
+// memberResponse формирует ответ по участнику проекта
+func memberResponse(user *domain.User, member *domain.ProjectMember) *domain.ProjectMemberResponse {
 	return &domain.ProjectMemberResponse{
-		UserID:    newUser.ID,
-		Email:     newUser.Email,
-		FirstName: newUser.FirstName,
-		LastName:  newUser.LastName,
-		Role:      req.Role,
+		UserID:    user.ID,
+		Email:     user.Email,
+		FirstName: user.FirstName,
+		LastName:  user.LastName,
+		Role:      member.Role,
 		JoinedAt:  member.JoinedAt,
-	}, nil
+	}
 }
 
 // UpdateMember обновляет роль участника проекта
@@ -498,11 +718,6 @@ func (s *ProjectService) UpdateMember(ctx context.Context, projectID string, mem
 		return nil, ErrProjectNotFound
 	}
 
-	// Проверяем права на управление участниками
-	if !s.canManageProject(ctx, projectID, userID) {
-		return nil, ErrInsufficientRights
-	}
-
 	// Проверяем, является ли пользователь участником проекта
 	member, err := s.projectRepo.GetMember(ctx, projectID, memberID)
 	if err != nil {
@@ -523,7 +738,7 @@ func (s *ProjectService) UpdateMember(ctx context.Context, projectID string, mem
 	}
 
 	// Обновляем роль участника
-	// oldRole := member.Role
+	oldRole := member.Role
 	member.Role = req.Role
 
 	if err := s.projectRepo.UpdateMember(ctx, projectID, member.UserID, member.Role); err != nil {
@@ -535,14 +750,9 @@ func (s *ProjectService) UpdateMember(ctx context.Context, projectID string, mem
 		return nil, err
 	}
 
-	// Удаляем проект из кэша
-	cacheKey := "project:" + projectID
-	if err := s.cacheRepo.Delete(ctx, cacheKey); err != nil {
-		s.logger.Warn("Failed to delete project from cache", map[string]interface{}{
-			"id": projectID,
-		}, map[string]interface{}{
-			"error": err,
-		})
+	// Записываем изменение роли в историю
+	if oldRole != member.Role {
+		s.logMembershipChange(ctx, projectID, memberID, userID, domain.MembershipActionRoleChanged, &oldRole, &member.Role)
 	}
 
 	// Получаем данные пользователя для ответа
@@ -616,7 +826,7 @@ func (s *ProjectService) RemoveMember(ctx context.Context, projectID string, mem
 	// Не позволяем удалять владельца проекта
 	if member.Role == domain.ProjectRoleOwner {
 		// Если владелец хочет выйти, он должен сначала передать права другому участнику
-		return errors.New("cannot remove project owner, transfer ownership first")
+		return ErrOwnerNotRemovable
 	}
 
 	// Удаляем участника из проекта
@@ -629,15 +839,8 @@ func (s *ProjectService) RemoveMember(ctx context.Context, projectID string, mem
 		return err
 	}
 
-	// Удаляем проект из кэша
-	cacheKey := "project:" + projectID
-	if err := s.cacheRepo.Delete(ctx, cacheKey); err != nil {
-		s.logger.Warn("Failed to delete project from cache", map[string]interface{}{
-			"id": projectID,
-		}, map[string]interface{}{
-			"error": err,
-		})
-	}
+	// Записываем удаление участника в историю
+	s.logMembershipChange(ctx, projectID, memberID, userID, domain.MembershipActionRemoved, &member.Role, nil)
 
 	// Отправляем событие об удалении участника
 	event := &messaging.ProjectMemberEvent{
@@ -663,31 +866,226 @@ func (s *ProjectService) RemoveMember(ctx context.Context, projectID string, mem
 	return nil
 }
 
-// TransferOwnership передает владение проектом другому участнику
-func (s *ProjectService) TransferOwnership(ctx context.Context, projectID string, newOwnerID string, userID string) error {
+// InitiateOwnershipTransfer создает запрос на передачу владения проектом другому участнику.
+// Роли не меняются, пока новый владелец не примет запрос
+func (s *ProjectService) InitiateOwnershipTransfer(ctx context.Context, projectID string, req domain.OwnershipTransferRequest, userID string) (*domain.OwnershipTransfer, error) {
 	// Проверяем, существует ли проект
 	project, err := s.projectRepo.GetByID(ctx, projectID)
-	if err != nil {
+	if err != nil || project == nil {
 		s.logger.Error("Failed to get project by ID for transferring ownership", err, map[string]interface{}{
 			"id": projectID,
 		})
-		return ErrProjectNotFound
+		return nil, ErrProjectNotFound
 	}
 
 	// Проверяем, является ли текущий пользователь владельцем проекта
 	currentOwner, err := s.projectRepo.GetMember(ctx, projectID, userID)
-	if err != nil || currentOwner.Role != domain.ProjectRoleOwner {
+	if err != nil || currentOwner == nil || currentOwner.Role != domain.ProjectRoleOwner {
 		s.logger.Warn("User attempted to transfer project ownership without owner rights", map[string]interface{}{
 			"user_id": userID,
 		}, map[string]interface{}{
 			"project_id": projectID,
 		})
-		return ErrInsufficientRights
+		return nil, ErrInsufficientRights
 	}
 
+	// Новый владелец должен быть другим участником проекта
+	if req.NewOwnerID == userID {
+		return nil, ErrMemberAlreadyExists
+	}
+	newOwner, err := s.projectRepo.GetMember(ctx, projectID, req.NewOwnerID)
+	if err != nil || newOwner == nil {
+		return nil, ErrMemberNotFound
+	}
+
+	// Проверяем, нет ли уже ожидающего запроса
+	pending, err := s.projectRepo.GetPendingOwnershipTransfer(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if pending != nil {
+		return nil, ErrOwnershipTransferPending
+	}
+
+	now := time.Now()
+	transfer := &domain.OwnershipTransfer{
+		ID:         uuid.New().String(),
+		ProjectID:  projectID,
+		FromUserID: userID,
+		ToUserID:   req.NewOwnerID,
+		Status:     domain.OwnershipTransferStatusPending,
+		CreatedAt:  now,
+		ExpiresAt:  now.Add(domain.OwnershipTransferTTL),
+	}
+
+	if err := s.projectRepo.CreateOwnershipTransfer(ctx, transfer); err != nil {
+		s.logger.Error("Failed to create ownership transfer", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return nil, err
+	}
+
+	s.publishOwnershipTransferEvent(ctx, project, transfer)
+
+	// Уведомляем нового владельца о необходимости подтвердить передачу
+	s.notifyUser(ctx, req.NewOwnerID, project,
+		"Запрос на передачу владения проектом",
+		fmt.Sprintf("Вам предлагают стать владельцем проекта \"%s\". Подтвердите передачу до %s",
+			project.Name, transfer.ExpiresAt.Format("02.01.2006 15:04")),
+		map[string]string{
+			"transfer_id": transfer.ID,
+			"from_user":   userID,
+			"expires_at":  transfer.ExpiresAt.Format(time.RFC3339),
+		},
+	)
+
+	return transfer, nil
+}
+
+// GetPendingOwnershipTransfer возвращает ожидающий запрос на передачу владения проектом
+func (s *ProjectService) GetPendingOwnershipTransfer(ctx context.Context, projectID string) (*domain.OwnershipTransfer, error) {
+	transfer, err := s.projectRepo.GetPendingOwnershipTransfer(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if transfer == nil {
+		return nil, ErrOwnershipTransferNotFound
+	}
+
+	return transfer, nil
+}
+
+// AcceptOwnershipTransfer подтверждает передачу владения проектом новым владельцем
+func (s *ProjectService) AcceptOwnershipTransfer(ctx context.Context, projectID string, userID string) (*domain.OwnershipTransfer, error) {
+	project, transfer, err := s.getPendingTransferForUser(ctx, projectID, userID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	// Просроченный запрос закрываем, владение не меняется
+	if transfer.IsExpired() {
+		s.resolveOwnershipTransfer(ctx, project, transfer, domain.OwnershipTransferStatusExpired, nil)
+		return nil, ErrOwnershipTransferExpired
+	}
+
+	// Текущий владелец мог смениться с момента создания запроса
+	currentOwner, err := s.projectRepo.GetMember(ctx, projectID, transfer.FromUserID)
+	if err != nil || currentOwner == nil || currentOwner.Role != domain.ProjectRoleOwner {
+		s.resolveOwnershipTransfer(ctx, project, transfer, domain.OwnershipTransferStatusCancelled, nil)
+		return nil, ErrOwnershipTransferNotFound
+	}
+
+	// Закрытие запроса, смена ролей и событие сохраняются в одной транзакции: запрос нельзя
+	// принять дважды, а при ошибке не останется принятого запроса без смены владельца
+	err = s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		resolved, err := s.projectRepo.ResolveOwnershipTransfer(ctx, transfer.ID, domain.OwnershipTransferStatusAccepted, &userID)
+		if err != nil {
+			return err
+		}
+		if !resolved {
+			return ErrOwnershipTransferNotFound
+		}
+
+		if err := s.applyOwnershipTransfer(ctx, project, currentOwner, userID); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		transfer.Status = domain.OwnershipTransferStatusAccepted
+		transfer.ResolvedAt = &now
+		transfer.ResolvedBy = &userID
+
+		s.publishOwnershipTransferEvent(messaging.WithOutbox(ctx), project, transfer)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return transfer, nil
+}
+
+// DeclineOwnershipTransfer отклоняет передачу владения проектом новым владельцем
+func (s *ProjectService) DeclineOwnershipTransfer(ctx context.Context, projectID string, userID string) (*domain.OwnershipTransfer, error) {
+	project, transfer, err := s.getPendingTransferForUser(ctx, projectID, userID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.resolveOwnershipTransfer(ctx, project, transfer, domain.OwnershipTransferStatusDeclined, &userID) {
+		return nil, ErrOwnershipTransferNotFound
+	}
+
+	s.notifyUser(ctx, transfer.FromUserID, project,
+		"Передача владения проектом отклонена",
+		fmt.Sprintf("Пользователь отклонил передачу владения проектом \"%s\"", project.Name),
+		map[string]string{"transfer_id": transfer.ID},
+	)
+
+	return transfer, nil
+}
+
+// CancelOwnershipTransfer отменяет запрос на передачу владения текущим владельцем
+func (s *ProjectService) CancelOwnershipTransfer(ctx context.Context, projectID string, userID string) (*domain.OwnershipTransfer, error) {
+	project, transfer, err := s.getPendingTransferForUser(ctx, projectID, userID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.resolveOwnershipTransfer(ctx, project, transfer, domain.OwnershipTransferStatusCancelled, &userID) {
+		return nil, ErrOwnershipTransferNotFound
+	}
+
+	return transfer, nil
+}
+
+// getPendingTransferForUser возвращает ожидающий запрос, если пользователь является
+// его получателем (asRecipient) или инициатором
+func (s *ProjectService) getPendingTransferForUser(ctx context.Context, projectID string, userID string, asRecipient bool) (*domain.Project, *domain.OwnershipTransfer, error) {
+	project, err := s.projectRepo.GetByID(ctx, projectID)
+	if err != nil || project == nil {
+		return nil, nil, ErrProjectNotFound
+	}
+
+	transfer, err := s.projectRepo.GetPendingOwnershipTransfer(ctx, projectID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if transfer == nil {
+		return nil, nil, ErrOwnershipTransferNotFound
+	}
+
+	if (asRecipient && transfer.ToUserID != userID) || (!asRecipient && transfer.FromUserID != userID) {
+		return nil, nil, ErrInsufficientRights
+	}
+
+	return project, transfer, nil
+}
+
+// resolveOwnershipTransfer закрывает запрос без смены владельца и публикует событие
+func (s *ProjectService) resolveOwnershipTransfer(ctx context.Context, project *domain.Project, transfer *domain.OwnershipTransfer, status domain.OwnershipTransferStatus, resolvedBy *string) bool {
+	resolved, err := s.projectRepo.ResolveOwnershipTransfer(ctx, transfer.ID, status, resolvedBy)
+	if err != nil || !resolved {
+		return false
+	}
+
+	now := time.Now()
+	transfer.Status = status
+	transfer.ResolvedAt = &now
+	transfer.ResolvedBy = resolvedBy
+
+	s.publishOwnershipTransferEvent(ctx, project, transfer)
+	return true
+}
+
+// applyOwnershipTransfer меняет роли текущего и нового владельца проекта.
+// Вызывается внутри транзакции: при ошибке обе роли откатываются вместе
+func (s *ProjectService) applyOwnershipTransfer(ctx context.Context, project *domain.Project, currentOwner *domain.ProjectMember, newOwnerID string) error {
+	projectID := project.ID
+
 	// Проверяем, является ли новый владелец участником проекта
 	newOwner, err := s.projectRepo.GetMember(ctx, projectID, newOwnerID)
-	if err != nil {
+	if err != nil || newOwner == nil {
 		s.logger.Error("Failed to get new owner as member", err, map[string]interface{}{
 			"project_id": projectID,
 		}, map[string]interface{}{
@@ -696,13 +1094,15 @@ func (s *ProjectService) TransferOwnership(ctx context.Context, projectID string
 		return ErrMemberNotFound
 	}
 
+	newOwnerOldRole := newOwner.Role
+
 	// Меняем роль текущего владельца на Manager
 	currentOwner.Role = domain.ProjectRoleManager
 	if err := s.projectRepo.UpdateMember(ctx, projectID, currentOwner.UserID, currentOwner.Role); err != nil {
 		s.logger.Error("Failed to update current owner role", err, map[string]interface{}{
 			"project_id": projectID,
 		}, map[string]interface{}{
-			"user_id": userID,
+			"user_id": currentOwner.UserID,
 		})
 		return err
 	}
@@ -715,23 +1115,20 @@ func (s *ProjectService) TransferOwnership(ctx context.Context, projectID string
 		}, map[string]interface{}{
 			"user_id": newOwnerID,
 		})
-		// Если не удалось обновить нового владельца, восстанавливаем старого
-		currentOwner.Role = domain.ProjectRoleOwner
-		_ = s.projectRepo.UpdateMember(ctx, projectID, currentOwner.UserID, currentOwner.Role)
 		return err
 	}
 
-	// Удаляем проект из кэша
-	cacheKey := "project:" + projectID
-	if err := s.cacheRepo.Delete(ctx, cacheKey); err != nil {
-		s.logger.Warn("Failed to delete project from cache", map[string]interface{}{
-			"id": projectID,
-		}, map[string]interface{}{
-			"error": err,
-		})
-	}
+	// Записываем смену ролей в историю участников
+	ownerRole := domain.ProjectRoleOwner
+	managerRole := domain.ProjectRoleManager
+	s.logMembershipChange(ctx, projectID, currentOwner.UserID, newOwnerID, domain.MembershipActionRoleChanged, &ownerRole, &managerRole)
+	s.logMembershipChange(ctx, projectID, newOwnerID, newOwnerID, domain.MembershipActionRoleChanged, &newOwnerOldRole, &ownerRole)
 
-	// Отправляем событие о передаче владения
+	return nil
+}
+
+// publishOwnershipTransferEvent отправляет событие об изменении статуса передачи владения
+func (s *ProjectService) publishOwnershipTransferEvent(ctx context.Context, project *domain.Project, transfer *domain.OwnershipTransfer) {
 	event := &messaging.ProjectEvent{
 		ID:          project.ID,
 		Name:        project.Name,
@@ -739,22 +1136,246 @@ func (s *ProjectService) TransferOwnership(ctx context.Context, projectID string
 		Status:      string(project.Status),
 		CreatedBy:   project.CreatedBy,
 		UpdatedAt:   time.Now(),
-		Type:        "project_ownership_transferred",
+		Type:        "project_ownership_transfer_" + string(transfer.Status),
 		Changes: map[string]interface{}{
-			"previous_owner": userID,
-			"new_owner":      newOwnerID,
+			"transfer_id":    transfer.ID,
+			"previous_owner": transfer.FromUserID,
+			"new_owner":      transfer.ToUserID,
+			"status":         transfer.Status,
 		},
 	}
 
 	if err := s.producer.PublishProjectUpdated(ctx, event, event.Changes); err != nil {
 		s.logger.Warn("Failed to publish ownership transfer event", map[string]interface{}{
-			"project_id": projectID,
+			"project_id": project.ID,
 		}, map[string]interface{}{
 			"error": err,
 		})
 	}
+}
 
-	return nil
+// notifyUser создает уведомление пользователю о событии проекта и отправляет его в очередь
+func (s *ProjectService) notifyUser(ctx context.Context, userID string, project *domain.Project, title, content string, metaData map[string]string) {
+	notification := &domain.Notification{
+		ID:         uuid.New().String(),
+		UserID:     userID,
+		Type:       domain.NotificationTypeProjectUpdated,
+		Title:      title,
+		Content:    content,
+		Status:     domain.NotificationStatusUnread,
+		EntityType: "project",
+		EntityID:   project.ID,
+		MetaData:   metaData,
+		CreatedAt:  time.Now(),
+	}
+
+	if err := s.notificationRepo.Create(ctx, notification); err != nil {
+		s.logger.Warn("Failed to create project notification", map[string]interface{}{
+			"project_id": project.ID,
+			"user_id":    userID,
+		}, map[string]interface{}{
+			"error": err,
+		})
+	}
+
+	event := &messaging.NotificationEvent{
+		UserIDs:    []string{userID},
+		Title:      notification.Title,
+		Content:    notification.Content,
+		Type:       string(notification.Type),
+		EntityID:   project.ID,
+		EntityType: "project",
+		CreatedAt:  notification.CreatedAt,
+		MetaData:   metaData,
+	}
+
+	if err := s.producer.PublishNotification(ctx, event); err != nil {
+		s.logger.Warn("Failed to publish project notification event", map[string]interface{}{
+			"project_id": project.ID,
+			"user_id":    userID,
+		}, map[string]interface{}{
+			"error": err,
+		})
+	}
+}
+
+// GetTaskSettings возвращает настройки задач проекта по умолчанию
+func (s *ProjectService) GetTaskSettings(ctx context.Context, projectID string) (*domain.ProjectTaskSettings, error) {
+	project, err := s.projectRepo.GetByID(ctx, projectID)
+	if err != nil || project == nil {
+		return nil, ErrProjectNotFound
+	}
+
+	settings, err := s.projectRepo.GetTaskSettings(ctx, projectID)
+	if err != nil {
+		s.logger.Error("Failed to get project task settings", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return nil, err
+	}
+
+	// Если настройки не заданы, возвращаем пустые значения
+	if settings == nil {
+		settings = &domain.ProjectTaskSettings{
+			ProjectID:             projectID,
+			RequiredFields:        []domain.TaskField{},
+			TransitionRules:       map[domain.TaskStatus][]domain.TaskField{},
+			AutoLabelStale:        true,
+			AssigneeOpenTaskLimit: domain.DefaultAssigneeOpenTaskLimit,
+			UpdatedAt:             project.UpdatedAt,
+		}
+	}
+
+	return settings, nil
+}
+
+// UpdateTaskSettings изменяет настройки задач проекта по умолчанию
+func (s *ProjectService) UpdateTaskSettings(ctx context.Context, projectID string, req domain.ProjectTaskSettingsRequest, userID string) (*domain.ProjectTaskSettings, error) {
+	settings, err := s.GetTaskSettings(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.DefaultAssigneeID != nil {
+		if *req.DefaultAssigneeID == "" {
+			settings.DefaultAssigneeID = nil
+		} else {
+			// Исполнитель по умолчанию должен быть участником проекта
+			if _, err := s.projectRepo.GetMember(ctx, projectID, *req.DefaultAssigneeID); err != nil {
+				return nil, ErrMemberNotFound
+			}
+			assigneeID := *req.DefaultAssigneeID
+			settings.DefaultAssigneeID = &assigneeID
+		}
+	}
+	if req.DefaultPriority != nil {
+		if *req.DefaultPriority == "" {
+			settings.DefaultPriority = nil
+		} else {
+			priority := domain.TaskPriority(*req.DefaultPriority)
+			settings.DefaultPriority = &priority
+		}
+	}
+	if req.DefaultDueOffsetDays != nil {
+		if *req.DefaultDueOffsetDays == 0 {
+			settings.DefaultDueOffsetDays = nil
+		} else {
+			offset := *req.DefaultDueOffsetDays
+			settings.DefaultDueOffsetDays = &offset
+		}
+	}
+	if req.RequiredFields != nil {
+		settings.RequiredFields = uniqueTaskFields(*req.RequiredFields)
+	}
+	if req.TransitionRules != nil {
+		rules := make(map[domain.TaskStatus][]domain.TaskField, len(*req.TransitionRules))
+		for status, fields := range *req.TransitionRules {
+			if len(fields) > 0 {
+				rules[status] = uniqueTaskFields(fields)
+			}
+		}
+		settings.TransitionRules = rules
+	}
+	if req.AutoLabelStale != nil {
+		settings.AutoLabelStale = *req.AutoLabelStale
+	}
+	if req.AssigneeOpenTaskLimit != nil {
+		settings.AssigneeOpenTaskLimit = *req.AssigneeOpenTaskLimit
+	}
+
+	settings.UpdatedBy = &userID
+	settings.UpdatedAt = time.Now()
+
+	if err := s.projectRepo.SaveTaskSettings(ctx, settings); err != nil {
+		s.logger.Error("Failed to save project task settings", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return nil, err
+	}
+
+	s.logger.Info("Project task settings updated", map[string]interface{}{
+		"project_id": projectID,
+		"user_id":    userID,
+	})
+
+	return settings, nil
+}
+
+// uniqueTaskFields убирает дубликаты из списка полей, сохраняя порядок
+func uniqueTaskFields(fields []domain.TaskField) []domain.TaskField {
+	seen := make(map[domain.TaskField]bool, len(fields))
+	result := make([]domain.TaskField, 0, len(fields))
+	for _, field := range fields {
+		if !seen[field] {
+			seen[field] = true
+			result = append(result, field)
+		}
+	}
+	return result
+}
+
+// GetMembershipHistory возвращает историю изменений участников проекта
+func (s *ProjectService) GetMembershipHistory(ctx context.Context, projectID string, page domain.PageRequest) (*domain.PagedResponse, error) {
+	// Проверяем, существует ли проект
+	project, err := s.projectRepo.GetByID(ctx, projectID)
+	if err != nil || project == nil {
+		return nil, ErrProjectNotFound
+	}
+
+	history, err := s.projectRepo.GetMembershipHistory(ctx, projectID, page.Limit(), page.Offset())
+	if err != nil {
+		s.logger.Error("Failed to get membership history", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return nil, err
+	}
+	history, hasMore := domain.PageItems(history, page)
+
+	total := 0
+	if !page.SkipCount {
+		total, err = s.projectRepo.CountMembershipHistory(ctx, projectID)
+		if err != nil {
+			s.logger.Error("Failed to count membership history", err, map[string]interface{}{
+				"project_id": projectID,
+			})
+			return nil, err
+		}
+	}
+
+	// Получаем данные пользователей, участвующих в истории
+	users := make(map[string]domain.UserBrief)
+	getBrief := func(id string) domain.UserBrief {
+		if brief, ok := users[id]; ok {
+			return brief
+		}
+		brief := domain.UserBrief{ID: id}
+		if user, err := s.userRepo.GetByID(ctx, id); err == nil && user != nil {
+			brief = domain.UserBrief{
+				ID:        user.ID,
+				Email:     user.Email,
+				FirstName: user.FirstName,
+				LastName:  user.LastName,
+				Avatar:    user.Avatar,
+			}
+		}
+		users[id] = brief
+		return brief
+	}
+
+	items := make([]domain.MembershipHistoryResponse, len(history))
+	for i, h := range history {
+		items[i] = domain.MembershipHistoryResponse{
+			ID:        h.ID,
+			User:      getBrief(h.UserID),
+			Actor:     getBrief(h.ActorID),
+			Action:    h.Action,
+			OldRole:   h.OldRole,
+			NewRole:   h.NewRole,
+			ChangedAt: h.ChangedAt,
+		}
+	}
+
+	return domain.NewPagedResponse(items, page, total, hasMore), nil
 }
 
 // Добавляем вспомогательные методы для проверки прав
@@ -762,27 +1383,44 @@ func (s *ProjectService) TransferOwnership(ctx context.Context, projectID string
 // hasAccessToProject проверяет, имеет ли пользователь доступ к проекту
 func (s *ProjectService) hasAccessToProject(ctx context.Context, projectID string, userID string) bool {
 	// Администраторы имеют доступ ко всем проектам
-	user, err := s.userRepo.GetByID(ctx, userID)
-	if err == nil && user.IsAdmin() {
+	if isAdminUser(ctx, s.userRepo, userID) {
 		return true
 	}
 
 	// Проверяем, является ли пользователь участником проекта
-	_, err = s.projectRepo.GetMember(ctx, projectID, userID)
-	return err == nil
+	member, err := s.projectRepo.GetMember(ctx, projectID, userID)
+	return err == nil && member != nil
+}
+
+// canEditProject проверяет, может ли пользователь изменять задачи проекта
+func (s *ProjectService) canEditProject(ctx context.Context, projectID string, userID string) bool {
+	// Администраторы могут изменять задачи всех проектов
+	if isAdminUser(ctx, s.userRepo, userID) {
+		return true
+	}
+
+	// Проверяем, является ли пользователь участником проекта
+	member, err := s.projectRepo.GetMember(ctx, projectID, userID)
+	if err != nil || member == nil {
+		return false
+	}
+
+	// Наблюдатели могут только просматривать задачи
+	return member.Role == domain.ProjectRoleOwner ||
+		member.Role == domain.ProjectRoleManager ||
+		member.Role == domain.ProjectRoleMember
 }
 
 // canManageProject проверяет, может ли пользователь управлять проектом
 func (s *ProjectService) canManageProject(ctx context.Context, projectID string, userID string) bool {
 	// Администраторы могут управлять всеми проектами
-	user, err := s.userRepo.GetByID(ctx, userID)
-	if err == nil && user.IsAdmin() {
+	if isAdminUser(ctx, s.userRepo, userID) {
 		return true
 	}
 
 	// Проверяем, является ли пользователь владельцем или менеджером проекта
 	member, err := s.projectRepo.GetMember(ctx, projectID, userID)
-	if err != nil {
+	if err != nil || member == nil {
 		return false
 	}
 
@@ -790,7 +1428,7 @@ func (s *ProjectService) canManageProject(ctx context.Context, projectID string,
 }
 
 // GetProjectMetrics возвращает метрики проекта
-func (s *ProjectService) GetProjectMetrics(ctx context.Context, projectID string, userID string) (*domain.ProjectMetrics, error) {
+func (s *ProjectService) GetProjectMetrics(ctx context.Context, projectID string) (*domain.ProjectMetrics, error) {
 	// Проверяем, существует ли проект
 	if _, err := s.projectRepo.GetByID(ctx, projectID); err != nil {
 		s.logger.Error("Failed to get project by ID for metrics", err, map[string]interface{}{
@@ -799,13 +1437,8 @@ func (s *ProjectService) GetProjectMetrics(ctx context.Context, projectID string
 		return nil, ErrProjectNotFound
 	}
 
-	// Проверяем доступ пользователя к проекту
-	if !s.hasAccessToProject(ctx, projectID, userID) {
-		return nil, ErrInsufficientRights
-	}
-
 	// Получаем метрики проекта
-	metrics, err := s.taskRepo.GetTaskMetrics(ctx, projectID)
+	metrics, err := s.getMetrics(ctx, projectID)
 	if err != nil {
 		s.logger.Error("Failed to get project metrics", err, map[string]interface{}{
 			"project_id": projectID,
@@ -815,3 +1448,95 @@ func (s *ProjectService) GetProjectMetrics(ctx context.Context, projectID string
 
 	return metrics, nil
 }
+
+// GetMetricsHistory возвращает ежедневные снимки метрик проекта за последние days дней
+func (s *ProjectService) GetMetricsHistory(ctx context.Context, projectID string, days int) ([]*domain.ProjectMetricsSnapshot, error) {
+	// Проверяем, существует ли проект
+	project, err := s.projectRepo.GetByID(ctx, projectID)
+	if err != nil || project == nil {
+		return nil, ErrProjectNotFound
+	}
+
+	now := time.Now()
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()).AddDate(0, 0, 1-days)
+
+	history, err := s.metricsRepo.GetHistory(ctx, projectID, from)
+	if err != nil {
+		s.logger.Error("Failed to get project metrics history", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return nil, err
+	}
+
+	return history, nil
+}
+
+// getMetrics возвращает материализованные метрики проекта.
+// Если метрики еще не рассчитывались, они пересчитываются сразу
+func (s *ProjectService) getMetrics(ctx context.Context, projectID string) (*domain.ProjectMetrics, error) {
+	metrics, err := s.metricsRepo.Get(ctx, projectID)
+	if err != nil {
+		s.logger.Warn("Failed to get materialized project metrics", map[string]interface{}{
+			"project_id": projectID,
+		}, map[string]interface{}{
+			"error": err,
+		})
+	}
+	if metrics != nil {
+		return metrics, nil
+	}
+
+	return s.RefreshMetrics(ctx, projectID)
+}
+
+// RefreshMetrics пересчитывает метрики проекта по задачам и сохраняет их.
+// Пометка устаревания снимается до расчета, поэтому изменения задач во время
+// расчета снова пометят метрики и они будут пересчитаны планировщиком
+func (s *ProjectService) RefreshMetrics(ctx context.Context, projectID string) (*domain.ProjectMetrics, error) {
+	refreshedAt := time.Now()
+
+	if err := s.metricsRepo.ClearDirty(ctx, projectID); err != nil {
+		return nil, err
+	}
+
+	metrics, err := s.taskRepo.GetTaskMetrics(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	metrics.RefreshedAt = &refreshedAt
+
+	// Ошибка сохранения не мешает вернуть рассчитанные метрики
+	if err := s.metricsRepo.Save(ctx, projectID, metrics); err != nil {
+		s.logger.Warn("Failed to save project metrics", map[string]interface{}{
+			"project_id": projectID,
+		}, map[string]interface{}{
+			"error": err,
+		})
+	}
+
+	return metrics, nil
+}
+
+// logMembershipChange записывает изменение состава участников проекта в историю
+func (s *ProjectService) logMembershipChange(ctx context.Context, projectID, memberID, actorID string, action domain.MembershipAction, oldRole, newRole *domain.ProjectRole) {
+	entry := &domain.MembershipHistory{
+		ID:        uuid.New().String(),
+		ProjectID: projectID,
+		UserID:    memberID,
+		ActorID:   actorID,
+		Action:    action,
+		OldRole:   oldRole,
+		NewRole:   newRole,
+		ChangedAt: time.Now(),
+	}
+
+	if err := s.projectRepo.LogMembershipHistory(ctx, entry); err != nil {
+		s.logger.Warn("Failed to log membership history", map[string]interface{}{
+			"project_id": projectID,
+			"user_id":    memberID,
+			"action":     action,
+		}, map[string]interface{}{
+			"error": err,
+		})
+	}
+}
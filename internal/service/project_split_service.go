@@ -0,0 +1,250 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/internal/messaging"
+	"github.com/nurlyy/task_manager/internal/repository"
+	"github.com/nurlyy/task_manager/internal/repository/cache"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
+	"github.com/nurlyy/task_manager/pkg/logger"
+)
+
+var (
+	ErrInvalidProjectSplit  = apperrors.New(apperrors.CodeInvalidSplit, "exactly one of tag or epic_id must be specified")
+	ErrProjectSplitEmpty    = apperrors.New(apperrors.CodeSplitEmpty, "no tasks match the split selection")
+	ErrProjectSplitTooLarge = apperrors.New(apperrors.CodeSplitTooLarge, "too many tasks to split at once")
+)
+
+// projectSplitMaxTasks ограничивает число задач, переносимых за одно разделение
+const projectSplitMaxTasks = 1000
+
+// ProjectSplitService представляет бизнес-логику выделения части задач проекта в новый проект
+type ProjectSplitService struct {
+	projectRepo repository.ProjectRepository
+	taskRepo    repository.TaskRepository
+	epicRepo    repository.EpicRepository
+	cacheRepo   *cache.RedisRepository
+	producer    *messaging.KafkaProducer
+	projectSvc  *ProjectService
+	logger      logger.Logger
+}
+
+// NewProjectSplitService создает новый экземпляр ProjectSplitService
+func NewProjectSplitService(
+	projectRepo repository.ProjectRepository,
+	taskRepo repository.TaskRepository,
+	epicRepo repository.EpicRepository,
+	cacheRepo *cache.RedisRepository,
+	producer *messaging.KafkaProducer,
+	projectSvc *ProjectService,
+	logger logger.Logger,
+) *ProjectSplitService {
+	return &ProjectSplitService{
+		projectRepo: projectRepo,
+		taskRepo:    taskRepo,
+		epicRepo:    epicRepo,
+		cacheRepo:   cacheRepo,
+		producer:    producer,
+		projectSvc:  projectSvc,
+		logger:      logger,
+	}
+}
+
+// Split переносит задачи проекта с указанным тегом или из указанного эпика в новый проект.
+// При dry_run возвращает только список задач, которые будут перенесены
+func (s *ProjectSplitService) Split(ctx context.Context, projectID string, req domain.ProjectSplitRequest, userID string) (*domain.ProjectSplitResult, error) {
+	if (req.Tag == nil) == (req.EpicID == nil) {
+		return nil, ErrInvalidProjectSplit
+	}
+
+	source, err := s.projectRepo.GetByID(ctx, projectID)
+	if err != nil || source == nil {
+		return nil, ErrProjectNotFound
+	}
+
+	if source.Status == domain.ProjectStatusArchived {
+		return nil, ErrProjectArchived
+	}
+
+	filter := repository.TaskFilter{
+		ProjectIDs: []string{projectID},
+		Limit:      projectSplitMaxTasks + 1,
+	}
+	if req.EpicID != nil {
+		epic, err := s.epicRepo.GetByID(ctx, *req.EpicID)
+		if err != nil || epic == nil {
+			return nil, ErrEpicNotFound
+		}
+		if epic.ProjectID != projectID {
+			return nil, ErrEpicProjectMismatch
+		}
+		filter.EpicID = req.EpicID
+	} else {
+		filter.Tags = []string{*req.Tag}
+	}
+
+	orderBy := "rank"
+	filter.OrderBy = &orderBy
+	tasks, err := s.taskRepo.List(ctx, filter)
+	if err != nil {
+		s.logger.Error("Failed to list tasks for project split", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return nil, err
+	}
+	if len(tasks) > projectSplitMaxTasks {
+		return nil, ErrProjectSplitTooLarge
+	}
+
+	members, err := s.projectRepo.GetMembers(ctx, projectID)
+	if err != nil {
+		s.logger.Error("Failed to get project members for split", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return nil, err
+	}
+
+	result := &domain.ProjectSplitResult{
+		DryRun:      req.DryRun,
+		EpicID:      req.EpicID,
+		MemberCount: len(members),
+		TaskCount:   len(tasks),
+		Tasks:       make([]domain.ProjectSplitTask, 0, len(tasks)),
+	}
+	taskIDs := make([]string, 0, len(tasks))
+	for _, task := range tasks {
+		taskIDs = append(taskIDs, task.ID)
+		result.Tasks = append(result.Tasks, domain.ProjectSplitTask{
+			ID:     task.ID,
+			Title:  task.Title,
+			Status: task.Status,
+			// При отборе по тегу задачи выходят из эпиков исходного проекта
+			LeavesEpic: task.EpicID != nil && req.EpicID == nil,
+		})
+	}
+
+	if req.DryRun {
+		return result, nil
+	}
+
+	if len(tasks) == 0 {
+		return nil, ErrProjectSplitEmpty
+	}
+
+	// Новый проект наследует владельца, статус и сроки исходного
+	now := time.Now()
+	project := &domain.Project{
+		ID:          uuid.New().String(),
+		Name:        req.Name,
+		Description: req.Description,
+		Status:      source.Status,
+		CreatedBy:   source.CreatedBy,
+		StartDate:   source.StartDate,
+		EndDate:     source.EndDate,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	split := &repository.ProjectSplit{
+		SourceProjectID: projectID,
+		Project:         project,
+		TaskIDs:         taskIDs,
+		EpicID:          req.EpicID,
+		UserID:          userID,
+	}
+	if err := s.projectRepo.Split(ctx, split); err != nil {
+		s.logger.Error("Failed to split project", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return nil, err
+	}
+
+	s.logger.Info("Project split", map[string]interface{}{
+		"project_id":     projectID,
+		"new_project_id": project.ID,
+		"tasks":          len(taskIDs),
+	})
+
+	for _, member := range members {
+		role := member.Role
+		s.projectSvc.logMembershipChange(ctx, project.ID, member.UserID, userID, domain.MembershipActionAdded, nil, &role)
+	}
+
+	s.afterSplit(ctx, source, project, tasks)
+
+	resp := project.ToResponse()
+	result.Project = &resp
+
+	return result, nil
+}
+
+// afterSplit сбрасывает кэш и публикует события о новом проекте и перенесенных задачах
+func (s *ProjectSplitService) afterSplit(ctx context.Context, source *domain.Project, project *domain.Project, tasks []*domain.Task) {
+	if err := s.cacheRepo.Delete(ctx, "project:"+source.ID); err != nil {
+		s.logger.Warn("Failed to delete project from cache", map[string]interface{}{
+			"id": source.ID,
+		}, map[string]interface{}{
+			"error": err,
+		})
+	}
+
+	projectEvent := &messaging.ProjectEvent{
+		ID:          project.ID,
+		Name:        project.Name,
+		Description: project.Description,
+		Status:      string(project.Status),
+		CreatedBy:   project.CreatedBy,
+		CreatedAt:   project.CreatedAt,
+		UpdatedAt:   project.UpdatedAt,
+		Type:        messaging.EventTypeProjectCreated,
+	}
+	if err := s.producer.PublishProjectCreated(ctx, projectEvent); err != nil {
+		s.logger.Warn("Failed to publish project creation event", map[string]interface{}{
+			"project_id": project.ID,
+		}, map[string]interface{}{
+			"error": err,
+		})
+	}
+
+	taskKeys := make([]string, len(tasks))
+	for i, task := range tasks {
+		taskKeys[i] = "task:" + task.ID
+	}
+	if err := s.cacheRepo.DeleteMany(ctx, taskKeys); err != nil {
+		s.logger.Warn("Failed to delete tasks from cache", map[string]interface{}{
+			"project_id": project.ID,
+		}, map[string]interface{}{
+			"error": err,
+		})
+	}
+
+	for _, task := range tasks {
+		changes := map[string]interface{}{
+			"project_id": map[string]interface{}{"old": source.ID, "new": project.ID},
+		}
+		event := &messaging.TaskEvent{
+			ID:          task.ID,
+			Title:       task.Title,
+			ProjectID:   project.ID,
+			Status:      string(task.Status),
+			Priority:    string(task.Priority),
+			AssigneeID:  task.AssigneeID,
+			AssigneeIDs: task.AssigneeIDs,
+			UpdatedAt:   project.UpdatedAt,
+			Type:        messaging.EventTypeTaskMoved,
+			Changes:     changes,
+		}
+		if err := s.producer.PublishTaskMoved(ctx, event, changes); err != nil {
+			s.logger.Warn("Failed to publish task moved event", map[string]interface{}{
+				"task_id": task.ID,
+			}, map[string]interface{}{
+				"error": err,
+			})
+		}
+	}
+}
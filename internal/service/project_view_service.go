@@ -0,0 +1,207 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/internal/repository"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
+	"github.com/nurlyy/task_manager/pkg/logger"
+)
+
+var (
+	ErrProjectViewNotFound = apperrors.New(apperrors.CodeProjectViewNotFound, "project view not found")
+	ErrProjectViewExists   = apperrors.New(apperrors.CodeProjectViewExists, "project view with this name already exists")
+)
+
+// ProjectViewService представляет бизнес-логику сохраненных представлений проектов
+type ProjectViewService struct {
+	viewRepo    repository.ProjectViewRepository
+	projectRepo repository.ProjectRepository
+	logger      logger.Logger
+}
+
+// NewProjectViewService создает новый экземпляр ProjectViewService
+func NewProjectViewService(
+	viewRepo repository.ProjectViewRepository,
+	projectRepo repository.ProjectRepository,
+	logger logger.Logger,
+) *ProjectViewService {
+	return &ProjectViewService{
+		viewRepo:    viewRepo,
+		projectRepo: projectRepo,
+		logger:      logger,
+	}
+}
+
+// List возвращает представления проекта
+func (s *ProjectViewService) List(ctx context.Context, projectID string) ([]*domain.ProjectView, error) {
+	return s.viewRepo.ListByProject(ctx, projectID)
+}
+
+// GetByID возвращает представление проекта
+func (s *ProjectViewService) GetByID(ctx context.Context, projectID, id string) (*domain.ProjectView, error) {
+	return s.getView(ctx, projectID, id)
+}
+
+// GetDefault возвращает представление, назначенное роли пользователя в проекте.
+// Если представление не назначено или пользователь не участник проекта (администратор),
+// возвращается встроенное представление
+func (s *ProjectViewService) GetDefault(ctx context.Context, projectID string, userID string) (*domain.ProjectView, error) {
+	member, err := s.projectRepo.GetMember(ctx, projectID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if member == nil {
+		return domain.DefaultProjectView(projectID), nil
+	}
+
+	view, err := s.viewRepo.GetDefaultForRole(ctx, projectID, member.Role)
+	if err != nil {
+		return nil, err
+	}
+	if view == nil {
+		return domain.DefaultProjectView(projectID), nil
+	}
+
+	return view, nil
+}
+
+// Create создает представление проекта. Роли по умолчанию переходят к новому представлению
+func (s *ProjectViewService) Create(ctx context.Context, projectID string, req domain.ProjectViewRequest, userID string) (*domain.ProjectView, error) {
+	if err := s.checkName(ctx, projectID, "", req.Name); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	view := &domain.ProjectView{
+		ID:        uuid.New().String(),
+		ProjectID: projectID,
+		CreatedBy: userID,
+		CreatedAt: now,
+	}
+	applyViewRequest(view, req, now)
+
+	if err := s.viewRepo.Create(ctx, view); err != nil {
+		s.logger.Error("Failed to create project view", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return nil, err
+	}
+
+	s.logger.Info("Project view created", map[string]interface{}{
+		"view_id":       view.ID,
+		"project_id":    projectID,
+		"default_roles": view.DefaultRoles,
+		"user_id":       userID,
+	})
+
+	return view, nil
+}
+
+// Update заменяет настройки представления проекта
+func (s *ProjectViewService) Update(ctx context.Context, projectID, id string, req domain.ProjectViewRequest) (*domain.ProjectView, error) {
+	view, err := s.getView(ctx, projectID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkName(ctx, projectID, view.ID, req.Name); err != nil {
+		return nil, err
+	}
+
+	applyViewRequest(view, req, time.Now())
+
+	if err := s.viewRepo.Update(ctx, view); err != nil {
+		s.logger.Error("Failed to update project view", err, map[string]interface{}{
+			"id": id,
+		})
+		return nil, err
+	}
+
+	return view, nil
+}
+
+// Delete удаляет представление проекта. Роли, которым оно было назначено,
+// получают встроенное представление
+func (s *ProjectViewService) Delete(ctx context.Context, projectID, id string) error {
+	if _, err := s.getView(ctx, projectID, id); err != nil {
+		return err
+	}
+
+	if err := s.viewRepo.Delete(ctx, id); err != nil {
+		s.logger.Error("Failed to delete project view", err, map[string]interface{}{
+			"id": id,
+		})
+		return err
+	}
+
+	return nil
+}
+
+// getView возвращает представление, если оно принадлежит проекту
+func (s *ProjectViewService) getView(ctx context.Context, projectID, id string) (*domain.ProjectView, error) {
+	view, err := s.viewRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if view == nil || view.ProjectID != projectID {
+		return nil, ErrProjectViewNotFound
+	}
+	return view, nil
+}
+
+// checkName проверяет уникальность имени представления в проекте
+func (s *ProjectViewService) checkName(ctx context.Context, projectID, viewID, name string) error {
+	existing, err := s.viewRepo.GetByName(ctx, projectID, name)
+	if err != nil {
+		return err
+	}
+	if existing != nil && existing.ID != viewID {
+		return ErrProjectViewExists
+	}
+	return nil
+}
+
+// applyViewRequest переносит данные запроса в представление, подставляя
+// значения встроенного представления вместо незаданных
+func applyViewRequest(view *domain.ProjectView, req domain.ProjectViewRequest, now time.Time) {
+	defaults := domain.DefaultProjectView(view.ProjectID)
+
+	view.Name = req.Name
+	view.Layout = req.Layout
+	view.Filters = req.Filters
+	view.SortBy = req.SortBy
+	if view.SortBy == "" {
+		view.SortBy = defaults.SortBy
+	}
+	view.SortDir = req.SortDir
+	if view.SortDir == "" {
+		view.SortDir = defaults.SortDir
+	}
+	view.GroupBy = req.GroupBy
+	if view.GroupBy == "" {
+		view.GroupBy = defaults.GroupBy
+	}
+
+	view.DefaultRoles = make([]domain.ProjectRole, 0, len(req.DefaultRoles))
+	for _, role := range req.DefaultRoles {
+		if !containsRole(view.DefaultRoles, role) {
+			view.DefaultRoles = append(view.DefaultRoles, role)
+		}
+	}
+	view.UpdatedAt = now
+}
+
+// containsRole проверяет наличие роли в списке
+func containsRole(roles []domain.ProjectRole, role domain.ProjectRole) bool {
+	for _, r := range roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
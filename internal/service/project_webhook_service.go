@@ -0,0 +1,391 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/internal/repository"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
+	"github.com/nurlyy/task_manager/pkg/jsonpath"
+	"github.com/nurlyy/task_manager/pkg/logger"
+)
+
+var (
+	ErrWebhookNotFound         = apperrors.New(apperrors.CodeWebhookNotFound, "webhook not found")
+	ErrWebhookExists           = apperrors.New(apperrors.CodeWebhookExists, "webhook with this name already exists")
+	ErrWebhookDisabled         = apperrors.New(apperrors.CodeWebhookDisabled, "webhook is disabled")
+	ErrWebhookSignatureInvalid = apperrors.New(apperrors.CodeInvalidSignature, "invalid webhook signature")
+	ErrWebhookPayloadInvalid   = apperrors.New(apperrors.CodeInvalidFormat, "invalid webhook payload")
+)
+
+// webhookSignaturePrefix - префикс значения заголовка подписи тела запроса: "sha256=<hex>"
+const webhookSignaturePrefix = "sha256="
+
+// ProjectWebhookService представляет бизнес-логику входящих вебхуков проектов
+type ProjectWebhookService struct {
+	webhookRepo repository.ProjectWebhookRepository
+	taskSvc     *TaskService
+	baseURL     string
+	logger      logger.Logger
+}
+
+// NewProjectWebhookService создает новый экземпляр ProjectWebhookService
+func NewProjectWebhookService(
+	webhookRepo repository.ProjectWebhookRepository,
+	taskSvc *TaskService,
+	baseURL string,
+	logger logger.Logger,
+) *ProjectWebhookService {
+	return &ProjectWebhookService{
+		webhookRepo: webhookRepo,
+		taskSvc:     taskSvc,
+		baseURL:     strings.TrimRight(baseURL, "/"),
+		logger:      logger,
+	}
+}
+
+// List возвращает вебхуки проекта
+func (s *ProjectWebhookService) List(ctx context.Context, projectID string) ([]*domain.ProjectWebhook, error) {
+	webhooks, err := s.webhookRepo.ListByProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, webhook := range webhooks {
+		webhook.URL = s.signedURL(webhook)
+	}
+	return webhooks, nil
+}
+
+// GetByID возвращает вебхук проекта
+func (s *ProjectWebhookService) GetByID(ctx context.Context, projectID, id string) (*domain.ProjectWebhook, error) {
+	webhook, err := s.getWebhook(ctx, projectID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	webhook.URL = s.signedURL(webhook)
+	return webhook, nil
+}
+
+// Create создает вебхук проекта. Задачи по вебхуку создаются от имени создавшего его пользователя
+func (s *ProjectWebhookService) Create(ctx context.Context, projectID string, req domain.ProjectWebhookRequest, userID string) (*domain.ProjectWebhook, error) {
+	if err := s.checkWebhook(ctx, projectID, "", req); err != nil {
+		return nil, err
+	}
+
+	secret, err := generateWebhookSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	webhook := &domain.ProjectWebhook{
+		ID:        uuid.New().String(),
+		ProjectID: projectID,
+		Secret:    secret,
+		Enabled:   true,
+		CreatedBy: userID,
+		CreatedAt: now,
+	}
+	applyWebhookRequest(webhook, req, now)
+
+	if err := s.webhookRepo.Create(ctx, webhook); err != nil {
+		s.logger.Error("Failed to create project webhook", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return nil, err
+	}
+
+	s.logger.Info("Project webhook created", map[string]interface{}{
+		"webhook_id": webhook.ID,
+		"project_id": projectID,
+		"user_id":    userID,
+	})
+
+	webhook.URL = s.signedURL(webhook)
+	return webhook, nil
+}
+
+// Update заменяет настройки вебхука проекта
+func (s *ProjectWebhookService) Update(ctx context.Context, projectID, id string, req domain.ProjectWebhookRequest) (*domain.ProjectWebhook, error) {
+	webhook, err := s.getWebhook(ctx, projectID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkWebhook(ctx, projectID, webhook.ID, req); err != nil {
+		return nil, err
+	}
+
+	applyWebhookRequest(webhook, req, time.Now())
+
+	if err := s.webhookRepo.Update(ctx, webhook); err != nil {
+		s.logger.Error("Failed to update project webhook", err, map[string]interface{}{
+			"id": id,
+		})
+		return nil, err
+	}
+
+	webhook.URL = s.signedURL(webhook)
+	return webhook, nil
+}
+
+// RotateSecret выдает вебхуку новый ключ подписи. Старые подпись и URL перестают приниматься
+func (s *ProjectWebhookService) RotateSecret(ctx context.Context, projectID, id string, userID string) (*domain.ProjectWebhook, error) {
+	webhook, err := s.getWebhook(ctx, projectID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := generateWebhookSecret()
+	if err != nil {
+		return nil, err
+	}
+	webhook.Secret = secret
+	webhook.UpdatedAt = time.Now()
+
+	if err := s.webhookRepo.Update(ctx, webhook); err != nil {
+		s.logger.Error("Failed to rotate project webhook secret", err, map[string]interface{}{
+			"id": id,
+		})
+		return nil, err
+	}
+
+	s.logger.Info("Project webhook secret rotated", map[string]interface{}{
+		"webhook_id": webhook.ID,
+		"user_id":    userID,
+	})
+
+	webhook.URL = s.signedURL(webhook)
+	return webhook, nil
+}
+
+// Delete удаляет вебхук проекта
+func (s *ProjectWebhookService) Delete(ctx context.Context, projectID, id string) error {
+	if _, err := s.getWebhook(ctx, projectID, id); err != nil {
+		return err
+	}
+
+	if err := s.webhookRepo.Delete(ctx, id); err != nil {
+		s.logger.Error("Failed to delete project webhook", err, map[string]interface{}{
+			"id": id,
+		})
+		return err
+	}
+
+	return nil
+}
+
+// Receive обрабатывает входящий запрос вебхука и создает задачу.
+// Запрос принимается, если заголовок подписи содержит HMAC-SHA256 тела запроса
+// либо URL содержит подпись ID вебхука, выданную в поле url
+func (s *ProjectWebhookService) Receive(ctx context.Context, id string, body []byte, signature, urlSignature string) (*domain.TaskResponse, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, ErrWebhookNotFound
+	}
+
+	webhook, err := s.webhookRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if webhook == nil {
+		return nil, ErrWebhookNotFound
+	}
+
+	if !verifyWebhookSignature(webhook, body, signature, urlSignature) {
+		s.logger.Warn("Rejected webhook request with invalid signature", map[string]interface{}{
+			"webhook_id": webhook.ID,
+		})
+		return nil, ErrWebhookSignatureInvalid
+	}
+	if !webhook.Enabled {
+		return nil, ErrWebhookDisabled
+	}
+
+	var payload interface{}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrWebhookPayloadInvalid, err)
+	}
+
+	req := s.buildTaskRequest(webhook, payload, body)
+
+	task, err := s.taskSvc.Create(ctx, req, webhook.CreatedBy)
+	if err != nil {
+		// Создатель вебхука покинул проект: задачи от его имени больше не создаются
+		if errors.Is(err, ErrProjectNotFound) {
+			s.logger.Warn("Webhook creator has no access to the project", map[string]interface{}{
+				"webhook_id": webhook.ID,
+				"user_id":    webhook.CreatedBy,
+			})
+		}
+		return nil, err
+	}
+
+	s.logger.Info("Task created from webhook", map[string]interface{}{
+		"webhook_id": webhook.ID,
+		"project_id": webhook.ProjectID,
+		"task_id":    task.ID,
+	})
+
+	return task, nil
+}
+
+// buildTaskRequest формирует запрос на создание задачи по правилам сопоставления вебхука
+func (s *ProjectWebhookService) buildTaskRequest(webhook *domain.ProjectWebhook, payload interface{}, body []byte) domain.TaskCreateRequest {
+	mapping := webhook.Mapping
+
+	req := domain.TaskCreateRequest{
+		Title:       truncateRunes(strings.Join(strings.Fields(jsonpath.Expand(mapping.Title, payload)), " "), 200),
+		Description: strings.TrimSpace(jsonpath.Expand(mapping.Description, payload)),
+		ProjectID:   webhook.ProjectID,
+		AssigneeID:  mapping.AssigneeID,
+		Tags:        []string{},
+	}
+
+	// Без описания в задачу попадает исходный запрос, чтобы не потерять данные оповещения
+	if req.Description == "" {
+		pretty, err := json.MarshalIndent(payload, "", "  ")
+		if err != nil {
+			pretty = body
+		}
+		req.Description = "```json\n" + string(pretty) + "\n```"
+	}
+
+	if mapping.Priority != "" {
+		value := strings.TrimSpace(jsonpath.Expand(mapping.Priority, payload))
+		if priority, ok := mapping.PriorityMap[value]; ok {
+			req.Priority = priority
+		} else if priority, ok := mapping.PriorityMap[strings.ToLower(value)]; ok {
+			req.Priority = priority
+		} else {
+			switch priority := domain.TaskPriority(strings.ToLower(value)); priority {
+			case domain.TaskPriorityLow, domain.TaskPriorityMedium, domain.TaskPriorityHigh, domain.TaskPriorityCritical:
+				req.Priority = priority
+			}
+		}
+	}
+
+	for _, tag := range mapping.Tags {
+		if tag = truncateRunes(strings.TrimSpace(jsonpath.Expand(tag, payload)), 50); tag != "" && !containsString(req.Tags, tag) {
+			req.Tags = append(req.Tags, tag)
+		}
+	}
+
+	if mapping.DueInHours != nil {
+		dueDate := time.Now().Add(time.Duration(*mapping.DueInHours) * time.Hour)
+		req.DueDate = &dueDate
+	}
+
+	return req
+}
+
+// getWebhook возвращает вебхук, если он принадлежит проекту
+func (s *ProjectWebhookService) getWebhook(ctx context.Context, projectID, id string) (*domain.ProjectWebhook, error) {
+	webhook, err := s.webhookRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if webhook == nil || webhook.ProjectID != projectID {
+		return nil, ErrWebhookNotFound
+	}
+	return webhook, nil
+}
+
+// checkWebhook проверяет уникальность имени вебхука, пути JSONPath в шаблонах и исполнителя
+func (s *ProjectWebhookService) checkWebhook(ctx context.Context, projectID, webhookID string, req domain.ProjectWebhookRequest) error {
+	existing, err := s.webhookRepo.GetByName(ctx, projectID, req.Name)
+	if err != nil {
+		return err
+	}
+	if existing != nil && existing.ID != webhookID {
+		return ErrWebhookExists
+	}
+
+	fields := []string{"mapping.title", "mapping.description", "mapping.priority"}
+	templates := []string{req.Mapping.Title, req.Mapping.Description, req.Mapping.Priority}
+	for i, tag := range req.Mapping.Tags {
+		fields = append(fields, fmt.Sprintf("mapping.tags[%d]", i))
+		templates = append(templates, tag)
+	}
+
+	var violations []domain.FieldViolation
+	for i, template := range templates {
+		if err := jsonpath.ValidateTemplate(template); err != nil {
+			violations = append(violations, domain.FieldViolation{
+				Field:   fields[i],
+				Message: "Template contains an invalid JSONPath expression",
+			})
+		}
+	}
+	if len(violations) > 0 {
+		return &TaskValidationError{Violations: violations}
+	}
+
+	if req.Mapping.AssigneeID != nil {
+		return s.taskSvc.checkAssigneesMembership(ctx, projectID, []string{*req.Mapping.AssigneeID})
+	}
+	return nil
+}
+
+// signedURL формирует URL вебхука с подписью ID, которую не нужно вычислять на стороне отправителя
+func (s *ProjectWebhookService) signedURL(webhook *domain.ProjectWebhook) string {
+	return fmt.Sprintf("%s/api/v1/hooks/%s?signature=%s", s.baseURL, webhook.ID, webhookHMAC(webhook.Secret, []byte(webhook.ID)))
+}
+
+// applyWebhookRequest переносит данные запроса в вебхук
+func applyWebhookRequest(webhook *domain.ProjectWebhook, req domain.ProjectWebhookRequest, now time.Time) {
+	webhook.Name = req.Name
+	webhook.Mapping = req.Mapping
+	if req.Enabled != nil {
+		webhook.Enabled = *req.Enabled
+	}
+	webhook.UpdatedAt = now
+}
+
+// verifyWebhookSignature проверяет подпись тела запроса или подпись из URL
+func verifyWebhookSignature(webhook *domain.ProjectWebhook, body []byte, signature, urlSignature string) bool {
+	if signature != "" {
+		signature = strings.TrimPrefix(strings.TrimSpace(signature), webhookSignaturePrefix)
+		return hmac.Equal([]byte(signature), []byte(webhookHMAC(webhook.Secret, body)))
+	}
+	if urlSignature != "" {
+		return hmac.Equal([]byte(urlSignature), []byte(webhookHMAC(webhook.Secret, []byte(webhook.ID))))
+	}
+	return false
+}
+
+// webhookHMAC возвращает HMAC-SHA256 данных в шестнадцатеричном виде
+func webhookHMAC(secret string, data []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(data)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// generateWebhookSecret генерирует ключ подписи вебхука
+func generateWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// truncateRunes обрезает строку до указанного количества символов
+func truncateRunes(s string, limit int) string {
+	if utf8.RuneCountInString(s) <= limit {
+		return s
+	}
+	return string([]rune(s)[:limit])
+}
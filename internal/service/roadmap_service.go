@@ -0,0 +1,335 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"html/template"
+	"strings"
+	"time"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/internal/repository"
+	"github.com/nurlyy/task_manager/internal/repository/cache"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
+	"github.com/nurlyy/task_manager/pkg/logger"
+)
+
+var (
+	ErrRoadmapNotFound = apperrors.New(apperrors.CodeRoadmapNotFound, "project roadmap not found")
+)
+
+const (
+	// RoadmapCacheTTL определяет, сколько опубликованная дорожная карта хранится в HTTP-кэше
+	RoadmapCacheTTL = 5 * time.Minute
+	// roadmapMaxItems ограничивает число элементов публичной дорожной карты
+	roadmapMaxItems = 200
+	// roadmapPath определяет путь публичного обработчика дорожной карты
+	roadmapPath = "/api/v1/public/roadmap/"
+)
+
+// roadmapStageLabels содержит названия этапов для HTML-представления
+var roadmapStageLabels = map[domain.RoadmapStage]string{
+	domain.RoadmapStagePlanned:    "Запланировано",
+	domain.RoadmapStageInProgress: "В работе",
+	domain.RoadmapStageShipped:    "Готово",
+}
+
+// roadmapTemplate формирует HTML-представление дорожной карты
+var roadmapTemplate = template.Must(template.New("roadmap").Parse(`<!DOCTYPE html>
+<html lang="ru">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<style>
+body{font-family:sans-serif;max-width:960px;margin:2rem auto;padding:0 1rem;color:#222}
+.stages{display:flex;gap:1rem;flex-wrap:wrap}
+.stage{flex:1;min-width:240px}
+.item{border:1px solid #ddd;border-radius:6px;padding:.5rem .75rem;margin-bottom:.5rem}
+.target{color:#777;font-size:.85rem}
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p>{{.ProjectName}}</p>
+{{if .Description}}<p>{{.Description}}</p>{{end}}
+<div class="stages">
+{{range .Stages}}<section class="stage">
+<h2>{{.Label}}</h2>
+{{range .Items}}<div class="item">{{.Title}}{{if .Target}}<div class="target">{{.Target}}</div>{{end}}</div>
+{{else}}<p class="target">Пока пусто</p>
+{{end}}</section>
+{{end}}</div>
+<p class="target">Обновлено {{.GeneratedAt}}</p>
+</body>
+</html>
+`))
+
+// RoadmapService представляет бизнес-логику публичной дорожной карты проекта
+type RoadmapService struct {
+	roadmapRepo repository.RoadmapRepository
+	projectRepo repository.ProjectRepository
+	cacheRepo   *cache.RedisRepository
+	baseURL     string
+	logger      logger.Logger
+}
+
+// NewRoadmapService создает новый экземпляр RoadmapService
+func NewRoadmapService(
+	roadmapRepo repository.RoadmapRepository,
+	projectRepo repository.ProjectRepository,
+	cacheRepo *cache.RedisRepository,
+	baseURL string,
+	logger logger.Logger,
+) *RoadmapService {
+	return &RoadmapService{
+		roadmapRepo: roadmapRepo,
+		projectRepo: projectRepo,
+		cacheRepo:   cacheRepo,
+		baseURL:     strings.TrimRight(baseURL, "/"),
+		logger:      logger,
+	}
+}
+
+// GetRoadmap возвращает настройки публичной дорожной карты проекта
+func (s *RoadmapService) GetRoadmap(ctx context.Context, projectID string) (*domain.ProjectRoadmap, error) {
+	roadmap, err := s.roadmapRepo.GetRoadmap(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if roadmap == nil {
+		return nil, ErrRoadmapNotFound
+	}
+
+	roadmap.URL = s.roadmapURL(roadmap.PublicKey)
+	return roadmap, nil
+}
+
+// SaveRoadmap включает или обновляет публичную дорожную карту проекта
+func (s *RoadmapService) SaveRoadmap(ctx context.Context, projectID string, req domain.ProjectRoadmapRequest, userID string) (*domain.ProjectRoadmap, error) {
+	roadmap, err := s.roadmapRepo.GetRoadmap(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if roadmap == nil {
+		key, err := generateRoadmapKey()
+		if err != nil {
+			return nil, err
+		}
+		roadmap = &domain.ProjectRoadmap{
+			ProjectID: projectID,
+			PublicKey: key,
+			Enabled:   true,
+			CreatedBy: userID,
+			CreatedAt: now,
+		}
+	}
+
+	roadmap.Title = req.Title
+	roadmap.Description = req.Description
+	roadmap.Label = strings.TrimSpace(req.Label)
+	if roadmap.Label == "" {
+		roadmap.Label = domain.DefaultRoadmapLabel
+	}
+	if req.Enabled != nil {
+		roadmap.Enabled = *req.Enabled
+	}
+	roadmap.UpdatedAt = now
+
+	if err := s.roadmapRepo.SaveRoadmap(ctx, roadmap); err != nil {
+		s.logger.Error("Failed to save project roadmap", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return nil, err
+	}
+
+	s.invalidateCache(ctx, roadmap.PublicKey)
+
+	roadmap.URL = s.roadmapURL(roadmap.PublicKey)
+	return roadmap, nil
+}
+
+// RotateRoadmapKey выдает дорожной карте новый публичный ключ; старая ссылка перестает работать
+func (s *RoadmapService) RotateRoadmapKey(ctx context.Context, projectID string) (*domain.ProjectRoadmap, error) {
+	roadmap, err := s.roadmapRepo.GetRoadmap(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if roadmap == nil {
+		return nil, ErrRoadmapNotFound
+	}
+
+	oldKey := roadmap.PublicKey
+	key, err := generateRoadmapKey()
+	if err != nil {
+		return nil, err
+	}
+	roadmap.PublicKey = key
+	roadmap.UpdatedAt = time.Now()
+
+	if err := s.roadmapRepo.SaveRoadmap(ctx, roadmap); err != nil {
+		s.logger.Error("Failed to rotate project roadmap key", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return nil, err
+	}
+
+	s.invalidateCache(ctx, oldKey)
+
+	roadmap.URL = s.roadmapURL(roadmap.PublicKey)
+	return roadmap, nil
+}
+
+// DeleteRoadmap снимает дорожную карту проекта с публикации
+func (s *RoadmapService) DeleteRoadmap(ctx context.Context, projectID string) error {
+	roadmap, err := s.roadmapRepo.GetRoadmap(ctx, projectID)
+	if err != nil {
+		return err
+	}
+	if roadmap == nil {
+		return nil
+	}
+
+	if err := s.roadmapRepo.DeleteRoadmap(ctx, projectID); err != nil {
+		return err
+	}
+
+	s.invalidateCache(ctx, roadmap.PublicKey)
+	return nil
+}
+
+// GetPublicRoadmap возвращает опубликованную дорожную карту по публичному ключу.
+// Ответ кэшируется на уровне HTTP и сбрасывается при изменении задач и настроек проекта
+func (s *RoadmapService) GetPublicRoadmap(ctx context.Context, publicKey string) (*domain.PublicRoadmap, error) {
+	roadmap, err := s.roadmapRepo.GetRoadmapByKey(ctx, publicKey)
+	if err != nil {
+		return nil, err
+	}
+	if roadmap == nil || !roadmap.Enabled {
+		return nil, ErrRoadmapNotFound
+	}
+
+	project, err := s.projectRepo.GetByID(ctx, roadmap.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	if project == nil {
+		return nil, ErrRoadmapNotFound
+	}
+
+	tasks, err := s.roadmapRepo.ListRoadmapTasks(ctx, roadmap.ProjectID, roadmap.Label, roadmapMaxItems)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &domain.PublicRoadmap{
+		ProjectID:   roadmap.ProjectID,
+		Title:       roadmap.Title,
+		Description: roadmap.Description,
+		ProjectName: project.Name,
+		Items:       make([]*domain.RoadmapItem, 0, len(tasks)),
+		GeneratedAt: time.Now().UTC(),
+	}
+	for _, task := range tasks {
+		result.Items = append(result.Items, roadmapItem(task))
+	}
+
+	return result, nil
+}
+
+// RenderRoadmapHTML формирует HTML-страницу опубликованной дорожной карты
+func (s *RoadmapService) RenderRoadmapHTML(roadmap *domain.PublicRoadmap) ([]byte, error) {
+	type stageView struct {
+		Label string
+		Items []*domain.RoadmapItem
+	}
+
+	stages := []domain.RoadmapStage{
+		domain.RoadmapStagePlanned,
+		domain.RoadmapStageInProgress,
+		domain.RoadmapStageShipped,
+	}
+	views := make([]stageView, 0, len(stages))
+	for _, stage := range stages {
+		view := stageView{Label: roadmapStageLabels[stage]}
+		for _, item := range roadmap.Items {
+			if item.Stage == stage {
+				view.Items = append(view.Items, item)
+			}
+		}
+		views = append(views, view)
+	}
+
+	var buf bytes.Buffer
+	err := roadmapTemplate.Execute(&buf, map[string]interface{}{
+		"Title":       roadmap.Title,
+		"Description": roadmap.Description,
+		"ProjectName": roadmap.ProjectName,
+		"Stages":      views,
+		"GeneratedAt": roadmap.GeneratedAt.Format("02.01.2006 15:04 MST"),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to render roadmap: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// invalidateCache удаляет ответы с опубликованной дорожной картой из HTTP-кэша
+func (s *RoadmapService) invalidateCache(ctx context.Context, publicKey string) {
+	if err := s.cacheRepo.PurgeSurrogateKeys(ctx, cache.RoadmapSurrogateKey(publicKey)); err != nil {
+		s.logger.Warn("Failed to invalidate public roadmap cache", map[string]interface{}{
+			"error": err.Error(),
+		})
+	}
+}
+
+// roadmapURL возвращает публичную ссылку на дорожную карту
+func (s *RoadmapService) roadmapURL(publicKey string) string {
+	return s.baseURL + roadmapPath + publicKey + "?format=html"
+}
+
+// roadmapItem сводит задачу к укрупненному элементу дорожной карты
+func roadmapItem(task *domain.RoadmapTask) *domain.RoadmapItem {
+	item := &domain.RoadmapItem{
+		Title: task.Title,
+		Stage: roadmapStage(task.Status),
+	}
+
+	// Для завершенных задач ориентиром служит квартал завершения, для остальных - квартал срока
+	target := task.DueDate
+	if item.Stage == domain.RoadmapStageShipped && task.CompletedAt != nil {
+		target = task.CompletedAt
+	}
+	if target != nil {
+		item.Target = fmt.Sprintf("%d Q%d", target.Year(), (int(target.Month())-1)/3+1)
+	}
+
+	return item
+}
+
+// roadmapStage сводит статус задачи к этапу дорожной карты
+func roadmapStage(status domain.TaskStatus) domain.RoadmapStage {
+	switch status {
+	case domain.TaskStatusInProgress, domain.TaskStatusReview:
+		return domain.RoadmapStageInProgress
+	case domain.TaskStatusCompleted:
+		return domain.RoadmapStageShipped
+	default:
+		return domain.RoadmapStagePlanned
+	}
+}
+
+// generateRoadmapKey генерирует публичный ключ дорожной карты
+func generateRoadmapKey() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate roadmap key: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,266 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/internal/repository"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
+	"github.com/nurlyy/task_manager/pkg/logger"
+)
+
+// scheduledTaskBatchSize ограничивает число задач, создаваемых за один запуск планировщика
+const scheduledTaskBatchSize = 100
+
+var (
+	ErrScheduledTaskNotFound  = apperrors.New(apperrors.CodeScheduledTaskNotFound, "scheduled task not found")
+	ErrScheduledTaskProcessed = apperrors.New(apperrors.CodeScheduledTaskProcessed, "scheduled task has already been processed")
+	ErrInvalidScheduleTime    = apperrors.New(apperrors.CodeInvalidScheduleTime, "scheduled creation time must be in the future")
+)
+
+// ScheduledTaskService представляет бизнес-логику отложенного создания задач
+type ScheduledTaskService struct {
+	scheduledRepo repository.ScheduledTaskRepository
+	projectSvc    *ProjectService
+	taskSvc       *TaskService
+	logger        logger.Logger
+}
+
+// NewScheduledTaskService создает новый экземпляр ScheduledTaskService
+func NewScheduledTaskService(
+	scheduledRepo repository.ScheduledTaskRepository,
+	projectSvc *ProjectService,
+	taskSvc *TaskService,
+	logger logger.Logger,
+) *ScheduledTaskService {
+	return &ScheduledTaskService{
+		scheduledRepo: scheduledRepo,
+		projectSvc:    projectSvc,
+		taskSvc:       taskSvc,
+		logger:        logger,
+	}
+}
+
+// Create планирует создание задачи в проекте
+func (s *ScheduledTaskService) Create(ctx context.Context, projectID string, req domain.ScheduledTaskRequest, userID string) (*domain.ScheduledTask, error) {
+	if err := s.projectSvc.ensureProjectWritable(ctx, projectID); err != nil {
+		return nil, err
+	}
+
+	req.Task.ProjectID = projectID
+	now := time.Now()
+	if err := s.checkRequest(ctx, req, now); err != nil {
+		return nil, err
+	}
+
+	scheduled := &domain.ScheduledTask{
+		ID:        uuid.New().String(),
+		ProjectID: projectID,
+		Task:      req.Task,
+		CreateAt:  req.CreateAt,
+		Status:    domain.ScheduledTaskStatusPending,
+		CreatedBy: userID,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err := s.scheduledRepo.Create(ctx, scheduled); err != nil {
+		s.logger.Error("Failed to schedule task", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return nil, err
+	}
+
+	s.logger.Info("Task scheduled", map[string]interface{}{
+		"scheduled_task_id": scheduled.ID,
+		"project_id":        projectID,
+		"create_at":         scheduled.CreateAt,
+	})
+
+	return scheduled, nil
+}
+
+// List возвращает запланированные задачи проекта
+func (s *ScheduledTaskService) List(ctx context.Context, projectID string, status *domain.ScheduledTaskStatus) ([]*domain.ScheduledTask, error) {
+	return s.scheduledRepo.ListByProject(ctx, projectID, status)
+}
+
+// GetByID возвращает запланированную задачу
+func (s *ScheduledTaskService) GetByID(ctx context.Context, projectID, id string) (*domain.ScheduledTask, error) {
+	return s.getScheduledTask(ctx, projectID, id)
+}
+
+// Update изменяет данные и время создания задачи, пока она не создана
+func (s *ScheduledTaskService) Update(ctx context.Context, projectID, id string, req domain.ScheduledTaskRequest, userID string) (*domain.ScheduledTask, error) {
+	scheduled, err := s.getEditableScheduledTask(ctx, projectID, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Task.ProjectID = scheduled.ProjectID
+	now := time.Now()
+	if err := s.checkRequest(ctx, req, now); err != nil {
+		return nil, err
+	}
+
+	scheduled.Task = req.Task
+	scheduled.CreateAt = req.CreateAt
+	scheduled.UpdatedAt = now
+
+	updated, err := s.scheduledRepo.UpdatePending(ctx, scheduled)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, ErrScheduledTaskProcessed
+	}
+
+	return scheduled, nil
+}
+
+// Cancel отменяет создание задачи
+func (s *ScheduledTaskService) Cancel(ctx context.Context, projectID, id string, userID string) (*domain.ScheduledTask, error) {
+	scheduled, err := s.getEditableScheduledTask(ctx, projectID, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	scheduled.Status = domain.ScheduledTaskStatusCancelled
+	scheduled.UpdatedAt = time.Now()
+
+	updated, err := s.scheduledRepo.UpdatePending(ctx, scheduled)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, ErrScheduledTaskProcessed
+	}
+
+	s.logger.Info("Scheduled task cancelled", map[string]interface{}{
+		"scheduled_task_id": id,
+		"user_id":           userID,
+	})
+
+	return scheduled, nil
+}
+
+// CreateDue создает задачи, время создания которых наступило, и возвращает число созданных.
+// Задача создается от имени автора, поэтому исполнители получают обычное уведомление о назначении.
+// Если создание невозможно (автор потерял доступ, проект архивирован, данные больше не проходят
+// проверку), запись помечается как failed; при прочих ошибках попытка повторяется при следующем запуске
+func (s *ScheduledTaskService) CreateDue(ctx context.Context, now time.Time) (int, error) {
+	due, err := s.scheduledRepo.ClaimDue(ctx, now, scheduledTaskBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	created := 0
+	for _, scheduled := range due {
+		task, err := s.taskSvc.Create(ctx, scheduled.Task, scheduled.CreatedBy)
+		if err != nil {
+			s.handleCreateError(ctx, scheduled, err)
+			continue
+		}
+
+		if err := s.scheduledRepo.Complete(ctx, scheduled.ID, domain.ScheduledTaskStatusCreated, &task.ID, nil, time.Now()); err != nil {
+			s.logger.Error("Failed to complete scheduled task", err, map[string]interface{}{
+				"scheduled_task_id": scheduled.ID,
+				"task_id":           task.ID,
+			})
+		}
+		created++
+
+		s.logger.Info("Scheduled task created", map[string]interface{}{
+			"scheduled_task_id": scheduled.ID,
+			"task_id":           task.ID,
+			"project_id":        scheduled.ProjectID,
+		})
+	}
+
+	return created, nil
+}
+
+// handleCreateError фиксирует неудачную попытку создания запланированной задачи
+func (s *ScheduledTaskService) handleCreateError(ctx context.Context, scheduled *domain.ScheduledTask, err error) {
+	errMsg := err.Error()
+	var validationErr *TaskValidationError
+	permanent := errors.As(err, &validationErr) ||
+		errors.Is(err, ErrProjectNotFound) ||
+		errors.Is(err, ErrProjectArchived) ||
+		errors.Is(err, ErrInvalidAssignee)
+
+	if !permanent {
+		s.logger.Warn("Failed to create scheduled task, will retry", map[string]interface{}{
+			"scheduled_task_id": scheduled.ID,
+			"error":             errMsg,
+		})
+		if err := s.scheduledRepo.Release(ctx, scheduled.ID, errMsg); err != nil {
+			s.logger.Error("Failed to release scheduled task", err, map[string]interface{}{
+				"scheduled_task_id": scheduled.ID,
+			})
+		}
+		return
+	}
+
+	s.logger.Warn("Scheduled task cannot be created", map[string]interface{}{
+		"scheduled_task_id": scheduled.ID,
+		"project_id":        scheduled.ProjectID,
+		"error":             errMsg,
+	})
+	if err := s.scheduledRepo.Complete(ctx, scheduled.ID, domain.ScheduledTaskStatusFailed, nil, &errMsg, time.Now()); err != nil {
+		s.logger.Error("Failed to mark scheduled task as failed", err, map[string]interface{}{
+			"scheduled_task_id": scheduled.ID,
+		})
+	}
+}
+
+// getScheduledTask возвращает запланированную задачу проекта
+func (s *ScheduledTaskService) getScheduledTask(ctx context.Context, projectID, id string) (*domain.ScheduledTask, error) {
+	scheduled, err := s.scheduledRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if scheduled == nil || scheduled.ProjectID != projectID {
+		return nil, ErrScheduledTaskNotFound
+	}
+	return scheduled, nil
+}
+
+// getEditableScheduledTask возвращает ожидающую задачу, которую пользователь может изменить:
+// автору или менеджеру проекта
+func (s *ScheduledTaskService) getEditableScheduledTask(ctx context.Context, projectID, id string, userID string) (*domain.ScheduledTask, error) {
+	scheduled, err := s.getScheduledTask(ctx, projectID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if scheduled.CreatedBy != userID && !s.projectSvc.canManageProject(ctx, scheduled.ProjectID, userID) {
+		return nil, ErrInsufficientRights
+	}
+
+	if scheduled.Status != domain.ScheduledTaskStatusPending {
+		return nil, ErrScheduledTaskProcessed
+	}
+
+	return scheduled, nil
+}
+
+// checkRequest проверяет время создания и исполнителей будущей задачи
+func (s *ScheduledTaskService) checkRequest(ctx context.Context, req domain.ScheduledTaskRequest, now time.Time) error {
+	if !req.CreateAt.After(now) {
+		return ErrInvalidScheduleTime
+	}
+
+	if req.Task.DueDate != nil && req.Task.DueDate.Before(req.CreateAt) {
+		return &TaskValidationError{Violations: []domain.FieldViolation{{
+			Field:   "task.due_date",
+			Message: "Due date must not be earlier than the scheduled creation time",
+		}}}
+	}
+
+	return s.taskSvc.checkAssigneesMembership(ctx, req.Task.ProjectID, mergeAssigneeIDs(req.Task.AssigneeID, req.Task.AssigneeIDs))
+}
@@ -0,0 +1,302 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/robfig/cron/v3"
+)
+
+// Имена задач планировщика в истории запусков
+const (
+	jobDailyDigest            = "daily_digest"
+	jobDeadlineReminders      = "deadline_reminders"
+	jobOverdueTasks           = "overdue_tasks"
+	jobArchiveProjects        = "archive_projects"
+	jobExpireTransfers        = "expire_ownership_transfers"
+	jobStaleTasks             = "stale_tasks"
+	jobInboxReminders         = "inbox_reminders"
+	jobScheduledTasks         = "scheduled_tasks"
+	jobRecurringTasks         = "recurring_tasks"
+	jobPurgeAttachments       = "purge_attachments"
+	jobRefreshProjectMetrics  = "refresh_project_metrics"
+	jobOverdueProjectMetrics  = "overdue_project_metrics"
+	jobSnapshotProjectMetrics = "snapshot_project_metrics"
+	jobReconcileUnreadCounts  = "reconcile_unread_counts"
+	jobConsistencyCheck       = "consistency_check"
+	jobPurgeJobRuns           = "purge_job_runs"
+	jobFlushTaskViews         = "flush_task_views"
+)
+
+// schedulerCronParser разбирает расписания с полем секунд, как cron.WithSeconds
+var schedulerCronParser = cron.NewParser(cron.Second | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+// jobAlertTimeout ограничивает отправку оповещения о задаче планировщика
+const jobAlertTimeout = 10 * time.Second
+
+// schedulerJob выполняет задачу планировщика. Возвращаемая ошибка означает, что запуск
+// прервался; ошибки отдельных элементов учитываются в run и не прерывают запуск
+type schedulerJob func(ctx context.Context, run *jobRun) error
+
+// scheduledJob описывает зарегистрированную задачу планировщика
+type scheduledJob struct {
+	name     string
+	schedule cron.Schedule
+	run      schedulerJob
+}
+
+// jobRun накапливает итоги выполняющегося запуска. Методы безопасны для вызова
+// из нескольких горутин
+type jobRun struct {
+	mu      sync.Mutex
+	record  domain.JobRun
+	alerted bool // Оповещение о превышении интервала уже отправлено
+}
+
+// addProcessed учитывает обработанные элементы
+func (r *jobRun) addProcessed(n int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.record.ItemsProcessed += n
+}
+
+// addErrors учитывает элементы, обработанные с ошибкой; сохраняется последняя ошибка
+func (r *jobRun) addErrors(n int, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.record.Errors += n
+	if err != nil {
+		message := err.Error()
+		r.record.Error = &message
+	}
+}
+
+// jobAlert представляет оповещение о задаче планировщика, отправляемое на SCHEDULER_JOB_ALERT_WEBHOOK_URL.
+// Поле text позволяет принимать оповещения входящими вебхуками мессенджеров
+type jobAlert struct {
+	Text   string         `json:"text"`
+	Reason string         `json:"reason"`
+	Run    *domain.JobRun `json:"run"`
+}
+
+// addJob регистрирует задачу в планировщике с записью каждого запуска в историю
+func (s *SchedulerService) addJob(name, spec string, job schedulerJob) {
+	schedule, err := schedulerCronParser.Parse(spec)
+	if err != nil {
+		s.logger.Error("Failed to schedule job", err, map[string]interface{}{
+			"job":      name,
+			"schedule": spec,
+		})
+		return
+	}
+
+	scheduled := &scheduledJob{
+		name:     name,
+		schedule: schedule,
+		run:      job,
+	}
+	s.jobs[name] = scheduled
+	s.cron.Schedule(schedule, cron.FuncJob(func() {
+		s.runJob(scheduled)
+	}))
+}
+
+// runJob выполняет запуск задачи и сохраняет его итоги. Если предыдущий запуск еще
+// не завершился, новый пропускается, а предыдущий помечается как превысивший интервал.
+// О прерванных запусках и превышении интервала отправляется оповещение
+func (s *SchedulerService) runJob(job *scheduledJob) {
+	ctx := context.Background()
+
+	s.jobsMu.Lock()
+	if active, ok := s.activeJobs[job.name]; ok {
+		s.jobsMu.Unlock()
+		s.jobStillRunning(ctx, active)
+		return
+	}
+	run := &jobRun{record: domain.JobRun{
+		ID:        uuid.New().String(),
+		Job:       job.name,
+		Status:    domain.JobRunStatusRunning,
+		StartedAt: time.Now(),
+	}}
+	s.activeJobs[job.name] = run
+	s.jobsMu.Unlock()
+
+	defer func() {
+		s.jobsMu.Lock()
+		delete(s.activeJobs, job.name)
+		s.jobsMu.Unlock()
+	}()
+
+	// История запусков не должна мешать выполнению задачи
+	if err := s.jobRunRepo.Create(ctx, &run.record); err != nil {
+		s.logger.Warn("Failed to record job run start", map[string]interface{}{
+			"job": job.name,
+		}, map[string]interface{}{
+			"error": err,
+		})
+	}
+
+	err := s.executeJob(ctx, job, run)
+	finishedAt := time.Now()
+
+	run.mu.Lock()
+	record := run.record
+	alerted := run.alerted
+	run.mu.Unlock()
+
+	record.FinishedAt = &finishedAt
+	record.Status = domain.JobRunStatusSucceeded
+	if err != nil {
+		message := err.Error()
+		record.Status = domain.JobRunStatusFailed
+		record.Error = &message
+	}
+	if finishedAt.After(job.schedule.Next(record.StartedAt)) {
+		record.Overran = true
+	}
+
+	if err := s.jobRunRepo.Finish(ctx, &record); err != nil {
+		s.logger.Warn("Failed to record job run result", map[string]interface{}{
+			"job": job.name,
+		}, map[string]interface{}{
+			"error": err,
+		})
+	}
+
+	switch {
+	case err != nil:
+		s.logger.Error("Scheduler job failed", err, map[string]interface{}{
+			"job":             job.name,
+			"items_processed": record.ItemsProcessed,
+			"errors":          record.Errors,
+		})
+		s.alertJob(ctx, &record, "failed")
+	case record.Overran && !alerted:
+		s.alertJob(ctx, &record, "overran")
+	}
+}
+
+// executeJob выполняет задачу; паника задачи считается ошибкой запуска
+func (s *SchedulerService) executeJob(ctx context.Context, job *scheduledJob, run *jobRun) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("panic: %v", recovered)
+		}
+	}()
+
+	return job.run(ctx, run)
+}
+
+// jobStillRunning помечает запуск, не завершившийся к следующему запуску по расписанию,
+// и один раз оповещает о превышении интервала
+func (s *SchedulerService) jobStillRunning(ctx context.Context, run *jobRun) {
+	run.mu.Lock()
+	alerted := run.alerted
+	run.alerted = true
+	run.record.Overran = true
+	record := run.record
+	run.mu.Unlock()
+
+	s.logger.Warn("Skipping scheduler job run: previous run is still in progress", map[string]interface{}{
+		"job":        record.Job,
+		"started_at": record.StartedAt,
+	})
+
+	if alerted {
+		return
+	}
+
+	if err := s.jobRunRepo.MarkOverran(ctx, record.ID); err != nil {
+		s.logger.Warn("Failed to mark job run overran", map[string]interface{}{
+			"job": record.Job,
+		}, map[string]interface{}{
+			"error": err,
+		})
+	}
+
+	s.alertJob(ctx, &record, "overran")
+}
+
+// alertJob оповещает о прерванном запуске или превышении интервала: пишет в журнал
+// и, если задан SCHEDULER_JOB_ALERT_WEBHOOK_URL, отправляет оповещение на него
+func (s *SchedulerService) alertJob(ctx context.Context, run *domain.JobRun, reason string) {
+	var text string
+	switch reason {
+	case "failed":
+		errorMessage := ""
+		if run.Error != nil {
+			errorMessage = *run.Error
+		}
+		text = fmt.Sprintf("Задача планировщика %s завершилась с ошибкой: %s", run.Job, errorMessage)
+	default:
+		text = fmt.Sprintf("Задача планировщика %s выполняется дольше интервала расписания", run.Job)
+	}
+
+	s.logger.Warn("Scheduler job alert", map[string]interface{}{
+		"job":    run.Job,
+		"reason": reason,
+		"run_id": run.ID,
+	})
+
+	if s.config.JobAlertWebhookURL == "" {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, jobAlertTimeout)
+	defer cancel()
+
+	if err := s.sendJobAlert(ctx, &jobAlert{Text: text, Reason: reason, Run: run}); err != nil {
+		s.logger.Error("Failed to send scheduler job alert", err, map[string]interface{}{
+			"job":    run.Job,
+			"reason": reason,
+		})
+	}
+}
+
+// sendJobAlert отправляет оповещение о задаче планировщика на адрес из конфигурации
+func (s *SchedulerService) sendJobAlert(ctx context.Context, alert *jobAlert) error {
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return fmt.Errorf("failed to encode job alert: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.config.JobAlertWebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create job alert request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.alertClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send job alert: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("job alert webhook returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// purgeJobRuns удаляет историю запусков старше JobRunRetentionDays дней
+func (s *SchedulerService) purgeJobRuns(ctx context.Context, run *jobRun) error {
+	before := time.Now().AddDate(0, 0, -s.config.JobRunRetentionDays)
+	deleted, err := s.jobRunRepo.DeleteBefore(ctx, before)
+	if err != nil {
+		return fmt.Errorf("failed to delete job runs: %w", err)
+	}
+	run.addProcessed(deleted)
+
+	return nil
+}
@@ -2,13 +2,20 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/nurlyy/task_manager/internal/domain"
 	"github.com/nurlyy/task_manager/internal/messaging"
 	"github.com/nurlyy/task_manager/internal/repository"
+	"github.com/nurlyy/task_manager/internal/repository/cache"
 	"github.com/nurlyy/task_manager/pkg/config"
+	"github.com/nurlyy/task_manager/pkg/datefilter"
 	"github.com/nurlyy/task_manager/pkg/logger"
 	"github.com/robfig/cron/v3"
 )
@@ -19,8 +26,24 @@ type SchedulerService struct {
 	userRepo         repository.UserRepository
 	projectRepo      repository.ProjectRepository
 	notificationRepo repository.NotificationRepository
+	metricsRepo      repository.ProjectMetricsRepository
+	unreadCounter    *cache.CountingNotificationRepository
+	scheduledTaskSvc *ScheduledTaskService
+	recurrenceSvc    *TaskRecurrenceService
+	attachmentSvc    *AttachmentService
+	inboxSvc         *InboxService
+	projectSvc       *ProjectService
+	consistencySvc   *ConsistencyService
+	taskViewSvc      *TaskViewService
+	cacheRepo        *cache.RedisRepository
+	jobRunRepo       repository.JobRunRepository
 	producer         *messaging.KafkaProducer
 	cron             *cron.Cron
+	jobs             map[string]*scheduledJob
+	activeJobs       map[string]*jobRun
+	jobsMu           sync.Mutex
+	alertClient      *http.Client
+	digestMetrics    *DigestMetrics
 	logger           logger.Logger
 	config           *config.SchedulerConfig
 }
@@ -31,6 +54,17 @@ func NewSchedulerService(
 	userRepo repository.UserRepository,
 	projectRepo repository.ProjectRepository,
 	notificationRepo repository.NotificationRepository,
+	metricsRepo repository.ProjectMetricsRepository,
+	unreadCounter *cache.CountingNotificationRepository,
+	scheduledTaskSvc *ScheduledTaskService,
+	recurrenceSvc *TaskRecurrenceService,
+	attachmentSvc *AttachmentService,
+	inboxSvc *InboxService,
+	projectSvc *ProjectService,
+	consistencySvc *ConsistencyService,
+	taskViewSvc *TaskViewService,
+	cacheRepo *cache.RedisRepository,
+	jobRunRepo repository.JobRunRepository,
 	producer *messaging.KafkaProducer,
 	config *config.SchedulerConfig,
 	logger logger.Logger,
@@ -43,8 +77,23 @@ func NewSchedulerService(
 		userRepo:         userRepo,
 		projectRepo:      projectRepo,
 		notificationRepo: notificationRepo,
+		metricsRepo:      metricsRepo,
+		unreadCounter:    unreadCounter,
+		scheduledTaskSvc: scheduledTaskSvc,
+		recurrenceSvc:    recurrenceSvc,
+		attachmentSvc:    attachmentSvc,
+		inboxSvc:         inboxSvc,
+		projectSvc:       projectSvc,
+		consistencySvc:   consistencySvc,
+		taskViewSvc:      taskViewSvc,
+		cacheRepo:        cacheRepo,
+		jobRunRepo:       jobRunRepo,
 		producer:         producer,
 		cron:             cronScheduler,
+		jobs:             make(map[string]*scheduledJob),
+		activeJobs:       make(map[string]*jobRun),
+		alertClient:      &http.Client{Timeout: jobAlertTimeout},
+		digestMetrics:    newDigestMetrics(),
 		logger:           logger,
 		config:           config,
 	}
@@ -54,12 +103,27 @@ func NewSchedulerService(
 func (s *SchedulerService) Start(ctx context.Context) error {
 	s.logger.Info("Starting scheduler service")
 
+	// Запуски, оставшиеся выполняющимися после остановки планировщика, считаются прерванными
+	failed, err := s.jobRunRepo.FailRunning(ctx, time.Now(), "interrupted by scheduler restart")
+	if err != nil {
+		s.logger.Warn("Failed to close interrupted job runs", map[string]interface{}{
+			"error": err,
+		})
+	} else if failed > 0 {
+		s.logger.Warn("Interrupted job runs closed", map[string]interface{}{
+			"count": failed,
+		})
+	}
+
 	// Регистрируем задачи по расписанию
 	s.registerTasks()
 
 	// Запускаем планировщик
 	s.cron.Start()
 
+	// Продолжаем сегодняшнюю рассылку дайджестов, если она была прервана
+	go s.resumeDailyDigests(ctx)
+
 	// Слушаем сигнал завершения
 	go func() {
 		<-ctx.Done()
@@ -70,144 +134,416 @@ func (s *SchedulerService) Start(ctx context.Context) error {
 	return nil
 }
 
+// DigestMetrics возвращает метрики рассылки ежедневных дайджестов
+func (s *SchedulerService) DigestMetrics() *DigestMetrics {
+	return s.digestMetrics
+}
+
 // registerTasks регистрирует все задачи в планировщике
 func (s *SchedulerService) registerTasks() {
 	// Задача для отправки ежедневных дайджестов
-	if _, err := s.cron.AddFunc(s.config.DailyDigestCron, s.sendDailyDigests); err != nil {
-		s.logger.Error("Failed to schedule daily digest task", err)
-	}
+	s.addJob(jobDailyDigest, s.config.DailyDigestCron, s.sendDailyDigests)
 
 	// Задача для отправки напоминаний о сроках
-	if _, err := s.cron.AddFunc(s.config.DeadlineReminderCron, s.sendDeadlineReminders); err != nil {
-		s.logger.Error("Failed to schedule deadline reminder task", err)
-	}
+	s.addJob(jobDeadlineReminders, s.config.DeadlineReminderCron, s.sendDeadlineReminders)
 
 	// Задача для проверки просроченных задач (каждый час)
-	if _, err := s.cron.AddFunc("0 0 * * * *", s.checkOverdueTasks); err != nil {
-		s.logger.Error("Failed to schedule overdue tasks check", err)
-	}
+	s.addJob(jobOverdueTasks, "0 0 * * * *", s.checkOverdueTasks)
 
 	// Задача для автоматического архивирования завершенных проектов (раз в неделю)
-	if _, err := s.cron.AddFunc("0 0 0 * * 0", s.archiveCompletedProjects); err != nil {
-		s.logger.Error("Failed to schedule project archiving task", err)
+	s.addJob(jobArchiveProjects, "0 0 0 * * 0", s.archiveCompletedProjects)
+
+	// Задача для отката просроченных запросов на передачу владения (каждые 15 минут)
+	s.addJob(jobExpireTransfers, "0 */15 * * * *", s.expireOwnershipTransfers)
+
+	// Задача для пометки устаревших задач бэклога, если она включена
+	if s.config.StaleTaskDays > 0 {
+		s.addJob(jobStaleTasks, s.config.StaleTaskCron, s.labelStaleTasks)
 	}
+
+	// Задача для напоминаний о неразобранных входящих, если они включены
+	if s.config.InboxReminderDays > 0 {
+		s.addJob(jobInboxReminders, s.config.InboxReminderCron, s.remindStaleInbox)
+	}
+
+	// Задача для создания отложенных задач (каждую минуту)
+	s.addJob(jobScheduledTasks, "0 * * * * *", s.createScheduledTasks)
+
+	// Задача для создания следующих задач повторяющихся серий (каждую минуту)
+	s.addJob(jobRecurringTasks, "15 * * * * *", s.createRecurringTasks)
+
+	// Задача для удаления файлов удаленных вложений из хранилища (каждые 5 минут)
+	s.addJob(jobPurgeAttachments, "45 */5 * * * *", s.purgeDeletedAttachments)
+
+	// Задача для пересчета устаревших метрик проектов (каждую минуту)
+	s.addJob(jobRefreshProjectMetrics, "30 * * * * *", s.refreshProjectMetrics)
+
+	// Задача для пометки метрик проектов с новыми просроченными задачами (каждые 15 минут)
+	s.addJob(jobOverdueProjectMetrics, "0 */15 * * * *", s.markOverdueProjectMetrics)
+
+	// Задача для сохранения ежедневных снимков метрик проектов
+	s.addJob(jobSnapshotProjectMetrics, "0 55 23 * * *", s.snapshotProjectMetrics)
+
+	// Задача для сверки счетчиков непрочитанных уведомлений с базой (раз в сутки ночью)
+	s.addJob(jobReconcileUnreadCounts, "0 30 3 * * *", s.reconcileUnreadCounts)
+
+	// Задача для проверки согласованности данных с исправлением расхождений, если она включена
+	if s.config.ConsistencyCron != "" {
+		s.addJob(jobConsistencyCheck, s.config.ConsistencyCron, s.checkConsistency)
+	}
+
+	// Задача для удаления устаревшей истории запусков планировщика (раз в сутки ночью)
+	s.addJob(jobPurgeJobRuns, "0 15 4 * * *", s.purgeJobRuns)
+
+	// Задача для переноса просмотров задач из Redis в базу (каждую минуту)
+	s.addJob(jobFlushTaskViews, "50 * * * * *", s.flushTaskViews)
 }
 
-// sendDailyDigests отправляет ежедневные дайджесты задач
-func (s *SchedulerService) sendDailyDigests() {
-	ctx := context.Background()
-	s.logger.Info("Running daily digest task")
+// sendDailyDigests отправляет ежедневные дайджесты задач. Незавершенная сегодняшняя
+// рассылка продолжается с сохраненной точки, иначе начинается новая
+func (s *SchedulerService) sendDailyDigests(ctx context.Context, run *jobRun) error {
+	now := time.Now()
 
-	// Получаем всех активных пользователей
-	filter := repository.UserFilter{
-		IsActive: getBoolPtr(true),
+	checkpoint, err := s.cacheRepo.GetDigestCheckpoint(ctx)
+	if err != nil {
+		// Без точки продолжения рассылка начинается сначала
+		s.logger.Warn("Failed to get daily digest checkpoint", map[string]interface{}{
+			"error": err,
+		})
 	}
-	users, err := s.userRepo.List(ctx, filter)
+	if checkpoint == nil || checkpoint.RunDate != now.Format(digestRunDateLayout) {
+		checkpoint = &domain.DigestCheckpoint{
+			RunDate:   now.Format(digestRunDateLayout),
+			StartedAt: now,
+		}
+	}
+
+	return s.runDailyDigests(ctx, run, checkpoint)
+}
+
+// resumeDailyDigests продолжает сегодняшнюю рассылку дайджестов, прерванную остановкой
+// планировщика. Следующий запуск по расписанию будет только завтра
+func (s *SchedulerService) resumeDailyDigests(ctx context.Context) {
+	checkpoint, err := s.cacheRepo.GetDigestCheckpoint(ctx)
 	if err != nil {
-		s.logger.Error("Failed to get users for daily digest", err)
+		s.logger.Warn("Failed to get daily digest checkpoint", map[string]interface{}{
+			"error": err,
+		})
+		return
+	}
+	if checkpoint == nil || checkpoint.RunDate != time.Now().Format(digestRunDateLayout) {
 		return
 	}
 
-	// Текущая дата
-	now := time.Now()
-	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	// Продолжение записывается в историю как отдельный запуск задачи
+	if job, ok := s.jobs[jobDailyDigest]; ok {
+		s.runJob(job)
+	}
+}
 
-	// Для каждого пользователя формируем и отправляем дайджест
-	for _, user := range users {
-		// Проверяем настройки уведомлений пользователя
-		settings, err := s.notificationRepo.GetUserNotificationSettings(ctx, user.ID)
+// digestRunDateLayout задает формат даты запуска рассылки дайджестов
+const digestRunDateLayout = "2006-01-02"
+
+// digestCheckpointTTL ограничивает время хранения точки продолжения рассылки дайджестов
+const digestCheckpointTTL = 24 * time.Hour
+
+// runDailyDigests обходит активных пользователей страницами по порядку ID после точки
+// продолжения. Дайджесты страницы формируются пулом воркеров, затем сохраняются и публикуются,
+// после чего точка продолжения сдвигается. При прерывании повторно обрабатывается
+// не больше одной страницы
+func (s *SchedulerService) runDailyDigests(ctx context.Context, run *jobRun, checkpoint *domain.DigestCheckpoint) error {
+	s.logger.Info("Running daily digest task", map[string]interface{}{
+		"run_date":  checkpoint.RunDate,
+		"resumed":   checkpoint.AfterUserID != "",
+		"processed": checkpoint.Processed,
+	})
+	s.digestMetrics.start(checkpoint)
+	defer s.digestMetrics.finish()
+
+	// Период задач дайджеста вычисляется от момента запуска, в том числе при продолжении
+	window := s.digestTaskFilter(checkpoint.StartedAt)
+
+	users := newUserIterator(s.userRepo, repository.UserFilter{
+		IsActive: getBoolPtr(true),
+	}, digestBatchSize, checkpoint.AfterUserID)
+
+	for {
+		page, err := users.Next(ctx)
 		if err != nil {
-			s.logger.Error("Failed to get notification settings", err, map[string]interface{}{
-				"user_id": user.ID,
+			// Точка продолжения сохранена, рассылка продолжится при следующем запуске планировщика
+			return fmt.Errorf("failed to get users for daily digest after %q: %w", checkpoint.AfterUserID, err)
+		}
+		if len(page) == 0 {
+			break
+		}
+
+		digests, failed := s.buildDigests(ctx, run, page, window)
+		s.publishDigests(ctx, run, digests)
+		run.addProcessed(len(page))
+
+		checkpoint.AfterUserID = page[len(page)-1].ID
+		checkpoint.Processed += len(page)
+		checkpoint.Sent += len(digests)
+		checkpoint.Failed += failed
+		s.digestMetrics.page(len(page), len(digests), failed)
+
+		if err := s.cacheRepo.SaveDigestCheckpoint(ctx, checkpoint, digestCheckpointTTL); err != nil {
+			s.logger.Warn("Failed to save daily digest checkpoint", map[string]interface{}{
+				"after_user_id": checkpoint.AfterUserID,
+			}, map[string]interface{}{
+				"error": err,
 			})
-			continue
 		}
 
-		// Проверяем, включены ли дайджесты для пользователя
-		digestEnabled := false
-		for _, setting := range settings {
+		s.logger.Debug("Daily digest progress", map[string]interface{}{
+			"processed": checkpoint.Processed,
+			"sent":      checkpoint.Sent,
+			"failed":    checkpoint.Failed,
+		})
+	}
+
+	s.digestMetrics.complete()
+
+	if err := s.cacheRepo.DeleteDigestCheckpoint(ctx); err != nil {
+		s.logger.Warn("Failed to delete daily digest checkpoint", map[string]interface{}{
+			"error": err,
+		})
+	}
+
+	s.logger.Info("Daily digest task completed", map[string]interface{}{
+		"run_date":  checkpoint.RunDate,
+		"processed": checkpoint.Processed,
+		"sent":      checkpoint.Sent,
+		"failed":    checkpoint.Failed,
+	})
+
+	return nil
+}
+
+// buildDigests формирует дайджесты страницы пользователей пулом из DigestWorkers воркеров.
+// Ошибка или паника при формировании дайджеста одного пользователя не влияет на остальных.
+// Возвращает дайджесты в порядке пользователей и число пользователей с ошибкой
+func (s *SchedulerService) buildDigests(ctx context.Context, run *jobRun, users []*domain.User, window repository.TaskFilter) ([]*domain.Notification, int) {
+	// Настройки уведомлений страницы загружаются одним запросом
+	userIDs := make([]string, len(users))
+	for i, user := range users {
+		userIDs[i] = user.ID
+	}
+	settingsByUser, err := s.notificationRepo.GetSettingsForUsers(ctx, userIDs)
+	if err != nil {
+		s.logger.Error("Failed to get notification settings for daily digest", err, map[string]interface{}{
+			"users": len(users),
+		})
+		run.addErrors(len(users), err)
+		return nil, len(users)
+	}
+
+	// Дайджест формируется только пользователям, у которых он включен
+	recipients := make([]string, 0, len(users))
+	for _, user := range users {
+		for _, setting := range settingsByUser[user.ID] {
 			if setting.NotificationType == domain.NotificationTypeDigest &&
 				(setting.EmailEnabled || setting.WebEnabled) {
-				digestEnabled = true
+				recipients = append(recipients, user.ID)
 				break
 			}
 		}
+	}
 
-		if !digestEnabled {
-			continue
-		}
+	results := make([]*domain.Notification, len(recipients))
+	var failed int32
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < s.config.DigestWorkers && i < len(recipients); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indexes {
+				digest, err := s.buildUserDigest(ctx, recipients[index], window)
+				if err != nil {
+					s.logger.Error("Failed to build daily digest", err, map[string]interface{}{
+						"user_id": recipients[index],
+					})
+					atomic.AddInt32(&failed, 1)
+					run.addErrors(1, err)
+					continue
+				}
+				results[index] = digest
+			}
+		}()
+	}
 
-		// Получаем задачи, назначенные пользователю
-		taskFilter := repository.TaskFilter{
-			AssigneeID: &user.ID,
-			DueAfter:   &today,
-			OrderBy:    getStringPtr("due_date"),
-			OrderDir:   getStringPtr("asc"),
-		}
-		tasks, err := s.taskRepo.GetTasksByAssignee(ctx, user.ID, taskFilter)
-		if err != nil {
-			s.logger.Error("Failed to get tasks for daily digest", err, map[string]interface{}{
-				"user_id": user.ID,
-			})
-			continue
-		}
+	for i := range recipients {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
 
-		// Если нет активных задач, пропускаем
-		if len(tasks) == 0 {
-			continue
+	digests := make([]*domain.Notification, 0, len(results))
+	for _, digest := range results {
+		if digest != nil {
+			digests = append(digests, digest)
 		}
+	}
 
-		// Формируем содержимое дайджеста
-		content := formatDailyDigest(tasks)
+	return digests, int(failed)
+}
 
-		// Создаем уведомление
-		notification := &domain.Notification{
-			UserID:     user.ID,
-			Type:       domain.NotificationTypeDigest,
-			Title:      "Ваш ежедневный отчет по задачам",
-			Content:    content,
-			Status:     domain.NotificationStatusUnread,
-			EntityType: "digest",
-			EntityID:   user.ID,
-			CreatedAt:  time.Now(),
+// buildUserDigest формирует дайджест пользователя; nil означает, что активных задач нет
+func (s *SchedulerService) buildUserDigest(ctx context.Context, userID string, window repository.TaskFilter) (digest *domain.Notification, err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("panic while building digest: %v", recovered)
 		}
+	}()
 
-		// Сохраняем уведомление
-		if err := s.notificationRepo.Create(ctx, notification); err != nil {
-			s.logger.Error("Failed to create digest notification", err, map[string]interface{}{
-				"user_id": user.ID,
-			})
-			continue
-		}
+	// Получаем задачи, назначенные пользователю
+	taskFilter := window
+	taskFilter.AssigneeID = &userID
+	tasks, err := s.taskRepo.GetTasksByAssignee(ctx, userID, taskFilter)
+	if err != nil {
+		return nil, err
+	}
 
-		// Отправляем событие для обработки уведомления
-		event := &messaging.NotificationEvent{
-			UserIDs:    []string{user.ID},
-			Title:      notification.Title,
-			Content:    notification.Content,
-			Type:       string(notification.Type),
-			EntityID:   user.ID,
-			EntityType: "digest",
-			CreatedAt:  notification.CreatedAt,
-			MetaData: map[string]string{
-				"user_id":    user.ID,
-				"task_count": fmt.Sprintf("%d", len(tasks)),
-			},
-		}
+	// Если нет активных задач, дайджест не нужен
+	if len(tasks) == 0 {
+		return nil, nil
+	}
 
-		if err := s.producer.PublishNotification(ctx, event); err != nil {
-			s.logger.Error("Failed to publish digest notification event", err, map[string]interface{}{
-				"user_id": user.ID,
-			})
+	return &domain.Notification{
+		ID:         uuid.New().String(),
+		UserID:     userID,
+		Type:       domain.NotificationTypeDigest,
+		Title:      "Ваш ежедневный отчет по задачам",
+		Content:    formatDailyDigest(tasks),
+		Status:     domain.NotificationStatusUnread,
+		EntityType: "digest",
+		EntityID:   userID,
+		CreatedAt:  time.Now(),
+	}, nil
+}
+
+// userIterator обходит пользователей страницами по возрастанию ID, не загружая
+// в память весь список
+type userIterator struct {
+	repo     repository.UserRepository
+	filter   repository.UserFilter
+	afterID  string
+	finished bool
+}
+
+// newUserIterator создает итератор пользователей, подходящих под фильтр, с ID больше afterID
+func newUserIterator(repo repository.UserRepository, filter repository.UserFilter, pageSize int, afterID string) *userIterator {
+	filter.OrderBy = getStringPtr("id")
+	filter.OrderDir = getStringPtr("asc")
+	filter.Limit = pageSize
+	filter.Offset = 0
+
+	return &userIterator{
+		repo:    repo,
+		filter:  filter,
+		afterID: afterID,
+	}
+}
+
+// Next возвращает следующую страницу пользователей; пустая страница означает конец обхода
+func (it *userIterator) Next(ctx context.Context) ([]*domain.User, error) {
+	if it.finished {
+		return nil, nil
+	}
+
+	filter := it.filter
+	if it.afterID != "" {
+		filter.AfterID = &it.afterID
+	}
+
+	users, err := it.repo.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(users) < it.filter.Limit {
+		it.finished = true
+	}
+	if len(users) > 0 {
+		it.afterID = users[len(users)-1].ID
+	}
+
+	return users, nil
+}
+
+// digestTaskFilter возвращает фильтр открытых задач дайджеста. Период задается условиями
+// SCHEDULER_DIGEST_WINDOW, без них в дайджест попадают задачи со сроком с сегодняшнего дня
+func (s *SchedulerService) digestTaskFilter(now time.Time) repository.TaskFilter {
+	filter := repository.TaskFilter{
+		Statuses: domain.OpenTaskStatuses,
+		OrderBy:  getStringPtr("due_date"),
+		OrderDir: getStringPtr("asc"),
+	}
+
+	if len(s.config.DigestWindow) == 0 {
+		today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+		filter.DueAfter = &today
+		return filter
+	}
+
+	conditions := make([]datefilter.Condition, 0, len(s.config.DigestWindow))
+	for _, value := range s.config.DigestWindow {
+		// Условия проверяются при загрузке конфигурации
+		if cond, err := datefilter.Parse(value); err == nil {
+			conditions = append(conditions, cond)
 		}
 	}
+	windows := datefilter.Resolve(conditions, now)
+	filter.DueAfter, filter.DueBefore = windows[datefilter.FieldDue].After, windows[datefilter.FieldDue].Before
+	filter.CreatedAfter, filter.CreatedBefore = windows[datefilter.FieldCreated].After, windows[datefilter.FieldCreated].Before
+	filter.UpdatedAfter, filter.UpdatedBefore = windows[datefilter.FieldUpdated].After, windows[datefilter.FieldUpdated].Before
+	filter.CompletedAfter, filter.CompletedBefore = windows[datefilter.FieldCompleted].After, windows[datefilter.FieldCompleted].Before
+	return filter
+}
+
+// digestBatchSize ограничивает число дайджестов в одном событии уведомления
+// и размер страницы пользователей при рассылке
+const digestBatchSize = 100
+
+// publishDigests сохраняет пакет дайджестов и публикует одно событие на весь пакет,
+// чтобы сервис уведомлений разослал их пакетной отправкой
+func (s *SchedulerService) publishDigests(ctx context.Context, run *jobRun, digests []*domain.Notification) {
+	if len(digests) == 0 {
+		return
+	}
 
-	s.logger.Info("Daily digest task completed")
+	if err := s.notificationRepo.CreateBatch(ctx, digests); err != nil {
+		s.logger.Error("Failed to create digest notifications", err, map[string]interface{}{
+			"count": len(digests),
+		})
+		run.addErrors(len(digests), err)
+		return
+	}
+
+	event := &messaging.NotificationEvent{
+		UserIDs:    make([]string, 0, len(digests)),
+		Contents:   make(map[string]string, len(digests)),
+		Title:      digests[0].Title,
+		Type:       string(domain.NotificationTypeDigest),
+		EntityType: "digest",
+		CreatedAt:  digests[0].CreatedAt,
+	}
+	for _, digest := range digests {
+		event.UserIDs = append(event.UserIDs, digest.UserID)
+		event.Contents[digest.UserID] = digest.Content
+	}
+
+	if err := s.producer.PublishNotification(ctx, event); err != nil {
+		s.logger.Error("Failed to publish digest notification event", err, map[string]interface{}{
+			"count": len(digests),
+		})
+	}
 }
 
 // sendDeadlineReminders отправляет напоминания о приближающихся сроках задач
-func (s *SchedulerService) sendDeadlineReminders() {
-	ctx := context.Background()
+func (s *SchedulerService) sendDeadlineReminders(ctx context.Context, run *jobRun) error {
 	s.logger.Info("Running deadline reminder task")
 
 	// Получаем задачи с дедлайном в ближайшие 24 часа
@@ -217,37 +553,37 @@ func (s *SchedulerService) sendDeadlineReminders() {
 	filter := repository.TaskFilter{
 		DueBefore: &dayAfter,
 		DueAfter:  &now,
-		Status:    getTaskStatusPtr(domain.TaskStatusNew, domain.TaskStatusInProgress, domain.TaskStatusOnHold),
+		Statuses:  domain.OpenTaskStatuses,
 	}
 
 	tasks, err := s.taskRepo.GetUpcomingTasks(ctx, 1, filter) // 1 день
 	if err != nil {
-		s.logger.Error("Failed to get upcoming tasks", err)
-		return
+		return fmt.Errorf("failed to get upcoming tasks: %w", err)
 	}
 
 	// Группируем задачи по исполнителям
 	tasksByAssignee := make(map[string][]*domain.Task)
 	for _, task := range tasks {
-		if task.AssigneeID != nil {
-			tasksByAssignee[*task.AssigneeID] = append(tasksByAssignee[*task.AssigneeID], task)
+		for _, assigneeID := range task.AssigneeIDs {
+			tasksByAssignee[assigneeID] = append(tasksByAssignee[assigneeID], task)
 		}
 	}
 
+	// Настройки уведомлений исполнителей загружаются одним запросом
+	assigneeIDs := make([]string, 0, len(tasksByAssignee))
+	for assigneeID := range tasksByAssignee {
+		assigneeIDs = append(assigneeIDs, assigneeID)
+	}
+	settingsByUser, err := s.notificationRepo.GetSettingsForUsers(ctx, assigneeIDs)
+	if err != nil {
+		return fmt.Errorf("failed to get notification settings for deadline reminders: %w", err)
+	}
+
 	// Отправляем уведомления для каждого исполнителя
 	for assigneeID, assigneeTasks := range tasksByAssignee {
-		// Проверяем настройки уведомлений
-		settings, err := s.notificationRepo.GetUserNotificationSettings(ctx, assigneeID)
-		if err != nil {
-			s.logger.Error("Failed to get notification settings", err, map[string]interface{}{
-				"user_id": assigneeID,
-			})
-			continue
-		}
-
 		// Проверяем, включены ли уведомления о дедлайнах
 		dueSoonEnabled := false
-		for _, setting := range settings {
+		for _, setting := range settingsByUser[assigneeID] {
 			if setting.NotificationType == domain.NotificationTypeTaskDueSoon &&
 				(setting.EmailEnabled || setting.WebEnabled) {
 				dueSoonEnabled = true
@@ -289,8 +625,10 @@ func (s *SchedulerService) sendDeadlineReminders() {
 				s.logger.Error("Failed to create deadline notification", err, map[string]interface{}{
 					"task_id": task.ID,
 				})
+				run.addErrors(1, err)
 				continue
 			}
+			run.addProcessed(1)
 
 			// Отправляем событие для обработки уведомления
 			event := &messaging.NotificationEvent{
@@ -313,124 +651,45 @@ func (s *SchedulerService) sendDeadlineReminders() {
 	}
 
 	s.logger.Info("Deadline reminder task completed")
+	return nil
 }
 
 // checkOverdueTasks проверяет просроченные задачи и отправляет уведомления
-func (s *SchedulerService) checkOverdueTasks() {
-	ctx := context.Background()
+func (s *SchedulerService) checkOverdueTasks(ctx context.Context, run *jobRun) error {
 	s.logger.Info("Running overdue tasks check")
 
 	// Получаем просроченные, но не завершенные задачи
 	now := time.Now()
 	filter := repository.TaskFilter{
 		DueBefore: &now,
-		Status:    getTaskStatusPtr(domain.TaskStatusNew, domain.TaskStatusInProgress, domain.TaskStatusOnHold),
+		Statuses:  domain.OpenTaskStatuses,
 	}
 
 	tasks, err := s.taskRepo.GetOverdueTasks(ctx, filter)
 	if err != nil {
-		s.logger.Error("Failed to get overdue tasks", err)
-		return
+		return fmt.Errorf("failed to get overdue tasks: %w", err)
 	}
+	run.addProcessed(len(tasks))
 
-	// Для каждой задачи отправляем уведомление
+	// Для каждой задачи отправляем уведомление всем исполнителям
 	for _, task := range tasks {
 		// Пропускаем задачи без исполнителя
-		if task.AssigneeID == nil {
+		if len(task.AssigneeIDs) == 0 {
 			continue
 		}
 
-		// Проверяем, было ли уже отправлено уведомление о просрочке
-		notificationFilter := repository.NotificationFilter{
-			EntityID:   &task.ID,
-			EntityType: getStringPtr("task"),
-			Types:      []domain.NotificationType{domain.NotificationTypeTaskOverdue},
-		}
-
-		existingNotifications, err := s.notificationRepo.GetUserNotifications(ctx, *task.AssigneeID, notificationFilter)
-		if err != nil {
-			s.logger.Error("Failed to check existing notifications", err, map[string]interface{}{
-				"task_id": task.ID,
-			})
-			continue
-		}
-
-		// Если уведомление уже есть, пропускаем
-		if len(existingNotifications) > 0 {
-			continue
-		}
-
-		// Проверяем настройки уведомлений
-		settings, err := s.notificationRepo.GetUserNotificationSettings(ctx, *task.AssigneeID)
-		if err != nil {
-			s.logger.Error("Failed to get notification settings", err, map[string]interface{}{
-				"user_id": *task.AssigneeID,
-			})
-			continue
-		}
-
-		// Проверяем, включены ли уведомления о просроченных задачах
-		overdueEnabled := false
-		for _, setting := range settings {
-			if setting.NotificationType == domain.NotificationTypeTaskOverdue &&
-				(setting.EmailEnabled || setting.WebEnabled) {
-				overdueEnabled = true
-				break
+		notified := false
+		for _, assigneeID := range task.AssigneeIDs {
+			if s.notifyAssigneeOverdue(ctx, run, task, assigneeID) {
+				notified = true
 			}
 		}
-
-		if !overdueEnabled {
-			continue
-		}
-
-		// Создаем уведомление
-		content := fmt.Sprintf("Срок выполнения задачи \"%s\" истек", task.Title)
-
-		notification := &domain.Notification{
-			UserID:     *task.AssigneeID,
-			Type:       domain.NotificationTypeTaskOverdue,
-			Title:      "Задача просрочена",
-			Content:    content,
-			Status:     domain.NotificationStatusUnread,
-			EntityType: "task",
-			EntityID:   task.ID,
-			CreatedAt:  time.Now(),
-			MetaData: map[string]string{
-				"task_id":    task.ID,
-				"task_title": task.Title,
-				"project_id": task.ProjectID,
-				"due_date":   task.DueDate.Format(time.RFC3339),
-			},
-		}
-
-		// Сохраняем уведомление
-		if err := s.notificationRepo.Create(ctx, notification); err != nil {
-			s.logger.Error("Failed to create overdue notification", err, map[string]interface{}{
-				"task_id": task.ID,
-			})
+		if !notified {
 			continue
 		}
 
-		// Отправляем событие для обработки уведомления
-		event := &messaging.NotificationEvent{
-			UserIDs:    []string{*task.AssigneeID},
-			Title:      notification.Title,
-			Content:    notification.Content,
-			Type:       string(notification.Type),
-			EntityID:   task.ID,
-			EntityType: "task",
-			CreatedAt:  notification.CreatedAt,
-			MetaData:   notification.MetaData,
-		}
-
-		if err := s.producer.PublishNotification(ctx, event); err != nil {
-			s.logger.Error("Failed to publish overdue notification event", err, map[string]interface{}{
-				"task_id": task.ID,
-			})
-		}
-
-		// Также уведомляем создателя задачи, если это не исполнитель
-		if task.CreatedBy != *task.AssigneeID {
+		// Также уведомляем создателя задачи, если он не среди исполнителей
+		if !containsString(task.AssigneeIDs, task.CreatedBy) {
 			creatorNotification := &domain.Notification{
 				UserID:     task.CreatedBy,
 				Type:       domain.NotificationTypeTaskOverdue,
@@ -444,7 +703,7 @@ func (s *SchedulerService) checkOverdueTasks() {
 					"task_id":     task.ID,
 					"task_title":  task.Title,
 					"project_id":  task.ProjectID,
-					"assignee_id": *task.AssigneeID,
+					"assignee_id": task.AssigneeIDs[0],
 					"due_date":    task.DueDate.Format(time.RFC3339),
 				},
 			}
@@ -453,12 +712,20 @@ func (s *SchedulerService) checkOverdueTasks() {
 				s.logger.Error("Failed to create creator overdue notification", err, map[string]interface{}{
 					"task_id": task.ID,
 				})
+				run.addErrors(1, err)
 				continue
 			}
 
-			event.UserIDs = []string{task.CreatedBy}
-			event.Content = creatorNotification.Content
-			event.MetaData = creatorNotification.MetaData
+			event := &messaging.NotificationEvent{
+				UserIDs:    []string{task.CreatedBy},
+				Title:      creatorNotification.Title,
+				Content:    creatorNotification.Content,
+				Type:       string(creatorNotification.Type),
+				EntityID:   task.ID,
+				EntityType: "task",
+				CreatedAt:  creatorNotification.CreatedAt,
+				MetaData:   creatorNotification.MetaData,
+			}
 
 			if err := s.producer.PublishNotification(ctx, event); err != nil {
 				s.logger.Error("Failed to publish creator overdue notification event", err, map[string]interface{}{
@@ -469,11 +736,109 @@ func (s *SchedulerService) checkOverdueTasks() {
 	}
 
 	s.logger.Info("Overdue tasks check completed")
+	return nil
+}
+
+// notifyAssigneeOverdue уведомляет исполнителя о просрочке задачи.
+// Возвращает true, если уведомление было отправлено
+func (s *SchedulerService) notifyAssigneeOverdue(ctx context.Context, run *jobRun, task *domain.Task, assigneeID string) bool {
+	// Проверяем, было ли уже отправлено уведомление о просрочке
+	notificationFilter := repository.NotificationFilter{
+		EntityID:   &task.ID,
+		EntityType: getStringPtr("task"),
+		Types:      []domain.NotificationType{domain.NotificationTypeTaskOverdue},
+	}
+
+	existingNotifications, err := s.notificationRepo.GetUserNotifications(ctx, assigneeID, notificationFilter)
+	if err != nil {
+		s.logger.Error("Failed to check existing notifications", err, map[string]interface{}{
+			"task_id": task.ID,
+		})
+		run.addErrors(1, err)
+		return false
+	}
+
+	// Если уведомление уже есть, пропускаем
+	if len(existingNotifications) > 0 {
+		return false
+	}
+
+	// Проверяем настройки уведомлений
+	settings, err := s.notificationRepo.GetUserNotificationSettings(ctx, assigneeID)
+	if err != nil {
+		s.logger.Error("Failed to get notification settings", err, map[string]interface{}{
+			"user_id": assigneeID,
+		})
+		run.addErrors(1, err)
+		return false
+	}
+
+	// Проверяем, включены ли уведомления о просроченных задачах
+	overdueEnabled := false
+	for _, setting := range settings {
+		if setting.NotificationType == domain.NotificationTypeTaskOverdue &&
+			(setting.EmailEnabled || setting.WebEnabled) {
+			overdueEnabled = true
+			break
+		}
+	}
+
+	if !overdueEnabled {
+		return false
+	}
+
+	// Создаем уведомление
+	content := fmt.Sprintf("Срок выполнения задачи \"%s\" истек", task.Title)
+
+	notification := &domain.Notification{
+		UserID:     assigneeID,
+		Type:       domain.NotificationTypeTaskOverdue,
+		Title:      "Задача просрочена",
+		Content:    content,
+		Status:     domain.NotificationStatusUnread,
+		EntityType: "task",
+		EntityID:   task.ID,
+		CreatedAt:  time.Now(),
+		MetaData: map[string]string{
+			"task_id":    task.ID,
+			"task_title": task.Title,
+			"project_id": task.ProjectID,
+			"due_date":   task.DueDate.Format(time.RFC3339),
+		},
+	}
+
+	// Сохраняем уведомление
+	if err := s.notificationRepo.Create(ctx, notification); err != nil {
+		s.logger.Error("Failed to create overdue notification", err, map[string]interface{}{
+			"task_id": task.ID,
+		})
+		run.addErrors(1, err)
+		return false
+	}
+
+	// Отправляем событие для обработки уведомления
+	event := &messaging.NotificationEvent{
+		UserIDs:    []string{assigneeID},
+		Title:      notification.Title,
+		Content:    notification.Content,
+		Type:       string(notification.Type),
+		EntityID:   task.ID,
+		EntityType: "task",
+		CreatedAt:  notification.CreatedAt,
+		MetaData:   notification.MetaData,
+	}
+
+	if err := s.producer.PublishNotification(ctx, event); err != nil {
+		s.logger.Error("Failed to publish overdue notification event", err, map[string]interface{}{
+			"task_id": task.ID,
+		})
+	}
+
+	return true
 }
 
 // archiveCompletedProjects архивирует завершенные проекты
-func (s *SchedulerService) archiveCompletedProjects() {
-	ctx := context.Background()
+func (s *SchedulerService) archiveCompletedProjects(ctx context.Context, run *jobRun) error {
 	s.logger.Info("Running project archiving task")
 
 	// Получаем завершенные проекты, которые не архивированы
@@ -483,9 +848,9 @@ func (s *SchedulerService) archiveCompletedProjects() {
 
 	projects, err := s.projectRepo.List(ctx, filter)
 	if err != nil {
-		s.logger.Error("Failed to get completed projects", err)
-		return
+		return fmt.Errorf("failed to get completed projects: %w", err)
 	}
+	run.addProcessed(len(projects))
 
 	// Для каждого проекта проверяем, что все задачи завершены и проект не обновлялся более недели
 	now := time.Now()
@@ -506,6 +871,7 @@ func (s *SchedulerService) archiveCompletedProjects() {
 			s.logger.Error("Failed to get project tasks", err, map[string]interface{}{
 				"project_id": project.ID,
 			})
+			run.addErrors(1, err)
 			continue
 		}
 
@@ -530,71 +896,237 @@ func (s *SchedulerService) archiveCompletedProjects() {
 			s.logger.Error("Failed to archive project", err, map[string]interface{}{
 				"project_id": project.ID,
 			})
+			run.addErrors(1, err)
 			continue
 		}
 
-		// Получаем список участников проекта
-		members, err := s.projectRepo.GetMembers(ctx, project.ID)
-		if err != nil {
-			s.logger.Error("Failed to get project members", err, map[string]interface{}{
+		// Уведомляем участников проекта одним событием: получатели раскрываются
+		// и уведомления сохраняются пакетом в сервисе уведомлений
+		event := &messaging.NotificationEvent{
+			RecipientProjectID: project.ID,
+			Persist:            true,
+			Title:              "Проект архивирован",
+			Content:            fmt.Sprintf("Проект \"%s\" был автоматически архивирован", project.Name),
+			Type:               string(domain.NotificationTypeProjectUpdated),
+			EntityID:           project.ID,
+			EntityType:         "project",
+			CreatedAt:          now,
+			MetaData: map[string]string{
+				"project_id":   project.ID,
+				"project_name": project.Name,
+				"old_status":   string(domain.ProjectStatusCompleted),
+				"new_status":   string(domain.ProjectStatusArchived),
+			},
+		}
+
+		if err := s.producer.PublishNotification(ctx, event); err != nil {
+			s.logger.Error("Failed to publish archive notification event", err, map[string]interface{}{
 				"project_id": project.ID,
 			})
+		}
+
+		s.logger.Info("Project archived", map[string]interface{}{
+			"project_id": project.ID,
+		}, map[string]interface{}{
+			"project_name": project.Name,
+		})
+	}
+
+	s.logger.Info("Project archiving task completed")
+	return nil
+}
+
+// expireOwnershipTransfers закрывает неподтвержденные запросы на передачу владения проектом.
+// Владелец проекта при этом не меняется, инициатор получает уведомление об отмене.
+func (s *SchedulerService) expireOwnershipTransfers(ctx context.Context, run *jobRun) error {
+	s.logger.Info("Running ownership transfer expiration task")
+
+	now := time.Now()
+	transfers, err := s.projectRepo.ExpireOwnershipTransfers(ctx, now)
+	if err != nil {
+		return fmt.Errorf("failed to expire ownership transfers: %w", err)
+	}
+	run.addProcessed(len(transfers))
+
+	for _, transfer := range transfers {
+		project, err := s.projectRepo.GetByID(ctx, transfer.ProjectID)
+		if err != nil || project == nil {
+			s.logger.Error("Failed to get project for expired ownership transfer", err, map[string]interface{}{
+				"project_id":  transfer.ProjectID,
+				"transfer_id": transfer.ID,
+			})
+			run.addErrors(1, err)
 			continue
 		}
 
-		// Отправляем уведомления участникам проекта
-		for _, member := range members {
-			notification := &domain.Notification{
-				UserID:     member.UserID,
-				Type:       domain.NotificationTypeProjectUpdated,
-				Title:      "Проект архивирован",
-				Content:    fmt.Sprintf("Проект \"%s\" был автоматически архивирован", project.Name),
-				Status:     domain.NotificationStatusUnread,
-				EntityType: "project",
-				EntityID:   project.ID,
-				CreatedAt:  now,
-				MetaData: map[string]string{
-					"project_id":   project.ID,
-					"project_name": project.Name,
-					"old_status":   string(domain.ProjectStatusCompleted),
-					"new_status":   string(domain.ProjectStatusArchived),
-				},
-			}
+		// Событие служит записью аудита об истечении запроса
+		projectEvent := &messaging.ProjectEvent{
+			ID:          project.ID,
+			Name:        project.Name,
+			Description: project.Description,
+			Status:      string(project.Status),
+			CreatedBy:   project.CreatedBy,
+			UpdatedAt:   now,
+			Type:        "project_ownership_transfer_" + string(domain.OwnershipTransferStatusExpired),
+			Changes: map[string]interface{}{
+				"transfer_id":    transfer.ID,
+				"previous_owner": transfer.FromUserID,
+				"new_owner":      transfer.ToUserID,
+				"status":         transfer.Status,
+			},
+		}
 
-			if err := s.notificationRepo.Create(ctx, notification); err != nil {
-				s.logger.Error("Failed to create archive notification", err, map[string]interface{}{
-					"user_id": member.UserID,
-				})
-				continue
-			}
+		if err := s.producer.PublishProjectUpdated(ctx, projectEvent, projectEvent.Changes); err != nil {
+			s.logger.Error("Failed to publish ownership transfer expiration event", err, map[string]interface{}{
+				"project_id": project.ID,
+			})
+		}
 
-			// Отправляем событие для обработки уведомления
-			event := &messaging.NotificationEvent{
-				UserIDs:    []string{member.UserID},
-				Title:      notification.Title,
-				Content:    notification.Content,
-				Type:       string(notification.Type),
-				EntityID:   project.ID,
-				EntityType: "project",
-				CreatedAt:  notification.CreatedAt,
-				MetaData:   notification.MetaData,
-			}
+		notification := &domain.Notification{
+			ID:         uuid.New().String(),
+			UserID:     transfer.FromUserID,
+			Type:       domain.NotificationTypeProjectUpdated,
+			Title:      "Передача владения отменена",
+			Content:    fmt.Sprintf("Запрос на передачу владения проектом \"%s\" не был подтвержден вовремя", project.Name),
+			Status:     domain.NotificationStatusUnread,
+			EntityType: "project",
+			EntityID:   project.ID,
+			CreatedAt:  now,
+			MetaData: map[string]string{
+				"project_id":   project.ID,
+				"project_name": project.Name,
+				"transfer_id":  transfer.ID,
+				"new_owner":    transfer.ToUserID,
+			},
+		}
 
-			if err := s.producer.PublishNotification(ctx, event); err != nil {
-				s.logger.Error("Failed to publish archive notification event", err, map[string]interface{}{
-					"user_id": member.UserID,
-				})
-			}
+		if err := s.notificationRepo.Create(ctx, notification); err != nil {
+			s.logger.Error("Failed to create ownership transfer expiration notification", err, map[string]interface{}{
+				"user_id": transfer.FromUserID,
+			})
+			run.addErrors(1, err)
+			continue
 		}
 
-		s.logger.Info("Project archived", map[string]interface{}{
-			"project_id": project.ID,
-		}, map[string]interface{}{
-			"project_name": project.Name,
+		event := &messaging.NotificationEvent{
+			UserIDs:    []string{transfer.FromUserID},
+			Title:      notification.Title,
+			Content:    notification.Content,
+			Type:       string(notification.Type),
+			EntityID:   project.ID,
+			EntityType: "project",
+			CreatedAt:  notification.CreatedAt,
+			MetaData:   notification.MetaData,
+		}
+
+		if err := s.producer.PublishNotification(ctx, event); err != nil {
+			s.logger.Error("Failed to publish ownership transfer expiration notification", err, map[string]interface{}{
+				"user_id": transfer.FromUserID,
+			})
+		}
+
+		s.logger.Info("Ownership transfer expired", map[string]interface{}{
+			"project_id":  project.ID,
+			"transfer_id": transfer.ID,
 		})
 	}
 
-	s.logger.Info("Project archiving task completed")
+	s.logger.Info("Ownership transfer expiration task completed", map[string]interface{}{
+		"expired": len(transfers),
+	})
+	return nil
+}
+
+// labelStaleTasks помечает тегом stale задачи бэклога старше StaleTaskDays дней.
+// Проекты, отключившие автоматическую пометку в настройках задач, пропускаются
+func (s *SchedulerService) labelStaleTasks(ctx context.Context, run *jobRun) error {
+	s.logger.Info("Running stale task labeling")
+
+	createdBefore := time.Now().AddDate(0, 0, -s.config.StaleTaskDays)
+	labels, err := s.taskRepo.LabelStaleTasks(ctx, createdBefore, domain.TaskTagStale)
+	if err != nil {
+		return fmt.Errorf("failed to label stale tasks: %w", err)
+	}
+	run.addProcessed(len(labels))
+
+	projects := make(map[string]int)
+	for _, label := range labels {
+		projects[label.ProjectID]++
+	}
+
+	s.logger.Info("Stale task labeling completed", map[string]interface{}{
+		"labeled":  len(labels),
+		"projects": len(projects),
+	})
+	return nil
+}
+
+// remindStaleInbox напоминает пользователям о записях входящих старше InboxReminderDays дней
+func (s *SchedulerService) remindStaleInbox(ctx context.Context, run *jobRun) error {
+	s.logger.Info("Running inbox reminder task")
+
+	createdBefore := time.Now().AddDate(0, 0, -s.config.InboxReminderDays)
+	sent, err := s.inboxSvc.RemindStale(ctx, createdBefore)
+	if err != nil {
+		return fmt.Errorf("failed to send inbox reminders: %w", err)
+	}
+	run.addProcessed(sent)
+
+	s.logger.Info("Inbox reminder task completed", map[string]interface{}{
+		"reminded": sent,
+	})
+	return nil
+}
+
+// createScheduledTasks создает задачи, время создания которых наступило
+func (s *SchedulerService) createScheduledTasks(ctx context.Context, run *jobRun) error {
+	created, err := s.scheduledTaskSvc.CreateDue(ctx, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to create scheduled tasks: %w", err)
+	}
+	run.addProcessed(created)
+
+	if created > 0 {
+		s.logger.Info("Scheduled tasks created", map[string]interface{}{
+			"created": created,
+		})
+	}
+
+	return nil
+}
+
+// createRecurringTasks создает следующие задачи серий, текущие задачи которых завершены
+func (s *SchedulerService) createRecurringTasks(ctx context.Context, run *jobRun) error {
+	created, err := s.recurrenceSvc.CreateDue(ctx, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to create recurring tasks: %w", err)
+	}
+	run.addProcessed(created)
+
+	if created > 0 {
+		s.logger.Info("Recurring tasks created", map[string]interface{}{
+			"created": created,
+		})
+	}
+
+	return nil
+}
+
+// purgeDeletedAttachments удаляет из хранилища файлы удаленных вложений
+func (s *SchedulerService) purgeDeletedAttachments(ctx context.Context, run *jobRun) error {
+	purged, err := s.attachmentSvc.PurgeDeleted(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to purge deleted attachments: %w", err)
+	}
+	run.addProcessed(purged)
+
+	if purged > 0 {
+		s.logger.Info("Deleted attachment files purged", map[string]interface{}{
+			"purged": purged,
+		})
+	}
+
+	return nil
 }
 
 // Вспомогательные функции
@@ -650,15 +1182,153 @@ func getStringPtr(s string) *string {
 	return &s
 }
 
-func getTaskStatusPtr(statuses ...domain.TaskStatus) *domain.TaskStatus {
-	if len(statuses) == 0 {
-		return nil
+func getProjectStatusPtr(status domain.ProjectStatus) *domain.ProjectStatus {
+	return &status
+}
+
+// projectMetricsRefreshBatch ограничивает число проектов, пересчитываемых за один запуск
+const projectMetricsRefreshBatch = 100
+
+// refreshProjectMetrics пересчитывает метрики проектов, помеченные как устаревшие
+func (s *SchedulerService) refreshProjectMetrics(ctx context.Context, run *jobRun) error {
+	_, err := s.refreshDirtyProjectMetrics(ctx, run)
+	return err
+}
+
+// refreshDirtyProjectMetrics пересчитывает очередную партию устаревших метрик и возвращает
+// число успешно пересчитанных проектов
+func (s *SchedulerService) refreshDirtyProjectMetrics(ctx context.Context, run *jobRun) (int, error) {
+	projectIDs, err := s.metricsRepo.ListDirty(ctx, projectMetricsRefreshBatch)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list dirty project metrics: %w", err)
 	}
-	// Если передано несколько статусов, возвращаем первый
-	// (в репозитории нужно будет изменить фильтрацию для поддержки множественных статусов)
-	return &statuses[0]
+
+	refreshed := 0
+	for _, projectID := range projectIDs {
+		if _, err := s.projectSvc.RefreshMetrics(ctx, projectID); err != nil {
+			s.logger.Error("Failed to refresh project metrics", err, map[string]interface{}{
+				"project_id": projectID,
+			})
+			run.addErrors(1, err)
+			continue
+		}
+		refreshed++
+	}
+
+	if refreshed > 0 {
+		s.logger.Info("Project metrics refreshed", map[string]interface{}{
+			"count": refreshed,
+		})
+	}
+
+	run.addProcessed(refreshed)
+	return refreshed, nil
 }
 
-func getProjectStatusPtr(status domain.ProjectStatus) *domain.ProjectStatus {
-	return &status
+// markOverdueProjectMetrics помечает устаревшими метрики проектов, в которых задачи стали
+// просроченными: такие изменения происходят со временем и не отслеживаются триггерами
+func (s *SchedulerService) markOverdueProjectMetrics(ctx context.Context, run *jobRun) error {
+	count, err := s.metricsRepo.MarkOverdueDirty(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to mark overdue project metrics: %w", err)
+	}
+	run.addProcessed(count)
+
+	if count > 0 {
+		s.logger.Info("Project metrics marked for overdue refresh", map[string]interface{}{
+			"count": count,
+		})
+	}
+
+	return nil
+}
+
+// snapshotProjectMetrics сохраняет ежедневные снимки метрик проектов для графиков динамики
+func (s *SchedulerService) snapshotProjectMetrics(ctx context.Context, run *jobRun) error {
+	s.logger.Info("Running project metrics snapshot task")
+
+	// Перед снимком пересчитываем устаревшие метрики, включая новые просроченные задачи
+	if _, err := s.metricsRepo.MarkOverdueDirty(ctx); err != nil {
+		s.logger.Error("Failed to mark overdue project metrics", err)
+		run.addErrors(1, err)
+	}
+	for i := 0; i < 10; i++ {
+		refreshed, err := s.refreshDirtyProjectMetrics(ctx, run)
+		if err != nil {
+			s.logger.Error("Failed to refresh project metrics before snapshot", err)
+			run.addErrors(1, err)
+			break
+		}
+		if refreshed < projectMetricsRefreshBatch {
+			break
+		}
+	}
+
+	count, err := s.metricsRepo.SaveSnapshots(ctx, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to save project metrics snapshots: %w", err)
+	}
+
+	s.logger.Info("Project metrics snapshots saved", map[string]interface{}{
+		"count": count,
+	})
+	return nil
+}
+
+// reconcileUnreadCounts удаляет счетчики непрочитанных уведомлений, разошедшиеся с базой
+func (s *SchedulerService) reconcileUnreadCounts(ctx context.Context, run *jobRun) error {
+	s.logger.Info("Running unread counts reconciliation task")
+
+	drifted, err := s.unreadCounter.ReconcileUnreadCounts(ctx, 500)
+	run.addProcessed(drifted)
+	if err != nil {
+		return fmt.Errorf("failed to reconcile unread counts: %w", err)
+	}
+
+	s.logger.Info("Unread counts reconciled", map[string]interface{}{
+		"drifted": drifted,
+	})
+	return nil
+}
+
+// taskViewFlushBatchSize - число просмотров задач, сохраняемых в базу одним запросом
+const taskViewFlushBatchSize = 500
+
+// flushTaskViews переносит в базу просмотры задач, записанные в Redis
+func (s *SchedulerService) flushTaskViews(ctx context.Context, run *jobRun) error {
+	flushed, err := s.taskViewSvc.Flush(ctx, taskViewFlushBatchSize)
+	run.addProcessed(flushed)
+	if err != nil {
+		return fmt.Errorf("failed to flush task views: %w", err)
+	}
+
+	if flushed > 0 {
+		s.logger.Info("Task views flushed", map[string]interface{}{
+			"count": flushed,
+		})
+	}
+	return nil
+}
+
+// checkConsistency сверяет производные данные с базой и исправляет расхождения.
+// Отчет сохраняется и доступен администраторам через API
+func (s *SchedulerService) checkConsistency(ctx context.Context, run *jobRun) error {
+	s.logger.Info("Running consistency check task")
+
+	report, err := s.consistencySvc.Run(ctx, domain.ConsistencyRequest{Repair: true})
+	if err != nil {
+		return fmt.Errorf("failed to run consistency check: %w", err)
+	}
+
+	for _, result := range report.Results {
+		run.addProcessed(result.Checked)
+		if result.Error != "" {
+			run.addErrors(1, errors.New(result.Error))
+		}
+	}
+	if report.Status == domain.ConsistencyRunFailed {
+		return fmt.Errorf("consistency check %s failed", report.ID)
+	}
+
+	return nil
 }
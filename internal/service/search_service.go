@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/internal/repository"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
+	"github.com/nurlyy/task_manager/pkg/logger"
+)
+
+// Стандартные ошибки
+var (
+	ErrSearchQueryEmpty = apperrors.New(apperrors.CodeMissingQuery, "search query is empty")
+)
+
+const (
+	// typeaheadDefaultLimit определяет количество результатов каждого типа по умолчанию
+	typeaheadDefaultLimit = 5
+	// typeaheadMaxLimit определяет максимальное количество результатов каждого типа
+	typeaheadMaxLimit = 20
+	// typeaheadMaxQueryLength ограничивает длину поискового запроса
+	typeaheadMaxQueryLength = 100
+	// peopleSearchDefaultLimit определяет количество найденных пользователей по умолчанию
+	peopleSearchDefaultLimit = 10
+	// peopleSearchMaxLimit определяет максимальное количество найденных пользователей
+	peopleSearchMaxLimit = 50
+)
+
+// SearchService представляет бизнес-логику быстрого поиска
+type SearchService struct {
+	searchRepo repository.SearchRepository
+	userRepo   repository.UserRepository
+	projectSvc *ProjectService
+	featureSvc *FeatureFlagService
+	logger     logger.Logger
+}
+
+// NewSearchService создает новый экземпляр SearchService
+func NewSearchService(
+	searchRepo repository.SearchRepository,
+	userRepo repository.UserRepository,
+	projectSvc *ProjectService,
+	featureSvc *FeatureFlagService,
+	logger logger.Logger,
+) *SearchService {
+	return &SearchService{
+		searchRepo: searchRepo,
+		userRepo:   userRepo,
+		projectSvc: projectSvc,
+		featureSvc: featureSvc,
+		logger:     logger,
+	}
+}
+
+// Typeahead возвращает первые limit совпадений среди задач, проектов, пользователей, решений и вики-страниц
+func (s *SearchService) Typeahead(ctx context.Context, query string, limit int, userID string) (*domain.TypeaheadResult, error) {
+	query = normalizeTypeaheadQuery(query)
+	if query == "" {
+		return nil, ErrSearchQueryEmpty
+	}
+
+	if limit <= 0 {
+		limit = typeaheadDefaultLimit
+	} else if limit > typeaheadMaxLimit {
+		limit = typeaheadMaxLimit
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Администраторы видят задачи, проекты и пользователей всех проектов
+	allProjects := user != nil && user.IsAdmin()
+	extended := s.featureSvc.IsEnabled(ctx, FeatureTypeaheadExtended, user)
+
+	pattern := escapeLikePattern(query)
+
+	tasks, err := s.searchRepo.SearchTasks(ctx, pattern, userID, allProjects, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	projects, err := s.searchRepo.SearchProjects(ctx, pattern, userID, allProjects, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	users, err := s.searchRepo.SearchUsers(ctx, pattern, userID, allProjects, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	// Решения и вики-страницы ищутся только при включенном флаге расширенного поиска
+	var decisions, wikiPages []*domain.TypeaheadItem
+	if extended {
+		decisions, err = s.searchRepo.SearchDecisions(ctx, pattern, userID, allProjects, limit)
+		if err != nil {
+			return nil, err
+		}
+
+		wikiPages, err = s.searchRepo.SearchWikiPages(ctx, pattern, userID, allProjects, limit)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	result := &domain.TypeaheadResult{
+		Query:     query,
+		Tasks:     nonNilTypeaheadItems(tasks),
+		Projects:  nonNilTypeaheadItems(projects),
+		Users:     nonNilTypeaheadItems(users),
+		Decisions: nonNilTypeaheadItems(decisions),
+		WikiPages: nonNilTypeaheadItems(wikiPages),
+	}
+
+	return result, nil
+}
+
+// SearchPeople ищет пользователей по имени и email с нечетким совпадением.
+// Если указан проект, поиск ограничивается его участниками, иначе - пользователями
+// из общих с текущим пользователем проектов. Результаты упорядочены
+// по качеству совпадения с учетом недавней совместной работы с текущим пользователем
+func (s *SearchService) SearchPeople(ctx context.Context, query string, projectID *string, limit int, userID string) ([]*domain.UserBrief, error) {
+	query = normalizeTypeaheadQuery(query)
+	if query == "" {
+		return nil, ErrSearchQueryEmpty
+	}
+
+	if limit <= 0 {
+		limit = peopleSearchDefaultLimit
+	} else if limit > peopleSearchMaxLimit {
+		limit = peopleSearchMaxLimit
+	}
+
+	if projectID != nil {
+		if !s.projectSvc.hasAccessToProject(ctx, *projectID, userID) {
+			return nil, ErrProjectNotFound
+		}
+	}
+
+	users, err := s.userRepo.SearchPeople(ctx, repository.UserSearchFilter{
+		Query:       query,
+		RequesterID: userID,
+		ProjectID:   projectID,
+		Limit:       limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if users == nil {
+		users = []*domain.UserBrief{}
+	}
+	return users, nil
+}
+
+// normalizeTypeaheadQuery приводит поисковый запрос к единому виду для поиска и кэширования
+func normalizeTypeaheadQuery(query string) string {
+	query = strings.ToLower(strings.Join(strings.Fields(query), " "))
+	if runes := []rune(query); len(runes) > typeaheadMaxQueryLength {
+		query = string(runes[:typeaheadMaxQueryLength])
+	}
+	return query
+}
+
+// escapeLikePattern экранирует спецсимволы шаблонов LIKE
+func escapeLikePattern(s string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return replacer.Replace(s)
+}
+
+// nonNilTypeaheadItems заменяет nil на пустой срез, чтобы в JSON возвращался []
+func nonNilTypeaheadItems(items []*domain.TypeaheadItem) []*domain.TypeaheadItem {
+	if items == nil {
+		return []*domain.TypeaheadItem{}
+	}
+	return items
+}
@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/nurlyy/task_manager/pkg/config"
+)
+
+// smsProvider описывает провайдера отправки SMS
+type smsProvider interface {
+	// send отправляет текст на номер в формате E.164
+	send(ctx context.Context, phone, text string) error
+}
+
+// newSMSProvider создает провайдера, выбранного в конфигурации; nil означает, что SMS отключены
+func newSMSProvider(cfg *config.SMSConfig) (smsProvider, error) {
+	client := &http.Client{Timeout: cfg.Timeout}
+
+	switch strings.ToLower(cfg.Provider) {
+	case "":
+		return nil, nil
+	case "twilio":
+		if cfg.Twilio.AccountSID == "" || cfg.Twilio.AuthToken == "" || cfg.Twilio.From == "" {
+			return nil, fmt.Errorf("twilio account SID, auth token and sender number are required")
+		}
+		return &twilioProvider{
+			client: client,
+			apiURL: strings.TrimRight(cfg.Twilio.APIURL, "/"),
+			config: cfg.Twilio,
+		}, nil
+	case "sns":
+		if cfg.SNS.Region == "" || cfg.SNS.AccessKeyID == "" || cfg.SNS.SecretAccessKey == "" {
+			return nil, fmt.Errorf("aws region and credentials are required for SNS")
+		}
+		return &snsProvider{
+			client:   client,
+			endpoint: "https://sns." + cfg.SNS.Region + ".amazonaws.com/",
+			config:   cfg.SNS,
+		}, nil
+	}
+	return nil, fmt.Errorf("unsupported SMS provider %q", cfg.Provider)
+}
+
+// twilioProvider отправляет SMS через Twilio Messages API
+type twilioProvider struct {
+	client *http.Client
+	apiURL string
+	config config.TwilioConfig
+}
+
+// send отправляет SMS через Twilio
+func (p *twilioProvider) send(ctx context.Context, phone, text string) error {
+	form := url.Values{}
+	form.Set("To", phone)
+	form.Set("From", p.config.From)
+	form.Set("Body", text)
+
+	endpoint := p.apiURL + "/2010-04-01/Accounts/" + url.PathEscape(p.config.AccountSID) + "/Messages.json"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to create Twilio request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(p.config.AccountSID, p.config.AuthToken)
+
+	return doSMSRequest(p.client, req)
+}
+
+// snsProvider отправляет SMS через Amazon SNS (действие Publish с подписью Signature Version 4)
+type snsProvider struct {
+	client   *http.Client
+	endpoint string
+	config   config.SNSConfig
+}
+
+// send отправляет транзакционное SMS через SNS
+func (p *snsProvider) send(ctx context.Context, phone, text string) error {
+	form := url.Values{}
+	form.Set("Action", "Publish")
+	form.Set("Version", "2010-03-31")
+	form.Set("PhoneNumber", phone)
+	form.Set("Message", text)
+	// Транзакционные SMS доставляются в приоритете и не блокируются по времени суток
+	form.Set("MessageAttributes.entry.1.Name", "AWS.SNS.SMS.SMSType")
+	form.Set("MessageAttributes.entry.1.Value.DataType", "String")
+	form.Set("MessageAttributes.entry.1.Value.StringValue", "Transactional")
+	if p.config.SenderID != "" {
+		form.Set("MessageAttributes.entry.2.Name", "AWS.SNS.SMS.SenderID")
+		form.Set("MessageAttributes.entry.2.Value.DataType", "String")
+		form.Set("MessageAttributes.entry.2.Value.StringValue", p.config.SenderID)
+	}
+	body := form.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.endpoint, strings.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create SNS request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+	p.sign(req, []byte(body), time.Now().UTC())
+
+	return doSMSRequest(p.client, req)
+}
+
+// sign подписывает запрос к SNS по схеме AWS Signature Version 4
+func (p *snsProvider) sign(req *http.Request, body []byte, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+
+	payloadHash := sha256.Sum256(body)
+	signedHeaders := "content-type;host;x-amz-date"
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		"/",
+		"",
+		"content-type:" + req.Header.Get("Content-Type") + "\n" +
+			"host:" + req.URL.Host + "\n" +
+			"x-amz-date:" + amzDate + "\n",
+		signedHeaders,
+		hex.EncodeToString(payloadHash[:]),
+	}, "\n")
+
+	scope := date + "/" + p.config.Region + "/sns/aws4_request"
+	canonicalHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(canonicalHash[:])
+
+	key := hmacSHA256([]byte("AWS4"+p.config.SecretAccessKey), date)
+	key = hmacSHA256(key, p.config.Region)
+	key = hmacSHA256(key, "sns")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		p.config.AccessKeyID, scope, signedHeaders, signature,
+	))
+}
+
+// hmacSHA256 вычисляет HMAC-SHA256 от строки
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// doSMSRequest выполняет запрос к API провайдера SMS
+func doSMSRequest(client *http.Client, req *http.Request) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send SMS: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("SMS provider returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+
+	return nil
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/internal/repository"
+	"github.com/nurlyy/task_manager/pkg/config"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
+	"github.com/nurlyy/task_manager/pkg/logger"
+)
+
+var (
+	ErrSMSNotConfigured = apperrors.New(apperrors.CodeSMSNotConfigured, "sms provider is not configured")
+	ErrSMSQuotaExceeded = apperrors.New(apperrors.CodeSMSQuotaExceeded, "monthly sms quota exceeded")
+)
+
+// smsTextLimit ограничивает длину SMS двумя сегментами кириллицы
+const smsTextLimit = 134
+
+// smsRule описывает критический тип уведомления; пустой Priority подходит для задач с любым приоритетом
+type smsRule struct {
+	Type     domain.NotificationType
+	Priority domain.TaskPriority
+}
+
+// SMSSender обеспечивает отправку SMS о критических событиях с учетом месячной квоты пользователя
+type SMSSender struct {
+	provider smsProvider
+	smsRepo  repository.SMSRepository
+	rules    []smsRule
+	quota    int
+	logger   logger.Logger
+}
+
+// NewSMSSender создает новый экземпляр SMSSender.
+// Если провайдер настроен с ошибкой, SMS отключаются, а ошибка пишется в лог
+func NewSMSSender(cfg *config.SMSConfig, smsRepo repository.SMSRepository, logger logger.Logger) *SMSSender {
+	provider, err := newSMSProvider(cfg)
+	if err != nil {
+		logger.Error("SMS notifications disabled", err, map[string]interface{}{
+			"provider": cfg.Provider,
+		})
+	}
+
+	var rules []smsRule
+	for _, raw := range cfg.CriticalTypes {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+		notificationType, priority, _ := strings.Cut(raw, ":")
+		rules = append(rules, smsRule{
+			Type:     domain.NotificationType(notificationType),
+			Priority: domain.TaskPriority(priority),
+		})
+	}
+
+	return &SMSSender{
+		provider: provider,
+		smsRepo:  smsRepo,
+		rules:    rules,
+		quota:    cfg.MonthlyQuota,
+		logger:   logger,
+	}
+}
+
+// Enabled сообщает, настроен ли провайдер SMS
+func (s *SMSSender) Enabled() bool {
+	return s.provider != nil
+}
+
+// MonthlyQuota возвращает месячную квоту SMS на пользователя
+func (s *SMSSender) MonthlyQuota() int {
+	return s.quota
+}
+
+// NeedsPriority сообщает, зависит ли критичность уведомления данного типа от приоритета задачи
+func (s *SMSSender) NeedsPriority(notificationType domain.NotificationType) bool {
+	for _, rule := range s.rules {
+		if rule.Type == notificationType && rule.Priority != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// IsCritical проверяет, отправляется ли уведомление данного типа по SMS
+func (s *SMSSender) IsCritical(notificationType domain.NotificationType, priority domain.TaskPriority) bool {
+	for _, rule := range s.rules {
+		if rule.Type == notificationType && (rule.Priority == "" || rule.Priority == priority) {
+			return true
+		}
+	}
+	return false
+}
+
+// SendNotification отправляет уведомление по SMS, если у пользователя подтвержден телефон и SMS включены
+func (s *SMSSender) SendNotification(ctx context.Context, userID string, notification *domain.Notification) error {
+	if !s.Enabled() {
+		return ErrSMSNotConfigured
+	}
+
+	settings, err := s.smsRepo.GetSettings(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if settings == nil || !settings.Verified() || !settings.Enabled {
+		return nil
+	}
+
+	text := notification.Title
+	if notification.Content != "" {
+		text += ": " + notification.Content
+	}
+	return s.Send(ctx, userID, settings.Phone, truncateRunes(text, smsTextLimit))
+}
+
+// Send отправляет SMS на номер пользователя, учитывая его в месячной квоте
+func (s *SMSSender) Send(ctx context.Context, userID, phone, text string) error {
+	if !s.Enabled() {
+		return ErrSMSNotConfigured
+	}
+
+	reserved, err := s.smsRepo.ReserveMonthlyUsage(ctx, userID, smsMonth(time.Now()), s.quota)
+	if err != nil {
+		return err
+	}
+	if !reserved {
+		return ErrSMSQuotaExceeded
+	}
+
+	if err := s.provider.send(ctx, phone, text); err != nil {
+		return fmt.Errorf("failed to send SMS: %w", err)
+	}
+
+	s.logger.Debug("SMS sent", map[string]interface{}{
+		"user_id": userID,
+	})
+	return nil
+}
+
+// smsMonth возвращает первый день месяца, по которому учитывается квота
+func smsMonth(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,222 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/internal/repository"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
+	"github.com/nurlyy/task_manager/pkg/logger"
+)
+
+var (
+	ErrSMSPhoneNotSet      = apperrors.New(apperrors.CodePhoneNotSet, "phone number is not set")
+	ErrSMSPhoneNotVerified = apperrors.New(apperrors.CodePhoneNotVerified, "phone number is not verified")
+	ErrSMSCodeInvalid      = apperrors.New(apperrors.CodeInvalidCode, "invalid verification code")
+	ErrSMSCodeExpired      = apperrors.New(apperrors.CodeCodeExpired, "verification code expired")
+	ErrSMSTooManyAttempts  = apperrors.New(apperrors.CodeTooManyAttempts, "too many verification attempts")
+)
+
+// smsMaxCodeAttempts ограничивает число попыток ввода одного кода подтверждения
+const smsMaxCodeAttempts = 5
+
+// SMSService представляет бизнес-логику привязки и подтверждения телефона для SMS-уведомлений
+type SMSService struct {
+	smsRepo   repository.SMSRepository
+	smsSender *SMSSender
+	codeTTL   time.Duration
+	logger    logger.Logger
+}
+
+// NewSMSService создает новый экземпляр SMSService
+func NewSMSService(smsRepo repository.SMSRepository, smsSender *SMSSender, codeTTL time.Duration, logger logger.Logger) *SMSService {
+	return &SMSService{
+		smsRepo:   smsRepo,
+		smsSender: smsSender,
+		codeTTL:   codeTTL,
+		logger:    logger,
+	}
+}
+
+// GetStatus возвращает состояние SMS-уведомлений пользователя
+func (s *SMSService) GetStatus(ctx context.Context, userID string) (*domain.SMSStatus, error) {
+	settings, err := s.smsRepo.GetSettings(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	sent, err := s.smsRepo.GetMonthlyUsage(ctx, userID, smsMonth(time.Now()))
+	if err != nil {
+		return nil, err
+	}
+
+	status := &domain.SMSStatus{
+		Available:     s.smsSender.Enabled(),
+		SentThisMonth: sent,
+		MonthlyQuota:  s.smsSender.MonthlyQuota(),
+	}
+	if settings != nil {
+		status.Phone = maskPhone(settings.Phone)
+		status.Verified = settings.Verified()
+		status.VerifiedAt = settings.VerifiedAt
+		status.Enabled = settings.Enabled
+	}
+	return status, nil
+}
+
+// SetPhone привязывает телефон и отправляет на него код подтверждения.
+// До подтверждения SMS-уведомления не отправляются
+func (s *SMSService) SetPhone(ctx context.Context, userID string, req domain.SMSPhoneRequest) (*domain.SMSStatus, error) {
+	if !s.smsSender.Enabled() {
+		return nil, ErrSMSNotConfigured
+	}
+
+	settings, err := s.smsRepo.GetSettings(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if settings == nil {
+		settings = &domain.SMSSettings{
+			UserID:    userID,
+			Enabled:   true,
+			CreatedAt: now,
+		}
+	}
+
+	code, err := generateSMSCode()
+	if err != nil {
+		return nil, err
+	}
+
+	// Код отправляется до сохранения, чтобы при ошибке провайдера не затереть подтвержденный номер
+	if err := s.smsSender.Send(ctx, userID, req.Phone, fmt.Sprintf("Код подтверждения: %s", code)); err != nil {
+		if !errors.Is(err, ErrSMSQuotaExceeded) {
+			s.logger.Error("Failed to send SMS verification code", err, map[string]interface{}{
+				"user_id": userID,
+			})
+		}
+		return nil, err
+	}
+
+	expiresAt := now.Add(s.codeTTL)
+	settings.Phone = req.Phone
+	settings.VerifiedAt = nil
+	settings.CodeHash = hashSMSCode(userID, code)
+	settings.CodeExpiresAt = &expiresAt
+	settings.CodeAttempts = 0
+	settings.UpdatedAt = now
+
+	if err := s.smsRepo.SaveSettings(ctx, settings); err != nil {
+		return nil, err
+	}
+
+	return s.GetStatus(ctx, userID)
+}
+
+// Verify подтверждает телефон кодом из SMS
+func (s *SMSService) Verify(ctx context.Context, userID string, req domain.SMSVerifyRequest) (*domain.SMSStatus, error) {
+	settings, err := s.smsRepo.GetSettings(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if settings == nil || settings.CodeHash == "" || settings.CodeExpiresAt == nil {
+		return nil, ErrSMSPhoneNotSet
+	}
+	if settings.CodeAttempts >= smsMaxCodeAttempts {
+		return nil, ErrSMSTooManyAttempts
+	}
+	if time.Now().After(*settings.CodeExpiresAt) {
+		return nil, ErrSMSCodeExpired
+	}
+
+	expected := hashSMSCode(userID, req.Code)
+	if subtle.ConstantTimeCompare([]byte(expected), []byte(settings.CodeHash)) != 1 {
+		settings.CodeAttempts++
+		settings.UpdatedAt = time.Now()
+		if err := s.smsRepo.SaveSettings(ctx, settings); err != nil {
+			return nil, err
+		}
+		return nil, ErrSMSCodeInvalid
+	}
+
+	now := time.Now()
+	settings.VerifiedAt = &now
+	settings.CodeHash = ""
+	settings.CodeExpiresAt = nil
+	settings.CodeAttempts = 0
+	settings.UpdatedAt = now
+
+	if err := s.smsRepo.SaveSettings(ctx, settings); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("SMS phone verified", map[string]interface{}{
+		"user_id": userID,
+	})
+
+	return s.GetStatus(ctx, userID)
+}
+
+// UpdateSettings включает или отключает SMS-уведомления
+func (s *SMSService) UpdateSettings(ctx context.Context, userID string, req domain.SMSSettingsRequest) (*domain.SMSStatus, error) {
+	settings, err := s.smsRepo.GetSettings(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if settings == nil {
+		return nil, ErrSMSPhoneNotSet
+	}
+	if !settings.Verified() {
+		return nil, ErrSMSPhoneNotVerified
+	}
+
+	settings.Enabled = *req.Enabled
+	settings.UpdatedAt = time.Now()
+	if err := s.smsRepo.SaveSettings(ctx, settings); err != nil {
+		return nil, err
+	}
+
+	return s.GetStatus(ctx, userID)
+}
+
+// DeletePhone отвязывает телефон пользователя
+func (s *SMSService) DeletePhone(ctx context.Context, userID string) error {
+	return s.smsRepo.DeleteSettings(ctx, userID)
+}
+
+// generateSMSCode генерирует шестизначный код подтверждения
+func generateSMSCode() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1000000))
+	if err != nil {
+		return "", fmt.Errorf("failed to generate verification code: %w", err)
+	}
+	return fmt.Sprintf("%06d", n.Int64()), nil
+}
+
+// hashSMSCode хеширует код подтверждения вместе с ID пользователя
+func hashSMSCode(userID, code string) string {
+	sum := sha256.Sum256([]byte(userID + ":" + code))
+	return hex.EncodeToString(sum[:])
+}
+
+// maskPhone скрывает середину номера телефона
+func maskPhone(phone string) string {
+	if len(phone) <= 6 {
+		return phone
+	}
+	masked := []byte(phone)
+	for i := 2; i < len(masked)-4; i++ {
+		masked[i] = '*'
+	}
+	return string(masked)
+}
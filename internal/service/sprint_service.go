@@ -0,0 +1,546 @@
+package service
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/internal/repository"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
+	"github.com/nurlyy/task_manager/pkg/logger"
+)
+
+var (
+	ErrSprintNotFound        = apperrors.New(apperrors.CodeSprintNotFound, "sprint not found")
+	ErrSprintActive          = apperrors.New(apperrors.CodeSprintActive, "project already has an active sprint")
+	ErrInvalidSprintState    = apperrors.New(apperrors.CodeInvalidSprintState, "operation is not allowed in the current sprint state")
+	ErrInvalidSprintDates    = apperrors.New(apperrors.CodeInvalidSprintDates, "sprint must end after it starts and last at most 90 days")
+	ErrSprintProjectMismatch = apperrors.New(apperrors.CodeSprintProjectMismatch, "tasks and target sprint must belong to the sprint project")
+)
+
+// sprintMaxDuration ограничивает длительность спринта и тем самым число точек диаграммы сгорания
+const sprintMaxDuration = 90 * 24 * time.Hour
+
+// burndownStep - интервал между точками диаграммы сгорания
+const burndownStep = 24 * time.Hour
+
+// SprintService представляет бизнес-логику спринтов проекта
+type SprintService struct {
+	sprintRepo repository.SprintRepository
+	taskRepo   repository.TaskRepository
+	projectSvc *ProjectService
+	taskSvc    *TaskService
+	logger     logger.Logger
+}
+
+// NewSprintService создает новый экземпляр SprintService
+func NewSprintService(
+	sprintRepo repository.SprintRepository,
+	taskRepo repository.TaskRepository,
+	projectSvc *ProjectService,
+	taskSvc *TaskService,
+	logger logger.Logger,
+) *SprintService {
+	return &SprintService{
+		sprintRepo: sprintRepo,
+		taskRepo:   taskRepo,
+		projectSvc: projectSvc,
+		taskSvc:    taskSvc,
+		logger:     logger,
+	}
+}
+
+// Create создает запланированный спринт в проекте
+func (s *SprintService) Create(ctx context.Context, projectID string, req domain.SprintCreateRequest, userID string) (*domain.Sprint, error) {
+	if err := s.projectSvc.ensureProjectWritable(ctx, projectID); err != nil {
+		return nil, err
+	}
+
+	if err := checkSprintDates(req.StartDate, req.EndDate); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	sprint := &domain.Sprint{
+		ID:        uuid.New().String(),
+		ProjectID: projectID,
+		Name:      req.Name,
+		Goal:      req.Goal,
+		Status:    domain.SprintStatusPlanned,
+		StartDate: req.StartDate,
+		EndDate:   req.EndDate,
+		CreatedBy: userID,
+		CreatedAt: now,
+		UpdatedAt: now,
+		Progress:  &domain.SprintProgress{},
+	}
+
+	if err := s.sprintRepo.Create(ctx, sprint); err != nil {
+		s.logger.Error("Failed to create sprint", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return nil, err
+	}
+
+	return sprint, nil
+}
+
+// List возвращает спринты проекта с прогрессом задач
+func (s *SprintService) List(ctx context.Context, projectID string) ([]*domain.Sprint, error) {
+	sprints, err := s.sprintRepo.ListByProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.fillProgress(ctx, sprints); err != nil {
+		return nil, err
+	}
+
+	return sprints, nil
+}
+
+// Get возвращает спринт с прогрессом задач
+func (s *SprintService) Get(ctx context.Context, id string, userID string) (*domain.Sprint, error) {
+	sprint, err := s.getAccessibleSprint(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.fillProgress(ctx, []*domain.Sprint{sprint}); err != nil {
+		return nil, err
+	}
+
+	return sprint, nil
+}
+
+// Update обновляет спринт. Даты завершенного спринта не меняются
+func (s *SprintService) Update(ctx context.Context, id string, req domain.SprintUpdateRequest, userID string) (*domain.Sprint, error) {
+	sprint, err := s.getAccessibleSprint(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkCanEdit(ctx, sprint.ProjectID, userID); err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		sprint.Name = *req.Name
+	}
+	if req.Goal != nil {
+		sprint.Goal = *req.Goal
+	}
+	if req.StartDate != nil || req.EndDate != nil {
+		if sprint.Status == domain.SprintStatusClosed {
+			return nil, ErrInvalidSprintState
+		}
+		if req.StartDate != nil {
+			sprint.StartDate = *req.StartDate
+		}
+		if req.EndDate != nil {
+			sprint.EndDate = *req.EndDate
+		}
+		if err := checkSprintDates(sprint.StartDate, sprint.EndDate); err != nil {
+			return nil, err
+		}
+	}
+	sprint.UpdatedAt = time.Now()
+
+	if err := s.sprintRepo.Update(ctx, sprint); err != nil {
+		s.logger.Error("Failed to update sprint", err, map[string]interface{}{
+			"id": id,
+		})
+		return nil, err
+	}
+
+	if err := s.fillProgress(ctx, []*domain.Sprint{sprint}); err != nil {
+		return nil, err
+	}
+
+	return sprint, nil
+}
+
+// Delete удаляет спринт; его задачи возвращаются в бэклог
+func (s *SprintService) Delete(ctx context.Context, id string, userID string) error {
+	sprint, err := s.getAccessibleSprint(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+
+	if !s.projectSvc.canManageProject(ctx, sprint.ProjectID, userID) {
+		return ErrInsufficientRights
+	}
+
+	if err := s.projectSvc.ensureProjectWritable(ctx, sprint.ProjectID); err != nil {
+		return err
+	}
+
+	return s.sprintRepo.Delete(ctx, id)
+}
+
+// Start начинает запланированный спринт. В проекте одновременно идет не более одного спринта
+func (s *SprintService) Start(ctx context.Context, id string, userID string) (*domain.Sprint, error) {
+	sprint, err := s.getAccessibleSprint(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkCanEdit(ctx, sprint.ProjectID, userID); err != nil {
+		return nil, err
+	}
+
+	if sprint.Status != domain.SprintStatusPlanned {
+		return nil, ErrInvalidSprintState
+	}
+
+	sprints, err := s.sprintRepo.ListByProject(ctx, sprint.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	for _, other := range sprints {
+		if other.Status == domain.SprintStatusActive {
+			return nil, ErrSprintActive
+		}
+	}
+
+	now := time.Now()
+	sprint.Status = domain.SprintStatusActive
+	sprint.StartedAt = &now
+	sprint.UpdatedAt = now
+
+	if err := s.sprintRepo.Update(ctx, sprint); err != nil {
+		s.logger.Error("Failed to start sprint", err, map[string]interface{}{
+			"id": id,
+		})
+		return nil, err
+	}
+
+	s.logger.Info("Sprint started", map[string]interface{}{
+		"id":         id,
+		"project_id": sprint.ProjectID,
+		"user_id":    userID,
+	})
+
+	if err := s.fillProgress(ctx, []*domain.Sprint{sprint}); err != nil {
+		return nil, err
+	}
+
+	return sprint, nil
+}
+
+// Close завершает активный спринт. Незавершенные задачи переносятся в указанный
+// запланированный спринт проекта или возвращаются в бэклог
+func (s *SprintService) Close(ctx context.Context, id string, req domain.SprintCloseRequest, userID string) (*domain.Sprint, error) {
+	sprint, err := s.getAccessibleSprint(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkCanEdit(ctx, sprint.ProjectID, userID); err != nil {
+		return nil, err
+	}
+
+	if sprint.Status != domain.SprintStatusActive {
+		return nil, ErrInvalidSprintState
+	}
+
+	if req.MoveToSprintID != nil {
+		target, err := s.sprintRepo.GetByID(ctx, *req.MoveToSprintID)
+		if err != nil {
+			return nil, err
+		}
+		if target == nil {
+			return nil, ErrSprintNotFound
+		}
+		if target.ProjectID != sprint.ProjectID {
+			return nil, ErrSprintProjectMismatch
+		}
+		if target.Status != domain.SprintStatusPlanned {
+			return nil, ErrInvalidSprintState
+		}
+	}
+
+	now := time.Now()
+	sprint.Status = domain.SprintStatusClosed
+	sprint.ClosedAt = &now
+	sprint.UpdatedAt = now
+
+	moved, err := s.sprintRepo.Close(ctx, sprint, req.MoveToSprintID, userID)
+	if err != nil {
+		s.logger.Error("Failed to close sprint", err, map[string]interface{}{
+			"id": id,
+		})
+		return nil, err
+	}
+	if len(moved) > 0 {
+		s.taskSvc.invalidateTaskCache(ctx, moved)
+	}
+
+	s.logger.Info("Sprint closed", map[string]interface{}{
+		"id":          id,
+		"project_id":  sprint.ProjectID,
+		"moved_tasks": len(moved),
+		"user_id":     userID,
+	})
+
+	if err := s.fillProgress(ctx, []*domain.Sprint{sprint}); err != nil {
+		return nil, err
+	}
+
+	return sprint, nil
+}
+
+// AddTasks включает задачи проекта в незавершенный спринт. Задачи других спринтов переносятся
+func (s *SprintService) AddTasks(ctx context.Context, id string, req domain.SprintTasksRequest, userID string) (*domain.Sprint, error) {
+	sprint, err := s.getAccessibleSprint(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkCanEdit(ctx, sprint.ProjectID, userID); err != nil {
+		return nil, err
+	}
+
+	if sprint.Status == domain.SprintStatusClosed {
+		return nil, ErrInvalidSprintState
+	}
+
+	tasks, err := s.taskRepo.List(ctx, repository.TaskFilter{
+		IDs:        req.TaskIDs,
+		ProjectIDs: []string{sprint.ProjectID},
+		Limit:      len(req.TaskIDs),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(tasks) != len(req.TaskIDs) {
+		return nil, ErrSprintProjectMismatch
+	}
+
+	if err := s.sprintRepo.SetTasksSprint(ctx, req.TaskIDs, &sprint.ID, userID); err != nil {
+		return nil, err
+	}
+	s.taskSvc.invalidateTaskCache(ctx, req.TaskIDs)
+
+	if err := s.fillProgress(ctx, []*domain.Sprint{sprint}); err != nil {
+		return nil, err
+	}
+
+	return sprint, nil
+}
+
+// RemoveTask возвращает задачу незавершенного спринта в бэклог
+func (s *SprintService) RemoveTask(ctx context.Context, id string, taskID string, userID string) error {
+	sprint, err := s.getAccessibleSprint(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.checkCanEdit(ctx, sprint.ProjectID, userID); err != nil {
+		return err
+	}
+
+	if sprint.Status == domain.SprintStatusClosed {
+		return ErrInvalidSprintState
+	}
+
+	task, err := s.taskRepo.GetByID(ctx, taskID)
+	if err != nil {
+		return err
+	}
+	if task == nil || task.SprintID == nil || *task.SprintID != sprint.ID {
+		return ErrTaskNotFound
+	}
+
+	if err := s.sprintRepo.SetTasksSprint(ctx, []string{taskID}, nil, userID); err != nil {
+		return err
+	}
+	s.taskSvc.invalidateTaskCache(ctx, []string{taskID})
+
+	return nil
+}
+
+// GetBurndown строит диаграмму сгорания спринта по истории задач: на конец каждого дня
+// считаются задачи, входившие в спринт, и незавершенные из них. Оценка задач берется текущая.
+// Отмененные задачи в объем не входят
+func (s *SprintService) GetBurndown(ctx context.Context, id string, userID string) (*domain.SprintBurndown, error) {
+	sprint, err := s.getAccessibleSprint(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks, err := s.sprintRepo.ListScopeTasks(ctx, sprint.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := s.sprintRepo.ListTaskChanges(ctx, sprint.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildBurndown(sprint, tasks, changes, time.Now()), nil
+}
+
+// burndownTaskState - состояние задачи спринта в момент точки диаграммы
+type burndownTaskState struct {
+	inSprint bool
+	status   domain.TaskStatus
+	hours    float64
+}
+
+// buildBurndown восстанавливает состояние задач на конец каждого дня спринта, отменяя
+// изменения, сделанные позже точки, начиная с текущего состояния
+func buildBurndown(sprint *domain.Sprint, tasks []*domain.Task, changes []*domain.SprintTaskChange, now time.Time) *domain.SprintBurndown {
+	burndown := &domain.SprintBurndown{
+		SprintID:  sprint.ID,
+		StartDate: sprint.StartDate,
+		EndDate:   sprint.EndDate,
+		Points:    []domain.BurndownPoint{},
+	}
+
+	limit := now
+	if sprint.ClosedAt != nil && sprint.ClosedAt.Before(limit) {
+		limit = *sprint.ClosedAt
+	}
+	if limit.Before(sprint.StartDate) {
+		return burndown
+	}
+
+	// Моменты точек: начало спринта и конец каждого дня, но не позже окончания и текущего момента
+	moments := []time.Time{sprint.StartDate}
+	for t := sprint.StartDate.Add(burndownStep); ; t = t.Add(burndownStep) {
+		if t.After(sprint.EndDate) {
+			t = sprint.EndDate
+		}
+		if t.After(limit) {
+			t = limit
+		}
+		if !t.After(moments[len(moments)-1]) {
+			break
+		}
+		moments = append(moments, t)
+		if t.Equal(sprint.EndDate) || t.Equal(limit) {
+			break
+		}
+	}
+
+	states := make(map[string]*burndownTaskState, len(tasks))
+	for _, task := range tasks {
+		state := &burndownTaskState{
+			inSprint: task.SprintID != nil && *task.SprintID == sprint.ID,
+			status:   task.Status,
+		}
+		if task.EstimatedHours != nil {
+			state.hours = *task.EstimatedHours
+		}
+		states[task.ID] = state
+	}
+
+	points := make([]domain.BurndownPoint, len(moments))
+	next := len(changes) - 1
+	for i := len(moments) - 1; i >= 0; i-- {
+		// Отменяем изменения, сделанные после момента точки
+		for ; next >= 0 && changes[next].ChangedAt.After(moments[i]); next-- {
+			change := changes[next]
+			state, ok := states[change.TaskID]
+			if !ok {
+				continue
+			}
+			switch change.Field {
+			case "status":
+				if change.OldValue != nil {
+					state.status = domain.TaskStatus(*change.OldValue)
+				}
+			case "sprint_id":
+				state.inSprint = change.OldValue != nil && *change.OldValue == sprint.ID
+			}
+		}
+
+		point := domain.BurndownPoint{Date: moments[i]}
+		for _, state := range states {
+			if !state.inSprint || state.status == domain.TaskStatusCancelled {
+				continue
+			}
+			point.ScopeTasks++
+			point.ScopeHours += state.hours
+			if state.status != domain.TaskStatusCompleted {
+				point.RemainingTasks++
+				point.RemainingHours += state.hours
+			}
+		}
+		points[i] = point
+	}
+
+	// Идеальная линия равномерно сводит объем на начало спринта к нулю к его окончанию
+	duration := sprint.EndDate.Sub(sprint.StartDate).Hours()
+	for i := range points {
+		left := sprint.EndDate.Sub(points[i].Date).Hours() / duration
+		points[i].IdealTasks = math.Round(float64(points[0].ScopeTasks)*left*100) / 100
+		points[i].IdealHours = math.Round(points[0].ScopeHours*left*100) / 100
+	}
+
+	burndown.Points = points
+	return burndown
+}
+
+// getAccessibleSprint возвращает спринт, если у пользователя есть доступ к его проекту
+func (s *SprintService) getAccessibleSprint(ctx context.Context, id string, userID string) (*domain.Sprint, error) {
+	sprint, err := s.sprintRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if sprint == nil || !s.projectSvc.hasAccessToProject(ctx, sprint.ProjectID, userID) {
+		return nil, ErrSprintNotFound
+	}
+	return sprint, nil
+}
+
+// checkCanEdit проверяет, что пользователь может планировать спринты проекта
+func (s *SprintService) checkCanEdit(ctx context.Context, projectID string, userID string) error {
+	if !s.projectSvc.hasAccessToProject(ctx, projectID, userID) {
+		return ErrProjectNotFound
+	}
+	if !s.taskSvc.canManageTask(ctx, projectID, userID) {
+		return ErrInsufficientRights
+	}
+	return s.projectSvc.ensureProjectWritable(ctx, projectID)
+}
+
+// fillProgress заполняет прогресс задач спринтов
+func (s *SprintService) fillProgress(ctx context.Context, sprints []*domain.Sprint) error {
+	if len(sprints) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(sprints))
+	for i, sprint := range sprints {
+		ids[i] = sprint.ID
+	}
+
+	progress, err := s.sprintRepo.GetProgress(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	for _, sprint := range sprints {
+		p, ok := progress[sprint.ID]
+		if !ok {
+			p = &domain.SprintProgress{}
+		}
+		sprint.Progress = p
+	}
+
+	return nil
+}
+
+// checkSprintDates проверяет, что спринт заканчивается после начала и не длиннее допустимого
+func checkSprintDates(start, end time.Time) error {
+	if !end.After(start) || end.Sub(start) > sprintMaxDuration {
+		return ErrInvalidSprintDates
+	}
+	return nil
+}
@@ -0,0 +1,473 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"html/template"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/internal/repository"
+	"github.com/nurlyy/task_manager/internal/repository/cache"
+	"github.com/nurlyy/task_manager/pkg/circuit"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
+	"github.com/nurlyy/task_manager/pkg/logger"
+)
+
+const (
+	// StatusCacheTTL определяет, сколько страница статуса хранится в HTTP-кэше
+	StatusCacheTTL = 30 * time.Second
+
+	// statusCheckInterval - период проверки компонентов сервиса
+	statusCheckInterval = 30 * time.Second
+	// statusCheckTimeout ограничивает проверку одного компонента
+	statusCheckTimeout = 5 * time.Second
+	// statusPageRefreshInterval задает, как долго экземпляр API отдает собранную
+	// страницу, не читая заметки и счетчики доступности заново
+	statusPageRefreshInterval = 15 * time.Second
+	// statusUptimeDays - период, за который показывается доступность API
+	statusUptimeDays = 30
+	// statusResolvedNotesWindow - сколько решенные инциденты остаются на странице
+	statusResolvedNotesWindow = 7 * 24 * time.Hour
+	// statusNotesLimit ограничивает количество заметок на странице
+	statusNotesLimit = 20
+)
+
+var (
+	ErrStatusNoteNotFound = apperrors.New(apperrors.CodeStatusNoteNotFound, "status note not found")
+
+	// ErrComponentDegraded возвращается проверкой компонента, который работает с перебоями
+	ErrComponentDegraded = errors.New("component is degraded")
+)
+
+// statusLabels содержит названия состояний для HTML-представления
+var statusLabels = map[domain.ComponentStatus]string{
+	domain.ComponentStatusOperational: "Работает",
+	domain.ComponentStatusDegraded:    "Работает с перебоями",
+	domain.ComponentStatusDown:        "Недоступен",
+}
+
+// statusTemplate формирует HTML-представление страницы статуса
+var statusTemplate = template.Must(template.New("status").Parse(`<!DOCTYPE html>
+<html lang="ru">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Статус сервиса</title>
+<style>
+body{font-family:sans-serif;max-width:760px;margin:2rem auto;padding:0 1rem;color:#222}
+.row{display:flex;justify-content:space-between;border-bottom:1px solid #eee;padding:.5rem 0}
+.operational{color:#1a7f37}.degraded{color:#b08800}.down{color:#cf222e}
+.note{border:1px solid #ddd;border-radius:6px;padding:.5rem .75rem;margin-bottom:.5rem}
+.muted{color:#777;font-size:.85rem}
+</style>
+</head>
+<body>
+<h1 class="{{.Status}}">{{.StatusLabel}}</h1>
+<section>
+<h2>Компоненты</h2>
+{{range .Components}}<div class="row"><span>{{.Name}}</span><span class="{{.Status}}">{{.Label}}</span></div>
+{{end}}</section>
+<section>
+<h2>Инциденты</h2>
+{{range .Notes}}<div class="note"><strong>{{.Title}}</strong>{{if .Resolved}} <span class="muted">решен</span>{{end}}
+{{if .Message}}<p>{{.Message}}</p>{{end}}<div class="muted">{{.CreatedAt}}</div></div>
+{{else}}<p class="muted">Инцидентов нет</p>
+{{end}}</section>
+<section>
+<h2>Доступность API</h2>
+<p>{{.Uptime}} за {{.UptimeDays}} дн.</p>
+</section>
+<p class="muted">Обновлено {{.GeneratedAt}}</p>
+</body>
+</html>
+`))
+
+// StatusCheck описывает проверку компонента сервиса для страницы статуса
+type StatusCheck struct {
+	Name string
+	// Critical означает, что без компонента API не работает; недоступность остальных
+	// компонентов отмечается как работа с перебоями
+	Critical bool
+	// Check возвращает nil, если компонент работает, и ErrComponentDegraded, если он работает с перебоями
+	Check func(ctx context.Context) error
+}
+
+// BreakerCheck возвращает проверку внешнего сервиса по состоянию его автоматического выключателя
+func BreakerCheck(breaker *circuit.Breaker) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		switch breaker.State() {
+		case circuit.StateOpen:
+			return circuit.ErrOpen
+		case circuit.StateHalfOpen:
+			return ErrComponentDegraded
+		default:
+			return nil
+		}
+	}
+}
+
+// StatusService собирает публичную страницу статуса сервиса: состояние компонентов
+// по периодическим проверкам, заметки администраторов об инцидентах и доступность API
+type StatusService struct {
+	checks    []StatusCheck
+	noteRepo  repository.StatusNoteRepository
+	cacheRepo *cache.RedisRepository
+	logger    logger.Logger
+
+	mu         sync.Mutex
+	components []domain.StatusComponent
+	page       *domain.StatusPage
+}
+
+// NewStatusService создает новый экземпляр StatusService
+func NewStatusService(
+	checks []StatusCheck,
+	noteRepo repository.StatusNoteRepository,
+	cacheRepo *cache.RedisRepository,
+	logger logger.Logger,
+) *StatusService {
+	return &StatusService{
+		checks:    checks,
+		noteRepo:  noteRepo,
+		cacheRepo: cacheRepo,
+		logger:    logger,
+	}
+}
+
+// StartChecks запускает периодическую проверку компонентов до отмены ctx.
+// Каждая проверка учитывается в счетчиках доступности API
+func (s *StatusService) StartChecks(ctx context.Context) {
+	ticker := time.NewTicker(statusCheckInterval)
+	go func() {
+		s.runChecks(ctx)
+		for {
+			select {
+			case <-ticker.C:
+				s.runChecks(ctx)
+			case <-ctx.Done():
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+}
+
+// Page возвращает страницу статуса. Собранная страница отдается повторно в течение
+// statusPageRefreshInterval, а при cachedOnly - пока не будет собрана новая.
+// Недоступность хранилищ не мешает показать страницу: берутся прежние данные
+func (s *StatusService) Page(ctx context.Context, cachedOnly bool) *domain.StatusPage {
+	s.mu.Lock()
+	page := s.page
+	components := s.components
+	s.mu.Unlock()
+
+	if page != nil && (cachedOnly || time.Since(page.GeneratedAt) < statusPageRefreshInterval) {
+		return page
+	}
+	if components == nil {
+		components = s.runChecks(ctx)
+	}
+
+	now := time.Now().UTC()
+	result := &domain.StatusPage{
+		Status:      overallStatus(s.checks, components),
+		Components:  components,
+		Notes:       []*domain.StatusNote{},
+		GeneratedAt: now,
+	}
+
+	notes, err := s.noteRepo.ListRecent(ctx, now.Add(-statusResolvedNotesWindow), statusNotesLimit)
+	switch {
+	case err == nil:
+		if notes != nil {
+			result.Notes = notes
+		}
+	case page != nil:
+		result.Notes = page.Notes
+	}
+
+	samples, err := s.cacheRepo.GetStatusSamples(ctx, now, statusUptimeDays)
+	switch {
+	case err == nil:
+		result.Uptime = uptimeFromSamples(samples)
+	case page != nil:
+		result.Uptime = page.Uptime
+	}
+	if err != nil {
+		s.logger.Warn("Failed to read status uptime", nil, map[string]interface{}{
+			"error": err,
+		})
+	}
+
+	s.mu.Lock()
+	s.page = result
+	s.mu.Unlock()
+
+	return result
+}
+
+// RenderStatusHTML формирует HTML-страницу статуса сервиса
+func (s *StatusService) RenderStatusHTML(page *domain.StatusPage) ([]byte, error) {
+	type componentView struct {
+		Name   string
+		Status domain.ComponentStatus
+		Label  string
+	}
+	type noteView struct {
+		Title     string
+		Message   string
+		Resolved  bool
+		CreatedAt string
+	}
+
+	components := make([]componentView, 0, len(page.Components))
+	for _, component := range page.Components {
+		components = append(components, componentView{
+			Name:   component.Name,
+			Status: component.Status,
+			Label:  statusLabels[component.Status],
+		})
+	}
+	notes := make([]noteView, 0, len(page.Notes))
+	for _, note := range page.Notes {
+		notes = append(notes, noteView{
+			Title:     note.Title,
+			Message:   note.Message,
+			Resolved:  note.ResolvedAt != nil,
+			CreatedAt: note.CreatedAt.UTC().Format("02.01.2006 15:04 UTC"),
+		})
+	}
+
+	uptime := "нет данных"
+	if len(page.Uptime.Days) > 0 {
+		uptime = strconv.FormatFloat(page.Uptime.Percent, 'f', -1, 64) + "%"
+	}
+
+	var buf bytes.Buffer
+	err := statusTemplate.Execute(&buf, map[string]interface{}{
+		"Status":      page.Status,
+		"StatusLabel": statusLabels[page.Status],
+		"Components":  components,
+		"Notes":       notes,
+		"Uptime":      uptime,
+		"UptimeDays":  statusUptimeDays,
+		"GeneratedAt": page.GeneratedAt.Format("02.01.2006 15:04 UTC"),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// ListNotes возвращает все заметки об инцидентах для администратора
+func (s *StatusService) ListNotes(ctx context.Context) ([]*domain.StatusNote, error) {
+	notes, err := s.noteRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if notes == nil {
+		notes = []*domain.StatusNote{}
+	}
+	return notes, nil
+}
+
+// CreateNote создает заметку об инциденте
+func (s *StatusService) CreateNote(ctx context.Context, req domain.StatusNoteRequest, userID string) (*domain.StatusNote, error) {
+	now := time.Now()
+	note := &domain.StatusNote{
+		ID:        uuid.New().String(),
+		Title:     strings.TrimSpace(req.Title),
+		Message:   req.Message,
+		Severity:  req.Severity,
+		CreatedBy: &userID,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if req.Resolved {
+		note.ResolvedAt = &now
+	}
+
+	if err := s.noteRepo.Create(ctx, note); err != nil {
+		return nil, err
+	}
+	s.invalidatePage(ctx)
+
+	return note, nil
+}
+
+// UpdateNote изменяет заметку об инциденте. Снятие отметки о решении возвращает инцидент в открытые
+func (s *StatusService) UpdateNote(ctx context.Context, id string, req domain.StatusNoteRequest) (*domain.StatusNote, error) {
+	note, err := s.getNote(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	note.Title = strings.TrimSpace(req.Title)
+	note.Message = req.Message
+	note.Severity = req.Severity
+	note.UpdatedAt = now
+	switch {
+	case req.Resolved && note.ResolvedAt == nil:
+		note.ResolvedAt = &now
+	case !req.Resolved:
+		note.ResolvedAt = nil
+	}
+
+	if err := s.noteRepo.Update(ctx, note); err != nil {
+		return nil, err
+	}
+	s.invalidatePage(ctx)
+
+	return note, nil
+}
+
+// DeleteNote удаляет заметку об инциденте
+func (s *StatusService) DeleteNote(ctx context.Context, id string) error {
+	if _, err := s.getNote(ctx, id); err != nil {
+		return err
+	}
+	if err := s.noteRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.invalidatePage(ctx)
+
+	return nil
+}
+
+// runChecks проверяет компоненты, сохраняет результат и учитывает его в счетчиках доступности
+func (s *StatusService) runChecks(ctx context.Context) []domain.StatusComponent {
+	components := make([]domain.StatusComponent, len(s.checks))
+
+	var wg sync.WaitGroup
+	for i, check := range s.checks {
+		wg.Add(1)
+		go func(i int, check StatusCheck) {
+			defer wg.Done()
+			components[i] = s.runCheck(ctx, check)
+		}(i, check)
+	}
+	wg.Wait()
+
+	s.mu.Lock()
+	s.components = components
+	s.mu.Unlock()
+
+	up := overallStatus(s.checks, components) != domain.ComponentStatusDown
+	if err := s.cacheRepo.RecordStatusSample(ctx, time.Now(), up); err != nil {
+		s.logger.Warn("Failed to record status sample", map[string]interface{}{
+			"up": up,
+		}, map[string]interface{}{
+			"error": err,
+		})
+	}
+
+	return components
+}
+
+// runCheck выполняет проверку одного компонента
+func (s *StatusService) runCheck(ctx context.Context, check StatusCheck) domain.StatusComponent {
+	ctx, cancel := context.WithTimeout(ctx, statusCheckTimeout)
+	defer cancel()
+
+	started := time.Now()
+	err := check.Check(ctx)
+	component := domain.StatusComponent{
+		Name:      check.Name,
+		Status:    domain.ComponentStatusOperational,
+		LatencyMs: time.Since(started).Milliseconds(),
+		CheckedAt: started.UTC(),
+	}
+
+	switch {
+	case err == nil:
+	case errors.Is(err, ErrComponentDegraded):
+		component.Status = domain.ComponentStatusDegraded
+	default:
+		component.Status = domain.ComponentStatusDown
+		s.logger.Warn("Status check failed", map[string]interface{}{
+			"component": check.Name,
+		}, map[string]interface{}{
+			"error": err,
+		})
+	}
+
+	return component
+}
+
+// invalidatePage сбрасывает собранную страницу и ее копии в HTTP-кэше
+func (s *StatusService) invalidatePage(ctx context.Context) {
+	s.mu.Lock()
+	s.page = nil
+	s.mu.Unlock()
+
+	if err := s.cacheRepo.PurgeSurrogateKeys(ctx, cache.SurrogateKeyStatus); err != nil {
+		s.logger.Warn("Failed to purge cached status page", nil, map[string]interface{}{
+			"error": err,
+		})
+	}
+}
+
+// getNote возвращает заметку об инциденте по ID
+func (s *StatusService) getNote(ctx context.Context, id string) (*domain.StatusNote, error) {
+	note, err := s.noteRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if note == nil {
+		return nil, ErrStatusNoteNotFound
+	}
+	return note, nil
+}
+
+// overallStatus определяет общее состояние сервиса: недоступность критичного компонента
+// означает недоступность сервиса, остальных - работу с перебоями
+func overallStatus(checks []StatusCheck, components []domain.StatusComponent) domain.ComponentStatus {
+	status := domain.ComponentStatusOperational
+	for i, component := range components {
+		switch {
+		case component.Status == domain.ComponentStatusDown && checks[i].Critical:
+			return domain.ComponentStatusDown
+		case component.Status != domain.ComponentStatusOperational:
+			status = domain.ComponentStatusDegraded
+		}
+	}
+	return status
+}
+
+// uptimeFromSamples считает доступность API по дневным счетчикам проверок.
+// Дни без проверок не учитываются
+func uptimeFromSamples(samples []cache.StatusUptimeSample) domain.StatusUptime {
+	uptime := domain.StatusUptime{Days: []domain.StatusUptimeDay{}}
+
+	total, ok := 0, 0
+	for _, sample := range samples {
+		if sample.Total == 0 {
+			continue
+		}
+		total += sample.Total
+		ok += sample.OK
+		uptime.Days = append(uptime.Days, domain.StatusUptimeDay{
+			Date:    sample.Day.Format("2006-01-02"),
+			Percent: uptimePercent(sample.OK, sample.Total),
+		})
+	}
+	if total > 0 {
+		uptime.Percent = uptimePercent(ok, total)
+	}
+
+	return uptime
+}
+
+// roundPercent возвращает долю part от total в процентах с точностью до сотых
+func uptimePercent(part, total int) float64 {
+	return math.Round(float64(part)/float64(total)*10000) / 100
+}
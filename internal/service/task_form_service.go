@@ -0,0 +1,440 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/internal/repository"
+	"github.com/nurlyy/task_manager/pkg/logger"
+)
+
+// taskFormDateLayout определяет формат даты в значениях формы, помимо RFC 3339
+const taskFormDateLayout = "2006-01-02"
+
+// TaskFormService представляет бизнес-логику форм создания задач
+type TaskFormService struct {
+	formRepo    repository.TaskFormRepository
+	taskRepo    repository.TaskRepository
+	projectRepo repository.ProjectRepository
+	projectSvc  *ProjectService
+	taskSvc     *TaskService
+	logger      logger.Logger
+}
+
+// NewTaskFormService создает новый экземпляр TaskFormService
+func NewTaskFormService(
+	formRepo repository.TaskFormRepository,
+	taskRepo repository.TaskRepository,
+	projectRepo repository.ProjectRepository,
+	projectSvc *ProjectService,
+	taskSvc *TaskService,
+	logger logger.Logger,
+) *TaskFormService {
+	return &TaskFormService{
+		formRepo:    formRepo,
+		taskRepo:    taskRepo,
+		projectRepo: projectRepo,
+		projectSvc:  projectSvc,
+		taskSvc:     taskSvc,
+		logger:      logger,
+	}
+}
+
+// GetForm возвращает форму создания задачи проекта или форму по умолчанию
+func (s *TaskFormService) GetForm(ctx context.Context, projectID string) (*domain.TaskForm, error) {
+	return s.getForm(ctx, projectID)
+}
+
+// SaveForm проверяет и сохраняет определение формы создания задачи
+func (s *TaskFormService) SaveForm(ctx context.Context, projectID string, req domain.TaskFormRequest, userID string) (*domain.TaskForm, error) {
+	project, err := s.projectRepo.GetByID(ctx, projectID)
+	if err != nil || project == nil {
+		return nil, ErrProjectNotFound
+	}
+
+	if violations := validateTaskFormDefinition(req.Fields); len(violations) > 0 {
+		return nil, &TaskValidationError{Violations: violations}
+	}
+
+	form := &domain.TaskForm{
+		ProjectID: projectID,
+		Fields:    req.Fields,
+		UpdatedBy: &userID,
+		UpdatedAt: time.Now(),
+	}
+
+	if err := s.formRepo.Save(ctx, form); err != nil {
+		s.logger.Error("Failed to save task form", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return nil, err
+	}
+
+	s.logger.Info("Task form updated", map[string]interface{}{
+		"project_id": projectID,
+		"user_id":    userID,
+	})
+
+	return form, nil
+}
+
+// ResetForm удаляет форму проекта, возвращая форму по умолчанию
+func (s *TaskFormService) ResetForm(ctx context.Context, projectID string) (*domain.TaskForm, error) {
+	project, err := s.projectRepo.GetByID(ctx, projectID)
+	if err != nil || project == nil {
+		return nil, ErrProjectNotFound
+	}
+
+	if err := s.formRepo.Delete(ctx, projectID); err != nil {
+		return nil, err
+	}
+
+	return domain.DefaultTaskForm(projectID), nil
+}
+
+// Submit проверяет заполненную форму по ее определению и создает задачу.
+// Значения скрытых полей отбрасываются, значения пользовательских полей сохраняются вместе с задачей
+func (s *TaskFormService) Submit(ctx context.Context, projectID string, submission domain.TaskFormSubmission, userID string) (*domain.TaskFormSubmissionResponse, error) {
+	form, err := s.getForm(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	values, violations := s.validateSubmission(ctx, projectID, form.Fields, submission.Values)
+	if len(violations) > 0 {
+		return nil, &TaskValidationError{Violations: violations}
+	}
+
+	// Разделяем встроенные поля задачи и пользовательские поля формы
+	req := domain.TaskCreateRequest{ProjectID: projectID}
+	custom := make(map[string]interface{})
+	for key, value := range values {
+		switch key {
+		case domain.TaskFormKeyTitle:
+			req.Title = value.(string)
+		case domain.TaskFormKeyDescription:
+			req.Description = value.(string)
+		case domain.TaskFormKeyPriority:
+			req.Priority = domain.TaskPriority(value.(string))
+		case domain.TaskFormKeyAssignee:
+			assigneeID := value.(string)
+			req.AssigneeID = &assigneeID
+		case domain.TaskFormKeyDueDate:
+			dueDate := value.(time.Time)
+			req.DueDate = &dueDate
+		case domain.TaskFormKeyEstimatedHours:
+			estimate := value.(float64)
+			req.EstimatedHours = &estimate
+		case domain.TaskFormKeyTags:
+			req.Tags = value.([]string)
+		default:
+			custom[key] = value
+		}
+	}
+
+	task, err := s.taskSvc.Create(ctx, req, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(custom) > 0 {
+		if err := s.formRepo.SaveValues(ctx, task.ID, custom); err != nil {
+			s.logger.Warn("Failed to save task form values", map[string]interface{}{
+				"task_id": task.ID,
+			}, map[string]interface{}{
+				"error": err,
+			})
+		}
+	}
+
+	return &domain.TaskFormSubmissionResponse{
+		Task:   task,
+		Values: custom,
+	}, nil
+}
+
+// GetValues возвращает значения пользовательских полей формы, заполненные при создании задачи
+func (s *TaskFormService) GetValues(ctx context.Context, taskID string, userID string) (map[string]interface{}, error) {
+	task, err := s.taskRepo.GetByID(ctx, taskID)
+	if err != nil || task == nil {
+		return nil, ErrTaskNotFound
+	}
+
+	// Проверяем доступ пользователя к задаче
+	if !s.taskSvc.hasAccessToTask(ctx, task.ProjectID, userID) {
+		return nil, ErrTaskAccessDenied
+	}
+
+	return s.formRepo.GetValues(ctx, taskID)
+}
+
+// getForm возвращает сохраненную форму проекта или форму по умолчанию
+func (s *TaskFormService) getForm(ctx context.Context, projectID string) (*domain.TaskForm, error) {
+	form, err := s.formRepo.GetByProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if form == nil {
+		return domain.DefaultTaskForm(projectID), nil
+	}
+	return form, nil
+}
+
+// validateTaskFormDefinition проверяет согласованность определения формы
+func validateTaskFormDefinition(fields []domain.TaskFormField) []domain.FieldViolation {
+	var violations []domain.FieldViolation
+	addViolation := func(index int, message string) {
+		violations = append(violations, domain.FieldViolation{
+			Field:   fmt.Sprintf("fields[%d]", index),
+			Message: message,
+		})
+	}
+
+	defined := make(map[string]bool, len(fields))
+	hasTitle := false
+	for i, field := range fields {
+		if defined[field.Key] {
+			addViolation(i, fmt.Sprintf("Duplicate field key %q", field.Key))
+			continue
+		}
+
+		// Встроенные поля должны иметь совместимый тип
+		if allowed, ok := domain.TaskFormBuiltinTypes[field.Key]; ok {
+			compatible := false
+			for _, t := range allowed {
+				if t == field.Type {
+					compatible = true
+					break
+				}
+			}
+			if !compatible {
+				addViolation(i, fmt.Sprintf("Field %q must have type %s", field.Key, allowed[0]))
+			}
+		}
+		if field.Key == domain.TaskFormKeyTitle {
+			hasTitle = true
+			if !field.Required || field.VisibleIf != nil {
+				addViolation(i, "Title field must be required and always visible")
+			}
+		}
+
+		// Для полей выбора нужен список вариантов; для меток он необязателен
+		if (field.Type == domain.TaskFormFieldSelect || field.Type == domain.TaskFormFieldMultiselect) &&
+			len(field.Options) == 0 && field.Key != domain.TaskFormKeyTags {
+			addViolation(i, fmt.Sprintf("Field %q must define options", field.Key))
+		}
+		if field.Key == domain.TaskFormKeyPriority {
+			for _, option := range field.Options {
+				switch domain.TaskPriority(option) {
+				case domain.TaskPriorityLow, domain.TaskPriorityMedium, domain.TaskPriorityHigh, domain.TaskPriorityCritical:
+				default:
+					addViolation(i, fmt.Sprintf("Unknown priority option %q", option))
+				}
+			}
+		}
+
+		// Условие видимости может ссылаться только на поле, расположенное выше
+		if field.VisibleIf != nil && !defined[field.VisibleIf.Field] {
+			addViolation(i, fmt.Sprintf("Visibility condition must reference a preceding field, got %q", field.VisibleIf.Field))
+		}
+
+		defined[field.Key] = true
+	}
+
+	if !hasTitle {
+		violations = append(violations, domain.FieldViolation{
+			Field:   "fields",
+			Message: "Form must contain the title field",
+		})
+	}
+
+	return violations
+}
+
+// validateSubmission проверяет значения формы и приводит их к типам полей.
+// Возвращает только значения видимых полей
+func (s *TaskFormService) validateSubmission(ctx context.Context, projectID string, fields []domain.TaskFormField, submitted map[string]interface{}) (map[string]interface{}, []domain.FieldViolation) {
+	var violations []domain.FieldViolation
+	addViolation := func(key, message string) {
+		violations = append(violations, domain.FieldViolation{Field: key, Message: message})
+	}
+
+	known := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		known[field.Key] = true
+	}
+	for key := range submitted {
+		if !known[key] {
+			addViolation(key, "Unknown field")
+		}
+	}
+
+	values := make(map[string]interface{})
+	visible := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		if !isTaskFormFieldVisible(field, visible, values) {
+			continue
+		}
+		visible[field.Key] = true
+
+		raw, ok := submitted[field.Key]
+		if !ok || isEmptyTaskFormValue(raw) {
+			if field.Required {
+				addViolation(field.Key, "This field is required")
+			}
+			continue
+		}
+
+		value, err := s.convertTaskFormValue(ctx, projectID, field, raw)
+		if err != nil {
+			addViolation(field.Key, err.Error())
+			continue
+		}
+		values[field.Key] = value
+	}
+
+	return values, violations
+}
+
+// convertTaskFormValue проверяет значение поля и приводит его к типу поля
+func (s *TaskFormService) convertTaskFormValue(ctx context.Context, projectID string, field domain.TaskFormField, raw interface{}) (interface{}, error) {
+	switch field.Type {
+	case domain.TaskFormFieldText, domain.TaskFormFieldTextarea:
+		value, ok := raw.(string)
+		if !ok {
+			return nil, errors.New("Value must be a string")
+		}
+		return value, nil
+
+	case domain.TaskFormFieldNumber:
+		value, ok := raw.(float64)
+		if !ok || math.IsNaN(value) || math.IsInf(value, 0) {
+			return nil, errors.New("Value must be a number")
+		}
+		if field.Key == domain.TaskFormKeyEstimatedHours && value < 0 {
+			return nil, errors.New("Value must not be negative")
+		}
+		return value, nil
+
+	case domain.TaskFormFieldDate:
+		value, ok := raw.(string)
+		if !ok {
+			return nil, errors.New("Value must be a date")
+		}
+		date, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			date, err = time.Parse(taskFormDateLayout, value)
+		}
+		if err != nil {
+			return nil, errors.New("Value must be a date in YYYY-MM-DD or RFC 3339 format")
+		}
+		if field.Key == domain.TaskFormKeyDueDate {
+			return date, nil
+		}
+		return value, nil
+
+	case domain.TaskFormFieldSelect:
+		value, ok := raw.(string)
+		if !ok || !containsString(field.Options, value) {
+			return nil, fmt.Errorf("Value must be one of: %s", strings.Join(field.Options, ", "))
+		}
+		return value, nil
+
+	case domain.TaskFormFieldMultiselect:
+		items, ok := raw.([]interface{})
+		if !ok {
+			return nil, errors.New("Value must be a list")
+		}
+		result := make([]string, 0, len(items))
+		for _, item := range items {
+			value, ok := item.(string)
+			if !ok || value == "" {
+				return nil, errors.New("List items must be non-empty strings")
+			}
+			if len(field.Options) > 0 && !containsString(field.Options, value) {
+				return nil, fmt.Errorf("Value must be one of: %s", strings.Join(field.Options, ", "))
+			}
+			result = append(result, value)
+		}
+		return result, nil
+
+	case domain.TaskFormFieldUser:
+		value, ok := raw.(string)
+		if !ok {
+			return nil, errors.New("Value must be a user ID")
+		}
+		if _, err := uuid.Parse(value); err != nil {
+			return nil, errors.New("Value must be a user ID")
+		}
+		if _, err := s.projectRepo.GetMember(ctx, projectID, value); err != nil {
+			return nil, errors.New("User must be a member of the project")
+		}
+		return value, nil
+
+	case domain.TaskFormFieldCheckbox:
+		value, ok := raw.(bool)
+		if !ok {
+			return nil, errors.New("Value must be a boolean")
+		}
+		return value, nil
+	}
+
+	return nil, fmt.Errorf("Unsupported field type %s", field.Type)
+}
+
+// isTaskFormFieldVisible вычисляет видимость поля по уже обработанным полям формы
+func isTaskFormFieldVisible(field domain.TaskFormField, visible map[string]bool, values map[string]interface{}) bool {
+	if field.VisibleIf == nil {
+		return true
+	}
+	if !visible[field.VisibleIf.Field] {
+		return false
+	}
+
+	switch value := values[field.VisibleIf.Field].(type) {
+	case string:
+		return containsString(field.VisibleIf.Values, value)
+	case bool:
+		return containsString(field.VisibleIf.Values, fmt.Sprintf("%t", value))
+	case float64:
+		return containsString(field.VisibleIf.Values, fmt.Sprintf("%g", value))
+	case []string:
+		for _, item := range value {
+			if containsString(field.VisibleIf.Values, item) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// isEmptyTaskFormValue проверяет, что значение поля не заполнено
+func isEmptyTaskFormValue(raw interface{}) bool {
+	switch value := raw.(type) {
+	case nil:
+		return true
+	case string:
+		return strings.TrimSpace(value) == ""
+	case []interface{}:
+		return len(value) == 0
+	}
+	return false
+}
+
+// containsString проверяет наличие строки в списке
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
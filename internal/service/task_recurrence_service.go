@@ -0,0 +1,368 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/internal/messaging"
+	"github.com/nurlyy/task_manager/internal/repository"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
+	"github.com/nurlyy/task_manager/pkg/logger"
+	"github.com/nurlyy/task_manager/pkg/rrule"
+)
+
+// taskRecurrenceBatchSize ограничивает число задач серий, создаваемых за один запуск планировщика
+const taskRecurrenceBatchSize = 100
+
+// taskRecurrenceLease - время, после которого правило, оставшееся в обработке,
+// считается брошенным упавшим планировщиком и захватывается снова
+const taskRecurrenceLease = 10 * time.Minute
+
+// maxSkippedOccurrences ограничивает число пропускаемых повторений, если задача
+// была завершена позже срока нескольких следующих повторений
+const maxSkippedOccurrences = 1000
+
+var (
+	ErrTaskRecurrenceNotFound = apperrors.New(apperrors.CodeTaskRecurrenceNotFound, "task recurrence not found")
+	ErrInvalidRecurrence      = apperrors.New(apperrors.CodeInvalidRecurrence, "invalid recurrence rule")
+	ErrTaskClosed             = apperrors.New(apperrors.CodeTaskClosed, "task is completed or cancelled")
+
+	// errRecurrenceAdvanced - правило перенесено другим экземпляром планировщика,
+	// созданная задача откатывается
+	errRecurrenceAdvanced = errors.New("task recurrence already advanced")
+)
+
+// TaskRecurrenceService представляет бизнес-логику повторяющихся задач
+type TaskRecurrenceService struct {
+	recurrenceRepo repository.TaskRecurrenceRepository
+	taskRepo       repository.TaskRepository
+	projectSvc     *ProjectService
+	taskSvc        *TaskService
+	txManager      repository.TxManager
+	logger         logger.Logger
+}
+
+// NewTaskRecurrenceService создает новый экземпляр TaskRecurrenceService
+func NewTaskRecurrenceService(
+	recurrenceRepo repository.TaskRecurrenceRepository,
+	taskRepo repository.TaskRepository,
+	projectSvc *ProjectService,
+	taskSvc *TaskService,
+	txManager repository.TxManager,
+	logger logger.Logger,
+) *TaskRecurrenceService {
+	return &TaskRecurrenceService{
+		recurrenceRepo: recurrenceRepo,
+		taskRepo:       taskRepo,
+		projectSvc:     projectSvc,
+		taskSvc:        taskSvc,
+		txManager:      txManager,
+		logger:         logger,
+	}
+}
+
+// Get возвращает правило повторения задачи вместе со сроком следующей задачи серии
+func (s *TaskRecurrenceService) Get(ctx context.Context, taskID string, userID string) (*domain.TaskRecurrence, error) {
+	task, err := s.getTask(ctx, taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	recurrence, err := s.recurrenceRepo.GetByTaskID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if recurrence == nil {
+		return nil, ErrTaskRecurrenceNotFound
+	}
+
+	s.fillNextDueDate(recurrence, task, time.Now())
+	return recurrence, nil
+}
+
+// Set устанавливает или заменяет правило повторения открытой задачи.
+// Следующая задача серии будет создана после завершения этой
+func (s *TaskRecurrenceService) Set(ctx context.Context, taskID string, req domain.TaskRecurrenceRequest, userID string) (*domain.TaskRecurrence, error) {
+	task, err := s.getEditableTask(ctx, taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	rule, err := rrule.Parse(req.Rule)
+	if err != nil {
+		return nil, ErrInvalidRecurrence.WithFields(apperrors.FieldError{Field: "rule", Message: err.Error()})
+	}
+
+	now := time.Now()
+	recurrence := &domain.TaskRecurrence{
+		ID:          uuid.New().String(),
+		TaskID:      task.ID,
+		Rule:        rule.String(),
+		Occurrences: 1,
+		Status:      domain.TaskRecurrenceStatusActive,
+		CreatedBy:   userID,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	if err := s.recurrenceRepo.Upsert(ctx, recurrence); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Task recurrence set", map[string]interface{}{
+		"task_id": task.ID,
+		"rule":    recurrence.Rule,
+		"user_id": userID,
+	})
+
+	s.fillNextDueDate(recurrence, task, now)
+	return recurrence, nil
+}
+
+// Delete удаляет правило повторения задачи; уже созданные задачи серии не затрагиваются
+func (s *TaskRecurrenceService) Delete(ctx context.Context, taskID string, userID string) error {
+	task, err := s.getTask(ctx, taskID, userID)
+	if err != nil {
+		return err
+	}
+	if !s.taskSvc.canManageTask(ctx, task.ProjectID, userID) {
+		return ErrInsufficientRights
+	}
+
+	deleted, err := s.recurrenceRepo.Delete(ctx, taskID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrTaskRecurrenceNotFound
+	}
+
+	s.logger.Info("Task recurrence deleted", map[string]interface{}{
+		"task_id": taskID,
+		"user_id": userID,
+	})
+
+	return nil
+}
+
+// CreateDue создает следующие задачи серий, текущие задачи которых завершены, и возвращает
+// число созданных. Задача создается от имени автора правила с обычными проверками и уведомлениями.
+// Если повторения исчерпаны или создание невозможно (автор потерял доступ, проект архивирован),
+// серия завершается; при прочих ошибках попытка повторяется при следующем запуске
+func (s *TaskRecurrenceService) CreateDue(ctx context.Context, now time.Time) (int, error) {
+	due, err := s.recurrenceRepo.ClaimDue(ctx, now, taskRecurrenceLease, taskRecurrenceBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	created := 0
+	for _, recurrence := range due {
+		if s.createNext(ctx, recurrence, now) {
+			created++
+		}
+	}
+
+	return created, nil
+}
+
+// createNext создает следующую задачу серии и переносит на нее правило
+func (s *TaskRecurrenceService) createNext(ctx context.Context, recurrence *domain.TaskRecurrence, now time.Time) bool {
+	task, err := s.taskRepo.GetByID(ctx, recurrence.TaskID)
+	if err != nil {
+		s.handleCreateError(ctx, recurrence, err)
+		return false
+	}
+
+	rule, err := rrule.Parse(recurrence.Rule)
+	if err != nil || task == nil {
+		s.finish(ctx, recurrence, ErrInvalidRecurrence)
+		return false
+	}
+
+	dueDate, ok := nextDueDate(rule, recurrence.Occurrences, task, now)
+	if !ok {
+		s.finish(ctx, recurrence, nil)
+		return false
+	}
+
+	req := domain.TaskCreateRequest{
+		Title:          task.Title,
+		Description:    task.Description,
+		ProjectID:      task.ProjectID,
+		Priority:       task.Priority,
+		AssigneeID:     task.AssigneeID,
+		AssigneeIDs:    task.AssigneeIDs,
+		DueDate:        &dueDate,
+		EstimatedHours: task.EstimatedHours,
+		Impact:         task.Impact,
+		Urgency:        task.Urgency,
+		Tags:           task.Tags,
+	}
+
+	// Подзадача повторяется в той же родительской задаче, пока та открыта
+	if task.ParentID != nil {
+		if parent, err := s.taskRepo.GetByID(ctx, *task.ParentID); err == nil && parent != nil && isOpenTaskStatus(parent.Status) {
+			req.ParentID = task.ParentID
+		}
+	}
+
+	// Задача и перенос правила на нее сохраняются в одной транзакции, а события задачи
+	// публикуются через таблицу исходящих событий: иначе сбой между ними создал бы
+	// следующую задачу серии повторно при следующем запуске
+	var next *domain.TaskResponse
+	err = s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		next, err = s.taskSvc.Create(messaging.WithOutbox(ctx), req, recurrence.CreatedBy)
+		if err != nil {
+			return err
+		}
+
+		advanced, err := s.recurrenceRepo.Advance(ctx, recurrence.ID, task.ID, next.ID, recurrence.Occurrences+1, time.Now())
+		if err != nil {
+			return err
+		}
+		if !advanced {
+			return errRecurrenceAdvanced
+		}
+		return nil
+	})
+	if errors.Is(err, errRecurrenceAdvanced) {
+		s.logger.Warn("Task recurrence already advanced, skipping", map[string]interface{}{
+			"recurrence_id": recurrence.ID,
+			"task_id":       task.ID,
+		})
+		return false
+	}
+	if err != nil {
+		s.handleCreateError(ctx, recurrence, err)
+		return false
+	}
+
+	s.logger.Info("Recurring task created", map[string]interface{}{
+		"recurrence_id":  recurrence.ID,
+		"completed_task": task.ID,
+		"task_id":        next.ID,
+		"due_date":       dueDate,
+	})
+
+	return true
+}
+
+// handleCreateError фиксирует неудачную попытку создания следующей задачи серии
+func (s *TaskRecurrenceService) handleCreateError(ctx context.Context, recurrence *domain.TaskRecurrence, err error) {
+	var validationErr *TaskValidationError
+	permanent := errors.As(err, &validationErr) ||
+		errors.Is(err, ErrProjectNotFound) ||
+		errors.Is(err, ErrProjectArchived) ||
+		errors.Is(err, ErrInvalidAssignee)
+
+	if permanent {
+		s.finish(ctx, recurrence, err)
+		return
+	}
+
+	s.logger.Warn("Failed to create recurring task, will retry", map[string]interface{}{
+		"recurrence_id": recurrence.ID,
+		"error":         err.Error(),
+	})
+	if err := s.recurrenceRepo.Release(ctx, recurrence.ID, err.Error()); err != nil {
+		s.logger.Error("Failed to release task recurrence", err, map[string]interface{}{
+			"recurrence_id": recurrence.ID,
+		})
+	}
+}
+
+// finish завершает серию; cause задается, если серия завершена из-за ошибки
+func (s *TaskRecurrenceService) finish(ctx context.Context, recurrence *domain.TaskRecurrence, cause error) {
+	var errMsg *string
+	if cause != nil {
+		msg := cause.Error()
+		errMsg = &msg
+		s.logger.Warn("Recurring task cannot be created, series finished", map[string]interface{}{
+			"recurrence_id": recurrence.ID,
+			"task_id":       recurrence.TaskID,
+			"error":         msg,
+		})
+	}
+
+	if err := s.recurrenceRepo.Finish(ctx, recurrence.ID, errMsg, time.Now()); err != nil {
+		s.logger.Error("Failed to finish task recurrence", err, map[string]interface{}{
+			"recurrence_id": recurrence.ID,
+		})
+	}
+}
+
+// fillNextDueDate добавляет срок следующей задачи серии, если она еще будет создана
+func (s *TaskRecurrenceService) fillNextDueDate(recurrence *domain.TaskRecurrence, task *domain.Task, now time.Time) {
+	if recurrence.Status == domain.TaskRecurrenceStatusFinished {
+		return
+	}
+	rule, err := rrule.Parse(recurrence.Rule)
+	if err != nil {
+		return
+	}
+	if dueDate, ok := nextDueDate(rule, recurrence.Occurrences, task, now); ok {
+		recurrence.NextDueDate = &dueDate
+	}
+}
+
+// getTask возвращает задачу, если пользователь имеет к ней доступ
+func (s *TaskRecurrenceService) getTask(ctx context.Context, taskID string, userID string) (*domain.Task, error) {
+	task, err := s.taskRepo.GetByID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if task == nil {
+		return nil, ErrTaskNotFound
+	}
+	if !s.taskSvc.hasAccessToTask(ctx, task.ProjectID, userID) {
+		return nil, ErrTaskAccessDenied
+	}
+	return task, nil
+}
+
+// getEditableTask возвращает открытую задачу доступного для записи проекта,
+// если пользователь может управлять задачами проекта
+func (s *TaskRecurrenceService) getEditableTask(ctx context.Context, taskID string, userID string) (*domain.Task, error) {
+	task, err := s.getTask(ctx, taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.projectSvc.ensureProjectWritable(ctx, task.ProjectID); err != nil {
+		return nil, err
+	}
+	if !s.taskSvc.canManageTask(ctx, task.ProjectID, userID) {
+		return nil, ErrInsufficientRights
+	}
+	if !isOpenTaskStatus(task.Status) {
+		return nil, ErrTaskClosed
+	}
+	return task, nil
+}
+
+// nextDueDate вычисляет срок следующей задачи серии от срока текущей задачи,
+// а если срока нет - от времени ее завершения (или now, пока она не завершена).
+// Повторения, срок которых уже прошел, пропускаются. Возвращает false, если повторения исчерпаны
+func nextDueDate(rule *rrule.Rule, occurrences int, task *domain.Task, now time.Time) (time.Time, bool) {
+	anchor := now
+	switch {
+	case task.DueDate != nil:
+		anchor = *task.DueDate
+	case task.CompletedAt != nil:
+		anchor = *task.CompletedAt
+	}
+
+	next := rule.Next(anchor)
+	for i := 0; i < maxSkippedOccurrences && next.Before(now); i++ {
+		next = rule.Next(next)
+	}
+
+	if rule.Exhausted(occurrences, next) {
+		return time.Time{}, false
+	}
+	return next, true
+}
@@ -3,34 +3,92 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"golang.org/x/sync/errgroup"
 
 	"github.com/nurlyy/task_manager/internal/domain"
 	"github.com/nurlyy/task_manager/internal/messaging"
 	"github.com/nurlyy/task_manager/internal/repository"
 	"github.com/nurlyy/task_manager/internal/repository/cache"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
+	"github.com/nurlyy/task_manager/pkg/lexorank"
 	"github.com/nurlyy/task_manager/pkg/logger"
+	"github.com/nurlyy/task_manager/pkg/validator"
 )
 
 // Стандартные ошибки
 var (
-	ErrTaskNotFound      = errors.New("task not found")
-	ErrTaskAccessDenied  = errors.New("access to task denied")
-	ErrInvalidTaskStatus = errors.New("invalid task status transition")
+	ErrTaskNotFound       = apperrors.New(apperrors.CodeTaskNotFound, "task not found")
+	ErrTaskAccessDenied   = apperrors.New(apperrors.CodeAccessDenied, "access to task denied")
+	ErrInvalidTaskStatus  = apperrors.New(apperrors.CodeInvalidStatus, "invalid task status transition")
+	ErrTaskLocked         = apperrors.New(apperrors.CodeTaskLocked, "task description is being edited by another user")
+	ErrTaskLockNotHeld    = apperrors.New(apperrors.CodeLockNotHeld, "task edit lock is not held by user")
+	ErrTaskValidation     = apperrors.New(apperrors.CodeValidationFailed, "task does not satisfy project rules")
+	ErrInvalidAssignee    = apperrors.New(apperrors.CodeInvalidAssignee, "assignee must be a member of the project")
+	ErrInvalidEffortSplit = apperrors.New(apperrors.CodeInvalidEffortSplit, "effort can only be split between task assignees")
+	ErrInvalidTaskMove    = apperrors.New(apperrors.CodeInvalidMove, "task can only be moved next to another task of the same project")
+	ErrTaskSameProject    = apperrors.New(apperrors.CodeSameProject, "task already belongs to the target project")
+	ErrInvalidParentTask  = apperrors.New(apperrors.CodeInvalidParent, "parent task must be an open task of the same project")
+	ErrOpenSubtasks       = apperrors.New(apperrors.CodeOpenSubtasks, "task cannot be completed while it has open subtasks")
+	ErrTagNotFound        = apperrors.New(apperrors.CodeTagNotFound, "tag is not used in the project")
+	ErrTagExists          = apperrors.New(apperrors.CodeTagExists, "tag already exists in the project, merge the tags instead")
+	ErrTagInUse           = apperrors.New(apperrors.CodeTagInUse, "tag is used by open tasks")
 )
 
+// TaskValidationError содержит нарушения правил заполнения полей задачи, заданных в проекте
+type TaskValidationError struct {
+	Violations []domain.FieldViolation
+}
+
+// Error реализует интерфейс error
+func (e *TaskValidationError) Error() string {
+	fields := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		fields = append(fields, v.Field)
+	}
+	return fmt.Sprintf("%s: %s", ErrTaskValidation.Error(), strings.Join(fields, ", "))
+}
+
+// Unwrap позволяет сравнивать ошибку с ErrTaskValidation через errors.Is
+func (e *TaskValidationError) Unwrap() error {
+	return ErrTaskValidation
+}
+
+// FieldErrors возвращает нарушения в виде ошибок полей для ответа API
+func (e *TaskValidationError) FieldErrors() []apperrors.FieldError {
+	return e.Violations
+}
+
+// taskEditLockTTL определяет время жизни блокировки редактирования описания задачи
+const taskEditLockTTL = 2 * time.Minute
+
+// taskDetailsTimeout ограничивает время загрузки связанных данных задачи
+const taskDetailsTimeout = 3 * time.Second
+
+// taskExportBatchSize определяет число задач, загружаемых за один запрос при выгрузке
+const taskExportBatchSize = 500
+
+// maxSubtasks ограничивает число подзадач, возвращаемых вместе с родительской задачей
+const maxSubtasks = 500
+
 // TaskService представляет бизнес-логику для работы с задачами
 type TaskService struct {
-	taskRepo    repository.TaskRepository
-	projectRepo repository.ProjectRepository
-	userRepo    repository.UserRepository
-	commentRepo repository.CommentRepository
-	cacheRepo   *cache.RedisRepository
-	producer    *messaging.KafkaProducer
-	projectSvc  *ProjectService
-	logger      logger.Logger
+	taskRepo       repository.TaskRepository
+	projectRepo    repository.ProjectRepository
+	userRepo       repository.UserRepository
+	commentRepo    repository.CommentRepository
+	attachmentRepo repository.AttachmentRepository
+	txManager      repository.TxManager
+	cacheRepo      *cache.RedisRepository
+	producer       *messaging.KafkaProducer
+	projectSvc     *ProjectService
+	viewSvc        *TaskViewService
+	logger         logger.Logger
 }
 
 // NewTaskService создает новый экземпляр TaskService
@@ -39,20 +97,26 @@ func NewTaskService(
 	projectRepo repository.ProjectRepository,
 	userRepo repository.UserRepository,
 	commentRepo repository.CommentRepository,
+	attachmentRepo repository.AttachmentRepository,
+	txManager repository.TxManager,
 	cacheRepo *cache.RedisRepository,
 	producer *messaging.KafkaProducer,
 	projectSvc *ProjectService,
+	viewSvc *TaskViewService,
 	logger logger.Logger,
 ) *TaskService {
 	return &TaskService{
-		taskRepo:    taskRepo,
-		projectRepo: projectRepo,
-		userRepo:    userRepo,
-		commentRepo: commentRepo,
-		cacheRepo:   cacheRepo,
-		producer:    producer,
-		projectSvc:  projectSvc,
-		logger:      logger,
+		taskRepo:       taskRepo,
+		projectRepo:    projectRepo,
+		userRepo:       userRepo,
+		commentRepo:    commentRepo,
+		attachmentRepo: attachmentRepo,
+		txManager:      txManager,
+		cacheRepo:      cacheRepo,
+		producer:       producer,
+		projectSvc:     projectSvc,
+		viewSvc:        viewSvc,
+		logger:         logger,
 	}
 }
 
@@ -63,8 +127,26 @@ func (s *TaskService) Create(ctx context.Context, req domain.TaskCreateRequest,
 		return nil, ErrProjectNotFound
 	}
 
-	// Создаем новую задачу
+	// Задачи архивного проекта доступны только для чтения
+	if err := s.projectSvc.ensureProjectWritable(ctx, req.ProjectID); err != nil {
+		return nil, err
+	}
+
+	// Если основной исполнитель не указан, им становится первый из списка
+	if req.AssigneeID == nil && len(req.AssigneeIDs) > 0 {
+		primaryID := req.AssigneeIDs[0]
+		req.AssigneeID = &primaryID
+	}
+
+	// Применяем настройки проекта по умолчанию и проверяем обязательные поля
 	now := time.Now()
+	settings, err := s.projectRepo.GetTaskSettings(ctx, req.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	s.applyTaskDefaults(ctx, &req, settings, now)
+
+	// Создаем новую задачу
 	task := &domain.Task{
 		ID:             uuid.New().String(),
 		Title:          req.Title,
@@ -79,6 +161,42 @@ func (s *TaskService) Create(ctx context.Context, req domain.TaskCreateRequest,
 		CreatedAt:      now,
 		UpdatedAt:      now,
 		Tags:           req.Tags,
+		AssigneeIDs:    mergeAssigneeIDs(req.AssigneeID, req.AssigneeIDs),
+		Impact:         req.Impact,
+		Urgency:        req.Urgency,
+	}
+	task.PriorityScore = task.ComputePriorityScore(now)
+
+	// Новая задача встает в конец ручной сортировки проекта
+	lastRank, err := s.taskRepo.PrevRank(ctx, task.ProjectID, "", task.ID)
+	if err != nil {
+		return nil, err
+	}
+	if task.Rank, err = lexorank.Between(lastRank, ""); err != nil {
+		return nil, err
+	}
+
+	// Дополнительные исполнители должны быть участниками проекта
+	if err := s.checkAssigneesMembership(ctx, task.ProjectID, req.AssigneeIDs); err != nil {
+		return nil, err
+	}
+
+	// Подзадачу можно добавить только к открытой задаче того же проекта
+	if req.ParentID != nil {
+		parent, err := s.taskRepo.GetByID(ctx, *req.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		if parent == nil || parent.ProjectID != task.ProjectID || !isOpenTaskStatus(parent.Status) {
+			return nil, ErrInvalidParentTask
+		}
+		task.ParentID = req.ParentID
+	}
+
+	if settings != nil {
+		if violations := missingTaskFields(task, settings.RequiredFields, validator.Message(ctx, "required_by_project", "")); len(violations) > 0 {
+			return nil, &TaskValidationError{Violations: violations}
+		}
 	}
 
 	// Сохраняем задачу в БД
@@ -107,6 +225,7 @@ func (s *TaskService) Create(ctx context.Context, req domain.TaskCreateRequest,
 		Status:      string(task.Status),
 		Priority:    string(task.Priority),
 		AssigneeID:  task.AssigneeID,
+		AssigneeIDs: task.AssigneeIDs,
 		CreatedBy:   userID,
 		DueDate:     task.DueDate,
 		CreatedAt:   task.CreatedAt,
@@ -122,13 +241,12 @@ func (s *TaskService) Create(ctx context.Context, req domain.TaskCreateRequest,
 		})
 	}
 
-	// Если указан исполнитель, отправляем уведомление о назначении
-	if task.AssigneeID != nil && *task.AssigneeID != userID {
-		s.notifyTaskAssigned(ctx, task, userID)
-	}
+	// Если указаны исполнители, отправляем уведомления о назначении
+	s.notifyTaskAssigned(ctx, task, userID, task.AssigneeIDs)
 
 	// Формируем ответ
 	resp := task.ToResponse()
+	s.fillAssignees(ctx, &resp)
 
 	// Добавляем информацию о пользователях
 	if task.AssigneeID != nil {
@@ -160,17 +278,27 @@ func (s *TaskService) Create(ctx context.Context, req domain.TaskCreateRequest,
 	return &resp, nil
 }
 
-// GetByID возвращает задачу по ID
+// GetByID возвращает задачу по ID со всеми подробностями и первой страницей комментариев
 func (s *TaskService) GetByID(ctx context.Context, id string, userID string) (*domain.TaskResponse, error) {
+	return s.GetDetails(ctx, id, userID, domain.DefaultTaskDetailOptions())
+}
+
+// GetDetails возвращает задачу по ID с запрошенными подробностями. В кэше хранится ответ
+// по умолчанию, поэтому из кэша отдаются только ответы с первой страницей комментариев
+func (s *TaskService) GetDetails(ctx context.Context, id string, userID string, opts domain.TaskDetailOptions) (*domain.TaskResponse, error) {
 	// Пытаемся получить из кэша
 	cacheKey := "task:" + id
 	var taskResp domain.TaskResponse
-	if err := s.cacheRepo.Get(ctx, cacheKey, &taskResp); err == nil {
-		// Проверяем доступ пользователя к задаче
-		if s.hasAccessToTask(ctx, taskResp.ProjectID, userID) {
-			return &taskResp, nil
+	if opts.DefaultComments() {
+		if err := s.cacheRepo.Get(ctx, cacheKey, &taskResp); err == nil {
+			// Проверяем доступ пользователя к задаче
+			if s.hasAccessToTask(ctx, taskResp.ProjectID, userID) {
+				s.viewSvc.RecordView(ctx, userID, taskResp.ID)
+				s.fillDetails(ctx, &taskResp, opts)
+				return &taskResp, nil
+			}
+			return nil, ErrTaskAccessDenied
 		}
-		return nil, ErrTaskAccessDenied
 	}
 
 	// Получаем задачу из БД
@@ -186,112 +314,312 @@ func (s *TaskService) GetByID(ctx context.Context, id string, userID string) (*d
 	if !s.hasAccessToTask(ctx, task.ProjectID, userID) {
 		return nil, ErrTaskAccessDenied
 	}
+	s.viewSvc.RecordView(ctx, userID, task.ID)
 
-	// Получаем теги задачи
-	tags, err := s.taskRepo.GetTags(ctx, id)
+	// Для кэша ответ собирается полностью, иначе загружаются только запрошенные части
+	cacheable := opts.DefaultComments()
+	assembleOpts := opts
+	if cacheable {
+		assembleOpts.Include = nil
+	}
+
+	// Связанные данные загружаются параллельно и независимо друг от друга
+	resp, err := s.assembleTaskDetails(ctx, task, assembleOpts)
 	if err != nil {
-		s.logger.Warn("Failed to get task tags", map[string]interface{}{
-			"task_id": id,
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		// Неполный ответ отдаем, но не кэшируем, чтобы следующий запрос загрузил данные заново
+		s.logger.Warn("Task details are incomplete", map[string]interface{}{
+			"id": id,
 		}, map[string]interface{}{
 			"error": err,
 		})
+		s.fillDetails(ctx, resp, opts)
+		return resp, nil
 	}
-	task.Tags = tags
 
-	// Формируем ответ
-	resp := task.ToResponse()
-
-	// Добавляем информацию о пользователях
-	if task.AssigneeID != nil {
-		assignee, err := s.userRepo.GetByID(ctx, *task.AssigneeID)
-		if err == nil {
-			resp.Assignee = &domain.UserBrief{
-				ID:        assignee.ID,
-				Email:     assignee.Email,
-				FirstName: assignee.FirstName,
-				LastName:  assignee.LastName,
-				Avatar:    assignee.Avatar,
-			}
+	// Сохраняем в кэш
+	if cacheable {
+		if err := s.cacheRepo.Set(ctx, cacheKey, resp); err != nil {
+			s.logger.Warn("Failed to cache task", map[string]interface{}{
+				"id": id,
+			}, map[string]interface{}{
+				"error": err,
+			})
 		}
 	}
 
-	creator, err := s.userRepo.GetByID(ctx, task.CreatedBy)
-	if err == nil {
-		resp.Creator = &domain.UserBrief{
-			ID:        creator.ID,
-			Email:     creator.Email,
-			FirstName: creator.FirstName,
-			LastName:  creator.LastName,
-			Avatar:    creator.Avatar,
-		}
+	s.fillDetails(ctx, resp, opts)
+	return resp, nil
+}
+
+// fillDetails убирает из ответа незапрошенные части и добавляет некэшируемые:
+// сводку по подзадачам и вложения
+func (s *TaskService) fillDetails(ctx context.Context, resp *domain.TaskResponse, opts domain.TaskDetailOptions) {
+	if !opts.Includes(domain.TaskIncludeComments) {
+		resp.Comments = nil
+		resp.CommentCount = nil
+		resp.CommentsHasMore = false
+	}
+	if !opts.Includes(domain.TaskIncludeHistory) {
+		resp.History = nil
+	}
+	if opts.Includes(domain.TaskIncludeSubtasks) {
+		s.fillSubtaskProgress(ctx, resp)
+	}
+	if opts.Includes(domain.TaskIncludeAttachments) {
+		s.fillAttachments(ctx, resp)
+	}
+}
+
+// fillSubtaskProgress добавляет в ответ сводку по подзадачам. Сводка меняется вместе
+// с подзадачами, поэтому не кэшируется вместе с задачей и загружается при каждом запросе
+func (s *TaskService) fillSubtaskProgress(ctx context.Context, resp *domain.TaskResponse) {
+	progress, err := s.taskRepo.GetSubtaskProgress(ctx, resp.ID)
+	if err != nil {
+		s.logger.Warn("Failed to get subtask progress", map[string]interface{}{
+			"id": resp.ID,
+		}, map[string]interface{}{
+			"error": err,
+		})
+		return
 	}
+	if progress.Total > 0 {
+		resp.Subtasks = progress
+	}
+}
+
+// fillAttachments добавляет в ответ метаданные вложений задачи. Вложения удаляются и каскадно
+// вместе с комментариями, поэтому, как и сводка по подзадачам, не кэшируются вместе с задачей
+func (s *TaskService) fillAttachments(ctx context.Context, resp *domain.TaskResponse) {
+	attachments, err := s.attachmentRepo.ListByTask(ctx, resp.ID)
+	if err != nil {
+		s.logger.Warn("Failed to get task attachments", map[string]interface{}{
+			"id": resp.ID,
+		}, map[string]interface{}{
+			"error": err,
+		})
+		return
+	}
+
+	resp.Attachments = make([]domain.Attachment, 0, len(attachments))
+	for _, attachment := range attachments {
+		resp.Attachments = append(resp.Attachments, *attachment)
+	}
+}
 
-	// Получаем комментарии к задаче
-	comments, err := s.commentRepo.GetCommentsByTask(ctx, id, repository.CommentFilter{
-		OrderBy:  func() *string { s := "created_at"; return &s }(),
-		OrderDir: func() *string { s := "desc"; return &s }(),
-		Limit:    50,
+// assembleTaskDetails формирует ответ с тегами, участниками и запрошенными комментариями и историей задачи.
+// Каждая часть загружается в отдельной горутине с общим ограничением по времени
+// taskDetailsTimeout. Ошибка одной части не прерывает остальные: незагруженная часть
+// остается пустой, а ответ возвращается вместе с первой ошибкой, чтобы вызывающий
+// мог не кэшировать неполные данные
+func (s *TaskService) assembleTaskDetails(ctx context.Context, task *domain.Task, opts domain.TaskDetailOptions) (*domain.TaskResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, taskDetailsTimeout)
+	defer cancel()
+
+	var (
+		g        errgroup.Group
+		tags     []string
+		people   map[string]domain.UserBrief
+		comments *taskCommentPage
+		history  []domain.TaskHistoryResponse
+	)
+
+	g.Go(func() error {
+		var err error
+		if tags, err = s.taskRepo.GetTags(ctx, task.ID); err != nil {
+			return fmt.Errorf("failed to get task tags: %w", err)
+		}
+		return nil
 	})
-	if err == nil {
-		commentResponses := make([]domain.CommentResponse, 0, len(comments))
-		for _, comment := range comments {
-			user, err := s.userRepo.GetByID(ctx, comment.UserID)
-			if err != nil {
-				continue
-			}
 
-			brief := domain.UserBrief{
-				ID:        user.ID,
-				Email:     user.Email,
-				FirstName: user.FirstName,
-				LastName:  user.LastName,
-				Avatar:    user.Avatar,
-			}
-			commentResponses = append(commentResponses, comment.ToResponse(brief))
+	g.Go(func() error {
+		userIDs := append([]string{task.CreatedBy}, task.AssigneeIDs...)
+		if task.AssigneeID != nil {
+			userIDs = append(userIDs, *task.AssigneeID)
 		}
-		resp.Comments = commentResponses
+		people = s.getUserBriefs(ctx, userIDs)
+		return ctx.Err()
+	})
+
+	if opts.Includes(domain.TaskIncludeComments) {
+		g.Go(func() error {
+			var err error
+			comments, err = s.getTaskComments(ctx, task.ID, opts)
+			return err
+		})
 	}
 
-	// Получаем историю изменений задачи
-	history, err := s.taskRepo.GetTaskHistory(ctx, id)
-	if err == nil {
-		historyResponses := make([]domain.TaskHistoryResponse, 0, len(history))
-		for _, h := range history {
-			user, err := s.userRepo.GetByID(ctx, h.UserID)
-			if err != nil {
-				continue
-			}
+	if opts.Includes(domain.TaskIncludeHistory) {
+		g.Go(func() error {
+			var err error
+			history, err = s.getTaskHistory(ctx, task.ID)
+			return err
+		})
+	}
 
-			brief := domain.UserBrief{
-				ID:        user.ID,
-				Email:     user.Email,
-				FirstName: user.FirstName,
-				LastName:  user.LastName,
-				Avatar:    user.Avatar,
+	err := g.Wait()
+
+	task.Tags = tags
+	resp := task.ToResponse()
+	if len(resp.AssigneeIDs) > 0 {
+		resp.Assignees = make([]domain.UserBrief, 0, len(resp.AssigneeIDs))
+		for _, assigneeID := range resp.AssigneeIDs {
+			if brief, ok := people[assigneeID]; ok {
+				resp.Assignees = append(resp.Assignees, brief)
 			}
-			historyResponses = append(historyResponses, domain.TaskHistoryResponse{
-				ID:        h.ID,
-				UserID:    h.UserID,
-				User:      brief,
-				Field:     h.Field,
-				OldValue:  h.OldValue,
-				NewValue:  h.NewValue,
-				ChangedAt: h.ChangedAt,
-			})
 		}
-		resp.History = historyResponses
 	}
+	if task.AssigneeID != nil {
+		if brief, ok := people[*task.AssigneeID]; ok {
+			resp.Assignee = &brief
+		}
+	}
+	if brief, ok := people[task.CreatedBy]; ok {
+		resp.Creator = &brief
+	}
+	if comments != nil {
+		resp.Comments = comments.items
+		resp.CommentCount = &comments.total
+		resp.CommentsHasMore = comments.hasMore
+	}
+	resp.History = history
 
-	// Сохраняем в кэш
-	if err := s.cacheRepo.Set(ctx, cacheKey, resp); err != nil {
-		s.logger.Warn("Failed to cache task", map[string]interface{}{
-			"id": id,
-		}, map[string]interface{}{
-			"error": err,
+	return &resp, err
+}
+
+// taskCommentPage - страница комментариев задачи с их общим количеством
+type taskCommentPage struct {
+	items   []domain.CommentResponse
+	total   int
+	hasMore bool
+}
+
+// getTaskComments возвращает страницу комментариев верхнего уровня к задаче с вложенными
+// ответами и данными авторов. Пагинация и общее число учитывают только комментарии
+// верхнего уровня, ответы всегда идут от старых к новым
+func (s *TaskService) getTaskComments(ctx context.Context, taskID string, opts domain.TaskDetailOptions) (*taskCommentPage, error) {
+	orderBy, orderDir := "created_at", opts.CommentsOrder
+	comments, err := s.commentRepo.GetCommentsByTask(ctx, taskID, repository.CommentFilter{
+		RootsOnly: true,
+		OrderBy:   &orderBy,
+		OrderDir:  &orderDir,
+		Limit:     opts.CommentsPageSize,
+		Offset:    (opts.CommentsPage - 1) * opts.CommentsPageSize,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task comments: %w", err)
+	}
+
+	total, err := s.commentRepo.Count(ctx, repository.CommentFilter{
+		TaskIDs:   []string{taskID},
+		RootsOnly: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to count task comments: %w", err)
+	}
+
+	items, err := s.commentTree(ctx, comments)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &taskCommentPage{
+		items:   items,
+		total:   total,
+		hasMore: opts.CommentsPage*opts.CommentsPageSize < total,
+	}
+
+	return page, ctx.Err()
+}
+
+// commentTree формирует ответы для комментариев с вложенными ответами не глубже
+// commentReplyDepth уровней и не более commentRepliesPerRoot под каждым комментарием.
+// Число прямых ответов позволяет клиенту догрузить остальные ответы постранично
+func (s *TaskService) commentTree(ctx context.Context, comments []*domain.Comment) ([]domain.CommentResponse, error) {
+	rootIDs := make([]string, 0, len(comments))
+	for _, comment := range comments {
+		rootIDs = append(rootIDs, comment.ID)
+	}
+	replies, err := s.commentRepo.GetReplies(ctx, rootIDs, commentReplyDepth, commentRepliesPerRoot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get comment replies: %w", err)
+	}
+
+	ids := rootIDs
+	for _, reply := range replies {
+		ids = append(ids, reply.ID)
+	}
+	replyCounts, err := s.commentRepo.CountReplies(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count comment replies: %w", err)
+	}
+
+	roots := s.commentResponses(ctx, comments)
+	nested := s.commentResponses(ctx, replies)
+	for i := range roots {
+		roots[i].ReplyCount = replyCounts[roots[i].ID]
+	}
+	for i := range nested {
+		nested[i].ReplyCount = replyCounts[nested[i].ID]
+	}
+
+	return domain.NestCommentReplies(roots, nested), nil
+}
+
+// commentResponses формирует ответы для комментариев с данными авторов.
+// Комментарии, автора которых не удалось загрузить, пропускаются
+func (s *TaskService) commentResponses(ctx context.Context, comments []*domain.Comment) []domain.CommentResponse {
+	userIDs := make([]string, 0, len(comments))
+	for _, comment := range comments {
+		userIDs = append(userIDs, comment.UserID)
+	}
+	users := s.getUserBriefs(ctx, userIDs)
+
+	responses := make([]domain.CommentResponse, 0, len(comments))
+	for _, comment := range comments {
+		brief, ok := users[comment.UserID]
+		if !ok {
+			continue
+		}
+		responses = append(responses, comment.ToResponse(brief))
+	}
+	return responses
+}
+
+// getTaskHistory возвращает историю изменений задачи с данными авторов изменений
+func (s *TaskService) getTaskHistory(ctx context.Context, taskID string) ([]domain.TaskHistoryResponse, error) {
+	history, err := s.taskRepo.GetTaskHistory(ctx, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task history: %w", err)
+	}
+
+	userIDs := make([]string, 0, len(history))
+	for _, h := range history {
+		userIDs = append(userIDs, h.UserID)
+	}
+	users := s.getUserBriefs(ctx, userIDs)
+
+	responses := make([]domain.TaskHistoryResponse, 0, len(history))
+	for _, h := range history {
+		brief, ok := users[h.UserID]
+		if !ok {
+			continue
+		}
+		responses = append(responses, domain.TaskHistoryResponse{
+			ID:        h.ID,
+			UserID:    h.UserID,
+			User:      brief,
+			Field:     h.Field,
+			OldValue:  h.OldValue,
+			NewValue:  h.NewValue,
+			ChangedAt: h.ChangedAt,
 		})
 	}
 
-	return &resp, nil
+	return responses, ctx.Err()
 }
 
 // Update обновляет данные задачи
@@ -310,6 +638,11 @@ func (s *TaskService) Update(ctx context.Context, id string, req domain.TaskUpda
 		return nil, ErrTaskAccessDenied
 	}
 
+	// Задачи архивного проекта доступны только для чтения
+	if err := s.projectSvc.ensureProjectWritable(ctx, task.ProjectID); err != nil {
+		return nil, err
+	}
+
 	// Для смены статуса нужно проверить, что пользователь не ниже члена проекта
 	if req.Status != nil && *req.Status != task.Status {
 		if !s.canManageTask(ctx, task.ProjectID, userID) {
@@ -319,6 +652,9 @@ func (s *TaskService) Update(ctx context.Context, id string, req domain.TaskUpda
 
 	// Фиксируем изменения для события
 	changes := make(map[string]interface{})
+	oldStatus := task.Status
+	oldAssigneeIDs := task.AssigneeIDs
+	oldTags := task.Tags
 
 	// Обновляем поля, которые были переданы
 	if req.Title != nil {
@@ -348,6 +684,11 @@ func (s *TaskService) Update(ctx context.Context, id string, req domain.TaskUpda
 		}
 		changes["assignee_id"] = map[string]interface{}{"old": oldAssigneeID, "new": newAssigneeID}
 		task.AssigneeID = req.AssigneeID
+
+		// Новый основной исполнитель должен быть участником проекта
+		if err := s.checkAssigneesMembership(ctx, task.ProjectID, []string{newAssigneeID}); err != nil {
+			return nil, err
+		}
 	}
 	if req.DueDate != nil {
 		changes["due_date"] = map[string]interface{}{"old": task.DueDate, "new": *req.DueDate}
@@ -361,8 +702,20 @@ func (s *TaskService) Update(ctx context.Context, id string, req domain.TaskUpda
 		changes["spent_hours"] = map[string]interface{}{"old": task.SpentHours, "new": *req.SpentHours}
 		task.SpentHours = req.SpentHours
 	}
+	if req.Impact != nil {
+		changes["impact"] = map[string]interface{}{"old": task.Impact, "new": *req.Impact}
+		task.Impact = req.Impact
+	}
+	if req.Urgency != nil {
+		changes["urgency"] = map[string]interface{}{"old": task.Urgency, "new": *req.Urgency}
+		task.Urgency = req.Urgency
+	}
+	if req.Tags != nil {
+		changes["tags"] = map[string]interface{}{"old": oldTags, "new": *req.Tags}
+	}
 
 	task.UpdatedAt = time.Now()
+	task.PriorityScore = task.ComputePriorityScore(task.UpdatedAt)
 
 	// Если статус изменен на "завершено", устанавливаем дату завершения
 	if req.Status != nil && *req.Status == domain.TaskStatusCompleted && task.CompletedAt == nil {
@@ -372,72 +725,74 @@ func (s *TaskService) Update(ctx context.Context, id string, req domain.TaskUpda
 		task.CompletedAt = nil
 	}
 
-	// Обновляем задачу в БД
-	if err := s.taskRepo.Update(ctx, task); err != nil {
-		s.logger.Error("Failed to update task", err, map[string]interface{}{
-			"id": id,
-		})
-		return nil, err
-	}
-
-	// Обновляем теги, если они были переданы
-	if req.Tags != nil {
-		if err := s.taskRepo.UpdateTags(ctx, task.ID, *req.Tags); err != nil {
-			s.logger.Warn("Failed to update task tags", map[string]interface{}{
-				"task_id": task.ID,
-			}, map[string]interface{}{
-				"error": err,
-			})
+	// При смене статуса проверяем поля, обязательные для перехода, с учетом переданных изменений
+	if req.Status != nil && *req.Status != oldStatus {
+		if req.Tags != nil {
+			task.Tags = *req.Tags
 		}
-		task.Tags = *req.Tags
-	} else {
-		// Получаем текущие теги
-		tags, err := s.taskRepo.GetTags(ctx, id)
-		if err == nil {
-			task.Tags = tags
+		if err := s.checkTransitionRules(ctx, task, *req.Status); err != nil {
+			return nil, err
+		}
+		if err := s.checkOpenSubtasks(ctx, task.ID, *req.Status); err != nil {
+			return nil, err
 		}
 	}
 
-	// Удаляем задачу из кэша
-	cacheKey := "task:" + id
-	if err := s.cacheRepo.Delete(ctx, cacheKey); err != nil {
-		s.logger.Warn("Failed to delete task from cache", map[string]interface{}{
-			"id": id,
-		}, map[string]interface{}{
-			"error": err,
-		})
-	}
+	// Задача, ее теги и событие об обновлении сохраняются в одной транзакции, чтобы при ошибке
+	// не сохранилась только часть изменений, а событие публиковалось только вместе с ними
+	err = s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.taskRepo.Update(ctx, task); err != nil {
+			s.logger.Error("Failed to update task", err, map[string]interface{}{
+				"id": id,
+			})
+			return err
+		}
 
-	// Отправляем событие об обновлении задачи, если были изменения
-	if len(changes) > 0 {
-		event := &messaging.TaskEvent{
-			ID:         task.ID,
-			Title:      task.Title,
-			ProjectID:  task.ProjectID,
-			Status:     string(task.Status),
-			Priority:   string(task.Priority),
-			AssigneeID: task.AssigneeID,
-			UpdatedAt:  task.UpdatedAt,
-			Type:       messaging.EventTypeTaskUpdated,
-			Changes:    changes,
+		if req.Tags != nil {
+			if err := s.taskRepo.UpdateTags(ctx, task.ID, *req.Tags); err != nil {
+				s.logger.Error("Failed to update task tags", err, map[string]interface{}{
+					"task_id": task.ID,
+				})
+				return err
+			}
+			task.Tags = *req.Tags
+		} else {
+			// Получаем текущие теги
+			tags, err := s.taskRepo.GetTags(ctx, id)
+			if err == nil {
+				task.Tags = tags
+			}
 		}
 
-		if err := s.producer.PublishTaskUpdated(ctx, event, event.Changes); err != nil {
-			s.logger.Warn("Failed to publish task update event", map[string]interface{}{
-				"task_id": task.ID,
-			}, map[string]interface{}{
-				"error": err,
-			})
+		// Смена основного исполнителя меняет и список исполнителей
+		if _, ok := changes["assignee_id"]; ok {
+			assigneeIDs, err := s.taskRepo.GetAssignees(ctx, id)
+			if err == nil {
+				task.AssigneeIDs = assigneeIDs
+			}
 		}
 
-		// Если изменился исполнитель, отправляем уведомление
-		if _, ok := changes["assignee_id"]; ok && task.AssigneeID != nil && *task.AssigneeID != userID {
-			s.notifyTaskAssigned(ctx, task, userID)
+		// Записываем событие об обновлении задачи, если были изменения
+		if len(changes) == 0 {
+			return nil
 		}
+		return s.enqueueTaskChanges(ctx, task, task.UpdatedAt, changes)
+	})
+	if err != nil {
+		return nil, err
 	}
 
+	// Если изменился исполнитель, уведомляем только новых исполнителей
+	if _, ok := changes["assignee_id"]; ok {
+		s.notifyTaskAssigned(ctx, task, userID, addedAssigneeIDs(oldAssigneeIDs, task.AssigneeIDs))
+	}
+
+	// Собственные изменения пользователя не отмечаются в списках как непросмотренные
+	s.viewSvc.RecordView(ctx, userID, task.ID)
+
 	// Формируем ответ
 	resp := task.ToResponse()
+	s.fillAssignees(ctx, &resp)
 
 	// Добавляем информацию о пользователях
 	if task.AssigneeID != nil {
@@ -469,29 +824,105 @@ func (s *TaskService) Update(ctx context.Context, id string, req domain.TaskUpda
 
 // Вспомогательные методы
 
-// hasAccessToTask проверяет, имеет ли пользователь доступ к задаче
-func (s *TaskService) hasAccessToTask(ctx context.Context, projectID string, userID string) bool {
-	return s.projectSvc.hasAccessToProject(ctx, projectID, userID)
-}
+// applyTaskDefaults заполняет незаданные поля новой задачи значениями из настроек проекта
+func (s *TaskService) applyTaskDefaults(ctx context.Context, req *domain.TaskCreateRequest, settings *domain.ProjectTaskSettings, now time.Time) {
+	if settings != nil {
+		// Исполнитель по умолчанию назначается, только если он все еще участник проекта
+		if req.AssigneeID == nil && settings.DefaultAssigneeID != nil {
+			if _, err := s.projectRepo.GetMember(ctx, req.ProjectID, *settings.DefaultAssigneeID); err == nil {
+				assigneeID := *settings.DefaultAssigneeID
+				req.AssigneeID = &assigneeID
+			}
+		}
+		if req.Priority == "" && settings.DefaultPriority != nil {
+			req.Priority = *settings.DefaultPriority
+		}
+		if req.DueDate == nil && settings.DefaultDueOffsetDays != nil {
+			dueDate := now.AddDate(0, 0, *settings.DefaultDueOffsetDays)
+			req.DueDate = &dueDate
+		}
+	}
 
-// canManageTask проверяет, может ли пользователь управлять задачей
-func (s *TaskService) canManageTask(ctx context.Context, projectID string, userID string) bool {
-	// Получаем пользователя
-	user, err := s.userRepo.GetByID(ctx, userID)
-	if err == nil && user.IsAdmin() {
-		return true
+	if req.Priority == "" {
+		req.Priority = domain.TaskPriorityMedium
 	}
+}
 
-	// Проверяем, является ли пользователь участником проекта
-	member, err := s.projectRepo.GetMember(ctx, projectID, userID)
+// checkTransitionRules проверяет, что у задачи заполнены поля, которые проект требует
+// для перехода в указанный статус
+func (s *TaskService) checkTransitionRules(ctx context.Context, task *domain.Task, status domain.TaskStatus) error {
+	settings, err := s.projectRepo.GetTaskSettings(ctx, task.ProjectID)
 	if err != nil {
-		return false
+		return err
+	}
+	if settings == nil || len(settings.TransitionRules[status]) == 0 {
+		return nil
+	}
+
+	message := validator.Message(ctx, "required_for_status", string(status))
+	if violations := missingTaskFields(task, settings.TransitionRules[status], message); len(violations) > 0 {
+		return &TaskValidationError{Violations: violations}
+	}
+
+	return nil
+}
+
+// checkOpenSubtasks запрещает завершать задачу, пока у нее есть открытые подзадачи
+func (s *TaskService) checkOpenSubtasks(ctx context.Context, taskID string, status domain.TaskStatus) error {
+	if status != domain.TaskStatusCompleted {
+		return nil
+	}
+
+	progress, err := s.taskRepo.GetSubtaskProgress(ctx, taskID)
+	if err != nil {
+		return err
+	}
+	if progress.Open > 0 {
+		return ErrOpenSubtasks
+	}
+
+	return nil
+}
+
+// isOpenTaskStatus проверяет, что задача не завершена и не отменена
+func isOpenTaskStatus(status domain.TaskStatus) bool {
+	return status != domain.TaskStatusCompleted && status != domain.TaskStatusCancelled
+}
+
+// missingTaskFields возвращает нарушения для незаполненных обязательных полей задачи
+func missingTaskFields(task *domain.Task, fields []domain.TaskField, message string) []domain.FieldViolation {
+	var violations []domain.FieldViolation
+	for _, field := range fields {
+		missing := false
+		switch field {
+		case domain.TaskFieldAssignee:
+			missing = task.AssigneeID == nil
+		case domain.TaskFieldDueDate:
+			missing = task.DueDate == nil
+		case domain.TaskFieldEstimate:
+			missing = task.EstimatedHours == nil
+		case domain.TaskFieldTags:
+			missing = len(task.Tags) == 0
+		}
+		if missing {
+			violations = append(violations, domain.FieldViolation{
+				Field:   string(field),
+				Message: message,
+			})
+		}
 	}
 
-	// Проверяем роль пользователя в проекте
-	return member.Role == domain.ProjectRoleOwner ||
-		member.Role == domain.ProjectRoleManager ||
-		member.Role == domain.ProjectRoleMember
+	return violations
+}
+
+// hasAccessToTask проверяет, имеет ли пользователь доступ к задаче
+func (s *TaskService) hasAccessToTask(ctx context.Context, projectID string, userID string) bool {
+	return s.projectSvc.hasAccessToProject(ctx, projectID, userID)
+}
+
+// canManageTask проверяет, может ли пользователь управлять задачей
+func (s *TaskService) canManageTask(ctx context.Context, projectID string, userID string) bool {
+	return s.projectSvc.canEditProject(ctx, projectID, userID)
 }
 
 // isValidStatusTransition проверяет корректность перехода из одного статуса в другой
@@ -548,18 +979,16 @@ func (s *TaskService) isValidStatusTransition(from, to domain.TaskStatus) bool {
 	return false
 }
 
-// notifyTaskAssigned отправляет уведомление о назначении задачи
-func (s *TaskService) notifyTaskAssigned(ctx context.Context, task *domain.Task, assignerID string) {
-	if task.AssigneeID == nil {
-		return
+// notifyTaskAssigned отправляет уведомление о назначении задачи указанным исполнителям
+func (s *TaskService) notifyTaskAssigned(ctx context.Context, task *domain.Task, assignerID string, assigneeIDs []string) {
+	// Назначившему самого себя уведомление не нужно
+	recipients := make([]string, 0, len(assigneeIDs))
+	for _, assigneeID := range assigneeIDs {
+		if assigneeID != assignerID {
+			recipients = append(recipients, assigneeID)
+		}
 	}
-
-	// Получаем данные о пользователях
-	assignee, err := s.userRepo.GetByID(ctx, *task.AssigneeID)
-	if err != nil {
-		s.logger.Error("Failed to get assignee", err, map[string]interface{}{
-			"user_id": assignee.ID,
-		})
+	if len(recipients) == 0 {
 		return
 	}
 
@@ -573,7 +1002,7 @@ func (s *TaskService) notifyTaskAssigned(ctx context.Context, task *domain.Task,
 
 	// Создаем событие для отправки уведомления
 	notificationEvent := &messaging.NotificationEvent{
-		UserIDs:    []string{*task.AssigneeID},
+		UserIDs:    recipients,
 		Title:      "Task assigned to you",
 		Content:    assigner.FullName() + " assigned you the task: " + task.Title,
 		Type:       string(domain.NotificationTypeTaskAssigned),
@@ -595,6 +1024,194 @@ func (s *TaskService) notifyTaskAssigned(ctx context.Context, task *domain.Task,
 	}
 }
 
+// checkAssigneesMembership проверяет, что все исполнители являются участниками проекта
+func (s *TaskService) checkAssigneesMembership(ctx context.Context, projectID string, assigneeIDs []string) error {
+	for _, assigneeID := range assigneeIDs {
+		if assigneeID == "" {
+			continue
+		}
+		if _, err := s.projectRepo.GetMember(ctx, projectID, assigneeID); err != nil {
+			return ErrInvalidAssignee
+		}
+	}
+	return nil
+}
+
+// assignmentDueOverlapWindow задает, насколько близкие сроки задач исполнителя считаются пересекающимися
+const assignmentDueOverlapWindow = 24 * time.Hour
+
+// checkAssigneeAvailability проверяет загрузку исполнителя задачи: число его незавершенных задач
+// во всех проектах сверяется с порогом из настроек проекта, а срок задачи - со сроками этих задач.
+// Ошибки проверки только логируются: без предупреждений назначение остается в силе
+func (s *TaskService) checkAssigneeAvailability(ctx context.Context, task *domain.Task, assigneeID string) []domain.AssignmentWarning {
+	limit := domain.DefaultAssigneeOpenTaskLimit
+	settings, err := s.projectRepo.GetTaskSettings(ctx, task.ProjectID)
+	if err != nil {
+		s.logger.Warn("Failed to get project task settings for assignee load check", map[string]interface{}{
+			"project_id": task.ProjectID,
+		}, map[string]interface{}{
+			"error": err,
+		})
+	} else if settings != nil {
+		limit = settings.AssigneeOpenTaskLimit
+	}
+
+	var dueFrom, dueTo *time.Time
+	if task.DueDate != nil {
+		from := task.DueDate.Add(-assignmentDueOverlapWindow)
+		to := task.DueDate.Add(assignmentDueOverlapWindow)
+		dueFrom, dueTo = &from, &to
+	}
+
+	load, err := s.taskRepo.GetAssigneeLoad(ctx, assigneeID, task.ID, dueFrom, dueTo)
+	if err != nil {
+		s.logger.Warn("Failed to check assignee load", map[string]interface{}{
+			"task_id":     task.ID,
+			"assignee_id": assigneeID,
+		}, map[string]interface{}{
+			"error": err,
+		})
+		return nil
+	}
+
+	var warnings []domain.AssignmentWarning
+	if limit > 0 && load.OpenTasks > limit {
+		warnings = append(warnings, domain.AssignmentWarning{
+			Code:       domain.AssignmentWarningOpenTasks,
+			Message:    fmt.Sprintf("Assignee already has %d open tasks (limit %d)", load.OpenTasks, limit),
+			AssigneeID: assigneeID,
+			Count:      load.OpenTasks,
+			Limit:      limit,
+		})
+	}
+	if load.OverlappingTasks > 0 {
+		warnings = append(warnings, domain.AssignmentWarning{
+			Code:       domain.AssignmentWarningOverlappingDueDates,
+			Message:    fmt.Sprintf("Assignee has %d open tasks due within a day of this task", load.OverlappingTasks),
+			AssigneeID: assigneeID,
+			Count:      load.OverlappingTasks,
+		})
+	}
+	return warnings
+}
+
+// fillAssignees добавляет в ответ краткую информацию обо всех исполнителях задачи
+func (s *TaskService) fillAssignees(ctx context.Context, resp *domain.TaskResponse) {
+	if len(resp.AssigneeIDs) == 0 {
+		return
+	}
+
+	resp.Assignees = make([]domain.UserBrief, 0, len(resp.AssigneeIDs))
+	for _, assigneeID := range resp.AssigneeIDs {
+		assignee, err := s.userRepo.GetByID(ctx, assigneeID)
+		if err != nil {
+			continue
+		}
+		resp.Assignees = append(resp.Assignees, domain.UserBrief{
+			ID:        assignee.ID,
+			Email:     assignee.Email,
+			FirstName: assignee.FirstName,
+			LastName:  assignee.LastName,
+			Avatar:    assignee.Avatar,
+		})
+	}
+}
+
+// getUserBriefs возвращает краткие данные пользователей по ID без повторных запросов.
+// Пользователи сначала ищутся в кэше одним запросом, остальные загружаются из БД тоже одним
+func (s *TaskService) getUserBriefs(ctx context.Context, userIDs []string) map[string]domain.UserBrief {
+	briefs := make(map[string]domain.UserBrief, len(userIDs))
+	unique := make([]string, 0, len(userIDs))
+	seen := make(map[string]bool, len(userIDs))
+	for _, id := range userIDs {
+		if id != "" && !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	if len(unique) == 0 {
+		return briefs
+	}
+
+	cached, err := s.cacheRepo.GetUserResponses(ctx, unique)
+	if err != nil {
+		s.logger.Warn("Failed to get users from cache", map[string]interface{}{
+			"count": len(unique),
+		}, map[string]interface{}{
+			"error": err,
+		})
+	}
+
+	missing := make([]string, 0, len(unique))
+	for _, id := range unique {
+		user, ok := cached[id]
+		if !ok {
+			missing = append(missing, id)
+			continue
+		}
+		briefs[id] = domain.UserBrief{
+			ID:        user.ID,
+			Email:     user.Email,
+			FirstName: user.FirstName,
+			LastName:  user.LastName,
+			Avatar:    user.Avatar,
+		}
+	}
+	if len(missing) == 0 {
+		return briefs
+	}
+
+	users, err := s.userRepo.GetByIDs(ctx, missing)
+	if err != nil {
+		s.logger.Warn("Failed to get users", map[string]interface{}{
+			"count": len(missing),
+		}, map[string]interface{}{
+			"error": err,
+		})
+		return briefs
+	}
+	for id, user := range users {
+		briefs[id] = domain.UserBrief{
+			ID:        user.ID,
+			Email:     user.Email,
+			FirstName: user.FirstName,
+			LastName:  user.LastName,
+			Avatar:    user.Avatar,
+		}
+	}
+
+	return briefs
+}
+
+// mergeAssigneeIDs объединяет основного исполнителя со списком, исключая повторы.
+// Основной исполнитель всегда идет первым
+func mergeAssigneeIDs(primaryID *string, assigneeIDs []string) []string {
+	result := []string{}
+	seen := make(map[string]bool)
+	if primaryID != nil {
+		result = append(result, *primaryID)
+		seen[*primaryID] = true
+	}
+	for _, id := range assigneeIDs {
+		if !seen[id] {
+			result = append(result, id)
+			seen[id] = true
+		}
+	}
+	return result
+}
+
+// addedAssigneeIDs возвращает исполнителей, которых не было в прежнем списке
+func addedAssigneeIDs(oldIDs, newIDs []string) []string {
+	added := []string{}
+	for _, id := range newIDs {
+		if !containsString(oldIDs, id) {
+			added = append(added, id)
+		}
+	}
+	return added
+}
+
 // UpdateAssignee обновляет исполнителя задачи
 func (s *TaskService) UpdateAssignee(ctx context.Context, id string, assigneeID *string, userID string) (*domain.TaskResponse, error) {
 	// Получаем задачу из БД
@@ -611,6 +1228,11 @@ func (s *TaskService) UpdateAssignee(ctx context.Context, id string, assigneeID
 		return nil, ErrTaskAccessDenied
 	}
 
+	// Задачи архивного проекта доступны только для чтения
+	if err := s.projectSvc.ensureProjectWritable(ctx, task.ProjectID); err != nil {
+		return nil, err
+	}
+
 	// Проверяем права на изменение исполнителя
 	if !s.canManageTask(ctx, task.ProjectID, userID) && task.CreatedBy != userID {
 		return nil, ErrInsufficientRights
@@ -618,8 +1240,8 @@ func (s *TaskService) UpdateAssignee(ctx context.Context, id string, assigneeID
 
 	// Если указан новый исполнитель, проверяем, что он является участником проекта
 	if assigneeID != nil {
-		if _, err := s.projectRepo.GetMember(ctx, task.ProjectID, *assigneeID); err != nil {
-			return nil, errors.New("assignee must be a member of the project")
+		if err := s.checkAssigneesMembership(ctx, task.ProjectID, []string{*assigneeID}); err != nil {
+			return nil, err
 		}
 	}
 
@@ -631,16 +1253,6 @@ func (s *TaskService) UpdateAssignee(ctx context.Context, id string, assigneeID
 		return nil, err
 	}
 
-	// Удаляем задачу из кэша
-	cacheKey := "task:" + id
-	if err := s.cacheRepo.Delete(ctx, cacheKey); err != nil {
-		s.logger.Warn("Failed to delete task from cache", map[string]interface{}{
-			"id": id,
-		}, map[string]interface{}{
-			"error": err,
-		})
-	}
-
 	// Получаем обновленную задачу
 	updatedTask, err := s.taskRepo.GetByID(ctx, id)
 	if err != nil {
@@ -666,15 +1278,16 @@ func (s *TaskService) UpdateAssignee(ctx context.Context, id string, assigneeID
 	}
 
 	event := &messaging.TaskEvent{
-		ID:         updatedTask.ID,
-		Title:      updatedTask.Title,
-		ProjectID:  updatedTask.ProjectID,
-		Status:     string(updatedTask.Status),
-		Priority:   string(updatedTask.Priority),
-		AssigneeID: updatedTask.AssigneeID,
-		AssignerID: userID,
-		UpdatedAt:  updatedTask.UpdatedAt,
-		Type:       messaging.EventTypeTaskAssigned,
+		ID:          updatedTask.ID,
+		Title:       updatedTask.Title,
+		ProjectID:   updatedTask.ProjectID,
+		Status:      string(updatedTask.Status),
+		Priority:    string(updatedTask.Priority),
+		AssigneeID:  updatedTask.AssigneeID,
+		AssigneeIDs: updatedTask.AssigneeIDs,
+		AssignerID:  userID,
+		UpdatedAt:   updatedTask.UpdatedAt,
+		Type:        messaging.EventTypeTaskAssigned,
 		Changes: map[string]interface{}{
 			"assignee_id": map[string]interface{}{
 				"old": oldAssigneeID,
@@ -692,12 +1305,11 @@ func (s *TaskService) UpdateAssignee(ctx context.Context, id string, assigneeID
 	}
 
 	// Если назначен новый исполнитель, отправляем уведомление
-	if updatedTask.AssigneeID != nil && *updatedTask.AssigneeID != userID {
-		s.notifyTaskAssigned(ctx, updatedTask, userID)
-	}
+	s.notifyTaskAssigned(ctx, updatedTask, userID, addedAssigneeIDs(task.AssigneeIDs, updatedTask.AssigneeIDs))
 
 	// Формируем ответ
 	resp := updatedTask.ToResponse()
+	s.fillAssignees(ctx, &resp)
 
 	// Добавляем информацию о пользователях
 	if updatedTask.AssigneeID != nil {
@@ -724,164 +1336,1336 @@ func (s *TaskService) UpdateAssignee(ctx context.Context, id string, assigneeID
 		}
 	}
 
+	// Новому исполнителю проверяем загрузку; предупреждения не отменяют назначение
+	if assigneeID != nil && oldAssigneeID != *assigneeID {
+		resp.Warnings = s.checkAssigneeAvailability(ctx, updatedTask, *assigneeID)
+	}
+
 	return &resp, nil
 }
 
-// LogTime добавляет запись о затраченном времени
-func (s *TaskService) LogTime(ctx context.Context, id string, req domain.LogTimeRequest, userID string) error {
+// UpdateAssignees заменяет список исполнителей задачи.
+// Если основной исполнитель не указан, сохраняется текущий, а при его отсутствии в списке им становится первый
+func (s *TaskService) UpdateAssignees(ctx context.Context, id string, req domain.TaskAssigneesRequest, userID string) (*domain.TaskResponse, error) {
 	// Получаем задачу из БД
 	task, err := s.taskRepo.GetByID(ctx, id)
-	if err != nil {
-		s.logger.Error("Failed to get task by ID for logging time", err, map[string]interface{}{
+	if err != nil || task == nil {
+		s.logger.Error("Failed to get task by ID for assignees update", err, map[string]interface{}{
 			"id": id,
 		})
-		return ErrTaskNotFound
+		return nil, ErrTaskNotFound
 	}
 
 	// Проверяем доступ пользователя к задаче
 	if !s.hasAccessToTask(ctx, task.ProjectID, userID) {
-		return ErrTaskAccessDenied
+		return nil, ErrTaskAccessDenied
 	}
 
-	// Создаем запись о затраченном времени
-	logDate := time.Now()
-	if req.Date != nil {
-		logDate = *req.Date
+	// Задачи архивного проекта доступны только для чтения
+	if err := s.projectSvc.ensureProjectWritable(ctx, task.ProjectID); err != nil {
+		return nil, err
 	}
 
-	timeLog := &repository.TimeLog{
-		ID:          uuid.New().String(),
-		TaskID:      id,
-		UserID:      userID,
-		Hours:       req.Hours,
-		Description: req.Description,
-		LoggedAt:    time.Now(),
-		LogDate:     logDate,
+	// Проверяем права на изменение исполнителей
+	if !s.canManageTask(ctx, task.ProjectID, userID) && task.CreatedBy != userID {
+		return nil, ErrInsufficientRights
 	}
 
-	// Добавляем запись о затраченном времени
-	if err := s.taskRepo.LogTime(ctx, timeLog); err != nil {
-		s.logger.Error("Failed to log time", err, map[string]interface{}{
-			"task_id": id,
-		})
-		return err
+	// Определяем основного исполнителя
+	primaryID := req.PrimaryID
+	if primaryID == nil && task.AssigneeID != nil && containsString(req.AssigneeIDs, *task.AssigneeID) {
+		primaryID = task.AssigneeID
 	}
-
-	// Обновляем общее затраченное время в задаче
-	var spentHours float64
-	if task.SpentHours != nil {
-		spentHours = *task.SpentHours + req.Hours
-	} else {
-		spentHours = req.Hours
+	if primaryID == nil && len(req.AssigneeIDs) > 0 {
+		primaryID = &req.AssigneeIDs[0]
 	}
+	assigneeIDs := mergeAssigneeIDs(primaryID, req.AssigneeIDs)
 
-	task.SpentHours = &spentHours
-	task.UpdatedAt = time.Now()
-
-	if err := s.taskRepo.Update(ctx, task); err != nil {
-		s.logger.Error("Failed to update task spent hours", err, map[string]interface{}{
-			"id": id,
-		})
-		return err
+	// Все исполнители должны быть участниками проекта
+	if err := s.checkAssigneesMembership(ctx, task.ProjectID, assigneeIDs); err != nil {
+		return nil, err
 	}
 
-	// Удаляем задачу из кэша
-	cacheKey := "task:" + id
-	if err := s.cacheRepo.Delete(ctx, cacheKey); err != nil {
-		s.logger.Warn("Failed to delete task from cache", map[string]interface{}{
+	// Сохраняем исполнителей
+	if err := s.taskRepo.SetAssignees(ctx, id, assigneeIDs, primaryID, userID); err != nil {
+		s.logger.Error("Failed to update task assignees", err, map[string]interface{}{
 			"id": id,
-		}, map[string]interface{}{
-			"error": err,
 		})
+		return nil, err
 	}
 
-	return nil
-}
-
-// GetTimeLogs возвращает записи о затраченном времени
-func (s *TaskService) GetTimeLogs(ctx context.Context, id string, userID string) ([]*repository.TimeLog, error) {
-	// Получаем задачу из БД
-	task, err := s.taskRepo.GetByID(ctx, id)
+	// Получаем обновленную задачу
+	updatedTask, err := s.taskRepo.GetByID(ctx, id)
 	if err != nil {
-		s.logger.Error("Failed to get task by ID for getting time logs", err, map[string]interface{}{
+		s.logger.Error("Failed to get updated task", err, map[string]interface{}{
 			"id": id,
 		})
-		return nil, ErrTaskNotFound
+		return nil, err
 	}
 
-	// Проверяем доступ пользователя к задаче
-	if !s.hasAccessToTask(ctx, task.ProjectID, userID) {
-		return nil, ErrTaskAccessDenied
+	// Отправляем событие об изменении исполнителей
+	var oldAssigneeID, newAssigneeID string
+	if task.AssigneeID != nil {
+		oldAssigneeID = *task.AssigneeID
+	}
+	if updatedTask.AssigneeID != nil {
+		newAssigneeID = *updatedTask.AssigneeID
 	}
 
-	// Получаем записи о затраченном времени
-	timeLogs, err := s.taskRepo.GetTimeLogs(ctx, id)
-	if err != nil {
-		s.logger.Error("Failed to get time logs", err, map[string]interface{}{
-			"task_id": id,
-		})
-		return nil, err
+	event := &messaging.TaskEvent{
+		ID:          updatedTask.ID,
+		Title:       updatedTask.Title,
+		ProjectID:   updatedTask.ProjectID,
+		Status:      string(updatedTask.Status),
+		Priority:    string(updatedTask.Priority),
+		AssigneeID:  updatedTask.AssigneeID,
+		AssigneeIDs: updatedTask.AssigneeIDs,
+		AssignerID:  userID,
+		UpdatedAt:   updatedTask.UpdatedAt,
+		Type:        messaging.EventTypeTaskAssigned,
+		Changes: map[string]interface{}{
+			"assignee_id": map[string]interface{}{
+				"old": oldAssigneeID,
+				"new": newAssigneeID,
+			},
+			"assignee_ids": map[string]interface{}{
+				"old": task.AssigneeIDs,
+				"new": updatedTask.AssigneeIDs,
+			},
+		},
 	}
 
-	return timeLogs, nil
-} // Delete удаляет задачу
-func (s *TaskService) Delete(ctx context.Context, id string, userID string) error {
-	// Получаем задачу из БД
-	task, err := s.taskRepo.GetByID(ctx, id)
-	if err != nil {
-		s.logger.Error("Failed to get task by ID for delete", err, map[string]interface{}{
-			"id": id,
+	if err := s.producer.PublishTaskUpdated(ctx, event, event.Changes); err != nil {
+		s.logger.Warn("Failed to publish task assignees update event", map[string]interface{}{
+			"task_id": updatedTask.ID,
+		}, map[string]interface{}{
+			"error": err,
 		})
-		return ErrTaskNotFound
 	}
 
-	// Проверяем права пользователя на управление задачей
-	if !s.canManageTask(ctx, task.ProjectID, userID) && task.CreatedBy != userID {
-		return ErrInsufficientRights
-	}
+	// Уведомляем только добавленных исполнителей
+	s.notifyTaskAssigned(ctx, updatedTask, userID, addedAssigneeIDs(task.AssigneeIDs, updatedTask.AssigneeIDs))
 
-	// Удаляем задачу из БД
-	if err := s.taskRepo.Delete(ctx, id); err != nil {
-		s.logger.Error("Failed to delete task", err, map[string]interface{}{
-			"id": id,
-		})
-		return err
+	// Формируем ответ
+	resp := updatedTask.ToResponse()
+	s.fillAssignees(ctx, &resp)
+
+	if updatedTask.AssigneeID != nil {
+		for i := range resp.Assignees {
+			if resp.Assignees[i].ID == *updatedTask.AssigneeID {
+				brief := resp.Assignees[i]
+				resp.Assignee = &brief
+				break
+			}
+		}
 	}
 
-	// Удаляем задачу из кэша
-	cacheKey := "task:" + id
-	if err := s.cacheRepo.Delete(ctx, cacheKey); err != nil {
-		s.logger.Warn("Failed to delete task from cache", map[string]interface{}{
-			"id": id,
-		}, map[string]interface{}{
-			"error": err,
-		})
+	creator, err := s.userRepo.GetByID(ctx, updatedTask.CreatedBy)
+	if err == nil {
+		resp.Creator = &domain.UserBrief{
+			ID:        creator.ID,
+			Email:     creator.Email,
+			FirstName: creator.FirstName,
+			LastName:  creator.LastName,
+			Avatar:    creator.Avatar,
+		}
 	}
 
-	return nil
+	return &resp, nil
 }
 
-// List возвращает список задач с фильтрацией
-func (s *TaskService) List(ctx context.Context, filter domain.TaskFilterOptions, userID string, page, pageSize int) (*domain.PagedResponse, error) {
-	// Преобразуем фильтр доменной модели в фильтр репозитория
-	repoFilter := repository.TaskFilter{
-		ProjectIDs: []string{},
-		SearchText: filter.SearchText,
-		Status:     filter.Status,
-		Priority:   filter.Priority,
-		AssigneeID: filter.AssigneeID,
-		CreatedBy:  filter.CreatedBy,
-		DueBefore:  filter.DueBefore,
-		DueAfter:   filter.DueAfter,
-		Tags:       filter.Tags,
-		Limit:      pageSize,
-		Offset:     (page - 1) * pageSize,
+// reprioritizeBatchSize определяет размер страницы при пересчете оценок приоритета
+const reprioritizeBatchSize = 500
+
+// Reprioritize пересчитывает оценки приоритета всех задач проекта.
+// При ApplyPriority задачам с заданными влиянием и срочностью выставляется приоритет по матрице
+func (s *TaskService) Reprioritize(ctx context.Context, projectID string, req domain.TaskReprioritizeRequest) (*domain.TaskReprioritizeResult, error) {
+	// Задачи архивного проекта доступны только для чтения
+	if err := s.projectSvc.ensureProjectWritable(ctx, projectID); err != nil {
+		return nil, err
 	}
 
-	// Если указан ID проекта, проверяем доступ пользователя к нему
-	if filter.ProjectID != nil {
-		if !s.projectSvc.hasAccessToProject(ctx, *filter.ProjectID, userID) {
-			return nil, ErrProjectNotFound
+	result := &domain.TaskReprioritizeResult{ProjectID: projectID}
+	now := time.Now()
+
+	// Сортируем по ID, чтобы пересчет не влиял на разбиение на страницы
+	orderBy := "id"
+	filter := repository.TaskFilter{
+		OrderBy: &orderBy,
+		Limit:   reprioritizeBatchSize,
+	}
+
+	for {
+		tasks, err := s.taskRepo.GetTasksByProject(ctx, projectID, filter)
+		if err != nil {
+			s.logger.Error("Failed to list project tasks for reprioritization", err, map[string]interface{}{
+				"project_id": projectID,
+			})
+			return nil, err
+		}
+
+		updates := make([]repository.TaskScoreUpdate, 0, len(tasks))
+		for _, task := range tasks {
+			update := repository.TaskScoreUpdate{TaskID: task.ID}
+
+			if req.ApplyPriority && task.Impact != nil && task.Urgency != nil {
+				if priority := domain.MatrixPriority(*task.Impact, *task.Urgency); priority != task.Priority {
+					task.Priority = priority
+					update.Priority = &priority
+					result.PriorityChanged++
+				}
+			}
+
+			update.Score = task.ComputePriorityScore(now)
+			if update.Score == task.PriorityScore && update.Priority == nil {
+				continue
+			}
+			updates = append(updates, update)
+		}
+
+		if len(updates) > 0 {
+			if err := s.taskRepo.UpdatePriorityScores(ctx, updates); err != nil {
+				s.logger.Error("Failed to save task priority scores", err, map[string]interface{}{
+					"project_id": projectID,
+				})
+				return nil, err
+			}
+
+			ids := make([]string, 0, len(updates))
+			for _, update := range updates {
+				ids = append(ids, update.TaskID)
+			}
+			s.invalidateTaskCache(ctx, ids)
+		}
+
+		result.TasksScored += len(tasks)
+		if len(tasks) < reprioritizeBatchSize {
+			break
+		}
+		filter.Offset += reprioritizeBatchSize
+	}
+
+	s.logger.Info("Project tasks reprioritized", map[string]interface{}{
+		"project_id":       projectID,
+		"tasks_scored":     result.TasksScored,
+		"priority_changed": result.PriorityChanged,
+	})
+
+	return result, nil
+}
+
+// GetBacklogAgeReport группирует задачи бэклога проекта по возрасту, чтобы на груминге
+// можно было начать с самых старых. Устаревшими считаются задачи с тегом stale
+func (s *TaskService) GetBacklogAgeReport(ctx context.Context, projectID string) (*domain.BacklogAgeReport, error) {
+	tasks, err := s.taskRepo.ListBacklog(ctx, projectID)
+	if err != nil {
+		s.logger.Error("Failed to get backlog tasks", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return nil, err
+	}
+
+	now := time.Now()
+	report := &domain.BacklogAgeReport{
+		ProjectID:   projectID,
+		Total:       len(tasks),
+		Bands:       make([]*domain.BacklogAgeGroup, 0, len(domain.BacklogAgeBands)),
+		GeneratedAt: now,
+	}
+	for _, band := range domain.BacklogAgeBands {
+		report.Bands = append(report.Bands, &domain.BacklogAgeGroup{
+			BacklogAgeBand: band,
+			Tasks:          []*domain.BacklogTask{},
+		})
+	}
+
+	for _, task := range tasks {
+		item := &domain.BacklogTask{
+			ID:         task.ID,
+			Title:      task.Title,
+			Status:     task.Status,
+			Priority:   task.Priority,
+			AssigneeID: task.AssigneeID,
+			CreatedAt:  task.CreatedAt,
+			AgeDays:    int(now.Sub(task.CreatedAt).Hours() / 24),
+			Stale:      containsString(task.Tags, domain.TaskTagStale),
+			Tags:       task.Tags,
+		}
+		if item.Stale {
+			report.StaleCount++
+		}
+
+		for _, group := range report.Bands {
+			if group.Contains(item.AgeDays) {
+				group.Tasks = append(group.Tasks, item)
+				group.Count++
+				break
+			}
+		}
+	}
+
+	return report, nil
+}
+
+// Move перемещает задачу при ручной сортировке после или перед другой задачей проекта,
+// либо в начало или конец списка. При слишком длинных рангах ранги проекта перебалансируются
+func (s *TaskService) Move(ctx context.Context, id string, req domain.TaskMoveRequest, userID string) (*domain.TaskResponse, error) {
+	task, err := s.taskRepo.GetByID(ctx, id)
+	if err != nil || task == nil {
+		s.logger.Error("Failed to get task by ID for move", err, map[string]interface{}{
+			"id": id,
+		})
+		return nil, ErrTaskNotFound
+	}
+
+	// Проверяем доступ пользователя к задаче
+	if !s.hasAccessToTask(ctx, task.ProjectID, userID) {
+		return nil, ErrTaskAccessDenied
+	}
+
+	// Задачи архивного проекта доступны только для чтения
+	if err := s.projectSvc.ensureProjectWritable(ctx, task.ProjectID); err != nil {
+		return nil, err
+	}
+
+	if !s.canManageTask(ctx, task.ProjectID, userID) {
+		return nil, ErrInsufficientRights
+	}
+
+	// Должен быть указан ровно один ориентир
+	targets := 0
+	for _, set := range []bool{req.AfterID != nil, req.BeforeID != nil, req.Position != nil} {
+		if set {
+			targets++
+		}
+	}
+	if targets != 1 {
+		return nil, ErrInvalidTaskMove
+	}
+
+	var rank string
+	for attempt := 0; attempt < 2; attempt++ {
+		rank, err = s.moveRank(ctx, task, req)
+		if err != nil {
+			return nil, err
+		}
+		if len(rank) <= lexorank.MaxLength || attempt > 0 {
+			break
+		}
+
+		// Ранги стали слишком плотными, перераспределяем их и считаем заново
+		if err := s.taskRepo.RebalanceRanks(ctx, task.ProjectID); err != nil {
+			s.logger.Error("Failed to rebalance task ranks", err, map[string]interface{}{
+				"project_id": task.ProjectID,
+			})
+			return nil, err
+		}
+		s.logger.Info("Task ranks rebalanced", map[string]interface{}{
+			"project_id": task.ProjectID,
+		})
+	}
+
+	if err := s.taskRepo.UpdateRank(ctx, id, rank); err != nil {
+		s.logger.Error("Failed to update task rank", err, map[string]interface{}{
+			"id": id,
+		})
+		return nil, err
+	}
+
+	event := &messaging.TaskEvent{
+		ID:          task.ID,
+		Title:       task.Title,
+		ProjectID:   task.ProjectID,
+		Status:      string(task.Status),
+		Priority:    string(task.Priority),
+		AssigneeID:  task.AssigneeID,
+		AssigneeIDs: task.AssigneeIDs,
+		UpdatedAt:   time.Now(),
+		Type:        messaging.EventTypeTaskUpdated,
+		Changes: map[string]interface{}{
+			"rank": map[string]interface{}{"old": task.Rank, "new": rank},
+		},
+	}
+
+	if err := s.producer.PublishTaskUpdated(ctx, event, event.Changes); err != nil {
+		s.logger.Warn("Failed to publish task move event", map[string]interface{}{
+			"task_id": task.ID,
+		}, map[string]interface{}{
+			"error": err,
+		})
+	}
+
+	return s.GetByID(ctx, id, userID)
+}
+
+// moveRank вычисляет новый ранг задачи по ориентиру из запроса
+func (s *TaskService) moveRank(ctx context.Context, task *domain.Task, req domain.TaskMoveRequest) (string, error) {
+	switch {
+	case req.Position != nil && *req.Position == "top":
+		first, err := s.taskRepo.NextRank(ctx, task.ProjectID, "", task.ID)
+		if err != nil {
+			return "", err
+		}
+		return lexorank.Between("", first)
+	case req.Position != nil:
+		last, err := s.taskRepo.PrevRank(ctx, task.ProjectID, "", task.ID)
+		if err != nil {
+			return "", err
+		}
+		return lexorank.Between(last, "")
+	}
+
+	anchorID := req.AfterID
+	if anchorID == nil {
+		anchorID = req.BeforeID
+	}
+	if *anchorID == task.ID {
+		return "", ErrInvalidTaskMove
+	}
+
+	anchor, err := s.taskRepo.GetByID(ctx, *anchorID)
+	if err != nil {
+		return "", err
+	}
+	if anchor == nil || anchor.ProjectID != task.ProjectID {
+		return "", ErrInvalidTaskMove
+	}
+
+	if req.AfterID != nil {
+		next, err := s.taskRepo.NextRank(ctx, task.ProjectID, anchor.Rank, task.ID)
+		if err != nil {
+			return "", err
+		}
+		return lexorank.Between(anchor.Rank, next)
+	}
+
+	prev, err := s.taskRepo.PrevRank(ctx, task.ProjectID, anchor.Rank, task.ID)
+	if err != nil {
+		return "", err
+	}
+	return lexorank.Between(prev, anchor.Rank)
+}
+
+// MoveToProject переносит задачу в другой проект. Комментарии, списания времени и история
+// остаются у задачи, статусы и теги переназначаются по сопоставлению из запроса
+func (s *TaskService) MoveToProject(ctx context.Context, id string, req domain.TaskProjectMoveRequest, userID string) (*domain.TaskResponse, error) {
+	task, err := s.taskRepo.GetByID(ctx, id)
+	if err != nil || task == nil {
+		s.logger.Error("Failed to get task by ID for project move", err, map[string]interface{}{
+			"id": id,
+		})
+		return nil, ErrTaskNotFound
+	}
+
+	// Проверяем права в исходном проекте
+	if !s.hasAccessToTask(ctx, task.ProjectID, userID) {
+		return nil, ErrTaskAccessDenied
+	}
+
+	if err := s.projectSvc.ensureProjectWritable(ctx, task.ProjectID); err != nil {
+		return nil, err
+	}
+
+	if !s.canManageTask(ctx, task.ProjectID, userID) {
+		return nil, ErrInsufficientRights
+	}
+
+	if req.ProjectID == task.ProjectID {
+		return nil, ErrTaskSameProject
+	}
+
+	// Проверяем права в целевом проекте
+	if !s.projectSvc.hasAccessToProject(ctx, req.ProjectID, userID) {
+		return nil, ErrProjectNotFound
+	}
+
+	if err := s.projectSvc.ensureProjectWritable(ctx, req.ProjectID); err != nil {
+		return nil, err
+	}
+
+	if !s.canManageTask(ctx, req.ProjectID, userID) {
+		return nil, ErrInsufficientRights
+	}
+
+	status := task.Status
+	if mapped, ok := req.StatusMapping[task.Status]; ok {
+		status = mapped
+	}
+
+	tags := make([]string, 0, len(task.Tags))
+	seen := make(map[string]bool, len(task.Tags))
+	tagsChanged := false
+	for _, tag := range task.Tags {
+		if mapped, ok := req.TagMapping[tag]; ok && mapped != tag {
+			tag = mapped
+			tagsChanged = true
+		}
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		tags = append(tags, tag)
+	}
+
+	// Исполнители, не состоящие в целевом проекте, снимаются с задачи
+	assigneeIDs := make([]string, 0, len(task.AssigneeIDs))
+	var removedIDs []string
+	for _, assigneeID := range task.AssigneeIDs {
+		member, err := s.projectRepo.GetMember(ctx, req.ProjectID, assigneeID)
+		if err != nil || member == nil {
+			removedIDs = append(removedIDs, assigneeID)
+			continue
+		}
+		assigneeIDs = append(assigneeIDs, assigneeID)
+	}
+
+	var primaryID *string
+	if task.AssigneeID != nil {
+		for _, assigneeID := range assigneeIDs {
+			if assigneeID == *task.AssigneeID {
+				primaryID = task.AssigneeID
+				break
+			}
+		}
+	}
+	if primaryID == nil && len(assigneeIDs) > 0 {
+		primaryID = &assigneeIDs[0]
+	}
+
+	// Задача должна удовлетворять правилам целевого проекта для своего статуса
+	moved := *task
+	moved.ProjectID = req.ProjectID
+	moved.Tags = tags
+	moved.AssigneeID = primaryID
+	if err := s.checkTransitionRules(ctx, &moved, status); err != nil {
+		return nil, err
+	}
+
+	// Переносимая задача встает в конец целевого проекта
+	var rank string
+	for attempt := 0; attempt < 2; attempt++ {
+		last, err := s.taskRepo.PrevRank(ctx, req.ProjectID, "", task.ID)
+		if err != nil {
+			return nil, err
+		}
+		rank, err = lexorank.Between(last, "")
+		if err != nil {
+			return nil, err
+		}
+		if len(rank) <= lexorank.MaxLength || attempt > 0 {
+			break
+		}
+
+		if err := s.taskRepo.RebalanceRanks(ctx, req.ProjectID); err != nil {
+			s.logger.Error("Failed to rebalance task ranks", err, map[string]interface{}{
+				"project_id": req.ProjectID,
+			})
+			return nil, err
+		}
+	}
+
+	move := &repository.TaskProjectMove{
+		TaskID:        task.ID,
+		FromProjectID: task.ProjectID,
+		ToProjectID:   req.ProjectID,
+		Status:        status,
+		Rank:          rank,
+		Tags:          tags,
+		AssigneeIDs:   assigneeIDs,
+		PrimaryID:     primaryID,
+		UserID:        userID,
+	}
+	if err := s.taskRepo.MoveToProject(ctx, move); err != nil {
+		s.logger.Error("Failed to move task to project", err, map[string]interface{}{
+			"id":         id,
+			"project_id": req.ProjectID,
+		})
+		return nil, err
+	}
+
+	changes := map[string]interface{}{
+		"project_id": map[string]interface{}{"old": task.ProjectID, "new": req.ProjectID},
+	}
+	if status != task.Status {
+		changes["status"] = map[string]interface{}{"old": task.Status, "new": status}
+	}
+	if len(removedIDs) > 0 {
+		changes["assignee_ids"] = map[string]interface{}{"old": task.AssigneeIDs, "new": assigneeIDs}
+	}
+	if tagsChanged {
+		changes["tags"] = map[string]interface{}{"old": task.Tags, "new": tags}
+	}
+
+	event := &messaging.TaskEvent{
+		ID:          task.ID,
+		Title:       task.Title,
+		ProjectID:   req.ProjectID,
+		Status:      string(status),
+		Priority:    string(task.Priority),
+		AssigneeID:  primaryID,
+		AssigneeIDs: assigneeIDs,
+		UpdatedAt:   time.Now(),
+		Type:        messaging.EventTypeTaskMoved,
+		Changes:     changes,
+	}
+
+	if err := s.producer.PublishTaskMoved(ctx, event, changes); err != nil {
+		s.logger.Warn("Failed to publish task moved event", map[string]interface{}{
+			"task_id": task.ID,
+		}, map[string]interface{}{
+			"error": err,
+		})
+	}
+
+	return s.GetByID(ctx, id, userID)
+}
+
+// LogTime добавляет запись о затраченном времени
+func (s *TaskService) LogTime(ctx context.Context, id string, req domain.LogTimeRequest, userID string) error {
+	// Получаем задачу из БД
+	task, err := s.taskRepo.GetByID(ctx, id)
+	if err != nil {
+		s.logger.Error("Failed to get task by ID for logging time", err, map[string]interface{}{
+			"id": id,
+		})
+		return ErrTaskNotFound
+	}
+
+	// Проверяем доступ пользователя к задаче
+	if !s.hasAccessToTask(ctx, task.ProjectID, userID) {
+		return ErrTaskAccessDenied
+	}
+
+	// Задачи архивного проекта доступны только для чтения
+	if err := s.projectSvc.ensureProjectWritable(ctx, task.ProjectID); err != nil {
+		return err
+	}
+
+	// Создаем запись о затраченном времени
+	logDate := time.Now()
+	if req.Date != nil {
+		logDate = *req.Date
+	}
+
+	timeLog := &repository.TimeLog{
+		ID:          uuid.New().String(),
+		TaskID:      id,
+		UserID:      userID,
+		Hours:       req.Hours,
+		Description: req.Description,
+		LoggedAt:    time.Now(),
+		LogDate:     logDate,
+	}
+
+	// Запись о времени, увеличение затраченного времени задачи, запись истории и событие
+	// об изменении сохраняются в одной транзакции. Затраченное время читается после увеличения,
+	// чтобы история учитывала одновременные записи о времени
+	return s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.taskRepo.LogTime(ctx, timeLog); err != nil {
+			s.logger.Error("Failed to log time", err, map[string]interface{}{
+				"task_id": id,
+			})
+			return err
+		}
+
+		updated, err := s.taskRepo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		var newSpentHours float64
+		if updated.SpentHours != nil {
+			newSpentHours = *updated.SpentHours
+		}
+		oldSpentHours := newSpentHours - req.Hours
+
+		if err := s.taskRepo.LogTaskHistory(ctx, &domain.TaskHistory{
+			ID:        uuid.New().String(),
+			TaskID:    id,
+			UserID:    userID,
+			Field:     "spent_hours",
+			OldValue:  strconv.FormatFloat(oldSpentHours, 'f', -1, 64),
+			NewValue:  strconv.FormatFloat(newSpentHours, 'f', -1, 64),
+			ChangedAt: timeLog.LoggedAt,
+		}); err != nil {
+			return err
+		}
+
+		return s.enqueueTaskChanges(ctx, task, timeLog.LoggedAt, map[string]interface{}{
+			"spent_hours": map[string]interface{}{"old": oldSpentHours, "new": newSpentHours},
+		})
+	})
+}
+
+// GetTimeReconciliation сравнивает затраченное время задачи с суммой ее записей о времени
+func (s *TaskService) GetTimeReconciliation(ctx context.Context, id string, userID string) (*domain.TimeReconciliation, error) {
+	task, err := s.taskRepo.GetByID(ctx, id)
+	if err != nil || task == nil {
+		return nil, ErrTaskNotFound
+	}
+
+	if !s.hasAccessToTask(ctx, task.ProjectID, userID) {
+		return nil, ErrTaskAccessDenied
+	}
+
+	reconciliation, err := s.taskRepo.GetTimeReconciliation(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if reconciliation == nil {
+		return nil, ErrTaskNotFound
+	}
+
+	return reconciliation, nil
+}
+
+// RecalculateSpentHours перезаписывает затраченное время задачи суммой ее записей о времени
+// и возвращает сверку до пересчета. Пересчет доступен тем, кто может управлять задачей
+func (s *TaskService) RecalculateSpentHours(ctx context.Context, id string, userID string) (*domain.TimeReconciliation, error) {
+	task, err := s.taskRepo.GetByID(ctx, id)
+	if err != nil || task == nil {
+		return nil, ErrTaskNotFound
+	}
+
+	if !s.canManageTask(ctx, task.ProjectID, userID) {
+		return nil, ErrInsufficientRights
+	}
+
+	reconciliation, err := s.taskRepo.RecalculateSpentHours(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if reconciliation == nil {
+		return nil, ErrTaskNotFound
+	}
+
+	if reconciliation.Recalculated {
+		s.logger.Info("Task spent hours recalculated", map[string]interface{}{
+			"task_id":      id,
+			"spent_hours":  reconciliation.SpentHours,
+			"logged_hours": reconciliation.LoggedHours,
+			"user_id":      userID,
+		})
+		s.invalidateTaskCache(ctx, []string{id})
+	}
+
+	return reconciliation, nil
+}
+
+// RecalculateProjectSpentHours перезаписывает затраченное время задач проекта суммами
+// их записей о времени. Пересчет доступен тем, кто может управлять проектом
+func (s *TaskService) RecalculateProjectSpentHours(ctx context.Context, projectID string, userID string) (*domain.ProjectTimeRecalculation, error) {
+	project, err := s.projectRepo.GetByID(ctx, projectID)
+	if err != nil || project == nil {
+		return nil, ErrProjectNotFound
+	}
+
+	reconciliations, err := s.taskRepo.RecalculateProjectSpentHours(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(reconciliations) > 0 {
+		ids := make([]string, 0, len(reconciliations))
+		for _, reconciliation := range reconciliations {
+			ids = append(ids, reconciliation.TaskID)
+		}
+		s.logger.Info("Project spent hours recalculated", map[string]interface{}{
+			"project_id": projectID,
+			"tasks":      len(ids),
+			"user_id":    userID,
+		})
+		s.invalidateTaskCache(ctx, ids)
+	}
+
+	return &domain.ProjectTimeRecalculation{
+		ProjectID: projectID,
+		Tasks:     reconciliations,
+	}, nil
+}
+
+// ListProjectTags возвращает теги задач проекта со статистикой использования
+func (s *TaskService) ListProjectTags(ctx context.Context, projectID string) ([]*domain.TagUsage, error) {
+	return s.taskRepo.GetTagUsage(ctx, projectID)
+}
+
+// RenameProjectTag переименовывает тег во всех задачах проекта.
+// Если новое имя уже используется, теги нужно объединить через MergeProjectTags
+func (s *TaskService) RenameProjectTag(ctx context.Context, projectID, tag string, req domain.TagRenameRequest, userID string) (*domain.TagOperationResult, error) {
+	usage, err := s.manageProjectTags(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	if findTagUsage(usage, tag) == nil {
+		return nil, ErrTagNotFound
+	}
+	if req.Name != tag && findTagUsage(usage, req.Name) != nil {
+		return nil, ErrTagExists
+	}
+
+	taskIDs, err := s.taskRepo.RenameTag(ctx, projectID, tag, req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.completeTagOperation(ctx, projectID, []string{tag}, req.Name, taskIDs, userID), nil
+}
+
+// MergeProjectTags заменяет исходные теги целевым во всех задачах проекта
+func (s *TaskService) MergeProjectTags(ctx context.Context, projectID string, req domain.TagMergeRequest, userID string) (*domain.TagOperationResult, error) {
+	usage, err := s.manageProjectTags(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	sources := make([]string, 0, len(req.Sources))
+	for _, source := range req.Sources {
+		if source == req.Target || containsString(sources, source) {
+			continue
+		}
+		if findTagUsage(usage, source) == nil {
+			return nil, ErrTagNotFound
+		}
+		sources = append(sources, source)
+	}
+
+	taskIDs, err := s.taskRepo.MergeTags(ctx, projectID, sources, req.Target)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.completeTagOperation(ctx, projectID, sources, req.Target, taskIDs, userID), nil
+}
+
+// DeleteProjectTag удаляет тег, оставшийся только у завершенных и отмененных задач проекта
+func (s *TaskService) DeleteProjectTag(ctx context.Context, projectID, tag string, userID string) (*domain.TagOperationResult, error) {
+	usage, err := s.manageProjectTags(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	tagUsage := findTagUsage(usage, tag)
+	if tagUsage == nil {
+		return nil, ErrTagNotFound
+	}
+	if !tagUsage.Unused {
+		return nil, ErrTagInUse
+	}
+
+	taskIDs, err := s.taskRepo.DeleteUnusedTag(ctx, projectID, tag)
+	if err != nil {
+		return nil, err
+	}
+	// Тег успели добавить открытой задаче после проверки
+	if len(taskIDs) == 0 {
+		return nil, ErrTagInUse
+	}
+
+	return s.completeTagOperation(ctx, projectID, []string{tag}, "", taskIDs, userID), nil
+}
+
+// manageProjectTags проверяет, что теги проекта можно изменять, и возвращает текущие теги
+func (s *TaskService) manageProjectTags(ctx context.Context, projectID string) ([]*domain.TagUsage, error) {
+	// Задачи архивного проекта доступны только для чтения
+	if err := s.projectSvc.ensureProjectWritable(ctx, projectID); err != nil {
+		return nil, err
+	}
+
+	return s.taskRepo.GetTagUsage(ctx, projectID)
+}
+
+// completeTagOperation сбрасывает кэш измененных задач и формирует результат операции над тегами
+func (s *TaskService) completeTagOperation(ctx context.Context, projectID string, tags []string, target string, taskIDs []string, userID string) *domain.TagOperationResult {
+	if len(taskIDs) > 0 {
+		s.invalidateTaskCache(ctx, taskIDs)
+	}
+
+	s.logger.Info("Project tags updated", map[string]interface{}{
+		"project_id": projectID,
+		"tags":       tags,
+		"target":     target,
+		"tasks":      len(taskIDs),
+		"user_id":    userID,
+	})
+
+	return &domain.TagOperationResult{
+		ProjectID:     projectID,
+		Tags:          tags,
+		Target:        target,
+		TasksAffected: len(taskIDs),
+	}
+}
+
+// findTagUsage ищет тег в статистике использования
+func findTagUsage(usage []*domain.TagUsage, tag string) *domain.TagUsage {
+	for _, u := range usage {
+		if u.Tag == tag {
+			return u
+		}
+	}
+	return nil
+}
+
+// invalidateTaskCache удаляет задачи из кэша
+func (s *TaskService) invalidateTaskCache(ctx context.Context, ids []string) {
+	if err := s.cacheRepo.InvalidateTasks(ctx, ids); err != nil {
+		s.logger.Warn("Failed to delete tasks from cache", map[string]interface{}{
+			"count": len(ids),
+		}, map[string]interface{}{
+			"error": err,
+		})
+	}
+}
+
+// GetEffortSplit возвращает распределение оценки задачи между исполнителями и списанное ими время
+func (s *TaskService) GetEffortSplit(ctx context.Context, id string, userID string) (*domain.TaskEffortSplit, error) {
+	// Получаем задачу из БД
+	task, err := s.taskRepo.GetByID(ctx, id)
+	if err != nil || task == nil {
+		s.logger.Error("Failed to get task by ID for effort split", err, map[string]interface{}{
+			"id": id,
+		})
+		return nil, ErrTaskNotFound
+	}
+
+	// Проверяем доступ пользователя к задаче
+	if !s.hasAccessToTask(ctx, task.ProjectID, userID) {
+		return nil, ErrTaskAccessDenied
+	}
+
+	return s.buildEffortSplit(ctx, task)
+}
+
+// UpdateEffortSplit распределяет оценку задачи между исполнителями.
+// Оценка задачи становится равной сумме долей, исполнители без доли из запроса теряют ее
+func (s *TaskService) UpdateEffortSplit(ctx context.Context, id string, req domain.TaskEffortSplitRequest, userID string) (*domain.TaskEffortSplit, error) {
+	task, err := s.getTaskForEffortSplit(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Доли можно назначать только исполнителям задачи, по одной на каждого
+	shares := make(map[string]float64, len(req.Shares))
+	var total float64
+	for _, share := range req.Shares {
+		if _, ok := shares[share.UserID]; ok || !containsString(task.AssigneeIDs, share.UserID) {
+			return nil, ErrInvalidEffortSplit
+		}
+		shares[share.UserID] = share.EstimatedHours
+		total += share.EstimatedHours
+	}
+
+	if err := s.taskRepo.SetEffortSplit(ctx, id, shares, userID); err != nil {
+		s.logger.Error("Failed to update task effort split", err, map[string]interface{}{
+			"id": id,
+		})
+		return nil, err
+	}
+
+	changes := map[string]interface{}{
+		"effort_split": shares,
+		"estimated_hours": map[string]interface{}{
+			"old": task.EstimatedHours,
+			"new": total,
+		},
+	}
+	task.EstimatedHours = &total
+
+	return s.afterEffortSplitChange(ctx, task, changes)
+}
+
+// ResetEffortSplit сбрасывает распределение оценки задачи, не меняя саму оценку
+func (s *TaskService) ResetEffortSplit(ctx context.Context, id string, userID string) (*domain.TaskEffortSplit, error) {
+	task, err := s.getTaskForEffortSplit(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.taskRepo.SetEffortSplit(ctx, id, nil, userID); err != nil {
+		s.logger.Error("Failed to reset task effort split", err, map[string]interface{}{
+			"id": id,
+		})
+		return nil, err
+	}
+
+	return s.afterEffortSplitChange(ctx, task, map[string]interface{}{
+		"effort_split": nil,
+	})
+}
+
+// getTaskForEffortSplit возвращает задачу, если пользователь может менять распределение ее оценки
+func (s *TaskService) getTaskForEffortSplit(ctx context.Context, id string, userID string) (*domain.Task, error) {
+	task, err := s.taskRepo.GetByID(ctx, id)
+	if err != nil || task == nil {
+		s.logger.Error("Failed to get task by ID for effort split", err, map[string]interface{}{
+			"id": id,
+		})
+		return nil, ErrTaskNotFound
+	}
+
+	if !s.hasAccessToTask(ctx, task.ProjectID, userID) {
+		return nil, ErrTaskAccessDenied
+	}
+
+	// Задачи архивного проекта доступны только для чтения
+	if err := s.projectSvc.ensureProjectWritable(ctx, task.ProjectID); err != nil {
+		return nil, err
+	}
+
+	if !s.canManageTask(ctx, task.ProjectID, userID) && task.CreatedBy != userID {
+		return nil, ErrInsufficientRights
+	}
+
+	return task, nil
+}
+
+// afterEffortSplitChange публикует событие об изменении распределения и возвращает новое распределение
+func (s *TaskService) afterEffortSplitChange(ctx context.Context, task *domain.Task, changes map[string]interface{}) (*domain.TaskEffortSplit, error) {
+	s.publishTaskChanges(ctx, task, changes)
+
+	return s.buildEffortSplit(ctx, task)
+}
+
+// publishTaskChanges публикует событие об обновлении задачи; по нему же сбрасывается кэш задачи
+func (s *TaskService) publishTaskChanges(ctx context.Context, task *domain.Task, changes map[string]interface{}) {
+	event := &messaging.TaskEvent{
+		ID:          task.ID,
+		Title:       task.Title,
+		ProjectID:   task.ProjectID,
+		Status:      string(task.Status),
+		Priority:    string(task.Priority),
+		AssigneeID:  task.AssigneeID,
+		AssigneeIDs: task.AssigneeIDs,
+		UpdatedAt:   time.Now(),
+		Type:        messaging.EventTypeTaskUpdated,
+		Changes:     changes,
+	}
+
+	if err := s.producer.PublishTaskUpdated(ctx, event, event.Changes); err != nil {
+		s.logger.Warn("Failed to publish task update event", map[string]interface{}{
+			"task_id": task.ID,
+		}, map[string]interface{}{
+			"error": err,
+		})
+	}
+}
+
+// enqueueTaskChanges записывает событие об изменении задачи в таблицу исходящих событий
+// в транзакции из контекста. В отличие от publishTaskChanges ошибка возвращается, чтобы
+// изменение не было зафиксировано без события
+func (s *TaskService) enqueueTaskChanges(ctx context.Context, task *domain.Task, updatedAt time.Time, changes map[string]interface{}) error {
+	event := &messaging.TaskEvent{
+		ID:          task.ID,
+		Title:       task.Title,
+		ProjectID:   task.ProjectID,
+		Status:      string(task.Status),
+		Priority:    string(task.Priority),
+		AssigneeID:  task.AssigneeID,
+		AssigneeIDs: task.AssigneeIDs,
+		UpdatedAt:   updatedAt,
+		Type:        messaging.EventTypeTaskUpdated,
+		Changes:     changes,
+	}
+
+	if err := s.producer.PublishTaskUpdated(messaging.WithOutbox(ctx), event, event.Changes); err != nil {
+		s.logger.Error("Failed to enqueue task update event", err, map[string]interface{}{
+			"task_id": task.ID,
+		})
+		return err
+	}
+	return nil
+}
+
+// buildEffortSplit собирает распределение оценки задачи с учетом списанного времени
+func (s *TaskService) buildEffortSplit(ctx context.Context, task *domain.Task) (*domain.TaskEffortSplit, error) {
+	shares, err := s.taskRepo.GetEffortShares(ctx, task.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	timeLogs, err := s.taskRepo.GetTimeLogs(ctx, task.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	split := &domain.TaskEffortSplit{
+		TaskID:         task.ID,
+		EstimatedHours: task.EstimatedHours,
+		SpentHours:     task.SpentHours,
+		Shares:         make([]domain.TaskEffortShare, 0, len(shares)),
+	}
+
+	for _, share := range shares {
+		if share.EstimatedHours != nil {
+			remaining := *share.EstimatedHours - share.SpentHours
+			if remaining < 0 {
+				remaining = 0
+			}
+			share.RemainingHours = &remaining
+		}
+
+		user, err := s.userRepo.GetByID(ctx, share.UserID)
+		if err == nil && user != nil {
+			share.User = &domain.UserBrief{
+				ID:        user.ID,
+				Email:     user.Email,
+				FirstName: user.FirstName,
+				LastName:  user.LastName,
+				Avatar:    user.Avatar,
+			}
+		}
+
+		split.Shares = append(split.Shares, *share)
+	}
+
+	// Время, списанное не исполнителями, не относится ни к одной доле
+	for _, timeLog := range timeLogs {
+		if !containsString(task.AssigneeIDs, timeLog.UserID) {
+			split.UnallocatedSpentHours += timeLog.Hours
+		}
+	}
+
+	return split, nil
+}
+
+// GetTimeLogs возвращает записи о затраченном времени
+func (s *TaskService) GetTimeLogs(ctx context.Context, id string, userID string) ([]*repository.TimeLog, error) {
+	// Получаем задачу из БД
+	task, err := s.taskRepo.GetByID(ctx, id)
+	if err != nil {
+		s.logger.Error("Failed to get task by ID for getting time logs", err, map[string]interface{}{
+			"id": id,
+		})
+		return nil, ErrTaskNotFound
+	}
+
+	// Проверяем доступ пользователя к задаче
+	if !s.hasAccessToTask(ctx, task.ProjectID, userID) {
+		return nil, ErrTaskAccessDenied
+	}
+
+	// Получаем записи о затраченном времени
+	timeLogs, err := s.taskRepo.GetTimeLogs(ctx, id)
+	if err != nil {
+		s.logger.Error("Failed to get time logs", err, map[string]interface{}{
+			"task_id": id,
+		})
+		return nil, err
+	}
+
+	return timeLogs, nil
+} // Delete удаляет задачу
+func (s *TaskService) Delete(ctx context.Context, id string, userID string) error {
+	// Получаем задачу из БД
+	task, err := s.taskRepo.GetByID(ctx, id)
+	if err != nil {
+		s.logger.Error("Failed to get task by ID for delete", err, map[string]interface{}{
+			"id": id,
+		})
+		return ErrTaskNotFound
+	}
+
+	// Проверяем права пользователя на управление задачей
+	if !s.canManageTask(ctx, task.ProjectID, userID) && task.CreatedBy != userID {
+		return ErrInsufficientRights
+	}
+
+	// Задачи архивного проекта доступны только для чтения
+	if err := s.projectSvc.ensureProjectWritable(ctx, task.ProjectID); err != nil {
+		return err
+	}
+
+	// Удаляем задачу из БД
+	if err := s.taskRepo.Delete(ctx, id); err != nil {
+		s.logger.Error("Failed to delete task", err, map[string]interface{}{
+			"id": id,
+		})
+		return err
+	}
+
+	// Отправляем событие об удалении задачи; по нему сбрасывается кэш задачи и ее проекта
+	event := &messaging.TaskEvent{
+		ID:        task.ID,
+		Title:     task.Title,
+		ProjectID: task.ProjectID,
+		Status:    string(task.Status),
+		Priority:  string(task.Priority),
+		UpdatedAt: time.Now(),
+		Type:      messaging.EventTypeTaskDeleted,
+	}
+
+	if err := s.producer.PublishTaskDeleted(ctx, event); err != nil {
+		s.logger.Warn("Failed to publish task delete event", map[string]interface{}{
+			"task_id": task.ID,
+		}, map[string]interface{}{
+			"error": err,
+		})
+	}
+
+	return nil
+}
+
+// CreateSubtask создает подзадачу в проекте родительской задачи
+func (s *TaskService) CreateSubtask(ctx context.Context, parentID string, req domain.TaskCreateRequest, userID string) (*domain.TaskResponse, error) {
+	parent, err := s.taskRepo.GetByID(ctx, parentID)
+	if err != nil {
+		return nil, err
+	}
+	if parent == nil {
+		return nil, ErrTaskNotFound
+	}
+
+	if !s.hasAccessToTask(ctx, parent.ProjectID, userID) {
+		return nil, ErrTaskAccessDenied
+	}
+
+	req.ProjectID = parent.ProjectID
+	req.ParentID = &parent.ID
+
+	return s.Create(ctx, req, userID)
+}
+
+// ListSubtasks возвращает подзадачи задачи в порядке ручной сортировки вместе со сводкой
+func (s *TaskService) ListSubtasks(ctx context.Context, parentID string, userID string) (*domain.SubtaskList, error) {
+	parent, err := s.taskRepo.GetByID(ctx, parentID)
+	if err != nil {
+		return nil, err
+	}
+	if parent == nil {
+		return nil, ErrTaskNotFound
+	}
+
+	if !s.hasAccessToTask(ctx, parent.ProjectID, userID) {
+		return nil, ErrTaskAccessDenied
+	}
+
+	progress, err := s.taskRepo.GetSubtaskProgress(ctx, parent.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	orderBy, orderDir := "rank", "asc"
+	tasks, err := s.taskRepo.List(ctx, repository.TaskFilter{
+		ProjectIDs: []string{parent.ProjectID},
+		ParentID:   &parent.ID,
+		OrderBy:    &orderBy,
+		OrderDir:   &orderDir,
+		Limit:      maxSubtasks,
+	})
+	if err != nil {
+		s.logger.Error("Failed to list subtasks", err, map[string]interface{}{
+			"parent_id": parentID,
+		})
+		return nil, err
+	}
+
+	responses := s.buildTaskResponses(ctx, tasks)
+	s.viewSvc.MarkChanged(ctx, userID, responses)
+
+	return &domain.SubtaskList{
+		ParentID: parent.ID,
+		Progress: *progress,
+		Tasks:    responses,
+	}, nil
+}
+
+// List возвращает список задач с фильтрацией
+func (s *TaskService) List(ctx context.Context, filter domain.TaskFilterOptions, userID string, page domain.PageRequest) (*domain.PagedResponse, error) {
+	repoFilter, err := s.listFilter(ctx, filter, userID)
+	if err != nil {
+		return nil, err
+	}
+	repoFilter.Limit = page.Limit()
+	repoFilter.Offset = page.Offset()
+
+	// Получаем список задач
+	tasks, err := s.taskRepo.List(ctx, repoFilter)
+	if err != nil {
+		s.logger.Error("Failed to list tasks", err)
+		return nil, err
+	}
+	tasks, hasMore := domain.PageItems(tasks, page)
+
+	// Получаем общее количество задач, если клиент не отказался от подсчета
+	total := 0
+	if !page.SkipCount {
+		total, err = s.taskRepo.Count(ctx, repoFilter)
+		if err != nil {
+			s.logger.Error("Failed to count tasks", err)
+			return nil, err
+		}
+	}
+
+	// Отмечаем задачи, изменившиеся после последнего просмотра пользователем
+	responses := s.buildTaskResponses(ctx, tasks)
+	s.viewSvc.MarkChanged(ctx, userID, responses)
+
+	// Формируем ответ с пагинацией
+	return domain.NewPagedResponse(responses, page, total, hasMore), nil
+}
+
+// Export передает в fn все задачи, подходящие под фильтр, без постраничного ограничения.
+// Задачи загружаются из БД порциями по taskExportBatchSize, поэтому в памяти
+// одновременно находится только одна порция. Ошибка fn прекращает выгрузку
+func (s *TaskService) Export(ctx context.Context, filter domain.TaskFilterOptions, userID string, fn func(domain.TaskResponse) error) error {
+	repoFilter, err := s.listFilter(ctx, filter, userID)
+	if err != nil {
+		return err
+	}
+	repoFilter.Limit = taskExportBatchSize
+
+	for {
+		tasks, err := s.taskRepo.List(ctx, repoFilter)
+		if err != nil {
+			s.logger.Error("Failed to list tasks for export", err, map[string]interface{}{
+				"user_id": userID,
+				"offset":  repoFilter.Offset,
+			})
+			return err
+		}
+
+		for _, resp := range s.buildTaskResponses(ctx, tasks) {
+			if err := fn(resp); err != nil {
+				return err
+			}
+		}
+
+		if len(tasks) < taskExportBatchSize {
+			return nil
+		}
+		repoFilter.Offset += len(tasks)
+	}
+}
+
+// listFilter преобразует фильтр доменной модели в фильтр репозитория,
+// ограничивая выборку проектами, доступными пользователю
+func (s *TaskService) listFilter(ctx context.Context, filter domain.TaskFilterOptions, userID string) (repository.TaskFilter, error) {
+	repoFilter := repository.TaskFilter{
+		ProjectIDs:      []string{},
+		SearchText:      filter.SearchText,
+		Statuses:        filter.Statuses,
+		Priorities:      filter.Priorities,
+		AssigneeID:      filter.AssigneeID,
+		CreatedBy:       filter.CreatedBy,
+		AssigneeIDs:     filter.AssigneeIDs,
+		DueBefore:       filter.DueBefore,
+		DueAfter:        filter.DueAfter,
+		CreatedBefore:   filter.CreatedBefore,
+		CreatedAfter:    filter.CreatedAfter,
+		UpdatedBefore:   filter.UpdatedBefore,
+		UpdatedAfter:    filter.UpdatedAfter,
+		CompletedBefore: filter.CompletedBefore,
+		CompletedAfter:  filter.CompletedAfter,
+		Tags:            filter.Tags,
+		EpicID:          filter.EpicID,
+		SprintID:        filter.SprintID,
+		ParentID:        filter.ParentID,
+	}
+
+	// Если указан ID проекта, проверяем доступ пользователя к нему
+	if filter.ProjectID != nil {
+		if !s.projectSvc.hasAccessToProject(ctx, *filter.ProjectID, userID) {
+			return repoFilter, ErrProjectNotFound
 		}
 		repoFilter.ProjectIDs = append(repoFilter.ProjectIDs, *filter.ProjectID)
 	} else {
@@ -894,7 +2678,7 @@ func (s *TaskService) List(ctx context.Context, filter domain.TaskFilterOptions,
 			s.logger.Error("Failed to list user projects", err, map[string]interface{}{
 				"user_id": userID,
 			})
-			return nil, err
+			return repoFilter, err
 		}
 
 		for _, project := range projects {
@@ -903,7 +2687,9 @@ func (s *TaskService) List(ctx context.Context, filter domain.TaskFilterOptions,
 	}
 
 	// Настройка сортировки
-	if filter.SortBy != nil {
+	if len(filter.Sort) > 0 {
+		repoFilter.Sort = filter.Sort
+	} else if filter.SortBy != nil {
 		repoFilter.OrderBy = filter.SortBy
 		if filter.SortOrder != nil {
 			repoFilter.OrderDir = filter.SortOrder
@@ -912,74 +2698,56 @@ func (s *TaskService) List(ctx context.Context, filter domain.TaskFilterOptions,
 			repoFilter.OrderDir = &dir
 		}
 	} else {
-		// По умолчанию сортируем по дате обновления
-		orderBy := "updated_at"
+		// По умолчанию сортируем по оценке приоритета
+		orderBy := "priority_score"
 		orderDir := "desc"
 		repoFilter.OrderBy = &orderBy
 		repoFilter.OrderDir = &orderDir
 	}
 
-	// Получаем список задач
-	tasks, err := s.taskRepo.List(ctx, repoFilter)
-	if err != nil {
-		s.logger.Error("Failed to list tasks", err)
-		return nil, err
-	}
+	return repoFilter, nil
+}
 
-	// Получаем общее количество задач
-	total, err := s.taskRepo.Count(ctx, repoFilter)
-	if err != nil {
-		s.logger.Error("Failed to count tasks", err)
-		return nil, err
+// buildTaskResponses формирует ответы для списка задач. Теги и исполнители загружаются
+// репозиторием вместе со списком, данные всех упомянутых пользователей - один раз для всего списка
+func (s *TaskService) buildTaskResponses(ctx context.Context, tasks []*domain.Task) []domain.TaskResponse {
+	userIDs := make([]string, 0, len(tasks)*2)
+	for _, task := range tasks {
+		userIDs = append(userIDs, task.CreatedBy)
+		if task.AssigneeID != nil {
+			userIDs = append(userIDs, *task.AssigneeID)
+		}
+		userIDs = append(userIDs, task.AssigneeIDs...)
 	}
+	briefs := s.getUserBriefs(ctx, userIDs)
 
-	// Формируем ответы для задач
 	taskResponses := make([]domain.TaskResponse, len(tasks))
 	for i, task := range tasks {
-		// Получаем теги задачи
-		tags, err := s.taskRepo.GetTags(ctx, task.ID)
-		if err == nil {
-			task.Tags = tags
-		}
-
 		resp := task.ToResponse()
+		if len(resp.AssigneeIDs) > 0 {
+			resp.Assignees = make([]domain.UserBrief, 0, len(resp.AssigneeIDs))
+			for _, assigneeID := range resp.AssigneeIDs {
+				if brief, ok := briefs[assigneeID]; ok {
+					resp.Assignees = append(resp.Assignees, brief)
+				}
+			}
+		}
 
 		// Добавляем базовую информацию о пользователях
 		if task.AssigneeID != nil {
-			assignee, err := s.userRepo.GetByID(ctx, *task.AssigneeID)
-			if err == nil {
-				resp.Assignee = &domain.UserBrief{
-					ID:        assignee.ID,
-					Email:     assignee.Email,
-					FirstName: assignee.FirstName,
-					LastName:  assignee.LastName,
-					Avatar:    assignee.Avatar,
-				}
+			if brief, ok := briefs[*task.AssigneeID]; ok {
+				resp.Assignee = &brief
 			}
 		}
 
-		creator, err := s.userRepo.GetByID(ctx, task.CreatedBy)
-		if err == nil {
-			resp.Creator = &domain.UserBrief{
-				ID:        creator.ID,
-				Email:     creator.Email,
-				FirstName: creator.FirstName,
-				LastName:  creator.LastName,
-				Avatar:    creator.Avatar,
-			}
+		if brief, ok := briefs[task.CreatedBy]; ok {
+			resp.Creator = &brief
 		}
 
 		taskResponses[i] = resp
 	}
 
-	// Формируем ответ с пагинацией
-	return &domain.PagedResponse{
-		Items:      taskResponses,
-		TotalItems: total,
-		Page:       page,
-		PageSize:   pageSize,
-		TotalPages: (total + pageSize - 1) / pageSize,
-	}, nil
+	return taskResponses
 }
 
 // UpdateStatus обновляет статус задачи
@@ -992,12 +2760,20 @@ func (s *TaskService) UpdateStatus(ctx context.Context, id string, status domain
 		})
 		return nil, ErrTaskNotFound
 	}
+	if task == nil {
+		return nil, ErrTaskNotFound
+	}
 
 	// Проверяем доступ пользователя к задаче
 	if !s.hasAccessToTask(ctx, task.ProjectID, userID) {
 		return nil, ErrTaskAccessDenied
 	}
 
+	// Задачи архивного проекта доступны только для чтения
+	if err := s.projectSvc.ensureProjectWritable(ctx, task.ProjectID); err != nil {
+		return nil, err
+	}
+
 	// Проверяем права на изменение статуса (должен быть хотя бы членом проекта)
 	if !s.canManageTask(ctx, task.ProjectID, userID) {
 		return nil, ErrInsufficientRights
@@ -1008,6 +2784,16 @@ func (s *TaskService) UpdateStatus(ctx context.Context, id string, status domain
 		return nil, ErrInvalidTaskStatus
 	}
 
+	// Проверяем поля, обязательные для перехода в новый статус
+	if status != task.Status {
+		if err := s.checkTransitionRules(ctx, task, status); err != nil {
+			return nil, err
+		}
+		if err := s.checkOpenSubtasks(ctx, task.ID, status); err != nil {
+			return nil, err
+		}
+	}
+
 	// Обновляем статус задачи
 	if err := s.taskRepo.UpdateStatus(ctx, id, status, userID); err != nil {
 		s.logger.Error("Failed to update task status", err, map[string]interface{}{
@@ -1016,16 +2802,6 @@ func (s *TaskService) UpdateStatus(ctx context.Context, id string, status domain
 		return nil, err
 	}
 
-	// Удаляем задачу из кэша
-	cacheKey := "task:" + id
-	if err := s.cacheRepo.Delete(ctx, cacheKey); err != nil {
-		s.logger.Warn("Failed to delete task from cache", map[string]interface{}{
-			"id": id,
-		}, map[string]interface{}{
-			"error": err,
-		})
-	}
-
 	// Получаем обновленную задачу
 	updatedTask, err := s.taskRepo.GetByID(ctx, id)
 	if err != nil {
@@ -1043,14 +2819,15 @@ func (s *TaskService) UpdateStatus(ctx context.Context, id string, status domain
 
 	// Отправляем событие об обновлении задачи
 	event := &messaging.TaskEvent{
-		ID:         updatedTask.ID,
-		Title:      updatedTask.Title,
-		ProjectID:  updatedTask.ProjectID,
-		Status:     string(updatedTask.Status),
-		Priority:   string(updatedTask.Priority),
-		AssigneeID: updatedTask.AssigneeID,
-		UpdatedAt:  updatedTask.UpdatedAt,
-		Type:       messaging.EventTypeTaskUpdated,
+		ID:          updatedTask.ID,
+		Title:       updatedTask.Title,
+		ProjectID:   updatedTask.ProjectID,
+		Status:      string(updatedTask.Status),
+		Priority:    string(updatedTask.Priority),
+		AssigneeID:  updatedTask.AssigneeID,
+		AssigneeIDs: updatedTask.AssigneeIDs,
+		UpdatedAt:   updatedTask.UpdatedAt,
+		Type:        messaging.EventTypeTaskUpdated,
 		Changes: map[string]interface{}{
 			"status": map[string]interface{}{
 				"old": string(task.Status),
@@ -1067,8 +2844,12 @@ func (s *TaskService) UpdateStatus(ctx context.Context, id string, status domain
 		})
 	}
 
+	// Собственные изменения пользователя не отмечаются в списках как непросмотренные
+	s.viewSvc.RecordView(ctx, userID, updatedTask.ID)
+
 	// Формируем ответ
 	resp := updatedTask.ToResponse()
+	s.fillAssignees(ctx, &resp)
 
 	// Добавляем информацию о пользователях
 	if updatedTask.AssigneeID != nil {
@@ -1097,3 +2878,220 @@ func (s *TaskService) UpdateStatus(ctx context.Context, id string, status domain
 
 	return &resp, nil
 }
+
+// AcquireDescriptionLock захватывает блокировку редактирования описания задачи.
+// Если блокировка удерживается другим пользователем, возвращает ее вместе с ErrTaskLocked.
+// Менеджеры и владельцы проекта могут принудительно перехватить блокировку
+func (s *TaskService) AcquireDescriptionLock(ctx context.Context, taskID string, force bool, userID string) (*domain.TaskEditLock, error) {
+	task, err := s.getTaskForLock(ctx, taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Описание задачи архивного проекта редактировать нельзя
+	if err := s.projectSvc.ensureProjectWritable(ctx, task.ProjectID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	lock := &domain.TaskEditLock{
+		TaskID:     taskID,
+		UserID:     userID,
+		AcquiredAt: now,
+		ExpiresAt:  now.Add(taskEditLockTTL),
+	}
+
+	// Пытаемся захватить свободную блокировку
+	acquired, err := s.cacheRepo.TryAcquireTaskEditLock(ctx, lock, taskEditLockTTL)
+	if err != nil {
+		return nil, err
+	}
+	if acquired {
+		return lock, nil
+	}
+
+	// Блокировка уже существует, проверяем ее владельца
+	current, err := s.cacheRepo.GetTaskEditLock(ctx, taskID)
+	if err != nil && !errors.Is(err, cache.ErrKeyNotFound) {
+		return nil, err
+	}
+
+	// Блокировка истекла после неудачного захвата, пробуем захватить ее еще раз
+	if current == nil {
+		acquired, err := s.cacheRepo.TryAcquireTaskEditLock(ctx, lock, taskEditLockTTL)
+		if err != nil {
+			return nil, err
+		}
+		if acquired {
+			return lock, nil
+		}
+		return s.currentLockConflict(ctx, taskID)
+	}
+
+	if current.UserID != userID {
+		if !force {
+			return current, ErrTaskLocked
+		}
+		// Принудительный перехват доступен только менеджерам проекта
+		if !s.projectSvc.canManageProject(ctx, task.ProjectID, userID) {
+			return current, ErrInsufficientRights
+		}
+	} else {
+		// Повторный захват своей блокировки сохраняет исходное время захвата
+		lock.AcquiredAt = current.AcquiredAt
+	}
+
+	// Блокировка заменяется, только если ее все еще держит тот же владелец: иначе
+	// перехват мог бы отнять блокировку у пользователя, захватившего ее после проверки
+	replaced, err := s.cacheRepo.ReplaceTaskEditLock(ctx, lock, current.UserID, taskEditLockTTL)
+	if err != nil {
+		return nil, err
+	}
+	if !replaced {
+		return s.currentLockConflict(ctx, taskID)
+	}
+
+	if current.UserID != userID {
+		s.logger.Info("Task edit lock taken over", map[string]interface{}{
+			"task_id":         taskID,
+			"user_id":         userID,
+			"previous_holder": current.UserID,
+		})
+	}
+
+	return lock, nil
+}
+
+// RenewDescriptionLock продлевает блокировку редактирования описания задачи
+func (s *TaskService) RenewDescriptionLock(ctx context.Context, taskID string, userID string) (*domain.TaskEditLock, error) {
+	if _, err := s.getTaskForLock(ctx, taskID, userID); err != nil {
+		return nil, err
+	}
+
+	current, err := s.cacheRepo.GetTaskEditLock(ctx, taskID)
+	if err != nil {
+		if errors.Is(err, cache.ErrKeyNotFound) {
+			return nil, ErrTaskLockNotHeld
+		}
+		return nil, err
+	}
+
+	// Продлить блокировку может только ее владелец
+	if current.UserID != userID {
+		return current, ErrTaskLocked
+	}
+
+	current.ExpiresAt = time.Now().Add(taskEditLockTTL)
+	renewed, err := s.cacheRepo.ReplaceTaskEditLock(ctx, current, userID, taskEditLockTTL)
+	if err != nil {
+		return nil, err
+	}
+	if !renewed {
+		// Блокировка истекла или перехвачена после чтения
+		latest, err := s.cacheRepo.GetTaskEditLock(ctx, taskID)
+		if err != nil {
+			if errors.Is(err, cache.ErrKeyNotFound) {
+				return nil, ErrTaskLockNotHeld
+			}
+			return nil, err
+		}
+		return latest, ErrTaskLocked
+	}
+
+	return current, nil
+}
+
+// ReleaseDescriptionLock освобождает блокировку редактирования описания задачи
+func (s *TaskService) ReleaseDescriptionLock(ctx context.Context, taskID string, userID string) error {
+	task, err := s.getTaskForLock(ctx, taskID, userID)
+	if err != nil {
+		return err
+	}
+
+	current, err := s.cacheRepo.GetTaskEditLock(ctx, taskID)
+	if err != nil {
+		if errors.Is(err, cache.ErrKeyNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	// Снять чужую блокировку могут только менеджеры проекта
+	if current.UserID != userID && !s.projectSvc.canManageProject(ctx, task.ProjectID, userID) {
+		return ErrInsufficientRights
+	}
+
+	// Удаляется только блокировка проверенного владельца: если ее успел захватить
+	// другой пользователь, его блокировка остается
+	_, err = s.cacheRepo.DeleteTaskEditLock(ctx, taskID, current.UserID)
+	return err
+}
+
+// currentLockConflict возвращает блокировку, которая помешала ее замене, вместе с ErrTaskLocked
+func (s *TaskService) currentLockConflict(ctx context.Context, taskID string) (*domain.TaskEditLock, error) {
+	current, err := s.cacheRepo.GetTaskEditLock(ctx, taskID)
+	if err != nil && !errors.Is(err, cache.ErrKeyNotFound) {
+		return nil, err
+	}
+	return current, ErrTaskLocked
+}
+
+// GetDescriptionLock возвращает текущую блокировку редактирования описания задачи или nil
+func (s *TaskService) GetDescriptionLock(ctx context.Context, taskID string, userID string) (*domain.TaskEditLock, error) {
+	if _, err := s.getTaskForLock(ctx, taskID, userID); err != nil {
+		return nil, err
+	}
+
+	current, err := s.cacheRepo.GetTaskEditLock(ctx, taskID)
+	if err != nil {
+		if errors.Is(err, cache.ErrKeyNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return current, nil
+}
+
+// ResolveRecipient определяет ID пользователя-адресата действия по задаче
+// (например, эскалации или согласования). Возвращает nil, если адресат не назначен
+func (s *TaskService) ResolveRecipient(ctx context.Context, task *domain.Task, target domain.TaskRecipient) (*string, error) {
+	switch target {
+	case domain.TaskRecipientAssignee:
+		return task.AssigneeID, nil
+	case domain.TaskRecipientCreator:
+		return &task.CreatedBy, nil
+	case domain.TaskRecipientAssigneeManager:
+		if task.AssigneeID == nil {
+			return nil, nil
+		}
+		assignee, err := s.userRepo.GetByID(ctx, *task.AssigneeID)
+		if err != nil {
+			s.logger.Error("Failed to get assignee for recipient resolution", err, map[string]interface{}{
+				"task_id":     task.ID,
+				"assignee_id": *task.AssigneeID,
+			})
+			return nil, err
+		}
+		if assignee == nil {
+			return nil, nil
+		}
+		return assignee.ManagerID, nil
+	default:
+		return nil, fmt.Errorf("unknown task recipient: %s", target)
+	}
+}
+
+// getTaskForLock получает задачу и проверяет доступ пользователя к ней
+func (s *TaskService) getTaskForLock(ctx context.Context, taskID string, userID string) (*domain.Task, error) {
+	task, err := s.taskRepo.GetByID(ctx, taskID)
+	if err != nil || task == nil {
+		return nil, ErrTaskNotFound
+	}
+
+	if !s.hasAccessToTask(ctx, task.ProjectID, userID) {
+		return nil, ErrTaskAccessDenied
+	}
+
+	return task, nil
+}
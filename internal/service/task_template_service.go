@@ -0,0 +1,288 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/internal/repository"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
+	"github.com/nurlyy/task_manager/pkg/logger"
+)
+
+var (
+	ErrTaskTemplateNotFound = apperrors.New(apperrors.CodeTaskTemplateNotFound, "task template not found")
+	ErrTaskTemplateExists   = apperrors.New(apperrors.CodeTaskTemplateExists, "task template with this name already exists")
+)
+
+// TaskTemplateService представляет бизнес-логику шаблонов задач
+type TaskTemplateService struct {
+	templateRepo repository.TaskTemplateRepository
+	projectSvc   *ProjectService
+	taskSvc      *TaskService
+	logger       logger.Logger
+}
+
+// NewTaskTemplateService создает новый экземпляр TaskTemplateService
+func NewTaskTemplateService(
+	templateRepo repository.TaskTemplateRepository,
+	projectSvc *ProjectService,
+	taskSvc *TaskService,
+	logger logger.Logger,
+) *TaskTemplateService {
+	return &TaskTemplateService{
+		templateRepo: templateRepo,
+		projectSvc:   projectSvc,
+		taskSvc:      taskSvc,
+		logger:       logger,
+	}
+}
+
+// List возвращает шаблоны задач проекта
+func (s *TaskTemplateService) List(ctx context.Context, projectID string) ([]domain.TaskTemplateResponse, error) {
+	templates, err := s.templateRepo.ListByProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]domain.TaskTemplateResponse, 0, len(templates))
+	for _, template := range templates {
+		responses = append(responses, template.ToResponse())
+	}
+
+	return responses, nil
+}
+
+// GetByID возвращает шаблон задачи
+func (s *TaskTemplateService) GetByID(ctx context.Context, id string, userID string) (*domain.TaskTemplateResponse, error) {
+	template, err := s.getTemplate(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := template.ToResponse()
+	return &resp, nil
+}
+
+// Create создает шаблон задачи в проекте
+func (s *TaskTemplateService) Create(ctx context.Context, projectID string, req domain.TaskTemplateRequest, userID string) (*domain.TaskTemplateResponse, error) {
+	if err := s.checkTemplate(ctx, projectID, "", req); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	template := &domain.TaskTemplate{
+		ID:        uuid.New().String(),
+		ProjectID: projectID,
+		CreatedBy: userID,
+		CreatedAt: now,
+	}
+	applyTemplateRequest(template, req, now)
+
+	if err := s.templateRepo.Create(ctx, template); err != nil {
+		s.logger.Error("Failed to create task template", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return nil, err
+	}
+
+	s.logger.Info("Task template created", map[string]interface{}{
+		"template_id": template.ID,
+		"project_id":  projectID,
+		"user_id":     userID,
+	})
+
+	resp := template.ToResponse()
+	return &resp, nil
+}
+
+// Update заменяет содержимое шаблона задачи
+func (s *TaskTemplateService) Update(ctx context.Context, id string, req domain.TaskTemplateRequest, userID string) (*domain.TaskTemplateResponse, error) {
+	template, err := s.getTemplate(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkCanManage(ctx, template.ProjectID, userID); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkTemplate(ctx, template.ProjectID, template.ID, req); err != nil {
+		return nil, err
+	}
+
+	applyTemplateRequest(template, req, time.Now())
+
+	if err := s.templateRepo.Update(ctx, template); err != nil {
+		s.logger.Error("Failed to update task template", err, map[string]interface{}{
+			"id": id,
+		})
+		return nil, err
+	}
+
+	resp := template.ToResponse()
+	return &resp, nil
+}
+
+// Delete удаляет шаблон задачи
+func (s *TaskTemplateService) Delete(ctx context.Context, id string, userID string) error {
+	template, err := s.getTemplate(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.checkCanManage(ctx, template.ProjectID, userID); err != nil {
+		return err
+	}
+
+	if err := s.templateRepo.Delete(ctx, id); err != nil {
+		s.logger.Error("Failed to delete task template", err, map[string]interface{}{
+			"id": id,
+		})
+		return err
+	}
+
+	return nil
+}
+
+// CreateTask создает задачу из шаблона, подставляя переданные значения переменных
+// в заголовок, описание, чек-лист и теги. Чек-лист добавляется в конец описания
+func (s *TaskTemplateService) CreateTask(ctx context.Context, id string, req domain.TaskFromTemplateRequest, userID string) (*domain.TaskResponse, error) {
+	template, err := s.getTemplate(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Все переменные шаблона должны получить значения
+	var violations []domain.FieldViolation
+	for _, variable := range template.Variables() {
+		if strings.TrimSpace(req.Variables[variable]) == "" {
+			violations = append(violations, domain.FieldViolation{
+				Field:   "variables." + variable,
+				Message: "This template variable is required",
+			})
+		}
+	}
+	if len(violations) > 0 {
+		return nil, &TaskValidationError{Violations: violations}
+	}
+
+	title := strings.TrimSpace(domain.ExpandTemplate(template.Title, req.Variables))
+	if len(title) < 3 || len(title) > 200 {
+		return nil, &TaskValidationError{Violations: []domain.FieldViolation{{
+			Field:   "title",
+			Message: "Expanded title must be between 3 and 200 characters",
+		}}}
+	}
+
+	description := domain.ExpandTemplate(template.Description, req.Variables)
+	if len(template.Checklist) > 0 {
+		var checklist strings.Builder
+		for _, item := range template.Checklist {
+			checklist.WriteString("\n- [ ] " + domain.ExpandTemplate(item, req.Variables))
+		}
+		description = strings.TrimSpace(description + "\n" + checklist.String())
+	}
+
+	tags := make([]string, 0, len(template.Tags))
+	for _, tag := range template.Tags {
+		if tag = strings.TrimSpace(domain.ExpandTemplate(tag, req.Variables)); tag != "" && !containsString(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+
+	// Исполнитель из запроса должен состоять в проекте, исполнителя из шаблона,
+	// покинувшего проект, просто не назначаем
+	assigneeID := req.AssigneeID
+	if assigneeID != nil {
+		if err := s.taskSvc.checkAssigneesMembership(ctx, template.ProjectID, []string{*assigneeID}); err != nil {
+			return nil, err
+		}
+	} else if template.AssigneeID != nil {
+		if err := s.taskSvc.checkAssigneesMembership(ctx, template.ProjectID, []string{*template.AssigneeID}); err == nil {
+			assigneeID = template.AssigneeID
+		} else {
+			s.logger.Warn("Template assignee is no longer a project member", map[string]interface{}{
+				"template_id": template.ID,
+				"assignee_id": *template.AssigneeID,
+			})
+		}
+	}
+
+	createReq := domain.TaskCreateRequest{
+		Title:          title,
+		Description:    description,
+		ProjectID:      template.ProjectID,
+		AssigneeID:     assigneeID,
+		DueDate:        req.DueDate,
+		EstimatedHours: template.EstimatedHours,
+		Tags:           tags,
+	}
+	if template.Priority != nil {
+		createReq.Priority = *template.Priority
+	}
+
+	return s.taskSvc.Create(ctx, createReq, userID)
+}
+
+// getTemplate возвращает шаблон, если пользователь имеет доступ к его проекту
+func (s *TaskTemplateService) getTemplate(ctx context.Context, id string, userID string) (*domain.TaskTemplate, error) {
+	template, err := s.templateRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if template == nil || !s.projectSvc.hasAccessToProject(ctx, template.ProjectID, userID) {
+		return nil, ErrTaskTemplateNotFound
+	}
+	return template, nil
+}
+
+// checkCanManage проверяет, что пользователь может управлять шаблонами проекта
+func (s *TaskTemplateService) checkCanManage(ctx context.Context, projectID string, userID string) error {
+	if !s.projectSvc.hasAccessToProject(ctx, projectID, userID) {
+		return ErrProjectNotFound
+	}
+	if !s.projectSvc.canManageProject(ctx, projectID, userID) {
+		return ErrInsufficientRights
+	}
+	return nil
+}
+
+// checkTemplate проверяет уникальность имени шаблона и исполнителя по умолчанию
+func (s *TaskTemplateService) checkTemplate(ctx context.Context, projectID, templateID string, req domain.TaskTemplateRequest) error {
+	existing, err := s.templateRepo.GetByName(ctx, projectID, req.Name)
+	if err != nil {
+		return err
+	}
+	if existing != nil && existing.ID != templateID {
+		return ErrTaskTemplateExists
+	}
+
+	if req.AssigneeID != nil {
+		return s.taskSvc.checkAssigneesMembership(ctx, projectID, []string{*req.AssigneeID})
+	}
+	return nil
+}
+
+// applyTemplateRequest переносит данные запроса в шаблон
+func applyTemplateRequest(template *domain.TaskTemplate, req domain.TaskTemplateRequest, now time.Time) {
+	template.Name = req.Name
+	template.Title = req.Title
+	template.Description = req.Description
+	template.Checklist = req.Checklist
+	template.Tags = req.Tags
+	// Колонки массивов не допускают NULL
+	if template.Checklist == nil {
+		template.Checklist = []string{}
+	}
+	if template.Tags == nil {
+		template.Tags = []string{}
+	}
+	template.Priority = req.Priority
+	template.AssigneeID = req.AssigneeID
+	template.EstimatedHours = req.EstimatedHours
+	template.UpdatedAt = now
+}
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/internal/repository"
+	"github.com/nurlyy/task_manager/internal/repository/cache"
+	"github.com/nurlyy/task_manager/pkg/auth"
+	"github.com/nurlyy/task_manager/pkg/logger"
+)
+
+// taskViewTTL задает, сколько просмотры пользователя хранятся в Redis после его последнего
+// просмотра задачи. Более старые просмотры читаются из базы
+const taskViewTTL = 7 * 24 * time.Hour
+
+// TaskViewService отслеживает просмотры задач пользователями, чтобы в списках отмечать задачи,
+// изменившиеся после последнего просмотра. Просмотр записывается в Redis, в базу просмотры
+// переносятся планировщиком пачками
+type TaskViewService struct {
+	viewRepo  repository.TaskViewRepository
+	cacheRepo *cache.RedisRepository
+	logger    logger.Logger
+}
+
+// NewTaskViewService создает новый экземпляр TaskViewService
+func NewTaskViewService(
+	viewRepo repository.TaskViewRepository,
+	cacheRepo *cache.RedisRepository,
+	logger logger.Logger,
+) *TaskViewService {
+	return &TaskViewService{
+		viewRepo:  viewRepo,
+		cacheRepo: cacheRepo,
+		logger:    logger,
+	}
+}
+
+// RecordView отмечает, что пользователь просмотрел задачу. Просмотр отмечается только
+// для пользователя запроса: задачи, прочитанные от имени другого пользователя (интеграции,
+// фоновые задачи), им не просмотрены. Ошибка записи только логируется
+func (s *TaskViewService) RecordView(ctx context.Context, userID, taskID string) {
+	if userID == "" || userID != auth.ActorID(ctx) {
+		return
+	}
+
+	view := &domain.TaskView{
+		UserID:   userID,
+		TaskID:   taskID,
+		ViewedAt: time.Now(),
+	}
+	if err := s.cacheRepo.RecordTaskView(ctx, view, taskViewTTL); err != nil {
+		s.logger.Warn("Failed to record task view", map[string]interface{}{
+			"user_id": userID,
+			"task_id": taskID,
+		}, map[string]interface{}{
+			"error": err,
+		})
+	}
+}
+
+// LastViews возвращает время последнего просмотра задач пользователем. Просмотры ищутся
+// в Redis, отсутствующие там - в базе. При ошибке возвращаются найденные просмотры
+func (s *TaskViewService) LastViews(ctx context.Context, userID string, taskIDs []string) map[string]time.Time {
+	views, err := s.cacheRepo.GetTaskViews(ctx, userID, taskIDs)
+	if err != nil {
+		s.logger.Warn("Failed to get task views from cache", map[string]interface{}{
+			"user_id": userID,
+		}, map[string]interface{}{
+			"error": err,
+		})
+		views = make(map[string]time.Time, len(taskIDs))
+	}
+
+	missing := make([]string, 0, len(taskIDs))
+	for _, taskID := range taskIDs {
+		if _, ok := views[taskID]; !ok {
+			missing = append(missing, taskID)
+		}
+	}
+	if len(missing) == 0 {
+		return views
+	}
+
+	stored, err := s.viewRepo.GetViews(ctx, userID, missing)
+	if err != nil {
+		s.logger.Warn("Failed to get task views", map[string]interface{}{
+			"user_id": userID,
+		}, map[string]interface{}{
+			"error": err,
+		})
+		return views
+	}
+	for taskID, viewedAt := range stored {
+		views[taskID] = viewedAt
+	}
+	return views
+}
+
+// MarkChanged заполняет в ответах списка время последнего просмотра и признак изменения
+// задачи после него. Непросмотренные задачи изменившимися не считаются
+func (s *TaskViewService) MarkChanged(ctx context.Context, userID string, tasks []domain.TaskResponse) {
+	if userID == "" || len(tasks) == 0 {
+		return
+	}
+
+	taskIDs := make([]string, len(tasks))
+	for i := range tasks {
+		taskIDs[i] = tasks[i].ID
+	}
+
+	views := s.LastViews(ctx, userID, taskIDs)
+	for i := range tasks {
+		viewedAt, ok := views[tasks[i].ID]
+		if !ok {
+			continue
+		}
+		tasks[i].LastViewedAt = &viewedAt
+		tasks[i].ChangedSinceView = tasks[i].UpdatedAt.After(viewedAt)
+	}
+}
+
+// Flush переносит в базу просмотры, записанные в Redis после предыдущего переноса, пачками
+// по batchSize и возвращает число перенесенных просмотров. Пачка, которую не удалось
+// сохранить, возвращается в очередь до следующего запуска
+func (s *TaskViewService) Flush(ctx context.Context, batchSize int) (int, error) {
+	flushed := 0
+	for {
+		views, err := s.cacheRepo.PopDirtyTaskViews(ctx, batchSize)
+		if err != nil {
+			return flushed, err
+		}
+
+		if err := s.viewRepo.SaveViews(ctx, views); err != nil {
+			if restoreErr := s.cacheRepo.MarkTaskViewsDirty(ctx, views); restoreErr != nil {
+				s.logger.Error("Failed to return task views to flush queue", restoreErr, map[string]interface{}{
+					"count": len(views),
+				})
+			}
+			return flushed, err
+		}
+		flushed += len(views)
+
+		if len(views) < batchSize {
+			return flushed, nil
+		}
+	}
+}
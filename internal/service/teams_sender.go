@@ -0,0 +1,239 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/pkg/circuit"
+	"github.com/nurlyy/task_manager/pkg/config"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
+	"github.com/nurlyy/task_manager/pkg/logger"
+)
+
+var (
+	ErrTeamsWebhookNotAllowed = apperrors.New(apperrors.CodeTeamsWebhookNotAllowed, "teams webhook host is not allowed")
+)
+
+// teamsFact описывает строку карточки: подпись и ключ метаданных уведомления со значением
+type teamsFact struct {
+	Title   string
+	MetaKey string
+}
+
+// teamsCardTemplate описывает адаптивную карточку для типа уведомления
+type teamsCardTemplate struct {
+	Color string // Цвет заголовка в терминах Adaptive Cards: Accent, Good, Warning, Attention
+	Facts []teamsFact
+}
+
+// teamsCardTemplates содержит шаблоны карточек по типам уведомлений
+var teamsCardTemplates = map[domain.NotificationType]teamsCardTemplate{
+	domain.NotificationTypeTaskAssigned: {
+		Color: "Accent",
+		Facts: []teamsFact{{"Задача", "task_title"}, {"Приоритет", "priority"}, {"Срок выполнения", "due_date"}},
+	},
+	domain.NotificationTypeTaskUpdated: {
+		Color: "Accent",
+		Facts: []teamsFact{{"Задача", "task_title"}, {"Статус", "status"}, {"Исполнитель", "assignee_name"}},
+	},
+	domain.NotificationTypeTaskCommented: {
+		Color: "Default",
+		Facts: []teamsFact{{"Задача", "task_title"}, {"Автор комментария", "user_name"}, {"Комментарий", "comment_content"}},
+	},
+	domain.NotificationTypeTaskDueSoon: {
+		Color: "Warning",
+		Facts: []teamsFact{{"Задача", "task_title"}, {"Срок выполнения", "due_date"}, {"Осталось часов", "hours_left"}},
+	},
+	domain.NotificationTypeTaskOverdue: {
+		Color: "Attention",
+		Facts: []teamsFact{{"Задача", "task_title"}, {"Срок выполнения истек", "due_date"}},
+	},
+	domain.NotificationTypeProjectMemberAdded: {
+		Color: "Good",
+		Facts: []teamsFact{{"Проект", "project_name"}, {"Роль", "role"}},
+	},
+	domain.NotificationTypeProjectUpdated: {
+		Color: "Accent",
+		Facts: []teamsFact{{"Проект", "project_name"}, {"Статус", "status"}},
+	},
+}
+
+// TeamsSender обеспечивает отправку уведомлений в Microsoft Teams через входящие вебхуки
+// (коннектор Incoming Webhook или процесс Workflows "Post to a chat when a webhook request is received")
+type TeamsSender struct {
+	client       *http.Client
+	breaker      *circuit.Breaker
+	allowedHosts []string
+	baseURL      string
+	logger       logger.Logger
+}
+
+// NewTeamsSender создает новый экземпляр TeamsSender
+func NewTeamsSender(config *config.TeamsConfig, baseURL string, logger logger.Logger) *TeamsSender {
+	allowedHosts := make([]string, 0, len(config.AllowedHosts))
+	for _, host := range config.AllowedHosts {
+		if host = strings.ToLower(strings.TrimSpace(host)); host != "" {
+			allowedHosts = append(allowedHosts, host)
+		}
+	}
+
+	return &TeamsSender{
+		client: &http.Client{
+			Timeout: config.Timeout,
+		},
+		breaker:      circuit.Default.Get("teams"),
+		allowedHosts: allowedHosts,
+		baseURL:      strings.TrimRight(baseURL, "/"),
+		logger:       logger,
+	}
+}
+
+// ValidateWebhookURL проверяет, что вебхук ведет на разрешенный хост Microsoft по HTTPS
+func (s *TeamsSender) ValidateWebhookURL(rawURL string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Scheme != "https" {
+		return ErrTeamsWebhookNotAllowed
+	}
+
+	host := strings.ToLower(parsed.Hostname())
+	for _, allowed := range s.allowedHosts {
+		if host == allowed || strings.HasSuffix(host, "."+allowed) {
+			return nil
+		}
+	}
+	return ErrTeamsWebhookNotAllowed
+}
+
+// SendNotification отправляет уведомление адаптивной карточкой на вебхук Teams
+func (s *TeamsSender) SendNotification(ctx context.Context, webhookURL string, notification *domain.Notification) error {
+	if err := s.ValidateWebhookURL(webhookURL); err != nil {
+		return err
+	}
+
+	payload := map[string]interface{}{
+		"type": "message",
+		"attachments": []map[string]interface{}{
+			{
+				"contentType": "application/vnd.microsoft.card.adaptive",
+				"contentUrl":  nil,
+				"content":     s.buildCard(notification),
+			},
+		},
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode Teams card: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create Teams request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	err = s.breaker.Do(func() error {
+		resp, err := s.client.Do(req)
+		if err != nil {
+			return fmt.Errorf("failed to send Teams notification: %w", err)
+		}
+		defer resp.Body.Close()
+
+		// Коннектор отвечает 200, процессы Workflows - 202
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+			return deliveryStatusError(resp.StatusCode, fmt.Errorf("teams webhook returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody))))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	s.logger.Debug("Teams notification sent", map[string]interface{}{
+		"notification_type": notification.Type,
+		"entity_id":         notification.EntityID,
+	})
+	return nil
+}
+
+// buildCard формирует адаптивную карточку по шаблону типа уведомления
+func (s *TeamsSender) buildCard(notification *domain.Notification) map[string]interface{} {
+	template, ok := teamsCardTemplates[notification.Type]
+	if !ok {
+		template = teamsCardTemplate{Color: "Default"}
+	}
+
+	facts := []map[string]string{}
+	for _, fact := range template.Facts {
+		if value := notification.MetaData[fact.MetaKey]; value != "" {
+			facts = append(facts, map[string]string{"title": fact.Title, "value": value})
+		}
+	}
+
+	body := []map[string]interface{}{
+		{
+			"type":   "TextBlock",
+			"text":   notification.Title,
+			"weight": "Bolder",
+			"size":   "Medium",
+			"color":  template.Color,
+			"wrap":   true,
+		},
+		{
+			"type": "TextBlock",
+			"text": notification.Content,
+			"wrap": true,
+		},
+	}
+	if len(facts) > 0 {
+		body = append(body, map[string]interface{}{
+			"type":  "FactSet",
+			"facts": facts,
+		})
+	}
+	body = append(body, map[string]interface{}{
+		"type":     "TextBlock",
+		"text":     "Отправлено: " + notification.CreatedAt.Format("02.01.2006 15:04"),
+		"isSubtle": true,
+		"size":     "Small",
+		"wrap":     true,
+	})
+
+	card := map[string]interface{}{
+		"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+		"type":    "AdaptiveCard",
+		"version": "1.4",
+		"body":    body,
+	}
+
+	if link := s.entityURL(notification); link != "" {
+		card["actions"] = []map[string]string{
+			{"type": "Action.OpenUrl", "title": "Открыть", "url": link},
+		}
+	}
+
+	return card
+}
+
+// entityURL возвращает ссылку на задачу или проект уведомления
+func (s *TeamsSender) entityURL(notification *domain.Notification) string {
+	if s.baseURL == "" || notification.EntityID == "" {
+		return ""
+	}
+
+	switch notification.EntityType {
+	case "task":
+		return s.baseURL + "/tasks/" + url.PathEscape(notification.EntityID)
+	case "project":
+		return s.baseURL + "/projects/" + url.PathEscape(notification.EntityID)
+	}
+	return ""
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/internal/repository"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
+	"github.com/nurlyy/task_manager/pkg/logger"
+)
+
+var (
+	ErrTeamsChannelNotFound    = apperrors.New(apperrors.CodeTeamsChannelNotFound, "teams channel not found")
+	ErrTeamsWebhookUnreachable = apperrors.New(apperrors.CodeTeamsWebhookUnreachable, "teams webhook is unreachable")
+)
+
+// TeamsService представляет бизнес-логику подключения Microsoft Teams к уведомлениям
+type TeamsService struct {
+	teamsRepo   repository.TeamsRepository
+	teamsSender *TeamsSender
+	logger      logger.Logger
+}
+
+// NewTeamsService создает новый экземпляр TeamsService
+func NewTeamsService(
+	teamsRepo repository.TeamsRepository,
+	teamsSender *TeamsSender,
+	logger logger.Logger,
+) *TeamsService {
+	return &TeamsService{
+		teamsRepo:   teamsRepo,
+		teamsSender: teamsSender,
+		logger:      logger,
+	}
+}
+
+// GetStatus возвращает состояние подключения личного чата Teams пользователя
+func (s *TeamsService) GetStatus(ctx context.Context, userID string) (*domain.TeamsStatus, error) {
+	link, err := s.teamsRepo.GetUserLink(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if link == nil {
+		return &domain.TeamsStatus{Connected: false}, nil
+	}
+	return &domain.TeamsStatus{Connected: true, UpdatedAt: &link.UpdatedAt}, nil
+}
+
+// Connect подключает личный чат Teams пользователя. Вебхук проверяется отправкой тестовой карточки
+func (s *TeamsService) Connect(ctx context.Context, userID string, req domain.TeamsLinkRequest) (*domain.TeamsStatus, error) {
+	if err := s.checkWebhook(ctx, req.WebhookURL, "Teams подключен", "Уведомления о задачах будут приходить в этот чат"); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	link := &domain.TeamsLink{
+		UserID:     userID,
+		WebhookURL: req.WebhookURL,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if err := s.teamsRepo.SaveUserLink(ctx, link); err != nil {
+		s.logger.Error("Failed to connect Teams", err, map[string]interface{}{
+			"user_id": userID,
+		})
+		return nil, err
+	}
+
+	s.logger.Info("Teams connected", map[string]interface{}{
+		"user_id": userID,
+	})
+
+	return &domain.TeamsStatus{Connected: true, UpdatedAt: &now}, nil
+}
+
+// Disconnect отключает личный чат Teams пользователя
+func (s *TeamsService) Disconnect(ctx context.Context, userID string) error {
+	return s.teamsRepo.DeleteUserLink(ctx, userID)
+}
+
+// GetProjectChannel возвращает канал Teams проекта
+func (s *TeamsService) GetProjectChannel(ctx context.Context, projectID string) (*domain.ProjectTeamsChannel, error) {
+	channel, err := s.teamsRepo.GetProjectChannel(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if channel == nil {
+		return nil, ErrTeamsChannelNotFound
+	}
+	return channel, nil
+}
+
+// SaveProjectChannel создает или обновляет канал Teams проекта
+func (s *TeamsService) SaveProjectChannel(ctx context.Context, projectID string, req domain.ProjectTeamsChannelRequest, userID string) (*domain.ProjectTeamsChannel, error) {
+	channel, err := s.teamsRepo.GetProjectChannel(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if channel == nil {
+		// Новому каналу нужен вебхук
+		if req.WebhookURL == "" {
+			return nil, &TaskValidationError{Violations: []domain.FieldViolation{{
+				Field:   "webhook_url",
+				Message: "Webhook URL is required",
+			}}}
+		}
+		channel = &domain.ProjectTeamsChannel{
+			ProjectID: projectID,
+			Enabled:   true,
+			CreatedBy: userID,
+			CreatedAt: now,
+		}
+	}
+
+	if req.WebhookURL != "" && req.WebhookURL != channel.WebhookURL {
+		if err := s.checkWebhook(ctx, req.WebhookURL, "Канал подключен", "Уведомления проекта будут публиковаться в этот канал"); err != nil {
+			return nil, err
+		}
+		channel.WebhookURL = req.WebhookURL
+	}
+	if req.Enabled != nil {
+		channel.Enabled = *req.Enabled
+	}
+	channel.NotificationTypes = req.NotificationTypes
+	channel.UpdatedAt = now
+
+	if err := s.teamsRepo.SaveProjectChannel(ctx, channel); err != nil {
+		s.logger.Error("Failed to save project Teams channel", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return nil, err
+	}
+
+	return channel, nil
+}
+
+// DeleteProjectChannel удаляет канал Teams проекта
+func (s *TeamsService) DeleteProjectChannel(ctx context.Context, projectID string) error {
+	return s.teamsRepo.DeleteProjectChannel(ctx, projectID)
+}
+
+// checkWebhook проверяет хост вебхука и отправляет на него тестовую карточку
+func (s *TeamsService) checkWebhook(ctx context.Context, webhookURL, title, content string) error {
+	if err := s.teamsSender.ValidateWebhookURL(webhookURL); err != nil {
+		return err
+	}
+
+	notification := &domain.Notification{
+		Title:     title,
+		Content:   content,
+		CreatedAt: time.Now(),
+	}
+	if err := s.teamsSender.SendNotification(ctx, webhookURL, notification); err != nil {
+		s.logger.Warn("Teams webhook check failed", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return fmt.Errorf("%w: %v", ErrTeamsWebhookUnreachable, err)
+	}
+	return nil
+}
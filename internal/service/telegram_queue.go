@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// telegramBatchWorkers ограничивает число одновременных отправок пакета сообщений.
+// Темп отправки задает telegramLimiter, воркеры лишь не дают медленному ответу
+// одного запроса задерживать весь пакет
+const telegramBatchWorkers = 8
+
+// telegramChatCleanupSize - размер таблицы темпа по чатам, после которого из нее
+// удаляются чаты без запланированных отправок
+const telegramChatCleanupSize = 1000
+
+// TelegramMessage представляет сообщение, ожидающее отправки в чат Telegram
+type TelegramMessage struct {
+	ChatID    string
+	Text      string
+	ParseMode string                  // Режим разметки; пустой - простой текст
+	Keyboard  *TelegramInlineKeyboard // Встроенная клавиатура под сообщением
+}
+
+// telegramLimiter выстраивает отправки в очередь с соблюдением ограничений Bot API:
+// общего числа сообщений в секунду для бота и интервала между сообщениями в один чат.
+// После ответа 429 все отправки приостанавливаются на указанное Telegram время
+type telegramLimiter struct {
+	global       *rate.Limiter
+	chatInterval time.Duration
+
+	mu          sync.Mutex
+	chatNext    map[string]time.Time
+	pausedUntil time.Time
+}
+
+// newTelegramLimiter создает очередь отправок с заданным темпом
+func newTelegramLimiter(perSecond float64, chatInterval time.Duration) *telegramLimiter {
+	return &telegramLimiter{
+		global:       rate.NewLimiter(rate.Limit(perSecond), 1),
+		chatInterval: chatInterval,
+		chatNext:     make(map[string]time.Time),
+	}
+}
+
+// wait занимает место в очереди чата и ожидает своей очереди на отправку
+func (l *telegramLimiter) wait(ctx context.Context, chatID string) error {
+	l.mu.Lock()
+	now := time.Now()
+	at := now
+	if next := l.chatNext[chatID]; next.After(at) {
+		at = next
+	}
+	if l.pausedUntil.After(at) {
+		at = l.pausedUntil
+	}
+	if len(l.chatNext) >= telegramChatCleanupSize {
+		for id, next := range l.chatNext {
+			if next.Before(now) {
+				delete(l.chatNext, id)
+			}
+		}
+	}
+	l.chatNext[chatID] = at.Add(l.chatInterval)
+	l.mu.Unlock()
+
+	// Приостановка после 429 могла начаться, пока сообщение ждало своей очереди
+	for delay := time.Until(at); delay > 0; delay = l.pausedFor() {
+		if err := sleepContext(ctx, delay); err != nil {
+			return err
+		}
+	}
+
+	return l.global.Wait(ctx)
+}
+
+// pausedFor возвращает оставшееся время приостановки отправок
+func (l *telegramLimiter) pausedFor() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return time.Until(l.pausedUntil)
+}
+
+// pause приостанавливает все отправки на время, указанное в ответе 429
+func (l *telegramLimiter) pause(d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if until := time.Now().Add(d); until.After(l.pausedUntil) {
+		l.pausedUntil = until
+	}
+}
+
+// sleepContext ожидает указанное время или отмену контекста
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -2,27 +2,37 @@ package service
 
 import (
 	"context"
+	"crypto/subtle"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/nurlyy/task_manager/internal/domain"
 	"github.com/nurlyy/task_manager/internal/repository"
+	"github.com/nurlyy/task_manager/pkg/circuit"
+	"github.com/nurlyy/task_manager/pkg/config"
 	"github.com/nurlyy/task_manager/pkg/logger"
 )
 
 // TelegramSender обеспечивает отправку уведомлений в Telegram
 type TelegramSender struct {
-	botToken     string
-	apiBaseURL   string
-	client       *http.Client
-	logger       logger.Logger
-	telegramRepo repository.TelegramRepository
-	botUsername  string
+	botToken      string
+	apiBaseURL    string
+	baseURL       string
+	webhookSecret string
+	client        *http.Client
+	breaker       *circuit.Breaker
+	limiter       *telegramLimiter
+	maxRetries    int
+	logger        logger.Logger
+	telegramRepo  repository.TelegramRepository
+	botUsername   string
 }
 
 // TelegramResponse представляет ответ от Telegram API
@@ -30,8 +40,20 @@ type TelegramResponse struct {
 	Ok          bool            `json:"ok"`
 	Description string          `json:"description,omitempty"`
 	Result      json.RawMessage `json:"result,omitempty"`
+	Parameters  *struct {
+		RetryAfter int `json:"retry_after,omitempty"` // Через сколько секунд можно повторить запрос после 429
+	} `json:"parameters,omitempty"`
 }
 
+// telegramRetryError сообщает, что Telegram ограничил частоту запросов
+type telegramRetryError struct {
+	retryAfter time.Duration
+	err        error
+}
+
+func (e *telegramRetryError) Error() string { return e.err.Error() }
+func (e *telegramRetryError) Unwrap() error { return e.err }
+
 // TelegramUser представляет информацию о пользователе Telegram
 type TelegramUser struct {
 	ID        int    `json:"id"`
@@ -42,7 +64,8 @@ type TelegramUser struct {
 
 // NewTelegramSender создает новый экземпляр TelegramSender
 func NewTelegramSender(
-	botToken string,
+	config *config.TelegramConfig,
+	baseURL string,
 	telegramRepo repository.TelegramRepository,
 	logger logger.Logger,
 ) *TelegramSender {
@@ -52,11 +75,16 @@ func NewTelegramSender(
 	}
 
 	sender := &TelegramSender{
-		botToken:     botToken,
-		apiBaseURL:   "https://api.telegram.org/bot",
-		client:       client,
-		logger:       logger,
-		telegramRepo: telegramRepo,
+		botToken:      config.Token,
+		apiBaseURL:    "https://api.telegram.org/bot",
+		baseURL:       strings.TrimRight(baseURL, "/"),
+		webhookSecret: config.WebhookSecret,
+		client:        client,
+		breaker:       circuit.Default.Get("telegram"),
+		limiter:       newTelegramLimiter(config.RateLimit, config.ChatInterval),
+		maxRetries:    config.MaxRetries,
+		logger:        logger,
+		telegramRepo:  telegramRepo,
 	}
 
 	// Получаем информацию о боте
@@ -107,7 +135,10 @@ func (s *TelegramSender) SetupWebhook(webhookURL string) error {
 	// Формируем данные запроса
 	data := url.Values{}
 	data.Set("url", webhookURL)
-	data.Set("allowed_updates", `["message"]`)
+	data.Set("allowed_updates", `["message","callback_query"]`)
+	if s.webhookSecret != "" {
+		data.Set("secret_token", s.webhookSecret)
+	}
 
 	// Отправляем POST-запрос
 	resp, err := s.client.Post(apiURL, "application/x-www-form-urlencoded", strings.NewReader(data.Encode()))
@@ -145,36 +176,135 @@ func (s *TelegramSender) GetBotUsername() string {
 	return s.botUsername
 }
 
+// VerifyWebhookSecret проверяет секрет из заголовка запроса webhook.
+// Если секрет не настроен, запросы отклоняются: иначе любой мог бы прислать поддельное
+// обновление, например привязать чужой аккаунт к своему чату командой /connect
+func (s *TelegramSender) VerifyWebhookSecret(token string) bool {
+	if s.webhookSecret == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(s.webhookSecret)) == 1
+}
+
 // SendNotification отправляет уведомление в Telegram
 func (s *TelegramSender) SendNotification(ctx context.Context, user *domain.User, notification *domain.Notification) error {
+	message, err := s.NotificationMessage(ctx, user, notification)
+	if err != nil {
+		return err
+	}
+
+	// Отправляем сообщение
+	if err := s.send(ctx, message); err != nil {
+		return fmt.Errorf("failed to send Telegram message: %w", err)
+	}
+
+	return nil
+}
+
+// NotificationMessage формирует сообщение с уведомлением для чата пользователя
+func (s *TelegramSender) NotificationMessage(ctx context.Context, user *domain.User, notification *domain.Notification) (TelegramMessage, error) {
 	// Получаем Telegram ID пользователя из репозитория
 	telegramLink, err := s.telegramRepo.GetByUserID(ctx, user.ID)
 	if err != nil {
-		return fmt.Errorf("failed to get Telegram link: %w", err)
+		return TelegramMessage{}, fmt.Errorf("failed to get Telegram link: %w", err)
 	}
 
 	// Проверяем, не nil ли telegramLink
 	if telegramLink == nil {
-		return fmt.Errorf("user %s has no telegram link", user.ID)
+		return TelegramMessage{}, fmt.Errorf("user %s has no telegram link", user.ID)
 	}
 
-	// Формируем сообщение в зависимости от типа уведомления
-	message := s.formatMessage(notification, user)
+	// Формируем сообщение по шаблону типа уведомления
+	return TelegramMessage{
+		ChatID:    telegramLink.ChatID,
+		Text:      s.formatMessage(notification),
+		ParseMode: telegramParseMode,
+		Keyboard:  s.notificationKeyboard(notification),
+	}, nil
+}
 
-	// Отправляем сообщение
-	if err := s.SendMessage(telegramLink.ChatID, message); err != nil {
-		return fmt.Errorf("failed to send Telegram message: %w", err)
+// SendBatch отправляет пакет сообщений через общую очередь с соблюдением ограничений
+// Telegram. Возвращает ошибки в порядке сообщений; nil означает успешную отправку
+func (s *TelegramSender) SendBatch(ctx context.Context, messages []TelegramMessage) []error {
+	errs := make([]error, len(messages))
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < telegramBatchWorkers && i < len(messages); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indexes {
+				errs[index] = s.send(ctx, messages[index])
+			}
+		}()
 	}
 
-	return nil
+	for i := range messages {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	return errs
+}
+
+// ThrottledFor возвращает, на сколько еще приостановлены отправки после ответа 429;
+// ноль, если отправки не приостановлены
+func (s *TelegramSender) ThrottledFor() time.Duration {
+	if d := s.limiter.pausedFor(); d > 0 {
+		return d
+	}
+	return 0
 }
 
 // SendMessage отправляет сообщение в Telegram
 func (s *TelegramSender) SendMessage(telegramID, message string) error {
+	return s.SendMessageContext(context.Background(), telegramID, message)
+}
+
+// SendMessageContext отправляет в Telegram сообщение простым текстом без разметки
+func (s *TelegramSender) SendMessageContext(ctx context.Context, telegramID, message string) error {
+	return s.send(ctx, TelegramMessage{ChatID: telegramID, Text: message})
+}
+
+// send отправляет сообщение в Telegram в порядке общей очереди отправок.
+// На ответ 429 сообщение отправляется повторно через указанное Telegram время,
+// не более maxRetries раз. При недоступности Telegram API сообщения отклоняются
+// выключателем без ожидания таймаута
+func (s *TelegramSender) send(ctx context.Context, message TelegramMessage) error {
+	telegramID := message.ChatID
+	for attempt := 0; ; attempt++ {
+		if err := s.limiter.wait(ctx, telegramID); err != nil {
+			return err
+		}
+
+		err := s.breaker.Do(func() error {
+			return s.sendMessage(ctx, message)
+		})
+
+		var retryErr *telegramRetryError
+		if !errors.As(err, &retryErr) || attempt >= s.maxRetries {
+			return err
+		}
+
+		s.logger.Warn("Telegram rate limit exceeded", map[string]interface{}{
+			"chat_id":     telegramID,
+			"retry_after": retryErr.retryAfter.String(),
+			"attempt":     attempt + 1,
+		})
+		s.limiter.pause(retryErr.retryAfter)
+	}
+}
+
+// sendMessage выполняет запрос отправки сообщения к Telegram API
+func (s *TelegramSender) sendMessage(ctx context.Context, message TelegramMessage) error {
+	telegramID := message.ChatID
+
 	// Логируем начало отправки
 	s.logger.Info("Starting to send Telegram message", map[string]interface{}{
 		"chat_id":        telegramID,
-		"message_length": len(message),
+		"message_length": len(message.Text),
 	})
 
 	// Формируем URL для отправки сообщения
@@ -186,18 +316,32 @@ func (s *TelegramSender) SendMessage(telegramID, message string) error {
 	// Формируем данные запроса
 	data := url.Values{}
 	data.Set("chat_id", telegramID)
-	data.Set("text", message)
-	data.Set("parse_mode", "Markdown")
+	data.Set("text", message.Text)
+	if message.ParseMode != "" {
+		data.Set("parse_mode", message.ParseMode)
+	}
+	if message.Keyboard != nil {
+		keyboard, err := json.Marshal(message.Keyboard)
+		if err != nil {
+			return fmt.Errorf("failed to encode reply markup: %w", err)
+		}
+		data.Set("reply_markup", string(keyboard))
+	}
 
 	s.logger.Info("Prepared request data", map[string]interface{}{
 		"chat_id":     telegramID,
-		"parse_mode":  "Markdown",
+		"parse_mode":  message.ParseMode,
 		"data_length": len(data.Encode()),
 	})
 
 	// Отправляем POST-запрос
 	s.logger.Info("Sending POST request to Telegram API")
-	resp, err := s.client.Post(apiURL, "application/x-www-form-urlencoded", strings.NewReader(data.Encode()))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL, strings.NewReader(data.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := s.client.Do(req)
 	if err != nil {
 		s.logger.Error("POST request failed", err, map[string]interface{}{
 			"chat_id": telegramID,
@@ -217,10 +361,13 @@ func (s *TelegramSender) SendMessage(telegramID, message string) error {
 		if readErr != nil {
 			s.logger.Error("Failed to read response body", readErr)
 		}
+		if resp.StatusCode == http.StatusTooManyRequests {
+			return circuit.Exclude(newTelegramRetryError(resp.Status, body))
+		}
 		s.logger.Error("Telegram API returned non-OK status", fmt.Errorf(resp.Status), map[string]interface{}{
 			"response_body": string(body),
 		})
-		return fmt.Errorf("telegram API returned non-OK status: %s, body: %s", resp.Status, string(body))
+		return deliveryStatusError(resp.StatusCode, fmt.Errorf("telegram API returned non-OK status: %s, body: %s", resp.Status, string(body)))
 	}
 
 	// Разбираем ответ
@@ -234,7 +381,7 @@ func (s *TelegramSender) SendMessage(telegramID, message string) error {
 	// Проверяем успешность операции
 	if !telegramResp.Ok {
 		s.logger.Error("Telegram API returned error in response", fmt.Errorf(telegramResp.Description))
-		return fmt.Errorf("telegram API returned error: %s", telegramResp.Description)
+		return circuit.Exclude(fmt.Errorf("telegram API returned error: %s", telegramResp.Description))
 	}
 
 	s.logger.Info("Message sent successfully to Telegram", map[string]interface{}{
@@ -243,6 +390,50 @@ func (s *TelegramSender) SendMessage(telegramID, message string) error {
 	return nil
 }
 
+// AnswerCallbackQuery отвечает на нажатие кнопки встроенной клавиатуры:
+// Telegram показывает текст ответа пользователю и снимает индикатор загрузки с кнопки
+func (s *TelegramSender) AnswerCallbackQuery(ctx context.Context, callbackID, text string) error {
+	apiURL := fmt.Sprintf("%s%s/answerCallbackQuery", s.apiBaseURL, s.botToken)
+
+	data := url.Values{}
+	data.Set("callback_query_id", callbackID)
+	data.Set("text", text)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL, strings.NewReader(data.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	return s.breaker.Do(func() error {
+		resp, err := s.client.Do(req)
+		if err != nil {
+			return fmt.Errorf("post request failed: %w", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			body, _ := ioutil.ReadAll(resp.Body)
+			return deliveryStatusError(resp.StatusCode, fmt.Errorf("telegram API returned non-OK status: %s, body: %s", resp.Status, string(body)))
+		}
+		return nil
+	})
+}
+
+// newTelegramRetryError разбирает время повтора из ответа 429
+func newTelegramRetryError(status string, body []byte) *telegramRetryError {
+	retryAfter := time.Second
+	var telegramResp TelegramResponse
+	if err := json.Unmarshal(body, &telegramResp); err == nil && telegramResp.Parameters != nil && telegramResp.Parameters.RetryAfter > 0 {
+		retryAfter = time.Duration(telegramResp.Parameters.RetryAfter) * time.Second
+	}
+
+	return &telegramRetryError{
+		retryAfter: retryAfter,
+		err:        fmt.Errorf("telegram API returned non-OK status: %s, body: %s", status, string(body)),
+	}
+}
+
 // getTelegramID получает Telegram ID пользователя из его данных
 func (s *TelegramSender) getTelegramID(user *domain.User) (string, bool) {
 	// Поскольку в модели User нет поля MetaData, можно:
@@ -268,117 +459,6 @@ func (s *TelegramSender) getTelegramID(user *domain.User) (string, bool) {
 	return "", false
 }
 
-// formatMessage форматирует сообщение в зависимости от типа уведомления
-func (s *TelegramSender) formatMessage(notification *domain.Notification, user *domain.User) string {
-	// Базовое сообщение
-	message := fmt.Sprintf("*%s*\n\n%s\n",
-		escapeMarkdown(notification.Title),
-		escapeMarkdown(notification.Content))
-
-	// Добавляем дополнительную информацию в зависимости от типа уведомления
-	if notification.MetaData != nil {
-		switch notification.Type {
-		case domain.NotificationTypeTaskAssigned:
-			if taskTitle, ok := notification.MetaData["task_title"]; ok {
-				message += fmt.Sprintf("\n*Задача:* %s", escapeMarkdown(taskTitle))
-			}
-			if priority, ok := notification.MetaData["priority"]; ok {
-				message += fmt.Sprintf("\n*Приоритет:* %s", escapeMarkdown(priority))
-			}
-			if dueDate, ok := notification.MetaData["due_date"]; ok {
-				message += fmt.Sprintf("\n*Срок выполнения:* %s", escapeMarkdown(dueDate))
-			}
-
-		case domain.NotificationTypeTaskUpdated:
-			if taskTitle, ok := notification.MetaData["task_title"]; ok {
-				message += fmt.Sprintf("\n*Задача:* %s", escapeMarkdown(taskTitle))
-			}
-			if status, ok := notification.MetaData["status"]; ok {
-				message += fmt.Sprintf("\n*Статус:* %s", escapeMarkdown(status))
-			}
-			if assigneeName, ok := notification.MetaData["assignee_name"]; ok {
-				message += fmt.Sprintf("\n*Исполнитель:* %s", escapeMarkdown(assigneeName))
-			}
-
-		case domain.NotificationTypeTaskCommented:
-			if taskTitle, ok := notification.MetaData["task_title"]; ok {
-				message += fmt.Sprintf("\n*Задача:* %s", escapeMarkdown(taskTitle))
-			}
-			if userName, ok := notification.MetaData["user_name"]; ok {
-				message += fmt.Sprintf("\n*Автор комментария:* %s", escapeMarkdown(userName))
-			}
-			if commentContent, ok := notification.MetaData["comment_content"]; ok {
-				message += fmt.Sprintf("\n*Комментарий:* %s", escapeMarkdown(commentContent))
-			}
-
-		case domain.NotificationTypeTaskDueSoon:
-			if taskTitle, ok := notification.MetaData["task_title"]; ok {
-				message += fmt.Sprintf("\n*Задача:* %s", escapeMarkdown(taskTitle))
-			}
-			if dueDate, ok := notification.MetaData["due_date"]; ok {
-				message += fmt.Sprintf("\n*Срок выполнения:* %s", escapeMarkdown(dueDate))
-			}
-			if hoursLeft, ok := notification.MetaData["hours_left"]; ok {
-				message += fmt.Sprintf("\n*Осталось времени:* %s часов", escapeMarkdown(hoursLeft))
-			}
-
-		case domain.NotificationTypeTaskOverdue:
-			if taskTitle, ok := notification.MetaData["task_title"]; ok {
-				message += fmt.Sprintf("\n*Задача:* %s", escapeMarkdown(taskTitle))
-			}
-			if dueDate, ok := notification.MetaData["due_date"]; ok {
-				message += fmt.Sprintf("\n*Срок выполнения истек:* %s", escapeMarkdown(dueDate))
-			}
-
-		case domain.NotificationTypeProjectMemberAdded:
-			if projectName, ok := notification.MetaData["project_name"]; ok {
-				message += fmt.Sprintf("\n*Проект:* %s", escapeMarkdown(projectName))
-			}
-			if role, ok := notification.MetaData["role"]; ok {
-				message += fmt.Sprintf("\n*Роль:* %s", escapeMarkdown(role))
-			}
-
-		case domain.NotificationTypeProjectUpdated:
-			if projectName, ok := notification.MetaData["project_name"]; ok {
-				message += fmt.Sprintf("\n*Проект:* %s", escapeMarkdown(projectName))
-			}
-			if status, ok := notification.MetaData["status"]; ok {
-				message += fmt.Sprintf("\n*Статус:* %s", escapeMarkdown(status))
-			}
-		}
-	}
-
-	// Добавляем дату/время
-	message += fmt.Sprintf("\n\n_Отправлено: %s_", notification.CreatedAt.Format("02.01.2006 15:04"))
-
-	return message
-}
-
-// escapeMarkdown экранирует специальные символы Markdown
-func escapeMarkdown(text string) string {
-	replacer := strings.NewReplacer(
-		"_", "\\_",
-		"*", "\\*",
-		"[", "\\[",
-		"]", "\\]",
-		"(", "\\(",
-		")", "\\)",
-		"~", "\\~",
-		"`", "\\`",
-		">", "\\>",
-		"#", "\\#",
-		"+", "\\+",
-		"-", "\\-",
-		"=", "\\=",
-		"|", "\\|",
-		"{", "\\{",
-		"}", "\\}",
-		".", "\\.",
-		"!", "\\!",
-	)
-	return replacer.Replace(text)
-}
-
 // RegisterUserTelegram регистрирует ассоциацию пользователя с Telegram
 func (s *TelegramSender) RegisterUserTelegram(userID, telegramID string) error {
 	// Здесь должна быть логика для сохранения ассоциации пользователя с Telegram ID
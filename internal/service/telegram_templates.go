@@ -0,0 +1,197 @@
+package service
+
+import (
+	"net/url"
+	"strings"
+	"text/template"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+)
+
+// telegramParseMode - режим разметки сообщений с уведомлениями
+const telegramParseMode = "MarkdownV2"
+
+// TelegramCallbackMarkDone - префикс данных кнопки "Отметить выполненной", за ним следует ID задачи
+const TelegramCallbackMarkDone = "done:"
+
+// telegramMarkdownEscaper экранирует символы, зарезервированные в MarkdownV2.
+// Обратная косая черта экранируется первой, чтобы не задеть добавленные экраны
+var telegramMarkdownEscaper = strings.NewReplacer(
+	"\\", "\\\\",
+	"_", "\\_",
+	"*", "\\*",
+	"[", "\\[",
+	"]", "\\]",
+	"(", "\\(",
+	")", "\\)",
+	"~", "\\~",
+	"`", "\\`",
+	">", "\\>",
+	"#", "\\#",
+	"+", "\\+",
+	"-", "\\-",
+	"=", "\\=",
+	"|", "\\|",
+	"{", "\\{",
+	"}", "\\}",
+	".", "\\.",
+	"!", "\\!",
+)
+
+// escapeMarkdownV2 экранирует текст для вставки в сообщение MarkdownV2
+func escapeMarkdownV2(text string) string {
+	return telegramMarkdownEscaper.Replace(text)
+}
+
+// telegramLayout - общий вид сообщения с уведомлением. Блок "fields" задается шаблоном типа
+// уведомления; все значения проходят через md, статический текст шаблонов уже экранирован
+const telegramLayout = `*{{md .Title}}*
+
+{{md .Content}}
+{{template "fields" .}}
+
+_Отправлено: {{md .SentAt}}_`
+
+// telegramFieldTemplates содержит блоки полей сообщений по типам уведомлений
+var telegramFieldTemplates = map[domain.NotificationType]string{
+	domain.NotificationTypeTaskAssigned: `
+{{- with index .Meta "task_title"}}
+*Задача:* {{md .}}{{end}}
+{{- with index .Meta "priority"}}
+*Приоритет:* {{md .}}{{end}}
+{{- with index .Meta "due_date"}}
+*Срок выполнения:* {{md .}}{{end}}`,
+	domain.NotificationTypeTaskUpdated: `
+{{- with index .Meta "task_title"}}
+*Задача:* {{md .}}{{end}}
+{{- with index .Meta "status"}}
+*Статус:* {{md .}}{{end}}
+{{- with index .Meta "assignee_name"}}
+*Исполнитель:* {{md .}}{{end}}`,
+	domain.NotificationTypeTaskCommented: `
+{{- with index .Meta "task_title"}}
+*Задача:* {{md .}}{{end}}
+{{- with index .Meta "user_name"}}
+*Автор комментария:* {{md .}}{{end}}
+{{- with index .Meta "comment_content"}}
+*Комментарий:* {{md .}}{{end}}`,
+	domain.NotificationTypeTaskDueSoon: `
+{{- with index .Meta "task_title"}}
+*Задача:* {{md .}}{{end}}
+{{- with index .Meta "due_date"}}
+*Срок выполнения:* {{md .}}{{end}}
+{{- with index .Meta "hours_left"}}
+*Осталось времени:* {{md .}} часов{{end}}`,
+	domain.NotificationTypeTaskOverdue: `
+{{- with index .Meta "task_title"}}
+*Задача:* {{md .}}{{end}}
+{{- with index .Meta "due_date"}}
+*Срок выполнения истек:* {{md .}}{{end}}`,
+	domain.NotificationTypeProjectMemberAdded: `
+{{- with index .Meta "project_name"}}
+*Проект:* {{md .}}{{end}}
+{{- with index .Meta "role"}}
+*Роль:* {{md .}}{{end}}`,
+	domain.NotificationTypeProjectUpdated: `
+{{- with index .Meta "project_name"}}
+*Проект:* {{md .}}{{end}}
+{{- with index .Meta "status"}}
+*Статус:* {{md .}}{{end}}`,
+}
+
+// telegramTemplateFuncs - функции, доступные в шаблонах сообщений
+var telegramTemplateFuncs = template.FuncMap{
+	"md": escapeMarkdownV2,
+}
+
+// telegramTemplates содержит разобранные шаблоны сообщений по типам уведомлений;
+// telegramDefaultTemplate используется для типов без собственного шаблона
+var (
+	telegramDefaultTemplate = parseTelegramTemplate("")
+	telegramTemplates       = func() map[domain.NotificationType]*template.Template {
+		templates := make(map[domain.NotificationType]*template.Template, len(telegramFieldTemplates))
+		for notificationType, fields := range telegramFieldTemplates {
+			templates[notificationType] = parseTelegramTemplate(fields)
+		}
+		return templates
+	}()
+)
+
+// parseTelegramTemplate собирает шаблон сообщения из общего вида и блока полей
+func parseTelegramTemplate(fields string) *template.Template {
+	tmpl := template.Must(template.New("telegram").Funcs(telegramTemplateFuncs).Parse(telegramLayout))
+	template.Must(tmpl.New("fields").Parse(fields))
+	return tmpl
+}
+
+// telegramTemplateData - данные, подставляемые в шаблон сообщения
+type telegramTemplateData struct {
+	Title   string
+	Content string
+	Meta    map[string]string
+	SentAt  string
+}
+
+// TelegramInlineButton представляет кнопку встроенной клавиатуры сообщения
+type TelegramInlineButton struct {
+	Text         string `json:"text"`
+	URL          string `json:"url,omitempty"`
+	CallbackData string `json:"callback_data,omitempty"`
+}
+
+// TelegramInlineKeyboard представляет встроенную клавиатуру (reply_markup) сообщения
+type TelegramInlineKeyboard struct {
+	InlineKeyboard [][]TelegramInlineButton `json:"inline_keyboard"`
+}
+
+// formatMessage форматирует сообщение по шаблону типа уведомления.
+// При ошибке шаблона отправляются только заголовок и текст уведомления
+func (s *TelegramSender) formatMessage(notification *domain.Notification) string {
+	tmpl, ok := telegramTemplates[notification.Type]
+	if !ok {
+		tmpl = telegramDefaultTemplate
+	}
+
+	data := telegramTemplateData{
+		Title:   notification.Title,
+		Content: notification.Content,
+		Meta:    notification.MetaData,
+		SentAt:  notification.CreatedAt.Format("02.01.2006 15:04"),
+	}
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		s.logger.Error("Failed to render Telegram message template", err, map[string]interface{}{
+			"notification_type": notification.Type,
+		})
+		return "*" + escapeMarkdownV2(notification.Title) + "*\n\n" + escapeMarkdownV2(notification.Content)
+	}
+	return b.String()
+}
+
+// notificationKeyboard возвращает кнопки для уведомления о задаче: ссылку на задачу
+// и отметку о выполнении. Для остальных уведомлений клавиатура не добавляется
+func (s *TelegramSender) notificationKeyboard(notification *domain.Notification) *TelegramInlineKeyboard {
+	if notification.EntityType != "task" || notification.EntityID == "" {
+		return nil
+	}
+
+	var row []TelegramInlineButton
+	if s.baseURL != "" {
+		row = append(row, TelegramInlineButton{
+			Text: "Открыть задачу",
+			URL:  s.baseURL + "/tasks/" + url.PathEscape(notification.EntityID),
+		})
+	}
+	if s.webhookSecret != "" {
+		row = append(row, TelegramInlineButton{
+			Text:         "Отметить выполненной",
+			CallbackData: TelegramCallbackMarkDone + notification.EntityID,
+		})
+	}
+	if len(row) == 0 {
+		return nil
+	}
+
+	return &TelegramInlineKeyboard{InlineKeyboard: [][]TelegramInlineButton{row}}
+}
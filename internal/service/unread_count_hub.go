@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/internal/repository/cache"
+	"github.com/nurlyy/task_manager/pkg/logger"
+)
+
+// unreadCountResubscribeDelay задает паузу перед повторной подпиской после ошибки Redis
+const unreadCountResubscribeDelay = 5 * time.Second
+
+// UnreadCountHub доставляет изменения счетчиков непрочитанных уведомлений открытым потокам
+// клиентов этого экземпляра API. Экземпляр держит одну подписку на канал Redis и раздает
+// события подписчикам по ID пользователя
+type UnreadCountHub struct {
+	cacheRepo *cache.RedisRepository
+	logger    logger.Logger
+
+	mu          sync.Mutex
+	subscribers map[string]map[chan int]struct{}
+}
+
+// NewUnreadCountHub создает новый экземпляр UnreadCountHub
+func NewUnreadCountHub(cacheRepo *cache.RedisRepository, logger logger.Logger) *UnreadCountHub {
+	return &UnreadCountHub{
+		cacheRepo:   cacheRepo,
+		logger:      logger,
+		subscribers: make(map[string]map[chan int]struct{}),
+	}
+}
+
+// Start подписывается на изменения счетчиков в фоне до отмены ctx
+func (h *UnreadCountHub) Start(ctx context.Context) {
+	go func() {
+		for {
+			err := h.cacheRepo.SubscribeUnreadCounts(ctx, h.dispatch)
+			if ctx.Err() != nil {
+				return
+			}
+			h.logger.Warn("Unread count subscription stopped", map[string]interface{}{
+				"retry_in": unreadCountResubscribeDelay.String(),
+			}, map[string]interface{}{
+				"error": err,
+			})
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(unreadCountResubscribeDelay):
+			}
+		}
+	}()
+}
+
+// Subscribe возвращает канал с новыми значениями счетчика пользователя и функцию отписки.
+// Канал хранит только последнее недоставленное значение, поэтому медленный клиент
+// пропускает промежуточные значения, но не задерживает остальных
+func (h *UnreadCountHub) Subscribe(userID string) (<-chan int, func()) {
+	ch := make(chan int, 1)
+
+	h.mu.Lock()
+	if h.subscribers[userID] == nil {
+		h.subscribers[userID] = make(map[chan int]struct{})
+	}
+	h.subscribers[userID][ch] = struct{}{}
+	h.mu.Unlock()
+
+	unsubscribe := func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		delete(h.subscribers[userID], ch)
+		if len(h.subscribers[userID]) == 0 {
+			delete(h.subscribers, userID)
+		}
+	}
+	return ch, unsubscribe
+}
+
+// dispatch передает значение счетчика всем подписчикам пользователя
+func (h *UnreadCountHub) dispatch(event *domain.UnreadCountEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for ch := range h.subscribers[event.UserID] {
+		// Недоставленное прежнее значение заменяется новым
+		select {
+		case <-ch:
+		default:
+		}
+		ch <- event.Count
+	}
+}
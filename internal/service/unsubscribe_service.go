@@ -0,0 +1,183 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"html/template"
+	"net/url"
+	"strings"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/internal/repository"
+	"github.com/nurlyy/task_manager/pkg/auth"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
+	"github.com/nurlyy/task_manager/pkg/logger"
+)
+
+// Стандартные ошибки
+var (
+	ErrInvalidUnsubscribeLink = apperrors.New(apperrors.CodeInvalidLink, "invalid or expired unsubscribe link")
+)
+
+// unsubscribePath определяет путь публичного обработчика отписки
+const unsubscribePath = "/api/v1/unsubscribe"
+
+// UnsubscribeResult представляет результат отписки от email-уведомлений
+type UnsubscribeResult struct {
+	UserID           string                  `json:"user_id"`
+	NotificationType domain.NotificationType `json:"notification_type"`
+	EmailEnabled     bool                    `json:"email_enabled"`
+}
+
+// UnsubscribeService представляет бизнес-логику для отписки от email-уведомлений по подписанным ссылкам
+type UnsubscribeService struct {
+	notificationRepo repository.NotificationRepository
+	manager          *auth.UnsubscribeManager
+	baseURL          string
+	logger           logger.Logger
+}
+
+// NewUnsubscribeService создает новый экземпляр UnsubscribeService
+func NewUnsubscribeService(
+	notificationRepo repository.NotificationRepository,
+	manager *auth.UnsubscribeManager,
+	baseURL string,
+	logger logger.Logger,
+) *UnsubscribeService {
+	return &UnsubscribeService{
+		notificationRepo: notificationRepo,
+		manager:          manager,
+		baseURL:          strings.TrimRight(baseURL, "/"),
+		logger:           logger,
+	}
+}
+
+// BuildLink формирует подписанную ссылку для отписки от уведомлений указанного типа
+func (s *UnsubscribeService) BuildLink(userID string, notificationType domain.NotificationType) (string, error) {
+	token, err := s.manager.GenerateToken(userID, string(notificationType))
+	if err != nil {
+		s.logger.Error("Failed to generate unsubscribe token", err, map[string]interface{}{
+			"user_id": userID,
+			"type":    notificationType,
+		})
+		return "", err
+	}
+
+	return s.baseURL + unsubscribePath + "?token=" + url.QueryEscape(token), nil
+}
+
+// unsubscribeHeaders возвращает заголовки List-Unsubscribe для готовой ссылки отписки (RFC 2369, RFC 8058).
+// Почтовый клиент отправляет по ссылке POST с телом List-Unsubscribe=One-Click
+func unsubscribeHeaders(link string) map[string]string {
+	return map[string]string{
+		"List-Unsubscribe":      "<" + link + ">",
+		"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+	}
+}
+
+// Check проверяет подписанный токен отписки, не меняя настроек, и возвращает тип уведомлений,
+// от которых отпишет ссылка
+func (s *UnsubscribeService) Check(token string) (domain.NotificationType, error) {
+	claims, err := s.manager.VerifyToken(token)
+	if err != nil {
+		s.logger.Warn("Invalid unsubscribe token", map[string]interface{}{
+			"error": err,
+		})
+		return "", ErrInvalidUnsubscribeLink
+	}
+
+	return domain.NotificationType(claims.NotificationType), nil
+}
+
+// RenderConfirmHTML формирует страницу подтверждения отписки. Переход по ссылке из письма
+// только показывает страницу: настройки меняет отправка формы методом POST, поэтому
+// почтовые сканеры и предзагрузка ссылок не отписывают пользователя
+func (s *UnsubscribeService) RenderConfirmHTML(token string, notificationType domain.NotificationType) ([]byte, error) {
+	return renderUnsubscribePage(unsubscribePage{
+		Action:           unsubscribePath + "?token=" + url.QueryEscape(token),
+		NotificationType: notificationType,
+	})
+}
+
+// RenderDoneHTML формирует страницу с результатом отписки
+func (s *UnsubscribeService) RenderDoneHTML(result *UnsubscribeResult) ([]byte, error) {
+	return renderUnsubscribePage(unsubscribePage{
+		NotificationType: result.NotificationType,
+		Done:             true,
+	})
+}
+
+// unsubscribePage - данные страницы отписки
+type unsubscribePage struct {
+	Action           string
+	NotificationType domain.NotificationType
+	Done             bool
+}
+
+// unsubscribeTemplate формирует страницу подтверждения и результата отписки
+var unsubscribeTemplate = template.Must(template.New("unsubscribe").Parse(`<!DOCTYPE html>
+<html lang="ru">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex">
+<title>Отписка от уведомлений</title>
+<style>
+body{font-family:sans-serif;max-width:560px;margin:2rem auto;padding:0 1rem;color:#222}
+button{padding:.5rem 1rem;font-size:1rem;cursor:pointer}
+.muted{color:#777;font-size:.85rem}
+</style>
+</head>
+<body>
+<h1>Отписка от уведомлений</h1>
+{{if .Done}}<p>Вы больше не будете получать письма с уведомлениями этого типа.</p>
+{{else}}<p>Отключить письма с уведомлениями этого типа?</p>
+<form method="post" action="{{.Action}}"><button type="submit">Отписаться</button></form>
+{{end}}<p class="muted">Тип уведомлений: {{.NotificationType}}</p>
+</body>
+</html>
+`))
+
+// renderUnsubscribePage формирует HTML-страницу отписки
+func renderUnsubscribePage(page unsubscribePage) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := unsubscribeTemplate.Execute(&buf, page); err != nil {
+		return nil, fmt.Errorf("failed to render unsubscribe page: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Unsubscribe отключает email-уведомления по подписанному токену без аутентификации пользователя
+func (s *UnsubscribeService) Unsubscribe(ctx context.Context, token string) (*UnsubscribeResult, error) {
+	// Проверяем подпись и срок действия токена
+	claims, err := s.manager.VerifyToken(token)
+	if err != nil {
+		s.logger.Warn("Invalid unsubscribe token", map[string]interface{}{
+			"error": err,
+		})
+		return nil, ErrInvalidUnsubscribeLink
+	}
+
+	notificationType := domain.NotificationType(claims.NotificationType)
+
+	// Отключаем email-уведомления указанного типа
+	if err := s.notificationRepo.SetEmailEnabled(ctx, claims.UserID, notificationType, false); err != nil {
+		s.logger.Error("Failed to disable email notifications", err, map[string]interface{}{
+			"user_id": claims.UserID,
+			"type":    notificationType,
+		})
+		return nil, err
+	}
+
+	s.logger.Info("User unsubscribed from email notifications", map[string]interface{}{
+		"user_id": claims.UserID,
+		"type":    notificationType,
+	})
+
+	return &UnsubscribeResult{
+		UserID:           claims.UserID,
+		NotificationType: notificationType,
+		EmailEnabled:     false,
+	}, nil
+}
@@ -2,9 +2,9 @@ package service
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"math/rand"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -14,15 +14,18 @@ import (
 	"github.com/nurlyy/task_manager/internal/repository"
 	"github.com/nurlyy/task_manager/internal/repository/cache"
 	"github.com/nurlyy/task_manager/pkg/auth"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
 	"github.com/nurlyy/task_manager/pkg/logger"
 )
 
 // Стандартные ошибки
 var (
-	ErrUserNotFound       = errors.New("user not found")
-	ErrEmailAlreadyExists = errors.New("email already exists")
-	ErrInvalidCredentials = errors.New("invalid credentials")
-	ErrInvalidPassword    = errors.New("invalid password")
+	ErrUserNotFound       = apperrors.New(apperrors.CodeUserNotFound, "user not found")
+	ErrEmailAlreadyExists = apperrors.New(apperrors.CodeEmailExists, "email already exists")
+	ErrInvalidCredentials = apperrors.New(apperrors.CodeInvalidCredentials, "invalid credentials")
+	ErrInvalidPassword    = apperrors.New(apperrors.CodeInvalidPassword, "invalid password")
+	ErrManagerNotFound    = apperrors.New(apperrors.CodeManagerNotFound, "manager not found")
+	ErrManagerCycle       = apperrors.New(apperrors.CodeManagerCycle, "manager assignment creates a reporting cycle")
 )
 
 // UserService представляет бизнес-логику для работы с пользователями
@@ -62,18 +65,19 @@ func (s *UserService) Create(ctx context.Context, req domain.UserCreateRequest)
 	// Создаем нового пользователя
 	now := time.Now()
 	user := &domain.User{
-		ID:             uuid.New().String(),
-		Email:          req.Email,
-		HashedPassword: string(hashedPassword),
-		FirstName:      req.FirstName,
-		LastName:       req.LastName,
-		Role:           req.Role,
-		Position:       req.Position,
-		Department:     req.Department,
-		Avatar:         req.Avatar,
-		IsActive:       true,
-		CreatedAt:      now,
-		UpdatedAt:      now,
+		ID:                  uuid.New().String(),
+		Email:               req.Email,
+		HashedPassword:      string(hashedPassword),
+		FirstName:           req.FirstName,
+		LastName:            req.LastName,
+		Role:                req.Role,
+		Position:            req.Position,
+		Department:          req.Department,
+		Avatar:              req.Avatar,
+		IsActive:            true,
+		WeeklyCapacityHours: domain.DefaultWeeklyCapacityHours,
+		CreatedAt:           now,
+		UpdatedAt:           now,
 	}
 
 	// Сохраняем пользователя в БД
@@ -106,6 +110,17 @@ func (s *UserService) GetByID(ctx context.Context, id string) (*domain.UserRespo
 		return nil, ErrUserNotFound
 	}
 
+	// Получаем навыки пользователя
+	skills, err := s.repo.GetSkills(ctx, id)
+	if err != nil {
+		s.logger.Warn("Failed to get user skills", map[string]interface{}{
+			"id": id,
+		}, map[string]interface{}{
+			"error": err,
+		})
+	}
+	user.Skills = skills
+
 	// Сохраняем в кэш
 	userResp = user.ToResponse()
 	if err := s.cacheRepo.Set(ctx, cacheKey, userResp); err != nil {
@@ -169,6 +184,9 @@ func (s *UserService) Update(ctx context.Context, id string, req domain.UserUpda
 	if req.IsActive != nil {
 		user.IsActive = *req.IsActive
 	}
+	if req.WeeklyCapacityHours != nil {
+		user.WeeklyCapacityHours = *req.WeeklyCapacityHours
+	}
 
 	user.UpdatedAt = time.Now()
 
@@ -180,6 +198,19 @@ func (s *UserService) Update(ctx context.Context, id string, req domain.UserUpda
 		return nil, err
 	}
 
+	// Обновляем навыки, если они были переданы
+	if req.Skills != nil {
+		user.Skills = normalizeSkills(*req.Skills)
+		if err := s.repo.UpdateSkills(ctx, id, user.Skills); err != nil {
+			s.logger.Error("Failed to update user skills", err, map[string]interface{}{
+				"id": id,
+			})
+			return nil, err
+		}
+	} else if skills, err := s.repo.GetSkills(ctx, id); err == nil {
+		user.Skills = skills
+	}
+
 	// Удаляем пользователя из кэша
 	cacheKey := "user:" + id
 	if err := s.cacheRepo.Delete(ctx, cacheKey); err != nil {
@@ -228,10 +259,10 @@ func (s *UserService) Delete(ctx context.Context, id string) error {
 }
 
 // List возвращает список пользователей с фильтрацией
-func (s *UserService) List(ctx context.Context, filter repository.UserFilter, page, pageSize int) (*domain.PagedResponse, error) {
+func (s *UserService) List(ctx context.Context, filter repository.UserFilter, page domain.PageRequest) (*domain.PagedResponse, error) {
 	// Настраиваем пагинацию
-	filter.Limit = pageSize
-	filter.Offset = (page - 1) * pageSize
+	filter.Limit = page.Limit()
+	filter.Offset = page.Offset()
 
 	// Получаем список пользователей
 	users, err := s.repo.List(ctx, filter)
@@ -240,11 +271,16 @@ func (s *UserService) List(ctx context.Context, filter repository.UserFilter, pa
 		return nil, err
 	}
 
-	// Получаем общее количество пользователей
-	total, err := s.repo.Count(ctx, filter)
-	if err != nil {
-		s.logger.Error("Failed to count users", err)
-		return nil, err
+	users, hasMore := domain.PageItems(users, page)
+
+	// Получаем общее количество пользователей, если клиент не отказался от подсчета
+	total := 0
+	if !page.SkipCount {
+		total, err = s.repo.Count(ctx, filter)
+		if err != nil {
+			s.logger.Error("Failed to count users", err)
+			return nil, err
+		}
 	}
 
 	// Преобразуем к UserResponse
@@ -254,13 +290,191 @@ func (s *UserService) List(ctx context.Context, filter repository.UserFilter, pa
 	}
 
 	// Формируем ответ с пагинацией
-	return &domain.PagedResponse{
-		Items:      userResponses,
-		TotalItems: total,
-		Page:       page,
-		PageSize:   pageSize,
-		TotalPages: (total + pageSize - 1) / pageSize,
-	}, nil
+	return domain.NewPagedResponse(userResponses, page, total, hasMore), nil
+}
+
+// Directory возвращает справочник активных пользователей с навыками и свободным временем
+func (s *UserService) Directory(ctx context.Context, filter repository.UserDirectoryFilter, page domain.PageRequest) (*domain.PagedResponse, error) {
+	// Настраиваем пагинацию
+	filter.Limit = page.Limit()
+	filter.Offset = page.Offset()
+	filter.Skills = normalizeSkills(filter.Skills)
+
+	// Получаем пользователей с их загрузкой
+	users, err := s.repo.ListDirectory(ctx, filter)
+	if err != nil {
+		s.logger.Error("Failed to list user directory", err)
+		return nil, err
+	}
+
+	users, hasMore := domain.PageItems(users, page)
+
+	// Получаем общее количество пользователей, если клиент не отказался от подсчета
+	total := 0
+	if !page.SkipCount {
+		total, err = s.repo.CountDirectory(ctx, filter)
+		if err != nil {
+			s.logger.Error("Failed to count user directory", err)
+			return nil, err
+		}
+	}
+
+	// Получаем навыки всех найденных пользователей одним запросом
+	userIDs := make([]string, len(users))
+	for i, user := range users {
+		userIDs[i] = user.ID
+	}
+	skills, err := s.repo.GetSkillsByUserIDs(ctx, userIDs)
+	if err != nil {
+		s.logger.Error("Failed to get skills for directory", err)
+		return nil, err
+	}
+
+	// Формируем записи справочника
+	entries := make([]domain.UserDirectoryEntry, len(users))
+	for i, user := range users {
+		user.Skills = skills[user.ID]
+		freeHours := user.WeeklyCapacityHours - user.WorkloadHours
+		if freeHours < 0 {
+			freeHours = 0
+		}
+		entries[i] = domain.UserDirectoryEntry{
+			UserResponse:  user.ToResponse(),
+			OpenTasks:     user.OpenTasks,
+			WorkloadHours: user.WorkloadHours,
+			FreeHours:     freeHours,
+		}
+	}
+
+	// Формируем ответ с пагинацией
+	return domain.NewPagedResponse(entries, page, total, hasMore), nil
+}
+
+// SetManager назначает пользователю руководителя или снимает его, если managerID равен nil
+func (s *UserService) SetManager(ctx context.Context, userID string, managerID *string) (*domain.UserResponse, error) {
+	// Проверяем, существует ли пользователь
+	user, err := s.repo.GetByID(ctx, userID)
+	if err != nil || user == nil {
+		return nil, ErrUserNotFound
+	}
+
+	if managerID != nil {
+		// Пользователь не может быть руководителем самому себе
+		if *managerID == userID {
+			return nil, ErrManagerCycle
+		}
+
+		// Проверяем, существует ли руководитель
+		manager, err := s.repo.GetByID(ctx, *managerID)
+		if err != nil || manager == nil || !manager.IsActive {
+			return nil, ErrManagerNotFound
+		}
+
+		// Пользователь не должен входить в цепочку руководителей нового руководителя
+		chain, err := s.repo.GetReportingChain(ctx, *managerID)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range chain {
+			if m.ID == userID {
+				return nil, ErrManagerCycle
+			}
+		}
+	}
+
+	// Сохраняем руководителя
+	if err := s.repo.UpdateManager(ctx, userID, managerID); err != nil {
+		s.logger.Error("Failed to update user manager", err, map[string]interface{}{
+			"id": userID,
+		})
+		return nil, err
+	}
+
+	// Удаляем пользователя из кэша
+	cacheKey := "user:" + userID
+	if err := s.cacheRepo.Delete(ctx, cacheKey); err != nil {
+		s.logger.Warn("Failed to delete user from cache", map[string]interface{}{
+			"id": userID,
+		}, map[string]interface{}{
+			"error": err,
+		})
+	}
+
+	user.ManagerID = managerID
+	response := user.ToResponse()
+	return &response, nil
+}
+
+// GetManager возвращает непосредственного руководителя пользователя или nil, если он не назначен
+func (s *UserService) GetManager(ctx context.Context, userID string) (*domain.User, error) {
+	user, err := s.repo.GetByID(ctx, userID)
+	if err != nil || user == nil {
+		return nil, ErrUserNotFound
+	}
+
+	if user.ManagerID == nil {
+		return nil, nil
+	}
+
+	manager, err := s.repo.GetByID(ctx, *user.ManagerID)
+	if err != nil {
+		s.logger.Error("Failed to get user manager", err, map[string]interface{}{
+			"id":         userID,
+			"manager_id": *user.ManagerID,
+		})
+		return nil, err
+	}
+
+	return manager, nil
+}
+
+// GetReportingChain возвращает цепочку руководителей пользователя снизу вверх
+func (s *UserService) GetReportingChain(ctx context.Context, userID string) ([]domain.ReportingChainEntry, error) {
+	// Проверяем, существует ли пользователь
+	if user, err := s.repo.GetByID(ctx, userID); err != nil || user == nil {
+		return nil, ErrUserNotFound
+	}
+
+	managers, err := s.repo.GetReportingChain(ctx, userID)
+	if err != nil {
+		s.logger.Error("Failed to get reporting chain", err, map[string]interface{}{
+			"id": userID,
+		})
+		return nil, err
+	}
+
+	chain := make([]domain.ReportingChainEntry, len(managers))
+	for i, manager := range managers {
+		chain[i] = domain.ReportingChainEntry{
+			Level: i + 1,
+			User:  manager.ToResponse(),
+		}
+	}
+
+	return chain, nil
+}
+
+// GetReports возвращает подчиненных пользователя; при recursive = true - всех подчиненных по иерархии
+func (s *UserService) GetReports(ctx context.Context, managerID string, recursive bool) ([]domain.UserResponse, error) {
+	// Проверяем, существует ли пользователь
+	if user, err := s.repo.GetByID(ctx, managerID); err != nil || user == nil {
+		return nil, ErrUserNotFound
+	}
+
+	reports, err := s.repo.GetReports(ctx, managerID, recursive)
+	if err != nil {
+		s.logger.Error("Failed to get user reports", err, map[string]interface{}{
+			"id": managerID,
+		})
+		return nil, err
+	}
+
+	responses := make([]domain.UserResponse, len(reports))
+	for i, report := range reports {
+		responses[i] = report.ToResponse()
+	}
+
+	return responses, nil
 }
 
 // Login выполняет вход пользователя
@@ -442,6 +656,21 @@ func (s *UserService) GetUserIDByToken(ctx context.Context, token string) (strin
 	return userID, nil
 }
 
+// normalizeSkills приводит навыки к нижнему регистру и удаляет пустые значения и дубликаты
+func normalizeSkills(skills []string) []string {
+	result := make([]string, 0, len(skills))
+	seen := make(map[string]bool, len(skills))
+	for _, skill := range skills {
+		skill = strings.ToLower(strings.TrimSpace(skill))
+		if skill == "" || seen[skill] {
+			continue
+		}
+		seen[skill] = true
+		result = append(result, skill)
+	}
+	return result
+}
+
 // generateRandomToken генерирует случайный токен указанной длины
 func generateRandomToken(length int) string {
 	const charset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
@@ -0,0 +1,395 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nurlyy/task_manager/internal/domain"
+	"github.com/nurlyy/task_manager/internal/repository"
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
+	"github.com/nurlyy/task_manager/pkg/logger"
+)
+
+var (
+	ErrWikiPageNotFound     = apperrors.New(apperrors.CodeWikiPageNotFound, "wiki page not found")
+	ErrWikiRevisionNotFound = apperrors.New(apperrors.CodeRevisionNotFound, "wiki page revision not found")
+	ErrWikiParentInvalid    = apperrors.New(apperrors.CodeInvalidParent, "invalid parent wiki page")
+	ErrWikiPageConflict     = apperrors.New(apperrors.CodeVersionConflict, "wiki page was changed by another user")
+	ErrWikiTaskInvalid      = apperrors.New(apperrors.CodeInvalidTask, "wiki page can only be linked to tasks of its project")
+	ErrWikiTaskLinked       = apperrors.New(apperrors.CodeTaskAlreadyLinked, "task is already linked to the wiki page")
+)
+
+const (
+	// wikiSearchDefaultLimit определяет количество результатов поиска по вики по умолчанию
+	wikiSearchDefaultLimit = 20
+	// wikiSearchMaxLimit определяет максимальное количество результатов поиска по вики
+	wikiSearchMaxLimit = 100
+)
+
+// WikiService представляет бизнес-логику вики проектов
+type WikiService struct {
+	wikiRepo   repository.WikiRepository
+	taskRepo   repository.TaskRepository
+	projectSvc *ProjectService
+	taskSvc    *TaskService
+	logger     logger.Logger
+}
+
+// NewWikiService создает новый экземпляр WikiService
+func NewWikiService(
+	wikiRepo repository.WikiRepository,
+	taskRepo repository.TaskRepository,
+	projectSvc *ProjectService,
+	taskSvc *TaskService,
+	logger logger.Logger,
+) *WikiService {
+	return &WikiService{
+		wikiRepo:   wikiRepo,
+		taskRepo:   taskRepo,
+		projectSvc: projectSvc,
+		taskSvc:    taskSvc,
+		logger:     logger,
+	}
+}
+
+// Create создает вики-страницу проекта
+func (s *WikiService) Create(ctx context.Context, projectID string, req domain.WikiPageCreateRequest, userID string) (*domain.WikiPage, error) {
+	if err := s.projectSvc.ensureProjectWritable(ctx, projectID); err != nil {
+		return nil, err
+	}
+
+	if req.ParentID != nil {
+		parent, err := s.wikiRepo.GetByID(ctx, *req.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		if parent == nil || parent.ProjectID != projectID {
+			return nil, ErrWikiParentInvalid
+		}
+	}
+
+	taskIDs := uniqueIDs(req.TaskIDs)
+	for _, taskID := range taskIDs {
+		if err := s.checkTask(ctx, projectID, taskID); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	page := &domain.WikiPage{
+		ID:        uuid.New().String(),
+		ProjectID: projectID,
+		ParentID:  req.ParentID,
+		Title:     req.Title,
+		Content:   req.Content,
+		Version:   1,
+		CreatedBy: userID,
+		UpdatedBy: userID,
+		CreatedAt: now,
+		UpdatedAt: now,
+		TaskIDs:   taskIDs,
+	}
+
+	if err := s.wikiRepo.Create(ctx, page); err != nil {
+		s.logger.Error("Failed to create wiki page", err, map[string]interface{}{
+			"project_id": projectID,
+		})
+		return nil, err
+	}
+
+	return page, nil
+}
+
+// GetTree возвращает дерево вики-страниц проекта
+func (s *WikiService) GetTree(ctx context.Context, projectID string) ([]*domain.WikiPageNode, error) {
+	pages, err := s.wikiRepo.ListByProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildWikiTree(pages), nil
+}
+
+// Search выполняет полнотекстовый поиск по вики проекта
+func (s *WikiService) Search(ctx context.Context, projectID string, query string, limit int) ([]*domain.WikiSearchResult, error) {
+	query = normalizeTypeaheadQuery(query)
+	if query == "" {
+		return nil, ErrSearchQueryEmpty
+	}
+
+	if limit <= 0 {
+		limit = wikiSearchDefaultLimit
+	} else if limit > wikiSearchMaxLimit {
+		limit = wikiSearchMaxLimit
+	}
+
+	return s.wikiRepo.Search(ctx, projectID, query, escapeLikePattern(query), limit)
+}
+
+// Get возвращает вики-страницу
+func (s *WikiService) Get(ctx context.Context, id string, userID string) (*domain.WikiPage, error) {
+	return s.getAccessiblePage(ctx, id, userID)
+}
+
+// Update сохраняет правку вики-страницы новой ревизией.
+// Правка, сделанная на основе устаревшей версии, отклоняется
+func (s *WikiService) Update(ctx context.Context, id string, req domain.WikiPageUpdateRequest, userID string) (*domain.WikiPage, error) {
+	page, err := s.getAccessiblePage(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkCanEdit(ctx, page.ProjectID, userID); err != nil {
+		return nil, err
+	}
+
+	if req.Version != page.Version {
+		return nil, ErrWikiPageConflict
+	}
+
+	if req.Title != nil {
+		page.Title = *req.Title
+	}
+	if req.Content != nil {
+		page.Content = *req.Content
+	}
+	if req.MoveToRoot {
+		page.ParentID = nil
+	} else if req.ParentID != nil {
+		if err := s.checkParent(ctx, page, *req.ParentID); err != nil {
+			return nil, err
+		}
+		page.ParentID = req.ParentID
+	}
+
+	return s.save(ctx, page, req.Version, userID)
+}
+
+// Delete удаляет вики-страницу; дочерние страницы переходят к ее родителю.
+// Удалить страницу может ее автор или менеджер проекта
+func (s *WikiService) Delete(ctx context.Context, id string, userID string) error {
+	page, err := s.getAccessiblePage(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+
+	if page.CreatedBy != userID && !s.projectSvc.canManageProject(ctx, page.ProjectID, userID) {
+		return ErrInsufficientRights
+	}
+
+	if err := s.projectSvc.ensureProjectWritable(ctx, page.ProjectID); err != nil {
+		return err
+	}
+
+	return s.wikiRepo.Delete(ctx, id)
+}
+
+// ListRevisions возвращает историю правок вики-страницы
+func (s *WikiService) ListRevisions(ctx context.Context, id string, userID string) ([]*domain.WikiPageRevision, error) {
+	if _, err := s.getAccessiblePage(ctx, id, userID); err != nil {
+		return nil, err
+	}
+
+	return s.wikiRepo.ListRevisions(ctx, id)
+}
+
+// GetRevision возвращает ревизию вики-страницы
+func (s *WikiService) GetRevision(ctx context.Context, id string, version int, userID string) (*domain.WikiPageRevision, error) {
+	if _, err := s.getAccessiblePage(ctx, id, userID); err != nil {
+		return nil, err
+	}
+
+	revision, err := s.wikiRepo.GetRevision(ctx, id, version)
+	if err != nil {
+		return nil, err
+	}
+	if revision == nil {
+		return nil, ErrWikiRevisionNotFound
+	}
+
+	return revision, nil
+}
+
+// RestoreRevision возвращает странице название и содержимое ревизии, сохраняя это новой правкой
+func (s *WikiService) RestoreRevision(ctx context.Context, id string, version int, userID string) (*domain.WikiPage, error) {
+	page, err := s.getAccessiblePage(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkCanEdit(ctx, page.ProjectID, userID); err != nil {
+		return nil, err
+	}
+
+	revision, err := s.wikiRepo.GetRevision(ctx, id, version)
+	if err != nil {
+		return nil, err
+	}
+	if revision == nil {
+		return nil, ErrWikiRevisionNotFound
+	}
+
+	currentVersion := page.Version
+	page.Title = revision.Title
+	page.Content = revision.Content
+
+	return s.save(ctx, page, currentVersion, userID)
+}
+
+// LinkTask связывает вики-страницу с задачей того же проекта
+func (s *WikiService) LinkTask(ctx context.Context, id string, req domain.WikiPageTaskRequest, userID string) (*domain.WikiPage, error) {
+	page, err := s.getAccessiblePage(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkCanEdit(ctx, page.ProjectID, userID); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkTask(ctx, page.ProjectID, req.TaskID); err != nil {
+		return nil, err
+	}
+
+	linked, err := s.wikiRepo.LinkTask(ctx, id, req.TaskID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !linked {
+		return nil, ErrWikiTaskLinked
+	}
+
+	return s.wikiRepo.GetByID(ctx, id)
+}
+
+// UnlinkTask удаляет связь вики-страницы с задачей
+func (s *WikiService) UnlinkTask(ctx context.Context, id string, taskID string, userID string) error {
+	page, err := s.getAccessiblePage(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.checkCanEdit(ctx, page.ProjectID, userID); err != nil {
+		return err
+	}
+
+	return s.wikiRepo.UnlinkTask(ctx, id, taskID)
+}
+
+// ListTaskPages возвращает вики-страницы, связанные с задачей
+func (s *WikiService) ListTaskPages(ctx context.Context, taskID string, userID string) ([]*domain.WikiPageNode, error) {
+	task, err := s.taskRepo.GetByID(ctx, taskID)
+	if err != nil || task == nil {
+		return nil, ErrTaskNotFound
+	}
+
+	if !s.taskSvc.hasAccessToTask(ctx, task.ProjectID, userID) {
+		return nil, ErrTaskAccessDenied
+	}
+
+	return s.wikiRepo.ListByTask(ctx, taskID)
+}
+
+// save сохраняет правку страницы, увеличивая ее версию
+func (s *WikiService) save(ctx context.Context, page *domain.WikiPage, expectedVersion int, userID string) (*domain.WikiPage, error) {
+	page.Version = expectedVersion + 1
+	page.UpdatedBy = userID
+	page.UpdatedAt = time.Now()
+
+	updated, err := s.wikiRepo.Update(ctx, page, expectedVersion)
+	if err != nil {
+		s.logger.Error("Failed to update wiki page", err, map[string]interface{}{
+			"id": page.ID,
+		})
+		return nil, err
+	}
+	if !updated {
+		return nil, ErrWikiPageConflict
+	}
+
+	return page, nil
+}
+
+// getAccessiblePage возвращает страницу, если у пользователя есть доступ к ее проекту
+func (s *WikiService) getAccessiblePage(ctx context.Context, id string, userID string) (*domain.WikiPage, error) {
+	page, err := s.wikiRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if page == nil || !s.projectSvc.hasAccessToProject(ctx, page.ProjectID, userID) {
+		return nil, ErrWikiPageNotFound
+	}
+	return page, nil
+}
+
+// checkCanEdit проверяет, что пользователь может редактировать вики проекта
+func (s *WikiService) checkCanEdit(ctx context.Context, projectID string, userID string) error {
+	if !s.projectSvc.hasAccessToProject(ctx, projectID, userID) {
+		return ErrProjectNotFound
+	}
+	if !s.taskSvc.canManageTask(ctx, projectID, userID) {
+		return ErrInsufficientRights
+	}
+	return s.projectSvc.ensureProjectWritable(ctx, projectID)
+}
+
+// checkParent проверяет, что новый родитель находится в том же проекте и не является потомком страницы
+func (s *WikiService) checkParent(ctx context.Context, page *domain.WikiPage, parentID string) error {
+	if parentID == page.ID {
+		return ErrWikiParentInvalid
+	}
+
+	parent, err := s.wikiRepo.GetByID(ctx, parentID)
+	if err != nil {
+		return err
+	}
+	if parent == nil || parent.ProjectID != page.ProjectID {
+		return ErrWikiParentInvalid
+	}
+
+	ancestorIDs, err := s.wikiRepo.GetAncestorIDs(ctx, parentID)
+	if err != nil {
+		return err
+	}
+	for _, ancestorID := range ancestorIDs {
+		if ancestorID == page.ID {
+			return ErrWikiParentInvalid
+		}
+	}
+
+	return nil
+}
+
+// checkTask проверяет, что задача существует и принадлежит проекту страницы
+func (s *WikiService) checkTask(ctx context.Context, projectID string, taskID string) error {
+	task, err := s.taskRepo.GetByID(ctx, taskID)
+	if err != nil || task == nil || task.ProjectID != projectID {
+		return ErrWikiTaskInvalid
+	}
+	return nil
+}
+
+// buildWikiTree собирает плоский список страниц в дерево.
+// Страницы с недоступным родителем становятся корневыми
+func buildWikiTree(pages []*domain.WikiPageNode) []*domain.WikiPageNode {
+	byID := make(map[string]*domain.WikiPageNode, len(pages))
+	for _, page := range pages {
+		page.Children = []*domain.WikiPageNode{}
+		byID[page.ID] = page
+	}
+
+	roots := []*domain.WikiPageNode{}
+	for _, page := range pages {
+		if page.ParentID != nil {
+			if parent, ok := byID[*page.ParentID]; ok {
+				parent.Children = append(parent.Children, page)
+				continue
+			}
+		}
+		roots = append(roots, page)
+	}
+
+	return roots
+}
@@ -0,0 +1,5 @@
+-- Удаление триграммных индексов
+DROP INDEX IF EXISTS idx_users_email_trgm;
+DROP INDEX IF EXISTS idx_users_full_name_trgm;
+DROP INDEX IF EXISTS idx_projects_name_trgm;
+DROP INDEX IF EXISTS idx_tasks_title_trgm;
@@ -0,0 +1,8 @@
+-- Расширение для триграммного поиска
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Триграммные индексы для быстрого поиска по префиксу
+CREATE INDEX idx_tasks_title_trgm ON tasks USING GIN (title gin_trgm_ops);
+CREATE INDEX idx_projects_name_trgm ON projects USING GIN (name gin_trgm_ops);
+CREATE INDEX idx_users_full_name_trgm ON users USING GIN ((first_name || ' ' || last_name) gin_trgm_ops);
+CREATE INDEX idx_users_email_trgm ON users USING GIN (email gin_trgm_ops);
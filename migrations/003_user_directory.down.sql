@@ -0,0 +1,5 @@
+-- Удаление таблицы навыков пользователей
+DROP TABLE IF EXISTS user_skills;
+
+-- Удаление доступности пользователя
+ALTER TABLE users DROP COLUMN IF EXISTS weekly_capacity_hours;
@@ -0,0 +1,12 @@
+-- Доступность пользователя: количество рабочих часов в неделю
+ALTER TABLE users ADD COLUMN weekly_capacity_hours NUMERIC(5, 2) NOT NULL DEFAULT 40;
+
+-- Таблица навыков пользователей
+CREATE TABLE user_skills (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    skill VARCHAR(50) NOT NULL,
+    PRIMARY KEY (user_id, skill)
+);
+
+-- Индексы для таблицы навыков пользователей
+CREATE INDEX idx_user_skills_skill ON user_skills (skill);
@@ -0,0 +1,4 @@
+-- Удаление руководителя пользователя
+DROP INDEX IF EXISTS idx_users_manager_id;
+ALTER TABLE users DROP CONSTRAINT IF EXISTS chk_users_manager_not_self;
+ALTER TABLE users DROP COLUMN IF EXISTS manager_id;
@@ -0,0 +1,6 @@
+-- Руководитель пользователя для построения оргструктуры
+ALTER TABLE users ADD COLUMN manager_id UUID REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE users ADD CONSTRAINT chk_users_manager_not_self CHECK (manager_id <> id);
+
+-- Индекс для поиска подчиненных
+CREATE INDEX idx_users_manager_id ON users (manager_id);
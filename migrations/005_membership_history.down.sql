@@ -0,0 +1,2 @@
+-- Удаление таблицы истории участников проекта
+DROP TABLE IF EXISTS membership_history;
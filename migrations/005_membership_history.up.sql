@@ -0,0 +1,15 @@
+-- Таблица истории изменений участников проекта
+CREATE TABLE membership_history (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    actor_id UUID NOT NULL REFERENCES users(id),
+    action VARCHAR(20) NOT NULL,
+    old_role project_role,
+    new_role project_role,
+    changed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Индексы для таблицы истории участников
+CREATE INDEX idx_membership_history_project_id ON membership_history (project_id, changed_at DESC);
+CREATE INDEX idx_membership_history_user_id ON membership_history (user_id);
@@ -0,0 +1,2 @@
+-- Удаление таблицы запросов на передачу владения проектом
+DROP TABLE IF EXISTS ownership_transfers;
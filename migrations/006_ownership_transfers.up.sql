@@ -0,0 +1,17 @@
+-- Таблица запросов на передачу владения проектом.
+-- Записи не удаляются и служат журналом аудита передач владения
+CREATE TABLE ownership_transfers (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    from_user_id UUID NOT NULL REFERENCES users(id),
+    to_user_id UUID NOT NULL REFERENCES users(id),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    resolved_at TIMESTAMP WITH TIME ZONE,
+    resolved_by UUID REFERENCES users(id)
+);
+
+-- Для проекта может существовать только один ожидающий запрос
+CREATE UNIQUE INDEX idx_ownership_transfers_pending ON ownership_transfers (project_id) WHERE status = 'pending';
+CREATE INDEX idx_ownership_transfers_expires_at ON ownership_transfers (expires_at) WHERE status = 'pending';
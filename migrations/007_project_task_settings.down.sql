@@ -0,0 +1,2 @@
+-- Удаление настроек задач проекта
+DROP TABLE IF EXISTS project_task_settings;
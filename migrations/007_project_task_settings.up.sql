@@ -0,0 +1,10 @@
+-- Настройки задач по умолчанию для проекта
+CREATE TABLE project_task_settings (
+    project_id UUID PRIMARY KEY REFERENCES projects(id) ON DELETE CASCADE,
+    default_assignee_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    default_priority task_priority,
+    default_due_offset_days INTEGER CHECK (default_due_offset_days IS NULL OR default_due_offset_days > 0),
+    required_fields TEXT[] NOT NULL DEFAULT '{}',
+    updated_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
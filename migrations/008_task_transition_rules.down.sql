@@ -0,0 +1,2 @@
+-- Удаление правил переходов статусов задач
+ALTER TABLE IF EXISTS project_task_settings DROP COLUMN IF EXISTS transition_rules;
@@ -0,0 +1,2 @@
+-- Обязательные поля для перехода задачи в статус: {"in_progress": ["estimated_hours"], ...}
+ALTER TABLE project_task_settings ADD COLUMN transition_rules JSONB NOT NULL DEFAULT '{}';
@@ -0,0 +1,3 @@
+-- Удаление форм создания задач
+DROP TABLE IF EXISTS task_form_values;
+DROP TABLE IF EXISTS task_forms;
@@ -0,0 +1,2 @@
+-- Удаление таблицы исполнителей задач
+DROP TABLE IF EXISTS task_assignees;
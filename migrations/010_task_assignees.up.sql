@@ -0,0 +1,18 @@
+-- Исполнители задачи. Основной исполнитель дублируется в tasks.assignee_id для совместимости
+CREATE TABLE task_assignees (
+    task_id UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    is_primary BOOLEAN NOT NULL DEFAULT FALSE,
+    assigned_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    assigned_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (task_id, user_id)
+);
+
+CREATE INDEX idx_task_assignees_user_id ON task_assignees (user_id);
+CREATE UNIQUE INDEX idx_task_assignees_primary ON task_assignees (task_id) WHERE is_primary;
+
+-- Переносим существующих исполнителей
+INSERT INTO task_assignees (task_id, user_id, is_primary, assigned_at)
+SELECT id, assignee_id, TRUE, updated_at
+FROM tasks
+WHERE assignee_id IS NOT NULL;
@@ -0,0 +1,4 @@
+-- Удаление распределения оценки задачи между исполнителями
+DROP INDEX IF EXISTS idx_time_logs_task_user;
+
+ALTER TABLE IF EXISTS task_assignees DROP COLUMN IF EXISTS estimated_hours;
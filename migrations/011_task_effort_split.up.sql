@@ -0,0 +1,5 @@
+-- Доля оценки задачи, приходящаяся на исполнителя
+ALTER TABLE task_assignees
+    ADD COLUMN estimated_hours NUMERIC(6, 2) CHECK (estimated_hours >= 0);
+
+CREATE INDEX idx_time_logs_task_user ON time_logs (task_id, user_id);
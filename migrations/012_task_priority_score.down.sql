@@ -0,0 +1,7 @@
+-- Удаление матрицы приоритетов
+DROP INDEX IF EXISTS idx_tasks_priority_score;
+
+ALTER TABLE IF EXISTS tasks
+    DROP COLUMN IF EXISTS impact,
+    DROP COLUMN IF EXISTS urgency,
+    DROP COLUMN IF EXISTS priority_score;
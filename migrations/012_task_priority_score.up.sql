@@ -0,0 +1,15 @@
+-- Матрица приоритетов: влияние и срочность задачи по шкале от 1 до 5
+ALTER TABLE tasks
+    ADD COLUMN impact SMALLINT CHECK (impact BETWEEN 1 AND 5),
+    ADD COLUMN urgency SMALLINT CHECK (urgency BETWEEN 1 AND 5),
+    ADD COLUMN priority_score NUMERIC(8, 2) NOT NULL DEFAULT 0;
+
+-- Начальная оценка по приоритету для существующих задач
+UPDATE tasks SET priority_score = CASE priority
+    WHEN 'low' THEN 95
+    WHEN 'medium' THEN 100
+    WHEN 'high' THEN 105
+    WHEN 'critical' THEN 110
+END;
+
+CREATE INDEX idx_tasks_priority_score ON tasks (project_id, priority_score DESC);
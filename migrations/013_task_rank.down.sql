@@ -0,0 +1,4 @@
+-- Удаление ранга ручной сортировки задач
+DROP INDEX IF EXISTS idx_tasks_project_rank;
+
+ALTER TABLE IF EXISTS tasks DROP COLUMN IF EXISTS rank;
@@ -0,0 +1,13 @@
+-- Ранг для ручной сортировки задач (lexorank). Сравнение побайтовое, поэтому используется COLLATE "C"
+ALTER TABLE tasks ADD COLUMN rank TEXT COLLATE "C" NOT NULL DEFAULT '';
+
+-- Начальные ранги в порядке создания задач внутри проекта
+UPDATE tasks t
+SET rank = lpad(to_hex(o.rn::int), 6, '0') || 'i'
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY project_id ORDER BY created_at, id) AS rn
+    FROM tasks
+) o
+WHERE o.id = t.id;
+
+CREATE INDEX idx_tasks_project_rank ON tasks (project_id, rank);
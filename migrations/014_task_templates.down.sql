@@ -0,0 +1,2 @@
+-- Удаление шаблонов задач
+DROP TABLE IF EXISTS task_templates;
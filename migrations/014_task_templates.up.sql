@@ -0,0 +1,17 @@
+-- Шаблоны задач с подстановкой переменных вида {{customer}}
+CREATE TABLE task_templates (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    title VARCHAR(200) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    checklist TEXT[] NOT NULL DEFAULT '{}',
+    tags TEXT[] NOT NULL DEFAULT '{}',
+    priority task_priority,
+    assignee_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    estimated_hours NUMERIC(6, 2) CHECK (estimated_hours >= 0),
+    created_by UUID NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (project_id, name)
+);
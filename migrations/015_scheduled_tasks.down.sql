@@ -0,0 +1,2 @@
+-- Удаление отложенного создания задач
+DROP TABLE IF EXISTS scheduled_tasks;
@@ -0,0 +1,18 @@
+-- Отложенное создание задач: задача создается планировщиком в указанное время.
+-- Данные будущей задачи хранятся в payload в формате запроса на создание задачи
+CREATE TABLE scheduled_tasks (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    payload JSONB NOT NULL,
+    create_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    task_id UUID REFERENCES tasks(id) ON DELETE SET NULL,
+    error TEXT,
+    created_by UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    processed_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_scheduled_tasks_project_id ON scheduled_tasks (project_id);
+CREATE INDEX idx_scheduled_tasks_due ON scheduled_tasks (create_at) WHERE status = 'pending';
@@ -0,0 +1,3 @@
+-- Удаление автоматической пометки устаревших задач
+DROP INDEX IF EXISTS idx_tasks_backlog_created_at;
+ALTER TABLE IF EXISTS project_task_settings DROP COLUMN IF EXISTS auto_label_stale;
@@ -0,0 +1,5 @@
+-- Автоматическая пометка устаревших задач бэклога; проект может отказаться от нее
+ALTER TABLE project_task_settings ADD COLUMN auto_label_stale BOOLEAN NOT NULL DEFAULT TRUE;
+
+-- Выборка бэклога проекта по возрасту
+CREATE INDEX idx_tasks_backlog_created_at ON tasks (project_id, created_at) WHERE status IN ('new', 'on_hold');
@@ -0,0 +1,3 @@
+-- Удаление создания задач по email
+DROP TABLE IF EXISTS inbound_email_messages;
+DROP TABLE IF EXISTS project_inbound_emails;
@@ -0,0 +1,21 @@
+-- Адрес проекта для создания задач по email: <mailbox>+<token>@<domain>
+CREATE TABLE project_inbound_emails (
+    project_id UUID PRIMARY KEY REFERENCES projects(id) ON DELETE CASCADE,
+    token VARCHAR(64) NOT NULL UNIQUE,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    spam_threshold NUMERIC(5,2),
+    created_by UUID NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Обработанные письма: повторная доставка того же письма не создает задачу второй раз
+CREATE TABLE inbound_email_messages (
+    message_id TEXT PRIMARY KEY,
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    task_id UUID REFERENCES tasks(id) ON DELETE SET NULL,
+    sender VARCHAR(255) NOT NULL,
+    received_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_inbound_email_messages_project_id ON inbound_email_messages (project_id);
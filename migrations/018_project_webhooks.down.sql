@@ -0,0 +1,2 @@
+-- Удаление входящих вебхуков проекта
+DROP TABLE IF EXISTS project_webhooks;
@@ -0,0 +1,3 @@
+-- Удаление интеграций с PagerDuty и Opsgenie
+DROP TABLE IF EXISTS incident_links;
+DROP TABLE IF EXISTS incident_integrations;
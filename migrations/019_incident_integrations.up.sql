@@ -0,0 +1,28 @@
+-- Интеграции проектов с PagerDuty и Opsgenie: инциденты создают задачи,
+-- а переходы связанных задач подтверждают и закрывают инциденты
+CREATE TABLE incident_integrations (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    provider VARCHAR(20) NOT NULL CHECK (provider IN ('pagerduty', 'opsgenie')),
+    secret VARCHAR(255) NOT NULL,
+    api_key VARCHAR(255) NOT NULL DEFAULT '',
+    from_email VARCHAR(255) NOT NULL DEFAULT '',
+    mapping JSONB NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_by UUID NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (project_id, provider)
+);
+
+-- Связь инцидента с созданной по нему задачей
+CREATE TABLE incident_links (
+    integration_id UUID NOT NULL REFERENCES incident_integrations(id) ON DELETE CASCADE,
+    incident_id VARCHAR(255) NOT NULL,
+    task_id UUID NOT NULL UNIQUE REFERENCES tasks(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'triggered',
+    url TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (integration_id, incident_id)
+);
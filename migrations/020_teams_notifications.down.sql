@@ -0,0 +1,4 @@
+-- Удаление уведомлений через Microsoft Teams
+DROP TABLE IF EXISTS project_teams_channels;
+DROP TABLE IF EXISTS user_teams_links;
+ALTER TABLE user_notification_settings DROP COLUMN IF EXISTS teams_enabled;
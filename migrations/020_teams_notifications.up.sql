@@ -0,0 +1,21 @@
+-- Уведомления через входящие вебхуки Microsoft Teams
+ALTER TABLE user_notification_settings ADD COLUMN teams_enabled BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Вебхук личного чата пользователя в Teams
+CREATE TABLE user_teams_links (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    webhook_url TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Канал Teams проекта и типы уведомлений, которые в него публикуются
+CREATE TABLE project_teams_channels (
+    project_id UUID PRIMARY KEY REFERENCES projects(id) ON DELETE CASCADE,
+    webhook_url TEXT NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    notification_types TEXT[] NOT NULL DEFAULT '{}',
+    created_by UUID NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
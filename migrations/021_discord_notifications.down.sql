@@ -0,0 +1,2 @@
+-- Удаление уведомлений в Discord
+DROP TABLE IF EXISTS project_discord_channels;
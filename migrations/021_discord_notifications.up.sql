@@ -0,0 +1,12 @@
+-- Канал Discord проекта: вебхук канала или ID канала для отправки ботом
+CREATE TABLE project_discord_channels (
+    project_id UUID PRIMARY KEY REFERENCES projects(id) ON DELETE CASCADE,
+    webhook_url TEXT,
+    channel_id VARCHAR(32),
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    notification_types TEXT[] NOT NULL DEFAULT '{}',
+    created_by UUID NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CHECK (webhook_url IS NOT NULL OR channel_id IS NOT NULL)
+);
@@ -0,0 +1,2 @@
+-- Удаление уведомлений в Matrix
+DROP TABLE IF EXISTS project_matrix_rooms;
@@ -0,0 +1,11 @@
+-- Комната Matrix проекта и типы уведомлений, которые в нее публикуются
+CREATE TABLE project_matrix_rooms (
+    project_id UUID PRIMARY KEY REFERENCES projects(id) ON DELETE CASCADE,
+    room_id VARCHAR(255) NOT NULL,
+    room_alias VARCHAR(255),
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    notification_types TEXT[] NOT NULL DEFAULT '{}',
+    created_by UUID NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
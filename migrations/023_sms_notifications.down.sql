@@ -0,0 +1,3 @@
+-- Удаление SMS-уведомлений
+DROP TABLE IF EXISTS sms_usage;
+DROP TABLE IF EXISTS user_sms_settings;
@@ -0,0 +1,20 @@
+-- Телефон пользователя для SMS-уведомлений о критических событиях
+CREATE TABLE user_sms_settings (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    phone VARCHAR(20) NOT NULL,
+    verified_at TIMESTAMP WITH TIME ZONE,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    code_hash VARCHAR(64),
+    code_expires_at TIMESTAMP WITH TIME ZONE,
+    code_attempts INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Количество отправленных пользователю SMS по месяцам
+CREATE TABLE sms_usage (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    month DATE NOT NULL,
+    sent INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (user_id, month)
+);
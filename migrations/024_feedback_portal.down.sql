@@ -0,0 +1,3 @@
+-- Удаление портала обратной связи
+DROP TABLE IF EXISTS feedback_submissions;
+DROP TABLE IF EXISTS feedback_portals;
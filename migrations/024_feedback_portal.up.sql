@@ -0,0 +1,33 @@
+-- Публичный портал обратной связи проекта: внешние пользователи отправляют запросы без учетной записи
+CREATE TABLE feedback_portals (
+    project_id UUID PRIMARY KEY REFERENCES projects(id) ON DELETE CASCADE,
+    public_key VARCHAR(64) NOT NULL UNIQUE,
+    title VARCHAR(200) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_by UUID NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Очередь запросов на модерацию
+CREATE TABLE feedback_submissions (
+    id UUID PRIMARY KEY,
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    title VARCHAR(200) NOT NULL,
+    description TEXT NOT NULL,
+    submitter_email VARCHAR(255) NOT NULL,
+    submitter_name VARCHAR(100) NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'merged', 'rejected')),
+    task_id UUID REFERENCES tasks(id) ON DELETE SET NULL,
+    moderation_note TEXT NOT NULL DEFAULT '',
+    moderated_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    moderated_at TIMESTAMP WITH TIME ZONE,
+    notified_task_status VARCHAR(20), -- Последний статус задачи, о котором сообщили автору запроса
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_feedback_submissions_project_status ON feedback_submissions(project_id, status, created_at DESC);
+CREATE INDEX idx_feedback_submissions_task ON feedback_submissions(task_id) WHERE task_id IS NOT NULL;
+CREATE INDEX idx_feedback_submissions_email ON feedback_submissions(project_id, LOWER(submitter_email)) WHERE status = 'pending';
@@ -0,0 +1,2 @@
+-- Удаление публичной дорожной карты
+DROP TABLE IF EXISTS project_roadmaps;
@@ -0,0 +1,12 @@
+-- Публичная дорожная карта проекта: задачи с выбранным тегом публикуются по ссылке без учетной записи
+CREATE TABLE project_roadmaps (
+    project_id UUID PRIMARY KEY REFERENCES projects(id) ON DELETE CASCADE,
+    public_key VARCHAR(64) NOT NULL UNIQUE,
+    title VARCHAR(200) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    label VARCHAR(50) NOT NULL DEFAULT 'public-roadmap',
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_by UUID NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,4 @@
+-- Удаление эпиков
+DROP TABLE IF EXISTS epic_comments;
+ALTER TABLE tasks DROP COLUMN IF EXISTS epic_id;
+DROP TABLE IF EXISTS epics;
@@ -0,0 +1,31 @@
+-- Эпики: группировка задач проекта вокруг общей цели
+CREATE TABLE epics (
+    id UUID PRIMARY KEY,
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    title VARCHAR(200) NOT NULL,
+    goal TEXT NOT NULL DEFAULT '',
+    owner_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    target_date TIMESTAMP WITH TIME ZONE,
+    created_by UUID NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_epics_project_id ON epics (project_id);
+
+-- Задача относится не более чем к одному эпику; при удалении эпика задачи остаются без эпика
+ALTER TABLE tasks ADD COLUMN epic_id UUID REFERENCES epics(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_tasks_epic_id ON tasks (epic_id);
+
+-- Комментарии к эпикам
+CREATE TABLE epic_comments (
+    id UUID PRIMARY KEY,
+    epic_id UUID NOT NULL REFERENCES epics(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id),
+    content TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_epic_comments_epic_id ON epic_comments (epic_id);
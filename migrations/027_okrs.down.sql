@@ -0,0 +1,4 @@
+-- Удаление целей и ключевых результатов
+DROP TABLE IF EXISTS key_result_links;
+DROP TABLE IF EXISTS key_results;
+DROP TABLE IF EXISTS objectives;
@@ -0,0 +1,39 @@
+-- Цели (Objectives) на квартал
+CREATE TABLE objectives (
+    id UUID PRIMARY KEY,
+    title VARCHAR(200) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    owner_id UUID NOT NULL REFERENCES users(id),
+    year SMALLINT NOT NULL,
+    quarter SMALLINT NOT NULL CHECK (quarter BETWEEN 1 AND 4),
+    created_by UUID NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_objectives_period ON objectives (year, quarter);
+
+-- Ключевые результаты цели
+CREATE TABLE key_results (
+    id UUID PRIMARY KEY,
+    objective_id UUID NOT NULL REFERENCES objectives(id) ON DELETE CASCADE,
+    title VARCHAR(200) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_key_results_objective_id ON key_results (objective_id);
+
+-- Проекты и эпики, вклад которых учитывается в ключевом результате
+CREATE TABLE key_result_links (
+    id UUID PRIMARY KEY,
+    key_result_id UUID NOT NULL REFERENCES key_results(id) ON DELETE CASCADE,
+    project_id UUID REFERENCES projects(id) ON DELETE CASCADE,
+    epic_id UUID REFERENCES epics(id) ON DELETE CASCADE,
+    created_by UUID NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CHECK ((project_id IS NULL) <> (epic_id IS NULL))
+);
+
+CREATE UNIQUE INDEX idx_key_result_links_project ON key_result_links (key_result_id, project_id) WHERE project_id IS NOT NULL;
+CREATE UNIQUE INDEX idx_key_result_links_epic ON key_result_links (key_result_id, epic_id) WHERE epic_id IS NOT NULL;
@@ -0,0 +1,3 @@
+-- Удаление журнала решений
+DROP TABLE IF EXISTS decision_tasks;
+DROP TABLE IF EXISTS decisions;
@@ -0,0 +1,28 @@
+-- Журнал решений проекта: контекст, принятое решение и рассмотренные альтернативы
+CREATE TABLE decisions (
+    id UUID PRIMARY KEY,
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    title VARCHAR(200) NOT NULL,
+    context TEXT NOT NULL DEFAULT '',
+    decision TEXT NOT NULL,
+    alternatives TEXT[] NOT NULL DEFAULT '{}',
+    approver_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    decided_at TIMESTAMP WITH TIME ZONE,
+    created_by UUID NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_decisions_project_id ON decisions (project_id, decided_at DESC);
+CREATE INDEX idx_decisions_title_trgm ON decisions USING GIN (title gin_trgm_ops);
+
+-- Связи решений с задачами
+CREATE TABLE decision_tasks (
+    decision_id UUID NOT NULL REFERENCES decisions(id) ON DELETE CASCADE,
+    task_id UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    created_by UUID NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (decision_id, task_id)
+);
+
+CREATE INDEX idx_decision_tasks_task_id ON decision_tasks (task_id);
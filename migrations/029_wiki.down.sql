@@ -0,0 +1,4 @@
+-- Удаление вики проектов
+DROP TABLE IF EXISTS wiki_page_tasks;
+DROP TABLE IF EXISTS wiki_page_revisions;
+DROP TABLE IF EXISTS wiki_pages;
@@ -0,0 +1,41 @@
+-- Вики-страницы проекта с иерархией и историей правок
+CREATE TABLE wiki_pages (
+    id UUID PRIMARY KEY,
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    parent_id UUID REFERENCES wiki_pages(id) ON DELETE SET NULL,
+    title VARCHAR(200) NOT NULL,
+    content TEXT NOT NULL DEFAULT '',
+    version INTEGER NOT NULL DEFAULT 1,
+    created_by UUID NOT NULL REFERENCES users(id),
+    updated_by UUID NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CHECK (parent_id IS NULL OR parent_id <> id)
+);
+
+CREATE INDEX idx_wiki_pages_project_id ON wiki_pages (project_id, parent_id);
+CREATE INDEX idx_wiki_pages_title_trgm ON wiki_pages USING GIN (title gin_trgm_ops);
+CREATE INDEX idx_wiki_pages_search ON wiki_pages USING GIN (to_tsvector('russian', title || ' ' || content));
+
+-- Ревизии вики-страниц: каждая правка сохраняет полный снимок страницы
+CREATE TABLE wiki_page_revisions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    page_id UUID NOT NULL REFERENCES wiki_pages(id) ON DELETE CASCADE,
+    version INTEGER NOT NULL,
+    title VARCHAR(200) NOT NULL,
+    content TEXT NOT NULL,
+    edited_by UUID NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (page_id, version)
+);
+
+-- Связи вики-страниц с задачами
+CREATE TABLE wiki_page_tasks (
+    page_id UUID NOT NULL REFERENCES wiki_pages(id) ON DELETE CASCADE,
+    task_id UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    created_by UUID NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (page_id, task_id)
+);
+
+CREATE INDEX idx_wiki_page_tasks_task_id ON wiki_page_tasks (task_id);
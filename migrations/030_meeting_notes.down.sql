@@ -0,0 +1,3 @@
+-- Удаление заметок встреч
+DROP TABLE IF EXISTS meeting_note_tasks;
+DROP TABLE IF EXISTS meeting_notes;
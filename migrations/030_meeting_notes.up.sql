@@ -0,0 +1,23 @@
+-- Заметки встреч проекта в формате Markdown
+CREATE TABLE meeting_notes (
+    id UUID PRIMARY KEY,
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    title VARCHAR(200) NOT NULL,
+    held_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    content TEXT NOT NULL DEFAULT '',
+    created_by UUID NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_meeting_notes_project_id ON meeting_notes (project_id, held_at DESC);
+
+-- Задачи, созданные из пунктов чек-листа заметки. Связь служит обратной ссылкой из задачи на заметку
+CREATE TABLE meeting_note_tasks (
+    note_id UUID NOT NULL REFERENCES meeting_notes(id) ON DELETE CASCADE,
+    task_id UUID NOT NULL UNIQUE REFERENCES tasks(id) ON DELETE CASCADE,
+    item_text TEXT NOT NULL,
+    created_by UUID NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (note_id, task_id)
+);
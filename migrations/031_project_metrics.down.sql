@@ -0,0 +1,8 @@
+-- Удаление материализованных метрик проектов
+DROP TRIGGER IF EXISTS mark_assignee_project_metrics_dirty_trigger ON task_assignees;
+DROP TRIGGER IF EXISTS mark_task_project_metrics_dirty_trigger ON tasks;
+DROP FUNCTION IF EXISTS mark_assignee_project_metrics_dirty();
+DROP FUNCTION IF EXISTS mark_task_project_metrics_dirty();
+DROP FUNCTION IF EXISTS mark_project_metrics_dirty(UUID);
+DROP TABLE IF EXISTS project_metrics_history;
+DROP TABLE IF EXISTS project_metrics;
@@ -0,0 +1,95 @@
+-- Материализованные метрики проектов. Строка помечается устаревшей (dirty) триггерами
+-- при изменении задач и пересчитывается планировщиком
+CREATE TABLE project_metrics (
+    project_id UUID PRIMARY KEY REFERENCES projects(id) ON DELETE CASCADE,
+    task_count INTEGER NOT NULL DEFAULT 0,
+    completed_tasks INTEGER NOT NULL DEFAULT 0,
+    overdue_tasks INTEGER NOT NULL DEFAULT 0,
+    tasks_by_status JSONB NOT NULL DEFAULT '{}',
+    tasks_by_user JSONB NOT NULL DEFAULT '{}',
+    dirty BOOLEAN NOT NULL DEFAULT TRUE,
+    refreshed_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_project_metrics_dirty ON project_metrics (project_id) WHERE dirty;
+
+-- Ежедневные снимки метрик для графиков динамики
+CREATE TABLE project_metrics_history (
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    snapshot_date DATE NOT NULL,
+    task_count INTEGER NOT NULL,
+    completed_tasks INTEGER NOT NULL,
+    overdue_tasks INTEGER NOT NULL,
+    tasks_by_status JSONB NOT NULL DEFAULT '{}',
+    PRIMARY KEY (project_id, snapshot_date)
+);
+
+-- Функция для пометки метрик проекта как устаревших.
+-- Если строка уже помечена, берется разделяемая блокировка: пересчет, снимающий пометку,
+-- дождется фиксации транзакции и увидит ее изменения
+CREATE OR REPLACE FUNCTION mark_project_metrics_dirty(p_project_id UUID)
+RETURNS VOID AS $$
+BEGIN
+    PERFORM 1 FROM project_metrics WHERE project_id = p_project_id AND dirty FOR SHARE;
+    IF FOUND THEN
+        RETURN;
+    END IF;
+
+    -- Проект может удаляться каскадно вместе с задачами
+    INSERT INTO project_metrics (project_id, dirty)
+    SELECT p_project_id, TRUE
+    WHERE EXISTS (SELECT 1 FROM projects WHERE id = p_project_id)
+    ON CONFLICT (project_id) DO UPDATE SET dirty = TRUE;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Функция для пометки метрик при изменении задач
+CREATE OR REPLACE FUNCTION mark_task_project_metrics_dirty()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP <> 'INSERT' THEN
+        PERFORM mark_project_metrics_dirty(OLD.project_id);
+    END IF;
+
+    IF TG_OP <> 'DELETE' AND (TG_OP = 'INSERT' OR NEW.project_id IS DISTINCT FROM OLD.project_id) THEN
+        PERFORM mark_project_metrics_dirty(NEW.project_id);
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Триггер для пометки метрик при изменении задач
+CREATE TRIGGER mark_task_project_metrics_dirty_trigger
+AFTER INSERT OR DELETE OR UPDATE OF project_id, status, due_date ON tasks
+FOR EACH ROW
+EXECUTE FUNCTION mark_task_project_metrics_dirty();
+
+-- Функция для пометки метрик при изменении исполнителей задач
+CREATE OR REPLACE FUNCTION mark_assignee_project_metrics_dirty()
+RETURNS TRIGGER AS $$
+DECLARE
+    v_task_id UUID;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        v_task_id := OLD.task_id;
+    ELSE
+        v_task_id := NEW.task_id;
+    END IF;
+
+    -- При каскадном удалении задачи строки уже нет, метрики помечены триггером задач
+    PERFORM mark_project_metrics_dirty(project_id) FROM tasks WHERE id = v_task_id;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Триггер для пометки метрик при изменении исполнителей задач
+CREATE TRIGGER mark_assignee_project_metrics_dirty_trigger
+AFTER INSERT OR DELETE ON task_assignees
+FOR EACH ROW
+EXECUTE FUNCTION mark_assignee_project_metrics_dirty();
+
+-- Метрики существующих проектов будут рассчитаны планировщиком
+INSERT INTO project_metrics (project_id)
+SELECT id FROM projects;
@@ -0,0 +1,2 @@
+-- Удаление приглашений в проекты
+DROP TABLE IF EXISTS project_invitations;
@@ -0,0 +1,16 @@
+-- Приглашения в проекты для email, у которых еще нет учетной записи.
+-- Хранится только хеш токена из ссылки приглашения
+CREATE TABLE project_invitations (
+    id UUID PRIMARY KEY,
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    email VARCHAR(255) NOT NULL,
+    role VARCHAR(20) NOT NULL,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    invited_by UUID NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    accepted_at TIMESTAMP WITH TIME ZONE
+);
+
+-- Для одного email в проекте действует не больше одного неиспользованного приглашения
+CREATE UNIQUE INDEX idx_project_invitations_pending ON project_invitations (project_id, email) WHERE accepted_at IS NULL;
@@ -0,0 +1,2 @@
+-- Удаление входящих
+DROP TABLE IF EXISTS inbox_items;
@@ -0,0 +1,12 @@
+-- Входящие: задачи, записанные без проекта и ожидающие разбора
+CREATE TABLE inbox_items (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    title VARCHAR(200) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    priority task_priority,
+    due_date TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_inbox_items_user_id ON inbox_items (user_id, created_at);
@@ -0,0 +1,2 @@
+-- Удаление заметок страницы статуса
+DROP TABLE IF EXISTS status_notes;
@@ -0,0 +1,2 @@
+-- Удаление представлений проектов
+DROP TABLE IF EXISTS project_views;
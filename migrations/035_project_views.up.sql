@@ -0,0 +1,16 @@
+-- Сохраненные представления задач проекта с представлениями по умолчанию для ролей
+CREATE TABLE project_views (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    layout VARCHAR(20) NOT NULL,
+    filters JSONB NOT NULL DEFAULT '{}',
+    sort_by VARCHAR(50) NOT NULL DEFAULT 'rank',
+    sort_dir VARCHAR(4) NOT NULL DEFAULT 'asc',
+    group_by VARCHAR(20) NOT NULL DEFAULT 'status',
+    default_roles project_role[] NOT NULL DEFAULT '{}',
+    created_by UUID NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (project_id, name)
+);
@@ -0,0 +1,2 @@
+-- Удаление повторяющихся задач
+DROP TABLE IF EXISTS task_recurrences;
@@ -0,0 +1,15 @@
+-- Повторяющиеся задачи: правило привязано к текущей задаче серии и переносится
+-- на следующую задачу, которую планировщик создает после завершения текущей
+CREATE TABLE task_recurrences (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    task_id UUID NOT NULL UNIQUE REFERENCES tasks(id) ON DELETE CASCADE,
+    rule VARCHAR(255) NOT NULL,
+    occurrences INTEGER NOT NULL DEFAULT 1,
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+    last_error TEXT,
+    created_by UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_task_recurrences_active ON task_recurrences (task_id) WHERE status = 'active';
@@ -0,0 +1,5 @@
+-- Удаление вложений
+DROP TRIGGER IF EXISTS enqueue_attachment_deletion_trigger ON attachments;
+DROP FUNCTION IF EXISTS enqueue_attachment_deletion();
+DROP TABLE IF EXISTS attachment_deletions;
+DROP TABLE IF EXISTS attachments;
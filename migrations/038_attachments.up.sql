@@ -0,0 +1,44 @@
+-- Вложения задач и комментариев. Содержимое файлов хранится во внешнем хранилище
+-- (локальный диск или S3), в таблице - только метаданные и ключ объекта
+CREATE TABLE attachments (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    task_id UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    comment_id UUID REFERENCES comments(id) ON DELETE CASCADE,
+    file_name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(255) NOT NULL,
+    size BIGINT NOT NULL,
+    storage_key VARCHAR(512) NOT NULL UNIQUE,
+    uploaded_by UUID NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_attachments_task_id ON attachments (task_id);
+CREATE INDEX idx_attachments_comment_id ON attachments (comment_id) WHERE comment_id IS NOT NULL;
+
+-- Очередь удаления файлов из хранилища. Строки вложений удаляются и каскадно вместе
+-- с задачами и комментариями, поэтому ключи их файлов собирает триггер, а сами
+-- файлы удаляет планировщик
+CREATE TABLE attachment_deletions (
+    storage_key VARCHAR(512) PRIMARY KEY,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Функция для постановки файла удаленного вложения в очередь удаления
+CREATE OR REPLACE FUNCTION enqueue_attachment_deletion()
+RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO attachment_deletions (storage_key)
+    VALUES (OLD.storage_key)
+    ON CONFLICT (storage_key) DO NOTHING;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Триггер для постановки файлов удаленных вложений в очередь удаления
+CREATE TRIGGER enqueue_attachment_deletion_trigger
+AFTER DELETE ON attachments
+FOR EACH ROW
+EXECUTE FUNCTION enqueue_attachment_deletion();
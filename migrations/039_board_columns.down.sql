@@ -0,0 +1,3 @@
+-- Удаление колонок канбан-доски
+DROP TABLE IF EXISTS board_column_tasks;
+DROP TABLE IF EXISTS board_columns;
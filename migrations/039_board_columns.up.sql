@@ -0,0 +1,24 @@
+-- Колонки канбан-доски проекта. Колонка со статусом показывает задачи в этом статусе,
+-- колонка без статуса - задачи, вручную перенесенные в нее
+CREATE TABLE board_columns (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    status task_status,
+    position INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (project_id, name)
+);
+
+CREATE UNIQUE INDEX idx_board_columns_status ON board_columns (project_id, status) WHERE status IS NOT NULL;
+CREATE INDEX idx_board_columns_position ON board_columns (project_id, position);
+
+-- Задачи, перенесенные в колонки без статуса. При удалении колонки задачи
+-- возвращаются в колонки своих статусов
+CREATE TABLE board_column_tasks (
+    task_id UUID PRIMARY KEY REFERENCES tasks(id) ON DELETE CASCADE,
+    column_id UUID NOT NULL REFERENCES board_columns(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_board_column_tasks_column_id ON board_column_tasks (column_id);
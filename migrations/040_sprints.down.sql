@@ -0,0 +1,5 @@
+-- Удаление спринтов
+DROP INDEX IF EXISTS idx_task_history_sprint;
+ALTER TABLE tasks DROP COLUMN IF EXISTS sprint_id;
+DROP TABLE IF EXISTS sprints;
+DROP TYPE IF EXISTS sprint_status;
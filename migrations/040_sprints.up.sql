@@ -0,0 +1,32 @@
+-- Спринты: итерации проекта с целью и плановыми датами
+CREATE TYPE sprint_status AS ENUM ('planned', 'active', 'closed');
+
+CREATE TABLE sprints (
+    id UUID PRIMARY KEY,
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    name VARCHAR(200) NOT NULL,
+    goal TEXT NOT NULL DEFAULT '',
+    status sprint_status NOT NULL DEFAULT 'planned',
+    start_date TIMESTAMP WITH TIME ZONE NOT NULL,
+    end_date TIMESTAMP WITH TIME ZONE NOT NULL,
+    started_at TIMESTAMP WITH TIME ZONE,
+    closed_at TIMESTAMP WITH TIME ZONE,
+    created_by UUID NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CHECK (end_date > start_date)
+);
+
+CREATE INDEX idx_sprints_project_id ON sprints (project_id, start_date);
+
+-- В проекте одновременно идет не более одного спринта
+CREATE UNIQUE INDEX idx_sprints_active ON sprints (project_id) WHERE status = 'active';
+
+-- Задача входит не более чем в один спринт; при удалении спринта задачи возвращаются в бэклог.
+-- Включение задачи в спринт и исключение из него записываются в task_history (поле sprint_id)
+ALTER TABLE tasks ADD COLUMN sprint_id UUID REFERENCES sprints(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_tasks_sprint_id ON tasks (sprint_id);
+
+-- Поиск задач, когда-либо входивших в спринт, для диаграммы сгорания
+CREATE INDEX idx_task_history_sprint ON task_history (new_value) WHERE field = 'sprint_id';
@@ -0,0 +1,3 @@
+-- Удаление истории запусков планировщика
+DROP TABLE IF EXISTS job_runs;
+DROP TYPE IF EXISTS job_run_status;
@@ -0,0 +1,18 @@
+-- История запусков задач планировщика: время работы, число обработанных элементов и ошибки
+CREATE TYPE job_run_status AS ENUM ('running', 'succeeded', 'failed');
+
+CREATE TABLE job_runs (
+    id UUID PRIMARY KEY,
+    job VARCHAR(100) NOT NULL,
+    status job_run_status NOT NULL DEFAULT 'running',
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    finished_at TIMESTAMP WITH TIME ZONE,
+    items_processed INTEGER NOT NULL DEFAULT 0,
+    errors INTEGER NOT NULL DEFAULT 0,
+    error TEXT,
+    -- Запуск не уложился в интервал до следующего запуска по расписанию
+    overran BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+CREATE INDEX idx_job_runs_job ON job_runs (job, started_at DESC);
+CREATE INDEX idx_job_runs_started_at ON job_runs (started_at DESC);
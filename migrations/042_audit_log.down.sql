@@ -0,0 +1,2 @@
+-- Удаление журнала аудита
+DROP TABLE IF EXISTS audit_log;
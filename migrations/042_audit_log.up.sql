@@ -0,0 +1,17 @@
+-- Журнал аудита изменений: кто, что и откуда изменил. Записи хранят ID автора без
+-- внешнего ключа, чтобы журнал сохранялся после удаления пользователя
+CREATE TABLE audit_log (
+    id UUID PRIMARY KEY,
+    actor_id UUID,
+    action VARCHAR(20) NOT NULL,
+    entity_type VARCHAR(100) NOT NULL,
+    entity_id VARCHAR(255) NOT NULL DEFAULT '',
+    changes JSONB,
+    ip VARCHAR(64),
+    route VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_audit_log_created_at ON audit_log (created_at DESC);
+CREATE INDEX idx_audit_log_entity ON audit_log (entity_type, entity_id, created_at DESC);
+CREATE INDEX idx_audit_log_actor ON audit_log (actor_id, created_at DESC);
@@ -0,0 +1,2 @@
+-- Удаление версии схемы
+DROP TABLE IF EXISTS schema_version;
@@ -0,0 +1,14 @@
+-- Версия схемы базы для проверки совместимости при запуске сервисов.
+-- Каждая следующая миграция обновляет version до своего номера. Расширяющие миграции
+-- (новые таблицы и колонки) не меняют compatible_from: код, рассчитанный на прежнюю схему,
+-- продолжает работать. Сужающие миграции (удаление и переименование) поднимают
+-- compatible_from до версии, начиная с которой код не использует удаленное
+CREATE TABLE schema_version (
+    id BOOLEAN PRIMARY KEY DEFAULT TRUE CHECK (id),
+    version INTEGER NOT NULL,
+    compatible_from INTEGER NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (compatible_from <= version)
+);
+
+INSERT INTO schema_version (version, compatible_from) VALUES (43, 43);
@@ -0,0 +1,35 @@
+-- Возврат триггера истории к чтению app.current_user_id без функции app_current_user_id
+CREATE OR REPLACE FUNCTION log_task_changes()
+RETURNS TRIGGER AS $$
+BEGIN
+    -- Изменение статуса
+    IF NEW.status IS DISTINCT FROM OLD.status THEN
+        INSERT INTO task_history (task_id, user_id, field, old_value, new_value)
+        VALUES (NEW.id, current_setting('app.current_user_id')::UUID, 'status', OLD.status::TEXT, NEW.status::TEXT);
+    END IF;
+
+    -- Изменение приоритета
+    IF NEW.priority IS DISTINCT FROM OLD.priority THEN
+        INSERT INTO task_history (task_id, user_id, field, old_value, new_value)
+        VALUES (NEW.id, current_setting('app.current_user_id')::UUID, 'priority', OLD.priority::TEXT, NEW.priority::TEXT);
+    END IF;
+
+    -- Изменение исполнителя
+    IF NEW.assignee_id IS DISTINCT FROM OLD.assignee_id THEN
+        INSERT INTO task_history (task_id, user_id, field, old_value, new_value)
+        VALUES (NEW.id, current_setting('app.current_user_id')::UUID, 'assignee_id', OLD.assignee_id::TEXT, NEW.assignee_id::TEXT);
+    END IF;
+
+    -- Изменение срока выполнения
+    IF NEW.due_date IS DISTINCT FROM OLD.due_date THEN
+        INSERT INTO task_history (task_id, user_id, field, old_value, new_value)
+        VALUES (NEW.id, current_setting('app.current_user_id')::UUID, 'due_date', OLD.due_date::TEXT, NEW.due_date::TEXT);
+    END IF;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP FUNCTION IF EXISTS app_current_user_id();
+
+UPDATE schema_version SET version = 43, updated_at = CURRENT_TIMESTAMP;
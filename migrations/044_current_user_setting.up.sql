@@ -0,0 +1,48 @@
+-- ID пользователя, от имени которого выполняется транзакция. Приложение задает его через
+-- set_config('app.current_user_id', $1, true); если значение не задано, функция возвращает NULL,
+-- а не ошибку, как current_setting без missing_ok
+CREATE OR REPLACE FUNCTION app_current_user_id()
+RETURNS UUID AS $$
+    SELECT NULLIF(current_setting('app.current_user_id', true), '')::UUID;
+$$ LANGUAGE sql STABLE;
+
+-- История изменений задачи записывается от имени пользователя транзакции. Изменения без
+-- пользователя (фоновые задачи) в историю не попадают, но и не прерываются ошибкой
+CREATE OR REPLACE FUNCTION log_task_changes()
+RETURNS TRIGGER AS $$
+DECLARE
+    actor_id UUID := app_current_user_id();
+BEGIN
+    IF actor_id IS NULL THEN
+        RETURN NEW;
+    END IF;
+
+    -- Изменение статуса
+    IF NEW.status IS DISTINCT FROM OLD.status THEN
+        INSERT INTO task_history (task_id, user_id, field, old_value, new_value)
+        VALUES (NEW.id, actor_id, 'status', OLD.status::TEXT, NEW.status::TEXT);
+    END IF;
+
+    -- Изменение приоритета
+    IF NEW.priority IS DISTINCT FROM OLD.priority THEN
+        INSERT INTO task_history (task_id, user_id, field, old_value, new_value)
+        VALUES (NEW.id, actor_id, 'priority', OLD.priority::TEXT, NEW.priority::TEXT);
+    END IF;
+
+    -- Изменение исполнителя
+    IF NEW.assignee_id IS DISTINCT FROM OLD.assignee_id THEN
+        INSERT INTO task_history (task_id, user_id, field, old_value, new_value)
+        VALUES (NEW.id, actor_id, 'assignee_id', OLD.assignee_id::TEXT, NEW.assignee_id::TEXT);
+    END IF;
+
+    -- Изменение срока выполнения
+    IF NEW.due_date IS DISTINCT FROM OLD.due_date THEN
+        INSERT INTO task_history (task_id, user_id, field, old_value, new_value)
+        VALUES (NEW.id, actor_id, 'due_date', OLD.due_date::TEXT, NEW.due_date::TEXT);
+    END IF;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+UPDATE schema_version SET version = 44, updated_at = CURRENT_TIMESTAMP;
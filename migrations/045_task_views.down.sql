@@ -0,0 +1,4 @@
+-- Удаление просмотров задач
+DROP TABLE IF EXISTS task_views;
+
+UPDATE schema_version SET version = 44, updated_at = CURRENT_TIMESTAMP;
@@ -0,0 +1,11 @@
+-- Последние просмотры задач пользователями. Просмотры сначала записываются в Redis
+-- и периодически переносятся сюда планировщиком; таблица нужна для просмотров,
+-- вытесненных из Redis
+CREATE TABLE task_views (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    task_id UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    viewed_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (user_id, task_id)
+);
+
+UPDATE schema_version SET version = 45, updated_at = CURRENT_TIMESTAMP;
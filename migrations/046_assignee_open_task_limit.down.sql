@@ -0,0 +1,4 @@
+-- Удаление порога предупреждения о загрузке исполнителя
+ALTER TABLE IF EXISTS project_task_settings DROP COLUMN IF EXISTS assignee_open_task_limit;
+
+UPDATE schema_version SET version = 45, updated_at = CURRENT_TIMESTAMP;
@@ -0,0 +1,6 @@
+-- Число незавершенных задач исполнителя, сверх которого назначение сопровождается
+-- предупреждением о загрузке; 0 отключает проверку
+ALTER TABLE project_task_settings ADD COLUMN assignee_open_task_limit INTEGER NOT NULL DEFAULT 10
+    CHECK (assignee_open_task_limit >= 0);
+
+UPDATE schema_version SET version = 46, updated_at = CURRENT_TIMESTAMP;
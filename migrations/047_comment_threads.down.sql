@@ -0,0 +1,5 @@
+-- Удаление веток ответов на комментарии
+DROP INDEX IF EXISTS idx_comments_parent_comment_id;
+ALTER TABLE IF EXISTS comments DROP COLUMN IF EXISTS parent_comment_id;
+
+UPDATE schema_version SET version = 46, updated_at = CURRENT_TIMESTAMP;
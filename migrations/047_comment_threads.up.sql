@@ -0,0 +1,7 @@
+-- Ответы на комментарии: ссылка на родительский комментарий той же задачи.
+-- Удаление комментария удаляет и всю ветку ответов под ним
+ALTER TABLE comments ADD COLUMN parent_comment_id UUID REFERENCES comments(id) ON DELETE CASCADE;
+
+CREATE INDEX idx_comments_parent_comment_id ON comments (parent_comment_id) WHERE parent_comment_id IS NOT NULL;
+
+UPDATE schema_version SET version = 47, updated_at = CURRENT_TIMESTAMP;
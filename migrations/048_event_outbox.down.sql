@@ -0,0 +1,4 @@
+-- Удаление таблицы исходящих событий
+DROP TABLE IF EXISTS event_outbox;
+
+UPDATE schema_version SET version = 47, updated_at = CURRENT_TIMESTAMP;
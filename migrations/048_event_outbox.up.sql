@@ -0,0 +1,14 @@
+-- Исходящие события: сервисы записывают событие в той же транзакции, что и изменение
+-- данных, а ретранслятор публикует его в Kafka и удаляет запись. Событие не теряется,
+-- если изменение зафиксировано, и не публикуется, если транзакция откатилась
+CREATE TABLE event_outbox (
+    id BIGSERIAL PRIMARY KEY,
+    topic VARCHAR(255) NOT NULL,
+    message_key VARCHAR(255) NOT NULL,
+    payload JSONB NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+UPDATE schema_version SET version = 48, updated_at = CURRENT_TIMESTAMP;
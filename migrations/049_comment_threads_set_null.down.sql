@@ -0,0 +1,6 @@
+-- Возврат каскадного удаления веток ответов на комментарии
+ALTER TABLE comments DROP CONSTRAINT IF EXISTS comments_parent_comment_id_fkey;
+ALTER TABLE comments ADD CONSTRAINT comments_parent_comment_id_fkey
+    FOREIGN KEY (parent_comment_id) REFERENCES comments(id) ON DELETE CASCADE;
+
+UPDATE schema_version SET version = 48, updated_at = CURRENT_TIMESTAMP;
@@ -0,0 +1,7 @@
+-- Удаление комментария не удаляет ответы других пользователей под ним:
+-- ответы на удаленный комментарий становятся комментариями верхнего уровня
+ALTER TABLE comments DROP CONSTRAINT IF EXISTS comments_parent_comment_id_fkey;
+ALTER TABLE comments ADD CONSTRAINT comments_parent_comment_id_fkey
+    FOREIGN KEY (parent_comment_id) REFERENCES comments(id) ON DELETE SET NULL;
+
+UPDATE schema_version SET version = 49, updated_at = CURRENT_TIMESTAMP;
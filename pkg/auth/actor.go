@@ -0,0 +1,62 @@
+package auth
+
+import "context"
+
+// actorKey - ключ пользователя запроса в контексте
+type actorKey struct{}
+
+// Actor - пользователь, от имени которого выполняется запрос. Создается из токена доступа
+// при аутентификации и передается через контекст до сервисов. Роль из токена в Actor не
+// переносится: она может быть устаревшей до истечения токена, поэтому проверки прав читают
+// текущую роль из базы (см. service.AccessService)
+type Actor struct {
+	ID     string
+	Email  string
+	Scopes []string // Области доступа токена; пустой список не ограничивает действия
+}
+
+// NewActor создает пользователя запроса из утверждений токена доступа
+func NewActor(claims *Claims) *Actor {
+	return &Actor{
+		ID:     claims.UserID,
+		Email:  claims.Email,
+		Scopes: claims.Scopes,
+	}
+}
+
+// HasScopes проверяет, что токен разрешает все указанные области доступа
+func (a *Actor) HasScopes(required ...string) bool {
+	if len(a.Scopes) == 0 {
+		return true
+	}
+
+	granted := make(map[string]bool, len(a.Scopes))
+	for _, scope := range a.Scopes {
+		granted[scope] = true
+	}
+	for _, scope := range required {
+		if !granted[scope] {
+			return false
+		}
+	}
+	return true
+}
+
+// WithActor возвращает контекст с пользователем запроса
+func WithActor(ctx context.Context, actor *Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext возвращает пользователя запроса из контекста
+func ActorFromContext(ctx context.Context) (*Actor, bool) {
+	actor, ok := ctx.Value(actorKey{}).(*Actor)
+	return actor, ok && actor != nil && actor.ID != ""
+}
+
+// ActorID возвращает ID пользователя запроса или пустую строку для запросов без аутентификации
+func ActorID(ctx context.Context) string {
+	if actor, ok := ActorFromContext(ctx); ok {
+		return actor.ID
+	}
+	return ""
+}
@@ -34,6 +34,8 @@ type Claims struct {
 	Email  string `json:"email"`
 	Role   string `json:"role"`
 	Type   string `json:"type"`
+	// Scopes ограничивает действия токена; токены сеанса пользователя выдаются без ограничений
+	Scopes []string `json:"scopes,omitempty"`
 	jwt.RegisteredClaims
 }
 
@@ -0,0 +1,88 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ErrInvalidUnsubscribeToken возвращается при невалидной ссылке отписки
+var ErrInvalidUnsubscribeToken = errors.New("unsubscribe token is invalid")
+
+// unsubscribeTokenType определяет тип токена отписки
+const unsubscribeTokenType = "unsubscribe"
+
+// UnsubscribeClaims содержит информацию о подписке, от которой отказывается пользователь
+type UnsubscribeClaims struct {
+	UserID           string `json:"user_id"`
+	NotificationType string `json:"notification_type"`
+	Type             string `json:"type"`
+	jwt.RegisteredClaims
+}
+
+// UnsubscribeManager подписывает и проверяет токены для ссылок отписки от email-уведомлений
+type UnsubscribeManager struct {
+	secret    []byte
+	expiresIn time.Duration
+}
+
+// NewUnsubscribeManager создает новый менеджер токенов отписки
+func NewUnsubscribeManager(secret string, expiresIn time.Duration) *UnsubscribeManager {
+	return &UnsubscribeManager{
+		secret:    []byte(secret),
+		expiresIn: expiresIn,
+	}
+}
+
+// GenerateToken создает подписанный токен отписки для пользователя и типа уведомлений
+func (m *UnsubscribeManager) GenerateToken(userID, notificationType string) (string, error) {
+	now := time.Now()
+	claims := &UnsubscribeClaims{
+		UserID:           userID,
+		NotificationType: notificationType,
+		Type:             unsubscribeTokenType,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(now.Add(m.expiresIn)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			Subject:   userID,
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	tokenString, err := token.SignedString(m.secret)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign unsubscribe token: %w", err)
+	}
+
+	return tokenString, nil
+}
+
+// VerifyToken проверяет подпись и срок действия токена отписки
+func (m *UnsubscribeManager) VerifyToken(tokenString string) (*UnsubscribeClaims, error) {
+	token, err := jwt.ParseWithClaims(
+		tokenString,
+		&UnsubscribeClaims{},
+		func(token *jwt.Token) (interface{}, error) {
+			// Проверяем алгоритм подписи
+			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+			}
+			return m.secret, nil
+		},
+	)
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, ErrExpiredToken
+		}
+		return nil, ErrInvalidUnsubscribeToken
+	}
+
+	claims, ok := token.Claims.(*UnsubscribeClaims)
+	if !ok || !token.Valid || claims.Type != unsubscribeTokenType || claims.UserID == "" || claims.NotificationType == "" {
+		return nil, ErrInvalidUnsubscribeToken
+	}
+
+	return claims, nil
+}
@@ -0,0 +1,172 @@
+package circuit
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+var (
+	ErrOpen = errors.New("circuit breaker is open")
+	ErrBusy = errors.New("too many concurrent calls")
+)
+
+// State описывает состояние автоматического выключателя
+type State int
+
+const (
+	StateClosed   State = iota // Вызовы проходят
+	StateHalfOpen              // Пропускается один пробный вызов
+	StateOpen                  // Вызовы отклоняются без обращения к сервису
+)
+
+// String возвращает название состояния
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateHalfOpen:
+		return "half_open"
+	default:
+		return "open"
+	}
+}
+
+// Settings содержит параметры автоматического выключателя
+type Settings struct {
+	FailureThreshold int           // Число ошибок подряд, после которого выключатель размыкается
+	OpenTimeout      time.Duration // Время до пробного вызова после размыкания
+	MaxConcurrent    int           // Ограничение одновременных вызовов; 0 - без ограничения
+}
+
+// excludedError помечает ошибку, не говорящую о неисправности сервиса
+type excludedError struct {
+	err error
+}
+
+func (e *excludedError) Error() string { return e.err.Error() }
+func (e *excludedError) Unwrap() error { return e.err }
+
+// Exclude помечает ошибку как не связанную с доступностью сервиса (например, ответ 4xx
+// на некорректный запрос), чтобы она не учитывалась выключателем
+func Exclude(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &excludedError{err: err}
+}
+
+// Breaker - автоматический выключатель вокруг обращений к внешнему сервису.
+// После FailureThreshold ошибок подряд вызовы отклоняются с ErrOpen до истечения
+// OpenTimeout, затем пропускается один пробный вызов: успех замыкает выключатель,
+// ошибка снова размыкает. Ограничение одновременных вызовов не дает медленному
+// сервису занять все рабочие горутины
+type Breaker struct {
+	name     string
+	settings Settings
+
+	mu        sync.Mutex
+	state     State
+	failures  int
+	openUntil time.Time
+	probing   bool
+	inFlight  int
+
+	calls    uint64
+	errors   uint64
+	rejected uint64
+	opened   uint64
+}
+
+// newBreaker создает замкнутый выключатель
+func newBreaker(name string, settings Settings) *Breaker {
+	return &Breaker{
+		name:     name,
+		settings: settings,
+	}
+}
+
+// Name возвращает имя выключателя
+func (b *Breaker) Name() string {
+	return b.name
+}
+
+// State возвращает текущее состояние выключателя
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == StateOpen && !time.Now().Before(b.openUntil) {
+		return StateHalfOpen
+	}
+	return b.state
+}
+
+// Do выполняет fn, если выключатель замкнут. Ошибка fn возвращается без изменений
+func (b *Breaker) Do(fn func() error) error {
+	probe, err := b.acquire()
+	if err != nil {
+		return err
+	}
+
+	err = fn()
+	b.release(probe, err)
+
+	return err
+}
+
+// acquire проверяет, можно ли выполнить вызов, и занимает место для него
+func (b *Breaker) acquire() (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == StateOpen && !time.Now().Before(b.openUntil) {
+		b.state = StateHalfOpen
+	}
+
+	switch {
+	case b.state == StateOpen, b.state == StateHalfOpen && b.probing:
+		b.rejected++
+		return false, ErrOpen
+	case b.settings.MaxConcurrent > 0 && b.inFlight >= b.settings.MaxConcurrent:
+		b.rejected++
+		return false, ErrBusy
+	}
+
+	probe := b.state == StateHalfOpen
+	if probe {
+		b.probing = true
+	}
+	b.inFlight++
+	b.calls++
+
+	return probe, nil
+}
+
+// release освобождает место вызова и учитывает его результат
+func (b *Breaker) release(probe bool, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.inFlight--
+	if probe {
+		b.probing = false
+	}
+
+	var excluded *excludedError
+	if err == nil || errors.As(err, &excluded) {
+		b.failures = 0
+		if probe {
+			b.state = StateClosed
+		}
+		return
+	}
+
+	b.errors++
+	b.failures++
+	if probe || (b.state == StateClosed && b.failures >= b.settings.FailureThreshold) {
+		b.state = StateOpen
+		b.openUntil = time.Now().Add(b.settings.OpenTimeout)
+		b.opened++
+	}
+}
@@ -0,0 +1,127 @@
+package circuit
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultSettings используются реестром, пока не заданы другие
+var DefaultSettings = Settings{
+	FailureThreshold: 5,
+	OpenTimeout:      30 * time.Second,
+	MaxConcurrent:    20,
+}
+
+// Default - реестр выключателей процесса. Отправители уведомлений создаются
+// в нескольких местах, но обращаются к одним и тем же сервисам, поэтому
+// выключатель одного сервиса общий для всего процесса
+var Default = NewRegistry(DefaultSettings)
+
+// Registry хранит выключатели по именам внешних сервисов и отдает их состояние
+// в текстовом формате Prometheus
+type Registry struct {
+	mu       sync.Mutex
+	settings Settings
+	breakers map[string]*Breaker
+}
+
+// NewRegistry создает пустой реестр
+func NewRegistry(settings Settings) *Registry {
+	return &Registry{
+		settings: settings,
+		breakers: make(map[string]*Breaker),
+	}
+}
+
+// Configure задает параметры для выключателей, создаваемых после вызова
+func (r *Registry) Configure(settings Settings) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.settings = settings
+}
+
+// Get возвращает выключатель сервиса, создавая его при первом обращении
+func (r *Registry) Get(name string) *Breaker {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	breaker, ok := r.breakers[name]
+	if !ok {
+		breaker = newBreaker(name, r.settings)
+		r.breakers[name] = breaker
+	}
+	return breaker
+}
+
+// WritePrometheus записывает состояние выключателей в текстовом формате Prometheus
+func (r *Registry) WritePrometheus(w io.Writer) error {
+	r.mu.Lock()
+	breakers := make([]*Breaker, 0, len(r.breakers))
+	for _, breaker := range r.breakers {
+		breakers = append(breakers, breaker)
+	}
+	r.mu.Unlock()
+
+	sort.Slice(breakers, func(i, j int) bool {
+		return breakers[i].name < breakers[j].name
+	})
+
+	type snapshot struct {
+		name                            string
+		state                           State
+		calls, errors, rejected, opened uint64
+	}
+	snapshots := make([]snapshot, len(breakers))
+	for i, breaker := range breakers {
+		state := breaker.State()
+		breaker.mu.Lock()
+		snapshots[i] = snapshot{
+			name:     breaker.name,
+			state:    state,
+			calls:    breaker.calls,
+			errors:   breaker.errors,
+			rejected: breaker.rejected,
+			opened:   breaker.opened,
+		}
+		breaker.mu.Unlock()
+	}
+
+	var b strings.Builder
+	b.WriteString("# HELP circuit_breaker_state State of the circuit breaker: 0 - closed, 1 - half-open, 2 - open.\n")
+	b.WriteString("# TYPE circuit_breaker_state gauge\n")
+	for _, s := range snapshots {
+		fmt.Fprintf(&b, "circuit_breaker_state{name=%q} %d\n", s.name, s.state)
+	}
+
+	b.WriteString("# HELP circuit_breaker_calls_total Calls passed to the external service.\n")
+	b.WriteString("# TYPE circuit_breaker_calls_total counter\n")
+	for _, s := range snapshots {
+		fmt.Fprintf(&b, "circuit_breaker_calls_total{name=%q} %d\n", s.name, s.calls)
+	}
+
+	b.WriteString("# HELP circuit_breaker_failures_total Calls that failed and counted against the service health.\n")
+	b.WriteString("# TYPE circuit_breaker_failures_total counter\n")
+	for _, s := range snapshots {
+		fmt.Fprintf(&b, "circuit_breaker_failures_total{name=%q} %d\n", s.name, s.errors)
+	}
+
+	b.WriteString("# HELP circuit_breaker_rejected_total Calls rejected without reaching the service.\n")
+	b.WriteString("# TYPE circuit_breaker_rejected_total counter\n")
+	for _, s := range snapshots {
+		fmt.Fprintf(&b, "circuit_breaker_rejected_total{name=%q} %d\n", s.name, s.rejected)
+	}
+
+	b.WriteString("# HELP circuit_breaker_opened_total Times the circuit breaker opened.\n")
+	b.WriteString("# TYPE circuit_breaker_opened_total counter\n")
+	for _, s := range snapshots {
+		fmt.Fprintf(&b, "circuit_breaker_opened_total{name=%q} %d\n", s.name, s.opened)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
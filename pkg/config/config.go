@@ -3,12 +3,7 @@ package config
 import (
 	"context"
 	"fmt"
-	"os"
-	"strconv"
-	"strings"
 	"time"
-
-	"github.com/joho/godotenv"
 )
 
 // Config содержит все конфигурационные параметры приложения
@@ -21,14 +16,22 @@ type Config struct {
 	JWT        JWTConfig
 	Scheduler  SchedulerConfig
 	Notifier   NotifierConfig
+	Inbound    InboundEmailConfig
+	Incidents  IncidentConfig
 	Monitoring MonitoringConfig
 	Telegram   TelegramConfig
+	Storage    StorageConfig
+	Features   FeaturesConfig
+
+	settings []ResolvedSetting // Итоговые значения настроек в порядке загрузки
+	problems []string          // Ошибки разбора, найденные при загрузке
 }
 
 // AppConfig содержит общие настройки приложения
 type AppConfig struct {
 	Name        string
 	Context     context.Context
+	Profile     Profile
 	Environment string
 	LogLevel    string
 	Debug       bool
@@ -37,11 +40,22 @@ type AppConfig struct {
 
 // HTTPConfig содержит настройки HTTP-сервера
 type HTTPConfig struct {
-	Port            string
-	ReadTimeout     time.Duration
-	WriteTimeout    time.Duration
-	ShutdownTimeout time.Duration
-	BasePath        string
+	Port              string
+	ReadTimeout       time.Duration
+	ReadHeaderTimeout time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration // Время жизни простаивающего keep-alive соединения
+	ShutdownTimeout   time.Duration
+	RequestTimeout    time.Duration // Срок обработки запроса, передаваемый через контекст до репозиториев
+	ExportTimeout     time.Duration // Срок обработки длительных выгрузок
+	UploadTimeout     time.Duration // Срок загрузки и скачивания вложений, включая чтение тела запроса
+	StreamTimeout     time.Duration // Длительность потоков событий; по истечении клиент переподключается
+	MaxHeaderBytes    int
+	MaxBodyBytes      int64
+	H2C               bool // HTTP/2 без TLS для работы за балансировщиком
+	BasePath          string
+	ReadOnly          bool   // Отклонять изменяющие запросы, как в режиме обслуживания, до перезапуска без флага
+	ReadOnlyMessage   string // Сообщение для пользователей в режиме только для чтения
 }
 
 // DatabaseConfig содержит настройки подключения к базе данных
@@ -55,6 +69,10 @@ type DatabaseConfig struct {
 	MaxOpenConns int
 	MaxIdleConns int
 	ConnMaxLife  time.Duration
+
+	SlowQueryThreshold time.Duration // Запросы дольше порога попадают в журнал; 0 отключает журнал
+
+	SchemaMismatch string // Поведение при несовместимой схеме базы: refuse - не запускаться, readonly - API только для чтения
 }
 
 // RedisConfig содержит настройки подключения к Redis
@@ -64,21 +82,49 @@ type RedisConfig struct {
 	Password   string
 	DB         int
 	DefaultTTL time.Duration
+	MemberTTL  time.Duration // Время жизни закэшированного членства пользователя в проекте
+
+	SettingsTTL time.Duration // Время жизни закэшированных настроек уведомлений пользователя
 }
 
 // KafkaConfig содержит настройки для работы с Kafka
 type KafkaConfig struct {
-	Brokers []string
-	Topics  KafkaTopics
+	Brokers   []string
+	Namespace string // Пространство имен развертывания, например организации: префикс топиков, групп потребителей и очереди отложенных уведомлений
+	Topics    KafkaTopics
+	Delays    DelayQueueConfig
+	Outbox    OutboxConfig
+}
+
+// DelayQueueConfig содержит настройки очереди отложенных уведомлений в Redis
+type DelayQueueConfig struct {
+	Key          string        // Ключ отсортированного множества с отложенными уведомлениями
+	PollInterval time.Duration // Интервал проверки наступивших уведомлений
+	BatchSize    int           // Число уведомлений, забираемых из очереди за одну проверку
+	Lease        time.Duration // Время, на которое забранное уведомление скрывается из очереди до подтверждения отправки
+}
+
+// OutboxConfig содержит настройки ретранслятора исходящих событий из базы в Kafka
+type OutboxConfig struct {
+	PollInterval time.Duration // Интервал проверки неопубликованных событий
+	BatchSize    int           // Число событий, публикуемых в одной транзакции
+	MaxAttempts  int           // Число попыток публикации, после которого событие остается в таблице для разбора
 }
 
-// KafkaTopics содержит названия топиков Kafka
+// KafkaTopics содержит названия топиков Kafka. Уведомления разделены по уровням
+// приоритета, чтобы срочные уведомления не ждали в очереди за дайджестами
 type KafkaTopics struct {
-	TaskCreated   string
-	TaskUpdated   string
-	TaskAssigned  string
-	TaskCommented string
-	Notifications string
+	TaskCreated           string
+	TaskUpdated           string
+	TaskAssigned          string
+	TaskCommented         string
+	ProjectCreated        string
+	ProjectUpdated        string
+	ProjectMemberAdded    string
+	ProjectMemberRemoved  string
+	NotificationsCritical string // Срочные уведомления, например о просроченных задачах
+	Notifications         string // Обычные уведомления
+	NotificationsDigest   string // Дайджесты
 }
 
 // JWTConfig содержит настройки JWT-аутентификации
@@ -93,12 +139,43 @@ type JWTConfig struct {
 type SchedulerConfig struct {
 	DailyDigestCron      string
 	DeadlineReminderCron string
+	StaleTaskCron        string
+	StaleTaskDays        int // Возраст задачи бэклога в днях, после которого она помечается как устаревшая; 0 отключает пометку
+	InboxReminderCron    string
+	InboxReminderDays    int      // Возраст записи входящих в днях, после которого напоминается о разборе; 0 отключает напоминания
+	ConsistencyCron      string   // Расписание проверки согласованности данных с исправлением расхождений; пустое значение отключает проверку
+	DigestWindow         []string // Условия по датам для задач дайджеста, например due:<7d; без условий в дайджест попадают задачи со сроком с сегодняшнего дня
+	DigestWorkers        int      // Число пользователей, дайджесты которых формируются одновременно
+	JobRunRetentionDays  int      // Срок хранения истории запусков задач планировщика в днях
+	JobAlertWebhookURL   string   // Адрес, на который отправляются оповещения о сбоях и превышении интервала задач; пустое значение отключает отправку
 }
 
 // NotifierConfig содержит настройки для сервиса уведомлений
 type NotifierConfig struct {
-	SMTP     SMTPConfig
-	Telegram TelegramConfig
+	MaxInFlight         int // Ограничение обычных уведомлений, обрабатываемых одновременно; при достижении чтение из Kafka ждет
+	CriticalMaxInFlight int // То же для срочных уведомлений
+	DigestMaxInFlight   int // То же для дайджестов
+	Breaker             BreakerConfig
+	SMTP                SMTPConfig
+	Telegram            TelegramConfig
+	Teams               TeamsConfig
+	Discord             DiscordConfig
+	Matrix              MatrixConfig
+	SMS                 SMSConfig
+	Unsubscribe         UnsubscribeConfig
+}
+
+// UnsubscribeConfig содержит настройки ссылок для отписки от email-уведомлений
+type UnsubscribeConfig struct {
+	Secret    string
+	ExpiresIn time.Duration
+}
+
+// BreakerConfig содержит настройки автоматических выключателей внешних сервисов уведомлений
+type BreakerConfig struct {
+	FailureThreshold int           // Число ошибок подряд до размыкания
+	OpenTimeout      time.Duration // Время до пробного вызова после размыкания
+	MaxConcurrent    int           // Ограничение одновременных обращений к одному сервису
 }
 
 // SMTPConfig содержит настройки SMTP-сервера для отправки email
@@ -108,12 +185,121 @@ type SMTPConfig struct {
 	Username string
 	Password string
 	From     string
+	Timeout  time.Duration
+}
+
+// InboundEmailConfig содержит настройки приема писем для создания задач.
+// Адрес проекта имеет вид <Mailbox>+<токен>@<Domain>; почтовый сервер складывает
+// письма в каталог Maildir, который обрабатывает воркер приема почты
+type InboundEmailConfig struct {
+	Domain        string
+	Mailbox       string
+	Maildir       string
+	PollInterval  time.Duration
+	MaxSize       int64
+	SpamThreshold float64 // Письма с оценкой спама не ниже порога отклоняются, если проект не задал свой порог
+	// Идентификатор (authserv-id) почтового сервера, заголовкам Authentication-Results которого
+	// доверяется проверка DKIM и DMARC отправителя. Сервер должен удалять такие заголовки
+	// с этим идентификатором из входящих писем. Без него письма не принимаются
+	AuthServID string
+}
+
+// IncidentConfig содержит настройки обращения к API систем управления инцидентами
+type IncidentConfig struct {
+	PagerDutyAPIURL string
+	OpsgenieAPIURL  string // Для аккаунтов в EU - https://api.eu.opsgenie.com
+	Timeout         time.Duration
 }
 
 // TelegramConfig содержит настройки для уведомлений через Telegram
 type TelegramConfig struct {
-	Token      string `json:"token" yaml:"token" env:"TELEGRAM_TOKEN"`
-	WebhookURL string `json:"webhook_url" yaml:"webhook_url" env:"TELEGRAM_WEBHOOK_URL"`
+	Token        string        `json:"token" yaml:"token" env:"TELEGRAM_TOKEN"`
+	WebhookURL   string        `json:"webhook_url" yaml:"webhook_url" env:"TELEGRAM_WEBHOOK_URL"`
+	RateLimit    float64       `json:"rate_limit" yaml:"rate_limit" env:"TELEGRAM_RATE_LIMIT"`          // Сообщений в секунду для бота
+	ChatInterval time.Duration `json:"chat_interval" yaml:"chat_interval" env:"TELEGRAM_CHAT_INTERVAL"` // Интервал между сообщениями в один чат
+	MaxRetries   int           `json:"max_retries" yaml:"max_retries" env:"TELEGRAM_MAX_RETRIES"`       // Повторы после ответа 429
+	// Секрет, который Telegram передает в заголовке X-Telegram-Bot-Api-Secret-Token запросов webhook.
+	// Без него запросы webhook отклоняются
+	WebhookSecret string `json:"webhook_secret" yaml:"webhook_secret" env:"TELEGRAM_WEBHOOK_SECRET"`
+}
+
+// TeamsConfig содержит настройки уведомлений через входящие вебхуки Microsoft Teams.
+// Уведомления отправляются только на хосты из AllowedHosts и их поддомены
+type TeamsConfig struct {
+	AllowedHosts []string
+	Timeout      time.Duration
+}
+
+// DiscordConfig содержит настройки уведомлений в Discord.
+// Каналы проектов подключаются вебхуком или по ID канала; для второго варианта нужен токен бота
+type DiscordConfig struct {
+	BotToken string
+	APIURL   string
+	Timeout  time.Duration
+}
+
+// MatrixConfig содержит настройки уведомлений в комнаты Matrix.
+// Сообщения отправляются от имени пользователя, которому выдан AccessToken
+type MatrixConfig struct {
+	HomeserverURL string
+	AccessToken   string
+	Timeout       time.Duration
+}
+
+// SMSConfig содержит настройки SMS-уведомлений о критических событиях.
+// Правило в CriticalTypes имеет вид "тип" или "тип:приоритет задачи", например task_overdue:critical
+type SMSConfig struct {
+	Provider      string // twilio или sns; пустое значение отключает SMS
+	CriticalTypes []string
+	MonthlyQuota  int // Максимум SMS на пользователя за календарный месяц, включая коды подтверждения
+	CodeTTL       time.Duration
+	Timeout       time.Duration
+	Twilio        TwilioConfig
+	SNS           SNSConfig
+}
+
+// TwilioConfig содержит настройки отправки SMS через Twilio
+type TwilioConfig struct {
+	AccountSID string
+	AuthToken  string
+	From       string
+	APIURL     string
+}
+
+// SNSConfig содержит настройки отправки SMS через Amazon SNS
+type SNSConfig struct {
+	Region          string
+	AccessKeyID     string
+	SecretAccessKey string
+	SenderID        string
+}
+
+// StorageConfig содержит настройки хранилища вложений задач и комментариев
+type StorageConfig struct {
+	Backend     string // local или s3
+	LocalDir    string // Каталог для файлов при Backend=local
+	MaxFileSize int64  // Максимальный размер одного вложения в байтах
+	S3          S3Config
+}
+
+// S3Config содержит настройки хранения файлов в Amazon S3 или совместимом хранилище.
+// Для MinIO и других хранилищ задается Endpoint; запросы адресуются в стиле path
+type S3Config struct {
+	Endpoint        string // Пустое значение означает https://s3.<Region>.amazonaws.com
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+	Timeout         time.Duration
+}
+
+// FeaturesConfig содержит настройки флагов функциональности
+type FeaturesConfig struct {
+	// Доли пользователей (0-100), для которых включены флаги по умолчанию.
+	// Задаются как FEATURE_FLAGS=typeahead_extended=100,new_feature=25
+	Rollouts map[string]int
+	// Как часто экземпляр перечитывает переопределения флагов из Redis
+	RefreshInterval time.Duration
 }
 
 // MonitoringConfig содержит настройки мониторинга
@@ -122,85 +308,223 @@ type MonitoringConfig struct {
 	PrometheusPort    string
 }
 
-// Load загружает конфигурацию из переменных окружения
+// Load загружает конфигурацию из переменных окружения. Профиль задается APP_ENV;
+// приоритет значений: переменные окружения, файл .env.<профиль>, файл .env,
+// значения профиля, значения по умолчанию. Ошибки разбора не прерывают загрузку,
+// их возвращает Validate
 func Load() (*Config, error) {
-	// Загружаем .env файл, если он существует
-	_ = godotenv.Load()
-	fmt.Println("THIS IS TELEGRAM_TOKEN:", os.Getenv("TELEGRAM_TOKEN"))
+	profile, problems := loadEnvFiles()
+	env := newEnvLoader(profile)
+
 	config := &Config{
 		App: AppConfig{
-			Name:        getEnv("APP_NAME", "task-tracker"),
-			Environment: getEnv("APP_ENV", "development"),
-			LogLevel:    getEnv("LOG_LEVEL", "info"),
-			Debug:       getEnvAsBool("APP_DEBUG", true),
-			BaseURL:     getEnv("BASE_URL", ""),
+			Name:        env.String("APP_NAME", "task-tracker"),
+			Profile:     profile,
+			Environment: env.String("APP_ENV", "development"),
+			LogLevel:    env.String("LOG_LEVEL", "info"),
+			Debug:       env.Bool("APP_DEBUG", true),
+			BaseURL:     env.String("BASE_URL", ""),
 		},
 		HTTP: HTTPConfig{
-			Port:            getEnv("HTTP_PORT", "8080"),
-			ReadTimeout:     getEnvAsDuration("HTTP_READ_TIMEOUT", 10*time.Second),
-			WriteTimeout:    getEnvAsDuration("HTTP_WRITE_TIMEOUT", 20*time.Second),
-			ShutdownTimeout: getEnvAsDuration("HTTP_SHUTDOWN_TIMEOUT", 5*time.Second),
-			BasePath:        getEnv("HTTP_BASE_PATH", ""),
+			Port:              env.String("HTTP_PORT", "8080"),
+			ReadTimeout:       env.Duration("HTTP_READ_TIMEOUT", 10*time.Second),
+			ReadHeaderTimeout: env.Duration("HTTP_READ_HEADER_TIMEOUT", 5*time.Second),
+			WriteTimeout:      env.Duration("HTTP_WRITE_TIMEOUT", 20*time.Second),
+			IdleTimeout:       env.Duration("HTTP_IDLE_TIMEOUT", 120*time.Second),
+			ShutdownTimeout:   env.Duration("HTTP_SHUTDOWN_TIMEOUT", 5*time.Second),
+			RequestTimeout:    env.Duration("HTTP_REQUEST_TIMEOUT", 15*time.Second),
+			ExportTimeout:     env.Duration("HTTP_EXPORT_TIMEOUT", 5*time.Minute),
+			UploadTimeout:     env.Duration("HTTP_UPLOAD_TIMEOUT", 5*time.Minute),
+			StreamTimeout:     env.Duration("HTTP_STREAM_TIMEOUT", 30*time.Minute),
+			MaxHeaderBytes:    env.Int("HTTP_MAX_HEADER_BYTES", 1<<20),
+			MaxBodyBytes:      int64(env.Int("HTTP_MAX_BODY_BYTES", 1<<20)),
+			H2C:               env.Bool("HTTP_H2C", false),
+			BasePath:          env.String("HTTP_BASE_PATH", ""),
+			ReadOnly:          env.Bool("HTTP_READ_ONLY", false),
+			ReadOnlyMessage:   env.String("HTTP_READ_ONLY_MESSAGE", ""),
 		},
 		Database: DatabaseConfig{
-			Host:         getEnv("DB_HOST", "localhost"),
-			Port:         getEnv("DB_PORT", "5432"),
-			Username:     getEnv("DB_USER", "taskuser"),
-			Password:     getEnv("DB_PASSWORD", "taskpass"),
-			Database:     getEnv("DB_NAME", "tasktracker"),
-			SSLMode:      getEnv("DB_SSLMODE", "disable"),
-			MaxOpenConns: getEnvAsInt("DB_MAX_OPEN_CONNS", 25),
-			MaxIdleConns: getEnvAsInt("DB_MAX_IDLE_CONNS", 10),
-			ConnMaxLife:  getEnvAsDuration("DB_CONN_MAX_LIFETIME", 5*time.Minute),
+			Host:         env.String("DB_HOST", "localhost"),
+			Port:         env.String("DB_PORT", "5432"),
+			Username:     env.String("DB_USER", "taskuser"),
+			Password:     env.String("DB_PASSWORD", "taskpass"),
+			Database:     env.String("DB_NAME", "tasktracker"),
+			SSLMode:      env.String("DB_SSLMODE", "disable"),
+			MaxOpenConns: env.Int("DB_MAX_OPEN_CONNS", 25),
+			MaxIdleConns: env.Int("DB_MAX_IDLE_CONNS", 10),
+			ConnMaxLife:  env.Duration("DB_CONN_MAX_LIFETIME", 5*time.Minute),
+
+			SlowQueryThreshold: env.Duration("DB_SLOW_QUERY_THRESHOLD", 200*time.Millisecond),
+			SchemaMismatch:     env.String("DB_SCHEMA_MISMATCH", "refuse"),
 		},
 		Redis: RedisConfig{
-			Host:       getEnv("REDIS_HOST", "localhost"),
-			Port:       getEnv("REDIS_PORT", "6379"),
-			Password:   getEnv("REDIS_PASSWORD", ""),
-			DB:         getEnvAsInt("REDIS_DB", 0),
-			DefaultTTL: getEnvAsDuration("REDIS_DEFAULT_TTL", 24*time.Hour),
+			Host:       env.String("REDIS_HOST", "localhost"),
+			Port:       env.String("REDIS_PORT", "6379"),
+			Password:   env.String("REDIS_PASSWORD", ""),
+			DB:         env.Int("REDIS_DB", 0),
+			DefaultTTL: env.Duration("REDIS_DEFAULT_TTL", 24*time.Hour),
+			MemberTTL:  env.Duration("REDIS_MEMBER_TTL", time.Minute),
+
+			SettingsTTL: env.Duration("REDIS_SETTINGS_TTL", time.Hour),
 		},
 		Kafka: KafkaConfig{
-			Brokers: strings.Split(getEnv("KAFKA_BROKERS", "localhost:9092"), ","),
+			Brokers:   env.List("KAFKA_BROKERS", "localhost:9092"),
+			Namespace: env.String("KAFKA_NAMESPACE", ""),
 			Topics: KafkaTopics{
-				TaskCreated:   getEnv("KAFKA_TOPIC_TASK_CREATED", "task_created"),
-				TaskUpdated:   getEnv("KAFKA_TOPIC_TASK_UPDATED", "task_updated"),
-				TaskAssigned:  getEnv("KAFKA_TOPIC_TASK_ASSIGNED", "task_assigned"),
-				TaskCommented: getEnv("KAFKA_TOPIC_TASK_COMMENTED", "task_commented"),
-				Notifications: getEnv("KAFKA_TOPIC_NOTIFICATIONS", "notifications"),
+				TaskCreated:           env.String("KAFKA_TOPIC_TASK_CREATED", "task_created"),
+				TaskUpdated:           env.String("KAFKA_TOPIC_TASK_UPDATED", "task_updated"),
+				TaskAssigned:          env.String("KAFKA_TOPIC_TASK_ASSIGNED", "task_assigned"),
+				TaskCommented:         env.String("KAFKA_TOPIC_TASK_COMMENTED", "task_commented"),
+				ProjectCreated:        env.String("KAFKA_TOPIC_PROJECT_CREATED", "project_created"),
+				ProjectUpdated:        env.String("KAFKA_TOPIC_PROJECT_UPDATED", "project_updated"),
+				ProjectMemberAdded:    env.String("KAFKA_TOPIC_PROJECT_MEMBER_ADDED", "project_member_added"),
+				ProjectMemberRemoved:  env.String("KAFKA_TOPIC_PROJECT_MEMBER_REMOVED", "project_member_removed"),
+				NotificationsCritical: env.String("KAFKA_TOPIC_NOTIFICATIONS_CRITICAL", "notifications_critical"),
+				Notifications:         env.String("KAFKA_TOPIC_NOTIFICATIONS", "notifications"),
+				NotificationsDigest:   env.String("KAFKA_TOPIC_NOTIFICATIONS_DIGEST", "notifications_digest"),
+			},
+			Delays: DelayQueueConfig{
+				Key:          env.String("DELAY_QUEUE_KEY", "delayed_notifications"),
+				PollInterval: env.Duration("DELAY_QUEUE_POLL_INTERVAL", time.Second),
+				BatchSize:    env.Int("DELAY_QUEUE_BATCH_SIZE", 100),
+				Lease:        env.Duration("DELAY_QUEUE_LEASE", time.Minute),
+			},
+			Outbox: OutboxConfig{
+				PollInterval: env.Duration("OUTBOX_POLL_INTERVAL", time.Second),
+				BatchSize:    env.Int("OUTBOX_BATCH_SIZE", 100),
+				MaxAttempts:  env.Int("OUTBOX_MAX_ATTEMPTS", 10),
 			},
 		},
 		JWT: JWTConfig{
-			Secret:           getEnv("JWT_SECRET", "your-secret-key-change-in-production"),
-			AccessExpiresIn:  getEnvAsDuration("JWT_ACCESS_EXPIRES_IN", 15*time.Minute),
-			RefreshExpiresIn: getEnvAsDuration("JWT_REFRESH_EXPIRES_IN", 7*24*time.Hour),
-			Issuer:           getEnv("JWT_ISSUER", "task-tracker"),
+			Secret:           env.String("JWT_SECRET", defaultJWTSecret),
+			AccessExpiresIn:  env.Duration("JWT_ACCESS_EXPIRES_IN", 15*time.Minute),
+			RefreshExpiresIn: env.Duration("JWT_REFRESH_EXPIRES_IN", 7*24*time.Hour),
+			Issuer:           env.String("JWT_ISSUER", "task-tracker"),
 		},
 		Scheduler: SchedulerConfig{
-			DailyDigestCron:      getEnv("SCHEDULER_DAILY_DIGEST_CRON", "0 8 * * *"),
-			DeadlineReminderCron: getEnv("SCHEDULER_DEADLINE_REMINDER_CRON", "0 9 * * *"),
+			DailyDigestCron:      env.String("SCHEDULER_DAILY_DIGEST_CRON", "0 0 8 * * *"),
+			DeadlineReminderCron: env.String("SCHEDULER_DEADLINE_REMINDER_CRON", "0 0 9 * * *"),
+			StaleTaskCron:        env.String("SCHEDULER_STALE_TASK_CRON", "0 0 6 * * *"),
+			StaleTaskDays:        env.Int("SCHEDULER_STALE_TASK_DAYS", 0),
+			InboxReminderCron:    env.String("SCHEDULER_INBOX_REMINDER_CRON", "0 0 10 * * *"),
+			InboxReminderDays:    env.Int("SCHEDULER_INBOX_REMINDER_DAYS", 3),
+			ConsistencyCron:      env.String("SCHEDULER_CONSISTENCY_CRON", "0 0 4 * * 0"),
+			DigestWindow:         env.DateFilters("SCHEDULER_DIGEST_WINDOW"),
+			DigestWorkers:        env.Int("SCHEDULER_DIGEST_WORKERS", 8),
+			JobRunRetentionDays:  env.Int("SCHEDULER_JOB_RUN_RETENTION_DAYS", 30),
+			JobAlertWebhookURL:   env.String("SCHEDULER_JOB_ALERT_WEBHOOK_URL", ""),
 		},
 		Notifier: NotifierConfig{
+			MaxInFlight:         env.Int("NOTIFIER_MAX_IN_FLIGHT", 50),
+			CriticalMaxInFlight: env.Int("NOTIFIER_CRITICAL_MAX_IN_FLIGHT", 20),
+			DigestMaxInFlight:   env.Int("NOTIFIER_DIGEST_MAX_IN_FLIGHT", 5),
+			Breaker: BreakerConfig{
+				FailureThreshold: env.Int("NOTIFIER_BREAKER_FAILURES", 5),
+				OpenTimeout:      env.Duration("NOTIFIER_BREAKER_OPEN_TIMEOUT", 30*time.Second),
+				MaxConcurrent:    env.Int("NOTIFIER_BREAKER_MAX_CONCURRENT", 20),
+			},
 			SMTP: SMTPConfig{
-				Host:     getEnv("SMTP_HOST", "localhost"),
-				Port:     getEnv("SMTP_PORT", "1025"),
-				Username: getEnv("SMTP_USER", ""),
-				Password: getEnv("SMTP_PASSWORD", ""),
-				From:     getEnv("SMTP_FROM", "noreply@tasktracker.com"),
+				Host:     env.String("SMTP_HOST", "localhost"),
+				Port:     env.String("SMTP_PORT", "1025"),
+				Username: env.String("SMTP_USER", ""),
+				Password: env.String("SMTP_PASSWORD", ""),
+				From:     env.String("SMTP_FROM", "noreply@tasktracker.com"),
+				Timeout:  env.Duration("SMTP_TIMEOUT", 30*time.Second),
 			},
 			Telegram: TelegramConfig{
-				Token: getEnv("TELEGRAM_TOKEN", ""),
+				Token:         env.String("TELEGRAM_TOKEN", ""),
+				RateLimit:     env.Float("TELEGRAM_RATE_LIMIT", 25),
+				ChatInterval:  env.Duration("TELEGRAM_CHAT_INTERVAL", time.Second),
+				MaxRetries:    env.Int("TELEGRAM_MAX_RETRIES", 3),
+				WebhookSecret: env.String("TELEGRAM_WEBHOOK_SECRET", ""),
+			},
+			Teams: TeamsConfig{
+				AllowedHosts: env.List("TEAMS_ALLOWED_HOSTS", "webhook.office.com,logic.azure.com,powerplatform.com"),
+				Timeout:      env.Duration("TEAMS_TIMEOUT", 10*time.Second),
+			},
+			Discord: DiscordConfig{
+				BotToken: env.String("DISCORD_BOT_TOKEN", ""),
+				APIURL:   env.String("DISCORD_API_URL", "https://discord.com/api/v10"),
+				Timeout:  env.Duration("DISCORD_TIMEOUT", 10*time.Second),
+			},
+			Matrix: MatrixConfig{
+				HomeserverURL: env.String("MATRIX_HOMESERVER_URL", ""),
+				AccessToken:   env.String("MATRIX_ACCESS_TOKEN", ""),
+				Timeout:       env.Duration("MATRIX_TIMEOUT", 10*time.Second),
+			},
+			SMS: SMSConfig{
+				Provider:      env.String("SMS_PROVIDER", ""),
+				CriticalTypes: env.List("SMS_CRITICAL_TYPES", "sla_breach,task_overdue:critical"),
+				MonthlyQuota:  env.Int("SMS_MONTHLY_QUOTA", 30),
+				CodeTTL:       env.Duration("SMS_CODE_TTL", 10*time.Minute),
+				Timeout:       env.Duration("SMS_TIMEOUT", 10*time.Second),
+				Twilio: TwilioConfig{
+					AccountSID: env.String("TWILIO_ACCOUNT_SID", ""),
+					AuthToken:  env.String("TWILIO_AUTH_TOKEN", ""),
+					From:       env.String("TWILIO_FROM", ""),
+					APIURL:     env.String("TWILIO_API_URL", "https://api.twilio.com"),
+				},
+				SNS: SNSConfig{
+					Region:          env.String("AWS_REGION", "us-east-1"),
+					AccessKeyID:     env.String("AWS_ACCESS_KEY_ID", ""),
+					SecretAccessKey: env.String("AWS_SECRET_ACCESS_KEY", ""),
+					SenderID:        env.String("SNS_SENDER_ID", ""),
+				},
+			},
+			Unsubscribe: UnsubscribeConfig{
+				Secret:    env.String("UNSUBSCRIBE_SECRET", defaultUnsubscribeSecret),
+				ExpiresIn: env.Duration("UNSUBSCRIBE_LINK_EXPIRES_IN", 30*24*time.Hour),
 			},
 		},
+		Inbound: InboundEmailConfig{
+			Domain:        env.String("INBOUND_EMAIL_DOMAIN", "tasks.tasktracker.com"),
+			Mailbox:       env.String("INBOUND_EMAIL_MAILBOX", "project"),
+			Maildir:       env.String("INBOUND_EMAIL_MAILDIR", ""),
+			PollInterval:  env.Duration("INBOUND_EMAIL_POLL_INTERVAL", 30*time.Second),
+			MaxSize:       int64(env.Int("INBOUND_EMAIL_MAX_SIZE", 10*1024*1024)),
+			SpamThreshold: env.Float("INBOUND_EMAIL_SPAM_THRESHOLD", 5.0),
+			AuthServID:    env.String("INBOUND_EMAIL_AUTHSERV_ID", ""),
+		},
+		Incidents: IncidentConfig{
+			PagerDutyAPIURL: env.String("PAGERDUTY_API_URL", "https://api.pagerduty.com"),
+			OpsgenieAPIURL:  env.String("OPSGENIE_API_URL", "https://api.opsgenie.com"),
+			Timeout:         env.Duration("INCIDENT_API_TIMEOUT", 10*time.Second),
+		},
 		Telegram: TelegramConfig{
-			Token: getEnv("TELEGRAM_TOKEN", ""),
+			Token:         env.String("TELEGRAM_TOKEN", ""),
+			RateLimit:     env.Float("TELEGRAM_RATE_LIMIT", 25),
+			ChatInterval:  env.Duration("TELEGRAM_CHAT_INTERVAL", time.Second),
+			MaxRetries:    env.Int("TELEGRAM_MAX_RETRIES", 3),
+			WebhookSecret: env.String("TELEGRAM_WEBHOOK_SECRET", ""),
+		},
+		Storage: StorageConfig{
+			Backend:     env.String("STORAGE_BACKEND", "local"),
+			LocalDir:    env.String("STORAGE_LOCAL_DIR", "./data/attachments"),
+			MaxFileSize: int64(env.Int("ATTACHMENT_MAX_SIZE", 25*1024*1024)),
+			S3: S3Config{
+				Endpoint:        env.String("S3_ENDPOINT", ""),
+				Region:          env.String("S3_REGION", "us-east-1"),
+				Bucket:          env.String("S3_BUCKET", ""),
+				AccessKeyID:     env.String("S3_ACCESS_KEY_ID", ""),
+				SecretAccessKey: env.String("S3_SECRET_ACCESS_KEY", ""),
+				Timeout:         env.Duration("S3_TIMEOUT", 5*time.Minute),
+			},
 		},
 		Monitoring: MonitoringConfig{
-			PrometheusEnabled: getEnvAsBool("PROMETHEUS_ENABLED", false),
-			PrometheusPort:    getEnv("PROMETHEUS_PORT", "9090"),
+			PrometheusEnabled: env.Bool("PROMETHEUS_ENABLED", false),
+			PrometheusPort:    env.String("PROMETHEUS_PORT", "9090"),
+		},
+		Features: FeaturesConfig{
+			Rollouts:        env.Rollouts("FEATURE_FLAGS"),
+			RefreshInterval: env.Duration("FEATURE_FLAGS_REFRESH", 10*time.Second),
 		},
 	}
 
+	config.Kafka.applyNamespace()
+
+	config.settings = env.settings
+	config.problems = append(problems, env.problems...)
+
 	return config, nil
 }
 
@@ -210,40 +534,38 @@ func (c *DatabaseConfig) DSN() string {
 		c.Host, c.Port, c.Username, c.Password, c.Database, c.SSLMode)
 }
 
-// RedisAddr возвращает адрес подключения к Redis
-func (c *RedisConfig) RedisAddr() string {
-	return fmt.Sprintf("%s:%s", c.Host, c.Port)
-}
-
-// Утилитарные функции для получения переменных окружения
-
-func getEnv(key, defaultValue string) string {
-	if value, exists := os.LookupEnv(key); exists {
-		return value
-	}
-	return defaultValue
-}
-
-func getEnvAsInt(key string, defaultValue int) int {
-	valueStr := getEnv(key, "")
-	if value, err := strconv.Atoi(valueStr); err == nil {
-		return value
+// Namespaced возвращает имя топика, группы потребителей или ключа Redis в пространстве
+// имен развертывания. Развертывания с разными пространствами имен не читают события
+// друг друга, и поток событий одного не задерживает уведомления другого
+func (c *KafkaConfig) Namespaced(name string) string {
+	if c.Namespace == "" {
+		return name
 	}
-	return defaultValue
+	return c.Namespace + "." + name
 }
 
-func getEnvAsBool(key string, defaultValue bool) bool {
-	valueStr := getEnv(key, "")
-	if value, err := strconv.ParseBool(valueStr); err == nil {
-		return value
+// applyNamespace переносит названия топиков и ключ очереди отложенных уведомлений
+// в пространство имен развертывания
+func (c *KafkaConfig) applyNamespace() {
+	for _, topic := range []*string{
+		&c.Topics.TaskCreated,
+		&c.Topics.TaskUpdated,
+		&c.Topics.TaskAssigned,
+		&c.Topics.TaskCommented,
+		&c.Topics.ProjectCreated,
+		&c.Topics.ProjectUpdated,
+		&c.Topics.ProjectMemberAdded,
+		&c.Topics.ProjectMemberRemoved,
+		&c.Topics.NotificationsCritical,
+		&c.Topics.Notifications,
+		&c.Topics.NotificationsDigest,
+	} {
+		*topic = c.Namespaced(*topic)
 	}
-	return defaultValue
+	c.Delays.Key = c.Namespaced(c.Delays.Key)
 }
 
-func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
-	valueStr := getEnv(key, "")
-	if value, err := time.ParseDuration(valueStr); err == nil {
-		return value
-	}
-	return defaultValue
+// RedisAddr возвращает адрес подключения к Redis
+func (c *RedisConfig) RedisAddr() string {
+	return fmt.Sprintf("%s:%s", c.Host, c.Port)
 }
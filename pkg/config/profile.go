@@ -0,0 +1,248 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+
+	"github.com/nurlyy/task_manager/pkg/datefilter"
+)
+
+// Profile определяет окружение, под которое собирается конфигурация
+type Profile string
+
+const (
+	// ProfileDev - локальная разработка; значения по умолчанию рассчитаны на него
+	ProfileDev Profile = "dev"
+	// ProfileStage - тестовый стенд
+	ProfileStage Profile = "stage"
+	// ProfileProd - рабочее окружение
+	ProfileProd Profile = "prod"
+)
+
+// profileDefaults переопределяет значения по умолчанию для профиля.
+// Переменные окружения и файлы .env имеют приоритет над ними
+var profileDefaults = map[Profile]map[string]string{
+	ProfileDev: {},
+	ProfileStage: {
+		"APP_DEBUG":  "false",
+		"DB_SSLMODE": "require",
+	},
+	ProfileProd: {
+		"APP_DEBUG":  "false",
+		"LOG_LEVEL":  "info",
+		"DB_SSLMODE": "require",
+	},
+}
+
+// ParseProfile разбирает имя профиля из APP_ENV, допуская полные названия окружений
+func ParseProfile(name string) (Profile, bool) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "dev", "development", "local":
+		return ProfileDev, true
+	case "stage", "staging":
+		return ProfileStage, true
+	case "prod", "production":
+		return ProfileProd, true
+	}
+	return ProfileDev, false
+}
+
+// Источники значений настроек
+const (
+	SourceEnv     = "env"     // Переменная окружения или файл .env
+	SourceProfile = "profile" // Значение по умолчанию профиля
+	SourceDefault = "default" // Значение по умолчанию приложения
+)
+
+// ResolvedSetting описывает итоговое значение настройки и его источник
+type ResolvedSetting struct {
+	Key    string
+	Value  string
+	Source string
+}
+
+// envLoader читает настройки из окружения с учетом профиля, запоминает итоговые
+// значения для вывода и собирает ошибки разбора вместо молчаливой подстановки значений по умолчанию
+type envLoader struct {
+	defaults map[string]string
+	settings []ResolvedSetting
+	seen     map[string]bool
+	problems []string
+}
+
+// loadEnvFiles определяет профиль и загружает файлы .env.<профиль> и .env.
+// Уже заданные переменные окружения файлы не переопределяют
+func loadEnvFiles() (Profile, []string) {
+	var problems []string
+
+	name, ok := os.LookupEnv("APP_ENV")
+	if !ok {
+		if values, err := godotenv.Read(".env"); err == nil {
+			name = values["APP_ENV"]
+		}
+	}
+	profile, ok := ParseProfile(name)
+	if !ok {
+		problems = append(problems, fmt.Sprintf("APP_ENV: unknown profile %q, expected dev, stage or prod", name))
+	}
+
+	for _, file := range []string{".env." + string(profile), ".env"} {
+		if _, err := os.Stat(file); err != nil {
+			continue
+		}
+		if err := godotenv.Load(file); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", file, err))
+		}
+	}
+
+	return profile, problems
+}
+
+// newEnvLoader создает загрузчик настроек для профиля
+func newEnvLoader(profile Profile) *envLoader {
+	return &envLoader{
+		defaults: profileDefaults[profile],
+		seen:     make(map[string]bool),
+	}
+}
+
+// lookup возвращает значение настройки: из окружения, из профиля или по умолчанию
+func (l *envLoader) lookup(key, defaultValue string) string {
+	value, source := defaultValue, SourceDefault
+	if envValue, ok := os.LookupEnv(key); ok {
+		value, source = envValue, SourceEnv
+	} else if profileValue, ok := l.defaults[key]; ok {
+		value, source = profileValue, SourceProfile
+	}
+
+	if !l.seen[key] {
+		l.seen[key] = true
+		l.settings = append(l.settings, ResolvedSetting{Key: key, Value: value, Source: source})
+	}
+	return value
+}
+
+// invalid запоминает ошибку разбора и возвращает значение по умолчанию
+func (l *envLoader) invalid(key, kind, value string) {
+	l.problems = append(l.problems, fmt.Sprintf("%s: invalid %s %q", key, kind, value))
+}
+
+// String возвращает строковую настройку
+func (l *envLoader) String(key, defaultValue string) string {
+	return l.lookup(key, defaultValue)
+}
+
+// List возвращает настройку-список через запятую
+func (l *envLoader) List(key, defaultValue string) []string {
+	return strings.Split(l.lookup(key, defaultValue), ",")
+}
+
+// Int возвращает целочисленную настройку. Пустое значение означает значение по умолчанию
+func (l *envLoader) Int(key string, defaultValue int) int {
+	raw := l.lookup(key, strconv.Itoa(defaultValue))
+	if raw == "" {
+		return defaultValue
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		l.invalid(key, "integer", raw)
+		return defaultValue
+	}
+	return value
+}
+
+// Float возвращает дробную настройку
+func (l *envLoader) Float(key string, defaultValue float64) float64 {
+	raw := l.lookup(key, strconv.FormatFloat(defaultValue, 'g', -1, 64))
+	if raw == "" {
+		return defaultValue
+	}
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		l.invalid(key, "number", raw)
+		return defaultValue
+	}
+	return value
+}
+
+// Bool возвращает логическую настройку
+func (l *envLoader) Bool(key string, defaultValue bool) bool {
+	raw := l.lookup(key, strconv.FormatBool(defaultValue))
+	if raw == "" {
+		return defaultValue
+	}
+	value, err := strconv.ParseBool(raw)
+	if err != nil {
+		l.invalid(key, "boolean", raw)
+		return defaultValue
+	}
+	return value
+}
+
+// Duration возвращает настройку-длительность в формате time.ParseDuration
+func (l *envLoader) Duration(key string, defaultValue time.Duration) time.Duration {
+	raw := l.lookup(key, defaultValue.String())
+	if raw == "" {
+		return defaultValue
+	}
+	value, err := time.ParseDuration(raw)
+	if err != nil {
+		l.invalid(key, "duration", raw)
+		return defaultValue
+	}
+	return value
+}
+
+// DateFilters разбирает список условий по датам задач через запятую, например due:<7d,updated:>30d
+func (l *envLoader) DateFilters(key string) []string {
+	var conditions []string
+	for _, item := range strings.Split(l.lookup(key, ""), ",") {
+		if item = strings.TrimSpace(item); item == "" {
+			continue
+		}
+		if _, err := datefilter.Parse(item); err != nil {
+			l.invalid(key, "date condition", item)
+			continue
+		}
+		conditions = append(conditions, item)
+	}
+	return conditions
+}
+
+// Rollouts разбирает список "флаг=процент" через запятую.
+// Вместо процента допускаются on и off
+func (l *envLoader) Rollouts(key string) map[string]int {
+	rollouts := make(map[string]int)
+	for _, item := range strings.Split(l.lookup(key, ""), ",") {
+		if item = strings.TrimSpace(item); item == "" {
+			continue
+		}
+
+		name, value, _ := strings.Cut(item, "=")
+		name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+		if name == "" {
+			l.invalid(key, "feature rollout", item)
+			continue
+		}
+
+		switch value {
+		case "on":
+			rollouts[name] = 100
+		case "off":
+			rollouts[name] = 0
+		default:
+			percentage, err := strconv.Atoi(value)
+			if err != nil || percentage < 0 || percentage > 100 {
+				l.invalid(key, "feature rollout", item)
+				continue
+			}
+			rollouts[name] = percentage
+		}
+	}
+	return rollouts
+}
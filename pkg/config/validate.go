@@ -0,0 +1,280 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// Значения секретов по умолчанию допустимы только для локальной разработки
+const (
+	defaultJWTSecret         = "your-secret-key-change-in-production"
+	defaultUnsubscribeSecret = "your-unsubscribe-secret-change-in-production"
+)
+
+// minSecretLength - минимальная длина секретов подписи вне профиля dev
+const minSecretLength = 32
+
+// secretKeyMarkers - части имен настроек, значения которых скрываются при выводе
+var secretKeyMarkers = []string{"PASSWORD", "SECRET", "TOKEN", "ACCESS_KEY"}
+
+// sslModes - допустимые значения DB_SSLMODE
+var sslModes = map[string]bool{
+	"disable": true, "allow": true, "prefer": true, "require": true, "verify-ca": true, "verify-full": true,
+}
+
+// kafkaNamespacePattern - допустимые значения KAFKA_NAMESPACE: префикс должен оставаться
+// допустимой частью названия топика Kafka
+var kafkaNamespacePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// cronParser разбирает расписания так же, как планировщик: с полем секунд
+var cronParser = cron.NewParser(cron.Second | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+// ValidationError содержит все проблемы конфигурации, найденные проверкой
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid configuration: " + strings.Join(e.Problems, "; ")
+}
+
+// configChecker накапливает проблемы конфигурации
+type configChecker struct {
+	problems []string
+}
+
+// check добавляет проблему, если условие не выполнено
+func (c *configChecker) check(ok bool, format string, args ...interface{}) {
+	if !ok {
+		c.problems = append(c.problems, fmt.Sprintf(format, args...))
+	}
+}
+
+// port проверяет номер порта
+func (c *configChecker) port(key, value string) {
+	port, err := strconv.Atoi(value)
+	c.check(err == nil && port > 0 && port < 65536, "%s: invalid port %q", key, value)
+}
+
+// positive проверяет, что длительность больше нуля
+func (c *configChecker) positive(key string, value time.Duration) {
+	c.check(value > 0, "%s: must be positive", key)
+}
+
+// absoluteURL проверяет, что пустое значение допустимо или задан абсолютный URL
+func (c *configChecker) absoluteURL(key, value string, required bool) {
+	if value == "" {
+		c.check(!required, "%s: required", key)
+		return
+	}
+	parsed, err := url.Parse(value)
+	c.check(err == nil && (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != "",
+		"%s: invalid URL %q", key, value)
+}
+
+// secret проверяет секрет подписи: вне dev он должен быть задан явно и быть достаточно длинным
+func (c *configChecker) secret(profile Profile, key, value, defaultValue string) {
+	c.check(value != "", "%s: required", key)
+	if profile == ProfileDev {
+		return
+	}
+	c.check(value != defaultValue, "%s: default value is not allowed in %s profile", key, profile)
+	c.check(len(value) >= minSecretLength, "%s: must be at least %d characters in %s profile", key, minSecretLength, profile)
+}
+
+// cron проверяет расписание планировщика
+func (c *configChecker) cron(key, spec string) {
+	_, err := cronParser.Parse(spec)
+	c.check(err == nil, "%s: invalid schedule %q: %v", key, spec, err)
+}
+
+// Validate проверяет согласованность конфигурации с учетом профиля.
+// Возвращает *ValidationError со всеми найденными проблемами
+func (c *Config) Validate() error {
+	v := &configChecker{problems: append([]string(nil), c.problems...)}
+	profile := c.App.Profile
+	strict := profile != ProfileDev
+
+	// Приложение и HTTP-сервер
+	v.absoluteURL("BASE_URL", c.App.BaseURL, strict)
+	v.check(!(profile == ProfileProd && c.App.Debug), "APP_DEBUG: must be disabled in prod profile")
+	v.port("HTTP_PORT", c.HTTP.Port)
+	v.positive("HTTP_READ_TIMEOUT", c.HTTP.ReadTimeout)
+	v.positive("HTTP_READ_HEADER_TIMEOUT", c.HTTP.ReadHeaderTimeout)
+	v.positive("HTTP_WRITE_TIMEOUT", c.HTTP.WriteTimeout)
+	v.positive("HTTP_IDLE_TIMEOUT", c.HTTP.IdleTimeout)
+	v.positive("HTTP_SHUTDOWN_TIMEOUT", c.HTTP.ShutdownTimeout)
+	v.positive("HTTP_REQUEST_TIMEOUT", c.HTTP.RequestTimeout)
+	v.positive("HTTP_EXPORT_TIMEOUT", c.HTTP.ExportTimeout)
+	v.positive("HTTP_UPLOAD_TIMEOUT", c.HTTP.UploadTimeout)
+	v.check(c.HTTP.StreamTimeout > time.Minute, "HTTP_STREAM_TIMEOUT: must be longer than 1m")
+	v.check(c.HTTP.MaxHeaderBytes > 0, "HTTP_MAX_HEADER_BYTES: must be positive")
+	v.check(c.HTTP.MaxBodyBytes > 0, "HTTP_MAX_BODY_BYTES: must be positive")
+
+	// База данных
+	v.check(c.Database.Host != "", "DB_HOST: required")
+	v.port("DB_PORT", c.Database.Port)
+	v.check(c.Database.Username != "", "DB_USER: required")
+	v.check(c.Database.Database != "", "DB_NAME: required")
+	v.check(sslModes[c.Database.SSLMode], "DB_SSLMODE: invalid mode %q", c.Database.SSLMode)
+	v.check(!(profile == ProfileProd && c.Database.SSLMode == "disable"), "DB_SSLMODE: must not be disable in prod profile")
+	v.check(c.Database.MaxOpenConns > 0, "DB_MAX_OPEN_CONNS: must be positive")
+	v.check(c.Database.MaxIdleConns >= 0 && c.Database.MaxIdleConns <= c.Database.MaxOpenConns,
+		"DB_MAX_IDLE_CONNS: must be between 0 and DB_MAX_OPEN_CONNS")
+	v.check(c.Database.SlowQueryThreshold >= 0, "DB_SLOW_QUERY_THRESHOLD: must not be negative")
+	v.check(c.Database.SchemaMismatch == "refuse" || c.Database.SchemaMismatch == "readonly",
+		"DB_SCHEMA_MISMATCH: invalid value %q, expected refuse or readonly", c.Database.SchemaMismatch)
+
+	// Redis и Kafka
+	v.check(c.Redis.Host != "", "REDIS_HOST: required")
+	v.port("REDIS_PORT", c.Redis.Port)
+	v.check(c.Redis.DB >= 0, "REDIS_DB: must not be negative")
+	v.positive("REDIS_DEFAULT_TTL", c.Redis.DefaultTTL)
+	v.positive("REDIS_MEMBER_TTL", c.Redis.MemberTTL)
+	v.positive("REDIS_SETTINGS_TTL", c.Redis.SettingsTTL)
+	for _, broker := range c.Kafka.Brokers {
+		v.check(strings.TrimSpace(broker) != "", "KAFKA_BROKERS: empty broker address")
+	}
+	v.check(c.Kafka.Namespace == "" || kafkaNamespacePattern.MatchString(c.Kafka.Namespace),
+		"KAFKA_NAMESPACE: only letters, digits, '_' and '-' are allowed")
+	topics := c.Kafka.Topics
+	v.check(topics.NotificationsCritical != topics.Notifications && topics.NotificationsCritical != topics.NotificationsDigest &&
+		topics.Notifications != topics.NotificationsDigest,
+		"KAFKA_TOPIC_NOTIFICATIONS_CRITICAL, KAFKA_TOPIC_NOTIFICATIONS, KAFKA_TOPIC_NOTIFICATIONS_DIGEST: must be distinct")
+	v.check(c.Kafka.Delays.Key != "", "DELAY_QUEUE_KEY: required")
+	v.positive("DELAY_QUEUE_POLL_INTERVAL", c.Kafka.Delays.PollInterval)
+	v.check(c.Kafka.Delays.BatchSize > 0, "DELAY_QUEUE_BATCH_SIZE: must be positive")
+	v.positive("DELAY_QUEUE_LEASE", c.Kafka.Delays.Lease)
+	v.positive("OUTBOX_POLL_INTERVAL", c.Kafka.Outbox.PollInterval)
+	v.check(c.Kafka.Outbox.BatchSize > 0, "OUTBOX_BATCH_SIZE: must be positive")
+	v.check(c.Kafka.Outbox.MaxAttempts > 0, "OUTBOX_MAX_ATTEMPTS: must be positive")
+
+	// Аутентификация и подписанные ссылки
+	v.secret(profile, "JWT_SECRET", c.JWT.Secret, defaultJWTSecret)
+	v.positive("JWT_ACCESS_EXPIRES_IN", c.JWT.AccessExpiresIn)
+	v.check(c.JWT.RefreshExpiresIn > c.JWT.AccessExpiresIn, "JWT_REFRESH_EXPIRES_IN: must be longer than JWT_ACCESS_EXPIRES_IN")
+	v.secret(profile, "UNSUBSCRIBE_SECRET", c.Notifier.Unsubscribe.Secret, defaultUnsubscribeSecret)
+
+	// Планировщик
+	v.cron("SCHEDULER_DAILY_DIGEST_CRON", c.Scheduler.DailyDigestCron)
+	v.cron("SCHEDULER_DEADLINE_REMINDER_CRON", c.Scheduler.DeadlineReminderCron)
+	v.cron("SCHEDULER_STALE_TASK_CRON", c.Scheduler.StaleTaskCron)
+	v.check(c.Scheduler.StaleTaskDays >= 0, "SCHEDULER_STALE_TASK_DAYS: must not be negative")
+	v.cron("SCHEDULER_INBOX_REMINDER_CRON", c.Scheduler.InboxReminderCron)
+	v.check(c.Scheduler.InboxReminderDays >= 0, "SCHEDULER_INBOX_REMINDER_DAYS: must not be negative")
+	v.check(c.Scheduler.DigestWorkers > 0, "SCHEDULER_DIGEST_WORKERS: must be positive")
+	v.check(c.Scheduler.JobRunRetentionDays > 0, "SCHEDULER_JOB_RUN_RETENTION_DAYS: must be positive")
+	v.absoluteURL("SCHEDULER_JOB_ALERT_WEBHOOK_URL", c.Scheduler.JobAlertWebhookURL, false)
+	if c.Scheduler.ConsistencyCron != "" {
+		v.cron("SCHEDULER_CONSISTENCY_CRON", c.Scheduler.ConsistencyCron)
+	}
+
+	// Уведомления
+	v.check(c.Notifier.MaxInFlight > 0, "NOTIFIER_MAX_IN_FLIGHT: must be positive")
+	v.check(c.Notifier.CriticalMaxInFlight > 0, "NOTIFIER_CRITICAL_MAX_IN_FLIGHT: must be positive")
+	v.check(c.Notifier.DigestMaxInFlight > 0, "NOTIFIER_DIGEST_MAX_IN_FLIGHT: must be positive")
+	v.check(c.Notifier.Breaker.FailureThreshold > 0, "NOTIFIER_BREAKER_FAILURES: must be positive")
+	v.positive("NOTIFIER_BREAKER_OPEN_TIMEOUT", c.Notifier.Breaker.OpenTimeout)
+	v.check(c.Notifier.Breaker.MaxConcurrent > 0, "NOTIFIER_BREAKER_MAX_CONCURRENT: must be positive")
+	v.port("SMTP_PORT", c.Notifier.SMTP.Port)
+	v.positive("SMTP_TIMEOUT", c.Notifier.SMTP.Timeout)
+	v.check(c.Telegram.RateLimit > 0, "TELEGRAM_RATE_LIMIT: must be positive")
+	v.check(c.Telegram.MaxRetries >= 0, "TELEGRAM_MAX_RETRIES: must not be negative")
+	v.check(!(strict && c.Telegram.Token != "" && c.Telegram.WebhookSecret == ""),
+		"TELEGRAM_WEBHOOK_SECRET: required when TELEGRAM_TOKEN is set outside dev profile")
+	v.absoluteURL("DISCORD_API_URL", c.Notifier.Discord.APIURL, false)
+	v.absoluteURL("MATRIX_HOMESERVER_URL", c.Notifier.Matrix.HomeserverURL, c.Notifier.Matrix.AccessToken != "")
+
+	switch sms := c.Notifier.SMS; sms.Provider {
+	case "":
+	case "twilio":
+		v.check(sms.Twilio.AccountSID != "" && sms.Twilio.AuthToken != "" && sms.Twilio.From != "",
+			"TWILIO_ACCOUNT_SID, TWILIO_AUTH_TOKEN, TWILIO_FROM: required for twilio SMS provider")
+	case "sns":
+		v.check(sms.SNS.AccessKeyID != "" && sms.SNS.SecretAccessKey != "",
+			"AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY: required for sns SMS provider")
+	default:
+		v.check(false, "SMS_PROVIDER: unknown provider %q, expected twilio or sns", sms.Provider)
+	}
+
+	// Прием почты и инциденты
+	v.check(c.Inbound.PollInterval > 0, "INBOUND_EMAIL_POLL_INTERVAL: must be positive")
+	v.check(c.Inbound.MaxSize > 0, "INBOUND_EMAIL_MAX_SIZE: must be positive")
+	v.check(!(strict && c.Inbound.Maildir != "" && c.Inbound.AuthServID == ""),
+		"INBOUND_EMAIL_AUTHSERV_ID: required when INBOUND_EMAIL_MAILDIR is set outside dev profile")
+	v.absoluteURL("PAGERDUTY_API_URL", c.Incidents.PagerDutyAPIURL, false)
+	v.absoluteURL("OPSGENIE_API_URL", c.Incidents.OpsgenieAPIURL, false)
+
+	// Хранилище вложений
+	v.check(c.Storage.MaxFileSize > 0, "ATTACHMENT_MAX_SIZE: must be positive")
+	switch storage := c.Storage; storage.Backend {
+	case "local":
+		v.check(storage.LocalDir != "", "STORAGE_LOCAL_DIR: required for local storage backend")
+	case "s3":
+		v.absoluteURL("S3_ENDPOINT", storage.S3.Endpoint, false)
+		v.check(storage.S3.Region != "", "S3_REGION: required for s3 storage backend")
+		v.check(storage.S3.Bucket != "", "S3_BUCKET: required for s3 storage backend")
+		v.check(storage.S3.AccessKeyID != "" && storage.S3.SecretAccessKey != "",
+			"S3_ACCESS_KEY_ID, S3_SECRET_ACCESS_KEY: required for s3 storage backend")
+		v.positive("S3_TIMEOUT", storage.S3.Timeout)
+	default:
+		v.check(false, "STORAGE_BACKEND: unknown backend %q, expected local or s3", storage.Backend)
+	}
+
+	v.positive("FEATURE_FLAGS_REFRESH", c.Features.RefreshInterval)
+
+	if len(v.problems) > 0 {
+		return &ValidationError{Problems: v.problems}
+	}
+	return nil
+}
+
+// Settings возвращает итоговые значения настроек; значения секретов скрыты
+func (c *Config) Settings() []ResolvedSetting {
+	settings := make([]ResolvedSetting, len(c.settings))
+	for i, setting := range c.settings {
+		if isSecretKey(setting.Key) && setting.Value != "" {
+			setting.Value = "******"
+		}
+		settings[i] = setting
+	}
+	return settings
+}
+
+// Report выводит итоговую конфигурацию со скрытыми секретами и результат проверки.
+// Возвращает ошибку Validate, чтобы команда проверки могла завершиться с ненулевым кодом
+func (c *Config) Report(w io.Writer) error {
+	fmt.Fprintf(w, "Profile: %s\n\n", c.App.Profile)
+	for _, setting := range c.Settings() {
+		fmt.Fprintf(w, "%s=%s\t(%s)\n", setting.Key, setting.Value, setting.Source)
+	}
+
+	err := c.Validate()
+	if validationErr, ok := err.(*ValidationError); ok {
+		fmt.Fprintf(w, "\nConfiguration is invalid:\n")
+		for _, problem := range validationErr.Problems {
+			fmt.Fprintf(w, "  - %s\n", problem)
+		}
+		return err
+	}
+
+	fmt.Fprintf(w, "\nConfiguration is valid\n")
+	return nil
+}
+
+// isSecretKey проверяет, содержит ли настройка секрет
+func isSecretKey(key string) bool {
+	for _, marker := range secretKeyMarkers {
+		if strings.Contains(key, marker) {
+			return true
+		}
+	}
+	return false
+}
@@ -2,11 +2,12 @@ package database
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"time"
 
 	"github.com/jmoiron/sqlx"
-	_ "github.com/lib/pq" // драйвер PostgreSQL
+	"github.com/lib/pq"
 
 	"github.com/nurlyy/task_manager/pkg/config"
 	"github.com/nurlyy/task_manager/pkg/logger"
@@ -14,9 +15,10 @@ import (
 
 // Postgres представляет клиент для работы с PostgreSQL
 type Postgres struct {
-	DB     *sqlx.DB
-	Config *config.DatabaseConfig
-	Logger logger.Logger
+	DB      *sqlx.DB
+	Metrics *QueryMetrics
+	Config  *config.DatabaseConfig
+	Logger  logger.Logger
 }
 
 // NewPostgres создает новое подключение к PostgreSQL
@@ -28,11 +30,15 @@ func NewPostgres(ctx context.Context, cfg *config.DatabaseConfig, log logger.Log
 		"db":   cfg.Database,
 	})
 
-	db, err := sqlx.ConnectContext(ctx, "postgres", cfg.DSN())
+	connector, err := pq.NewConnector(cfg.DSN())
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to PostgreSQL: %w", err)
 	}
 
+	// Все запросы проходят через слой замера длительности
+	metrics := NewQueryMetrics(cfg.SlowQueryThreshold, log)
+	db := sqlx.NewDb(sql.OpenDB(&timedConnector{connector: connector, metrics: metrics}), "postgres")
+
 	// Настройка пула соединений
 	db.SetMaxOpenConns(cfg.MaxOpenConns)
 	db.SetMaxIdleConns(cfg.MaxIdleConns)
@@ -46,9 +52,10 @@ func NewPostgres(ctx context.Context, cfg *config.DatabaseConfig, log logger.Log
 	log.Info("Successfully connected to PostgreSQL")
 
 	return &Postgres{
-		DB:     db,
-		Config: cfg,
-		Logger: log,
+		DB:      db,
+		Metrics: metrics,
+		Config:  cfg,
+		Logger:  log,
 	}, nil
 }
 
@@ -0,0 +1,141 @@
+package database
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nurlyy/task_manager/pkg/logger"
+)
+
+// queryDurationBuckets задает границы корзин гистограммы длительности запросов в секундах
+var queryDurationBuckets = []float64{0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// slowQueryMaxLength ограничивает длину текста запроса в журнале медленных запросов
+const slowQueryMaxLength = 1000
+
+// queryStats содержит накопленную статистику по одному имени запроса
+type queryStats struct {
+	buckets []uint64
+	count   uint64
+	sum     float64
+	errors  uint64
+	slow    uint64
+}
+
+// QueryMetrics собирает гистограммы длительности SQL-запросов по именам запросов
+// и журналирует медленные запросы. Отдает метрики в текстовом формате Prometheus
+type QueryMetrics struct {
+	mu            sync.Mutex
+	stats         map[string]*queryStats
+	slowThreshold time.Duration
+	logger        logger.Logger
+}
+
+// NewQueryMetrics создает новый экземпляр QueryMetrics.
+// Нулевой slowThreshold отключает журнал медленных запросов
+func NewQueryMetrics(slowThreshold time.Duration, logger logger.Logger) *QueryMetrics {
+	return &QueryMetrics{
+		stats:         make(map[string]*queryStats),
+		slowThreshold: slowThreshold,
+		logger:        logger,
+	}
+}
+
+// observe учитывает выполненный запрос. Значения параметров запроса в журнал не попадают
+func (m *QueryMetrics) observe(name, query string, argCount int, elapsed time.Duration, err error) {
+	seconds := elapsed.Seconds()
+	slow := m.slowThreshold > 0 && elapsed >= m.slowThreshold
+
+	m.mu.Lock()
+	stats, ok := m.stats[name]
+	if !ok {
+		stats = &queryStats{buckets: make([]uint64, len(queryDurationBuckets))}
+		m.stats[name] = stats
+	}
+	for i, bound := range queryDurationBuckets {
+		if seconds <= bound {
+			stats.buckets[i]++
+		}
+	}
+	stats.count++
+	stats.sum += seconds
+	if err != nil {
+		stats.errors++
+	}
+	if slow {
+		stats.slow++
+	}
+	m.mu.Unlock()
+
+	if slow {
+		m.logger.Warn("Slow query", map[string]interface{}{
+			"query":       name,
+			"duration_ms": elapsed.Milliseconds(),
+			"sql":         compactQuery(query),
+			"args":        argCount,
+			"failed":      err != nil,
+		})
+	}
+}
+
+// WritePrometheus записывает метрики в текстовом формате Prometheus
+func (m *QueryMetrics) WritePrometheus(w io.Writer) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	names := make([]string, 0, len(m.stats))
+	for name := range m.stats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteString("# HELP db_query_duration_seconds Duration of database queries by repository method.\n")
+	b.WriteString("# TYPE db_query_duration_seconds histogram\n")
+	for _, name := range names {
+		stats := m.stats[name]
+		for i, bound := range queryDurationBuckets {
+			fmt.Fprintf(&b, "db_query_duration_seconds_bucket{query=%q,le=\"%g\"} %d\n", name, bound, stats.buckets[i])
+		}
+		fmt.Fprintf(&b, "db_query_duration_seconds_bucket{query=%q,le=\"+Inf\"} %d\n", name, stats.count)
+		fmt.Fprintf(&b, "db_query_duration_seconds_sum{query=%q} %g\n", name, stats.sum)
+		fmt.Fprintf(&b, "db_query_duration_seconds_count{query=%q} %d\n", name, stats.count)
+	}
+
+	b.WriteString("# HELP db_query_errors_total Database queries that returned an error.\n")
+	b.WriteString("# TYPE db_query_errors_total counter\n")
+	for _, name := range names {
+		fmt.Fprintf(&b, "db_query_errors_total{query=%q} %d\n", name, m.stats[name].errors)
+	}
+
+	b.WriteString("# HELP db_slow_queries_total Database queries slower than the configured threshold.\n")
+	b.WriteString("# TYPE db_slow_queries_total counter\n")
+	for _, name := range names {
+		fmt.Fprintf(&b, "db_slow_queries_total{query=%q} %d\n", name, m.stats[name].slow)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// ServeHTTP отдает метрики запросов для сборщика Prometheus
+func (m *QueryMetrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	if err := m.WritePrometheus(w); err != nil {
+		m.logger.Error("Failed to write query metrics", err)
+	}
+}
+
+// compactQuery схлопывает пробельные символы запроса и обрезает слишком длинный текст
+func compactQuery(query string) string {
+	query = strings.Join(strings.Fields(query), " ")
+	if len(query) > slowQueryMaxLength {
+		query = query[:slowQueryMaxLength] + "..."
+	}
+	return query
+}
@@ -0,0 +1,58 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// SchemaVersion - версия схемы базы, на которую рассчитан код: номер последней миграции.
+// Обновляется вместе с каждой новой миграцией
+const SchemaVersion = 49
+
+// SchemaStatus описывает совместимость схемы базы с кодом
+type SchemaStatus struct {
+	Expected       int // Версия схемы, на которую рассчитан код
+	Version        int // Версия схемы базы; 0, если версия не записана
+	CompatibleFrom int // Самая ранняя версия схемы, код для которой может работать с базой
+}
+
+// Compatible проверяет, может ли код работать с базой: все нужные ему миграции применены,
+// а примененные сужающие миграции не удалили то, что он использует
+func (s *SchemaStatus) Compatible() bool {
+	return s.Version >= s.Expected && s.CompatibleFrom <= s.Expected
+}
+
+// Problem описывает несовместимость схемы; для совместимой схемы возвращает пустую строку
+func (s *SchemaStatus) Problem() string {
+	switch {
+	case s.Version < s.Expected:
+		return fmt.Sprintf("database schema version %d is older than %d expected by this build: apply migrations first", s.Version, s.Expected)
+	case s.CompatibleFrom > s.Expected:
+		return fmt.Sprintf("database schema version %d requires a build for schema %d or newer, this build expects %d", s.Version, s.CompatibleFrom, s.Expected)
+	default:
+		return ""
+	}
+}
+
+// CheckSchema читает версию схемы базы и сравнивает ее с версией, на которую рассчитан код.
+// База без записанной версии считается базой версии 0
+func CheckSchema(ctx context.Context, db *sqlx.DB) (*SchemaStatus, error) {
+	status := &SchemaStatus{Expected: SchemaVersion}
+
+	err := db.QueryRowxContext(ctx, `SELECT version, compatible_from FROM schema_version`).
+		Scan(&status.Version, &status.CompatibleFrom)
+	var pqErr *pq.Error
+	if errors.Is(err, sql.ErrNoRows) || errors.As(err, &pqErr) && pqErr.Code == "42P01" { // undefined_table
+		return status, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema version: %w", err)
+	}
+
+	return status, nil
+}
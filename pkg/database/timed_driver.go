@@ -0,0 +1,218 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// modulePrefix - префикс пакетов приложения, по которому ищется вызвавший запрос метод
+const modulePrefix = "github.com/nurlyy/task_manager/"
+
+// timedConnector оборачивает соединения драйвера, замеряя длительность запросов
+type timedConnector struct {
+	connector driver.Connector
+	metrics   *QueryMetrics
+}
+
+// Connect открывает соединение с замером запросов
+func (c *timedConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &timedConn{Conn: conn, metrics: c.metrics}, nil
+}
+
+// Driver возвращает исходный драйвер
+func (c *timedConnector) Driver() driver.Driver {
+	return c.connector.Driver()
+}
+
+// timedConn замеряет запросы, выполняемые через соединение, в том числе внутри транзакций.
+// Остальные вызовы передаются исходному соединению без изменений
+type timedConn struct {
+	driver.Conn
+	metrics *QueryMetrics
+}
+
+// QueryContext выполняет запрос с замером длительности
+func (c *timedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	queryer, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+
+	start := time.Now()
+	rows, err := queryer.QueryContext(ctx, query, args)
+	c.observe(query, len(args), time.Since(start), err)
+	return rows, err
+}
+
+// ExecContext выполняет команду с замером длительности
+func (c *timedConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	execer, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+
+	start := time.Now()
+	result, err := execer.ExecContext(ctx, query, args)
+	c.observe(query, len(args), time.Since(start), err)
+	return result, err
+}
+
+// PrepareContext подготавливает запрос; выполнения подготовленного выражения тоже замеряются
+func (c *timedConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	var (
+		stmt driver.Stmt
+		err  error
+	)
+	if preparer, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		stmt, err = preparer.PrepareContext(ctx, query)
+	} else {
+		stmt, err = c.Conn.Prepare(query)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &timedStmt{Stmt: stmt, query: query, metrics: c.metrics}, nil
+}
+
+// BeginTx начинает транзакцию
+func (c *timedConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if beginner, ok := c.Conn.(driver.ConnBeginTx); ok {
+		return beginner.BeginTx(ctx, opts)
+	}
+	return c.Conn.Begin()
+}
+
+// Ping проверяет соединение
+func (c *timedConn) Ping(ctx context.Context) error {
+	if pinger, ok := c.Conn.(driver.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
+
+// ResetSession сбрасывает состояние соединения перед повторным использованием
+func (c *timedConn) ResetSession(ctx context.Context) error {
+	if resetter, ok := c.Conn.(driver.SessionResetter); ok {
+		return resetter.ResetSession(ctx)
+	}
+	return nil
+}
+
+// IsValid сообщает, можно ли вернуть соединение в пул
+func (c *timedConn) IsValid() bool {
+	if validator, ok := c.Conn.(driver.Validator); ok {
+		return validator.IsValid()
+	}
+	return true
+}
+
+// observe учитывает запрос, если драйвер действительно его выполнил
+func (c *timedConn) observe(query string, argCount int, elapsed time.Duration, err error) {
+	if errors.Is(err, driver.ErrSkip) {
+		return
+	}
+	c.metrics.observe(queryName(), query, argCount, elapsed, err)
+}
+
+// timedStmt замеряет выполнения подготовленного выражения
+type timedStmt struct {
+	driver.Stmt
+	query   string
+	metrics *QueryMetrics
+}
+
+// QueryContext выполняет подготовленный запрос с замером длительности
+func (s *timedStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	start := time.Now()
+	var (
+		rows driver.Rows
+		err  error
+	)
+	if queryer, ok := s.Stmt.(driver.StmtQueryContext); ok {
+		rows, err = queryer.QueryContext(ctx, args)
+	} else {
+		var values []driver.Value
+		if values, err = namedValuesToValues(args); err == nil {
+			rows, err = s.Stmt.Query(values)
+		}
+	}
+	s.metrics.observe(queryName(), s.query, len(args), time.Since(start), err)
+	return rows, err
+}
+
+// ExecContext выполняет подготовленную команду с замером длительности
+func (s *timedStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	start := time.Now()
+	var (
+		result driver.Result
+		err    error
+	)
+	if execer, ok := s.Stmt.(driver.StmtExecContext); ok {
+		result, err = execer.ExecContext(ctx, args)
+	} else {
+		var values []driver.Value
+		if values, err = namedValuesToValues(args); err == nil {
+			result, err = s.Stmt.Exec(values)
+		}
+	}
+	s.metrics.observe(queryName(), s.query, len(args), time.Since(start), err)
+	return result, err
+}
+
+// namedValuesToValues преобразует параметры для драйверов без поддержки контекста
+func namedValuesToValues(args []driver.NamedValue) ([]driver.Value, error) {
+	values := make([]driver.Value, len(args))
+	for i, arg := range args {
+		if arg.Name != "" {
+			return nil, errors.New("database: driver does not support named parameters")
+		}
+		values[i] = arg.Value
+	}
+	return values, nil
+}
+
+// queryName возвращает имя запроса по вызвавшему его методу приложения,
+// например "TaskRepository.List"
+func queryName() string {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(3, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if strings.HasPrefix(frame.Function, modulePrefix) && !strings.HasPrefix(frame.Function, modulePrefix+"pkg/database.") {
+			return shortFuncName(frame.Function)
+		}
+		if !more {
+			return "unknown"
+		}
+	}
+}
+
+// shortFuncName сокращает полное имя функции до вида "Тип.Метод" без пути пакета и замыканий
+func shortFuncName(function string) string {
+	name := function[strings.LastIndex(function, "/")+1:]
+	if i := strings.Index(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+	name = strings.NewReplacer("(*", "", ")", "").Replace(name)
+
+	// Запросы из замыканий относим к объемлющему методу
+	parts := strings.Split(name, ".")
+	for len(parts) > 1 && isClosureName(parts[len(parts)-1]) {
+		parts = parts[:len(parts)-1]
+	}
+	return strings.Join(parts, ".")
+}
+
+// isClosureName проверяет, что часть имени функции обозначает замыкание ("func1", "2")
+func isClosureName(part string) bool {
+	return strings.HasPrefix(part, "func") || strings.Trim(part, "0123456789") == ""
+}
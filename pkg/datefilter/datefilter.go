@@ -0,0 +1,165 @@
+// Package datefilter разбирает условия по датам задач вида поле:<значение и поле:>значение,
+// например due:<7d или updated:>30d. Значение - относительный интервал (12h, 30d, 2w)
+// или дата YYYY-MM-DD. Относительные условия вычисляются в момент запроса, поэтому
+// сохраненные фильтры и дайджесты задают скользящие окна, а не фиксированные даты.
+//
+// Интервал отсчитывается от текущего момента: для срока (due) - вперед, для остальных
+// полей - назад. Так due:<7d - срок наступает менее чем через 7 дней (включая просроченные),
+// due:>7d - позже чем через 7 дней, updated:>30d - задача не обновлялась более 30 дней,
+// created:<1w - создана за последнюю неделю. Для дат < означает раньше указанного дня,
+// а > - позже него
+package datefilter
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidCondition возвращается, если условие не является поддерживаемым условием по дате
+var ErrInvalidCondition = errors.New("datefilter: invalid condition")
+
+// Field определяет поле задачи, к которому относится условие
+type Field string
+
+const (
+	FieldDue       Field = "due"
+	FieldCreated   Field = "created"
+	FieldUpdated   Field = "updated"
+	FieldCompleted Field = "completed"
+)
+
+// dateLayout - формат даты в условии
+const dateLayout = "2006-01-02"
+
+// maxAmount ограничивает величину интервала, чтобы даты не уходили за пределы разумного
+const maxAmount = 100000
+
+// units сопоставляет единицы интервала с их длительностью
+var units = map[byte]time.Duration{
+	'h': time.Hour,
+	'd': 24 * time.Hour,
+	'w': 7 * 24 * time.Hour,
+}
+
+// Condition представляет условие по дате задачи
+type Condition struct {
+	Field  Field
+	Before bool          // true для <, false для >
+	Offset time.Duration // Относительный интервал; не используется, если задана Date
+	Date   *time.Time    // Дата для условий с абсолютным значением
+	raw    string
+}
+
+// Parse разбирает условие вида поле:<значение или поле:>значение
+func Parse(value string) (Condition, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	name, expr, found := strings.Cut(value, ":")
+	if !found || len(expr) < 2 {
+		return Condition{}, fmt.Errorf("%w: %q must look like field:<value or field:>value", ErrInvalidCondition, value)
+	}
+
+	cond := Condition{Field: Field(name)}
+	switch cond.Field {
+	case FieldDue, FieldCreated, FieldUpdated, FieldCompleted:
+	default:
+		return Condition{}, fmt.Errorf("%w: unsupported field %q", ErrInvalidCondition, name)
+	}
+
+	switch expr[0] {
+	case '<':
+		cond.Before = true
+	case '>':
+	default:
+		return Condition{}, fmt.Errorf("%w: operator must be < or >", ErrInvalidCondition)
+	}
+
+	operand := expr[1:]
+	if date, err := time.Parse(dateLayout, operand); err == nil {
+		cond.Date = &date
+	} else {
+		offset, err := parseOffset(operand)
+		if err != nil {
+			return Condition{}, fmt.Errorf("%w: %v", ErrInvalidCondition, err)
+		}
+		cond.Offset = offset
+	}
+
+	cond.raw = value
+	return cond, nil
+}
+
+// String возвращает условие в исходном виде
+func (c Condition) String() string {
+	return c.raw
+}
+
+// Bound возвращает границу, которую задает условие в момент now, и сообщает,
+// должно ли значение поля быть не позже границы (true) или не раньше нее (false)
+func (c Condition) Bound(now time.Time) (time.Time, bool) {
+	if c.Date != nil {
+		if c.Before {
+			// Раньше указанного дня
+			return c.Date.Add(-time.Nanosecond), true
+		}
+		// Позже указанного дня
+		return c.Date.AddDate(0, 0, 1), false
+	}
+
+	if c.Field == FieldDue {
+		return now.Add(c.Offset), c.Before
+	}
+	// Для прошедших событий "меньше интервала назад" означает "позже границы"
+	return now.Add(-c.Offset), !c.Before
+}
+
+// Window - период, в который должно попадать значение поля; nil означает отсутствие границы
+type Window struct {
+	After  *time.Time
+	Before *time.Time
+}
+
+// Windows содержит периоды по полям задачи
+type Windows map[Field]Window
+
+// Resolve вычисляет периоды по условиям в момент now. Несколько условий
+// по одному полю сужают период
+func Resolve(conditions []Condition, now time.Time) Windows {
+	windows := make(Windows)
+	for _, cond := range conditions {
+		bound, before := cond.Bound(now)
+		if before {
+			windows.Restrict(cond.Field, nil, &bound)
+		} else {
+			windows.Restrict(cond.Field, &bound, nil)
+		}
+	}
+	return windows
+}
+
+// Restrict сужает период поля указанными границами
+func (w Windows) Restrict(field Field, after, before *time.Time) {
+	window := w[field]
+	if after != nil && (window.After == nil || after.After(*window.After)) {
+		window.After = after
+	}
+	if before != nil && (window.Before == nil || before.Before(*window.Before)) {
+		window.Before = before
+	}
+	w[field] = window
+}
+
+// parseOffset разбирает интервал вида 12h, 30d или 2w
+func parseOffset(value string) (time.Duration, error) {
+	unit, ok := units[value[len(value)-1]]
+	if !ok {
+		return 0, fmt.Errorf("value %q must be a date YYYY-MM-DD or an interval like 12h, 30d, 2w", value)
+	}
+	amount, err := strconv.Atoi(value[:len(value)-1])
+	if err != nil || amount < 0 || amount > maxAmount {
+		return 0, fmt.Errorf("interval %q must be a number between 0 and %d followed by h, d or w", value, maxAmount)
+	}
+	return time.Duration(amount) * unit, nil
+}
@@ -0,0 +1,535 @@
+package errors
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// Code - машиночитаемый код ошибки API. Клиенты ветвятся по коду, а не по тексту
+// сообщения, поэтому коды стабильны: существующий код нельзя переименовать или
+// назначить ему другой HTTP-статус, можно только добавить новый
+type Code string
+
+// Коды ошибок API
+const (
+	CodeAccessDenied                Code = "access_denied"
+	CodeAddMemberFailed             Code = "add_member_failed"
+	CodeAlreadyOwner                Code = "already_owner"
+	CodeArchiveFailed               Code = "archive_failed"
+	CodeAssigneeUpdateFailed        Code = "assignee_update_failed"
+	CodeAttachmentFailed            Code = "attachment_failed"
+	CodeAttachmentNotFound          Code = "attachment_not_found"
+	CodeAttachmentTooLarge          Code = "attachment_too_large"
+	CodeAuditFetchFailed            Code = "audit_fetch_failed"
+	CodeBacklogReportFailed         Code = "backlog_report_failed"
+	CodeBadRequest                  Code = "bad_request"
+	CodeBoardColumnExists           Code = "board_column_exists"
+	CodeBoardColumnNotFound         Code = "board_column_not_found"
+	CodeBoardFailed                 Code = "board_failed"
+	CodeBoardLastColumn             Code = "board_last_column"
+	CodeCodeExpired                 Code = "code_expired"
+	CodeCommentFetchFailed          Code = "comment_fetch_failed"
+	CodeCommentNotFound             Code = "comment_not_found"
+	CodeCommentsFetchFailed         Code = "comments_fetch_failed"
+	CodeConflict                    Code = "conflict"
+	CodeConsistencyFailed           Code = "consistency_failed"
+	CodeConsistencyReportNotFound   Code = "consistency_report_not_found"
+	CodeConsistencyRunning          Code = "consistency_running"
+	CodeCreationFailed              Code = "creation_failed"
+	CodeDecisionFailed              Code = "decision_failed"
+	CodeDecisionNotFound            Code = "decision_not_found"
+	CodeDeleteFailed                Code = "delete_failed"
+	CodeDirectoryFetchFailed        Code = "directory_fetch_failed"
+	CodeDiscordBotNotConfigured     Code = "discord_bot_not_configured"
+	CodeDiscordChannelNotFound      Code = "discord_channel_not_found"
+	CodeDiscordFailed               Code = "discord_failed"
+	CodeDiscordUnreachable          Code = "discord_unreachable"
+	CodeDiscordWebhookNotAllowed    Code = "discord_webhook_not_allowed"
+	CodeDraftDeleteFailed           Code = "draft_delete_failed"
+	CodeDraftFetchFailed            Code = "draft_fetch_failed"
+	CodeDraftNotFound               Code = "draft_not_found"
+	CodeDraftSaveFailed             Code = "draft_save_failed"
+	CodeEffortSplitFailed           Code = "effort_split_failed"
+	CodeEmailExists                 Code = "email_exists"
+	CodeEpicFailed                  Code = "epic_failed"
+	CodeEpicNotFound                Code = "epic_not_found"
+	CodeEpicProjectMismatch         Code = "epic_project_mismatch"
+	CodeFeatureFlagFailed           Code = "feature_flag_failed"
+	CodeFeedbackAlreadyModerated    Code = "feedback_already_moderated"
+	CodeFeedbackFailed              Code = "feedback_failed"
+	CodeFeedbackPortalNotFound      Code = "feedback_portal_not_found"
+	CodeFeedbackSubmissionNotFound  Code = "feedback_submission_not_found"
+	CodeForbidden                   Code = "forbidden"
+	CodeHistoryFetchFailed          Code = "history_fetch_failed"
+	CodeInboundEmailFailed          Code = "inbound_email_failed"
+	CodeInboundEmailNotFound        Code = "inbound_email_not_found"
+	CodeInboundEmailRejected        Code = "inbound_email_rejected"
+	CodeInboxFailed                 Code = "inbox_failed"
+	CodeInboxItemNotFound           Code = "inbox_item_not_found"
+	CodeIncidentIntegrationFailed   Code = "incident_integration_failed"
+	CodeIncidentIntegrationNotFound Code = "incident_integration_not_found"
+	CodeInsufficientRights          Code = "insufficient_rights"
+	CodeInsufficientScope           Code = "insufficient_scope"
+	CodeInternalError               Code = "internal_error"
+	CodeInvalidApprover             Code = "invalid_approver"
+	CodeInvalidAssignee             Code = "invalid_assignee"
+	CodeInvalidBoardOrder           Code = "invalid_board_order"
+	CodeInvalidCode                 Code = "invalid_code"
+	CodeInvalidCredentials          Code = "invalid_credentials"
+	CodeInvalidCSV                  Code = "invalid_csv"
+	CodeInvalidCursor               Code = "invalid_cursor"
+	CodeInvalidDays                 Code = "invalid_days"
+	CodeInvalidEffortSplit          Code = "invalid_effort_split"
+	CodeInvalidEmailHeader          Code = "invalid_email_header"
+	CodeInvalidFeatureFlag          Code = "invalid_feature_flag"
+	CodeInvalidFormat               Code = "invalid_format"
+	CodeInvalidInput                Code = "invalid_input"
+	CodeInvalidLimit                Code = "invalid_limit"
+	CodeInvalidLink                 Code = "invalid_link"
+	CodeInvalidMatrixRoom           Code = "invalid_matrix_room"
+	CodeInvalidMinFreeHours         Code = "invalid_min_free_hours"
+	CodeInvalidMove                 Code = "invalid_move"
+	CodeInvalidOwner                Code = "invalid_owner"
+	CodeInvalidParent               Code = "invalid_parent"
+	CodeInvalidPassword             Code = "invalid_password"
+	CodeInvalidPeriod               Code = "invalid_period"
+	CodeInvalidRecurrence           Code = "invalid_recurrence"
+	CodeInvalidScheduleTime         Code = "invalid_schedule_time"
+	CodeInvalidSignature            Code = "invalid_signature"
+	CodeInvalidSplit                Code = "invalid_split"
+	CodeInvalidSprintDates          Code = "invalid_sprint_dates"
+	CodeInvalidSprintState          Code = "invalid_sprint_state"
+	CodeInvalidStatus               Code = "invalid_status"
+	CodeInvalidTask                 Code = "invalid_task"
+	CodeInvalidToken                Code = "invalid_token"
+	CodeInvalidUserID               Code = "invalid_user_id"
+	CodeInvalidVersion              Code = "invalid_version"
+	CodeInvitationEmailMismatch     Code = "invitation_email_mismatch"
+	CodeInvitationExpired           Code = "invitation_expired"
+	CodeInvitationFailed            Code = "invitation_failed"
+	CodeInvitationNotFound          Code = "invitation_not_found"
+	CodeJobRunsFetchFailed          Code = "job_runs_fetch_failed"
+	CodeKeyResultNotFound           Code = "key_result_not_found"
+	CodeLinkExists                  Code = "link_exists"
+	CodeLinkNotFound                Code = "link_not_found"
+	CodeLockFailed                  Code = "lock_failed"
+	CodeLockNotHeld                 Code = "lock_not_held"
+	CodeLogTimeFailed               Code = "log_time_failed"
+	CodeLoginFailed                 Code = "login_failed"
+	CodeMaintenance                 Code = "maintenance"
+	CodeMaintenanceFailed           Code = "maintenance_failed"
+	CodeMaintenanceForced           Code = "maintenance_forced"
+	CodeManagerCycle                Code = "manager_cycle"
+	CodeManagerNotFound             Code = "manager_not_found"
+	CodeMarkAllReadFailed           Code = "mark_all_read_failed"
+	CodeMarkReadFailed              Code = "mark_read_failed"
+	CodeMatrixFailed                Code = "matrix_failed"
+	CodeMatrixNotConfigured         Code = "matrix_not_configured"
+	CodeMatrixRoomNotFound          Code = "matrix_room_not_found"
+	CodeMatrixUnreachable           Code = "matrix_unreachable"
+	CodeMeetingNoteFailed           Code = "meeting_note_failed"
+	CodeMeetingNoteNotFound         Code = "meeting_note_not_found"
+	CodeMemberExists                Code = "member_exists"
+	CodeMemberNotFound              Code = "member_not_found"
+	CodeMetricsFetchFailed          Code = "metrics_fetch_failed"
+	CodeMissingID                   Code = "missing_id"
+	CodeMissingMemberID             Code = "missing_member_id"
+	CodeMissingQuery                Code = "missing_query"
+	CodeMissingTaskID               Code = "missing_task_id"
+	CodeMissingToken                Code = "missing_token"
+	CodeMoveFailed                  Code = "move_failed"
+	CodeNotFound                    Code = "not_found"
+	CodeNotificationFetchFailed     Code = "notification_fetch_failed"
+	CodeNotificationNotFound        Code = "notification_not_found"
+	CodeNotificationsFetchFailed    Code = "notifications_fetch_failed"
+	CodeObjectiveNotFound           Code = "objective_not_found"
+	CodeOKRFailed                   Code = "okr_failed"
+	CodeOpenSubtasks                Code = "open_subtasks"
+	CodeOwnerNotRemovable           Code = "owner_not_removable"
+	CodeOwnershipTransferFailed     Code = "ownership_transfer_failed"
+	CodePasswordChangeFailed        Code = "password_change_failed"
+	CodePayloadTooLarge             Code = "payload_too_large"
+	CodePermissionDenied            Code = "permission_denied"
+	CodePhoneNotSet                 Code = "phone_not_set"
+	CodePhoneNotVerified            Code = "phone_not_verified"
+	CodeProjectArchived             Code = "project_archived"
+	CodeProjectFetchFailed          Code = "project_fetch_failed"
+	CodeProjectNotArchived          Code = "project_not_archived"
+	CodeProjectNotFound             Code = "project_not_found"
+	CodeProjectViewExists           Code = "project_view_exists"
+	CodeProjectViewFailed           Code = "project_view_failed"
+	CodeProjectViewNotFound         Code = "project_view_not_found"
+	CodeProjectsFetchFailed         Code = "projects_fetch_failed"
+	CodeRateLimited                 Code = "rate_limited"
+	CodeRefreshFailed               Code = "refresh_failed"
+	CodeRemoveMemberFailed          Code = "remove_member_failed"
+	CodeReportingChainFailed        Code = "reporting_chain_failed"
+	CodeReportsFetchFailed          Code = "reports_fetch_failed"
+	CodeReprioritizeFailed          Code = "reprioritize_failed"
+	CodeRequestTimeout              Code = "request_timeout"
+	CodeRestoreFailed               Code = "restore_failed"
+	CodeRevisionNotFound            Code = "revision_not_found"
+	CodeRoadmapFailed               Code = "roadmap_failed"
+	CodeRoadmapNotFound             Code = "roadmap_not_found"
+	CodeSameProject                 Code = "same_project"
+	CodeScheduledTaskFailed         Code = "scheduled_task_failed"
+	CodeScheduledTaskNotFound       Code = "scheduled_task_not_found"
+	CodeScheduledTaskProcessed      Code = "scheduled_task_processed"
+	CodeSearchFailed                Code = "search_failed"
+	CodeServiceUnavailable          Code = "service_unavailable"
+	CodeSettingsFetchFailed         Code = "settings_fetch_failed"
+	CodeSettingsUpdateFailed        Code = "settings_update_failed"
+	CodeSMSFailed                   Code = "sms_failed"
+	CodeSMSNotConfigured            Code = "sms_not_configured"
+	CodeSMSQuotaExceeded            Code = "sms_quota_exceeded"
+	CodeSplitEmpty                  Code = "split_empty"
+	CodeSplitFailed                 Code = "split_failed"
+	CodeSplitTooLarge               Code = "split_too_large"
+	CodeSprintActive                Code = "sprint_active"
+	CodeSprintFailed                Code = "sprint_failed"
+	CodeSprintNotFound              Code = "sprint_not_found"
+	CodeSprintProjectMismatch       Code = "sprint_project_mismatch"
+	CodeStatusNoteFailed            Code = "status_note_failed"
+	CodeStatusNoteNotFound          Code = "status_note_not_found"
+	CodeStatusUpdateFailed          Code = "status_update_failed"
+	CodeTagExists                   Code = "tag_exists"
+	CodeTagInUse                    Code = "tag_in_use"
+	CodeTagNotFound                 Code = "tag_not_found"
+	CodeTagsFailed                  Code = "tags_failed"
+	CodeTaskAlreadyLinked           Code = "task_already_linked"
+	CodeTaskClosed                  Code = "task_closed"
+	CodeTaskFetchFailed             Code = "task_fetch_failed"
+	CodeTaskFormFailed              Code = "task_form_failed"
+	CodeTaskLocked                  Code = "task_locked"
+	CodeTaskNotFound                Code = "task_not_found"
+	CodeTaskRecurrenceFailed        Code = "task_recurrence_failed"
+	CodeTaskRecurrenceNotFound      Code = "task_recurrence_not_found"
+	CodeTaskTemplateExists          Code = "task_template_exists"
+	CodeTaskTemplateFailed          Code = "task_template_failed"
+	CodeTaskTemplateNotFound        Code = "task_template_not_found"
+	CodeTasksExportFailed           Code = "tasks_export_failed"
+	CodeTasksFetchFailed            Code = "tasks_fetch_failed"
+	CodeTeamsChannelNotFound        Code = "teams_channel_not_found"
+	CodeTeamsFailed                 Code = "teams_failed"
+	CodeTeamsWebhookNotAllowed      Code = "teams_webhook_not_allowed"
+	CodeTeamsWebhookUnreachable     Code = "teams_webhook_unreachable"
+	CodeTimeLogsFetchFailed         Code = "time_logs_fetch_failed"
+	CodeTimeRecalculationFailed     Code = "time_recalculation_failed"
+	CodeTooManyAttempts             Code = "too_many_attempts"
+	CodeTooManyPending              Code = "too_many_pending"
+	CodeTransferExpired             Code = "transfer_expired"
+	CodeTransferNotFound            Code = "transfer_not_found"
+	CodeTransferPending             Code = "transfer_pending"
+	CodeUnauthorized                Code = "unauthorized"
+	CodeUnreadCountFailed           Code = "unread_count_failed"
+	CodeUnsubscribeFailed           Code = "unsubscribe_failed"
+	CodeUnsupportedProvider         Code = "unsupported_provider"
+	CodeUpdateFailed                Code = "update_failed"
+	CodeUpdateRoleFailed            Code = "update_role_failed"
+	CodeUserFetchFailed             Code = "user_fetch_failed"
+	CodeUserNotFound                Code = "user_not_found"
+	CodeUsersFetchFailed            Code = "users_fetch_failed"
+	CodeValidationFailed            Code = "validation_failed"
+	CodeVersionConflict             Code = "version_conflict"
+	CodeWebhookDisabled             Code = "webhook_disabled"
+	CodeWebhookExists               Code = "webhook_exists"
+	CodeWebhookFailed               Code = "webhook_failed"
+	CodeWebhookNotFound             Code = "webhook_not_found"
+	CodeWikiFailed                  Code = "wiki_failed"
+	CodeWikiPageNotFound            Code = "wiki_page_not_found"
+)
+
+// Definition описывает код ошибки в каталоге
+type Definition struct {
+	Code    Code   `json:"code"`
+	Status  int    `json:"status"`
+	Title   string `json:"title"`
+	DocsURL string `json:"docs_url,omitempty"`
+}
+
+// catalog содержит все коды ошибок API с их HTTP-статусами и описаниями
+var catalog = newCatalog(
+	Definition{Code: CodeAccessDenied, Status: http.StatusForbidden, Title: "Access denied"},
+	Definition{Code: CodeAddMemberFailed, Status: http.StatusInternalServerError, Title: "Failed to add member"},
+	Definition{Code: CodeAlreadyOwner, Status: http.StatusBadRequest, Title: "User is already the project owner"},
+	Definition{Code: CodeArchiveFailed, Status: http.StatusInternalServerError, Title: "Failed to archive project"},
+	Definition{Code: CodeAssigneeUpdateFailed, Status: http.StatusInternalServerError, Title: "Failed to update task assignees"},
+	Definition{Code: CodeAttachmentFailed, Status: http.StatusInternalServerError, Title: "Failed to process attachment"},
+	Definition{Code: CodeAttachmentNotFound, Status: http.StatusNotFound, Title: "Attachment not found"},
+	Definition{Code: CodeAttachmentTooLarge, Status: http.StatusRequestEntityTooLarge, Title: "Attachment exceeds the maximum file size"},
+	Definition{Code: CodeAuditFetchFailed, Status: http.StatusInternalServerError, Title: "Failed to get audit log"},
+	Definition{Code: CodeBacklogReportFailed, Status: http.StatusInternalServerError, Title: "Failed to get backlog age report"},
+	Definition{Code: CodeBadRequest, Status: http.StatusBadRequest, Title: "Bad request"},
+	Definition{Code: CodeBoardColumnExists, Status: http.StatusConflict, Title: "Board column with this name or status already exists"},
+	Definition{Code: CodeBoardColumnNotFound, Status: http.StatusNotFound, Title: "Board column not found"},
+	Definition{Code: CodeBoardFailed, Status: http.StatusInternalServerError, Title: "Failed to process board"},
+	Definition{Code: CodeBoardLastColumn, Status: http.StatusConflict, Title: "Board must keep at least one column"},
+	Definition{Code: CodeCodeExpired, Status: http.StatusBadRequest, Title: "Verification code expired, request a new one"},
+	Definition{Code: CodeCommentFetchFailed, Status: http.StatusInternalServerError, Title: "Failed to get comment info"},
+	Definition{Code: CodeCommentNotFound, Status: http.StatusNotFound, Title: "Comment not found"},
+	Definition{Code: CodeCommentsFetchFailed, Status: http.StatusInternalServerError, Title: "Failed to get comments"},
+	Definition{Code: CodeConflict, Status: http.StatusConflict, Title: "Conflict"},
+	Definition{Code: CodeConsistencyFailed, Status: http.StatusInternalServerError, Title: "Failed to process consistency check request"},
+	Definition{Code: CodeConsistencyReportNotFound, Status: http.StatusNotFound, Title: "Consistency check has not been run yet"},
+	Definition{Code: CodeConsistencyRunning, Status: http.StatusConflict, Title: "Consistency check is already running"},
+	Definition{Code: CodeCreationFailed, Status: http.StatusInternalServerError, Title: "Failed to create resource"},
+	Definition{Code: CodeDecisionFailed, Status: http.StatusInternalServerError, Title: "Failed to process decision request"},
+	Definition{Code: CodeDecisionNotFound, Status: http.StatusNotFound, Title: "Decision not found"},
+	Definition{Code: CodeDeleteFailed, Status: http.StatusInternalServerError, Title: "Failed to delete resource"},
+	Definition{Code: CodeDirectoryFetchFailed, Status: http.StatusInternalServerError, Title: "Failed to get user directory"},
+	Definition{Code: CodeDiscordBotNotConfigured, Status: http.StatusBadRequest, Title: "Discord bot is not configured, use a channel webhook"},
+	Definition{Code: CodeDiscordChannelNotFound, Status: http.StatusNotFound, Title: "Discord channel not found"},
+	Definition{Code: CodeDiscordFailed, Status: http.StatusInternalServerError, Title: "Failed to process Discord request"},
+	Definition{Code: CodeDiscordUnreachable, Status: http.StatusBadGateway, Title: "Discord rejected the test message"},
+	Definition{Code: CodeDiscordWebhookNotAllowed, Status: http.StatusBadRequest, Title: "Webhook URL must be a Discord channel webhook"},
+	Definition{Code: CodeDraftDeleteFailed, Status: http.StatusInternalServerError, Title: "Failed to delete comment draft"},
+	Definition{Code: CodeDraftFetchFailed, Status: http.StatusInternalServerError, Title: "Failed to get comment draft"},
+	Definition{Code: CodeDraftNotFound, Status: http.StatusNotFound, Title: "Comment draft not found"},
+	Definition{Code: CodeDraftSaveFailed, Status: http.StatusInternalServerError, Title: "Failed to save comment draft"},
+	Definition{Code: CodeEffortSplitFailed, Status: http.StatusInternalServerError, Title: "Failed to process task effort split"},
+	Definition{Code: CodeEmailExists, Status: http.StatusConflict, Title: "Email already exists"},
+	Definition{Code: CodeEpicFailed, Status: http.StatusInternalServerError, Title: "Failed to process epic request"},
+	Definition{Code: CodeEpicNotFound, Status: http.StatusNotFound, Title: "Epic not found"},
+	Definition{Code: CodeEpicProjectMismatch, Status: http.StatusBadRequest, Title: "Epic belongs to another project"},
+	Definition{Code: CodeFeatureFlagFailed, Status: http.StatusInternalServerError, Title: "Failed to handle feature flag request"},
+	Definition{Code: CodeFeedbackAlreadyModerated, Status: http.StatusConflict, Title: "Feedback submission has already been moderated"},
+	Definition{Code: CodeFeedbackFailed, Status: http.StatusInternalServerError, Title: "Failed to process feedback request"},
+	Definition{Code: CodeFeedbackPortalNotFound, Status: http.StatusNotFound, Title: "Feedback portal not found"},
+	Definition{Code: CodeFeedbackSubmissionNotFound, Status: http.StatusNotFound, Title: "Feedback submission not found"},
+	Definition{Code: CodeForbidden, Status: http.StatusForbidden, Title: "Forbidden"},
+	Definition{Code: CodeHistoryFetchFailed, Status: http.StatusInternalServerError, Title: "Failed to get membership history"},
+	Definition{Code: CodeInboundEmailFailed, Status: http.StatusInternalServerError, Title: "Failed to process inbound email settings"},
+	Definition{Code: CodeInboundEmailNotFound, Status: http.StatusNotFound, Title: "Inbound email address is not generated for this project"},
+	Definition{Code: CodeInboundEmailRejected, Status: http.StatusUnprocessableEntity, Title: "Inbound email rejected"},
+	Definition{Code: CodeInboxFailed, Status: http.StatusInternalServerError, Title: "Failed to process inbox request"},
+	Definition{Code: CodeInboxItemNotFound, Status: http.StatusNotFound, Title: "Inbox item not found"},
+	Definition{Code: CodeIncidentIntegrationFailed, Status: http.StatusInternalServerError, Title: "Failed to process incident integration"},
+	Definition{Code: CodeIncidentIntegrationNotFound, Status: http.StatusNotFound, Title: "Incident integration not found"},
+	Definition{Code: CodeInsufficientRights, Status: http.StatusForbidden, Title: "Insufficient rights to perform this action"},
+	Definition{Code: CodeInsufficientScope, Status: http.StatusForbidden, Title: "Token scopes do not allow this action"},
+	Definition{Code: CodeInternalError, Status: http.StatusInternalServerError, Title: "Internal server error"},
+	Definition{Code: CodeInvalidApprover, Status: http.StatusBadRequest, Title: "Approver must be a member of the project"},
+	Definition{Code: CodeInvalidAssignee, Status: http.StatusBadRequest, Title: "Assignee must be a member of the project"},
+	Definition{Code: CodeInvalidBoardOrder, Status: http.StatusBadRequest, Title: "Column order must list every board column exactly once"},
+	Definition{Code: CodeInvalidCode, Status: http.StatusBadRequest, Title: "Invalid verification code"},
+	Definition{Code: CodeInvalidCredentials, Status: http.StatusUnauthorized, Title: "Invalid credentials"},
+	Definition{Code: CodeInvalidCSV, Status: http.StatusBadRequest, Title: "Invalid CSV roster, expected a header row with email and optional role columns"},
+	Definition{Code: CodeInvalidCursor, Status: http.StatusBadRequest, Title: "Invalid pagination cursor"},
+	Definition{Code: CodeInvalidDays, Status: http.StatusBadRequest, Title: "Days must be between 1 and 365"},
+	Definition{Code: CodeInvalidEffortSplit, Status: http.StatusBadRequest, Title: "Effort can only be split between task assignees, once per assignee"},
+	Definition{Code: CodeInvalidEmailHeader, Status: http.StatusBadRequest, Title: "Invalid email header"},
+	Definition{Code: CodeInvalidFeatureFlag, Status: http.StatusBadRequest, Title: "Feature flag name must contain only lowercase letters, digits and underscores"},
+	Definition{Code: CodeInvalidFormat, Status: http.StatusBadRequest, Title: "Invalid request format"},
+	Definition{Code: CodeInvalidInput, Status: http.StatusBadRequest, Title: "Invalid input"},
+	Definition{Code: CodeInvalidLimit, Status: http.StatusBadRequest, Title: "Invalid limit"},
+	Definition{Code: CodeInvalidLink, Status: http.StatusBadRequest, Title: "Invalid or expired link"},
+	Definition{Code: CodeInvalidMatrixRoom, Status: http.StatusBadRequest, Title: "Room must be a Matrix room ID or alias"},
+	Definition{Code: CodeInvalidMinFreeHours, Status: http.StatusBadRequest, Title: "Invalid min_free_hours"},
+	Definition{Code: CodeInvalidMove, Status: http.StatusBadRequest, Title: "Specify exactly one of after_id, before_id or position; the target must be another task of the same project"},
+	Definition{Code: CodeInvalidOwner, Status: http.StatusBadRequest, Title: "Invalid owner"},
+	Definition{Code: CodeInvalidParent, Status: http.StatusBadRequest, Title: "Parent page must belong to the project and must not be a descendant of the page"},
+	Definition{Code: CodeInvalidPassword, Status: http.StatusBadRequest, Title: "Invalid old password"},
+	Definition{Code: CodeInvalidPeriod, Status: http.StatusBadRequest, Title: "Invalid OKR period"},
+	Definition{Code: CodeInvalidRecurrence, Status: http.StatusBadRequest, Title: "Invalid recurrence rule"},
+	Definition{Code: CodeInvalidScheduleTime, Status: http.StatusBadRequest, Title: "Scheduled creation time must be in the future"},
+	Definition{Code: CodeInvalidSignature, Status: http.StatusUnauthorized, Title: "Invalid webhook signature"},
+	Definition{Code: CodeInvalidSplit, Status: http.StatusBadRequest, Title: "Specify exactly one of tag or epic_id"},
+	Definition{Code: CodeInvalidSprintDates, Status: http.StatusBadRequest, Title: "Sprint must end after it starts and last at most 90 days"},
+	Definition{Code: CodeInvalidSprintState, Status: http.StatusConflict, Title: "Operation is not allowed in the current sprint state"},
+	Definition{Code: CodeInvalidStatus, Status: http.StatusBadRequest, Title: "Invalid status transition"},
+	Definition{Code: CodeInvalidTask, Status: http.StatusBadRequest, Title: "Task does not belong to the project"},
+	Definition{Code: CodeInvalidToken, Status: http.StatusUnauthorized, Title: "Invalid or expired token"},
+	Definition{Code: CodeInvalidUserID, Status: http.StatusBadRequest, Title: "All settings must belong to the current user"},
+	Definition{Code: CodeInvalidVersion, Status: http.StatusBadRequest, Title: "Invalid revision version"},
+	Definition{Code: CodeInvitationEmailMismatch, Status: http.StatusForbidden, Title: "Invitation was sent to another email address"},
+	Definition{Code: CodeInvitationExpired, Status: http.StatusGone, Title: "Invitation has expired"},
+	Definition{Code: CodeInvitationFailed, Status: http.StatusInternalServerError, Title: "Failed to process invitation"},
+	Definition{Code: CodeInvitationNotFound, Status: http.StatusNotFound, Title: "Invitation not found"},
+	Definition{Code: CodeJobRunsFetchFailed, Status: http.StatusInternalServerError, Title: "Failed to get scheduler job runs"},
+	Definition{Code: CodeKeyResultNotFound, Status: http.StatusNotFound, Title: "Key result not found"},
+	Definition{Code: CodeLinkExists, Status: http.StatusConflict, Title: "Key result is already linked to this project or epic"},
+	Definition{Code: CodeLinkNotFound, Status: http.StatusNotFound, Title: "Key result link not found"},
+	Definition{Code: CodeLockFailed, Status: http.StatusInternalServerError, Title: "Failed to process edit lock"},
+	Definition{Code: CodeLockNotHeld, Status: http.StatusConflict, Title: "Edit lock is not held"},
+	Definition{Code: CodeLogTimeFailed, Status: http.StatusInternalServerError, Title: "Failed to log time"},
+	Definition{Code: CodeLoginFailed, Status: http.StatusInternalServerError, Title: "Login failed"},
+	Definition{Code: CodeMaintenance, Status: http.StatusServiceUnavailable, Title: "Service is under maintenance, changes are temporarily unavailable"},
+	Definition{Code: CodeMaintenanceFailed, Status: http.StatusInternalServerError, Title: "Failed to handle maintenance request"},
+	Definition{Code: CodeMaintenanceForced, Status: http.StatusConflict, Title: "Read-only mode is forced by instance configuration"},
+	Definition{Code: CodeManagerCycle, Status: http.StatusBadRequest, Title: "Manager assignment creates a reporting cycle"},
+	Definition{Code: CodeManagerNotFound, Status: http.StatusBadRequest, Title: "Manager not found"},
+	Definition{Code: CodeMarkAllReadFailed, Status: http.StatusInternalServerError, Title: "Failed to mark all notifications as read"},
+	Definition{Code: CodeMarkReadFailed, Status: http.StatusInternalServerError, Title: "Failed to mark notification as read"},
+	Definition{Code: CodeMatrixFailed, Status: http.StatusInternalServerError, Title: "Failed to process Matrix request"},
+	Definition{Code: CodeMatrixNotConfigured, Status: http.StatusServiceUnavailable, Title: "Matrix homeserver is not configured"},
+	Definition{Code: CodeMatrixRoomNotFound, Status: http.StatusNotFound, Title: "Matrix room not found"},
+	Definition{Code: CodeMatrixUnreachable, Status: http.StatusBadGateway, Title: "Failed to join the Matrix room or post a test message"},
+	Definition{Code: CodeMeetingNoteFailed, Status: http.StatusInternalServerError, Title: "Failed to process meeting note request"},
+	Definition{Code: CodeMeetingNoteNotFound, Status: http.StatusNotFound, Title: "Meeting note not found"},
+	Definition{Code: CodeMemberExists, Status: http.StatusConflict, Title: "User is already a member of the project"},
+	Definition{Code: CodeMemberNotFound, Status: http.StatusNotFound, Title: "Member not found"},
+	Definition{Code: CodeMetricsFetchFailed, Status: http.StatusInternalServerError, Title: "Failed to get project metrics"},
+	Definition{Code: CodeMissingID, Status: http.StatusBadRequest, Title: "Resource ID is required"},
+	Definition{Code: CodeMissingMemberID, Status: http.StatusBadRequest, Title: "Member ID is required"},
+	Definition{Code: CodeMissingQuery, Status: http.StatusBadRequest, Title: "Search query is required"},
+	Definition{Code: CodeMissingTaskID, Status: http.StatusBadRequest, Title: "Task ID is required"},
+	Definition{Code: CodeMissingToken, Status: http.StatusBadRequest, Title: "Unsubscribe token is required"},
+	Definition{Code: CodeMoveFailed, Status: http.StatusInternalServerError, Title: "Failed to move task"},
+	Definition{Code: CodeNotFound, Status: http.StatusNotFound, Title: "Resource not found"},
+	Definition{Code: CodeNotificationFetchFailed, Status: http.StatusInternalServerError, Title: "Failed to get notification info"},
+	Definition{Code: CodeNotificationNotFound, Status: http.StatusNotFound, Title: "Notification not found"},
+	Definition{Code: CodeNotificationsFetchFailed, Status: http.StatusInternalServerError, Title: "Failed to get notifications"},
+	Definition{Code: CodeObjectiveNotFound, Status: http.StatusNotFound, Title: "Objective not found"},
+	Definition{Code: CodeOKRFailed, Status: http.StatusInternalServerError, Title: "Failed to process OKR request"},
+	Definition{Code: CodeOpenSubtasks, Status: http.StatusConflict, Title: "Task has open subtasks"},
+	Definition{Code: CodeOwnerNotRemovable, Status: http.StatusConflict, Title: "Project owner cannot be removed, transfer ownership first"},
+	Definition{Code: CodeOwnershipTransferFailed, Status: http.StatusInternalServerError, Title: "Failed to process ownership transfer"},
+	Definition{Code: CodePasswordChangeFailed, Status: http.StatusInternalServerError, Title: "Change password failed"},
+	Definition{Code: CodePayloadTooLarge, Status: http.StatusRequestEntityTooLarge, Title: "Request body is too large"},
+	Definition{Code: CodePermissionDenied, Status: http.StatusForbidden, Title: "Permission denied"},
+	Definition{Code: CodePhoneNotSet, Status: http.StatusBadRequest, Title: "Phone number is not set"},
+	Definition{Code: CodePhoneNotVerified, Status: http.StatusBadRequest, Title: "Phone number is not verified"},
+	Definition{Code: CodeProjectArchived, Status: http.StatusConflict, Title: "Project is archived and read-only"},
+	Definition{Code: CodeProjectFetchFailed, Status: http.StatusInternalServerError, Title: "Failed to get project info"},
+	Definition{Code: CodeProjectNotArchived, Status: http.StatusConflict, Title: "Project is not archived"},
+	Definition{Code: CodeProjectNotFound, Status: http.StatusNotFound, Title: "Project not found"},
+	Definition{Code: CodeProjectViewExists, Status: http.StatusConflict, Title: "Project view with this name already exists"},
+	Definition{Code: CodeProjectViewFailed, Status: http.StatusInternalServerError, Title: "Failed to process project view"},
+	Definition{Code: CodeProjectViewNotFound, Status: http.StatusNotFound, Title: "Project view not found"},
+	Definition{Code: CodeProjectsFetchFailed, Status: http.StatusInternalServerError, Title: "Failed to get projects"},
+	Definition{Code: CodeRateLimited, Status: http.StatusTooManyRequests, Title: "Rate limit exceeded"},
+	Definition{Code: CodeRefreshFailed, Status: http.StatusInternalServerError, Title: "Token refresh failed"},
+	Definition{Code: CodeRemoveMemberFailed, Status: http.StatusInternalServerError, Title: "Failed to remove member"},
+	Definition{Code: CodeReportingChainFailed, Status: http.StatusInternalServerError, Title: "Failed to get reporting chain"},
+	Definition{Code: CodeReportsFetchFailed, Status: http.StatusInternalServerError, Title: "Failed to get user reports"},
+	Definition{Code: CodeReprioritizeFailed, Status: http.StatusInternalServerError, Title: "Failed to reprioritize tasks"},
+	Definition{Code: CodeRequestTimeout, Status: http.StatusGatewayTimeout, Title: "Request timed out"},
+	Definition{Code: CodeRestoreFailed, Status: http.StatusInternalServerError, Title: "Failed to restore project"},
+	Definition{Code: CodeRevisionNotFound, Status: http.StatusNotFound, Title: "Wiki page revision not found"},
+	Definition{Code: CodeRoadmapFailed, Status: http.StatusInternalServerError, Title: "Failed to process roadmap request"},
+	Definition{Code: CodeRoadmapNotFound, Status: http.StatusNotFound, Title: "Roadmap not found"},
+	Definition{Code: CodeSameProject, Status: http.StatusBadRequest, Title: "Task already belongs to the target project"},
+	Definition{Code: CodeScheduledTaskFailed, Status: http.StatusInternalServerError, Title: "Failed to process scheduled task"},
+	Definition{Code: CodeScheduledTaskNotFound, Status: http.StatusNotFound, Title: "Scheduled task not found"},
+	Definition{Code: CodeScheduledTaskProcessed, Status: http.StatusConflict, Title: "Scheduled task has already been processed"},
+	Definition{Code: CodeSearchFailed, Status: http.StatusInternalServerError, Title: "Failed to perform search"},
+	Definition{Code: CodeServiceUnavailable, Status: http.StatusServiceUnavailable, Title: "Service is temporarily unavailable"},
+	Definition{Code: CodeSettingsFetchFailed, Status: http.StatusInternalServerError, Title: "Failed to get settings"},
+	Definition{Code: CodeSettingsUpdateFailed, Status: http.StatusInternalServerError, Title: "Failed to update settings"},
+	Definition{Code: CodeSMSFailed, Status: http.StatusInternalServerError, Title: "Failed to process SMS request"},
+	Definition{Code: CodeSMSNotConfigured, Status: http.StatusServiceUnavailable, Title: "SMS notifications are not available"},
+	Definition{Code: CodeSMSQuotaExceeded, Status: http.StatusTooManyRequests, Title: "Monthly SMS quota exceeded"},
+	Definition{Code: CodeSplitEmpty, Status: http.StatusBadRequest, Title: "No tasks match the split selection"},
+	Definition{Code: CodeSplitFailed, Status: http.StatusInternalServerError, Title: "Failed to split project"},
+	Definition{Code: CodeSplitTooLarge, Status: http.StatusBadRequest, Title: "Too many tasks to split at once"},
+	Definition{Code: CodeSprintActive, Status: http.StatusConflict, Title: "Project already has an active sprint"},
+	Definition{Code: CodeSprintFailed, Status: http.StatusInternalServerError, Title: "Failed to process sprint request"},
+	Definition{Code: CodeSprintNotFound, Status: http.StatusNotFound, Title: "Sprint not found"},
+	Definition{Code: CodeSprintProjectMismatch, Status: http.StatusBadRequest, Title: "Tasks and target sprint must belong to the sprint project"},
+	Definition{Code: CodeStatusNoteFailed, Status: http.StatusInternalServerError, Title: "Failed to process status note"},
+	Definition{Code: CodeStatusNoteNotFound, Status: http.StatusNotFound, Title: "Status note not found"},
+	Definition{Code: CodeStatusUpdateFailed, Status: http.StatusInternalServerError, Title: "Failed to update task status"},
+	Definition{Code: CodeTagExists, Status: http.StatusConflict, Title: "Tag already exists in the project"},
+	Definition{Code: CodeTagInUse, Status: http.StatusConflict, Title: "Tag is used by open tasks"},
+	Definition{Code: CodeTagNotFound, Status: http.StatusNotFound, Title: "Tag not found"},
+	Definition{Code: CodeTagsFailed, Status: http.StatusInternalServerError, Title: "Failed to manage project tags"},
+	Definition{Code: CodeTaskAlreadyLinked, Status: http.StatusConflict, Title: "Task is already linked"},
+	Definition{Code: CodeTaskClosed, Status: http.StatusConflict, Title: "Task is completed or cancelled"},
+	Definition{Code: CodeTaskFetchFailed, Status: http.StatusInternalServerError, Title: "Failed to get task info"},
+	Definition{Code: CodeTaskFormFailed, Status: http.StatusInternalServerError, Title: "Failed to process task form"},
+	Definition{Code: CodeTaskLocked, Status: http.StatusConflict, Title: "Task description is being edited by another user"},
+	Definition{Code: CodeTaskNotFound, Status: http.StatusNotFound, Title: "Task not found"},
+	Definition{Code: CodeTaskRecurrenceFailed, Status: http.StatusInternalServerError, Title: "Failed to process task recurrence"},
+	Definition{Code: CodeTaskRecurrenceNotFound, Status: http.StatusNotFound, Title: "Task recurrence not found"},
+	Definition{Code: CodeTaskTemplateExists, Status: http.StatusConflict, Title: "Task template with this name already exists"},
+	Definition{Code: CodeTaskTemplateFailed, Status: http.StatusInternalServerError, Title: "Failed to process task template"},
+	Definition{Code: CodeTaskTemplateNotFound, Status: http.StatusNotFound, Title: "Task template not found"},
+	Definition{Code: CodeTasksExportFailed, Status: http.StatusInternalServerError, Title: "Failed to export tasks"},
+	Definition{Code: CodeTasksFetchFailed, Status: http.StatusInternalServerError, Title: "Failed to get tasks"},
+	Definition{Code: CodeTeamsChannelNotFound, Status: http.StatusNotFound, Title: "Teams channel not found"},
+	Definition{Code: CodeTeamsFailed, Status: http.StatusInternalServerError, Title: "Failed to process Teams request"},
+	Definition{Code: CodeTeamsWebhookNotAllowed, Status: http.StatusBadRequest, Title: "Webhook URL must point to Microsoft Teams"},
+	Definition{Code: CodeTeamsWebhookUnreachable, Status: http.StatusBadGateway, Title: "Teams webhook rejected the test message"},
+	Definition{Code: CodeTimeLogsFetchFailed, Status: http.StatusInternalServerError, Title: "Failed to get time logs"},
+	Definition{Code: CodeTimeRecalculationFailed, Status: http.StatusInternalServerError, Title: "Failed to reconcile spent hours"},
+	Definition{Code: CodeTooManyAttempts, Status: http.StatusTooManyRequests, Title: "Too many attempts, request a new code"},
+	Definition{Code: CodeTooManyPending, Status: http.StatusTooManyRequests, Title: "Too many requests are awaiting review"},
+	Definition{Code: CodeTransferExpired, Status: http.StatusGone, Title: "Ownership transfer has expired"},
+	Definition{Code: CodeTransferNotFound, Status: http.StatusNotFound, Title: "Pending ownership transfer not found"},
+	Definition{Code: CodeTransferPending, Status: http.StatusConflict, Title: "Ownership transfer is already pending"},
+	Definition{Code: CodeUnauthorized, Status: http.StatusUnauthorized, Title: "Authentication required"},
+	Definition{Code: CodeUnreadCountFailed, Status: http.StatusInternalServerError, Title: "Failed to get unread count"},
+	Definition{Code: CodeUnsubscribeFailed, Status: http.StatusInternalServerError, Title: "Failed to unsubscribe"},
+	Definition{Code: CodeUnsupportedProvider, Status: http.StatusBadRequest, Title: "Supported providers are pagerduty and opsgenie"},
+	Definition{Code: CodeUpdateFailed, Status: http.StatusInternalServerError, Title: "Failed to update resource"},
+	Definition{Code: CodeUpdateRoleFailed, Status: http.StatusInternalServerError, Title: "Failed to update member role"},
+	Definition{Code: CodeUserFetchFailed, Status: http.StatusInternalServerError, Title: "Failed to get user info"},
+	Definition{Code: CodeUserNotFound, Status: http.StatusNotFound, Title: "User not found"},
+	Definition{Code: CodeUsersFetchFailed, Status: http.StatusInternalServerError, Title: "Failed to get users"},
+	Definition{Code: CodeValidationFailed, Status: http.StatusBadRequest, Title: "Validation failed"},
+	Definition{Code: CodeVersionConflict, Status: http.StatusConflict, Title: "Wiki page was changed by another user, reload it and retry"},
+	Definition{Code: CodeWebhookDisabled, Status: http.StatusForbidden, Title: "Webhook is disabled"},
+	Definition{Code: CodeWebhookExists, Status: http.StatusConflict, Title: "Webhook with this name already exists"},
+	Definition{Code: CodeWebhookFailed, Status: http.StatusInternalServerError, Title: "Failed to process webhook"},
+	Definition{Code: CodeWebhookNotFound, Status: http.StatusNotFound, Title: "Webhook not found"},
+	Definition{Code: CodeWikiFailed, Status: http.StatusInternalServerError, Title: "Failed to process wiki request"},
+	Definition{Code: CodeWikiPageNotFound, Status: http.StatusNotFound, Title: "Wiki page not found"},
+)
+
+// newCatalog собирает каталог; повторное определение кода - ошибка программиста
+func newCatalog(definitions ...Definition) map[Code]Definition {
+	catalog := make(map[Code]Definition, len(definitions))
+	for _, definition := range definitions {
+		if _, ok := catalog[definition.Code]; ok {
+			panic(fmt.Sprintf("errors: duplicate error code %q", definition.Code))
+		}
+		catalog[definition.Code] = definition
+	}
+	return catalog
+}
+
+// docsBaseURL - адрес, по которому опубликовано описание кодов ошибок
+var docsBaseURL = "/api/v1/errors"
+
+// SetDocsBaseURL задает адрес описания кодов ошибок, например полный URL API
+func SetDocsBaseURL(baseURL string) {
+	docsBaseURL = strings.TrimRight(baseURL, "/")
+}
+
+// DocsURL возвращает ссылку на описание кода ошибки
+func DocsURL(code Code) string {
+	return docsBaseURL + "/" + string(code)
+}
+
+// Lookup возвращает описание кода ошибки из каталога
+func Lookup(code Code) (Definition, bool) {
+	definition, ok := catalog[code]
+	if !ok {
+		return Definition{}, false
+	}
+	definition.DocsURL = DocsURL(code)
+	return definition, true
+}
+
+// Status возвращает HTTP-статус кода ошибки; для неизвестных кодов - 500
+func Status(code Code) int {
+	if definition, ok := catalog[code]; ok {
+		return definition.Status
+	}
+	return http.StatusInternalServerError
+}
+
+// Catalog возвращает все коды ошибок, упорядоченные по коду
+func Catalog() []Definition {
+	definitions := make([]Definition, 0, len(catalog))
+	for code := range catalog {
+		definition, _ := Lookup(code)
+		definitions = append(definitions, definition)
+	}
+	sort.Slice(definitions, func(i, j int) bool {
+		return definitions[i].Code < definitions[j].Code
+	})
+	return definitions
+}
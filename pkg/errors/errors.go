@@ -3,28 +3,30 @@ package errors
 import (
 	"errors"
 	"fmt"
-	"net/http"
 )
 
-// Типы ошибок
+// Общие ошибки приложения
 var (
-	ErrInternalServer   = errors.New("internal server error")
-	ErrNotFound         = errors.New("resource not found")
-	ErrBadRequest       = errors.New("bad request")
-	ErrUnauthorized     = errors.New("unauthorized")
-	ErrForbidden        = errors.New("forbidden")
-	ErrConflict         = errors.New("conflict")
-	ErrValidation       = errors.New("validation error")
-	ErrTimeout          = errors.New("request timeout")
-	ErrServiceUnavailable = errors.New("service unavailable")
+	ErrInternalServer   = New(CodeInternalError, "internal server error")
+	ErrNotFound         = New(CodeNotFound, "resource not found")
+	ErrBadRequest       = New(CodeBadRequest, "bad request")
+	ErrUnauthorized     = New(CodeUnauthorized, "unauthorized")
+	ErrForbidden        = New(CodeForbidden, "forbidden")
+	ErrConflict         = New(CodeConflict, "conflict")
+	ErrValidation       = New(CodeValidationFailed, "validation error")
+	ErrTimeout          = New(CodeRequestTimeout, "request timeout")
+	ErrServiceUnavailable = New(CodeServiceUnavailable, "service unavailable")
 )
 
-// AppError представляет ошибку приложения
+// AppError представляет ошибку приложения с кодом из каталога.
+// Сервисы объявляют через New свои ошибки, а обработчики сравнивают их через errors.Is
+// и отвечают клиенту кодом и HTTP-статусом из каталога
 type AppError struct {
 	Err        error
 	StatusCode int
 	Message    string
-	Code       string
+	Code       Code
+	Fields     []FieldError
 	Data       interface{}
 }
 
@@ -41,45 +43,58 @@ func (e *AppError) Unwrap() error {
 	return e.Err
 }
 
-// NewAppError создает новую ошибку приложения
-func NewAppError(err error, statusCode int, message, code string, data interface{}) *AppError {
+// New создает ошибку с кодом из каталога. Используется для объявления ошибок сервисов
+// вместо errors.New: сравнение через errors.Is продолжает работать
+func New(code Code, message string) *AppError {
+	return &AppError{
+		StatusCode: Status(code),
+		Message:    message,
+		Code:       code,
+	}
+}
+
+// NewAppError создает новую ошибку приложения; HTTP-статус определяется кодом
+func NewAppError(err error, code Code, message string, data interface{}) *AppError {
 	return &AppError{
 		Err:        err,
-		StatusCode: statusCode,
+		StatusCode: Status(code),
 		Message:    message,
 		Code:       code,
 		Data:       data,
 	}
 }
 
-// FromError создает AppError из обычной ошибки
+// WithFields возвращает ошибку с тем же кодом и перечнем ошибок полей.
+// Исходная ошибка не меняется и остается доступной для errors.Is
+func (e *AppError) WithFields(fields ...FieldError) *AppError {
+	return &AppError{
+		Err:        e,
+		StatusCode: e.StatusCode,
+		Message:    e.Message,
+		Code:       e.Code,
+		Fields:     fields,
+		Data:       e.Data,
+	}
+}
+
+// FieldErrorer реализуется ошибками, которые содержат ошибки отдельных полей
+type FieldErrorer interface {
+	FieldErrors() []FieldError
+}
+
+// FromError создает AppError из обычной ошибки.
+// Ошибки без кода из каталога считаются внутренними
 func FromError(err error) *AppError {
 	var appErr *AppError
-	if errors.As(err, &appErr) {
-		return appErr
+	if !errors.As(err, &appErr) {
+		return NewAppError(err, CodeInternalError, "Internal server error", nil)
 	}
 
-	// Проверяем стандартные ошибки
-	switch {
-	case errors.Is(err, ErrNotFound):
-		return NewAppError(err, http.StatusNotFound, "Resource not found", "not_found", nil)
-	case errors.Is(err, ErrBadRequest):
-		return NewAppError(err, http.StatusBadRequest, "Bad request", "bad_request", nil)
-	case errors.Is(err, ErrUnauthorized):
-		return NewAppError(err, http.StatusUnauthorized, "Unauthorized", "unauthorized", nil)
-	case errors.Is(err, ErrForbidden):
-		return NewAppError(err, http.StatusForbidden, "Forbidden", "forbidden", nil)
-	case errors.Is(err, ErrConflict):
-		return NewAppError(err, http.StatusConflict, "Conflict", "conflict", nil)
-	case errors.Is(err, ErrValidation):
-		return NewAppError(err, http.StatusUnprocessableEntity, "Validation error", "validation_error", nil)
-	case errors.Is(err, ErrTimeout):
-		return NewAppError(err, http.StatusRequestTimeout, "Request timeout", "request_timeout", nil)
-	case errors.Is(err, ErrServiceUnavailable):
-		return NewAppError(err, http.StatusServiceUnavailable, "Service unavailable", "service_unavailable", nil)
-	default:
-		return NewAppError(err, http.StatusInternalServerError, "Internal server error", "internal_error", nil)
+	var fieldErr FieldErrorer
+	if len(appErr.Fields) == 0 && errors.As(err, &fieldErr) {
+		return appErr.WithFields(fieldErr.FieldErrors()...)
 	}
+	return appErr
 }
 
 // Error создает и возвращает новую ошибку
@@ -90,12 +105,12 @@ func Error(err error, message string) error {
 // NotFound создает ошибку 404 Not Found
 func NotFound(entity string, id interface{}) *AppError {
 	msg := fmt.Sprintf("%s with ID %v not found", entity, id)
-	return NewAppError(ErrNotFound, http.StatusNotFound, msg, "not_found", nil)
+	return NewAppError(ErrNotFound, CodeNotFound, msg, nil)
 }
 
 // BadRequest создает ошибку 400 Bad Request
 func BadRequest(message string) *AppError {
-	return NewAppError(ErrBadRequest, http.StatusBadRequest, message, "bad_request", nil)
+	return NewAppError(ErrBadRequest, CodeBadRequest, message, nil)
 }
 
 // Unauthorized создает ошибку 401 Unauthorized
@@ -103,7 +118,7 @@ func Unauthorized(message string) *AppError {
 	if message == "" {
 		message = "Authentication required"
 	}
-	return NewAppError(ErrUnauthorized, http.StatusUnauthorized, message, "unauthorized", nil)
+	return NewAppError(ErrUnauthorized, CodeUnauthorized, message, nil)
 }
 
 // Forbidden создает ошибку 403 Forbidden
@@ -111,23 +126,23 @@ func Forbidden(message string) *AppError {
 	if message == "" {
 		message = "You don't have permission to perform this action"
 	}
-	return NewAppError(ErrForbidden, http.StatusForbidden, message, "forbidden", nil)
+	return NewAppError(ErrForbidden, CodeForbidden, message, nil)
 }
 
 // Conflict создает ошибку 409 Conflict
 func Conflict(entity string, field string, value interface{}) *AppError {
 	msg := fmt.Sprintf("%s with %s %v already exists", entity, field, value)
-	return NewAppError(ErrConflict, http.StatusConflict, msg, "conflict", nil)
+	return NewAppError(ErrConflict, CodeConflict, msg, nil)
 }
 
-// ValidationError создает ошибку 422 Unprocessable Entity
-func ValidationError(data interface{}) *AppError {
-	return NewAppError(ErrValidation, http.StatusUnprocessableEntity, "Validation failed", "validation_error", data)
+// ValidationError создает ошибку 400 Bad Request с ошибками полей
+func ValidationError(fields ...FieldError) *AppError {
+	return ErrValidation.WithFields(fields...)
 }
 
 // InternalServer создает ошибку 500 Internal Server Error
 func InternalServer(err error) *AppError {
-	return NewAppError(err, http.StatusInternalServerError, "Internal server error", "internal_error", nil)
+	return NewAppError(err, CodeInternalError, "Internal server error", nil)
 }
 
 // ServiceUnavailable создает ошибку 503 Service Unavailable
@@ -135,5 +150,5 @@ func ServiceUnavailable(message string) *AppError {
 	if message == "" {
 		message = "Service is temporarily unavailable"
 	}
-	return NewAppError(ErrServiceUnavailable, http.StatusServiceUnavailable, message, "service_unavailable", nil)
+	return NewAppError(ErrServiceUnavailable, CodeServiceUnavailable, message, nil)
 }
\ No newline at end of file
@@ -0,0 +1,99 @@
+package errors
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Типы содержимого ответов с ошибкой
+const (
+	ContentTypeJSON    = "application/json"
+	ContentTypeProblem = "application/problem+json"
+)
+
+// Problem - ответ с ошибкой в формате Problem Details (RFC 7807).
+// Code и Errors - расширения формата с кодом из каталога и ошибками полей
+type Problem struct {
+	Type     string       `json:"type"`
+	Title    string       `json:"title"`
+	Status   int          `json:"status"`
+	Detail   string       `json:"detail,omitempty"`
+	Instance string       `json:"instance,omitempty"`
+	Code     Code         `json:"code"`
+	Errors   []FieldError `json:"errors,omitempty"`
+}
+
+// NewProblem преобразует ответ с ошибкой в Problem Details. Тип проблемы - ссылка
+// на описание кода, заголовок - описание кода из каталога, подробности - сообщение ошибки
+func NewProblem(status int, response Response, instance string) Problem {
+	problem := Problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   response.Message,
+		Instance: instance,
+		Code:     response.Code,
+		Errors:   response.Fields,
+	}
+	if definition, ok := Lookup(response.Code); ok {
+		problem.Type = definition.DocsURL
+		problem.Title = definition.Title
+	}
+	return problem
+}
+
+// WantsProblem проверяет, что клиент предпочитает application/problem+json обычному JSON.
+// Формат выбирается только явно: problem+json должен быть указан в Accept с весом
+// не меньше, чем у application/json и */*
+func WantsProblem(r *http.Request) bool {
+	accept := r.Header.Get("Accept")
+	if !strings.Contains(accept, ContentTypeProblem) {
+		return false
+	}
+
+	var problemQ, jsonQ float64
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, _ := strings.Cut(part, ";")
+		q := acceptQuality(params)
+		switch strings.ToLower(strings.TrimSpace(mediaType)) {
+		case ContentTypeProblem:
+			problemQ = max(problemQ, q)
+		case ContentTypeJSON, "application/*", "*/*":
+			jsonQ = max(jsonQ, q)
+		}
+	}
+	return problemQ > 0 && problemQ >= jsonQ
+}
+
+// acceptQuality возвращает вес q из параметров элемента заголовка Accept
+func acceptQuality(params string) float64 {
+	for _, param := range strings.Split(params, ";") {
+		name, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+		if !ok || strings.ToLower(strings.TrimSpace(name)) != "q" {
+			continue
+		}
+		q, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || q < 0 {
+			return 0
+		}
+		return min(q, 1)
+	}
+	return 1
+}
+
+// WriteResponse отправляет ответ с ошибкой в формате, выбранном по заголовку Accept
+func WriteResponse(w http.ResponseWriter, r *http.Request, status int, response Response) error {
+	var body interface{} = response
+	contentType := ContentTypeJSON
+	if WantsProblem(r) {
+		body = NewProblem(status, response, r.URL.Path)
+		contentType = ContentTypeProblem
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Add("Vary", "Accept")
+	w.WriteHeader(status)
+	return json.NewEncoder(w).Encode(body)
+}
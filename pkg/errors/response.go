@@ -0,0 +1,47 @@
+package errors
+
+import "net/http"
+
+// FieldError описывает ошибку в поле запроса
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Response - тело ответа API с ошибкой
+type Response struct {
+	Success bool         `json:"success"`
+	Message string       `json:"error"`
+	Code    Code         `json:"error_code"`
+	DocsURL string       `json:"docs_url,omitempty"`
+	Fields  []FieldError `json:"errors,omitempty"`
+}
+
+// NewResponse собирает тело ответа по коду ошибки.
+// Если сообщение не задано, используется описание кода из каталога
+func NewResponse(code Code, message string, fields ...FieldError) Response {
+	response := Response{
+		Success: false,
+		Message: message,
+		Code:    code,
+		Fields:  fields,
+	}
+	if definition, ok := Lookup(code); ok {
+		response.DocsURL = definition.DocsURL
+		if response.Message == "" {
+			response.Message = definition.Title
+		}
+	}
+	return response
+}
+
+// Response возвращает тело ответа API для ошибки
+func (e *AppError) Response() Response {
+	return NewResponse(e.Code, e.Message, e.Fields...)
+}
+
+// Write отправляет ответ с ошибкой и статусом из каталога. Используется там, где
+// нет обработчика с BaseHandler, например в middleware
+func Write(w http.ResponseWriter, r *http.Request, code Code, message string) {
+	_ = WriteResponse(w, r, Status(code), NewResponse(code, message))
+}
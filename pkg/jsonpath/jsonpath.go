@@ -0,0 +1,165 @@
+package jsonpath
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidPath возвращается, если выражение не является поддерживаемым путем JSONPath
+var ErrInvalidPath = errors.New("jsonpath: invalid path")
+
+// ErrNotFound возвращается, если по пути нет значения
+var ErrNotFound = errors.New("jsonpath: value not found")
+
+// templatePattern описывает подстановку пути в шаблон: {{ $.alerts[0].labels.alertname }}
+var templatePattern = regexp.MustCompile(`\{\{\s*(\$[^{}]*?)\s*\}\}`)
+
+// Lookup возвращает значение документа по пути.
+// Поддерживается подмножество JSONPath: корень $, поля через точку или ['поле'] и индексы массивов [n];
+// отрицательный индекс отсчитывается с конца массива.
+// Документ должен быть получен через json.Unmarshal в interface{}
+func Lookup(doc interface{}, path string) (interface{}, error) {
+	steps, err := parse(path)
+	if err != nil {
+		return nil, err
+	}
+
+	current := doc
+	for _, step := range steps {
+		switch value := current.(type) {
+		case map[string]interface{}:
+			if step.isIndex {
+				return nil, ErrNotFound
+			}
+			next, ok := value[step.key]
+			if !ok {
+				return nil, ErrNotFound
+			}
+			current = next
+		case []interface{}:
+			if !step.isIndex {
+				return nil, ErrNotFound
+			}
+			index := step.index
+			if index < 0 {
+				index += len(value)
+			}
+			if index < 0 || index >= len(value) {
+				return nil, ErrNotFound
+			}
+			current = value[index]
+		default:
+			return nil, ErrNotFound
+		}
+	}
+
+	return current, nil
+}
+
+// Validate проверяет, что выражение является поддерживаемым путем
+func Validate(path string) error {
+	_, err := parse(path)
+	return err
+}
+
+// Expand подставляет в шаблон значения путей вида {{ $.path }}.
+// Отсутствующие значения заменяются пустой строкой
+func Expand(template string, doc interface{}) string {
+	return templatePattern.ReplaceAllStringFunc(template, func(match string) string {
+		path := templatePattern.FindStringSubmatch(match)[1]
+		value, err := Lookup(doc, path)
+		if err != nil {
+			return ""
+		}
+		return Format(value)
+	})
+}
+
+// ValidateTemplate проверяет все пути, используемые в шаблоне
+func ValidateTemplate(template string) error {
+	for _, match := range templatePattern.FindAllStringSubmatch(template, -1) {
+		if err := Validate(match[1]); err != nil {
+			return fmt.Errorf("%w: %s", err, match[1])
+		}
+	}
+	return nil
+}
+
+// Format преобразует значение документа в строку: строки как есть,
+// числа без лишних нулей, объекты и массивы в виде JSON
+func Format(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return ""
+		}
+		return string(data)
+	}
+}
+
+// step представляет один шаг пути: поле объекта или индекс массива
+type step struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+// parse разбирает путь на шаги
+func parse(path string) ([]step, error) {
+	path = strings.TrimSpace(path)
+	if !strings.HasPrefix(path, "$") {
+		return nil, ErrInvalidPath
+	}
+
+	steps := []step{}
+	rest := path[1:]
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end == -1 {
+				end = len(rest)
+			}
+			if end == 0 {
+				return nil, ErrInvalidPath
+			}
+			steps = append(steps, step{key: rest[:end]})
+			rest = rest[end:]
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end == -1 {
+				return nil, ErrInvalidPath
+			}
+			inner := strings.TrimSpace(rest[1:end])
+			rest = rest[end+1:]
+
+			if len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0] {
+				steps = append(steps, step{key: inner[1 : len(inner)-1]})
+				continue
+			}
+			index, err := strconv.Atoi(inner)
+			if err != nil {
+				return nil, ErrInvalidPath
+			}
+			steps = append(steps, step{index: index, isIndex: true})
+		default:
+			return nil, ErrInvalidPath
+		}
+	}
+
+	return steps, nil
+}
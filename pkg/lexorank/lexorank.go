@@ -0,0 +1,102 @@
+package lexorank
+
+import (
+	"errors"
+	"strings"
+)
+
+// alphabet содержит цифры ранга в порядке возрастания
+const alphabet = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+// base - основание системы счисления ранга
+const base = len(alphabet)
+
+// MaxLength определяет длину ранга, после которой ранги стоит перебалансировать
+const MaxLength = 16
+
+// ErrInvalidRange возвращается, если нижняя граница не меньше верхней
+var ErrInvalidRange = errors.New("lexorank: lower bound must be less than upper bound")
+
+// ErrInvalidRank возвращается, если ранг содержит недопустимые символы
+var ErrInvalidRank = errors.New("lexorank: invalid rank")
+
+// Between возвращает ранг строго между prev и next.
+// Пустая строка означает отсутствие границы с соответствующей стороны.
+// Сгенерированные ранги никогда не заканчиваются на "0", поэтому между любыми двумя из них есть место
+func Between(prev, next string) (string, error) {
+	if !valid(prev) || !valid(next) {
+		return "", ErrInvalidRank
+	}
+	if next != "" && prev >= next {
+		return "", ErrInvalidRange
+	}
+
+	var result strings.Builder
+	for i := 0; ; i++ {
+		p := 0
+		if i < len(prev) {
+			p = strings.IndexByte(alphabet, prev[i])
+		}
+		n := base
+		if next != "" {
+			if i >= len(next) {
+				// next является префиксом результата, меньшего ранга не существует
+				return "", ErrInvalidRange
+			}
+			n = strings.IndexByte(alphabet, next[i])
+		}
+
+		if n-p > 1 {
+			result.WriteByte(alphabet[(p+n)/2])
+			return result.String(), nil
+		}
+
+		result.WriteByte(alphabet[p])
+		if n-p == 1 {
+			// Дальше результат уже меньше next, верхняя граница больше не ограничивает
+			next = ""
+		}
+	}
+}
+
+// Spread возвращает count равномерно распределенных рангов одинаковой длины
+func Spread(count int) []string {
+	width := 4
+	for capacity := pow(base, width); capacity <= count; capacity *= base {
+		width++
+	}
+
+	ranks := make([]string, count)
+	for i := range ranks {
+		ranks[i] = encode(i+1, width) + string(alphabet[base/2])
+	}
+	return ranks
+}
+
+// encode переводит число в ранг заданной ширины с ведущими нулями
+func encode(value, width int) string {
+	digits := make([]byte, width)
+	for i := width - 1; i >= 0; i-- {
+		digits[i] = alphabet[value%base]
+		value /= base
+	}
+	return string(digits)
+}
+
+// valid проверяет, что ранг состоит только из символов алфавита
+func valid(rank string) bool {
+	for i := 0; i < len(rank); i++ {
+		if strings.IndexByte(alphabet, rank[i]) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func pow(x, n int) int {
+	result := 1
+	for i := 0; i < n; i++ {
+		result *= x
+	}
+	return result
+}
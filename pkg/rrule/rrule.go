@@ -0,0 +1,250 @@
+package rrule
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidRule возвращается, если правило не является поддерживаемым правилом повторения
+var ErrInvalidRule = errors.New("rrule: invalid rule")
+
+// Frequency определяет частоту повторения
+type Frequency string
+
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+	Yearly  Frequency = "YEARLY"
+)
+
+// maxInterval ограничивает интервал повторения, чтобы даты не уходили за пределы разумного
+const maxInterval = 1000
+
+// untilLayouts - форматы UNTIL: дата или дата со временем в UTC
+const (
+	untilDateLayout     = "20060102"
+	untilDateTimeLayout = "20060102T150405Z"
+)
+
+// weekdays сопоставляет коды дней недели BYDAY с time.Weekday
+var weekdays = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+// Rule представляет правило повторения - подмножество RRULE из RFC 5545:
+// FREQ (DAILY, WEEKLY, MONTHLY, YEARLY), INTERVAL, BYDAY (только для WEEKLY),
+// BYMONTHDAY (только для MONTHLY, 1..31 или -1 для последнего дня), COUNT и UNTIL
+type Rule struct {
+	Freq       Frequency
+	Interval   int
+	ByDay      []time.Weekday // Дни недели по возрастанию, начиная с понедельника
+	ByMonthDay int            // 0, если день месяца берется из предыдущего повторения
+	Count      int            // 0 - без ограничения числа повторений
+	Until      *time.Time     // Последний момент, на который может прийтись повторение
+}
+
+// Parse разбирает правило вида FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE;COUNT=10.
+// Префикс RRULE: допускается
+func Parse(value string) (*Rule, error) {
+	value = strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(value)), "RRULE:")
+	if value == "" {
+		return nil, fmt.Errorf("%w: empty rule", ErrInvalidRule)
+	}
+
+	rule := &Rule{Interval: 1}
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(value, ";") {
+		name, param, found := strings.Cut(part, "=")
+		name = strings.TrimSpace(name)
+		param = strings.TrimSpace(param)
+		if !found || param == "" {
+			return nil, fmt.Errorf("%w: malformed part %q", ErrInvalidRule, part)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("%w: duplicate %s", ErrInvalidRule, name)
+		}
+		seen[name] = true
+
+		var err error
+		switch name {
+		case "FREQ":
+			rule.Freq = Frequency(param)
+			if rule.Freq != Daily && rule.Freq != Weekly && rule.Freq != Monthly && rule.Freq != Yearly {
+				err = fmt.Errorf("unsupported FREQ %s", param)
+			}
+		case "INTERVAL":
+			rule.Interval, err = parseBounded(param, 1, maxInterval)
+		case "COUNT":
+			rule.Count, err = parseBounded(param, 1, maxInterval)
+		case "BYDAY":
+			rule.ByDay, err = parseByDay(param)
+		case "BYMONTHDAY":
+			rule.ByMonthDay, err = parseBounded(param, -1, 31)
+			if err == nil && rule.ByMonthDay == 0 {
+				err = errors.New("BYMONTHDAY must not be 0")
+			}
+		case "UNTIL":
+			rule.Until, err = parseUntil(param)
+		default:
+			err = fmt.Errorf("unsupported part %s", name)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidRule, err)
+		}
+	}
+
+	switch {
+	case rule.Freq == "":
+		return nil, fmt.Errorf("%w: FREQ is required", ErrInvalidRule)
+	case len(rule.ByDay) > 0 && rule.Freq != Weekly:
+		return nil, fmt.Errorf("%w: BYDAY is supported only with FREQ=WEEKLY", ErrInvalidRule)
+	case rule.ByMonthDay != 0 && rule.Freq != Monthly:
+		return nil, fmt.Errorf("%w: BYMONTHDAY is supported only with FREQ=MONTHLY", ErrInvalidRule)
+	case rule.Count > 0 && rule.Until != nil:
+		return nil, fmt.Errorf("%w: COUNT and UNTIL must not be used together", ErrInvalidRule)
+	}
+
+	return rule, nil
+}
+
+// String возвращает правило в каноническом виде
+func (r *Rule) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, len(r.ByDay))
+		for i, day := range r.ByDay {
+			days[i] = strings.ToUpper(day.String()[:2])
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if r.ByMonthDay != 0 {
+		parts = append(parts, "BYMONTHDAY="+strconv.Itoa(r.ByMonthDay))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format(untilDateTimeLayout))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Next возвращает следующее повторение после after. Время суток берется из after
+func (r *Rule) Next(after time.Time) time.Time {
+	switch r.Freq {
+	case Daily:
+		return after.AddDate(0, 0, r.Interval)
+	case Weekly:
+		return r.nextWeekly(after)
+	case Monthly:
+		day := r.ByMonthDay
+		if day == 0 {
+			day = after.Day()
+		}
+		// Если день повторения еще не наступил в текущем месяце, повторение приходится на него
+		if candidate := monthDay(after, 0, day); candidate.After(after) && r.ByMonthDay != 0 {
+			return candidate
+		}
+		return monthDay(after, r.Interval, day)
+	default:
+		// Для 29 февраля в невисокосный год повторение переносится на 28 февраля
+		return monthDay(after, 12*r.Interval, after.Day())
+	}
+}
+
+// Exhausted сообщает, что повторение next не должно наступить: число повторений
+// с учетом уже созданных occurrences исчерпано или next позже UNTIL
+func (r *Rule) Exhausted(occurrences int, next time.Time) bool {
+	if r.Count > 0 && occurrences >= r.Count {
+		return true
+	}
+	return r.Until != nil && next.After(*r.Until)
+}
+
+// nextWeekly возвращает следующий день из BYDAY в текущей неделе или первый день
+// из BYDAY через Interval недель. Недели начинаются с понедельника
+func (r *Rule) nextWeekly(after time.Time) time.Time {
+	if len(r.ByDay) == 0 {
+		return after.AddDate(0, 0, 7*r.Interval)
+	}
+
+	offset := weekdayOffset(after.Weekday())
+	for _, day := range r.ByDay {
+		if dayOffset := weekdayOffset(day); dayOffset > offset {
+			return after.AddDate(0, 0, dayOffset-offset)
+		}
+	}
+
+	weekStart := after.AddDate(0, 0, -offset)
+	return weekStart.AddDate(0, 0, 7*r.Interval+weekdayOffset(r.ByDay[0]))
+}
+
+// monthDay возвращает день day месяца, отстоящего от t на months месяцев. Если в месяце
+// нет такого дня, берется последний; -1 означает последний день месяца
+func monthDay(t time.Time, months, day int) time.Time {
+	first := time.Date(t.Year(), t.Month(), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location()).AddDate(0, months, 0)
+	lastDay := first.AddDate(0, 1, -1).Day()
+	if day < 0 || day > lastDay {
+		day = lastDay
+	}
+	return first.AddDate(0, 0, day-1)
+}
+
+// weekdayOffset возвращает номер дня в неделе, начинающейся с понедельника
+func weekdayOffset(day time.Weekday) int {
+	return (int(day) + 6) % 7
+}
+
+func parseBounded(value string, min, max int) (int, error) {
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed < min || parsed > max {
+		return 0, fmt.Errorf("value %s must be between %d and %d", value, min, max)
+	}
+	return parsed, nil
+}
+
+func parseByDay(value string) ([]time.Weekday, error) {
+	seen := make(map[time.Weekday]bool)
+	var days []time.Weekday
+	for _, code := range strings.Split(value, ",") {
+		day, ok := weekdays[strings.TrimSpace(code)]
+		if !ok {
+			return nil, fmt.Errorf("unsupported BYDAY %s", code)
+		}
+		if !seen[day] {
+			seen[day] = true
+			days = append(days, day)
+		}
+	}
+	sort.Slice(days, func(i, j int) bool {
+		return weekdayOffset(days[i]) < weekdayOffset(days[j])
+	})
+	return days, nil
+}
+
+// parseUntil разбирает UNTIL; дата без времени включает весь день по UTC
+func parseUntil(value string) (*time.Time, error) {
+	if until, err := time.Parse(untilDateTimeLayout, value); err == nil {
+		return &until, nil
+	}
+	until, err := time.Parse(untilDateLayout, value)
+	if err != nil {
+		return nil, fmt.Errorf("UNTIL %s must be YYYYMMDD or YYYYMMDDTHHMMSSZ", value)
+	}
+	endOfDay := until.AddDate(0, 0, 1).Add(-time.Second)
+	return &endOfDay, nil
+}
@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/nurlyy/task_manager/pkg/config"
+)
+
+// ErrNotFound возвращается, если объекта с указанным ключом нет в хранилище
+var ErrNotFound = errors.New("storage: object not found")
+
+// ErrInvalidKey возвращается для ключей, выходящих за пределы хранилища
+var ErrInvalidKey = errors.New("storage: invalid key")
+
+// Storage описывает хранилище файлов. Ключ - относительный путь из сегментов,
+// разделенных "/", например attachments/<task_id>/<id>
+type Storage interface {
+	// Put сохраняет объект размером size байт; существующий объект заменяется
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+
+	// Get открывает объект на чтение; вызывающий должен закрыть его
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+
+	// Delete удаляет объект; отсутствие объекта ошибкой не считается
+	Delete(ctx context.Context, key string) error
+}
+
+// New создает хранилище, выбранное в конфигурации
+func New(cfg *config.StorageConfig) (Storage, error) {
+	switch strings.ToLower(cfg.Backend) {
+	case "", "local":
+		return NewLocal(cfg.LocalDir)
+	case "s3":
+		if cfg.S3.Bucket == "" || cfg.S3.AccessKeyID == "" || cfg.S3.SecretAccessKey == "" {
+			return nil, fmt.Errorf("s3 bucket and credentials are required")
+		}
+		return NewS3(cfg.S3, &http.Client{Timeout: cfg.S3.Timeout}), nil
+	}
+	return nil, fmt.Errorf("unsupported storage backend %q", cfg.Backend)
+}
+
+// validateKey проверяет, что ключ не пустой и не содержит переходов на уровень выше
+func validateKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") {
+		return fmt.Errorf("%w: %q", ErrInvalidKey, key)
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "" || segment == "." || segment == ".." || strings.Contains(segment, "\\") {
+			return fmt.Errorf("%w: %q", ErrInvalidKey, key)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package validator
+
+import (
+	"context"
+	"strings"
+
+	apperrors "github.com/nurlyy/task_manager/pkg/errors"
+)
+
+// Errors накапливает ошибки валидации полей. Используется и для проверки запросов,
+// и сервисами для проверок предметной области, поэтому клиенты получают ошибки
+// в одной структуре с кодом validation_failed
+type Errors struct {
+	locale Locale
+	fields []apperrors.FieldError
+}
+
+// NewErrors создает набор ошибок; сообщения правил переводятся на язык из контекста
+func NewErrors(ctx context.Context) *Errors {
+	return &Errors{locale: LocaleFromContext(ctx)}
+}
+
+// Add добавляет ошибку поля с готовым сообщением
+func (e *Errors) Add(field, message string) {
+	e.fields = append(e.fields, apperrors.FieldError{Field: field, Message: message})
+}
+
+// AddRule добавляет ошибку поля с сообщением для нарушенного правила
+func (e *Errors) AddRule(field, rule, param string) {
+	e.Add(field, localizedMessage(e.locale, rule, param))
+}
+
+// Len возвращает число ошибок
+func (e *Errors) Len() int {
+	return len(e.fields)
+}
+
+// Err возвращает набор как ошибку или nil, если ошибок нет
+func (e *Errors) Err() error {
+	if len(e.fields) == 0 {
+		return nil
+	}
+	return e
+}
+
+// Error реализует интерфейс error
+func (e *Errors) Error() string {
+	msgs := make([]string, 0, len(e.fields))
+	for _, field := range e.fields {
+		msgs = append(msgs, field.Field+": "+field.Message)
+	}
+	return "validation failed: " + strings.Join(msgs, "; ")
+}
+
+// Unwrap позволяет сравнивать ошибку с apperrors.ErrValidation через errors.Is
+func (e *Errors) Unwrap() error {
+	return apperrors.ErrValidation
+}
+
+// FieldErrors возвращает ошибки полей для ответа API
+func (e *Errors) FieldErrors() []apperrors.FieldError {
+	return e.fields
+}
@@ -0,0 +1,148 @@
+package validator
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Locale - язык сообщений об ошибках валидации
+type Locale string
+
+const (
+	LocaleEN Locale = "en"
+	LocaleRU Locale = "ru"
+
+	// DefaultLocale используется, если клиент не указал поддерживаемый язык
+	DefaultLocale = LocaleEN
+)
+
+// localeKey - ключ контекста, под которым хранится язык запроса
+type localeKey struct{}
+
+// WithLocale возвращает контекст с языком сообщений
+func WithLocale(ctx context.Context, locale Locale) context.Context {
+	return context.WithValue(ctx, localeKey{}, locale)
+}
+
+// LocaleFromContext возвращает язык сообщений из контекста
+func LocaleFromContext(ctx context.Context) Locale {
+	if ctx != nil {
+		if locale, ok := ctx.Value(localeKey{}).(Locale); ok {
+			return locale
+		}
+	}
+	return DefaultLocale
+}
+
+// ParseAcceptLanguage выбирает поддерживаемый язык из заголовка Accept-Language.
+// Языки проверяются в порядке перечисления; веса q не учитываются
+func ParseAcceptLanguage(header string) Locale {
+	for _, part := range strings.Split(header, ",") {
+		tag, _, _ := strings.Cut(part, ";")
+		tag, _, _ = strings.Cut(strings.TrimSpace(tag), "-")
+		switch Locale(strings.ToLower(tag)) {
+		case LocaleRU:
+			return LocaleRU
+		case LocaleEN:
+			return LocaleEN
+		}
+	}
+	return DefaultLocale
+}
+
+// messages содержит сообщения об ошибках по правилам; %s заменяется параметром правила
+var messages = map[Locale]map[string]string{
+	LocaleEN: {
+		"required":            "This field is required",
+		"email":               "Invalid email format",
+		"url":                 "Invalid URL",
+		"uuid":                "Invalid UUID format",
+		"uuid_list":           "Must be a list of unique UUIDs",
+		"e164":                "Phone number must be in international format, e.g. +14155550100",
+		"numeric":             "Must be a number",
+		"startswith":          "Must start with %s",
+		"oneof":               "Value must be one of: %s",
+		"enum":                "Value must be one of: %s",
+		"min":                 "Must be at least %s",
+		"max":                 "Must be at most %s",
+		"len":                 "Must be exactly %s",
+		"min_string":          "Minimum length is %s",
+		"max_string":          "Maximum length is %s",
+		"len_string":          "Length must be exactly %s",
+		"min_items":           "Must contain at least %s items",
+		"max_items":           "Must contain at most %s items",
+		"len_items":           "Must contain exactly %s items",
+		"gt":                  "Must be greater than %s",
+		"gte":                 "Must be greater than or equal to %s",
+		"lt":                  "Must be less than %s",
+		"lte":                 "Must be less than or equal to %s",
+		"gtfield":             "Value must be greater than %s field",
+		"nefield":             "Value must differ from %s field",
+		"after_field":         "Date must be after %s",
+		"excluded_with":       "Must not be set together with %s",
+		"required_by_project": "This field is required by project settings",
+		"required_for_status": "This field is required to move the task to %s",
+		"invalid":             "Invalid value",
+		"boolean":             "Must be true or false",
+		"date":                "Must be a date in YYYY-MM-DD or RFC 3339 format",
+		"date_filter":         "Must be a date condition like due:<7d, updated:>30d or created:>2024-01-31",
+	},
+	LocaleRU: {
+		"required":            "Обязательное поле",
+		"email":               "Некорректный адрес электронной почты",
+		"url":                 "Некорректный URL",
+		"uuid":                "Некорректный формат UUID",
+		"uuid_list":           "Должен быть списком неповторяющихся UUID",
+		"e164":                "Номер телефона должен быть в международном формате, например +79001234567",
+		"numeric":             "Должно быть числом",
+		"startswith":          "Должно начинаться с %s",
+		"oneof":               "Допустимые значения: %s",
+		"enum":                "Допустимые значения: %s",
+		"min":                 "Значение должно быть не меньше %s",
+		"max":                 "Значение должно быть не больше %s",
+		"len":                 "Значение должно быть равно %s",
+		"min_string":          "Минимальная длина - %s",
+		"max_string":          "Максимальная длина - %s",
+		"len_string":          "Длина должна быть равна %s",
+		"min_items":           "Должно быть не меньше %s элементов",
+		"max_items":           "Должно быть не больше %s элементов",
+		"len_items":           "Должно быть ровно %s элементов",
+		"gt":                  "Значение должно быть больше %s",
+		"gte":                 "Значение должно быть не меньше %s",
+		"lt":                  "Значение должно быть меньше %s",
+		"lte":                 "Значение должно быть не больше %s",
+		"gtfield":             "Значение должно быть больше поля %s",
+		"nefield":             "Значение должно отличаться от поля %s",
+		"after_field":         "Дата должна быть позже %s",
+		"excluded_with":       "Нельзя указывать вместе с %s",
+		"required_by_project": "Поле обязательно по настройкам проекта",
+		"required_for_status": "Поле обязательно для перевода задачи в статус %s",
+		"invalid":             "Некорректное значение",
+		"boolean":             "Должно быть true или false",
+		"date":                "Дата должна быть в формате YYYY-MM-DD или RFC 3339",
+		"date_filter":         "Условие по дате должно иметь вид due:<7d, updated:>30d или created:>2024-01-31",
+	},
+}
+
+// Message возвращает сообщение для правила на языке из контекста.
+// Для неизвестных правил возвращается общее сообщение с именем правила
+func Message(ctx context.Context, rule, param string) string {
+	return localizedMessage(LocaleFromContext(ctx), rule, param)
+}
+
+// localizedMessage возвращает сообщение для правила на указанном языке
+func localizedMessage(locale Locale, rule, param string) string {
+	format, ok := messages[locale][rule]
+	if !ok {
+		format, ok = messages[DefaultLocale][rule]
+	}
+	if !ok {
+		return fmt.Sprintf("%s (%s)", messages[locale]["invalid"], rule)
+	}
+
+	if strings.Contains(format, "%s") {
+		return fmt.Sprintf(format, param)
+	}
+	return format
+}
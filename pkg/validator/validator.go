@@ -1,39 +1,27 @@
 package validator
 
 import (
-	"fmt"
+	"context"
+	"errors"
 	"reflect"
 	"strings"
+	"time"
+	"unicode"
 
 	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+
+	"github.com/nurlyy/task_manager/pkg/datefilter"
 )
 
-// CustomValidator - структура для валидации данных
+// CustomValidator - общий валидатор запросов: правила задаются тегами validate,
+// ошибки возвращаются с именами полей из JSON и сообщениями на языке запроса
 type CustomValidator struct {
 	validator *validator.Validate
+	enums     map[string][]string
 }
 
-// ValidationError представляет ошибку валидации
-type ValidationError struct {
-	Field   string `json:"field"`
-	Message string `json:"message"`
-}
-
-// ValidationErrors содержит список ошибок валидации
-type ValidationErrors struct {
-	Errors []ValidationError `json:"errors"`
-}
-
-// Error реализует интерфейс error
-func (ve ValidationErrors) Error() string {
-	var errMsgs []string
-	for _, err := range ve.Errors {
-		errMsgs = append(errMsgs, fmt.Sprintf("%s: %s", err.Field, err.Message))
-	}
-	return strings.Join(errMsgs, "; ")
-}
-
-// NewValidator создает новый экземпляр валидатора
+// NewValidator создает новый экземпляр валидатора с дополнительными правилами
 func NewValidator() *CustomValidator {
 	v := validator.New()
 
@@ -46,70 +34,165 @@ func NewValidator() *CustomValidator {
 		return name
 	})
 
-	return &CustomValidator{
+	cv := &CustomValidator{
 		validator: v,
+		enums:     make(map[string][]string),
 	}
+	cv.RegisterCustomValidations()
+
+	return cv
 }
 
-// RegisterCustomValidations регистрирует кастомные валидации
+// RegisterCustomValidations регистрирует дополнительные правила:
+//   - uuid_list: список UUID без повторов;
+//   - after_field=Поле: дата позже даты в указанном поле, если обе заданы;
+//   - date_filter: условие по дате задачи вида due:<7d или updated:>30d
 func (cv *CustomValidator) RegisterCustomValidations() {
-	// Пример регистрации кастомной валидации
-	cv.validator.RegisterValidation("task_status", validateTaskStatus)
+	cv.validator.RegisterValidation("uuid_list", validateUUIDList)
+	cv.validator.RegisterValidation("after_field", validateAfterField)
+	cv.validator.RegisterValidation("date_filter", validateDateFilter)
 }
 
-// Пример кастомной валидации
-func validateTaskStatus(fl validator.FieldLevel) bool {
-	status := fl.Field().String()
-	validStatuses := map[string]bool{
-		"new":        true,
-		"in_progress": true,
-		"on_hold":    true,
-		"completed":  true,
-		"cancelled":  true,
+// RegisterEnum регистрирует правило-перечисление: значение поля должно быть одним из values
+func (cv *CustomValidator) RegisterEnum(tag string, values ...string) {
+	allowed := make(map[string]bool, len(values))
+	for _, value := range values {
+		allowed[value] = true
 	}
-	return validStatuses[status]
+
+	cv.enums[tag] = values
+	cv.validator.RegisterValidation(tag, func(fl validator.FieldLevel) bool {
+		return allowed[fl.Field().String()]
+	})
 }
 
-// Validate проверяет структуру на соответствие правилам валидации
-func (cv *CustomValidator) Validate(i interface{}) error {
-	if err := cv.validator.Struct(i); err != nil {
-		var validationErrors ValidationErrors
+// Validate проверяет структуру на соответствие правилам валидации.
+// Нарушения возвращаются как *Errors с сообщениями на языке из контекста
+func (cv *CustomValidator) Validate(ctx context.Context, i interface{}) error {
+	err := cv.validator.Struct(i)
+	if err == nil {
+		return nil
+	}
+
+	var fieldErrors validator.ValidationErrors
+	if !errors.As(err, &fieldErrors) {
+		return err
+	}
+
+	verrs := NewErrors(ctx)
+	for _, fieldErr := range fieldErrors {
+		rule, param := ruleFor(fieldErr), fieldErr.Param()
+		if values, ok := cv.enums[fieldErr.Tag()]; ok {
+			rule, param = "enum", strings.Join(values, " ")
+		}
+		if rule == "after_field" {
+			param = snakeCase(param)
+		}
+		verrs.AddRule(fieldPath(fieldErr), rule, param)
+	}
+	return verrs
+}
 
-		for _, err := range err.(validator.ValidationErrors) {
-			field := err.Field()
-			message := getErrorMessage(err)
+// fieldPath возвращает путь к полю без имени корневой структуры, например items[0].title
+func fieldPath(fieldErr validator.FieldError) string {
+	namespace := fieldErr.Namespace()
+	if i := strings.Index(namespace, "."); i >= 0 {
+		return namespace[i+1:]
+	}
+	return fieldErr.Field()
+}
 
-			validationErrors.Errors = append(validationErrors.Errors, ValidationError{
-				Field:   field,
-				Message: message,
-			})
+// ruleFor уточняет правило для сообщения: ограничения длины строк и списков
+// формулируются иначе, чем ограничения чисел
+func ruleFor(fieldErr validator.FieldError) string {
+	rule := fieldErr.Tag()
+	switch rule {
+	case "min", "max", "len":
+		switch fieldErr.Kind() {
+		case reflect.String:
+			return rule + "_string"
+		case reflect.Slice, reflect.Array, reflect.Map:
+			return rule + "_items"
 		}
+	}
+	return rule
+}
 
-		return validationErrors
+// snakeCase переводит имя поля структуры в имя поля JSON, например StartDate в start_date
+func snakeCase(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
 	}
-	return nil
+	return b.String()
 }
 
-// getErrorMessage возвращает понятное сообщение об ошибке на основе тега валидации
-func getErrorMessage(err validator.FieldError) string {
-	switch err.Tag() {
-	case "required":
-		return "This field is required"
-	case "email":
-		return "Invalid email format"
-	case "min":
-		if err.Type().Kind() == reflect.String {
-			return fmt.Sprintf("Must be at least %s characters long", err.Param())
+// validateUUIDList проверяет, что список состоит из UUID и не содержит повторов
+func validateUUIDList(fl validator.FieldLevel) bool {
+	field := fl.Field()
+	if field.Kind() != reflect.Slice {
+		return false
+	}
+
+	seen := make(map[string]bool, field.Len())
+	for i := 0; i < field.Len(); i++ {
+		item := field.Index(i)
+		if item.Kind() != reflect.String {
+			return false
 		}
-		return fmt.Sprintf("Must be at least %s", err.Param())
-	case "max":
-		if err.Type().Kind() == reflect.String {
-			return fmt.Sprintf("Must be at most %s characters long", err.Param())
+		id, err := uuid.Parse(item.String())
+		if err != nil || seen[id.String()] {
+			return false
 		}
-		return fmt.Sprintf("Must be at most %s", err.Param())
-	case "task_status":
-		return "Invalid task status"
-	default:
-		return fmt.Sprintf("Failed validation for '%s'", err.Tag())
+		seen[id.String()] = true
+	}
+	return true
+}
+
+// validateAfterField проверяет, что дата позже даты в другом поле. Если одна из дат
+// не задана, диапазон не проверяется: обязательность задается отдельным правилом
+func validateAfterField(fl validator.FieldLevel) bool {
+	end, ok := timeValue(fl.Field())
+	if !ok {
+		return true
+	}
+
+	startField, _, _, found := fl.GetStructFieldOKAdvanced2(fl.Parent(), fl.Param())
+	if !found {
+		return false
+	}
+	start, ok := timeValue(startField)
+	if !ok {
+		return true
 	}
-}
\ No newline at end of file
+
+	return end.After(start)
+}
+
+// validateDateFilter проверяет условие по дате задачи, например due:<7d
+func validateDateFilter(fl validator.FieldLevel) bool {
+	_, err := datefilter.Parse(fl.Field().String())
+	return err == nil
+}
+
+// timeValue извлекает время из поля time.Time или *time.Time
+func timeValue(field reflect.Value) (time.Time, bool) {
+	for field.Kind() == reflect.Ptr {
+		if field.IsNil() {
+			return time.Time{}, false
+		}
+		field = field.Elem()
+	}
+
+	t, ok := field.Interface().(time.Time)
+	if !ok || t.IsZero() {
+		return time.Time{}, false
+	}
+	return t, true
+}